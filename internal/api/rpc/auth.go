@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/authz"
+	"github.com/marmotdata/marmot/internal/core/serviceaccount"
+	"github.com/marmotdata/marmot/internal/core/user"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader carries the API key, matching the REST API's X-API-Key.
+const apiKeyHeader = "x-api-key"
+
+type permission struct {
+	resourceType string
+	action       string
+}
+
+// methodPermissions lists the permission each RPC requires. Methods missing
+// from the map are rejected.
+var methodPermissions = map[string]permission{
+	ingestv1.IngestService_StartRun_FullMethodName:    {"ingestion", "manage"},
+	ingestv1.IngestService_Ingest_FullMethodName:      {"ingestion", "manage"},
+	ingestv1.IngestService_CompleteRun_FullMethodName: {"ingestion", "manage"},
+	ingestv1.IngestService_GetAsset_FullMethodName:    {"assets", "view"},
+	ingestv1.IngestService_GetLineage_FullMethodName:  {"assets", "view"},
+}
+
+type principalKey struct{}
+
+func principalFromContext(ctx context.Context) (auth.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(auth.Principal)
+	return p, ok
+}
+
+type authenticator struct {
+	userService           user.Service
+	serviceAccountService serviceaccount.Service
+	authz                 *authz.Service
+}
+
+// authenticate resolves the caller's API key to a user or service account,
+// checks it holds the permission the method requires and runs the
+// authorization hook, as the REST API does.
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	perm, ok := methodPermissions[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "Permission denied")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(apiKeyHeader)
+	if len(keys) == 0 || keys[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "API key required")
+	}
+
+	u, err := a.userService.ValidateAPIKey(ctx, keys[0])
+	if err == nil {
+		allowed, err := a.userService.HasPermission(ctx, u.ID, perm.resourceType, perm.action)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Failed to check permissions")
+		}
+		if !allowed {
+			return nil, status.Error(codes.PermissionDenied, "Permission denied")
+		}
+		return a.authorize(ctx, method, perm, auth.NewUserPrincipal(u))
+	}
+
+	if errors.Is(err, user.ErrInvalidAPIKey) && a.serviceAccountService != nil {
+		sa, saErr := a.serviceAccountService.ValidateAPIKey(ctx, keys[0])
+		if saErr == nil {
+			roleNames := make([]string, 0, len(sa.Roles))
+			permKeys := make([]string, 0)
+			for _, r := range sa.Roles {
+				roleNames = append(roleNames, r.Name)
+				for _, p := range r.Permissions {
+					permKeys = append(permKeys, p.ResourceType+":"+p.Action)
+				}
+			}
+			principal := auth.NewServiceAccountPrincipal(sa.ID, sa.Name, roleNames, permKeys)
+			if !principal.HasPermission(perm.resourceType, perm.action) {
+				return nil, status.Error(codes.PermissionDenied, "Permission denied")
+			}
+			return a.authorize(ctx, method, perm, principal)
+		}
+	}
+
+	log.Debug().Err(err).Str("method", method).Msg("Failed to validate gRPC API key")
+	return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+}
+
+// authorize runs the authorization hook for a caller that passed the role
+// check and returns the context carrying the caller.
+func (a *authenticator) authorize(ctx context.Context, method string, perm permission, principal auth.Principal) (context.Context, error) {
+	if a.authz != nil {
+		decision, err := a.authz.Authorize(ctx, authz.Request{
+			Principal:    principal,
+			ResourceType: perm.resourceType,
+			Action:       perm.action,
+			// gRPC calls are HTTP/2 POSTs to the method's path.
+			HTTP: authz.HTTPRequest{Method: "POST", Path: method, Route: method},
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Failed to check permissions")
+		}
+		if !decision.Allow {
+			msg := "Permission denied"
+			if decision.Reason != "" {
+				msg += ": " + decision.Reason
+			}
+			return nil, status.Error(codes.PermissionDenied, msg)
+		}
+	}
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
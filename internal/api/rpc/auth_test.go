@@ -0,0 +1,204 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/authz"
+	"github.com/marmotdata/marmot/internal/core/role"
+	"github.com/marmotdata/marmot/internal/core/serviceaccount"
+	"github.com/marmotdata/marmot/internal/core/user"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUsers knows one user API key; the user holds the listed permissions.
+type fakeUsers struct {
+	user.Service
+	key         string
+	permissions map[string]bool
+}
+
+func (f *fakeUsers) ValidateAPIKey(_ context.Context, apiKey string) (*user.User, error) {
+	if apiKey != f.key {
+		return nil, user.ErrInvalidAPIKey
+	}
+	return &user.User{ID: "user-1", Username: "alice"}, nil
+}
+
+func (f *fakeUsers) HasPermission(_ context.Context, _ string, resourceType, action string) (bool, error) {
+	return f.permissions[resourceType+":"+action], nil
+}
+
+// fakeServiceAccounts knows one service account API key.
+type fakeServiceAccounts struct {
+	serviceaccount.Service
+	key         string
+	permissions []role.Permission
+}
+
+func (f *fakeServiceAccounts) ValidateAPIKey(_ context.Context, apiKey string) (*serviceaccount.ServiceAccount, error) {
+	if apiKey != f.key {
+		return nil, serviceaccount.ErrKeyNotFound
+	}
+	return &serviceaccount.ServiceAccount{
+		ID:    "sa-1",
+		Name:  "ingest-agent",
+		Roles: []*role.Role{{Name: "ingest", Permissions: f.permissions}},
+	}, nil
+}
+
+func newTestAuthenticator() *authenticator {
+	return &authenticator{
+		userService: &fakeUsers{key: "user-key", permissions: map[string]bool{"assets:view": true}},
+		serviceAccountService: &fakeServiceAccounts{
+			key:         "sa-key",
+			permissions: []role.Permission{{ResourceType: "ingestion", Action: "manage"}},
+		},
+	}
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, key))
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+		wantType auth.PrincipalType
+	}{
+		{
+			name:     "unknown method",
+			ctx:      withAPIKey("user-key"),
+			method:   "/marmot.ingest.v1.IngestService/DeleteEverything",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "missing key",
+			ctx:      context.Background(),
+			method:   ingestv1.IngestService_GetAsset_FullMethodName,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "empty key",
+			ctx:      withAPIKey(""),
+			method:   ingestv1.IngestService_GetAsset_FullMethodName,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid key",
+			ctx:      withAPIKey("nope"),
+			method:   ingestv1.IngestService_GetAsset_FullMethodName,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "user with permission",
+			ctx:      withAPIKey("user-key"),
+			method:   ingestv1.IngestService_GetAsset_FullMethodName,
+			wantType: auth.PrincipalTypeUser,
+		},
+		{
+			name:     "user without permission",
+			ctx:      withAPIKey("user-key"),
+			method:   ingestv1.IngestService_StartRun_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "service account with permission",
+			ctx:      withAPIKey("sa-key"),
+			method:   ingestv1.IngestService_Ingest_FullMethodName,
+			wantType: auth.PrincipalTypeServiceAccount,
+		},
+		{
+			name:     "service account without permission",
+			ctx:      withAPIKey("sa-key"),
+			method:   ingestv1.IngestService_GetLineage_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	a := newTestAuthenticator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := a.authenticate(tt.ctx, tt.method)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			p, ok := principalFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantType, p.Type())
+		})
+	}
+}
+
+func TestAuthenticateWithoutServiceAccounts(t *testing.T) {
+	a := newTestAuthenticator()
+	a.serviceAccountService = nil
+
+	_, err := a.authenticate(withAPIKey("sa-key"), ingestv1.IngestService_Ingest_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthenticateRunsAuthorizationHook(t *testing.T) {
+	// An OPA policy that keeps service accounts from ingesting.
+	var inputs []authz.Input
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input authz.Input `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		inputs = append(inputs, body.Input)
+
+		allow := body.Input.Subject.Type != string(auth.PrincipalTypeServiceAccount)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"result": map[string]any{"allow": allow, "reason": "service accounts may not ingest"},
+		})
+	}))
+	defer opa.Close()
+
+	a := newTestAuthenticator()
+	a.authz = authz.NewService(authz.NewOPAHook(opa.URL, time.Second), nil, false)
+
+	called := false
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: ingestv1.IngestService_Ingest_FullMethodName}
+	_, err := a.unaryInterceptor(withAPIKey("sa-key"), nil, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "service accounts may not ingest")
+	assert.False(t, called, "a denied call doesn't reach the handler")
+
+	require.Len(t, inputs, 1)
+	assert.Equal(t, "ingestion", inputs[0].ResourceType)
+	assert.Equal(t, "manage", inputs[0].Action)
+	assert.Equal(t, ingestv1.IngestService_Ingest_FullMethodName, inputs[0].Request.Route)
+
+	// The policy lets users through.
+	_, err = a.authenticate(withAPIKey("user-key"), ingestv1.IngestService_GetAsset_FullMethodName)
+	require.NoError(t, err)
+}
+
+func TestAuthenticateHookUnavailable(t *testing.T) {
+	a := newTestAuthenticator()
+	a.authz = authz.NewService(authz.NewOPAHook("http://127.0.0.1:1", time.Second), nil, false)
+
+	_, err := a.authenticate(withAPIKey("user-key"), ingestv1.IngestService_GetAsset_FullMethodName)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
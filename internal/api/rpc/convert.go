@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/plugin"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func assetInputFromProto(a *ingestv1.Asset) (runs.CreateAssetInput, error) {
+	if a.GetName() == "" || a.GetType() == "" || len(a.GetProviders()) == 0 {
+		return runs.CreateAssetInput{}, fmt.Errorf("asset %q: name, type and at least one provider are required", a.GetName())
+	}
+
+	input := runs.CreateAssetInput{
+		Name:          a.GetName(),
+		Type:          a.GetType(),
+		Providers:     a.GetProviders(),
+		Description:   a.Description,
+		Metadata:      a.GetMetadata().AsMap(),
+		Schema:        a.GetSchema().AsMap(),
+		Tags:          a.GetTags(),
+		Sources:       a.GetSources(),
+		Query:         a.Query,
+		QueryLanguage: a.QueryLanguage,
+	}
+	if a.GetMrn() != "" {
+		m := a.GetMrn()
+		input.MRN = &m
+	}
+	for _, link := range a.GetExternalLinks() {
+		input.ExternalLinks = append(input.ExternalLinks, map[string]string{
+			"name": link.GetName(),
+			"url":  link.GetUrl(),
+			"icon": link.GetIcon(),
+		})
+	}
+	return input, nil
+}
+
+func lineageInputFromProto(l *ingestv1.Lineage) runs.LineageInput {
+	input := runs.LineageInput{Source: l.GetSource(), Target: l.GetTarget(), Type: l.GetType()}
+	for _, c := range l.GetColumns() {
+		input.Columns = append(input.Columns, lineage.ColumnMapping{
+			SourceColumn:   c.GetSourceColumn(),
+			TargetColumn:   c.GetTargetColumn(),
+			Transformation: c.GetTransformation(),
+		})
+	}
+	return input
+}
+
+func assetToProto(a *asset.Asset) (*ingestv1.Asset, error) {
+	out := &ingestv1.Asset{
+		Id:            a.ID,
+		Type:          a.Type,
+		Providers:     a.Providers,
+		Description:   a.Description,
+		Tags:          a.Tags,
+		Query:         a.Query,
+		QueryLanguage: a.QueryLanguage,
+	}
+	if a.MRN != nil {
+		out.Mrn = *a.MRN
+	}
+	if a.Name != nil {
+		out.Name = *a.Name
+	}
+	for _, src := range a.Sources {
+		out.Sources = append(out.Sources, src.Name)
+	}
+	for _, link := range a.ExternalLinks {
+		out.ExternalLinks = append(out.ExternalLinks, &ingestv1.ExternalLink{Name: link.Name, Url: link.URL, Icon: link.Icon})
+	}
+
+	var err error
+	if out.Metadata, err = toStruct(a.Metadata); err != nil {
+		return nil, fmt.Errorf("converting metadata: %w", err)
+	}
+	if out.Schema, err = toStruct(a.Schema); err != nil {
+		return nil, fmt.Errorf("converting schema: %w", err)
+	}
+	return out, nil
+}
+
+// toStruct converts a JSON-shaped value to a Struct. Values are round-tripped
+// through JSON first, since structpb only accepts plain maps and slices.
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	return structpb.NewStruct(m)
+}
+
+func ingestResponseFromResults(batch uint64, resp *runs.ProcessAssetsResponse) *ingestv1.IngestResponse {
+	out := &ingestv1.IngestResponse{
+		Batch:   batch,
+		Summary: countsToProto(resp.Summary),
+	}
+	for _, r := range resp.Assets {
+		out.Results = append(out.Results, &ingestv1.EntityResult{EntityType: "asset", Mrn: r.MRN, Status: r.Status, Error: r.Error})
+	}
+	for _, r := range resp.Lineage {
+		out.Results = append(out.Results, &ingestv1.EntityResult{EntityType: "lineage", Source: r.Source, Target: r.Target, Status: r.Status, Error: r.Error})
+	}
+	for _, r := range resp.Documentation {
+		out.Results = append(out.Results, &ingestv1.EntityResult{EntityType: "documentation", Mrn: r.AssetMRN, Status: r.Status, Error: r.Error})
+	}
+	return out
+}
+
+func countsToProto(summary plugin.EntitySummary) map[string]*ingestv1.EntityCounts {
+	out := make(map[string]*ingestv1.EntityCounts, len(summary))
+	for entityType, c := range summary {
+		out[entityType] = &ingestv1.EntityCounts{
+			Created:   int64(c.Created),
+			Updated:   int64(c.Updated),
+			Unchanged: int64(c.Unchanged),
+			Deleted:   int64(c.Deleted),
+			Failed:    int64(c.Failed),
+		}
+	}
+	return out
+}
+
+// runSummaryFromProto builds the run summary stored on completion, keeping
+// the flat counters the UI and CLI read in step with the per-entity ones.
+func runSummaryFromProto(entities map[string]*ingestv1.EntityCounts) *plugin.RunSummary {
+	summary := &plugin.RunSummary{Entities: plugin.EntitySummary{}}
+	for entityType, c := range entities {
+		counts := &plugin.EntityCounts{
+			Created:   int(c.GetCreated()),
+			Updated:   int(c.GetUpdated()),
+			Unchanged: int(c.GetUnchanged()),
+			Deleted:   int(c.GetDeleted()),
+			Failed:    int(c.GetFailed()),
+		}
+		summary.Entities[entityType] = counts
+		summary.ErrorsCount += counts.Failed
+		summary.TotalEntities += counts.Created + counts.Updated + counts.Unchanged + counts.Failed
+
+		switch entityType {
+		case "asset":
+			summary.AssetsCreated = counts.Created
+			summary.AssetsUpdated = counts.Updated
+			summary.AssetsDeleted = counts.Deleted
+		case "lineage":
+			summary.LineageCreated = counts.Created
+			summary.LineageUpdated = counts.Updated
+		case "documentation":
+			summary.DocumentationAdded = counts.Created
+		}
+	}
+	return summary
+}
+
+func lineageToProto(resp *lineage.LineageResponse) *ingestv1.LineageGraph {
+	out := &ingestv1.LineageGraph{}
+	for _, n := range resp.Nodes {
+		node := &ingestv1.LineageNode{Mrn: n.ID, Type: n.Type, Depth: int32(n.Depth)}
+		if n.Asset != nil {
+			if n.Asset.Name != nil {
+				node.Name = *n.Asset.Name
+			}
+			node.Type = n.Asset.Type
+		}
+		out.Nodes = append(out.Nodes, node)
+	}
+	for _, e := range resp.Edges {
+		out.Edges = append(out.Edges, &ingestv1.Lineage{Source: e.Source, Target: e.Target, Type: e.Type})
+	}
+	return out
+}
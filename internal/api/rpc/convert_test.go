@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestAssetInputFromProto(t *testing.T) {
+	metadata, err := structpb.NewStruct(map[string]interface{}{"owner": "data-team", "rows": 42.0})
+	require.NoError(t, err)
+
+	input, err := assetInputFromProto(&ingestv1.Asset{
+		Name:          "orders",
+		Type:          "Table",
+		Providers:     []string{"PostgreSQL"},
+		Metadata:      metadata,
+		ExternalLinks: []*ingestv1.ExternalLink{{Name: "Docs", Url: "https://example.com"}},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, input.MRN)
+	assert.Equal(t, map[string]interface{}{"owner": "data-team", "rows": 42.0}, input.Metadata)
+	assert.Equal(t, "https://example.com", input.ExternalLinks[0]["url"])
+
+	_, err = assetInputFromProto(&ingestv1.Asset{Name: "orders", Type: "Table"})
+	assert.Error(t, err, "an asset without a provider can't be given an MRN")
+}
+
+func TestLineageInputFromProto(t *testing.T) {
+	input := lineageInputFromProto(&ingestv1.Lineage{
+		Source: "mrn://table/postgres/orders",
+		Target: "mrn://table/postgres/revenue",
+		Type:   "DIRECT",
+		Columns: []*ingestv1.ColumnMapping{
+			{SourceColumn: "amount", TargetColumn: "total", Transformation: "SUM(amount)"},
+			{SourceColumn: "id", TargetColumn: "order_id"},
+		},
+	})
+
+	assert.Equal(t, "mrn://table/postgres/revenue", input.Target)
+	assert.Equal(t, []lineage.ColumnMapping{
+		{SourceColumn: "amount", TargetColumn: "total", Transformation: "SUM(amount)"},
+		{SourceColumn: "id", TargetColumn: "order_id"},
+	}, input.Columns)
+
+	assert.Nil(t, lineageInputFromProto(&ingestv1.Lineage{Source: "a", Target: "b"}).Columns)
+}
+
+func TestRunSummaryFromProto(t *testing.T) {
+	summary := runSummaryFromProto(map[string]*ingestv1.EntityCounts{
+		"asset":   {Created: 3, Updated: 2, Unchanged: 10, Failed: 1},
+		"lineage": {Created: 4},
+	})
+
+	assert.Equal(t, 3, summary.AssetsCreated)
+	assert.Equal(t, 2, summary.AssetsUpdated)
+	assert.Equal(t, 4, summary.LineageCreated)
+	assert.Equal(t, 1, summary.ErrorsCount)
+	assert.Equal(t, 20, summary.TotalEntities)
+	assert.Equal(t, 10, summary.Entities["asset"].Unchanged)
+}
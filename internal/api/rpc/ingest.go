@@ -0,0 +1,218 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/core/quota"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/plugin"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxBatchEntities bounds the entities in one IngestRequest.
+	maxBatchEntities = 5000
+	// maxLineageDepth caps the hops GetLineage follows from the asset.
+	maxLineageDepth = 20
+)
+
+type ingestServer struct {
+	ingestv1.UnimplementedIngestServiceServer
+
+	runService     runs.Service
+	scheduleSvc    *runs.ScheduleService
+	assetService   asset.Service
+	lineageService lineage.Service
+}
+
+func (s *ingestServer) StartRun(ctx context.Context, req *ingestv1.StartRunRequest) (*ingestv1.Run, error) {
+	if req.GetPipelineName() == "" || req.GetSourceName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "pipeline_name and source_name are required")
+	}
+	if !plugin.GetLoadState().Ready() {
+		return nil, status.Error(codes.Unavailable, "Plugins are still loading")
+	}
+
+	createdBy := principalName(ctx)
+	run, err := s.runService.StartRun(ctx, req.GetPipelineName(), req.GetSourceName(), createdBy, plugin.RawPluginConfig(req.GetConfig().AsMap()))
+	if err != nil {
+		return nil, toStatus(err, "Failed to start run")
+	}
+
+	if s.scheduleSvc != nil {
+		if _, err := s.scheduleSvc.CreateCLIJobRun(ctx, req.GetPipelineName(), req.GetSourceName(), run.ID, createdBy); err != nil {
+			log.Warn().Err(err).Msg("Failed to create job run for gRPC ingestion")
+		}
+	}
+
+	return &ingestv1.Run{
+		Id:           run.ID,
+		RunId:        run.RunID,
+		PipelineName: run.PipelineName,
+		SourceName:   run.SourceName,
+		Status:       string(run.Status),
+	}, nil
+}
+
+// Ingest stores each batch as it arrives and answers it before reading the
+// next, so a slow database pushes back on the agent through flow control.
+// The run is looked up for every batch, so a run completed or cancelled
+// elsewhere stops accepting batches straight away.
+func (s *ingestServer) Ingest(stream grpc.BidiStreamingServer[ingestv1.IngestRequest, ingestv1.IngestResponse]) error {
+	ctx := stream.Context()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		total := len(req.GetAssets()) + len(req.GetLineage()) + len(req.GetDocumentation()) + len(req.GetStatistics())
+		if total > maxBatchEntities {
+			return status.Errorf(codes.InvalidArgument, "batch %d has %d entities, the limit is %d", req.GetBatch(), total, maxBatchEntities)
+		}
+
+		run, err := s.runService.GetByRunID(ctx, req.GetRunId())
+		if err != nil {
+			return toStatus(err, "Failed to get run")
+		}
+		if run.Status != plugin.StatusRunning {
+			return status.Errorf(codes.FailedPrecondition, "run %s is %s", run.RunID, run.Status)
+		}
+
+		assets := make([]runs.CreateAssetInput, 0, len(req.GetAssets()))
+		for _, a := range req.GetAssets() {
+			input, err := assetInputFromProto(a)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "batch %d: %v", req.GetBatch(), err)
+			}
+			assets = append(assets, input)
+		}
+		lineageInputs := make([]runs.LineageInput, 0, len(req.GetLineage()))
+		for _, l := range req.GetLineage() {
+			lineageInputs = append(lineageInputs, lineageInputFromProto(l))
+		}
+		docs := make([]runs.DocumentationInput, 0, len(req.GetDocumentation()))
+		for _, d := range req.GetDocumentation() {
+			docs = append(docs, runs.DocumentationInput{AssetMRN: d.GetAssetMrn(), Content: d.GetContent(), Type: d.GetType()})
+		}
+		stats := make([]runs.StatisticInput, 0, len(req.GetStatistics()))
+		for _, st := range req.GetStatistics() {
+			stats = append(stats, runs.StatisticInput{AssetMRN: st.GetAssetMrn(), MetricName: st.GetMetricName(), Value: st.GetValue()})
+		}
+
+		resp, err := s.runService.ProcessEntityBatch(ctx, run.RunID, assets, lineageInputs, docs, stats, run.PipelineName, run.SourceName)
+		if err != nil {
+			return toStatus(err, fmt.Sprintf("Failed to process batch %d", req.GetBatch()))
+		}
+
+		if err := stream.Send(ingestResponseFromResults(req.GetBatch(), resp)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *ingestServer) CompleteRun(ctx context.Context, req *ingestv1.CompleteRunRequest) (*ingestv1.CompleteRunResponse, error) {
+	runStatus := plugin.RunStatus(req.GetStatus())
+	summary := runSummaryFromProto(req.GetEntities())
+
+	resp := &ingestv1.CompleteRunResponse{}
+	if runStatus == plugin.StatusCompleted {
+		stale, err := s.runService.RemoveStaleAssets(ctx, req.GetRunId())
+		if err != nil {
+			return nil, toStatus(err, "Failed to remove stale assets")
+		}
+		summary.Entities.Merge(stale.Summary)
+		summary.AssetsDeleted += len(stale.StaleEntitiesRemoved)
+		resp.StaleAssetsRemoved = stale.StaleEntitiesRemoved
+	}
+
+	if err := s.runService.CompleteRun(ctx, req.GetRunId(), runStatus, summary, req.GetError()); err != nil {
+		return nil, toStatus(err, "Failed to complete run")
+	}
+
+	return resp, nil
+}
+
+func (s *ingestServer) GetAsset(ctx context.Context, req *ingestv1.GetAssetRequest) (*ingestv1.Asset, error) {
+	if req.GetMrn() == "" {
+		return nil, status.Error(codes.InvalidArgument, "mrn is required")
+	}
+
+	a, err := s.assetService.GetByMRN(ctx, req.GetMrn())
+	if err != nil {
+		return nil, toStatus(err, "Failed to get asset")
+	}
+
+	out, err := assetToProto(a)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to convert asset: %v", err)
+	}
+	return out, nil
+}
+
+func (s *ingestServer) GetLineage(ctx context.Context, req *ingestv1.GetLineageRequest) (*ingestv1.LineageGraph, error) {
+	if req.GetMrn() == "" {
+		return nil, status.Error(codes.InvalidArgument, "mrn is required")
+	}
+
+	depth := int(req.GetDepth())
+	if depth <= 0 {
+		depth = 10
+	}
+	depth = min(depth, maxLineageDepth)
+	direction := req.GetDirection()
+	if direction == "" {
+		direction = "both"
+	}
+
+	a, err := s.assetService.GetByMRN(ctx, req.GetMrn())
+	if err != nil {
+		return nil, toStatus(err, "Failed to get asset")
+	}
+
+	graph, err := s.lineageService.GetAssetLineage(ctx, a.ID, depth, direction)
+	if err != nil {
+		return nil, toStatus(err, "Failed to get lineage")
+	}
+	return lineageToProto(graph), nil
+}
+
+func principalName(ctx context.Context) string {
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return auth.CreatedBy(p)
+}
+
+// toStatus maps service errors to gRPC codes the way the REST handlers map
+// them to HTTP statuses.
+func toStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, runs.ErrInvalidInput), errors.Is(err, asset.ErrInvalidInput):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, runs.ErrInvalidStatus):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, runs.ErrNotFound), errors.Is(err, asset.ErrAssetNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, quota.ErrExceeded):
+		return status.Errorf(codes.ResourceExhausted, "%s: %v", msg, err)
+	default:
+		log.Error().Err(err).Msg(msg)
+		return status.Error(codes.Internal, msg)
+	}
+}
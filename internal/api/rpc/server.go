@@ -0,0 +1,112 @@
+// Package rpc serves the gRPC ingestion API defined in
+// proto/marmot/ingest/v1/ingest.proto.
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/authz"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/core/serviceaccount"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+	ingestv1 "github.com/marmotdata/marmot/pkg/ingest/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Services are the core services the gRPC API delegates to.
+type Services struct {
+	Runs            runs.Service
+	Schedules       *runs.ScheduleService
+	Assets          asset.Service
+	Lineage         lineage.Service
+	Users           user.Service
+	ServiceAccounts serviceaccount.Service
+	// Authz runs authorization hooks after the role check. Nil disables them.
+	Authz *authz.Service
+}
+
+// stopTimeout bounds how long Stop waits for open ingestion streams.
+const stopTimeout = 10 * time.Second
+
+type Server struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+// NewServer builds the gRPC server. It uses the REST server's TLS settings
+// when those are configured.
+func NewServer(cfg *config.Config, svcs Services) (*Server, error) {
+	auth := &authenticator{
+		userService:           svcs.Users,
+		serviceAccountService: svcs.ServiceAccounts,
+		authz:                 svcs.Authz,
+	}
+
+	maxSize := cfg.GRPC.MaxMessageSize * 1024 * 1024
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxSize),
+		grpc.MaxSendMsgSize(maxSize),
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	}
+	if cfg.Server.TLS != nil {
+		tlsCfg, err := cfg.Server.TLS.ToServerTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("configuring gRPC TLS: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	ingestv1.RegisterIngestServiceServer(grpcServer, &ingestServer{
+		runService:     svcs.Runs,
+		scheduleSvc:    svcs.Schedules,
+		assetService:   svcs.Assets,
+		lineageService: svcs.Lineage,
+	})
+
+	return &Server{
+		grpcServer: grpcServer,
+		addr:       fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.GRPC.Port),
+	}, nil
+}
+
+// Start listens on the configured port and serves in the background.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.addr, err)
+	}
+
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			log.Error().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
+	log.Info().Str("address", s.addr).Msg("gRPC server started")
+	return nil
+}
+
+// Stop waits for in-flight calls to finish, up to stopTimeout, and stops the
+// server.
+func (s *Server) Stop() {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(stopTimeout):
+		s.grpcServer.Stop()
+	}
+}
@@ -4,14 +4,19 @@ import (
 	"net/http"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
-	"github.com/marmotdata/marmot/pkg/config"
 	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/demo"
 	"github.com/marmotdata/marmot/internal/core/search"
 	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/internal/telemetry"
+	"github.com/marmotdata/marmot/pkg/config"
 )
 
 type Handler struct {
 	reindexer   *search.Reindexer
+	rebuilder   *search.Rebuilder
+	demoSeeder  *demo.Seeder
+	telemetry   *telemetry.Collector
 	userService user.Service
 	authService auth.Service
 	config      *config.Config
@@ -19,12 +24,18 @@ type Handler struct {
 
 func NewHandler(
 	reindexer *search.Reindexer,
+	rebuilder *search.Rebuilder,
+	demoSeeder *demo.Seeder,
+	telemetryCollector *telemetry.Collector,
 	userService user.Service,
 	authService auth.Service,
 	config *config.Config,
 ) *Handler {
 	return &Handler{
 		reindexer:   reindexer,
+		rebuilder:   rebuilder,
+		demoSeeder:  demoSeeder,
+		telemetry:   telemetryCollector,
 		userService: userService,
 		authService: authService,
 		config:      config,
@@ -50,5 +61,29 @@ func (h *Handler) Routes() []common.Route {
 			Handler:    h.getReindexStatus,
 			Middleware: authMiddleware,
 		},
+		{
+			Path:       "/api/v1/admin/search/rebuild",
+			Method:     http.MethodPost,
+			Handler:    h.startRebuild,
+			Middleware: authMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/search/rebuild",
+			Method:     http.MethodGet,
+			Handler:    h.getRebuildStatus,
+			Middleware: authMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/demo/seed",
+			Method:     http.MethodPost,
+			Handler:    h.seedDemo,
+			Middleware: authMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/telemetry/preview",
+			Method:     http.MethodGet,
+			Handler:    h.previewTelemetry,
+			Middleware: authMiddleware,
+		},
 	}
 }
@@ -2,12 +2,15 @@ package admin
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
 	"github.com/marmotdata/marmot/internal/core/search"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/rs/zerolog/log"
 )
 
 type ReindexAcceptedResponse struct {
@@ -76,3 +79,93 @@ func (h *Handler) getReindexStatus(w http.ResponseWriter, r *http.Request) {
 		ESConfigured: h.reindexer != nil,
 	})
 }
+
+// @Summary Start search rebuild
+// @Description Rebuild PostgreSQL search vectors and the unified search index for one asset, all assets from a provider, or everything. A global rebuild also rebuilds glossary, team and data product entries and the full-text and trigram indexes. Runs asynchronously; poll the status endpoint for progress. Needed after indexing rules change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body search.RebuildRequest true "Rebuild scope"
+// @Success 202 {object} search.RebuildStatus
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Router /admin/search/rebuild [post]
+func (h *Handler) startRebuild(w http.ResponseWriter, r *http.Request) {
+	var req search.RebuildRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.rebuilder.Start(req); err != nil {
+		switch {
+		case errors.Is(err, search.ErrInvalidRebuildScope):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, search.ErrRebuildInProgress):
+			common.RespondError(w, http.StatusConflict, "Rebuild already in progress")
+		default:
+			common.RespondError(w, http.StatusInternalServerError, "Failed to start rebuild")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusAccepted, h.rebuilder.Status())
+}
+
+// @Summary Get search rebuild status
+// @Description Get the progress of the current or most recent search rebuild.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} search.RebuildStatus
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Router /admin/search/rebuild [get]
+func (h *Handler) getRebuildStatus(w http.ResponseWriter, r *http.Request) {
+	common.RespondJSON(w, http.StatusOK, h.rebuilder.Status())
+}
+
+// @Summary Seed demo data
+// @Description Populate the catalog with an example shop: assets across several providers, a lineage graph, documentation, glossary terms, data products and run history. Seeding is idempotent; entities that already exist are left unchanged.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} demo.Result
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/demo/seed [post]
+func (h *Handler) seedDemo(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	result, err := h.demoSeeder.Seed(r.Context(), usr.ID, usr.Username)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to seed demo data")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to seed demo data")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Preview telemetry
+// @Description Show exactly what the next anonymous telemetry report would contain, including opt-in feature usage counts when enabled. Nothing is sent. The preview is built even when telemetry is disabled, to show what enabling it would report.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} telemetry.Preview
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 503 {object} common.ErrorResponse
+// @Router /admin/telemetry/preview [get]
+func (h *Handler) previewTelemetry(w http.ResponseWriter, r *http.Request) {
+	if h.telemetry == nil {
+		common.RespondError(w, http.StatusServiceUnavailable, "Telemetry is not available")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, h.telemetry.Preview(r.Context()))
+}
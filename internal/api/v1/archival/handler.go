@@ -0,0 +1,92 @@
+package archival
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/archival"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *archival.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *archival.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/archival/candidates",
+			Method:  http.MethodGet,
+			Handler: h.listCandidates,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/archival/candidates/{assetId}/exempt",
+			Method:  http.MethodPut,
+			Handler: h.setExempt,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/archival/assets",
+			Method:  http.MethodGet,
+			Handler: h.listArchived,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/archival/assets/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getArchived,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/archival/assets/{id}/restore",
+			Method:  http.MethodPost,
+			Handler: h.restoreArchived,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/admin/archival/run",
+			Method:  http.MethodPost,
+			Handler: h.runArchival,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+				common.WithRateLimit(h.config, 5, 60),
+			},
+		},
+	}
+}
@@ -0,0 +1,205 @@
+package archival
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/archival"
+	"github.com/rs/zerolog/log"
+)
+
+type ExemptRequest struct {
+	Exempt bool `json:"exempt"`
+} // @name ArchivalExemptRequest
+
+type CandidatesResponse struct {
+	Candidates []*archival.Candidate `json:"candidates"`
+	Total      int                   `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+} // @name ArchivalCandidatesResponse
+
+type ArchivedResponse struct {
+	Assets []*archival.ArchivedAsset `json:"assets"`
+	Total  int                       `json:"total"`
+	Limit  int                       `json:"limit"`
+	Offset int                       `json:"offset"`
+} // @name ArchivedAssetsResponse
+
+// @Summary List archival candidates
+// @Description List assets flagged as stale and the date they will be archived
+// @Tags archival
+// @Produce json
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} CandidatesResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /archival/candidates [get]
+func (h *Handler) listCandidates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	candidates, total, err := h.svc.ListCandidates(r.Context(), limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list archival candidates")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list archival candidates")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, CandidatesResponse{
+		Candidates: candidates,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// @Summary Exempt asset from archival
+// @Description Keep a flagged asset from being archived, or make it eligible again. An exemption lasts until the asset is next synced.
+// @Tags archival
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param request body ExemptRequest true "Exemption"
+// @Success 200 {object} archival.Candidate
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /archival/candidates/{assetId}/exempt [put]
+func (h *Handler) setExempt(w http.ResponseWriter, r *http.Request) {
+	var req ExemptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var userID *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		userID = &usr.ID
+	}
+
+	candidate, err := h.svc.SetExempt(r.Context(), r.PathValue("assetId"), req.Exempt, userID)
+	if err != nil {
+		if errors.Is(err, archival.ErrCandidateMissing) {
+			common.RespondError(w, http.StatusNotFound, "Asset is not flagged for archival")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to update archival exemption")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to update archival exemption")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, candidate)
+}
+
+// @Summary List archived assets
+// @Description List assets archived by the archival policy, most recent first
+// @Tags archival
+// @Produce json
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ArchivedResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /archival/assets [get]
+func (h *Handler) listArchived(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	archived, total, err := h.svc.ListArchived(r.Context(), limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list archived assets")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list archived assets")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ArchivedResponse{
+		Assets: archived,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// @Summary Get archived asset
+// @Description Get an archived asset with a snapshot of it and its owners when it was archived
+// @Tags archival
+// @Produce json
+// @Param id path string true "Archived asset ID"
+// @Success 200 {object} archival.ArchivedAsset
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /archival/assets/{id} [get]
+func (h *Handler) getArchived(w http.ResponseWriter, r *http.Request) {
+	archived, err := h.svc.GetArchived(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, archival.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Archived asset not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get archived asset")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get archived asset")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, archived)
+}
+
+// @Summary Restore archived asset
+// @Description Return an archived asset to search and listings
+// @Tags archival
+// @Produce json
+// @Param id path string true "Archived asset ID"
+// @Success 200 {object} asset.Asset
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /archival/assets/{id}/restore [post]
+func (h *Handler) restoreArchived(w http.ResponseWriter, r *http.Request) {
+	restored, err := h.svc.Restore(r.Context(), r.PathValue("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, archival.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Archived asset not found")
+		case errors.Is(err, archival.ErrAssetExists):
+			common.RespondError(w, http.StatusConflict, "An asset with this MRN already exists")
+		default:
+			log.Error().Err(err).Msg("Failed to restore archived asset")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to restore archived asset")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, restored)
+}
+
+// @Summary Run archival policy
+// @Description Restore archived assets that were synced again, flag stale assets, archive candidates whose grace period has expired and archive assets whose time to live has run out
+// @Tags admin
+// @Produce json
+// @Success 200 {object} archival.RunResult
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/archival/run [post]
+func (h *Handler) runArchival(w http.ResponseWriter, r *http.Request) {
+	result, err := h.svc.RunOnce(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run archival policy")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to run archival policy")
+		return
+	}
+
+	expired, err := h.svc.ExpireOnce(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to archive expired assets")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to archive expired assets")
+		return
+	}
+	result.Expired = expired
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,56 @@
+package assethealth
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assethealth"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *assethealth.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *assethealth.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/assets/health/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getHealth,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/quality-results/{id}",
+			Method:  http.MethodPost,
+			Handler: h.recordQualityResults,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+				common.WithRateLimit(h.config, 60, 60),
+			},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package assethealth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assethealth"
+	"github.com/rs/zerolog/log"
+)
+
+// QualityResultsRequest reports the results of quality checks run against
+// an asset.
+type QualityResultsRequest struct {
+	Results []assethealth.QualityResultInput `json:"results"`
+} // @name QualityResultsRequest
+
+// @Summary Get asset health
+// @Description Computes an asset's health from its freshness expectation, the latest result of each quality check and its recent runs. The status is the worst of the contributing factors, or unknown when there are none.
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} assethealth.Health
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/health/{id} [get]
+func (h *Handler) getHealth(w http.ResponseWriter, r *http.Request) {
+	health, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, assethealth.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+			return
+		}
+		log.Error().Err(err).Str("asset_id", r.PathValue("id")).Msg("Failed to compute asset health")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to compute asset health")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, health)
+}
+
+// @Summary Report quality check results
+// @Description Records the results of data quality checks, such as dbt tests, run against an asset. The latest result of each check contributes to the asset's health.
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param results body QualityResultsRequest true "Check results"
+// @Success 201 {array} assethealth.QualityResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/quality-results/{id} [post]
+func (h *Handler) recordQualityResults(w http.ResponseWriter, r *http.Request) {
+	var req QualityResultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var reportedBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		reportedBy = &usr.ID
+	}
+
+	results, err := h.svc.RecordQualityResults(r.Context(), r.PathValue("id"), req.Results, reportedBy)
+	if err != nil {
+		switch {
+		case assethealth.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, assethealth.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("asset_id", r.PathValue("id")).Msg("Failed to record quality results")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to record quality results")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, results)
+}
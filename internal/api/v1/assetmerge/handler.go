@@ -0,0 +1,73 @@
+package assetmerge
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assetmerge"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *assetmerge.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *assetmerge.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/admin/assets/merge",
+			Method:  http.MethodPost,
+			Handler: h.mergeAssets,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/admin/assets/duplicates",
+			Method:  http.MethodGet,
+			Handler: h.listDuplicates,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/admin/assets/duplicates/{id}/dismiss",
+			Method:  http.MethodPost,
+			Handler: h.dismissDuplicate,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/admin/assets/duplicates/{id}/merge",
+			Method:  http.MethodPost,
+			Handler: h.mergeDuplicate,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+	}
+}
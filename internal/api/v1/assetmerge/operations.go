@@ -0,0 +1,177 @@
+package assetmerge
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assetmerge"
+	"github.com/rs/zerolog/log"
+)
+
+type MergeRequest struct {
+	// SourceMRN is the asset merged away and deleted.
+	SourceMRN string `json:"source_mrn" example:"mrn://table/postgres/public.orders"`
+	// TargetMRN is the asset that survives.
+	TargetMRN string `json:"target_mrn" example:"mrn://table/postgres/orders"`
+} // @name AssetMergeRequest
+
+// @Summary Merge two assets
+// @Description Merge a duplicate asset into another, such as a lineage stub into the real asset or the same table reported by two plugins under different MRNs. Fields are combined with the target's values winning, unless the target is a stub. Lineage, data product memberships, owners, glossary terms, run history, subscriptions, watches and favorites move to the target, the source is deleted, and the merge is recorded in the target's run history.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body MergeRequest true "Assets to merge"
+// @Success 200 {object} assetmerge.Result
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/assets/merge [post]
+func (h *Handler) mergeAssets(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Merge(r.Context(), assetmerge.Input{
+		SourceMRN: req.SourceMRN,
+		TargetMRN: req.TargetMRN,
+	}, usr.Username)
+	if err != nil {
+		switch {
+		case assetmerge.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, assetmerge.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("source_mrn", req.SourceMRN).Str("target_mrn", req.TargetMRN).Msg("Failed to merge assets")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to merge assets")
+		}
+		return
+	}
+
+	log.Info().Str("source_mrn", req.SourceMRN).Str("target_mrn", req.TargetMRN).Str("user", usr.Username).Msg("Assets merged")
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+type MergeDuplicateRequest struct {
+	// TargetMRN is the asset that survives: either of the pair. Defaults
+	// to the asset that already existed.
+	TargetMRN string `json:"target_mrn,omitempty" example:"mrn://table/postgres/orders"`
+} // @name AssetDuplicateMergeRequest
+
+func respondDuplicateError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case assetmerge.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, assetmerge.ErrDuplicateNotFound):
+		common.RespondError(w, http.StatusNotFound, "Duplicate not found")
+	case errors.Is(err, assetmerge.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List possible duplicate assets
+// @Description List pairs of assets flagged as possible duplicates when a pipeline run created one of them: same name, under a different MRN, with a similar schema.
+// @Tags admin
+// @Produce json
+// @Param status query string false "open or dismissed" default(open)
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} assetmerge.DuplicateList
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/assets/duplicates [get]
+func (h *Handler) listDuplicates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListDuplicates(r.Context(), query.Get("status"), limit, offset)
+	if err != nil {
+		respondDuplicateError(w, err, "Failed to list duplicate assets")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Dismiss a possible duplicate
+// @Description Mark a flagged pair as not duplicates. The pair leaves the review queue and isn't flagged again.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Duplicate ID"
+// @Success 200 {object} assetmerge.Duplicate
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/assets/duplicates/{id}/dismiss [post]
+func (h *Handler) dismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	dup, err := h.svc.DismissDuplicate(r.Context(), r.PathValue("id"), usr.Username)
+	if err != nil {
+		respondDuplicateError(w, err, "Failed to dismiss duplicate")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, dup)
+}
+
+// @Summary Merge a possible duplicate
+// @Description Merge a flagged pair of assets, as the merge endpoint does. The asset that already existed survives unless target_mrn names the other one.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Duplicate ID"
+// @Param request body MergeDuplicateRequest false "Asset to keep"
+// @Success 200 {object} assetmerge.Result
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/assets/duplicates/{id}/merge [post]
+func (h *Handler) mergeDuplicate(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req MergeDuplicateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.MergeDuplicate(r.Context(), r.PathValue("id"), req.TargetMRN, usr.Username)
+	if err != nil {
+		respondDuplicateError(w, err, "Failed to merge duplicate")
+		return
+	}
+
+	log.Info().Str("source_mrn", result.MergedMRN).Str("user", usr.Username).Msg("Duplicate assets merged")
+	common.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,64 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Browse assets by group
+// @Description Browse assets as a tree of provider, database and schema, one level at a time. Pass the values of the levels above the one to expand: no parameters lists providers, provider lists its databases, provider and database list schemas, and all three list the schema's assets. An empty value selects the assets without one, such as a database parameter of "" for assets with no database.
+// @Tags assets
+// @Produce json
+// @Param provider query string false "Provider"
+// @Param database query string false "Database, catalog, project or bucket"
+// @Param schema query string false "Schema, dataset or namespace"
+// @Param limit query int false "Limit" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} asset.GroupResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/groups [get]
+func (h *Handler) browseAssetGroups(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var path asset.GroupPath
+	for _, level := range asset.GroupLevels {
+		if !query.Has(string(level)) {
+			break
+		}
+		path = append(path, query.Get(string(level)))
+	}
+	for _, level := range asset.GroupLevels[len(path):] {
+		if query.Has(string(level)) {
+			common.RespondError(w, http.StatusBadRequest, fmt.Sprintf("%s requires %s", level, asset.GroupLevels[len(path)]))
+			return
+		}
+	}
+
+	limit := common.ParseLimit(query.Get("limit"), 100, 1000)
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	result, err := h.assetService.Browse(r.Context(), path, limit, offset)
+	if err != nil {
+		if errors.Is(err, asset.ErrInvalidInput) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Strs("path", path).Msg("Failed to browse asset groups")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to browse assets")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
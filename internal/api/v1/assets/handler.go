@@ -7,16 +7,25 @@ import (
 	"github.com/marmotdata/marmot/pkg/config"
 	"github.com/marmotdata/marmot/internal/core/asset"
 	"github.com/marmotdata/marmot/internal/core/assetdocs"
+	"github.com/marmotdata/marmot/internal/core/assethealth"
 	"github.com/marmotdata/marmot/internal/core/assetrule"
 	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/mention"
 	"github.com/marmotdata/marmot/internal/core/runs"
 	"github.com/marmotdata/marmot/internal/core/team"
 	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/internal/core/watchlist"
 	"github.com/marmotdata/marmot/internal/crypto"
 	"github.com/marmotdata/marmot/internal/metrics"
 	"github.com/marmotdata/marmot/internal/telemetry/lookups"
 )
 
+// ViewRecorder counts users opening assets, so their searches can favour
+// assets they use.
+type ViewRecorder interface {
+	RecordView(userID, assetID string)
+}
+
 type Handler struct {
 	assetService     asset.Service
 	assetDocsService assetdocs.Service
@@ -27,9 +36,13 @@ type Handler struct {
 	scheduleService  *runs.ScheduleService
 	teamService      *team.Service
 	assetRuleService assetrule.Service
+	mentionService   *mention.Service
 	encryptor        *crypto.Encryptor
 	config           *config.Config
 	lookups          lookups.Recorder
+	views            ViewRecorder
+	healthService    *assethealth.Service
+	watchService     *watchlist.Service
 }
 
 func NewHandler(
@@ -42,9 +55,11 @@ func NewHandler(
 	scheduleService *runs.ScheduleService,
 	teamService *team.Service,
 	assetRuleService assetrule.Service,
+	mentionService *mention.Service,
 	encryptor *crypto.Encryptor,
 	config *config.Config,
 	lookupsRecorder lookups.Recorder,
+	viewRecorder ViewRecorder,
 ) *Handler {
 	return &Handler{
 		assetService:     assetService,
@@ -56,12 +71,24 @@ func NewHandler(
 		scheduleService:  scheduleService,
 		teamService:      teamService,
 		assetRuleService: assetRuleService,
+		mentionService:   mentionService,
 		encryptor:        encryptor,
 		config:           config,
 		lookups:          lookupsRecorder,
+		views:            viewRecorder,
 	}
 }
 
+// SetHealthService enables computed health on asset responses.
+func (h *Handler) SetHealthService(svc *assethealth.Service) {
+	h.healthService = svc
+}
+
+// SetWatchService enables watcher counts on asset responses.
+func (h *Handler) SetWatchService(svc *watchlist.Service) {
+	h.watchService = svc
+}
+
 func (h *Handler) Routes() []common.Route {
 	routes := []common.Route{
 		{
@@ -71,6 +98,7 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "assets", "manage"),
+				common.WithIdempotency(),
 			},
 		},
 		{
@@ -108,6 +136,7 @@ func (h *Handler) Routes() []common.Route {
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "assets", "view"),
 				common.WithRateLimit(h.config, 50, 60), // 50 requests per 60 seconds
+				common.WithQueryBudget(h.config),
 			},
 		},
 		{
@@ -130,6 +159,16 @@ func (h *Handler) Routes() []common.Route {
 				common.WithRateLimit(h.config, 30, 60), // 30 requests per 60 seconds
 			},
 		},
+		{
+			Path:    "/api/v1/assets/groups",
+			Method:  http.MethodGet,
+			Handler: h.browseAssetGroups,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 50, 60), // 50 requests per 60 seconds
+			},
+		},
 		{
 			Path:    "/api/v1/assets/qualified-name/{name}",
 			Method:  http.MethodGet,
@@ -182,6 +221,7 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "assets", "manage"),
+				common.WithIdempotency(),
 			},
 		},
 		{
@@ -214,6 +254,16 @@ func (h *Handler) Routes() []common.Route {
 				common.WithRateLimit(h.config, 50, 60), // 50 requests per 60 seconds
 			},
 		},
+		{
+			Path:    "/api/v1/assets/suggestions/metadata/profile",
+			Method:  http.MethodGet,
+			Handler: h.getMetadataProfile,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 20, 60), // 20 requests per 60 seconds
+			},
+		},
 		{
 			Path:    "/api/v1/assets/suggestions/tags",
 			Method:  http.MethodGet,
@@ -233,6 +283,15 @@ func (h *Handler) Routes() []common.Route {
 				common.RequirePermission(h.userService, "assets", "view"),
 			},
 		},
+		{
+			Path:    "/api/v1/assets/schema-tree/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getSchemaTree,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
 		{
 			Path:    "/api/v1/assets/run-history/{id}",
 			Method:  http.MethodGet,
@@ -290,6 +349,69 @@ func (h *Handler) Routes() []common.Route {
 				common.RequirePermission(h.userService, "assets", "view"),
 			},
 		},
+		{
+			Path:    "/api/v1/assets/locks/{id}",
+			Method:  http.MethodPut,
+			Handler: h.updateLocks,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/lifecycle/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getLifecycle,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/lifecycle/{id}",
+			Method:  http.MethodPut,
+			Handler: h.setLifecycle,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/revisions/{id}",
+			Method:  http.MethodGet,
+			Handler: h.listRevisions,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/revisions/{id}/diff",
+			Method:  http.MethodGet,
+			Handler: h.diffRevisions,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/revisions/{id}/{revision}",
+			Method:  http.MethodGet,
+			Handler: h.getRevision,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/assets/revisions/{id}/{revision}/revert",
+			Method:  http.MethodPost,
+			Handler: h.revertToRevision,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
 		{
 			Path:    "/api/v1/assets/owners/",
 			Method:  http.MethodGet,
@@ -0,0 +1,88 @@
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+// withBanner sets the configured banner for the lifecycle's status.
+func (h *Handler) withBanner(lifecycle *asset.Lifecycle) *asset.Lifecycle {
+	lifecycle.Banner = lifecycle.RenderBanner(h.config.Lifecycle.Banners[string(lifecycle.Status)])
+	return lifecycle
+}
+
+// @Summary Get asset lifecycle
+// @Description Get an asset's lifecycle status, with the deprecation reason and replacement for deprecated assets and the configured banner
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} asset.Lifecycle
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/lifecycle/{id} [get]
+func (h *Handler) getLifecycle(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	lifecycle, err := h.assetService.GetLifecycle(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+			return
+		}
+		log.Error().Err(err).Str("id", id).Msg("Failed to get asset lifecycle")
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, h.withBanner(lifecycle))
+}
+
+// @Summary Set asset lifecycle
+// @Description Move an asset to active, experimental, deprecated or archived. Deprecating an asset can name the replacement by MRN and a reason, and tracks its consumers like any other deprecation. Archived assets stay in the catalog; the archival policy is separate.
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param lifecycle body asset.LifecycleInput true "New lifecycle status"
+// @Success 200 {object} asset.Lifecycle
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/lifecycle/{id} [put]
+func (h *Handler) setLifecycle(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var input asset.LifecycleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var userID string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		userID = usr.ID
+	}
+
+	lifecycle, err := h.assetService.SetLifecycle(r.Context(), id, input, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		case errors.Is(err, asset.ErrReplacementNotFound):
+			common.RespondError(w, http.StatusBadRequest, "Replacement asset not found")
+		case errors.Is(err, asset.ErrInvalidInput):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Error().Err(err).Str("id", id).Msg("Failed to set asset lifecycle")
+			common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, h.withBanner(lifecycle))
+}
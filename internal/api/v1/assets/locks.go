@@ -0,0 +1,81 @@
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+type LockRequest struct {
+	Lock   []string `json:"lock,omitempty"`
+	Unlock []string `json:"unlock,omitempty"`
+} // @name AssetLockRequest
+
+// @Summary Lock or unlock asset fields
+// @Description Lock fields so ingestion runs don't overwrite them, or unlock them so the next run takes over again. Lockable fields are description and tags. Editing one of these fields by hand locks it automatically.
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param request body LockRequest true "Fields to lock and unlock"
+// @Success 200 {object} asset.Asset
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/locks/{id} [put]
+func (h *Handler) updateLocks(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		common.RespondError(w, http.StatusBadRequest, "Asset ID is required")
+		return
+	}
+
+	var req LockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var (
+		updated *asset.Asset
+		err     error
+	)
+	if len(req.Unlock) > 0 {
+		updated, err = h.assetService.UnlockFields(r.Context(), id, req.Unlock)
+	}
+	if err == nil && (len(req.Lock) > 0 || updated == nil) {
+		updated, err = h.assetService.LockFields(r.Context(), id, req.Lock)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		case errors.Is(err, asset.ErrInvalidInput):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Error().Err(err).Str("id", id).Msg("Failed to update asset locks")
+			common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, updated)
+}
+
+// lockEdited locks fields a user has just edited by hand. Failing to lock
+// doesn't fail the edit itself.
+func (h *Handler) lockEdited(r *http.Request, a *asset.Asset, fields ...string) {
+	if a == nil || len(fields) == 0 {
+		return
+	}
+	locked, err := h.assetService.LockFields(r.Context(), a.ID, fields)
+	if err != nil {
+		log.Warn().Err(err).Str("id", a.ID).Strs("fields", fields).Msg("Failed to lock edited asset fields")
+		return
+	}
+	a.LockedFields = locked.LockedFields
+}
@@ -0,0 +1,134 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockService records lock changes to a single asset, backed by the real
+// lock rules.
+type lockService struct {
+	asset.Service
+	calls []string
+}
+
+func newLockService(locked ...string) *lockService {
+	return &lockService{Service: asset.NewService(&lockRepo{asset: &asset.Asset{ID: "a1", LockedFields: locked}})}
+}
+
+func (s *lockService) LockFields(ctx context.Context, id string, fields []string) (*asset.Asset, error) {
+	s.calls = append(s.calls, "lock "+strings.Join(fields, ","))
+	return s.Service.LockFields(ctx, id, fields)
+}
+
+func (s *lockService) UnlockFields(ctx context.Context, id string, fields []string) (*asset.Asset, error) {
+	s.calls = append(s.calls, "unlock "+strings.Join(fields, ","))
+	return s.Service.UnlockFields(ctx, id, fields)
+}
+
+type lockRepo struct {
+	asset.Repository
+	asset *asset.Asset
+}
+
+func (r *lockRepo) Get(_ context.Context, id string) (*asset.Asset, error) {
+	if r.asset.ID != id {
+		return nil, asset.ErrNotFound
+	}
+	a := *r.asset
+	return &a, nil
+}
+
+func (r *lockRepo) SetLockedFields(_ context.Context, _ string, fields []string) error {
+	r.asset.LockedFields = fields
+	return nil
+}
+
+func doUpdateLocks(h *Handler, id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/assets/locks/"+id, strings.NewReader(body))
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+	h.updateLocks(rec, req)
+	return rec
+}
+
+func TestUpdateLocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		locked    []string
+		body      string
+		wantCalls []string
+		want      []string
+	}{
+		{
+			name:      "lock",
+			body:      `{"lock":["description"]}`,
+			wantCalls: []string{"lock description"},
+			want:      []string{"description"},
+		},
+		{
+			name:      "unlock",
+			locked:    []string{"description", "tags"},
+			body:      `{"unlock":["tags"]}`,
+			wantCalls: []string{"unlock tags"},
+			want:      []string{"description"},
+		},
+		{
+			name:      "unlock then lock",
+			locked:    []string{"description"},
+			body:      `{"lock":["tags"],"unlock":["description"]}`,
+			wantCalls: []string{"unlock description", "lock tags"},
+			want:      []string{"tags"},
+		},
+		{
+			name:      "empty request returns current locks",
+			locked:    []string{"tags"},
+			body:      `{}`,
+			wantCalls: []string{"lock "},
+			want:      []string{"tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newLockService(tt.locked...)
+			rec := doUpdateLocks(&Handler{assetService: svc}, "a1", tt.body)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var got asset.Asset
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got.LockedFields)
+			assert.Equal(t, tt.wantCalls, svc.calls)
+		})
+	}
+}
+
+func TestUpdateLocksErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{name: "invalid body", id: "a1", body: `{`, want: http.StatusBadRequest},
+		{name: "owners can't be locked", id: "a1", body: `{"lock":["owners"]}`, want: http.StatusBadRequest},
+		{name: "unknown field", id: "a1", body: `{"unlock":["name"]}`, want: http.StatusBadRequest},
+		{name: "unknown asset", id: "missing", body: `{"lock":["tags"]}`, want: http.StatusNotFound},
+		{name: "missing id", id: "", body: `{"lock":["tags"]}`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doUpdateLocks(&Handler{assetService: newLockService()}, tt.id, tt.body)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
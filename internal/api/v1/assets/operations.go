@@ -8,7 +8,9 @@ import (
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
 	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/assethealth"
 	"github.com/marmotdata/marmot/internal/core/assetrule"
+	"github.com/marmotdata/marmot/internal/core/mention"
 	"github.com/marmotdata/marmot/internal/core/user"
 	"github.com/marmotdata/marmot/internal/mrn"
 	"github.com/marmotdata/marmot/internal/telemetry/lookups"
@@ -18,7 +20,11 @@ import (
 // AssetResponse wraps an asset with enriched external links from rules.
 type AssetResponse struct {
 	*asset.Asset
+	Kind                  asset.Kind                       `json:"kind"`
 	EnrichedExternalLinks []assetrule.EnrichedExternalLink `json:"enriched_external_links,omitempty"`
+	Lifecycle             *asset.Lifecycle                 `json:"lifecycle,omitempty"`
+	Health                *assethealth.Health              `json:"health,omitempty"`
+	WatcherCount          int                              `json:"watcher_count"`
 }
 
 type CreateRequest struct {
@@ -112,7 +118,13 @@ func (h *Handler) createAsset(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) enrichAssetResponse(r *http.Request, result *asset.Asset) *AssetResponse {
-	resp := &AssetResponse{Asset: result}
+	resp := &AssetResponse{Asset: result, Kind: result.Kind()}
+
+	hasRuns, err := h.assetService.HasRunHistory(r.Context(), result.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", result.ID).Msg("Failed to check run history")
+	}
+	result.HasRunHistory = hasRuns
 
 	enrichedLinks, err := h.assetRuleService.GetEnrichedLinks(r.Context(), result.ID)
 	if err != nil {
@@ -130,9 +142,43 @@ func (h *Handler) enrichAssetResponse(r *http.Request, result *asset.Asset) *Ass
 		resp.EnrichedExternalLinks = allLinks
 	}
 
+	if result.Status != "" && result.Status != asset.LifecycleActive {
+		lifecycle, err := h.assetService.GetLifecycle(r.Context(), result.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("asset_id", result.ID).Msg("Failed to get asset lifecycle")
+		} else {
+			resp.Lifecycle = h.withBanner(lifecycle)
+		}
+	}
+
+	if h.healthService != nil {
+		health, err := h.healthService.Get(r.Context(), result.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("asset_id", result.ID).Msg("Failed to compute asset health")
+		} else {
+			resp.Health = health
+		}
+	}
+
+	if h.watchService != nil {
+		counts, err := h.watchService.CountWatchers(r.Context(), []string{result.ID})
+		if err != nil {
+			log.Warn().Err(err).Str("asset_id", result.ID).Msg("Failed to count asset watchers")
+		} else {
+			resp.WatcherCount = counts[result.ID]
+		}
+	}
+
 	return resp
 }
 
+// recordView counts the authenticated user opening an asset.
+func (h *Handler) recordView(r *http.Request, assetID string) {
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		h.views.RecordView(usr.ID, assetID)
+	}
+}
+
 // @Summary Get an asset by ID
 // @Description Get detailed information about a specific asset
 // @Tags assets
@@ -164,6 +210,7 @@ func (h *Handler) getAsset(w http.ResponseWriter, r *http.Request) {
 
 	h.metricsService.GetRecorder().RecordAssetView(r.Context(), result.ID, result.Type, *result.Name, result.Providers[0])
 	h.lookups.Record(r.Context(), lookups.CategoryAssetDetail)
+	h.recordView(r, result.ID)
 
 	common.RespondJSON(w, http.StatusOK, h.enrichAssetResponse(r, result))
 }
@@ -207,7 +254,7 @@ func (h *Handler) updateAsset(w http.ResponseWriter, r *http.Request) {
 		ExternalLinks:   req.ExternalLinks,
 	}
 
-	updated, err := h.assetService.Update(r.Context(), id, input)
+	updated, err := h.assetService.Update(withUserActor(r), id, input)
 	if err != nil {
 		switch {
 		case errors.Is(err, asset.ErrAssetNotFound):
@@ -221,6 +268,23 @@ func (h *Handler) updateAsset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var edited []string
+	if req.Description != nil {
+		edited = append(edited, asset.FieldDescription)
+	}
+	if req.Tags != nil {
+		edited = append(edited, asset.FieldTags)
+	}
+	h.lockEdited(r, updated, edited...)
+
+	if req.UserDescription != nil && h.mentionService != nil {
+		var actorID string
+		if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+			actorID = usr.ID
+		}
+		h.mentionService.TrackMentions(r.Context(), string(mention.SourceAssetDescription), updated.ID, updated.ID, *req.UserDescription, actorID)
+	}
+
 	common.RespondJSON(w, http.StatusOK, updated)
 }
 
@@ -294,6 +358,7 @@ func (h *Handler) getAssetByMRN(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.lookups.Record(r.Context(), lookups.CategoryAssetDetail)
+	h.recordView(r, result.ID)
 
 	common.RespondJSON(w, http.StatusOK, h.enrichAssetResponse(r, result))
 }
@@ -0,0 +1,192 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+type RevisionListResponse struct {
+	Revisions []*asset.Revision `json:"revisions"`
+	Total     int               `json:"total"`
+	Limit     int               `json:"limit"`
+	Offset    int               `json:"offset"`
+} // @name AssetRevisionListResponse
+
+type RevertRequest struct {
+	// Fields to revert. Empty reverts every revertible field.
+	Fields []string `json:"fields,omitempty"`
+} // @name AssetRevertRequest
+
+// withUserActor attributes asset changes made while serving r to the
+// calling user in the asset's revision history.
+func withUserActor(r *http.Request) context.Context {
+	actor := asset.Actor{Source: asset.RevisionSourceUser}
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		actor.Name = usr.Name
+	}
+	return asset.WithActor(r.Context(), actor)
+}
+
+func respondRevisionError(w http.ResponseWriter, err error, id, msg string) {
+	switch {
+	case errors.Is(err, asset.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	case errors.Is(err, asset.ErrRevisionNotFound):
+		common.RespondError(w, http.StatusNotFound, "Revision not found")
+	case errors.Is(err, asset.ErrInvalidInput):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Error().Err(err).Str("id", id).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseRevisionID(s string) (int64, bool) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	return id, err == nil && id >= 0
+}
+
+// @Summary List asset revisions
+// @Description List recorded changes to an asset's name, descriptions, metadata, schema, tags, links and query, newest first
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} RevisionListResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/revisions/{id} [get]
+func (h *Handler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	limit := common.ParseLimit(r.URL.Query().Get("limit"), 20, 100)
+	offset := common.ParseOffset(r.URL.Query().Get("offset"))
+
+	revisions, total, err := h.assetService.ListRevisions(r.Context(), id, limit, offset)
+	if err != nil {
+		respondRevisionError(w, err, id, "Failed to list asset revisions")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, RevisionListResponse{
+		Revisions: revisions,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+// @Summary Diff asset revisions
+// @Description Show how an asset changed between two revisions. Each field's old value is as of just after revision from, and its new value as of just after revision to. Use from=0 to diff from before the oldest kept revision.
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param from query int true "Revision to diff from"
+// @Param to query int true "Revision to diff to"
+// @Success 200 {object} asset.RevisionDiff
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/revisions/{id}/diff [get]
+func (h *Handler) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	from, okFrom := parseRevisionID(r.URL.Query().Get("from"))
+	to, okTo := parseRevisionID(r.URL.Query().Get("to"))
+	if !okFrom || !okTo {
+		common.RespondError(w, http.StatusBadRequest, "from and to must be revision IDs")
+		return
+	}
+
+	diff, err := h.assetService.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		respondRevisionError(w, err, id, "Failed to diff asset revisions")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, diff)
+}
+
+// @Summary Get an asset revision
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param revision path int true "Revision ID"
+// @Success 200 {object} asset.Revision
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/revisions/{id}/{revision} [get]
+func (h *Handler) getRevision(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	revisionID, ok := parseRevisionID(r.PathValue("revision"))
+	if !ok {
+		common.RespondError(w, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	rev, err := h.assetService.GetRevision(r.Context(), id, revisionID)
+	if err != nil {
+		respondRevisionError(w, err, id, "Failed to get asset revision")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, rev)
+}
+
+// @Summary Revert an asset to a revision
+// @Description Set user-editable fields back to their values as of just after the revision. Revertible fields are description, user_description, metadata, tags and external_links. The revert is recorded as a new revision, and reverted descriptions and tags are locked like any other manual edit.
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param revision path int true "Revision ID"
+// @Param request body RevertRequest false "Fields to revert"
+// @Success 200 {object} asset.Asset
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/revisions/{id}/{revision}/revert [post]
+func (h *Handler) revertToRevision(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	revisionID, ok := parseRevisionID(r.PathValue("revision"))
+	if !ok {
+		common.RespondError(w, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	var req RevertRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	updated, err := h.assetService.RevertToRevision(withUserActor(r), id, revisionID, req.Fields)
+	if err != nil {
+		respondRevisionError(w, err, id, "Failed to revert asset")
+		return
+	}
+
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = asset.RevertibleFields
+	}
+	var edited []string
+	for _, f := range []string{asset.FieldDescription, asset.FieldTags} {
+		if slices.Contains(fields, f) {
+			edited = append(edited, f)
+		}
+	}
+	h.lockEdited(r, updated, edited...)
+
+	common.RespondJSON(w, http.StatusOK, updated)
+}
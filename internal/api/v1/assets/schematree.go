@@ -0,0 +1,51 @@
+package assets
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/schematree"
+	"github.com/rs/zerolog/log"
+)
+
+type SchemaTreeResponse struct {
+	AssetID  string               `json:"asset_id"`
+	Sections []schematree.Section `json:"sections"`
+} // @name SchemaTreeResponse
+
+// @Summary Get asset schema tree
+// @Description Get an asset's schema as a tree of columns. Nested structs, and the structs in arrays and maps, are children of their column, whether the schema is a column list, Avro or JSON Schema.
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} SchemaTreeResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/schema-tree/{id} [get]
+func (h *Handler) getSchemaTree(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		common.RespondError(w, http.StatusBadRequest, "Asset ID required")
+		return
+	}
+
+	result, err := h.assetService.Get(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("id", id).Msg("Failed to get asset")
+			common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, SchemaTreeResponse{
+		AssetID:  result.ID,
+		Sections: schematree.Build(result.Schema),
+	})
+}
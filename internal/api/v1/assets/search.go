@@ -41,8 +41,10 @@ type SearchResponse struct {
 // @Param offset query int false "Number of items to skip" default(0)
 // @Param calculateCounts query bool false "Calculate filter counts" default(false)
 // @Success 200 {object} SearchResponse
+// @Header 200 {string} X-Marmot-Truncated "Comma-separated reasons the results are partial"
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 500 {object} common.ErrorResponse
+// @Failure 504 {object} common.ErrorResponse
 // @Router /assets/search [get]
 func (h *Handler) searchAssets(w http.ResponseWriter, r *http.Request) {
 	queryValues := r.URL.Query()
@@ -74,6 +76,9 @@ func (h *Handler) searchAssets(w http.ResponseWriter, r *http.Request) {
 
 	results, total, availableFilters, err := h.assetService.Search(r.Context(), searchFilter, calculateCounts)
 	if err != nil {
+		if common.RespondQueryTimeout(w, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, asset.ErrInvalidInput):
 			common.RespondError(w, http.StatusBadRequest, "Invalid search query")
@@ -236,6 +241,7 @@ func (h *Handler) lookupAsset(w http.ResponseWriter, r *http.Request) {
 
 	h.metricsService.GetRecorder().RecordAssetView(r.Context(), result.ID, result.Type, *result.Name, result.Providers[0])
 	h.lookups.Record(r.Context(), lookups.CategoryAssetDetail)
+	h.recordView(r, result.ID)
 
 	common.RespondJSON(w, http.StatusOK, h.enrichAssetResponse(r, result))
 }
@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -87,3 +88,42 @@ func (h *Handler) getMetadataValueSuggestions(w http.ResponseWriter, r *http.Req
 
 	common.RespondJSON(w, http.StatusOK, suggestions)
 }
+
+// @Summary Get metadata field profile
+// @Description Get distribution statistics for a metadata field across assets: coverage, distinct count, top values and numeric min/max/percentiles
+// @Tags assets
+// @Produce json
+// @Param field query string true "Metadata field name (dot-separated for nested fields)"
+// @Param limit query int false "Maximum number of top values" default(10)
+// @Success 200 {object} asset.MetadataProfile
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/suggestions/metadata/profile [get]
+func (h *Handler) getMetadataProfile(w http.ResponseWriter, r *http.Request) {
+	field := r.URL.Query().Get("field")
+	if field == "" {
+		common.RespondError(w, http.StatusBadRequest, "field parameter is required")
+		return
+	}
+
+	limit := common.ParseLimit(r.URL.Query().Get("limit"), 10, 100)
+
+	profile, err := h.assetService.GetMetadataProfile(r.Context(), field, limit)
+	if err != nil {
+		if errors.Is(err, asset.ErrInvalidMetadataField) {
+			common.RespondError(w, http.StatusBadRequest, "Invalid metadata field")
+			return
+		}
+
+		log.Error().
+			Err(err).
+			Str("endpoint", r.URL.Path).
+			Str("field", field).
+			Msg("Failed to get metadata profile")
+
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get metadata profile")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, profile)
+}
@@ -43,7 +43,7 @@ func (h *Handler) addTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updated, err := h.assetService.AddTag(r.Context(), id, input.Tag)
+	updated, err := h.assetService.AddTag(withUserActor(r), id, input.Tag)
 	if err != nil {
 		switch {
 		case errors.Is(err, asset.ErrAssetNotFound):
@@ -57,6 +57,8 @@ func (h *Handler) addTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.lockEdited(r, updated, asset.FieldTags)
+
 	common.RespondJSON(w, http.StatusOK, updated)
 }
 
@@ -89,7 +91,7 @@ func (h *Handler) removeTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updated, err := h.assetService.RemoveTag(r.Context(), id, input.Tag)
+	updated, err := h.assetService.RemoveTag(withUserActor(r), id, input.Tag)
 	if err != nil {
 		switch {
 		case errors.Is(err, asset.ErrAssetNotFound):
@@ -101,6 +103,8 @@ func (h *Handler) removeTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.lockEdited(r, updated, asset.FieldTags)
+
 	common.RespondJSON(w, http.StatusOK, updated)
 }
 
@@ -0,0 +1,73 @@
+package assettypes
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assettype"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *assettype.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *assettype.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/asset-types",
+			Method:  http.MethodGet,
+			Handler: h.listTypes,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/asset-types/{name}",
+			Method:  http.MethodGet,
+			Handler: h.getType,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/asset-types/{name}",
+			Method:  http.MethodPut,
+			Handler: h.putType,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/asset-types/{name}",
+			Method:  http.MethodDelete,
+			Handler: h.deleteType,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+			},
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package assettypes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/assettype"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary List asset types
+// @Description List the custom asset types registered by admins
+// @Tags asset-types
+// @Produce json
+// @Success 200 {array} assettype.AssetType
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-types [get]
+func (h *Handler) listTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := h.svc.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list asset types")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list asset types")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, types)
+}
+
+// @Summary Get asset type
+// @Description Get a custom asset type with its icon, description and expected metadata
+// @Tags asset-types
+// @Produce json
+// @Param name path string true "Asset type name, e.g. ML Model"
+// @Success 200 {object} assettype.AssetType
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-types/{name} [get]
+func (h *Handler) getType(w http.ResponseWriter, r *http.Request) {
+	t, err := h.svc.Get(r.Context(), r.PathValue("name"))
+	if err != nil {
+		if errors.Is(err, assettype.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset type not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get asset type")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get asset type")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, t)
+}
+
+// @Summary Register asset type
+// @Description Create or replace a custom asset type. Assets of the type are rejected on create and update if their metadata doesn't match the metadata schema.
+// @Tags asset-types
+// @Accept json
+// @Produce json
+// @Param name path string true "Asset type name, e.g. ML Model"
+// @Param type body assettype.Input true "Asset type"
+// @Success 200 {object} assettype.AssetType
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-types/{name} [put]
+func (h *Handler) putType(w http.ResponseWriter, r *http.Request) {
+	var input assettype.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updatedBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		updatedBy = &usr.ID
+	}
+
+	t, err := h.svc.Put(r.Context(), r.PathValue("name"), input, updatedBy)
+	if err != nil {
+		if assettype.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to save asset type")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to save asset type")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, t)
+}
+
+// @Summary Delete asset type
+// @Description Delete a custom asset type. Existing assets keep their type but their metadata is no longer validated.
+// @Tags asset-types
+// @Param name path string true "Asset type name, e.g. ML Model"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-types/{name} [delete]
+func (h *Handler) deleteType(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("name")); err != nil {
+		if errors.Is(err, assettype.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset type not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete asset type")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete asset type")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package businessmetrics
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/businessmetric"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *businessmetric.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *businessmetric.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/business-metrics",
+			Method:  http.MethodGet,
+			Handler: h.listMetrics,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/business-metrics",
+			Method:  http.MethodPost,
+			Handler: h.createMetric,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/business-metrics/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getMetric,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/business-metrics/{id}",
+			Method:  http.MethodPut,
+			Handler: h.defineMetric,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/business-metrics/{id}/certification",
+			Method:  http.MethodPut,
+			Handler: h.certifyMetric,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package businessmetrics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/businessmetric"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case businessmetric.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, businessmetric.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Metric not found")
+	case errors.Is(err, businessmetric.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	case errors.Is(err, businessmetric.ErrNotMetric):
+		common.RespondError(w, http.StatusBadRequest, "Asset is not a metric")
+	case errors.Is(err, businessmetric.ErrAlreadyExists):
+		common.RespondError(w, http.StatusConflict, "A metric with this name already exists for this provider")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List metrics
+// @Description List Metric assets with their definitions, certified metrics first
+// @Tags business-metrics
+// @Produce json
+// @Param certified query bool false "Only certified or uncertified metrics"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} businessmetric.ListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /business-metrics [get]
+func (h *Handler) listMetrics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := businessmetric.ListFilter{
+		Limit:  common.ParseLimit(query.Get("limit"), 50, 100),
+		Offset: common.ParseOffset(query.Get("offset")),
+	}
+	if raw := query.Get("certified"); raw != "" {
+		certified, err := strconv.ParseBool(raw)
+		if err != nil {
+			common.RespondError(w, http.StatusBadRequest, "certified must be true or false")
+			return
+		}
+		filter.Certified = &certified
+	}
+
+	result, err := h.svc.List(r.Context(), filter)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list metrics")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Create a metric
+// @Description Create a Metric asset with its definition, owners, and lineage from its sources and to its consumers
+// @Tags business-metrics
+// @Accept json
+// @Produce json
+// @Param metric body businessmetric.CreateInput true "Metric"
+// @Success 201 {object} businessmetric.Metric
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /business-metrics [post]
+func (h *Handler) createMetric(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input businessmetric.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	m, err := h.svc.Create(r.Context(), input, usr.ID)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create metric")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, m)
+}
+
+// @Summary Get a metric
+// @Tags business-metrics
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} businessmetric.Metric
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /business-metrics/{id} [get]
+func (h *Handler) getMetric(w http.ResponseWriter, r *http.Request) {
+	m, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get metric")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, m)
+}
+
+// @Summary Set a metric's definition
+// @Description Set the definition, formula and unit of a Metric asset. Changing them revokes certification.
+// @Tags business-metrics
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param definition body businessmetric.DefinitionInput true "Definition"
+// @Success 200 {object} businessmetric.Metric
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /business-metrics/{id} [put]
+func (h *Handler) defineMetric(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input businessmetric.DefinitionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	m, err := h.svc.Define(r.Context(), r.PathValue("id"), input, usr.ID)
+	if err != nil {
+		respondServiceError(w, err, "Failed to save metric definition")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, m)
+}
+
+// @Summary Certify a metric
+// @Description Mark a metric as certified, or revoke its certification
+// @Tags business-metrics
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param certification body businessmetric.CertifyInput true "Certification"
+// @Success 200 {object} businessmetric.Metric
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /business-metrics/{id}/certification [put]
+func (h *Handler) certifyMetric(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input businessmetric.CertifyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	m, err := h.svc.Certify(r.Context(), r.PathValue("id"), input, usr.ID)
+	if err != nil {
+		respondServiceError(w, err, "Failed to certify metric")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, m)
+}
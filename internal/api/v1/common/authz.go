@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/authz"
+)
+
+type grantedPermissionKey struct{}
+
+type grantedPermission struct {
+	resourceType string
+	action       string
+}
+
+// withGrantedPermission records the permission RequirePermission allowed so
+// authorization hooks further down the chain can evaluate it.
+func withGrantedPermission(r *http.Request, resourceType, action string) *http.Request {
+	ctx := context.WithValue(r.Context(), grantedPermissionKey{}, grantedPermission{
+		resourceType: resourceType,
+		action:       action,
+	})
+	return r.WithContext(ctx)
+}
+
+// WithAuthorizationHook runs svc's hook on requests that passed
+// RequirePermission. It must wrap the handler itself, inside every other
+// middleware. Routes without a permission check are not affected.
+func WithAuthorizationHook(svc *authz.Service) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			granted, ok := r.Context().Value(grantedPermissionKey{}).(grantedPermission)
+			if !ok {
+				next(w, r)
+				return
+			}
+
+			principal, _ := PrincipalFromContext(r.Context())
+
+			decision, err := svc.Authorize(r.Context(), authz.Request{
+				Principal:    principal,
+				ResourceType: granted.resourceType,
+				Action:       granted.action,
+				HTTP: authz.HTTPRequest{
+					Method: r.Method,
+					Path:   r.URL.Path,
+					Route:  r.Pattern,
+				},
+				AssetID: requestAssetID(r),
+			})
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, "Failed to check permissions")
+				return
+			}
+			if !decision.Allow {
+				msg := "Permission denied"
+				if decision.Reason != "" {
+					msg += ": " + decision.Reason
+				}
+				RespondError(w, http.StatusForbidden, msg)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// requestAssetID returns the asset a request names: the {assetId} path
+// value on any route, or {id} on routes under /api/v1/assets/.
+func requestAssetID(r *http.Request) string {
+	if id := r.PathValue("assetId"); id != "" {
+		return id
+	}
+	pattern := r.Pattern
+	if i := strings.Index(pattern, " "); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+	if strings.HasPrefix(pattern, "/api/v1/assets/") {
+		return r.PathValue("id")
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package common
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/query"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+// TruncatedHeader lists why a response holds partial results, e.g.
+// "facets,count". It is absent when results are complete.
+const TruncatedHeader = "X-Marmot-Truncated"
+
+// WithQueryBudget attaches a query budget from the search config to the
+// request. Repositories that honour it return partial results instead of
+// failing when they hit a limit, and the reasons are reported in
+// TruncatedHeader.
+func WithQueryBudget(cfg *config.Config) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			budget := &query.Budget{
+				StatementTimeout:   time.Duration(cfg.Search.StatementTimeout) * time.Millisecond,
+				MaxRows:            cfg.Search.MaxResults,
+				MaxOffset:          cfg.Search.MaxOffset,
+				FacetLoadThreshold: cfg.Search.FacetLoadThreshold,
+			}
+
+			bw := &budgetResponseWriter{ResponseWriter: w, budget: budget}
+			next(bw, r.WithContext(query.WithBudget(r.Context(), budget)))
+		}
+	}
+}
+
+// RespondQueryTimeout responds 504 when err is a budgeted query running out of
+// time, and reports whether it did. Partial results are only returned for the
+// optional parts of a response; without its main query there is nothing
+// worth returning.
+func RespondQueryTimeout(w http.ResponseWriter, err error) bool {
+	if !query.IsTimeout(err) {
+		return false
+	}
+	RespondError(w, http.StatusGatewayTimeout, "Search timed out, narrow the query and try again")
+	return true
+}
+
+// budgetResponseWriter sets TruncatedHeader just before the response headers
+// are sent, once the handler has finished querying.
+type budgetResponseWriter struct {
+	http.ResponseWriter
+	budget      *query.Budget
+	wroteHeader bool
+}
+
+func (w *budgetResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if reasons := w.budget.Truncations(); len(reasons) > 0 {
+			w.Header().Set(TruncatedHeader, strings.Join(reasons, ","))
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *budgetResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/marmotdata/marmot/internal/query"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+func TestRespondQueryTimeout(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if RespondQueryTimeout(rec, errors.New("connection refused")) {
+		t.Fatal("expected other errors to be left to the caller")
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected nothing written, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	err := fmt.Errorf("searching: %w", &pgconn.PgError{Code: "57014"})
+	if !RespondQueryTimeout(rec, err) {
+		t.Fatal("expected a statement timeout to be handled")
+	}
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected 504, got %d", rec.Code)
+	}
+}
+
+func TestWithQueryBudgetReportsTruncations(t *testing.T) {
+	cfg := &config.Config{}
+	handler := WithQueryBudget(cfg)(func(w http.ResponseWriter, r *http.Request) {
+		budget := query.BudgetFromContext(r.Context())
+		budget.Truncate(query.TruncatedFacets)
+		budget.Truncate(query.TruncatedCount)
+		_, _ = w.Write([]byte("{}"))
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/search", nil))
+	if got := rec.Header().Get(TruncatedHeader); got != "facets,count" {
+		t.Errorf("expected %s to be %q, got %q", TruncatedHeader, "facets,count", got)
+	}
+}
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/core/idempotency"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestSize  = 32 << 20
+	maxIdempotentResponseSize = 4 << 20
+)
+
+var globalIdempotencyService *idempotency.Service
+
+// SetIdempotencyService registers the service used by WithIdempotency.
+func SetIdempotencyService(svc *idempotency.Service) {
+	globalIdempotencyService = svc
+}
+
+// WithIdempotency makes a write endpoint safe to retry. When a request carries
+// an Idempotency-Key header, the first response for that key is stored and
+// replayed for later requests with the same key and body. Reusing a key for a
+// different request is rejected, as is a retry while the original is still
+// running. Server errors release the key so the request can be retried.
+//
+// It must run after WithAuth, since keys are scoped to the caller.
+func WithIdempotency() func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			svc := globalIdempotencyService
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if svc == nil || key == "" {
+				next(w, r)
+				return
+			}
+			if len(key) > idempotency.MaxKeyLength {
+				RespondError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			// The body is buffered to fingerprint it, so cap how much is read.
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestSize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					RespondError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+					return
+				}
+				RespondError(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := idempotencyScope(r.Context())
+			fingerprint := idempotency.Fingerprint(r.Method, r.URL.Path, body)
+
+			rec, reserved, err := svc.Begin(r.Context(), scope, key, r.Method, r.URL.Path, fingerprint)
+			if err != nil {
+				if errors.Is(err, idempotency.ErrConflict) {
+					w.Header().Set("Retry-After", "1")
+					RespondError(w, http.StatusConflict, "A request with this Idempotency-Key is in progress")
+					return
+				}
+				// Don't turn a storage problem into a failed write; run the
+				// request without replay protection.
+				log.Error().Err(err).Msg("Failed to reserve idempotency key")
+				next(w, r)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case rec.Fingerprint != fingerprint:
+					RespondError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				case !rec.Completed():
+					w.Header().Set("Retry-After", "1")
+					RespondError(w, http.StatusConflict, "A request with this Idempotency-Key is in progress")
+				default:
+					if rec.ContentType != "" {
+						w.Header().Set("Content-Type", rec.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(rec.StatusCode)
+					w.Write(rec.Body)
+				}
+				return
+			}
+
+			rw := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next(rw, r)
+
+			ctx := context.WithoutCancel(r.Context())
+			if rw.status >= http.StatusInternalServerError || rw.overflow {
+				if err := svc.Release(ctx, scope, key); err != nil {
+					log.Error().Err(err).Msg("Failed to release idempotency key")
+				}
+				return
+			}
+			if err := svc.Complete(ctx, scope, key, rw.status, rw.Header().Get("Content-Type"), rw.body.Bytes()); err != nil {
+				log.Error().Err(err).Msg("Failed to store idempotent response")
+			}
+		}
+	}
+}
+
+func idempotencyScope(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p != nil {
+		return string(p.Type()) + ":" + p.ID()
+	}
+	if u, ok := GetAuthenticatedUser(ctx); ok && u != nil {
+		return "user:" + u.ID
+	}
+	return "anonymous"
+}
+
+// recordingResponseWriter copies the response so it can be replayed. Responses
+// too large to store are passed through but not recorded.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxIdempotentResponseSize {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,228 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/idempotency"
+)
+
+// memIdempotencyRepo keeps keys in memory, taking a held key over when it
+// expired or its request never finished before staleBefore.
+type memIdempotencyRepo struct {
+	records map[string]*idempotency.Record
+}
+
+func (r *memIdempotencyRepo) Reserve(_ context.Context, rec *idempotency.Record, staleBefore time.Time) (*idempotency.Record, bool, error) {
+	if existing, ok := r.records[rec.Key]; ok {
+		stale := !existing.Completed() && existing.LockedAt.Before(staleBefore)
+		if existing.ExpiresAt.After(time.Now()) && !stale {
+			return existing, false, nil
+		}
+	}
+	rec.LockedAt = time.Now()
+	r.records[rec.Key] = rec
+	return rec, true, nil
+}
+
+func (r *memIdempotencyRepo) Complete(_ context.Context, _, key string, statusCode int, contentType string, body []byte) error {
+	rec := r.records[key]
+	rec.StatusCode, rec.ContentType, rec.Body = statusCode, contentType, body
+	return nil
+}
+
+func (r *memIdempotencyRepo) Release(_ context.Context, _, key string) error {
+	if rec, ok := r.records[key]; ok && !rec.Completed() {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) DeleteExpired(context.Context) (int, error) {
+	return 0, nil
+}
+
+type idempotencyTest struct {
+	repo    *memIdempotencyRepo
+	calls   int
+	status  int
+	handler http.HandlerFunc
+}
+
+func newIdempotencyTest(t *testing.T) *idempotencyTest {
+	t.Helper()
+	it := &idempotencyTest{repo: &memIdempotencyRepo{records: map[string]*idempotency.Record{}}, status: http.StatusCreated}
+	SetIdempotencyService(idempotency.NewService(it.repo, time.Hour))
+	t.Cleanup(func() { SetIdempotencyService(nil) })
+
+	it.handler = WithIdempotency()(func(w http.ResponseWriter, r *http.Request) {
+		it.calls++
+		body, _ := io.ReadAll(r.Body)
+		RespondJSON(w, it.status, map[string]any{"call": it.calls, "body": string(body)})
+	})
+	return it
+}
+
+func (it *idempotencyTest) do(key string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/assets", body)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	it.handler(rec, req)
+	return rec
+}
+
+func TestWithIdempotencyReplaysStoredResponse(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	first := it.do("key-1", strings.NewReader(`{"name":"orders"}`))
+	second := it.do("key-1", strings.NewReader(`{"name":"orders"}`))
+
+	if it.calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", it.calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("expected replay of %d %q, got %d %q", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected %s on the replay", IdempotentReplayedHeader)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("expected no %s on the original response", IdempotentReplayedHeader)
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected replayed Content-Type application/json, got %q", got)
+	}
+}
+
+func TestWithIdempotencyRejectsDifferentRequest(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	it.do("key-1", strings.NewReader(`{"name":"orders"}`))
+	rec := it.do("key-1", strings.NewReader(`{"name":"customers"}`))
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rec.Code)
+	}
+	if it.calls != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", it.calls)
+	}
+}
+
+func TestWithIdempotencyRejectsRequestInProgress(t *testing.T) {
+	it := newIdempotencyTest(t)
+	body := `{"name":"orders"}`
+	it.repo.records["key-1"] = &idempotency.Record{
+		Key:         "key-1",
+		Fingerprint: idempotency.Fingerprint(http.MethodPost, "/api/v1/assets", []byte(body)),
+		LockedAt:    time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	rec := it.do("key-1", strings.NewReader(body))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After on a conflict")
+	}
+	if it.calls != 0 {
+		t.Errorf("expected the handler not to run, ran %d times", it.calls)
+	}
+}
+
+func TestWithIdempotencyTakesOverStaleLock(t *testing.T) {
+	it := newIdempotencyTest(t)
+	body := `{"name":"orders"}`
+	it.repo.records["key-1"] = &idempotency.Record{
+		Key:         "key-1",
+		Fingerprint: idempotency.Fingerprint(http.MethodPost, "/api/v1/assets", []byte(body)),
+		LockedAt:    time.Now().Add(-idempotency.DefaultLockTimeout - time.Minute),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	rec := it.do("key-1", strings.NewReader(body))
+
+	if rec.Code != http.StatusCreated || it.calls != 1 {
+		t.Errorf("expected the request to run, got %d after %d calls", rec.Code, it.calls)
+	}
+	if !it.repo.records["key-1"].Completed() {
+		t.Error("expected the response to be stored")
+	}
+}
+
+func TestWithIdempotencyReleasesKeyAfterServerError(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.status = http.StatusInternalServerError
+
+	if rec := it.do("key-1", strings.NewReader(`{}`)); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if _, ok := it.repo.records["key-1"]; ok {
+		t.Fatal("expected the key to be released")
+	}
+
+	it.status = http.StatusCreated
+	rec := it.do("key-1", strings.NewReader(`{}`))
+	if rec.Code != http.StatusCreated || it.calls != 2 {
+		t.Errorf("expected the retry to run, got %d after %d calls", rec.Code, it.calls)
+	}
+	if rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("expected the retry not to be a replay")
+	}
+}
+
+func TestWithIdempotencyStoresClientErrors(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.status = http.StatusBadRequest
+
+	it.do("key-1", strings.NewReader(`{}`))
+	rec := it.do("key-1", strings.NewReader(`{}`))
+
+	if rec.Code != http.StatusBadRequest || it.calls != 1 {
+		t.Errorf("expected the 400 to be replayed, got %d after %d calls", rec.Code, it.calls)
+	}
+}
+
+func TestWithIdempotencyLimitsBody(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	rec := it.do("key-1", io.LimitReader(zeroReader{}, maxIdempotentRequestSize+1))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rec.Code)
+	}
+	if it.calls != 0 || len(it.repo.records) != 0 {
+		t.Errorf("expected nothing to run or be reserved, got %d calls and %d keys", it.calls, len(it.repo.records))
+	}
+}
+
+func TestWithIdempotencyWithoutKey(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	it.do("", strings.NewReader(`{}`))
+	it.do("", strings.NewReader(`{}`))
+
+	if it.calls != 2 || len(it.repo.records) != 0 {
+		t.Errorf("expected requests without a key to pass through, got %d calls and %d keys", it.calls, len(it.repo.records))
+	}
+
+	rec := it.do(strings.Repeat("k", idempotency.MaxKeyLength+1), strings.NewReader(`{}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an overlong key, got %d", rec.Code)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
@@ -221,7 +221,7 @@ func RequirePermission(userService user.Service, resourceType, action string) fu
 							RespondError(w, http.StatusForbidden, "Permission denied")
 							return
 						}
-						next(w, r)
+						next(w, withGrantedPermission(r, resourceType, action))
 						return
 					}
 				}
@@ -235,7 +235,7 @@ func RequirePermission(userService user.Service, resourceType, action string) fu
 					RespondError(w, http.StatusForbidden, "Permission denied")
 					return
 				}
-				next(w, r)
+				next(w, withGrantedPermission(r, resourceType, action))
 				return
 			}
 
@@ -251,7 +251,7 @@ func RequirePermission(userService user.Service, resourceType, action string) fu
 				return
 			}
 
-			next(w, r)
+			next(w, withGrantedPermission(r, resourceType, action))
 		}
 	}
 }
@@ -0,0 +1,107 @@
+package connections
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/connection"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc                  *connection.Service
+	userService          user.Service
+	authService          auth.Service
+	config               *config.Config
+	encryptionConfigured bool
+}
+
+func NewHandler(
+	svc *connection.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+	encryptionConfigured bool,
+) *Handler {
+	return &Handler{
+		svc:                  svc,
+		userService:          userService,
+		authService:          authService,
+		config:               cfg,
+		encryptionConfigured: encryptionConfigured,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/ingestion/connections",
+			Method:  http.MethodGet,
+			Handler: h.listConnections,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections",
+			Method:  http.MethodPost,
+			Handler: h.createConnection,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.RequireEncryption(h.encryptionConfigured),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getConnection,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections/{id}",
+			Method:  http.MethodPut,
+			Handler: h.updateConnection,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.RequireEncryption(h.encryptionConfigured),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections/{id}",
+			Method:  http.MethodDelete,
+			Handler: h.deleteConnection,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections/{id}/rotate",
+			Method:  http.MethodPost,
+			Handler: h.rotateCredentials,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.RequireEncryption(h.encryptionConfigured),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/connections/{id}/check",
+			Method:  http.MethodPost,
+			Handler: h.checkConnection,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+	}
+}
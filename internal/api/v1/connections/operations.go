@@ -0,0 +1,199 @@
+package connections
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/connection"
+	"github.com/rs/zerolog/log"
+)
+
+type RotateCredentialsRequest struct {
+	// Credentials maps credential field names, such as password, to their
+	// new values.
+	Credentials map[string]string `json:"credentials"`
+} // @name RotateCredentialsRequest
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case connection.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, connection.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Connection not found")
+	case errors.Is(err, connection.ErrNameExists):
+		common.RespondError(w, http.StatusConflict, "Connection with this name already exists")
+	case errors.Is(err, connection.ErrInUse):
+		common.RespondError(w, http.StatusConflict, "Connection is used by schedules; detach them first")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List connections
+// @Description Credentials are masked
+// @Tags ingestion
+// @Produce json
+// @Param provider query string false "Only connections for this plugin"
+// @Success 200 {array} connection.Connection
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections [get]
+func (h *Handler) listConnections(w http.ResponseWriter, r *http.Request) {
+	conns, err := h.svc.List(r.Context(), r.URL.Query().Get("provider"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list connections")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, conns)
+}
+
+// @Summary Create a connection
+// @Description Create a shared provider connection that schedules can reference
+// @Tags ingestion
+// @Accept json
+// @Produce json
+// @Param connection body connection.CreateInput true "Connection"
+// @Success 201 {object} connection.Connection
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections [post]
+func (h *Handler) createConnection(w http.ResponseWriter, r *http.Request) {
+	if !common.RequirePluginsReady(w) {
+		return
+	}
+
+	var input connection.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var createdBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = &usr.ID
+	}
+
+	conn, err := h.svc.Create(r.Context(), input, createdBy)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create connection")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, conn)
+}
+
+// @Summary Get a connection
+// @Description Credentials are masked
+// @Tags ingestion
+// @Produce json
+// @Param id path string true "Connection ID"
+// @Success 200 {object} connection.Connection
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections/{id} [get]
+func (h *Handler) getConnection(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get connection")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, conn)
+}
+
+// @Summary Update a connection
+// @Description Replace a connection's settings. Masked or omitted credentials keep their stored value.
+// @Tags ingestion
+// @Accept json
+// @Produce json
+// @Param id path string true "Connection ID"
+// @Param connection body connection.UpdateInput true "Connection"
+// @Success 200 {object} connection.Connection
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections/{id} [put]
+func (h *Handler) updateConnection(w http.ResponseWriter, r *http.Request) {
+	var input connection.UpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	conn, err := h.svc.Update(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update connection")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, conn)
+}
+
+// @Summary Delete a connection
+// @Tags ingestion
+// @Param id path string true "Connection ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections/{id} [delete]
+func (h *Handler) deleteConnection(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("id")); err != nil {
+		respondServiceError(w, err, "Failed to delete connection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Rotate connection credentials
+// @Description Replace credential fields only, then check the connection. Schedules using it pick up the new credentials on their next run.
+// @Tags ingestion
+// @Accept json
+// @Produce json
+// @Param id path string true "Connection ID"
+// @Param credentials body RotateCredentialsRequest true "New credentials"
+// @Success 200 {object} connection.Connection
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections/{id}/rotate [post]
+func (h *Handler) rotateCredentials(w http.ResponseWriter, r *http.Request) {
+	var req RotateCredentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	conn, err := h.svc.RotateCredentials(r.Context(), r.PathValue("id"), req.Credentials)
+	if err != nil {
+		respondServiceError(w, err, "Failed to rotate credentials")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, conn)
+}
+
+// @Summary Check a connection
+// @Description Check the connection's endpoint now and record the result
+// @Tags ingestion
+// @Produce json
+// @Param id path string true "Connection ID"
+// @Success 200 {object} connection.Connection
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/connections/{id}/check [post]
+func (h *Handler) checkConnection(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.svc.Check(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to check connection")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, conn)
+}
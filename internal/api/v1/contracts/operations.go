@@ -0,0 +1,47 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/contract"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Validate against data contract
+// @Description Check a producer's candidate schema and metadata against the asset as registered in the catalog. Removing a column or metadata key, or changing its type, fails validation; added columns and keys pass. The response includes a summary of the downstream assets affected by removed or retyped columns. Intended as a merge gate in producer CI: fail the build when passed is false.
+// @Tags contracts
+// @Accept json
+// @Produce json
+// @Param request body contract.ValidateRequest true "Candidate schema and metadata"
+// @Success 200 {object} contract.ValidationResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /contracts/validate [post]
+func (h *Handler) validate(w http.ResponseWriter, r *http.Request) {
+	var req contract.ValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Validate(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, contract.ErrInvalidRequest):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("mrn", req.MRN).Msg("Failed to validate contract")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to validate contract")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
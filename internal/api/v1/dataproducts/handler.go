@@ -13,6 +13,7 @@ import (
 
 type Handler struct {
 	dataProductService dataproduct.Service
+	onboardingService  *dataproduct.OnboardingService
 	userService        user.Service
 	authService        auth.Service
 	config             *config.Config
@@ -21,6 +22,7 @@ type Handler struct {
 
 func NewHandler(
 	dataProductService dataproduct.Service,
+	onboardingService *dataproduct.OnboardingService,
 	userService user.Service,
 	authService auth.Service,
 	config *config.Config,
@@ -28,6 +30,7 @@ func NewHandler(
 ) *Handler {
 	return &Handler{
 		dataProductService: dataProductService,
+		onboardingService:  onboardingService,
 		userService:        userService,
 		authService:        authService,
 		config:             config,
@@ -174,6 +177,60 @@ func (h *Handler) Routes() []common.Route {
 				common.RequirePermission(h.userService, "assets", "manage"),
 			},
 		},
+		{
+			Path:    "/api/v1/products/drafts",
+			Method:  http.MethodGet,
+			Handler: h.listDrafts,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/products/drafts",
+			Method:  http.MethodPost,
+			Handler: h.createDraft,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/products/drafts/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getDraft,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/products/drafts/{id}",
+			Method:  http.MethodDelete,
+			Handler: h.deleteDraft,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/products/drafts/{id}/steps/{step}",
+			Method:  http.MethodPut,
+			Handler: h.saveDraftStep,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/products/drafts/{id}/publish",
+			Method:  http.MethodPost,
+			Handler: h.publishDraft,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
 		{
 			Path:    "/api/v1/products/images/{id}/{purpose}",
 			Method:  http.MethodPost,
@@ -0,0 +1,217 @@
+package dataproducts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/dataproduct"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/rs/zerolog/log"
+)
+
+// PublishErrorResponse explains why a draft can't be published yet.
+type PublishErrorResponse struct {
+	Error string                   `json:"error"`
+	Steps []dataproduct.StepStatus `json:"steps"`
+} // @name DataProductPublishErrorResponse
+
+type DraftListResponse struct {
+	Drafts []*dataproduct.Draft `json:"drafts"`
+} // @name DataProductDraftListResponse
+
+func respondDraftError(w http.ResponseWriter, err error, id, msg string) {
+	var onboardingErr *dataproduct.OnboardingError
+	switch {
+	case errors.As(err, &onboardingErr):
+		common.RespondJSON(w, http.StatusUnprocessableEntity, PublishErrorResponse{
+			Error: onboardingErr.Error(),
+			Steps: onboardingErr.Steps,
+		})
+	case errors.Is(err, dataproduct.ErrDraftNotFound):
+		common.RespondError(w, http.StatusNotFound, "Draft not found")
+	case errors.Is(err, dataproduct.ErrDraftPublished):
+		common.RespondError(w, http.StatusConflict, "Draft has already been published")
+	case errors.Is(err, dataproduct.ErrUnknownStep):
+		common.RespondError(w, http.StatusNotFound, err.Error())
+	default:
+		log.Error().Err(err).Str("id", id).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// @Summary List data product drafts
+// @Description List your data product onboarding drafts, most recently updated first
+// @Tags products
+// @Produce json
+// @Param include_published query bool false "Include drafts that have been published"
+// @Success 200 {object} DraftListResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts [get]
+func (h *Handler) listDrafts(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	includePublished := r.URL.Query().Get("include_published") == "true"
+	drafts, err := h.onboardingService.ListDrafts(r.Context(), usr.ID, includePublished)
+	if err != nil {
+		respondDraftError(w, err, "", "Failed to list data product drafts")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, DraftListResponse{Drafts: drafts})
+}
+
+// @Summary Start a data product draft
+// @Description Start the guided data product creation flow. The body may fill in any steps up front; steps are details, owners, assets and sla.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param draft body dataproduct.DraftContent false "Initial draft content"
+// @Success 201 {object} dataproduct.Draft
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts [post]
+func (h *Handler) createDraft(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	var content dataproduct.DraftContent
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+			common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	draft, err := h.onboardingService.CreateDraft(r.Context(), usr.ID, content)
+	if err != nil {
+		respondDraftError(w, err, "", "Failed to create data product draft")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, draft)
+}
+
+// @Summary Get a data product draft
+// @Description Get a draft with the status of each onboarding step and any validation errors
+// @Tags products
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Success 200 {object} dataproduct.Draft
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts/{id} [get]
+func (h *Handler) getDraft(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	id := r.PathValue("id")
+	draft, err := h.onboardingService.GetDraft(r.Context(), id, usr.ID)
+	if err != nil {
+		respondDraftError(w, err, id, "Failed to get data product draft")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, draft)
+}
+
+// @Summary Delete a data product draft
+// @Description Discard a draft. Deleting a published draft leaves its data product alone.
+// @Tags products
+// @Param id path string true "Draft ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts/{id} [delete]
+func (h *Handler) deleteDraft(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.onboardingService.DeleteDraft(r.Context(), id, usr.ID); err != nil {
+		respondDraftError(w, err, id, "Failed to delete data product draft")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Save a data product onboarding step
+// @Description Save one step of a draft. Only the fields for that step are read from the body: name, description and tags for details; owners for owners; rules and asset_ids for assets; sla for sla. Incomplete steps are saved and their problems reported in the draft's step statuses.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Param step path string true "Step" Enums(details, owners, assets, sla)
+// @Param content body dataproduct.DraftContent true "Step content"
+// @Success 200 {object} dataproduct.Draft
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts/{id}/steps/{step} [put]
+func (h *Handler) saveDraftStep(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	var content dataproduct.DraftContent
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	id := r.PathValue("id")
+	step := dataproduct.OnboardingStep(r.PathValue("step"))
+	draft, err := h.onboardingService.SaveStep(r.Context(), id, usr.ID, step, content)
+	if err != nil {
+		respondDraftError(w, err, id, "Failed to save data product draft step")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, draft)
+}
+
+// @Summary Publish a data product draft
+// @Description Create the data product described by a draft. Every step must be complete: a description of at least 20 characters, at least one owner, at least one rule or asset, and an SLA with freshness_hours and support_contact.
+// @Tags products
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Success 201 {object} dataproduct.DataProduct
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 422 {object} PublishErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /products/drafts/{id}/publish [post]
+func (h *Handler) publishDraft(w http.ResponseWriter, r *http.Request) {
+	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		return
+	}
+
+	id := r.PathValue("id")
+	dp, err := h.onboardingService.Publish(r.Context(), id, usr.ID)
+	if err != nil {
+		respondDraftError(w, err, id, "Failed to publish data product draft")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, dp)
+}
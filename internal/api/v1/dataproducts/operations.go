@@ -22,15 +22,18 @@ type OwnerRequest struct {
 } // @name DataProductOwnerRequest
 
 type RuleRequest struct {
-	Name            string  `json:"name" validate:"required"`
-	Description     *string `json:"description,omitempty"`
-	RuleType        string  `json:"rule_type" validate:"required"`
-	QueryExpression *string `json:"query_expression,omitempty"`
-	MetadataField   *string `json:"metadata_field,omitempty"`
-	PatternType     *string `json:"pattern_type,omitempty"`
-	PatternValue    *string `json:"pattern_value,omitempty"`
-	Priority        int     `json:"priority"`
-	IsEnabled       bool    `json:"is_enabled"`
+	Name             string  `json:"name" validate:"required"`
+	Description      *string `json:"description,omitempty"`
+	RuleType         string  `json:"rule_type" validate:"required"`
+	QueryExpression  *string `json:"query_expression,omitempty"`
+	MetadataField    *string `json:"metadata_field,omitempty"`
+	PatternType      *string `json:"pattern_type,omitempty"`
+	PatternValue     *string `json:"pattern_value,omitempty"`
+	LineageAssetID   *string `json:"lineage_asset_id,omitempty"`
+	LineageDirection *string `json:"lineage_direction,omitempty"`
+	LineageDepth     *int    `json:"lineage_depth,omitempty"`
+	Priority         int     `json:"priority"`
+	IsEnabled        bool    `json:"is_enabled"`
 } // @name DataProductRuleRequest
 
 type CreateRequest struct {
@@ -99,15 +102,18 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	rules := make([]dataproduct.RuleInput, len(req.Rules))
 	for i, rule := range req.Rules {
 		rules[i] = dataproduct.RuleInput{
-			Name:            rule.Name,
-			Description:     rule.Description,
-			RuleType:        dataproduct.RuleType(rule.RuleType),
-			QueryExpression: rule.QueryExpression,
-			MetadataField:   rule.MetadataField,
-			PatternType:     rule.PatternType,
-			PatternValue:    rule.PatternValue,
-			Priority:        rule.Priority,
-			IsEnabled:       rule.IsEnabled,
+			Name:             rule.Name,
+			Description:      rule.Description,
+			RuleType:         dataproduct.RuleType(rule.RuleType),
+			QueryExpression:  rule.QueryExpression,
+			MetadataField:    rule.MetadataField,
+			PatternType:      rule.PatternType,
+			PatternValue:     rule.PatternValue,
+			LineageAssetID:   rule.LineageAssetID,
+			LineageDirection: rule.LineageDirection,
+			LineageDepth:     rule.LineageDepth,
+			Priority:         rule.Priority,
+			IsEnabled:        rule.IsEnabled,
 		}
 	}
 
@@ -522,15 +528,18 @@ func (h *Handler) createRule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := dataproduct.RuleInput{
-		Name:            req.Name,
-		Description:     req.Description,
-		RuleType:        dataproduct.RuleType(req.RuleType),
-		QueryExpression: req.QueryExpression,
-		MetadataField:   req.MetadataField,
-		PatternType:     req.PatternType,
-		PatternValue:    req.PatternValue,
-		Priority:        req.Priority,
-		IsEnabled:       req.IsEnabled,
+		Name:             req.Name,
+		Description:      req.Description,
+		RuleType:         dataproduct.RuleType(req.RuleType),
+		QueryExpression:  req.QueryExpression,
+		MetadataField:    req.MetadataField,
+		PatternType:      req.PatternType,
+		PatternValue:     req.PatternValue,
+		LineageAssetID:   req.LineageAssetID,
+		LineageDirection: req.LineageDirection,
+		LineageDepth:     req.LineageDepth,
+		Priority:         req.Priority,
+		IsEnabled:        req.IsEnabled,
 	}
 
 	rule, err := h.dataProductService.CreateRule(r.Context(), id, input)
@@ -579,15 +588,18 @@ func (h *Handler) updateRule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := dataproduct.RuleInput{
-		Name:            req.Name,
-		Description:     req.Description,
-		RuleType:        dataproduct.RuleType(req.RuleType),
-		QueryExpression: req.QueryExpression,
-		MetadataField:   req.MetadataField,
-		PatternType:     req.PatternType,
-		PatternValue:    req.PatternValue,
-		Priority:        req.Priority,
-		IsEnabled:       req.IsEnabled,
+		Name:             req.Name,
+		Description:      req.Description,
+		RuleType:         dataproduct.RuleType(req.RuleType),
+		QueryExpression:  req.QueryExpression,
+		MetadataField:    req.MetadataField,
+		PatternType:      req.PatternType,
+		PatternValue:     req.PatternValue,
+		LineageAssetID:   req.LineageAssetID,
+		LineageDirection: req.LineageDirection,
+		LineageDepth:     req.LineageDepth,
+		Priority:         req.Priority,
+		IsEnabled:        req.IsEnabled,
 	}
 
 	rule, err := h.dataProductService.UpdateRule(r.Context(), ruleID, input)
@@ -663,15 +675,18 @@ func (h *Handler) previewRule(w http.ResponseWriter, r *http.Request) {
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 
 	input := dataproduct.RuleInput{
-		Name:            req.Name,
-		Description:     req.Description,
-		RuleType:        dataproduct.RuleType(req.RuleType),
-		QueryExpression: req.QueryExpression,
-		MetadataField:   req.MetadataField,
-		PatternType:     req.PatternType,
-		PatternValue:    req.PatternValue,
-		Priority:        req.Priority,
-		IsEnabled:       true,
+		Name:             req.Name,
+		Description:      req.Description,
+		RuleType:         dataproduct.RuleType(req.RuleType),
+		QueryExpression:  req.QueryExpression,
+		MetadataField:    req.MetadataField,
+		PatternType:      req.PatternType,
+		PatternValue:     req.PatternValue,
+		LineageAssetID:   req.LineageAssetID,
+		LineageDirection: req.LineageDirection,
+		LineageDepth:     req.LineageDepth,
+		Priority:         req.Priority,
+		IsEnabled:        true,
 	}
 
 	preview, err := h.dataProductService.PreviewRule(r.Context(), input, limit)
@@ -0,0 +1,136 @@
+package deprecations
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/deprecation"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case deprecation.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, deprecation.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset is not deprecated")
+	case errors.Is(err, deprecation.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	case errors.Is(err, deprecation.ErrReplacementNotFound):
+		common.RespondError(w, http.StatusBadRequest, "Replacement asset not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List deprecated assets
+// @Description List deprecated assets with their replacements and how many consumers still read them
+// @Tags deprecations
+// @Produce json
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} deprecation.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /deprecations [get]
+func (h *Handler) listDeprecations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.List(r.Context(), limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list deprecations")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Get an asset's deprecation
+// @Tags deprecations
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} deprecation.Deprecation
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/deprecation/{id} [get]
+func (h *Handler) getDeprecation(w http.ResponseWriter, r *http.Request) {
+	d, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get deprecation")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, d)
+}
+
+// @Summary Deprecate an asset
+// @Description Mark an asset as deprecated, optionally naming the asset its consumers should move to. Deprecating an already deprecated asset updates it.
+// @Tags deprecations
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param deprecation body deprecation.DeprecateInput true "Deprecation"
+// @Success 200 {object} deprecation.Deprecation
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/deprecation/{id} [put]
+func (h *Handler) deprecateAsset(w http.ResponseWriter, r *http.Request) {
+	var input deprecation.DeprecateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var userID string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		userID = usr.ID
+	}
+
+	d, err := h.svc.Deprecate(r.Context(), r.PathValue("id"), input, userID)
+	if err != nil {
+		respondServiceError(w, err, "Failed to deprecate asset")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, d)
+}
+
+// @Summary Remove an asset's deprecation
+// @Tags deprecations
+// @Param id path string true "Asset ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/deprecation/{id} [delete]
+func (h *Handler) undeprecateAsset(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Undeprecate(r.Context(), r.PathValue("id")); err != nil {
+		respondServiceError(w, err, "Failed to remove deprecation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List the consumers of a deprecated asset
+// @Description List the assets that still read a deprecated asset, those that have moved off it, and daily migration progress
+// @Tags deprecations
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} deprecation.ConsumersReport
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/deprecation/{id}/consumers [get]
+func (h *Handler) listConsumers(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.Consumers(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list deprecation consumers")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,102 @@
+package favorites
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/favorite"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, favorite.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Favorite not found")
+	case errors.Is(err, favorite.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List favorite assets
+// @Description List the current user's favorite assets, most recently added first
+// @Tags favorites
+// @Produce json
+// @Param limit query int false "Maximum number of favorites to return" default(50)
+// @Param offset query int false "Number of favorites to skip" default(0)
+// @Success 200 {object} favorite.ListResult
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /favorites [get]
+func (h *Handler) listFavorites(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.List(r.Context(), usr.ID, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list favorites")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Favorite an asset
+// @Description Add an asset to the current user's favorites. Favorites are boosted in the user's search results.
+// @Tags favorites
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} favorite.Favorite
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /favorites/{id} [put]
+func (h *Handler) addFavorite(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	fav, err := h.svc.Add(r.Context(), usr.ID, r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to add favorite")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, fav)
+}
+
+// @Summary Unfavorite an asset
+// @Description Remove an asset from the current user's favorites
+// @Tags favorites
+// @Param id path string true "Asset ID"
+// @Success 204
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /favorites/{id} [delete]
+func (h *Handler) removeFavorite(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.Remove(r.Context(), usr.ID, r.PathValue("id")); err != nil {
+		respondServiceError(w, err, "Failed to remove favorite")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -17,6 +17,7 @@ type Handler struct {
 	authService     auth.Service
 	config          *config.Config
 	lookups         lookups.Recorder
+	usage           *glossary.UsageService
 }
 
 func NewHandler(
@@ -25,6 +26,7 @@ func NewHandler(
 	authService auth.Service,
 	config *config.Config,
 	lookupsRecorder lookups.Recorder,
+	usageService *glossary.UsageService,
 ) *Handler {
 	return &Handler{
 		glossaryService: glossaryService,
@@ -32,6 +34,7 @@ func NewHandler(
 		authService:     authService,
 		config:          config,
 		lookups:         lookupsRecorder,
+		usage:           usageService,
 	}
 }
 
@@ -57,6 +60,43 @@ func (h *Handler) Routes() []common.Route {
 				common.WithRateLimit(h.config, 50, 60),
 			},
 		},
+		{
+			Path:    "/api/v1/glossary/usage",
+			Method:  http.MethodGet,
+			Handler: h.listUsage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "glossary", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/glossary/usage/unused",
+			Method:  http.MethodGet,
+			Handler: h.listUnused,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "glossary", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/glossary/{id}/usage",
+			Method:  http.MethodGet,
+			Handler: h.getUsage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "glossary", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/glossary/{id}/click",
+			Method:  http.MethodPost,
+			Handler: h.recordClick,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "glossary", "view"),
+				common.WithRateLimit(h.config, 100, 60),
+			},
+		},
 		{
 			Path:    "/api/v1/glossary/",
 			Method:  http.MethodPost,
@@ -134,6 +134,7 @@ func (h *Handler) getTerm(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.lookups.Record(r.Context(), lookups.CategoryGlossaryTerm)
+	h.usage.RecordView(term.ID)
 
 	common.RespondJSON(w, http.StatusOK, term)
 }
@@ -310,6 +311,12 @@ func (h *Handler) searchTerms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.TrimSpace(query) != "" {
+		for _, term := range result.Terms {
+			h.usage.RecordSearch(term.ID)
+		}
+	}
+
 	common.RespondJSON(w, http.StatusOK, result)
 }
 
@@ -0,0 +1,142 @@
+package glossary
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/glossary"
+	"github.com/rs/zerolog/log"
+)
+
+func parseDays(r *http.Request) int {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	return days
+}
+
+// GetUsage retrieves usage stats for a glossary term
+// @Summary Get glossary term usage
+// @Description Retrieve how many assets use a glossary term and how often it was searched, viewed and clicked through over a window
+// @Tags glossary
+// @Produce json
+// @Param id path string true "Glossary Term ID"
+// @Param days query int false "Days of usage to include" default(90)
+// @Success 200 {object} glossary.TermUsage
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /glossary/{id}/usage [get]
+func (h *Handler) getUsage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	usage, err := h.usage.GetUsage(r.Context(), id, parseDays(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, glossary.ErrInvalidInput):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, glossary.ErrTermNotFound):
+			common.RespondError(w, http.StatusNotFound, "Glossary term not found")
+		default:
+			log.Error().Err(err).Str("id", id).Msg("Failed to get glossary term usage")
+			common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, usage)
+}
+
+// ListUsage lists usage stats for all glossary terms
+// @Summary List glossary term usage
+// @Description Retrieve usage stats for every glossary term over a window. Searches count the times a term appeared in search results, clicks the times it was opened from them.
+// @Tags glossary
+// @Produce json
+// @Param days query int false "Days of usage to include" default(90)
+// @Param sort query string false "Sort order" Enums(searches, views, clicks, ctr, assets, name) default(searches)
+// @Param limit query int false "Maximum number of terms to return" default(50)
+// @Param offset query int false "Number of terms to skip" default(0)
+// @Success 200 {object} glossary.UsageListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /glossary/usage [get]
+func (h *Handler) listUsage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := glossary.UsageFilter{
+		Days:   parseDays(r),
+		Sort:   query.Get("sort"),
+		Limit:  common.ParseLimit(query.Get("limit"), 50, 500),
+		Offset: common.ParseOffset(query.Get("offset")),
+	}
+
+	result, err := h.usage.ListUsage(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, glossary.ErrInvalidInput) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to list glossary term usage")
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListUnused lists glossary terms nobody uses
+// @Summary List unused glossary terms
+// @Description Retrieve terms older than the window that are attached to no assets and were not searched, viewed or clicked within it, oldest first
+// @Tags glossary
+// @Produce json
+// @Param days query int false "Days without use" default(90)
+// @Param limit query int false "Maximum number of terms to return" default(50)
+// @Param offset query int false "Number of terms to skip" default(0)
+// @Success 200 {object} glossary.UnusedListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /glossary/usage/unused [get]
+func (h *Handler) listUnused(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.usage.ListUnused(r.Context(), parseDays(r), limit, offset)
+	if err != nil {
+		if errors.Is(err, glossary.ErrInvalidInput) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to list unused glossary terms")
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// RecordClick records a click through to a glossary term
+// @Summary Record glossary term click
+// @Description Record that a glossary term was opened from search results
+// @Tags glossary
+// @Param id path string true "Glossary Term ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /glossary/{id}/click [post]
+func (h *Handler) recordClick(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	term, err := h.glossaryService.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, glossary.ErrTermNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Glossary term not found")
+			return
+		}
+		log.Error().Err(err).Str("id", id).Msg("Failed to get glossary term")
+		common.RespondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	h.usage.RecordClick(term.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,47 @@
+package graphexport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/graphexport"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Export catalog graph
+// @Description Stream every asset, lineage edge and ownership record as newline-delimited JSON. Each line is a record with the cursor that resumes the export after it; the last line has kind "end" and, when more records remain, a next_cursor to pass back. A response without an end line was interrupted and can be resumed from the last record's cursor.
+// @Tags export
+// @Produce application/x-ndjson
+// @Param cursor query string false "Cursor to resume from"
+// @Param limit query int false "Maximum records in this response" default(10000)
+// @Success 200 {array} graphexport.Line
+// @Failure 400 {object} common.ErrorResponse
+// @Router /export/graph [get]
+func (h *Handler) exportGraph(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	cursor := query.Get("cursor")
+	if err := graphexport.ValidateCursor(cursor); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	limit := graphexport.DefaultLimit
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			common.RespondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	if err := h.svc.Export(r.Context(), w, cursor, limit); err != nil {
+		// Headers are already sent; the missing end line tells the client
+		// the export was cut short.
+		log.Error().Err(err).Msg("Failed to export catalog graph")
+	}
+}
@@ -0,0 +1,194 @@
+package incidents
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/incident"
+	"github.com/rs/zerolog/log"
+)
+
+type SyncResponse struct {
+	Synced int `json:"synced"`
+} // @name IncidentSyncResponse
+
+// @Summary List asset incident links
+// @Description List ticket and incident links attached directly to an asset, whatever their status
+// @Tags incidents
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {array} incident.Link
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/assets/{assetId} [get]
+func (h *Handler) listAssetIncidents(w http.ResponseWriter, r *http.Request) {
+	links, err := h.svc.ListForAsset(r.Context(), r.PathValue("assetId"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list asset incident links")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list incident links")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, links)
+}
+
+// @Summary List known issues affecting an asset
+// @Description Active incident links on the asset and propagating links on its upstream dependencies
+// @Tags incidents
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param depth query int false "Upstream lineage depth" default(5)
+// @Success 200 {array} incident.AffectingLink
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/assets/{assetId}/affecting [get]
+func (h *Handler) listAffectingIncidents(w http.ResponseWriter, r *http.Request) {
+	depth := common.ParseLimit(r.URL.Query().Get("depth"), 5, 10)
+
+	links, err := h.svc.ListAffecting(r.Context(), r.PathValue("assetId"), depth)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list affecting incident links")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list incident links")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, links)
+}
+
+// @Summary Link a ticket or incident to an asset
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param link body incident.CreateInput true "Incident link"
+// @Success 201 {object} incident.Link
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/assets/{assetId} [post]
+func (h *Handler) createIncidentLink(w http.ResponseWriter, r *http.Request) {
+	var input incident.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var createdBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = &usr.ID
+	}
+
+	link, err := h.svc.Create(r.Context(), r.PathValue("assetId"), input, createdBy)
+	if err != nil {
+		switch {
+		case incident.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, incident.ErrAlreadyExists):
+			common.RespondError(w, http.StatusConflict, "Incident link already exists for this asset")
+		default:
+			log.Error().Err(err).Msg("Failed to create incident link")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to create incident link")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, link)
+}
+
+// @Summary Get incident link
+// @Tags incidents
+// @Produce json
+// @Param id path string true "Incident link ID"
+// @Success 200 {object} incident.Link
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/{id} [get]
+func (h *Handler) getIncidentLink(w http.ResponseWriter, r *http.Request) {
+	link, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Incident link not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get incident link")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get incident link")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, link)
+}
+
+// @Summary Update incident link
+// @Description Update a link by hand, e.g. for providers that are not polled
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident link ID"
+// @Param link body incident.UpdateInput true "Fields to update"
+// @Success 200 {object} incident.Link
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/{id} [put]
+func (h *Handler) updateIncidentLink(w http.ResponseWriter, r *http.Request) {
+	var input incident.UpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	link, err := h.svc.Update(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		switch {
+		case incident.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, incident.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Incident link not found")
+		default:
+			log.Error().Err(err).Msg("Failed to update incident link")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to update incident link")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, link)
+}
+
+// @Summary Delete incident link
+// @Tags incidents
+// @Param id path string true "Incident link ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/{id} [delete]
+func (h *Handler) deleteIncidentLink(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("id")); err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Incident link not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete incident link")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete incident link")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Poll incident statuses now
+// @Description Refresh active links from their trackers without waiting for the next poll
+// @Tags incidents
+// @Produce json
+// @Success 200 {object} SyncResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /incidents/sync [post]
+func (h *Handler) syncIncidentStatuses(w http.ResponseWriter, r *http.Request) {
+	synced, err := h.svc.SyncStatuses(r.Context(), 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sync incident statuses")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to sync incident statuses")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, SyncResponse{Synced: synced})
+}
@@ -2,6 +2,7 @@ package lineage
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
@@ -9,19 +10,16 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-type BatchLineageResult struct {
-	Edge   lineage.LineageEdge `json:"edge"`
-	Status string              `json:"status"` // "created", "duplicate", or "existing"
-} // @name BatchLineageResult
-
 // @Summary Batch create lineage edges
-// @Description Create lineage edges in batch
+// @Description Create up to 1000 lineage edges in a single transaction. Each edge gets its own result: created, existing, duplicate (repeated within the batch), invalid or failed. Endpoints missing from the catalog are created as stub assets unless create_stubs is false.
 // @Tags lineage
 // @Accept json
 // @Produce json
 // @Param edges body []lineage.LineageEdge true "Array of lineage edges to create"
-// @Success 200 {array} BatchLineageResult
+// @Param create_stubs query bool false "Create stub assets for unknown endpoints" default(true)
+// @Success 200 {array} lineage.BatchEdgeResult
 // @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
 // @Router /lineage/batch [post]
 func (h *Handler) batchCreateLineage(w http.ResponseWriter, r *http.Request) {
 	var edges []lineage.LineageEdge
@@ -30,54 +28,23 @@ func (h *Handler) batchCreateLineage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results := make([]BatchLineageResult, 0, len(edges))
-	seenEdges := make(map[string]struct{})
-
-	for _, edge := range edges {
-		edgeKey := edge.Source + "->" + edge.Target
-		if _, exists := seenEdges[edgeKey]; exists {
-			results = append(results, BatchLineageResult{
-				Edge:   edge,
-				Status: "duplicate",
-			})
-			continue
-		}
-		seenEdges[edgeKey] = struct{}{}
-
-		exists, err := h.lineageService.EdgeExists(r.Context(), edge.Source, edge.Target)
-		if err != nil {
-			log.Error().Err(err).
-				Str("source", edge.Source).
-				Str("target", edge.Target).
-				Msg("Failed to check lineage edge")
-			continue
-		}
-
-		if exists {
-			results = append(results, BatchLineageResult{
-				Edge:   edge,
-				Status: "existing",
-			})
-			continue
-		}
+	opts := lineage.BatchOptions{
+		CreateStubs: r.URL.Query().Get("create_stubs") != "false",
+		CreatedBy:   "system",
+	}
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		opts.CreatedBy = usr.Name
+	}
 
-		// Use edge.Type if provided, otherwise default to empty string
-		lineageType := ""
-		if edge.Type != "" {
-			lineageType = edge.Type
+	results, err := h.lineageService.BatchCreateLineage(r.Context(), edges, opts)
+	if err != nil {
+		if errors.Is(err, lineage.ErrBatchTooLarge) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
 		}
-		if _, err := h.lineageService.CreateDirectLineage(r.Context(), edge.Source, edge.Target, lineageType); err != nil {
-			log.Error().Err(err).
-				Str("source", edge.Source).
-				Str("target", edge.Target).
-				Msg("Failed to create lineage edge")
-			continue
-		}
-
-		results = append(results, BatchLineageResult{
-			Edge:   edge,
-			Status: "created",
-		})
+		log.Error().Err(err).Int("edges", len(edges)).Msg("Failed to create lineage batch")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to create lineage edges")
+		return
 	}
 
 	common.RespondJSON(w, http.StatusOK, results)
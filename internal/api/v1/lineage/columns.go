@@ -0,0 +1,127 @@
+package lineage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/telemetry/lookups"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Create column lineage
+// @Description Record which source columns feed which target columns, e.g. from dbt, Trino or a custom pipeline. Existing edges have their transformation and job updated. Both assets of every edge must already exist.
+// @Tags lineage
+// @Accept json
+// @Produce json
+// @Param edges body []lineage.ColumnLineageEdge true "Column lineage edges to create"
+// @Success 200 {array} lineage.ColumnLineageEdge
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/columns [post]
+func (h *Handler) createColumnLineage(w http.ResponseWriter, r *http.Request) {
+	var edges []lineage.ColumnLineageEdge
+	if err := json.NewDecoder(r.Body).Decode(&edges); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.lineageService.CreateColumnLineage(r.Context(), edges)
+	if err != nil {
+		switch {
+		case errors.Is(err, lineage.ErrInvalidColumnLineage):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, err.Error())
+		default:
+			log.Error().Err(err).Int("edges", len(edges)).Msg("Failed to create column lineage")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to create column lineage")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, created)
+}
+
+// @Summary Get column lineage for an asset
+// @Description Get the column edges upstream and downstream of an asset, or of one of its columns when column is set
+// @Tags lineage
+// @Produce json
+// @Param id path string true "Asset ID" format(uuid)
+// @Param column query string false "Column to trace, matched case-insensitively"
+// @Param direction query string false "Direction of lineage" Enums(upstream, downstream, both) default(both)
+// @Param depth query int false "Maximum hops to follow" default(5) maximum(10)
+// @Success 200 {object} lineage.ColumnLineageResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/columns/assets/{id} [get]
+func (h *Handler) getColumnLineage(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(r.URL.Path, "/")
+	assetID := parts[len(parts)-1]
+	if assetID == "" {
+		common.RespondError(w, http.StatusBadRequest, "Asset ID is required")
+		return
+	}
+
+	q := lineage.ColumnLineageQuery{
+		AssetID:   assetID,
+		Column:    r.URL.Query().Get("column"),
+		Direction: r.URL.Query().Get("direction"),
+	}
+	if v := r.URL.Query().Get("depth"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil {
+			common.RespondError(w, http.StatusBadRequest, "depth must be an integer")
+			return
+		}
+		q.Depth = depth
+	}
+
+	resp, err := h.lineageService.GetColumnLineage(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, lineage.ErrInvalidColumnLineage):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).
+				Str("asset_id", assetID).
+				Str("column", q.Column).
+				Msg("Failed to get column lineage")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to get column lineage")
+		}
+		return
+	}
+
+	h.lookups.Record(r.Context(), lookups.CategoryLineage)
+
+	common.RespondJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Delete column lineage
+// @Description Delete a column lineage edge by its ID
+// @Tags lineage
+// @Param id path string true "Column edge ID" format(uuid)
+// @Success 200 "OK"
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/columns/{id} [delete]
+func (h *Handler) deleteColumnLineage(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+
+	if err := h.lineageService.DeleteColumnLineage(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("Failed to delete column lineage")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete column lineage")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
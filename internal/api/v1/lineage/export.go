@@ -0,0 +1,70 @@
+package lineage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/query"
+	"github.com/marmotdata/marmot/internal/telemetry/lookups"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Export lineage graph
+// @Description Export the lineage graph as GraphML (Gephi, yEd), DOT (Graphviz) or Cypher statements (Neo4j). When asset_id is set the export covers that asset's upstream and downstream lineage up to depth hops; otherwise it covers the whole graph up to 10000 assets. A capped export reports "limit" in the X-Marmot-Truncated header and starts with a comment saying so.
+// @Tags lineage
+// @Produce plain
+// @Param format query string true "Export format" Enums(graphml, dot, cypher)
+// @Param asset_id query string false "Root asset ID"
+// @Param depth query int false "Traversal depth from the root asset (max 20)" default(10)
+// @Success 200 {string} string "Lineage graph"
+// @Header 200 {string} X-Marmot-Truncated "Set to limit when the export stopped at the node limit"
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/export [get]
+func (h *Handler) exportLineage(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	format := strings.ToLower(params.Get("format"))
+	switch format {
+	case lineage.ExportFormatGraphML, lineage.ExportFormatDOT, lineage.ExportFormatCypher:
+	default:
+		common.RespondError(w, http.StatusBadRequest, "format must be one of graphml, dot or cypher")
+		return
+	}
+
+	assetID := params.Get("asset_id")
+	depth := lineage.DefaultExportDepth
+	if d, err := strconv.Atoi(params.Get("depth")); err == nil && d > 0 {
+		depth = min(d, lineage.MaxExportDepth)
+	}
+
+	graph, err := h.lineageService.ExportLineage(r.Context(), assetID, depth)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+			return
+		}
+		log.Error().Err(err).Str("asset_id", assetID).Msg("Failed to export lineage")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to export lineage")
+		return
+	}
+
+	h.lookups.Record(r.Context(), lookups.CategoryLineage)
+
+	w.Header().Set("Content-Type", lineage.ExportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="lineage.%s"`, lineage.ExportFileExtension(format)))
+	if graph.Truncated {
+		w.Header().Set(common.TruncatedHeader, query.TruncatedLimit)
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := lineage.WriteGraph(w, graph, format); err != nil {
+		log.Error().Err(err).Msg("Failed to write lineage export")
+	}
+}
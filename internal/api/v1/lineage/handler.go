@@ -51,6 +51,36 @@ func (h *Handler) Routes() []common.Route {
 				common.WithRateLimit(h.config, 30, 60), // 30 requests per 60 seconds
 			},
 		},
+		{
+			Path:    "/api/v1/lineage/export",
+			Method:  http.MethodGet,
+			Handler: h.exportLineage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 10, 60),
+			},
+		},
+		{
+			Path:    "/api/v1/lineage/impact",
+			Method:  http.MethodPost,
+			Handler: h.analyzeImpact,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+		{
+			Path:    "/api/v1/lineage/paths",
+			Method:  http.MethodGet,
+			Handler: h.findLineagePaths,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
 		{
 			Path:    "/api/v1/lineage/direct",
 			Method:  http.MethodPost,
@@ -86,6 +116,46 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "assets", "manage"),
+				common.WithIdempotency(),
+			},
+		},
+		{
+			Path:    "/api/v1/lineage/columns",
+			Method:  http.MethodPost,
+			Handler: h.createColumnLineage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+				common.WithIdempotency(),
+			},
+		},
+		{
+			Path:    "/api/v1/lineage/columns/assets/{id}",
+			Method:  http.MethodGet,
+			Handler: h.getColumnLineage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+		{
+			Path:    "/api/v1/lineage/columns/{id}",
+			Method:  http.MethodDelete,
+			Handler: h.deleteColumnLineage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "manage"),
+			},
+		},
+		{
+			Path:    "/api/v1/admin/lineage/purge",
+			Method:  http.MethodPost,
+			Handler: h.purgeLineage,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "users", "manage"),
+				common.WithRateLimit(h.config, 5, 60),
 			},
 		},
 		// OpenLineage endpoint - auth configurable via openlineage.auth.enabled
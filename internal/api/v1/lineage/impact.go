@@ -0,0 +1,47 @@
+package lineage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Analyze schema change impact
+// @Description Report the downstream columns, models and dashboards affected by a proposed change to an asset's columns. Intended to be called from CI in producer repositories; breaking is true when a drop, rename or type change reaches any downstream asset.
+// @Tags lineage
+// @Accept json
+// @Produce json
+// @Param request body lineage.ImpactRequest true "Proposed column changes"
+// @Success 200 {object} lineage.ImpactReport
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/impact [post]
+func (h *Handler) analyzeImpact(w http.ResponseWriter, r *http.Request) {
+	var req lineage.ImpactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	report, err := h.lineageService.AnalyzeImpact(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, lineage.ErrInvalidImpactRequest):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("mrn", req.MRN).Msg("Failed to analyze schema change impact")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to analyze impact")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
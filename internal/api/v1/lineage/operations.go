@@ -3,8 +3,10 @@ package lineage
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -99,6 +101,21 @@ func (h *Handler) createDirectLineage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(edge.Columns) > 0 {
+		if err := h.lineageService.SetColumnLineage(r.Context(), edge.Source, edge.Target, edge.Columns, edge.JobMRN); err != nil {
+			if errors.Is(err, lineage.ErrInvalidColumnLineage) {
+				common.RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			log.Error().Err(err).
+				Str("source", edge.Source).
+				Str("target", edge.Target).
+				Msg("Failed to set column lineage")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to set column lineage")
+			return
+		}
+	}
+
 	edge.ID = edgeID
 	common.RespondJSON(w, http.StatusOK, edge)
 }
@@ -149,6 +166,13 @@ func (h *Handler) deleteDirectLineage(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Asset ID" format(uuid)
 // @Param limit query int false "Maximum depth of lineage graph" default(10)
 // @Param direction query string false "Direction of lineage (upstream, downstream, or both)" Enums(upstream, downstream, both) default(both)
+// @Param hide_stale query bool false "Hide edges their pipeline has stopped reporting, and assets only reachable through them"
+// @Param stale_after_runs query int false "Completed pipeline runs an edge can be missing from before it is stale" default(3)
+// @Param hide_stubs query bool false "Hide stub assets, keeping paths through them"
+// @Param collapse_pass_through query bool false "Collapse assets with exactly one input and one output into a single edge"
+// @Param group_by query string false "Merge assets sharing a provider or schema into one node" Enums(provider, schema)
+// @Param max_edges_per_node query int false "Maximum incoming and outgoing edges kept per node; hidden edges are counted on the node"
+// @Param expand query []string false "Node IDs exempt from max_edges_per_node" collectionFormat(multi)
 // @Success 200 {object} lineage.LineageResponse
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 404 {object} common.ErrorResponse
@@ -179,7 +203,18 @@ func (h *Handler) getAssetLineage(w http.ResponseWriter, r *http.Request) {
 		direction = "both"
 	}
 
-	lineageResp, err := h.lineageService.GetAssetLineage(r.Context(), assetID, limit, direction)
+	opts, err := parseSimplifyOptions(r.URL.Query())
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var lineageResp *lineage.LineageResponse
+	if opts.IsZero() && opts.StaleAfterRuns == 0 {
+		lineageResp, err = h.lineageService.GetAssetLineage(r.Context(), assetID, limit, direction)
+	} else {
+		lineageResp, err = h.lineageService.GetSimplifiedAssetLineage(r.Context(), assetID, limit, direction, opts)
+	}
 	if err != nil {
 		log.Error().Err(err).
 			Str("asset_id", assetID).
@@ -201,6 +236,41 @@ func (h *Handler) getAssetLineage(w http.ResponseWriter, r *http.Request) {
 	common.RespondJSON(w, http.StatusOK, lineageResp)
 }
 
+func parseSimplifyOptions(query url.Values) (lineage.SimplifyOptions, error) {
+	opts := lineage.SimplifyOptions{
+		HideStale:           query.Get("hide_stale") == "true",
+		HideStubs:           query.Get("hide_stubs") == "true",
+		CollapsePassThrough: query.Get("collapse_pass_through") == "true",
+		GroupBy:             query.Get("group_by"),
+	}
+
+	if v := query.Get("stale_after_runs"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("stale_after_runs must be an integer")
+		}
+		opts.StaleAfterRuns = n
+	}
+
+	if v := query.Get("max_edges_per_node"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("max_edges_per_node must be an integer")
+		}
+		opts.MaxEdgesPerNode = n
+	}
+
+	for _, v := range query["expand"] {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				opts.Expand = append(opts.Expand, id)
+			}
+		}
+	}
+
+	return opts, opts.Validate()
+}
+
 // @Summary Ingest OpenLineage event
 // @Description Process OpenLineage run events and update assets/lineage accordingly
 // @Tags lineage
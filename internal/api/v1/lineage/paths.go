@@ -0,0 +1,69 @@
+package lineage
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/telemetry/lookups"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Find lineage paths between two assets
+// @Description Find the most plausible routes data takes downstream from one asset to another. Edges are scored by how they were recorded and how recently they were confirmed or observed, and paths by the product of their edge scores. The first path is the best one; paths is empty when the target isn't reachable within depth hops.
+// @Tags lineage
+// @Produce json
+// @Param from query string true "MRN of the upstream asset"
+// @Param to query string true "MRN of the downstream asset"
+// @Param mode query string false "Rank paths by confidence, or by hop count with confidence breaking ties" Enums(confidence, shortest) default(confidence)
+// @Param depth query int false "Maximum hops to search from the upstream asset" default(6) maximum(10)
+// @Param limit query int false "Maximum number of paths" default(3) maximum(10)
+// @Success 200 {object} lineage.PathResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /lineage/paths [get]
+func (h *Handler) findLineagePaths(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := lineage.PathRequest{
+		From: query.Get("from"),
+		To:   query.Get("to"),
+		Mode: query.Get("mode"),
+	}
+	for name, dst := range map[string]*int{"depth": &req.Depth, "limit": &req.Limit} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			common.RespondError(w, http.StatusBadRequest, name+" must be an integer")
+			return
+		}
+		*dst = n
+	}
+
+	result, err := h.lineageService.FindPaths(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, lineage.ErrInvalidPathRequest):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).
+				Str("from", req.From).
+				Str("to", req.To).
+				Msg("Failed to find lineage paths")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to find lineage paths")
+		}
+		return
+	}
+
+	h.lookups.Record(r.Context(), lookups.CategoryLineage)
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,46 @@
+package lineage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Purge lineage edges
+// @Description Delete lineage edges in bulk by the pipeline that reported them, an MRN pattern matched against either end (* is a wildcard) and/or edge type. Filters are combined and at least one is required. Assets are never deleted. With dry_run set nothing is deleted and the response lists what would be.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body lineage.PurgeRequest true "Edges to purge"
+// @Success 200 {object} lineage.PurgeResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/lineage/purge [post]
+func (h *Handler) purgeLineage(w http.ResponseWriter, r *http.Request) {
+	var req lineage.PurgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.lineageService.PurgeLineage(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, lineage.ErrInvalidPurgeRequest) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).
+			Str("pipeline", req.Pipeline).
+			Str("mrn_pattern", req.MRNPattern).
+			Str("type", req.Type).
+			Msg("Failed to purge lineage edges")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to purge lineage edges")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
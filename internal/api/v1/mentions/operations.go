@@ -0,0 +1,124 @@
+package mentions
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/mention"
+	"github.com/rs/zerolog/log"
+)
+
+const maxResolveLength = 20000
+
+type ResolveRequest struct {
+	Text string `json:"text"`
+} // @name ResolveMentionsRequest
+
+type ResolveResponse struct {
+	Targets []mention.Target `json:"targets"`
+} // @name ResolveMentionsResponse
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	if mention.IsValidationError(err) {
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Error().Err(err).Msg(msg)
+	common.RespondError(w, http.StatusInternalServerError, msg)
+}
+
+// @Summary List the mentions in a description, question or answer
+// @Tags mentions
+// @Produce json
+// @Param source_type query string true "asset_description, question or answer"
+// @Param source_id query string true "Asset, question or answer ID"
+// @Success 200 {array} mention.Mention
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /mentions [get]
+func (h *Handler) listSourceMentions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	mentions, err := h.svc.ListForSource(r.Context(), mention.SourceType(query.Get("source_type")), query.Get("source_id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list mentions")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, mentions)
+}
+
+// @Summary List where a user, team or glossary term is mentioned
+// @Tags mentions
+// @Produce json
+// @Param type path string true "user, team or term"
+// @Param id path string true "User, team or term ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} mention.ListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /mentions/targets/{type}/{id} [get]
+func (h *Handler) listTargetMentions(w http.ResponseWriter, r *http.Request) {
+	h.listForTarget(w, r, mention.TargetType(r.PathValue("type")), r.PathValue("id"))
+}
+
+// @Summary List where the current user is mentioned
+// @Tags mentions
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} mention.ListResult
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /mentions/me [get]
+func (h *Handler) listMyMentions(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	h.listForTarget(w, r, mention.TargetUser, usr.ID)
+}
+
+func (h *Handler) listForTarget(w http.ResponseWriter, r *http.Request, targetType mention.TargetType, targetID string) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListForTarget(r.Context(), targetType, targetID, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list mentions")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Resolve the mentions in a piece of text
+// @Description Parses @user, @team and #term mentions and returns the ones that match, without storing them. Editors use this to preview which mentions will link.
+// @Tags mentions
+// @Accept json
+// @Produce json
+// @Param request body ResolveRequest true "Text to parse"
+// @Success 200 {object} ResolveResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Router /mentions/resolve [post]
+func (h *Handler) resolveMentions(w http.ResponseWriter, r *http.Request) {
+	var req ResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Text) > maxResolveLength {
+		common.RespondError(w, http.StatusBadRequest, "text is too long")
+		return
+	}
+
+	targets := h.svc.Resolve(r.Context(), mention.Parse(req.Text))
+	if targets == nil {
+		targets = []mention.Target{}
+	}
+
+	common.RespondJSON(w, http.StatusOK, ResolveResponse{Targets: targets})
+}
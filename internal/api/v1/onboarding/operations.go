@@ -0,0 +1,156 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/onboarding"
+	"github.com/rs/zerolog/log"
+)
+
+type StepRequest struct {
+	// Scope is "user" (default) or "deployment". Deployment changes apply
+	// to every user and need users:manage.
+	Scope string `json:"scope"`
+} // @name OnboardingStepRequest
+
+type DismissRequest struct {
+	Dismissed bool `json:"dismissed"`
+} // @name OnboardingDismissRequest
+
+// @Summary Get onboarding progress
+// @Description Guided catalog setup checklist for the current user. Steps are completed automatically once Marmot sees the work done.
+// @Tags onboarding
+// @Produce json
+// @Success 200 {object} onboarding.Progress
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /onboarding [get]
+func (h *Handler) getProgress(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	progress, err := h.svc.GetProgress(r.Context(), usr.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get onboarding progress")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get onboarding progress")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, progress)
+}
+
+// @Summary Skip an onboarding step
+// @Description Skip the current step for yourself or, with scope "deployment", for everyone
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param step path string true "Step ID"
+// @Param request body StepRequest false "Scope"
+// @Success 200 {object} onboarding.Progress
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /onboarding/steps/{step}/skip [post]
+func (h *Handler) skipStep(w http.ResponseWriter, r *http.Request) {
+	h.changeStep(w, r, h.svc.Skip)
+}
+
+// @Summary Reset an onboarding step
+// @Description Return a step to pending for yourself or, with scope "deployment", for everyone
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param step path string true "Step ID"
+// @Param request body StepRequest false "Scope"
+// @Success 200 {object} onboarding.Progress
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /onboarding/steps/{step}/reset [post]
+func (h *Handler) resetStep(w http.ResponseWriter, r *http.Request) {
+	h.changeStep(w, r, h.svc.Reset)
+}
+
+func (h *Handler) changeStep(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, stepID, scope, userID string) (*onboarding.Progress, error)) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req StepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = onboarding.ScopeUser
+	}
+
+	if req.Scope == onboarding.ScopeDeployment {
+		allowed, err := h.userService.HasPermission(r.Context(), usr.ID, "users", "manage")
+		if err != nil || !allowed {
+			common.RespondError(w, http.StatusForbidden, "Changing deployment onboarding requires users:manage")
+			return
+		}
+	}
+
+	progress, err := change(r.Context(), r.PathValue("step"), req.Scope, usr.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, onboarding.ErrUnknownStep):
+			common.RespondError(w, http.StatusNotFound, "Onboarding step not found")
+		case onboarding.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Error().Err(err).Msg("Failed to update onboarding step")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to update onboarding step")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, progress)
+}
+
+// @Summary Dismiss the onboarding checklist
+// @Description Hide or show the checklist for the current user
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param request body DismissRequest true "Dismissed"
+// @Success 200 {object} onboarding.Progress
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /onboarding/dismiss [put]
+func (h *Handler) setDismissed(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req DismissRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	progress, err := h.svc.SetDismissed(r.Context(), usr.ID, req.Dismissed)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to dismiss onboarding")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to update onboarding")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, progress)
+}
@@ -0,0 +1,41 @@
+package ownerimport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/ownerimport"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Import asset ownership from a mapping
+// @Description Apply a CODEOWNERS-style or YAML mapping of MRN patterns to owners. With dry_run the changes are only reported.
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param request body ownerimport.Request true "Ownership mapping"
+// @Success 200 {object} ownerimport.Result
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/owners/import [post]
+func (h *Handler) importOwners(w http.ResponseWriter, r *http.Request) {
+	var req ownerimport.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Import(r.Context(), req)
+	if err != nil {
+		if ownerimport.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to import ownership")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to import ownership")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,284 @@
+package ownershiprequests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/ownershiprequest"
+	"github.com/rs/zerolog/log"
+)
+
+// actor resolves the calling user. Users who can manage assets may decide
+// any request.
+func (h *Handler) actor(r *http.Request) (ownershiprequest.Actor, bool) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		return ownershiprequest.Actor{}, false
+	}
+	canModerate, err := h.userService.HasPermission(r.Context(), usr.ID, "assets", "manage")
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", usr.ID).Msg("Failed to check asset manage permission")
+	}
+	return ownershiprequest.Actor{UserID: usr.ID, CanModerate: canModerate}, true
+}
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case ownershiprequest.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ownershiprequest.ErrForbidden):
+		common.RespondError(w, http.StatusForbidden, "Only the requester, asset owners or moderators can do this")
+	case errors.Is(err, ownershiprequest.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Ownership request not found")
+	case errors.Is(err, ownershiprequest.ErrNotPending):
+		common.RespondError(w, http.StatusConflict, "Request has already been decided")
+	case errors.Is(err, ownershiprequest.ErrDuplicate):
+		common.RespondError(w, http.StatusConflict, "You already have a pending request for this asset")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List asset ownership requests
+// @Description Owners and moderators see every request for the asset; other users see only their own
+// @Tags ownership-requests
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected, cancelled)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ownershiprequest.ListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/assets/{assetId} [get]
+func (h *Handler) listAssetRequests(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListForAsset(r.Context(), r.PathValue("assetId"), ownershiprequest.Status(query.Get("status")), actor, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list ownership requests")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Request ownership of or access to an asset
+// @Description Asset owners are notified of new requests. Ownership may be requested for a team the caller belongs to.
+// @Tags ownership-requests
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param request body ownershiprequest.CreateInput true "Request"
+// @Success 201 {object} ownershiprequest.Request
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/assets/{assetId} [post]
+func (h *Handler) createRequest(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input ownershiprequest.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req, err := h.svc.Create(r.Context(), r.PathValue("assetId"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create ownership request")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, req)
+}
+
+// @Summary List my ownership requests
+// @Tags ownership-requests
+// @Produce json
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected, cancelled)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ownershiprequest.ListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/mine [get]
+func (h *Handler) listMyRequests(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListMine(r.Context(), ownershiprequest.Status(query.Get("status")), actor, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list ownership requests")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary List ownership requests to review
+// @Description Pending requests on assets the caller owns, directly or through a team
+// @Tags ownership-requests
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ownershiprequest.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/review [get]
+func (h *Handler) listReviewRequests(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListForReview(r.Context(), actor, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list ownership requests")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Get ownership request
+// @Description Visible to the requester, the asset's owners and moderators
+// @Tags ownership-requests
+// @Produce json
+// @Param id path string true "Request ID"
+// @Success 200 {object} ownershiprequest.Request
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/{id} [get]
+func (h *Handler) getRequest(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req, err := h.svc.Get(r.Context(), r.PathValue("id"), actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to get ownership request")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, req)
+}
+
+// @Summary Approve ownership request
+// @Description Approving an ownership request adds the requester, or their team, as an owner. The requester is notified.
+// @Tags ownership-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body ownershiprequest.DecideInput false "Optional note for the requester"
+// @Success 200 {object} ownershiprequest.Request
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/{id}/approve [post]
+func (h *Handler) approveRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideRequest(w, r, h.svc.Approve, "Failed to approve ownership request")
+}
+
+// @Summary Reject ownership request
+// @Description The requester is notified.
+// @Tags ownership-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body ownershiprequest.DecideInput false "Optional note for the requester"
+// @Success 200 {object} ownershiprequest.Request
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/{id}/reject [post]
+func (h *Handler) rejectRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideRequest(w, r, h.svc.Reject, "Failed to reject ownership request")
+}
+
+type decideFunc func(ctx context.Context, id string, input ownershiprequest.DecideInput, actor ownershiprequest.Actor) (*ownershiprequest.Request, error)
+
+func (h *Handler) decideRequest(w http.ResponseWriter, r *http.Request, decide decideFunc, msg string) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// The note is optional, so an empty body is allowed.
+	var input ownershiprequest.DecideInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req, err := decide(r.Context(), r.PathValue("id"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, msg)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, req)
+}
+
+// @Summary Cancel ownership request
+// @Description Withdraw a pending request. Only the requester or a moderator can cancel.
+// @Tags ownership-requests
+// @Produce json
+// @Param id path string true "Request ID"
+// @Success 200 {object} ownershiprequest.Request
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-requests/{id}/cancel [post]
+func (h *Handler) cancelRequest(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req, err := h.svc.Cancel(r.Context(), r.PathValue("id"), actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to cancel ownership request")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, req)
+}
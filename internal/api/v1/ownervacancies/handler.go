@@ -0,0 +1,66 @@
+package ownervacancies
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/ownervacancy"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	svc         *ownervacancy.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *ownervacancy.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/ownership-vacancies",
+			Method:  http.MethodGet,
+			Handler: h.getReport,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "teams", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+	}
+}
+
+// @Summary Get ownership vacancies
+// @Description Returns the assets and data products whose owners have all been deactivated or left their team, with their former owners and when admins were last alerted.
+// @Tags ownership-vacancies
+// @Produce json
+// @Success 200 {object} ownervacancy.Report
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ownership-vacancies [get]
+func (h *Handler) getReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.Report(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build ownership vacancy report")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to build ownership vacancy report")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
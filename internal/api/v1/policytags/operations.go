@@ -0,0 +1,275 @@
+package policytags
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/policytag"
+	"github.com/rs/zerolog/log"
+)
+
+type AssignRequest struct {
+	PolicyTagID string `json:"policy_tag_id"`
+	Column      string `json:"column,omitempty"`
+} // @name AssignPolicyTagRequest
+
+type EnforcementResponse struct {
+	Hints  []*policytag.EnforcementHint `json:"hints"`
+	Total  int                          `json:"total"`
+	Limit  int                          `json:"limit"`
+	Offset int                          `json:"offset"`
+} // @name PolicyEnforcementResponse
+
+// @Summary List policy tags
+// @Description List all policy tags and their enforcement hints
+// @Tags policy-tags
+// @Produce json
+// @Success 200 {array} policytag.PolicyTag
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags [get]
+func (h *Handler) listPolicyTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.svc.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list policy tags")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tags)
+}
+
+// @Summary Create policy tag
+// @Description Create a policy tag with masking and access hints for downstream query tools
+// @Tags policy-tags
+// @Accept json
+// @Produce json
+// @Param tag body policytag.CreateInput true "Policy tag to create"
+// @Success 201 {object} policytag.PolicyTag
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags [post]
+func (h *Handler) createPolicyTag(w http.ResponseWriter, r *http.Request) {
+	var input policytag.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var createdBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = &usr.ID
+	}
+
+	tag, err := h.svc.Create(r.Context(), input, createdBy)
+	if err != nil {
+		switch {
+		case policytag.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, policytag.ErrAlreadyExists):
+			common.RespondError(w, http.StatusConflict, "Policy tag already exists")
+		default:
+			log.Error().Err(err).Str("name", input.Name).Msg("Failed to create policy tag")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to create policy tag")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, tag)
+}
+
+// @Summary Get policy tag
+// @Tags policy-tags
+// @Produce json
+// @Param id path string true "Policy tag ID"
+// @Success 200 {object} policytag.PolicyTag
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/{id} [get]
+func (h *Handler) getPolicyTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, policytag.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Policy tag not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get policy tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get policy tag")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tag)
+}
+
+// @Summary Update policy tag
+// @Tags policy-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy tag ID"
+// @Param tag body policytag.UpdateInput true "Fields to update"
+// @Success 200 {object} policytag.PolicyTag
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/{id} [put]
+func (h *Handler) updatePolicyTag(w http.ResponseWriter, r *http.Request) {
+	var input policytag.UpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tag, err := h.svc.Update(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		switch {
+		case policytag.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, policytag.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Policy tag not found")
+		default:
+			log.Error().Err(err).Msg("Failed to update policy tag")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to update policy tag")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tag)
+}
+
+// @Summary Delete policy tag
+// @Description Delete a policy tag and remove it from all assets
+// @Tags policy-tags
+// @Param id path string true "Policy tag ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/{id} [delete]
+func (h *Handler) deletePolicyTag(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("id")); err != nil {
+		if errors.Is(err, policytag.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Policy tag not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete policy tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete policy tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List asset policy tags
+// @Description List policy tags assigned to an asset and its columns
+// @Tags policy-tags
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {array} policytag.Assignment
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId} [get]
+func (h *Handler) listAssetPolicyTags(w http.ResponseWriter, r *http.Request) {
+	assignments, err := h.svc.ListForAsset(r.Context(), r.PathValue("assetId"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list asset policy tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list asset policy tags")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, assignments)
+}
+
+// @Summary Assign policy tag
+// @Description Assign a policy tag to an asset, or to one of its columns
+// @Tags policy-tags
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param assignment body AssignRequest true "Policy tag assignment"
+// @Success 201 {object} policytag.Assignment
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId} [post]
+func (h *Handler) assignPolicyTag(w http.ResponseWriter, r *http.Request) {
+	var req AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var createdBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = &usr.ID
+	}
+
+	assignment, err := h.svc.Assign(r.Context(), r.PathValue("assetId"), req.PolicyTagID, req.Column, createdBy)
+	if err != nil {
+		switch {
+		case policytag.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, policytag.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Policy tag not found")
+		default:
+			log.Error().Err(err).Msg("Failed to assign policy tag")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to assign policy tag")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, assignment)
+}
+
+// @Summary Remove policy tag from asset
+// @Tags policy-tags
+// @Param assetId path string true "Asset ID"
+// @Param tagId path string true "Policy tag ID"
+// @Param column query string false "Column the tag was assigned to (omit for asset-level)"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId}/{tagId} [delete]
+func (h *Handler) unassignPolicyTag(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.Unassign(r.Context(), r.PathValue("assetId"), r.PathValue("tagId"), r.URL.Query().Get("column"))
+	if err != nil {
+		if errors.Is(err, policytag.ErrAssignmentNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Policy tag assignment not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to remove policy tag assignment")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to remove policy tag assignment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Export policy enforcement hints
+// @Description Flattened masking and access hints per asset and column, for query engines and proxies that enforce catalog policies
+// @Tags policy-tags
+// @Produce json
+// @Param mrn query []string false "Restrict to these asset MRNs"
+// @Param limit query int false "Limit" default(500)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} EnforcementResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/enforcement [get]
+func (h *Handler) listEnforcementHints(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 500, 5000)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	hints, total, err := h.svc.ListEnforcementHints(r.Context(), query["mrn"], limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy enforcement hints")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list enforcement hints")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, EnforcementResponse{
+		Hints:  hints,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
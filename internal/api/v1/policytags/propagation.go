@@ -0,0 +1,177 @@
+package policytags
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/policytag"
+	"github.com/rs/zerolog/log"
+)
+
+type OverrideAuditResponse struct {
+	Entries []*policytag.OverrideAuditEntry `json:"entries"`
+	Total   int                             `json:"total"`
+	Limit   int                             `json:"limit"`
+	Offset  int                             `json:"offset"`
+} // @name PolicyTagOverrideAuditResponse
+
+// @Summary List inherited policy tags
+// @Description List column policy tags an asset inherits through column lineage, with the upstream column each came from
+// @Tags policy-tags
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {array} policytag.InheritedAssignment
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId}/inherited [get]
+func (h *Handler) listInheritedPolicyTags(w http.ResponseWriter, r *http.Request) {
+	inherited, err := h.svc.ListInherited(r.Context(), r.PathValue("assetId"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list inherited policy tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list inherited policy tags")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, inherited)
+}
+
+// @Summary List policy tag overrides
+// @Description List the inherited policy tags cleared from an asset's columns
+// @Tags policy-tags
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {array} policytag.Override
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId}/overrides [get]
+func (h *Handler) listOverrides(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.svc.ListOverrides(r.Context(), r.PathValue("assetId"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy tag overrides")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list policy tag overrides")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, overrides)
+}
+
+// @Summary Clear inherited policy tag
+// @Description Stop a column inheriting a policy tag through column lineage. Columns derived from it stop inheriting the tag through it too. A reason is required and recorded in the override audit.
+// @Tags policy-tags
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param override body policytag.OverrideInput true "Tag and column to clear"
+// @Success 201 {object} policytag.Override
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId}/overrides [post]
+func (h *Handler) clearInheritedPolicyTag(w http.ResponseWriter, r *http.Request) {
+	var input policytag.OverrideInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var actor *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		actor = &usr.ID
+	}
+
+	override, err := h.svc.ClearInherited(r.Context(), r.PathValue("assetId"), input, actor)
+	if err != nil {
+		switch {
+		case policytag.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, policytag.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Policy tag not found")
+		default:
+			log.Error().Err(err).Msg("Failed to clear inherited policy tag")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to clear inherited policy tag")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, override)
+}
+
+// @Summary Restore inherited policy tag
+// @Description Remove an override so the column inherits the policy tag again
+// @Tags policy-tags
+// @Param assetId path string true "Asset ID"
+// @Param tagId path string true "Policy tag ID"
+// @Param column query string true "Column the tag was cleared from"
+// @Param reason query string false "Reason recorded in the override audit"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/assets/{assetId}/overrides/{tagId} [delete]
+func (h *Handler) restoreInheritedPolicyTag(w http.ResponseWriter, r *http.Request) {
+	var actor *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		actor = &usr.ID
+	}
+
+	query := r.URL.Query()
+	err := h.svc.RestoreInherited(r.Context(), r.PathValue("assetId"), r.PathValue("tagId"), query.Get("column"), query.Get("reason"), actor)
+	if err != nil {
+		if errors.Is(err, policytag.ErrOverrideNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Policy tag override not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to restore inherited policy tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to restore inherited policy tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List policy tag override audit
+// @Description List overrides being set and removed, newest first
+// @Tags policy-tags
+// @Produce json
+// @Param asset_id query string false "Restrict to one asset"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} OverrideAuditResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/overrides/audit [get]
+func (h *Handler) listOverrideAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	entries, total, err := h.svc.ListOverrideAudit(r.Context(), query.Get("asset_id"), limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy tag override audit")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list policy tag override audit")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, OverrideAuditResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// @Summary Recompute policy tag propagation
+// @Description Rebuild inherited column policy tags from current assignments, overrides and column lineage. This also runs periodically in the background.
+// @Tags policy-tags
+// @Produce json
+// @Success 200 {object} policytag.PropagationResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /policy-tags/propagation/recompute [post]
+func (h *Handler) recomputePropagation(w http.ResponseWriter, r *http.Request) {
+	result, err := h.svc.RecomputePropagation(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to recompute policy tag propagation")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to recompute policy tag propagation")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
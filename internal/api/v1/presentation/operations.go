@@ -0,0 +1,112 @@
+package presentation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/presentation"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary List presentation schemas
+// @Description List the presentation schema of every asset type that has one
+// @Tags presentation
+// @Produce json
+// @Success 200 {array} presentation.Schema
+// @Failure 500 {object} common.ErrorResponse
+// @Router /presentation [get]
+func (h *Handler) listSchemas(w http.ResponseWriter, r *http.Request) {
+	schemas, err := h.svc.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list presentation schemas")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list presentation schemas")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, schemas)
+}
+
+// @Summary Get presentation schema
+// @Description Get how the metadata of an asset type is grouped, labelled and linked in the UI
+// @Tags presentation
+// @Produce json
+// @Param type path string true "Asset type, e.g. Table"
+// @Success 200 {object} presentation.Schema
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /presentation/{type} [get]
+func (h *Handler) getSchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := h.svc.Get(r.Context(), r.PathValue("type"))
+	if err != nil {
+		if errors.Is(err, presentation.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Presentation schema not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get presentation schema")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get presentation schema")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, schema)
+}
+
+// @Summary Set presentation schema
+// @Description Create or replace the presentation schema of an asset type: which metadata keys are highlighted, how they are grouped and labelled, their units, and external link templates
+// @Tags presentation
+// @Accept json
+// @Produce json
+// @Param type path string true "Asset type, e.g. Table"
+// @Param schema body presentation.Input true "Presentation schema"
+// @Success 200 {object} presentation.Schema
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /presentation/{type} [put]
+func (h *Handler) putSchema(w http.ResponseWriter, r *http.Request) {
+	var input presentation.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updatedBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		updatedBy = &usr.ID
+	}
+
+	schema, err := h.svc.Put(r.Context(), r.PathValue("type"), input, updatedBy)
+	if err != nil {
+		if presentation.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to save presentation schema")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to save presentation schema")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, schema)
+}
+
+// @Summary Delete presentation schema
+// @Description Delete the presentation schema of an asset type so its metadata is shown ungrouped
+// @Tags presentation
+// @Param type path string true "Asset type, e.g. Table"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /presentation/{type} [delete]
+func (h *Handler) deleteSchema(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("type")); err != nil {
+		if errors.Is(err, presentation.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Presentation schema not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete presentation schema")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete presentation schema")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
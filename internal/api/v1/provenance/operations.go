@@ -0,0 +1,39 @@
+package provenance
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/provenance"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Get asset provenance
+// @Description Reconstructs how an asset reached its current state: the sources that report it ranked by merge priority, which source or edit each field's value came from, and a timeline of the pipeline runs and edits that shaped it, oldest first.
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param limit query int false "Latest runs and revisions to include" default(100)
+// @Success 200 {object} provenance.Chain
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/provenance/{id} [get]
+func (h *Handler) getProvenance(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	limit := common.ParseLimit(r.URL.Query().Get("limit"), provenance.DefaultLimit, provenance.MaxLimit)
+
+	chain, err := h.svc.Get(r.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+			return
+		}
+		log.Error().Err(err).Str("asset_id", id).Msg("Failed to build asset provenance")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to build asset provenance")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, chain)
+}
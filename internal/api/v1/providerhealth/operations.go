@@ -0,0 +1,52 @@
+package providerhealth
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/providerhealth"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Get provider and pipeline health
+// @Description Aggregates the last successful run, error streak, asset count trend and stale asset count of every pipeline, and the asset counts of every provider. Pipelines and providers are ordered worst first.
+// @Tags health
+// @Produce json
+// @Param stale_after_days query int false "Days without a sync before an asset is stale. Defaults to the archival policy's"
+// @Param runs query int false "Successful runs in each asset trend" default(10)
+// @Success 200 {object} providerhealth.Report
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /health/providers [get]
+func (h *Handler) getReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var opts providerhealth.Options
+	if v := query.Get("stale_after_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			common.RespondError(w, http.StatusBadRequest, "stale_after_days must be a positive integer")
+			return
+		}
+		opts.StaleAfter = time.Duration(days) * 24 * time.Hour
+	}
+	if v := query.Get("runs"); v != "" {
+		runs, err := strconv.Atoi(v)
+		if err != nil || runs < 1 {
+			common.RespondError(w, http.StatusBadRequest, "runs must be a positive integer")
+			return
+		}
+		opts.TrendRuns = runs
+	}
+
+	report, err := h.svc.Report(r.Context(), opts)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build provider health report")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to build provider health report")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,307 @@
+package questions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/question"
+	"github.com/rs/zerolog/log"
+)
+
+type AcceptRequest struct {
+	// AnswerID to accept. Empty clears the accepted answer.
+	AnswerID string `json:"answer_id"`
+} // @name AcceptAnswerRequest
+
+// actor resolves the calling user. Users who can manage assets may moderate
+// any question or answer.
+func (h *Handler) actor(r *http.Request) (question.Actor, bool) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		return question.Actor{}, false
+	}
+	canModerate, err := h.userService.HasPermission(r.Context(), usr.ID, "assets", "manage")
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", usr.ID).Msg("Failed to check asset manage permission")
+	}
+	return question.Actor{UserID: usr.ID, CanModerate: canModerate}, true
+}
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case question.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, question.ErrForbidden):
+		common.RespondError(w, http.StatusForbidden, "Only the author, asset owners or moderators can do this")
+	case errors.Is(err, question.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Question not found")
+	case errors.Is(err, question.ErrAnswerNotFound):
+		common.RespondError(w, http.StatusNotFound, "Answer not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List asset questions
+// @Tags questions
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param unanswered query bool false "Only questions without an accepted answer"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} question.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/assets/{assetId} [get]
+func (h *Handler) listAssetQuestions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListForAsset(r.Context(), r.PathValue("assetId"), query.Get("unanswered") == "true", limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list questions")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Ask a question about an asset
+// @Description Asset owners are notified of new questions
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param question body question.AskInput true "Question"
+// @Success 201 {object} question.Question
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/assets/{assetId} [post]
+func (h *Handler) askQuestion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input question.AskInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	q, err := h.svc.Ask(r.Context(), r.PathValue("assetId"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create question")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, q)
+}
+
+// @Summary Get question
+// @Description Get a question with its answers, accepted answer first
+// @Tags questions
+// @Produce json
+// @Param id path string true "Question ID"
+// @Success 200 {object} question.Question
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id} [get]
+func (h *Handler) getQuestion(w http.ResponseWriter, r *http.Request) {
+	q, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get question")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
+
+// @Summary Update question
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param question body question.UpdateInput true "Fields to update"
+// @Success 200 {object} question.Question
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id} [put]
+func (h *Handler) updateQuestion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input question.UpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	q, err := h.svc.Update(r.Context(), r.PathValue("id"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update question")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
+
+// @Summary Delete question
+// @Tags questions
+// @Param id path string true "Question ID"
+// @Success 204
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id} [delete]
+func (h *Handler) deleteQuestion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), r.PathValue("id"), actor); err != nil {
+		respondServiceError(w, err, "Failed to delete question")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Answer a question
+// @Description The asker is notified of new answers
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param answer body question.AnswerInput true "Answer"
+// @Success 201 {object} question.Answer
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id}/answers [post]
+func (h *Handler) answerQuestion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input question.AnswerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	a, err := h.svc.Answer(r.Context(), r.PathValue("id"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create answer")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, a)
+}
+
+// @Summary Update answer
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param answerId path string true "Answer ID"
+// @Param answer body question.AnswerInput true "Answer"
+// @Success 200 {object} question.Answer
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id}/answers/{answerId} [put]
+func (h *Handler) updateAnswer(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input question.AnswerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	a, err := h.svc.UpdateAnswer(r.Context(), r.PathValue("id"), r.PathValue("answerId"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update answer")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, a)
+}
+
+// @Summary Delete answer
+// @Tags questions
+// @Param id path string true "Question ID"
+// @Param answerId path string true "Answer ID"
+// @Success 204
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id}/answers/{answerId} [delete]
+func (h *Handler) deleteAnswer(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.DeleteAnswer(r.Context(), r.PathValue("id"), r.PathValue("answerId"), actor); err != nil {
+		respondServiceError(w, err, "Failed to delete answer")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Accept an answer
+// @Description Mark an answer as accepted so it is indexed into search with the asset. Only the asker and asset owners can accept.
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param accept body AcceptRequest true "Answer to accept"
+// @Success 200 {object} question.Question
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /questions/{id}/accept [put]
+func (h *Handler) acceptAnswer(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req AcceptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	q, err := h.svc.Accept(r.Context(), r.PathValue("id"), req.AnswerID, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to accept answer")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
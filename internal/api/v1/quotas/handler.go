@@ -0,0 +1,66 @@
+package quotas
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/quota"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	svc         *quota.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	svc *quota.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/quotas",
+			Method:  http.MethodGet,
+			Handler: h.getReport,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+	}
+}
+
+// @Summary Get quota usage
+// @Description Returns the usage of every configured quota: assets per provider, stub assets and runs started in the last 24 hours. Limits at or past the warning threshold are flagged.
+// @Tags quotas
+// @Produce json
+// @Success 200 {object} quota.Report
+// @Failure 500 {object} common.ErrorResponse
+// @Router /quotas [get]
+func (h *Handler) getReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.Report(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build quota report")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to build quota report")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,95 @@
+package runs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/rs/zerolog/log"
+)
+
+type RunArtifactsResponse struct {
+	Artifacts []*runs.RunArtifact `json:"artifacts"`
+} // @name RunArtifactsResponse
+
+// @Summary List run artifacts
+// @Description List the raw payloads stored for a run, one per batch the plugin sent. Payloads are only stored when artifact capture is enabled.
+// @Tags runs
+// @Produce json
+// @Param id path string true "Run ID"
+// @Success 200 {object} RunArtifactsResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Router /runs/{id}/artifacts [get]
+func (h *Handler) listRunArtifacts(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	if _, err := h.runService.GetRun(r.Context(), runID); err != nil {
+		if errors.Is(err, runs.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Run not found")
+			return
+		}
+		log.Error().Err(err).Str("run_id", runID).Msg("Failed to get run")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list run artifacts")
+		return
+	}
+
+	artifacts, err := h.runService.ListArtifacts(r.Context(), runID)
+	if err != nil {
+		log.Error().Err(err).Str("run_id", runID).Msg("Failed to list run artifacts")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list run artifacts")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, RunArtifactsResponse{Artifacts: artifacts})
+}
+
+// @Summary Get run artifact
+// @Description Download a raw payload exactly as the plugin emitted it. The payload is sent gzip-encoded to clients that accept it.
+// @Tags runs
+// @Produce json
+// @Param id path string true "Run ID"
+// @Param sequence path int true "Artifact sequence number"
+// @Success 200 {object} runs.RunPayload
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Router /runs/{id}/artifacts/{sequence} [get]
+func (h *Handler) getRunArtifact(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	sequence, err := strconv.Atoi(r.PathValue("sequence"))
+	if err != nil || sequence < 1 {
+		common.RespondError(w, http.StatusBadRequest, "Invalid artifact sequence")
+		return
+	}
+
+	_, data, err := h.runService.GetArtifact(r.Context(), runID, sequence)
+	if err != nil {
+		if errors.Is(err, runs.ErrArtifactNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Run artifact not found")
+			return
+		}
+		log.Error().Err(err).Str("run_id", runID).Int("sequence", sequence).Msg("Failed to get run artifact")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get run artifact")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"run-"+runID+"-"+strconv.Itoa(sequence)+".json\"")
+	w.Header().Add("Vary", "Accept-Encoding")
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+		return
+	}
+
+	raw, err := runs.DecompressArtifact(data)
+	if err != nil {
+		log.Error().Err(err).Str("run_id", runID).Int("sequence", sequence).Msg("Failed to decompress run artifact")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get run artifact")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(raw)
+}
@@ -37,6 +37,7 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.WithIdempotency(),
 			},
 		},
 		{
@@ -55,6 +56,7 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.WithIdempotency(),
 			},
 		},
 		{
@@ -104,6 +106,26 @@ func (h *Handler) Routes() []common.Route {
 				common.WithRateLimit(h.config, 30, 60), // 30 requests per 60 seconds
 			},
 		},
+		{
+			Path:    "/api/v1/runs/{id}/artifacts",
+			Method:  http.MethodGet,
+			Handler: h.listRunArtifacts,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+		{
+			Path:    "/api/v1/runs/{id}/artifacts/{sequence}",
+			Method:  http.MethodGet,
+			Handler: h.getRunArtifact,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
 	}
 }
 
@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/core/quota"
 	"github.com/marmotdata/marmot/internal/core/runs"
-	"github.com/marmotdata/marmot/internal/core/user"
 	"github.com/marmotdata/marmot/internal/plugin"
 	"github.com/rs/zerolog/log"
 )
@@ -54,9 +57,10 @@ type CreateStatRequest struct {
 } // @name CreateStatRequest
 
 type CreateLineageRequest struct {
-	Source string `json:"source"`
-	Target string `json:"target"`
-	Type   string `json:"type"`
+	Source  string                  `json:"source"`
+	Target  string                  `json:"target"`
+	Type    string                  `json:"type"`
+	Columns []lineage.ColumnMapping `json:"columns,omitempty"`
 } // @name CreateLineageRequest
 
 type CreateDocRequest struct {
@@ -81,15 +85,16 @@ type DocumentationResult struct {
 } // @name DocumentationResult
 
 type CreateAssetRequest struct {
-	Name          string                 `json:"name"`
-	Type          string                 `json:"type"`
-	Providers     []string               `json:"providers"`
-	Description   *string                `json:"description"`
-	Metadata      map[string]interface{} `json:"metadata"`
-	Schema        map[string]interface{} `json:"schema"`
-	Tags          []string               `json:"tags"`
-	Sources       []string               `json:"sources"`
-	ExternalLinks []map[string]string    `json:"external_links"`
+	Name          string                       `json:"name"`
+	Type          string                       `json:"type"`
+	Providers     []string                     `json:"providers"`
+	Description   *string                      `json:"description"`
+	Metadata      map[string]interface{}       `json:"metadata"`
+	Schema        map[string]interface{}       `json:"schema"`
+	Tags          []string                     `json:"tags"`
+	Sources       []string                     `json:"sources"`
+	ExternalLinks []map[string]string          `json:"external_links"`
+	Environments  map[string]asset.Environment `json:"environments,omitempty"`
 } // @name RunCreateAssetRequest
 
 type BatchCreateResponse struct {
@@ -97,6 +102,7 @@ type BatchCreateResponse struct {
 	StaleEntitiesRemoved []string              `json:"stale_entities_removed,omitempty"`
 	Lineage              []LineageResult       `json:"lineage,omitempty"`
 	Documentation        []DocumentationResult `json:"documentation,omitempty"`
+	Summary              plugin.EntitySummary  `json:"summary"`
 } // @name BatchCreateResponse
 
 type BatchAssetResult struct {
@@ -135,20 +141,25 @@ func (h *Handler) startRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	usr, ok := r.Context().Value(common.UserContextKey).(*user.User)
+	principal, ok := common.PrincipalFromContext(r.Context())
 	if !ok {
-		common.RespondError(w, http.StatusUnauthorized, "User context required")
+		common.RespondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	createdBy := auth.CreatedBy(principal)
 
-	run, err := h.runService.StartRun(r.Context(), req.PipelineName, req.SourceName, usr.Username, req.Config)
+	run, err := h.runService.StartRun(r.Context(), req.PipelineName, req.SourceName, createdBy, req.Config)
 	if err != nil {
+		if errors.Is(err, quota.ErrExceeded) {
+			common.RespondError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
 		common.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to start run: %v", err))
 		return
 	}
 
 	if h.scheduleSvc != nil {
-		if _, err := h.scheduleSvc.CreateCLIJobRun(r.Context(), req.PipelineName, req.SourceName, run.ID, usr.Username); err != nil {
+		if _, err := h.scheduleSvc.CreateCLIJobRun(r.Context(), req.PipelineName, req.SourceName, run.ID, createdBy); err != nil {
 			log.Warn().Err(err).Msg("Failed to create job run for CLI ingestion")
 		}
 	}
@@ -195,6 +206,11 @@ func (h *Handler) completeRun(w http.ResponseWriter, r *http.Request) {
 					lc = req.Summary.LineageCreated
 					da = req.Summary.DocumentationAdded
 				}
+				if req.Summary != nil && len(req.Summary.Entities) > 0 {
+					if err := h.scheduleSvc.UpdateJobRunEntityCounts(r.Context(), jobRun.ID, req.Summary.Entities); err != nil {
+						log.Warn().Err(err).Msg("Failed to record entity counts for CLI ingestion")
+					}
+				}
 				if err := h.scheduleSvc.CompleteJobRun(r.Context(), jobRun.ID, success, errMsg, ac, au, ad, lc, da); err != nil {
 					log.Warn().Err(err).Msg("Failed to complete job run for CLI ingestion")
 				}
@@ -231,14 +247,16 @@ func (h *Handler) batchCreateAssets(w http.ResponseWriter, r *http.Request) {
 			Tags:          asset.Tags,
 			Sources:       asset.Sources,
 			ExternalLinks: asset.ExternalLinks,
+			Environments:  asset.Environments,
 		}
 	}
 	lineageRequests := make([]runs.LineageInput, len(req.Lineage))
-	for i, lineage := range req.Lineage {
+	for i, lin := range req.Lineage {
 		lineageRequests[i] = runs.LineageInput{
-			Source: lineage.Source,
-			Target: lineage.Target,
-			Type:   lineage.Type,
+			Source:  lin.Source,
+			Target:  lin.Target,
+			Type:    lin.Type,
+			Columns: lin.Columns,
 		}
 	}
 	docRequests := make([]runs.DocumentationInput, len(req.Documentation))
@@ -0,0 +1,251 @@
+package savedqueries
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/savedquery"
+	"github.com/rs/zerolog/log"
+)
+
+// actor resolves the calling user. Users who can manage assets may edit any
+// saved query.
+func (h *Handler) actor(r *http.Request) (savedquery.Actor, bool) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		return savedquery.Actor{}, false
+	}
+	canModerate, err := h.userService.HasPermission(r.Context(), usr.ID, "assets", "manage")
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", usr.ID).Msg("Failed to check asset manage permission")
+	}
+	return savedquery.Actor{UserID: usr.ID, CanModerate: canModerate}, true
+}
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case savedquery.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, savedquery.ErrForbidden):
+		common.RespondError(w, http.StatusForbidden, "Only the author, owners or moderators can change this query")
+	case errors.Is(err, savedquery.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Query not found")
+	case errors.Is(err, savedquery.ErrAssetNotFound):
+		common.RespondError(w, http.StatusBadRequest, "Asset not found")
+	case errors.Is(err, savedquery.ErrOwnerNotFound):
+		common.RespondError(w, http.StatusBadRequest, "Owner not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List saved queries
+// @Tags queries
+// @Produce json
+// @Param language query string false "Only queries in this language, e.g. sql"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} savedquery.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries [get]
+func (h *Handler) listQueries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	result, err := h.svc.List(r.Context(), savedquery.ListFilter{
+		Language: query.Get("language"),
+		Limit:    common.ParseLimit(query.Get("limit"), 20, 100),
+		Offset:   common.ParseOffset(query.Get("offset")),
+	})
+	if err != nil {
+		respondServiceError(w, err, "Failed to list queries")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary List the saved queries linked to an asset
+// @Tags queries
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} savedquery.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/assets/{assetId} [get]
+func (h *Handler) listAssetQueries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	result, err := h.svc.List(r.Context(), savedquery.ListFilter{
+		AssetID: r.PathValue("assetId"),
+		Limit:   common.ParseLimit(query.Get("limit"), 20, 100),
+		Offset:  common.ParseOffset(query.Get("offset")),
+	})
+	if err != nil {
+		respondServiceError(w, err, "Failed to list queries")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Save a query
+// @Tags queries
+// @Accept json
+// @Produce json
+// @Param query body savedquery.Input true "Query"
+// @Success 201 {object} savedquery.Query
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries [post]
+func (h *Handler) createQuery(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input savedquery.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	q, err := h.svc.Create(r.Context(), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to save query")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, q)
+}
+
+// @Summary Get a saved query
+// @Tags queries
+// @Produce json
+// @Param id path string true "Query ID"
+// @Success 200 {object} savedquery.Query
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/{id} [get]
+func (h *Handler) getQuery(w http.ResponseWriter, r *http.Request) {
+	q, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get query")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
+
+// @Summary Update a saved query
+// @Description Replace every field of a saved query, including its owner and linked assets
+// @Tags queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param query body savedquery.Input true "Query"
+// @Success 200 {object} savedquery.Query
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/{id} [put]
+func (h *Handler) updateQuery(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input savedquery.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	q, err := h.svc.Update(r.Context(), r.PathValue("id"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update query")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
+
+// @Summary Delete a saved query
+// @Tags queries
+// @Param id path string true "Query ID"
+// @Success 204
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/{id} [delete]
+func (h *Handler) deleteQuery(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), r.PathValue("id"), actor); err != nil {
+		respondServiceError(w, err, "Failed to delete query")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Link a saved query to an asset
+// @Tags queries
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param assetId path string true "Asset ID"
+// @Success 200 {object} savedquery.Query
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/{id}/assets/{assetId} [put]
+func (h *Handler) linkAsset(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	q, err := h.svc.LinkAsset(r.Context(), r.PathValue("id"), r.PathValue("assetId"), actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to link query")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
+
+// @Summary Unlink a saved query from an asset
+// @Tags queries
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param assetId path string true "Asset ID"
+// @Success 200 {object} savedquery.Query
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /queries/{id}/assets/{assetId} [delete]
+func (h *Handler) unlinkAsset(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	q, err := h.svc.UnlinkAsset(r.Context(), r.PathValue("id"), r.PathValue("assetId"), actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to unlink query")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, q)
+}
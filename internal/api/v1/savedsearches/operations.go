@@ -0,0 +1,264 @@
+package savedsearches
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/savedsearch"
+	"github.com/rs/zerolog/log"
+)
+
+// actor resolves the calling user. Users who can manage assets may see and
+// edit any saved search.
+func (h *Handler) actor(r *http.Request) (savedsearch.Actor, bool) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		return savedsearch.Actor{}, false
+	}
+	canModerate, err := h.userService.HasPermission(r.Context(), usr.ID, "assets", "manage")
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", usr.ID).Msg("Failed to check asset manage permission")
+	}
+	return savedsearch.Actor{UserID: usr.ID, CanModerate: canModerate}, true
+}
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case savedsearch.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, savedsearch.ErrForbidden):
+		common.RespondError(w, http.StatusForbidden, "Only the author or moderators can change this search")
+	case errors.Is(err, savedsearch.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Saved search not found")
+	case errors.Is(err, savedsearch.ErrTeamNotFound):
+		common.RespondError(w, http.StatusBadRequest, "Team not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List saved searches
+// @Description List the saved searches you created or that are shared with your teams
+// @Tags search
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} savedsearch.ListResult
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved [get]
+func (h *Handler) listSavedSearches(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	result, err := h.svc.List(r.Context(), actor,
+		common.ParseLimit(query.Get("limit"), 20, 100),
+		common.ParseOffset(query.Get("offset")))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list saved searches")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Save a search
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param search body savedsearch.Input true "Saved search"
+// @Success 201 {object} savedsearch.SavedSearch
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved [post]
+func (h *Handler) createSavedSearch(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input savedsearch.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ss, err := h.svc.Create(r.Context(), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to save search")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, ss)
+}
+
+// @Summary Get a saved search
+// @Tags search
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} savedsearch.SavedSearch
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id} [get]
+func (h *Handler) getSavedSearch(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	ss, err := h.svc.Get(r.Context(), r.PathValue("id"), actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to get saved search")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ss)
+}
+
+// @Summary Update a saved search
+// @Description Replace every field of a saved search, including the teams it is shared with. Changing the query or filters restarts new-match tracking for subscribers.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Param search body savedsearch.Input true "Saved search"
+// @Success 200 {object} savedsearch.SavedSearch
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id} [put]
+func (h *Handler) updateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input savedsearch.Input
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ss, err := h.svc.Update(r.Context(), r.PathValue("id"), input, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update saved search")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ss)
+}
+
+// @Summary Delete a saved search
+// @Tags search
+// @Param id path string true "Saved search ID"
+// @Success 204
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id} [delete]
+func (h *Handler) deleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), r.PathValue("id"), actor); err != nil {
+		respondServiceError(w, err, "Failed to delete saved search")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Run a saved search
+// @Tags search
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} search.Response
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id}/results [get]
+func (h *Handler) runSavedSearch(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	resp, err := h.svc.Run(r.Context(), r.PathValue("id"), actor,
+		common.ParseLimit(query.Get("limit"), 20, 100),
+		common.ParseOffset(query.Get("offset")))
+	if err != nil {
+		respondServiceError(w, err, "Failed to run saved search")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Subscribe to a saved search
+// @Description Get a notification when assets start matching the saved search
+// @Tags search
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} savedsearch.SavedSearch
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id}/subscription [put]
+func (h *Handler) subscribe(w http.ResponseWriter, r *http.Request) {
+	h.setSubscription(w, r, true)
+}
+
+// @Summary Unsubscribe from a saved search
+// @Tags search
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} savedsearch.SavedSearch
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/saved/{id}/subscription [delete]
+func (h *Handler) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	h.setSubscription(w, r, false)
+}
+
+func (h *Handler) setSubscription(w http.ResponseWriter, r *http.Request, subscribed bool) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := r.PathValue("id")
+	var err error
+	if subscribed {
+		err = h.svc.Subscribe(r.Context(), id, actor)
+	} else {
+		err = h.svc.Unsubscribe(r.Context(), id, actor)
+	}
+	if err != nil {
+		respondServiceError(w, err, "Failed to update subscription")
+		return
+	}
+
+	ss, err := h.svc.Get(r.Context(), id, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to get saved search")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ss)
+}
@@ -3,6 +3,7 @@ package schedules
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -31,6 +32,7 @@ type Handler struct {
 	config               *config.Config
 	encryptionConfigured bool
 	runCRDTrigger        RunCRDTrigger
+	connections          runs.ConnectionResolver
 }
 
 func NewHandler(service *runs.ScheduleService, runService runs.Service, userSvc user.Service, authSvc auth.Service, encryptor *crypto.Encryptor, config *config.Config, encryptionConfigured bool) *Handler {
@@ -50,6 +52,151 @@ func (h *Handler) SetRunCRDTrigger(trigger RunCRDTrigger) {
 	h.runCRDTrigger = trigger
 }
 
+// SetConnectionResolver enables checking that a schedule's connection
+// exists and matches its plugin.
+func (h *Handler) SetConnectionResolver(resolver runs.ConnectionResolver) {
+	h.connections = resolver
+}
+
+// checkConnection responds with 400 and returns false when connectionID does
+// not name a connection for pluginID.
+func (h *Handler) checkConnection(w http.ResponseWriter, r *http.Request, pluginID string, connectionID *string) bool {
+	if connectionID == nil || h.connections == nil {
+		return true
+	}
+	provider, _, err := h.connections.ResolveConnection(r.Context(), *connectionID)
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Connection not found")
+		return false
+	}
+	if provider != pluginID {
+		common.RespondError(w, http.StatusBadRequest, "Connection is for the "+provider+" plugin")
+		return false
+	}
+	return true
+}
+
+// setConnection applies the request's connection to a saved schedule.
+func (h *Handler) setConnection(w http.ResponseWriter, r *http.Request, schedule *runs.Schedule, connectionID *string) (*runs.Schedule, bool) {
+	if connectionID == nil && schedule.ConnectionID == nil {
+		return schedule, true
+	}
+	updated, err := h.service.SetScheduleConnection(r.Context(), schedule.ID, connectionID)
+	if err != nil {
+		if err == runs.ErrConnectionNotFound {
+			common.RespondError(w, http.StatusBadRequest, "Connection not found")
+			return nil, false
+		}
+		log.Error().Err(err).Msg("Failed to set schedule connection")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to set schedule connection")
+		return nil, false
+	}
+	return updated, true
+}
+
+// setServiceAccount applies the request's service account to a saved
+// schedule.
+func (h *Handler) setServiceAccount(w http.ResponseWriter, r *http.Request, schedule *runs.Schedule, serviceAccountID *string) (*runs.Schedule, bool) {
+	if serviceAccountID == nil && schedule.ServiceAccountID == nil {
+		return schedule, true
+	}
+	updated, err := h.service.SetScheduleServiceAccount(r.Context(), schedule.ID, serviceAccountID)
+	if err != nil {
+		if errors.Is(err, runs.ErrServiceAccountUnavailable) {
+			common.RespondError(w, http.StatusBadRequest, "Service account not found or inactive")
+			return nil, false
+		}
+		log.Error().Err(err).Msg("Failed to set schedule service account")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to set schedule service account")
+		return nil, false
+	}
+	return updated, true
+}
+
+// scheduleLimits returns the concurrency limit and timeout a schedule ends
+// up with: the request's values, or the schedule's current ones where the
+// request leaves them out. existing is nil for a new schedule.
+func scheduleLimits(existing *runs.Schedule, maxConcurrentRuns, timeoutSeconds *int) (int, *int) {
+	var limit int
+	var timeout *int
+	if existing != nil {
+		limit, timeout = existing.ConcurrencyLimit, existing.TimeoutSeconds
+	}
+	if maxConcurrentRuns != nil {
+		limit = *maxConcurrentRuns
+	}
+	if timeoutSeconds != nil {
+		timeout = timeoutSeconds
+	}
+	return limit, timeout
+}
+
+// canBindServiceAccount reports whether the caller may make schedules run as
+// a service account, which takes the permission to manage service accounts.
+func (h *Handler) canBindServiceAccount(r *http.Request) (bool, error) {
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		return h.userSvc.HasPermission(r.Context(), usr.ID, "service_accounts", "manage")
+	}
+	p, ok := common.PrincipalFromContext(r.Context())
+	return ok && p.HasPermission("service_accounts", "manage"), nil
+}
+
+// checkSettings responds with an error and returns false when the request's
+// service account or limits can't be applied, so nothing is saved for a
+// request that would fail part way. existing is nil for a new schedule.
+func (h *Handler) checkSettings(w http.ResponseWriter, r *http.Request, existing *runs.Schedule, serviceAccountID *string, maxConcurrentRuns, timeoutSeconds *int) bool {
+	if serviceAccountID != nil && (existing == nil || existing.ServiceAccountID == nil || *existing.ServiceAccountID != *serviceAccountID) {
+		allowed, err := h.canBindServiceAccount(r)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to check service account permission")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to check permissions")
+			return false
+		}
+		if !allowed {
+			common.RespondError(w, http.StatusForbidden, "Running schedules as a service account requires permission to manage service accounts")
+			return false
+		}
+		if err := h.service.CheckServiceAccount(r.Context(), *serviceAccountID); err != nil {
+			if errors.Is(err, runs.ErrServiceAccountUnavailable) {
+				common.RespondError(w, http.StatusBadRequest, "Service account not found or inactive")
+				return false
+			}
+			log.Error().Err(err).Msg("Failed to check schedule service account")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to check schedule service account")
+			return false
+		}
+	}
+
+	if maxConcurrentRuns != nil || timeoutSeconds != nil {
+		if err := runs.ValidateScheduleLimits(scheduleLimits(existing, maxConcurrentRuns, timeoutSeconds)); err != nil {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return false
+		}
+	}
+	return true
+}
+
+// setLimits applies the request's concurrency limit and timeout to a saved
+// schedule.
+func (h *Handler) setLimits(w http.ResponseWriter, r *http.Request, schedule *runs.Schedule, maxConcurrentRuns, timeoutSeconds *int) (*runs.Schedule, bool) {
+	if maxConcurrentRuns == nil && timeoutSeconds == nil {
+		return schedule, true
+	}
+	limit, timeout := scheduleLimits(schedule, maxConcurrentRuns, timeoutSeconds)
+
+	updated, err := h.service.SetScheduleLimits(r.Context(), schedule.ID, limit, timeout)
+	if err != nil {
+		if errors.Is(err, runs.ErrInvalidScheduleLimits) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		log.Error().Err(err).Msg("Failed to set schedule limits")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to set schedule limits")
+		return nil, false
+	}
+	return updated, true
+}
+
 func (h *Handler) Routes() []common.Route {
 	return []common.Route{
 		{
@@ -71,6 +218,15 @@ func (h *Handler) Routes() []common.Route {
 				common.RequireEncryption(h.encryptionConfigured),
 			},
 		},
+		{
+			Path:    "/api/v1/ingestion/schedules/preview",
+			Method:  http.MethodPost,
+			Handler: h.previewSchedule,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userSvc, h.authSvc, h.config),
+				common.RequirePermission(h.userSvc, "ingestion", "view"),
+			},
+		},
 		{
 			Path:    "/api/v1/ingestion/schedules",
 			Method:  http.MethodGet,
@@ -233,20 +389,32 @@ func (h *Handler) validateConfig(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-type CreateScheduleRequest struct{
-	Name           string                 `json:"name"`
-	PluginID       string                 `json:"plugin_id"`
-	Config         map[string]interface{} `json:"config"`
-	CronExpression string                 `json:"cron_expression"`
-	Enabled        bool                   `json:"enabled"`
+type CreateScheduleRequest struct {
+	Name             string                 `json:"name"`
+	PluginID         string                 `json:"plugin_id"`
+	Config           map[string]interface{} `json:"config"`
+	ConnectionID     *string                `json:"connection_id,omitempty"`
+	ServiceAccountID *string                `json:"service_account_id,omitempty"`
+	CronExpression   string                 `json:"cron_expression"`
+	Enabled          bool                   `json:"enabled"`
+	// MaxConcurrentRuns and TimeoutSeconds are left unchanged when omitted.
+	// 0 removes the limit.
+	MaxConcurrentRuns *int `json:"max_concurrent_runs,omitempty"`
+	TimeoutSeconds    *int `json:"timeout_seconds,omitempty"`
 } // @name CreateScheduleRequest
 
 type UpdateScheduleRequest struct {
-	Name           string                 `json:"name"`
-	PluginID       string                 `json:"plugin_id"`
-	Config         map[string]interface{} `json:"config"`
-	CronExpression string                 `json:"cron_expression"`
-	Enabled        bool                   `json:"enabled"`
+	Name             string                 `json:"name"`
+	PluginID         string                 `json:"plugin_id"`
+	Config           map[string]interface{} `json:"config"`
+	ConnectionID     *string                `json:"connection_id,omitempty"`
+	ServiceAccountID *string                `json:"service_account_id,omitempty"`
+	CronExpression   string                 `json:"cron_expression"`
+	Enabled          bool                   `json:"enabled"`
+	// MaxConcurrentRuns and TimeoutSeconds are left unchanged when omitted.
+	// 0 removes the limit.
+	MaxConcurrentRuns *int `json:"max_concurrent_runs,omitempty"`
+	TimeoutSeconds    *int `json:"timeout_seconds,omitempty"`
 } // @name UpdateScheduleRequest
 
 type ListSchedulesResponse struct {
@@ -271,6 +439,7 @@ type ListJobRunsResponse struct {
 // @Success 201 {object} runs.Schedule
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
 // @Failure 500 {object} common.ErrorResponse
 // @Router /ingestion/schedules [post]
 func (h *Handler) createSchedule(w http.ResponseWriter, r *http.Request) {
@@ -290,6 +459,13 @@ func (h *Handler) createSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.checkConnection(w, r, req.PluginID, req.ConnectionID) {
+		return
+	}
+	if !h.checkSettings(w, r, nil, req.ServiceAccountID, req.MaxConcurrentRuns, req.TimeoutSeconds) {
+		return
+	}
+
 	user, _ := common.GetAuthenticatedUser(r.Context())
 	var createdBy *string
 	if user != nil {
@@ -331,6 +507,21 @@ func (h *Handler) createSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	schedule, ok := h.setConnection(w, r, schedule, req.ConnectionID)
+	if !ok {
+		return
+	}
+
+	schedule, ok = h.setServiceAccount(w, r, schedule, req.ServiceAccountID)
+	if !ok {
+		return
+	}
+
+	schedule, ok = h.setLimits(w, r, schedule, req.MaxConcurrentRuns, req.TimeoutSeconds)
+	if !ok {
+		return
+	}
+
 	if h.encryptor != nil {
 		if err := runs.DecryptScheduleConfig(schedule, h.encryptor); err != nil {
 			log.Error().Err(err).Msg("Failed to decrypt config")
@@ -437,6 +628,7 @@ func (h *Handler) getSchedule(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} runs.Schedule
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
 // @Failure 404 {object} common.ErrorResponse
 // @Failure 500 {object} common.ErrorResponse
 // @Router /ingestion/schedules/{id} [put]
@@ -463,6 +655,24 @@ func (h *Handler) updateSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.checkConnection(w, r, req.PluginID, req.ConnectionID) {
+		return
+	}
+
+	existing, err := h.service.GetSchedule(r.Context(), id)
+	if err != nil {
+		if err == runs.ErrScheduleNotFound {
+			common.RespondError(w, http.StatusNotFound, "Schedule not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get schedule")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get schedule")
+		return
+	}
+	if !h.checkSettings(w, r, existing, req.ServiceAccountID, req.MaxConcurrentRuns, req.TimeoutSeconds) {
+		return
+	}
+
 	if h.encryptor != nil {
 		if err := runs.EncryptScheduleConfig(&runs.Schedule{
 			PluginID: req.PluginID,
@@ -502,6 +712,21 @@ func (h *Handler) updateSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	schedule, ok := h.setConnection(w, r, schedule, req.ConnectionID)
+	if !ok {
+		return
+	}
+
+	schedule, ok = h.setServiceAccount(w, r, schedule, req.ServiceAccountID)
+	if !ok {
+		return
+	}
+
+	schedule, ok = h.setLimits(w, r, schedule, req.MaxConcurrentRuns, req.TimeoutSeconds)
+	if !ok {
+		return
+	}
+
 	if h.encryptor != nil {
 		if err := runs.DecryptScheduleConfig(schedule, h.encryptor); err != nil {
 			log.Error().Err(err).Msg("Failed to decrypt config")
@@ -577,11 +802,13 @@ func (h *Handler) deleteSchedule(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Manually trigger an ingestion schedule
+// @Description Runs of a schedule with a service account are attributed to the account rather than the caller.
 // @Tags ingestion
 // @Param id path string true "Schedule ID"
 // @Success 201 {object} runs.JobRun
 // @Failure 401 {object} common.ErrorResponse
 // @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
 // @Failure 500 {object} common.ErrorResponse
 // @Router /ingestion/schedules/{id}/trigger [post]
 func (h *Handler) triggerSchedule(w http.ResponseWriter, r *http.Request) {
@@ -595,7 +822,7 @@ func (h *Handler) triggerSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	usr, ok := common.GetAuthenticatedUser(r.Context())
+	principal, ok := common.PrincipalFromContext(r.Context())
 	if !ok {
 		common.RespondError(w, http.StatusUnauthorized, "Authentication required")
 		return
@@ -627,7 +854,18 @@ func (h *Handler) triggerSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.service.CreateJobRun(r.Context(), &id, usr.Username)
+	createdBy, err := h.service.RunAs(r.Context(), schedule, auth.CreatedBy(principal))
+	if err != nil {
+		if errors.Is(err, runs.ErrServiceAccountUnavailable) {
+			common.RespondError(w, http.StatusConflict, "Schedule's service account is deleted or inactive")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to resolve schedule service account")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to create job run")
+		return
+	}
+
+	run, err := h.service.CreateJobRun(r.Context(), &id, createdBy)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create job run")
 		common.RespondError(w, http.StatusInternalServerError, "Failed to create job run")
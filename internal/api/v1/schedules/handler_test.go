@@ -0,0 +1,197 @@
+package schedules
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	activeAccount   = "7a1c2b3d-0000-4000-8000-000000000001"
+	inactiveAccount = "7a1c2b3d-0000-4000-8000-000000000002"
+)
+
+// scheduleRepo keeps schedules in memory and counts writes.
+type scheduleRepo struct {
+	runs.ScheduleRepository
+	schedules map[string]*runs.Schedule
+	writes    int
+}
+
+func (r *scheduleRepo) CreateSchedule(_ context.Context, s *runs.Schedule) error {
+	s.ID = "created"
+	r.schedules[s.ID] = s
+	r.writes++
+	return nil
+}
+
+func (r *scheduleRepo) GetSchedule(_ context.Context, id string) (*runs.Schedule, error) {
+	s, ok := r.schedules[id]
+	if !ok {
+		return nil, runs.ErrScheduleNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *scheduleRepo) UpdateSchedule(_ context.Context, s *runs.Schedule) error {
+	r.schedules[s.ID] = s
+	r.writes++
+	return nil
+}
+
+func (r *scheduleRepo) SetScheduleServiceAccount(_ context.Context, id string, serviceAccountID *string) error {
+	r.schedules[id].ServiceAccountID = serviceAccountID
+	r.writes++
+	return nil
+}
+
+func (r *scheduleRepo) SetScheduleLimits(_ context.Context, id string, maxConcurrentRuns int, timeoutSeconds *int) error {
+	r.schedules[id].ConcurrencyLimit = maxConcurrentRuns
+	r.schedules[id].TimeoutSeconds = timeoutSeconds
+	r.writes++
+	return nil
+}
+
+type accountResolver struct{}
+
+func (accountResolver) ResolveServiceAccount(_ context.Context, id string) (string, error) {
+	if id != activeAccount {
+		return "", runs.ErrServiceAccountUnavailable
+	}
+	return "service_account:loader", nil
+}
+
+// permissionUsers grants the listed "resource:action" permissions.
+type permissionUsers struct {
+	user.Service
+	granted map[string]bool
+}
+
+func (s *permissionUsers) HasPermission(_ context.Context, _ string, resourceType, action string) (bool, error) {
+	return s.granted[resourceType+":"+action], nil
+}
+
+func newScheduleTest(canBind bool) (*Handler, *scheduleRepo) {
+	bound := activeAccount
+	repo := &scheduleRepo{schedules: map[string]*runs.Schedule{
+		"bound": {ID: "bound", Name: "warehouse", PluginID: "postgresql", ServiceAccountID: &bound},
+	}}
+	svc := runs.NewScheduleService(repo)
+	svc.SetServiceAccountResolver(accountResolver{})
+
+	granted := map[string]bool{"ingestion:manage": true}
+	if canBind {
+		granted["service_accounts:manage"] = true
+	}
+	return &Handler{service: svc, userSvc: &permissionUsers{granted: granted}}, repo
+}
+
+func scheduleRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), common.UserContextKey, &user.User{ID: "user-1"}))
+}
+
+func TestCreateScheduleValidatesBeforeSaving(t *testing.T) {
+	tests := []struct {
+		name       string
+		canBind    bool
+		body       string
+		wantStatus int
+		wantWrites int
+	}{
+		{
+			name:       "service account",
+			canBind:    true,
+			body:       `{"name":"sales","plugin_id":"postgresql","service_account_id":"` + activeAccount + `","max_concurrent_runs":2}`,
+			wantStatus: http.StatusCreated,
+			wantWrites: 3,
+		},
+		{
+			name:       "service account without permission",
+			body:       `{"name":"sales","plugin_id":"postgresql","service_account_id":"` + activeAccount + `"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "inactive service account",
+			canBind:    true,
+			body:       `{"name":"sales","plugin_id":"postgresql","service_account_id":"` + inactiveAccount + `"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed service account",
+			canBind:    true,
+			body:       `{"name":"sales","plugin_id":"postgresql","service_account_id":"loader"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "limit out of range",
+			body:       `{"name":"sales","plugin_id":"postgresql","max_concurrent_runs":1000}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "negative timeout",
+			body:       `{"name":"sales","plugin_id":"postgresql","timeout_seconds":-1}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo := newScheduleTest(tt.canBind)
+
+			w := httptest.NewRecorder()
+			h.createSchedule(w, scheduleRequest(http.MethodPost, "/api/v1/ingestion/schedules", tt.body))
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.wantWrites, repo.writes)
+		})
+	}
+}
+
+func TestUpdateScheduleValidatesBeforeSaving(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "keeps its service account without permission",
+			body:       `{"name":"warehouse","plugin_id":"postgresql","service_account_id":"` + activeAccount + `"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "binds another service account without permission",
+			body:       `{"name":"warehouse","plugin_id":"postgresql","service_account_id":"` + inactiveAccount + `"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "timeout out of range",
+			body:       `{"name":"warehouse","plugin_id":"postgresql","service_account_id":"` + activeAccount + `","timeout_seconds":999999999}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo := newScheduleTest(false)
+
+			req := scheduleRequest(http.MethodPut, "/api/v1/ingestion/schedules/bound", tt.body)
+			req.SetPathValue("id", "bound")
+			w := httptest.NewRecorder()
+			h.updateSchedule(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				assert.Zero(t, repo.writes, "nothing is saved when the request is rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package schedules
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/runs"
+)
+
+type PreviewScheduleRequest struct {
+	CronExpression  string                `json:"cron_expression"`
+	Timezone        string                `json:"timezone,omitempty" example:"Europe/London"`
+	Count           int                   `json:"count,omitempty"`
+	BlackoutWindows []runs.BlackoutWindow `json:"blackout_windows,omitempty"`
+} // @name PreviewScheduleRequest
+
+// @Summary Preview ingestion schedule run times
+// @Description Returns the next run times of a cron expression, flagging those in a blackout window. Save the returned cron_expression to get these run times.
+// @Tags ingestion
+// @Accept json
+// @Produce json
+// @Param preview body PreviewScheduleRequest true "Schedule to preview"
+// @Success 200 {object} runs.SchedulePreview
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Router /ingestion/schedules/preview [post]
+func (h *Handler) previewSchedule(w http.ResponseWriter, r *http.Request) {
+	var req PreviewScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	preview, err := runs.PreviewSchedule(runs.PreviewInput{
+		CronExpression:  req.CronExpression,
+		Timezone:        req.Timezone,
+		Count:           req.Count,
+		BlackoutWindows: req.BlackoutWindows,
+	}, time.Now())
+	if err != nil {
+		if errors.Is(err, runs.ErrInvalidInput) || errors.Is(err, runs.ErrInvalidCronExpression) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		common.RespondError(w, http.StatusInternalServerError, "Failed to preview schedule")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, preview)
+}
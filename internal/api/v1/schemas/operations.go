@@ -0,0 +1,100 @@
+package schemas
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/schemablob"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case schemablob.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, schemablob.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Schema not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary Get schema deduplication stats
+// @Tags schemas
+// @Produce json
+// @Success 200 {object} schemablob.Stats
+// @Failure 500 {object} common.ErrorResponse
+// @Router /schemas/stats [get]
+func (h *Handler) getStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.svc.Stats(r.Context())
+	if err != nil {
+		respondServiceError(w, err, "Failed to get schema stats")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, stats)
+}
+
+// @Summary Get a schema by content hash
+// @Tags schemas
+// @Produce json
+// @Param hash path string true "Schema hash, with or without the sha256: prefix"
+// @Success 200 {object} schemablob.Blob
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /schemas/{hash} [get]
+func (h *Handler) getSchema(w http.ResponseWriter, r *http.Request) {
+	blob, err := h.svc.Get(r.Context(), r.PathValue("hash"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get schema")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, blob)
+}
+
+// @Summary List the assets that share a schema
+// @Tags schemas
+// @Produce json
+// @Param hash path string true "Schema hash, with or without the sha256: prefix"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} schemablob.AssetsResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /schemas/{hash}/assets [get]
+func (h *Handler) listSchemaAssets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 20, 100)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.ListAssets(r.Context(), r.PathValue("hash"), limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list schema assets")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary List an asset's schemas with their hashes
+// @Description Each schema includes how many other assets have exactly the same content.
+// @Tags schemas
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {array} schemablob.AssetSchema
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/schemas/{id} [get]
+func (h *Handler) listAssetSchemas(w http.ResponseWriter, r *http.Request) {
+	schemas, err := h.svc.ListForAsset(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list asset schemas")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, schemas)
+}
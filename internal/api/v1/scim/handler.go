@@ -0,0 +1,82 @@
+package scim
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/scim"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc    *scim.Service
+	config *config.Config
+}
+
+func NewHandler(svc *scim.Service, cfg *config.Config) *Handler {
+	return &Handler{
+		svc:    svc,
+		config: cfg,
+	}
+}
+
+// Routes serves SCIM under /scim/v2. Nothing is served unless SCIM is
+// enabled and a token is configured.
+func (h *Handler) Routes() []common.Route {
+	if !h.config.Auth.SCIM.Enabled || h.config.Auth.SCIM.Token == "" {
+		return nil
+	}
+
+	routes := []struct {
+		path    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"/scim/v2/ServiceProviderConfig", http.MethodGet, h.getServiceProviderConfig},
+		{"/scim/v2/ResourceTypes", http.MethodGet, h.listResourceTypes},
+
+		{"/scim/v2/Users", http.MethodGet, h.listUsers},
+		{"/scim/v2/Users", http.MethodPost, h.createUser},
+		{"/scim/v2/Users/{id}", http.MethodGet, h.getUser},
+		{"/scim/v2/Users/{id}", http.MethodPut, h.replaceUser},
+		{"/scim/v2/Users/{id}", http.MethodPatch, h.patchUser},
+		{"/scim/v2/Users/{id}", http.MethodDelete, h.deleteUser},
+
+		{"/scim/v2/Groups", http.MethodGet, h.listGroups},
+		{"/scim/v2/Groups", http.MethodPost, h.createGroup},
+		{"/scim/v2/Groups/{id}", http.MethodGet, h.getGroup},
+		{"/scim/v2/Groups/{id}", http.MethodPut, h.replaceGroup},
+		{"/scim/v2/Groups/{id}", http.MethodPatch, h.patchGroup},
+		{"/scim/v2/Groups/{id}", http.MethodDelete, h.deleteGroup},
+	}
+
+	out := make([]common.Route, len(routes))
+	for i, r := range routes {
+		out[i] = common.Route{
+			Path:    r.path,
+			Method:  r.method,
+			Handler: r.handler,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				h.withToken,
+				common.WithRateLimit(h.config, 300, 60),
+			},
+		}
+	}
+	return out
+}
+
+// withToken authenticates the identity provider by the configured bearer
+// token. SCIM requests don't act as a Marmot user.
+func (h *Handler) withToken(next http.HandlerFunc) http.HandlerFunc {
+	expected := []byte(h.config.Auth.SCIM.Token)
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), expected) != 1 {
+			respondError(w, &scim.Error{Status: http.StatusUnauthorized, Detail: "invalid or missing bearer token"})
+			return
+		}
+		next(w, r)
+	}
+}
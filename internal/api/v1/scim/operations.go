@@ -0,0 +1,216 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/scim"
+	"github.com/rs/zerolog/log"
+)
+
+const contentType = "application/scim+json"
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if data != nil {
+		_ = json.NewEncoder(w).Encode(data)
+	}
+}
+
+func respondError(w http.ResponseWriter, err error) {
+	var scimErr *scim.Error
+	if !errors.As(err, &scimErr) {
+		log.Error().Err(err).Msg("SCIM request failed")
+		scimErr = &scim.Error{Status: http.StatusInternalServerError, Detail: "internal server error"}
+	}
+	respondJSON(w, scimErr.Status, scimErr.Response())
+}
+
+func decode(r *http.Request, dst interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return &scim.Error{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: "invalid request body"}
+	}
+	return nil
+}
+
+// listParams reads the filter and pagination query parameters.
+func listParams(r *http.Request) (filter string, startIndex, count int) {
+	q := r.URL.Query()
+	startIndex, count = 1, scim.DefaultCount
+	if v, err := strconv.Atoi(q.Get("startIndex")); err == nil {
+		startIndex = v
+	}
+	if v, err := strconv.Atoi(q.Get("count")); err == nil {
+		count = v
+	}
+	return q.Get("filter"), startIndex, count
+}
+
+func (h *Handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, scim.ServiceProviderConfig())
+}
+
+func (h *Handler) listResourceTypes(w http.ResponseWriter, r *http.Request) {
+	types := scim.ResourceTypes(h.svc.BaseURL())
+	respondJSON(w, http.StatusOK, &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(types),
+		StartIndex:   1,
+		ItemsPerPage: len(types),
+		Resources:    types,
+	})
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	filter, startIndex, count := listParams(r)
+	resp, err := h.svc.ListUsers(r.Context(), filter, startIndex, count)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	u, err := h.svc.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, u)
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var in scim.User
+	if err := decode(r, &in); err != nil {
+		respondError(w, err)
+		return
+	}
+	u, err := h.svc.CreateUser(r.Context(), &in)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	log.Info().Str("user_id", u.ID).Str("username", u.UserName).Msg("User provisioned via SCIM")
+	respondJSON(w, http.StatusCreated, u)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	var in scim.User
+	if err := decode(r, &in); err != nil {
+		respondError(w, err)
+		return
+	}
+	u, err := h.svc.ReplaceUser(r.Context(), r.PathValue("id"), &in)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, u)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	var req scim.PatchRequest
+	if err := decode(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+	u, err := h.svc.PatchUser(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, u)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.svc.DeleteUser(r.Context(), id); err != nil {
+		respondError(w, err)
+		return
+	}
+	log.Info().Str("user_id", id).Msg("User deprovisioned via SCIM")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	filter, startIndex, count := listParams(r)
+	excludeMembers := false
+	for _, attr := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			excludeMembers = true
+		}
+	}
+	resp, err := h.svc.ListGroups(r.Context(), filter, startIndex, count, excludeMembers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	g, err := h.svc.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, g)
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	var in scim.Group
+	if err := decode(r, &in); err != nil {
+		respondError(w, err)
+		return
+	}
+	g, err := h.svc.CreateGroup(r.Context(), &in)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	log.Info().Str("team_id", g.ID).Str("group", g.DisplayName).Msg("Group provisioned via SCIM")
+	respondJSON(w, http.StatusCreated, g)
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	var in scim.Group
+	if err := decode(r, &in); err != nil {
+		respondError(w, err)
+		return
+	}
+	g, err := h.svc.ReplaceGroup(r.Context(), r.PathValue("id"), &in)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, g)
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	var req scim.PatchRequest
+	if err := decode(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+	g, err := h.svc.PatchGroup(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, g)
+}
+
+func (h *Handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.svc.DeleteGroup(r.Context(), id); err != nil {
+		respondError(w, err)
+		return
+	}
+	log.Info().Str("team_id", id).Msg("Group deprovisioned via SCIM")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,7 +1,9 @@
 package search
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
 	"github.com/marmotdata/marmot/pkg/config"
@@ -11,8 +13,23 @@ import (
 	"github.com/marmotdata/marmot/internal/metrics"
 )
 
+// LineageScopeResolver returns the IDs of the assets upstream, downstream or
+// both of an asset, given its ID or MRN. The asset itself is not included.
+type LineageScopeResolver interface {
+	ResolveLineageScope(ctx context.Context, assetRef, direction string, depth int) ([]string, error)
+}
+
+// GlossaryUsageRecorder counts glossary terms appearing in search results.
+type GlossaryUsageRecorder interface {
+	RecordSearch(termIDs ...string)
+}
+
 type Handler struct {
 	searchService  search.Service
+	facetCache     *search.FacetCache
+	defaults       search.Defaults
+	lineageScope   LineageScopeResolver
+	glossaryUsage  GlossaryUsageRecorder
 	userService    user.Service
 	authService    auth.Service
 	metricsService *metrics.Service
@@ -21,6 +38,8 @@ type Handler struct {
 
 func NewHandler(
 	searchService search.Service,
+	lineageScope LineageScopeResolver,
+	glossaryUsage GlossaryUsageRecorder,
 	userService user.Service,
 	authService auth.Service,
 	metricsService *metrics.Service,
@@ -28,6 +47,15 @@ func NewHandler(
 ) *Handler {
 	return &Handler{
 		searchService:  searchService,
+		facetCache:     search.NewFacetCache(searchService, time.Duration(config.Search.FacetCacheTTL)*time.Second),
+		defaults: search.NewDefaults(
+			config.Search.Defaults.IncludeStubs,
+			config.Search.Defaults.Types,
+			config.Search.Defaults.Environments,
+			config.Search.Defaults.Sort,
+		),
+		lineageScope:   lineageScope,
+		glossaryUsage:  glossaryUsage,
 		userService:    userService,
 		authService:    authService,
 		metricsService: metricsService,
@@ -44,6 +72,25 @@ func (h *Handler) Routes() []common.Route {
 			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
 				common.WithAuth(h.userService, h.authService, h.config),
 				common.WithRateLimit(h.config, 50, 60), // 50 requests per 60 seconds
+				common.WithQueryBudget(h.config),
+			},
+		},
+		{
+			Path:    "/api/v1/search/facets",
+			Method:  http.MethodGet,
+			Handler: h.facets,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.WithRateLimit(h.config, 50, 60),
+				common.WithQueryBudget(h.config),
+			},
+		},
+		{
+			Path:    "/api/v1/search/defaults",
+			Method:  http.MethodGet,
+			Handler: h.getDefaults,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
 			},
 		},
 	}
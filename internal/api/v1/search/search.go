@@ -1,11 +1,15 @@
 package search
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/favorite"
 	"github.com/marmotdata/marmot/internal/core/search"
 	"github.com/rs/zerolog/log"
 )
@@ -18,9 +22,23 @@ import (
 // @Param types query []string false "Filter by result types (asset, glossary, team, user)"
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
+// @Param aggregations_only query bool false "Return only facet counts (no hits); responds with SearchAggregationResponse"
+// @Param group_by query string false "Comma-separated metadata fields to group by (aggregations_only mode)"
+// @Param facet_limit query int false "Maximum buckets per facet (aggregations_only mode)" default(100)
+// @Param lineage_of query string false "Only return assets in the lineage of this asset ID or MRN"
+// @Param lineage_direction query string false "Lineage direction for lineage_of (upstream, downstream, both)" default(downstream)
+// @Param lineage_depth query int false "Lineage depth for lineage_of" default(5)
+// @Param personalize query bool false "Boost assets the user favorited, owns or recently viewed; overrides the personalized_search preference" default(true)
+// @Param facets query bool false "Compute facets; pass false to return hits sooner and fetch facets from /search/facets" default(true)
+// @Param include_stubs query bool false "Include stub assets; defaults to search.defaults.include_stubs"
+// @Param environments query string false "Comma-separated environments assets must be deployed in; defaults to search.defaults.environments, pass empty to search all"
+// @Param sort query string false "Result order (relevance, updated, name); defaults to search.defaults.sort"
 // @Success 200 {object} search.Response
+// @Header 200 {string} X-Marmot-Truncated "Comma-separated reasons the results are partial"
 // @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
 // @Failure 500 {object} common.ErrorResponse
+// @Failure 504 {object} common.ErrorResponse
 // @Router /search [get]
 func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
 	queryValues := r.URL.Query()
@@ -31,18 +49,6 @@ func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse type filters
-	var types []search.ResultType
-	if typeParams := queryValues["types[]"]; len(typeParams) > 0 {
-		for _, t := range typeParams {
-			types = append(types, search.ResultType(t))
-		}
-	} else if typeParam := queryValues.Get("types"); typeParam != "" {
-		for _, t := range strings.Split(typeParam, ",") {
-			types = append(types, search.ResultType(strings.TrimSpace(t)))
-		}
-	}
-
 	// Parse limit and offset
 	limit := 20
 	offset := 0
@@ -59,52 +65,322 @@ func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	types, assetTypes, providers, tags := parseFilters(queryValues)
+
+	scope, err := h.parseScope(queryValues, types)
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assetIDs, ok := h.resolveLineageScope(w, r)
+	if !ok {
+		return
+	}
+
+	if queryValues.Get("aggregations_only") == "true" {
+		h.aggregate(w, r, search.AggregationFilter{
+			Query:        query,
+			Types:        scope.types,
+			AssetTypes:   assetTypes,
+			Providers:    providers,
+			Tags:         tags,
+			AssetIDs:     assetIDs,
+			Environments: scope.environments,
+			IncludeStubs: scope.includeStubs,
+		})
+		return
+	}
+
+	filter := search.Filter{
+		Query:        query,
+		Types:        scope.types,
+		AssetTypes:   assetTypes,
+		Providers:    providers,
+		Tags:         tags,
+		Limit:        limit,
+		Offset:       offset,
+		AssetIDs:     assetIDs,
+		Environments: scope.environments,
+		IncludeStubs: scope.includeStubs,
+		Sort:         scope.sort,
+		SkipFacets:   queryValues.Get("facets") == "false",
+	}
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok && personalize(queryValues.Get("personalize"), usr.Preferences) {
+		filter.PersonalizeFor = usr.ID
+	}
+
+	response, err := h.searchService.Search(r.Context(), filter)
+	if err != nil {
+		if common.RespondQueryTimeout(w, err) {
+			return
+		}
+		log.Error().Err(err).Str("query", query).Msg("Failed to execute search")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to execute search")
+		return
+	}
+
+	if query != "" && response.Total > 0 {
+		recorder := h.metricsService.GetRecorder()
+		queryType := "full_text"
+		if len(filter.Types) > 0 || len(filter.AssetTypes) > 0 || len(filter.Providers) > 0 || len(filter.Tags) > 0 || filter.AssetIDs != nil {
+			queryType = "filtered"
+		}
+		recorder.RecordSearchQuery(r.Context(), queryType, query)
+
+		for _, result := range response.Results {
+			if result.Type == search.ResultTypeGlossary {
+				h.glossaryUsage.RecordSearch(result.ID)
+			}
+		}
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// @Summary Search facets
+// @Description Facet counts and the total for a search, without its hits. Takes the same filters as /search. Use it with facets=false on /search to show hits before the slower facet counts arrive. Facets are cached briefly per query.
+// @Tags search
+// @Produce json
+// @Param q query string false "Search query"
+// @Param types query []string false "Filter by result types (asset, glossary, team, user)"
+// @Param asset_types query string false "Comma-separated asset types"
+// @Param providers query string false "Comma-separated providers"
+// @Param tags query string false "Comma-separated tags"
+// @Param facet_limit query int false "Maximum buckets per facet" default(50)
+// @Param lineage_of query string false "Only count assets in the lineage of this asset ID or MRN"
+// @Param lineage_direction query string false "Lineage direction for lineage_of (upstream, downstream, both)" default(downstream)
+// @Param lineage_depth query int false "Lineage depth for lineage_of" default(5)
+// @Param include_stubs query bool false "Include stub assets; defaults to search.defaults.include_stubs"
+// @Param environments query string false "Comma-separated environments assets must be deployed in; defaults to search.defaults.environments, pass empty to search all"
+// @Success 200 {object} search.AggregationResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/facets [get]
+func (h *Handler) facets(w http.ResponseWriter, r *http.Request) {
+	queryValues := r.URL.Query()
+	query := strings.TrimSpace(queryValues.Get("q"))
+
+	if len(query) > 256 {
+		common.RespondError(w, http.StatusBadRequest, "Search query must be 256 characters or less")
+		return
+	}
+
+	types, assetTypes, providers, tags := parseFilters(queryValues)
+
+	scope, err := h.parseScope(queryValues, types)
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assetIDs, ok := h.resolveLineageScope(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.facetCache.Facets(r.Context(), search.AggregationFilter{
+		Query:        query,
+		Types:        scope.types,
+		AssetTypes:   assetTypes,
+		Providers:    providers,
+		Tags:         tags,
+		AssetIDs:     assetIDs,
+		Environments: scope.environments,
+		IncludeStubs: scope.includeStubs,
+		FacetLimit:   common.ParseLimit(queryValues.Get("facet_limit"), defaultFacetLimit, search.MaxAggregationLimit),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("query", query).Msg("Failed to compute search facets")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to compute search facets")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// defaultFacetLimit matches the facets search returns with its hits.
+const defaultFacetLimit = 50
+
+// parseFilters reads the result type and asset filters shared by search
+// and its facets.
+func parseFilters(queryValues url.Values) (types []search.ResultType, assetTypes, providers, tags []string) {
+	// Parse type filters
+	if typeParams := queryValues["types[]"]; len(typeParams) > 0 {
+		for _, t := range typeParams {
+			types = append(types, search.ResultType(t))
+		}
+	} else if typeParam := queryValues.Get("types"); typeParam != "" {
+		for _, t := range strings.Split(typeParam, ",") {
+			types = append(types, search.ResultType(strings.TrimSpace(t)))
+		}
+	}
+
 	// Parse asset-specific filters
-	var assetTypes []string
 	if assetTypesParam := queryValues.Get("asset_types"); assetTypesParam != "" {
 		for _, t := range strings.Split(assetTypesParam, ",") {
 			assetTypes = append(assetTypes, strings.TrimSpace(t))
 		}
 	}
 
-	var providers []string
 	if providersParam := queryValues.Get("providers"); providersParam != "" {
 		for _, p := range strings.Split(providersParam, ",") {
 			providers = append(providers, strings.TrimSpace(p))
 		}
 	}
 
-	var tags []string
 	if tagsParam := queryValues.Get("tags"); tagsParam != "" {
 		for _, tag := range strings.Split(tagsParam, ",") {
 			tags = append(tags, strings.TrimSpace(tag))
 		}
 	}
 
-	filter := search.Filter{
-		Query:      query,
-		Types:      types,
-		AssetTypes: assetTypes,
-		Providers:  providers,
-		Tags:       tags,
-		Limit:      limit,
-		Offset:     offset,
+	return types, assetTypes, providers, tags
+}
+
+// searchScope is the part of a search the deployment sets defaults for.
+type searchScope struct {
+	types        []search.ResultType
+	environments []string
+	includeStubs bool
+	sort         search.SortOrder
+}
+
+// parseScope reads the stub, environment and sort parameters, falling back to
+// the deployment's search defaults for those a request leaves out. Result
+// types fall back too when neither types nor types[] is given. An empty
+// environments parameter searches every environment.
+func (h *Handler) parseScope(queryValues url.Values, types []search.ResultType) (searchScope, error) {
+	scope := searchScope{
+		types:        types,
+		includeStubs: h.defaults.IncludeStubs,
+		sort:         h.defaults.Sort,
 	}
 
-	response, err := h.searchService.Search(r.Context(), filter)
+	_, hasTypes := queryValues["types"]
+	if !hasTypes && len(queryValues["types[]"]) == 0 && len(h.defaults.Types) > 0 {
+		scope.types = append([]search.ResultType(nil), h.defaults.Types...)
+	}
+
+	if param := queryValues.Get("include_stubs"); param != "" {
+		includeStubs, err := strconv.ParseBool(param)
+		if err != nil {
+			return scope, errors.New("include_stubs must be true or false")
+		}
+		scope.includeStubs = includeStubs
+	}
+
+	if _, ok := queryValues["environments"]; ok {
+		for _, env := range strings.Split(queryValues.Get("environments"), ",") {
+			if env = strings.TrimSpace(env); env != "" {
+				scope.environments = append(scope.environments, env)
+			}
+		}
+	} else if len(h.defaults.Environments) > 0 {
+		scope.environments = append([]string(nil), h.defaults.Environments...)
+	}
+
+	if param := queryValues.Get("sort"); param != "" {
+		scope.sort = search.SortOrder(param)
+		if !scope.sort.Valid() {
+			return scope, errors.New("sort must be relevance, updated or name")
+		}
+	}
+
+	return scope, nil
+}
+
+// @Summary Search defaults
+// @Description The search scope this deployment applies when a request leaves it out: whether stub assets are included, the result types and environments searched, and the sort order. Empty types and environments don't restrict results.
+// @Tags search
+// @Produce json
+// @Success 200 {object} search.Defaults
+// @Router /search/defaults [get]
+func (h *Handler) getDefaults(w http.ResponseWriter, r *http.Request) {
+	common.RespondJSON(w, http.StatusOK, h.defaults)
+}
+
+// personalize reports whether results should be boosted for the user. The
+// personalize parameter wins over the user's preference.
+func personalize(param string, preferences map[string]interface{}) bool {
+	if enabled, err := strconv.ParseBool(param); err == nil {
+		return enabled
+	}
+	return favorite.Enabled(preferences)
+}
+
+// resolveLineageScope turns the lineage_of parameters into the asset IDs the
+// search is restricted to. It returns nil when no scope was requested, and
+// writes the error response itself when ok is false.
+func (h *Handler) resolveLineageScope(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	queryValues := r.URL.Query()
+	assetRef := strings.TrimSpace(queryValues.Get("lineage_of"))
+	if assetRef == "" {
+		return nil, true
+	}
+	if h.lineageScope == nil {
+		common.RespondError(w, http.StatusBadRequest, "Lineage scoped search is not available")
+		return nil, false
+	}
+
+	direction := queryValues.Get("lineage_direction")
+	switch direction {
+	case "":
+		direction = "downstream"
+	case "upstream", "downstream", "both":
+	default:
+		common.RespondError(w, http.StatusBadRequest, "lineage_direction must be upstream, downstream or both")
+		return nil, false
+	}
+	depth := common.ParseLimit(queryValues.Get("lineage_depth"), 5, 10)
+
+	assetIDs, err := h.lineageScope.ResolveLineageScope(r.Context(), assetRef, direction, depth)
 	if err != nil {
-		log.Error().Err(err).Str("query", query).Msg("Failed to execute search")
-		common.RespondError(w, http.StatusInternalServerError, "Failed to execute search")
-		return
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Lineage asset not found")
+			return nil, false
+		}
+		log.Error().Err(err).Str("lineage_of", assetRef).Msg("Failed to resolve lineage scope for search")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to resolve lineage scope")
+		return nil, false
+	}
+	if assetIDs == nil {
+		assetIDs = []string{}
 	}
 
-	if query != "" && response.Total > 0 {
-		recorder := h.metricsService.GetRecorder()
-		queryType := "full_text"
-		if len(filter.Types) > 0 || len(filter.AssetTypes) > 0 || len(filter.Providers) > 0 || len(filter.Tags) > 0 {
-			queryType = "filtered"
+	return assetIDs, true
+}
+
+// aggregate serves the aggregations_only mode of the search endpoint. Hits are
+// never fetched, so facets can be much larger and are computed for text
+// queries too.
+func (h *Handler) aggregate(w http.ResponseWriter, r *http.Request, filter search.AggregationFilter) {
+	queryValues := r.URL.Query()
+
+	if groupByParam := queryValues.Get("group_by"); groupByParam != "" {
+		for _, field := range strings.Split(groupByParam, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				filter.GroupBy = append(filter.GroupBy, field)
+			}
 		}
-		recorder.RecordSearchQuery(r.Context(), queryType, query)
+	}
+	if len(filter.GroupBy) > search.MaxAggregationGroups {
+		common.RespondError(w, http.StatusBadRequest, "group_by supports at most 5 fields")
+		return
+	}
+
+	filter.FacetLimit = common.ParseLimit(queryValues.Get("facet_limit"), search.DefaultAggregationLimit, search.MaxAggregationLimit)
+
+	response, err := h.searchService.Aggregate(r.Context(), filter)
+	if errors.Is(err, search.ErrInvalidAggregation) {
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("query", filter.Query).Msg("Failed to execute search aggregation")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to execute search aggregation")
+		return
 	}
 
 	common.RespondJSON(w, http.StatusOK, response)
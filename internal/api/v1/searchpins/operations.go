@@ -0,0 +1,120 @@
+package searchpins
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/searchpin"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case searchpin.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, searchpin.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Search pin not found")
+	case errors.Is(err, searchpin.ErrAssetNotFound):
+		common.RespondError(w, http.StatusBadRequest, "Asset not found")
+	case errors.Is(err, searchpin.ErrAlreadyPinned):
+		common.RespondError(w, http.StatusConflict, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List search pins
+// @Description List the assets pinned to search terms, optionally for one term
+// @Tags search
+// @Produce json
+// @Param term query string false "Search term"
+// @Success 200 {array} searchpin.Pin
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/pins [get]
+func (h *Handler) listPins(w http.ResponseWriter, r *http.Request) {
+	pins, err := h.svc.List(r.Context(), r.URL.Query().Get("term"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list search pins")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, pins)
+}
+
+// @Summary Pin an asset to a search term
+// @Description Promote an asset to the top of the results for a search term
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param pin body searchpin.CreateInput true "Search pin"
+// @Success 201 {object} searchpin.Pin
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/pins [post]
+func (h *Handler) createPin(w http.ResponseWriter, r *http.Request) {
+	var input searchpin.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var createdBy string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = usr.ID
+	}
+
+	pin, err := h.svc.Create(r.Context(), input, createdBy)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create search pin")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, pin)
+}
+
+// @Summary Reorder a search pin
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param id path string true "Search pin ID"
+// @Param pin body searchpin.UpdateInput true "New position"
+// @Success 200 {object} searchpin.Pin
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/pins/{id} [put]
+func (h *Handler) updatePin(w http.ResponseWriter, r *http.Request) {
+	var input searchpin.UpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pin, err := h.svc.Update(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		respondServiceError(w, err, "Failed to update search pin")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, pin)
+}
+
+// @Summary Unpin an asset from a search term
+// @Tags search
+// @Param id path string true "Search pin ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /search/pins/{id} [delete]
+func (h *Handler) deletePin(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), r.PathValue("id")); err != nil {
+		respondServiceError(w, err, "Failed to delete search pin")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,9 +2,12 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,46 +18,120 @@ import (
 	"github.com/marmotdata/marmot/internal/crypto"
 
 	"github.com/marmotdata/marmot/internal/api/auth"
+	"github.com/marmotdata/marmot/internal/api/rpc"
 	adminAPI "github.com/marmotdata/marmot/internal/api/v1/admin"
 	agentsAPI "github.com/marmotdata/marmot/internal/api/v1/agents"
+	archivalAPI "github.com/marmotdata/marmot/internal/api/v1/archival"
+	assethealthAPI "github.com/marmotdata/marmot/internal/api/v1/assethealth"
+	assetmergeAPI "github.com/marmotdata/marmot/internal/api/v1/assetmerge"
 	assetrulesAPI "github.com/marmotdata/marmot/internal/api/v1/assetrules"
+	assettypesAPI "github.com/marmotdata/marmot/internal/api/v1/assettypes"
+	businessmetricsAPI "github.com/marmotdata/marmot/internal/api/v1/businessmetrics"
 	"github.com/marmotdata/marmot/internal/api/v1/common"
+	connectionsAPI "github.com/marmotdata/marmot/internal/api/v1/connections"
+	contractsAPI "github.com/marmotdata/marmot/internal/api/v1/contracts"
 	"github.com/marmotdata/marmot/internal/api/v1/dataproducts"
+	deprecationsAPI "github.com/marmotdata/marmot/internal/api/v1/deprecations"
 	docsAPI "github.com/marmotdata/marmot/internal/api/v1/docs"
+	favoritesAPI "github.com/marmotdata/marmot/internal/api/v1/favorites"
 	"github.com/marmotdata/marmot/internal/api/v1/glossary"
+	graphexportAPI "github.com/marmotdata/marmot/internal/api/v1/graphexport"
+	incidentsAPI "github.com/marmotdata/marmot/internal/api/v1/incidents"
 	"github.com/marmotdata/marmot/internal/api/v1/lineage"
 	mcpAPI "github.com/marmotdata/marmot/internal/api/v1/mcp"
+	mentionsAPI "github.com/marmotdata/marmot/internal/api/v1/mentions"
 	metricsAPI "github.com/marmotdata/marmot/internal/api/v1/metrics"
 	notificationsAPI "github.com/marmotdata/marmot/internal/api/v1/notifications"
+	onboardingAPI "github.com/marmotdata/marmot/internal/api/v1/onboarding"
+	ownerimportAPI "github.com/marmotdata/marmot/internal/api/v1/ownerimport"
+	ownershipRequestsAPI "github.com/marmotdata/marmot/internal/api/v1/ownershiprequests"
+	ownervacanciesAPI "github.com/marmotdata/marmot/internal/api/v1/ownervacancies"
 	"github.com/marmotdata/marmot/internal/api/v1/plugins"
+	policytagsAPI "github.com/marmotdata/marmot/internal/api/v1/policytags"
+	presentationAPI "github.com/marmotdata/marmot/internal/api/v1/presentation"
+	provenanceAPI "github.com/marmotdata/marmot/internal/api/v1/provenance"
+	providerhealthAPI "github.com/marmotdata/marmot/internal/api/v1/providerhealth"
+	questionsAPI "github.com/marmotdata/marmot/internal/api/v1/questions"
+	quotasAPI "github.com/marmotdata/marmot/internal/api/v1/quotas"
 	rolesAPI "github.com/marmotdata/marmot/internal/api/v1/roles"
 	"github.com/marmotdata/marmot/internal/api/v1/runs"
+	savedqueriesAPI "github.com/marmotdata/marmot/internal/api/v1/savedqueries"
+	savedsearchesAPI "github.com/marmotdata/marmot/internal/api/v1/savedsearches"
 	schedulesAPI "github.com/marmotdata/marmot/internal/api/v1/schedules"
+	schemasAPI "github.com/marmotdata/marmot/internal/api/v1/schemas"
+	scimAPI "github.com/marmotdata/marmot/internal/api/v1/scim"
 	searchAPI "github.com/marmotdata/marmot/internal/api/v1/search"
+	searchpinsAPI "github.com/marmotdata/marmot/internal/api/v1/searchpins"
 	serviceaccountsAPI "github.com/marmotdata/marmot/internal/api/v1/serviceaccounts"
+	shareAPI "github.com/marmotdata/marmot/internal/api/v1/share"
 	subscriptionsAPI "github.com/marmotdata/marmot/internal/api/v1/subscriptions"
+	tagsyncAPI "github.com/marmotdata/marmot/internal/api/v1/tagsync"
+	tagtaxonomyAPI "github.com/marmotdata/marmot/internal/api/v1/tagtaxonomy"
 	"github.com/marmotdata/marmot/internal/api/v1/teams"
+	thumbnailsAPI "github.com/marmotdata/marmot/internal/api/v1/thumbnails"
+	timelineAPI "github.com/marmotdata/marmot/internal/api/v1/timeline"
 	"github.com/marmotdata/marmot/internal/api/v1/ui"
 	"github.com/marmotdata/marmot/internal/api/v1/users"
+	watchAPI "github.com/marmotdata/marmot/internal/api/v1/watch"
+	watchlistAPI "github.com/marmotdata/marmot/internal/api/v1/watchlist"
 	webhooksAPI "github.com/marmotdata/marmot/internal/api/v1/webhooks"
 	agentService "github.com/marmotdata/marmot/internal/core/agent"
+	archivalService "github.com/marmotdata/marmot/internal/core/archival"
 	"github.com/marmotdata/marmot/internal/core/asset"
 	"github.com/marmotdata/marmot/internal/core/assetdocs"
+	assethealthService "github.com/marmotdata/marmot/internal/core/assethealth"
+	assetmergeService "github.com/marmotdata/marmot/internal/core/assetmerge"
 	assetruleService "github.com/marmotdata/marmot/internal/core/assetrule"
+	assettypeService "github.com/marmotdata/marmot/internal/core/assettype"
 	authService "github.com/marmotdata/marmot/internal/core/auth"
+	authzService "github.com/marmotdata/marmot/internal/core/authz"
+	businessmetricService "github.com/marmotdata/marmot/internal/core/businessmetric"
+	connectionService "github.com/marmotdata/marmot/internal/core/connection"
+	contractService "github.com/marmotdata/marmot/internal/core/contract"
 	dataproductService "github.com/marmotdata/marmot/internal/core/dataproduct"
+	"github.com/marmotdata/marmot/internal/core/demo"
+	deprecationService "github.com/marmotdata/marmot/internal/core/deprecation"
 	docsService "github.com/marmotdata/marmot/internal/core/docs"
 	"github.com/marmotdata/marmot/internal/core/enrichment"
+	exposureService "github.com/marmotdata/marmot/internal/core/exposure"
+	favoriteService "github.com/marmotdata/marmot/internal/core/favorite"
 	glossaryService "github.com/marmotdata/marmot/internal/core/glossary"
+	graphexportService "github.com/marmotdata/marmot/internal/core/graphexport"
+	idempotencyService "github.com/marmotdata/marmot/internal/core/idempotency"
+	incidentService "github.com/marmotdata/marmot/internal/core/incident"
 	lineageService "github.com/marmotdata/marmot/internal/core/lineage"
+	mentionService "github.com/marmotdata/marmot/internal/core/mention"
 	notificationService "github.com/marmotdata/marmot/internal/core/notification"
+	onboardingService "github.com/marmotdata/marmot/internal/core/onboarding"
+	ownerimportService "github.com/marmotdata/marmot/internal/core/ownerimport"
+	ownershipRequestService "github.com/marmotdata/marmot/internal/core/ownershiprequest"
+	ownervacancyService "github.com/marmotdata/marmot/internal/core/ownervacancy"
+	policytagService "github.com/marmotdata/marmot/internal/core/policytag"
+	presentationService "github.com/marmotdata/marmot/internal/core/presentation"
+	provenanceService "github.com/marmotdata/marmot/internal/core/provenance"
+	providerhealthService "github.com/marmotdata/marmot/internal/core/providerhealth"
+	questionService "github.com/marmotdata/marmot/internal/core/question"
+	quotaService "github.com/marmotdata/marmot/internal/core/quota"
 	roleService "github.com/marmotdata/marmot/internal/core/role"
 	runService "github.com/marmotdata/marmot/internal/core/runs"
+	samplingService "github.com/marmotdata/marmot/internal/core/sampling"
+	savedqueryService "github.com/marmotdata/marmot/internal/core/savedquery"
+	savedsearchService "github.com/marmotdata/marmot/internal/core/savedsearch"
+	schemablobService "github.com/marmotdata/marmot/internal/core/schemablob"
+	scimService "github.com/marmotdata/marmot/internal/core/scim"
 	searchService "github.com/marmotdata/marmot/internal/core/search"
+	searchpinService "github.com/marmotdata/marmot/internal/core/searchpin"
 	serviceaccountService "github.com/marmotdata/marmot/internal/core/serviceaccount"
+	shareService "github.com/marmotdata/marmot/internal/core/share"
 	"github.com/marmotdata/marmot/internal/core/subscription"
+	tagsyncService "github.com/marmotdata/marmot/internal/core/tagsync"
+	tagtaxonomyService "github.com/marmotdata/marmot/internal/core/tagtaxonomy"
 	teamService "github.com/marmotdata/marmot/internal/core/team"
+	thumbnailService "github.com/marmotdata/marmot/internal/core/thumbnail"
+	timelineService "github.com/marmotdata/marmot/internal/core/timeline"
 	userService "github.com/marmotdata/marmot/internal/core/user"
+	watchService "github.com/marmotdata/marmot/internal/core/watch"
+	watchlistService "github.com/marmotdata/marmot/internal/core/watchlist"
 	webhookService "github.com/marmotdata/marmot/internal/core/webhook"
 	"github.com/marmotdata/marmot/internal/metrics"
 	marmotOAuth2 "github.com/marmotdata/marmot/internal/oauth2"
@@ -62,6 +139,7 @@ import (
 	"github.com/marmotdata/marmot/internal/plugin"
 	"github.com/marmotdata/marmot/internal/plugin/install"
 	"github.com/marmotdata/marmot/internal/search/elasticsearch"
+	"github.com/marmotdata/marmot/internal/telemetry"
 	"github.com/marmotdata/marmot/internal/telemetry/lookups"
 	"github.com/marmotdata/marmot/internal/websocket"
 	"github.com/marmotdata/marmot/pkg/config"
@@ -79,6 +157,11 @@ type Server struct {
 	metricsService *metrics.Service
 	wsHub          *websocket.Hub
 	scheduler      *runService.Scheduler
+	// Run checkpoint compactor, nil when compaction is disabled
+	checkpointCompactor *runService.Compactor
+
+	// Run artifact pruner, nil when artifacts aren't stored
+	artifactPruner *runService.ArtifactPruner
 
 	// Data product membership evaluation
 	membershipService    *dataproductService.MembershipService
@@ -93,18 +176,61 @@ type Server struct {
 
 	// Webhook dispatcher
 	webhookDispatcher *webhookService.Dispatcher
+	eventWebhooks     *webhookService.EventService
 
 	// Elasticsearch
-	esIndexer   *elasticsearch.Client
-	syncService *searchService.IndexSyncService
+	esIndexer *elasticsearch.Client
+	// Pushes search_index writes to Elasticsearch and invalidates caches
+	changeFeed *searchService.ChangeFeed
 
 	// Operator Run CRD syncer
 	operatorSyncer *operatorSync.Syncer
 
-	handlers []interface{ Routes() []common.Route }
+	// Warehouse tag syncers
+	tagSyncers []*tagsyncService.Syncer
+
+	// Stale asset archiver and TTL expirer
+	archiver       *archivalService.Archiver
+	expirer        *archivalService.Archiver
+	incidentPoller *incidentService.Poller
+	// Snapshots migration progress off deprecated assets
+	deprecationTracker *deprecationService.Tracker
+	// Recomputes column policy tags inherited through column lineage
+	tagPropagator *policytagService.Propagator
+	// Links dbt exposures to the BI assets at their URLs
+	exposureResolver *exposureService.Resolver
+	// Flushes glossary term search, view and click counts
+	glossaryUsage *glossaryService.UsageService
+	// Flushes asset views that personalize search
+	favoriteSvc *favoriteService.Service
+	// Quota early-warning monitor, nil when no quota is configured
+	quotaMonitor       *quotaService.Monitor
+	// Ownership vacancy monitor, nil when disabled
+	vacancyMonitor     *ownervacancyService.Monitor
+	savedSearchChecker *savedsearchService.Checker
+	idempotencySvc     *idempotencyService.Service
+	// Watch folder scanner, nil when watch folders are disabled
+	watchSvc *watchService.Service
+	// gRPC ingestion API, nil when disabled
+	grpcServer *rpc.Server
+	// Connection health monitor, nil when monitoring is disabled
+	connectionMonitor *connectionService.Monitor
+	// Object storage schema sampler, nil when sampling is disabled
+	sampler *samplingService.Sampler
+	// Dashboard thumbnail capturer, nil when capture is disabled
+	thumbnailCapturer *thumbnailService.Capturer
+	// Custom authorization hooks, nil when none are configured
+	authzSvc *authzService.Service
+
+	handlers []routeHandler
+}
+
+// routeHandler is implemented by every API handler.
+type routeHandler interface {
+	Routes() []common.Route
 }
 
-func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Recorder) *Server {
+func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Recorder, telemetryCollector *telemetry.Collector) *Server {
 	metricsStore := metrics.NewPostgresStore(db)
 	metricsService := metrics.NewService(metricsStore, db)
 	metricsService.Start(context.Background())
@@ -132,11 +258,22 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	assetDocsSvc := assetdocs.NewService(assetDocsRepo)
 	authSvc := authService.NewService(authRepo, userSvc)
 	runsSvc := runService.NewService(runRepo, assetSvc, lineageSvc, recorder)
+	runsSvc.SetBatchSize(config.Pipelines.BatchSize)
+	assetMergeSvc := assetmergeService.NewService(assetmergeService.NewPostgresRepository(db), assetSvc)
+	assetMergeSvc.SetSimilarity(config.Pipelines.DuplicateSimilarity)
+	if config.Pipelines.DuplicateSimilarity > 0 {
+		runsSvc.SetDuplicateDetector(assetMergeSvc)
+	}
 	glossarySvc := glossaryService.NewService(glossaryRepo)
+	glossaryUsageSvc := glossaryService.NewUsageService(glossaryService.NewPostgresUsageRepository(db), 0)
+	glossaryUsageSvc.Start(context.Background())
+	favoriteSvc := favoriteService.NewService(favoriteService.NewPostgresRepository(db), 0)
+	favoriteSvc.Start(context.Background())
 	teamRepo := teamService.NewPostgresRepository(db)
 	teamSvc := teamService.NewService(teamRepo)
 	searchSvc := searchService.NewService(searchRepo)
 	dataProductSvc := dataproductService.NewService(dataProductRepo)
+	onboardingSvc := dataproductService.NewOnboardingService(dataproductService.NewPostgresDraftRepository(db, recorder), dataProductSvc)
 	docsRepo := docsService.NewPostgresRepository(db)
 	docsSvc := docsService.NewService(docsRepo)
 	notificationRepo := notificationService.NewPostgresRepository(db)
@@ -149,6 +286,14 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	notificationSvc.Start(context.Background())
 	subscriptionRepo := subscription.NewPostgresRepository(db)
 	subscriptionSvc := subscription.NewService(subscriptionRepo)
+	watchlistSvc := watchlistService.NewService(watchlistService.NewPostgresRepository(db))
+	policyTagRepo := policytagService.NewPostgresRepository(db)
+	policyTagSvc := policytagService.NewService(policyTagRepo)
+	presentationSvc := presentationService.NewService(presentationService.NewPostgresRepository(db))
+	assetTypeSvc := assettypeService.NewService(assettypeService.NewPostgresRepository(db))
+	assetSvc.SetMetadataValidator(assetTypeSvc)
+	tagTaxonomySvc := tagtaxonomyService.NewService(tagtaxonomyService.NewPostgresRepository(db))
+	assetSvc.SetTagValidator(tagTaxonomySvc)
 	membershipRepo := dataproductService.NewPostgresMembershipRepository(db, recorder)
 	membershipSvc := dataproductService.NewMembershipService(
 		dataProductRepo,
@@ -198,6 +343,24 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	// Register membership service with data product service for rule event hooks
 	dataProductSvc.SetRuleObserver(membershipSvc)
 
+	// Lineage-based data product rules are resolved through the lineage service
+	lineageRuleResolver := &lineageRuleResolver{lineageSvc: lineageSvc}
+	membershipSvc.SetLineageResolver(lineageRuleResolver)
+	dataProductSvc.SetLineageResolver(lineageRuleResolver)
+
+	incidentSvc := incidentService.NewService(incidentService.NewPostgresRepository(db))
+	incidentSvc.SetUpstreamResolver(lineageRuleResolver)
+
+	var mergePolicy *asset.MergePolicy
+	if config.AssetMerge.Enabled {
+		policy, err := asset.NewMergePolicy(config.AssetMerge.SourcePriority, config.AssetMerge.Fields)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid asset merge configuration")
+		}
+		mergePolicy = policy
+		runsSvc.SetMergePolicy(mergePolicy)
+	}
+
 	// Register notification observers
 	runsSvc.SetCompletionObserver(&runCompletionNotifier{
 		notificationSvc: notificationSvc,
@@ -209,6 +372,7 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 		lineageSvc:      lineageSvc,
 		assetSvc:        assetSvc,
 		subscriptionSvc: subscriptionSvc,
+		watchlistSvc:    watchlistSvc,
 	})
 	lineageSvc.SetLineageChangeObserver(&lineageChangeNotifier{
 		notificationSvc: notificationSvc,
@@ -216,6 +380,10 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 		assetSvc:        assetSvc,
 		subscriptionSvc: subscriptionSvc,
 	})
+	deprecationSvc := deprecationService.NewService(deprecationService.NewPostgresRepository(db))
+	lineageSvc.SetDeprecationLookup(deprecationSvc)
+	savedQuerySvc := savedqueryService.NewService(savedqueryService.NewPostgresRepository(db))
+	businessMetricSvc := businessmetricService.NewService(businessmetricService.NewPostgresRepository(db), assetSvc, lineageSvc, teamSvc)
 	teamSvc.SetMembershipNotifier(&teamMembershipNotifier{
 		notificationSvc: notificationSvc,
 	})
@@ -227,6 +395,7 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 
 	scheduleRepo := runService.NewSchedulePostgresRepository(db)
 	scheduleSvc := runService.NewScheduleService(scheduleRepo)
+	scheduleSvc.SetServiceAccountResolver(&scheduleServiceAccounts{svc: serviceAccountSvc})
 
 	wsHub := websocket.NewHub(userSvc, authSvc, config)
 	wsHub.Start(context.Background())
@@ -297,6 +466,21 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 
 	pluginRegistry := plugin.GetRegistry()
 
+	connectionSvc := connectionService.NewService(
+		connectionService.NewPostgresRepository(db),
+		pluginConnectionFields{},
+		scheduleEncryptor,
+		connectionService.NewDialChecker(time.Duration(config.Connections.HealthCheckTimeout)*time.Second),
+	)
+	var connectionMonitor *connectionService.Monitor
+	if config.Features.Scheduling && config.Connections.HealthCheckInterval > 0 {
+		connectionMonitor = connectionService.NewMonitor(connectionSvc, &connectionService.MonitorConfig{
+			Interval: time.Duration(config.Connections.HealthCheckInterval) * time.Second,
+			DB:       db,
+		})
+		connectionMonitor.Start(context.Background())
+	}
+
 	schedulerConfig := &runService.SchedulerConfig{
 		MaxWorkers:        config.Pipelines.MaxWorkers,
 		SchedulerInterval: time.Duration(config.Pipelines.SchedulerInterval) * time.Second,
@@ -304,14 +488,41 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 		ClaimExpiry:       time.Duration(config.Pipelines.ClaimExpiry) * time.Second,
 		LinkAssets:        config.Experimental.TablePreview,
 		DB:                db,
+		Connections:       &scheduleConnections{svc: connectionSvc},
 	}
 	if config.Plugins.Autoinstall {
 		schedulerConfig.PluginInstall = &install.Options{Registry: config.Plugins.Registry}
 	}
-	scheduler := runService.NewScheduler(scheduleSvc, runsSvc, scheduleEncryptor, pluginRegistry, pluginLoadState, schedulerConfig)
+	var scheduler *runService.Scheduler
+	if config.Features.Scheduling {
+		scheduler = runService.NewScheduler(scheduleSvc, runsSvc, scheduleEncryptor, pluginRegistry, pluginLoadState, schedulerConfig)
+		if err := scheduler.Start(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Failed to start scheduler")
+		}
+	} else {
+		log.Info().Msg("Scheduling feature disabled - pipeline scheduler not started")
+	}
+
+	var checkpointCompactor *runService.Compactor
+	if config.Pipelines.CheckpointCompactionInterval > 0 {
+		checkpointCompactor = runService.NewCompactor(runsSvc, &runService.CompactorConfig{
+			Interval: time.Duration(config.Pipelines.CheckpointCompactionInterval) * time.Second,
+			History:  config.Pipelines.CheckpointHistory,
+			DB:       db,
+		})
+		checkpointCompactor.Start(context.Background())
+	}
 
-	if err := scheduler.Start(context.Background()); err != nil {
-		log.Error().Err(err).Msg("Failed to start scheduler")
+	var artifactPruner *runService.ArtifactPruner
+	if config.Pipelines.StoreArtifacts {
+		runsSvc.SetArtifactCapture(&runService.ArtifactConfig{
+			MaxSize: config.Pipelines.ArtifactMaxSizeMB << 20,
+		})
+		artifactPruner = runService.NewArtifactPruner(runsSvc, &runService.ArtifactPrunerConfig{
+			Retention: time.Duration(config.Pipelines.ArtifactRetentionDays) * 24 * time.Hour,
+			DB:        db,
+		})
+		artifactPruner.Start(context.Background())
 	}
 
 	oauthManager := authService.NewOAuthManager()
@@ -423,6 +634,21 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	common.SetOAuthManager(oauthManager)
 	common.SetServiceAccountService(serviceAccountSvc)
 
+	if config.Auth.SCIM.Enabled && config.Auth.SCIM.Token == "" {
+		log.Warn().Msg("SCIM is enabled but auth.scim.token is not set - SCIM endpoint will not be served")
+	}
+	scimSvc := scimService.NewService(scimService.NewPostgresRepository(db), userSvc, teamSvc, scimService.Config{
+		BaseURL:      strings.TrimSuffix(config.Server.RootURL, "/") + "/scim/v2",
+		DefaultRoles: config.Auth.SCIM.DefaultRoles,
+	})
+
+	idempotencySvc := idempotencyService.NewService(
+		idempotencyService.NewPostgresRepository(db),
+		time.Duration(config.Idempotency.TTL)*time.Second,
+	)
+	idempotencySvc.StartCleanup(context.Background(), db)
+	common.SetIdempotencyService(idempotencySvc)
+
 	signingKey, signingKeyErr := authSvc.GetSigningKey(context.Background())
 	if signingKeyErr != nil {
 		log.Fatal().Err(signingKeyErr).Msg("Failed to get signing key for OAuth2 provider")
@@ -442,10 +668,185 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	webhookDispatcher.Start(context.Background())
 	webhookSvc := webhookService.NewService(webhookRepo, scheduleEncryptor, webhookDispatcher)
 	notificationSvc.SetExternalNotifier(webhookSvc)
+	runsSvc.SetDeltaPublisher(&catalogDeltaPublisher{webhookSvc: webhookSvc})
+
+	// Admin webhooks for catalog events
+	eventWebhookSvc := webhookService.NewEventService(webhookService.NewPostgresEventRepository(db), scheduleEncryptor, webhookService.EventDeliveryConfig{DB: db})
+	eventWebhookSvc.Start(context.Background())
+	eventPublisher := &eventWebhookPublisher{events: eventWebhookSvc}
+	assetSvc.AddMembershipObserver(&assetCreatedPublisher{publisher: eventPublisher})
+	assetSvc.AddChangeObserver(eventPublisher)
+	lineageSvc.AddLineageChangeObserver(eventPublisher)
+	runsSvc.AddCompletionObserver(eventPublisher)
+
+	tagSyncers := newTagSyncers(config, db, assetSvc, policyTagSvc)
+	tagSyncSvcs := make([]*tagsyncService.Service, len(tagSyncers))
+	for i, syncer := range tagSyncers {
+		tagSyncSvcs[i] = syncer.Service()
+	}
+
+	archivalSvc := archivalService.NewService(
+		archivalService.NewPostgresRepository(db),
+		assetSvc,
+		&archivalOwnerStore{teamSvc: teamSvc},
+		archivalService.Policy{
+			StaleAfter:  time.Duration(config.Archival.StaleAfterDays) * 24 * time.Hour,
+			GracePeriod: time.Duration(config.Archival.GracePeriodDays) * 24 * time.Hour,
+		},
+	)
+	archivalSvc.SetNotifier(&archivalNotifier{notificationSvc: notificationSvc})
+
+	questionSvc := questionService.NewService(questionService.NewPostgresRepository(db), teamSvc)
+	questionSvc.SetNotifier(&questionNotifier{notificationSvc: notificationSvc, teamSvc: teamSvc, assetSvc: assetSvc})
+
+	mentionSvc := mentionService.NewService(mentionService.NewPostgresRepository(db))
+	mentionSvc.SetNotifier(&mentionNotifier{notificationSvc: notificationSvc, userSvc: userSvc, assetSvc: assetSvc})
+	questionSvc.SetMentionTracker(mentionSvc)
+
+	ownershipRequestSvc := ownershipRequestService.NewService(ownershipRequestService.NewPostgresRepository(db), teamSvc)
+	ownershipRequestSvc.SetNotifier(&ownershipRequestNotifier{notificationSvc: notificationSvc, teamSvc: teamSvc})
+
+	shareSvc := shareService.NewService(shareService.NewPostgresRepository(db), authSvc, assetSvc)
+
+	var archiver *archivalService.Archiver
+	if config.Archival.Enabled {
+		archiver = archivalService.NewArchiver(archivalSvc, &archivalService.ArchiverConfig{
+			Interval: time.Duration(config.Archival.Interval) * time.Second,
+			DB:       db,
+		})
+		archiver.Start(context.Background())
+	}
+
+	var expirer *archivalService.Archiver
+	if config.Archival.ExpiryEnabled {
+		expirer = archivalService.NewExpirer(archivalSvc, &archivalService.ArchiverConfig{
+			Interval: time.Duration(config.Archival.ExpiryInterval) * time.Second,
+			DB:       db,
+		})
+		expirer.Start(context.Background())
+	}
+
+	deprecationTracker := deprecationService.NewTracker(deprecationSvc, &deprecationService.TrackerConfig{DB: db})
+	deprecationTracker.Start(context.Background())
+
+	tagPropagator := policytagService.NewPropagator(policyTagSvc, &policytagService.PropagatorConfig{DB: db})
+	tagPropagator.Start(context.Background())
+
+	exposureSvc := exposureService.NewService(exposureService.NewPostgresRepository(db), lineageSvc)
+	exposureResolver := exposureService.NewResolver(exposureSvc, &exposureService.ResolverConfig{DB: db})
+	exposureResolver.Start(context.Background())
+
+	quotaSvc := quotaService.NewService(quotaService.NewPostgresRepository(db), quotaService.Limits{
+		MaxAssetsPerProvider: config.Quotas.MaxAssetsPerProvider,
+		Providers:            config.Quotas.Providers,
+		MaxStubs:             config.Quotas.MaxStubs,
+		MaxRunsPerDay:        config.Quotas.MaxRunsPerDay,
+		WarnAt:               config.Quotas.WarnAt,
+	})
+	var quotaMonitor *quotaService.Monitor
+	if quotaSvc.Enabled() {
+		runsSvc.SetQuotas(quotaSvc)
+		lineageSvc.SetStubQuota(quotaSvc)
+		quotaSvc.SetNotifier(&quotaNotifier{notificationSvc: notificationSvc, userSvc: userSvc, roleSvc: roleSvc})
+		quotaMonitor = quotaService.NewMonitor(quotaSvc, &quotaService.MonitorConfig{
+			Interval: time.Duration(config.Quotas.Interval) * time.Second,
+			DB:       db,
+		})
+		quotaMonitor.Start(context.Background())
+	}
+
+	vacancySvc := ownervacancyService.NewService(ownervacancyService.NewPostgresRepository(db),
+		time.Duration(config.OwnershipVacancies.RemindAfterDays)*24*time.Hour)
+	var vacancyMonitor *ownervacancyService.Monitor
+	if config.OwnershipVacancies.Enabled {
+		vacancySvc.SetNotifier(&vacancyNotifier{notificationSvc: notificationSvc, userSvc: userSvc, roleSvc: roleSvc})
+		vacancyMonitor = ownervacancyService.NewMonitor(vacancySvc, &ownervacancyService.MonitorConfig{
+			Interval: time.Duration(config.OwnershipVacancies.Interval) * time.Second,
+			DB:       db,
+		})
+		vacancyMonitor.Start(context.Background())
+	}
+
+	var sampler *samplingService.Sampler
+	if config.Sampling.Enabled {
+		sampler = newSampler(config, db)
+	}
+
+	assetHealthSvc := assethealthService.NewService(assethealthService.NewPostgresRepository(db))
+
+	thumbnailSvc := newThumbnailService(config, db)
+	var thumbnailCapturer *thumbnailService.Capturer
+	if config.Thumbnails.Enabled && config.Thumbnails.RendererURL != "" {
+		thumbnailCapturer = thumbnailService.NewCapturer(thumbnailSvc, &thumbnailService.CapturerConfig{
+			Interval: time.Duration(config.Thumbnails.Interval) * time.Second,
+			DB:       db,
+		})
+		thumbnailCapturer.Start(context.Background())
+		log.Info().Str("renderer", config.Thumbnails.RendererURL).Msg("Dashboard thumbnail capture enabled")
+	}
+
+	var incidentPoller *incidentService.Poller
+	if config.Incidents.Polling {
+		registerIncidentPollers(config, incidentSvc)
+		if incidentSvc.HasPollers() {
+			incidentPoller = incidentService.NewPoller(incidentSvc, &incidentService.PollerConfig{
+				Interval:  time.Duration(config.Incidents.Interval) * time.Second,
+				BatchSize: config.Incidents.BatchSize,
+				DB:        db,
+			})
+			incidentPoller.Start(context.Background())
+		} else {
+			log.Warn().Msg("Incident polling enabled but no tracker is configured")
+		}
+	}
+
+	var watchSvc *watchService.Service
+	if config.Watch.Enabled {
+		folders := make([]watchService.Folder, len(config.Watch.Folders))
+		for i, f := range config.Watch.Folders {
+			folders[i] = watchService.Folder{
+				Name:     f.Name,
+				Path:     f.Path,
+				Region:   f.Region,
+				Endpoint: f.Endpoint,
+				Config:   f.Config,
+			}
+		}
+		watchSvc = watchService.NewService(watchService.NewPostgresRepository(db), scheduleSvc, runsSvc, folders)
+		watchSvc.Start(context.Background(), db, time.Duration(config.Watch.Interval)*time.Second)
+	}
+
+	var authzSvc *authzService.Service
+	if opa := config.Auth.Authorization.OPA; opa.URL != "" {
+		hook := authzService.NewOPAHook(opa.URL, time.Duration(opa.Timeout)*time.Second)
+		authzSvc = authzService.NewService(hook, &authzAttributeAdapter{assetSvc: assetSvc, teamSvc: teamSvc}, opa.FailOpen)
+		log.Info().Str("url", opa.URL).Bool("fail_open", opa.FailOpen).Msg("OPA authorization hook enabled")
+	}
+
+	var grpcServer *rpc.Server
+	if config.GRPC.Enabled {
+		srv, err := rpc.NewServer(config, rpc.Services{
+			Runs:            runsSvc,
+			Schedules:       scheduleSvc,
+			Assets:          assetSvc,
+			Lineage:         lineageSvc,
+			Users:           userSvc,
+			ServiceAccounts: serviceAccountSvc,
+			Authz:           authzSvc,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create gRPC server")
+		}
+		if err := srv.Start(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start gRPC server")
+		}
+		grpcServer = srv
+	}
+
+	searchRebuilder := searchService.NewRebuilder(searchRepo, 500)
 
 	var finalSearchSvc searchService.Service = searchSvc
 	var esClient *elasticsearch.Client
-	var syncSvc *searchService.IndexSyncService
 	var reindexer *searchService.Reindexer
 
 	if esConfig := config.Search.Elasticsearch; esConfig != nil && esConfig.Enabled {
@@ -470,26 +871,6 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 					log.Error().Err(err).Msg("Failed to create Elasticsearch index")
 				}
 
-				syncSvc = searchService.NewIndexSyncService(esClient, searchRepo)
-				syncSvc.Start(context.Background())
-
-				assetSvc.AddMembershipObserver(&assetSearchSyncAdapter{syncSvc: syncSvc})
-				assetSvc.SetNotificationObserver(&assetNotificationSearchAdapter{
-					syncSvc: syncSvc,
-					delegate: &assetChangeNotifier{
-						notificationSvc: notificationSvc,
-						teamSvc:         teamSvc,
-						lineageSvc:      lineageSvc,
-						assetSvc:        assetSvc,
-						subscriptionSvc: subscriptionSvc,
-					},
-				})
-
-				glossarySvc.SetSearchObserver(syncSvc)
-				teamSvc.SetSearchObserver(syncSvc)
-				dataProductSvc.SetSearchObserver(syncSvc)
-				docsSvc.SetSearchObserver(&docsSearchSyncAdapter{syncSvc: syncSvc, assetSvc: assetSvc})
-
 				reindexer = searchService.NewReindexer(esClient, searchRepo, esConfig.BulkSize)
 				reindexBroadcaster := websocket.NewSearchReindexBroadcaster(wsHub)
 				reindexer.SetBroadcaster(reindexBroadcaster)
@@ -507,54 +888,161 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 		}
 	}
 
+	// The change feed picks up every search_index write, from any server
+	// and including ingestion runs, so it is the only path that updates
+	// Elasticsearch.
+	changeFeedCfg := &searchService.ChangeFeedConfig{DB: db, Repo: searchRepo}
+	if esClient != nil {
+		changeFeedCfg.Indexer = esClient
+		changeFeedCfg.BatchSize = config.Search.Elasticsearch.BulkSize
+		changeFeedCfg.Window = time.Duration(config.Search.Elasticsearch.FlushInterval) * time.Millisecond
+	}
+	changeFeed := searchService.NewChangeFeed(changeFeedCfg)
+	changeFeed.AddListener(func(_ context.Context, entityTypes []string) {
+		if entityTypes == nil || slices.Contains(entityTypes, string(searchService.ResultTypeAsset)) {
+			assetSvc.InvalidateCaches()
+		}
+	})
+	changeFeed.Start(context.Background())
+
+	// Pins are applied on top of whichever backend ranks the results.
+	searchPinSvc := searchpinService.NewService(searchpinService.NewPostgresRepository(db))
+	finalSearchSvc = searchService.NewPersonalizedSearchService(finalSearchSvc, favoriteSvc)
+	finalSearchSvc = searchService.NewPinnedSearchService(finalSearchSvc, searchPinSvc)
+	finalSearchSvc = searchService.NewThumbnailSearchService(finalSearchSvc, thumbnailSvc, thumbnailService.URL)
+	finalSearchSvc = searchService.NewHealthSearchService(finalSearchSvc, assetHealthSvc)
+	finalSearchSvc = searchService.NewTypeFacetSearchService(finalSearchSvc, assetTypeSvc)
+
+	savedSearchSvc := savedsearchService.NewService(savedsearchService.NewPostgresRepository(db), finalSearchSvc)
+	savedSearchSvc.SetNotifier(&savedSearchNotifier{notificationSvc: notificationSvc})
+	savedSearchChecker := savedsearchService.NewChecker(savedSearchSvc, &savedsearchService.CheckerConfig{DB: db})
+	savedSearchChecker.Start(context.Background())
+
 	server := &Server{
 		config:                     config,
 		metricsService:             metricsService,
 		wsHub:                      wsHub,
 		scheduler:                  scheduler,
+		checkpointCompactor:        checkpointCompactor,
+		artifactPruner:             artifactPruner,
 		membershipService:          membershipSvc,
 		membershipReconciler:       membershipReconciler,
 		assetRuleMembershipService: assetRuleMemberSvc,
 		assetRuleReconciler:        assetRuleReconciler,
 		notificationService:        notificationSvc,
 		webhookDispatcher:          webhookDispatcher,
+		eventWebhooks:              eventWebhookSvc,
 		esIndexer:                  esClient,
-		syncService:                syncSvc,
+		changeFeed:                 changeFeed,
+		tagSyncers:                 tagSyncers,
+		archiver:                   archiver,
+		expirer:                    expirer,
+		incidentPoller:             incidentPoller,
+		deprecationTracker:         deprecationTracker,
+		tagPropagator:              tagPropagator,
+		exposureResolver:           exposureResolver,
+		glossaryUsage:              glossaryUsageSvc,
+		favoriteSvc:                favoriteSvc,
+		quotaMonitor:               quotaMonitor,
+		vacancyMonitor:             vacancyMonitor,
+		savedSearchChecker:         savedSearchChecker,
+		connectionMonitor:          connectionMonitor,
+		sampler:                    sampler,
+		thumbnailCapturer:          thumbnailCapturer,
+		idempotencySvc:             idempotencySvc,
+		watchSvc:                   watchSvc,
+		grpcServer:                 grpcServer,
+		authzSvc:                   authzSvc,
 	}
 
 	schedulesHandler := schedulesAPI.NewHandler(scheduleSvc, runsSvc, userSvc, authSvc, scheduleEncryptor, config, encryptionConfigured)
+	schedulesHandler.SetConnectionResolver(&scheduleConnections{svc: connectionSvc})
+
+	assetsHandler := assets.NewHandler(assetSvc, assetDocsSvc, userSvc, authSvc, metricsService, runsSvc, scheduleSvc, teamSvc, assetRuleSvc, mentionSvc, scheduleEncryptor, config, lookupsRecorder, favoriteSvc)
+	assetsHandler.SetHealthService(assetHealthSvc)
+	assetsHandler.SetWatchService(watchlistSvc)
 
 	authHandler := auth.NewHandler(authSvc, oauthManager, userSvc, config, oauthFositeProvider, authorizeSessionStore)
 	common.SetOAuthAuthorizeCompleter(authHandler)
 
-	server.handlers = []interface{ Routes() []common.Route }{
+	server.handlers = []routeHandler{
 		health.NewHandler(),
-		assets.NewHandler(assetSvc, assetDocsSvc, userSvc, authSvc, metricsService, runsSvc, scheduleSvc, teamSvc, assetRuleSvc, scheduleEncryptor, config, lookupsRecorder),
+		assetsHandler,
+		assethealthAPI.NewHandler(assetHealthSvc, userSvc, authSvc, config),
+		timelineAPI.NewHandler(timelineService.NewService(timelineService.NewPostgresRepository(db)), userSvc, authSvc, config),
 		users.NewHandler(userSvc, authSvc, config),
 		authHandler,
+		scimAPI.NewHandler(scimSvc, config),
 		lineage.NewHandler(lineageSvc, userSvc, authSvc, config, lookupsRecorder),
-		mcpAPI.NewHandler(assetSvc, glossarySvc, userSvc, teamSvc, dataProductSvc, lineageSvc, finalSearchSvc, authSvc, config, lookupsRecorder),
+		contractsAPI.NewHandler(contractService.NewService(assetSvc, lineageSvc), userSvc, authSvc, config),
+		deprecationsAPI.NewHandler(deprecationSvc, userSvc, authSvc, config),
 		metricsAPI.NewHandler(metricsService, userSvc, authSvc, config),
 		runs.NewHandler(runsSvc, userSvc, authSvc, scheduleSvc, config),
-		glossary.NewHandler(glossarySvc, userSvc, authSvc, config, lookupsRecorder),
-		dataproducts.NewHandler(dataProductSvc, userSvc, authSvc, config, lookupsRecorder),
 		assetrulesAPI.NewHandler(assetRuleSvc, userSvc, authSvc, config),
 		docsAPI.NewHandler(docsSvc, userSvc, authSvc, config),
 		notificationsAPI.NewHandler(notificationSvc, userSvc, authSvc, config),
 		subscriptionsAPI.NewHandler(subscriptionSvc, userSvc, authSvc, config),
+		policytagsAPI.NewHandler(policyTagSvc, userSvc, authSvc, config),
+		presentationAPI.NewHandler(presentationSvc, userSvc, authSvc, config),
+		assettypesAPI.NewHandler(assetTypeSvc, userSvc, authSvc, config),
+		tagtaxonomyAPI.NewHandler(tagTaxonomySvc, userSvc, authSvc, config),
+		graphexportAPI.NewHandler(graphexportService.NewService(graphexportService.NewPostgresRepository(db)), userSvc, authSvc, config),
+		provenanceAPI.NewHandler(provenanceService.NewService(provenanceService.NewPostgresRepository(db), assetSvc, mergePolicy), userSvc, authSvc, config),
+		providerhealthAPI.NewHandler(providerhealthService.NewService(providerhealthService.NewPostgresRepository(db), time.Duration(config.Archival.StaleAfterDays)*24*time.Hour), userSvc, authSvc, config),
+		quotasAPI.NewHandler(quotaSvc, userSvc, authSvc, config),
+		ownervacanciesAPI.NewHandler(vacancySvc, userSvc, authSvc, config),
+		incidentsAPI.NewHandler(incidentSvc, userSvc, authSvc, config),
+		questionsAPI.NewHandler(questionSvc, userSvc, authSvc, config),
+		ownershipRequestsAPI.NewHandler(ownershipRequestSvc, userSvc, authSvc, config),
+		savedqueriesAPI.NewHandler(savedQuerySvc, userSvc, authSvc, config),
+		savedsearchesAPI.NewHandler(savedSearchSvc, userSvc, authSvc, config),
+		businessmetricsAPI.NewHandler(businessMetricSvc, userSvc, authSvc, config),
+		mentionsAPI.NewHandler(mentionSvc, userSvc, authSvc, config),
+		shareAPI.NewHandler(shareSvc, userSvc, authSvc, config),
+		thumbnailsAPI.NewHandler(thumbnailSvc, userSvc, authSvc, config),
+		onboardingAPI.NewHandler(onboardingService.NewService(onboardingService.NewPostgresRepository(db)), userSvc, authSvc, config),
+		ownerimportAPI.NewHandler(ownerimportService.NewService(ownerimportService.NewPostgresRepository(db)), userSvc, authSvc, config),
 		teams.NewHandler(teamSvc, userSvc, authSvc, config),
 		webhooksAPI.NewHandler(webhookSvc, teamSvc, userSvc, authSvc, config, encryptionConfigured),
-		searchAPI.NewHandler(finalSearchSvc, userSvc, authSvc, metricsService, config),
-		schedulesHandler,
+		webhooksAPI.NewEventHandler(eventWebhookSvc, userSvc, authSvc, config, encryptionConfigured),
+		searchAPI.NewHandler(finalSearchSvc, &searchLineageScope{assetSvc: assetSvc, lineage: lineageRuleResolver}, glossaryUsageSvc, userSvc, authSvc, metricsService, config),
+		searchpinsAPI.NewHandler(searchPinSvc, userSvc, authSvc, config),
+		favoritesAPI.NewHandler(favoriteSvc, userSvc, authSvc, config),
+		watchlistAPI.NewHandler(watchlistSvc, userSvc, authSvc, config),
+		assetmergeAPI.NewHandler(assetMergeSvc, userSvc, authSvc, config),
+		schemasAPI.NewHandler(schemablobService.NewService(schemablobService.NewPostgresRepository(db)), userSvc, authSvc, config),
 		websocket.NewHandler(wsHub, config),
 		rolesAPI.NewHandler(roleSvc, userSvc, authSvc, config),
 		serviceaccountsAPI.NewHandler(serviceAccountSvc, userSvc, authSvc, config),
 		plugins.NewHandler(),
-		ui.NewHandler(config, encryptionConfigured),
-		adminAPI.NewHandler(reindexer, userSvc, authSvc, config),
-		agentsAPI.NewHandler(agentSvc, userSvc, authSvc, config),
+		ui.NewHandler(userSvc, authSvc, config, encryptionConfigured),
+		adminAPI.NewHandler(reindexer, searchRebuilder, demo.NewSeeder(runsSvc, assetSvc, glossarySvc, dataProductSvc), telemetryCollector, userSvc, authSvc, config),
+		tagsyncAPI.NewHandler(tagSyncSvcs, userSvc, authSvc, config),
+		archivalAPI.NewHandler(archivalSvc, userSvc, authSvc, config),
+		watchAPI.NewHandler(watchSvc, userSvc, authSvc, config),
 	}
 
+	server.handlers = append(server.handlers, featureHandlers{
+		glossary: func() []routeHandler {
+			return []routeHandler{glossary.NewHandler(glossarySvc, userSvc, authSvc, config, lookupsRecorder, glossaryUsageSvc)}
+		},
+		dataProducts: func() []routeHandler {
+			return []routeHandler{dataproducts.NewHandler(dataProductSvc, onboardingSvc, userSvc, authSvc, config, lookupsRecorder)}
+		},
+		scheduling: func() []routeHandler {
+			return []routeHandler{
+				schedulesHandler,
+				connectionsAPI.NewHandler(connectionSvc, userSvc, authSvc, config, encryptionConfigured),
+			}
+		},
+		ai: func() []routeHandler {
+			return []routeHandler{
+				mcpAPI.NewHandler(assetSvc, glossarySvc, userSvc, teamSvc, dataProductSvc, lineageSvc, finalSearchSvc, authSvc, config, lookupsRecorder),
+				agentsAPI.NewHandler(agentSvc, userSvc, authSvc, config),
+			}
+		},
+	}.enabled(config)...)
+
 	// Set up K8s SA token auth and operator syncer if enabled
 	if config.Operator.Enabled {
 		k8sValidator, err := common.NewK8sTokenValidator()
@@ -585,12 +1073,100 @@ func New(config *config.Config, db *pgxpool.Pool, lookupsRecorder lookups.Record
 	return server
 }
 
+// featureHandlers builds the handlers of each optional subsystem.
+type featureHandlers struct {
+	glossary     func() []routeHandler
+	dataProducts func() []routeHandler
+	scheduling   func() []routeHandler
+	ai           func() []routeHandler
+}
+
+// enabled returns the handlers of the features switched on in cfg. Disabled
+// features are never built, so none of their routes are registered.
+func (f featureHandlers) enabled(cfg *config.Config) []routeHandler {
+	features := []struct {
+		on    bool
+		build func() []routeHandler
+	}{
+		{cfg.Features.Glossary, f.glossary},
+		{cfg.Features.DataProducts, f.dataProducts},
+		{cfg.Features.Scheduling, f.scheduling},
+		{cfg.Features.AI, f.ai},
+	}
+
+	var handlers []routeHandler
+	for _, feature := range features {
+		if feature.on {
+			handlers = append(handlers, feature.build()...)
+		}
+	}
+	return handlers
+}
+
 func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
 	if s.operatorSyncer != nil {
 		s.operatorSyncer.Stop()
 	}
-	if s.syncService != nil {
-		s.syncService.Stop()
+	for _, syncer := range s.tagSyncers {
+		syncer.Stop()
+	}
+	if s.archiver != nil {
+		s.archiver.Stop()
+	}
+	if s.expirer != nil {
+		s.expirer.Stop()
+	}
+	if s.incidentPoller != nil {
+		s.incidentPoller.Stop()
+	}
+	if s.deprecationTracker != nil {
+		s.deprecationTracker.Stop()
+	}
+	if s.tagPropagator != nil {
+		s.tagPropagator.Stop()
+	}
+	if s.exposureResolver != nil {
+		s.exposureResolver.Stop()
+	}
+	if s.glossaryUsage != nil {
+		s.glossaryUsage.Stop()
+	}
+	if s.favoriteSvc != nil {
+		s.favoriteSvc.Stop()
+	}
+	if s.quotaMonitor != nil {
+		s.quotaMonitor.Stop()
+	}
+	if s.vacancyMonitor != nil {
+		s.vacancyMonitor.Stop()
+	}
+	if s.savedSearchChecker != nil {
+		s.savedSearchChecker.Stop()
+	}
+	if s.connectionMonitor != nil {
+		s.connectionMonitor.Stop()
+	}
+	if s.sampler != nil {
+		s.sampler.Stop()
+	}
+	if s.thumbnailCapturer != nil {
+		s.thumbnailCapturer.Stop()
+	}
+	if s.checkpointCompactor != nil {
+		s.checkpointCompactor.Stop()
+	}
+	if s.artifactPruner != nil {
+		s.artifactPruner.Stop()
+	}
+	s.idempotencySvc.Stop()
+	if s.watchSvc != nil {
+		s.watchSvc.Stop()
+	}
+	if s.changeFeed != nil {
+		s.changeFeed.Stop()
 	}
 	if s.esIndexer != nil {
 		s.esIndexer.Close()
@@ -610,6 +1186,9 @@ func (s *Server) Stop() {
 	if s.webhookDispatcher != nil {
 		s.webhookDispatcher.Stop()
 	}
+	if s.eventWebhooks != nil {
+		s.eventWebhooks.Stop()
+	}
 	if s.notificationService != nil {
 		s.notificationService.Stop()
 	}
@@ -646,6 +1225,9 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 		handlers := make(map[string]http.HandlerFunc)
 		for _, route := range pathRoutes {
 			handler := route.Handler
+			if s.authzSvc != nil {
+				handler = common.WithAuthorizationHook(s.authzSvc)(handler)
+			}
 			for i := len(route.Middleware) - 1; i >= 0; i-- {
 				handler = route.Middleware[i](handler)
 			}
@@ -691,6 +1273,72 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	registerSwagger(mux)
 }
 
+// authzAttributeAdapter adapts the asset and team services to
+// authz.AttributeSource
+type authzAttributeAdapter struct {
+	assetSvc asset.Service
+	teamSvc  *teamService.Service
+}
+
+func (a *authzAttributeAdapter) UserTeams(ctx context.Context, userID string) ([]string, error) {
+	teams, err := a.teamSvc.ListUserTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(teams))
+	for i, t := range teams {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+func (a *authzAttributeAdapter) Asset(ctx context.Context, id string) (*authzService.Resource, error) {
+	ast, err := a.assetSvc.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resource := &authzService.Resource{
+		ID:           ast.ID,
+		Type:         ast.Type,
+		Providers:    ast.Providers,
+		Tags:         ast.Tags,
+		Environments: make([]string, 0, len(ast.Environments)),
+		OwnerTeams:   []string{},
+		OwnerUsers:   []string{},
+	}
+	if ast.MRN != nil {
+		resource.MRN = *ast.MRN
+	}
+	if resource.Providers == nil {
+		resource.Providers = []string{}
+	}
+	if resource.Tags == nil {
+		resource.Tags = []string{}
+	}
+	for name := range ast.Environments {
+		resource.Environments = append(resource.Environments, name)
+	}
+	sort.Strings(resource.Environments)
+
+	owners, err := a.teamSvc.ListAssetOwners(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range owners {
+		switch {
+		case o.Type == "team":
+			resource.OwnerTeams = append(resource.OwnerTeams, o.Name)
+		case o.Username != nil:
+			resource.OwnerUsers = append(resource.OwnerUsers, *o.Username)
+		}
+	}
+	return resource, nil
+}
+
 // teamMembershipAdapter adapts teamService to notification.TeamMembershipProvider
 type teamMembershipAdapter struct {
 	teamSvc *teamService.Service
@@ -773,6 +1421,9 @@ func (n *runCompletionNotifier) OnRunCompleted(ctx context.Context, run *plugin.
 	case "scheduler", "system", "operator", "anonymous":
 		return
 	}
+	if strings.HasPrefix(run.CreatedBy, authService.ServiceAccountSubjectPrefix) {
+		return
+	}
 
 	// Look up the user who triggered the run
 	user, err := n.userSvc.GetUserByUsername(ctx, run.CreatedBy)
@@ -821,12 +1472,117 @@ func (n *runCompletionNotifier) OnRunCompleted(ctx context.Context, run *plugin.
 	}
 }
 
+// catalogDeltaPublisher sends run catalog deltas to webhooks subscribed to
+// catalog_delta events.
+type catalogDeltaPublisher struct {
+	webhookSvc *webhookService.Service
+}
+
+func (p *catalogDeltaPublisher) PublishCatalogDelta(ctx context.Context, delta *runService.CatalogDelta) {
+	data := map[string]interface{}{
+		"run_id":        delta.RunID,
+		"pipeline_name": delta.Pipeline,
+		"source":        delta.Source,
+		"status":        delta.Status,
+		"completed_at":  delta.CompletedAt,
+		"counts":        delta.Counts,
+		"changes":       delta.Changes,
+		"truncated":     delta.Truncated,
+	}
+
+	p.webhookSvc.DispatchEvent(ctx, webhookService.EventCatalogDelta,
+		fmt.Sprintf("Catalog changed by %s", delta.Pipeline),
+		fmt.Sprintf("%d created, %d updated, %d deleted",
+			delta.Counts[runService.StatusCreated], delta.Counts[runService.StatusUpdated], delta.Counts[runService.StatusDeleted]),
+		data)
+}
+
+// eventWebhookPublisher sends asset, lineage and run changes to the event
+// webhooks subscribed to them.
+type eventWebhookPublisher struct {
+	events *webhookService.EventService
+}
+
+func assetEventData(a *asset.Asset) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":        a.ID,
+		"type":      a.Type,
+		"providers": a.Providers,
+	}
+	if a.MRN != nil {
+		data["mrn"] = *a.MRN
+	}
+	if a.Name != nil {
+		data["name"] = *a.Name
+	}
+	return data
+}
+
+func (p *eventWebhookPublisher) OnAssetUpdated(ctx context.Context, a *asset.Asset, changeType string, changedFields []string) {
+	p.events.Publish(ctx, webhookService.EventAssetUpdated, map[string]interface{}{
+		"asset":          assetEventData(a),
+		"change_type":    changeType,
+		"changed_fields": changedFields,
+	})
+}
+
+func (p *eventWebhookPublisher) OnAssetDeleted(ctx context.Context, a *asset.Asset) {
+	p.events.Publish(ctx, webhookService.EventAssetDeleted, map[string]interface{}{
+		"asset": assetEventData(a),
+	})
+}
+
+func (p *eventWebhookPublisher) OnEdgeCreated(ctx context.Context, sourceMRN, targetMRN, edgeType string) {
+	p.events.Publish(ctx, webhookService.EventLineageCreated, map[string]interface{}{
+		"source": sourceMRN,
+		"target": targetMRN,
+		"type":   edgeType,
+	})
+}
+
+func (p *eventWebhookPublisher) OnEdgeDeleted(ctx context.Context, sourceMRN, targetMRN string) {
+	p.events.Publish(ctx, webhookService.EventLineageDeleted, map[string]interface{}{
+		"source": sourceMRN,
+		"target": targetMRN,
+	})
+}
+
+func (p *eventWebhookPublisher) OnRunCompleted(ctx context.Context, run *plugin.Run) {
+	p.events.Publish(ctx, webhookService.EventRunCompleted, map[string]interface{}{
+		"run_id":        run.RunID,
+		"pipeline_name": run.PipelineName,
+		"source":        run.SourceName,
+		"status":        run.Status,
+		"started_at":    run.StartedAt,
+		"completed_at":  run.CompletedAt,
+		"error_message": run.ErrorMessage,
+		"summary":       run.Summary,
+	})
+}
+
+// assetCreatedPublisher adapts eventWebhookPublisher to
+// asset.MembershipObserver, whose deletions it leaves to OnAssetDeleted.
+type assetCreatedPublisher struct {
+	publisher *eventWebhookPublisher
+}
+
+func (p *assetCreatedPublisher) OnAssetCreated(ctx context.Context, a *asset.Asset) {
+	p.publisher.events.Publish(ctx, webhookService.EventAssetCreated, map[string]interface{}{
+		"asset": assetEventData(a),
+	})
+}
+
+func (p *assetCreatedPublisher) OnAssetDeleted(ctx context.Context, assetID string) error {
+	return nil
+}
+
 type assetChangeNotifier struct {
 	notificationSvc *notificationService.Service
 	teamSvc         *teamService.Service
 	lineageSvc      lineageService.Service
 	assetSvc        asset.Service
 	subscriptionSvc *subscription.Service
+	watchlistSvc    *watchlistService.Service
 }
 
 func (n *assetChangeNotifier) OnAssetUpdated(ctx context.Context, a *asset.Asset, changeType string, changedFields []string) {
@@ -877,6 +1633,8 @@ func (n *assetChangeNotifier) OnAssetUpdated(ctx context.Context, a *asset.Asset
 		}
 	}
 
+	recipients = n.addWatchers(ctx, a.ID, recipients, seen)
+
 	if len(recipients) > 0 {
 		n.notificationSvc.QueueAssetChange(a.ID, assetMRN, assetName, changeType, recipients, changedFields)
 	}
@@ -935,11 +1693,37 @@ func (n *assetChangeNotifier) OnAssetDeleted(ctx context.Context, a *asset.Asset
 		}
 	}
 
+	recipients = n.addWatchers(ctx, a.ID, recipients, seen)
+
 	if len(recipients) > 0 {
 		n.notificationSvc.QueueAssetChange(a.ID, assetMRN, assetName, notificationService.TypeAssetDeleted, recipients, nil)
 	}
 }
 
+// addWatchers adds the users watching an asset to recipients. Watchers get
+// every change to the asset, unlike subscribers who choose the types.
+func (n *assetChangeNotifier) addWatchers(ctx context.Context, assetID string, recipients []notificationService.Recipient, seen map[string]bool) []notificationService.Recipient {
+	if n.watchlistSvc == nil {
+		return recipients
+	}
+	watcherIDs, err := n.watchlistSvc.Watchers(ctx, watchlistService.EntityAsset, assetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", assetID).Msg("Failed to get asset watchers")
+		return recipients
+	}
+	for _, userID := range watcherIDs {
+		key := notificationService.RecipientTypeUser + ":" + userID
+		if !seen[key] {
+			recipients = append(recipients, notificationService.Recipient{
+				Type: notificationService.RecipientTypeUser,
+				ID:   userID,
+			})
+			seen[key] = true
+		}
+	}
+	return recipients
+}
+
 func (n *assetChangeNotifier) notifyLineageNeighborsOfSchemaChange(ctx context.Context, assetMRN, assetName string) {
 	// Notify downstream asset owners (they have an upstream schema change)
 	downstreamMRNs, err := n.lineageSvc.GetImmediateNeighbors(ctx, assetMRN, "downstream")
@@ -1267,80 +2051,809 @@ func (n *docsMentionNotifier) handleTeamMention(ctx context.Context, mention doc
 	}
 }
 
-// docsSearchSyncAdapter adapts IndexSyncService to docs.SearchObserver.
-type docsSearchSyncAdapter struct {
-	syncSvc  *searchService.IndexSyncService
-	assetSvc asset.Service
+// lineageRuleResolver adapts the lineage service to dataproduct.LineageResolver.
+type lineageRuleResolver struct {
+	lineageSvc lineageService.Service
 }
 
-func (a *docsSearchSyncAdapter) OnDocChanged(ctx context.Context, entityType docsService.EntityType, entityID string) {
-	switch entityType {
-	case docsService.EntityTypeAsset:
-		asst, err := a.assetSvc.GetByMRN(ctx, entityID)
-		if err != nil || asst == nil {
-			log.Warn().Err(err).Str("entity_id", entityID).Msg("Failed to resolve asset for search sync")
-			return
+func (a *lineageRuleResolver) ResolveLineageAssets(ctx context.Context, assetID string, direction string, depth int) ([]string, error) {
+	lineageDirection := direction
+	if direction == dataproductService.LineageDirectionBoth {
+		lineageDirection = ""
+	}
+
+	resp, err := a.lineageSvc.GetAssetLineage(ctx, assetID, depth, lineageDirection)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(resp.Nodes))
+	assetIDs := make([]string, 0, len(resp.Nodes))
+	for _, node := range resp.Nodes {
+		if node.Depth == 0 || node.Asset == nil || node.Asset.IsStub || node.Asset.ID == assetID {
+			continue
 		}
-		a.syncSvc.SyncAsset(ctx, asst.ID)
-	default:
-		a.syncSvc.OnEntityChanged(ctx, string(entityType), entityID)
+		if _, ok := seen[node.Asset.ID]; ok {
+			continue
+		}
+		seen[node.Asset.ID] = struct{}{}
+		assetIDs = append(assetIDs, node.Asset.ID)
 	}
+
+	return assetIDs, nil
 }
 
-// assetSearchSyncAdapter adapts IndexSyncService to asset.MembershipObserver.
-type assetSearchSyncAdapter struct {
-	syncSvc *searchService.IndexSyncService
+// searchLineageScope resolves lineage_of search parameters, which may name
+// the asset by ID or MRN.
+type searchLineageScope struct {
+	assetSvc asset.Service
+	lineage  *lineageRuleResolver
 }
 
-func (a *assetSearchSyncAdapter) OnAssetCreated(ctx context.Context, asst *asset.Asset) {
-	a.syncSvc.SyncAsset(ctx, asst.ID)
+func (a *searchLineageScope) ResolveLineageScope(ctx context.Context, assetRef, direction string, depth int) ([]string, error) {
+	var root *asset.Asset
+	var err error
+	if strings.HasPrefix(assetRef, "mrn://") {
+		root, err = a.assetSvc.GetByMRN(ctx, assetRef)
+	} else {
+		root, err = a.assetSvc.Get(ctx, assetRef)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a.lineage.ResolveLineageAssets(ctx, root.ID, direction, depth)
 }
 
-func (a *assetSearchSyncAdapter) OnAssetDeleted(ctx context.Context, assetID string) error {
-	a.syncSvc.DeleteAsset(ctx, assetID)
-	return nil
+// registerIncidentPollers enables status polling for each tracker with
+// credentials configured.
+func registerIncidentPollers(cfg *config.Config, svc *incidentService.Service) {
+	if jira := cfg.Incidents.Jira; jira.URL != "" {
+		svc.RegisterPoller(&incidentService.JiraPoller{BaseURL: jira.URL, Email: jira.Email, APIToken: jira.APIToken})
+	}
+	if gh := cfg.Incidents.GitHub; gh.Token != "" {
+		svc.RegisterPoller(&incidentService.GitHubPoller{BaseURL: gh.URL, Token: gh.Token})
+	}
+	if pd := cfg.Incidents.PagerDuty; pd.Token != "" {
+		svc.RegisterPoller(&incidentService.PagerDutyPoller{Token: pd.Token})
+	}
 }
 
-// assetNotificationSearchAdapter wraps NotificationObserver to also sync changes to ES.
-type assetNotificationSearchAdapter struct {
-	syncSvc  *searchService.IndexSyncService
-	delegate asset.NotificationObserver
+// archivalOwnerStore adapts the team service to archival.OwnerStore.
+type archivalOwnerStore struct {
+	teamSvc *teamService.Service
 }
 
-func (a *assetNotificationSearchAdapter) OnAssetUpdated(ctx context.Context, asst *asset.Asset, changeType string, changedFields []string) {
-	a.syncSvc.SyncAsset(ctx, asst.ID)
-	a.delegate.OnAssetUpdated(ctx, asst, changeType, changedFields)
+func (a *archivalOwnerStore) ListAssetOwners(ctx context.Context, assetID string) ([]archivalService.Owner, error) {
+	owners, err := a.teamSvc.ListAssetOwners(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]archivalService.Owner, 0, len(owners))
+	for _, o := range owners {
+		result = append(result, archivalService.Owner{Type: o.Type, ID: o.ID})
+	}
+	return result, nil
 }
 
-func (a *assetNotificationSearchAdapter) OnAssetDeleted(ctx context.Context, asst *asset.Asset) {
-	a.syncSvc.DeleteAsset(ctx, asst.ID)
-	a.delegate.OnAssetDeleted(ctx, asst)
+func (a *archivalOwnerStore) AddAssetOwner(ctx context.Context, assetID, ownerType, ownerID string) error {
+	return a.teamSvc.AddAssetOwner(ctx, assetID, ownerType, ownerID)
 }
 
-type responseWriter struct {
-	http.ResponseWriter
-	statusCode int
-	written    bool
+// archivalNotifier warns asset owners that an asset is about to be archived.
+type archivalNotifier struct {
+	notificationSvc *notificationService.Service
 }
 
-func (rw *responseWriter) WriteHeader(code int) {
-	if !rw.written {
-		rw.statusCode = code
-		rw.written = true
+func (a *archivalNotifier) NotifyPendingArchival(ctx context.Context, owners []archivalService.Owner, c *archivalService.Candidate) {
+	recipients := make([]notificationService.Recipient, 0, len(owners))
+	for _, o := range owners {
+		recipients = append(recipients, notificationService.Recipient{Type: o.Type, ID: o.ID})
 	}
-	rw.ResponseWriter.WriteHeader(code)
-}
 
-func (rw *responseWriter) Write(b []byte) (int, error) {
-	if !rw.written {
-		rw.written = true
+	err := a.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeAssetArchival,
+		Title:      "Asset scheduled for archival",
+		Message: fmt.Sprintf("%s has not been reported by its source since %s and will be archived on %s unless it is exempted.",
+			c.Name, c.LastSyncAt.Format("2006-01-02"), c.ArchiveAfter.Format("2006-01-02")),
+		Data: map[string]interface{}{
+			"asset_id":      c.AssetID,
+			"asset_mrn":     c.MRN,
+			"archive_after": c.ArchiveAfter,
+			"link":          fmt.Sprintf("/discover/%s", strings.TrimPrefix(c.MRN, "mrn://")),
+		},
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", c.AssetID).Msg("Failed to send archival notification")
 	}
-	return rw.ResponseWriter.Write(b)
 }
 
-func (s *Server) getMetricPath(routePath, actualPath string) string {
-	if strings.Contains(routePath, "{") {
-		return routePath
+// quotaNotifier warns admins that a quota is nearly or fully used.
+type quotaNotifier struct {
+	notificationSvc *notificationService.Service
+	userSvc         userService.Service
+	roleSvc         roleService.Service
+}
+
+func (n *quotaNotifier) NotifyQuota(ctx context.Context, usage quotaService.Usage, warnAt int) {
+	recipients, err := adminRecipients(ctx, n.userSvc, n.roleSvc)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to find admins for quota notification")
+		return
 	}
-	return actualPath
+	if len(recipients) == 0 {
+		return
+	}
+
+	subject := map[string]string{
+		quotaService.KindAssets: usage.Provider + " assets",
+		quotaService.KindStubs:  "Stub assets",
+		quotaService.KindRuns:   "Runs started in the last 24 hours",
+	}[usage.Kind]
+
+	title := fmt.Sprintf("%s are at %d%% of their quota", subject, warnAt)
+	message := fmt.Sprintf("%d of %d used. Ingestion continues until the limit is reached.", usage.Used, usage.Limit)
+	if usage.Status == quotaService.StatusExceeded {
+		title = fmt.Sprintf("%s have reached their quota", subject)
+		message = fmt.Sprintf("%d of %d used. New ones are rejected until usage drops or the limit is raised.", usage.Used, usage.Limit)
+	}
+
+	err = n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeQuota,
+		Title:      title,
+		Message:    message,
+		Data: map[string]interface{}{
+			"kind":     usage.Kind,
+			"provider": usage.Provider,
+			"used":     usage.Used,
+			"limit":    usage.Limit,
+			"status":   usage.Status,
+		},
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("kind", usage.Kind).Msg("Failed to send quota notification")
+	}
+}
+
+// vacancyNotifier alerts admins to assets and data products that have lost
+// every active owner.
+type vacancyNotifier struct {
+	notificationSvc *notificationService.Service
+	userSvc         userService.Service
+	roleSvc         roleService.Service
+}
+
+func (n *vacancyNotifier) NotifyVacancies(ctx context.Context, vacancies []ownervacancyService.Vacancy, reminder bool) {
+	recipients, err := adminRecipients(ctx, n.userSvc, n.roleSvc)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to find admins for ownership vacancy notification")
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	still := ""
+	if reminder {
+		still = "still "
+	}
+
+	var title, message string
+	data := map[string]interface{}{
+		"count":    len(vacancies),
+		"reminder": reminder,
+	}
+	if len(vacancies) == 1 {
+		v := vacancies[0]
+		owners := make([]string, 0, len(v.Owners))
+		for _, o := range v.Owners {
+			if o.Reason == ownervacancyService.ReasonDeactivated {
+				owners = append(owners, o.Name+" was deactivated")
+			} else {
+				owners = append(owners, o.Name+" has no active members")
+			}
+		}
+		title = fmt.Sprintf("%s %shas no active owner", v.Name, still)
+		message = fmt.Sprintf("%s. Assign a new owner so it isn't left unmaintained.", strings.Join(owners, ", "))
+		data["entity_type"] = v.EntityType
+		data["entity_id"] = v.EntityID
+		if v.EntityType == ownervacancyService.EntityAsset {
+			data["asset_mrn"] = v.MRN
+			data["link"] = fmt.Sprintf("/discover/%s", strings.TrimPrefix(v.MRN, "mrn://"))
+		} else {
+			data["link"] = fmt.Sprintf("/products/%s", v.EntityID)
+		}
+	} else {
+		names := make([]string, 0, 3)
+		for _, v := range vacancies[:min(len(vacancies), 3)] {
+			names = append(names, v.Name)
+		}
+		if rest := len(vacancies) - len(names); rest > 0 {
+			names = append(names, fmt.Sprintf("%d more", rest))
+		}
+		title = fmt.Sprintf("%d assets and data products %shave no active owner", len(vacancies), still)
+		message = fmt.Sprintf("Every owner of %s was deactivated or left their team. Assign new owners so they aren't left unmaintained.",
+			strings.Join(names, ", "))
+	}
+
+	err = n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeOwnershipVacancy,
+		Title:      title,
+		Message:    message,
+		Data:       data,
+	})
+	if err != nil {
+		log.Warn().Err(err).Int("count", len(vacancies)).Msg("Failed to send ownership vacancy notification")
+	}
+}
+
+// adminRecipients returns the active admins, who receive deployment-wide
+// alerts.
+func adminRecipients(ctx context.Context, userSvc userService.Service, roleSvc roleService.Service) ([]notificationService.Recipient, error) {
+	roles, err := roleSvc.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleIDs []string
+	for _, r := range roles {
+		if r.Name == authService.AdminRoleName {
+			roleIDs = append(roleIDs, r.ID)
+		}
+	}
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+
+	active := true
+	admins, _, err := userSvc.List(ctx, userService.Filter{RoleIDs: roleIDs, Active: &active, Limit: 100})
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]notificationService.Recipient, 0, len(admins))
+	for _, u := range admins {
+		recipients = append(recipients, notificationService.Recipient{Type: notificationService.RecipientTypeUser, ID: u.ID})
+	}
+	return recipients, nil
+}
+
+// newTagSyncers builds and starts a periodic syncer for each enabled tag
+// integration. Misconfigured integrations are logged and skipped.
+func newTagSyncers(cfg *config.Config, db *pgxpool.Pool, assetSvc asset.Service, policyTags tagsyncService.PolicyTagStore) []*tagsyncService.Syncer {
+	var services []*tagsyncService.Service
+
+	if sf := cfg.TagSync.Snowflake; sf != nil && sf.Enabled {
+		provider, err := tagsyncService.NewSnowflakeProvider(tagsyncService.SnowflakeConfig{
+			Account:        sf.Account,
+			Host:           sf.Host,
+			User:           sf.User,
+			Role:           sf.Role,
+			Warehouse:      sf.Warehouse,
+			PrivateKeyPath: sf.PrivateKeyPath,
+			OAuthToken:     sf.OAuthToken,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to init Snowflake tag sync - disabled")
+		} else if svc, err := tagsyncService.NewService(assetSvc, provider, tagSyncMappings(sf.Mappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid Snowflake tag sync mappings - disabled")
+		} else {
+			services = append(services, svc)
+		}
+	}
+
+	if bq := cfg.TagSync.BigQuery; bq != nil && bq.Enabled {
+		provider, err := tagsyncService.NewBigQueryProvider(context.Background(), tagsyncService.BigQueryConfig{
+			ProjectID:       bq.ProjectID,
+			CredentialsFile: bq.CredentialsFile,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to init BigQuery tag sync - disabled")
+		} else if svc, err := tagsyncService.NewService(assetSvc, provider, tagSyncMappings(bq.Mappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid BigQuery tag sync mappings - disabled")
+		} else if err := svc.SetColumnMappings(policyTags, policyTagSyncMappings(bq.PolicyTagMappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid BigQuery policy tag mappings - disabled")
+		} else {
+			services = append(services, svc)
+		}
+	}
+
+	if aws := cfg.TagSync.AWS; aws != nil && aws.Enabled {
+		provider, err := tagsyncService.NewAWSProvider(context.Background(), tagsyncService.AWSConfig{
+			Region:      aws.Region,
+			Endpoint:    aws.Endpoint,
+			MetadataKey: aws.MetadataKey,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to init AWS tag sync - disabled")
+		} else if svc, err := tagsyncService.NewService(assetSvc, provider, tagSyncMappings(aws.Mappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid AWS tag sync mappings - disabled")
+		} else {
+			services = append(services, svc)
+		}
+	}
+
+	if gcs := cfg.TagSync.GCS; gcs != nil && gcs.Enabled {
+		provider, err := tagsyncService.NewGCSProvider(context.Background(), tagsyncService.GCSConfig{
+			CredentialsFile: gcs.CredentialsFile,
+			MetadataKey:     gcs.MetadataKey,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to init GCS tag sync - disabled")
+		} else if svc, err := tagsyncService.NewService(assetSvc, provider, tagSyncMappings(gcs.Mappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid GCS tag sync mappings - disabled")
+		} else {
+			services = append(services, svc)
+		}
+	}
+
+	if k8s := cfg.TagSync.Kubernetes; k8s != nil && k8s.Enabled {
+		provider, err := tagsyncService.NewKubernetesProvider(tagsyncService.KubernetesConfig{
+			Kubeconfig:  k8s.Kubeconfig,
+			Context:     k8s.Context,
+			ClusterName: k8s.ClusterName,
+			MetadataKey: k8s.MetadataKey,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to init Kubernetes tag sync - disabled")
+		} else if svc, err := tagsyncService.NewService(assetSvc, provider, tagSyncMappings(k8s.Mappings)); err != nil {
+			log.Error().Err(err).Msg("Invalid Kubernetes tag sync mappings - disabled")
+		} else {
+			services = append(services, svc)
+		}
+	}
+
+	syncers := make([]*tagsyncService.Syncer, 0, len(services))
+	for _, svc := range services {
+		syncer := tagsyncService.NewSyncer(svc, &tagsyncService.SyncerConfig{
+			Interval: time.Duration(cfg.TagSync.Interval) * time.Second,
+			DB:       db,
+			DryRun:   cfg.TagSync.DryRun,
+		})
+		syncer.Start(context.Background())
+		syncers = append(syncers, syncer)
+		log.Info().
+			Str("provider", svc.Provider()).
+			Int("mappings", len(svc.Mappings())+len(svc.ColumnMappings())).
+			Bool("dry_run", cfg.TagSync.DryRun).
+			Msg("Tag sync enabled")
+	}
+
+	return syncers
+}
+
+// newSampler starts the object storage schema sampler for every provider
+// whose credentials load. It returns nil when neither does.
+func newSampler(cfg *config.Config, db *pgxpool.Pool) *samplingService.Sampler {
+	stores := map[string]samplingService.ObjectStore{}
+
+	if s3, err := samplingService.NewS3Store(context.Background(), cfg.Sampling.S3Endpoint); err != nil {
+		log.Warn().Err(err).Msg("Failed to init S3 schema sampling - S3 buckets will not be sampled")
+	} else {
+		stores["S3"] = s3
+	}
+	if gcs, err := samplingService.NewGCSStore(context.Background(), cfg.Sampling.GCSCredentialsFile); err != nil {
+		log.Warn().Err(err).Msg("Failed to init GCS schema sampling - GCS buckets will not be sampled")
+	} else {
+		stores["GCS"] = gcs
+	}
+	if len(stores) == 0 {
+		return nil
+	}
+
+	svc := samplingService.NewService(samplingService.NewPostgresRepository(db), stores, samplingService.Config{
+		MaxRows:       cfg.Sampling.MaxRows,
+		MaxBytes:      cfg.Sampling.MaxBytes,
+		MaxDatasets:   cfg.Sampling.MaxDatasets,
+		ResampleAfter: time.Duration(cfg.Sampling.ResampleAfter) * time.Second,
+	})
+	sampler := samplingService.NewSampler(svc, &samplingService.SamplerConfig{
+		Interval: time.Duration(cfg.Sampling.Interval) * time.Second,
+		DB:       db,
+	})
+	sampler.Start(context.Background())
+	log.Info().Int("providers", len(stores)).Msg("Object storage schema sampling enabled")
+	return sampler
+}
+
+func tagSyncMappings(mappings []config.TagSyncMapping) []tagsyncService.Mapping {
+	result := make([]tagsyncService.Mapping, len(mappings))
+	for i, m := range mappings {
+		result[i] = tagsyncService.Mapping{
+			Category:   m.Category,
+			RemoteTag:  m.Tag,
+			Direction:  m.Direction,
+			OnConflict: m.OnConflict,
+		}
+	}
+	return result
+}
+
+func policyTagSyncMappings(mappings []config.PolicyTagSyncMapping) []tagsyncService.ColumnMapping {
+	result := make([]tagsyncService.ColumnMapping, len(mappings))
+	for i, m := range mappings {
+		result[i] = tagsyncService.ColumnMapping{
+			PolicyTag:  m.PolicyTag,
+			RemoteTag:  m.Tag,
+			Direction:  m.Direction,
+			OnConflict: m.OnConflict,
+		}
+	}
+	return result
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	written    bool
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.written {
+		rw.statusCode = code
+		rw.written = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.written {
+		rw.written = true
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+func (s *Server) getMetricPath(routePath, actualPath string) string {
+	if strings.Contains(routePath, "{") {
+		return routePath
+	}
+	return actualPath
+}
+
+// savedSearchNotifier tells saved search subscribers about assets that
+// started matching.
+type savedSearchNotifier struct {
+	notificationSvc *notificationService.Service
+}
+
+func (n *savedSearchNotifier) NotifyNewMatches(ctx context.Context, ss *savedsearchService.SavedSearch, subscriberIDs []string, matches []savedsearchService.Match) {
+	if len(subscriberIDs) == 0 || len(matches) == 0 {
+		return
+	}
+
+	recipients := make([]notificationService.Recipient, len(subscriberIDs))
+	for i, id := range subscriberIDs {
+		recipients[i] = notificationService.Recipient{Type: notificationService.RecipientTypeUser, ID: id}
+	}
+
+	const maxListed = 5
+	names := make([]string, 0, maxListed)
+	assetIDs := make([]string, len(matches))
+	for i, m := range matches {
+		assetIDs[i] = m.AssetID
+		if i < maxListed {
+			names = append(names, m.Name)
+		}
+	}
+	message := strings.Join(names, ", ")
+	if len(matches) > maxListed {
+		message += fmt.Sprintf(" and %d more", len(matches)-maxListed)
+	}
+
+	// Titles are capped at 255 characters, as are search names.
+	name := ss.Name
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200]) + "..."
+	}
+	title := fmt.Sprintf("%d new assets match %q", len(matches), name)
+	if len(matches) == 1 {
+		title = fmt.Sprintf("A new asset matches %q", name)
+	}
+
+	data := map[string]interface{}{
+		"saved_search_id": ss.ID,
+		"link":            ss.URL,
+		"asset_ids":       assetIDs,
+	}
+	if len(matches) == 1 && matches[0].MRN != "" {
+		delete(data, "link")
+		data["asset_id"] = matches[0].AssetID
+		data["asset_mrn"] = matches[0].MRN
+	}
+
+	err := n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeSavedSearchMatch,
+		Title:      title,
+		Message:    message,
+		Data:       data,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("saved_search_id", ss.ID).Msg("Failed to send saved search notification")
+	}
+}
+
+// questionNotifier tells asset owners about new questions and askers about
+// new answers.
+type questionNotifier struct {
+	notificationSvc *notificationService.Service
+	teamSvc         *teamService.Service
+	assetSvc        asset.Service
+}
+
+func (n *questionNotifier) NotifyQuestionAsked(ctx context.Context, q *questionService.Question) {
+	owners, err := n.teamSvc.ListAssetOwners(ctx, q.AssetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", q.AssetID).Msg("Failed to get asset owners for question notification")
+		return
+	}
+
+	recipients := make([]notificationService.Recipient, 0, len(owners))
+	for _, o := range owners {
+		if o.Type == notificationService.RecipientTypeUser && q.CreatedBy != nil && o.ID == *q.CreatedBy {
+			continue
+		}
+		recipients = append(recipients, notificationService.Recipient{Type: o.Type, ID: o.ID})
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	name, data := n.assetContext(ctx, q)
+	err = n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeAssetQuestion,
+		Title:      fmt.Sprintf("New question on %s", name),
+		Message:    q.Title,
+		Data:       data,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("question_id", q.ID).Msg("Failed to send question notification")
+	}
+}
+
+func (n *questionNotifier) NotifyQuestionAnswered(ctx context.Context, q *questionService.Question, a *questionService.Answer) {
+	if q.CreatedBy == nil {
+		return
+	}
+
+	name, data := n.assetContext(ctx, q)
+	data["answer_id"] = a.ID
+	err := n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: []notificationService.Recipient{{Type: notificationService.RecipientTypeUser, ID: *q.CreatedBy}},
+		Type:       notificationService.TypeAssetQuestion,
+		Title:      fmt.Sprintf("Your question on %s was answered", name),
+		Message:    q.Title,
+		Data:       data,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("question_id", q.ID).Msg("Failed to send answer notification")
+	}
+}
+
+func (n *questionNotifier) assetContext(ctx context.Context, q *questionService.Question) (string, map[string]interface{}) {
+	name := q.AssetID
+	data := map[string]interface{}{
+		"asset_id":    q.AssetID,
+		"question_id": q.ID,
+	}
+
+	a, err := n.assetSvc.Get(ctx, q.AssetID)
+	if err != nil {
+		return name, data
+	}
+	if a.Name != nil {
+		name = *a.Name
+	}
+	if a.MRN != nil {
+		data["asset_mrn"] = *a.MRN
+		data["link"] = fmt.Sprintf("/discover/%s", strings.TrimPrefix(*a.MRN, "mrn://"))
+	}
+	return name, data
+}
+
+// ownershipRequestNotifier tells asset owners about new ownership and access
+// requests and requesters about decisions.
+type ownershipRequestNotifier struct {
+	notificationSvc *notificationService.Service
+	teamSvc         *teamService.Service
+}
+
+func (n *ownershipRequestNotifier) NotifyRequested(ctx context.Context, r *ownershipRequestService.Request) {
+	owners, err := n.teamSvc.ListAssetOwners(ctx, r.AssetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", r.AssetID).Msg("Failed to get asset owners for ownership request notification")
+		return
+	}
+
+	recipients := make([]notificationService.Recipient, 0, len(owners))
+	for _, o := range owners {
+		if o.Type == notificationService.RecipientTypeUser && o.ID == r.RequestedBy {
+			continue
+		}
+		recipients = append(recipients, notificationService.Recipient{Type: o.Type, ID: o.ID})
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("%s requested access to %s", ownershipRequester(r), r.AssetName)
+	if r.Kind == ownershipRequestService.KindOwnership {
+		title = fmt.Sprintf("%s requested ownership of %s", ownershipRequester(r), r.AssetName)
+	}
+	err = n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: recipients,
+		Type:       notificationService.TypeOwnershipRequest,
+		Title:      title,
+		Message:    r.Reason,
+		Data:       ownershipRequestData(r),
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("request_id", r.ID).Msg("Failed to send ownership request notification")
+	}
+}
+
+func (n *ownershipRequestNotifier) NotifyDecided(ctx context.Context, r *ownershipRequestService.Request) {
+	err := n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: []notificationService.Recipient{{Type: notificationService.RecipientTypeUser, ID: r.RequestedBy}},
+		Type:       notificationService.TypeOwnershipRequest,
+		Title:      fmt.Sprintf("Your %s request for %s was %s", r.Kind, r.AssetName, r.Status),
+		Message:    r.DecisionNote,
+		Data:       ownershipRequestData(r),
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("request_id", r.ID).Msg("Failed to send ownership decision notification")
+	}
+}
+
+func ownershipRequester(r *ownershipRequestService.Request) string {
+	name := r.RequesterName
+	if name == "" {
+		name = "Someone"
+	}
+	if r.TeamName != "" {
+		name = fmt.Sprintf("%s (for %s)", name, r.TeamName)
+	}
+	return name
+}
+
+func ownershipRequestData(r *ownershipRequestService.Request) map[string]interface{} {
+	return map[string]interface{}{
+		"asset_id":   r.AssetID,
+		"request_id": r.ID,
+		"kind":       string(r.Kind),
+		"status":     string(r.Status),
+	}
+}
+
+type mentionNotifier struct {
+	notificationSvc *notificationService.Service
+	userSvc         userService.Service
+	assetSvc        asset.Service
+}
+
+func (n *mentionNotifier) NotifyMentioned(ctx context.Context, source mentionService.Source, target mentionService.Target, actorID string) {
+	recipient := notificationService.Recipient{Type: notificationService.RecipientTypeUser, ID: target.ID}
+	if target.Type == mentionService.TargetTeam {
+		recipient.Type = notificationService.RecipientTypeTeam
+	}
+
+	mentioner := "Someone"
+	if actorID != "" {
+		if u, err := n.userSvc.Get(ctx, actorID); err == nil {
+			mentioner = u.Name
+		}
+	}
+
+	name := source.AssetID
+	data := map[string]interface{}{
+		"source_type": string(source.Type),
+		"source_id":   source.ID,
+		"asset_id":    source.AssetID,
+		"mentioner":   mentioner,
+	}
+	if a, err := n.assetSvc.Get(ctx, source.AssetID); err == nil {
+		if a.Name != nil {
+			name = *a.Name
+		}
+		if a.MRN != nil {
+			data["asset_mrn"] = *a.MRN
+			data["link"] = fmt.Sprintf("/discover/%s", strings.TrimPrefix(*a.MRN, "mrn://"))
+		}
+	}
+
+	where := "the description of " + name
+	switch source.Type {
+	case mentionService.SourceQuestion:
+		where = "a question on " + name
+	case mentionService.SourceAnswer:
+		where = "an answer on " + name
+	}
+
+	title := fmt.Sprintf("You were mentioned on %s", name)
+	message := fmt.Sprintf("%s mentioned you in %s.", mentioner, where)
+	if target.Type == mentionService.TargetTeam {
+		title = fmt.Sprintf("%s was mentioned on %s", target.Label, name)
+		message = fmt.Sprintf("%s mentioned your team in %s.", mentioner, where)
+	}
+
+	err := n.notificationSvc.Create(ctx, notificationService.CreateNotificationInput{
+		Recipients: []notificationService.Recipient{recipient},
+		Type:       notificationService.TypeMention,
+		Title:      title,
+		Message:    message,
+		Data:       data,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("target_type", string(target.Type)).Str("target_id", target.ID).Msg("Failed to send mention notification")
+	}
+}
+
+// pluginConnectionFields reads a provider's credential fields from its
+// plugin's config spec.
+type pluginConnectionFields struct{}
+
+func (pluginConnectionFields) SensitiveFields(provider string) (map[string]bool, error) {
+	entry, err := plugin.GetRegistry().Get(provider)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.GetSensitiveFields(entry.Meta.ConfigSpec), nil
+}
+
+// scheduleConnections adapts the connection service to runs.ConnectionResolver.
+type scheduleConnections struct {
+	svc *connectionService.Service
+}
+
+func (c *scheduleConnections) ResolveConnection(ctx context.Context, id string) (string, map[string]interface{}, error) {
+	conn, err := c.svc.Resolve(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return conn.Provider, conn.Config, nil
+}
+
+// scheduleServiceAccounts lets schedules run as service accounts.
+type scheduleServiceAccounts struct {
+	svc serviceaccountService.Service
+}
+
+func (s *scheduleServiceAccounts) ResolveServiceAccount(ctx context.Context, id string) (string, error) {
+	sa, err := s.svc.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, serviceaccountService.ErrNotFound) {
+			return "", runService.ErrServiceAccountUnavailable
+		}
+		return "", err
+	}
+	if !sa.Active {
+		return "", runService.ErrServiceAccountUnavailable
+	}
+	return authService.ServiceAccountSubject(sa.Name), nil
+}
+
+// newThumbnailService builds the asset thumbnail service. Capture is only
+// possible with a renderer configured, and only runs periodically when
+// enabled; uploads are always accepted.
+func newThumbnailService(cfg *config.Config, db *pgxpool.Pool) *thumbnailService.Service {
+	var renderer thumbnailService.Renderer
+	if cfg.Thumbnails.RendererURL != "" {
+		renderer = thumbnailService.NewHTTPRenderer(
+			cfg.Thumbnails.RendererURL,
+			cfg.Thumbnails.RendererToken,
+			time.Duration(cfg.Thumbnails.Timeout)*time.Second,
+		)
+	}
+
+	return thumbnailService.NewService(thumbnailService.NewPostgresRepository(db), renderer, thumbnailService.Config{
+		Width:        cfg.Thumbnails.Width,
+		Height:       cfg.Thumbnails.Height,
+		AssetTypes:   cfg.Thumbnails.AssetTypes,
+		RefreshAfter: time.Duration(cfg.Thumbnails.RefreshAfter) * time.Second,
+	})
 }
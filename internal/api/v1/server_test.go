@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type pathHandler string
+
+func (p pathHandler) Routes() []common.Route {
+	return []common.Route{{Path: string(p), Method: http.MethodGet, Handler: func(http.ResponseWriter, *http.Request) {}}}
+}
+
+func TestFeatureHandlersSkipDisabledFeatures(t *testing.T) {
+	built := map[string]bool{}
+	builder := func(path string) func() []routeHandler {
+		return func() []routeHandler {
+			built[path] = true
+			return []routeHandler{pathHandler(path)}
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Features.DataProducts = true
+	cfg.Features.AI = true
+
+	server := &Server{handlers: featureHandlers{
+		glossary:     builder("/api/v1/glossary/list"),
+		dataProducts: builder("/api/v1/products/list"),
+		scheduling:   builder("/api/v1/ingestion/schedules"),
+		ai:           builder("/api/v1/agents/runs"),
+	}.enabled(cfg)}
+
+	mux := http.NewServeMux()
+	server.RegisterRoutes(mux)
+
+	for path, enabled := range map[string]bool{
+		"/api/v1/glossary/list":       false,
+		"/api/v1/products/list":       true,
+		"/api/v1/ingestion/schedules": false,
+		"/api/v1/agents/runs":         true,
+	} {
+		assert.Equal(t, enabled, built[path], "handler for %s built", path)
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, enabled, pattern != "", "route %s registered", path)
+	}
+}
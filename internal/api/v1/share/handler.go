@@ -0,0 +1,97 @@
+package share
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/share"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *share.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+	// trustedProxies are the proxies whose X-Forwarded-For header is used
+	// for the recorded access IP.
+	trustedProxies []netip.Prefix
+}
+
+func NewHandler(
+	svc *share.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	var trusted []netip.Prefix
+	for _, proxy := range cfg.Server.TrustedProxies {
+		// Entries are checked when the config is loaded.
+		if prefix, err := config.ParseTrustedProxy(proxy); err == nil {
+			trusted = append(trusted, prefix)
+		}
+	}
+
+	return &Handler{
+		svc:            svc,
+		userService:    userService,
+		authService:    authService,
+		config:         cfg,
+		trustedProxies: trusted,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	// Anyone who can view an asset can share it. Revoking and listing
+	// accesses are checked per link by the service. Opening a link needs no account, only a valid token.
+	return []common.Route{
+		{
+			Path:    "/api/v1/share-links/assets/{assetId}",
+			Method:  http.MethodGet,
+			Handler: h.listLinks,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/share-links/assets/{assetId}",
+			Method:  http.MethodPost,
+			Handler: h.createLink,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+				common.WithRateLimit(h.config, 30, 60),
+			},
+		},
+		{
+			Path:    "/api/v1/share-links/{id}",
+			Method:  http.MethodDelete,
+			Handler: h.revokeLink,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/share-links/{id}/accesses",
+			Method:  http.MethodGet,
+			Handler: h.listAccesses,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "assets", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/shared/{token}",
+			Method:  http.MethodGet,
+			Handler: h.openLink,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithRateLimit(h.config, 60, 60),
+			},
+		},
+	}
+}
@@ -0,0 +1,251 @@
+package share
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/share"
+	"github.com/rs/zerolog/log"
+)
+
+type CreateLinkResponse struct {
+	share.CreatedLink
+	// URL opens the shared asset in the UI.
+	URL string `json:"url"`
+} // @name CreateShareLinkResponse
+
+type ListAccessesResponse struct {
+	Accesses []*share.Access `json:"accesses"`
+	Total    int             `json:"total"`
+} // @name ListShareLinkAccessesResponse
+
+// actor resolves the calling user. Users who can manage assets may revoke
+// and audit any link.
+func (h *Handler) actor(r *http.Request) (share.Actor, bool) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		return share.Actor{}, false
+	}
+	canManage, err := h.userService.HasPermission(r.Context(), usr.ID, "assets", "manage")
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", usr.ID).Msg("Failed to check asset manage permission")
+	}
+	return share.Actor{UserID: usr.ID, CanManage: canManage}, true
+}
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case share.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, share.ErrForbidden):
+		common.RespondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, share.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Share link not found")
+	case errors.Is(err, share.ErrInvalidLink):
+		common.RespondError(w, http.StatusNotFound, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// linkID returns the {id} path value in canonical form, or responds with 400
+// if it is not a UUID.
+func linkID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid share link ID")
+		return "", false
+	}
+	return id.String(), true
+}
+
+// clientIP returns the address of the caller. X-Forwarded-For is only read
+// when the connection comes from a trusted proxy, and then from the right,
+// taking the nearest hop that is not itself a trusted proxy. Anything to the
+// left of that was written by the client and can't be believed.
+func (h *Handler) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !h.trustedProxy(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !h.trustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (h *Handler) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// @Summary List share links for an asset
+// @Description Includes expired and revoked links so their access history stays visible
+// @Tags share-links
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {array} share.Link
+// @Failure 500 {object} common.ErrorResponse
+// @Router /share-links/assets/{assetId} [get]
+func (h *Handler) listLinks(w http.ResponseWriter, r *http.Request) {
+	links, err := h.svc.ListForAsset(r.Context(), r.PathValue("assetId"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to list share links")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, links)
+}
+
+// @Summary Create a share link
+// @Description Create a signed, expiring, read-only link to an asset for people without an account. The token is only returned once.
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param link body share.CreateInput true "Link options"
+// @Success 201 {object} CreateLinkResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /share-links/assets/{assetId} [post]
+func (h *Handler) createLink(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input share.CreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), r.PathValue("assetId"), input, actor.UserID)
+	if err != nil {
+		respondServiceError(w, err, "Failed to create share link")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, CreateLinkResponse{
+		CreatedLink: *created,
+		URL:         strings.TrimSuffix(h.config.Server.RootURL, "/") + "/share/" + created.Token,
+	})
+}
+
+// @Summary Revoke a share link
+// @Description Only the link's creator or users who can manage assets may revoke it
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Share link ID"
+// @Success 200 {object} share.Link
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /share-links/{id} [delete]
+func (h *Handler) revokeLink(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, ok := linkID(w, r)
+	if !ok {
+		return
+	}
+
+	link, err := h.svc.Revoke(r.Context(), id, actor)
+	if err != nil {
+		respondServiceError(w, err, "Failed to revoke share link")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, link)
+}
+
+// @Summary List share link accesses
+// @Description Only the link's creator or users who can manage assets may see who opened it
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Share link ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ListAccessesResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /share-links/{id}/accesses [get]
+func (h *Handler) listAccesses(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(r)
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	id, ok := linkID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	accesses, total, err := h.svc.ListAccesses(r.Context(), id, actor, limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list share link accesses")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ListAccessesResponse{Accesses: accesses, Total: total})
+}
+
+// @Summary Open a share link
+// @Description Public, read-only view of a shared asset. Unknown, expired and revoked links all return 404.
+// @Tags share-links
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} share.SharedAsset
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /shared/{token} [get]
+func (h *Handler) openLink(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.svc.Open(r.Context(), r.PathValue("token"), h.clientIP(r), r.UserAgent())
+	if err != nil {
+		respondServiceError(w, err, "Failed to open share link")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	common.RespondJSON(w, http.StatusOK, shared)
+}
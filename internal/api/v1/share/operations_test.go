@@ -0,0 +1,92 @@
+package share
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "no trusted proxies ignores X-Forwarded-For",
+			remoteAddr: "198.51.100.4:5123",
+			forwarded:  []string{"203.0.113.7"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "untrusted peer ignores X-Forwarded-For",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "198.51.100.4:5123",
+			forwarded:  []string{"203.0.113.7"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "trusted proxy uses the hop it added",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:5123",
+			forwarded:  []string{"203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed hops left of the client are ignored",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:5123",
+			forwarded:  []string{"192.0.2.1, 203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "chained trusted proxies are skipped",
+			trusted:    []string{"10.0.0.0/8", "172.16.0.9"},
+			remoteAddr: "10.1.2.3:5123",
+			forwarded:  []string{"192.0.2.1, 203.0.113.7", "172.16.0.9"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy without header",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:5123",
+			want:       "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Server.TrustedProxies = tt.trusted
+			h := NewHandler(nil, nil, nil, cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/shared/token", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			assert.Equal(t, tt.want, h.clientIP(req))
+		})
+	}
+}
+
+func TestLinkIDRejectsNonUUID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/share-links/abc/accesses", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	_, ok := linkID(rec, req)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req.SetPathValue("id", "7A1C2B3D-0000-4000-8000-000000000001")
+	id, ok := linkID(httptest.NewRecorder(), req)
+	assert.True(t, ok)
+	assert.Equal(t, "7a1c2b3d-0000-4000-8000-000000000001", id)
+}
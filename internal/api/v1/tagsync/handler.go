@@ -0,0 +1,55 @@
+package tagsync
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/tagsync"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+// Handler exposes configured warehouse tag syncs for inspection and manual runs.
+type Handler struct {
+	services    []*tagsync.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+func NewHandler(
+	services []*tagsync.Service,
+	userService user.Service,
+	authService auth.Service,
+	config *config.Config,
+) *Handler {
+	return &Handler{
+		services:    services,
+		userService: userService,
+		authService: authService,
+		config:      config,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	authMiddleware := []func(http.HandlerFunc) http.HandlerFunc{
+		common.WithAuth(h.userService, h.authService, h.config),
+		common.RequirePermission(h.userService, "users", "manage"),
+	}
+
+	return []common.Route{
+		{
+			Path:       "/api/v1/admin/tag-sync",
+			Method:     http.MethodGet,
+			Handler:    h.listTagSyncs,
+			Middleware: authMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/tag-sync/{provider}/run",
+			Method:     http.MethodPost,
+			Handler:    h.runTagSync,
+			Middleware: append(authMiddleware, common.WithRateLimit(h.config, 5, 60)),
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package tagsync
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/tagsync"
+	"github.com/rs/zerolog/log"
+)
+
+// manualSyncTimeout bounds a sync triggered through the API.
+const manualSyncTimeout = 30 * time.Minute
+
+type TagSyncConfigResponse struct {
+	Provider       string                  `json:"provider"`
+	Mappings       []tagsync.Mapping       `json:"mappings"`
+	ColumnMappings []tagsync.ColumnMapping `json:"column_mappings"`
+} // @name TagSyncConfigResponse
+
+// @Summary List tag syncs
+// @Description List the enabled warehouse tag syncs and their mappings.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} TagSyncConfigResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Router /admin/tag-sync [get]
+func (h *Handler) listTagSyncs(w http.ResponseWriter, r *http.Request) {
+	response := make([]TagSyncConfigResponse, 0, len(h.services))
+	for _, svc := range h.services {
+		response = append(response, TagSyncConfigResponse{
+			Provider:       svc.Provider(),
+			Mappings:       svc.Mappings(),
+			ColumnMappings: svc.ColumnMappings(),
+		})
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// @Summary Run tag sync
+// @Description Run a warehouse tag sync immediately and return a report of the changes. With dry_run=true nothing is written on either side and the report lists what would change, including how conflicts would be resolved.
+// @Tags admin
+// @Produce json
+// @Param provider path string true "Provider name, e.g. snowflake or bigquery"
+// @Param dry_run query bool false "Only report changes"
+// @Success 200 {object} tagsync.Report
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 403 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/tag-sync/{provider}/run [post]
+func (h *Handler) runTagSync(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	var svc *tagsync.Service
+	for _, s := range h.services {
+		if strings.EqualFold(s.Provider(), provider) {
+			svc = s
+			break
+		}
+	}
+	if svc == nil {
+		common.RespondError(w, http.StatusNotFound, "Tag sync is not enabled for this provider")
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	ctx, cancel := context.WithTimeout(r.Context(), manualSyncTimeout)
+	defer cancel()
+
+	report, err := svc.Sync(ctx, tagsync.SyncOptions{DryRun: dryRun})
+	if err != nil {
+		log.Error().Err(err).Str("provider", provider).Msg("Failed to run tag sync")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to run tag sync")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, report)
+}
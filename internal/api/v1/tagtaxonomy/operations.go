@@ -0,0 +1,315 @@
+package tagtaxonomy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/tagtaxonomy"
+	"github.com/rs/zerolog/log"
+)
+
+func authenticatedUserID(r *http.Request) *string {
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		return &usr.ID
+	}
+	return nil
+}
+
+// @Summary List tag categories
+// @Description List the categories of the tag taxonomy, e.g. pii for pii.email
+// @Tags tag-taxonomy
+// @Produce json
+// @Success 200 {array} tagtaxonomy.Category
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/categories [get]
+func (h *Handler) listCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.svc.ListCategories(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list tag categories")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list tag categories")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, categories)
+}
+
+// @Summary Get tag category
+// @Description Get a category of the tag taxonomy
+// @Tags tag-taxonomy
+// @Produce json
+// @Param name path string true "Category name, e.g. pii"
+// @Success 200 {object} tagtaxonomy.Category
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/categories/{name} [get]
+func (h *Handler) getCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.svc.GetCategory(r.Context(), r.PathValue("name"))
+	if err != nil {
+		if errors.Is(err, tagtaxonomy.ErrCategoryNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Tag category not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get tag category")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get tag category")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, category)
+}
+
+// @Summary Save tag category
+// @Description Create or replace a tag category. When a category is enforced, assets can only use its managed tags.
+// @Tags tag-taxonomy
+// @Accept json
+// @Produce json
+// @Param name path string true "Category name, e.g. pii"
+// @Param category body tagtaxonomy.CategoryInput true "Category"
+// @Success 200 {object} tagtaxonomy.Category
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/categories/{name} [put]
+func (h *Handler) putCategory(w http.ResponseWriter, r *http.Request) {
+	var input tagtaxonomy.CategoryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.svc.PutCategory(r.Context(), r.PathValue("name"), input, authenticatedUserID(r))
+	if err != nil {
+		if tagtaxonomy.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to save tag category")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to save tag category")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, category)
+}
+
+// @Summary Delete tag category
+// @Description Delete a tag category. Categories that still have tags can't be deleted.
+// @Tags tag-taxonomy
+// @Param name path string true "Category name, e.g. pii"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 409 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/categories/{name} [delete]
+func (h *Handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.DeleteCategory(r.Context(), r.PathValue("name")); err != nil {
+		switch {
+		case errors.Is(err, tagtaxonomy.ErrCategoryNotFound):
+			common.RespondError(w, http.StatusNotFound, "Tag category not found")
+		case errors.Is(err, tagtaxonomy.ErrCategoryInUse):
+			common.RespondError(w, http.StatusConflict, "Delete the category's tags first")
+		default:
+			log.Error().Err(err).Msg("Failed to delete tag category")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to delete tag category")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List managed tags
+// @Description List the approved tags of the tag taxonomy with how many assets use each
+// @Tags tag-taxonomy
+// @Produce json
+// @Param category query string false "Only list tags in this category"
+// @Success 200 {array} tagtaxonomy.Tag
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/tags [get]
+func (h *Handler) listTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.svc.ListTags(r.Context(), r.URL.Query().Get("category"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list managed tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list managed tags")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tags)
+}
+
+// @Summary Get managed tag
+// @Description Get an approved tag of the tag taxonomy
+// @Tags tag-taxonomy
+// @Produce json
+// @Param name path string true "Tag name, e.g. pii.email"
+// @Success 200 {object} tagtaxonomy.Tag
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/tags/{name} [get]
+func (h *Handler) getTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := h.svc.GetTag(r.Context(), r.PathValue("name"))
+	if err != nil {
+		if errors.Is(err, tagtaxonomy.ErrTagNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Managed tag not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get managed tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get managed tag")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tag)
+}
+
+// @Summary Save managed tag
+// @Description Create or replace an approved tag. A tag with a dot belongs to the category before the first dot, which must exist.
+// @Tags tag-taxonomy
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name, e.g. pii.email"
+// @Param tag body tagtaxonomy.TagInput true "Tag"
+// @Success 200 {object} tagtaxonomy.Tag
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/tags/{name} [put]
+func (h *Handler) putTag(w http.ResponseWriter, r *http.Request) {
+	var input tagtaxonomy.TagInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tag, err := h.svc.PutTag(r.Context(), r.PathValue("name"), input, authenticatedUserID(r))
+	if err != nil {
+		if tagtaxonomy.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to save managed tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to save managed tag")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tag)
+}
+
+// @Summary Delete managed tag
+// @Description Remove a tag from the tag taxonomy. Assets that use it keep it.
+// @Tags tag-taxonomy
+// @Param name path string true "Tag name, e.g. pii.email"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/tags/{name} [delete]
+func (h *Handler) deleteTag(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.DeleteTag(r.Context(), r.PathValue("name")); err != nil {
+		if errors.Is(err, tagtaxonomy.ErrTagNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Managed tag not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete managed tag")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to delete managed tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get tag taxonomy settings
+// @Description Get whether assets are restricted to managed tags
+// @Tags tag-taxonomy
+// @Produce json
+// @Success 200 {object} tagtaxonomy.Settings
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/settings [get]
+func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.svc.GetSettings(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag taxonomy settings")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get tag taxonomy settings")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, settings)
+}
+
+// @Summary Update tag taxonomy settings
+// @Description Turn enforcement of the tag taxonomy on or off. When enforced, tags added to assets must be managed tags.
+// @Tags tag-taxonomy
+// @Accept json
+// @Produce json
+// @Param settings body tagtaxonomy.SettingsInput true "Settings"
+// @Success 200 {object} tagtaxonomy.Settings
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/settings [put]
+func (h *Handler) updateSettings(w http.ResponseWriter, r *http.Request) {
+	var input tagtaxonomy.SettingsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.svc.UpdateSettings(r.Context(), input, authenticatedUserID(r))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update tag taxonomy settings")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to update tag taxonomy settings")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, settings)
+}
+
+// @Summary List unmanaged tags
+// @Description List the free-form tags on assets that aren't in the tag taxonomy, most used first, with the managed tag each probably means
+// @Tags tag-taxonomy
+// @Produce json
+// @Param limit query int false "Maximum number of tags" default(100)
+// @Success 200 {array} tagtaxonomy.UnmanagedTag
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/unmanaged [get]
+func (h *Handler) listUnmanaged(w http.ResponseWriter, r *http.Request) {
+	limit := common.ParseLimit(r.URL.Query().Get("limit"), 100, 1000)
+
+	tags, err := h.svc.ListUnmanaged(r.Context(), limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list unmanaged tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list unmanaged tags")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, tags)
+}
+
+// @Summary Migrate tags into the taxonomy
+// @Description Replace free-form tags on every asset with managed tags. A mapping with an empty to removes the tag. Use dry_run to see how many assets each mapping would change.
+// @Tags tag-taxonomy
+// @Accept json
+// @Produce json
+// @Param migration body tagtaxonomy.MigrationInput true "Tag mappings"
+// @Success 200 {object} tagtaxonomy.MigrationResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /tag-taxonomy/migrate [post]
+func (h *Handler) migrate(w http.ResponseWriter, r *http.Request) {
+	var input tagtaxonomy.MigrationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Migrate(r.Context(), input)
+	if err != nil {
+		if tagtaxonomy.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to migrate tags")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to migrate tags")
+		return
+	}
+
+	if !result.DryRun {
+		log.Info().Int("mappings", len(result.Mappings)).Msg("Migrated tags into the tag taxonomy")
+	}
+	common.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,169 @@
+package thumbnails
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/imageproc"
+	"github.com/marmotdata/marmot/internal/core/thumbnail"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Get asset thumbnail
+// @Description Serve the asset's thumbnail image, e.g. a dashboard screenshot, as a JPEG
+// @Tags thumbnails
+// @Produce image/jpeg
+// @Param id path string true "Asset ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /thumbnails/assets/{id} [get]
+func (h *Handler) getThumbnail(w http.ResponseWriter, r *http.Request) {
+	assetID := r.PathValue("id")
+
+	t, err := h.svc.Get(r.Context(), assetID)
+	if err != nil {
+		if errors.Is(err, thumbnail.ErrNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Thumbnail not found")
+			return
+		}
+		log.Error().Err(err).Str("asset_id", assetID).Msg("Failed to get thumbnail")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to get thumbnail")
+		return
+	}
+
+	etag := fmt.Sprintf(`"%d"`, t.CapturedAt.UnixNano())
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", t.ContentType)
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(t.Data) //nolint:gosec // G705: thumbnails are re-encoded JPEGs, served with CSP default-src 'none' and nosniff
+}
+
+// @Summary Upload asset thumbnail
+// @Description Upload an image as the asset's thumbnail. It is scaled down and re-encoded as a JPEG, and is never replaced by automatic capture.
+// @Tags thumbnails
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param file formData file true "Image file (JPEG, PNG, GIF or WebP)"
+// @Success 200 {object} thumbnail.Thumbnail
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /thumbnails/assets/{id} [put]
+func (h *Handler) uploadThumbnail(w http.ResponseWriter, r *http.Request) {
+	assetID := r.PathValue("id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, thumbnail.MaxUploadBytes+1<<20)
+	if err := r.ParseMultipartForm(thumbnail.MaxUploadBytes + 1<<20); err != nil { //nolint:gosec // G120: body size limited by MaxBytesReader above
+		common.RespondError(w, http.StatusBadRequest, "Failed to parse form: "+err.Error())
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		common.RespondError(w, http.StatusBadRequest, "No file provided")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read uploaded file")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to read file")
+		return
+	}
+
+	var createdBy *string
+	if usr, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = &usr.ID
+	}
+
+	t, err := h.svc.Upload(r.Context(), assetID, data, createdBy)
+	if err != nil {
+		h.respondThumbnailError(w, err, assetID, "Failed to upload thumbnail")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, t)
+}
+
+// @Summary Capture asset thumbnail
+// @Description Take a screenshot of the asset's URL now and store it as its thumbnail, replacing any existing one. The URL is the asset's dashboard_url or url metadata, else its first external link.
+// @Tags thumbnails
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Success 200 {object} thumbnail.Thumbnail
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 502 {object} common.ErrorResponse
+// @Failure 503 {object} common.ErrorResponse
+// @Router /thumbnails/assets/{id}/capture [post]
+func (h *Handler) captureThumbnail(w http.ResponseWriter, r *http.Request) {
+	assetID := r.PathValue("id")
+
+	t, err := h.svc.Capture(r.Context(), assetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, thumbnail.ErrCaptureDisabled):
+			common.RespondError(w, http.StatusServiceUnavailable, err.Error())
+		case errors.Is(err, thumbnail.ErrNoURL):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, asset.ErrAssetNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Warn().Err(err).Str("asset_id", assetID).Msg("Failed to capture thumbnail")
+			common.RespondError(w, http.StatusBadGateway, "Failed to capture thumbnail")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, t)
+}
+
+// @Summary Delete asset thumbnail
+// @Description Delete the asset's thumbnail. A captured thumbnail is captured again on the next run.
+// @Tags thumbnails
+// @Param id path string true "Asset ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /thumbnails/assets/{id} [delete]
+func (h *Handler) deleteThumbnail(w http.ResponseWriter, r *http.Request) {
+	assetID := r.PathValue("id")
+
+	if err := h.svc.Delete(r.Context(), assetID); err != nil {
+		h.respondThumbnailError(w, err, assetID, "Failed to delete thumbnail")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondThumbnailError(w http.ResponseWriter, err error, assetID, msg string) {
+	switch {
+	case errors.Is(err, thumbnail.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Thumbnail not found")
+	case errors.Is(err, asset.ErrAssetNotFound):
+		common.RespondError(w, http.StatusNotFound, "Asset not found")
+	case errors.Is(err, thumbnail.ErrTooLarge),
+		errors.Is(err, imageproc.ErrUnsupportedFormat),
+		errors.Is(err, imageproc.ErrDecodeFailed),
+		errors.Is(err, imageproc.ErrDimensionsTooLarge):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Error().Err(err).Str("asset_id", assetID).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,59 @@
+package timeline
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/timeline"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Get asset timeline
+// @Description Returns everything recorded about an asset as one feed, newest first: schema changes, metadata revisions, ownership changes, finished runs, questions and answers, and deprecation and incident announcements. Filter with types, given comma-separated or repeated.
+// @Tags assets
+// @Produce json
+// @Param id path string true "Asset ID"
+// @Param types query []string false "Event types (schema_change, metadata_change, ownership, run, comment, announcement)" collectionFormat(csv)
+// @Param limit query int false "Number of events per page" default(50)
+// @Param offset query int false "Number of events to skip" default(0)
+// @Success 200 {object} timeline.Timeline
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /assets/timeline/{id} [get]
+func (h *Handler) getTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var types []timeline.EventType
+	for _, value := range query["types"] {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, timeline.EventType(t))
+			}
+		}
+	}
+
+	filter := timeline.Filter{
+		Types:  types,
+		Limit:  common.ParseLimit(query.Get("limit"), timeline.DefaultLimit, timeline.MaxLimit),
+		Offset: common.ParseOffset(query.Get("offset")),
+	}
+
+	page, err := h.svc.List(r.Context(), r.PathValue("id"), filter)
+	if err != nil {
+		switch {
+		case timeline.IsValidationError(err):
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, timeline.ErrNotFound):
+			common.RespondError(w, http.StatusNotFound, "Asset not found")
+		default:
+			log.Error().Err(err).Str("asset_id", r.PathValue("id")).Msg("Failed to get asset timeline")
+			common.RespondError(w, http.StatusInternalServerError, "Failed to get asset timeline")
+		}
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, page)
+}
@@ -1,19 +1,27 @@
 package ui
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
 	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/rs/zerolog/log"
 )
 
 type Handler struct {
+	userService          user.Service
+	authService          auth.Service
 	config               *config.Config
 	encryptionConfigured bool
 }
 
-func NewHandler(config *config.Config, encryptionConfigured bool) *Handler {
+func NewHandler(userService user.Service, authService auth.Service, config *config.Config, encryptionConfigured bool) *Handler {
 	return &Handler{
+		userService:          userService,
+		authService:          authService,
 		config:               config,
 		encryptionConfigured: encryptionConfigured,
 	}
@@ -26,6 +34,14 @@ func (h *Handler) Routes() []common.Route {
 			Method:  http.MethodGet,
 			Handler: h.getUIConfig,
 		},
+		{
+			Path:    "/api/v1/ui/features",
+			Method:  http.MethodGet,
+			Handler: h.getFeatures,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+			},
+		},
 	}
 }
 
@@ -66,3 +82,65 @@ func (h *Handler) getUIConfig(w http.ResponseWriter, r *http.Request) {
 
 	common.RespondJSON(w, http.StatusOK, response)
 }
+
+// Feature reports whether a subsystem is switched on for the deployment and
+// what the current user's roles allow them to do with it.
+type Feature struct {
+	Enabled   bool `json:"enabled"`
+	CanView   bool `json:"can_view"`
+	CanManage bool `json:"can_manage"`
+} // @name UIFeature
+
+type FeaturesResponse struct {
+	Glossary     Feature `json:"glossary"`
+	DataProducts Feature `json:"data_products"`
+	Scheduling   Feature `json:"scheduling"`
+	// AI covers the MCP server and agent run reporting. CanManage means the
+	// user may record agent runs.
+	AI Feature `json:"ai"`
+} // @name UIFeaturesResponse
+
+// @Summary Get enabled features
+// @Description Which catalog features are enabled by server config and permitted for the current user by their roles
+// @Tags ui
+// @Produce json
+// @Success 200 {object} FeaturesResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Router /ui/features [get]
+func (h *Handler) getFeatures(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	features := h.config.Features
+	response := FeaturesResponse{
+		Glossary:     h.feature(r.Context(), usr.ID, features.Glossary, "glossary", "view", "glossary", "manage"),
+		DataProducts: h.feature(r.Context(), usr.ID, features.DataProducts, "assets", "view", "assets", "manage"),
+		Scheduling:   h.feature(r.Context(), usr.ID, features.Scheduling, "ingestion", "view", "ingestion", "manage"),
+		AI:           h.feature(r.Context(), usr.ID, features.AI, "assets", "view", "agents", "emit"),
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) feature(ctx context.Context, userID string, enabled bool, viewResource, viewAction, manageResource, manageAction string) Feature {
+	if !enabled {
+		return Feature{}
+	}
+	return Feature{
+		Enabled:   true,
+		CanView:   h.hasPermission(ctx, userID, viewResource, viewAction),
+		CanManage: h.hasPermission(ctx, userID, manageResource, manageAction),
+	}
+}
+
+func (h *Handler) hasPermission(ctx context.Context, userID, resource, action string) bool {
+	ok, err := h.userService.HasPermission(ctx, userID, resource, action)
+	if err != nil {
+		log.Warn().Err(err).Str("resource", resource).Str("action", action).Msg("Failed to check permission for UI features")
+		return false
+	}
+	return ok
+}
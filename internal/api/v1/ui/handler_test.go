@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permissionUsers grants the listed "resource:action" permissions to every
+// user.
+type permissionUsers struct {
+	user.Service
+	granted map[string]bool
+}
+
+func (s *permissionUsers) HasPermission(_ context.Context, _ string, resourceType, action string) (bool, error) {
+	return s.granted[resourceType+":"+action], nil
+}
+
+func TestGetFeatures(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Features.DataProducts = true
+	cfg.Features.Scheduling = true
+
+	h := &Handler{
+		userService: &permissionUsers{granted: map[string]bool{
+			"glossary:view": true, "glossary:manage": true,
+			"assets:view": true, "ingestion:view": true,
+		}},
+		config: cfg,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ui/features", nil)
+	req = req.WithContext(context.WithValue(req.Context(), common.UserContextKey, &user.User{ID: "user-1"}))
+	w := httptest.NewRecorder()
+	h.getFeatures(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp FeaturesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// A disabled feature reports nothing, whatever the user's roles allow.
+	assert.Equal(t, Feature{}, resp.Glossary)
+	assert.Equal(t, Feature{}, resp.AI)
+	assert.Equal(t, Feature{Enabled: true, CanView: true}, resp.DataProducts)
+	assert.Equal(t, Feature{Enabled: true, CanView: true}, resp.Scheduling)
+}
+
+func TestGetFeaturesRequiresUser(t *testing.T) {
+	h := &Handler{config: &config.Config{}}
+
+	w := httptest.NewRecorder()
+	h.getFeatures(w, httptest.NewRequest(http.MethodGet, "/api/v1/ui/features", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
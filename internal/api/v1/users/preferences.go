@@ -83,6 +83,12 @@ func (h *Handler) updatePreferences(w http.ResponseWriter, r *http.Request) {
 			notification.TypeDownstreamSchemaChange: true,
 			notification.TypeLineageChange:          true,
 			notification.TypeAssetDeleted:           true,
+			notification.TypeAssetArchival:          true,
+			notification.TypeAssetQuestion:          true,
+			notification.TypeSavedSearchMatch:       true,
+			notification.TypeOwnershipRequest:       true,
+			notification.TypeQuota:                  true,
+			notification.TypeOwnershipVacancy:       true,
 		}
 		for key, val := range notifPrefs {
 			if !validTypes[key] {
@@ -0,0 +1,58 @@
+package watch
+
+import (
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/internal/core/watch"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+type Handler struct {
+	svc         *watch.Service
+	userService user.Service
+	authService auth.Service
+	config      *config.Config
+}
+
+// NewHandler creates the watch folder handler. svc is nil when watch folders
+// are disabled.
+func NewHandler(
+	svc *watch.Service,
+	userService user.Service,
+	authService auth.Service,
+	cfg *config.Config,
+) *Handler {
+	return &Handler{
+		svc:         svc,
+		userService: userService,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+func (h *Handler) Routes() []common.Route {
+	return []common.Route{
+		{
+			Path:    "/api/v1/ingestion/watch",
+			Method:  http.MethodGet,
+			Handler: h.listDrops,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "view"),
+			},
+		},
+		{
+			Path:    "/api/v1/ingestion/watch/scan",
+			Method:  http.MethodPost,
+			Handler: h.scan,
+			Middleware: []func(http.HandlerFunc) http.HandlerFunc{
+				common.WithAuth(h.userService, h.authService, h.config),
+				common.RequirePermission(h.userService, "ingestion", "manage"),
+				common.WithRateLimit(h.config, 10, 60),
+			},
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/watch"
+	"github.com/rs/zerolog/log"
+)
+
+type DropsResponse struct {
+	Folders []watch.Folder      `json:"folders"`
+	Drops   []*watch.DropStatus `json:"drops"`
+	Total   int                 `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+} // @name WatchFolderDropsResponse
+
+// @Summary List watch folder drops
+// @Description List the watched folders and the dbt, OpenAPI and CSV drops picked up from them, with their ingestion status
+// @Tags ingestion
+// @Produce json
+// @Param folder query string false "Only list drops from this folder"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} DropsResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/watch [get]
+func (h *Handler) listDrops(w http.ResponseWriter, r *http.Request) {
+	if h.svc == nil {
+		common.RespondError(w, http.StatusNotFound, "Watch folders are not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	drops, total, err := h.svc.ListDrops(r.Context(), query.Get("folder"), limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list watch folder drops")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list watch folder drops")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, DropsResponse{
+		Folders: h.svc.Folders(),
+		Drops:   drops,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// @Summary Scan watch folders
+// @Description Check the watch folders for new or changed drops now instead of waiting for the next scheduled scan
+// @Tags ingestion
+// @Produce json
+// @Param folder query string false "Only scan this folder"
+// @Success 200 {object} watch.ScanResult
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingestion/watch/scan [post]
+func (h *Handler) scan(w http.ResponseWriter, r *http.Request) {
+	if h.svc == nil {
+		common.RespondError(w, http.StatusNotFound, "Watch folders are not enabled")
+		return
+	}
+
+	result, err := h.svc.Scan(r.Context(), r.URL.Query().Get("folder"))
+	if err != nil {
+		if errors.Is(err, watch.ErrFolderNotFound) {
+			common.RespondError(w, http.StatusNotFound, "Watch folder not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to scan watch folders")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to scan watch folders")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
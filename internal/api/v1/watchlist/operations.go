@@ -0,0 +1,137 @@
+package watchlist
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/watchlist"
+	"github.com/rs/zerolog/log"
+)
+
+func respondServiceError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case watchlist.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, watchlist.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Watch not found")
+	case errors.Is(err, watchlist.ErrEntityNotFound):
+		common.RespondError(w, http.StatusNotFound, "Entity not found")
+	default:
+		log.Error().Err(err).Msg(msg)
+		common.RespondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// @Summary List watched entities
+// @Description List the assets, data products and glossary terms the current user watches, most recently watched first
+// @Tags watches
+// @Produce json
+// @Param entity_type query string false "Only list this kind of entity" Enums(asset, data_product, glossary_term)
+// @Param limit query int false "Maximum number of watches to return" default(50)
+// @Param offset query int false "Number of watches to skip" default(0)
+// @Success 200 {object} watchlist.ListResult
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /watches [get]
+func (h *Handler) listWatches(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := common.ParseLimit(query.Get("limit"), 50, 500)
+	offset := common.ParseOffset(query.Get("offset"))
+
+	result, err := h.svc.List(r.Context(), usr.ID, query.Get("entity_type"), limit, offset)
+	if err != nil {
+		respondServiceError(w, err, "Failed to list watches")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Get watch status
+// @Description Get whether the current user watches an entity and how many users watch it
+// @Tags watches
+// @Produce json
+// @Param entity_type path string true "Entity type" Enums(asset, data_product, glossary_term)
+// @Param id path string true "Entity ID"
+// @Success 200 {object} watchlist.Status
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /watches/{entity_type}/{id} [get]
+func (h *Handler) getWatchStatus(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.svc.Status(r.Context(), usr.ID, r.PathValue("entity_type"), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to get watch status")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, status)
+}
+
+// @Summary Watch an entity
+// @Description Watch an asset, data product or glossary term. Watchers are notified when it changes.
+// @Tags watches
+// @Produce json
+// @Param entity_type path string true "Entity type" Enums(asset, data_product, glossary_term)
+// @Param id path string true "Entity ID"
+// @Success 200 {object} watchlist.Watch
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /watches/{entity_type}/{id} [put]
+func (h *Handler) watch(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	watch, err := h.svc.Watch(r.Context(), usr.ID, r.PathValue("entity_type"), r.PathValue("id"))
+	if err != nil {
+		respondServiceError(w, err, "Failed to watch entity")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, watch)
+}
+
+// @Summary Unwatch an entity
+// @Description Stop watching an asset, data product or glossary term
+// @Tags watches
+// @Param entity_type path string true "Entity type" Enums(asset, data_product, glossary_term)
+// @Param id path string true "Entity ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /watches/{entity_type}/{id} [delete]
+func (h *Handler) unwatch(w http.ResponseWriter, r *http.Request) {
+	usr, ok := common.GetAuthenticatedUser(r.Context())
+	if !ok {
+		common.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.svc.Unwatch(r.Context(), usr.ID, r.PathValue("entity_type"), r.PathValue("id")); err != nil {
+		respondServiceError(w, err, "Failed to unwatch entity")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
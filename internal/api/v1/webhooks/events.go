@@ -0,0 +1,359 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/api/v1/common"
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/marmotdata/marmot/internal/core/webhook"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/rs/zerolog/log"
+)
+
+// EventHandler handles admin event webhook API requests.
+type EventHandler struct {
+	eventService         *webhook.EventService
+	userService          user.Service
+	authService          auth.Service
+	config               *config.Config
+	encryptionConfigured bool
+}
+
+type ListEventWebhooksResponse struct {
+	Webhooks []*webhook.EventWebhook `json:"webhooks"`
+	Events   []string                `json:"events"`
+} // @name ListEventWebhooksResponse
+
+type ListDeliveriesResponse struct {
+	Deliveries []*webhook.Delivery `json:"deliveries"`
+	Total      int                 `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+} // @name ListWebhookDeliveriesResponse
+
+// NewEventHandler creates a new event webhook handler.
+func NewEventHandler(eventService *webhook.EventService, userService user.Service, authService auth.Service, cfg *config.Config, encryptionConfigured bool) *EventHandler {
+	return &EventHandler{
+		eventService:         eventService,
+		userService:          userService,
+		authService:          authService,
+		config:               cfg,
+		encryptionConfigured: encryptionConfigured,
+	}
+}
+
+// Routes returns the event webhook routes.
+func (h *EventHandler) Routes() []common.Route {
+	adminMiddleware := []func(http.HandlerFunc) http.HandlerFunc{
+		common.WithAuth(h.userService, h.authService, h.config),
+		common.RequirePermission(h.userService, "users", "manage"),
+	}
+	encryptedMiddleware := append(adminMiddleware[:len(adminMiddleware):len(adminMiddleware)],
+		common.RequireEncryption(h.encryptionConfigured))
+
+	return []common.Route{
+		{
+			Path:       "/api/v1/admin/webhooks",
+			Method:     http.MethodGet,
+			Handler:    h.listEventWebhooks,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks",
+			Method:     http.MethodPost,
+			Handler:    h.createEventWebhook,
+			Middleware: encryptedMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}",
+			Method:     http.MethodGet,
+			Handler:    h.getEventWebhook,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}",
+			Method:     http.MethodPut,
+			Handler:    h.updateEventWebhook,
+			Middleware: encryptedMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}",
+			Method:     http.MethodDelete,
+			Handler:    h.deleteEventWebhook,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}/ping",
+			Method:     http.MethodPost,
+			Handler:    h.pingEventWebhook,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}/deliveries",
+			Method:     http.MethodGet,
+			Handler:    h.listDeliveries,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}/deliveries/{deliveryId}",
+			Method:     http.MethodGet,
+			Handler:    h.getDelivery,
+			Middleware: adminMiddleware,
+		},
+		{
+			Path:       "/api/v1/admin/webhooks/{id}/deliveries/{deliveryId}/redeliver",
+			Method:     http.MethodPost,
+			Handler:    h.redeliver,
+			Middleware: adminMiddleware,
+		},
+	}
+}
+
+// @Summary List event webhooks
+// @Description List the webhooks that receive catalog events, and the events they can subscribe to. Secrets are not returned.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} ListEventWebhooksResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks [get]
+func (h *EventHandler) listEventWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.eventService.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list event webhooks")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to list webhooks")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ListEventWebhooksResponse{
+		Webhooks: webhooks,
+		Events:   webhook.Events,
+	})
+}
+
+// @Summary Create an event webhook
+// @Description Register a URL to receive catalog events, signed with a secret. A secret is generated when none is given; it is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body webhook.CreateEventWebhookInput true "Webhook to create"
+// @Success 201 {object} webhook.EventWebhook
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks [post]
+func (h *EventHandler) createEventWebhook(w http.ResponseWriter, r *http.Request) {
+	var input webhook.CreateEventWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	createdBy := ""
+	if u, ok := common.GetAuthenticatedUser(r.Context()); ok {
+		createdBy = u.Username
+	}
+
+	result, err := h.eventService.Create(r.Context(), input, createdBy)
+	if err != nil {
+		if webhook.IsValidationError(err) {
+			common.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to create event webhook")
+		common.RespondError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, result)
+}
+
+// @Summary Get an event webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} webhook.EventWebhook
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id} [get]
+func (h *EventHandler) getEventWebhook(w http.ResponseWriter, r *http.Request) {
+	result, err := h.eventService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.respondError(w, err, "Failed to get webhook")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Update an event webhook
+// @Description Change a webhook. Set rotate_secret to replace its secret with a generated one; a new secret is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body webhook.UpdateEventWebhookInput true "Fields to change"
+// @Success 200 {object} webhook.EventWebhook
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id} [put]
+func (h *EventHandler) updateEventWebhook(w http.ResponseWriter, r *http.Request) {
+	var input webhook.UpdateEventWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		common.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.eventService.Update(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		h.respondError(w, err, "Failed to update webhook")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, result)
+}
+
+// @Summary Delete an event webhook
+// @Description Delete a webhook and its delivery history.
+// @Tags webhooks
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id} [delete]
+func (h *EventHandler) deleteEventWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.eventService.Delete(r.Context(), r.PathValue("id")); err != nil {
+		h.respondError(w, err, "Failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Send a test event
+// @Description Send a ping event to a webhook, even a disabled one. The returned delivery is pending; fetch it to see the outcome.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 202 {object} webhook.Delivery
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id}/ping [post]
+func (h *EventHandler) pingEventWebhook(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.eventService.Ping(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.respondError(w, err, "Failed to send test event")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusAccepted, delivery)
+}
+
+// @Summary List webhook deliveries
+// @Description List the events sent to a webhook, most recent first, with the outcome of their latest attempt.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Filter by status" Enums(pending, succeeded, failed)
+// @Param event query string false "Filter by event type"
+// @Param limit query int false "Maximum deliveries to return" default(50) maximum(100)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} ListDeliveriesResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *EventHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := webhook.DeliveryFilter{
+		Status:    query.Get("status"),
+		EventType: query.Get("event"),
+	}
+	switch filter.Status {
+	case "", webhook.DeliveryPending, webhook.DeliverySucceeded, webhook.DeliveryFailed:
+	default:
+		common.RespondError(w, http.StatusBadRequest, "status must be pending, succeeded or failed")
+		return
+	}
+	for name, dst := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			common.RespondError(w, http.StatusBadRequest, name+" must be an integer")
+			return
+		}
+		*dst = n
+	}
+
+	deliveries, total, err := h.eventService.ListDeliveries(r.Context(), r.PathValue("id"), filter)
+	if err != nil {
+		h.respondError(w, err, "Failed to list deliveries")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, ListDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	})
+}
+
+// @Summary Get a webhook delivery
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} webhook.Delivery
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *EventHandler) getDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.eventService.GetDelivery(r.Context(), r.PathValue("id"), r.PathValue("deliveryId"))
+	if err != nil {
+		h.respondError(w, err, "Failed to get delivery")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, delivery)
+}
+
+// @Summary Redeliver a webhook event
+// @Description Send the event of a previous delivery again, as a new delivery with the same event ID.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} webhook.Delivery
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *EventHandler) redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.eventService.Redeliver(r.Context(), r.PathValue("id"), r.PathValue("deliveryId"))
+	if err != nil {
+		h.respondError(w, err, "Failed to redeliver event")
+		return
+	}
+
+	common.RespondJSON(w, http.StatusAccepted, delivery)
+}
+
+func (h *EventHandler) respondError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		common.RespondError(w, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		common.RespondError(w, http.StatusNotFound, "Delivery not found")
+	case webhook.IsValidationError(err):
+		common.RespondError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Error().Err(err).Msg(message)
+		common.RespondError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/cmd/output"
+	"github.com/marmotdata/marmot/internal/core/backup"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	restoreCmd.Flags().Bool("force", false, "Restore into a catalog that already holds data, skipping rows that exist")
+	restoreCmd.Flags().Bool("dry-run", false, "Validate and load the archive, then roll back")
+
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [file]",
+	Short: "Export the catalog to a backup archive",
+	Long: `Export assets, lineage, glossary, teams, users, data products and schedules
+to a versioned archive. The archive is independent of the PostgreSQL version and
+can be restored into the same or a newer Marmot release with 'marmot restore'.
+
+Connects directly to the database configured by --config or MARMOT_DATABASE_*
+environment variables. Pass - as the file to write the archive to stdout.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := fmt.Sprintf("marmot-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		db, err := connectDatabase(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var manifest *backup.Manifest
+		if path == "-" {
+			manifest, err = backup.Export(cmd.Context(), db, os.Stdout, Version)
+			if err != nil {
+				return err
+			}
+		} else {
+			manifest, err = writeBackup(cmd, db, path)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Keep stdout for the archive when streaming it.
+		out := io.Writer(os.Stdout)
+		if path == "-" {
+			out = os.Stderr
+		}
+		p := output.NewPrinter(viper.GetString("output"), out)
+		if p.IsRaw() {
+			return p.PrintJSON(manifest)
+		}
+
+		t := output.NewTable("TABLE", "SECTION", "ROWS")
+		total := 0
+		for _, info := range manifest.Tables {
+			t.AddRow(info.Name, info.Section, strconv.Itoa(info.Rows))
+			total += info.Rows
+		}
+		t.SetFooter("Backed up %d rows to %s (schema version %d)", total, path, manifest.SchemaVersion)
+		p.PrintTable(t)
+		return nil
+	},
+}
+
+// writeBackup writes to a temporary file beside path and renames it once the
+// export succeeds, so a failed backup never leaves a truncated archive.
+func writeBackup(cmd *cobra.Command, db *pgxpool.Pool, path string) (*backup.Manifest, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), ".marmot-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating backup file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	manifest, err := backup.Export(cmd.Context(), db, f, Version)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("writing backup file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return nil, fmt.Errorf("writing backup file: %w", err)
+	}
+	return manifest, nil
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore the catalog from a backup archive",
+	Long: `Restore a backup archive created by 'marmot backup'. Database migrations are
+applied first, so an archive can be restored into an empty database.
+
+The archive's checksums and references are verified before anything is
+written, and the restore runs in a single transaction. By default the catalog
+must be empty; --force merges into existing data, keeping rows that already
+exist. Use --dry-run to check an archive without changing the database.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		archive, err := backup.ReadArchive(f)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		db, err := initializeDatabase(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		result, err := backup.Restore(cmd.Context(), db, archive, backup.RestoreOptions{Force: force, DryRun: dryRun})
+		if err != nil {
+			var integrityErr *backup.IntegrityError
+			if errors.As(err, &integrityErr) {
+				for _, v := range integrityErr.Violations {
+					fmt.Fprintln(os.Stderr, v.String())
+				}
+			}
+			return err
+		}
+
+		p := getPrinter()
+		if p.IsRaw() {
+			return p.PrintJSON(result)
+		}
+
+		t := output.NewTable("TABLE", "ROWS", "INSERTED", "SKIPPED")
+		var total, inserted int64
+		for _, r := range result.Tables {
+			t.AddRow(r.Name, strconv.Itoa(r.Rows), strconv.FormatInt(r.Inserted, 10), strconv.FormatInt(int64(r.Rows)-r.Inserted, 10))
+			total += int64(r.Rows)
+			inserted += r.Inserted
+		}
+		if result.DryRun {
+			t.SetFooter("Dry run: %d of %d rows would be restored, nothing was written", inserted, total)
+		} else {
+			t.SetFooter("Restored %d of %d rows from %s", inserted, total, args[0])
+		}
+		p.PrintTable(t)
+		return nil
+	},
+}
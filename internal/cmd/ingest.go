@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/marmotdata/marmot/internal/core/asset"
 	"github.com/marmotdata/marmot/internal/plugin"
 	"github.com/rs/zerolog"
@@ -26,6 +27,8 @@ const (
 	apiLineageBatch     = "/api/v1/lineage/batch"
 	apiDocsBatch        = "/api/v1/assets/documentation/batch"
 
+	idempotentAttempts = 3
+
 	statusCreated   = "created"
 	statusUpdated   = "updated"
 	statusUnchanged = "unchanged"
@@ -82,15 +85,16 @@ type BatchCreateRequest struct {
 }
 
 type CreateAssetRequest struct {
-	Name          string                 `json:"name"`
-	Type          string                 `json:"type"`
-	Providers     []string               `json:"providers"`
-	Description   *string                `json:"description"`
-	Metadata      map[string]interface{} `json:"metadata"`
-	Schema        map[string]interface{} `json:"schema"`
-	Tags          []string               `json:"tags"`
-	Sources       []string               `json:"sources"`
-	ExternalLinks []map[string]string    `json:"external_links"`
+	Name          string                       `json:"name"`
+	Type          string                       `json:"type"`
+	Providers     []string                     `json:"providers"`
+	Description   *string                      `json:"description"`
+	Metadata      map[string]interface{}       `json:"metadata"`
+	Schema        map[string]interface{}       `json:"schema"`
+	Tags          []string                     `json:"tags"`
+	Sources       []string                     `json:"sources"`
+	ExternalLinks []map[string]string          `json:"external_links"`
+	Environments  map[string]asset.Environment `json:"environments,omitempty"`
 }
 
 type CreateLineageRequest struct {
@@ -116,6 +120,7 @@ type BatchCreateResponse struct {
 	Lineage              []LineageResult       `json:"lineage"`
 	Documentation        []DocumentationResult `json:"documentation"`
 	StaleEntitiesRemoved []string              `json:"stale_entities_removed,omitempty"`
+	Summary              plugin.EntitySummary  `json:"summary,omitempty"`
 }
 
 type AssetResult struct {
@@ -203,7 +208,7 @@ func (c *apiClient) startRun(ctx context.Context, request StartRunRequest) (*plu
 	}
 
 	var run plugin.Run
-	if err := c.do(req, &run); err != nil {
+	if err := c.doIdempotent(req, &run); err != nil {
 		return nil, err
 	}
 
@@ -226,7 +231,7 @@ func (c *apiClient) batchCreateAssets(ctx context.Context, request BatchCreateRe
 	}
 
 	var response BatchCreateResponse
-	if err := c.do(req, &response); err != nil {
+	if err := c.doIdempotent(req, &response); err != nil {
 		return nil, err
 	}
 
@@ -288,6 +293,58 @@ func (c *apiClient) do(req *http.Request, v interface{}) error {
 	return nil
 }
 
+// doIdempotent sends req with an Idempotency-Key and retries it on network
+// errors and server errors. The server replays the original response for a
+// retried key, so a write that succeeded before the connection dropped isn't
+// applied twice.
+func (c *apiClient) doIdempotent(req *http.Request, v interface{}) error {
+	req.Header.Set("Idempotency-Key", uuid.NewString())
+
+	var lastErr error
+	for attempt := 0; attempt < idempotentAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-req.Context().Done():
+				return req.Context().Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.client.Do(req) //nolint:gosec // G704: URL is from operator-provided --server flag
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusConflict {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("request failed with status %d", resp.StatusCode)
+			continue
+		}
+
+		err = decodeResponse(resp, v)
+		resp.Body.Close()
+		return err
+	}
+
+	return lastErr
+}
+
+func decodeResponse(resp *http.Response, v interface{}) error {
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	if v != nil {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+	return nil
+}
+
 func runIngestion(ctx context.Context) error {
 	if quiet {
 		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
@@ -436,6 +493,7 @@ func executeRun(ctx context.Context, run plugin.SourceRun, client *apiClient, ov
 		}
 
 		plugin.FilterDiscoveryResult(result, rawConfig)
+		plugin.ApplyTTLRules(result, rawConfig)
 
 		if len(result.Assets) == 0 {
 			printWarning("No assets discovered")
@@ -455,6 +513,7 @@ func executeRun(ctx context.Context, run plugin.SourceRun, client *apiClient, ov
 
 		runSummary := &plugin.RunSummary{
 			TotalEntities: len(result.Assets) + len(result.Lineage) + len(result.Documentation),
+			Entities:      plugin.EntitySummary{},
 		}
 
 		if len(result.Assets) > 0 {
@@ -491,6 +550,7 @@ func executeRun(ctx context.Context, run plugin.SourceRun, client *apiClient, ov
 					Tags:          asset.Tags,
 					Sources:       sources,
 					ExternalLinks: convertExternalLinks(asset.ExternalLinks),
+					Environments:  asset.Environments,
 				})
 			}
 
@@ -538,6 +598,7 @@ func executeRun(ctx context.Context, run plugin.SourceRun, client *apiClient, ov
 			processAssetResults(assetResponse.Assets, runSummary, overallSummary)
 			processLineageResults(assetResponse.Lineage, runSummary, overallSummary)
 			processDocumentationResults(assetResponse.Documentation, runSummary, overallSummary)
+			runSummary.Entities.Merge(assetResponse.Summary)
 
 			runSummary.AssetsDeleted = len(assetResponse.StaleEntitiesRemoved)
 			overallSummary.AssetsDeleted += runSummary.AssetsDeleted
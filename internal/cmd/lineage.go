@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/marmotdata/marmot/internal/core/lineage"
 	marmot "github.com/marmotdata/marmot/sdk/go"
 	"github.com/marmotdata/marmot/internal/cmd/output"
 	"github.com/spf13/cobra"
@@ -71,9 +79,206 @@ var lineageGetCmd = &cobra.Command{
 	},
 }
 
+var lineageExportCmd = &cobra.Command{
+	Use:   "export [asset-id]",
+	Short: "Export the lineage graph as GraphML, DOT or Cypher",
+	Long: `Export the lineage graph for analysis in tools such as Gephi, Graphviz or Neo4j.
+
+With an asset ID the export covers that asset's upstream and downstream
+lineage up to --depth hops (at most 20). Without one it covers the whole
+graph up to 10000 assets; a warning is printed when the export was cut short.`,
+	Example: `  marmot lineage export --format graphml --file lineage.graphml
+  marmot lineage export 3f2a... --format dot --depth 3 | dot -Tsvg > lineage.svg
+  marmot lineage export --format cypher | cypher-shell -u neo4j`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		depth, _ := cmd.Flags().GetInt("depth")
+		file, _ := cmd.Flags().GetString("file")
+
+		query := url.Values{}
+		query.Set("format", format)
+		if len(args) == 1 {
+			query.Set("asset_id", args[0])
+		}
+		if depth > 0 {
+			query.Set("depth", strconv.Itoa(depth))
+		}
+
+		var w io.Writer = os.Stdout
+		if file != "" {
+			f, err := os.Create(file)
+			if err != nil {
+				return fmt.Errorf("creating %s: %w", file, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		token, isSAToken := getAuthToken()
+		client := newAPIClient(getHost(), token, isSAToken)
+		if err := client.exportLineage(cmd.Context(), query, w); err != nil {
+			return err
+		}
+
+		if file != "" {
+			fmt.Fprintf(os.Stderr, "Lineage exported to %s\n", file)
+		}
+		return nil
+	},
+}
+
+func (c *apiClient) exportLineage(ctx context.Context, query url.Values, w io.Writer) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/lineage/export?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req) //nolint:gosec // G704: URL is from operator-provided --server flag
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return err
+	}
+	if resp.Header.Get("X-Marmot-Truncated") != "" {
+		fmt.Fprintf(os.Stderr, "Warning: export stopped at %d assets, pass an asset ID to export the rest\n", lineage.DefaultExportLimit)
+	}
+	return nil
+}
+
+var lineageImpactCmd = &cobra.Command{
+	Use:   "impact <mrn>",
+	Short: "Show what a proposed column change would break downstream",
+	Long: `Report the downstream columns, models and dashboards affected by a
+proposed change to an asset's columns.
+
+Use --fail-on-breaking in CI to exit non-zero when a drop, rename or type
+change reaches any downstream asset.`,
+	Example: `  marmot lineage impact mrn://table/postgres/shop.public.orders --drop email --rename total=amount
+  marmot lineage impact mrn://table/postgres/shop.public.orders --type "price=numeric(10,2)" --fail-on-breaking`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		drops, _ := cmd.Flags().GetStringSlice("drop")
+		renames, _ := cmd.Flags().GetStringSlice("rename")
+		types, _ := cmd.Flags().GetStringArray("type")
+		depth, _ := cmd.Flags().GetInt("depth")
+		failOnBreaking, _ := cmd.Flags().GetBool("fail-on-breaking")
+
+		req := lineage.ImpactRequest{MRN: args[0], Depth: depth}
+		for _, col := range drops {
+			req.Changes = append(req.Changes, lineage.ColumnChange{Column: col, Change: lineage.ColumnChangeDrop})
+		}
+		for _, r := range renames {
+			from, to, ok := strings.Cut(r, "=")
+			if !ok {
+				return fmt.Errorf("invalid --rename %q, expected old=new", r)
+			}
+			req.Changes = append(req.Changes, lineage.ColumnChange{Column: from, Change: lineage.ColumnChangeRename, NewName: to})
+		}
+		for _, t := range types {
+			col, newType, ok := strings.Cut(t, "=")
+			if !ok {
+				return fmt.Errorf("invalid --type %q, expected column=type", t)
+			}
+			req.Changes = append(req.Changes, lineage.ColumnChange{Column: col, Change: lineage.ColumnChangeType, NewType: newType})
+		}
+		if len(req.Changes) == 0 {
+			return fmt.Errorf("at least one of --drop, --rename or --type is required")
+		}
+
+		token, isSAToken := getAuthToken()
+		client := newAPIClient(getHost(), token, isSAToken)
+		httpReq, err := client.newRequest(cmd.Context(), http.MethodPost, "/api/v1/lineage/impact", req)
+		if err != nil {
+			return err
+		}
+
+		var report lineage.ImpactReport
+		if err := client.do(httpReq, &report); err != nil {
+			return err
+		}
+
+		p := getPrinter()
+		if p.IsRaw() {
+			data, err := marshalPayload(report)
+			if err != nil {
+				return err
+			}
+			if err := p.PrintRaw(data); err != nil {
+				return err
+			}
+		} else {
+			printImpactReport(p, &report)
+		}
+
+		if failOnBreaking && report.Breaking {
+			return fmt.Errorf("proposed change impacts downstream assets")
+		}
+		return nil
+	},
+}
+
+func printImpactReport(p *output.Printer, report *lineage.ImpactReport) {
+	if !report.Breaking {
+		fmt.Println("No downstream impact found.")
+		return
+	}
+
+	if len(report.Columns) > 0 {
+		fmt.Println("Columns:")
+		t := output.NewTable("ASSET", "COLUMN", "FROM", "DEPTH")
+		for _, c := range report.Columns {
+			t.AddRow(c.AssetName, c.Column, strings.Join(c.SourceColumns, ", "), fmt.Sprintf("%d", c.Depth))
+		}
+		p.PrintTable(t)
+	}
+
+	sections := []struct {
+		title  string
+		assets []lineage.ImpactedAsset
+	}{
+		{"Models", report.Models},
+		{"Dashboards", report.Dashboards},
+		{"Other assets", report.Assets},
+	}
+	for _, section := range sections {
+		if len(section.assets) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", section.title)
+		t := output.NewTable("NAME", "TYPE", "COLUMNS", "REASON", "DEPTH")
+		for _, a := range section.assets {
+			t.AddRow(a.Name, a.Type, strings.Join(a.Columns, ", "), a.Reason, fmt.Sprintf("%d", a.Depth))
+		}
+		p.PrintTable(t)
+	}
+
+	fmt.Printf("\n%d columns, %d models, %d dashboards, %d other assets impacted\n",
+		len(report.Columns), len(report.Models), len(report.Dashboards), len(report.Assets))
+}
+
 func init() {
 	lineageGetCmd.Flags().Int("depth", 0, "Maximum traversal depth (0 = default)")
 
+	lineageImpactCmd.Flags().StringSlice("drop", nil, "Column to drop (repeatable)")
+	lineageImpactCmd.Flags().StringSlice("rename", nil, "Column rename as old=new (repeatable)")
+	lineageImpactCmd.Flags().StringArray("type", nil, "Column type change as column=type (repeatable)")
+	lineageImpactCmd.Flags().Int("depth", 0, "Downstream traversal depth (0 = default)")
+	lineageImpactCmd.Flags().Bool("fail-on-breaking", false, "Exit non-zero if any downstream asset is impacted")
+
+	lineageExportCmd.Flags().String("format", "graphml", "Export format: graphml, dot or cypher")
+	lineageExportCmd.Flags().Int("depth", 0, "Traversal depth from the root asset (0 = default)")
+	lineageExportCmd.Flags().StringP("file", "f", "", "Write the export to a file instead of stdout")
+
 	lineageCmd.AddCommand(lineageGetCmd)
+	lineageCmd.AddCommand(lineageExportCmd)
+	lineageCmd.AddCommand(lineageImpactCmd)
 	rootCmd.AddCommand(lineageCmd)
 }
@@ -66,6 +66,9 @@ func runMarmot(_ *cobra.Command) error {
 	// Telemetry
 	if cfg.Telemetry.Enabled {
 		log.Info().Msg("Anonymous telemetry enabled — learn more: https://marmotdata.io/docs/configure/telemetry — disable with telemetry.enabled: false or MARMOT_TELEMETRY_ENABLED=false")
+		if cfg.Telemetry.FeatureUsage {
+			log.Info().Msg("Feature usage telemetry enabled — preview the report at /api/v1/admin/telemetry/preview")
+		}
 	}
 
 	// Lookup counters feed the anonymous telemetry payload; recorder is always
@@ -87,16 +90,17 @@ func runMarmot(_ *cobra.Command) error {
 	}
 
 	telemetryCfg := telemetry.CollectorConfig{
-		Enabled:  cfg.Telemetry.Enabled,
-		Endpoint: cfg.Telemetry.Endpoint,
-		Interval: time.Duration(cfg.Telemetry.Interval) * time.Second,
-		Version:  Version,
+		Enabled:      cfg.Telemetry.Enabled,
+		Endpoint:     cfg.Telemetry.Endpoint,
+		Interval:     time.Duration(cfg.Telemetry.Interval) * time.Second,
+		Version:      Version,
+		FeatureUsage: cfg.Telemetry.FeatureUsage,
 	}
 	collector := telemetry.NewCollector(db, telemetryCfg, lookupsStore)
 	go collector.Run(ctx)
 
 	mux := http.NewServeMux()
-	server := v1.New(cfg, db, lookupsRecorder)
+	server := v1.New(cfg, db, lookupsRecorder, collector)
 	server.RegisterRoutes(mux)
 	if lookupsFlusher != nil {
 		defer lookupsFlusher.Stop()
@@ -181,6 +185,22 @@ func securityHeaders(next http.Handler, custom map[string]string) http.Handler {
 }
 
 func initializeDatabase(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := connectDatabase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := postgres.NewSetup(pool)
+	if err := setup.Initialize(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("initializing database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// connectDatabase opens a connection pool without running migrations.
+func connectDatabase(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(cfg.BuildDSN())
 	if err != nil {
 		return nil, fmt.Errorf("parsing connection string: %w", err)
@@ -196,14 +216,10 @@ func initializeDatabase(ctx context.Context, cfg *config.Config) (*pgxpool.Pool,
 	}
 
 	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	setup := postgres.NewSetup(pool)
-	if err := setup.Initialize(ctx); err != nil {
-		return nil, fmt.Errorf("initializing database: %w", err)
-	}
-
 	return pool, nil
 }
 
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marmotdata/marmot/internal/cmd/output"
+	"github.com/marmotdata/marmot/internal/core/demo"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(seedDemoCmd)
+}
+
+var seedDemoCmd = &cobra.Command{
+	Use:   "seed-demo",
+	Short: "Populate the catalog with demo data",
+	Long: `Populate a Marmot instance with an example shop catalog: PostgreSQL tables,
+Kafka topics, an S3 bucket, an Airflow DAG and dbt models connected by lineage,
+plus documentation, glossary terms, data products and run history.
+
+Seeding is idempotent. Demo assets, lineage and documentation are recorded under
+the "marmot-demo" pipeline, so deleting that pipeline removes them; glossary
+terms and data products are tagged "demo". Requires permission to manage users.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, isBearer := getAuthToken()
+		client := newAPIClient(getHost(), token, isBearer)
+
+		req, err := client.newRequest(cmd.Context(), http.MethodPost, "/api/v1/admin/demo/seed", nil)
+		if err != nil {
+			return err
+		}
+		var result demo.Result
+		if err := client.do(req, &result); err != nil {
+			return fmt.Errorf("seeding demo data: %w", err)
+		}
+
+		p := getPrinter()
+		if p.IsRaw() {
+			return p.PrintJSON(result)
+		}
+
+		t := output.NewTable("ENTITY", "CREATED")
+		t.AddRow("Assets", strconv.Itoa(result.Assets))
+		t.AddRow("Lineage edges", strconv.Itoa(result.Lineage))
+		t.AddRow("Documentation", strconv.Itoa(result.Documentation))
+		t.AddRow("Glossary terms", strconv.Itoa(result.GlossaryTerms))
+		t.AddRow("Data products", strconv.Itoa(result.DataProducts))
+		t.AddRow("Runs", strconv.Itoa(result.Runs))
+		t.SetFooter("Demo catalog ready at %s", getHost())
+		p.PrintTable(t)
+		return nil
+	},
+}
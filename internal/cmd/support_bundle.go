@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/cmd/output"
+	"github.com/marmotdata/marmot/internal/core/supportbundle"
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	supportBundleCmd.Flags().Duration("since", supportbundle.DefaultSince, "How far back to collect failed runs and log lines")
+	supportBundleCmd.Flags().String("logs", "", "Server log file to include warnings and errors from")
+	supportBundleCmd.Flags().Int("max-log-lines", supportbundle.DefaultMaxLogLines, "Most recent log lines to include")
+
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle [file]",
+	Short: "Collect diagnostics for a bug report",
+	Long: `Collect the configuration, database schema stats, recent failed runs and
+catalog statistics into an archive to attach to a bug report.
+
+The bundle doesn't reveal what is in the catalog: secrets, hosts and other
+string settings are redacted, catalog statistics are counts by asset type and
+provider with no names or metadata values, pipelines are pseudonymised, and
+names, URLs and addresses are masked in error messages. Pass --logs to include
+warnings and errors from a server log, filtered the same way.
+
+Connects directly to the database configured by --config or MARMOT_DATABASE_*
+environment variables. Pass - as the file to write the archive to stdout.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := fmt.Sprintf("marmot-support-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		since, _ := cmd.Flags().GetDuration("since")
+		logFile, _ := cmd.Flags().GetString("logs")
+		maxLogLines, _ := cmd.Flags().GetInt("max-log-lines")
+		opts := supportbundle.Options{Since: since, LogFile: logFile, MaxLogLines: maxLogLines}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		db, err := connectDatabase(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var manifest *supportbundle.Manifest
+		if path == "-" {
+			manifest, err = supportbundle.Write(cmd.Context(), db, cfg, os.Stdout, Version, opts)
+		} else {
+			manifest, err = writeSupportBundle(cmd, db, cfg, path, opts)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Keep stdout for the archive when streaming it.
+		out := io.Writer(os.Stdout)
+		if path == "-" {
+			out = os.Stderr
+		}
+		p := output.NewPrinter(viper.GetString("output"), out)
+		if p.IsRaw() {
+			return p.PrintJSON(manifest)
+		}
+
+		t := output.NewTable("FILE", "SIZE", "ERROR")
+		for _, f := range manifest.Files {
+			size := strconv.Itoa(f.Size)
+			if f.Error != "" {
+				size = "-"
+			}
+			t.AddRow(f.Name, size, f.Error)
+		}
+		t.SetFooter("Wrote support bundle to %s; review it before sharing", path)
+		p.PrintTable(t)
+		return nil
+	},
+}
+
+// writeSupportBundle writes to a temporary file beside path and renames it
+// once the bundle is complete, like writeBackup.
+func writeSupportBundle(cmd *cobra.Command, db *pgxpool.Pool, cfg *config.Config, path string, opts supportbundle.Options) (*supportbundle.Manifest, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), ".marmot-support-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating support bundle: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	manifest, err := supportbundle.Write(cmd.Context(), db, cfg, f, Version, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("writing support bundle: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return nil, fmt.Errorf("writing support bundle: %w", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,85 @@
+package archival
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const (
+	DefaultArchiveInterval = 24 * time.Hour
+	DefaultExpiryInterval  = time.Hour
+)
+
+// Archiver periodically applies the archival policy.
+type Archiver struct {
+	task *background.SingletonTask
+}
+
+// ArchiverConfig configures the archiver.
+type ArchiverConfig struct {
+	// Interval between policy runs. Default: 24 hours.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewArchiver creates a new archiver for svc.
+func NewArchiver(svc *Service, config *ArchiverConfig) *Archiver {
+	if config == nil {
+		config = &ArchiverConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultArchiveInterval
+	}
+
+	return &Archiver{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "stale-asset-archival",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.RunOnce(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// NewExpirer creates an archiver that archives assets whose time to live
+// has run out. It runs more often than the stale policy, since TTLs are
+// usually hours rather than weeks. Default interval: 1 hour.
+func NewExpirer(svc *Service, config *ArchiverConfig) *Archiver {
+	if config == nil {
+		config = &ArchiverConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultExpiryInterval
+	}
+
+	return &Archiver{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "asset-ttl-expiry",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.ExpireOnce(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins the periodic archival loop.
+func (a *Archiver) Start(ctx context.Context) {
+	a.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the archiver.
+func (a *Archiver) Stop() {
+	a.task.Stop()
+}
@@ -0,0 +1,380 @@
+package archival
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultStaleAfter  = 30 * 24 * time.Hour
+	DefaultGracePeriod = 7 * 24 * time.Hour
+
+	// batchSize bounds how many assets are flagged or archived per run so a
+	// misconfigured threshold cannot archive the whole catalog in one go.
+	batchSize = 500
+)
+
+// Reasons an asset was archived.
+const (
+	ReasonStale   = "stale"
+	ReasonExpired = "expired"
+)
+
+var (
+	ErrNotFound         = errors.New("archived asset not found")
+	ErrCandidateMissing = errors.New("archival candidate not found")
+	ErrAssetExists      = errors.New("an asset with this MRN already exists")
+)
+
+// Policy controls when assets are archived.
+type Policy struct {
+	// StaleAfter is how long an asset may go without being synced before it
+	// is flagged.
+	StaleAfter time.Duration
+	// GracePeriod is how long owners have to react before a flagged asset is
+	// archived.
+	GracePeriod time.Duration
+}
+
+// Owner identifies a user or team that owns an asset.
+type Owner struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Candidate is an asset flagged for archival.
+type Candidate struct {
+	AssetID      string    `json:"asset_id"`
+	MRN          string    `json:"mrn"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	LastSyncAt   time.Time `json:"last_sync_at"`
+	FlaggedAt    time.Time `json:"flagged_at"`
+	ArchiveAfter time.Time `json:"archive_after"`
+	Exempt       bool      `json:"exempt"`
+	ExemptedBy   *string   `json:"exempted_by,omitempty"`
+} // @name ArchivalCandidate
+
+// ArchivedAsset records an asset archived by the archival policy, with a
+// snapshot of it and its owners at the time.
+type ArchivedAsset struct {
+	ID         string       `json:"id"`
+	AssetID    string       `json:"asset_id"`
+	MRN        string       `json:"mrn"`
+	Name       string       `json:"name"`
+	Type       string       `json:"type"`
+	Snapshot   *asset.Asset `json:"snapshot"`
+	Owners     []Owner      `json:"owners"`
+	LastSyncAt time.Time    `json:"last_sync_at"`
+	ArchivedAt time.Time    `json:"archived_at"`
+	Reason     string       `json:"reason"`
+} // @name ArchivedAsset
+
+// RunResult summarises one policy run.
+type RunResult struct {
+	Recovered int `json:"recovered"`
+	Restored  int `json:"restored"`
+	Flagged   int `json:"flagged"`
+	Archived  int `json:"archived"`
+	Expired   int `json:"expired"`
+}
+
+// OwnerStore looks up and restores asset ownership.
+type OwnerStore interface {
+	ListAssetOwners(ctx context.Context, assetID string) ([]Owner, error)
+	AddAssetOwner(ctx context.Context, assetID, ownerType, ownerID string) error
+}
+
+// Notifier tells owners that an asset is about to be archived.
+type Notifier interface {
+	NotifyPendingArchival(ctx context.Context, owners []Owner, candidate *Candidate)
+}
+
+// Service applies the stale asset archival policy.
+type Service struct {
+	repo     Repository
+	assetSvc asset.Service
+	owners   OwnerStore
+	notifier Notifier
+	policy   Policy
+}
+
+// NewService creates an archival service.
+func NewService(repo Repository, assetSvc asset.Service, owners OwnerStore, policy Policy) *Service {
+	if policy.StaleAfter <= 0 {
+		policy.StaleAfter = DefaultStaleAfter
+	}
+	if policy.GracePeriod < 0 {
+		policy.GracePeriod = DefaultGracePeriod
+	}
+
+	return &Service{
+		repo:     repo,
+		assetSvc: assetSvc,
+		owners:   owners,
+		policy:   policy,
+	}
+}
+
+// SetNotifier registers the notifier used to warn owners of pending archival.
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// Policy returns the effective archival policy.
+func (s *Service) Policy() Policy {
+	return s.policy
+}
+
+// RunOnce clears candidates and restores archived assets that have been
+// synced again, flags newly stale assets and archives candidates whose grace
+// period has expired.
+func (s *Service) RunOnce(ctx context.Context) (*RunResult, error) {
+	result := &RunResult{}
+	now := time.Now()
+
+	recovered, err := s.repo.ClearRecovered(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("clearing recovered candidates: %w", err)
+	}
+	result.Recovered = recovered
+
+	restored, err := s.repo.RestoreResynced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Restored = restored
+
+	stale, err := s.repo.FindStale(ctx, now.Add(-s.policy.StaleAfter), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("finding stale assets: %w", err)
+	}
+
+	for _, c := range stale {
+		c.FlaggedAt = now
+		c.ArchiveAfter = now.Add(s.policy.GracePeriod)
+		if err := s.repo.CreateCandidate(ctx, c); err != nil {
+			log.Error().Err(err).Str("asset_id", c.AssetID).Msg("Failed to flag stale asset")
+			continue
+		}
+		result.Flagged++
+		s.notify(ctx, c)
+	}
+
+	due, err := s.repo.ListDue(ctx, now, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("listing due candidates: %w", err)
+	}
+
+	for _, c := range due {
+		ok, err := s.archive(ctx, c, ReasonStale)
+		if err != nil {
+			log.Error().Err(err).Str("asset_id", c.AssetID).Msg("Failed to archive stale asset")
+			continue
+		}
+		if ok {
+			result.Archived++
+		}
+	}
+
+	log.Info().
+		Int("recovered", result.Recovered).
+		Int("restored", result.Restored).
+		Int("flagged", result.Flagged).
+		Int("archived", result.Archived).
+		Msg("Stale asset archival completed")
+
+	return result, nil
+}
+
+// ExpireOnce archives assets whose time to live has run out. An asset's TTL
+// is set in its metadata by the plugin that ingested it or by the run's ttl
+// rules; syncing the asset again restarts the clock and restores it if it
+// was archived. Unlike stale archival there is no grace period, since the
+// TTL already says how long to wait.
+func (s *Service) ExpireOnce(ctx context.Context) (int, error) {
+	restored, err := s.repo.RestoreResynced(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if restored > 0 {
+		log.Info().Int("restored", restored).Msg("Restored archived assets synced again")
+	}
+
+	expired, err := s.repo.FindExpired(ctx, time.Now(), batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("finding expired assets: %w", err)
+	}
+
+	archived := 0
+	for _, c := range expired {
+		ok, err := s.archive(ctx, c, ReasonExpired)
+		if err != nil {
+			log.Error().Err(err).Str("asset_id", c.AssetID).Msg("Failed to archive expired asset")
+			continue
+		}
+		if ok {
+			archived++
+		}
+	}
+
+	if archived > 0 {
+		log.Info().Int("archived", archived).Msg("Expired asset archival completed")
+	}
+
+	return archived, nil
+}
+
+func (s *Service) notify(ctx context.Context, c *Candidate) {
+	if s.notifier == nil || s.owners == nil {
+		return
+	}
+
+	owners, err := s.owners.ListAssetOwners(ctx, c.AssetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", c.AssetID).Msg("Failed to get asset owners for archival notice")
+		return
+	}
+	if len(owners) > 0 {
+		s.notifier.NotifyPendingArchival(ctx, owners, c)
+	}
+}
+
+// archive records a snapshot of the asset and its owners and hides the asset
+// from search and listings. The asset itself is kept. ok is false when the
+// asset was deleted or archived in the meantime.
+func (s *Service) archive(ctx context.Context, c *Candidate, reason string) (bool, error) {
+	a, err := s.assetSvc.Get(ctx, c.AssetID)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			return false, s.repo.DeleteCandidate(ctx, c.AssetID)
+		}
+		return false, fmt.Errorf("getting asset: %w", err)
+	}
+
+	archived := &ArchivedAsset{
+		AssetID:    a.ID,
+		MRN:        c.MRN,
+		Name:       c.Name,
+		Type:       a.Type,
+		Snapshot:   a,
+		Owners:     []Owner{},
+		LastSyncAt: a.LastSyncAt,
+		Reason:     reason,
+	}
+	if s.owners != nil {
+		owners, err := s.owners.ListAssetOwners(ctx, a.ID)
+		if err != nil {
+			return false, fmt.Errorf("listing owners: %w", err)
+		}
+		archived.Owners = owners
+	}
+
+	if err := s.repo.Archive(ctx, archived); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, s.repo.DeleteCandidate(ctx, c.AssetID)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ListCandidates returns assets waiting to be archived, soonest first.
+func (s *Service) ListCandidates(ctx context.Context, limit, offset int) ([]*Candidate, int, error) {
+	return s.repo.ListCandidates(ctx, limit, offset)
+}
+
+// SetExempt keeps a flagged asset from being archived until it is next synced,
+// or makes it eligible again.
+func (s *Service) SetExempt(ctx context.Context, assetID string, exempt bool, userID *string) (*Candidate, error) {
+	if !exempt {
+		userID = nil
+	}
+	return s.repo.SetExempt(ctx, assetID, exempt, userID)
+}
+
+// ListArchived returns archived assets, most recent first.
+func (s *Service) ListArchived(ctx context.Context, limit, offset int) ([]*ArchivedAsset, int, error) {
+	return s.repo.ListArchived(ctx, limit, offset)
+}
+
+// GetArchived returns a single archived asset.
+func (s *Service) GetArchived(ctx context.Context, id string) (*ArchivedAsset, error) {
+	return s.repo.GetArchived(ctx, id)
+}
+
+// Restore returns an archived asset to search and listings.
+func (s *Service) Restore(ctx context.Context, id string) (*asset.Asset, error) {
+	archived, err := s.repo.GetArchived(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.Unarchive(ctx, archived)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return s.assetSvc.Get(ctx, archived.AssetID)
+	}
+	return s.recreate(ctx, archived)
+}
+
+// recreate restores an asset that was deleted when it was archived, as the
+// policy did before archived assets were kept, from its snapshot and re-adds
+// its owners. The asset gets a new ID; lineage and documentation keyed by
+// MRN reattach automatically.
+func (s *Service) recreate(ctx context.Context, archived *ArchivedAsset) (*asset.Asset, error) {
+	snap := archived.Snapshot
+	restored, err := s.assetSvc.Create(ctx, asset.CreateInput{
+		Name:          snap.Name,
+		MRN:           snap.MRN,
+		Type:          snap.Type,
+		Providers:     snap.Providers,
+		Description:   snap.Description,
+		Metadata:      snap.Metadata,
+		Schema:        snap.Schema,
+		Tags:          snap.Tags,
+		CreatedBy:     snap.CreatedBy,
+		Sources:       snap.Sources,
+		Environments:  snap.Environments,
+		ExternalLinks: snap.ExternalLinks,
+		Query:         snap.Query,
+		QueryLanguage: snap.QueryLanguage,
+	})
+	if err != nil {
+		if errors.Is(err, asset.ErrAlreadyExists) {
+			return nil, ErrAssetExists
+		}
+		return nil, fmt.Errorf("recreating asset: %w", err)
+	}
+
+	if snap.UserDescription != nil {
+		if updated, err := s.assetSvc.Update(ctx, restored.ID, asset.UpdateInput{
+			UserDescription:  snap.UserDescription,
+			SkipNotification: true,
+		}); err == nil {
+			restored = updated
+		}
+	}
+
+	if s.owners != nil {
+		for _, o := range archived.Owners {
+			if err := s.owners.AddAssetOwner(ctx, restored.ID, o.Type, o.ID); err != nil {
+				log.Warn().Err(err).Str("asset_id", restored.ID).Str("owner_id", o.ID).Msg("Failed to restore asset owner")
+			}
+		}
+	}
+
+	if err := s.repo.DeleteArchived(ctx, archived.ID); err != nil {
+		return nil, fmt.Errorf("removing snapshot: %w", err)
+	}
+
+	return restored, nil
+}
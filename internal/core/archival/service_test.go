@@ -0,0 +1,364 @@
+package archival
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo keeps candidates and archive records in memory. archivedAt holds
+// the asset IDs Archive has hidden.
+type fakeRepo struct {
+	stale      []*Candidate
+	expired    []*Candidate
+	candidates map[string]*Candidate
+	records    map[string]*ArchivedAsset
+	archivedAt map[string]time.Time
+	resynced   []string
+	deleted    []string
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		candidates: map[string]*Candidate{},
+		records:    map[string]*ArchivedAsset{},
+		archivedAt: map[string]time.Time{},
+	}
+}
+
+func (r *fakeRepo) FindStale(context.Context, time.Time, int) ([]*Candidate, error) {
+	return r.stale, nil
+}
+
+func (r *fakeRepo) FindExpired(context.Context, time.Time, int) ([]*Candidate, error) {
+	return r.expired, nil
+}
+
+func (r *fakeRepo) ClearRecovered(context.Context) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeRepo) CreateCandidate(_ context.Context, c *Candidate) error {
+	r.candidates[c.AssetID] = c
+	return nil
+}
+
+func (r *fakeRepo) DeleteCandidate(_ context.Context, assetID string) error {
+	delete(r.candidates, assetID)
+	return nil
+}
+
+func (r *fakeRepo) ListDue(_ context.Context, now time.Time, _ int) ([]*Candidate, error) {
+	var due []*Candidate
+	for _, c := range r.candidates {
+		if !c.Exempt && !c.ArchiveAfter.After(now) {
+			due = append(due, c)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeRepo) ListCandidates(context.Context, int, int) ([]*Candidate, int, error) {
+	panic("not used")
+}
+
+func (r *fakeRepo) SetExempt(_ context.Context, assetID string, exempt bool, userID *string) (*Candidate, error) {
+	c, ok := r.candidates[assetID]
+	if !ok {
+		return nil, ErrCandidateMissing
+	}
+	c.Exempt, c.ExemptedBy = exempt, userID
+	return c, nil
+}
+
+func (r *fakeRepo) Archive(_ context.Context, a *ArchivedAsset) error {
+	if _, ok := r.archivedAt[a.AssetID]; ok {
+		return ErrNotFound
+	}
+	a.ID = "archived-" + a.AssetID
+	a.ArchivedAt = time.Now()
+	r.records[a.ID] = a
+	r.archivedAt[a.AssetID] = a.ArchivedAt
+	delete(r.candidates, a.AssetID)
+	return nil
+}
+
+func (r *fakeRepo) Unarchive(_ context.Context, a *ArchivedAsset) (bool, error) {
+	if _, ok := r.archivedAt[a.AssetID]; !ok {
+		return false, nil
+	}
+	delete(r.archivedAt, a.AssetID)
+	delete(r.records, a.ID)
+	return true, nil
+}
+
+func (r *fakeRepo) RestoreResynced(context.Context) (int, error) {
+	restored := 0
+	for _, id := range r.resynced {
+		if _, ok := r.archivedAt[id]; ok {
+			delete(r.archivedAt, id)
+			delete(r.records, "archived-"+id)
+			restored++
+		}
+	}
+	r.resynced = nil
+	return restored, nil
+}
+
+func (r *fakeRepo) GetArchived(_ context.Context, id string) (*ArchivedAsset, error) {
+	a, ok := r.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+func (r *fakeRepo) ListArchived(context.Context, int, int) ([]*ArchivedAsset, int, error) {
+	panic("not used")
+}
+
+func (r *fakeRepo) DeleteArchived(_ context.Context, id string) error {
+	if _, ok := r.records[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.records, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// fakeAssets holds assets by ID. Delete is deliberately not implemented:
+// archiving must never remove an asset.
+type fakeAssets struct {
+	asset.Service
+	assets map[string]*asset.Asset
+}
+
+func (f *fakeAssets) Get(_ context.Context, id string) (*asset.Asset, error) {
+	a, ok := f.assets[id]
+	if !ok {
+		return nil, asset.ErrAssetNotFound
+	}
+	return a, nil
+}
+
+func (f *fakeAssets) Create(_ context.Context, input asset.CreateInput) (*asset.Asset, error) {
+	for _, a := range f.assets {
+		if *a.MRN == *input.MRN {
+			return nil, asset.ErrAlreadyExists
+		}
+	}
+	a := &asset.Asset{ID: "new-id", Name: input.Name, MRN: input.MRN, Type: input.Type, Tags: input.Tags}
+	f.assets[a.ID] = a
+	return a, nil
+}
+
+func (f *fakeAssets) Update(_ context.Context, id string, input asset.UpdateInput) (*asset.Asset, error) {
+	a := f.assets[id]
+	a.UserDescription = input.UserDescription
+	return a, nil
+}
+
+type fakeOwners struct {
+	owners map[string][]Owner
+}
+
+func (o *fakeOwners) ListAssetOwners(_ context.Context, assetID string) ([]Owner, error) {
+	return o.owners[assetID], nil
+}
+
+func (o *fakeOwners) AddAssetOwner(_ context.Context, assetID, ownerType, ownerID string) error {
+	o.owners[assetID] = append(o.owners[assetID], Owner{Type: ownerType, ID: ownerID})
+	return nil
+}
+
+type fakeNotifier struct {
+	notified []string
+}
+
+func (n *fakeNotifier) NotifyPendingArchival(_ context.Context, _ []Owner, c *Candidate) {
+	n.notified = append(n.notified, c.AssetID)
+}
+
+func testAsset(id string) *asset.Asset {
+	name, mrn := id, "mrn://table/postgres/db."+id
+	return &asset.Asset{ID: id, Name: &name, MRN: &mrn, Type: "Table", LastSyncAt: time.Now().Add(-60 * 24 * time.Hour)}
+}
+
+func candidate(id string) *Candidate {
+	return &Candidate{AssetID: id, MRN: "mrn://table/postgres/db." + id, Name: id, Type: "Table"}
+}
+
+func newTestService(policy Policy, assets ...*asset.Asset) (*Service, *fakeRepo, *fakeAssets, *fakeOwners) {
+	repo := newFakeRepo()
+	catalog := &fakeAssets{assets: map[string]*asset.Asset{}}
+	for _, a := range assets {
+		catalog.assets[a.ID] = a
+	}
+	owners := &fakeOwners{owners: map[string][]Owner{}}
+	return NewService(repo, catalog, owners, policy), repo, catalog, owners
+}
+
+func TestNewServiceDefaults(t *testing.T) {
+	svc, _, _, _ := newTestService(Policy{GracePeriod: -1})
+	assert.Equal(t, Policy{StaleAfter: DefaultStaleAfter, GracePeriod: DefaultGracePeriod}, svc.Policy())
+
+	svc, _, _, _ = newTestService(Policy{StaleAfter: time.Hour})
+	assert.Equal(t, Policy{StaleAfter: time.Hour}, svc.Policy())
+}
+
+func TestRunOnceFlagsAndNotifies(t *testing.T) {
+	svc, repo, _, owners := newTestService(Policy{StaleAfter: time.Hour, GracePeriod: 24 * time.Hour}, testAsset("orders"), testAsset("unowned"))
+	notifier := &fakeNotifier{}
+	svc.SetNotifier(notifier)
+	owners.owners["orders"] = []Owner{{Type: "user", ID: "u1"}}
+	repo.stale = []*Candidate{candidate("orders"), candidate("unowned")}
+
+	result, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &RunResult{Flagged: 2}, result)
+
+	c := repo.candidates["orders"]
+	require.NotNil(t, c)
+	assert.Equal(t, 24*time.Hour, c.ArchiveAfter.Sub(c.FlaggedAt))
+	assert.Equal(t, []string{"orders"}, notifier.notified, "only owned assets are notified")
+	assert.Empty(t, repo.archivedAt)
+}
+
+func TestRunOnceArchivesInPlace(t *testing.T) {
+	orders := testAsset("orders")
+	svc, repo, catalog, owners := newTestService(Policy{StaleAfter: time.Hour}, orders)
+	owners.owners["orders"] = []Owner{{Type: "team", ID: "t1"}}
+	repo.stale = []*Candidate{candidate("orders")}
+
+	// With no grace period the asset is flagged and archived in one run.
+	result, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Flagged)
+	assert.Equal(t, 1, result.Archived)
+
+	assert.Contains(t, catalog.assets, "orders", "archived assets are kept")
+	assert.Contains(t, repo.archivedAt, "orders")
+	assert.NotContains(t, repo.candidates, "orders")
+
+	record := repo.records["archived-orders"]
+	require.NotNil(t, record)
+	assert.Equal(t, ReasonStale, record.Reason)
+	assert.Equal(t, orders, record.Snapshot)
+	assert.Equal(t, []Owner{{Type: "team", ID: "t1"}}, record.Owners)
+	assert.Equal(t, orders.LastSyncAt, record.LastSyncAt)
+}
+
+func TestRunOnceSkipsExemptAndMissingAssets(t *testing.T) {
+	svc, repo, _, _ := newTestService(Policy{StaleAfter: time.Hour}, testAsset("kept"))
+	past := time.Now().Add(-time.Hour)
+	repo.candidates["kept"] = &Candidate{AssetID: "kept", ArchiveAfter: past, Exempt: true}
+	repo.candidates["gone"] = &Candidate{AssetID: "gone", ArchiveAfter: past}
+
+	result, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Archived)
+	assert.Contains(t, repo.candidates, "kept")
+	assert.NotContains(t, repo.candidates, "gone", "candidates for deleted assets are dropped")
+	assert.Empty(t, repo.records)
+}
+
+func TestRunOnceRestoresResyncedAssets(t *testing.T) {
+	svc, repo, _, _ := newTestService(Policy{StaleAfter: time.Hour}, testAsset("orders"))
+	require.NoError(t, repo.Archive(context.Background(), &ArchivedAsset{AssetID: "orders"}))
+	repo.resynced = []string{"orders"}
+
+	result, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Restored)
+	assert.Empty(t, repo.archivedAt)
+	assert.Empty(t, repo.records)
+}
+
+func TestExpireOnce(t *testing.T) {
+	svc, repo, catalog, _ := newTestService(Policy{}, testAsset("tmp_orders"), testAsset("tmp_restored"))
+	require.NoError(t, repo.Archive(context.Background(), &ArchivedAsset{AssetID: "tmp_restored"}))
+	repo.resynced = []string{"tmp_restored"}
+	repo.expired = []*Candidate{candidate("tmp_orders"), candidate("tmp_gone")}
+
+	archived, err := svc.ExpireOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	assert.Contains(t, catalog.assets, "tmp_orders")
+	assert.Equal(t, ReasonExpired, repo.records["archived-tmp_orders"].Reason)
+	assert.NotContains(t, repo.archivedAt, "tmp_restored")
+}
+
+func TestRestoreInPlace(t *testing.T) {
+	orders := testAsset("orders")
+	svc, repo, _, owners := newTestService(Policy{}, orders)
+	require.NoError(t, repo.Archive(context.Background(), &ArchivedAsset{AssetID: "orders"}))
+
+	restored, err := svc.Restore(context.Background(), "archived-orders")
+	require.NoError(t, err)
+	assert.Same(t, orders, restored, "the asset keeps its ID")
+	assert.Empty(t, repo.archivedAt)
+	assert.Empty(t, repo.records)
+	assert.Empty(t, owners.owners, "owners were never removed")
+
+	_, err = svc.Restore(context.Background(), "archived-orders")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRestoreDeletedAsset(t *testing.T) {
+	desc := "Orders placed online"
+	snapshot := testAsset("old-id")
+	snapshot.UserDescription = &desc
+	legacy := &ArchivedAsset{
+		ID:       "legacy",
+		AssetID:  "old-id",
+		Snapshot: snapshot,
+		Owners:   []Owner{{Type: "user", ID: "u1"}},
+	}
+
+	t.Run("recreated from snapshot", func(t *testing.T) {
+		svc, repo, catalog, owners := newTestService(Policy{})
+		repo.records["legacy"] = legacy
+
+		restored, err := svc.Restore(context.Background(), "legacy")
+		require.NoError(t, err)
+		assert.Equal(t, "new-id", restored.ID)
+		assert.Equal(t, snapshot.MRN, restored.MRN)
+		assert.Equal(t, &desc, catalog.assets["new-id"].UserDescription)
+		assert.Equal(t, []Owner{{Type: "user", ID: "u1"}}, owners.owners["new-id"])
+		assert.Equal(t, []string{"legacy"}, repo.deleted)
+	})
+
+	t.Run("mrn taken", func(t *testing.T) {
+		existing := testAsset("other-id")
+		existing.MRN = snapshot.MRN
+		svc, repo, _, _ := newTestService(Policy{}, existing)
+		repo.records["legacy"] = legacy
+
+		_, err := svc.Restore(context.Background(), "legacy")
+		assert.ErrorIs(t, err, ErrAssetExists)
+		assert.Contains(t, repo.records, "legacy")
+	})
+}
+
+func TestSetExempt(t *testing.T) {
+	svc, repo, _, _ := newTestService(Policy{})
+	repo.candidates["orders"] = candidate("orders")
+	user := "u1"
+
+	c, err := svc.SetExempt(context.Background(), "orders", true, &user)
+	require.NoError(t, err)
+	assert.True(t, c.Exempt)
+	assert.Equal(t, &user, c.ExemptedBy)
+
+	c, err = svc.SetExempt(context.Background(), "orders", false, &user)
+	require.NoError(t, err)
+	assert.False(t, c.Exempt)
+	assert.Nil(t, c.ExemptedBy, "clearing an exemption forgets who set it")
+
+	_, err = svc.SetExempt(context.Background(), "missing", true, &user)
+	assert.ErrorIs(t, err, ErrCandidateMissing)
+}
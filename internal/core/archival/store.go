@@ -0,0 +1,394 @@
+package archival
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the archival data access interface.
+type Repository interface {
+	FindStale(ctx context.Context, cutoff time.Time, limit int) ([]*Candidate, error)
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*Candidate, error)
+	ClearRecovered(ctx context.Context) (int, error)
+	CreateCandidate(ctx context.Context, c *Candidate) error
+	DeleteCandidate(ctx context.Context, assetID string) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Candidate, error)
+	ListCandidates(ctx context.Context, limit, offset int) ([]*Candidate, int, error)
+	SetExempt(ctx context.Context, assetID string, exempt bool, userID *string) (*Candidate, error)
+
+	Archive(ctx context.Context, a *ArchivedAsset) error
+	Unarchive(ctx context.Context, a *ArchivedAsset) (bool, error)
+	RestoreResynced(ctx context.Context) (int, error)
+	GetArchived(ctx context.Context, id string) (*ArchivedAsset, error)
+	ListArchived(ctx context.Context, limit, offset int) ([]*ArchivedAsset, int, error)
+	DeleteArchived(ctx context.Context, id string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// FindStale returns ingested assets that have not been synced since cutoff
+// and whose pipeline has completed a run since it last reported them, i.e.
+// the source is still running but no longer emits the asset. Assets that were
+// never ingested by a pipeline are left alone.
+func (r *PostgresRepository) FindStale(ctx context.Context, cutoff time.Time, limit int) ([]*Candidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.mrn, a.name, a.type, a.last_sync_at
+		FROM assets a
+		CROSS JOIN LATERAL (
+			SELECT r.pipeline_name, r.source_name, r.started_at
+			FROM run_checkpoints c
+			JOIN runs r ON r.id = c.run_id
+			WHERE c.entity_type = 'asset' AND c.entity_mrn = a.mrn
+			ORDER BY r.started_at DESC
+			LIMIT 1
+		) last_seen
+		WHERE a.is_stub = FALSE
+		  AND a.archived_at IS NULL
+		  AND a.last_sync_at < $1
+		  AND last_seen.started_at < $1
+		  AND EXISTS (
+			SELECT 1 FROM runs r
+			WHERE r.pipeline_name = last_seen.pipeline_name
+			  AND r.source_name = last_seen.source_name
+			  AND r.status = 'completed'
+			  AND r.started_at > last_seen.started_at
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM asset_archival_candidates ac WHERE ac.asset_id = a.id
+		  )
+		ORDER BY a.last_sync_at ASC
+		LIMIT $2`, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying stale assets: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*Candidate{}
+	for rows.Next() {
+		c := &Candidate{}
+		if err := rows.Scan(&c.AssetID, &c.MRN, &c.Name, &c.Type, &c.LastSyncAt); err != nil {
+			return nil, fmt.Errorf("scanning stale asset: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// FindExpired returns assets with a time to live that have not been synced
+// within it. Assets exempted from archival by an owner are skipped.
+func (r *PostgresRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*Candidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.mrn, a.name, a.type, a.last_sync_at
+		FROM assets a
+		WHERE a.metadata ? 'ttl_seconds'
+		  AND a.is_stub = FALSE
+		  AND a.archived_at IS NULL
+		  AND (a.metadata->>'ttl_seconds') ~ '^[0-9]+$'
+		  AND a.last_sync_at + make_interval(secs => (a.metadata->>'ttl_seconds')::double precision) < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM asset_archival_candidates ac WHERE ac.asset_id = a.id AND ac.exempt
+		  )
+		ORDER BY a.last_sync_at ASC
+		LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying expired assets: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*Candidate{}
+	for rows.Next() {
+		c := &Candidate{}
+		if err := rows.Scan(&c.AssetID, &c.MRN, &c.Name, &c.Type, &c.LastSyncAt); err != nil {
+			return nil, fmt.Errorf("scanning expired asset: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// ClearRecovered removes candidates whose asset has been synced since it was
+// flagged. Exemptions are cleared with them.
+func (r *PostgresRepository) ClearRecovered(ctx context.Context) (int, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM asset_archival_candidates ac
+		USING assets a
+		WHERE ac.asset_id = a.id AND a.last_sync_at > ac.last_sync_at`)
+	if err != nil {
+		return 0, fmt.Errorf("clearing recovered candidates: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *PostgresRepository) CreateCandidate(ctx context.Context, c *Candidate) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_archival_candidates (asset_id, mrn, last_sync_at, flagged_at, archive_after)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (asset_id) DO NOTHING`,
+		c.AssetID, c.MRN, c.LastSyncAt, c.FlaggedAt, c.ArchiveAfter)
+	if err != nil {
+		return fmt.Errorf("creating archival candidate: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteCandidate(ctx context.Context, assetID string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM asset_archival_candidates WHERE asset_id = $1`, assetID); err != nil {
+		return fmt.Errorf("deleting archival candidate: %w", err)
+	}
+	return nil
+}
+
+const selectCandidate = `
+	SELECT ac.asset_id, ac.mrn, a.name, a.type, ac.last_sync_at, ac.flagged_at,
+	       ac.archive_after, ac.exempt, ac.exempted_by
+	FROM asset_archival_candidates ac
+	JOIN assets a ON a.id = ac.asset_id`
+
+func (r *PostgresRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*Candidate, error) {
+	rows, err := r.db.Query(ctx, selectCandidate+`
+		WHERE ac.exempt = FALSE AND ac.archive_after <= $1
+		ORDER BY ac.archive_after ASC
+		LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying due candidates: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCandidates(rows)
+}
+
+func (r *PostgresRepository) ListCandidates(ctx context.Context, limit, offset int) ([]*Candidate, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_archival_candidates`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting archival candidates: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectCandidate+`
+		ORDER BY ac.exempt ASC, ac.archive_after ASC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing archival candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates, err := scanCandidates(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return candidates, total, nil
+}
+
+func (r *PostgresRepository) SetExempt(ctx context.Context, assetID string, exempt bool, userID *string) (*Candidate, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE asset_archival_candidates SET exempt = $2, exempted_by = $3
+		WHERE asset_id = $1`, assetID, exempt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("updating archival candidate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrCandidateMissing
+	}
+
+	rows, err := r.db.Query(ctx, selectCandidate+` WHERE ac.asset_id = $1`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("getting archival candidate: %w", err)
+	}
+	defer rows.Close()
+
+	candidates, err := scanCandidates(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, ErrCandidateMissing
+	}
+	return candidates[0], nil
+}
+
+func scanCandidates(rows pgx.Rows) ([]*Candidate, error) {
+	candidates := []*Candidate{}
+	for rows.Next() {
+		c := &Candidate{}
+		if err := rows.Scan(&c.AssetID, &c.MRN, &c.Name, &c.Type, &c.LastSyncAt, &c.FlaggedAt,
+			&c.ArchiveAfter, &c.Exempt, &c.ExemptedBy); err != nil {
+			return nil, fmt.Errorf("scanning archival candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// Archive records the snapshot and hides the asset from search and
+// listings. The asset is kept, so restoring it keeps its ID, lineage and
+// history.
+func (r *PostgresRepository) Archive(ctx context.Context, a *ArchivedAsset) error {
+	snapshot, err := json.Marshal(a.Snapshot)
+	if err != nil {
+		return fmt.Errorf("marshaling snapshot: %w", err)
+	}
+	owners, err := json.Marshal(a.Owners)
+	if err != nil {
+		return fmt.Errorf("marshaling owners: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO archived_assets (asset_id, mrn, name, type, snapshot, owners, last_sync_at, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, archived_at`,
+		a.AssetID, a.MRN, a.Name, a.Type, snapshot, owners, a.LastSyncAt, a.Reason,
+	).Scan(&a.ID, &a.ArchivedAt)
+	if err != nil {
+		return fmt.Errorf("creating archived asset: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE assets SET archived_at = $2
+		WHERE id = $1 AND archived_at IS NULL`, a.AssetID, a.ArchivedAt)
+	if err != nil {
+		return fmt.Errorf("archiving asset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM asset_archival_candidates WHERE asset_id = $1`, a.AssetID); err != nil {
+		return fmt.Errorf("deleting archival candidate: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Unarchive returns an archived asset to search and listings and removes
+// its archive record. ok is false, and nothing is changed, when the asset
+// no longer exists.
+func (r *PostgresRepository) Unarchive(ctx context.Context, a *ArchivedAsset) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE assets SET archived_at = NULL WHERE id = $1`, a.AssetID)
+	if err != nil {
+		return false, fmt.Errorf("unarchiving asset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	tag, err = tx.Exec(ctx, `DELETE FROM archived_assets WHERE id = $1`, a.ID)
+	if err != nil {
+		return false, fmt.Errorf("deleting archived asset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, ErrNotFound
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// RestoreResynced unarchives assets that have been synced since they were
+// archived, i.e. the source reports them again.
+func (r *PostgresRepository) RestoreResynced(ctx context.Context) (int, error) {
+	var restored int
+	err := r.db.QueryRow(ctx, `
+		WITH restored AS (
+			UPDATE assets SET archived_at = NULL
+			WHERE archived_at IS NOT NULL AND last_sync_at > archived_at
+			RETURNING id
+		), removed AS (
+			DELETE FROM archived_assets aa
+			USING restored
+			WHERE aa.asset_id = restored.id
+		)
+		SELECT COUNT(*) FROM restored`).Scan(&restored)
+	if err != nil {
+		return 0, fmt.Errorf("restoring resynced assets: %w", err)
+	}
+	return restored, nil
+}
+
+const selectArchived = `
+	SELECT id, asset_id, mrn, name, type, snapshot, owners, last_sync_at, archived_at, reason
+	FROM archived_assets`
+
+func (r *PostgresRepository) GetArchived(ctx context.Context, id string) (*ArchivedAsset, error) {
+	a, err := scanArchived(r.db.QueryRow(ctx, selectArchived+` WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting archived asset: %w", err)
+	}
+	return a, nil
+}
+
+func (r *PostgresRepository) ListArchived(ctx context.Context, limit, offset int) ([]*ArchivedAsset, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM archived_assets`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting archived assets: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectArchived+`
+		ORDER BY archived_at DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing archived assets: %w", err)
+	}
+	defer rows.Close()
+
+	archived := []*ArchivedAsset{}
+	for rows.Next() {
+		a, err := scanArchived(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning archived asset: %w", err)
+		}
+		archived = append(archived, a)
+	}
+	return archived, total, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteArchived(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM archived_assets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting archived asset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanArchived(row pgx.Row) (*ArchivedAsset, error) {
+	a := &ArchivedAsset{}
+	var snapshot, owners []byte
+	if err := row.Scan(&a.ID, &a.AssetID, &a.MRN, &a.Name, &a.Type, &snapshot, &owners,
+		&a.LastSyncAt, &a.ArchivedAt, &a.Reason); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &a.Snapshot); err != nil {
+		return nil, fmt.Errorf("unmarshaling snapshot: %w", err)
+	}
+	if err := json.Unmarshal(owners, &a.Owners); err != nil {
+		return nil, fmt.Errorf("unmarshaling owners: %w", err)
+	}
+	return a, nil
+}
@@ -14,3 +14,7 @@ const (
 	FieldEnvironments    = "environments"
 	FieldExternalLinks   = "external_links"
 )
+
+// LockableFields are the fields that can be locked against ingestion
+// overwrites. Owners aren't among them because ingestion never sets them.
+var LockableFields = []string{FieldDescription, FieldTags}
@@ -0,0 +1,181 @@
+package asset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GroupLevel is a level of the browse tree.
+type GroupLevel string // @name AssetGroupLevel
+
+const (
+	GroupProvider GroupLevel = "provider"
+	GroupDatabase GroupLevel = "database"
+	GroupSchema   GroupLevel = "schema"
+)
+
+// GroupLevels are the levels of the browse tree, outermost first. Below the
+// last level are the assets themselves.
+var GroupLevels = []GroupLevel{GroupProvider, GroupDatabase, GroupSchema}
+
+// groupExprs derive each level from an asset. Plugins name the same concept
+// differently, so the first key a plugin sets wins: a Trino catalog or a
+// BigQuery project sits where a Postgres database does, and a BigQuery
+// dataset where a schema does. Assets without a value group under "".
+var groupExprs = map[GroupLevel]string{
+	GroupProvider: `COALESCE(providers[1], '')`,
+	GroupDatabase: `COALESCE(
+		metadata->>'catalog', metadata->>'catalog_name',
+		metadata->>'database', metadata->>'database_name',
+		metadata->>'project_id', metadata->>'bucket', metadata->>'bucket_name',
+		metadata->>'container_name', '')`,
+	GroupSchema: `COALESCE(
+		metadata->>'schema', metadata->>'schema_name',
+		metadata->>'dataset_id', metadata->>'namespace', '')`,
+}
+
+// GroupPath selects a node of the browse tree by the value at each level,
+// outermost first. An empty string selects the assets without a value.
+type GroupPath []string
+
+// Group is a child of a browse tree node.
+type Group struct {
+	// Value is empty for the assets without a value at this level.
+	Value string `json:"value"`
+	Count int    `json:"count"`
+} // @name AssetGroup
+
+// GroupResult is one node of the browse tree. Nodes above the last level
+// list their child groups; the deepest nodes list their assets.
+type GroupResult struct {
+	Path GroupPath `json:"path"`
+	// Level is the level of Groups, empty when the node lists assets.
+	Level  GroupLevel `json:"level,omitempty"`
+	Groups []Group    `json:"groups,omitempty"`
+	Assets []*Asset   `json:"assets,omitempty"`
+	// Total is the number of groups or assets, before pagination.
+	Total int `json:"total"`
+} // @name AssetGroupResult
+
+// Browse returns the children of the node at path: its groups at the next
+// level, or its assets when path covers every level.
+func (s *service) Browse(ctx context.Context, path GroupPath, limit, offset int) (*GroupResult, error) {
+	if len(path) > len(GroupLevels) {
+		return nil, fmt.Errorf("%w: group path has %d levels, at most %d allowed", ErrInvalidInput, len(path), len(GroupLevels))
+	}
+	if limit <= 0 {
+		limit = 100
+	} else if limit > 1000 {
+		limit = 1000
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if path == nil {
+		path = GroupPath{}
+	}
+
+	result := &GroupResult{Path: path}
+	if len(path) == len(GroupLevels) {
+		assets, total, err := s.repo.ListGroupAssets(ctx, path, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("listing group assets: %w", err)
+		}
+		result.Assets, result.Total = assets, total
+		return result, nil
+	}
+
+	result.Level = GroupLevels[len(path)]
+	groups, total, err := s.repo.ListGroups(ctx, path, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing asset groups: %w", err)
+	}
+	result.Groups, result.Total = groups, total
+	return result, nil
+}
+
+// groupFilter builds the WHERE clause selecting the assets under path.
+func groupFilter(path GroupPath) (string, []interface{}) {
+	conditions := []string{"is_stub = FALSE", "archived_at IS NULL"}
+	args := make([]interface{}, 0, len(path))
+	for i, value := range path {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", groupExprs[GroupLevels[i]], len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *PostgresRepository) ListGroups(ctx context.Context, path GroupPath, limit, offset int) ([]Group, int, error) {
+	start := time.Now()
+	where, args := groupFilter(path)
+	expr := groupExprs[GroupLevels[len(path)]]
+
+	var total int
+	if err := r.db.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(DISTINCT %s) FROM assets WHERE %s`, expr, where), args...).Scan(&total); err != nil {
+		r.recorder.RecordDBQuery(ctx, "list_asset_groups", time.Since(start), false)
+		return nil, 0, fmt.Errorf("counting asset groups: %w", err)
+	}
+
+	// The empty group sorts last so named groups come first in the tree.
+	n := len(args)
+	args = append(args, limit, offset)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT value, count
+		FROM (
+			SELECT %s AS value, COUNT(*) AS count
+			FROM assets
+			WHERE %s
+			GROUP BY 1
+		) groups
+		ORDER BY value = '', LOWER(value), value
+		LIMIT $%d OFFSET $%d`, expr, where, n+1, n+2), args...)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "list_asset_groups", time.Since(start), false)
+		return nil, 0, fmt.Errorf("querying asset groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.Value, &g.Count); err != nil {
+			r.recorder.RecordDBQuery(ctx, "list_asset_groups", time.Since(start), false)
+			return nil, 0, fmt.Errorf("scanning asset group: %w", err)
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		r.recorder.RecordDBQuery(ctx, "list_asset_groups", time.Since(start), false)
+		return nil, 0, fmt.Errorf("iterating asset groups: %w", err)
+	}
+
+	r.recorder.RecordDBQuery(ctx, "list_asset_groups", time.Since(start), true)
+	return groups, total, nil
+}
+
+func (r *PostgresRepository) ListGroupAssets(ctx context.Context, path GroupPath, limit, offset int) ([]*Asset, int, error) {
+	start := time.Now()
+	where, args := groupFilter(path)
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM assets WHERE "+where, args...).Scan(&total); err != nil {
+		r.recorder.RecordDBQuery(ctx, "list_group_assets", time.Since(start), false)
+		return nil, 0, fmt.Errorf("counting group assets: %w", err)
+	}
+
+	n := len(args)
+	args = append(args, limit, offset)
+	assets, err := r.scanMultipleAssets(ctx, fmt.Sprintf(`%s
+		WHERE %s
+		ORDER BY LOWER(name), id
+		LIMIT $%d OFFSET $%d`, baseSelectAsset, where, n+1, n+2), args...)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "list_group_assets", time.Since(start), false)
+		return nil, 0, fmt.Errorf("querying group assets: %w", err)
+	}
+
+	r.recorder.RecordDBQuery(ctx, "list_group_assets", time.Since(start), true)
+	return assets, total, nil
+}
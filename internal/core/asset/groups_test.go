@@ -0,0 +1,30 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupFilter(t *testing.T) {
+	where, args := groupFilter(nil)
+	assert.Equal(t, "is_stub = FALSE AND archived_at IS NULL", where)
+	assert.Empty(t, args)
+
+	where, args = groupFilter(GroupPath{"postgresql", ""})
+	assert.Equal(t, []interface{}{"postgresql", ""}, args)
+	assert.Contains(t, where, groupExprs[GroupProvider]+" = $1")
+	assert.Contains(t, where, groupExprs[GroupDatabase]+" = $2")
+	assert.False(t, strings.Contains(where, "$3"))
+}
+
+func TestBrowseRejectsDeepPaths(t *testing.T) {
+	s := &service{}
+	_, err := s.Browse(context.Background(), GroupPath{"a", "b", "c", "d"}, 10, 0)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
@@ -0,0 +1,80 @@
+package asset
+
+import "strings"
+
+// Kind groups asset types by the role they play in lineage.
+type Kind string // @name AssetKind
+
+const (
+	// KindDataset assets hold data: tables, topics, files and the like.
+	KindDataset Kind = "dataset"
+	// KindJob assets move or transform data and have runs.
+	KindJob Kind = "job"
+	// KindService assets are deployed applications that produce or consume
+	// data.
+	KindService Kind = "service"
+)
+
+// Metadata keys that tie a job asset to the runs it executes. They hold the
+// OpenLineage job namespace and name recorded in run history.
+const (
+	MetadataJobNamespace = "job_namespace"
+	MetadataJobName      = "job_name"
+)
+
+var jobTypes = map[string]bool{
+	"job":      true,
+	"task":     true,
+	"dag":      true,
+	"pipeline": true,
+	"query":    true,
+	"command":  true,
+}
+
+var serviceTypes = map[string]bool{
+	"service":     true,
+	"endpoint":    true,
+	"application": true,
+}
+
+// KindOf classifies an asset by its type. Assets created from OpenLineage
+// jobs record a job_type in their metadata and are jobs whatever their type,
+// so a dbt model run is a job while a dbt model ingested by the plugin is a
+// dataset.
+func KindOf(assetType string, metadata map[string]interface{}) Kind {
+	t := strings.ToLower(assetType)
+	switch {
+	case serviceTypes[t]:
+		return KindService
+	case jobTypes[t]:
+		return KindJob
+	}
+	if jobType, ok := metadata["job_type"].(string); ok && jobType != "" {
+		return KindJob
+	}
+	if _, ok := JobRefOf(metadata); ok {
+		return KindJob
+	}
+	return KindDataset
+}
+
+// Kind classifies the asset. See KindOf.
+func (a *Asset) Kind() Kind {
+	return KindOf(a.Type, a.Metadata)
+}
+
+// JobRef identifies a job in run history.
+type JobRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+} // @name JobRef
+
+// JobRefOf returns the job an asset's metadata links it to, if any.
+func JobRefOf(metadata map[string]interface{}) (JobRef, bool) {
+	namespace, _ := metadata[MetadataJobNamespace].(string)
+	name, _ := metadata[MetadataJobName].(string)
+	if namespace == "" || name == "" {
+		return JobRef{}, false
+	}
+	return JobRef{Namespace: namespace, Name: name}, true
+}
@@ -0,0 +1,40 @@
+package asset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		assetType string
+		metadata  map[string]interface{}
+		want      Kind
+	}{
+		{"table", "Table", nil, KindDataset},
+		{"glue job", "Job", nil, KindJob},
+		{"airflow dag", "Dag", nil, KindJob},
+		{"airflow pipeline", "Pipeline", nil, KindJob},
+		{"kubernetes service", "Service", nil, KindService},
+		{"api endpoint", "Endpoint", nil, KindService},
+		{"dbt model from plugin", "Model", map[string]interface{}{"materialization": "table"}, KindDataset},
+		{"dbt model from openlineage", "Model", map[string]interface{}{"job_type": "Model"}, KindJob},
+		{"linked to runs", "Function", map[string]interface{}{"job_namespace": "prod", "job_name": "etl"}, KindJob},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.assetType, tt.metadata))
+		})
+	}
+}
+
+func TestJobRefOf(t *testing.T) {
+	ref, ok := JobRefOf(map[string]interface{}{"job_namespace": "airflow", "job_name": "daily.load"})
+	assert.True(t, ok)
+	assert.Equal(t, JobRef{Namespace: "airflow", Name: "daily.load"}, ref)
+
+	_, ok = JobRefOf(map[string]interface{}{"job_name": "daily.load"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,208 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LifecycleStatus is where an asset is in its lifecycle.
+type LifecycleStatus string // @name AssetLifecycleStatus
+
+const (
+	LifecycleActive       LifecycleStatus = "active"
+	LifecycleExperimental LifecycleStatus = "experimental"
+	LifecycleDeprecated   LifecycleStatus = "deprecated"
+	LifecycleArchived     LifecycleStatus = "archived"
+)
+
+// LifecycleStatuses lists every lifecycle status.
+var LifecycleStatuses = []LifecycleStatus{LifecycleActive, LifecycleExperimental, LifecycleDeprecated, LifecycleArchived}
+
+const maxLifecycleReasonLength = 2000
+
+var ErrReplacementNotFound = errors.New("replacement asset not found")
+
+// Lifecycle is an asset's lifecycle status. Reason, ReplacementMRN and
+// SunsetAt are only set for deprecated assets.
+type Lifecycle struct {
+	Status         LifecycleStatus `json:"status"`
+	Reason         string          `json:"reason,omitempty"`
+	ReplacementMRN *string         `json:"replacement_mrn,omitempty"`
+	SunsetAt       *time.Time      `json:"sunset_at,omitempty"`
+	// Banner is the configured notice for the status, empty when there
+	// is none.
+	Banner string `json:"banner,omitempty"`
+} // @name AssetLifecycle
+
+type LifecycleInput struct {
+	Status         LifecycleStatus `json:"status"`
+	Reason         string          `json:"reason,omitempty"`
+	ReplacementMRN *string         `json:"replacement_mrn,omitempty"`
+	SunsetAt       *time.Time      `json:"sunset_at,omitempty"`
+} // @name AssetLifecycleInput
+
+// ParseLifecycleStatus returns the status named s, ignoring case.
+func ParseLifecycleStatus(s string) (LifecycleStatus, bool) {
+	for _, status := range LifecycleStatuses {
+		if strings.EqualFold(s, string(status)) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// RenderBanner fills {status}, {reason}, {replacement} and {sunset} in a
+// banner template from the lifecycle.
+func (l *Lifecycle) RenderBanner(template string) string {
+	if template == "" {
+		return ""
+	}
+	replacement, sunset := "", ""
+	if l.ReplacementMRN != nil {
+		replacement = *l.ReplacementMRN
+	}
+	if l.SunsetAt != nil {
+		sunset = l.SunsetAt.Format("2006-01-02")
+	}
+	banner := strings.NewReplacer(
+		"{status}", string(l.Status),
+		"{reason}", l.Reason,
+		"{replacement}", replacement,
+		"{sunset}", sunset,
+	).Replace(template)
+	return strings.Join(strings.Fields(banner), " ")
+}
+
+func (s *service) GetLifecycle(ctx context.Context, id string) (*Lifecycle, error) {
+	lifecycle, err := s.repo.GetLifecycle(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("getting asset lifecycle: %w", err)
+	}
+	return lifecycle, nil
+}
+
+// SetLifecycle moves an asset to a new lifecycle status. Deprecating an
+// asset records it as a deprecation, so its consumers are tracked, and
+// moving it out of deprecated removes that deprecation.
+func (s *service) SetLifecycle(ctx context.Context, id string, input LifecycleInput, userID string) (*Lifecycle, error) {
+	status, ok := ParseLifecycleStatus(string(input.Status))
+	if !ok {
+		return nil, fmt.Errorf("%w: status must be one of active, experimental, deprecated or archived", ErrInvalidInput)
+	}
+	input.Status = status
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.ReplacementMRN != nil {
+		if mrn := strings.TrimSpace(*input.ReplacementMRN); mrn == "" {
+			input.ReplacementMRN = nil
+		} else {
+			input.ReplacementMRN = &mrn
+		}
+	}
+
+	if status != LifecycleDeprecated && (input.Reason != "" || input.ReplacementMRN != nil || input.SunsetAt != nil) {
+		return nil, fmt.Errorf("%w: reason, replacement and sunset only apply to deprecated assets", ErrInvalidInput)
+	}
+	if len(input.Reason) > maxLifecycleReasonLength {
+		return nil, fmt.Errorf("%w: reason must be %d characters or fewer", ErrInvalidInput, maxLifecycleReasonLength)
+	}
+
+	current, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("getting asset: %w", err)
+	}
+	if input.ReplacementMRN != nil && current.MRN != nil && strings.EqualFold(*input.ReplacementMRN, *current.MRN) {
+		return nil, fmt.Errorf("%w: an asset cannot replace itself", ErrInvalidInput)
+	}
+
+	var setBy *string
+	if userID != "" {
+		setBy = &userID
+	}
+	if err := s.repo.SetLifecycle(ctx, id, input, setBy); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetLifecycle(ctx, id)
+}
+
+func (r *PostgresRepository) GetLifecycle(ctx context.Context, id string) (*Lifecycle, error) {
+	var l Lifecycle
+	var reason *string
+	err := r.db.QueryRow(ctx, `
+		SELECT a.lifecycle_status, d.reason, ra.mrn, d.sunset_at
+		FROM assets a
+		LEFT JOIN asset_deprecations d ON d.asset_id = a.id AND a.lifecycle_status = 'deprecated'
+		LEFT JOIN assets ra ON ra.id = d.replacement_asset_id
+		WHERE a.id = $1`, id).Scan(&l.Status, &reason, &l.ReplacementMRN, &l.SunsetAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if reason != nil {
+		l.Reason = *reason
+	}
+	return &l, nil
+}
+
+func (r *PostgresRepository) SetLifecycle(ctx context.Context, id string, input LifecycleInput, setBy *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE assets SET lifecycle_status = $1 WHERE id = $2`, input.Status, id)
+	if err != nil {
+		return fmt.Errorf("updating asset lifecycle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if input.Status == LifecycleDeprecated {
+		var replacementID *string
+		if input.ReplacementMRN != nil {
+			var rid string
+			err := tx.QueryRow(ctx, `SELECT id FROM assets WHERE LOWER(mrn) = LOWER($1)`, *input.ReplacementMRN).Scan(&rid)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrReplacementNotFound
+				}
+				return fmt.Errorf("resolving replacement asset: %w", err)
+			}
+			replacementID = &rid
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO asset_deprecations (asset_id, replacement_asset_id, reason, sunset_at, deprecated_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (asset_id) DO UPDATE SET
+				replacement_asset_id = EXCLUDED.replacement_asset_id,
+				reason = EXCLUDED.reason,
+				sunset_at = EXCLUDED.sunset_at`,
+			id, replacementID, input.Reason, input.SunsetAt, setBy)
+		if err != nil {
+			return fmt.Errorf("recording asset deprecation: %w", err)
+		}
+	} else if _, err := tx.Exec(ctx, `DELETE FROM asset_deprecations WHERE asset_id = $1`, id); err != nil {
+		return fmt.Errorf("removing asset deprecation: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
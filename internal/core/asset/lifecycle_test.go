@@ -0,0 +1,85 @@
+package asset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleRepo stores a single asset's lifecycle in memory.
+type lifecycleRepo struct {
+	Repository
+	asset     *Asset
+	lifecycle Lifecycle
+}
+
+func (r *lifecycleRepo) Get(_ context.Context, id string) (*Asset, error) {
+	if r.asset.ID != id {
+		return nil, ErrNotFound
+	}
+	return r.asset, nil
+}
+
+func (r *lifecycleRepo) GetLifecycle(_ context.Context, id string) (*Lifecycle, error) {
+	if r.asset.ID != id {
+		return nil, ErrNotFound
+	}
+	l := r.lifecycle
+	return &l, nil
+}
+
+func (r *lifecycleRepo) SetLifecycle(_ context.Context, _ string, input LifecycleInput, _ *string) error {
+	r.lifecycle = Lifecycle{
+		Status:         input.Status,
+		Reason:         input.Reason,
+		ReplacementMRN: input.ReplacementMRN,
+		SunsetAt:       input.SunsetAt,
+	}
+	return nil
+}
+
+func TestSetLifecycle(t *testing.T) {
+	ctx := context.Background()
+	mrn := "postgres://db/public/orders"
+	repo := &lifecycleRepo{asset: &Asset{ID: "a1", MRN: &mrn}}
+	svc := NewService(repo)
+
+	replacement := " postgres://db/public/orders_v2 "
+	l, err := svc.SetLifecycle(ctx, "a1", LifecycleInput{
+		Status:         "Deprecated",
+		Reason:         "  Moved to v2  ",
+		ReplacementMRN: &replacement,
+	}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, LifecycleDeprecated, l.Status)
+	assert.Equal(t, "Moved to v2", l.Reason)
+	assert.Equal(t, "postgres://db/public/orders_v2", *l.ReplacementMRN)
+
+	_, err = svc.SetLifecycle(ctx, "a1", LifecycleInput{Status: "retired"}, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = svc.SetLifecycle(ctx, "a1", LifecycleInput{Status: LifecycleArchived, Reason: "old"}, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	self := "POSTGRES://db/public/orders"
+	_, err = svc.SetLifecycle(ctx, "a1", LifecycleInput{Status: LifecycleDeprecated, ReplacementMRN: &self}, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = svc.SetLifecycle(ctx, "missing", LifecycleInput{Status: LifecycleActive}, "")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+}
+
+func TestRenderBanner(t *testing.T) {
+	replacement := "kafka://orders-v2"
+	sunset := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	l := &Lifecycle{Status: LifecycleDeprecated, Reason: "Schema rewrite.", ReplacementMRN: &replacement, SunsetAt: &sunset}
+
+	assert.Equal(t,
+		"Deprecated: Schema rewrite. Use kafka://orders-v2 by 2026-03-01.",
+		l.RenderBanner("Deprecated: {reason} Use {replacement} by {sunset}."))
+	assert.Equal(t, "This asset is deprecated.", (&Lifecycle{Status: LifecycleDeprecated}).RenderBanner("This asset is {status}. {reason}"))
+	assert.Empty(t, l.RenderBanner(""))
+}
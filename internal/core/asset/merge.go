@@ -0,0 +1,311 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MergeStrategy decides how values reported by different sources for the
+// same asset field are combined.
+type MergeStrategy string
+
+const (
+	// MergeLastWrite keeps the value from the most recent run.
+	MergeLastWrite MergeStrategy = "last_write"
+	// MergePriority keeps the value from the highest-priority source that
+	// reported one.
+	MergePriority MergeStrategy = "priority"
+	// MergeUnion combines the values from every source.
+	MergeUnion MergeStrategy = "union"
+	// MergeKeys merges maps key by key; higher-priority sources win
+	// conflicting keys.
+	MergeKeys MergeStrategy = "merge"
+)
+
+// contributionKey is the AssetSource property holding what a source last
+// reported for the asset.
+const contributionKey = "contributed"
+
+// mergeFields lists the fields governed by a MergePolicy and the strategies
+// each accepts. The first strategy is the default.
+var mergeFields = map[string][]MergeStrategy{
+	FieldDescription:   {MergePriority, MergeLastWrite},
+	FieldQuery:         {MergePriority, MergeLastWrite},
+	FieldTags:          {MergeUnion, MergePriority, MergeLastWrite},
+	FieldExternalLinks: {MergeUnion, MergePriority, MergeLastWrite},
+	FieldMetadata:      {MergeKeys, MergePriority, MergeLastWrite},
+	FieldSchema:        {MergePriority, MergeKeys, MergeLastWrite},
+}
+
+// MergePolicy resolves asset fields when several sources report the same
+// asset. Each source's latest values are kept on its AssetSource entry, so
+// the result doesn't depend on the order runs happen in.
+type MergePolicy struct {
+	sourcePriority map[string]int
+	fields         map[string]MergeStrategy
+}
+
+// NewMergePolicy builds a policy from source priorities (higher wins) and
+// per-field strategy overrides.
+func NewMergePolicy(sourcePriority map[string]int, fields map[string]string) (*MergePolicy, error) {
+	p := &MergePolicy{
+		sourcePriority: make(map[string]int, len(sourcePriority)),
+		fields:         make(map[string]MergeStrategy, len(mergeFields)),
+	}
+	for source, priority := range sourcePriority {
+		p.sourcePriority[strings.ToLower(source)] = priority
+	}
+	for field, allowed := range mergeFields {
+		p.fields[field] = allowed[0]
+	}
+
+	for field, strategy := range fields {
+		allowed, ok := mergeFields[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q has no merge strategy", ErrInvalidInput, field)
+		}
+		valid := false
+		for _, s := range allowed {
+			if string(s) == strategy {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: merge strategy %q is not supported for %s", ErrInvalidInput, strategy, field)
+		}
+		p.fields[field] = MergeStrategy(strategy)
+	}
+
+	return p, nil
+}
+
+// Priority returns the configured priority of a source, 0 if unset.
+func (p *MergePolicy) Priority(source string) int {
+	return p.sourcePriority[strings.ToLower(source)]
+}
+
+// Strategy returns the strategy for a field.
+func (p *MergePolicy) Strategy(field string) MergeStrategy {
+	if s, ok := p.fields[field]; ok {
+		return s
+	}
+	return MergeLastWrite
+}
+
+// Merges reports whether the policy governs field.
+func (p *MergePolicy) Merges(field string) bool {
+	_, ok := mergeFields[field]
+	return ok
+}
+
+// SourceContribution is what one source reported for an asset.
+type SourceContribution struct {
+	Description   *string                `json:"description,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Schema        map[string]string      `json:"schema,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	ExternalLinks []ExternalLink         `json:"external_links,omitempty"`
+	Query         *string                `json:"query,omitempty"`
+}
+
+// NewSource returns the AssetSource entry recording a source's contribution.
+func (p *MergePolicy) NewSource(source string, c SourceContribution, syncedAt time.Time) AssetSource {
+	props := map[string]interface{}{}
+	if data, err := json.Marshal(c); err == nil {
+		var m map[string]interface{}
+		if json.Unmarshal(data, &m) == nil {
+			props[contributionKey] = m
+		}
+	}
+	return AssetSource{
+		Name:       source,
+		LastSyncAt: syncedAt,
+		Properties: props,
+		Priority:   p.Priority(source),
+	}
+}
+
+// Contributions returns what each source last reported for the asset, by
+// source name. Sources synced before merging was enabled are left out.
+func Contributions(a *Asset) map[string]SourceContribution {
+	out := make(map[string]SourceContribution, len(a.Sources))
+	for _, src := range a.Sources {
+		if c, ok := decodeContribution(src); ok {
+			out[src.Name] = c
+		}
+	}
+	return out
+}
+
+func decodeContribution(src AssetSource) (SourceContribution, bool) {
+	var sc SourceContribution
+	raw, ok := src.Properties[contributionKey]
+	if !ok {
+		return sc, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil || json.Unmarshal(data, &sc) != nil {
+		return sc, false
+	}
+	return sc, true
+}
+
+type contributor struct {
+	source       AssetSource
+	contribution SourceContribution
+}
+
+// Resolve applies a source's contribution to an existing asset and returns
+// the update to store. Sources recorded before the policy was enabled carry
+// no contribution and are ignored.
+func (p *MergePolicy) Resolve(existing *Asset, source string, c SourceContribution, syncedAt time.Time) UpdateInput {
+	incoming := p.NewSource(source, c, syncedAt)
+
+	var contributors []contributor
+	for _, src := range UpdateSources(existing.Sources, []AssetSource{incoming}) {
+		if src.Name == source {
+			contributors = append(contributors, contributor{source: incoming, contribution: c})
+			continue
+		}
+		sc, ok := decodeContribution(src)
+		if !ok {
+			continue
+		}
+		// Priorities come from the current configuration, not the one in
+		// force when the source last ran.
+		src.Priority = p.Priority(src.Name)
+		contributors = append(contributors, contributor{source: src, contribution: sc})
+	}
+
+	// Highest priority first; most recently synced breaks ties.
+	sort.SliceStable(contributors, func(i, j int) bool {
+		a, b := contributors[i].source, contributors[j].source
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.LastSyncAt.Equal(b.LastSyncAt) {
+			return a.LastSyncAt.After(b.LastSyncAt)
+		}
+		return a.Name < b.Name
+	})
+
+	update := UpdateInput{Sources: []AssetSource{incoming}}
+
+	switch p.Strategy(FieldDescription) {
+	case MergePriority:
+		for _, ct := range contributors {
+			if ct.contribution.Description != nil && *ct.contribution.Description != "" {
+				update.Description = ct.contribution.Description
+				break
+			}
+		}
+	default:
+		update.Description = c.Description
+	}
+
+	switch p.Strategy(FieldQuery) {
+	case MergePriority:
+		for _, ct := range contributors {
+			if ct.contribution.Query != nil && *ct.contribution.Query != "" {
+				update.Query = ct.contribution.Query
+				break
+			}
+		}
+	default:
+		update.Query = c.Query
+	}
+
+	switch p.Strategy(FieldTags) {
+	case MergeUnion:
+		seen := map[string]bool{}
+		tags := []string{}
+		for _, ct := range contributors {
+			for _, tag := range ct.contribution.Tags {
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+		}
+		update.Tags = tags
+	case MergePriority:
+		for _, ct := range contributors {
+			if len(ct.contribution.Tags) > 0 {
+				update.Tags = ct.contribution.Tags
+				break
+			}
+		}
+	default:
+		update.Tags = c.Tags
+	}
+
+	switch p.Strategy(FieldExternalLinks) {
+	case MergeUnion:
+		seen := map[string]bool{}
+		links := []ExternalLink{}
+		for _, ct := range contributors {
+			for _, link := range ct.contribution.ExternalLinks {
+				if !seen[link.URL] {
+					seen[link.URL] = true
+					links = append(links, link)
+				}
+			}
+		}
+		update.ExternalLinks = links
+	case MergePriority:
+		for _, ct := range contributors {
+			if len(ct.contribution.ExternalLinks) > 0 {
+				update.ExternalLinks = ct.contribution.ExternalLinks
+				break
+			}
+		}
+	default:
+		update.ExternalLinks = c.ExternalLinks
+	}
+
+	switch p.Strategy(FieldMetadata) {
+	case MergeKeys:
+		metadata := map[string]interface{}{}
+		for i := len(contributors) - 1; i >= 0; i-- {
+			for k, v := range contributors[i].contribution.Metadata {
+				metadata[k] = v
+			}
+		}
+		update.Metadata = metadata
+	case MergePriority:
+		for _, ct := range contributors {
+			if len(ct.contribution.Metadata) > 0 {
+				update.Metadata = ct.contribution.Metadata
+				break
+			}
+		}
+	default:
+		update.Metadata = c.Metadata
+	}
+
+	switch p.Strategy(FieldSchema) {
+	case MergeKeys:
+		schema := map[string]string{}
+		for i := len(contributors) - 1; i >= 0; i-- {
+			for k, v := range contributors[i].contribution.Schema {
+				schema[k] = v
+			}
+		}
+		update.Schema = schema
+	case MergePriority:
+		for _, ct := range contributors {
+			if len(ct.contribution.Schema) > 0 {
+				update.Schema = ct.contribution.Schema
+				break
+			}
+		}
+	default:
+		update.Schema = c.Schema
+	}
+
+	return update
+}
@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePolicyResolve(t *testing.T) {
+	policy, err := NewMergePolicy(map[string]int{"dbt": 100, "trino": 10}, nil)
+	require.NoError(t, err)
+
+	dbtDesc, trinoDesc := "Orders placed by customers", "orders table"
+	now := time.Now()
+
+	existing := &Asset{Sources: []AssetSource{
+		policy.NewSource("dbt", SourceContribution{
+			Description: &dbtDesc,
+			Tags:        []string{"finance"},
+			Metadata:    map[string]interface{}{"owner": "analytics", "materialization": "table"},
+			Schema:      map[string]string{"dbt": "{}"},
+		}, now.Add(-time.Hour)),
+		{Name: "legacy"},
+	}}
+
+	update := policy.Resolve(existing, "trino", SourceContribution{
+		Description: &trinoDesc,
+		Tags:        []string{"warehouse", "finance"},
+		Metadata:    map[string]interface{}{"owner": "platform", "rows": 10},
+		Schema:      map[string]string{"trino": "{}"},
+	}, now)
+
+	require.NotNil(t, update.Description)
+	assert.Equal(t, dbtDesc, *update.Description)
+	assert.ElementsMatch(t, []string{"finance", "warehouse"}, update.Tags)
+	assert.Equal(t, map[string]interface{}{"owner": "analytics", "materialization": "table", "rows": 10}, update.Metadata)
+	assert.Equal(t, map[string]string{"dbt": "{}"}, update.Schema)
+	require.Len(t, update.Sources, 1)
+	assert.Equal(t, "trino", update.Sources[0].Name)
+	assert.Equal(t, 10, update.Sources[0].Priority)
+}
+
+func TestNewMergePolicyValidatesStrategies(t *testing.T) {
+	_, err := NewMergePolicy(nil, map[string]string{"tags": "merge"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = NewMergePolicy(nil, map[string]string{"owners": "union"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	policy, err := NewMergePolicy(nil, map[string]string{"description": "last_write"})
+	require.NoError(t, err)
+	assert.Equal(t, MergeLastWrite, policy.Strategy(FieldDescription))
+	assert.Equal(t, MergeUnion, policy.Strategy(FieldTags))
+}
@@ -0,0 +1,137 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidMetadataField is returned when a metadata profile is requested
+// without a usable field path.
+var ErrInvalidMetadataField = errors.New("invalid metadata field")
+
+// MetadataProfile describes how a single metadata field is distributed
+// across non-stub assets.
+type MetadataProfile struct {
+	Field         string                    `json:"field"`
+	TotalAssets   int                       `json:"total_assets"`
+	PresentCount  int                       `json:"present_count"`
+	Coverage      float64                   `json:"coverage"`
+	DistinctCount int                       `json:"distinct_count"`
+	TopValues     []MetadataValueSuggestion `json:"top_values"`
+	Numeric       *NumericProfile           `json:"numeric,omitempty"`
+} // @name MetadataProfile
+
+// NumericProfile holds summary statistics for the numeric values of a
+// metadata field. Non-numeric values are ignored.
+type NumericProfile struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P25   float64 `json:"p25"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+} // @name NumericProfile
+
+// GetMetadataProfile computes distribution statistics for a metadata field.
+// Nested fields are addressed with dots, e.g. "owner.team".
+func (r *PostgresRepository) GetMetadataProfile(ctx context.Context, field string, limit int) (*MetadataProfile, error) {
+	start := time.Now()
+	path := strings.Split(field, ".")
+
+	profile := &MetadataProfile{
+		Field:     field,
+		TopValues: []MetadataValueSuggestion{},
+	}
+
+	const valuesCTE = `
+		WITH vals AS (
+			SELECT metadata #> $1 AS v
+			FROM assets
+			WHERE is_stub = FALSE
+			AND metadata #> $1 IS NOT NULL
+			AND jsonb_typeof(metadata #> $1) <> 'null'
+		)`
+
+	err := r.db.QueryRow(ctx, valuesCTE+`
+		SELECT
+			(SELECT COUNT(*) FROM assets WHERE is_stub = FALSE),
+			COUNT(*),
+			COUNT(DISTINCT v)
+		FROM vals`, path).Scan(&profile.TotalAssets, &profile.PresentCount, &profile.DistinctCount)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "asset_metadata_profile", time.Since(start), false)
+		return nil, fmt.Errorf("querying metadata field counts: %w", err)
+	}
+
+	if profile.TotalAssets > 0 {
+		profile.Coverage = float64(profile.PresentCount) / float64(profile.TotalAssets)
+	}
+
+	if profile.PresentCount == 0 {
+		r.recorder.RecordDBQuery(ctx, "asset_metadata_profile", time.Since(start), true)
+		return profile, nil
+	}
+
+	topValues, err := r.scanMetadataValues(ctx, valuesCTE+`
+		SELECT
+			CASE WHEN jsonb_typeof(v) = 'string' THEN v #>> '{}' ELSE v::text END as value,
+			COUNT(*) as count
+		FROM vals
+		GROUP BY value
+		ORDER BY count DESC, value ASC
+		LIMIT $2`, path, limit)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "asset_metadata_profile", time.Since(start), false)
+		return nil, fmt.Errorf("querying top metadata values: %w", err)
+	}
+	profile.TopValues = topValues
+
+	var (
+		numericCount int
+		minVal       *float64
+		maxVal       *float64
+		meanVal      *float64
+		percentiles  []float64
+	)
+	err = r.db.QueryRow(ctx, valuesCTE+`
+		SELECT
+			COUNT(n),
+			MIN(n),
+			MAX(n),
+			AVG(n),
+			percentile_cont(ARRAY[0.25, 0.5, 0.75, 0.9, 0.99]) WITHIN GROUP (ORDER BY n)
+		FROM (
+			SELECT (v #>> '{}')::double precision AS n
+			FROM vals
+			WHERE jsonb_typeof(v) = 'number'
+		) numeric_vals`, path).Scan(&numericCount, &minVal, &maxVal, &meanVal, &percentiles)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		r.recorder.RecordDBQuery(ctx, "asset_metadata_profile", time.Since(start), false)
+		return nil, fmt.Errorf("querying numeric metadata stats: %w", err)
+	}
+
+	if numericCount > 0 && minVal != nil && maxVal != nil && meanVal != nil && len(percentiles) == 5 {
+		profile.Numeric = &NumericProfile{
+			Count: numericCount,
+			Min:   *minVal,
+			Max:   *maxVal,
+			Mean:  *meanVal,
+			P25:   percentiles[0],
+			P50:   percentiles[1],
+			P75:   percentiles[2],
+			P90:   percentiles[3],
+			P99:   percentiles[4],
+		}
+	}
+
+	r.recorder.RecordDBQuery(ctx, "asset_metadata_profile", time.Since(start), true)
+	return profile, nil
+}
@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profileRepo records the arguments of GetMetadataProfile.
+type profileRepo struct {
+	Repository
+	field string
+	limit int
+	err   error
+}
+
+func (r *profileRepo) GetMetadataProfile(_ context.Context, field string, limit int) (*MetadataProfile, error) {
+	r.field = field
+	r.limit = limit
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &MetadataProfile{Field: field, TotalAssets: 4, PresentCount: 2, Coverage: 0.5}, nil
+}
+
+func TestGetMetadataProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		limit     int
+		wantField string
+		wantLimit int
+	}{
+		{name: "plain field", field: "owner", limit: 5, wantField: "owner", wantLimit: 5},
+		{name: "nested field", field: "owner.team", limit: 5, wantField: "owner.team", wantLimit: 5},
+		{name: "metadata prefix stripped", field: " metadata.region ", limit: 5, wantField: "region", wantLimit: 5},
+		{name: "default limit", field: "owner", wantField: "owner", wantLimit: 10},
+		{name: "negative limit", field: "owner", limit: -1, wantField: "owner", wantLimit: 10},
+		{name: "limit capped", field: "owner", limit: 1000, wantField: "owner", wantLimit: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &profileRepo{}
+			svc := NewService(repo)
+
+			profile, err := svc.GetMetadataProfile(context.Background(), tt.field, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantField, repo.field)
+			assert.Equal(t, tt.wantLimit, repo.limit)
+			assert.Equal(t, tt.wantField, profile.Field)
+			assert.Equal(t, 0.5, profile.Coverage)
+		})
+	}
+}
+
+func TestGetMetadataProfile_InvalidField(t *testing.T) {
+	for _, field := range []string{"", "  ", "metadata.", ".owner", "owner.", "owner..team"} {
+		t.Run(field, func(t *testing.T) {
+			repo := &profileRepo{}
+			svc := NewService(repo)
+
+			_, err := svc.GetMetadataProfile(context.Background(), field, 10)
+			assert.ErrorIs(t, err, ErrInvalidMetadataField)
+			assert.Empty(t, repo.field, "repository should not be queried")
+		})
+	}
+}
+
+func TestGetMetadataProfile_RepositoryError(t *testing.T) {
+	boom := errors.New("boom")
+	svc := NewService(&profileRepo{err: boom})
+
+	_, err := svc.GetMetadataProfile(context.Background(), "owner", 10)
+	assert.ErrorIs(t, err, boom)
+}
@@ -0,0 +1,387 @@
+package asset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Revision sources record what kind of actor changed an asset.
+const (
+	RevisionSourceUser      = "user"
+	RevisionSourceIngestion = "ingestion"
+	RevisionSourceSystem    = "system"
+)
+
+// MaxRevisionsPerAsset is how many revisions are kept for each asset. Older
+// ones are dropped as new ones are recorded.
+const MaxRevisionsPerAsset = 200
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionFields are the fields whose changes are recorded in an asset's
+// revision history.
+var RevisionFields = []string{
+	FieldName, FieldDescription, FieldUserDescription, FieldMetadata,
+	FieldSchema, FieldTags, FieldExternalLinks, FieldQuery,
+}
+
+// RevertibleFields are the user-editable fields that can be reverted to an
+// earlier revision.
+var RevertibleFields = []string{
+	FieldDescription, FieldUserDescription, FieldMetadata, FieldTags, FieldExternalLinks,
+}
+
+// FieldChange is a field's value before and after a change, as JSON.
+type FieldChange struct {
+	Old json.RawMessage `json:"old" swaggertype:"object"`
+	New json.RawMessage `json:"new" swaggertype:"object"`
+} // @name AssetFieldChange
+
+// Revision is one recorded change to an asset. IDs increase over time.
+type Revision struct {
+	ID            int64                  `json:"id"`
+	AssetID       string                 `json:"asset_id"`
+	ChangedFields []string               `json:"changed_fields"`
+	Changes       map[string]FieldChange `json:"changes"`
+	ChangedBy     string                 `json:"changed_by,omitempty"`
+	Source        string                 `json:"source" enums:"user,ingestion,system"`
+	CreatedAt     time.Time              `json:"created_at"`
+} // @name AssetRevision
+
+// RevisionDiff is the net change to an asset between two revisions.
+type RevisionDiff struct {
+	AssetID string                 `json:"asset_id"`
+	From    int64                  `json:"from"`
+	To      int64                  `json:"to"`
+	Changes map[string]FieldChange `json:"changes"`
+} // @name AssetRevisionDiff
+
+// Actor identifies who is changing an asset, for its revision history.
+type Actor struct {
+	Name   string
+	Source string
+}
+
+type actorKey struct{}
+
+// WithActor attributes asset changes made with ctx to actor. Changes made
+// without one are recorded as system changes.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) Actor {
+	if a, ok := ctx.Value(actorKey{}).(Actor); ok && a.Source != "" {
+		return a
+	}
+	return Actor{Source: RevisionSourceSystem}
+}
+
+// recordRevision stores the tracked fields that differ between old and
+// updated. Failing to record doesn't fail the update.
+func (s *service) recordRevision(ctx context.Context, old, updated *Asset) {
+	changes := make(map[string]FieldChange)
+	fields := []string{}
+	for _, f := range RevisionFields {
+		oldJSON, err := json.Marshal(fieldValue(old, f))
+		if err != nil {
+			log.Warn().Err(err).Str("field", f).Msg("Failed to encode revision value")
+			return
+		}
+		newJSON, err := json.Marshal(fieldValue(updated, f))
+		if err != nil {
+			log.Warn().Err(err).Str("field", f).Msg("Failed to encode revision value")
+			return
+		}
+		if jsonEqual(oldJSON, newJSON) {
+			continue
+		}
+		changes[f] = FieldChange{Old: oldJSON, New: newJSON}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	actor := actorFrom(ctx)
+	rev := &Revision{
+		AssetID:       updated.ID,
+		ChangedFields: fields,
+		Changes:       changes,
+		ChangedBy:     actor.Name,
+		Source:        actor.Source,
+	}
+	if err := s.repo.CreateRevision(ctx, rev); err != nil {
+		log.Warn().Err(err).Str("asset_id", updated.ID).Msg("Failed to record asset revision")
+	}
+}
+
+func (s *service) ListRevisions(ctx context.Context, assetID string, limit, offset int) ([]*Revision, int, error) {
+	if _, err := s.Get(ctx, assetID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListRevisions(ctx, assetID, limit, offset)
+}
+
+func (s *service) GetRevision(ctx context.Context, assetID string, id int64) (*Revision, error) {
+	return s.repo.GetRevision(ctx, assetID, id)
+}
+
+// DiffRevisions returns how the asset changed from just after revision from
+// to just after revision to. from may be 0 for the state before the oldest
+// kept revision.
+func (s *service) DiffRevisions(ctx context.Context, assetID string, from, to int64) (*RevisionDiff, error) {
+	if from > to {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidInput)
+	}
+	for _, id := range []int64{from, to} {
+		if id == 0 {
+			continue
+		}
+		if _, err := s.repo.GetRevision(ctx, assetID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	revs, err := s.repo.ListRevisionsBetween(ctx, assetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{AssetID: assetID, From: from, To: to, Changes: netChanges(revs)}, nil
+}
+
+// RevertToRevision sets fields back to their values just after the
+// revision. Only RevertibleFields can be reverted; no fields means all of
+// them. The revert is itself recorded as a new revision.
+func (s *service) RevertToRevision(ctx context.Context, assetID string, id int64, fields []string) (*Asset, error) {
+	if len(fields) == 0 {
+		fields = RevertibleFields
+	}
+	for _, f := range fields {
+		if !slices.Contains(RevertibleFields, f) {
+			return nil, fmt.Errorf("%w: field %q cannot be reverted", ErrInvalidInput, f)
+		}
+	}
+	if _, err := s.repo.GetRevision(ctx, assetID, id); err != nil {
+		return nil, err
+	}
+
+	later, err := s.repo.ListRevisionsBetween(ctx, assetID, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// The value a field had just after the revision is the old value of
+	// the first later revision that changed it.
+	var input UpdateInput
+	for f, change := range netChanges(later) {
+		if !slices.Contains(fields, f) {
+			continue
+		}
+		if err := setRevertValue(&input, f, change.Old); err != nil {
+			return nil, err
+		}
+	}
+	return s.Update(ctx, assetID, input)
+}
+
+// netChanges folds revisions, oldest first, into one change per field,
+// dropping fields that ended up where they started.
+func netChanges(revs []*Revision) map[string]FieldChange {
+	out := make(map[string]FieldChange)
+	for _, rev := range revs {
+		for f, c := range rev.Changes {
+			if first, ok := out[f]; ok {
+				out[f] = FieldChange{Old: first.Old, New: c.New}
+			} else {
+				out[f] = c
+			}
+		}
+	}
+	for f, c := range out {
+		if jsonEqual(c.Old, c.New) {
+			delete(out, f)
+		}
+	}
+	return out
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func fieldValue(a *Asset, field string) interface{} {
+	switch field {
+	case FieldName:
+		return a.Name
+	case FieldDescription:
+		return a.Description
+	case FieldUserDescription:
+		return a.UserDescription
+	case FieldMetadata:
+		return a.Metadata
+	case FieldSchema:
+		return a.Schema
+	case FieldTags:
+		return a.Tags
+	case FieldExternalLinks:
+		return a.ExternalLinks
+	case FieldQuery:
+		return a.Query
+	}
+	return nil
+}
+
+// setRevertValue sets field on input to the JSON value. Empty values are
+// set to their empty form so Update clears the field.
+func setRevertValue(input *UpdateInput, field string, value json.RawMessage) error {
+	var err error
+	switch field {
+	case FieldDescription:
+		if err = json.Unmarshal(value, &input.Description); input.Description == nil {
+			input.Description = new(string)
+		}
+	case FieldUserDescription:
+		if err = json.Unmarshal(value, &input.UserDescription); input.UserDescription == nil {
+			input.UserDescription = new(string)
+		}
+	case FieldMetadata:
+		if err = json.Unmarshal(value, &input.Metadata); input.Metadata == nil {
+			input.Metadata = map[string]interface{}{}
+		}
+	case FieldTags:
+		if err = json.Unmarshal(value, &input.Tags); input.Tags == nil {
+			input.Tags = []string{}
+		}
+	case FieldExternalLinks:
+		if err = json.Unmarshal(value, &input.ExternalLinks); input.ExternalLinks == nil {
+			input.ExternalLinks = []ExternalLink{}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("decoding %s from revision: %w", field, err)
+	}
+	return nil
+}
+
+const revisionColumns = `id, asset_id, changed_fields, changes, COALESCE(changed_by, ''), source, created_at`
+
+// CreateRevision stores rev and drops the asset's oldest revisions beyond
+// MaxRevisionsPerAsset.
+func (r *PostgresRepository) CreateRevision(ctx context.Context, rev *Revision) error {
+	changesJSON, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return fmt.Errorf("marshaling revision changes: %w", err)
+	}
+
+	var changedBy *string
+	if rev.ChangedBy != "" {
+		changedBy = &rev.ChangedBy
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO asset_revisions (asset_id, changed_fields, changes, changed_by, source)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		rev.AssetID, rev.ChangedFields, changesJSON, changedBy, rev.Source,
+	).Scan(&rev.ID, &rev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting revision: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM asset_revisions
+		WHERE asset_id = $1 AND id <= (
+			SELECT id FROM asset_revisions WHERE asset_id = $1
+			ORDER BY id DESC OFFSET $2 LIMIT 1
+		)`, rev.AssetID, MaxRevisionsPerAsset)
+	if err != nil {
+		return fmt.Errorf("pruning revisions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) ListRevisions(ctx context.Context, assetID string, limit, offset int) ([]*Revision, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_revisions WHERE asset_id = $1`, assetID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting revisions: %w", err)
+	}
+
+	revs, err := r.queryRevisions(ctx, `
+		SELECT `+revisionColumns+`
+		FROM asset_revisions
+		WHERE asset_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return revs, total, nil
+}
+
+func (r *PostgresRepository) GetRevision(ctx context.Context, assetID string, id int64) (*Revision, error) {
+	revs, err := r.queryRevisions(ctx, `
+		SELECT `+revisionColumns+`
+		FROM asset_revisions
+		WHERE asset_id = $1 AND id = $2`, assetID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, ErrRevisionNotFound
+	}
+	return revs[0], nil
+}
+
+func (r *PostgresRepository) ListRevisionsBetween(ctx context.Context, assetID string, afterID, uptoID int64) ([]*Revision, error) {
+	return r.queryRevisions(ctx, `
+		SELECT `+revisionColumns+`
+		FROM asset_revisions
+		WHERE asset_id = $1 AND id > $2 AND ($3 = 0 OR id <= $3)
+		ORDER BY id ASC`, assetID, afterID, uptoID)
+}
+
+func (r *PostgresRepository) queryRevisions(ctx context.Context, sql string, args ...interface{}) ([]*Revision, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revs := []*Revision{}
+	for rows.Next() {
+		var rev Revision
+		var changesJSON []byte
+		if err := rows.Scan(&rev.ID, &rev.AssetID, &rev.ChangedFields, &changesJSON,
+			&rev.ChangedBy, &rev.Source, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning revision: %w", err)
+		}
+		if err := json.Unmarshal(changesJSON, &rev.Changes); err != nil {
+			return nil, fmt.Errorf("unmarshaling revision changes: %w", err)
+		}
+		revs = append(revs, &rev)
+	}
+	return revs, rows.Err()
+}
@@ -0,0 +1,111 @@
+package asset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revisionRepo stores a single asset and its revisions in memory.
+type revisionRepo struct {
+	Repository
+	asset     *Asset
+	revisions []*Revision
+}
+
+func (r *revisionRepo) Get(_ context.Context, id string) (*Asset, error) {
+	if r.asset == nil || r.asset.ID != id {
+		return nil, ErrNotFound
+	}
+	copied := *r.asset
+	return &copied, nil
+}
+
+func (r *revisionRepo) Update(_ context.Context, a *Asset) error {
+	copied := *a
+	r.asset = &copied
+	return nil
+}
+
+func (r *revisionRepo) CreateRevision(_ context.Context, rev *Revision) error {
+	rev.ID = int64(len(r.revisions) + 1)
+	r.revisions = append(r.revisions, rev)
+	return nil
+}
+
+func (r *revisionRepo) GetRevision(_ context.Context, _ string, id int64) (*Revision, error) {
+	if id < 1 || int(id) > len(r.revisions) {
+		return nil, ErrRevisionNotFound
+	}
+	return r.revisions[id-1], nil
+}
+
+func (r *revisionRepo) ListRevisionsBetween(_ context.Context, _ string, afterID, uptoID int64) ([]*Revision, error) {
+	var out []*Revision
+	for _, rev := range r.revisions {
+		if rev.ID > afterID && (uptoID == 0 || rev.ID <= uptoID) {
+			out = append(out, rev)
+		}
+	}
+	return out, nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestRevisionsRecordDiffAndRevert(t *testing.T) {
+	ctx := context.Background()
+	repo := &revisionRepo{asset: &Asset{ID: "a1", Name: strPtr("orders"), Tags: []string{"raw"}}}
+	svc := NewService(repo)
+
+	userCtx := WithActor(ctx, Actor{Name: "Jane", Source: RevisionSourceUser})
+	_, err := svc.Update(userCtx, "a1", UpdateInput{Description: strPtr("Orders table")})
+	require.NoError(t, err)
+	_, err = svc.AddTag(userCtx, "a1", "pii")
+	require.NoError(t, err)
+	_, err = svc.Update(ctx, "a1", UpdateInput{
+		Description: strPtr("Raw orders"),
+		Metadata:    map[string]interface{}{"owner": "etl"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, repo.revisions, 3)
+	assert.Equal(t, []string{FieldDescription}, repo.revisions[0].ChangedFields)
+	assert.Equal(t, "Jane", repo.revisions[0].ChangedBy)
+	assert.Equal(t, RevisionSourceUser, repo.revisions[1].Source)
+	assert.JSONEq(t, `["raw","pii"]`, string(repo.revisions[1].Changes[FieldTags].New))
+	assert.Equal(t, RevisionSourceSystem, repo.revisions[2].Source)
+
+	diff, err := svc.DiffRevisions(ctx, "a1", 1, 3)
+	require.NoError(t, err)
+	assert.Len(t, diff.Changes, 3)
+	assert.JSONEq(t, `"Orders table"`, string(diff.Changes[FieldDescription].Old))
+	assert.JSONEq(t, `"Raw orders"`, string(diff.Changes[FieldDescription].New))
+	assert.JSONEq(t, `["raw"]`, string(diff.Changes[FieldTags].Old))
+
+	_, err = svc.DiffRevisions(ctx, "a1", 3, 1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	reverted, err := svc.RevertToRevision(userCtx, "a1", 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Orders table", *reverted.Description)
+	assert.Equal(t, []string{"raw"}, reverted.Tags)
+	assert.Empty(t, reverted.Metadata)
+	assert.Equal(t, "orders", *reverted.Name)
+	require.Len(t, repo.revisions, 4)
+	assert.Equal(t, "Jane", repo.revisions[3].ChangedBy)
+
+	_, err = svc.RevertToRevision(userCtx, "a1", 1, []string{FieldSchema})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = svc.RevertToRevision(userCtx, "a1", 9, nil)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+func TestNetChangesDropsRoundTrips(t *testing.T) {
+	revs := []*Revision{
+		{ID: 1, Changes: map[string]FieldChange{FieldTags: {Old: []byte(`["a"]`), New: []byte(`["a","b"]`)}}},
+		{ID: 2, Changes: map[string]FieldChange{FieldTags: {Old: []byte(`["a","b"]`), New: []byte(`["a"]`)}}},
+	}
+	assert.Empty(t, netChanges(revs))
+}
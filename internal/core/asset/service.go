@@ -16,6 +16,10 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// MetadataTTLKey is the metadata key holding an asset's time to live in
+// seconds. Assets that are not synced again within that time are archived.
+const MetadataTTLKey = "ttl_seconds"
+
 type AssetSource struct {
 	Name       string                 `json:"name"`
 	LastSyncAt time.Time              `json:"last_sync_at"`
@@ -48,6 +52,9 @@ type Asset struct {
 	IsStub          bool                   `json:"is_stub"`
 	ExternalLinks   []ExternalLink         `json:"external_links,omitempty"`
 	HasRunHistory   bool                   `json:"has_run_history"`
+	LockedFields    []string               `json:"locked_fields,omitempty"`
+	Status          LifecycleStatus        `json:"status,omitempty"`
+	ArchivedAt      *time.Time             `json:"archived_at,omitempty"`
 	CreatedAt       time.Time              `json:"created_at,omitempty"`
 	UpdatedAt       time.Time              `json:"updated_at,omitempty"`
 	LastSyncAt      time.Time              `json:"last_sync_at,omitempty"`
@@ -183,6 +190,10 @@ var (
 
 type Service interface {
 	Create(ctx context.Context, input CreateInput) (*Asset, error)
+	// CreateMany creates several assets with one insert, returning the
+	// created asset or the error for each input in order. An input whose
+	// MRN exists, or repeats an earlier input's, fails with ErrAlreadyExists.
+	CreateMany(ctx context.Context, inputs []CreateInput) ([]*Asset, []error)
 	Get(ctx context.Context, id string) (*Asset, error)
 	GetByMRN(ctx context.Context, qualifiedName string) (*Asset, error)
 	Search(ctx context.Context, filter SearchFilter, calculateCounts bool) ([]*Asset, int, AvailableFilters, error)
@@ -198,8 +209,10 @@ type Service interface {
 	GetByTypeAndName(ctx context.Context, assetType, name string) (*Asset, error)
 	GetMetadataFields(ctx context.Context, queryContext *MetadataContext) ([]MetadataFieldSuggestion, error)
 	GetMetadataValues(ctx context.Context, field string, prefix string, limit int, queryContext *MetadataContext) ([]MetadataValueSuggestion, error)
+	GetMetadataProfile(ctx context.Context, field string, limit int) (*MetadataProfile, error)
 	GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]string, error)
 	GetRunHistory(ctx context.Context, assetID string, limit, offset int) ([]*RunHistory, int, error)
+	HasRunHistory(ctx context.Context, assetID string) (bool, error)
 	GetRunHistoryHistogram(ctx context.Context, assetID string, days int) ([]HistogramBucket, error)
 
 	AddTerms(ctx context.Context, assetID string, termIDs []string, source string, createdBy string) error
@@ -207,12 +220,47 @@ type Service interface {
 	GetTerms(ctx context.Context, assetID string) ([]AssetTerm, error)
 	GetAssetsByTerm(ctx context.Context, termID string, limit, offset int) ([]*Asset, int, error)
 
+	// LockFields protects fields from being overwritten by ingestion runs.
+	LockFields(ctx context.Context, id string, fields []string) (*Asset, error)
+	// UnlockFields lets ingestion runs overwrite fields again.
+	UnlockFields(ctx context.Context, id string, fields []string) (*Asset, error)
+
+	// GetLifecycle returns the asset's lifecycle status and, for deprecated
+	// assets, why and what replaces it.
+	GetLifecycle(ctx context.Context, id string) (*Lifecycle, error)
+	// SetLifecycle moves the asset to a new lifecycle status.
+	SetLifecycle(ctx context.Context, id string, input LifecycleInput, userID string) (*Lifecycle, error)
+
+	// ListRevisions returns the asset's recorded changes, newest first.
+	ListRevisions(ctx context.Context, assetID string, limit, offset int) ([]*Revision, int, error)
+	GetRevision(ctx context.Context, assetID string, id int64) (*Revision, error)
+	// DiffRevisions returns the net change between two revisions.
+	DiffRevisions(ctx context.Context, assetID string, from, to int64) (*RevisionDiff, error)
+	// RevertToRevision restores user-editable fields to their values as of a revision.
+	RevertToRevision(ctx context.Context, assetID string, id int64, fields []string) (*Asset, error)
+
+	// Browse returns a node of the provider, database and schema tree.
+	Browse(ctx context.Context, path GroupPath, limit, offset int) (*GroupResult, error)
+
+	// InvalidateCaches drops cached summaries and metadata field suggestions
+	// so the next request reads fresh data.
+	InvalidateCaches()
+
 	// SetMembershipObserver registers an observer for asset create/delete events.
 	SetMembershipObserver(observer MembershipObserver)
 	// AddMembershipObserver registers an additional observer for asset create/delete events.
 	AddMembershipObserver(observer MembershipObserver)
 	// SetNotificationObserver registers an observer for asset update notifications.
 	SetNotificationObserver(observer NotificationObserver)
+	// AddChangeObserver registers an observer for every asset update and
+	// deletion, including updates made without notifications.
+	AddChangeObserver(observer NotificationObserver)
+	// SetMetadataValidator checks metadata on create and update against the
+	// schema registered for the asset's type.
+	SetMetadataValidator(validator MetadataValidator)
+	// SetTagValidator checks tags added on create and update against the
+	// tag taxonomy.
+	SetTagValidator(validator TagValidator)
 }
 
 // MembershipObserver is notified when assets are created or deleted.
@@ -228,6 +276,17 @@ type NotificationObserver interface {
 	OnAssetDeleted(ctx context.Context, asset *Asset)
 }
 
+// MetadataValidator checks an asset's metadata against what its type
+// expects.
+type MetadataValidator interface {
+	ValidateMetadata(ctx context.Context, assetType string, metadata map[string]interface{}) error
+}
+
+// TagValidator checks that tags are allowed by the tag taxonomy.
+type TagValidator interface {
+	ValidateTags(ctx context.Context, tags []string) error
+}
+
 // summaryCache holds cached summary data with TTL
 type summaryCache struct {
 	sync.RWMutex
@@ -252,6 +311,9 @@ type service struct {
 	membershipObserver   MembershipObserver
 	membershipObservers  []MembershipObserver
 	notificationObserver NotificationObserver
+	changeObservers      []NotificationObserver
+	metadataValidator    MetadataValidator
+	tagValidator         TagValidator
 	summaryCache         summaryCache
 	metadataFieldsCache  metadataFieldsCache
 }
@@ -287,6 +349,16 @@ func WithMetrics(metrics MetricsClient) ServiceOption {
 	}
 }
 
+func (s *service) InvalidateCaches() {
+	s.summaryCache.Lock()
+	s.summaryCache.data = nil
+	s.summaryCache.Unlock()
+
+	s.metadataFieldsCache.Lock()
+	s.metadataFieldsCache.data = nil
+	s.metadataFieldsCache.Unlock()
+}
+
 func (s *service) SetMembershipObserver(observer MembershipObserver) {
 	s.membershipObserver = observer
 }
@@ -299,6 +371,53 @@ func (s *service) SetNotificationObserver(observer NotificationObserver) {
 	s.notificationObserver = observer
 }
 
+func (s *service) AddChangeObserver(observer NotificationObserver) {
+	s.changeObservers = append(s.changeObservers, observer)
+}
+
+func (s *service) SetMetadataValidator(validator MetadataValidator) {
+	s.metadataValidator = validator
+}
+
+// validateMetadata rejects metadata that doesn't match the asset type's
+// registered schema.
+func (s *service) validateMetadata(ctx context.Context, assetType string, metadata map[string]interface{}) error {
+	if s.metadataValidator == nil {
+		return nil
+	}
+	if err := s.metadataValidator.ValidateMetadata(ctx, assetType, metadata); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+func (s *service) SetTagValidator(validator TagValidator) {
+	s.tagValidator = validator
+}
+
+// validateTags rejects tags the taxonomy doesn't allow. Only tags the
+// asset doesn't already have are checked, so assets tagged before a tag
+// was restricted can still be edited.
+func (s *service) validateTags(ctx context.Context, existing, tags []string) error {
+	if s.tagValidator == nil {
+		return nil
+	}
+	current := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		current[tag] = true
+	}
+	var added []string
+	for _, tag := range tags {
+		if !current[tag] {
+			added = append(added, tag)
+		}
+	}
+	if err := s.tagValidator.ValidateTags(ctx, added); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
 func (s *service) GetRunHistoryHistogram(ctx context.Context, assetID string, days int) ([]HistogramBucket, error) {
 	if days <= 0 || days > 365 {
 		return nil, fmt.Errorf("invalid days parameter: must be between 1 and 365")
@@ -320,6 +439,10 @@ func (s *service) GetRunHistory(ctx context.Context, assetID string, limit, offs
 	return s.repo.GetRunHistory(ctx, assetID, limit, offset)
 }
 
+func (s *service) HasRunHistory(ctx context.Context, assetID string) (bool, error) {
+	return s.repo.HasRunHistory(ctx, assetID)
+}
+
 func (s *service) GetMetadataFields(ctx context.Context, queryContext *MetadataContext) ([]MetadataFieldSuggestion, error) {
 	if queryContext != nil && queryContext.Query != "" {
 		fields, err := s.repo.GetMetadataFieldsWithContext(ctx, queryContext)
@@ -363,6 +486,26 @@ func (s *service) GetMetadataValues(ctx context.Context, field string, prefix st
 	return values, nil
 }
 
+func (s *service) GetMetadataProfile(ctx context.Context, field string, limit int) (*MetadataProfile, error) {
+	field = strings.TrimPrefix(strings.TrimSpace(field), "metadata.")
+	if field == "" || strings.HasPrefix(field, ".") || strings.HasSuffix(field, ".") || strings.Contains(field, "..") {
+		return nil, ErrInvalidMetadataField
+	}
+
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	profile, err := s.repo.GetMetadataProfile(ctx, field, limit)
+	if err != nil {
+		return nil, fmt.Errorf("getting metadata profile: %w", err)
+	}
+
+	return profile, nil
+}
+
 func (s *service) GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]string, error) {
 	if limit <= 0 {
 		limit = 10
@@ -409,8 +552,8 @@ func (s *service) ListByPattern(ctx context.Context, pattern string, assetType s
 }
 
 func (s *service) Create(ctx context.Context, input CreateInput) (*Asset, error) {
-	if err := s.validator.Struct(input); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	if err := s.validateCreate(ctx, input); err != nil {
+		return nil, err
 	}
 
 	existing, err := s.repo.GetByMRN(ctx, *input.MRN)
@@ -422,11 +565,96 @@ func (s *service) Create(ctx context.Context, input CreateInput) (*Asset, error)
 		return nil, ErrAlreadyExists
 	}
 
+	asset := newAsset(input, time.Now())
+	if err := s.repo.Create(ctx, asset); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	s.notifyCreated(ctx, asset)
+	return asset, nil
+}
+
+func (s *service) CreateMany(ctx context.Context, inputs []CreateInput) ([]*Asset, []error) {
+	created := make([]*Asset, len(inputs))
+	errs := make([]error, len(inputs))
+	failAll := func(err error) ([]*Asset, []error) {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = err
+			}
+		}
+		return created, errs
+	}
+
+	mrns := make([]string, 0, len(inputs))
+	for i, input := range inputs {
+		if errs[i] = s.validateCreate(ctx, input); errs[i] == nil {
+			mrns = append(mrns, *input.MRN)
+		}
+	}
+	if len(mrns) == 0 {
+		return created, errs
+	}
+
+	existing, err := s.GetByMRNs(ctx, mrns)
+	if err != nil {
+		return failAll(fmt.Errorf("checking existing assets: %w", err))
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(mrns))
+	toInsert := make([]*Asset, 0, len(mrns))
+	for i, input := range inputs {
+		if errs[i] != nil {
+			continue
+		}
+		if _, ok := existing[*input.MRN]; ok || seen[*input.MRN] {
+			errs[i] = ErrAlreadyExists
+			continue
+		}
+		seen[*input.MRN] = true
+		created[i] = newAsset(input, now)
+		toInsert = append(toInsert, created[i])
+	}
+
+	inserted, err := s.repo.CreateMany(ctx, toInsert)
+	if err != nil {
+		clear(created)
+		return failAll(fmt.Errorf("failed to create assets: %w", err))
+	}
+
+	for i, asset := range created {
+		if asset == nil {
+			continue
+		}
+		// Another writer took the MRN between the lookup and the insert.
+		if !inserted[asset.ID] {
+			created[i], errs[i] = nil, ErrAlreadyExists
+			continue
+		}
+		s.notifyCreated(ctx, asset)
+	}
+	return created, errs
+}
+
+func (s *service) validateCreate(ctx context.Context, input CreateInput) error {
+	if err := s.validator.Struct(input); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := s.validateMetadata(ctx, input.Type, input.Metadata); err != nil {
+		return err
+	}
+	return s.validateTags(ctx, nil, input.Tags)
+}
+
+func newAsset(input CreateInput, now time.Time) *Asset {
 	if input.Schema == nil {
 		input.Schema = make(map[string]string)
 	}
 
-	now := time.Now()
 	asset := &Asset{
 		ID:            uuid.New().String(),
 		Name:          input.Name,
@@ -447,18 +675,15 @@ func (s *service) Create(ctx context.Context, input CreateInput) (*Asset, error)
 		Query:         input.Query,
 		QueryLanguage: input.QueryLanguage,
 		IsStub:        input.IsStub,
+		Status:        LifecycleActive,
 	}
 	if asset.Tags == nil {
 		asset.Tags = []string{}
 	}
+	return asset
+}
 
-	if err := s.repo.Create(ctx, asset); err != nil {
-		if errors.Is(err, ErrConflict) {
-			return nil, ErrAlreadyExists
-		}
-		return nil, fmt.Errorf("failed to create asset: %w", err)
-	}
-
+func (s *service) notifyCreated(ctx context.Context, asset *Asset) {
 	// Notify membership observers asynchronously
 	if s.membershipObserver != nil {
 		s.membershipObserver.OnAssetCreated(ctx, asset)
@@ -466,8 +691,6 @@ func (s *service) Create(ctx context.Context, input CreateInput) (*Asset, error)
 	for _, observer := range s.membershipObservers {
 		observer.OnAssetCreated(ctx, asset)
 	}
-
-	return asset, nil
 }
 
 func (s *service) GetByTypeAndName(ctx context.Context, assetType, name string) (*Asset, error) {
@@ -572,6 +795,9 @@ func (s *service) Update(ctx context.Context, id string, input UpdateInput) (*As
 		updated = true
 	}
 	if input.Metadata != nil {
+		if err := s.validateMetadata(ctx, asset.Type, input.Metadata); err != nil {
+			return nil, err
+		}
 		asset.Metadata = input.Metadata
 		updated = true
 	}
@@ -581,6 +807,9 @@ func (s *service) Update(ctx context.Context, id string, input UpdateInput) (*As
 		schemaUpdated = true
 	}
 	if input.Tags != nil {
+		if err := s.validateTags(ctx, asset.Tags, input.Tags); err != nil {
+			return nil, err
+		}
 		asset.Tags = input.Tags
 		updated = true
 	}
@@ -620,12 +849,19 @@ func (s *service) Update(ctx context.Context, id string, input UpdateInput) (*As
 		return nil, fmt.Errorf("failed to update asset: %w", err)
 	}
 
-	if s.notificationObserver != nil && !input.SkipNotification && len(changedFields) > 0 {
+	s.recordRevision(ctx, &oldAsset, asset)
+
+	if len(changedFields) > 0 {
 		changeType := "asset_change"
 		if schemaUpdated {
 			changeType = "schema_change"
 		}
-		s.notificationObserver.OnAssetUpdated(ctx, asset, changeType, changedFields)
+		if s.notificationObserver != nil && !input.SkipNotification {
+			s.notificationObserver.OnAssetUpdated(ctx, asset, changeType, changedFields)
+		}
+		for _, observer := range s.changeObservers {
+			observer.OnAssetUpdated(ctx, asset, changeType, changedFields)
+		}
 	}
 
 	return asset, nil
@@ -755,6 +991,10 @@ func (s *service) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to delete asset: %w", err)
 	}
 
+	for _, observer := range s.changeObservers {
+		observer.OnAssetDeleted(ctx, asset)
+	}
+
 	log.Info().
 		Str("asset_id", id).
 		Msg("Asset deleted")
@@ -797,6 +1037,10 @@ func (s *service) DeleteByMRN(ctx context.Context, mrn string) error {
 		return fmt.Errorf("failed to delete asset by MRN: %w", err)
 	}
 
+	for _, observer := range s.changeObservers {
+		observer.OnAssetDeleted(ctx, asset)
+	}
+
 	log.Info().
 		Str("asset_mrn", mrn).
 		Msg("Asset deleted by MRN")
@@ -823,6 +1067,11 @@ func (s *service) AddTag(ctx context.Context, id string, tag string) (*Asset, er
 		}
 	}
 
+	if err := s.validateTags(ctx, asset.Tags, []string{tag}); err != nil {
+		return nil, err
+	}
+
+	oldAsset := *asset
 	asset.Tags = append(asset.Tags, tag)
 	asset.UpdatedAt = time.Now()
 
@@ -830,6 +1079,8 @@ func (s *service) AddTag(ctx context.Context, id string, tag string) (*Asset, er
 		return nil, fmt.Errorf("failed to add tag to asset: %w", err)
 	}
 
+	s.recordRevision(ctx, &oldAsset, asset)
+
 	log.Debug().
 		Str("asset_id", id).
 		Str("tag", tag).
@@ -865,6 +1116,7 @@ func (s *service) RemoveTag(ctx context.Context, assetId string, tag string) (*A
 		return asset, nil
 	}
 
+	oldAsset := *asset
 	asset.Tags = newTags
 	asset.UpdatedAt = time.Now()
 
@@ -872,6 +1124,8 @@ func (s *service) RemoveTag(ctx context.Context, assetId string, tag string) (*A
 		return nil, fmt.Errorf("failed to remove tag from asset: %w", err)
 	}
 
+	s.recordRevision(ctx, &oldAsset, asset)
+
 	log.Debug().
 		Str("asset_id", assetId).
 		Str("tag", tag).
@@ -884,6 +1138,73 @@ func (s *service) RemoveTag(ctx context.Context, assetId string, tag string) (*A
 	return asset, nil
 }
 
+// IsLocked reports whether field is protected from ingestion overwrites.
+func (a *Asset) IsLocked(field string) bool {
+	for _, f := range a.LockedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) LockFields(ctx context.Context, id string, fields []string) (*Asset, error) {
+	return s.setLocks(ctx, id, fields, true)
+}
+
+func (s *service) UnlockFields(ctx context.Context, id string, fields []string) (*Asset, error) {
+	return s.setLocks(ctx, id, fields, false)
+}
+
+func (s *service) setLocks(ctx context.Context, id string, fields []string, locked bool) (*Asset, error) {
+	for _, f := range fields {
+		if !isLockable(f) {
+			return nil, fmt.Errorf("%w: field %q cannot be locked", ErrInvalidInput, f)
+		}
+	}
+
+	asset, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("getting asset: %w", err)
+	}
+
+	change := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		change[f] = true
+	}
+	next := make([]string, 0, len(LockableFields))
+	for _, f := range LockableFields {
+		if (locked && change[f]) || (asset.IsLocked(f) && !change[f]) {
+			next = append(next, f)
+		}
+	}
+	if slices.Equal(next, asset.LockedFields) {
+		return asset, nil
+	}
+
+	if err := s.repo.SetLockedFields(ctx, id, next); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("setting locked fields: %w", err)
+	}
+	asset.LockedFields = next
+
+	return asset, nil
+}
+
+func isLockable(field string) bool {
+	for _, f := range LockableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *service) AddTerms(ctx context.Context, assetID string, termIDs []string, source string, createdBy string) error {
 	_, err := s.repo.Get(ctx, assetID)
 	if err != nil {
@@ -0,0 +1,182 @@
+package asset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockRepo stores a single asset's locked fields in memory.
+type lockRepo struct {
+	Repository
+	asset  *Asset
+	writes int
+}
+
+func (r *lockRepo) Get(_ context.Context, id string) (*Asset, error) {
+	if r.asset.ID != id {
+		return nil, ErrNotFound
+	}
+	a := *r.asset
+	return &a, nil
+}
+
+func (r *lockRepo) SetLockedFields(_ context.Context, _ string, fields []string) error {
+	r.writes++
+	r.asset.LockedFields = fields
+	return nil
+}
+
+func TestSetLocks(t *testing.T) {
+	tests := []struct {
+		name       string
+		locked     []string
+		lock       []string
+		unlock     []string
+		want       []string
+		wantWrites int
+	}{
+		{name: "lock one field", lock: []string{FieldTags}, want: []string{FieldTags}, wantWrites: 1},
+		{
+			name:       "locks keep a stable order",
+			locked:     []string{FieldTags},
+			lock:       []string{FieldDescription},
+			want:       []string{FieldDescription, FieldTags},
+			wantWrites: 1,
+		},
+		{name: "locking a locked field is a no-op", locked: []string{FieldTags}, lock: []string{FieldTags}, want: []string{FieldTags}},
+		{
+			name:       "unlock keeps other locks",
+			locked:     []string{FieldDescription, FieldTags},
+			unlock:     []string{FieldDescription},
+			want:       []string{FieldTags},
+			wantWrites: 1,
+		},
+		{name: "unlocking an unlocked field is a no-op", unlock: []string{FieldTags}},
+		{
+			name:       "unlock drops fields that are no longer lockable",
+			locked:     []string{"owners", FieldTags},
+			unlock:     []string{FieldTags},
+			want:       []string{},
+			wantWrites: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lockRepo{asset: &Asset{ID: "a1", LockedFields: tt.locked}}
+			svc := NewService(repo)
+
+			var (
+				got *Asset
+				err error
+			)
+			if tt.lock != nil {
+				got, err = svc.LockFields(context.Background(), "a1", tt.lock)
+			} else {
+				got, err = svc.UnlockFields(context.Background(), "a1", tt.unlock)
+			}
+			require.NoError(t, err)
+			if len(tt.want) == 0 {
+				assert.Empty(t, got.LockedFields)
+			} else {
+				assert.Equal(t, tt.want, got.LockedFields)
+			}
+			assert.Equal(t, tt.wantWrites, repo.writes)
+		})
+	}
+}
+
+func TestSetLocksRejectsUnlockableFields(t *testing.T) {
+	repo := &lockRepo{asset: &Asset{ID: "a1"}}
+	svc := NewService(repo)
+
+	for _, field := range []string{"owners", FieldName, ""} {
+		_, err := svc.LockFields(context.Background(), "a1", []string{FieldTags, field})
+		assert.ErrorIs(t, err, ErrInvalidInput, "field %q", field)
+	}
+	assert.Zero(t, repo.writes)
+}
+
+func TestSetLocksUnknownAsset(t *testing.T) {
+	svc := NewService(&lockRepo{asset: &Asset{ID: "a1"}})
+
+	_, err := svc.LockFields(context.Background(), "missing", []string{FieldTags})
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+}
+
+func TestIsLocked(t *testing.T) {
+	a := &Asset{LockedFields: []string{FieldDescription}}
+	assert.True(t, a.IsLocked(FieldDescription))
+	assert.False(t, a.IsLocked(FieldTags))
+	assert.False(t, (&Asset{}).IsLocked(FieldDescription))
+}
+
+// createRepo holds the assets with the given MRNs and inserts new ones in
+// memory, except those whose MRN another writer takes first.
+type createRepo struct {
+	Repository
+	byMRN   map[string]*Asset
+	raced   map[string]bool
+	inserts [][]*Asset
+}
+
+func (r *createRepo) GetByMRNs(_ context.Context, mrns []string) ([]*Asset, error) {
+	var found []*Asset
+	for _, m := range mrns {
+		if a, ok := r.byMRN[m]; ok {
+			found = append(found, a)
+		}
+	}
+	return found, nil
+}
+
+func (r *createRepo) CreateMany(_ context.Context, assets []*Asset) (map[string]bool, error) {
+	r.inserts = append(r.inserts, assets)
+	inserted := map[string]bool{}
+	for _, a := range assets {
+		if !r.raced[*a.MRN] {
+			inserted[a.ID] = true
+		}
+	}
+	return inserted, nil
+}
+
+func TestCreateMany(t *testing.T) {
+	taken := "mrn://table/postgres/taken"
+	repo := &createRepo{
+		byMRN: map[string]*Asset{taken: {ID: "a1", MRN: &taken}},
+		raced: map[string]bool{"mrn://table/postgres/raced": true},
+	}
+	svc := NewService(repo)
+
+	input := func(mrn, assetType string) CreateInput {
+		name := mrn
+		return CreateInput{Name: &name, MRN: &mrn, Type: assetType, Providers: []string{"PostgreSQL"}, CreatedBy: "run"}
+	}
+	created, errs := svc.CreateMany(context.Background(), []CreateInput{
+		input("mrn://table/postgres/orders", "Table"),
+		input(taken, "Table"),
+		input("mrn://table/postgres/orders", "Table"),
+		input("mrn://table/postgres/invalid", ""),
+		input("mrn://table/postgres/raced", "Table"),
+	})
+
+	require.Len(t, created, 5)
+	require.Len(t, errs, 5)
+	require.NoError(t, errs[0])
+	assert.Equal(t, "mrn://table/postgres/orders", *created[0].MRN)
+	assert.Equal(t, []string{}, created[0].Tags)
+	assert.ErrorIs(t, errs[1], ErrAlreadyExists, "existing MRN")
+	assert.ErrorIs(t, errs[2], ErrAlreadyExists, "repeated MRN")
+	assert.ErrorIs(t, errs[3], ErrInvalidInput)
+	assert.ErrorIs(t, errs[4], ErrAlreadyExists, "MRN taken before the insert")
+	for _, i := range []int{1, 2, 3, 4} {
+		assert.Nil(t, created[i])
+	}
+
+	require.Len(t, repo.inserts, 1, "one insert for the whole batch")
+	assert.Len(t, repo.inserts[0], 2)
+}
@@ -30,14 +30,17 @@ const (
 	baseSelectAsset = `
    	SELECT
    		id, name, mrn, type, providers, environments, external_links,
-   		description, user_description, metadata, schema, sources, tags,
+   		description, user_description, metadata, resolve_schema_blobs(schema) AS schema, sources, tags,
    		created_at, created_by, updated_at, last_sync_at,
-   		query, query_language, is_stub
+   		query, query_language, is_stub, locked_fields, lifecycle_status, archived_at
    	FROM assets`
 )
 
 type Repository interface {
 	Create(ctx context.Context, asset *Asset) error
+	// CreateMany inserts assets with a multi-row insert, skipping any whose
+	// MRN is taken, and returns the IDs of those inserted.
+	CreateMany(ctx context.Context, assets []*Asset) (map[string]bool, error)
 	Get(ctx context.Context, id string) (*Asset, error)
 	GetByMRN(ctx context.Context, qualifiedName string) (*Asset, error)
 	Search(ctx context.Context, filter SearchFilter, calculateCounts bool) ([]*Asset, int, AvailableFilters, error)
@@ -53,14 +56,33 @@ type Repository interface {
 	GetMetadataValuesWithContext(ctx context.Context, field string, prefix string, limit int, queryContext *MetadataContext) ([]MetadataValueSuggestion, error)
 	GetMetadataFields(ctx context.Context) ([]MetadataFieldSuggestion, error)
 	GetMetadataValues(ctx context.Context, field string, prefix string, limit int) ([]MetadataValueSuggestion, error)
+	GetMetadataProfile(ctx context.Context, field string, limit int) (*MetadataProfile, error)
 	GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]string, error)
 	GetRunHistory(ctx context.Context, assetID string, limit, offset int) ([]*RunHistory, int, error)
+	HasRunHistory(ctx context.Context, assetID string) (bool, error)
 	GetRunHistoryHistogram(ctx context.Context, assetID string, days int) ([]HistogramBucket, error)
 
 	AddTerms(ctx context.Context, assetID string, termIDs []string, source string, createdBy string) error
 	RemoveTerm(ctx context.Context, assetID string, termID string) error
 	GetTerms(ctx context.Context, assetID string) ([]AssetTerm, error)
 	GetAssetsByTerm(ctx context.Context, termID string, limit, offset int) ([]*Asset, int, error)
+
+	SetLockedFields(ctx context.Context, id string, fields []string) error
+
+	GetLifecycle(ctx context.Context, id string) (*Lifecycle, error)
+	SetLifecycle(ctx context.Context, id string, input LifecycleInput, setBy *string) error
+
+	CreateRevision(ctx context.Context, rev *Revision) error
+	ListRevisions(ctx context.Context, assetID string, limit, offset int) ([]*Revision, int, error)
+	GetRevision(ctx context.Context, assetID string, id int64) (*Revision, error)
+	// ListRevisionsBetween returns revisions after afterID up to and including
+	// uptoID, oldest first. uptoID 0 means no upper bound.
+	ListRevisionsBetween(ctx context.Context, assetID string, afterID, uptoID int64) ([]*Revision, error)
+
+	// ListGroups returns the groups at the level below path, with asset counts.
+	ListGroups(ctx context.Context, path GroupPath, limit, offset int) ([]Group, int, error)
+	// ListGroupAssets returns the assets under a path covering every level.
+	ListGroupAssets(ctx context.Context, path GroupPath, limit, offset int) ([]*Asset, int, error)
 }
 
 type AvailableFilters struct {
@@ -157,6 +179,83 @@ func (r *PostgresRepository) Create(ctx context.Context, asset *Asset) error {
 	return nil
 }
 
+// assetInsertColumns is the number of values createChunk binds per asset.
+// Postgres allows 65535 bind parameters per statement, which caps the rows
+// per insert.
+const (
+	assetInsertColumns = 20
+	maxAssetInsertRows = 65535 / assetInsertColumns
+)
+
+func (r *PostgresRepository) CreateMany(ctx context.Context, assets []*Asset) (map[string]bool, error) {
+	start := time.Now()
+	inserted := make(map[string]bool, len(assets))
+	for i := 0; i < len(assets); i += maxAssetInsertRows {
+		end := min(i+maxAssetInsertRows, len(assets))
+		if err := r.createChunk(ctx, assets[i:end], inserted); err != nil {
+			r.recorder.RecordDBQuery(ctx, "asset_create_many", time.Since(start), false)
+			return nil, err
+		}
+	}
+	r.recorder.RecordDBQuery(ctx, "asset_create_many", time.Since(start), true)
+	return inserted, nil
+}
+
+func (r *PostgresRepository) createChunk(ctx context.Context, chunk []*Asset, inserted map[string]bool) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	valueStrings := make([]string, 0, len(chunk))
+	valueArgs := make([]interface{}, 0, len(chunk)*assetInsertColumns)
+	for i, asset := range chunk {
+		metadataJSON, sourcesJSON, environmentsJSON, externalLinksJSON, err := marshalAssetFields(asset)
+		if err != nil {
+			return err
+		}
+
+		placeholders := make([]string, assetInsertColumns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*assetInsertColumns+j+1)
+		}
+		valueStrings = append(valueStrings, "("+strings.Join(placeholders, ", ")+")")
+		valueArgs = append(valueArgs,
+			asset.ID, asset.Name, asset.MRN, asset.Type, asset.Providers,
+			environmentsJSON, asset.Description, asset.UserDescription, metadataJSON, asset.Schema,
+			sourcesJSON, asset.Tags, externalLinksJSON,
+			asset.CreatedBy, asset.CreatedAt, asset.UpdatedAt, asset.LastSyncAt,
+			asset.Query, asset.QueryLanguage, asset.IsStub)
+	}
+
+	query := fmt.Sprintf(`
+   	INSERT INTO assets (
+   		id, name, mrn, type, providers, environments, description, user_description,
+   		metadata, schema, sources, tags, external_links,
+   		created_by, created_at, updated_at, last_sync_at,
+   		query, query_language, is_stub
+   	) VALUES %s
+   	ON CONFLICT DO NOTHING
+   	RETURNING id`, strings.Join(valueStrings, ", "))
+
+	rows, err := r.db.Query(ctx, query, valueArgs...)
+	if err != nil {
+		return fmt.Errorf("inserting assets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("scanning inserted asset: %w", err)
+		}
+		inserted[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("inserting assets: %w", err)
+	}
+	return nil
+}
+
 func (r *PostgresRepository) Get(ctx context.Context, id string) (*Asset, error) {
 	return r.scanSingleAsset(ctx, baseSelectAsset+" WHERE id = $1", id)
 }
@@ -223,6 +322,19 @@ func (r *PostgresRepository) Update(ctx context.Context, asset *Asset) error {
 	return nil
 }
 
+// SetLockedFields replaces the asset's field locks. Locks are written on their
+// own so a concurrent ingestion update can't clear them.
+func (r *PostgresRepository) SetLockedFields(ctx context.Context, id string, fields []string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE assets SET locked_fields = $1 WHERE id = $2`, fields, id)
+	if err != nil {
+		return fmt.Errorf("updating locked fields: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *PostgresRepository) DeleteByMRN(ctx context.Context, mrn string) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -305,6 +417,7 @@ func (r *PostgresRepository) scanAsset(ctx context.Context, row pgx.Row) (*Asset
 		&metadataJSON, &schemaJSON, &sourcesJSON,
 		&asset.Tags, &asset.CreatedAt, &asset.CreatedBy, &asset.UpdatedAt,
 		&asset.LastSyncAt, &asset.Query, &asset.QueryLanguage, &asset.IsStub,
+		&asset.LockedFields, &asset.Status, &asset.ArchivedAt,
 	)
 
 	if err != nil {
@@ -338,6 +451,9 @@ func (r *PostgresRepository) scanAsset(ctx context.Context, row pgx.Row) (*Asset
 	if asset.Providers == nil {
 		asset.Providers = make([]string, 0)
 	}
+	if asset.LockedFields == nil {
+		asset.LockedFields = make([]string, 0)
+	}
 
 	if len(metadataJSON) > 0 {
 		if err := json.Unmarshal(metadataJSON, &asset.Metadata); err != nil {
@@ -383,7 +499,11 @@ func (r *PostgresRepository) scanSingleAsset(ctx context.Context, query string,
 }
 
 func (r *PostgresRepository) scanMultipleAssets(ctx context.Context, query string, args ...interface{}) ([]*Asset, error) {
-	rows, err := r.db.Query(ctx, query, args...)
+	return r.scanAssetsFrom(ctx, r.db, query, args...)
+}
+
+func (r *PostgresRepository) scanAssetsFrom(ctx context.Context, q query.Querier, sql string, args ...interface{}) ([]*Asset, error) {
+	rows, err := q.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying assets: %w", err)
 	}
@@ -926,6 +1046,7 @@ func (r *PostgresRepository) GetTagSuggestions(ctx context.Context, prefix strin
 }
 
 func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter, calculateCounts bool) ([]*Asset, int, AvailableFilters, error) {
+	budget := query.BudgetFromContext(ctx)
 	parser := query.NewParser()
 	builder := query.NewBuilder()
 
@@ -935,45 +1056,47 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter, ca
 	}
 
 	baseQuery := `SELECT *, ts_rank_cd(search_text, websearch_to_tsquery('english', $1), 32) as search_rank, word_similarity($1, name) as name_similarity FROM assets`
-	query, params, err := builder.BuildSQL(searchQuery, baseQuery)
+	sqlQuery, params, err := builder.BuildSQL(searchQuery, baseQuery)
 	if err != nil {
 		return nil, 0, AvailableFilters{}, fmt.Errorf("building query: %w", err)
 	}
 
-	query = strings.TrimPrefix(query, "WITH search_results AS (")
-	query = strings.TrimSuffix(query, ") SELECT * FROM search_results ORDER BY search_rank DESC")
+	sqlQuery = strings.TrimPrefix(sqlQuery, "WITH search_results AS (")
+	sqlQuery = strings.TrimSuffix(sqlQuery, ") SELECT * FROM search_results ORDER BY search_rank DESC")
+
+	if strings.Contains(sqlQuery, "WHERE") {
+		sqlQuery += " AND archived_at IS NULL"
+	} else {
+		sqlQuery += " WHERE archived_at IS NULL"
+	}
 
 	if !filter.IncludeStubs {
-		if strings.Contains(query, "WHERE") {
-			query += " AND is_stub = FALSE"
-		} else {
-			query += " WHERE is_stub = FALSE"
-		}
+		sqlQuery += " AND is_stub = FALSE"
 	}
 
 	if len(filter.Types) > 0 {
-		if strings.Contains(query, "WHERE") {
-			query += fmt.Sprintf(" AND type = ANY($%d)", len(params)+1)
+		if strings.Contains(sqlQuery, "WHERE") {
+			sqlQuery += fmt.Sprintf(" AND type = ANY($%d)", len(params)+1)
 		} else {
-			query += fmt.Sprintf(" WHERE type = ANY($%d)", len(params)+1)
+			sqlQuery += fmt.Sprintf(" WHERE type = ANY($%d)", len(params)+1)
 		}
 		params = append(params, filter.Types)
 	}
 
 	if len(filter.Providers) > 0 {
-		if strings.Contains(query, "WHERE") {
-			query += fmt.Sprintf(" AND providers && $%d", len(params)+1)
+		if strings.Contains(sqlQuery, "WHERE") {
+			sqlQuery += fmt.Sprintf(" AND providers && $%d", len(params)+1)
 		} else {
-			query += fmt.Sprintf(" WHERE providers && $%d", len(params)+1)
+			sqlQuery += fmt.Sprintf(" WHERE providers && $%d", len(params)+1)
 		}
 		params = append(params, filter.Providers)
 	}
 
 	if len(filter.Tags) > 0 {
-		if strings.Contains(query, "WHERE") {
-			query += fmt.Sprintf(" AND tags @> $%d", len(params)+1)
+		if strings.Contains(sqlQuery, "WHERE") {
+			sqlQuery += fmt.Sprintf(" AND tags @> $%d", len(params)+1)
 		} else {
-			query += fmt.Sprintf(" WHERE tags @> $%d", len(params)+1)
+			sqlQuery += fmt.Sprintf(" WHERE tags @> $%d", len(params)+1)
 		}
 		params = append(params, filter.Tags)
 	}
@@ -988,47 +1111,77 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter, ca
 		}
 
 		if ownerCondition != "" {
-			if strings.Contains(query, "WHERE") {
-				query += ownerCondition
+			if strings.Contains(sqlQuery, "WHERE") {
+				sqlQuery += ownerCondition
 			} else {
-				query += " WHERE" + strings.TrimPrefix(ownerCondition, " AND")
+				sqlQuery += " WHERE" + strings.TrimPrefix(ownerCondition, " AND")
 			}
 			params = append(params, *filter.OwnerID)
 		}
 	}
 
-	wrappedQuery := fmt.Sprintf("WITH search_results AS (%s)", query)
+	wrappedQuery := fmt.Sprintf("WITH search_results AS (%s)", sqlQuery)
 
-	var total int
-	err = r.db.QueryRow(ctx, wrappedQuery+" SELECT COUNT(*) FROM search_results", params...).Scan(&total)
-	if err != nil {
-		return nil, 0, AvailableFilters{}, fmt.Errorf("counting results: %w", err)
+	availableFilters := AvailableFilters{
+		Types:     make(map[string]int),
+		Providers: make(map[string]int),
+		Tags:      make(map[string]int),
+	}
+
+	limit, ok := budget.ClampPage(filter.Limit, filter.Offset)
+	if !ok {
+		return []*Asset{}, 0, availableFilters, nil
+	}
+
+	// An exact count over a broad match is often the most expensive part of
+	// a search, so under load, or when it runs out of time, it is replaced
+	// with a lower bound derived from the returned page.
+	total := -1
+	if !budget.UnderLoad(r.db) {
+		err = budget.Run(ctx, r.db, func(q query.Querier) error {
+			return q.QueryRow(ctx, wrappedQuery+" SELECT COUNT(*) FROM search_results", params...).Scan(&total)
+		})
+		if err != nil {
+			if budget == nil || !query.IsTimeout(err) {
+				return nil, 0, AvailableFilters{}, fmt.Errorf("counting results: %w", err)
+			}
+			total = -1
+		}
 	}
 
 	wrappedQuery += `
       SELECT
           id, name, mrn, type, providers, environments, external_links,
-          description, user_description, metadata, schema, sources, tags,
+          description, user_description, metadata, resolve_schema_blobs(schema) AS schema, sources, tags,
           created_at, created_by, updated_at, last_sync_at,
-          query, query_language, is_stub
+          query, query_language, is_stub, locked_fields, lifecycle_status, archived_at
       FROM search_results
       ORDER BY
           CASE WHEN name_similarity > 0.8 THEN name_similarity * 2
           ELSE search_rank END DESC
       LIMIT $%d OFFSET $%d
   `
-	params = append(params, filter.Limit, filter.Offset)
+	params = append(params, limit, filter.Offset)
 	wrappedQuery = fmt.Sprintf(wrappedQuery, len(params)-1, len(params))
 
-	assets, err := r.scanMultipleAssets(ctx, wrappedQuery, params...)
+	var assets []*Asset
+	err = budget.Run(ctx, r.db, func(q query.Querier) error {
+		var err error
+		assets, err = r.scanAssetsFrom(ctx, q, wrappedQuery, params...)
+		return err
+	})
 	if err != nil {
 		return nil, 0, AvailableFilters{}, fmt.Errorf("executing search: %w", err)
 	}
 
-	availableFilters := AvailableFilters{
-		Types:     make(map[string]int),
-		Providers: make(map[string]int),
-		Tags:      make(map[string]int),
+	if total < 0 {
+		budget.Truncate(query.TruncatedCount)
+		total = filter.Offset + len(assets)
+	}
+
+	if calculateCounts && budget.UnderLoad(r.db) {
+		budget.Truncate(query.TruncatedFacets)
+		calculateCounts = false
 	}
 
 	if calculateCounts {
@@ -1113,8 +1266,14 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter, ca
        `
 
 		var types, providers, tags pgtype.JSONB
-		err = r.db.QueryRow(ctx, countQuery, countParams...).Scan(&types, &providers, &tags)
+		err = budget.Run(ctx, r.db, func(q query.Querier) error {
+			return q.QueryRow(ctx, countQuery, countParams...).Scan(&types, &providers, &tags)
+		})
 		if err != nil {
+			if budget != nil && query.IsTimeout(err) {
+				budget.Truncate(query.TruncatedFacets)
+				return assets, total, availableFilters, nil
+			}
 			return nil, 0, AvailableFilters{}, fmt.Errorf("getting counts: %w", err)
 		}
 
@@ -1132,49 +1291,72 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter, ca
 	return assets, total, availableFilters, nil
 }
 
+// assetRunsCTE selects the run history of asset $1: events recorded against
+// the asset itself, plus events of the job its job_namespace and job_name
+// metadata link it to.
+const assetRunsCTE = `asset_runs AS (
+		SELECT rh.* FROM run_history rh WHERE rh.asset_id = $1
+		UNION
+		SELECT rh.* FROM run_history rh
+		JOIN assets a ON a.id = $1
+		WHERE rh.job_namespace = a.metadata->>'job_namespace'
+		AND rh.job_name = a.metadata->>'job_name'
+	)`
+
+// HasRunHistory reports whether any runs are recorded for, or linked to, an
+// asset.
+func (r *PostgresRepository) HasRunHistory(ctx context.Context, assetID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `WITH `+assetRunsCTE+` SELECT EXISTS (SELECT 1 FROM asset_runs)`, assetID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking run history: %w", err)
+	}
+	return exists, nil
+}
+
 func (r *PostgresRepository) GetRunHistory(ctx context.Context, assetID string, limit, offset int) ([]*RunHistory, int, error) {
 	var total int
-	err := r.db.QueryRow(ctx, `SELECT COUNT(DISTINCT run_id) FROM run_history WHERE asset_id = $1`, assetID).Scan(&total)
+	err := r.db.QueryRow(ctx, `WITH `+assetRunsCTE+` SELECT COUNT(DISTINCT run_id) FROM asset_runs`, assetID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("counting runs: %w", err)
 	}
 
 	query := `
-   	WITH run_status AS (
+   	WITH ` + assetRunsCTE + `,
+   	run_status AS (
    		SELECT 
    			run_id,
    			job_namespace,
    			job_name,
    			CASE 
    				WHEN bool_or(event_type IN ('COMPLETE', 'FAIL', 'ABORT')) THEN
-   					(SELECT event_type FROM run_history rh2 
-   					 WHERE rh2.asset_id = $1 AND rh2.run_id = rh.run_id 
+   					(SELECT event_type FROM asset_runs rh2 
+   					 WHERE rh2.run_id = rh.run_id 
    					 AND rh2.event_type IN ('COMPLETE', 'FAIL', 'ABORT')
    					 ORDER BY event_time DESC LIMIT 1)
    				ELSE 'RUNNING'
    			END as status,
    			MAX(event_time) as latest_event_time,
-   			(SELECT run_facets FROM run_history rh3 
-   			 WHERE rh3.asset_id = $1 AND rh3.run_id = rh.run_id 
+   			(SELECT run_facets FROM asset_runs rh3 
+   			 WHERE rh3.run_id = rh.run_id 
    			 ORDER BY event_time DESC LIMIT 1) as run_facets,
-   			(SELECT job_facets FROM run_history rh4 
-   			 WHERE rh4.asset_id = $1 AND rh4.run_id = rh.run_id 
+   			(SELECT job_facets FROM asset_runs rh4 
+   			 WHERE rh4.run_id = rh.run_id 
    			 ORDER BY event_time DESC LIMIT 1) as job_facets,
    			MAX(created_at) as created_at
-   		FROM run_history rh
-   		WHERE asset_id = $1 
+   		FROM asset_runs rh
    		GROUP BY run_id, job_namespace, job_name
    	),
    	start_events AS (
    		SELECT run_id, MIN(event_time) as start_time
-   		FROM run_history 
-   		WHERE asset_id = $1 AND event_type = 'START'
+   		FROM asset_runs 
+   		WHERE event_type = 'START'
    		GROUP BY run_id
    	),
    	end_events AS (
    		SELECT run_id, MAX(event_time) as end_time
-   		FROM run_history 
-   		WHERE asset_id = $1 AND event_type IN ('COMPLETE', 'FAIL', 'ABORT')
+   		FROM asset_runs 
+   		WHERE event_type IN ('COMPLETE', 'FAIL', 'ABORT')
    		GROUP BY run_id
    	)
    	SELECT 
@@ -1248,7 +1430,8 @@ func (r *PostgresRepository) GetRunHistory(ctx context.Context, assetID string,
 
 func (r *PostgresRepository) GetRunHistoryHistogram(ctx context.Context, assetID string, days int) ([]HistogramBucket, error) {
 	query := `
-	WITH date_series AS (
+	WITH ` + assetRunsCTE + `,
+	date_series AS (
 		SELECT generate_series(
 			CURRENT_DATE - INTERVAL '%d days' + INTERVAL '1 day',
 			CURRENT_DATE,
@@ -1261,15 +1444,14 @@ func (r *PostgresRepository) GetRunHistoryHistogram(ctx context.Context, assetID
 			run_id,
 			CASE 
 				WHEN bool_or(event_type IN ('COMPLETE', 'FAIL', 'ABORT')) THEN
-					(SELECT event_type FROM run_history rh2 
-					 WHERE rh2.asset_id = $1 AND rh2.run_id = rh.run_id 
+					(SELECT event_type FROM asset_runs rh2 
+					 WHERE rh2.run_id = rh.run_id 
 					 AND rh2.event_type IN ('COMPLETE', 'FAIL', 'ABORT')
 					 ORDER BY event_time DESC LIMIT 1)
 				ELSE 'RUNNING'
 			END as final_status
-		FROM run_history rh
-		WHERE asset_id = $1 
-		AND event_time >= CURRENT_DATE - INTERVAL '%d days'
+		FROM asset_runs rh
+		WHERE event_time >= CURRENT_DATE - INTERVAL '%d days'
 		GROUP BY DATE(event_time), run_id
 	),
 	daily_counts AS (
@@ -1432,7 +1614,7 @@ func (r *PostgresRepository) GetAssetsByTerm(ctx context.Context, termID string,
 		SELECT COUNT(DISTINCT a.id)
 		FROM assets a
 		JOIN asset_terms at ON a.id = at.asset_id
-		WHERE at.glossary_term_id = $1`
+		WHERE at.glossary_term_id = $1 AND a.archived_at IS NULL`
 
 	var total int
 	err := r.db.QueryRow(ctx, countQuery, termID).Scan(&total)
@@ -1442,7 +1624,7 @@ func (r *PostgresRepository) GetAssetsByTerm(ctx context.Context, termID string,
 
 	query := baseSelectAsset + `
 		JOIN asset_terms at ON assets.id = at.asset_id
-		WHERE at.glossary_term_id = $1
+		WHERE at.glossary_term_id = $1 AND assets.archived_at IS NULL
 		ORDER BY assets.name ASC
 		LIMIT $2 OFFSET $3`
 
@@ -1478,7 +1660,7 @@ func (r *PostgresRepository) GetMyAssets(ctx context.Context, userID string, tea
 		FROM assets a
 		JOIN asset_owners ao ON a.id = ao.asset_id
 		WHERE (ao.user_id = $1 OR ao.team_id = ANY($2))
-		AND a.is_stub = FALSE`
+		AND a.is_stub = FALSE AND a.archived_at IS NULL`
 
 	var total int
 	err := r.db.QueryRow(ctx, countQuery, userID, teamIDs).Scan(&total)
@@ -1491,13 +1673,13 @@ func (r *PostgresRepository) GetMyAssets(ctx context.Context, userID string, tea
 	query := `
 		SELECT DISTINCT
 			a.id, a.name, a.mrn, a.type, a.providers, a.environments, a.external_links,
-			a.description, a.user_description, a.metadata, a.schema, a.sources, a.tags,
+			a.description, a.user_description, a.metadata, resolve_schema_blobs(a.schema) AS schema, a.sources, a.tags,
 			a.created_at, a.created_by, a.updated_at, a.last_sync_at,
-			a.query, a.query_language, a.is_stub
+			a.query, a.query_language, a.is_stub, a.locked_fields, a.lifecycle_status, a.archived_at
 		FROM assets a
 		JOIN asset_owners ao ON a.id = ao.asset_id
 		WHERE (ao.user_id = $1 OR ao.team_id = ANY($2))
-		AND a.is_stub = FALSE
+		AND a.is_stub = FALSE AND a.archived_at IS NULL
 		ORDER BY a.updated_at DESC, a.name ASC
 		LIMIT $3 OFFSET $4`
 
@@ -0,0 +1,340 @@
+package assethealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MetadataFreshnessKey is the metadata key holding how many hours may pass
+// between successful updates of an asset. Assets without it inherit the
+// strictest freshness_hours SLA of the data products they belong to.
+const MetadataFreshnessKey = "freshness_hours"
+
+const (
+	// RecentRuns is the number of finished runs the run factor looks at.
+	RecentRuns = 5
+	// MaxQualityResults bounds the results accepted in one report.
+	MaxQualityResults = 500
+
+	// staleFactor is how far past its freshness expectation an asset may
+	// fall before it is red rather than yellow.
+	staleFactor = 2
+)
+
+var ErrNotFound = errors.New("asset not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+type Status string // @name AssetHealthStatus
+
+const (
+	StatusGreen  Status = "green"
+	StatusYellow Status = "yellow"
+	StatusRed    Status = "red"
+	// StatusUnknown assets have no freshness expectation, quality results
+	// or runs to judge them by.
+	StatusUnknown Status = "unknown"
+)
+
+// severity orders statuses from best to worst.
+var severity = map[Status]int{
+	StatusUnknown: 0,
+	StatusGreen:   1,
+	StatusYellow:  2,
+	StatusRed:     3,
+}
+
+type FactorKind string // @name AssetHealthFactorKind
+
+const (
+	FactorFreshness FactorKind = "freshness"
+	FactorQuality   FactorKind = "quality"
+	FactorRuns      FactorKind = "runs"
+)
+
+// Factor is one signal contributing to an asset's health.
+type Factor struct {
+	Kind    FactorKind `json:"kind"`
+	Status  Status     `json:"status"`
+	Message string     `json:"message"`
+} // @name AssetHealthFactor
+
+// Health is the computed health of an asset: the worst status of its
+// contributing factors.
+type Health struct {
+	Status  Status   `json:"status"`
+	Factors []Factor `json:"factors"`
+} // @name AssetHealth
+
+type QualityStatus string // @name QualityCheckStatus
+
+const (
+	QualityPassed  QualityStatus = "passed"
+	QualityWarning QualityStatus = "warning"
+	QualityFailed  QualityStatus = "failed"
+)
+
+var validQualityStatuses = map[QualityStatus]bool{
+	QualityPassed:  true,
+	QualityWarning: true,
+	QualityFailed:  true,
+}
+
+// QualityResult is the outcome of one data quality check run against an
+// asset.
+type QualityResult struct {
+	ID         string        `json:"id"`
+	AssetID    string        `json:"asset_id"`
+	CheckName  string        `json:"check_name"`
+	Status     QualityStatus `json:"status"`
+	Message    string        `json:"message,omitempty"`
+	CheckedAt  time.Time     `json:"checked_at"`
+	ReportedBy *string       `json:"reported_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+} // @name QualityResult
+
+// QualityResultInput is one check result reported by a quality tool.
+type QualityResultInput struct {
+	CheckName string        `json:"check_name"`
+	Status    QualityStatus `json:"status"`
+	Message   string        `json:"message,omitempty"`
+	// CheckedAt defaults to the time the result is reported.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+} // @name QualityResultInput
+
+// Signals are the facts an asset's health is computed from.
+type Signals struct {
+	AssetID string
+	// FreshnessHours is the asset's freshness expectation, if it has one.
+	FreshnessHours *float64
+	LastSyncAt     time.Time
+	// LastSuccessAt is when the last successful run of the asset finished.
+	LastSuccessAt *time.Time
+	// RecentRuns are the outcomes of the asset's last finished runs, newest
+	// first.
+	RecentRuns []RunOutcome
+	// Quality is the latest result of each quality check.
+	Quality []QualityResult
+}
+
+// RunOutcome is a finished run of an asset.
+type RunOutcome struct {
+	RunID      string
+	EventType  string
+	FinishedAt time.Time
+}
+
+func (r RunOutcome) failed() bool {
+	return r.EventType == "FAIL" || r.EventType == "ABORT"
+}
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Get computes the health of one asset.
+func (s *Service) Get(ctx context.Context, assetID string) (*Health, error) {
+	healths, err := s.HealthFor(ctx, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	h, ok := healths[assetID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return h, nil
+}
+
+// HealthFor computes the health of a set of assets. Assets that don't exist
+// are left out of the result.
+func (s *Service) HealthFor(ctx context.Context, assetIDs []string) (map[string]*Health, error) {
+	if len(assetIDs) == 0 {
+		return map[string]*Health{}, nil
+	}
+	signals, err := s.repo.ListSignals(ctx, assetIDs, RecentRuns)
+	if err != nil {
+		return nil, fmt.Errorf("listing health signals: %w", err)
+	}
+
+	now := s.now().UTC()
+	healths := make(map[string]*Health, len(signals))
+	for _, sig := range signals {
+		healths[sig.AssetID] = Compute(now, sig)
+	}
+	return healths, nil
+}
+
+// RecordQualityResults stores the results of quality checks run against an
+// asset.
+func (s *Service) RecordQualityResults(ctx context.Context, assetID string, inputs []QualityResultInput, reportedBy *string) ([]*QualityResult, error) {
+	if len(inputs) == 0 {
+		return nil, &ValidationError{Message: "at least one result is required"}
+	}
+	if len(inputs) > MaxQualityResults {
+		return nil, &ValidationError{Message: fmt.Sprintf("at most %d results may be reported at once", MaxQualityResults)}
+	}
+
+	now := s.now().UTC()
+	results := make([]*QualityResult, len(inputs))
+	for i, in := range inputs {
+		name := strings.TrimSpace(in.CheckName)
+		if name == "" {
+			return nil, &ValidationError{Message: fmt.Sprintf("result %d: check_name is required", i)}
+		}
+		if len(name) > 255 {
+			return nil, &ValidationError{Message: fmt.Sprintf("result %d: check_name must be at most 255 characters", i)}
+		}
+		if !validQualityStatuses[in.Status] {
+			return nil, &ValidationError{Message: fmt.Sprintf("result %d: status must be one of passed, warning or failed", i)}
+		}
+		checkedAt := now
+		if in.CheckedAt != nil {
+			checkedAt = in.CheckedAt.UTC()
+		}
+		results[i] = &QualityResult{
+			AssetID:    assetID,
+			CheckName:  name,
+			Status:     in.Status,
+			Message:    in.Message,
+			CheckedAt:  checkedAt,
+			ReportedBy: reportedBy,
+		}
+	}
+
+	if err := s.repo.CreateQualityResults(ctx, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Compute derives an asset's health from its signals. Factors without
+// anything to judge by are left out; an asset with no factors is unknown.
+func Compute(now time.Time, sig Signals) *Health {
+	h := &Health{Status: StatusUnknown, Factors: []Factor{}}
+	add := func(f *Factor) {
+		if f == nil {
+			return
+		}
+		h.Factors = append(h.Factors, *f)
+		if severity[f.Status] > severity[h.Status] {
+			h.Status = f.Status
+		}
+	}
+
+	add(freshnessFactor(now, sig))
+	add(qualityFactor(sig.Quality))
+	add(runsFactor(sig.RecentRuns))
+
+	return h
+}
+
+func freshnessFactor(now time.Time, sig Signals) *Factor {
+	if sig.FreshnessHours == nil || *sig.FreshnessHours <= 0 {
+		return nil
+	}
+
+	// Runs say when the data last changed. Assets nothing runs against are
+	// only as fresh as their last sync.
+	updatedAt := sig.LastSyncAt
+	source := "synced"
+	if sig.LastSuccessAt != nil {
+		updatedAt = *sig.LastSuccessAt
+		source = "updated by a successful run"
+	}
+	if updatedAt.IsZero() {
+		return &Factor{Kind: FactorFreshness, Status: StatusRed, Message: "never updated"}
+	}
+
+	expected := time.Duration(*sig.FreshnessHours * float64(time.Hour))
+	age := now.Sub(updatedAt)
+	msg := fmt.Sprintf("last %s %s ago, expected every %s", source, formatDuration(age), formatDuration(expected))
+	switch {
+	case age <= expected:
+		return &Factor{Kind: FactorFreshness, Status: StatusGreen, Message: msg}
+	case age <= staleFactor*expected:
+		return &Factor{Kind: FactorFreshness, Status: StatusYellow, Message: msg}
+	default:
+		return &Factor{Kind: FactorFreshness, Status: StatusRed, Message: msg}
+	}
+}
+
+func qualityFactor(results []QualityResult) *Factor {
+	if len(results) == 0 {
+		return nil
+	}
+
+	var failed, warned []string
+	for _, r := range results {
+		switch r.Status {
+		case QualityFailed:
+			failed = append(failed, r.CheckName)
+		case QualityWarning:
+			warned = append(warned, r.CheckName)
+		}
+	}
+	sort.Strings(failed)
+	sort.Strings(warned)
+
+	switch {
+	case len(failed) > 0:
+		return &Factor{Kind: FactorQuality, Status: StatusRed, Message: fmt.Sprintf("%d of %d checks failed: %s", len(failed), len(results), strings.Join(failed, ", "))}
+	case len(warned) > 0:
+		return &Factor{Kind: FactorQuality, Status: StatusYellow, Message: fmt.Sprintf("%d of %d checks warned: %s", len(warned), len(results), strings.Join(warned, ", "))}
+	default:
+		return &Factor{Kind: FactorQuality, Status: StatusGreen, Message: fmt.Sprintf("all %d checks passed", len(results))}
+	}
+}
+
+func runsFactor(runs []RunOutcome) *Factor {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	failures := 0
+	for _, r := range runs {
+		if r.failed() {
+			failures++
+		}
+	}
+
+	switch {
+	case runs[0].failed():
+		return &Factor{Kind: FactorRuns, Status: StatusRed, Message: fmt.Sprintf("last run failed at %s", runs[0].FinishedAt.UTC().Format(time.RFC3339))}
+	case failures > 0:
+		return &Factor{Kind: FactorRuns, Status: StatusYellow, Message: fmt.Sprintf("%d of the last %d runs failed", failures, len(runs))}
+	default:
+		return &Factor{Kind: FactorRuns, Status: StatusGreen, Message: fmt.Sprintf("last %d runs succeeded", len(runs))}
+	}
+}
+
+// formatDuration renders d in the largest whole unit that fits, e.g. "3d".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
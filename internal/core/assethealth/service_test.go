@@ -0,0 +1,197 @@
+package assethealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	signals []Signals
+	created []*QualityResult
+}
+
+func (f *fakeRepo) ListSignals(_ context.Context, assetIDs []string, _ int) ([]Signals, error) {
+	var out []Signals
+	for _, s := range f.signals {
+		for _, id := range assetIDs {
+			if s.AssetID == id {
+				out = append(out, s)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) CreateQualityResults(_ context.Context, results []*QualityResult) error {
+	f.created = append(f.created, results...)
+	return nil
+}
+
+func hours(h float64) *float64 { return &h }
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	complete := func(d time.Duration) RunOutcome {
+		return RunOutcome{EventType: "COMPLETE", FinishedAt: now.Add(-d)}
+	}
+	failed := func(d time.Duration) RunOutcome {
+		return RunOutcome{EventType: "FAIL", FinishedAt: now.Add(-d)}
+	}
+
+	tests := []struct {
+		name    string
+		signals Signals
+		status  Status
+		factors map[FactorKind]Status
+	}{
+		{
+			name:    "no signals",
+			signals: Signals{LastSyncAt: now},
+			status:  StatusUnknown,
+			factors: map[FactorKind]Status{},
+		},
+		{
+			name: "fresh with passing checks and runs",
+			signals: Signals{
+				FreshnessHours: hours(24),
+				LastSuccessAt:  ago(2 * time.Hour),
+				RecentRuns:     []RunOutcome{complete(2 * time.Hour), complete(26 * time.Hour)},
+				Quality:        []QualityResult{{CheckName: "not_null_id", Status: QualityPassed}},
+			},
+			status: StatusGreen,
+			factors: map[FactorKind]Status{
+				FactorFreshness: StatusGreen,
+				FactorQuality:   StatusGreen,
+				FactorRuns:      StatusGreen,
+			},
+		},
+		{
+			name: "late and an earlier failure",
+			signals: Signals{
+				FreshnessHours: hours(24),
+				LastSuccessAt:  ago(30 * time.Hour),
+				RecentRuns:     []RunOutcome{complete(30 * time.Hour), failed(54 * time.Hour)},
+			},
+			status: StatusYellow,
+			factors: map[FactorKind]Status{
+				FactorFreshness: StatusYellow,
+				FactorRuns:      StatusYellow,
+			},
+		},
+		{
+			name: "last run failed",
+			signals: Signals{
+				RecentRuns: []RunOutcome{failed(time.Hour), complete(25 * time.Hour)},
+				Quality:    []QualityResult{{CheckName: "row_count", Status: QualityWarning}},
+			},
+			status: StatusRed,
+			factors: map[FactorKind]Status{
+				FactorQuality: StatusYellow,
+				FactorRuns:    StatusRed,
+			},
+		},
+		{
+			name: "stale sync and failed check",
+			signals: Signals{
+				FreshnessHours: hours(1),
+				LastSyncAt:     now.Add(-3 * time.Hour),
+				Quality: []QualityResult{
+					{CheckName: "unique_id", Status: QualityFailed},
+					{CheckName: "row_count", Status: QualityPassed},
+				},
+			},
+			status: StatusRed,
+			factors: map[FactorKind]Status{
+				FactorFreshness: StatusRed,
+				FactorQuality:   StatusRed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compute(now, tt.signals)
+			assert.Equal(t, tt.status, h.Status)
+
+			got := make(map[FactorKind]Status)
+			for _, f := range h.Factors {
+				got[f.Kind] = f.Status
+				assert.NotEmpty(t, f.Message)
+			}
+			assert.Equal(t, tt.factors, got)
+		})
+	}
+}
+
+func TestCompute_Messages(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	h := Compute(now, Signals{
+		FreshnessHours: hours(24),
+		LastSyncAt:     now.Add(-72 * time.Hour),
+		RecentRuns: []RunOutcome{
+			{EventType: "COMPLETE", FinishedAt: now},
+			{EventType: "ABORT", FinishedAt: now.Add(-time.Hour)},
+			{EventType: "FAIL", FinishedAt: now.Add(-2 * time.Hour)},
+		},
+		Quality: []QualityResult{
+			{CheckName: "b", Status: QualityFailed},
+			{CheckName: "a", Status: QualityFailed},
+			{CheckName: "c", Status: QualityPassed},
+		},
+	})
+
+	require.Len(t, h.Factors, 3)
+	assert.Equal(t, "last synced 3d ago, expected every 24h", h.Factors[0].Message)
+	assert.Equal(t, "2 of 3 checks failed: a, b", h.Factors[1].Message)
+	assert.Equal(t, "2 of the last 3 runs failed", h.Factors[2].Message)
+}
+
+func TestService_HealthFor(t *testing.T) {
+	repo := &fakeRepo{signals: []Signals{
+		{AssetID: "a", RecentRuns: []RunOutcome{{EventType: "FAIL"}}},
+		{AssetID: "b"},
+	}}
+	svc := NewService(repo)
+
+	healths, err := svc.HealthFor(context.Background(), []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	require.Len(t, healths, 2)
+	assert.Equal(t, StatusRed, healths["a"].Status)
+	assert.Equal(t, StatusUnknown, healths["b"].Status)
+
+	_, err = svc.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_RecordQualityResults(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	results, err := svc.RecordQualityResults(context.Background(), "a", []QualityResultInput{
+		{CheckName: " not_null_id ", Status: QualityPassed},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "not_null_id", results[0].CheckName)
+	assert.Equal(t, now, results[0].CheckedAt)
+	assert.Len(t, repo.created, 1)
+
+	for _, inputs := range [][]QualityResultInput{
+		nil,
+		{{CheckName: "", Status: QualityPassed}},
+		{{CheckName: "x", Status: "ok"}},
+	} {
+		_, err := svc.RecordQualityResults(context.Background(), "a", inputs, nil)
+		assert.True(t, IsValidationError(err), "inputs %v", inputs)
+	}
+}
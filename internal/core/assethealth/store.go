@@ -0,0 +1,210 @@
+package assethealth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository reads the signals asset health is computed from, and stores
+// quality check results.
+type Repository interface {
+	// ListSignals returns the signals of each existing asset in assetIDs,
+	// with up to recentRuns of its latest finished runs.
+	ListSignals(ctx context.Context, assetIDs []string, recentRuns int) ([]Signals, error)
+	CreateQualityResults(ctx context.Context, results []*QualityResult) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListSignals(ctx context.Context, assetIDs []string, recentRuns int) ([]Signals, error) {
+	// An asset's own freshness expectation wins over the SLAs of the data
+	// products it belongs to, of which the strictest applies.
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.metadata->>'`+MetadataFreshnessKey+`', a.last_sync_at,
+		       (SELECT MIN((dp.metadata->'sla'->>'freshness_hours')::float8)
+		        FROM data_product_memberships m
+		        JOIN data_products dp ON dp.id = m.data_product_id
+		        WHERE m.asset_id = a.id
+		        AND jsonb_typeof(dp.metadata->'sla'->'freshness_hours') = 'number')
+		FROM assets a
+		WHERE a.id = ANY($1)
+		ORDER BY a.id`, assetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("querying assets: %w", err)
+	}
+	defer rows.Close()
+
+	var signals []Signals
+	index := make(map[string]int)
+	for rows.Next() {
+		var s Signals
+		var own *string
+		var product *float64
+		if err := rows.Scan(&s.AssetID, &own, &s.LastSyncAt, &product); err != nil {
+			return nil, fmt.Errorf("scanning asset: %w", err)
+		}
+		s.FreshnessHours = product
+		if own != nil {
+			if hours, err := strconv.ParseFloat(*own, 64); err == nil && hours > 0 {
+				s.FreshnessHours = &hours
+			}
+		}
+		index[s.AssetID] = len(signals)
+		signals = append(signals, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating assets: %w", err)
+	}
+	if len(signals) == 0 {
+		return signals, nil
+	}
+
+	ids := make([]string, len(signals))
+	for i, s := range signals {
+		ids[i] = s.AssetID
+	}
+	if err := r.addRuns(ctx, ids, recentRuns, signals, index); err != nil {
+		return nil, err
+	}
+	if err := r.addQuality(ctx, ids, signals, index); err != nil {
+		return nil, err
+	}
+	return signals, nil
+}
+
+// addRuns loads the latest finished runs of each asset. Like an asset's run
+// history, these are runs recorded against the asset plus runs of the job
+// its job_namespace and job_name metadata link it to.
+func (r *PostgresRepository) addRuns(ctx context.Context, ids []string, recentRuns int, signals []Signals, index map[string]int) error {
+	rows, err := r.db.Query(ctx, `
+		WITH asset_runs AS (
+			SELECT rh.asset_id, rh.run_id, rh.event_type, rh.event_time
+			FROM run_history rh
+			WHERE rh.asset_id = ANY($1)
+			AND rh.event_type IN ('COMPLETE', 'FAIL', 'ABORT')
+			UNION
+			SELECT a.id, rh.run_id, rh.event_type, rh.event_time
+			FROM assets a
+			JOIN run_history rh ON rh.job_namespace = a.metadata->>'job_namespace'
+				AND rh.job_name = a.metadata->>'job_name'
+			WHERE a.id = ANY($1)
+			AND rh.event_type IN ('COMPLETE', 'FAIL', 'ABORT')
+		),
+		finished AS (
+			SELECT DISTINCT ON (asset_id, run_id) asset_id, run_id, event_type, event_time
+			FROM asset_runs
+			ORDER BY asset_id, run_id, event_time DESC
+		),
+		ranked AS (
+			SELECT asset_id, run_id, event_type, event_time,
+			       ROW_NUMBER() OVER (PARTITION BY asset_id ORDER BY event_time DESC) AS rn,
+			       MAX(event_time) FILTER (WHERE event_type = 'COMPLETE') OVER (PARTITION BY asset_id) AS last_success_at
+			FROM finished
+		)
+		SELECT asset_id, run_id, event_type, event_time, last_success_at
+		FROM ranked
+		WHERE rn <= GREATEST($2, 1)
+		ORDER BY asset_id, event_time DESC`, ids, recentRuns)
+	if err != nil {
+		return fmt.Errorf("querying runs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetID string
+		var run RunOutcome
+		var lastSuccessAt *time.Time
+		if err := rows.Scan(&assetID, &run.RunID, &run.EventType, &run.FinishedAt, &lastSuccessAt); err != nil {
+			return fmt.Errorf("scanning run: %w", err)
+		}
+		s := &signals[index[assetID]]
+		s.LastSuccessAt = lastSuccessAt
+		if len(s.RecentRuns) < recentRuns {
+			s.RecentRuns = append(s.RecentRuns, run)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating runs: %w", err)
+	}
+	return nil
+}
+
+// addQuality loads the latest result of each quality check of each asset.
+func (r *PostgresRepository) addQuality(ctx context.Context, ids []string, signals []Signals, index map[string]int) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT ON (asset_id, check_name)
+		       id, asset_id, check_name, status, message, checked_at, reported_by, created_at
+		FROM asset_quality_results
+		WHERE asset_id = ANY($1)
+		ORDER BY asset_id, check_name, checked_at DESC, created_at DESC`, ids)
+	if err != nil {
+		return fmt.Errorf("querying quality results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var q QualityResult
+		if err := rows.Scan(&q.ID, &q.AssetID, &q.CheckName, &q.Status, &q.Message, &q.CheckedAt, &q.ReportedBy, &q.CreatedAt); err != nil {
+			return fmt.Errorf("scanning quality result: %w", err)
+		}
+		s := &signals[index[q.AssetID]]
+		s.Quality = append(s.Quality, q)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating quality results: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CreateQualityResults(ctx context.Context, results []*QualityResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1)`, results[0].AssetID).Scan(&exists); err != nil {
+		return fmt.Errorf("checking asset: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	batch := &pgx.Batch{}
+	for _, q := range results {
+		batch.Queue(`
+			INSERT INTO asset_quality_results (asset_id, check_name, status, message, checked_at, reported_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at`,
+			q.AssetID, q.CheckName, q.Status, q.Message, q.CheckedAt, q.ReportedBy,
+		)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	br := tx.SendBatch(ctx, batch)
+	for _, q := range results {
+		if err := br.QueryRow().Scan(&q.ID, &q.CreatedAt); err != nil {
+			_ = br.Close()
+			return fmt.Errorf("inserting quality result: %w", err)
+		}
+	}
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("inserting quality results: %w", err)
+	}
+	return tx.Commit(ctx)
+}
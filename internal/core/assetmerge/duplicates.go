@@ -0,0 +1,199 @@
+package assetmerge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/schematree"
+)
+
+var ErrDuplicateNotFound = errors.New("duplicate not found")
+
+// DefaultSimilarity is how alike two schemas must be, from 0 to 1, for
+// assets of the same name to be flagged as possible duplicates.
+const DefaultSimilarity = 0.8
+
+// Statuses of a possible duplicate in the review queue.
+const (
+	DuplicateOpen      = "open"
+	DuplicateDismissed = "dismissed"
+)
+
+// DuplicateAsset is one side of a possible duplicate.
+type DuplicateAsset struct {
+	ID        string   `json:"id"`
+	MRN       string   `json:"mrn"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+} // @name AssetDuplicateAsset
+
+// Duplicate is a pair of assets that look like the same thing under
+// different MRNs: Asset was created by a run while DuplicateOf already
+// existed. Similarity is the share of schema columns the two have in
+// common.
+type Duplicate struct {
+	ID          string         `json:"id"`
+	Asset       DuplicateAsset `json:"asset"`
+	DuplicateOf DuplicateAsset `json:"duplicate_of"`
+	Similarity  float64        `json:"similarity"`
+	Status      string         `json:"status"`
+	DetectedAt  time.Time      `json:"detected_at"`
+	ReviewedBy  *string        `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time     `json:"reviewed_at,omitempty"`
+} // @name AssetDuplicate
+
+type DuplicateList struct {
+	Duplicates []*Duplicate `json:"duplicates"`
+	Total      int          `json:"total"`
+} // @name AssetDuplicateList
+
+// Fingerprint is what duplicate detection compares: the asset's name
+// without any dotted prefix, and the paths of its schema columns, both
+// lowercased.
+type Fingerprint struct {
+	Name    string
+	Columns []string
+}
+
+// FingerprintOf returns a's fingerprint. Columns are read from every schema
+// section, including nested fields, and sorted.
+func FingerprintOf(a *asset.Asset) Fingerprint {
+	var fp Fingerprint
+	if a.Name != nil {
+		fp.Name = nameKey(*a.Name)
+	}
+
+	seen := map[string]bool{}
+	var collect func([]*schematree.Column)
+	collect = func(columns []*schematree.Column) {
+		for _, c := range columns {
+			path := strings.ToLower(c.Path)
+			if !seen[path] {
+				seen[path] = true
+				fp.Columns = append(fp.Columns, path)
+			}
+			collect(c.Children)
+		}
+	}
+	for _, section := range schematree.Build(a.Schema) {
+		collect(section.Columns)
+	}
+	sort.Strings(fp.Columns)
+	return fp
+}
+
+// Similarity is the Jaccard index of the two fingerprints' columns, or 0
+// if their names differ or either has no columns.
+func (fp Fingerprint) Similarity(other Fingerprint) float64 {
+	if fp.Name == "" || fp.Name != other.Name || len(fp.Columns) == 0 || len(other.Columns) == 0 {
+		return 0
+	}
+	columns := make(map[string]bool, len(fp.Columns))
+	for _, c := range fp.Columns {
+		columns[c] = true
+	}
+	shared := 0
+	for _, c := range other.Columns {
+		if columns[c] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(fp.Columns)+len(other.Columns)-shared)
+}
+
+// nameKey lowercases a name and drops any database or schema prefix, so
+// that orders, public.orders and ANALYTICS.PUBLIC.ORDERS compare equal.
+func nameKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndexAny(name, "./"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "`\"[]")
+}
+
+// SetSimilarity sets how alike schemas must be for FlagDuplicates to flag
+// an asset. A threshold of 0 or less turns detection off.
+func (s *Service) SetSimilarity(threshold float64) {
+	s.similarity = threshold
+}
+
+// FlagDuplicates compares a newly created asset with existing assets of the
+// same name under other MRNs, and records those whose schemas are at least
+// as similar as the threshold as possible duplicates. It returns the MRNs
+// of the assets it was flagged against. Assets without a schema aren't
+// compared.
+func (s *Service) FlagDuplicates(ctx context.Context, a *asset.Asset) ([]string, error) {
+	if s.similarity <= 0 || a == nil || a.MRN == nil || a.IsStub {
+		return nil, nil
+	}
+	fp := FingerprintOf(a)
+	if fp.Name == "" || len(fp.Columns) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := s.repo.FindSameName(ctx, fp.Name, *a.MRN)
+	if err != nil {
+		return nil, fmt.Errorf("finding assets named %s: %w", fp.Name, err)
+	}
+
+	var flagged []string
+	for _, candidate := range candidates {
+		similarity := fp.Similarity(FingerprintOf(candidate))
+		if similarity < s.similarity {
+			continue
+		}
+		if err := s.repo.AddDuplicate(ctx, a.ID, candidate.ID, similarity); err != nil {
+			return flagged, fmt.Errorf("recording duplicate of %s: %w", *candidate.MRN, err)
+		}
+		flagged = append(flagged, *candidate.MRN)
+	}
+	return flagged, nil
+}
+
+// ListDuplicates returns the possible duplicates with the given status,
+// most recently detected first. An empty status lists open ones.
+func (s *Service) ListDuplicates(ctx context.Context, status string, limit, offset int) (*DuplicateList, error) {
+	if status == "" {
+		status = DuplicateOpen
+	}
+	if status != DuplicateOpen && status != DuplicateDismissed {
+		return nil, &ValidationError{Message: fmt.Sprintf("status must be %s or %s", DuplicateOpen, DuplicateDismissed)}
+	}
+	duplicates, total, err := s.repo.ListDuplicates(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &DuplicateList{Duplicates: duplicates, Total: total}, nil
+}
+
+// DismissDuplicate marks a pair as not duplicates, so it leaves the queue
+// and isn't flagged again.
+func (s *Service) DismissDuplicate(ctx context.Context, id, reviewedBy string) (*Duplicate, error) {
+	return s.repo.DismissDuplicate(ctx, id, reviewedBy, time.Now())
+}
+
+// MergeDuplicate merges a flagged pair. The asset that already existed
+// survives unless targetMRN names the other one. The pair leaves the queue
+// with the asset merged away.
+func (s *Service) MergeDuplicate(ctx context.Context, id, targetMRN, mergedBy string) (*Result, error) {
+	dup, err := s.repo.GetDuplicate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input := Input{SourceMRN: dup.Asset.MRN, TargetMRN: dup.DuplicateOf.MRN}
+	switch strings.TrimSpace(targetMRN) {
+	case "", dup.DuplicateOf.MRN:
+	case dup.Asset.MRN:
+		input = Input{SourceMRN: dup.DuplicateOf.MRN, TargetMRN: dup.Asset.MRN}
+	default:
+		return nil, &ValidationError{Message: "target_mrn must be one of the duplicate assets"}
+	}
+	return s.Merge(ctx, input, mergedBy)
+}
@@ -0,0 +1,90 @@
+package assetmerge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := &asset.Asset{
+		Name: strPtr("ANALYTICS.PUBLIC.Orders"),
+		Schema: map[string]string{
+			"columns": `[{"column_name": "ID", "data_type": "int"}, {"column_name": "customer", "data_type": "STRUCT<email: STRING>"}]`,
+			"dbt":     `[{"name": "id", "type": "integer"}]`,
+		},
+	}
+	fp := FingerprintOf(a)
+	assert.Equal(t, "orders", fp.Name)
+	assert.Equal(t, []string{"customer", "customer.email", "id"}, fp.Columns)
+
+	same := Fingerprint{Name: "orders", Columns: []string{"customer", "customer.email", "id"}}
+	assert.Equal(t, 1.0, fp.Similarity(same))
+	assert.Equal(t, 0.75, fp.Similarity(Fingerprint{Name: "orders", Columns: []string{"customer", "customer.email", "id", "total"}}))
+	assert.Zero(t, fp.Similarity(Fingerprint{Name: "order_lines", Columns: same.Columns}), "names must match")
+	assert.Zero(t, fp.Similarity(Fingerprint{Name: "orders"}), "assets without a schema aren't compared")
+}
+
+func TestFlagDuplicates(t *testing.T) {
+	schema := func(columns string) map[string]string {
+		return map[string]string{"columns": columns}
+	}
+	repo := &fakeRepo{sameName: []*asset.Asset{
+		{ID: "pg", MRN: strPtr("mrn://table/postgres/public.orders"), Name: strPtr("public.orders"),
+			Schema: schema(`[{"column_name": "id"}, {"column_name": "total"}, {"column_name": "placed_at"}]`)},
+		{ID: "kafka", MRN: strPtr("mrn://topic/kafka/orders"), Name: strPtr("orders"),
+			Schema: schema(`[{"column_name": "event_id"}, {"column_name": "payload"}]`)},
+	}}
+	svc := NewService(repo, &fakeAssets{})
+
+	created := &asset.Asset{
+		ID: "sf", MRN: strPtr("mrn://table/snowflake/orders"), Name: strPtr("ORDERS"),
+		Schema: schema(`[{"column_name": "ID"}, {"column_name": "TOTAL"}, {"column_name": "PLACED_AT"}]`),
+	}
+	flagged, err := svc.FlagDuplicates(context.Background(), created)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mrn://table/postgres/public.orders"}, flagged)
+	assert.Equal(t, map[string]float64{"sf->pg": 1}, repo.added)
+
+	svc.SetSimilarity(0)
+	flagged, err = svc.FlagDuplicates(context.Background(), created)
+	require.NoError(t, err)
+	assert.Empty(t, flagged, "a threshold of 0 turns detection off")
+}
+
+func TestMergeDuplicate(t *testing.T) {
+	assets := &fakeAssets{assets: map[string]*asset.Asset{
+		"mrn://new": {ID: "new", MRN: strPtr("mrn://new"), Type: "Table"},
+		"mrn://old": {ID: "old", MRN: strPtr("mrn://old"), Type: "Table"},
+	}}
+	repo := &fakeRepo{duplicates: map[string]*Duplicate{
+		"d1": {
+			ID:          "d1",
+			Asset:       DuplicateAsset{ID: "new", MRN: "mrn://new"},
+			DuplicateOf: DuplicateAsset{ID: "old", MRN: "mrn://old"},
+			Status:      DuplicateOpen,
+		},
+	}}
+	svc := NewService(repo, assets)
+	ctx := context.Background()
+
+	_, err := svc.MergeDuplicate(ctx, "d1", "mrn://elsewhere", "alice")
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.MergeDuplicate(ctx, "missing", "", "alice")
+	assert.ErrorIs(t, err, ErrDuplicateNotFound)
+
+	result, err := svc.MergeDuplicate(ctx, "d1", "", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "mrn://new", result.MergedMRN, "the existing asset survives by default")
+	assert.Equal(t, []string{"new"}, assets.deleted)
+}
+
+func TestListDuplicatesValidatesStatus(t *testing.T) {
+	svc := NewService(&fakeRepo{}, &fakeAssets{})
+	_, err := svc.ListDuplicates(context.Background(), "merged", 50, 0)
+	assert.True(t, IsValidationError(err))
+}
@@ -0,0 +1,275 @@
+// Package assetmerge merges duplicate assets into one, such as a lineage stub
+// and the asset it stands in for, or the same table reported by two plugins
+// under different MRNs. It also flags assets that look like duplicates as
+// runs create them, for an administrator to merge or dismiss.
+package assetmerge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+var ErrAssetNotFound = errors.New("asset not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Input names the asset merged away and the asset that survives.
+type Input struct {
+	SourceMRN string `json:"source_mrn"`
+	TargetMRN string `json:"target_mrn"`
+}
+
+// Result describes a completed merge. Moved counts the references
+// repointed to the surviving asset, by kind.
+type Result struct {
+	Asset     *asset.Asset     `json:"asset"`
+	MergedMRN string           `json:"merged_mrn"`
+	RunID     string           `json:"run_id"`
+	Moved     map[string]int64 `json:"moved"`
+} // @name AssetMergeResult
+
+// Record is what the repository applies in one transaction: the surviving
+// asset's fields the asset service doesn't update, the references to move
+// and the run history entry recording the merge.
+type Record struct {
+	Source    *asset.Asset
+	Target    *asset.Asset
+	Type      string
+	Providers []string
+	IsStub    bool
+	RunID     string
+	MergedBy  string
+	MergedAt  time.Time
+}
+
+type Repository interface {
+	// Merge repoints everything referencing the source asset to the
+	// target and records the merge in the target's run history. It
+	// returns the number of references moved, by kind.
+	Merge(ctx context.Context, record *Record) (map[string]int64, error)
+
+	// FindSameName returns the assets other than excludeMRN whose name,
+	// without any dotted prefix, is name, with their schemas.
+	FindSameName(ctx context.Context, name, excludeMRN string) ([]*asset.Asset, error)
+	// AddDuplicate records a possible duplicate. A pair already recorded,
+	// either way round, is left as it is.
+	AddDuplicate(ctx context.Context, assetID, duplicateOfID string, similarity float64) error
+	ListDuplicates(ctx context.Context, status string, limit, offset int) ([]*Duplicate, int, error)
+	GetDuplicate(ctx context.Context, id string) (*Duplicate, error)
+	DismissDuplicate(ctx context.Context, id, reviewedBy string, reviewedAt time.Time) (*Duplicate, error)
+}
+
+// AssetService is the part of asset.Service merging uses.
+type AssetService interface {
+	GetByMRNs(ctx context.Context, mrns []string) (map[string]*asset.Asset, error)
+	Update(ctx context.Context, id string, input asset.UpdateInput) (*asset.Asset, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type Service struct {
+	repo       Repository
+	assets     AssetService
+	similarity float64
+}
+
+func NewService(repo Repository, assets AssetService) *Service {
+	return &Service{repo: repo, assets: assets, similarity: DefaultSimilarity}
+}
+
+// Merge folds the source asset into the target and deletes the source.
+// Fields are combined, the target's values winning, unless the target is a
+// stub and the source isn't. Lineage, data product memberships, owners,
+// glossary terms, run history and users' subscriptions, watches and
+// favorites move to the target.
+//
+// Merging is safe to retry: if it fails part way, merging the same pair
+// again finishes the job.
+func (s *Service) Merge(ctx context.Context, input Input, mergedBy string) (*Result, error) {
+	sourceMRN := strings.TrimSpace(input.SourceMRN)
+	targetMRN := strings.TrimSpace(input.TargetMRN)
+	if sourceMRN == "" || targetMRN == "" {
+		return nil, &ValidationError{Message: "source_mrn and target_mrn are required"}
+	}
+	if sourceMRN == targetMRN {
+		return nil, &ValidationError{Message: "cannot merge an asset into itself"}
+	}
+
+	found, err := s.assets.GetByMRNs(ctx, []string{sourceMRN, targetMRN})
+	if err != nil {
+		return nil, fmt.Errorf("getting assets: %w", err)
+	}
+	source, target := found[sourceMRN], found[targetMRN]
+	if source == nil || target == nil {
+		return nil, ErrAssetNotFound
+	}
+
+	merged := Combine(target, source)
+
+	updateCtx := asset.WithActor(ctx, asset.Actor{Name: mergedBy, Source: asset.RevisionSourceUser})
+	if _, err := s.assets.Update(updateCtx, target.ID, asset.UpdateInput{
+		Name:             merged.Name,
+		Description:      merged.Description,
+		UserDescription:  merged.UserDescription,
+		Metadata:         merged.Metadata,
+		Schema:           merged.Schema,
+		Tags:             merged.Tags,
+		Sources:          merged.Sources,
+		Environments:     merged.Environments,
+		ExternalLinks:    merged.ExternalLinks,
+		Query:            merged.Query,
+		QueryLanguage:    merged.QueryLanguage,
+		SkipNotification: true,
+	}); err != nil {
+		if errors.Is(err, asset.ErrInvalidInput) {
+			return nil, &ValidationError{Message: err.Error()}
+		}
+		return nil, fmt.Errorf("updating surviving asset: %w", err)
+	}
+
+	record := &Record{
+		Source:    source,
+		Target:    target,
+		Type:      merged.Type,
+		Providers: merged.Providers,
+		IsStub:    merged.IsStub,
+		RunID:     uuid.New().String(),
+		MergedBy:  mergedBy,
+		MergedAt:  time.Now(),
+	}
+	moved, err := s.repo.Merge(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("moving references: %w", err)
+	}
+
+	if err := s.assets.Delete(ctx, source.ID); err != nil && !errors.Is(err, asset.ErrAssetNotFound) {
+		return nil, fmt.Errorf("deleting merged asset: %w", err)
+	}
+
+	survivor, err := s.assets.GetByMRNs(ctx, []string{targetMRN})
+	if err != nil {
+		return nil, fmt.Errorf("getting surviving asset: %w", err)
+	}
+
+	return &Result{
+		Asset:     survivor[targetMRN],
+		MergedMRN: sourceMRN,
+		RunID:     record.RunID,
+		Moved:     moved,
+	}, nil
+}
+
+// Combine returns target with source's fields folded in. Scalar fields come
+// from the primary asset, which is the target unless the target is a stub
+// and the source isn't; the other asset fills in what the primary lacks.
+// Tags, providers, sources and links are combined, and metadata and
+// environments are merged key by key.
+func Combine(target, source *asset.Asset) *asset.Asset {
+	primary, secondary := target, source
+	if target.IsStub && !source.IsStub {
+		primary, secondary = source, target
+	}
+
+	merged := *target
+	merged.Name = firstString(primary.Name, secondary.Name)
+	merged.Type = primary.Type
+	merged.Description = firstString(primary.Description, secondary.Description)
+	merged.UserDescription = firstString(primary.UserDescription, secondary.UserDescription)
+	merged.Query = firstString(primary.Query, secondary.Query)
+	merged.QueryLanguage = firstString(primary.QueryLanguage, secondary.QueryLanguage)
+	merged.IsStub = target.IsStub && source.IsStub
+
+	merged.Schema = primary.Schema
+	if len(merged.Schema) == 0 {
+		merged.Schema = secondary.Schema
+	}
+
+	merged.Metadata = make(map[string]interface{}, len(primary.Metadata)+len(secondary.Metadata))
+	for k, v := range secondary.Metadata {
+		merged.Metadata[k] = v
+	}
+	for k, v := range primary.Metadata {
+		merged.Metadata[k] = v
+	}
+
+	merged.Environments = make(map[string]asset.Environment, len(primary.Environments)+len(secondary.Environments))
+	for k, v := range secondary.Environments {
+		merged.Environments[k] = v
+	}
+	for k, v := range primary.Environments {
+		merged.Environments[k] = v
+	}
+
+	merged.Tags = union(primary.Tags, secondary.Tags)
+	merged.Providers = union(primary.Providers, secondary.Providers)
+
+	merged.ExternalLinks = append([]asset.ExternalLink{}, primary.ExternalLinks...)
+	seenLinks := make(map[string]bool, len(primary.ExternalLinks))
+	for _, link := range primary.ExternalLinks {
+		seenLinks[link.URL] = true
+	}
+	for _, link := range secondary.ExternalLinks {
+		if !seenLinks[link.URL] {
+			seenLinks[link.URL] = true
+			merged.ExternalLinks = append(merged.ExternalLinks, link)
+		}
+	}
+
+	// A source reporting both assets keeps its most recent sync.
+	merged.Sources = append([]asset.AssetSource{}, primary.Sources...)
+	sourceIndex := make(map[string]int, len(primary.Sources))
+	for i, src := range merged.Sources {
+		sourceIndex[src.Name] = i
+	}
+	for _, src := range secondary.Sources {
+		if i, ok := sourceIndex[src.Name]; ok {
+			if src.LastSyncAt.After(merged.Sources[i].LastSyncAt) {
+				merged.Sources[i] = src
+			}
+			continue
+		}
+		sourceIndex[src.Name] = len(merged.Sources)
+		merged.Sources = append(merged.Sources, src)
+	}
+
+	return &merged
+}
+
+func firstString(values ...*string) *string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return v
+		}
+	}
+	return nil
+}
+
+func union(a, b []string) []string {
+	result := make([]string, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,231 @@
+package assetmerge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestCombineTargetWins(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	target := &asset.Asset{
+		ID:          "t",
+		MRN:         strPtr("mrn://table/snowflake/orders"),
+		Name:        strPtr("orders"),
+		Type:        "Table",
+		Providers:   []string{"Snowflake"},
+		Description: strPtr("Orders from Snowflake"),
+		Metadata:    map[string]interface{}{"owner": "data", "rows": 10},
+		Tags:        []string{"pii"},
+		Sources:     []asset.AssetSource{{Name: "snowflake", LastSyncAt: older}},
+		ExternalLinks: []asset.ExternalLink{
+			{Name: "Console", URL: "https://example.com/orders"},
+		},
+	}
+	source := &asset.Asset{
+		ID:          "s",
+		MRN:         strPtr("mrn://table/dbt/orders"),
+		Name:        strPtr("orders_dbt"),
+		Type:        "Model",
+		Providers:   []string{"DBT", "Snowflake"},
+		Description: strPtr("Orders model"),
+		Query:       strPtr("select * from raw.orders"),
+		Metadata:    map[string]interface{}{"rows": 20, "materialized": "table"},
+		Schema:      map[string]string{"id": "int"},
+		Tags:        []string{"finance", "pii"},
+		Sources: []asset.AssetSource{
+			{Name: "snowflake", LastSyncAt: newer},
+			{Name: "dbt", LastSyncAt: older},
+		},
+		ExternalLinks: []asset.ExternalLink{
+			{Name: "Console", URL: "https://example.com/orders"},
+			{Name: "Docs", URL: "https://example.com/docs"},
+		},
+	}
+
+	merged := Combine(target, source)
+
+	assert.Equal(t, "t", merged.ID)
+	assert.Equal(t, "orders", *merged.Name)
+	assert.Equal(t, "Table", merged.Type)
+	assert.Equal(t, "Orders from Snowflake", *merged.Description)
+	assert.Equal(t, "select * from raw.orders", *merged.Query, "missing fields are filled in from the source")
+	assert.Equal(t, map[string]string{"id": "int"}, merged.Schema)
+	assert.Equal(t, map[string]interface{}{"owner": "data", "rows": 10, "materialized": "table"}, merged.Metadata)
+	assert.Equal(t, []string{"pii", "finance"}, merged.Tags)
+	assert.Equal(t, []string{"Snowflake", "DBT"}, merged.Providers)
+	assert.Len(t, merged.ExternalLinks, 2)
+	require.Len(t, merged.Sources, 2)
+	assert.Equal(t, newer, merged.Sources[0].LastSyncAt, "the most recent sync of a shared source is kept")
+	assert.Equal(t, "dbt", merged.Sources[1].Name)
+	assert.False(t, merged.IsStub)
+
+	assert.Equal(t, "Orders from Snowflake", *target.Description, "inputs are not modified")
+	assert.Len(t, target.Tags, 1)
+}
+
+func TestCombineStubTargetTakesSourceFields(t *testing.T) {
+	target := &asset.Asset{
+		ID:        "t",
+		MRN:       strPtr("mrn://table/postgres/orders"),
+		Name:      strPtr("orders"),
+		Type:      "Unknown",
+		IsStub:    true,
+		Providers: []string{"Postgres"},
+		Metadata:  map[string]interface{}{"stub": true},
+	}
+	source := &asset.Asset{
+		ID:          "s",
+		MRN:         strPtr("mrn://table/postgres/public.orders"),
+		Name:        strPtr("public.orders"),
+		Type:        "Table",
+		Providers:   []string{"Postgres"},
+		Description: strPtr("Orders"),
+		Metadata:    map[string]interface{}{"stub": false, "schema": "public"},
+	}
+
+	merged := Combine(target, source)
+
+	assert.Equal(t, "t", merged.ID)
+	assert.Equal(t, "public.orders", *merged.Name)
+	assert.Equal(t, "Table", merged.Type)
+	assert.False(t, merged.IsStub)
+	assert.Equal(t, false, merged.Metadata["stub"])
+	assert.Equal(t, []string{"Postgres"}, merged.Providers)
+}
+
+type fakeAssets struct {
+	assets  map[string]*asset.Asset
+	updated asset.UpdateInput
+	deleted []string
+}
+
+func (f *fakeAssets) GetByMRNs(_ context.Context, mrns []string) (map[string]*asset.Asset, error) {
+	result := map[string]*asset.Asset{}
+	for _, m := range mrns {
+		if a, ok := f.assets[m]; ok {
+			result[m] = a
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAssets) Update(_ context.Context, id string, input asset.UpdateInput) (*asset.Asset, error) {
+	f.updated = input
+	return nil, nil
+}
+
+func (f *fakeAssets) Delete(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	for m, a := range f.assets {
+		if a.ID == id {
+			delete(f.assets, m)
+		}
+	}
+	return nil
+}
+
+type fakeRepo struct {
+	record     *Record
+	sameName   []*asset.Asset
+	added      map[string]float64
+	duplicates map[string]*Duplicate
+}
+
+func (f *fakeRepo) Merge(_ context.Context, record *Record) (map[string]int64, error) {
+	f.record = record
+	return map[string]int64{"lineage_edges": 2}, nil
+}
+
+func (f *fakeRepo) FindSameName(_ context.Context, name, excludeMRN string) ([]*asset.Asset, error) {
+	var found []*asset.Asset
+	for _, a := range f.sameName {
+		if *a.MRN != excludeMRN && nameKey(*a.Name) == name {
+			found = append(found, a)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeRepo) AddDuplicate(_ context.Context, assetID, duplicateOfID string, similarity float64) error {
+	if f.added == nil {
+		f.added = map[string]float64{}
+	}
+	f.added[assetID+"->"+duplicateOfID] = similarity
+	return nil
+}
+
+func (f *fakeRepo) ListDuplicates(_ context.Context, status string, limit, offset int) ([]*Duplicate, int, error) {
+	var list []*Duplicate
+	for _, d := range f.duplicates {
+		if d.Status == status {
+			list = append(list, d)
+		}
+	}
+	return list, len(list), nil
+}
+
+func (f *fakeRepo) GetDuplicate(_ context.Context, id string) (*Duplicate, error) {
+	if d, ok := f.duplicates[id]; ok {
+		return d, nil
+	}
+	return nil, ErrDuplicateNotFound
+}
+
+func (f *fakeRepo) DismissDuplicate(_ context.Context, id, reviewedBy string, reviewedAt time.Time) (*Duplicate, error) {
+	d, ok := f.duplicates[id]
+	if !ok {
+		return nil, ErrDuplicateNotFound
+	}
+	d.Status, d.ReviewedBy, d.ReviewedAt = DuplicateDismissed, &reviewedBy, &reviewedAt
+	return d, nil
+}
+
+func TestMerge(t *testing.T) {
+	assets := &fakeAssets{assets: map[string]*asset.Asset{
+		"mrn://a": {ID: "a", MRN: strPtr("mrn://a"), Type: "Table", Tags: []string{"x"}},
+		"mrn://b": {ID: "b", MRN: strPtr("mrn://b"), Type: "Table", Tags: []string{"y"}, IsStub: true},
+	}}
+	repo := &fakeRepo{}
+	svc := NewService(repo, assets)
+
+	result, err := svc.Merge(context.Background(), Input{SourceMRN: "mrn://b", TargetMRN: "mrn://a"}, "alice")
+	require.NoError(t, err)
+
+	assert.Equal(t, "mrn://b", result.MergedMRN)
+	assert.Equal(t, "a", result.Asset.ID)
+	assert.Equal(t, int64(2), result.Moved["lineage_edges"])
+	assert.NotEmpty(t, result.RunID)
+	assert.Equal(t, []string{"x", "y"}, assets.updated.Tags)
+	assert.True(t, assets.updated.SkipNotification)
+	assert.Equal(t, []string{"b"}, assets.deleted)
+	require.NotNil(t, repo.record)
+	assert.Equal(t, "alice", repo.record.MergedBy)
+	assert.False(t, repo.record.IsStub)
+}
+
+func TestMergeValidation(t *testing.T) {
+	assets := &fakeAssets{assets: map[string]*asset.Asset{
+		"mrn://a": {ID: "a", MRN: strPtr("mrn://a")},
+	}}
+	svc := NewService(&fakeRepo{}, assets)
+	ctx := context.Background()
+
+	_, err := svc.Merge(ctx, Input{SourceMRN: "mrn://a"}, "alice")
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.Merge(ctx, Input{SourceMRN: "mrn://a", TargetMRN: "mrn://a"}, "alice")
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.Merge(ctx, Input{SourceMRN: "mrn://missing", TargetMRN: "mrn://a"}, "alice")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+	assert.Empty(t, assets.deleted)
+}
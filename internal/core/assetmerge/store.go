@@ -0,0 +1,316 @@
+package assetmerge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+// mergeJobNamespace and mergeJobName identify merges in run history.
+const (
+	mergeJobNamespace = "marmot"
+	mergeJobName      = "asset-merge"
+)
+
+// assetReference is a table pointing at assets by ID. keys are the columns
+// that, with asset_id, identify a row; rows the target already has are left
+// behind and removed with the source.
+type assetReference struct {
+	kind  string
+	table string
+	keys  []string
+}
+
+var assetReferences = []assetReference{
+	{"data_product_memberships", "data_product_memberships", []string{"data_product_id"}},
+	{"owners", "asset_owners", []string{"user_id", "team_id"}},
+	{"glossary_terms", "asset_terms", []string{"glossary_term_id"}},
+	{"subscriptions", "asset_subscriptions", []string{"user_id"}},
+	{"watches", "watches", []string{"user_id"}},
+	{"favorites", "asset_favorites", []string{"user_id"}},
+	{"run_history", "run_history", nil},
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Merge(ctx context.Context, record *Record) (map[string]int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sourceMRN, targetMRN := *record.Source.MRN, *record.Target.MRN
+	moved := make(map[string]int64, len(assetReferences)+2)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE assets SET type = $2, providers = $3, is_stub = $4, updated_at = $5
+		WHERE id = $1`,
+		record.Target.ID, record.Type, record.Providers, record.IsStub, record.MergedAt)
+	if err != nil {
+		return nil, fmt.Errorf("updating surviving asset: %w", err)
+	}
+
+	if moved["lineage_edges"], err = moveLineage(ctx, tx, "lineage_edges", nil, sourceMRN, targetMRN); err != nil {
+		return nil, err
+	}
+	if moved["column_lineage"], err = moveLineage(ctx, tx, "column_lineage", []string{"source_column", "target_column"}, sourceMRN, targetMRN); err != nil {
+		return nil, err
+	}
+
+	for _, ref := range assetReferences {
+		n, err := moveReferences(ctx, tx, ref, record.Source.ID, record.Target.ID)
+		if err != nil {
+			return nil, err
+		}
+		moved[ref.kind] = n
+	}
+
+	if err := recordRun(ctx, tx, record); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return moved, nil
+}
+
+// moveLineage repoints a lineage table's edges from sourceMRN to targetMRN.
+// Edges between the two assets would become loops and are dropped, as are
+// edges the target already has. Lineage edges match on type, column
+// lineage on the columns named in keys.
+func moveLineage(ctx context.Context, tx pgx.Tx, table string, keys []string, sourceMRN, targetMRN string) (int64, error) {
+	match := "o.type IS NOT DISTINCT FROM e.type"
+	if len(keys) > 0 {
+		conds := make([]string, len(keys))
+		for i, key := range keys {
+			conds[i] = fmt.Sprintf("o.%s = e.%s", key, key)
+		}
+		match = strings.Join(conds, " AND ")
+	}
+
+	statements := []string{
+		`DELETE FROM ` + table + `
+		WHERE (source_mrn = $1 AND target_mrn = $2) OR (source_mrn = $2 AND target_mrn = $1)`,
+		`DELETE FROM ` + table + ` e
+		WHERE e.source_mrn = $1 AND EXISTS (
+			SELECT 1 FROM ` + table + ` o
+			WHERE o.source_mrn = $2 AND o.target_mrn = e.target_mrn AND ` + match + `)`,
+		`DELETE FROM ` + table + ` e
+		WHERE e.target_mrn = $1 AND EXISTS (
+			SELECT 1 FROM ` + table + ` o
+			WHERE o.target_mrn = $2 AND o.source_mrn = e.source_mrn AND ` + match + `)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt, sourceMRN, targetMRN); err != nil {
+			return 0, fmt.Errorf("removing duplicate %s: %w", table, err)
+		}
+	}
+
+	var moved int64
+	for _, column := range []string{"source_mrn", "target_mrn"} {
+		tag, err := tx.Exec(ctx, `UPDATE `+table+` SET `+column+` = $2 WHERE `+column+` = $1`, sourceMRN, targetMRN)
+		if err != nil {
+			return 0, fmt.Errorf("moving %s: %w", table, err)
+		}
+		moved += tag.RowsAffected()
+	}
+	return moved, nil
+}
+
+func moveReferences(ctx context.Context, tx pgx.Tx, ref assetReference, sourceID, targetID string) (int64, error) {
+	query := `UPDATE ` + ref.table + ` m SET asset_id = $2 WHERE m.asset_id = $1`
+	if len(ref.keys) > 0 {
+		conds := make([]string, len(ref.keys))
+		for i, key := range ref.keys {
+			conds[i] = fmt.Sprintf("o.%s IS NOT DISTINCT FROM m.%s", key, key)
+		}
+		query += ` AND NOT EXISTS (
+			SELECT 1 FROM ` + ref.table + ` o
+			WHERE o.asset_id = $2 AND ` + strings.Join(conds, " AND ") + `)`
+	}
+
+	tag, err := tx.Exec(ctx, query, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("moving %s: %w", ref.kind, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// recordRun adds a completed run to the surviving asset's run history, so
+// the merge shows alongside the asset's other runs.
+func recordRun(ctx context.Context, tx pgx.Tx, record *Record) error {
+	runFacets, err := json.Marshal(map[string]interface{}{
+		"merge": map[string]interface{}{
+			"mergedMRN":    *record.Source.MRN,
+			"survivingMRN": *record.Target.MRN,
+			"mergedBy":     record.MergedBy,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling run facets: %w", err)
+	}
+	jobFacets, err := json.Marshal(map[string]interface{}{
+		"jobType": map[string]interface{}{
+			"processingType": "MERGE",
+			"integration":    "MARMOT",
+			"jobType":        "MERGE",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling job facets: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO run_history (
+			id, asset_id, run_id, job_namespace, job_name,
+			event_type, event_time, producer, run_facets, job_facets, created_at
+		) VALUES ($1, $2, $3, $4, $5, 'COMPLETE', $6, 'marmot', $7, $8, $6)`,
+		uuid.New().String(), record.Target.ID, record.RunID, mergeJobNamespace, mergeJobName,
+		record.MergedAt, runFacets, jobFacets)
+	if err != nil {
+		return fmt.Errorf("recording merge run: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) FindSameName(ctx context.Context, name, excludeMRN string) ([]*asset.Asset, error) {
+	pattern := likeEscaper.Replace(name)
+	rows, err := r.db.Query(ctx, `
+		SELECT id, mrn, COALESCE(name, ''), type, COALESCE(providers, '{}'), resolve_schema_blobs(schema)
+		FROM assets
+		WHERE mrn <> $3 AND is_stub = FALSE AND schema <> '{}'::jsonb
+		  AND (name ILIKE $1 OR name ILIKE $2)
+		LIMIT 100`, pattern, "%."+pattern, excludeMRN)
+	if err != nil {
+		return nil, fmt.Errorf("querying assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*asset.Asset
+	for rows.Next() {
+		var a asset.Asset
+		var mrn, assetName string
+		var schemaJSON []byte
+		if err := rows.Scan(&a.ID, &mrn, &assetName, &a.Type, &a.Providers, &schemaJSON); err != nil {
+			return nil, fmt.Errorf("scanning asset: %w", err)
+		}
+		if err := json.Unmarshal(schemaJSON, &a.Schema); err != nil {
+			return nil, fmt.Errorf("unmarshaling schema of %s: %w", mrn, err)
+		}
+		a.MRN, a.Name = &mrn, &assetName
+		// ILIKE only narrows the search; dotted prefixes and wildcards in
+		// the name are checked here.
+		if nameKey(assetName) == name {
+			assets = append(assets, &a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating assets: %w", err)
+	}
+	return assets, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *PostgresRepository) AddDuplicate(ctx context.Context, assetID, duplicateOfID string, similarity float64) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_duplicates (asset_id, duplicate_of_id, similarity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, assetID, duplicateOfID, similarity)
+	if err != nil {
+		return fmt.Errorf("inserting asset duplicate: %w", err)
+	}
+	return nil
+}
+
+const selectDuplicate = `
+	SELECT d.id, a.id, a.mrn, COALESCE(a.name, ''), a.type, COALESCE(a.providers, '{}'),
+	       o.id, o.mrn, COALESCE(o.name, ''), o.type, COALESCE(o.providers, '{}'),
+	       d.similarity, d.status, d.detected_at, d.reviewed_by, d.reviewed_at
+	FROM asset_duplicates d
+	JOIN assets a ON a.id = d.asset_id
+	JOIN assets o ON o.id = d.duplicate_of_id`
+
+func (r *PostgresRepository) ListDuplicates(ctx context.Context, status string, limit, offset int) ([]*Duplicate, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_duplicates WHERE status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting asset duplicates: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectDuplicate+`
+		WHERE d.status = $1
+		ORDER BY d.detected_at DESC, d.id
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing asset duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	duplicates := []*Duplicate{}
+	for rows.Next() {
+		d, err := scanDuplicate(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		duplicates = append(duplicates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating asset duplicates: %w", err)
+	}
+	return duplicates, total, nil
+}
+
+func (r *PostgresRepository) GetDuplicate(ctx context.Context, id string) (*Duplicate, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrDuplicateNotFound
+	}
+	d, err := scanDuplicate(r.db.QueryRow(ctx, selectDuplicate+` WHERE d.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDuplicateNotFound
+	}
+	return d, err
+}
+
+func (r *PostgresRepository) DismissDuplicate(ctx context.Context, id, reviewedBy string, reviewedAt time.Time) (*Duplicate, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrDuplicateNotFound
+	}
+	tag, err := r.db.Exec(ctx, `
+		UPDATE asset_duplicates SET status = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE id = $1`, id, DuplicateDismissed, reviewedBy, reviewedAt)
+	if err != nil {
+		return nil, fmt.Errorf("dismissing asset duplicate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrDuplicateNotFound
+	}
+	return r.GetDuplicate(ctx, id)
+}
+
+func scanDuplicate(row pgx.Row) (*Duplicate, error) {
+	var d Duplicate
+	err := row.Scan(&d.ID,
+		&d.Asset.ID, &d.Asset.MRN, &d.Asset.Name, &d.Asset.Type, &d.Asset.Providers,
+		&d.DuplicateOf.ID, &d.DuplicateOf.MRN, &d.DuplicateOf.Name, &d.DuplicateOf.Type, &d.DuplicateOf.Providers,
+		&d.Similarity, &d.Status, &d.DetectedAt, &d.ReviewedBy, &d.ReviewedAt)
+	if err != nil {
+		return nil, fmt.Errorf("scanning asset duplicate: %w", err)
+	}
+	return &d, nil
+}
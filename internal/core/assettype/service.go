@@ -0,0 +1,329 @@
+package assettype
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNotFound = errors.New("asset type not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxNameLength        = 100
+	maxIconLength        = 255
+	maxDescriptionLength = 2000
+	maxFields            = 200
+
+	// cacheTTL bounds how long other instances can validate against a
+	// stale registry after a type changes.
+	cacheTTL = 30 * time.Second
+)
+
+// Value types a metadata field can require. An empty type accepts any value.
+const (
+	FieldString  = "string"
+	FieldNumber  = "number"
+	FieldBoolean = "boolean"
+	FieldObject  = "object"
+	FieldArray   = "array"
+)
+
+var fieldTypes = map[string]bool{
+	FieldString:  true,
+	FieldNumber:  true,
+	FieldBoolean: true,
+	FieldObject:  true,
+	FieldArray:   true,
+}
+
+// AssetType is an asset type registered by an admin, with the metadata its
+// assets are expected to have.
+type AssetType struct {
+	Name           string          `json:"name"`
+	Icon           string          `json:"icon,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	MetadataSchema []MetadataField `json:"metadata_schema"`
+	UpdatedBy      *string         `json:"updated_by,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+} // @name AssetTypeDefinition
+
+// MetadataField is a metadata key assets of a type are expected to have.
+type MetadataField struct {
+	// Key is a dot-separated path into the asset's metadata.
+	Key string `json:"key"`
+	// Type is one of string, number, boolean, object or array. Empty
+	// accepts any value.
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Enum restricts a string field to these values.
+	Enum []string `json:"enum,omitempty"`
+} // @name AssetTypeMetadataField
+
+// Input is the editable part of an asset type.
+type Input struct {
+	Icon           string          `json:"icon,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	MetadataSchema []MetadataField `json:"metadata_schema"`
+} // @name AssetTypeInput
+
+type registryCache struct {
+	sync.RWMutex
+	types     map[string]*AssetType // lower-cased name -> type
+	expiresAt time.Time
+}
+
+type Service struct {
+	repo  Repository
+	cache registryCache
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Put creates or replaces an asset type. Names are matched ignoring case,
+// and an existing type keeps the casing it was registered with.
+func (s *Service) Put(ctx context.Context, name string, input Input, updatedBy *string) (*AssetType, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("name must be between 1 and %d characters", maxNameLength)}
+	}
+	input.Icon = strings.TrimSpace(input.Icon)
+	if len(input.Icon) > maxIconLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("icon must be %d characters or fewer", maxIconLength)}
+	}
+	input.Description = strings.TrimSpace(input.Description)
+	if len(input.Description) > maxDescriptionLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("description must be %d characters or fewer", maxDescriptionLength)}
+	}
+	if err := validateSchema(input.MetadataSchema); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.Get(ctx, name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		name = existing.Name
+	}
+
+	t := &AssetType{
+		Name:           name,
+		Icon:           input.Icon,
+		Description:    input.Description,
+		MetadataSchema: input.MetadataSchema,
+		UpdatedBy:      updatedBy,
+	}
+	if t.MetadataSchema == nil {
+		t.MetadataSchema = []MetadataField{}
+	}
+
+	if err := s.repo.Upsert(ctx, t); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return t, nil
+}
+
+func (s *Service) Get(ctx context.Context, name string) (*AssetType, error) {
+	return s.repo.Get(ctx, name)
+}
+
+func (s *Service) List(ctx context.Context) ([]*AssetType, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *Service) Delete(ctx context.Context, name string) error {
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ValidateMetadata checks metadata against the schema registered for the
+// asset type. Types without a registration accept any metadata. It
+// implements asset.MetadataValidator.
+func (s *Service) ValidateMetadata(ctx context.Context, assetType string, metadata map[string]interface{}) error {
+	types, err := s.registry(ctx)
+	if err != nil {
+		return fmt.Errorf("loading asset types: %w", err)
+	}
+	t, ok := types[strings.ToLower(assetType)]
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	for _, f := range t.MetadataSchema {
+		value, found := lookup(metadata, f.Key)
+		if !found || value == nil {
+			if f.Required {
+				problems = append(problems, fmt.Sprintf("metadata.%s is required", f.Key))
+			}
+			continue
+		}
+		if f.Type != "" && !hasType(value, f.Type) {
+			problems = append(problems, fmt.Sprintf("metadata.%s must be a %s", f.Key, f.Type))
+			continue
+		}
+		if len(f.Enum) > 0 {
+			if str, ok := value.(string); !ok || !contains(f.Enum, str) {
+				problems = append(problems, fmt.Sprintf("metadata.%s must be one of %s", f.Key, strings.Join(f.Enum, ", ")))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Message: fmt.Sprintf("%s assets: %s", t.Name, strings.Join(problems, "; "))}
+	}
+	return nil
+}
+
+// Names returns the names of the registered types, sorted.
+func (s *Service) Names(ctx context.Context) ([]string, error) {
+	types, err := s.registry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// registry returns the registered types keyed by lower-cased name, cached
+// so validating ingested assets doesn't query the registry every time.
+func (s *Service) registry(ctx context.Context) (map[string]*AssetType, error) {
+	s.cache.RLock()
+	if s.cache.types != nil && time.Now().Before(s.cache.expiresAt) {
+		types := s.cache.types
+		s.cache.RUnlock()
+		return types, nil
+	}
+	s.cache.RUnlock()
+
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]*AssetType, len(list))
+	for _, t := range list {
+		types[strings.ToLower(t.Name)] = t
+	}
+
+	s.cache.Lock()
+	s.cache.types = types
+	s.cache.expiresAt = time.Now().Add(cacheTTL)
+	s.cache.Unlock()
+	return types, nil
+}
+
+func (s *Service) invalidate() {
+	s.cache.Lock()
+	s.cache.types = nil
+	s.cache.Unlock()
+}
+
+func validateSchema(fields []MetadataField) error {
+	if len(fields) > maxFields {
+		return &ValidationError{Message: fmt.Sprintf("at most %d metadata fields are allowed", maxFields)}
+	}
+	keys := make(map[string]bool, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		f.Key = strings.TrimSpace(f.Key)
+		f.Type = strings.ToLower(strings.TrimSpace(f.Type))
+		f.Description = strings.TrimSpace(f.Description)
+
+		if f.Key == "" {
+			return &ValidationError{Message: "metadata field key is required"}
+		}
+		for _, part := range strings.Split(f.Key, ".") {
+			if part == "" {
+				return &ValidationError{Message: fmt.Sprintf("invalid metadata key %q", f.Key)}
+			}
+		}
+		if keys[f.Key] {
+			return &ValidationError{Message: fmt.Sprintf("metadata key %q appears more than once", f.Key)}
+		}
+		keys[f.Key] = true
+
+		if f.Type != "" && !fieldTypes[f.Type] {
+			return &ValidationError{Message: fmt.Sprintf("metadata field %q has unknown type %q", f.Key, f.Type)}
+		}
+		if len(f.Enum) > 0 && f.Type != FieldString {
+			return &ValidationError{Message: fmt.Sprintf("metadata field %q can only have enum values if its type is string", f.Key)}
+		}
+	}
+	return nil
+}
+
+// lookup finds a dot-separated key in nested metadata.
+func lookup(metadata map[string]interface{}, key string) (interface{}, bool) {
+	var current interface{} = metadata
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func hasType(value interface{}, fieldType string) bool {
+	switch fieldType {
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldNumber:
+		switch value.(type) {
+		case float64, float32, int, int32, int64, uint, uint32, uint64:
+			return true
+		}
+		return false
+	case FieldBoolean:
+		_, ok := value.(bool)
+		return ok
+	case FieldObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case FieldArray:
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return true
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
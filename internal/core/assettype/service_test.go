@@ -0,0 +1,162 @@
+package assettype
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	types map[string]*AssetType
+}
+
+func (f *fakeRepo) Upsert(ctx context.Context, t *AssetType) error {
+	f.types[strings.ToLower(t.Name)] = t
+	return nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, name string) (*AssetType, error) {
+	if t, ok := f.types[strings.ToLower(name)]; ok {
+		return t, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (f *fakeRepo) List(ctx context.Context) ([]*AssetType, error) {
+	list := make([]*AssetType, 0, len(f.types))
+	for _, t := range f.types {
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, name string) error {
+	if _, ok := f.types[strings.ToLower(name)]; !ok {
+		return ErrNotFound
+	}
+	delete(f.types, strings.ToLower(name))
+	return nil
+}
+
+func TestPutKeepsExistingName(t *testing.T) {
+	repo := &fakeRepo{types: map[string]*AssetType{}}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.Put(ctx, " ML Model ", Input{
+		Icon:           " brain ",
+		MetadataSchema: []MetadataField{{Key: " framework ", Type: " String "}},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ML Model", created.Name)
+	assert.Equal(t, "brain", created.Icon)
+	assert.Equal(t, MetadataField{Key: "framework", Type: FieldString}, created.MetadataSchema[0])
+
+	updated, err := svc.Put(ctx, "ml model", Input{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ML Model", updated.Name)
+	assert.Equal(t, []MetadataField{}, updated.MetadataSchema)
+	assert.Len(t, repo.types, 1)
+}
+
+func TestPutValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Input
+	}{
+		{"empty key", Input{MetadataSchema: []MetadataField{{Key: " "}}}},
+		{"empty key segment", Input{MetadataSchema: []MetadataField{{Key: "model..owner"}}}},
+		{"duplicate key", Input{MetadataSchema: []MetadataField{{Key: "a"}, {Key: "a"}}}},
+		{"unknown type", Input{MetadataSchema: []MetadataField{{Key: "a", Type: "date"}}}},
+		{"enum on number", Input{MetadataSchema: []MetadataField{{Key: "a", Type: FieldNumber, Enum: []string{"1"}}}}},
+	}
+
+	svc := NewService(&fakeRepo{types: map[string]*AssetType{}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Put(context.Background(), "Model", tt.input, nil)
+			assert.True(t, IsValidationError(err), "expected validation error, got %v", err)
+		})
+	}
+
+	_, err := svc.Put(context.Background(), " ", Input{}, nil)
+	assert.True(t, IsValidationError(err))
+}
+
+func TestValidateMetadata(t *testing.T) {
+	svc := NewService(&fakeRepo{types: map[string]*AssetType{}})
+	ctx := context.Background()
+	_, err := svc.Put(ctx, "ML Model", Input{MetadataSchema: []MetadataField{
+		{Key: "framework", Type: FieldString, Required: true, Enum: []string{"pytorch", "tensorflow"}},
+		{Key: "training.epochs", Type: FieldNumber},
+		{Key: "features", Type: FieldArray},
+	}}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		assetType string
+		metadata  map[string]interface{}
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			assetType: "ml model",
+			metadata: map[string]interface{}{
+				"framework": "pytorch",
+				"training":  map[string]interface{}{"epochs": float64(10)},
+				"features":  []interface{}{"age"},
+			},
+		},
+		{name: "unregistered type", assetType: "Table"},
+		{name: "missing required", assetType: "ML Model", metadata: map[string]interface{}{}, wantErr: "metadata.framework is required"},
+		{name: "not in enum", assetType: "ML Model", metadata: map[string]interface{}{"framework": "jax"}, wantErr: "metadata.framework must be one of pytorch, tensorflow"},
+		{
+			name:      "wrong nested type",
+			assetType: "ML Model",
+			metadata: map[string]interface{}{
+				"framework": "pytorch",
+				"training":  map[string]interface{}{"epochs": "ten"},
+			},
+			wantErr: "metadata.training.epochs must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.ValidateMetadata(ctx, tt.assetType, tt.metadata)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.True(t, IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRegistryInvalidatedOnWrite(t *testing.T) {
+	svc := NewService(&fakeRepo{types: map[string]*AssetType{}})
+	ctx := context.Background()
+
+	names, err := svc.Names(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = svc.Put(ctx, "Model", Input{}, nil)
+	require.NoError(t, err)
+	_, err = svc.Put(ctx, "Feature", Input{}, nil)
+	require.NoError(t, err)
+
+	names, err = svc.Names(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Feature", "Model"}, names)
+
+	require.NoError(t, svc.Delete(ctx, "model"))
+	names, err = svc.Names(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Feature"}, names)
+}
@@ -0,0 +1,109 @@
+package assettype
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the asset type registry data access interface. Names
+// are matched ignoring case.
+type Repository interface {
+	Upsert(ctx context.Context, t *AssetType) error
+	Get(ctx context.Context, name string) (*AssetType, error)
+	List(ctx context.Context) ([]*AssetType, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectAssetType = `
+	SELECT name, icon, description, metadata_schema, updated_by, created_at, updated_at
+	FROM asset_types`
+
+func (r *PostgresRepository) Upsert(ctx context.Context, t *AssetType) error {
+	schemaJSON, err := json.Marshal(t.MetadataSchema)
+	if err != nil {
+		return fmt.Errorf("marshaling metadata schema: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO asset_types (name, icon, description, metadata_schema, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE
+		SET icon = EXCLUDED.icon, description = EXCLUDED.description,
+		    metadata_schema = EXCLUDED.metadata_schema,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING created_at, updated_at`,
+		t.Name, t.Icon, t.Description, schemaJSON, t.UpdatedBy,
+	).Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving asset type: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, name string) (*AssetType, error) {
+	t, err := scanAssetType(r.db.QueryRow(ctx, selectAssetType+" WHERE LOWER(name) = LOWER($1)", name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting asset type: %w", err)
+	}
+	return t, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]*AssetType, error) {
+	rows, err := r.db.Query(ctx, selectAssetType+" ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing asset types: %w", err)
+	}
+	defer rows.Close()
+
+	types := []*AssetType{}
+	for rows.Next() {
+		t, err := scanAssetType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning asset type: %w", err)
+		}
+		types = append(types, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating asset types: %w", err)
+	}
+	return types, nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, name string) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM asset_types WHERE LOWER(name) = LOWER($1)", name)
+	if err != nil {
+		return fmt.Errorf("deleting asset type: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanAssetType(row pgx.Row) (*AssetType, error) {
+	var t AssetType
+	var schemaJSON []byte
+	if err := row.Scan(&t.Name, &t.Icon, &t.Description, &schemaJSON, &t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(schemaJSON, &t.MetadataSchema); err != nil {
+		return nil, fmt.Errorf("unmarshaling metadata schema: %w", err)
+	}
+	return &t, nil
+}
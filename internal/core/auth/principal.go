@@ -184,7 +184,7 @@ func (p serviceAccountPrincipal) ID() string          { return p.id }
 func (p serviceAccountPrincipal) Type() PrincipalType { return PrincipalTypeServiceAccount }
 func (p serviceAccountPrincipal) DisplayName() string { return p.name }
 func (p serviceAccountPrincipal) AuditSubject() string {
-	return "service_account:" + strings.ReplaceAll(p.name, ":", "%3A")
+	return ServiceAccountSubject(p.name)
 }
 func (p serviceAccountPrincipal) Roles() []string { return p.roleNames }
 
@@ -208,3 +208,21 @@ func (p serviceAccountPrincipal) HasPermission(resourceType, action string) bool
 
 func (p serviceAccountPrincipal) AsUser() *user.User { return nil }
 
+// ServiceAccountSubjectPrefix starts the AuditSubject of every service account.
+const ServiceAccountSubjectPrefix = "service_account:"
+
+// ServiceAccountSubject returns the AuditSubject of the service account name.
+func ServiceAccountSubject(name string) string {
+	return ServiceAccountSubjectPrefix + strings.ReplaceAll(name, ":", "%3A")
+}
+
+// CreatedBy returns the name a principal is recorded under as the creator of
+// runs and the assets they ingest: the username for users, and the
+// AuditSubject for machine principals, so a service account never reads
+// as a person.
+func CreatedBy(p Principal) string {
+	if u := p.AsUser(); u != nil {
+		return u.Username
+	}
+	return p.AuditSubject()
+}
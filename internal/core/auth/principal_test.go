@@ -184,3 +184,22 @@ func TestAuditSubject_NeverLeaksUUID(t *testing.T) {
 		t.Errorf("operator AuditSubject() leaked raw UUID: %q", got)
 	}
 }
+
+func TestCreatedBy(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Principal
+		want string
+	}{
+		{"user", NewUserPrincipal(&user.User{Username: "alice", Name: "Alice"}), "alice"},
+		{"operator", NewOperatorPrincipal(), "operator"},
+		{"service account", NewServiceAccountPrincipal("sa-1", "ingest-prod", nil, nil), "service_account:ingest-prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreatedBy(tt.p); got != tt.want {
+				t.Errorf("CreatedBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OPAHook asks an Open Policy Agent server for a decision through its data
+// API. The policy may return a boolean, or an object with an "allow" boolean
+// and an optional "reason". An undefined result denies the request.
+type OPAHook struct {
+	url    string
+	client *http.Client
+}
+
+func NewOPAHook(url string, timeout time.Duration) *OPAHook {
+	return &OPAHook{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+type opaRequest struct {
+	Input Input `json:"input"`
+}
+
+type opaResponse struct {
+	Result json.RawMessage `json:"result"`
+}
+
+type opaObjectResult struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+func (h *OPAHook) Authorize(ctx context.Context, input Input) (Decision, error) {
+	body, err := json.Marshal(opaRequest{Input: input})
+	if err != nil {
+		return Decision{}, fmt.Errorf("encoding OPA input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, fmt.Errorf("creating OPA request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("querying OPA: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Decision{}, fmt.Errorf("OPA returned %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out opaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Decision{}, fmt.Errorf("decoding OPA response: %w", err)
+	}
+
+	if len(out.Result) == 0 || string(out.Result) == "null" {
+		return Decision{Reason: "no policy decision"}, nil
+	}
+
+	var allow bool
+	if err := json.Unmarshal(out.Result, &allow); err == nil {
+		return Decision{Allow: allow}, nil
+	}
+
+	var obj opaObjectResult
+	if err := json.Unmarshal(out.Result, &obj); err != nil {
+		return Decision{}, fmt.Errorf("OPA result must be a boolean or an object with allow: %w", err)
+	}
+	return Decision{Allow: obj.Allow, Reason: obj.Reason}, nil
+}
@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOPAHookDecisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     Decision
+	}{
+		{name: "boolean allow", response: `{"result": true}`, want: Decision{Allow: true}},
+		{name: "boolean deny", response: `{"result": false}`, want: Decision{}},
+		{
+			name:     "object with reason",
+			response: `{"result": {"allow": false, "reason": "pii assets are restricted"}}`,
+			want:     Decision{Reason: "pii assets are restricted"},
+		},
+		{name: "undefined result", response: `{}`, want: Decision{Reason: "no policy decision"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			got, err := NewOPAHook(srv.URL, time.Second).Authorize(context.Background(), Input{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOPAHookSendsInput(t *testing.T) {
+	var received opaRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"result": true}`))
+	}))
+	defer srv.Close()
+
+	input := Input{
+		Subject:      Subject{ID: "u1", Type: "user", Teams: []string{"finance"}},
+		ResourceType: "assets",
+		Action:       "manage",
+		Resource:     &Resource{ID: "a1", Tags: []string{"pii"}, Environments: []string{"prod"}},
+	}
+	_, err := NewOPAHook(srv.URL, time.Second).Authorize(context.Background(), input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "manage", received.Input.Action)
+	assert.Equal(t, []string{"finance"}, received.Input.Subject.Teams)
+	require.NotNil(t, received.Input.Resource)
+	assert.Equal(t, []string{"prod"}, received.Input.Resource.Environments)
+}
+
+func TestOPAHookErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "policy error", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewOPAHook(srv.URL, time.Second).Authorize(context.Background(), Input{})
+	assert.ErrorContains(t, err, "500")
+}
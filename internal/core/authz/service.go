@@ -0,0 +1,168 @@
+// Package authz evaluates custom authorization hooks, such as Open Policy
+// Agent, on API requests. Hooks run after the built-in role based check has
+// allowed a request and can only narrow access, never widen it.
+package authz
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrUnavailable = errors.New("authorization hook unavailable")
+
+// Subject is the principal making a request.
+type Subject struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Roles   []string `json:"roles"`
+	Teams   []string `json:"teams"`
+	IsAdmin bool     `json:"is_admin"`
+}
+
+// Resource describes the asset a request acts on. Owner teams and users are
+// given by name and username.
+type Resource struct {
+	ID           string   `json:"id"`
+	MRN          string   `json:"mrn"`
+	Type         string   `json:"type"`
+	Providers    []string `json:"providers"`
+	Tags         []string `json:"tags"`
+	Environments []string `json:"environments"`
+	OwnerTeams   []string `json:"owner_teams"`
+	OwnerUsers   []string `json:"owner_users"`
+}
+
+// HTTPRequest is the API call being authorized. Route is the matched
+// pattern, e.g. "/api/v1/assets/{id}".
+type HTTPRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Route  string `json:"route"`
+}
+
+// Input is everything a hook is given to decide on a request.
+type Input struct {
+	Subject Subject `json:"subject"`
+	// ResourceType and Action are the permission Marmot checked, e.g.
+	// "assets" and "manage".
+	ResourceType string      `json:"resource_type"`
+	Action       string      `json:"action"`
+	Request      HTTPRequest `json:"request"`
+	// Resource is set when the request names a single asset.
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+type Decision struct {
+	Allow bool
+	// Reason is shown to the caller when the request is denied.
+	Reason string
+}
+
+// Hook decides whether a request already allowed by Marmot's roles may go
+// ahead.
+type Hook interface {
+	Authorize(ctx context.Context, input Input) (Decision, error)
+}
+
+// AttributeSource looks up what hooks need to know about subjects and
+// resources.
+type AttributeSource interface {
+	// UserTeams returns the names of the teams a user belongs to.
+	UserTeams(ctx context.Context, userID string) ([]string, error)
+	// Asset returns the attributes of an asset, or nil if it doesn't exist.
+	Asset(ctx context.Context, id string) (*Resource, error)
+}
+
+// Request identifies a permission check to run hooks for.
+type Request struct {
+	Principal    auth.Principal
+	ResourceType string
+	Action       string
+	HTTP         HTTPRequest
+	// AssetID is the asset named by the request, if any.
+	AssetID string
+}
+
+type Service struct {
+	hook     Hook
+	attrs    AttributeSource
+	failOpen bool
+}
+
+// NewService creates a service that runs hook. With failOpen, requests are
+// allowed when the hook fails.
+func NewService(hook Hook, attrs AttributeSource, failOpen bool) *Service {
+	return &Service{hook: hook, attrs: attrs, failOpen: failOpen}
+}
+
+// Authorize runs the hook for req. It returns ErrUnavailable when the hook
+// fails and the service isn't configured to fail open.
+func (s *Service) Authorize(ctx context.Context, req Request) (Decision, error) {
+	input := Input{
+		ResourceType: req.ResourceType,
+		Action:       req.Action,
+		Request:      req.HTTP,
+	}
+	if p := req.Principal; p != nil {
+		input.Subject = Subject{
+			ID:      p.ID(),
+			Type:    string(p.Type()),
+			Name:    p.DisplayName(),
+			Roles:   nonNil(p.Roles()),
+			Teams:   []string{},
+			IsAdmin: p.IsAdmin(),
+		}
+		if p.Type() == auth.PrincipalTypeUser && s.attrs != nil {
+			teams, err := s.attrs.UserTeams(ctx, p.ID())
+			if err != nil {
+				return s.failure(err, "Failed to load subject teams for authorization hook")
+			}
+			input.Subject.Teams = nonNil(teams)
+		}
+	}
+
+	if req.AssetID != "" && s.attrs != nil {
+		resource, err := s.attrs.Asset(ctx, req.AssetID)
+		if err != nil {
+			return s.failure(err, "Failed to load asset for authorization hook")
+		}
+		input.Resource = resource
+	}
+
+	decision, err := s.hook.Authorize(ctx, input)
+	if err != nil {
+		return s.failure(err, "Authorization hook failed")
+	}
+	if !decision.Allow {
+		log.Debug().
+			Str("subject", input.Subject.ID).
+			Str("resource_type", input.ResourceType).
+			Str("action", input.Action).
+			Str("route", input.Request.Route).
+			Str("reason", decision.Reason).
+			Msg("Request denied by authorization hook")
+	}
+	return decision, nil
+}
+
+func (s *Service) failure(err error, msg string) (Decision, error) {
+	log.Error().Err(err).Bool("fail_open", s.failOpen).Msg(msg)
+	if s.failOpen {
+		return Decision{Allow: true}, nil
+	}
+	return Decision{}, ErrUnavailable
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/auth"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHook struct {
+	input    Input
+	decision Decision
+	err      error
+}
+
+func (h *recordingHook) Authorize(_ context.Context, input Input) (Decision, error) {
+	h.input = input
+	return h.decision, h.err
+}
+
+type fakeAttributes struct {
+	teams  []string
+	assets map[string]*Resource
+	err    error
+}
+
+func (f *fakeAttributes) UserTeams(context.Context, string) ([]string, error) {
+	return f.teams, f.err
+}
+
+func (f *fakeAttributes) Asset(_ context.Context, id string) (*Resource, error) {
+	return f.assets[id], f.err
+}
+
+func TestAuthorizeBuildsInput(t *testing.T) {
+	hook := &recordingHook{decision: Decision{Allow: true}}
+	attrs := &fakeAttributes{
+		teams:  []string{"platform", "finance"},
+		assets: map[string]*Resource{"a1": {ID: "a1", Tags: []string{"pii"}}},
+	}
+	svc := NewService(hook, attrs, false)
+
+	principal := auth.NewUserPrincipal(&user.User{
+		ID:       "u1",
+		Username: "alice",
+		Roles:    []user.Role{{Name: "user"}},
+	})
+	decision, err := svc.Authorize(context.Background(), Request{
+		Principal:    principal,
+		ResourceType: "assets",
+		Action:       "manage",
+		HTTP:         HTTPRequest{Method: "PUT", Path: "/api/v1/assets/a1", Route: "/api/v1/assets/{id}"},
+		AssetID:      "a1",
+	})
+	require.NoError(t, err)
+	assert.True(t, decision.Allow)
+
+	assert.Equal(t, "u1", hook.input.Subject.ID)
+	assert.Equal(t, "user", hook.input.Subject.Type)
+	assert.Equal(t, []string{"user"}, hook.input.Subject.Roles)
+	assert.Equal(t, []string{"finance", "platform"}, hook.input.Subject.Teams)
+	assert.False(t, hook.input.Subject.IsAdmin)
+	assert.Equal(t, "manage", hook.input.Action)
+	assert.Equal(t, "/api/v1/assets/{id}", hook.input.Request.Route)
+	require.NotNil(t, hook.input.Resource)
+	assert.Equal(t, []string{"pii"}, hook.input.Resource.Tags)
+}
+
+func TestAuthorizeFailures(t *testing.T) {
+	hook := &recordingHook{err: errors.New("connection refused")}
+
+	_, err := NewService(hook, nil, false).Authorize(context.Background(), Request{ResourceType: "assets", Action: "view"})
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	decision, err := NewService(hook, nil, true).Authorize(context.Background(), Request{ResourceType: "assets", Action: "view"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allow)
+
+	attrs := &fakeAttributes{err: errors.New("db down")}
+	_, err = NewService(&recordingHook{decision: Decision{Allow: true}}, attrs, false).
+		Authorize(context.Background(), Request{ResourceType: "assets", Action: "view", AssetID: "a1"})
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
@@ -0,0 +1,271 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxRowSize bounds a single row in a table file.
+const maxRowSize = 64 * 1024 * 1024
+
+// Archive is an unpacked backup. Table files are spooled to a temporary
+// directory so large catalogs aren't held in memory; call Close to remove it.
+type Archive struct {
+	Manifest Manifest
+	dir      string
+}
+
+// Close removes the archive's spooled table files.
+func (a *Archive) Close() error {
+	return os.RemoveAll(a.dir)
+}
+
+// Info returns the manifest entry for a table.
+func (a *Archive) Info(table string) (TableInfo, bool) {
+	for _, t := range a.Manifest.Tables {
+		if t.Name == table {
+			return t, true
+		}
+	}
+	return TableInfo{}, false
+}
+
+// EachRow calls fn with every row of a table. Numbers are decoded as
+// json.Number so IDs and large values survive unchanged.
+func (a *Archive) EachRow(table string, fn func(row map[string]interface{}) error) error {
+	if _, ok := a.Info(table); !ok {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Join(a.dir, table+".ndjson"))
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", table, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxRowSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.UseNumber()
+		var row map[string]interface{}
+		if err := dec.Decode(&row); err != nil {
+			return fmt.Errorf("%s row %d: %w", table, line, err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", table, err)
+	}
+	return nil
+}
+
+// tableSpool collects a table's rows while counting and hashing them.
+type tableSpool struct {
+	file *os.File
+	buf  *bufio.Writer
+	sum  func() string
+	rows int
+}
+
+func newTableSpool(dir, table string) (*tableSpool, error) {
+	f, err := os.Create(filepath.Join(dir, table+".ndjson"))
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	return &tableSpool{
+		file: f,
+		buf:  bufio.NewWriter(io.MultiWriter(f, h)),
+		sum:  func() string { return hex.EncodeToString(h.Sum(nil)) },
+	}, nil
+}
+
+func (s *tableSpool) writeRow(row []byte) error {
+	if _, err := s.buf.Write(row); err != nil {
+		return err
+	}
+	s.rows++
+	return s.buf.WriteByte('\n')
+}
+
+func (s *tableSpool) close() error {
+	if err := s.buf.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// writeArchive packs the manifest and spooled table files into w.
+func writeArchive(w io.Writer, manifest Manifest, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	for _, t := range manifest.Tables {
+		if err := addFile(tw, filepath.Join(dir, t.Name+".ndjson"), tablesDir+t.Name+".ndjson", manifest.CreatedAt); err != nil {
+			return fmt.Errorf("adding %s: %w", t.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, path, name string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: stat.Size(), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// ReadArchive unpacks an archive, checking its format version and that every
+// table file matches the row count and checksum in the manifest.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	dir, err := os.MkdirTemp("", "marmot-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool directory: %w", err)
+	}
+	archive := &Archive{dir: dir}
+
+	if err := archive.unpack(tar.NewReader(gz)); err != nil {
+		archive.Close()
+		return nil, err
+	}
+	return archive, nil
+}
+
+func (a *Archive) unpack(tr *tar.Reader) error {
+	hdr, err := tr.Next()
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+	if hdr.Name != manifestName {
+		return fmt.Errorf("archive must start with %s, found %s", manifestName, hdr.Name)
+	}
+	if err := json.NewDecoder(tr).Decode(&a.Manifest); err != nil {
+		return fmt.Errorf("decoding manifest: %w", err)
+	}
+	if a.Manifest.FormatVersion < 1 || a.Manifest.FormatVersion > FormatVersion {
+		return fmt.Errorf("unsupported archive format %d, this version of Marmot reads up to %d", a.Manifest.FormatVersion, FormatVersion)
+	}
+
+	known := map[string]bool{}
+	for _, t := range Tables {
+		known[t.Name] = true
+	}
+	expected := map[string]TableInfo{}
+	for _, t := range a.Manifest.Tables {
+		if !known[t.Name] {
+			return fmt.Errorf("archive contains unknown table %q", t.Name)
+		}
+		expected[t.Name] = t
+	}
+
+	seen := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+
+		name, ok := strings.CutPrefix(hdr.Name, tablesDir)
+		name, isTable := strings.CutSuffix(name, ".ndjson")
+		info, listed := expected[name]
+		if !ok || !isTable || !listed {
+			return fmt.Errorf("unexpected archive entry %s", hdr.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate archive entry %s", hdr.Name)
+		}
+		seen[name] = true
+
+		spool, err := newTableSpool(a.dir, name)
+		if err != nil {
+			return fmt.Errorf("spooling %s: %w", name, err)
+		}
+		scanner := bufio.NewScanner(tr)
+		scanner.Buffer(make([]byte, 64*1024), maxRowSize)
+		for scanner.Scan() {
+			if err := spool.writeRow(scanner.Bytes()); err != nil {
+				spool.close()
+				return fmt.Errorf("spooling %s: %w", name, err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			spool.close()
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := spool.close(); err != nil {
+			return fmt.Errorf("spooling %s: %w", name, err)
+		}
+
+		if spool.rows != info.Rows {
+			return fmt.Errorf("%s has %d rows, manifest records %d", name, spool.rows, info.Rows)
+		}
+		if sum := spool.sum(); sum != info.SHA256 {
+			return fmt.Errorf("%s checksum mismatch: archive is corrupt", name)
+		}
+	}
+
+	for name := range expected {
+		if !seen[name] {
+			return fmt.Errorf("archive is missing table %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package backup exports the catalog to a versioned archive and restores it,
+// independently of the database's physical layout.
+//
+// An archive is a gzipped tar holding manifest.json followed by one
+// newline-delimited JSON file per table. Rows are stored as the table's
+// columns, so an archive can be restored into a newer schema: columns added
+// since the backup take their defaults.
+package backup
+
+import (
+	"time"
+)
+
+// FormatVersion is the archive layout version. Restore refuses archives with
+// a newer format.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	tablesDir    = "tables/"
+)
+
+const (
+	SectionUsers     = "users"
+	SectionTeams     = "teams"
+	SectionAssets    = "assets"
+	SectionLineage   = "lineage"
+	SectionGlossary  = "glossary"
+	SectionProducts  = "products"
+	SectionSchedules = "schedules"
+)
+
+// Table is a table included in a backup.
+type Table struct {
+	Name    string
+	Section string
+	// NaturalKey names a unique column identifying rows that may already
+	// exist in the target, such as the seeded admin user. Existing rows are
+	// kept and references to them are rewritten to their IDs.
+	NaturalKey string
+	// Key is the primary key column used to remap IDs. Defaults to "id".
+	Key string
+}
+
+func (t Table) key() string {
+	if t.Key != "" {
+		return t.Key
+	}
+	return "id"
+}
+
+// Tables lists the tables in a backup, parents before children. Derived
+// tables (search index, tag index, metrics) are rebuilt by the server and
+// left out, as are credentials such as API keys and SSO identities.
+var Tables = []Table{
+	{Name: "users", Section: SectionUsers, NaturalKey: "username"},
+	{Name: "roles", Section: SectionUsers, NaturalKey: "name"},
+	{Name: "permissions", Section: SectionUsers, NaturalKey: "name"},
+	{Name: "role_permissions", Section: SectionUsers},
+	{Name: "user_roles", Section: SectionUsers},
+	{Name: "service_accounts", Section: SectionUsers},
+	{Name: "service_account_roles", Section: SectionUsers},
+
+	{Name: "teams", Section: SectionTeams},
+	{Name: "team_members", Section: SectionTeams},
+	{Name: "sso_team_mappings", Section: SectionTeams},
+
+	{Name: "schemas", Section: SectionAssets, Key: "hash"},
+	{Name: "assets", Section: SectionAssets},
+	{Name: "asset_owners", Section: SectionAssets},
+	{Name: "documentation", Section: SectionAssets},
+
+	{Name: "lineage_events", Section: SectionLineage, Key: "event_id"},
+	{Name: "lineage_edges", Section: SectionLineage},
+
+	{Name: "glossary_terms", Section: SectionGlossary},
+	{Name: "glossary_term_owners", Section: SectionGlossary},
+	{Name: "asset_terms", Section: SectionGlossary},
+
+	{Name: "data_products", Section: SectionProducts},
+	{Name: "data_product_owners", Section: SectionProducts},
+	{Name: "data_product_assets", Section: SectionProducts},
+	{Name: "data_product_rules", Section: SectionProducts},
+	{Name: "data_product_rule_targets", Section: SectionProducts},
+	{Name: "data_product_memberships", Section: SectionProducts},
+	{Name: "product_images", Section: SectionProducts},
+
+	{Name: "connections", Section: SectionSchedules},
+	{Name: "ingestion_schedules", Section: SectionSchedules},
+	{Name: "asset_schedules", Section: SectionSchedules},
+}
+
+// catalogTables must be empty before a restore unless it is forced.
+var catalogTables = []string{"assets", "glossary_terms", "teams", "data_products", "ingestion_schedules"}
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int         `json:"format_version"`
+	SchemaVersion int32       `json:"schema_version"`
+	MarmotVersion string      `json:"marmot_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Tables        []TableInfo `json:"tables"`
+}
+
+// TableInfo records a table's row count and the SHA-256 of its data file.
+type TableInfo struct {
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	Rows    int    `json:"rows"`
+	SHA256  string `json:"sha256"`
+}
+
+// ForeignKey is a single-column reference between tables.
+type ForeignKey struct {
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+}
@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildArchive(t *testing.T, tables map[string][]string) []byte {
+	t.Helper()
+	dir := t.TempDir()
+
+	manifest := Manifest{FormatVersion: FormatVersion, SchemaVersion: 56, CreatedAt: time.Now().UTC()}
+	for _, table := range Tables {
+		rows, ok := tables[table.Name]
+		if !ok {
+			continue
+		}
+		spool, err := newTableSpool(dir, table.Name)
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, spool.writeRow([]byte(row)))
+		}
+		require.NoError(t, spool.close())
+		manifest.Tables = append(manifest.Tables, TableInfo{Name: table.Name, Section: table.Section, Rows: spool.rows, SHA256: spool.sum()})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeArchive(&buf, manifest, dir))
+	return buf.Bytes()
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	data := buildArchive(t, map[string][]string{
+		"teams":        {`{"id":"t1","name":"Data"}`},
+		"team_members": {`{"team_id":"t1","user_id":"u1","weight":12345678901234567}`},
+	})
+
+	a, err := ReadArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer a.Close()
+
+	assert.Equal(t, int32(56), a.Manifest.SchemaVersion)
+	info, ok := a.Info("team_members")
+	require.True(t, ok)
+	assert.Equal(t, 1, info.Rows)
+
+	var rows []map[string]interface{}
+	require.NoError(t, a.EachRow("team_members", func(row map[string]interface{}) error {
+		rows = append(rows, row)
+		return nil
+	}))
+	require.Len(t, rows, 1)
+	assert.Equal(t, json.Number("12345678901234567"), rows[0]["weight"])
+}
+
+func TestReadArchiveDetectsCorruption(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := newTableSpool(dir, "teams")
+	require.NoError(t, err)
+	require.NoError(t, spool.writeRow([]byte(`{"id":"t1","name":"Data"}`)))
+	require.NoError(t, spool.close())
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		Tables:        []TableInfo{{Name: "teams", Section: SectionTeams, Rows: 1, SHA256: spool.sum()}},
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "teams.ndjson"), []byte(`{"id":"t1","name":"Date"}`+"\n"), 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeArchive(&buf, manifest, dir))
+
+	_, err = ReadArchive(&buf)
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestReadArchiveRejectsNewerFormat(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeArchive(&buf, Manifest{FormatVersion: FormatVersion + 1}, t.TempDir()))
+
+	_, err := ReadArchive(&buf)
+	assert.ErrorContains(t, err, "unsupported archive format")
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	data := buildArchive(t, map[string][]string{
+		"teams": {`{"id":"t1"}`},
+		"team_members": {
+			`{"team_id":"t1","user_id":"u1"}`,
+			`{"team_id":"t2","user_id":"u1"}`,
+			`{"team_id":"t3","user_id":"u1"}`,
+		},
+	})
+	a, err := ReadArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer a.Close()
+
+	violations, err := CheckIntegrity(a, []ForeignKey{
+		{Table: "team_members", Column: "team_id", RefTable: "teams", RefColumn: "id"},
+		// users isn't in the archive, so the database checks it instead.
+		{Table: "team_members", Column: "user_id", RefTable: "users", RefColumn: "id"},
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "team_id", violations[0].Column)
+	assert.Equal(t, 2, violations[0].Count)
+	assert.Equal(t, "t2", violations[0].Example)
+}
+
+func TestCheckIntegrityScheduleReferences(t *testing.T) {
+	data := buildArchive(t, map[string][]string{
+		"service_accounts": {`{"id":"sa1","name":"loader"}`},
+		"connections":      {`{"id":"c1","name":"warehouse"}`},
+		"ingestion_schedules": {
+			`{"id":"s1","connection_id":"c1","service_account_id":"sa1"}`,
+			`{"id":"s2","connection_id":"c2","service_account_id":"sa2"}`,
+			`{"id":"s3","connection_id":null,"service_account_id":null}`,
+		},
+	})
+	a, err := ReadArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer a.Close()
+
+	violations, err := CheckIntegrity(a, []ForeignKey{
+		{Table: "ingestion_schedules", Column: "connection_id", RefTable: "connections", RefColumn: "id"},
+		{Table: "ingestion_schedules", Column: "service_account_id", RefTable: "service_accounts", RefColumn: "id"},
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "c2", violations[0].Example)
+	assert.Equal(t, "sa2", violations[1].Example)
+}
@@ -0,0 +1,147 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Export writes a backup of the catalog to w. Tables are read in a single
+// repeatable-read transaction, so the archive is a consistent snapshot even
+// while the server is running.
+func Export(ctx context.Context, db *pgxpool.Pool, w io.Writer, marmotVersion string) (*Manifest, error) {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "marmot-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		MarmotVersion: marmotVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for _, t := range Tables {
+		info, err := exportTable(ctx, tx, dir, t)
+		if err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", t.Name, err)
+		}
+		manifest.Tables = append(manifest.Tables, info)
+	}
+
+	if err := writeArchive(w, manifest, dir); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	return &manifest, nil
+}
+
+func exportTable(ctx context.Context, q querier, dir string, t Table) (TableInfo, error) {
+	spool, err := newTableSpool(dir, t.Name)
+	if err != nil {
+		return TableInfo{}, err
+	}
+
+	rows, err := q.Query(ctx, fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t`, pgx.Identifier{t.Name}.Sanitize()))
+	if err != nil {
+		spool.close()
+		return TableInfo{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			spool.close()
+			return TableInfo{}, err
+		}
+		if err := spool.writeRow([]byte(line)); err != nil {
+			spool.close()
+			return TableInfo{}, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		spool.close()
+		return TableInfo{}, err
+	}
+	if err := spool.close(); err != nil {
+		return TableInfo{}, err
+	}
+
+	return TableInfo{Name: t.Name, Section: t.Section, Rows: spool.rows, SHA256: spool.sum()}, nil
+}
+
+func schemaVersion(ctx context.Context, q querier) (int32, error) {
+	var version int32
+	err := q.QueryRow(ctx, `SELECT version FROM public.schema_version`).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("database has not been migrated")
+		}
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+	return version, nil
+}
+
+// foreignKeys returns the single-column foreign keys between backed-up
+// tables.
+func foreignKeys(ctx context.Context, q querier) ([]ForeignKey, error) {
+	included := map[string]bool{}
+	for _, t := range Tables {
+		included[t.Name] = true
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT cl.relname, a.attname, rcl.relname, ra.attname
+		FROM pg_constraint c
+		JOIN pg_class cl ON cl.oid = c.conrelid
+		JOIN pg_class rcl ON rcl.oid = c.confrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+		JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = c.confkey[1]
+		WHERE c.contype = 'f' AND n.nspname = 'public' AND array_length(c.conkey, 1) = 1`)
+	if err != nil {
+		return nil, fmt.Errorf("reading foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		if err := rows.Scan(&fk.Table, &fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+			return nil, fmt.Errorf("scanning foreign key: %w", err)
+		}
+		if included[fk.Table] && included[fk.RefTable] {
+			fks = append(fks, fk)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating foreign keys: %w", err)
+	}
+	return fks, nil
+}
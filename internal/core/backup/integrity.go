@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Violation is a column whose values reference rows missing from the
+// archive.
+type Violation struct {
+	Table     string `json:"table"`
+	Column    string `json:"column"`
+	RefTable  string `json:"ref_table"`
+	RefColumn string `json:"ref_column"`
+	Count     int    `json:"count"`
+	// Example is one of the missing values.
+	Example string `json:"example"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s.%s: %d references to missing %s.%s (e.g. %s)", v.Table, v.Column, v.Count, v.RefTable, v.RefColumn, v.Example)
+}
+
+// CheckIntegrity reports references between tables in the archive that
+// point at rows the archive doesn't contain. References to tables outside
+// the archive are left to the database.
+func CheckIntegrity(a *Archive, fks []ForeignKey) ([]Violation, error) {
+	type ref struct{ table, column string }
+
+	targets := map[ref]map[string]bool{}
+	for _, fk := range fks {
+		_, fromIncluded := a.Info(fk.Table)
+		_, toIncluded := a.Info(fk.RefTable)
+		if fromIncluded && toIncluded {
+			targets[ref{fk.RefTable, fk.RefColumn}] = map[string]bool{}
+		}
+	}
+
+	for r, values := range targets {
+		err := a.EachRow(r.table, func(row map[string]interface{}) error {
+			if v, ok := row[r.column]; ok && v != nil {
+				values[fmt.Sprint(v)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var violations []Violation
+	for _, fk := range fks {
+		values, ok := targets[ref{fk.RefTable, fk.RefColumn}]
+		if !ok {
+			continue
+		}
+		if _, ok := a.Info(fk.Table); !ok {
+			continue
+		}
+
+		v := Violation{Table: fk.Table, Column: fk.Column, RefTable: fk.RefTable, RefColumn: fk.RefColumn}
+		err := a.EachRow(fk.Table, func(row map[string]interface{}) error {
+			val, ok := row[fk.Column]
+			if !ok || val == nil {
+				return nil
+			}
+			if s := fmt.Sprint(val); !values[s] {
+				if v.Count == 0 {
+					v.Example = s
+				}
+				v.Count++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if v.Count > 0 {
+			violations = append(violations, v)
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Table != violations[j].Table {
+			return violations[i].Table < violations[j].Table
+		}
+		return violations[i].Column < violations[j].Column
+	})
+	return violations, nil
+}
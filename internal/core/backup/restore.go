@@ -0,0 +1,354 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// restoreBatchSize is the number of rows inserted per statement.
+const restoreBatchSize = 1000
+
+var (
+	ErrNewerSchema = errors.New("archive was taken from a newer schema")
+	ErrNotEmpty    = errors.New("catalog is not empty")
+)
+
+// IntegrityError reports references in an archive that don't resolve.
+type IntegrityError struct {
+	Violations []Violation
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("archive failed integrity checks: %d dangling reference(s)", len(e.Violations))
+}
+
+type RestoreOptions struct {
+	// Force restores into a catalog that already holds data. Rows that
+	// conflict with existing ones are skipped.
+	Force bool
+	// DryRun restores inside a transaction and rolls it back.
+	DryRun bool
+}
+
+type RestoreResult struct {
+	Tables []TableResult `json:"tables"`
+	DryRun bool          `json:"dry_run"`
+}
+
+// TableResult records how many of a table's archived rows were inserted.
+// Rows skipped because they already exist make up the difference.
+type TableResult struct {
+	Name     string `json:"name"`
+	Rows     int    `json:"rows"`
+	Inserted int64  `json:"inserted"`
+}
+
+// Restore loads an archive into the database in a single transaction. The
+// archive must come from the same or an older schema version, and every
+// reference in it must resolve within the archive.
+func Restore(ctx context.Context, db *pgxpool.Pool, a *Archive, opts RestoreOptions) (*RestoreResult, error) {
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if a.Manifest.SchemaVersion > version {
+		return nil, fmt.Errorf("%w: archive is at version %d, database is at %d; upgrade Marmot before restoring", ErrNewerSchema, a.Manifest.SchemaVersion, version)
+	}
+
+	fks, err := foreignKeys(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := CheckIntegrity(a, fks)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return nil, &IntegrityError{Violations: violations}
+	}
+
+	if !opts.Force {
+		if err := checkEmpty(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	r := &restorer{tx: tx, archive: a, fks: fks, idMaps: map[string]map[string]interface{}{}}
+	result := &RestoreResult{DryRun: opts.DryRun}
+	for _, t := range Tables {
+		info, ok := a.Info(t.Name)
+		if !ok {
+			continue
+		}
+		inserted, err := r.restoreTable(ctx, t)
+		if err != nil {
+			return nil, fmt.Errorf("restoring %s: %w", t.Name, err)
+		}
+		result.Tables = append(result.Tables, TableResult{Name: t.Name, Rows: info.Rows, Inserted: inserted})
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing restore: %w", err)
+	}
+	return result, nil
+}
+
+func checkEmpty(ctx context.Context, q querier) error {
+	var populated []string
+	for _, table := range catalogTables {
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, pgx.Identifier{table}.Sanitize())
+		if err := q.QueryRow(ctx, query).Scan(&exists); err != nil {
+			return fmt.Errorf("checking %s: %w", table, err)
+		}
+		if exists {
+			populated = append(populated, table)
+		}
+	}
+	if len(populated) > 0 {
+		return fmt.Errorf("%w: %s already hold data; use --force to merge into it", ErrNotEmpty, strings.Join(populated, ", "))
+	}
+	return nil
+}
+
+type restorer struct {
+	tx      pgx.Tx
+	archive *Archive
+	fks     []ForeignKey
+	// idMaps maps archived IDs to existing ones for tables with a natural
+	// key, keyed by table.
+	idMaps map[string]map[string]interface{}
+}
+
+func (r *restorer) restoreTable(ctx context.Context, t Table) (int64, error) {
+	columns, err := r.insertableColumns(ctx, t.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	var remaps, selfRefs []ForeignKey
+	for _, fk := range r.fks {
+		if fk.Table != t.Name {
+			continue
+		}
+		if fk.RefTable == t.Name {
+			selfRefs = append(selfRefs, fk)
+		} else if _, ok := r.idMaps[fk.RefTable]; ok {
+			remaps = append(remaps, fk)
+		}
+	}
+
+	var (
+		inserted int64
+		batch    []map[string]interface{}
+		deferred []map[string]interface{}
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := r.insertBatch(ctx, t, columns, batch)
+		if err != nil {
+			return err
+		}
+		inserted += n
+		batch = batch[:0]
+		return nil
+	}
+
+	err = r.archive.EachRow(t.Name, func(row map[string]interface{}) error {
+		for _, fk := range remaps {
+			row[fk.Column] = r.remap(fk.RefTable, row[fk.Column])
+		}
+		// Self references are set once every row of the table exists.
+		if len(selfRefs) > 0 {
+			update := map[string]interface{}{t.key(): row[t.key()]}
+			for _, fk := range selfRefs {
+				if row[fk.Column] != nil {
+					update[fk.Column] = row[fk.Column]
+					row[fk.Column] = nil
+				}
+			}
+			if len(update) > 1 {
+				deferred = append(deferred, update)
+			}
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= restoreBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+
+	for _, fk := range selfRefs {
+		if err := r.updateSelfRefs(ctx, t, fk.Column, deferred); err != nil {
+			return 0, err
+		}
+	}
+	return inserted, nil
+}
+
+func (r *restorer) insertBatch(ctx context.Context, t Table, columns map[string]bool, rows []map[string]interface{}) (int64, error) {
+	var cols []string
+	for _, c := range columnOrder(rows[0]) {
+		if columns[c] {
+			cols = append(cols, pgx.Identifier{c}.Sanitize())
+		}
+	}
+	if len(cols) == 0 {
+		return 0, nil
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return 0, fmt.Errorf("encoding rows: %w", err)
+	}
+
+	table := pgx.Identifier{t.Name}.Sanitize()
+	colList := strings.Join(cols, ", ")
+	query := fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM json_populate_recordset(NULL::%s, $1::json) ON CONFLICT DO NOTHING`,
+		table, colList, colList, table)
+	tag, err := r.tx.Exec(ctx, query, string(data))
+	if err != nil {
+		return 0, err
+	}
+
+	if t.NaturalKey != "" {
+		if err := r.mapNaturalKeys(ctx, t, rows); err != nil {
+			return 0, err
+		}
+	}
+	return tag.RowsAffected(), nil
+}
+
+// mapNaturalKeys records the database ID for each archived row of a table
+// with a natural key, which differs when the row already existed.
+func (r *restorer) mapNaturalKeys(ctx context.Context, t Table, rows []map[string]interface{}) error {
+	archived := make(map[string]interface{}, len(rows))
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		natural, ok := row[t.NaturalKey].(string)
+		if !ok {
+			continue
+		}
+		archived[natural] = row[t.key()]
+		keys = append(keys, natural)
+	}
+
+	query := fmt.Sprintf(`SELECT %s::text, %s::text FROM %s WHERE %s = ANY($1)`,
+		pgx.Identifier{t.NaturalKey}.Sanitize(), pgx.Identifier{t.key()}.Sanitize(),
+		pgx.Identifier{t.Name}.Sanitize(), pgx.Identifier{t.NaturalKey}.Sanitize())
+	dbRows, err := r.tx.Query(ctx, query, keys)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", t.NaturalKey, err)
+	}
+	defer dbRows.Close()
+
+	ids := r.idMaps[t.Name]
+	if ids == nil {
+		ids = map[string]interface{}{}
+		r.idMaps[t.Name] = ids
+	}
+	for dbRows.Next() {
+		var natural, id string
+		if err := dbRows.Scan(&natural, &id); err != nil {
+			return fmt.Errorf("resolving %s: %w", t.NaturalKey, err)
+		}
+		if old, ok := archived[natural]; ok {
+			ids[fmt.Sprint(old)] = id
+		}
+	}
+	return dbRows.Err()
+}
+
+func (r *restorer) remap(table string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if id, ok := r.idMaps[table][fmt.Sprint(value)]; ok {
+		return id
+	}
+	return value
+}
+
+func (r *restorer) updateSelfRefs(ctx context.Context, t Table, column string, rows []map[string]interface{}) error {
+	var updates []map[string]interface{}
+	for _, row := range rows {
+		if row[column] != nil {
+			updates = append(updates, row)
+		}
+	}
+
+	table := pgx.Identifier{t.Name}.Sanitize()
+	col := pgx.Identifier{column}.Sanitize()
+	key := pgx.Identifier{t.key()}.Sanitize()
+	query := fmt.Sprintf(`UPDATE %s t SET %s = x.%s FROM json_populate_recordset(NULL::%s, $1::json) x WHERE t.%s = x.%s`,
+		table, col, col, table, key, key)
+
+	for start := 0; start < len(updates); start += restoreBatchSize {
+		end := min(start+restoreBatchSize, len(updates))
+		data, err := json.Marshal(updates[start:end])
+		if err != nil {
+			return fmt.Errorf("encoding rows: %w", err)
+		}
+		if _, err := r.tx.Exec(ctx, query, string(data)); err != nil {
+			return fmt.Errorf("setting %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// insertableColumns returns the table's columns that accept explicit values.
+// Columns in the archive that no longer exist are dropped.
+func (r *restorer) insertableColumns(ctx context.Context, table string) (map[string]bool, error) {
+	rows, err := r.tx.Query(ctx, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1
+		  AND is_generated = 'NEVER'
+		  AND COALESCE(identity_generation, '') <> 'ALWAYS'`, table)
+	if err != nil {
+		return nil, fmt.Errorf("reading columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("reading columns: %w", err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+func columnOrder(row map[string]interface{}) []string {
+	cols := make([]string, 0, len(row))
+	for c := range row {
+		cols = append(cols, c)
+	}
+	return cols
+}
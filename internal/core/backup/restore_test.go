@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/store/postgres/postgrestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrip backs up src and restores the archive into dst.
+func roundTrip(t *testing.T, src, dst *pgxpool.Pool) *RestoreResult {
+	t.Helper()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	_, err := Export(ctx, src, &buf, "test")
+	require.NoError(t, err)
+
+	a, err := ReadArchive(&buf)
+	require.NoError(t, err)
+	defer a.Close()
+
+	result, err := Restore(ctx, dst, a, RestoreOptions{})
+	require.NoError(t, err)
+	return result
+}
+
+func TestRestoreScheduleWithConnection(t *testing.T) {
+	src, dst := postgrestest.New(t), postgrestest.New(t)
+	ctx := context.Background()
+
+	var connectionID string
+	require.NoError(t, src.QueryRow(ctx, `
+		INSERT INTO connections (name, provider, config)
+		VALUES ('warehouse', 'postgresql', '{"host":"db.internal"}')
+		RETURNING id::text`).Scan(&connectionID))
+	_, err := src.Exec(ctx, `
+		INSERT INTO ingestion_schedules (name, plugin_id, cron_expression, connection_id)
+		VALUES ('nightly', 'postgresql', '0 2 * * *', $1)`, connectionID)
+	require.NoError(t, err)
+
+	roundTrip(t, src, dst)
+
+	var restored string
+	require.NoError(t, dst.QueryRow(ctx, `
+		SELECT c.name FROM ingestion_schedules s
+		JOIN connections c ON c.id = s.connection_id
+		WHERE s.name = 'nightly'`).Scan(&restored))
+	assert.Equal(t, "warehouse", restored)
+}
+
+func TestRestoreScheduleWithServiceAccount(t *testing.T) {
+	src, dst := postgrestest.New(t), postgrestest.New(t)
+	ctx := context.Background()
+
+	var accountID string
+	require.NoError(t, src.QueryRow(ctx, `
+		INSERT INTO service_accounts (name) VALUES ('loader') RETURNING id::text`).Scan(&accountID))
+	_, err := src.Exec(ctx, `
+		INSERT INTO service_account_roles (service_account_id, role_id)
+		SELECT $1, id FROM roles WHERE name = 'admin'`, accountID)
+	require.NoError(t, err)
+	_, err = src.Exec(ctx, `
+		INSERT INTO service_account_api_keys (service_account_id, name, key_hash)
+		VALUES ($1, 'ci', 'hash')`, accountID)
+	require.NoError(t, err)
+	_, err = src.Exec(ctx, `
+		INSERT INTO ingestion_schedules (name, plugin_id, cron_expression, service_account_id)
+		VALUES ('nightly', 'postgresql', '0 2 * * *', $1)`, accountID)
+	require.NoError(t, err)
+
+	roundTrip(t, src, dst)
+
+	var account, role string
+	require.NoError(t, dst.QueryRow(ctx, `
+		SELECT sa.name, r.name FROM ingestion_schedules s
+		JOIN service_accounts sa ON sa.id = s.service_account_id
+		JOIN service_account_roles sar ON sar.service_account_id = sa.id
+		JOIN roles r ON r.id = sar.role_id
+		WHERE s.name = 'nightly'`).Scan(&account, &role))
+	assert.Equal(t, "loader", account)
+	assert.Equal(t, "admin", role)
+
+	var keys int
+	require.NoError(t, dst.QueryRow(ctx, `SELECT COUNT(*) FROM service_account_api_keys`).Scan(&keys))
+	assert.Zero(t, keys, "API keys are left out of backups")
+}
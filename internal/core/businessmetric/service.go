@@ -0,0 +1,397 @@
+// Package businessmetric turns assets of type Metric into a lightweight
+// metrics layer: a definition and formula, certification, and lineage from
+// the models a metric is computed from to the dashboards that use it.
+package businessmetric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+var (
+	ErrNotFound      = errors.New("metric not found")
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrNotMetric     = errors.New("asset is not a metric")
+	ErrAlreadyExists = errors.New("metric already exists")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	// AssetType is the asset type of metrics.
+	AssetType = "Metric"
+	// DefaultProvider is used for metrics created without a provider.
+	DefaultProvider        = "Marmot"
+	DefaultFormulaLanguage = "sql"
+
+	// lineageType is the type of edges to sources and consumers.
+	lineageType = "DIRECT"
+
+	maxNameLength       = 255
+	maxDefinitionLength = 10000
+	maxFormulaLength    = 100000
+	maxLanguageLength   = 50
+	maxUnitLength       = 50
+	maxNoteLength       = 2000
+	maxLinkedAssets     = 100
+)
+
+// AssetRef is an asset a metric is computed from or used by.
+type AssetRef struct {
+	ID        string   `json:"id"`
+	MRN       string   `json:"mrn"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+} // @name MetricAssetRef
+
+type Owner struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+} // @name MetricOwner
+
+// Metric is a Metric asset with its definition.
+type Metric struct {
+	ID          string   `json:"id"`
+	MRN         string   `json:"mrn"`
+	Name        string   `json:"name"`
+	Providers   []string `json:"providers"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags"`
+	Owners      []Owner  `json:"owners"`
+
+	Definition      string  `json:"definition"`
+	Formula         string  `json:"formula"`
+	FormulaLanguage string  `json:"formula_language"`
+	Unit            *string `json:"unit,omitempty"`
+
+	Certified         bool       `json:"certified"`
+	CertifiedBy       *string    `json:"certified_by,omitempty"`
+	CertifiedByName   string     `json:"certified_by_name,omitempty"`
+	CertifiedAt       *time.Time `json:"certified_at,omitempty"`
+	CertificationNote *string    `json:"certification_note,omitempty"`
+
+	// Sources are the direct upstream assets, e.g. the models the metric
+	// is computed from. Consumers are the direct downstream assets, e.g.
+	// dashboards that show it.
+	Sources   []AssetRef `json:"sources"`
+	Consumers []AssetRef `json:"consumers"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+} // @name BusinessMetric
+
+// DefinitionInput sets what a metric means and how it is computed.
+type DefinitionInput struct {
+	Definition      string  `json:"definition"`
+	Formula         string  `json:"formula"`
+	FormulaLanguage string  `json:"formula_language"`
+	Unit            *string `json:"unit,omitempty"`
+} // @name MetricDefinitionInput
+
+type OwnerInput struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+} // @name MetricOwnerInput
+
+// CreateInput creates a Metric asset with its definition, owners and lineage.
+type CreateInput struct {
+	DefinitionInput
+
+	Name string `json:"name"`
+	// Provider is the tool the metric is defined in, e.g. dbt or Looker.
+	Provider    string       `json:"provider"`
+	Description *string      `json:"description,omitempty"`
+	Tags        []string     `json:"tags"`
+	Owners      []OwnerInput `json:"owners"`
+	SourceIDs   []string     `json:"source_ids"`
+	ConsumerIDs []string     `json:"consumer_ids"`
+} // @name CreateMetricInput
+
+type CertifyInput struct {
+	Certified bool    `json:"certified"`
+	Note      *string `json:"note,omitempty"`
+} // @name MetricCertificationInput
+
+type ListFilter struct {
+	// Certified restricts the list to certified or uncertified metrics.
+	Certified *bool
+	Limit     int
+	Offset    int
+}
+
+type ListResult struct {
+	Metrics []*Metric `json:"metrics"`
+	Total   int       `json:"total"`
+} // @name BusinessMetricListResult
+
+// AssetService is the part of asset.Service metrics need.
+type AssetService interface {
+	Create(ctx context.Context, input asset.CreateInput) (*asset.Asset, error)
+	Get(ctx context.Context, id string) (*asset.Asset, error)
+}
+
+type LineageWriter interface {
+	CreateDirectLineage(ctx context.Context, sourceMRN, targetMRN, lineageType string) (string, error)
+}
+
+type OwnerWriter interface {
+	AddAssetOwner(ctx context.Context, assetID, ownerType, ownerID string) error
+}
+
+// SearchObserver is notified when a metric's searchable fields change.
+type SearchObserver interface {
+	OnEntityChanged(ctx context.Context, entityType, entityID string)
+}
+
+type Service struct {
+	repo           Repository
+	assets         AssetService
+	lineage        LineageWriter
+	owners         OwnerWriter
+	searchObserver SearchObserver
+}
+
+func NewService(repo Repository, assets AssetService, lineage LineageWriter, owners OwnerWriter) *Service {
+	return &Service{repo: repo, assets: assets, lineage: lineage, owners: owners}
+}
+
+// SetSearchObserver registers an observer for search index sync.
+func (s *Service) SetSearchObserver(observer SearchObserver) {
+	s.searchObserver = observer
+}
+
+// Create creates a Metric asset, then its definition, owners and lineage
+// edges from each source and to each consumer.
+func (s *Service) Create(ctx context.Context, input CreateInput, userID string) (*Metric, error) {
+	name := strings.TrimSpace(input.Name)
+	switch {
+	case name == "":
+		return nil, &ValidationError{Message: "name is required"}
+	case len(name) > maxNameLength:
+		return nil, &ValidationError{Message: fmt.Sprintf("name must be %d characters or fewer", maxNameLength)}
+	}
+	provider := strings.TrimSpace(input.Provider)
+	if provider == "" {
+		provider = DefaultProvider
+	}
+
+	def, err := normalizeDefinition(input.DefinitionInput)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range input.Owners {
+		if o.Type != "user" && o.Type != "team" {
+			return nil, &ValidationError{Message: "owner type must be user or team"}
+		}
+	}
+
+	// Resolve every linked asset before creating anything, so a bad ID
+	// doesn't leave a half-built metric behind.
+	sources, err := s.resolveAssets(ctx, input.SourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := s.resolveAssets(ctx, input.ConsumerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	metricMRN := mrn.New(AssetType, provider, name)
+	created, err := s.assets.Create(ctx, asset.CreateInput{
+		Name:        &name,
+		MRN:         &metricMRN,
+		Type:        AssetType,
+		Providers:   []string{provider},
+		Description: input.Description,
+		Tags:        input.Tags,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		if errors.Is(err, asset.ErrAlreadyExists) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("creating metric asset: %w", err)
+	}
+
+	if err := s.repo.UpsertDefinition(ctx, created.ID, def, userID); err != nil {
+		return nil, err
+	}
+	for _, o := range input.Owners {
+		if err := s.owners.AddAssetOwner(ctx, created.ID, o.Type, o.ID); err != nil {
+			return nil, fmt.Errorf("adding metric owner: %w", err)
+		}
+	}
+	for _, src := range sources {
+		if _, err := s.lineage.CreateDirectLineage(ctx, src, metricMRN, lineageType); err != nil {
+			return nil, fmt.Errorf("linking metric source: %w", err)
+		}
+	}
+	for _, consumer := range consumers {
+		if _, err := s.lineage.CreateDirectLineage(ctx, metricMRN, consumer, lineageType); err != nil {
+			return nil, fmt.Errorf("linking metric consumer: %w", err)
+		}
+	}
+
+	s.changed(ctx, created.ID)
+	return s.repo.Get(ctx, created.ID)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Metric, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns metrics, certified ones first and then by name.
+func (s *Service) List(ctx context.Context, filter ListFilter) (*ListResult, error) {
+	metrics, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Metrics: metrics, Total: total}, nil
+}
+
+// Define sets the definition of a Metric asset. Changing the definition,
+// formula or unit of a certified metric revokes its certification.
+func (s *Service) Define(ctx context.Context, id string, input DefinitionInput, userID string) (*Metric, error) {
+	if err := s.requireMetric(ctx, id); err != nil {
+		return nil, err
+	}
+	def, err := normalizeDefinition(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpsertDefinition(ctx, id, def, userID); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, id)
+
+	return s.repo.Get(ctx, id)
+}
+
+// Certify marks a metric as the approved version to use, or revokes that.
+func (s *Service) Certify(ctx context.Context, id string, input CertifyInput, userID string) (*Metric, error) {
+	m, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var note *string
+	if input.Note != nil {
+		trimmed := strings.TrimSpace(*input.Note)
+		if len(trimmed) > maxNoteLength {
+			return nil, &ValidationError{Message: fmt.Sprintf("note must be %d characters or fewer", maxNoteLength)}
+		}
+		if trimmed != "" {
+			note = &trimmed
+		}
+	}
+	if input.Certified && (m.Definition == "" || m.Formula == "") {
+		return nil, &ValidationError{Message: "a metric needs a definition and formula before it can be certified"}
+	}
+
+	if err := s.repo.SetCertification(ctx, id, input.Certified, note, userID); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, id)
+
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) requireMetric(ctx context.Context, id string) error {
+	a, err := s.assets.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			return ErrAssetNotFound
+		}
+		return fmt.Errorf("getting asset: %w", err)
+	}
+	if !strings.EqualFold(a.Type, AssetType) {
+		return ErrNotMetric
+	}
+	return nil
+}
+
+// resolveAssets returns the MRNs of the assets with the given IDs.
+func (s *Service) resolveAssets(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) > maxLinkedAssets {
+		return nil, &ValidationError{Message: fmt.Sprintf("a metric can be linked to at most %d sources and %d consumers", maxLinkedAssets, maxLinkedAssets)}
+	}
+
+	seen := make(map[string]bool, len(ids))
+	mrns := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		a, err := s.assets.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, asset.ErrAssetNotFound) {
+				return nil, &ValidationError{Message: fmt.Sprintf("asset %s not found", id)}
+			}
+			return nil, fmt.Errorf("getting asset: %w", err)
+		}
+		if a.MRN == nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("asset %s has no MRN", id)}
+		}
+		mrns = append(mrns, *a.MRN)
+	}
+	return mrns, nil
+}
+
+func (s *Service) changed(ctx context.Context, id string) {
+	if s.searchObserver != nil {
+		s.searchObserver.OnEntityChanged(ctx, "asset", id)
+	}
+}
+
+func normalizeDefinition(input DefinitionInput) (DefinitionInput, error) {
+	def := DefinitionInput{
+		Definition:      strings.TrimSpace(input.Definition),
+		Formula:         strings.TrimSpace(input.Formula),
+		FormulaLanguage: strings.ToLower(strings.TrimSpace(input.FormulaLanguage)),
+	}
+
+	switch {
+	case len(def.Definition) > maxDefinitionLength:
+		return def, &ValidationError{Message: fmt.Sprintf("definition must be %d characters or fewer", maxDefinitionLength)}
+	case len(def.Formula) > maxFormulaLength:
+		return def, &ValidationError{Message: fmt.Sprintf("formula must be %d characters or fewer", maxFormulaLength)}
+	case len(def.FormulaLanguage) > maxLanguageLength:
+		return def, &ValidationError{Message: fmt.Sprintf("formula language must be %d characters or fewer", maxLanguageLength)}
+	}
+	if def.FormulaLanguage == "" {
+		def.FormulaLanguage = DefaultFormulaLanguage
+	}
+
+	if input.Unit != nil {
+		unit := strings.TrimSpace(*input.Unit)
+		if len(unit) > maxUnitLength {
+			return def, &ValidationError{Message: fmt.Sprintf("unit must be %d characters or fewer", maxUnitLength)}
+		}
+		if unit != "" {
+			def.Unit = &unit
+		}
+	}
+	return def, nil
+}
@@ -0,0 +1,230 @@
+package businessmetric
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	metrics map[string]*Metric
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{metrics: make(map[string]*Metric)}
+}
+
+func (f *fakeRepo) UpsertDefinition(_ context.Context, assetID string, def DefinitionInput, _ string) error {
+	m, ok := f.metrics[assetID]
+	if !ok {
+		m = &Metric{ID: assetID}
+		f.metrics[assetID] = m
+	}
+	changed := m.Definition != def.Definition || m.Formula != def.Formula ||
+		m.FormulaLanguage != def.FormulaLanguage || unitOf(m.Unit) != unitOf(def.Unit)
+	m.Definition, m.Formula, m.FormulaLanguage, m.Unit = def.Definition, def.Formula, def.FormulaLanguage, def.Unit
+	if changed {
+		m.Certified = false
+	}
+	return nil
+}
+
+func unitOf(unit *string) string {
+	if unit == nil {
+		return ""
+	}
+	return *unit
+}
+
+func (f *fakeRepo) SetCertification(_ context.Context, assetID string, certified bool, note *string, userID string) error {
+	m, ok := f.metrics[assetID]
+	if !ok {
+		return ErrNotFound
+	}
+	m.Certified = certified
+	m.CertificationNote = note
+	m.CertifiedBy = &userID
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, assetID string) (*Metric, error) {
+	m, ok := f.metrics[assetID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *m
+	return &out, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, _ ListFilter) ([]*Metric, int, error) {
+	var out []*Metric
+	for _, m := range f.metrics {
+		out = append(out, m)
+	}
+	return out, len(out), nil
+}
+
+type fakeAssets struct {
+	assets map[string]*asset.Asset
+}
+
+func (f *fakeAssets) Create(_ context.Context, input asset.CreateInput) (*asset.Asset, error) {
+	for _, a := range f.assets {
+		if *a.MRN == *input.MRN {
+			return nil, asset.ErrAlreadyExists
+		}
+	}
+	a := &asset.Asset{ID: fmt.Sprintf("asset-%d", len(f.assets)+1), MRN: input.MRN, Name: input.Name, Type: input.Type}
+	f.assets[a.ID] = a
+	return a, nil
+}
+
+func (f *fakeAssets) Get(_ context.Context, id string) (*asset.Asset, error) {
+	a, ok := f.assets[id]
+	if !ok {
+		return nil, asset.ErrAssetNotFound
+	}
+	return a, nil
+}
+
+type fakeLineage struct {
+	edges []string
+}
+
+func (f *fakeLineage) CreateDirectLineage(_ context.Context, sourceMRN, targetMRN, _ string) (string, error) {
+	f.edges = append(f.edges, sourceMRN+" -> "+targetMRN)
+	return fmt.Sprintf("edge-%d", len(f.edges)), nil
+}
+
+type fakeOwners struct {
+	owners []string
+}
+
+func (f *fakeOwners) AddAssetOwner(_ context.Context, assetID, ownerType, ownerID string) error {
+	f.owners = append(f.owners, assetID+":"+ownerType+":"+ownerID)
+	return nil
+}
+
+type fakeObserver struct {
+	changed []string
+}
+
+func (o *fakeObserver) OnEntityChanged(_ context.Context, entityType, entityID string) {
+	o.changed = append(o.changed, entityType+":"+entityID)
+}
+
+func strPtr(s string) *string { return &s }
+
+func newTestService() (*Service, *fakeAssets, *fakeLineage, *fakeOwners) {
+	assets := &fakeAssets{assets: map[string]*asset.Asset{
+		"orders":    {ID: "orders", MRN: strPtr("mrn://table/postgres/orders"), Type: "Table"},
+		"dashboard": {ID: "dashboard", MRN: strPtr("mrn://dashboard/looker/revenue"), Type: "Dashboard"},
+	}}
+	lineage := &fakeLineage{}
+	owners := &fakeOwners{}
+	return NewService(newFakeRepo(), assets, lineage, owners), assets, lineage, owners
+}
+
+func TestCreateLinksSourcesConsumersAndOwners(t *testing.T) {
+	svc, assets, lineage, owners := newTestService()
+	observer := &fakeObserver{}
+	svc.SetSearchObserver(observer)
+
+	m, err := svc.Create(context.Background(), CreateInput{
+		DefinitionInput: DefinitionInput{
+			Definition: "Total order value",
+			Formula:    "SELECT sum(total) FROM orders",
+			Unit:       strPtr(" USD "),
+		},
+		Name:        " Revenue ",
+		Owners:      []OwnerInput{{Type: "team", ID: "finance"}},
+		SourceIDs:   []string{"orders", "orders"},
+		ConsumerIDs: []string{"dashboard"},
+	}, "user-1")
+	require.NoError(t, err)
+
+	created := assets.assets[m.ID]
+	assert.Equal(t, "mrn://metric/marmot/revenue", *created.MRN)
+	assert.Equal(t, AssetType, created.Type)
+	assert.Equal(t, DefaultFormulaLanguage, m.FormulaLanguage)
+	assert.Equal(t, "USD", *m.Unit)
+	assert.Equal(t, []string{
+		"mrn://table/postgres/orders -> mrn://metric/marmot/revenue",
+		"mrn://metric/marmot/revenue -> mrn://dashboard/looker/revenue",
+	}, lineage.edges)
+	assert.Equal(t, []string{m.ID + ":team:finance"}, owners.owners)
+	assert.Equal(t, []string{"asset:" + m.ID}, observer.changed)
+}
+
+func TestCreateValidation(t *testing.T) {
+	svc, assets, lineage, _ := newTestService()
+	ctx := context.Background()
+
+	cases := []CreateInput{
+		{},
+		{Name: strings.Repeat("n", maxNameLength+1)},
+		{Name: "unit", DefinitionInput: DefinitionInput{Unit: strPtr(strings.Repeat("u", maxUnitLength+1))}},
+		{Name: "owner", Owners: []OwnerInput{{Type: "group", ID: "g"}}},
+		{Name: "missing source", SourceIDs: []string{"missing"}},
+	}
+	for _, input := range cases {
+		_, err := svc.Create(ctx, input, "user-1")
+		assert.True(t, IsValidationError(err), "input %+v", input)
+	}
+
+	assert.Len(t, assets.assets, 2, "no asset is created for invalid input")
+	assert.Empty(t, lineage.edges)
+
+	_, err := svc.Create(ctx, CreateInput{Name: "Revenue"}, "user-1")
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, CreateInput{Name: "revenue"}, "user-1")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestDefineRequiresMetricAsset(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	ctx := context.Background()
+
+	_, err := svc.Define(ctx, "orders", DefinitionInput{Definition: "x"}, "user-1")
+	assert.ErrorIs(t, err, ErrNotMetric)
+
+	_, err = svc.Define(ctx, "missing", DefinitionInput{Definition: "x"}, "user-1")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+}
+
+func TestCertification(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	ctx := context.Background()
+
+	m, err := svc.Create(ctx, CreateInput{Name: "Active users"}, "user-1")
+	require.NoError(t, err)
+
+	_, err = svc.Certify(ctx, m.ID, CertifyInput{Certified: true}, "user-1")
+	assert.True(t, IsValidationError(err), "certifying without a formula should fail")
+
+	def := DefinitionInput{Definition: "Users active in the last 30 days", Formula: "count(distinct user_id)"}
+	_, err = svc.Define(ctx, m.ID, def, "user-1")
+	require.NoError(t, err)
+
+	m, err = svc.Certify(ctx, m.ID, CertifyInput{Certified: true, Note: strPtr(" Reviewed ")}, "steward")
+	require.NoError(t, err)
+	assert.True(t, m.Certified)
+	assert.Equal(t, "Reviewed", *m.CertificationNote)
+
+	m, err = svc.Define(ctx, m.ID, def, "user-1")
+	require.NoError(t, err)
+	assert.True(t, m.Certified, "saving an unchanged definition keeps certification")
+
+	def.Formula = "count(user_id)"
+	m, err = svc.Define(ctx, m.ID, def, "user-1")
+	require.NoError(t, err)
+	assert.False(t, m.Certified, "changing the formula revokes certification")
+
+	_, err = svc.Certify(ctx, "missing", CertifyInput{}, "user-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,252 @@
+package businessmetric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the metric data access interface.
+type Repository interface {
+	// UpsertDefinition creates or replaces a metric's definition. A change
+	// to the definition, formula or unit clears its certification.
+	UpsertDefinition(ctx context.Context, assetID string, def DefinitionInput, userID string) error
+	SetCertification(ctx context.Context, assetID string, certified bool, note *string, userID string) error
+	Get(ctx context.Context, assetID string) (*Metric, error)
+	List(ctx context.Context, filter ListFilter) ([]*Metric, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectMetric = `
+	SELECT a.id, a.mrn, a.name, a.providers, COALESCE(a.user_description, a.description), COALESCE(a.tags, '{}'),
+	       m.definition, m.formula, m.formula_language, m.unit,
+	       m.certified, m.certified_by::text, COALESCE(cu.name, cu.username, ''), m.certified_at, m.certification_note,
+	       m.updated_at
+	FROM metric_definitions m
+	JOIN assets a ON a.id = m.asset_id
+	LEFT JOIN users cu ON cu.id = m.certified_by`
+
+// definitionChanged compares the existing row with the incoming one in an
+// ON CONFLICT clause. SET expressions see the row's old values, so it can be
+// used in every assignment.
+const definitionChanged = `(
+	metric_definitions.definition <> EXCLUDED.definition OR
+	metric_definitions.formula <> EXCLUDED.formula OR
+	metric_definitions.formula_language <> EXCLUDED.formula_language OR
+	metric_definitions.unit IS DISTINCT FROM EXCLUDED.unit)`
+
+func (r *PostgresRepository) UpsertDefinition(ctx context.Context, assetID string, def DefinitionInput, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO metric_definitions (asset_id, definition, formula, formula_language, unit, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid)
+		ON CONFLICT (asset_id) DO UPDATE SET
+			definition = EXCLUDED.definition,
+			formula = EXCLUDED.formula,
+			formula_language = EXCLUDED.formula_language,
+			unit = EXCLUDED.unit,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW(),
+			certified = metric_definitions.certified AND NOT `+definitionChanged+`,
+			certified_by = CASE WHEN `+definitionChanged+` THEN NULL ELSE metric_definitions.certified_by END,
+			certified_at = CASE WHEN `+definitionChanged+` THEN NULL ELSE metric_definitions.certified_at END,
+			certification_note = CASE WHEN `+definitionChanged+` THEN NULL ELSE metric_definitions.certification_note END`,
+		assetID, def.Definition, def.Formula, def.FormulaLanguage, def.Unit, userID)
+	if err != nil {
+		return mapWriteError(err, "saving metric definition")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetCertification(ctx context.Context, assetID string, certified bool, note *string, userID string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE metric_definitions SET
+			certified = $2,
+			certified_by = CASE WHEN $2 THEN NULLIF($4, '')::uuid END,
+			certified_at = CASE WHEN $2 THEN NOW() END,
+			certification_note = $3,
+			updated_at = NOW()
+		WHERE asset_id = $1`, assetID, certified, note, userID)
+	if err != nil {
+		return mapWriteError(err, "certifying metric")
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, assetID string) (*Metric, error) {
+	m, err := scanMetric(r.db.QueryRow(ctx, selectMetric+` WHERE m.asset_id = $1`, assetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting metric: %w", err)
+	}
+
+	if err := r.loadRelations(ctx, []*Metric{m}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter ListFilter) ([]*Metric, int, error) {
+	where := ` WHERE ($1::boolean IS NULL OR m.certified = $1)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM metric_definitions m`+where,
+		filter.Certified).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting metrics: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectMetric+where+`
+		ORDER BY m.certified DESC, a.name, a.id
+		LIMIT $2 OFFSET $3`, filter.Certified, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing metrics: %w", err)
+	}
+	defer rows.Close()
+
+	metrics := []*Metric{}
+	for rows.Next() {
+		m, err := scanMetric(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning metric: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating metrics: %w", err)
+	}
+
+	if err := r.loadRelations(ctx, metrics); err != nil {
+		return nil, 0, err
+	}
+	return metrics, total, nil
+}
+
+// loadRelations fills in owners, sources and consumers with one query each.
+func (r *PostgresRepository) loadRelations(ctx context.Context, metrics []*Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Metric, len(metrics))
+	byMRN := make(map[string]*Metric, len(metrics))
+	ids := make([]string, len(metrics))
+	mrns := make([]string, len(metrics))
+	for i, m := range metrics {
+		m.Owners = []Owner{}
+		m.Sources = []AssetRef{}
+		m.Consumers = []AssetRef{}
+		byID[m.ID] = m
+		byMRN[m.MRN] = m
+		ids[i] = m.ID
+		mrns[i] = m.MRN
+	}
+
+	ownerRows, err := r.db.Query(ctx, `
+		SELECT ao.asset_id,
+		       CASE WHEN ao.user_id IS NOT NULL THEN 'user' ELSE 'team' END,
+		       COALESCE(ao.user_id, ao.team_id)::text,
+		       COALESCE(u.name, u.username, t.name, '')
+		FROM asset_owners ao
+		LEFT JOIN users u ON u.id = ao.user_id
+		LEFT JOIN teams t ON t.id = ao.team_id
+		WHERE ao.asset_id = ANY($1)
+		ORDER BY 2, 4`, ids)
+	if err != nil {
+		return fmt.Errorf("loading metric owners: %w", err)
+	}
+	defer ownerRows.Close()
+
+	for ownerRows.Next() {
+		var assetID string
+		var o Owner
+		if err := ownerRows.Scan(&assetID, &o.Type, &o.ID, &o.Name); err != nil {
+			return fmt.Errorf("scanning metric owner: %w", err)
+		}
+		if m := byID[assetID]; m != nil {
+			m.Owners = append(m.Owners, o)
+		}
+	}
+	if err := ownerRows.Err(); err != nil {
+		return fmt.Errorf("iterating metric owners: %w", err)
+	}
+
+	linkRows, err := r.db.Query(ctx, `
+		SELECT DISTINCT e.target_mrn, 'source', a.id, a.mrn, a.name, a.type, a.providers
+		FROM lineage_edges e
+		JOIN assets a ON a.mrn = e.source_mrn
+		WHERE e.target_mrn = ANY($1)
+		UNION
+		SELECT DISTINCT e.source_mrn, 'consumer', a.id, a.mrn, a.name, a.type, a.providers
+		FROM lineage_edges e
+		JOIN assets a ON a.mrn = e.target_mrn
+		WHERE e.source_mrn = ANY($1)
+		ORDER BY 1, 2, 5`, mrns)
+	if err != nil {
+		return fmt.Errorf("loading metric lineage: %w", err)
+	}
+	defer linkRows.Close()
+
+	for linkRows.Next() {
+		var metricMRN, direction string
+		var ref AssetRef
+		if err := linkRows.Scan(&metricMRN, &direction, &ref.ID, &ref.MRN, &ref.Name, &ref.Type, &ref.Providers); err != nil {
+			return fmt.Errorf("scanning metric lineage: %w", err)
+		}
+		m := byMRN[metricMRN]
+		if m == nil {
+			continue
+		}
+		if direction == "source" {
+			m.Sources = append(m.Sources, ref)
+		} else {
+			m.Consumers = append(m.Consumers, ref)
+		}
+	}
+	return linkRows.Err()
+}
+
+func mapWriteError(err error, action string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			if pgErr.ConstraintName == "metric_definitions_asset_id_fkey" {
+				return ErrAssetNotFound
+			}
+		case "22P02":
+			return &ValidationError{Message: "invalid id"}
+		}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+func scanMetric(row pgx.Row) (*Metric, error) {
+	var m Metric
+	if err := row.Scan(
+		&m.ID, &m.MRN, &m.Name, &m.Providers, &m.Description, &m.Tags,
+		&m.Definition, &m.Formula, &m.FormulaLanguage, &m.Unit,
+		&m.Certified, &m.CertifiedBy, &m.CertifiedByName, &m.CertifiedAt, &m.CertificationNote,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if m.Providers == nil {
+		m.Providers = []string{}
+	}
+	return &m, nil
+}
@@ -0,0 +1,170 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultCheckTimeout = 5 * time.Second
+
+// ErrNoEndpoint is returned by DialChecker when a connection's config has no
+// host to check. The connection's status stays unknown.
+var ErrNoEndpoint = errors.New("no host or endpoint to check in config")
+
+// defaultPorts are used when a config names a host without a port.
+var defaultPorts = map[string]int{
+	"postgresql": 5432,
+	"mysql":      3306,
+	"sqlserver":  1433,
+	"oracle":     1521,
+	"clickhouse": 9000,
+	"mongodb":    27017,
+	"kafka":      9092,
+	"redpanda":   9092,
+	"redis":      6379,
+	"trino":      8080,
+	"airflow":    8080,
+	"nats":       4222,
+}
+
+// DialChecker checks that a connection's endpoint accepts TCP connections.
+// It does not log in, so it confirms the network path rather than the
+// credentials; credential problems surface on the next scheduled run.
+type DialChecker struct {
+	Timeout time.Duration
+}
+
+func NewDialChecker(timeout time.Duration) *DialChecker {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &DialChecker{Timeout: timeout}
+}
+
+// Check dials the connection's endpoints in order and succeeds on the first
+// that answers.
+func (c *DialChecker) Check(ctx context.Context, provider string, config map[string]interface{}) (time.Duration, error) {
+	addrs := Endpoints(provider, config)
+	if len(addrs) == 0 {
+		return 0, ErrNoEndpoint
+	}
+
+	dialer := net.Dialer{Timeout: c.Timeout}
+	var lastErr error
+	for _, addr := range addrs {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn.Close()
+		return time.Since(start), nil
+	}
+	return 0, fmt.Errorf("unreachable: %w", lastErr)
+}
+
+// Endpoints extracts host:port addresses from the config keys plugins use
+// for endpoints: host and port, URL-like fields, and broker lists.
+func Endpoints(provider string, config map[string]interface{}) []string {
+	port := defaultPorts[strings.ToLower(provider)]
+	if p, ok := intValue(config["port"]); ok {
+		port = p
+	}
+
+	var addrs []string
+	if host, ok := config["host"].(string); ok && host != "" {
+		if addr := withPort(host, port); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	for _, key := range []string{"endpoint", "url", "uri", "address", "base_url", "api_url"} {
+		raw, ok := config[key].(string)
+		if !ok || raw == "" {
+			continue
+		}
+		if addr := urlAddress(raw); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	for _, key := range []string{"brokers", "bootstrap_servers", "hosts"} {
+		for _, broker := range stringList(config[key]) {
+			if addr := withPort(broker, port); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	return addrs
+}
+
+func urlAddress(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		return net.JoinHostPort(u.Hostname(), "80")
+	case "https", "wss":
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return ""
+}
+
+// withPort returns host with port appended, or "" when host has no port and
+// the provider's default port is unknown.
+func withPort(host string, port int) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	if port == 0 {
+		return ""
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func stringList(v interface{}) []string {
+	var out []string
+	switch list := v.(type) {
+	case string:
+		for _, s := range strings.Split(list, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	case []interface{}:
+		for _, item := range list {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = list
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package connection
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		config   map[string]interface{}
+		want     []string
+	}{
+		{"host with default port", "postgresql", map[string]interface{}{"host": "db.internal"}, []string{"db.internal:5432"}},
+		{"explicit port", "postgresql", map[string]interface{}{"host": "db", "port": float64(6543)}, []string{"db:6543"}},
+		{"unknown provider port", "custom", map[string]interface{}{"host": "db"}, nil},
+		{"https endpoint", "custom", map[string]interface{}{"endpoint": "https://api.example.com/v1"}, []string{"api.example.com:443"}},
+		{"broker list", "kafka", map[string]interface{}{"bootstrap_servers": "k1:9093, k2"}, []string{"k1:9093", "k2:9092"}},
+		{"nothing to check", "s3", map[string]interface{}{"region": "eu-west-1"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Endpoints(tt.provider, tt.config))
+		})
+	}
+}
+
+func TestDialChecker(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	checker := NewDialChecker(time.Second)
+	ctx := context.Background()
+
+	_, err = checker.Check(ctx, "custom", map[string]interface{}{"host": ln.Addr().String()})
+	assert.NoError(t, err)
+
+	_, err = checker.Check(ctx, "s3", map[string]interface{}{})
+	assert.ErrorIs(t, err, ErrNoEndpoint)
+
+	addr := ln.Addr().String()
+	ln.Close()
+	_, err = checker.Check(ctx, "custom", map[string]interface{}{"host": addr})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package connection
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultMonitorInterval = 5 * time.Minute
+
+// Monitor periodically checks every connection's health.
+type Monitor struct {
+	task *background.SingletonTask
+}
+
+// MonitorConfig configures the monitor.
+type MonitorConfig struct {
+	// Interval between checks. Default: 5 minutes.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewMonitor creates a new health monitor for svc.
+func NewMonitor(svc *Service, config *MonitorConfig) *Monitor {
+	if config == nil {
+		config = &MonitorConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultMonitorInterval
+	}
+
+	return &Monitor{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "connection-health",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.CheckAll(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins the periodic health checks.
+func (m *Monitor) Start(ctx context.Context) {
+	m.task.Start(ctx)
+}
+
+// Stop stops the health checks.
+func (m *Monitor) Stop() {
+	m.task.Stop()
+}
@@ -0,0 +1,407 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// MaskedValue replaces sensitive config values in responses. Sending it back
+// on update keeps the stored value.
+const MaskedValue = "********"
+
+var (
+	ErrNotFound   = errors.New("connection not found")
+	ErrNameExists = errors.New("connection name already exists")
+	// ErrInUse is returned when deleting a connection schedules still use.
+	ErrInUse = errors.New("connection is used by one or more schedules")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+type Status string // @name ConnectionStatus
+
+const (
+	StatusUnknown   Status = "unknown"
+	StatusHealthy   Status = "healthy"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// Connection is a provider endpoint and its credentials, shared by every
+// schedule that references it.
+type Connection struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Provider    string                 `json:"provider"`
+	Description string                 `json:"description,omitempty"`
+	Config      map[string]interface{} `json:"config"`
+	Status      Status                 `json:"status"`
+	// LastError explains the last failed health check.
+	LastError            string     `json:"last_error,omitempty"`
+	LatencyMS            *int       `json:"latency_ms,omitempty"`
+	LastCheckedAt        *time.Time `json:"last_checked_at,omitempty"`
+	CredentialsRotatedAt *time.Time `json:"credentials_rotated_at,omitempty"`
+	ScheduleCount        int        `json:"schedule_count"`
+	CreatedBy            *string    `json:"created_by,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+} // @name Connection
+
+type CreateInput struct {
+	Name        string                 `json:"name"`
+	Provider    string                 `json:"provider"`
+	Description string                 `json:"description,omitempty"`
+	Config      map[string]interface{} `json:"config"`
+} // @name CreateConnectionInput
+
+// UpdateInput replaces a connection's settings. The provider cannot change.
+// Sensitive fields that are omitted or sent as MaskedValue keep their stored
+// value.
+type UpdateInput struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Config      map[string]interface{} `json:"config"`
+} // @name UpdateConnectionInput
+
+// Health is the outcome of one health check.
+type Health struct {
+	Status    Status
+	Error     string
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+// Providers describes the config of each provider, as declared by its plugin.
+type Providers interface {
+	// SensitiveFields returns the provider's credential fields. It fails for
+	// unknown providers.
+	SensitiveFields(provider string) (map[string]bool, error)
+}
+
+// Checker tests whether a connection's endpoint is reachable with its
+// decrypted config.
+type Checker interface {
+	Check(ctx context.Context, provider string, config map[string]interface{}) (time.Duration, error)
+}
+
+type Service struct {
+	repo      Repository
+	providers Providers
+	encryptor *crypto.Encryptor
+	checker   Checker
+	now       func() time.Time
+}
+
+// NewService creates a connection service. A nil encryptor stores
+// credentials unencrypted, matching schedules.
+func NewService(repo Repository, providers Providers, encryptor *crypto.Encryptor, checker Checker) *Service {
+	return &Service{repo: repo, providers: providers, encryptor: encryptor, checker: checker, now: time.Now}
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput, createdBy *string) (*Connection, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validateName(input.Name); err != nil {
+		return nil, err
+	}
+	sensitive, err := s.providers.SensitiveFields(input.Provider)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("unknown provider %q", input.Provider)}
+	}
+
+	config := copyConfig(input.Config)
+	if err := s.encrypt(config, sensitive); err != nil {
+		return nil, err
+	}
+
+	conn := &Connection{
+		Name:        input.Name,
+		Provider:    input.Provider,
+		Description: strings.TrimSpace(input.Description),
+		Config:      config,
+		Status:      StatusUnknown,
+		CreatedBy:   createdBy,
+	}
+	if err := s.repo.Create(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return s.masked(conn, sensitive), nil
+}
+
+// Get returns a connection with its credentials masked.
+func (s *Service) Get(ctx context.Context, id string) (*Connection, error) {
+	conn, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := s.providers.SensitiveFields(conn.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("getting provider fields: %w", err)
+	}
+	return s.masked(conn, sensitive), nil
+}
+
+// List returns all connections, optionally for one provider, with their
+// credentials masked.
+func (s *Service) List(ctx context.Context, provider string) ([]*Connection, error) {
+	conns, err := s.repo.List(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	for i, conn := range conns {
+		sensitive, err := s.providers.SensitiveFields(conn.Provider)
+		if err != nil {
+			// The provider's plugin is not loaded; hide all config rather
+			// than risk returning credentials.
+			conn.Config = map[string]interface{}{}
+			continue
+		}
+		conns[i] = s.masked(conn, sensitive)
+	}
+	return conns, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Connection, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validateName(input.Name); err != nil {
+		return nil, err
+	}
+
+	conn, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := s.providers.SensitiveFields(conn.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("getting provider fields: %w", err)
+	}
+
+	config := copyConfig(input.Config)
+	for key := range sensitive {
+		if v, ok := config[key]; !ok || v == MaskedValue {
+			if stored, ok := conn.Config[key]; ok {
+				config[key] = stored
+			}
+		}
+	}
+	changed := changedFields(sensitive, config, conn.Config)
+	if err := s.encrypt(config, changed); err != nil {
+		return nil, err
+	}
+
+	rotated := len(changed) > 0
+	conn.Name = input.Name
+	conn.Description = strings.TrimSpace(input.Description)
+	conn.Config = config
+	if rotated {
+		now := s.now()
+		conn.CredentialsRotatedAt = &now
+	}
+
+	if err := s.repo.Update(ctx, conn); err != nil {
+		return nil, err
+	}
+	if rotated {
+		return s.Check(ctx, id)
+	}
+	return s.masked(conn, sensitive), nil
+}
+
+// RotateCredentials replaces only the connection's sensitive fields, then
+// checks the connection so a bad credential shows up straight away. Every
+// schedule using the connection picks up the new credentials on its next run.
+func (s *Service) RotateCredentials(ctx context.Context, id string, credentials map[string]string) (*Connection, error) {
+	if len(credentials) == 0 {
+		return nil, &ValidationError{Message: "at least one credential is required"}
+	}
+
+	conn, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := s.providers.SensitiveFields(conn.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("getting provider fields: %w", err)
+	}
+
+	updates := make(map[string]interface{}, len(credentials))
+	for key, value := range credentials {
+		if !sensitive[key] {
+			return nil, &ValidationError{Message: fmt.Sprintf("%s is not a credential field of %s", key, conn.Provider)}
+		}
+		updates[key] = value
+	}
+	if err := s.encrypt(updates, sensitive); err != nil {
+		return nil, err
+	}
+	for key, value := range updates {
+		conn.Config[key] = value
+	}
+
+	now := s.now()
+	conn.CredentialsRotatedAt = &now
+	if err := s.repo.Update(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return s.Check(ctx, id)
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Resolve returns a connection with its credentials decrypted, for running
+// schedules. Never return it to API clients.
+func (s *Service) Resolve(ctx context.Context, id string) (*Connection, error) {
+	conn, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := s.providers.SensitiveFields(conn.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("getting provider fields: %w", err)
+	}
+	if s.encryptor != nil {
+		if err := s.encryptor.DecryptMap(conn.Config, sensitive); err != nil {
+			return nil, fmt.Errorf("decrypting connection config: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Check tests a connection and records the result.
+func (s *Service) Check(ctx context.Context, id string) (*Connection, error) {
+	conn, err := s.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	health := s.check(ctx, conn)
+	if err := s.repo.UpdateHealth(ctx, id, health); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+// CheckAll tests every connection. Failing connections are recorded, not
+// returned as errors.
+func (s *Service) CheckAll(ctx context.Context) (int, error) {
+	conns, err := s.repo.List(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	unhealthy := 0
+	for _, c := range conns {
+		if ctx.Err() != nil {
+			return unhealthy, ctx.Err()
+		}
+		conn, err := s.Resolve(ctx, c.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("connection", c.Name).Msg("Skipping connection health check")
+			continue
+		}
+		health := s.check(ctx, conn)
+		if health.Status == StatusUnhealthy {
+			unhealthy++
+			if c.Status != StatusUnhealthy {
+				log.Warn().Str("connection", c.Name).Str("error", health.Error).Msg("Connection became unhealthy")
+			}
+		}
+		if err := s.repo.UpdateHealth(ctx, c.ID, health); err != nil {
+			log.Warn().Err(err).Str("connection", c.Name).Msg("Failed to record connection health")
+		}
+	}
+	return unhealthy, nil
+}
+
+func (s *Service) check(ctx context.Context, conn *Connection) Health {
+	health := Health{CheckedAt: s.now()}
+	latency, err := s.checker.Check(ctx, conn.Provider, conn.Config)
+	switch {
+	case errors.Is(err, ErrNoEndpoint):
+		health.Status = StatusUnknown
+		health.Error = err.Error()
+	case err != nil:
+		health.Status = StatusUnhealthy
+		health.Error = err.Error()
+	default:
+		health.Status = StatusHealthy
+		health.Latency = latency
+	}
+	return health
+}
+
+func (s *Service) encrypt(config map[string]interface{}, sensitive map[string]bool) error {
+	if s.encryptor == nil || len(sensitive) == 0 {
+		return nil
+	}
+	if err := s.encryptor.EncryptMap(config, sensitive); err != nil {
+		return fmt.Errorf("encrypting connection config: %w", err)
+	}
+	return nil
+}
+
+// masked returns conn with every non-empty sensitive value replaced by
+// MaskedValue.
+func (s *Service) masked(conn *Connection, sensitive map[string]bool) *Connection {
+	config := copyConfig(conn.Config)
+	for key := range sensitive {
+		if v, ok := config[key].(string); ok && v != "" {
+			config[key] = MaskedValue
+		}
+	}
+	c := *conn
+	c.Config = config
+	return &c
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	if len(name) > 255 {
+		return &ValidationError{Message: "name must be at most 255 characters"}
+	}
+	return nil
+}
+
+// changedFields returns the sensitive fields whose new value differs from
+// the stored one. Unchanged fields are still encrypted and must not be
+// encrypted twice.
+func changedFields(sensitive map[string]bool, config, stored map[string]interface{}) map[string]bool {
+	changed := make(map[string]bool)
+	for key := range sensitive {
+		if !reflect.DeepEqual(config[key], stored[key]) {
+			changed[key] = true
+		}
+	}
+	return changed
+}
+
+func copyConfig(config map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,172 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	conns map[string]*Connection
+}
+
+func (f *fakeRepo) Create(ctx context.Context, conn *Connection) error {
+	conn.ID = fmt.Sprintf("conn-%d", len(f.conns)+1)
+	f.conns[conn.ID] = conn
+	return nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (*Connection, error) {
+	conn, ok := f.conns[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c := *conn
+	c.Config = copyConfig(conn.Config)
+	return &c, nil
+}
+
+func (f *fakeRepo) Update(ctx context.Context, conn *Connection) error {
+	c := *conn
+	c.Config = copyConfig(conn.Config)
+	f.conns[conn.ID] = &c
+	return nil
+}
+
+func (f *fakeRepo) UpdateHealth(ctx context.Context, id string, health Health) error {
+	f.conns[id].Status = health.Status
+	f.conns[id].LastError = health.Error
+	return nil
+}
+
+type fakeProviders map[string]map[string]bool
+
+func (f fakeProviders) SensitiveFields(provider string) (map[string]bool, error) {
+	fields, ok := f[provider]
+	if !ok {
+		return nil, errors.New("unknown plugin")
+	}
+	return fields, nil
+}
+
+// recordingChecker records the config it was given and returns err.
+type recordingChecker struct {
+	config map[string]interface{}
+	err    error
+}
+
+func (c *recordingChecker) Check(ctx context.Context, provider string, config map[string]interface{}) (time.Duration, error) {
+	c.config = config
+	return time.Millisecond, c.err
+}
+
+func newTestService(t *testing.T) (*Service, *fakeRepo, *recordingChecker) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	encryptor, err := crypto.NewEncryptor(key)
+	require.NoError(t, err)
+
+	repo := &fakeRepo{conns: map[string]*Connection{}}
+	checker := &recordingChecker{}
+	providers := fakeProviders{"postgresql": {"password": true}}
+	return NewService(repo, providers, encryptor, checker), repo, checker
+}
+
+func TestService_CreateEncryptsAndMasks(t *testing.T) {
+	svc, repo, _ := newTestService(t)
+	ctx := context.Background()
+
+	conn, err := svc.Create(ctx, CreateInput{
+		Name:     "warehouse",
+		Provider: "postgresql",
+		Config:   map[string]interface{}{"host": "db", "password": "s3cret"},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, MaskedValue, conn.Config["password"])
+	assert.Equal(t, "db", conn.Config["host"])
+	assert.NotEqual(t, "s3cret", repo.conns[conn.ID].Config["password"], "stored credentials must be encrypted")
+
+	resolved, err := svc.Resolve(ctx, conn.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", resolved.Config["password"])
+
+	_, err = svc.Create(ctx, CreateInput{Name: "x", Provider: "oracle"}, nil)
+	assert.True(t, IsValidationError(err))
+}
+
+func TestService_UpdateKeepsMaskedCredentials(t *testing.T) {
+	svc, repo, checker := newTestService(t)
+	ctx := context.Background()
+
+	conn, err := svc.Create(ctx, CreateInput{
+		Name:     "warehouse",
+		Provider: "postgresql",
+		Config:   map[string]interface{}{"host": "db", "password": "s3cret"},
+	}, nil)
+	require.NoError(t, err)
+
+	updated, err := svc.Update(ctx, conn.ID, UpdateInput{
+		Name:   "warehouse",
+		Config: map[string]interface{}{"host": "db2", "password": MaskedValue},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, updated.CredentialsRotatedAt)
+	assert.Nil(t, checker.config, "unchanged credentials are not rechecked")
+
+	resolved, err := svc.Resolve(ctx, conn.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "db2", resolved.Config["host"])
+	assert.Equal(t, "s3cret", resolved.Config["password"])
+	assert.Equal(t, StatusUnknown, repo.conns[conn.ID].Status)
+}
+
+func TestService_RotateCredentials(t *testing.T) {
+	svc, repo, checker := newTestService(t)
+	ctx := context.Background()
+
+	conn, err := svc.Create(ctx, CreateInput{
+		Name:     "warehouse",
+		Provider: "postgresql",
+		Config:   map[string]interface{}{"host": "db", "password": "old"},
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = svc.RotateCredentials(ctx, conn.ID, map[string]string{"host": "other"})
+	assert.True(t, IsValidationError(err), "only credential fields can be rotated")
+
+	checker.err = errors.New("connection refused")
+	rotated, err := svc.RotateCredentials(ctx, conn.ID, map[string]string{"password": "new"})
+	require.NoError(t, err)
+	assert.NotNil(t, rotated.CredentialsRotatedAt)
+	assert.Equal(t, StatusUnhealthy, rotated.Status)
+	assert.Equal(t, "connection refused", rotated.LastError)
+	assert.Equal(t, "new", checker.config["password"], "checks use decrypted credentials")
+	assert.Equal(t, MaskedValue, rotated.Config["password"])
+
+	resolved, err := svc.Resolve(ctx, conn.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "new", resolved.Config["password"])
+	assert.Equal(t, "db", repo.conns[conn.ID].Config["host"])
+}
+
+func TestService_CheckWithoutEndpoint(t *testing.T) {
+	svc, _, checker := newTestService(t)
+	ctx := context.Background()
+
+	conn, err := svc.Create(ctx, CreateInput{Name: "w", Provider: "postgresql", Config: map[string]interface{}{}}, nil)
+	require.NoError(t, err)
+
+	checker.err = ErrNoEndpoint
+	checked, err := svc.Check(ctx, conn.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnknown, checked.Status)
+}
@@ -0,0 +1,171 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the connection data access interface.
+type Repository interface {
+	Create(ctx context.Context, conn *Connection) error
+	Get(ctx context.Context, id string) (*Connection, error)
+	List(ctx context.Context, provider string) ([]*Connection, error)
+	Update(ctx context.Context, conn *Connection) error
+	UpdateHealth(ctx context.Context, id string, health Health) error
+	Delete(ctx context.Context, id string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectConnection = `
+	SELECT c.id, c.name, c.provider, COALESCE(c.description, ''), c.config, c.status,
+	       COALESCE(c.last_error, ''), c.latency_ms, c.last_checked_at, c.credentials_rotated_at,
+	       (SELECT COUNT(*) FROM ingestion_schedules s WHERE s.connection_id = c.id),
+	       c.created_by, c.created_at, c.updated_at
+	FROM connections c`
+
+func (r *PostgresRepository) Create(ctx context.Context, conn *Connection) error {
+	configJSON, err := json.Marshal(conn.Config)
+	if err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO connections (name, provider, description, config, status, created_by)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		conn.Name, conn.Provider, conn.Description, configJSON, conn.Status, conn.CreatedBy,
+	).Scan(&conn.ID, &conn.CreatedAt, &conn.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrNameExists
+		}
+		return fmt.Errorf("creating connection: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Connection, error) {
+	conn, err := scanConnection(r.db.QueryRow(ctx, selectConnection+` WHERE c.id::text = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting connection: %w", err)
+	}
+	return conn, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, provider string) ([]*Connection, error) {
+	rows, err := r.db.Query(ctx, selectConnection+`
+		WHERE $1 = '' OR c.provider = $1
+		ORDER BY c.name`, provider)
+	if err != nil {
+		return nil, fmt.Errorf("listing connections: %w", err)
+	}
+	defer rows.Close()
+
+	conns := []*Connection{}
+	for rows.Next() {
+		conn, err := scanConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning connection: %w", err)
+		}
+		conns = append(conns, conn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating connections: %w", err)
+	}
+	return conns, nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, conn *Connection) error {
+	configJSON, err := json.Marshal(conn.Config)
+	if err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		UPDATE connections
+		SET name = $2, description = NULLIF($3, ''), config = $4, credentials_rotated_at = $5, updated_at = NOW()
+		WHERE id::text = $1
+		RETURNING updated_at`,
+		conn.ID, conn.Name, conn.Description, configJSON, conn.CredentialsRotatedAt,
+	).Scan(&conn.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrNameExists
+		}
+		return fmt.Errorf("updating connection: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateHealth(ctx context.Context, id string, health Health) error {
+	var latencyMS *int
+	if health.Status == StatusHealthy {
+		ms := int(health.Latency.Milliseconds())
+		latencyMS = &ms
+	}
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE connections
+		SET status = $2, last_error = NULLIF($3, ''), latency_ms = $4, last_checked_at = $5
+		WHERE id::text = $1`,
+		id, health.Status, health.Error, latencyMS, health.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("recording connection health: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM connections WHERE id::text = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrInUse
+		}
+		return fmt.Errorf("deleting connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanConnection(row pgx.Row) (*Connection, error) {
+	var c Connection
+	var configJSON []byte
+	if err := row.Scan(
+		&c.ID, &c.Name, &c.Provider, &c.Description, &configJSON, &c.Status,
+		&c.LastError, &c.LatencyMS, &c.LastCheckedAt, &c.CredentialsRotatedAt,
+		&c.ScheduleCount, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(configJSON, &c.Config); err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+	if c.Config == nil {
+		c.Config = map[string]interface{}{}
+	}
+	return &c, nil
+}
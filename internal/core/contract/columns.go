@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type column struct {
+	name string
+	typ  string
+}
+
+// columnTypes returns the columns of a schema keyed by lowercased name, with
+// their declared types where the schema has them. It reads the same shapes as
+// lineage.SchemaColumns: plugin column lists, OpenLineage fields and JSON
+// Schema properties.
+func columnTypes(schema map[string]string) map[string]column {
+	columns := map[string]column{}
+	for _, raw := range schema {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			continue
+		}
+		collect(parsed, columns)
+	}
+	return columns
+}
+
+func collect(v interface{}, columns map[string]column) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range []string{"column_name", "name"} {
+				if name, ok := obj[key].(string); ok && name != "" {
+					columns[strings.ToLower(name)] = column{name: name, typ: typeOf(obj, "data_type", "type")}
+					break
+				}
+			}
+		}
+	case map[string]interface{}:
+		if fields, ok := t["fields"]; ok {
+			collect(fields, columns)
+		}
+		if columnList, ok := t["columns"]; ok {
+			collect(columnList, columns)
+		}
+		if props, ok := t["properties"].(map[string]interface{}); ok {
+			for name, prop := range props {
+				typ := ""
+				if obj, ok := prop.(map[string]interface{}); ok {
+					typ = typeOf(obj, "type")
+				}
+				columns[strings.ToLower(name)] = column{name: name, typ: typ}
+			}
+		}
+	}
+}
+
+// typeOf returns the first type found under keys. JSON Schema type lists
+// are joined, ignoring null, so making a column nullable isn't a type change.
+func typeOf(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch t := obj[key].(type) {
+		case string:
+			if t != "" {
+				return strings.TrimSpace(t)
+			}
+		case []interface{}:
+			var types []string
+			for _, item := range t {
+				if s, ok := item.(string); ok && s != "null" {
+					types = append(types, s)
+				}
+			}
+			sort.Strings(types)
+			if len(types) > 0 {
+				return strings.Join(types, "|")
+			}
+		}
+	}
+	return ""
+}
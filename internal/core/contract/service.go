@@ -0,0 +1,283 @@
+// Package contract checks a producer's candidate schema and metadata against
+// the asset as registered in the catalog, so producers can gate merges on
+// not breaking their consumers.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+)
+
+var ErrInvalidRequest = errors.New("invalid contract validation request")
+
+// Violation kinds.
+const (
+	ViolationColumnRemoved       = "column_removed"
+	ViolationColumnTypeChanged   = "column_type_changed"
+	ViolationMetadataRemoved     = "metadata_removed"
+	ViolationMetadataTypeChanged = "metadata_type_changed"
+)
+
+// ValidateRequest is a candidate schema and metadata for an asset, as the
+// producer is about to publish it.
+type ValidateRequest struct {
+	MRN string `json:"mrn"`
+	// Schema is keyed like asset schemas. Values may be JSON documents or
+	// strings holding them.
+	Schema map[string]interface{} `json:"schema,omitempty"`
+	// Metadata is compared key by key with the registered metadata.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Depth limits how far downstream impact is followed.
+	Depth int `json:"depth,omitempty"`
+} // @name ContractValidateRequest
+
+// Violation is one way the candidate breaks the registered contract.
+type Violation struct {
+	Kind     string `json:"kind" enums:"column_removed,column_type_changed,metadata_removed,metadata_type_changed"`
+	Field    string `json:"field"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Message  string `json:"message"`
+} // @name ContractViolation
+
+// ImpactSummary counts the downstream assets affected by the candidate's
+// column changes.
+type ImpactSummary struct {
+	Breaking   bool                    `json:"breaking"`
+	Columns    int                     `json:"columns"`
+	Models     int                     `json:"models"`
+	Dashboards int                     `json:"dashboards"`
+	Assets     int                     `json:"assets"`
+	Downstream []lineage.ImpactedAsset `json:"downstream"`
+} // @name ContractImpactSummary
+
+// ValidationResult is the verdict on a candidate. Passed is false when there
+// is at least one violation.
+type ValidationResult struct {
+	MRN        string                 `json:"mrn"`
+	Passed     bool                   `json:"passed"`
+	Violations []Violation            `json:"violations"`
+	Changes    []lineage.ColumnChange `json:"changes"`
+	Impact     ImpactSummary          `json:"impact"`
+} // @name ContractValidationResult
+
+type Service struct {
+	assetSvc   asset.Service
+	lineageSvc lineage.Service
+}
+
+func NewService(assetSvc asset.Service, lineageSvc lineage.Service) *Service {
+	return &Service{assetSvc: assetSvc, lineageSvc: lineageSvc}
+}
+
+// Validate compares req with the registered asset. The registered schema and
+// metadata are the contract: removing a column or metadata key, or changing
+// its type, is a violation. Added columns and keys are allowed. Downstream
+// impact is reported for every removed or retyped column.
+func (s *Service) Validate(ctx context.Context, req *ValidateRequest) (*ValidationResult, error) {
+	if strings.TrimSpace(req.MRN) == "" {
+		return nil, fmt.Errorf("%w: mrn is required", ErrInvalidRequest)
+	}
+	if req.Schema == nil && req.Metadata == nil {
+		return nil, fmt.Errorf("%w: schema or metadata is required", ErrInvalidRequest)
+	}
+	if req.Depth < 0 || req.Depth > lineage.MaxImpactDepth {
+		return nil, fmt.Errorf("%w: depth must be between 1 and %d", ErrInvalidRequest, lineage.MaxImpactDepth)
+	}
+
+	registered, err := s.assetSvc.GetByMRN(ctx, req.MRN)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{
+		MRN:        req.MRN,
+		Violations: []Violation{},
+		Changes:    []lineage.ColumnChange{},
+		Impact:     ImpactSummary{Downstream: []lineage.ImpactedAsset{}},
+	}
+
+	if req.Schema != nil {
+		candidate, err := normalizeSchema(req.Schema)
+		if err != nil {
+			return nil, err
+		}
+		violations, changes := CompareSchemas(registered.Schema, candidate)
+		result.Violations = append(result.Violations, violations...)
+		result.Changes = changes
+	}
+	if req.Metadata != nil {
+		result.Violations = append(result.Violations, CompareMetadata(registered.Metadata, req.Metadata)...)
+	}
+	result.Passed = len(result.Violations) == 0
+
+	if hasBreakingChange(result.Changes) {
+		report, err := s.lineageSvc.AnalyzeImpact(ctx, &lineage.ImpactRequest{
+			MRN:     req.MRN,
+			Changes: result.Changes,
+			Depth:   req.Depth,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("analyzing impact: %w", err)
+		}
+		result.Impact = summarize(report)
+	}
+
+	return result, nil
+}
+
+// normalizeSchema turns request schema values into the JSON strings assets
+// store.
+func normalizeSchema(schema map[string]interface{}) (map[string]string, error) {
+	out := make(map[string]string, len(schema))
+	for key, v := range schema {
+		if str, ok := v.(string); ok {
+			if !json.Valid([]byte(str)) {
+				return nil, fmt.Errorf("%w: schema %q is not valid JSON", ErrInvalidRequest, key)
+			}
+			out[key] = str
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: schema %q: %v", ErrInvalidRequest, key, err)
+		}
+		out[key] = string(data)
+	}
+	return out, nil
+}
+
+// CompareSchemas returns the violations of candidate against registered and
+// the column changes between them. Columns are matched by name across all
+// schema keys, case-insensitively.
+func CompareSchemas(registered, candidate map[string]string) ([]Violation, []lineage.ColumnChange) {
+	before := columnTypes(registered)
+	after := columnTypes(candidate)
+
+	violations := []Violation{}
+	changes := []lineage.ColumnChange{}
+
+	for _, key := range sortedKeys(before) {
+		old := before[key]
+		cur, ok := after[key]
+		if !ok {
+			violations = append(violations, Violation{
+				Kind:     ViolationColumnRemoved,
+				Field:    old.name,
+				Expected: old.typ,
+				Message:  fmt.Sprintf("column %q is missing", old.name),
+			})
+			changes = append(changes, lineage.ColumnChange{Column: old.name, Change: lineage.ColumnChangeDrop})
+			continue
+		}
+		if old.typ != "" && cur.typ != "" && !strings.EqualFold(old.typ, cur.typ) {
+			violations = append(violations, Violation{
+				Kind:     ViolationColumnTypeChanged,
+				Field:    old.name,
+				Expected: old.typ,
+				Actual:   cur.typ,
+				Message:  fmt.Sprintf("column %q changed type from %s to %s", old.name, old.typ, cur.typ),
+			})
+			changes = append(changes, lineage.ColumnChange{Column: old.name, Change: lineage.ColumnChangeType, NewType: cur.typ})
+		}
+	}
+
+	for _, key := range sortedKeys(after) {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, lineage.ColumnChange{Column: after[key].name, Change: lineage.ColumnChangeAdd, NewType: after[key].typ})
+		}
+	}
+
+	return violations, changes
+}
+
+// CompareMetadata returns a violation for every top-level registered
+// metadata key that candidate drops or holds a different kind of value for.
+// Changed values of the same kind are allowed.
+func CompareMetadata(registered, candidate map[string]interface{}) []Violation {
+	violations := []Violation{}
+	for _, key := range sortedKeys(registered) {
+		old := registered[key]
+		cur, ok := candidate[key]
+		if !ok {
+			violations = append(violations, Violation{
+				Kind:    ViolationMetadataRemoved,
+				Field:   key,
+				Message: fmt.Sprintf("metadata key %q is missing", key),
+			})
+			continue
+		}
+		if old == nil || cur == nil {
+			continue
+		}
+		if oldKind, curKind := valueKind(old), valueKind(cur); oldKind != curKind {
+			violations = append(violations, Violation{
+				Kind:     ViolationMetadataTypeChanged,
+				Field:    key,
+				Expected: oldKind,
+				Actual:   curKind,
+				Message:  fmt.Sprintf("metadata key %q changed from %s to %s", key, oldKind, curKind),
+			})
+		}
+	}
+	return violations
+}
+
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func hasBreakingChange(changes []lineage.ColumnChange) bool {
+	for _, c := range changes {
+		if c.Change != lineage.ColumnChangeAdd {
+			return true
+		}
+	}
+	return false
+}
+
+func summarize(report *lineage.ImpactReport) ImpactSummary {
+	downstream := make([]lineage.ImpactedAsset, 0, len(report.Models)+len(report.Dashboards)+len(report.Assets))
+	downstream = append(downstream, report.Models...)
+	downstream = append(downstream, report.Dashboards...)
+	downstream = append(downstream, report.Assets...)
+	sort.SliceStable(downstream, func(i, j int) bool { return downstream[i].Depth < downstream[j].Depth })
+
+	return ImpactSummary{
+		Breaking:   report.Breaking,
+		Columns:    len(report.Columns),
+		Models:     len(report.Models),
+		Dashboards: len(report.Dashboards),
+		Assets:     len(report.Assets),
+		Downstream: downstream,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	registered := map[string]string{
+		"columns": `[{"column_name":"id","data_type":"integer"},{"column_name":"Email","data_type":"text"},{"column_name":"total","data_type":"numeric"}]`,
+	}
+	candidate := map[string]string{
+		"columns": `[{"column_name":"id","data_type":"INTEGER"},{"column_name":"total","data_type":"text"},{"column_name":"created_at","data_type":"timestamp"}]`,
+	}
+
+	violations, changes := CompareSchemas(registered, candidate)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, ViolationColumnRemoved, violations[0].Kind)
+	assert.Equal(t, "Email", violations[0].Field)
+	assert.Equal(t, ViolationColumnTypeChanged, violations[1].Kind)
+	assert.Equal(t, "numeric", violations[1].Expected)
+	assert.Equal(t, "text", violations[1].Actual)
+
+	assert.Equal(t, []lineage.ColumnChange{
+		{Column: "Email", Change: lineage.ColumnChangeDrop},
+		{Column: "total", Change: lineage.ColumnChangeType, NewType: "text"},
+		{Column: "created_at", Change: lineage.ColumnChangeAdd, NewType: "timestamp"},
+	}, changes)
+}
+
+func TestCompareSchemasJSONSchema(t *testing.T) {
+	registered := map[string]string{"event": `{"properties":{"id":{"type":"string"},"amount":{"type":"number"}}}`}
+	candidate := map[string]string{"event": `{"properties":{"id":{"type":["string","null"]},"amount":{"type":"number"}}}`}
+
+	violations, changes := CompareSchemas(registered, candidate)
+	assert.Empty(t, violations)
+	assert.Empty(t, changes)
+}
+
+func TestCompareMetadata(t *testing.T) {
+	registered := map[string]interface{}{"owner": "payments", "retention_days": 30.0, "partitioned": true}
+	candidate := map[string]interface{}{"owner": "billing", "retention_days": "30", "extra": 1.0}
+
+	violations := CompareMetadata(registered, candidate)
+	require.Len(t, violations, 2)
+	assert.Equal(t, Violation{Kind: ViolationMetadataRemoved, Field: "partitioned", Message: `metadata key "partitioned" is missing`}, violations[0])
+	assert.Equal(t, ViolationMetadataTypeChanged, violations[1].Kind)
+	assert.Equal(t, "retention_days", violations[1].Field)
+	assert.Equal(t, "number", violations[1].Expected)
+	assert.Equal(t, "string", violations[1].Actual)
+}
+
+func TestNormalizeSchema(t *testing.T) {
+	schema, err := normalizeSchema(map[string]interface{}{
+		"columns": `[{"name":"id"}]`,
+		"event":   map[string]interface{}{"properties": map[string]interface{}{}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"id"}]`, schema["columns"])
+	assert.Equal(t, `{"properties":{}}`, schema["event"])
+
+	_, err = normalizeSchema(map[string]interface{}{"columns": "not json"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
@@ -2,6 +2,7 @@ package dataproduct
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ type MembershipService struct {
 	repo        Repository
 	memberRepo  MembershipRepository
 	assetGetter AssetGetter
+	lineage     LineageResolver
 
 	// Background processing
 	workerPool *worker.Pool
@@ -31,6 +33,12 @@ type AssetGetter interface {
 	Get(ctx context.Context, id string) (*asset.Asset, error)
 }
 
+// LineageResolver returns the IDs of non-stub assets reachable from a root
+// asset through lineage, excluding the root itself.
+type LineageResolver interface {
+	ResolveLineageAssets(ctx context.Context, assetID string, direction string, depth int) ([]string, error)
+}
+
 // MembershipConfig configures the membership service.
 type MembershipConfig struct {
 	// MaxWorkers for rule evaluation. Default: 5.
@@ -94,6 +102,12 @@ func NewMembershipService(
 	return svc
 }
 
+// SetLineageResolver registers the resolver used to evaluate lineage rules.
+// Without one, lineage rules match nothing.
+func (s *MembershipService) SetLineageResolver(resolver LineageResolver) {
+	s.lineage = resolver
+}
+
 // Start begins background processing.
 func (s *MembershipService) Start(ctx context.Context) {
 	s.ctx, s.cancel = context.WithCancel(ctx)
@@ -284,7 +298,12 @@ func (s *MembershipService) EvaluateRule(ctx context.Context, ruleID string) err
 	}
 
 	// Execute the rule to get matching asset IDs
-	assetIDs, err := s.repo.ExecuteRule(ctx, rule)
+	var assetIDs []string
+	if rule.RuleType == RuleTypeLineage {
+		assetIDs, err = resolveLineageRule(ctx, s.lineage, rule)
+	} else {
+		assetIDs, err = s.repo.ExecuteRule(ctx, rule)
+	}
 	if err != nil {
 		return err
 	}
@@ -368,6 +387,30 @@ func (j *ruleEvaluationJob) Execute(ctx context.Context) error {
 	return j.svc.EvaluateRule(ctx, j.ruleID)
 }
 
+// resolveLineageRule walks lineage from the rule's root asset. Lineage rules
+// have no rule targets, so they are only refreshed on rule changes and by the
+// periodic reconciler rather than on every asset event.
+func resolveLineageRule(ctx context.Context, resolver LineageResolver, rule *Rule) ([]string, error) {
+	if resolver == nil {
+		return nil, ErrLineageRuleUnsupported
+	}
+	if rule.LineageAssetID == nil || *rule.LineageAssetID == "" {
+		return nil, fmt.Errorf("%w: lineage rule missing lineage_asset_id", ErrInvalidInput)
+	}
+
+	direction := LineageDirectionDownstream
+	if rule.LineageDirection != nil && *rule.LineageDirection != "" {
+		direction = *rule.LineageDirection
+	}
+
+	depth := DefaultLineageDepth
+	if rule.LineageDepth != nil && *rule.LineageDepth > 0 {
+		depth = min(*rule.LineageDepth, MaxLineageDepth)
+	}
+
+	return resolver.ResolveLineageAssets(ctx, *rule.LineageAssetID, direction, depth)
+}
+
 // evaluateMetadataRuleInMemory checks if an asset matches a metadata rule without DB access.
 func evaluateMetadataRuleInMemory(rule *Rule, ast *asset.Asset) bool {
 	if rule.MetadataField == nil || rule.PatternType == nil || rule.PatternValue == nil {
@@ -474,6 +517,12 @@ func extractMetadataKeys(metadata map[string]interface{}) []string {
 func ExtractRuleTargets(rule *Rule) []RuleTarget {
 	targets := []RuleTarget{}
 
+	// Lineage membership depends on edges rather than on asset fields, so
+	// there is nothing to match a new asset against.
+	if rule.RuleType == RuleTypeLineage {
+		return targets
+	}
+
 	if rule.RuleType == RuleTypeMetadataMatch {
 		if rule.MetadataField != nil {
 			parts := strings.Split(*rule.MetadataField, ".")
@@ -562,7 +611,7 @@ func extractTargetsFromFilter(filter query.Filter) []RuleTarget {
 				TargetValue: filter.Field[0],
 			})
 		}
-	case query.FieldName:
+	case query.FieldName, query.FieldStatus:
 		targets = append(targets, RuleTarget{
 			TargetType:  TargetTypeQuery,
 			TargetValue: "",
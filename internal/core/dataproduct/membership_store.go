@@ -397,14 +397,14 @@ func (r *PostgresMembershipRepository) evaluateQueryRuleForAsset(ctx context.Con
 	// Add is_stub filter after BuildSQL constructs the query
 	sqlQuery = strings.Replace(sqlQuery,
 		") SELECT * FROM search_results",
-		" AND is_stub = FALSE) SELECT id, search_rank FROM search_results",
+		" AND is_stub = FALSE AND archived_at IS NULL) SELECT id, search_rank FROM search_results",
 		1)
 
 	// If there was no WHERE clause added by BuildSQL, we need to add WHERE instead of AND
 	if !strings.Contains(sqlQuery, "WHERE") {
 		sqlQuery = strings.Replace(sqlQuery,
-			" AND is_stub = FALSE)",
-			" WHERE is_stub = FALSE)",
+			" AND is_stub = FALSE AND archived_at IS NULL)",
+			" WHERE is_stub = FALSE AND archived_at IS NULL)",
 			1)
 	}
 
@@ -498,7 +498,7 @@ func (r *PostgresMembershipRepository) evaluateMetadataRuleForAsset(ctx context.
 		return false, fmt.Errorf("unsupported pattern type: %s", *rule.PatternType)
 	}
 
-	q := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM assets WHERE id = $1 AND is_stub = FALSE AND %s)", condition)
+	q := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM assets WHERE id = $1 AND is_stub = FALSE AND archived_at IS NULL AND %s)", condition)
 
 	var exists bool
 	err := r.db.QueryRow(ctx, q, args...).Scan(&exists)
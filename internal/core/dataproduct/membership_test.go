@@ -0,0 +1,168 @@
+package dataproduct
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeLineage records the walk requested and returns assets.
+type fakeLineage struct {
+	assets    []string
+	err       error
+	rootID    string
+	direction string
+	depth     int
+}
+
+func (f *fakeLineage) ResolveLineageAssets(_ context.Context, assetID, direction string, depth int) ([]string, error) {
+	f.rootID, f.direction, f.depth = assetID, direction, depth
+	return f.assets, f.err
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+
+func TestResolveLineageRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          Rule
+		wantDirection string
+		wantDepth     int
+	}{
+		{
+			name:          "defaults",
+			rule:          Rule{LineageAssetID: strPtr("root")},
+			wantDirection: LineageDirectionDownstream,
+			wantDepth:     DefaultLineageDepth,
+		},
+		{
+			name:          "empty direction falls back to downstream",
+			rule:          Rule{LineageAssetID: strPtr("root"), LineageDirection: strPtr("")},
+			wantDirection: LineageDirectionDownstream,
+			wantDepth:     DefaultLineageDepth,
+		},
+		{
+			name:          "explicit direction and depth",
+			rule:          Rule{LineageAssetID: strPtr("root"), LineageDirection: strPtr(LineageDirectionUpstream), LineageDepth: intPtr(2)},
+			wantDirection: LineageDirectionUpstream,
+			wantDepth:     2,
+		},
+		{
+			name:          "depth capped",
+			rule:          Rule{LineageAssetID: strPtr("root"), LineageDirection: strPtr(LineageDirectionBoth), LineageDepth: intPtr(50)},
+			wantDirection: LineageDirectionBoth,
+			wantDepth:     MaxLineageDepth,
+		},
+		{
+			name:          "non-positive depth uses default",
+			rule:          Rule{LineageAssetID: strPtr("root"), LineageDepth: intPtr(0)},
+			wantDirection: LineageDirectionDownstream,
+			wantDepth:     DefaultLineageDepth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeLineage{assets: []string{"a", "b"}}
+			rule := tt.rule
+			rule.RuleType = RuleTypeLineage
+
+			got, err := resolveLineageRule(context.Background(), resolver, &rule)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, []string{"a", "b"}) {
+				t.Fatalf("got assets %v", got)
+			}
+			if resolver.rootID != "root" || resolver.direction != tt.wantDirection || resolver.depth != tt.wantDepth {
+				t.Fatalf("walked %s %s depth %d, want root %s depth %d",
+					resolver.rootID, resolver.direction, resolver.depth, tt.wantDirection, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestResolveLineageRuleErrors(t *testing.T) {
+	rule := &Rule{RuleType: RuleTypeLineage, LineageAssetID: strPtr("root")}
+
+	if _, err := resolveLineageRule(context.Background(), nil, rule); !errors.Is(err, ErrLineageRuleUnsupported) {
+		t.Fatalf("without a resolver: got %v", err)
+	}
+
+	for _, id := range []*string{nil, strPtr("")} {
+		_, err := resolveLineageRule(context.Background(), &fakeLineage{}, &Rule{RuleType: RuleTypeLineage, LineageAssetID: id})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("missing root asset: got %v", err)
+		}
+	}
+
+	boom := errors.New("boom")
+	if _, err := resolveLineageRule(context.Background(), &fakeLineage{err: boom}, rule); !errors.Is(err, boom) {
+		t.Fatalf("resolver error not returned: got %v", err)
+	}
+}
+
+func TestPreviewLineageRule(t *testing.T) {
+	resolver := &fakeLineage{assets: []string{"a", "b", "c"}}
+	svc := NewService(nil)
+	svc.SetLineageResolver(resolver)
+
+	preview, err := svc.PreviewRule(context.Background(), RuleInput{
+		Name:           "downstream of orders",
+		RuleType:       RuleTypeLineage,
+		LineageAssetID: strPtr("root"),
+	}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.AssetCount != 3 || !reflect.DeepEqual(preview.AssetIDs, []string{"a", "b"}) || len(preview.Errors) != 0 {
+		t.Fatalf("preview should be limited but count every asset, got %+v", preview)
+	}
+}
+
+func TestPreviewLineageRuleReportsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver LineageResolver
+		input    RuleInput
+	}{
+		{
+			name:     "missing root asset",
+			resolver: &fakeLineage{},
+			input:    RuleInput{Name: "r", RuleType: RuleTypeLineage},
+		},
+		{
+			name:     "depth out of range",
+			resolver: &fakeLineage{},
+			input:    RuleInput{Name: "r", RuleType: RuleTypeLineage, LineageAssetID: strPtr("root"), LineageDepth: intPtr(MaxLineageDepth + 1)},
+		},
+		{
+			name:  "no resolver",
+			input: RuleInput{Name: "r", RuleType: RuleTypeLineage, LineageAssetID: strPtr("root")},
+		},
+		{
+			name:     "resolver fails",
+			resolver: &fakeLineage{err: errors.New("boom")},
+			input:    RuleInput{Name: "r", RuleType: RuleTypeLineage, LineageAssetID: strPtr("root")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(nil)
+			if tt.resolver != nil {
+				svc.SetLineageResolver(tt.resolver)
+			}
+
+			preview, err := svc.PreviewRule(context.Background(), tt.input, 10)
+			if err != nil {
+				t.Fatalf("errors should be reported in the preview, got %v", err)
+			}
+			if len(preview.Errors) == 0 || preview.AssetCount != 0 || len(preview.AssetIDs) != 0 {
+				t.Fatalf("expected an empty preview with errors, got %+v", preview)
+			}
+		})
+	}
+}
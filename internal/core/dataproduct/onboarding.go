@@ -0,0 +1,420 @@
+package dataproduct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+)
+
+// OnboardingStep is one stage of the guided data product creation flow.
+type OnboardingStep string // @name DataProductOnboardingStep
+
+const (
+	// StepDetails covers the name, description and tags.
+	StepDetails OnboardingStep = "details"
+	// StepOwners covers the owning users and teams.
+	StepOwners OnboardingStep = "owners"
+	// StepAssets covers the rules and manually added assets.
+	StepAssets OnboardingStep = "assets"
+	// StepSLA covers the service level the owners commit to.
+	StepSLA OnboardingStep = "sla"
+)
+
+// OnboardingSteps lists the steps in the order they are worked through.
+var OnboardingSteps = []OnboardingStep{StepDetails, StepOwners, StepAssets, StepSLA}
+
+const (
+	// MinDescriptionLength is the shortest description a draft can be
+	// published with.
+	MinDescriptionLength = 20
+	MaxDraftAssets       = 1000
+	MaxFreshnessHours    = 24 * 365
+)
+
+var (
+	ErrDraftNotFound  = errors.New("data product draft not found")
+	ErrDraftPublished = errors.New("data product draft has already been published")
+	ErrUnknownStep    = errors.New("unknown onboarding step")
+)
+
+// SLA is the service level a data product's owners commit to. It is stored
+// under the "sla" metadata key of the published product.
+type SLA struct {
+	// FreshnessHours is the longest the product's data may go without an update.
+	FreshnessHours int `json:"freshness_hours"`
+	// Availability is the target uptime as a percentage, e.g. 99.9.
+	Availability *float64 `json:"availability,omitempty"`
+	// SupportContact is where consumers go for help, e.g. a channel or email.
+	SupportContact string `json:"support_contact"`
+} // @name DataProductSLA
+
+// DraftContent is everything saved in a draft so far. Each step saves only
+// its own fields.
+type DraftContent struct {
+	Name        string       `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Owners      []OwnerInput `json:"owners,omitempty"`
+	Rules       []RuleInput  `json:"rules,omitempty"`
+	AssetIDs    []string     `json:"asset_ids,omitempty"`
+	SLA         *SLA         `json:"sla,omitempty"`
+} // @name DataProductDraftContent
+
+// StepStatus reports whether a step meets the bar for publishing.
+type StepStatus struct {
+	Step     OnboardingStep `json:"step"`
+	Complete bool           `json:"complete"`
+	Errors   []string       `json:"errors,omitempty"`
+} // @name DataProductOnboardingStepStatus
+
+// Draft is a data product being created through the onboarding flow.
+type Draft struct {
+	ID      string       `json:"id"`
+	Content DraftContent `json:"content"`
+	Steps   []StepStatus `json:"steps"`
+	// CurrentStep is the first incomplete step, empty when every step is complete.
+	CurrentStep        OnboardingStep `json:"current_step,omitempty"`
+	ReadyToPublish     bool           `json:"ready_to_publish"`
+	PublishedProductID *string        `json:"published_product_id,omitempty"`
+	PublishedAt        *time.Time     `json:"published_at,omitempty"`
+	CreatedBy          string         `json:"created_by"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+} // @name DataProductDraft
+
+// OnboardingError is returned when a draft can't be published because
+// steps are incomplete. Steps holds the status of every step.
+type OnboardingError struct {
+	Steps []StepStatus
+}
+
+func (e *OnboardingError) Error() string {
+	var incomplete []string
+	for _, s := range e.Steps {
+		if !s.Complete {
+			incomplete = append(incomplete, string(s.Step))
+		}
+	}
+	return fmt.Sprintf("incomplete onboarding steps: %s", strings.Join(incomplete, ", "))
+}
+
+// DraftRepository persists onboarding drafts.
+type DraftRepository interface {
+	CreateDraft(ctx context.Context, draft *Draft) error
+	GetDraft(ctx context.Context, id string) (*Draft, error)
+	ListDrafts(ctx context.Context, userID string, includePublished bool) ([]*Draft, error)
+	UpdateDraftContent(ctx context.Context, id string, content DraftContent) error
+	MarkDraftPublished(ctx context.Context, id, productID string) error
+	DeleteDraft(ctx context.Context, id string) error
+}
+
+// OnboardingService guides users through creating a data product one step
+// at a time. Drafts are private to the user who started them and can only
+// be published once every step meets the minimum quality bar.
+type OnboardingService struct {
+	repo      DraftRepository
+	products  Service
+	validator *validator.Validate
+}
+
+// NewOnboardingService creates an onboarding service that publishes drafts
+// through products.
+func NewOnboardingService(repo DraftRepository, products Service) *OnboardingService {
+	return &OnboardingService{
+		repo:      repo,
+		products:  products,
+		validator: validator.New(),
+	}
+}
+
+// CreateDraft starts a draft, optionally with some content already filled in.
+func (s *OnboardingService) CreateDraft(ctx context.Context, userID string, content DraftContent) (*Draft, error) {
+	draft := &Draft{Content: normalizeContent(content), CreatedBy: userID}
+	if err := s.repo.CreateDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	s.evaluate(draft)
+	return draft, nil
+}
+
+// GetDraft returns one of the user's drafts with the status of every step.
+func (s *OnboardingService) GetDraft(ctx context.Context, id, userID string) (*Draft, error) {
+	draft, err := s.repo.GetDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.CreatedBy != userID {
+		return nil, ErrDraftNotFound
+	}
+	s.evaluate(draft)
+	return draft, nil
+}
+
+// ListDrafts returns the user's drafts, most recently updated first.
+func (s *OnboardingService) ListDrafts(ctx context.Context, userID string, includePublished bool) ([]*Draft, error) {
+	drafts, err := s.repo.ListDrafts(ctx, userID, includePublished)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drafts {
+		s.evaluate(d)
+	}
+	return drafts, nil
+}
+
+// SaveStep saves the fields belonging to step from input, leaving the rest
+// of the draft alone. Incomplete steps are saved too; their problems are
+// reported in the returned draft's step statuses.
+func (s *OnboardingService) SaveStep(ctx context.Context, id, userID string, step OnboardingStep, input DraftContent) (*Draft, error) {
+	draft, err := s.GetDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.PublishedAt != nil {
+		return nil, ErrDraftPublished
+	}
+
+	input = normalizeContent(input)
+	content := draft.Content
+	switch step {
+	case StepDetails:
+		content.Name = input.Name
+		content.Description = input.Description
+		content.Tags = input.Tags
+	case StepOwners:
+		content.Owners = input.Owners
+	case StepAssets:
+		content.Rules = input.Rules
+		content.AssetIDs = input.AssetIDs
+	case StepSLA:
+		content.SLA = input.SLA
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStep, step)
+	}
+
+	if err := s.repo.UpdateDraftContent(ctx, id, content); err != nil {
+		return nil, err
+	}
+	return s.GetDraft(ctx, id, userID)
+}
+
+// DeleteDraft discards one of the user's drafts. Deleting a published
+// draft leaves the data product alone.
+func (s *OnboardingService) DeleteDraft(ctx context.Context, id, userID string) error {
+	if _, err := s.GetDraft(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteDraft(ctx, id)
+}
+
+// Publish creates the data product described by a draft. It returns an
+// *OnboardingError if any step is incomplete, including problems only found
+// while creating the product, such as a name that is already taken.
+func (s *OnboardingService) Publish(ctx context.Context, id, userID string) (*DataProduct, error) {
+	draft, err := s.GetDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.PublishedAt != nil {
+		return nil, ErrDraftPublished
+	}
+	if !draft.ReadyToPublish {
+		return nil, &OnboardingError{Steps: draft.Steps}
+	}
+
+	c := draft.Content
+	dp, err := s.products.Create(ctx, CreateInput{
+		Name:        c.Name,
+		Description: c.Description,
+		Tags:        c.Tags,
+		Owners:      c.Owners,
+		Rules:       c.Rules,
+		Metadata:    map[string]interface{}{"sla": slaMetadata(c.SLA)},
+	})
+	switch {
+	case errors.Is(err, ErrConflict):
+		return nil, stepFailure(draft.Steps, StepDetails, "a data product with this name already exists")
+	case isForeignKeyViolation(err):
+		return nil, stepFailure(draft.Steps, StepOwners, "an owner does not exist")
+	case err != nil:
+		return nil, err
+	}
+
+	if len(c.AssetIDs) > 0 {
+		if err := s.products.AddAssets(ctx, dp.ID, c.AssetIDs, userID); err != nil {
+			// Don't leave a half-built product behind.
+			if delErr := s.products.Delete(ctx, dp.ID); delErr != nil {
+				log.Warn().Err(delErr).Str("data_product_id", dp.ID).Msg("Failed to remove partially published data product")
+			}
+			if isForeignKeyViolation(err) {
+				return nil, stepFailure(draft.Steps, StepAssets, "an asset does not exist")
+			}
+			return nil, fmt.Errorf("adding assets: %w", err)
+		}
+	}
+
+	if err := s.repo.MarkDraftPublished(ctx, id, dp.ID); err != nil {
+		log.Warn().Err(err).Str("draft_id", id).Str("data_product_id", dp.ID).Msg("Failed to mark data product draft as published")
+	}
+
+	return s.products.Get(ctx, dp.ID)
+}
+
+// evaluate fills in the draft's step statuses.
+func (s *OnboardingService) evaluate(draft *Draft) {
+	draft.Steps = make([]StepStatus, len(OnboardingSteps))
+	draft.CurrentStep = ""
+	for i, step := range OnboardingSteps {
+		errs := s.validateStep(step, draft.Content)
+		draft.Steps[i] = StepStatus{Step: step, Complete: len(errs) == 0, Errors: errs}
+		if len(errs) > 0 && draft.CurrentStep == "" {
+			draft.CurrentStep = step
+		}
+	}
+	draft.ReadyToPublish = draft.CurrentStep == ""
+}
+
+// validateStep returns what stops step from being complete.
+func (s *OnboardingService) validateStep(step OnboardingStep, c DraftContent) []string {
+	var errs []string
+	switch step {
+	case StepDetails:
+		switch {
+		case c.Name == "":
+			errs = append(errs, "name is required")
+		case len(c.Name) > 255:
+			errs = append(errs, "name must be at most 255 characters")
+		}
+		if c.Description == nil || len([]rune(*c.Description)) < MinDescriptionLength {
+			errs = append(errs, fmt.Sprintf("description must be at least %d characters", MinDescriptionLength))
+		}
+
+	case StepOwners:
+		if len(c.Owners) == 0 {
+			errs = append(errs, "at least one owner is required")
+		}
+		seen := make(map[OwnerInput]bool, len(c.Owners))
+		for _, o := range c.Owners {
+			if err := s.validator.Struct(o); err != nil || s.validator.Var(o.ID, "uuid") != nil {
+				errs = append(errs, fmt.Sprintf("owner %q: id must be a user or team ID and type must be user or team", o.ID))
+				continue
+			}
+			if seen[o] {
+				errs = append(errs, fmt.Sprintf("owner %q is listed more than once", o.ID))
+			}
+			seen[o] = true
+		}
+
+	case StepAssets:
+		if len(c.Rules) == 0 && len(c.AssetIDs) == 0 {
+			errs = append(errs, "at least one rule or asset is required")
+		}
+		if len(c.Rules) > MaxRules {
+			errs = append(errs, fmt.Sprintf("at most %d rules are allowed", MaxRules))
+		}
+		if len(c.AssetIDs) > MaxDraftAssets {
+			errs = append(errs, fmt.Sprintf("at most %d assets can be added while onboarding", MaxDraftAssets))
+		}
+		for i, r := range c.Rules {
+			err := s.validator.Struct(r)
+			if err == nil {
+				err = validateRule(r)
+			}
+			if err != nil {
+				name := r.Name
+				if name == "" {
+					name = fmt.Sprintf("#%d", i+1)
+				}
+				errs = append(errs, fmt.Sprintf("rule %s: %s", name, strings.TrimPrefix(err.Error(), ErrInvalidInput.Error()+": ")))
+			}
+		}
+
+	case StepSLA:
+		if c.SLA == nil {
+			errs = append(errs, "an SLA is required")
+			break
+		}
+		if c.SLA.FreshnessHours < 1 || c.SLA.FreshnessHours > MaxFreshnessHours {
+			errs = append(errs, fmt.Sprintf("freshness_hours must be between 1 and %d", MaxFreshnessHours))
+		}
+		if a := c.SLA.Availability; a != nil && (*a <= 0 || *a > 100) {
+			errs = append(errs, "availability must be a percentage above 0 and at most 100")
+		}
+		switch {
+		case c.SLA.SupportContact == "":
+			errs = append(errs, "support_contact is required")
+		case len(c.SLA.SupportContact) > 255:
+			errs = append(errs, "support_contact must be at most 255 characters")
+		}
+	}
+	return errs
+}
+
+// normalizeContent trims text and drops blank and repeated list entries.
+func normalizeContent(c DraftContent) DraftContent {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Description != nil {
+		d := strings.TrimSpace(*c.Description)
+		c.Description = &d
+	}
+	c.Tags = dedupeStrings(c.Tags)
+	c.AssetIDs = dedupeStrings(c.AssetIDs)
+	if c.SLA != nil {
+		sla := *c.SLA
+		sla.SupportContact = strings.TrimSpace(sla.SupportContact)
+		c.SLA = &sla
+	}
+	return c
+}
+
+func dedupeStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func slaMetadata(sla *SLA) map[string]interface{} {
+	m := map[string]interface{}{
+		"freshness_hours": sla.FreshnessHours,
+		"support_contact": sla.SupportContact,
+	}
+	if sla.Availability != nil {
+		m["availability"] = *sla.Availability
+	}
+	return m
+}
+
+// stepFailure marks step as failed with msg on a copy of steps.
+func stepFailure(steps []StepStatus, step OnboardingStep, msg string) *OnboardingError {
+	out := make([]StepStatus, len(steps))
+	copy(out, steps)
+	for i := range out {
+		if out[i].Step == step {
+			out[i].Complete = false
+			out[i].Errors = append(append([]string{}, out[i].Errors...), msg)
+		}
+	}
+	return &OnboardingError{Steps: out}
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,136 @@
+package dataproduct
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/metrics"
+)
+
+// PostgresDraftRepository implements DraftRepository for PostgreSQL.
+type PostgresDraftRepository struct {
+	db       *pgxpool.Pool
+	recorder metrics.Recorder
+}
+
+// NewPostgresDraftRepository creates a new PostgreSQL draft repository.
+func NewPostgresDraftRepository(db *pgxpool.Pool, recorder metrics.Recorder) *PostgresDraftRepository {
+	return &PostgresDraftRepository{
+		db:       db,
+		recorder: recorder,
+	}
+}
+
+const draftColumns = `id, content, created_by, published_product_id, published_at, created_at, updated_at`
+
+func (r *PostgresDraftRepository) CreateDraft(ctx context.Context, draft *Draft) error {
+	start := time.Now()
+
+	contentJSON, err := json.Marshal(draft.Content)
+	if err != nil {
+		return fmt.Errorf("marshaling draft content: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO data_product_drafts (content, created_by)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at`,
+		contentJSON, draft.CreatedBy,
+	).Scan(&draft.ID, &draft.CreatedAt, &draft.UpdatedAt)
+	r.recorder.RecordDBQuery(ctx, "dataproduct_draft_create", time.Since(start), err == nil)
+	if err != nil {
+		return fmt.Errorf("creating data product draft: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDraftRepository) GetDraft(ctx context.Context, id string) (*Draft, error) {
+	start := time.Now()
+
+	draft, err := scanDraft(r.db.QueryRow(ctx, `SELECT `+draftColumns+` FROM data_product_drafts WHERE id = $1`, id))
+	r.recorder.RecordDBQuery(ctx, "dataproduct_draft_get", time.Since(start), err == nil || errors.Is(err, ErrDraftNotFound))
+	return draft, err
+}
+
+func (r *PostgresDraftRepository) ListDrafts(ctx context.Context, userID string, includePublished bool) ([]*Draft, error) {
+	start := time.Now()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+draftColumns+`
+		FROM data_product_drafts
+		WHERE created_by = $1 AND ($2 OR published_at IS NULL)
+		ORDER BY updated_at DESC`, userID, includePublished)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "dataproduct_draft_list", time.Since(start), false)
+		return nil, fmt.Errorf("listing data product drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := []*Draft{}
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			r.recorder.RecordDBQuery(ctx, "dataproduct_draft_list", time.Since(start), false)
+			return nil, err
+		}
+		drafts = append(drafts, draft)
+	}
+	r.recorder.RecordDBQuery(ctx, "dataproduct_draft_list", time.Since(start), rows.Err() == nil)
+	return drafts, rows.Err()
+}
+
+func (r *PostgresDraftRepository) UpdateDraftContent(ctx context.Context, id string, content DraftContent) error {
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("marshaling draft content: %w", err)
+	}
+	return r.exec(ctx, "dataproduct_draft_update",
+		`UPDATE data_product_drafts SET content = $2, updated_at = NOW() WHERE id = $1`, id, contentJSON)
+}
+
+func (r *PostgresDraftRepository) MarkDraftPublished(ctx context.Context, id, productID string) error {
+	return r.exec(ctx, "dataproduct_draft_publish", `
+		UPDATE data_product_drafts
+		SET published_product_id = $2, published_at = NOW(), updated_at = NOW()
+		WHERE id = $1`, id, productID)
+}
+
+func (r *PostgresDraftRepository) DeleteDraft(ctx context.Context, id string) error {
+	return r.exec(ctx, "dataproduct_draft_delete", `DELETE FROM data_product_drafts WHERE id = $1`, id)
+}
+
+func (r *PostgresDraftRepository) exec(ctx context.Context, name, sql string, args ...interface{}) error {
+	start := time.Now()
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	r.recorder.RecordDBQuery(ctx, name, time.Since(start), err == nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDraftNotFound
+	}
+	return nil
+}
+
+func scanDraft(row pgx.Row) (*Draft, error) {
+	var draft Draft
+	var contentJSON []byte
+	err := row.Scan(&draft.ID, &contentJSON, &draft.CreatedBy, &draft.PublishedProductID,
+		&draft.PublishedAt, &draft.CreatedAt, &draft.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDraftNotFound
+		}
+		return nil, fmt.Errorf("scanning data product draft: %w", err)
+	}
+	if err := json.Unmarshal(contentJSON, &draft.Content); err != nil {
+		return nil, fmt.Errorf("unmarshaling draft content: %w", err)
+	}
+	return &draft, nil
+}
@@ -0,0 +1,69 @@
+package dataproduct
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluateDraft(t *testing.T) {
+	s := NewOnboardingService(nil, nil)
+	desc := "Orders placed through the web shop"
+	query := `@type: "table"`
+
+	draft := &Draft{Content: normalizeContent(DraftContent{
+		Name:        "  Orders ",
+		Description: &desc,
+		Tags:        []string{"sales", " sales", ""},
+	})}
+	s.evaluate(draft)
+
+	if draft.Content.Name != "Orders" || len(draft.Content.Tags) != 1 {
+		t.Fatalf("content not normalized: %+v", draft.Content)
+	}
+	if !draft.Steps[0].Complete {
+		t.Fatalf("details should be complete, got %v", draft.Steps[0].Errors)
+	}
+	if draft.CurrentStep != StepOwners || draft.ReadyToPublish {
+		t.Fatalf("expected current step owners, got %q (ready=%v)", draft.CurrentStep, draft.ReadyToPublish)
+	}
+
+	ownerID := "7f1c1d3e-3f0a-4a5e-9b1c-2d3e4f5a6b7c"
+	draft.Content.Owners = []OwnerInput{{ID: ownerID, Type: "user"}, {ID: ownerID, Type: "user"}}
+	draft.Content.Rules = []RuleInput{{Name: "tables", RuleType: RuleTypeQuery, QueryExpression: &query}}
+	draft.Content.SLA = &SLA{FreshnessHours: 24}
+	s.evaluate(draft)
+
+	if draft.Steps[1].Complete || !strings.Contains(strings.Join(draft.Steps[1].Errors, ";"), "more than once") {
+		t.Fatalf("duplicate owner should be reported, got %v", draft.Steps[1].Errors)
+	}
+	if draft.Steps[3].Complete {
+		t.Fatal("sla without a support contact should be incomplete")
+	}
+
+	draft.Content.Owners = draft.Content.Owners[:1]
+	draft.Content.SLA.SupportContact = "#orders"
+	s.evaluate(draft)
+
+	for _, st := range draft.Steps {
+		if !st.Complete {
+			t.Fatalf("step %s incomplete: %v", st.Step, st.Errors)
+		}
+	}
+	if !draft.ReadyToPublish || draft.CurrentStep != "" {
+		t.Fatal("draft should be ready to publish")
+	}
+}
+
+func TestOnboardingErrorListsIncompleteSteps(t *testing.T) {
+	err := stepFailure([]StepStatus{
+		{Step: StepDetails, Complete: true},
+		{Step: StepOwners, Complete: true},
+	}, StepDetails, "name taken")
+
+	if err.Steps[0].Complete || err.Steps[0].Errors[0] != "name taken" {
+		t.Fatalf("details should be marked failed: %+v", err.Steps[0])
+	}
+	if got := err.Error(); got != "incomplete onboarding steps: details" {
+		t.Fatalf("unexpected error message %q", got)
+	}
+}
@@ -68,6 +68,7 @@ type Service interface {
 
 	SetRuleObserver(observer RuleObserver)
 	SetSearchObserver(observer SearchObserver)
+	SetLineageResolver(resolver LineageResolver)
 }
 
 // RuleObserver is notified when rules are created, updated, or deleted.
@@ -88,6 +89,7 @@ type service struct {
 	validator      *validator.Validate
 	ruleObserver   RuleObserver
 	searchObserver SearchObserver
+	lineage        LineageResolver
 }
 
 func NewService(repo Repository) Service {
@@ -105,13 +107,17 @@ func (s *service) SetSearchObserver(observer SearchObserver) {
 	s.searchObserver = observer
 }
 
+func (s *service) SetLineageResolver(resolver LineageResolver) {
+	s.lineage = resolver
+}
+
 func (s *service) Create(ctx context.Context, input CreateInput) (*DataProduct, error) {
 	if err := s.validator.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
 	}
 
 	for _, rule := range input.Rules {
-		if err := s.validateRule(rule); err != nil {
+		if err := validateRule(rule); err != nil {
 			return nil, err
 		}
 	}
@@ -275,7 +281,7 @@ func (s *service) CreateRule(ctx context.Context, dataProductID string, input Ru
 		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
 	}
 
-	if err := s.validateRule(input); err != nil {
+	if err := validateRule(input); err != nil {
 		return nil, err
 	}
 
@@ -307,7 +313,7 @@ func (s *service) UpdateRule(ctx context.Context, ruleID string, input RuleInput
 		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
 	}
 
-	if err := s.validateRule(input); err != nil {
+	if err := validateRule(input); err != nil {
 		return nil, err
 	}
 
@@ -344,7 +350,7 @@ func (s *service) GetRules(ctx context.Context, dataProductID string) ([]Rule, e
 }
 
 func (s *service) PreviewRule(ctx context.Context, input RuleInput, limit int) (*RulePreview, error) {
-	if err := s.validateRule(input); err != nil {
+	if err := validateRule(input); err != nil {
 		return &RulePreview{
 			AssetIDs:   []string{},
 			AssetCount: 0,
@@ -358,9 +364,40 @@ func (s *service) PreviewRule(ctx context.Context, input RuleInput, limit int) (
 		limit = MaxLimit
 	}
 
+	if input.RuleType == RuleTypeLineage {
+		return s.previewLineageRule(ctx, &input, limit)
+	}
+
 	return s.repo.PreviewRule(ctx, &input, limit)
 }
 
+func (s *service) previewLineageRule(ctx context.Context, input *RuleInput, limit int) (*RulePreview, error) {
+	assetIDs, err := resolveLineageRule(ctx, s.lineage, &Rule{
+		RuleType:         input.RuleType,
+		LineageAssetID:   input.LineageAssetID,
+		LineageDirection: input.LineageDirection,
+		LineageDepth:     input.LineageDepth,
+		IsEnabled:        true,
+	})
+	if err != nil {
+		return &RulePreview{
+			AssetIDs:   []string{},
+			AssetCount: 0,
+			Errors:     []string{err.Error()},
+		}, nil
+	}
+
+	total := len(assetIDs)
+	if limit < len(assetIDs) {
+		assetIDs = assetIDs[:limit]
+	}
+
+	return &RulePreview{
+		AssetIDs:   assetIDs,
+		AssetCount: total,
+	}, nil
+}
+
 func (s *service) GetResolvedAssets(ctx context.Context, dataProductID string, limit, offset int) (*ResolvedAssets, error) {
 	if _, err := s.repo.Get(ctx, dataProductID); err != nil {
 		return nil, err
@@ -434,7 +471,7 @@ func (s *service) UploadImage(ctx context.Context, dataProductID string, purpose
 		Filename:      image.Filename,
 		ContentType:   image.ContentType,
 		SizeBytes:     image.SizeBytes,
-		URL:           fmt.Sprintf("/api/v1/products/images/%s/%s", image.DataProductID, image.Purpose),
+		URL:           productImageURL(image.DataProductID, image.Purpose),
 		CreatedAt:     image.CreatedAt,
 	}, nil
 }
@@ -469,7 +506,7 @@ func (s *service) ListImages(ctx context.Context, dataProductID string) ([]*Prod
 	return s.repo.ListProductImages(ctx, dataProductID)
 }
 
-func (s *service) validateRule(input RuleInput) error {
+func validateRule(input RuleInput) error {
 	switch input.RuleType {
 	case RuleTypeQuery:
 		if input.QueryExpression == nil || *input.QueryExpression == "" {
@@ -496,6 +533,13 @@ func (s *service) validateRule(input RuleInput) error {
 				return fmt.Errorf("%w: invalid regex pattern: %v", ErrInvalidInput, err)
 			}
 		}
+	case RuleTypeLineage:
+		if input.LineageAssetID == nil || *input.LineageAssetID == "" {
+			return fmt.Errorf("%w: lineage_asset_id required for lineage rule type", ErrInvalidInput)
+		}
+		if input.LineageDepth != nil && (*input.LineageDepth < 1 || *input.LineageDepth > MaxLineageDepth) {
+			return fmt.Errorf("%w: lineage_depth must be between 1 and %d", ErrInvalidInput, MaxLineageDepth)
+		}
 	default:
 		return fmt.Errorf("%w: invalid rule_type", ErrInvalidInput)
 	}
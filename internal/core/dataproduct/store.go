@@ -21,6 +21,8 @@ var (
 	ErrConflict     = errors.New("data product with this name already exists")
 	ErrInvalidInput = errors.New("invalid input")
 	ErrRuleNotFound = errors.New("rule not found")
+
+	ErrLineageRuleUnsupported = errors.New("lineage rules must be evaluated via the lineage service")
 )
 
 type RuleType string // @name DataProductRuleType
@@ -28,6 +30,16 @@ type RuleType string // @name DataProductRuleType
 const (
 	RuleTypeQuery         RuleType = "query"
 	RuleTypeMetadataMatch RuleType = "metadata_match"
+	RuleTypeLineage       RuleType = "lineage"
+)
+
+const (
+	LineageDirectionUpstream   = "upstream"
+	LineageDirectionDownstream = "downstream"
+	LineageDirectionBoth       = "both"
+
+	DefaultLineageDepth = 3
+	MaxLineageDepth     = 10
 )
 
 const (
@@ -84,34 +96,40 @@ type OwnerInput struct {
 }
 
 type Rule struct {
-	ID              string    `json:"id"`
-	DataProductID   string    `json:"data_product_id"`
-	Name            string    `json:"name"`
-	Description     *string   `json:"description,omitempty"`
-	RuleType        RuleType  `json:"rule_type"`
-	QueryExpression *string   `json:"query_expression,omitempty"`
-	MetadataField   *string   `json:"metadata_field,omitempty"`
-	PatternType     *string   `json:"pattern_type,omitempty"`
-	PatternValue    *string   `json:"pattern_value,omitempty"`
-	Priority        int       `json:"priority"`
-	IsEnabled       bool      `json:"is_enabled"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID               string    `json:"id"`
+	DataProductID    string    `json:"data_product_id"`
+	Name             string    `json:"name"`
+	Description      *string   `json:"description,omitempty"`
+	RuleType         RuleType  `json:"rule_type"`
+	QueryExpression  *string   `json:"query_expression,omitempty"`
+	MetadataField    *string   `json:"metadata_field,omitempty"`
+	PatternType      *string   `json:"pattern_type,omitempty"`
+	PatternValue     *string   `json:"pattern_value,omitempty"`
+	LineageAssetID   *string   `json:"lineage_asset_id,omitempty"`
+	LineageDirection *string   `json:"lineage_direction,omitempty"`
+	LineageDepth     *int      `json:"lineage_depth,omitempty"`
+	Priority         int       `json:"priority"`
+	IsEnabled        bool      `json:"is_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 
 	MatchedAssetCount int `json:"matched_asset_count,omitempty"`
 } // @name DataProductRule
 
 type RuleInput struct {
-	ID              *string  `json:"id,omitempty"`
-	Name            string   `json:"name" validate:"required,min=1,max=255"`
-	Description     *string  `json:"description,omitempty"`
-	RuleType        RuleType `json:"rule_type" validate:"required,oneof=query metadata_match"`
-	QueryExpression *string  `json:"query_expression,omitempty"`
-	MetadataField   *string  `json:"metadata_field,omitempty"`
-	PatternType     *string  `json:"pattern_type,omitempty" validate:"omitempty,oneof=exact wildcard regex prefix"`
-	PatternValue    *string  `json:"pattern_value,omitempty"`
-	Priority        int      `json:"priority"`
-	IsEnabled       bool     `json:"is_enabled"`
+	ID               *string  `json:"id,omitempty"`
+	Name             string   `json:"name" validate:"required,min=1,max=255"`
+	Description      *string  `json:"description,omitempty"`
+	RuleType         RuleType `json:"rule_type" validate:"required,oneof=query metadata_match lineage"`
+	QueryExpression  *string  `json:"query_expression,omitempty"`
+	MetadataField    *string  `json:"metadata_field,omitempty"`
+	PatternType      *string  `json:"pattern_type,omitempty" validate:"omitempty,oneof=exact wildcard regex prefix"`
+	PatternValue     *string  `json:"pattern_value,omitempty"`
+	LineageAssetID   *string  `json:"lineage_asset_id,omitempty" validate:"omitempty,uuid"`
+	LineageDirection *string  `json:"lineage_direction,omitempty" validate:"omitempty,oneof=upstream downstream both"`
+	LineageDepth     *int     `json:"lineage_depth,omitempty" validate:"omitempty,gte=1,lte=10"`
+	Priority         int      `json:"priority"`
+	IsEnabled        bool     `json:"is_enabled"`
 }
 
 type SearchFilter struct {
@@ -227,6 +245,48 @@ func (r *PostgresRepository) loadOwners(ctx context.Context, dataProductID strin
 	return owners, nil
 }
 
+// loadOwnersForProducts loads the owners of several data products in one
+// query, keyed by data product ID.
+func (r *PostgresRepository) loadOwnersForProducts(ctx context.Context, dataProductIDs []string) (map[string][]Owner, error) {
+	q := `
+		SELECT
+			dpo.data_product_id,
+			COALESCE(u.id::text, t.id::text) as id,
+			u.username,
+			COALESCE(u.name, t.name) as name,
+			CASE WHEN u.id IS NOT NULL THEN 'user' ELSE 'team' END as type,
+			ui.provider_email,
+			u.profile_picture
+		FROM data_product_owners dpo
+		LEFT JOIN users u ON dpo.user_id = u.id
+		LEFT JOIN teams t ON dpo.team_id = t.id
+		LEFT JOIN user_identities ui ON u.id = ui.user_id
+		WHERE dpo.data_product_id = ANY($1)
+		ORDER BY dpo.data_product_id, type, COALESCE(u.username, t.name)`
+
+	rows, err := r.db.Query(ctx, q, dataProductIDs)
+	if err != nil {
+		return nil, fmt.Errorf("loading owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := make(map[string][]Owner, len(dataProductIDs))
+	for rows.Next() {
+		var dataProductID string
+		var owner Owner
+		if err := rows.Scan(&dataProductID, &owner.ID, &owner.Username, &owner.Name, &owner.Type, &owner.Email, &owner.ProfilePicture); err != nil {
+			return nil, fmt.Errorf("scanning owner: %w", err)
+		}
+		owners[dataProductID] = append(owners[dataProductID], owner)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating owners: %w", err)
+	}
+
+	return owners, nil
+}
+
 func (r *PostgresRepository) setOwners(ctx context.Context, tx pgx.Tx, dataProductID string, owners []OwnerInput) error {
 	_, err := tx.Exec(ctx, "DELETE FROM data_product_owners WHERE data_product_id = $1", dataProductID)
 	if err != nil {
@@ -253,7 +313,8 @@ func (r *PostgresRepository) setOwners(ctx context.Context, tx pgx.Tx, dataProdu
 func (r *PostgresRepository) loadRules(ctx context.Context, dataProductID string) ([]Rule, error) {
 	q := `
 		SELECT id, data_product_id, name, description, rule_type, query_expression,
-			   metadata_field, pattern_type, pattern_value, priority, is_enabled,
+			   metadata_field, pattern_type, pattern_value, lineage_asset_id,
+			   lineage_direction, lineage_depth, priority, is_enabled,
 			   created_at, updated_at
 		FROM data_product_rules
 		WHERE data_product_id = $1
@@ -271,7 +332,8 @@ func (r *PostgresRepository) loadRules(ctx context.Context, dataProductID string
 		if err := rows.Scan(
 			&rule.ID, &rule.DataProductID, &rule.Name, &rule.Description,
 			&rule.RuleType, &rule.QueryExpression, &rule.MetadataField,
-			&rule.PatternType, &rule.PatternValue, &rule.Priority,
+			&rule.PatternType, &rule.PatternValue, &rule.LineageAssetID,
+			&rule.LineageDirection, &rule.LineageDepth, &rule.Priority,
 			&rule.IsEnabled, &rule.CreatedAt, &rule.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning rule: %w", err)
@@ -301,6 +363,98 @@ func (r *PostgresRepository) getAssetCounts(ctx context.Context, dataProductID s
 	return manualCount, ruleCount, nil
 }
 
+// loadListDetails fills in the owners, asset counts and icon of a page of
+// data products with one query each, rather than three per product.
+func (r *PostgresRepository) loadListDetails(ctx context.Context, products []*DataProduct) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	for i, dp := range products {
+		ids[i] = dp.ID
+	}
+
+	owners, err := r.loadOwnersForProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]assetCounts, len(ids))
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			data_product_id,
+			COUNT(*) FILTER (WHERE source = 'manual'),
+			COUNT(*) FILTER (WHERE source = 'rule')
+		FROM data_product_memberships
+		WHERE data_product_id = ANY($1)
+		GROUP BY data_product_id`, ids)
+	if err != nil {
+		return fmt.Errorf("counting assets: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		var c assetCounts
+		if err := rows.Scan(&id, &c.manual, &c.rule); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning asset counts: %w", err)
+		}
+		counts[id] = c
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating asset counts: %w", err)
+	}
+
+	hasIcon := make(map[string]bool, len(ids))
+	rows, err = r.db.Query(ctx, `
+		SELECT data_product_id
+		FROM product_images
+		WHERE data_product_id = ANY($1) AND purpose = $2`, ids, ImagePurposeIcon)
+	if err != nil {
+		return fmt.Errorf("loading icons: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning icon: %w", err)
+		}
+		hasIcon[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating icons: %w", err)
+	}
+
+	applyListDetails(products, owners, counts, hasIcon)
+	return nil
+}
+
+// assetCounts holds a data product's manual and rule-matched member counts.
+type assetCounts struct{ manual, rule int }
+
+// applyListDetails sets the owners, asset counts and icon URL of each
+// product from the lookups loadListDetails makes for the whole page.
+// Products missing from a lookup get no owners, zero counts and no icon.
+func applyListDetails(products []*DataProduct, owners map[string][]Owner, counts map[string]assetCounts, hasIcon map[string]bool) {
+	for _, dp := range products {
+		dp.Owners = owners[dp.ID]
+		if dp.Owners == nil {
+			dp.Owners = []Owner{}
+		}
+
+		c := counts[dp.ID]
+		dp.ManualAssetCount, dp.RuleAssetCount = c.manual, c.rule
+		dp.AssetCount = c.manual + c.rule
+
+		if hasIcon[dp.ID] {
+			url := productImageURL(dp.ID, ImagePurposeIcon)
+			dp.IconURL = &url
+		}
+	}
+}
+
 func (r *PostgresRepository) Create(ctx context.Context, dp *DataProduct, owners []OwnerInput) error {
 	start := time.Now()
 
@@ -533,19 +687,6 @@ func (r *PostgresRepository) List(ctx context.Context, offset, limit int) (*List
 			return nil, fmt.Errorf("unmarshaling metadata: %w", err)
 		}
 
-		dp.Owners, err = r.loadOwners(ctx, dp.ID)
-		if err != nil {
-			r.recorder.RecordDBQuery(ctx, "dataproduct_list", time.Since(start), false)
-			return nil, fmt.Errorf("loading owners for %s: %w", dp.ID, err)
-		}
-
-		dp.ManualAssetCount, dp.RuleAssetCount, _ = r.getAssetCounts(ctx, dp.ID)
-		dp.AssetCount = dp.ManualAssetCount + dp.RuleAssetCount
-
-		if iconMeta, err := r.GetProductImageMeta(ctx, dp.ID, ImagePurposeIcon); err == nil {
-			dp.IconURL = &iconMeta.URL
-		}
-
 		products = append(products, &dp)
 	}
 
@@ -553,6 +694,12 @@ func (r *PostgresRepository) List(ctx context.Context, offset, limit int) (*List
 		r.recorder.RecordDBQuery(ctx, "dataproduct_list", time.Since(start), false)
 		return nil, fmt.Errorf("iterating data products: %w", err)
 	}
+	rows.Close()
+
+	if err := r.loadListDetails(ctx, products); err != nil {
+		r.recorder.RecordDBQuery(ctx, "dataproduct_list", time.Since(start), false)
+		return nil, err
+	}
 
 	r.recorder.RecordDBQuery(ctx, "dataproduct_list", time.Since(start), true)
 	return &ListResult{DataProducts: products, Total: total}, nil
@@ -632,19 +779,6 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter) (*
 			return nil, fmt.Errorf("unmarshaling metadata: %w", err)
 		}
 
-		dp.Owners, err = r.loadOwners(ctx, dp.ID)
-		if err != nil {
-			r.recorder.RecordDBQuery(ctx, "dataproduct_search", time.Since(start), false)
-			return nil, fmt.Errorf("loading owners for %s: %w", dp.ID, err)
-		}
-
-		dp.ManualAssetCount, dp.RuleAssetCount, _ = r.getAssetCounts(ctx, dp.ID)
-		dp.AssetCount = dp.ManualAssetCount + dp.RuleAssetCount
-
-		if iconMeta, err := r.GetProductImageMeta(ctx, dp.ID, ImagePurposeIcon); err == nil {
-			dp.IconURL = &iconMeta.URL
-		}
-
 		products = append(products, &dp)
 	}
 
@@ -652,6 +786,12 @@ func (r *PostgresRepository) Search(ctx context.Context, filter SearchFilter) (*
 		r.recorder.RecordDBQuery(ctx, "dataproduct_search", time.Since(start), false)
 		return nil, fmt.Errorf("iterating search results: %w", err)
 	}
+	rows.Close()
+
+	if err := r.loadListDetails(ctx, products); err != nil {
+		r.recorder.RecordDBQuery(ctx, "dataproduct_search", time.Since(start), false)
+		return nil, err
+	}
 
 	r.recorder.RecordDBQuery(ctx, "dataproduct_search", time.Since(start), true)
 	return &ListResult{DataProducts: products, Total: total}, nil
@@ -796,16 +936,18 @@ func (r *PostgresRepository) CreateRule(ctx context.Context, dataProductID strin
 	q := `
 		INSERT INTO data_product_rules (
 			data_product_id, name, description, rule_type, query_expression,
-			metadata_field, pattern_type, pattern_value, priority, is_enabled,
+			metadata_field, pattern_type, pattern_value, lineage_asset_id,
+			lineage_direction, lineage_depth, priority, is_enabled,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id`
 
 	now := time.Now().UTC()
 	var id string
 	err := r.db.QueryRow(ctx, q,
 		dataProductID, rule.Name, rule.Description, rule.RuleType, rule.QueryExpression,
-		rule.MetadataField, rule.PatternType, rule.PatternValue, rule.Priority, rule.IsEnabled,
+		rule.MetadataField, rule.PatternType, rule.PatternValue, rule.LineageAssetID,
+		rule.LineageDirection, rule.LineageDepth, rule.Priority, rule.IsEnabled,
 		now, now,
 	).Scan(&id)
 
@@ -827,12 +969,14 @@ func (r *PostgresRepository) UpdateRule(ctx context.Context, ruleID string, rule
 		UPDATE data_product_rules
 		SET name = $1, description = $2, rule_type = $3, query_expression = $4,
 			metadata_field = $5, pattern_type = $6, pattern_value = $7,
-			priority = $8, is_enabled = $9, updated_at = $10
-		WHERE id = $11`
+			lineage_asset_id = $8, lineage_direction = $9, lineage_depth = $10,
+			priority = $11, is_enabled = $12, updated_at = $13
+		WHERE id = $14`
 
 	result, err := r.db.Exec(ctx, q,
 		rule.Name, rule.Description, rule.RuleType, rule.QueryExpression,
 		rule.MetadataField, rule.PatternType, rule.PatternValue,
+		rule.LineageAssetID, rule.LineageDirection, rule.LineageDepth,
 		rule.Priority, rule.IsEnabled, time.Now().UTC(), ruleID,
 	)
 
@@ -882,7 +1026,8 @@ func (r *PostgresRepository) GetRule(ctx context.Context, ruleID string) (*Rule,
 
 	q := `
 		SELECT id, data_product_id, name, description, rule_type, query_expression,
-			   metadata_field, pattern_type, pattern_value, priority, is_enabled,
+			   metadata_field, pattern_type, pattern_value, lineage_asset_id,
+			   lineage_direction, lineage_depth, priority, is_enabled,
 			   created_at, updated_at
 		FROM data_product_rules
 		WHERE id = $1`
@@ -891,7 +1036,8 @@ func (r *PostgresRepository) GetRule(ctx context.Context, ruleID string) (*Rule,
 	err := r.db.QueryRow(ctx, q, ruleID).Scan(
 		&rule.ID, &rule.DataProductID, &rule.Name, &rule.Description,
 		&rule.RuleType, &rule.QueryExpression, &rule.MetadataField,
-		&rule.PatternType, &rule.PatternValue, &rule.Priority,
+		&rule.PatternType, &rule.PatternValue, &rule.LineageAssetID,
+		&rule.LineageDirection, &rule.LineageDepth, &rule.Priority,
 		&rule.IsEnabled, &rule.CreatedAt, &rule.UpdatedAt,
 	)
 
@@ -983,6 +1129,10 @@ func (r *PostgresRepository) ExecuteRule(ctx context.Context, rule *Rule) ([]str
 		assetIDs, err = r.executeQueryRule(ctx, *rule.QueryExpression)
 	case rule.RuleType == RuleTypeMetadataMatch:
 		assetIDs, err = r.executeMetadataMatchRule(ctx, rule)
+	case rule.RuleType == RuleTypeLineage:
+		// Lineage rules are resolved by the membership service through the
+		// lineage service rather than with a direct query.
+		return nil, ErrLineageRuleUnsupported
 	default:
 		return nil, fmt.Errorf("unsupported rule type: %s", rule.RuleType)
 	}
@@ -1012,14 +1162,14 @@ func (r *PostgresRepository) executeQueryRule(ctx context.Context, queryExpressi
 	// We need to inject it into the CTE before the closing paren
 	sqlQuery = strings.Replace(sqlQuery,
 		") SELECT * FROM search_results",
-		" AND is_stub = FALSE) SELECT id, search_rank FROM search_results",
+		" AND is_stub = FALSE AND archived_at IS NULL) SELECT id, search_rank FROM search_results",
 		1)
 
 	// If there was no WHERE clause added by BuildSQL, we need to add WHERE instead of AND
 	if !strings.Contains(sqlQuery, "WHERE") {
 		sqlQuery = strings.Replace(sqlQuery,
-			" AND is_stub = FALSE)",
-			" WHERE is_stub = FALSE)",
+			" AND is_stub = FALSE AND archived_at IS NULL)",
+			" WHERE is_stub = FALSE AND archived_at IS NULL)",
 			1)
 	}
 
@@ -1102,7 +1252,7 @@ func (r *PostgresRepository) executeMetadataMatchRule(ctx context.Context, rule
 		return nil, fmt.Errorf("unsupported pattern type: %s", *rule.PatternType)
 	}
 
-	q := fmt.Sprintf("SELECT id FROM assets WHERE is_stub = FALSE AND %s", condition)
+	q := fmt.Sprintf("SELECT id FROM assets WHERE is_stub = FALSE AND archived_at IS NULL AND %s", condition)
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -1199,12 +1349,6 @@ func (r *PostgresRepository) GetDataProductsForAsset(ctx context.Context, assetI
 			return nil, fmt.Errorf("unmarshaling metadata: %w", err)
 		}
 
-		dp.Owners, err = r.loadOwners(ctx, dp.ID)
-		if err != nil {
-			r.recorder.RecordDBQuery(ctx, "dataproduct_get_for_asset", time.Since(start), false)
-			return nil, fmt.Errorf("loading owners for %s: %w", dp.ID, err)
-		}
-
 		products = append(products, &dp)
 	}
 
@@ -1212,6 +1356,25 @@ func (r *PostgresRepository) GetDataProductsForAsset(ctx context.Context, assetI
 		r.recorder.RecordDBQuery(ctx, "dataproduct_get_for_asset", time.Since(start), false)
 		return nil, fmt.Errorf("iterating data products: %w", err)
 	}
+	rows.Close()
+
+	if len(products) > 0 {
+		ids := make([]string, len(products))
+		for i, dp := range products {
+			ids[i] = dp.ID
+		}
+		owners, err := r.loadOwnersForProducts(ctx, ids)
+		if err != nil {
+			r.recorder.RecordDBQuery(ctx, "dataproduct_get_for_asset", time.Since(start), false)
+			return nil, err
+		}
+		for _, dp := range products {
+			dp.Owners = owners[dp.ID]
+			if dp.Owners == nil {
+				dp.Owners = []Owner{}
+			}
+		}
+	}
 
 	r.recorder.RecordDBQuery(ctx, "dataproduct_get_for_asset", time.Since(start), true)
 	return products, nil
@@ -1358,6 +1521,12 @@ func (r *PostgresRepository) GetProductImageByPurpose(ctx context.Context, dataP
 	return &image, nil
 }
 
+// productImageURL is where an image is served from. The URL only depends on
+// the product and purpose, so it can be built without loading the image.
+func productImageURL(dataProductID string, purpose ImagePurpose) string {
+	return fmt.Sprintf("/api/v1/products/images/%s/%s", dataProductID, purpose)
+}
+
 func (r *PostgresRepository) GetProductImageMeta(ctx context.Context, dataProductID string, purpose ImagePurpose) (*ProductImageMeta, error) {
 	start := time.Now()
 
@@ -1383,7 +1552,7 @@ func (r *PostgresRepository) GetProductImageMeta(ctx context.Context, dataProduc
 		return nil, fmt.Errorf("getting image metadata: %w", err)
 	}
 
-	meta.URL = fmt.Sprintf("/api/v1/products/images/%s/%s", meta.DataProductID, meta.Purpose)
+	meta.URL = productImageURL(meta.DataProductID, meta.Purpose)
 	r.recorder.RecordDBQuery(ctx, "dataproduct_get_image_meta", duration, true)
 	return &meta, nil
 }
@@ -1437,7 +1606,7 @@ func (r *PostgresRepository) ListProductImages(ctx context.Context, dataProductI
 			r.recorder.RecordDBQuery(ctx, "dataproduct_list_images", time.Since(start), false)
 			return nil, fmt.Errorf("scanning image: %w", err)
 		}
-		meta.URL = fmt.Sprintf("/api/v1/products/images/%s/%s", meta.DataProductID, meta.Purpose)
+		meta.URL = productImageURL(meta.DataProductID, meta.Purpose)
 		images = append(images, &meta)
 	}
 
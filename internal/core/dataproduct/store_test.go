@@ -0,0 +1,58 @@
+package dataproduct
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyListDetails(t *testing.T) {
+	products := []*DataProduct{{ID: "orders"}, {ID: "payments"}, {ID: "empty"}}
+	owners := map[string][]Owner{
+		"orders":   {{ID: "u1", Name: "Alice", Type: "user"}, {ID: "t1", Name: "Data", Type: "team"}},
+		"payments": {{ID: "t1", Name: "Data", Type: "team"}},
+	}
+	counts := map[string]assetCounts{
+		"orders":   {manual: 2, rule: 3},
+		"payments": {rule: 4},
+	}
+	hasIcon := map[string]bool{"payments": true}
+
+	applyListDetails(products, owners, counts, hasIcon)
+
+	tests := []struct {
+		product    *DataProduct
+		wantOwners []string
+		wantManual int
+		wantRule   int
+		wantIcon   string
+	}{
+		{product: products[0], wantOwners: []string{"u1", "t1"}, wantManual: 2, wantRule: 3},
+		{product: products[1], wantOwners: []string{"t1"}, wantRule: 4, wantIcon: "/api/v1/products/images/payments/icon"},
+		{product: products[2], wantOwners: []string{}},
+	}
+
+	for _, tt := range tests {
+		dp := tt.product
+		ownerIDs := []string{}
+		for _, o := range dp.Owners {
+			ownerIDs = append(ownerIDs, o.ID)
+		}
+		if dp.Owners == nil {
+			t.Errorf("%s: owners are nil, want an empty list", dp.ID)
+		}
+		if !reflect.DeepEqual(ownerIDs, tt.wantOwners) {
+			t.Errorf("%s: owners = %v, want %v", dp.ID, ownerIDs, tt.wantOwners)
+		}
+		if dp.ManualAssetCount != tt.wantManual || dp.RuleAssetCount != tt.wantRule || dp.AssetCount != tt.wantManual+tt.wantRule {
+			t.Errorf("%s: counts = %d manual, %d rule, %d total, want %d, %d, %d", dp.ID,
+				dp.ManualAssetCount, dp.RuleAssetCount, dp.AssetCount, tt.wantManual, tt.wantRule, tt.wantManual+tt.wantRule)
+		}
+		var icon string
+		if dp.IconURL != nil {
+			icon = *dp.IconURL
+		}
+		if icon != tt.wantIcon {
+			t.Errorf("%s: icon = %q, want %q", dp.ID, icon, tt.wantIcon)
+		}
+	}
+}
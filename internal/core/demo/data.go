@@ -0,0 +1,297 @@
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marmotdata/marmot/internal/core/dataproduct"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+// The demo catalog models a small online shop: an operational PostgreSQL
+// database streams changes through Kafka into an S3 landing bucket, an
+// Airflow DAG loads them into the warehouse and dbt builds the marts.
+
+var (
+	customersMRN     = mrn.New("Table", "PostgreSQL", "shop.public.customers")
+	ordersMRN        = mrn.New("Table", "PostgreSQL", "shop.public.orders")
+	orderItemsMRN    = mrn.New("Table", "PostgreSQL", "shop.public.order_items")
+	productsMRN      = mrn.New("Table", "PostgreSQL", "shop.public.products")
+	ordersTopicMRN   = mrn.New("Topic", "Kafka", "shop.orders.created")
+	customerTopicMRN = mrn.New("Topic", "Kafka", "shop.customers.updated")
+	landingMRN       = mrn.New("Bucket", "S3", "acme-landing")
+	dagMRN           = mrn.New("Pipeline", "Airflow", "load_shop_events")
+	stgOrdersMRN     = mrn.New("Model", "DBT", "analytics.staging.stg_orders")
+	stgCustomersMRN  = mrn.New("Model", "DBT", "analytics.staging.stg_customers")
+	fctOrdersMRN     = mrn.New("Model", "DBT", "analytics.marts.fct_orders")
+	dimCustomersMRN  = mrn.New("Model", "DBT", "analytics.marts.dim_customers")
+)
+
+type column struct {
+	Name     string `json:"column_name"`
+	DataType string `json:"data_type"`
+	Nullable bool   `json:"is_nullable"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// source is one demo ingestion source, seeded as a run of the demo pipeline.
+type source struct {
+	name    string
+	assets  []runs.CreateAssetInput
+	lineage []runs.LineageInput
+	docs    []runs.DocumentationInput
+	stats   []runs.StatisticInput
+	// failure, when set, records a failed run before the successful one so
+	// run history shows both outcomes.
+	failure string
+}
+
+func table(assetType, provider, database, schema, name, description, domain string, tags []string, cols ...column) runs.CreateAssetInput {
+	colsJSON, _ := json.Marshal(cols)
+	id := mrn.New(assetType, provider, fmt.Sprintf("%s.%s.%s", database, schema, name))
+	return runs.CreateAssetInput{
+		Name:        name,
+		MRN:         &id,
+		Type:        assetType,
+		Providers:   []string{provider},
+		Description: &description,
+		Metadata: map[string]interface{}{
+			"database":   database,
+			"schema":     schema,
+			"table_name": name,
+			"domain":     domain,
+		},
+		Schema:  map[string]interface{}{"columns": string(colsJSON)},
+		Tags:    append([]string{"demo"}, tags...),
+		Sources: []string{provider},
+	}
+}
+
+func topic(name, description, domain, valueSchema string) runs.CreateAssetInput {
+	id := mrn.New("Topic", "Kafka", name)
+	return runs.CreateAssetInput{
+		Name:        name,
+		MRN:         &id,
+		Type:        "Topic",
+		Providers:   []string{"Kafka"},
+		Description: &description,
+		Metadata: map[string]interface{}{
+			"partitions":         6,
+			"replication_factor": 3,
+			"retention_ms":       "604800000",
+			"cleanup_policy":     "delete",
+			"domain":             domain,
+		},
+		Schema:  map[string]interface{}{"value": valueSchema},
+		Tags:    []string{"demo", "streaming"},
+		Sources: []string{"Kafka"},
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func sources() []source {
+	dagQuery := "COPY INTO analytics.raw.shop_events FROM @landing/shop/ FILE_FORMAT = (TYPE = JSON)"
+
+	return []source{
+		{
+			name: "shop-postgres",
+			assets: []runs.CreateAssetInput{
+				table("Table", "PostgreSQL", "shop", "public", "customers", "Registered customers of the online shop.", "customers",
+					[]string{"pii"},
+					column{"id", "bigint", false, "Primary key"},
+					column{"email", "text", false, "Login email address"},
+					column{"full_name", "text", true, ""},
+					column{"country", "char(2)", true, "ISO 3166-1 alpha-2"},
+					column{"created_at", "timestamptz", false, ""},
+				),
+				table("Table", "PostgreSQL", "shop", "public", "orders", "One row per checkout.", "orders", nil,
+					column{"id", "bigint", false, "Primary key"},
+					column{"customer_id", "bigint", false, "References customers.id"},
+					column{"status", "text", false, "pending, paid, shipped or refunded"},
+					column{"total_amount", "numeric(12,2)", false, "Gross order value in EUR"},
+					column{"created_at", "timestamptz", false, ""},
+				),
+				table("Table", "PostgreSQL", "shop", "public", "order_items", "Line items of each order.", "orders", nil,
+					column{"id", "bigint", false, "Primary key"},
+					column{"order_id", "bigint", false, "References orders.id"},
+					column{"product_id", "bigint", false, "References products.id"},
+					column{"quantity", "integer", false, ""},
+					column{"unit_price", "numeric(12,2)", false, ""},
+				),
+				table("Table", "PostgreSQL", "shop", "public", "products", "Product catalog.", "orders", nil,
+					column{"id", "bigint", false, "Primary key"},
+					column{"sku", "text", false, "Stock keeping unit"},
+					column{"name", "text", false, ""},
+					column{"category", "text", true, ""},
+					column{"price", "numeric(12,2)", false, "List price in EUR"},
+				),
+			},
+			lineage: []runs.LineageInput{
+				{Source: customersMRN, Target: ordersMRN, Type: "FOREIGN_KEY"},
+				{Source: ordersMRN, Target: orderItemsMRN, Type: "FOREIGN_KEY"},
+				{Source: productsMRN, Target: orderItemsMRN, Type: "FOREIGN_KEY"},
+			},
+			docs: []runs.DocumentationInput{
+				{
+					AssetMRN: customersMRN,
+					Type:     "markdown",
+					Content: "## Customers\n\nThe system of record for customer accounts. " +
+						"Rows are never deleted; closed accounts keep their history for seven years.\n\n" +
+						"Contains **personal data**. Access is restricted to the customer domain team.",
+				},
+			},
+			stats: []runs.StatisticInput{
+				{AssetMRN: customersMRN, MetricName: "asset.row_count", Value: 184_302},
+				{AssetMRN: ordersMRN, MetricName: "asset.row_count", Value: 1_204_877},
+				{AssetMRN: orderItemsMRN, MetricName: "asset.row_count", Value: 3_512_940},
+				{AssetMRN: productsMRN, MetricName: "asset.row_count", Value: 4_812},
+			},
+		},
+		{
+			name:    "events-kafka",
+			failure: "connecting to broker kafka-1:9092: dial tcp 10.0.4.12:9092: connect: connection refused",
+			assets: []runs.CreateAssetInput{
+				topic("shop.orders.created", "Emitted by the shop when an order is paid.", "orders",
+					`{"type":"object","properties":{"order_id":{"type":"integer"},"customer_id":{"type":"integer"},"total_amount":{"type":"number"},"created_at":{"type":"string","format":"date-time"}},"required":["order_id","customer_id"]}`),
+				topic("shop.customers.updated", "Change events for customer profiles.", "customers",
+					`{"type":"object","properties":{"customer_id":{"type":"integer"},"email":{"type":"string","format":"email"},"country":{"type":"string"}},"required":["customer_id"]}`),
+			},
+			lineage: []runs.LineageInput{
+				{Source: ordersMRN, Target: ordersTopicMRN, Type: "FEEDS"},
+				{Source: customersMRN, Target: customerTopicMRN, Type: "FEEDS"},
+			},
+		},
+		{
+			name: "landing-s3",
+			assets: []runs.CreateAssetInput{{
+				Name:        "acme-landing",
+				MRN:         ptr(landingMRN),
+				Type:        "Bucket",
+				Providers:   []string{"S3"},
+				Description: ptr("Raw event landing zone, partitioned by topic and date."),
+				Metadata: map[string]interface{}{
+					"region":     "eu-west-1",
+					"versioning": "Enabled",
+					"encryption": "aws:kms",
+				},
+				Tags:    []string{"demo", "raw"},
+				Sources: []string{"S3"},
+			}},
+			lineage: []runs.LineageInput{
+				{Source: ordersTopicMRN, Target: landingMRN, Type: "FEEDS"},
+				{Source: customerTopicMRN, Target: landingMRN, Type: "FEEDS"},
+			},
+		},
+		{
+			name: "orchestration-airflow",
+			assets: []runs.CreateAssetInput{{
+				Name:          "load_shop_events",
+				MRN:           ptr(dagMRN),
+				Type:          "Pipeline",
+				Providers:     []string{"Airflow"},
+				Description:   ptr("Hourly load of landed shop events into the warehouse."),
+				Metadata:      map[string]interface{}{"schedule_interval": "@hourly", "owners": "data-platform", "is_paused": false},
+				Tags:          []string{"demo"},
+				Sources:       []string{"Airflow"},
+				Query:         &dagQuery,
+				QueryLanguage: ptr("sql"),
+			}},
+			lineage: []runs.LineageInput{
+				{Source: landingMRN, Target: dagMRN, Type: "FEEDS"},
+				{Source: dagMRN, Target: stgOrdersMRN, Type: "PRODUCES"},
+				{Source: dagMRN, Target: stgCustomersMRN, Type: "PRODUCES"},
+			},
+		},
+		{
+			name: "warehouse-dbt",
+			assets: []runs.CreateAssetInput{
+				table("Model", "DBT", "analytics", "staging", "stg_orders", "Orders cleaned and typed from raw events.", "orders", nil,
+					column{"order_id", "number", false, ""},
+					column{"customer_id", "number", false, ""},
+					column{"order_total", "number(12,2)", false, ""},
+					column{"ordered_at", "timestamp_ntz", false, ""},
+				),
+				table("Model", "DBT", "analytics", "staging", "stg_customers", "Latest profile per customer.", "customers", []string{"pii"},
+					column{"customer_id", "number", false, ""},
+					column{"email", "varchar", false, ""},
+					column{"country", "varchar", true, ""},
+				),
+				table("Model", "DBT", "analytics", "marts", "fct_orders", "Order facts with net revenue, one row per order.", "orders", []string{"gold"},
+					column{"order_id", "number", false, ""},
+					column{"customer_id", "number", false, ""},
+					column{"gross_revenue", "number(12,2)", false, ""},
+					column{"net_revenue", "number(12,2)", false, "Gross revenue less refunds and VAT"},
+					column{"ordered_at", "timestamp_ntz", false, ""},
+				),
+				table("Model", "DBT", "analytics", "marts", "dim_customers", "Customer dimension with lifetime value.", "customers", []string{"gold"},
+					column{"customer_id", "number", false, ""},
+					column{"country", "varchar", true, ""},
+					column{"first_order_at", "timestamp_ntz", true, ""},
+					column{"lifetime_value", "number(14,2)", false, ""},
+				),
+			},
+			lineage: []runs.LineageInput{
+				{Source: stgOrdersMRN, Target: fctOrdersMRN, Type: "DEPENDS_ON"},
+				{Source: stgCustomersMRN, Target: dimCustomersMRN, Type: "DEPENDS_ON"},
+				{Source: fctOrdersMRN, Target: dimCustomersMRN, Type: "DEPENDS_ON"},
+			},
+			docs: []runs.DocumentationInput{
+				{
+					AssetMRN: fctOrdersMRN,
+					Type:     "markdown",
+					Content: "## fct_orders\n\nThe reporting source for revenue. `net_revenue` excludes VAT and " +
+						"refunds issued within 30 days, matching the finance definition of **Net Revenue**.\n\n" +
+						"Refreshed hourly after `load_shop_events` completes.",
+				},
+			},
+			stats: []runs.StatisticInput{
+				{AssetMRN: fctOrdersMRN, MetricName: "asset.row_count", Value: 1_198_410},
+				{AssetMRN: dimCustomersMRN, MetricName: "asset.row_count", Value: 183_977},
+			},
+		},
+	}
+}
+
+// term is a demo glossary term. Children reference their parent by name.
+type term struct {
+	name       string
+	definition string
+	parent     string
+	tags       []string
+}
+
+var terms = []term{
+	{name: "Customer", definition: "A person or organisation with a registered account who has placed at least one order."},
+	{name: "Order", definition: "A confirmed checkout containing one or more line items."},
+	{name: "Revenue", definition: "Income from orders, reported in EUR."},
+	{name: "Net Revenue", parent: "Revenue", definition: "Gross order value less VAT and refunds issued within 30 days of the order."},
+	{name: "Personal Data", definition: "Any information relating to an identified or identifiable person, as defined by GDPR Article 4.", tags: []string{"compliance"}},
+}
+
+// product is a demo data product, populated by rules or by explicit assets.
+type product struct {
+	name        string
+	description string
+	rules       []dataproduct.RuleInput
+	assets      []string
+}
+
+var products = []product{
+	{
+		name:        "Order Analytics",
+		description: "Everything needed to report on orders and revenue, from the source tables to the marts.",
+		rules: []dataproduct.RuleInput{{
+			Name:            "Orders domain",
+			RuleType:        dataproduct.RuleTypeQuery,
+			QueryExpression: ptr(`@metadata.domain: "orders"`),
+			IsEnabled:       true,
+		}},
+	},
+	{
+		name:        "Customer 360",
+		description: "A single view of the customer across operational and analytical systems.",
+		assets:      []string{customersMRN, customerTopicMRN, stgCustomersMRN, dimCustomersMRN},
+	},
+}
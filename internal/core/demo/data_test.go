@@ -0,0 +1,47 @@
+package demo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemoDataIsConsistent(t *testing.T) {
+	seeded := map[string]bool{}
+	for _, src := range sources() {
+		for _, a := range src.assets {
+			require.NotNil(t, a.MRN, a.Name)
+			assert.False(t, seeded[*a.MRN], "duplicate asset %s", *a.MRN)
+			seeded[*a.MRN] = true
+		}
+	}
+
+	for _, src := range sources() {
+		for _, l := range src.lineage {
+			assert.True(t, seeded[l.Source], "lineage source %s is not seeded", l.Source)
+			assert.True(t, seeded[l.Target], "lineage target %s is not seeded", l.Target)
+		}
+		for _, d := range src.docs {
+			assert.True(t, seeded[d.AssetMRN], "documented asset %s is not seeded", d.AssetMRN)
+		}
+		for _, s := range src.stats {
+			assert.True(t, seeded[s.AssetMRN], "statistic for %s is not seeded", s.AssetMRN)
+		}
+	}
+	assert.True(t, seeded[dagMRN])
+
+	for _, p := range products {
+		for _, m := range p.assets {
+			assert.True(t, seeded[m], "%s references unseeded asset %s", p.name, m)
+		}
+	}
+
+	defined := map[string]bool{}
+	for _, term := range terms {
+		if term.parent != "" {
+			assert.True(t, defined[term.parent], "%s must come after its parent %s", term.name, term.parent)
+		}
+		defined[term.name] = true
+	}
+}
@@ -0,0 +1,239 @@
+// Package demo seeds a realistic example catalog so Marmot can be explored
+// without connecting real sources.
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/dataproduct"
+	"github.com/marmotdata/marmot/internal/core/glossary"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/plugin"
+)
+
+// Pipeline is the pipeline name demo runs are recorded under. Destroying it
+// removes the demo assets, lineage and documentation.
+const Pipeline = "marmot-demo"
+
+// Result counts what a seed created. Seeding is idempotent, so a repeated
+// seed reports zero for entities that already existed.
+type Result struct {
+	Assets        int `json:"assets"`
+	Lineage       int `json:"lineage"`
+	Documentation int `json:"documentation"`
+	GlossaryTerms int `json:"glossary_terms"`
+	DataProducts  int `json:"data_products"`
+	Runs          int `json:"runs"`
+} // @name DemoSeedResult
+
+type Seeder struct {
+	runService         runs.Service
+	assetService       asset.Service
+	glossaryService    glossary.Service
+	dataProductService dataproduct.Service
+}
+
+func NewSeeder(runService runs.Service, assetService asset.Service, glossaryService glossary.Service, dataProductService dataproduct.Service) *Seeder {
+	return &Seeder{
+		runService:         runService,
+		assetService:       assetService,
+		glossaryService:    glossaryService,
+		dataProductService: dataProductService,
+	}
+}
+
+// Seed populates the demo catalog. Glossary terms and data products are owned
+// by ownerID, and runs are recorded as created by createdBy.
+func (s *Seeder) Seed(ctx context.Context, ownerID, createdBy string) (*Result, error) {
+	_, total, err := s.runService.ListRuns(ctx, Pipeline, 1, 0)
+	if err != nil {
+		return nil, fmt.Errorf("checking for previous demo runs: %w", err)
+	}
+	firstSeed := total == 0
+
+	result := &Result{}
+	for _, src := range sources() {
+		if err := s.seedSource(ctx, src, createdBy, firstSeed, result); err != nil {
+			return nil, fmt.Errorf("seeding %s: %w", src.name, err)
+		}
+	}
+
+	if firstSeed {
+		if err := s.seedJobRuns(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.seedGlossary(ctx, ownerID, result); err != nil {
+		return nil, err
+	}
+	if err := s.seedDataProducts(ctx, ownerID, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Seeder) seedSource(ctx context.Context, src source, createdBy string, firstSeed bool, result *Result) error {
+	if firstSeed && src.failure != "" {
+		run, err := s.runService.StartRun(ctx, Pipeline, src.name, createdBy, plugin.RawPluginConfig{})
+		if err != nil {
+			return err
+		}
+		if err := s.runService.CompleteRun(ctx, run.RunID, plugin.StatusFailed, &plugin.RunSummary{ErrorsCount: 1}, src.failure); err != nil {
+			return err
+		}
+		result.Runs++
+	}
+
+	run, err := s.runService.StartRun(ctx, Pipeline, src.name, createdBy, plugin.RawPluginConfig{})
+	if err != nil {
+		return err
+	}
+	result.Runs++
+
+	resp, err := s.runService.ProcessEntities(ctx, run.RunID, src.assets, src.lineage, src.docs, src.stats, Pipeline, src.name)
+	if err != nil {
+		_ = s.runService.CompleteRun(ctx, run.RunID, plugin.StatusFailed, &plugin.RunSummary{}, err.Error())
+		return err
+	}
+
+	summary := summarize(resp.Summary)
+	result.Assets += summary.AssetsCreated
+	result.Lineage += summary.LineageCreated
+	result.Documentation += summary.DocumentationAdded
+
+	return s.runService.CompleteRun(ctx, run.RunID, plugin.StatusCompleted, summary, "")
+}
+
+func summarize(entities plugin.EntitySummary) *plugin.RunSummary {
+	summary := &plugin.RunSummary{Entities: entities}
+	for entityType, c := range entities {
+		summary.ErrorsCount += c.Failed
+		summary.TotalEntities += c.Created + c.Updated + c.Unchanged + c.Failed
+		switch entityType {
+		case "asset":
+			summary.AssetsCreated = c.Created
+			summary.AssetsUpdated = c.Updated
+			summary.AssetsDeleted = c.Deleted
+		case "lineage":
+			summary.LineageCreated = c.Created
+			summary.LineageUpdated = c.Updated
+		case "documentation":
+			summary.DocumentationAdded = c.Created
+		}
+	}
+	return summary
+}
+
+// seedJobRuns records a week of daily DAG runs, including one failure,
+// so the Airflow pipeline has run history to show.
+func (s *Seeder) seedJobRuns(ctx context.Context) error {
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	var history []runs.RunHistoryInput
+	for i := 7; i >= 1; i-- {
+		start := day.Add(-time.Duration(i) * 24 * time.Hour).Add(2 * time.Hour)
+		runID := "scheduled__" + start.Format(time.RFC3339)
+		outcome, duration := "COMPLETE", 11*time.Minute
+		if i == 3 {
+			outcome, duration = "FAIL", 4*time.Minute
+		}
+		facets := map[string]interface{}{"run_type": "scheduled", "dag_run_id": runID, "dag_id": "load_shop_events"}
+		history = append(history,
+			runs.RunHistoryInput{AssetMRN: dagMRN, RunID: runID, JobNamespace: "airflow", JobName: "load_shop_events", EventType: "START", EventTime: start, RunFacets: facets},
+			runs.RunHistoryInput{AssetMRN: dagMRN, RunID: runID, JobNamespace: "airflow", JobName: "load_shop_events", EventType: outcome, EventTime: start.Add(duration), RunFacets: facets},
+		)
+	}
+
+	if _, err := s.runService.ProcessRunHistory(ctx, history); err != nil {
+		return fmt.Errorf("seeding job runs: %w", err)
+	}
+	return nil
+}
+
+func (s *Seeder) seedGlossary(ctx context.Context, ownerID string, result *Result) error {
+	owners := []glossary.OwnerInput{{ID: ownerID, Type: "user"}}
+	ids := map[string]string{}
+
+	for _, t := range terms {
+		input := glossary.CreateTermInput{
+			Name:       t.name,
+			Definition: t.definition,
+			Owners:     owners,
+			Tags:       append([]string{"demo"}, t.tags...),
+		}
+		if t.parent != "" {
+			parentID := ids[t.parent]
+			input.ParentTermID = &parentID
+		}
+
+		created, err := s.glossaryService.Create(ctx, input)
+		if errors.Is(err, glossary.ErrConflict) {
+			existing, err := s.findTerm(ctx, t.name)
+			if err != nil {
+				return err
+			}
+			ids[t.name] = existing
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("creating glossary term %q: %w", t.name, err)
+		}
+		ids[t.name] = created.ID
+		result.GlossaryTerms++
+	}
+	return nil
+}
+
+func (s *Seeder) findTerm(ctx context.Context, name string) (string, error) {
+	found, err := s.glossaryService.Search(ctx, glossary.SearchFilter{Query: name, Limit: 100})
+	if err != nil {
+		return "", fmt.Errorf("finding glossary term %q: %w", name, err)
+	}
+	for _, term := range found.Terms {
+		if term.Name == name {
+			return term.ID, nil
+		}
+	}
+	return "", fmt.Errorf("glossary term %q exists but could not be found", name)
+}
+
+func (s *Seeder) seedDataProducts(ctx context.Context, ownerID string, result *Result) error {
+	for _, p := range products {
+		description := p.description
+		dp, err := s.dataProductService.Create(ctx, dataproduct.CreateInput{
+			Name:        p.name,
+			Description: &description,
+			Tags:        []string{"demo"},
+			Owners:      []dataproduct.OwnerInput{{ID: ownerID, Type: "user"}},
+			Rules:       p.rules,
+		})
+		if errors.Is(err, dataproduct.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("creating data product %q: %w", p.name, err)
+		}
+		result.DataProducts++
+
+		if len(p.assets) == 0 {
+			continue
+		}
+		assets, err := s.assetService.GetByMRNs(ctx, p.assets)
+		if err != nil {
+			return fmt.Errorf("resolving assets for %q: %w", p.name, err)
+		}
+		ids := make([]string, 0, len(assets))
+		for _, a := range assets {
+			ids = append(ids, a.ID)
+		}
+		if err := s.dataProductService.AddAssets(ctx, dp.ID, ids, ownerID); err != nil {
+			return fmt.Errorf("adding assets to %q: %w", p.name, err)
+		}
+	}
+	return nil
+}
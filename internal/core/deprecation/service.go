@@ -0,0 +1,284 @@
+package deprecation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound            = errors.New("asset is not deprecated")
+	ErrAssetNotFound       = errors.New("asset not found")
+	ErrReplacementNotFound = errors.New("replacement asset not found")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxReasonLength = 2000
+
+	// HistoryDays bounds how many daily snapshots a consumers report returns.
+	HistoryDays = 90
+)
+
+// AssetRef identifies an asset in deprecation responses.
+type AssetRef struct {
+	ID   string `json:"id"`
+	MRN  string `json:"mrn"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+} // @name DeprecationAssetRef
+
+// Deprecation marks an asset as deprecated, optionally naming the asset
+// consumers should move to.
+type Deprecation struct {
+	Asset       AssetRef   `json:"asset"`
+	Replacement *AssetRef  `json:"replacement,omitempty"`
+	Reason      string     `json:"reason"`
+	SunsetAt    *time.Time `json:"sunset_at,omitempty"`
+	// Consumers counts the assets that currently read the deprecated asset
+	// directly.
+	Consumers    int       `json:"consumers"`
+	DeprecatedBy *string   `json:"deprecated_by,omitempty"`
+	DeprecatedAt time.Time `json:"deprecated_at"`
+} // @name AssetDeprecation
+
+type DeprecateInput struct {
+	ReplacementAssetID *string    `json:"replacement_asset_id,omitempty"`
+	Reason             string     `json:"reason"`
+	SunsetAt           *time.Time `json:"sunset_at,omitempty"`
+} // @name DeprecateAssetInput
+
+type ListResult struct {
+	Deprecations []*Deprecation `json:"deprecations"`
+	Total        int            `json:"total"`
+} // @name AssetDeprecationList
+
+// Consumer is an asset that reads, or used to read, a deprecated asset
+// directly.
+type Consumer struct {
+	// AssetID is empty when the consumer has since been deleted.
+	AssetID   string   `json:"asset_id,omitempty"`
+	MRN       string   `json:"mrn"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+	// ReadsDeprecated is true while lineage still has an edge from the
+	// deprecated asset to the consumer.
+	ReadsDeprecated bool `json:"reads_deprecated"`
+	// ReadsReplacement is true once lineage has an edge from the
+	// replacement to the consumer.
+	ReadsReplacement bool      `json:"reads_replacement"`
+	FirstSeenAt      time.Time `json:"first_seen_at"`
+} // @name DeprecationConsumer
+
+// Snapshot counts a deprecated asset's consumers on one day.
+type Snapshot struct {
+	RecordedOn time.Time `json:"recorded_on"`
+	// Remaining consumers still read the deprecated asset.
+	Remaining int `json:"remaining"`
+	// Dual counts the remaining consumers that already read the
+	// replacement as well.
+	Dual int `json:"dual"`
+	// Migrated consumers no longer read the deprecated asset.
+	Migrated int `json:"migrated"`
+} // @name DeprecationSnapshot
+
+// ConsumersReport lists who still has to move off a deprecated asset and how
+// the migration has progressed.
+type ConsumersReport struct {
+	Deprecation *Deprecation `json:"deprecation"`
+	Remaining   []Consumer   `json:"remaining"`
+	Migrated    []Consumer   `json:"migrated"`
+	Progress    Snapshot     `json:"progress"`
+	// History holds one snapshot per day, oldest first.
+	History []Snapshot `json:"history"`
+} // @name DeprecationConsumersReport
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Deprecate marks an asset as deprecated, replacing any earlier deprecation
+// of the same asset, and records its current consumers.
+func (s *Service) Deprecate(ctx context.Context, assetID string, input DeprecateInput, userID string) (*Deprecation, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if len(input.Reason) > maxReasonLength {
+		return nil, &ValidationError{Message: "reason must be 2000 characters or fewer"}
+	}
+	if input.ReplacementAssetID != nil {
+		replacement := strings.TrimSpace(*input.ReplacementAssetID)
+		if replacement == "" {
+			input.ReplacementAssetID = nil
+		} else if replacement == assetID {
+			return nil, &ValidationError{Message: "an asset cannot replace itself"}
+		} else {
+			input.ReplacementAssetID = &replacement
+		}
+	}
+
+	var deprecatedBy *string
+	if userID != "" {
+		deprecatedBy = &userID
+	}
+	if err := s.repo.Upsert(ctx, assetID, input, deprecatedBy); err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.Get(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.track(ctx, d); err != nil {
+		log.Warn().Err(err).Str("asset_id", assetID).Msg("Failed to record deprecation consumers")
+	}
+	return d, nil
+}
+
+func (s *Service) Undeprecate(ctx context.Context, assetID string) error {
+	return s.repo.Delete(ctx, assetID)
+}
+
+func (s *Service) Get(ctx context.Context, assetID string) (*Deprecation, error) {
+	return s.repo.Get(ctx, assetID)
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) (*ListResult, error) {
+	deprecations, total, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Deprecations: deprecations, Total: total}, nil
+}
+
+// Consumers reports the assets still reading a deprecated asset, those that
+// have moved off it, and the daily history of both. The current counts are
+// recorded as today's snapshot.
+func (s *Service) Consumers(ctx context.Context, assetID string) (*ConsumersReport, error) {
+	d, err := s.repo.Get(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumers, err := s.track(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConsumersReport{
+		Deprecation: d,
+		Remaining:   []Consumer{},
+		Migrated:    []Consumer{},
+		Progress:    summarize(consumers),
+	}
+	for _, c := range consumers {
+		if c.ReadsDeprecated {
+			report.Remaining = append(report.Remaining, c)
+		} else {
+			report.Migrated = append(report.Migrated, c)
+		}
+	}
+
+	report.History, err = s.repo.ListSnapshots(ctx, assetID, HistoryDays)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RecordProgress snapshots the consumers of every deprecated asset. It
+// returns how many deprecations were recorded.
+func (s *Service) RecordProgress(ctx context.Context) (int, error) {
+	const pageSize = 100
+
+	recorded := 0
+	for offset := 0; ; offset += pageSize {
+		page, total, err := s.repo.List(ctx, pageSize, offset)
+		if err != nil {
+			return recorded, err
+		}
+		for _, d := range page {
+			if _, err := s.track(ctx, d); err != nil {
+				log.Warn().Err(err).Str("asset_id", d.Asset.ID).Msg("Failed to record deprecation progress")
+				continue
+			}
+			recorded++
+		}
+		if len(page) == 0 || offset+pageSize >= total {
+			return recorded, nil
+		}
+	}
+}
+
+// DeprecationsByMRN implements lineage.DeprecationLookup.
+func (s *Service) DeprecationsByMRN(ctx context.Context, mrns []string) (map[string]*lineage.DeprecationNotice, error) {
+	deprecations, err := s.repo.ListByMRNs(ctx, mrns)
+	if err != nil {
+		return nil, err
+	}
+
+	notices := make(map[string]*lineage.DeprecationNotice, len(deprecations))
+	for _, d := range deprecations {
+		notice := &lineage.DeprecationNotice{Reason: d.Reason, SunsetAt: d.SunsetAt}
+		if d.Replacement != nil {
+			notice.ReplacementID = d.Replacement.ID
+			notice.ReplacementMRN = d.Replacement.MRN
+			notice.ReplacementName = d.Replacement.Name
+		}
+		notices[d.Asset.MRN] = notice
+	}
+	return notices, nil
+}
+
+// track records the current consumers of d, returns every consumer seen
+// since it was deprecated, and stores today's snapshot.
+func (s *Service) track(ctx context.Context, d *Deprecation) ([]Consumer, error) {
+	replacementMRN := ""
+	if d.Replacement != nil {
+		replacementMRN = d.Replacement.MRN
+	}
+
+	consumers, err := s.repo.SyncConsumers(ctx, d.Asset.ID, d.Asset.MRN, replacementMRN)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordSnapshot(ctx, d.Asset.ID, summarize(consumers)); err != nil {
+		return nil, err
+	}
+	return consumers, nil
+}
+
+func summarize(consumers []Consumer) Snapshot {
+	snapshot := Snapshot{RecordedOn: time.Now().UTC().Truncate(24 * time.Hour)}
+	for _, c := range consumers {
+		switch {
+		case !c.ReadsDeprecated:
+			snapshot.Migrated++
+		case c.ReadsReplacement:
+			snapshot.Remaining++
+			snapshot.Dual++
+		default:
+			snapshot.Remaining++
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,272 @@
+package deprecation
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	assets       map[string]AssetRef
+	edges        map[string]map[string]bool
+	deprecations map[string]*Deprecation
+	consumers    map[string]map[string]time.Time
+	snapshots    map[string][]Snapshot
+}
+
+func newFakeRepo(assets ...AssetRef) *fakeRepo {
+	f := &fakeRepo{
+		assets:       map[string]AssetRef{},
+		edges:        map[string]map[string]bool{},
+		deprecations: map[string]*Deprecation{},
+		consumers:    map[string]map[string]time.Time{},
+		snapshots:    map[string][]Snapshot{},
+	}
+	for _, a := range assets {
+		f.assets[a.ID] = a
+	}
+	return f
+}
+
+func (f *fakeRepo) addEdge(source, target string) {
+	if f.edges[source] == nil {
+		f.edges[source] = map[string]bool{}
+	}
+	f.edges[source][target] = true
+}
+
+func (f *fakeRepo) assetByMRN(mrn string) (AssetRef, bool) {
+	for _, a := range f.assets {
+		if a.MRN == mrn {
+			return a, true
+		}
+	}
+	return AssetRef{}, false
+}
+
+func (f *fakeRepo) Upsert(_ context.Context, assetID string, input DeprecateInput, deprecatedBy *string) error {
+	a, ok := f.assets[assetID]
+	if !ok {
+		return ErrAssetNotFound
+	}
+	d := &Deprecation{Asset: a, Reason: input.Reason, SunsetAt: input.SunsetAt, DeprecatedBy: deprecatedBy}
+	if input.ReplacementAssetID != nil {
+		r, ok := f.assets[*input.ReplacementAssetID]
+		if !ok {
+			return ErrReplacementNotFound
+		}
+		d.Replacement = &r
+	}
+	f.deprecations[assetID] = d
+	return nil
+}
+
+func (f *fakeRepo) Delete(_ context.Context, assetID string) error {
+	if _, ok := f.deprecations[assetID]; !ok {
+		return ErrNotFound
+	}
+	delete(f.deprecations, assetID)
+	delete(f.consumers, assetID)
+	delete(f.snapshots, assetID)
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, assetID string) (*Deprecation, error) {
+	d, ok := f.deprecations[assetID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *d
+	return &out, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, limit, offset int) ([]*Deprecation, int, error) {
+	var all []*Deprecation
+	for _, d := range f.deprecations {
+		out := *d
+		all = append(all, &out)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Asset.ID < all[j].Asset.ID })
+	total := len(all)
+	if offset >= total {
+		return []*Deprecation{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return all[offset:end], total, nil
+}
+
+func (f *fakeRepo) ListByMRNs(_ context.Context, mrns []string) ([]*Deprecation, error) {
+	var out []*Deprecation
+	for _, d := range f.deprecations {
+		for _, m := range mrns {
+			if d.Asset.MRN == m {
+				copied := *d
+				out = append(out, &copied)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) SyncConsumers(_ context.Context, assetID, assetMRN, replacementMRN string) ([]Consumer, error) {
+	if f.consumers[assetID] == nil {
+		f.consumers[assetID] = map[string]time.Time{}
+	}
+	for target := range f.edges[assetMRN] {
+		if target == replacementMRN {
+			continue
+		}
+		if _, ok := f.consumers[assetID][target]; !ok {
+			f.consumers[assetID][target] = time.Now()
+		}
+	}
+
+	consumers := []Consumer{}
+	for mrn, seen := range f.consumers[assetID] {
+		c := Consumer{
+			MRN:              mrn,
+			ReadsDeprecated:  f.edges[assetMRN][mrn],
+			ReadsReplacement: f.edges[replacementMRN][mrn],
+			FirstSeenAt:      seen,
+		}
+		if a, ok := f.assetByMRN(mrn); ok {
+			c.AssetID, c.Name, c.Type = a.ID, a.Name, a.Type
+		}
+		consumers = append(consumers, c)
+	}
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].MRN < consumers[j].MRN })
+	return consumers, nil
+}
+
+func (f *fakeRepo) RecordSnapshot(_ context.Context, assetID string, s Snapshot) error {
+	snapshots := f.snapshots[assetID]
+	if n := len(snapshots); n > 0 && snapshots[n-1].RecordedOn.Equal(s.RecordedOn) {
+		snapshots[n-1] = s
+		return nil
+	}
+	f.snapshots[assetID] = append(snapshots, s)
+	return nil
+}
+
+func (f *fakeRepo) ListSnapshots(_ context.Context, assetID string, days int) ([]Snapshot, error) {
+	snapshots := f.snapshots[assetID]
+	if len(snapshots) > days {
+		snapshots = snapshots[len(snapshots)-days:]
+	}
+	return append([]Snapshot{}, snapshots...), nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func ordersFixture() *fakeRepo {
+	repo := newFakeRepo(
+		AssetRef{ID: "orders", MRN: "mrn://table/pg/orders", Name: "orders", Type: "Table"},
+		AssetRef{ID: "orders_v2", MRN: "mrn://table/pg/orders_v2", Name: "orders_v2", Type: "Table"},
+		AssetRef{ID: "revenue", MRN: "mrn://model/dbt/revenue", Name: "revenue", Type: "Model"},
+		AssetRef{ID: "churn", MRN: "mrn://model/dbt/churn", Name: "churn", Type: "Model"},
+		AssetRef{ID: "sales", MRN: "mrn://dashboard/looker/sales", Name: "sales", Type: "Dashboard"},
+	)
+	repo.addEdge("mrn://table/pg/orders", "mrn://table/pg/orders_v2")
+	repo.addEdge("mrn://table/pg/orders", "mrn://model/dbt/revenue")
+	repo.addEdge("mrn://table/pg/orders", "mrn://model/dbt/churn")
+	repo.addEdge("mrn://table/pg/orders", "mrn://dashboard/looker/sales")
+	return repo
+}
+
+func TestDeprecateValidation(t *testing.T) {
+	svc := NewService(ordersFixture())
+	ctx := context.Background()
+
+	_, err := svc.Deprecate(ctx, "orders", DeprecateInput{ReplacementAssetID: strPtr("orders")}, "")
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.Deprecate(ctx, "orders", DeprecateInput{ReplacementAssetID: strPtr("missing")}, "")
+	assert.ErrorIs(t, err, ErrReplacementNotFound)
+
+	_, err = svc.Deprecate(ctx, "missing", DeprecateInput{}, "")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+
+	d, err := svc.Deprecate(ctx, "orders", DeprecateInput{ReplacementAssetID: strPtr("  "), Reason: " old "}, "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, d.Replacement)
+	assert.Equal(t, "old", d.Reason)
+	assert.Equal(t, "user-1", *d.DeprecatedBy)
+}
+
+func TestConsumersTracksMigration(t *testing.T) {
+	repo := ordersFixture()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	_, err := svc.Deprecate(ctx, "orders", DeprecateInput{ReplacementAssetID: strPtr("orders_v2")}, "")
+	require.NoError(t, err)
+
+	// revenue reads both, churn has moved over, sales hasn't started.
+	repo.addEdge("mrn://table/pg/orders_v2", "mrn://model/dbt/revenue")
+	repo.addEdge("mrn://table/pg/orders_v2", "mrn://model/dbt/churn")
+	delete(repo.edges["mrn://table/pg/orders"], "mrn://model/dbt/churn")
+
+	report, err := svc.Consumers(ctx, "orders")
+	require.NoError(t, err)
+
+	var remaining, migrated []string
+	for _, c := range report.Remaining {
+		remaining = append(remaining, c.MRN)
+	}
+	for _, c := range report.Migrated {
+		migrated = append(migrated, c.MRN)
+	}
+	assert.Equal(t, []string{"mrn://dashboard/looker/sales", "mrn://model/dbt/revenue"}, remaining)
+	assert.Equal(t, []string{"mrn://model/dbt/churn"}, migrated)
+	assert.True(t, report.Migrated[0].ReadsReplacement)
+
+	assert.Equal(t, 2, report.Progress.Remaining)
+	assert.Equal(t, 1, report.Progress.Dual)
+	assert.Equal(t, 1, report.Progress.Migrated)
+
+	// Deprecating and reporting on the same day keep a single snapshot.
+	require.Len(t, report.History, 1)
+	assert.Equal(t, report.Progress, report.History[0])
+}
+
+func TestDeprecationsByMRN(t *testing.T) {
+	svc := NewService(ordersFixture())
+	ctx := context.Background()
+
+	_, err := svc.Deprecate(ctx, "orders", DeprecateInput{ReplacementAssetID: strPtr("orders_v2"), Reason: "split by region"}, "")
+	require.NoError(t, err)
+
+	notices, err := svc.DeprecationsByMRN(ctx, []string{"mrn://table/pg/orders", "mrn://model/dbt/revenue"})
+	require.NoError(t, err)
+	require.Len(t, notices, 1)
+
+	notice := notices["mrn://table/pg/orders"]
+	require.NotNil(t, notice)
+	assert.Equal(t, "split by region", notice.Reason)
+	assert.Equal(t, "orders_v2", notice.ReplacementID)
+	assert.Equal(t, "mrn://table/pg/orders_v2", notice.ReplacementMRN)
+}
+
+func TestRecordProgress(t *testing.T) {
+	svc := NewService(ordersFixture())
+	ctx := context.Background()
+
+	_, err := svc.Deprecate(ctx, "orders", DeprecateInput{}, "")
+	require.NoError(t, err)
+	_, err = svc.Deprecate(ctx, "revenue", DeprecateInput{}, "")
+	require.NoError(t, err)
+
+	recorded, err := svc.RecordProgress(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, recorded)
+
+	require.NoError(t, svc.Undeprecate(ctx, "revenue"))
+	assert.ErrorIs(t, svc.Undeprecate(ctx, "revenue"), ErrNotFound)
+}
@@ -0,0 +1,264 @@
+package deprecation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the deprecation data access interface.
+type Repository interface {
+	Upsert(ctx context.Context, assetID string, input DeprecateInput, deprecatedBy *string) error
+	Delete(ctx context.Context, assetID string) error
+	Get(ctx context.Context, assetID string) (*Deprecation, error)
+	List(ctx context.Context, limit, offset int) ([]*Deprecation, int, error)
+	ListByMRNs(ctx context.Context, mrns []string) ([]*Deprecation, error)
+	// SyncConsumers remembers the assets that currently read assetMRN and
+	// returns every consumer remembered for the deprecation. The replacement
+	// is never counted as a consumer.
+	SyncConsumers(ctx context.Context, assetID, assetMRN, replacementMRN string) ([]Consumer, error)
+	// RecordSnapshot stores s as today's snapshot, replacing an earlier one
+	// from the same day.
+	RecordSnapshot(ctx context.Context, assetID string, s Snapshot) error
+	// ListSnapshots returns the latest days snapshots, oldest first.
+	ListSnapshots(ctx context.Context, assetID string, days int) ([]Snapshot, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectDeprecation = `
+	SELECT a.id, a.mrn, a.name, a.type,
+	       r.id, r.mrn, r.name, r.type,
+	       d.reason, d.sunset_at,
+	       (SELECT COUNT(DISTINCT e.target_mrn) FROM lineage_edges e
+	        WHERE e.source_mrn = a.mrn AND e.target_mrn IS DISTINCT FROM r.mrn),
+	       d.deprecated_by::text, d.deprecated_at
+	FROM asset_deprecations d
+	JOIN assets a ON a.id = d.asset_id
+	LEFT JOIN assets r ON r.id = d.replacement_asset_id`
+
+// Upsert records the deprecation and moves the asset to the deprecated
+// lifecycle status.
+func (r *PostgresRepository) Upsert(ctx context.Context, assetID string, input DeprecateInput, deprecatedBy *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO asset_deprecations (asset_id, replacement_asset_id, reason, sunset_at, deprecated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (asset_id) DO UPDATE SET
+			replacement_asset_id = EXCLUDED.replacement_asset_id,
+			reason = EXCLUDED.reason,
+			sunset_at = EXCLUDED.sunset_at`,
+		assetID, input.ReplacementAssetID, input.Reason, input.SunsetAt, deprecatedBy)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			if strings.Contains(pgErr.ConstraintName, "replacement") {
+				return ErrReplacementNotFound
+			}
+			return ErrAssetNotFound
+		}
+		return fmt.Errorf("deprecating asset: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE assets SET lifecycle_status = 'deprecated' WHERE id = $1`, assetID); err != nil {
+		return fmt.Errorf("updating asset lifecycle: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// Delete removes the deprecation and returns a deprecated asset to active.
+func (r *PostgresRepository) Delete(ctx context.Context, assetID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM asset_deprecations WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return fmt.Errorf("removing asset deprecation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE assets SET lifecycle_status = 'active'
+		WHERE id = $1 AND lifecycle_status = 'deprecated'`, assetID)
+	if err != nil {
+		return fmt.Errorf("updating asset lifecycle: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, assetID string) (*Deprecation, error) {
+	d, err := scanDeprecation(r.db.QueryRow(ctx, selectDeprecation+` WHERE d.asset_id = $1`, assetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting asset deprecation: %w", err)
+	}
+	return d, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, limit, offset int) ([]*Deprecation, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_deprecations`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting asset deprecations: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectDeprecation+`
+		ORDER BY d.sunset_at ASC NULLS LAST, d.deprecated_at DESC, a.id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing asset deprecations: %w", err)
+	}
+	deprecations, err := scanDeprecations(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deprecations, total, nil
+}
+
+func (r *PostgresRepository) ListByMRNs(ctx context.Context, mrns []string) ([]*Deprecation, error) {
+	rows, err := r.db.Query(ctx, selectDeprecation+` WHERE a.mrn = ANY($1)`, mrns)
+	if err != nil {
+		return nil, fmt.Errorf("listing asset deprecations: %w", err)
+	}
+	return scanDeprecations(rows)
+}
+
+func (r *PostgresRepository) SyncConsumers(ctx context.Context, assetID, assetMRN, replacementMRN string) ([]Consumer, error) {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO asset_deprecation_consumers (asset_id, consumer_mrn)
+		SELECT DISTINCT $1::varchar, e.target_mrn
+		FROM lineage_edges e
+		WHERE e.source_mrn = $2 AND e.target_mrn <> $3
+		ON CONFLICT DO NOTHING`, assetID, assetMRN, replacementMRN); err != nil {
+		return nil, fmt.Errorf("recording deprecation consumers: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT COALESCE(a.id, ''), c.consumer_mrn, COALESCE(a.name, ''), COALESCE(a.type, ''),
+		       COALESCE(a.providers, '{}'),
+		       EXISTS (SELECT 1 FROM lineage_edges e WHERE e.source_mrn = $2 AND e.target_mrn = c.consumer_mrn),
+		       EXISTS (SELECT 1 FROM lineage_edges e WHERE e.source_mrn = $3 AND e.target_mrn = c.consumer_mrn),
+		       c.first_seen_at
+		FROM asset_deprecation_consumers c
+		LEFT JOIN assets a ON a.mrn = c.consumer_mrn
+		WHERE c.asset_id = $1 AND c.consumer_mrn <> $3
+		ORDER BY COALESCE(a.name, c.consumer_mrn), c.consumer_mrn`, assetID, assetMRN, replacementMRN)
+	if err != nil {
+		return nil, fmt.Errorf("listing deprecation consumers: %w", err)
+	}
+	defer rows.Close()
+
+	consumers := []Consumer{}
+	for rows.Next() {
+		var c Consumer
+		if err := rows.Scan(&c.AssetID, &c.MRN, &c.Name, &c.Type, &c.Providers,
+			&c.ReadsDeprecated, &c.ReadsReplacement, &c.FirstSeenAt); err != nil {
+			return nil, fmt.Errorf("scanning deprecation consumer: %w", err)
+		}
+		consumers = append(consumers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating deprecation consumers: %w", err)
+	}
+	return consumers, nil
+}
+
+func (r *PostgresRepository) RecordSnapshot(ctx context.Context, assetID string, s Snapshot) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_deprecation_snapshots (asset_id, recorded_on, remaining, dual, migrated)
+		VALUES ($1, CURRENT_DATE, $2, $3, $4)
+		ON CONFLICT (asset_id, recorded_on) DO UPDATE SET
+			remaining = EXCLUDED.remaining,
+			dual = EXCLUDED.dual,
+			migrated = EXCLUDED.migrated`,
+		assetID, s.Remaining, s.Dual, s.Migrated)
+	if err != nil {
+		return fmt.Errorf("recording deprecation snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListSnapshots(ctx context.Context, assetID string, days int) ([]Snapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT recorded_on, remaining, dual, migrated
+		FROM (
+			SELECT * FROM asset_deprecation_snapshots
+			WHERE asset_id = $1
+			ORDER BY recorded_on DESC
+			LIMIT $2
+		) latest
+		ORDER BY recorded_on`, assetID, days)
+	if err != nil {
+		return nil, fmt.Errorf("listing deprecation snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		var s Snapshot
+		if err := rows.Scan(&s.RecordedOn, &s.Remaining, &s.Dual, &s.Migrated); err != nil {
+			return nil, fmt.Errorf("scanning deprecation snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating deprecation snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+func scanDeprecations(rows pgx.Rows) ([]*Deprecation, error) {
+	defer rows.Close()
+
+	deprecations := []*Deprecation{}
+	for rows.Next() {
+		d, err := scanDeprecation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning asset deprecation: %w", err)
+		}
+		deprecations = append(deprecations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating asset deprecations: %w", err)
+	}
+	return deprecations, nil
+}
+
+func scanDeprecation(row pgx.Row) (*Deprecation, error) {
+	var d Deprecation
+	var replacementID, replacementMRN, replacementName, replacementType *string
+	err := row.Scan(
+		&d.Asset.ID, &d.Asset.MRN, &d.Asset.Name, &d.Asset.Type,
+		&replacementID, &replacementMRN, &replacementName, &replacementType,
+		&d.Reason, &d.SunsetAt, &d.Consumers, &d.DeprecatedBy, &d.DeprecatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if replacementID != nil {
+		d.Replacement = &AssetRef{ID: *replacementID, MRN: *replacementMRN, Name: *replacementName, Type: *replacementType}
+	}
+	return &d, nil
+}
@@ -0,0 +1,58 @@
+package deprecation
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultTrackInterval = 24 * time.Hour
+
+// Tracker periodically snapshots the consumers of deprecated assets, so
+// migration progress is charted even when nobody looks at it.
+type Tracker struct {
+	task *background.SingletonTask
+}
+
+// TrackerConfig configures the tracker.
+type TrackerConfig struct {
+	// Interval between snapshots. Default: 24 hours.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewTracker creates a new tracker for svc.
+func NewTracker(svc *Service, config *TrackerConfig) *Tracker {
+	if config == nil {
+		config = &TrackerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultTrackInterval
+	}
+
+	return &Tracker{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "deprecation-progress",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.RecordProgress(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins the periodic snapshot loop.
+func (t *Tracker) Start(ctx context.Context) {
+	t.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the tracker.
+func (t *Tracker) Stop() {
+	t.task.Stop()
+}
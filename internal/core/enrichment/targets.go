@@ -124,7 +124,7 @@ func extractTargetsFromFilter(filter query.Filter) []RuleTarget {
 				TargetValue: filter.Field[0],
 			})
 		}
-	case query.FieldName:
+	case query.FieldName, query.FieldStatus:
 		targets = append(targets, RuleTarget{
 			TargetType:  TargetTypeQuery,
 			TargetValue: "",
@@ -0,0 +1,68 @@
+package exposure
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/rs/zerolog/log"
+)
+
+const DefaultResolveInterval = 15 * time.Minute
+
+// Resolver periodically links exposures to BI assets, so the link appears
+// once both the dbt project and the BI tool have been ingested, whichever
+// comes first.
+type Resolver struct {
+	task *background.SingletonTask
+}
+
+// ResolverConfig configures the resolver.
+type ResolverConfig struct {
+	// Interval between resolutions. Default: 15 minutes.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewResolver creates a resolver for svc.
+func NewResolver(svc *Service, config *ResolverConfig) *Resolver {
+	if config == nil {
+		config = &ResolverConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultResolveInterval
+	}
+
+	return &Resolver{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "exposure-lineage",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 2 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				result, err := svc.Resolve(ctx)
+				if err != nil {
+					return err
+				}
+				log.Debug().
+					Int("exposures", result.Exposures).
+					Int("created", result.Created).
+					Int("removed", result.Removed).
+					Msg("Resolved exposure lineage")
+				return nil
+			},
+		}),
+	}
+}
+
+// Start begins the periodic resolution loop.
+func (r *Resolver) Start(ctx context.Context) {
+	r.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the resolver.
+func (r *Resolver) Stop() {
+	r.task.Stop()
+}
@@ -0,0 +1,170 @@
+// Package exposure links dbt exposures to the BI assets they describe.
+//
+// A dbt exposure declares which models a dashboard reads and the URL it
+// lives at. BI plugins discover the same dashboard, with the same URL, as
+// an asset of their own. Matching the two URLs connects the exposure to
+// the dashboard, so lineage runs from the warehouse through dbt to BI
+// without either plugin knowing about the other.
+package exposure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EdgeType marks the lineage edges the resolver manages. Edges of this type
+// that no longer match an exposure's URL are removed.
+const EdgeType = "EXPOSES"
+
+// Asset is an asset with the URL it is matched by.
+type Asset struct {
+	MRN string
+	URL string
+}
+
+// Link is an edge created by the resolver from an exposure to a BI asset.
+type Link struct {
+	EdgeID string
+	Source string
+	Target string
+}
+
+// LineageWriter creates and deletes lineage edges.
+type LineageWriter interface {
+	CreateDirectLineage(ctx context.Context, sourceMRN string, targetMRN string, lineageType string) (string, error)
+	DeleteDirectLineage(ctx context.Context, edgeID string) error
+}
+
+// ResolveResult summarizes one resolution pass.
+type ResolveResult struct {
+	Exposures int `json:"exposures"`
+	Matched   int `json:"matched"`
+	Created   int `json:"created"`
+	Removed   int `json:"removed"`
+}
+
+type Service struct {
+	repo    Repository
+	lineage LineageWriter
+}
+
+func NewService(repo Repository, lineage LineageWriter) *Service {
+	return &Service{repo: repo, lineage: lineage}
+}
+
+// Resolve links every exposure to the BI assets found at its URL and
+// removes the links of exposures whose URL no longer matches. An exposure
+// whose URL matches several assets is linked to each of them.
+func (s *Service) Resolve(ctx context.Context) (*ResolveResult, error) {
+	exposures, err := s.repo.ListExposures(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing exposures: %w", err)
+	}
+	targets, err := s.repo.ListTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing BI assets: %w", err)
+	}
+	links, err := s.repo.ListLinks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing exposure links: %w", err)
+	}
+
+	byURL := make(map[string][]string)
+	for _, t := range targets {
+		if key := NormalizeURL(t.URL); key != "" {
+			byURL[key] = append(byURL[key], t.MRN)
+		}
+	}
+
+	result := &ResolveResult{Exposures: len(exposures)}
+	wanted := make(map[string]bool)
+	var missing []Link
+	existing := make(map[string]bool, len(links))
+	for _, l := range links {
+		existing[l.Source+"->"+l.Target] = true
+	}
+
+	for _, e := range exposures {
+		matches := byURL[NormalizeURL(e.URL)]
+		if len(matches) > 0 {
+			result.Matched++
+		}
+		for _, target := range matches {
+			if target == e.MRN {
+				continue
+			}
+			key := e.MRN + "->" + target
+			wanted[key] = true
+			if !existing[key] {
+				missing = append(missing, Link{Source: e.MRN, Target: target})
+			}
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Source != missing[j].Source {
+			return missing[i].Source < missing[j].Source
+		}
+		return missing[i].Target < missing[j].Target
+	})
+	for _, l := range missing {
+		if _, err := s.lineage.CreateDirectLineage(ctx, l.Source, l.Target, EdgeType); err != nil {
+			log.Warn().Err(err).Str("source", l.Source).Str("target", l.Target).Msg("Failed to link exposure")
+			continue
+		}
+		result.Created++
+	}
+
+	for _, l := range links {
+		if wanted[l.Source+"->"+l.Target] {
+			continue
+		}
+		if err := s.lineage.DeleteDirectLineage(ctx, l.EdgeID); err != nil {
+			log.Warn().Err(err).Str("edge_id", l.EdgeID).Msg("Failed to remove stale exposure link")
+			continue
+		}
+		result.Removed++
+	}
+
+	return result, nil
+}
+
+// NormalizeURL reduces a URL to the parts that identify the page it points
+// at, so the URL in an exposure matches the one a BI plugin reports even
+// when they were copied from different places:
+//
+//   - the scheme, a www. prefix, default ports, the query and trailing
+//     slashes are dropped, and the host is lower-cased;
+//   - Looker's embed and dashboards-next paths become the plain dashboard
+//     path;
+//   - a fragment holding a route, as in Tableau's /#/site/x/views/...
+//     URLs, is kept as part of the path.
+//
+// It returns "" for anything that isn't an absolute URL.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	path := u.EscapedPath()
+	if route := strings.TrimPrefix(u.EscapedFragment(), "!"); strings.HasPrefix(route, "/") {
+		route, _, _ = strings.Cut(route, "?")
+		path = strings.TrimSuffix(path, "/") + "/#" + route
+	}
+	path = strings.TrimPrefix(path, "/embed")
+	path = strings.Replace(path, "/dashboards-next/", "/dashboards/", 1)
+	path = strings.TrimRight(path, "/")
+
+	return host + path
+}
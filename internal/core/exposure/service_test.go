@@ -0,0 +1,115 @@
+package exposure
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	exposures []Asset
+	targets   []Asset
+	lineage   *fakeLineage
+}
+
+func (f *fakeRepo) ListExposures(context.Context) ([]Asset, error) { return f.exposures, nil }
+func (f *fakeRepo) ListTargets(context.Context) ([]Asset, error)   { return f.targets, nil }
+
+func (f *fakeRepo) ListLinks(context.Context) ([]Link, error) {
+	var links []Link
+	for id, l := range f.lineage.edges {
+		if l.typ == EdgeType {
+			links = append(links, Link{EdgeID: id, Source: l.source, Target: l.target})
+		}
+	}
+	return links, nil
+}
+
+type fakeEdge struct {
+	source, target, typ string
+}
+
+type fakeLineage struct {
+	edges  map[string]fakeEdge
+	nextID int
+}
+
+func (f *fakeLineage) CreateDirectLineage(_ context.Context, source, target, typ string) (string, error) {
+	f.nextID++
+	id := fmt.Sprint(f.nextID)
+	f.edges[id] = fakeEdge{source, target, typ}
+	return id, nil
+}
+
+func (f *fakeLineage) DeleteDirectLineage(_ context.Context, edgeID string) error {
+	delete(f.edges, edgeID)
+	return nil
+}
+
+func (f *fakeLineage) targets(source string) []string {
+	var out []string
+	for _, e := range f.edges {
+		if e.source == source {
+			out = append(out, e.target)
+		}
+	}
+	return out
+}
+
+func TestResolve(t *testing.T) {
+	lineage := &fakeLineage{edges: map[string]fakeEdge{}}
+	repo := &fakeRepo{
+		lineage: lineage,
+		exposures: []Asset{
+			{MRN: "mrn://exposure/dbt/analytics.weekly_kpis", URL: "https://looker.example.com/dashboards/42?filters=x"},
+			{MRN: "mrn://exposure/dbt/analytics.churn", URL: "https://tableau.example.com/#/site/ops/views/Churn/Overview"},
+			{MRN: "mrn://exposure/dbt/analytics.notebook", URL: "https://notebooks.example.com/churn"},
+		},
+		targets: []Asset{
+			{MRN: "mrn://dashboard/looker/42", URL: "https://Looker.example.com/dashboards-next/42/"},
+			{MRN: "mrn://dashboard/tableau/churn", URL: "https://tableau.example.com/#/site/ops/views/Churn/Overview?:iid=1"},
+		},
+	}
+	svc := NewService(repo, lineage)
+
+	result, err := svc.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &ResolveResult{Exposures: 3, Matched: 2, Created: 2}, result)
+	assert.Equal(t, []string{"mrn://dashboard/looker/42"}, lineage.targets("mrn://exposure/dbt/analytics.weekly_kpis"))
+	assert.Equal(t, []string{"mrn://dashboard/tableau/churn"}, lineage.targets("mrn://exposure/dbt/analytics.churn"))
+
+	result, err = svc.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Created, "existing links are kept")
+	assert.Len(t, lineage.edges, 2)
+
+	repo.exposures[0].URL = "https://looker.example.com/dashboards/43"
+	lineage.edges["manual"] = fakeEdge{"mrn://exposure/dbt/analytics.weekly_kpis", "mrn://table/snowflake/orders", "DIRECT"}
+	result, err = svc.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Removed)
+	assert.Equal(t, []string{"mrn://table/snowflake/orders"}, lineage.targets("mrn://exposure/dbt/analytics.weekly_kpis"),
+		"only the links the resolver manages are removed")
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://looker.example.com/dashboards/42", "looker.example.com/dashboards/42"},
+		{"http://www.Looker.example.com:443/dashboards/42/?filters=a", "looker.example.com/dashboards/42"},
+		{"https://looker.example.com/embed/dashboards-next/42", "looker.example.com/dashboards/42"},
+		{"https://looker.example.com:9999/looks/7", "looker.example.com:9999/looks/7"},
+		{"https://tableau.example.com/#/site/ops/views/Churn/Overview?:iid=1", "tableau.example.com/#/site/ops/views/Churn/Overview"},
+		{"https://app.example.com/report#section-2", "app.example.com/report"},
+		{"dashboards/42", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizeURL(tt.raw), tt.raw)
+	}
+}
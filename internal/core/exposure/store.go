@@ -0,0 +1,96 @@
+package exposure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the exposure data access interface.
+type Repository interface {
+	// ListExposures returns the dbt exposures that have a URL.
+	ListExposures(ctx context.Context) ([]Asset, error)
+	// ListTargets returns the assets of other providers that have a URL.
+	ListTargets(ctx context.Context) ([]Asset, error)
+	// ListLinks returns the edges of EdgeType.
+	ListLinks(ctx context.Context) ([]Link, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// targetURL is the page a BI asset lives at: an explicit dashboard URL in
+// its metadata, else its URL.
+const targetURL = `COALESCE(
+	NULLIF(a.metadata->>'dashboard_url', ''),
+	NULLIF(a.metadata->>'url', ''),
+	'')`
+
+func (r *PostgresRepository) ListExposures(ctx context.Context) ([]Asset, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.mrn, a.metadata->>'url'
+		FROM assets a
+		WHERE a.type = 'Exposure'
+		  AND 'DBT' = ANY(a.providers)
+		  AND NOT a.is_stub
+		  AND COALESCE(a.metadata->>'url', '') <> ''`)
+	if err != nil {
+		return nil, fmt.Errorf("querying exposures: %w", err)
+	}
+	return scanAssets(rows)
+}
+
+func (r *PostgresRepository) ListTargets(ctx context.Context) ([]Asset, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.mrn, `+targetURL+`
+		FROM assets a
+		WHERE NOT ('DBT' = ANY(a.providers))
+		  AND NOT a.is_stub
+		  AND `+targetURL+` <> ''`)
+	if err != nil {
+		return nil, fmt.Errorf("querying BI assets: %w", err)
+	}
+	return scanAssets(rows)
+}
+
+func (r *PostgresRepository) ListLinks(ctx context.Context) ([]Link, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, source_mrn, target_mrn
+		FROM lineage_edges
+		WHERE type = $1`, EdgeType)
+	if err != nil {
+		return nil, fmt.Errorf("querying exposure links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []Link
+	for rows.Next() {
+		var l Link
+		if err := rows.Scan(&l.EdgeID, &l.Source, &l.Target); err != nil {
+			return nil, fmt.Errorf("scanning exposure link: %w", err)
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func scanAssets(rows pgx.Rows) ([]Asset, error) {
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var a Asset
+		if err := rows.Scan(&a.MRN, &a.URL); err != nil {
+			return nil, fmt.Errorf("scanning asset: %w", err)
+		}
+		assets = append(assets, a)
+	}
+	return assets, rows.Err()
+}
@@ -0,0 +1,208 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/search"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound      = errors.New("favorite not found")
+	ErrAssetNotFound = errors.New("asset not found")
+)
+
+const (
+	// DefaultViewFlushInterval is how often in-memory asset views are persisted.
+	DefaultViewFlushInterval = 30 * time.Second
+	// RecentViewWindow is how far back a view counts towards search
+	// personalization.
+	RecentViewWindow = 30 * 24 * time.Hour
+	// PreferenceKey is the user preference that turns search personalization
+	// off when set to false.
+	PreferenceKey = "personalized_search"
+)
+
+// Favorite is an asset a user starred.
+type Favorite struct {
+	AssetID   string    `json:"asset_id"`
+	AssetMRN  string    `json:"asset_mrn"`
+	AssetName string    `json:"asset_name"`
+	AssetType string    `json:"asset_type"`
+	CreatedAt time.Time `json:"created_at"`
+} // @name AssetFavorite
+
+type ListResult struct {
+	Favorites []*Favorite `json:"favorites"`
+	Total     int         `json:"total"`
+} // @name AssetFavoritesListResult
+
+// View is a number of times a user opened an asset.
+type View struct {
+	UserID  string
+	AssetID string
+	Count   int64
+}
+
+type viewKey struct {
+	userID  string
+	assetID string
+}
+
+// Service manages asset favorites and the recent views that personalize
+// search. RecordView is safe for concurrent use and does no I/O; views are
+// flushed in the background.
+type Service struct {
+	repo     Repository
+	views    sync.Map // viewKey -> *atomic.Int64
+	interval time.Duration
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func NewService(repo Repository, interval time.Duration) *Service {
+	if interval <= 0 {
+		interval = DefaultViewFlushInterval
+	}
+	return &Service{
+		repo:     repo,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+func (s *Service) Add(ctx context.Context, userID, assetID string) (*Favorite, error) {
+	if err := s.repo.Add(ctx, userID, assetID); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, userID, assetID)
+}
+
+func (s *Service) Remove(ctx context.Context, userID, assetID string) error {
+	return s.repo.Remove(ctx, userID, assetID)
+}
+
+// List returns a user's favorites, most recently added first.
+func (s *Service) List(ctx context.Context, userID string, limit, offset int) (*ListResult, error) {
+	favorites, total, err := s.repo.List(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Favorites: favorites, Total: total}, nil
+}
+
+// RecordView counts a user opening an asset.
+func (s *Service) RecordView(userID, assetID string) {
+	if userID == "" || assetID == "" {
+		return
+	}
+	k := viewKey{userID: userID, assetID: assetID}
+	if v, ok := s.views.Load(k); ok {
+		v.(*atomic.Int64).Add(1)
+		return
+	}
+	var fresh atomic.Int64
+	fresh.Add(1)
+	actual, loaded := s.views.LoadOrStore(k, &fresh)
+	if loaded {
+		actual.(*atomic.Int64).Add(1)
+	}
+}
+
+// snapshot drains the in-memory view counters.
+func (s *Service) snapshot() []View {
+	var out []View
+	s.views.Range(func(k, v any) bool {
+		n := v.(*atomic.Int64).Swap(0)
+		if n == 0 {
+			return true
+		}
+		kk := k.(viewKey)
+		out = append(out, View{UserID: kk.userID, AssetID: kk.assetID, Count: n})
+		return true
+	})
+	return out
+}
+
+// Flush persists the views recorded since the last flush.
+func (s *Service) Flush(ctx context.Context) error {
+	views := s.snapshot()
+	if len(views) == 0 {
+		return nil
+	}
+	return s.repo.AddViews(ctx, time.Now().UTC(), views)
+}
+
+// Start runs the flush loop until Stop or ctx cancellation.
+func (s *Service) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop signals the loop to exit and blocks until the final flush completes.
+func (s *Service) Stop() {
+	select {
+	case <-s.stopCh:
+	default:
+		close(s.stopCh)
+	}
+	<-s.doneCh
+}
+
+func (s *Service) run(ctx context.Context) {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	flush := func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.Flush(flushCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to persist asset views; dropping this window")
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case <-s.stopCh:
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// AssetAffinity reports which of the assets the user favorited, owns
+// directly or through a team, or opened recently. It implements
+// search.AffinitySource.
+func (s *Service) AssetAffinity(ctx context.Context, userID string, assetIDs []string) (map[string]search.Affinity, error) {
+	if len(assetIDs) == 0 {
+		return nil, nil
+	}
+	// Favorites and views are keyed by user UUID; nothing else has any.
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, nil
+	}
+	affinity, err := s.repo.Affinity(ctx, userID, assetIDs, time.Now().Add(-RecentViewWindow))
+	if err != nil {
+		return nil, fmt.Errorf("loading asset affinity: %w", err)
+	}
+	return affinity, nil
+}
+
+// Enabled reports whether a user's preferences allow personalized search.
+// It is on unless the preference is explicitly false.
+func Enabled(preferences map[string]interface{}) bool {
+	enabled, ok := preferences[PreferenceKey].(bool)
+	return !ok || enabled
+}
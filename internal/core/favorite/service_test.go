@@ -0,0 +1,72 @@
+package favorite
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	views       [][]View
+	affinityFor string
+}
+
+func (f *fakeRepo) AddViews(_ context.Context, _ time.Time, views []View) error {
+	f.views = append(f.views, views)
+	return nil
+}
+
+func (f *fakeRepo) Affinity(_ context.Context, userID string, _ []string, _ time.Time) (map[string]search.Affinity, error) {
+	f.affinityFor = userID
+	return map[string]search.Affinity{"a": {Favorite: true}}, nil
+}
+
+func TestRecordViewFlush(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo, time.Hour)
+	svc.Start(context.Background())
+
+	svc.RecordView("u1", "a")
+	svc.RecordView("u1", "a")
+	svc.RecordView("u2", "a")
+	svc.RecordView("", "a")
+	svc.Stop()
+
+	require.Len(t, repo.views, 1)
+	views := repo.views[0]
+	sort.Slice(views, func(i, j int) bool { return views[i].UserID < views[j].UserID })
+	assert.Equal(t, []View{
+		{UserID: "u1", AssetID: "a", Count: 2},
+		{UserID: "u2", AssetID: "a", Count: 1},
+	}, views)
+}
+
+func TestAssetAffinity(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo, 0)
+	ctx := context.Background()
+
+	affinity, err := svc.AssetAffinity(ctx, "svc-account", []string{"a"})
+	require.NoError(t, err)
+	assert.Nil(t, affinity)
+	assert.Empty(t, repo.affinityFor)
+
+	userID := "7d8e3c2a-5b1f-4c1e-9a3d-2f6b8c9d0e1f"
+	affinity, err = svc.AssetAffinity(ctx, userID, []string{"a"})
+	require.NoError(t, err)
+	assert.True(t, affinity["a"].Favorite)
+	assert.Equal(t, userID, repo.affinityFor)
+}
+
+func TestEnabled(t *testing.T) {
+	assert.True(t, Enabled(nil))
+	assert.True(t, Enabled(map[string]interface{}{PreferenceKey: true}))
+	assert.True(t, Enabled(map[string]interface{}{PreferenceKey: "no"}))
+	assert.False(t, Enabled(map[string]interface{}{PreferenceKey: false}))
+}
@@ -0,0 +1,172 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/core/search"
+)
+
+// Repository defines the favorites and recent views data access interface.
+type Repository interface {
+	Add(ctx context.Context, userID, assetID string) error
+	Get(ctx context.Context, userID, assetID string) (*Favorite, error)
+	Remove(ctx context.Context, userID, assetID string) error
+	List(ctx context.Context, userID string, limit, offset int) ([]*Favorite, int, error)
+	// AddViews adds view counts and moves each asset's last view to at.
+	// Assets that no longer exist are skipped.
+	AddViews(ctx context.Context, at time.Time, views []View) error
+	// Affinity returns the user's relationship to each asset. Views before
+	// viewedSince are ignored.
+	Affinity(ctx context.Context, userID string, assetIDs []string, viewedSince time.Time) (map[string]search.Affinity, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectFavorite = `
+	SELECT f.asset_id, a.mrn, a.name, a.type, f.created_at
+	FROM asset_favorites f
+	JOIN assets a ON a.id = f.asset_id`
+
+func (r *PostgresRepository) Add(ctx context.Context, userID, assetID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_favorites (user_id, asset_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, asset_id) DO NOTHING`,
+		userID, assetID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrAssetNotFound
+		}
+		return fmt.Errorf("adding favorite: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, assetID string) (*Favorite, error) {
+	f, err := scanFavorite(r.db.QueryRow(ctx, selectFavorite+`
+		WHERE f.user_id = $1 AND f.asset_id = $2`, userID, assetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting favorite: %w", err)
+	}
+	return f, nil
+}
+
+func (r *PostgresRepository) Remove(ctx context.Context, userID, assetID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM asset_favorites WHERE user_id = $1 AND asset_id = $2`, userID, assetID)
+	if err != nil {
+		return fmt.Errorf("removing favorite: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, limit, offset int) ([]*Favorite, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_favorites WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting favorites: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectFavorite+`
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC, f.asset_id
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []*Favorite{}
+	for rows.Next() {
+		f, err := scanFavorite(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning favorite: %w", err)
+		}
+		favorites = append(favorites, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating favorites: %w", err)
+	}
+	return favorites, total, nil
+}
+
+func (r *PostgresRepository) AddViews(ctx context.Context, at time.Time, views []View) error {
+	users := make([]string, len(views))
+	assets := make([]string, len(views))
+	counts := make([]int64, len(views))
+	for i, v := range views {
+		users[i], assets[i], counts[i] = v.UserID, v.AssetID, v.Count
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_recent_views (user_id, asset_id, view_count, last_viewed_at)
+		SELECT u.id, a.id, v.count, $1
+		FROM unnest($2::text[], $3::text[], $4::bigint[]) AS v(user_id, asset_id, count)
+		JOIN users u ON u.id::text = v.user_id
+		JOIN assets a ON a.id = v.asset_id
+		ON CONFLICT (user_id, asset_id) DO UPDATE
+		SET view_count = asset_recent_views.view_count + EXCLUDED.view_count,
+		    last_viewed_at = GREATEST(asset_recent_views.last_viewed_at, EXCLUDED.last_viewed_at)`,
+		at, users, assets, counts)
+	if err != nil {
+		return fmt.Errorf("adding asset views: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Affinity(ctx context.Context, userID string, assetIDs []string, viewedSince time.Time) (map[string]search.Affinity, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT ids.id,
+		       EXISTS (SELECT 1 FROM asset_favorites f WHERE f.user_id = $1 AND f.asset_id = ids.id),
+		       EXISTS (
+		           SELECT 1 FROM asset_owners o
+		           WHERE o.asset_id = ids.id
+		             AND (o.user_id = $1 OR o.team_id IN (SELECT team_id FROM team_members WHERE user_id = $1))
+		       ),
+		       COALESCE((
+		           SELECT v.view_count FROM asset_recent_views v
+		           WHERE v.user_id = $1 AND v.asset_id = ids.id AND v.last_viewed_at >= $3
+		       ), 0)
+		FROM unnest($2::text[]) AS ids(id)`,
+		userID, assetIDs, viewedSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	affinity := make(map[string]search.Affinity, len(assetIDs))
+	for rows.Next() {
+		var id string
+		var a search.Affinity
+		if err := rows.Scan(&id, &a.Favorite, &a.Owned, &a.RecentViews); err != nil {
+			return nil, err
+		}
+		affinity[id] = a
+	}
+	return affinity, rows.Err()
+}
+
+func scanFavorite(row pgx.Row) (*Favorite, error) {
+	var f Favorite
+	if err := row.Scan(&f.AssetID, &f.AssetMRN, &f.AssetName, &f.AssetType, &f.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,475 @@
+package glossary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultUsageFlushInterval is how often in-memory usage counts are persisted.
+	DefaultUsageFlushInterval = 30 * time.Second
+	// DefaultUsageWindowDays is the window usage stats cover when none is given.
+	DefaultUsageWindowDays = 90
+	// MaxUsageWindowDays bounds how far back usage stats can look.
+	MaxUsageWindowDays = 365
+)
+
+// Sort orders for ListUsage.
+const (
+	UsageSortAssets   = "assets"
+	UsageSortSearches = "searches"
+	UsageSortViews    = "views"
+	UsageSortClicks   = "clicks"
+	UsageSortCTR      = "ctr"
+	UsageSortName     = "name"
+)
+
+var usageSortColumns = map[string]string{
+	UsageSortAssets:   "asset_count DESC",
+	UsageSortSearches: "searches DESC",
+	UsageSortViews:    "views DESC",
+	UsageSortClicks:   "clicks DESC",
+	UsageSortCTR:      "CASE WHEN searches > 0 THEN clicks::float / searches ELSE 0 END DESC",
+	UsageSortName:     "t.name ASC",
+}
+
+// TermUsage is how much a term has been used over a window. Searches counts
+// the times the term appeared in search results, Clicks the times it was
+// opened from them.
+type TermUsage struct {
+	TermID           string     `json:"term_id"`
+	Name             string     `json:"name"`
+	ParentTermID     *string    `json:"parent_term_id,omitempty"`
+	AssetCount       int        `json:"asset_count"`
+	Searches         int64      `json:"searches"`
+	Views            int64      `json:"views"`
+	Clicks           int64      `json:"clicks"`
+	ClickThroughRate float64    `json:"click_through_rate"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+} // @name GlossaryTermUsage
+
+// UnusedTerm is a term with no assets and no searches, views or clicks in
+// the window.
+type UnusedTerm struct {
+	TermID       string     `json:"term_id"`
+	Name         string     `json:"name"`
+	ParentTermID *string    `json:"parent_term_id,omitempty"`
+	ChildCount   int        `json:"child_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+} // @name UnusedGlossaryTerm
+
+type UsageFilter struct {
+	Days   int
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+type UsageListResult struct {
+	Terms []*TermUsage `json:"terms"`
+	Total int          `json:"total"`
+	Days  int          `json:"days"`
+} // @name GlossaryUsageListResult
+
+type UnusedListResult struct {
+	Terms []*UnusedTerm `json:"terms"`
+	Total int           `json:"total"`
+	Days  int           `json:"days"`
+} // @name UnusedGlossaryTermsResult
+
+// UsageCounts are the usage deltas for one term.
+type UsageCounts struct {
+	Searches int64
+	Views    int64
+	Clicks   int64
+}
+
+type UsageRepository interface {
+	// AddUsage adds the deltas to each term's totals for day. Terms that no
+	// longer exist are skipped.
+	AddUsage(ctx context.Context, day time.Time, deltas map[string]UsageCounts) error
+	GetUsage(ctx context.Context, termID string, since time.Time) (*TermUsage, error)
+	ListUsage(ctx context.Context, since time.Time, sort string, limit, offset int) ([]*TermUsage, int, error)
+	ListUnused(ctx context.Context, since time.Time, limit, offset int) ([]*UnusedTerm, int, error)
+}
+
+type usageKind int
+
+const (
+	usageSearch usageKind = iota
+	usageView
+	usageClick
+)
+
+type usageKey struct {
+	termID string
+	kind   usageKind
+}
+
+// UsageService counts glossary term searches, views and clicks in memory,
+// flushes them to daily totals in the background and reports on them.
+// Record calls are safe for concurrent use and do no I/O.
+type UsageService struct {
+	repo     UsageRepository
+	counters sync.Map // usageKey -> *atomic.Int64
+	interval time.Duration
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func NewUsageService(repo UsageRepository, interval time.Duration) *UsageService {
+	if interval <= 0 {
+		interval = DefaultUsageFlushInterval
+	}
+	return &UsageService{
+		repo:     repo,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// RecordSearch counts each term as having appeared in a search's results.
+func (s *UsageService) RecordSearch(termIDs ...string) {
+	for _, id := range termIDs {
+		s.record(id, usageSearch)
+	}
+}
+
+// RecordView counts a term being opened.
+func (s *UsageService) RecordView(termID string) {
+	s.record(termID, usageView)
+}
+
+// RecordClick counts a term being opened from search results.
+func (s *UsageService) RecordClick(termID string) {
+	s.record(termID, usageClick)
+}
+
+func (s *UsageService) record(termID string, kind usageKind) {
+	if termID == "" {
+		return
+	}
+	k := usageKey{termID: termID, kind: kind}
+	if v, ok := s.counters.Load(k); ok {
+		v.(*atomic.Int64).Add(1)
+		return
+	}
+	var fresh atomic.Int64
+	fresh.Add(1)
+	actual, loaded := s.counters.LoadOrStore(k, &fresh)
+	if loaded {
+		actual.(*atomic.Int64).Add(1)
+	}
+}
+
+// snapshot drains the in-memory counters.
+func (s *UsageService) snapshot() map[string]UsageCounts {
+	out := map[string]UsageCounts{}
+	s.counters.Range(func(k, v any) bool {
+		kk := k.(usageKey)
+		n := v.(*atomic.Int64).Swap(0)
+		if n == 0 {
+			return true
+		}
+		counts := out[kk.termID]
+		switch kk.kind {
+		case usageSearch:
+			counts.Searches += n
+		case usageView:
+			counts.Views += n
+		case usageClick:
+			counts.Clicks += n
+		}
+		out[kk.termID] = counts
+		return true
+	})
+	return out
+}
+
+// Flush persists the counts recorded since the last flush.
+func (s *UsageService) Flush(ctx context.Context) error {
+	deltas := s.snapshot()
+	if len(deltas) == 0 {
+		return nil
+	}
+	return s.repo.AddUsage(ctx, time.Now().UTC(), deltas)
+}
+
+// Start runs the flush loop until Stop or ctx cancellation.
+func (s *UsageService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop signals the loop to exit and blocks until the final flush completes.
+func (s *UsageService) Stop() {
+	select {
+	case <-s.stopCh:
+	default:
+		close(s.stopCh)
+	}
+	<-s.doneCh
+}
+
+func (s *UsageService) run(ctx context.Context) {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	flush := func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.Flush(flushCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to persist glossary term usage; dropping this window")
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case <-s.stopCh:
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// GetUsage returns a term's usage over the last days days.
+func (s *UsageService) GetUsage(ctx context.Context, termID string, days int) (*TermUsage, error) {
+	if days == 0 {
+		days = DefaultUsageWindowDays
+	}
+	since, err := usageSince(days)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUsage(ctx, termID, since)
+}
+
+// ListUsage returns usage for every term, ordered by filter.Sort.
+func (s *UsageService) ListUsage(ctx context.Context, filter UsageFilter) (*UsageListResult, error) {
+	if filter.Days == 0 {
+		filter.Days = DefaultUsageWindowDays
+	}
+	since, err := usageSince(filter.Days)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Sort == "" {
+		filter.Sort = UsageSortSearches
+	}
+	if _, ok := usageSortColumns[filter.Sort]; !ok {
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidInput, filter.Sort)
+	}
+
+	terms, total, err := s.repo.ListUsage(ctx, since, filter.Sort, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &UsageListResult{Terms: terms, Total: total, Days: filter.Days}, nil
+}
+
+// ListUnused returns terms created before the window that have no assets
+// and no searches, views or clicks within it, oldest first.
+func (s *UsageService) ListUnused(ctx context.Context, days, limit, offset int) (*UnusedListResult, error) {
+	if days == 0 {
+		days = DefaultUsageWindowDays
+	}
+	since, err := usageSince(days)
+	if err != nil {
+		return nil, err
+	}
+
+	terms, total, err := s.repo.ListUnused(ctx, since, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &UnusedListResult{Terms: terms, Total: total, Days: days}, nil
+}
+
+func usageSince(days int) (time.Time, error) {
+	if days < 1 || days > MaxUsageWindowDays {
+		return time.Time{}, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, MaxUsageWindowDays)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -(days - 1)), nil
+}
+
+func clickThroughRate(clicks, searches int64) float64 {
+	if searches == 0 {
+		return 0
+	}
+	return float64(clicks) / float64(searches)
+}
+
+type PostgresUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresUsageRepository(db *pgxpool.Pool) *PostgresUsageRepository {
+	return &PostgresUsageRepository{db: db}
+}
+
+func (r *PostgresUsageRepository) AddUsage(ctx context.Context, day time.Time, deltas map[string]UsageCounts) error {
+	ids := make([]string, 0, len(deltas))
+	var searches, views, clicks []int64
+	for id, counts := range deltas {
+		if _, err := uuid.Parse(id); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		searches = append(searches, counts.Searches)
+		views = append(views, counts.Views)
+		clicks = append(clicks, counts.Clicks)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO glossary_term_usage (term_id, day, searches, views, clicks)
+		SELECT t.id, $1::date, d.searches, d.views, d.clicks
+		FROM unnest($2::text[], $3::bigint[], $4::bigint[], $5::bigint[]) AS d(term_id, searches, views, clicks)
+		JOIN glossary_terms t ON t.id = d.term_id::uuid
+		ON CONFLICT (term_id, day) DO UPDATE
+		SET searches = glossary_term_usage.searches + EXCLUDED.searches,
+		    views = glossary_term_usage.views + EXCLUDED.views,
+		    clicks = glossary_term_usage.clicks + EXCLUDED.clicks,
+		    updated_at = NOW()`,
+		day, ids, searches, views, clicks)
+	if err != nil {
+		return fmt.Errorf("adding glossary term usage: %w", err)
+	}
+	return nil
+}
+
+// termUsageQuery selects usage columns for live terms. $1 is the start of
+// the window; callers add their own conditions and ordering.
+const termUsageQuery = `
+	WITH usage AS (
+		SELECT term_id, SUM(searches) AS searches, SUM(views) AS views,
+		       SUM(clicks) AS clicks, MAX(day) AS last_day
+		FROM glossary_term_usage
+		WHERE day >= $1
+		GROUP BY term_id
+	)
+	SELECT t.id, t.name, t.parent_term_id,
+	       (SELECT COUNT(*) FROM asset_terms at WHERE at.glossary_term_id = t.id) AS asset_count,
+	       COALESCE(u.searches, 0) AS searches, COALESCE(u.views, 0) AS views,
+	       COALESCE(u.clicks, 0) AS clicks, u.last_day::timestamptz,
+	       COUNT(*) OVER ()
+	FROM glossary_terms t
+	LEFT JOIN usage u ON u.term_id = t.id
+	WHERE t.deleted_at IS NULL`
+
+func scanTermUsage(row pgx.Row) (*TermUsage, int, error) {
+	var u TermUsage
+	var total int
+	if err := row.Scan(&u.TermID, &u.Name, &u.ParentTermID, &u.AssetCount,
+		&u.Searches, &u.Views, &u.Clicks, &u.LastUsedAt, &total); err != nil {
+		return nil, 0, err
+	}
+	u.ClickThroughRate = clickThroughRate(u.Clicks, u.Searches)
+	return &u, total, nil
+}
+
+func (r *PostgresUsageRepository) GetUsage(ctx context.Context, termID string, since time.Time) (*TermUsage, error) {
+	if _, err := uuid.Parse(termID); err != nil {
+		return nil, ErrTermNotFound
+	}
+
+	usage, _, err := scanTermUsage(r.db.QueryRow(ctx, termUsageQuery+` AND t.id = $2`, since, termID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTermNotFound
+		}
+		return nil, fmt.Errorf("getting glossary term usage: %w", err)
+	}
+	return usage, nil
+}
+
+func (r *PostgresUsageRepository) ListUsage(ctx context.Context, since time.Time, sort string, limit, offset int) ([]*TermUsage, int, error) {
+	orderBy, ok := usageSortColumns[sort]
+	if !ok {
+		orderBy = usageSortColumns[UsageSortSearches]
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.Query(ctx, termUsageQuery+`
+		ORDER BY `+orderBy+`, t.name ASC
+		LIMIT $2 OFFSET $3`, since, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing glossary term usage: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []*TermUsage{}
+	total := 0
+	for rows.Next() {
+		usage, count, err := scanTermUsage(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning glossary term usage: %w", err)
+		}
+		terms = append(terms, usage)
+		total = count
+	}
+	return terms, total, rows.Err()
+}
+
+func (r *PostgresUsageRepository) ListUnused(ctx context.Context, since time.Time, limit, offset int) ([]*UnusedTerm, int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT t.id, t.name, t.parent_term_id,
+		       (SELECT COUNT(*) FROM glossary_terms c
+		        WHERE c.parent_term_id = t.id AND c.deleted_at IS NULL) AS child_count,
+		       t.created_at, t.updated_at,
+		       (SELECT MAX(day)::timestamptz FROM glossary_term_usage u
+		        WHERE u.term_id = t.id AND u.searches + u.views + u.clicks > 0) AS last_used,
+		       COUNT(*) OVER ()
+		FROM glossary_terms t
+		WHERE t.deleted_at IS NULL
+		  AND t.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM asset_terms at WHERE at.glossary_term_id = t.id)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM glossary_term_usage u
+		      WHERE u.term_id = t.id AND u.day >= $1
+		        AND u.searches + u.views + u.clicks > 0)
+		ORDER BY t.created_at ASC, t.name ASC
+		LIMIT $2 OFFSET $3`, since, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing unused glossary terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []*UnusedTerm{}
+	total := 0
+	for rows.Next() {
+		var t UnusedTerm
+		if err := rows.Scan(&t.TermID, &t.Name, &t.ParentTermID, &t.ChildCount,
+			&t.CreatedAt, &t.UpdatedAt, &t.LastUsedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("scanning unused glossary term: %w", err)
+		}
+		terms = append(terms, &t)
+	}
+	return terms, total, rows.Err()
+}
@@ -0,0 +1,85 @@
+package glossary
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsageRepo struct {
+	UsageRepository
+	added []map[string]UsageCounts
+	since time.Time
+}
+
+func (f *fakeUsageRepo) AddUsage(_ context.Context, _ time.Time, deltas map[string]UsageCounts) error {
+	f.added = append(f.added, deltas)
+	return nil
+}
+
+func (f *fakeUsageRepo) ListUsage(_ context.Context, since time.Time, _ string, _, _ int) ([]*TermUsage, int, error) {
+	f.since = since
+	return []*TermUsage{}, 0, nil
+}
+
+func TestUsageServiceFlush(t *testing.T) {
+	repo := &fakeUsageRepo{}
+	svc := NewUsageService(repo, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.RecordSearch("a", "b")
+			svc.RecordView("a")
+			svc.RecordClick("")
+		}()
+	}
+	wg.Wait()
+	svc.RecordClick("b")
+
+	require.NoError(t, svc.Flush(context.Background()))
+	require.Len(t, repo.added, 1)
+	assert.Equal(t, UsageCounts{Searches: 50, Views: 50}, repo.added[0]["a"])
+	assert.Equal(t, UsageCounts{Searches: 50, Clicks: 1}, repo.added[0]["b"])
+
+	// Counts are drained, so nothing is written twice.
+	require.NoError(t, svc.Flush(context.Background()))
+	assert.Len(t, repo.added, 1)
+}
+
+func TestUsageServiceStopFlushes(t *testing.T) {
+	repo := &fakeUsageRepo{}
+	svc := NewUsageService(repo, time.Hour)
+	svc.Start(context.Background())
+
+	svc.RecordView("a")
+	svc.Stop()
+
+	require.Len(t, repo.added, 1)
+	assert.Equal(t, int64(1), repo.added[0]["a"].Views)
+}
+
+func TestUsageServiceListUsage(t *testing.T) {
+	repo := &fakeUsageRepo{}
+	svc := NewUsageService(repo, 0)
+	ctx := context.Background()
+
+	result, err := svc.ListUsage(ctx, UsageFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUsageWindowDays, result.Days)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	assert.Equal(t, today.AddDate(0, 0, -(DefaultUsageWindowDays-1)), repo.since)
+
+	_, err = svc.ListUsage(ctx, UsageFilter{Sort: "popularity"})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = svc.ListUsage(ctx, UsageFilter{Days: MaxUsageWindowDays + 1})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
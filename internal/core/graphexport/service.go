@@ -0,0 +1,236 @@
+// Package graphexport streams the whole catalog graph, assets, lineage edges
+// and ownership, as newline-delimited JSON for offline analysis.
+package graphexport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	DefaultLimit = 10000
+	MaxLimit     = 100000
+
+	// batchSize is how many records are read from the database at a time.
+	batchSize = 1000
+)
+
+var ErrInvalidCursor = errors.New("invalid export cursor")
+
+// Record kinds, in the order they are exported.
+const (
+	KindAsset = "asset"
+	KindEdge  = "edge"
+	KindOwner = "owner"
+	// KindEnd is the last line of every response.
+	KindEnd = "end"
+)
+
+var sections = []string{KindAsset, KindEdge, KindOwner}
+
+// Asset is an exported asset. Schemas are left out to keep lines small.
+type Asset struct {
+	ID          string                 `json:"id"`
+	MRN         string                 `json:"mrn"`
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Providers   []string               `json:"providers"`
+	Description *string                `json:"description,omitempty"`
+	Tags        []string               `json:"tags"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	IsStub      bool                   `json:"is_stub"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	LastSyncAt  time.Time              `json:"last_sync_at"`
+} // @name GraphExportAsset
+
+// Edge is an exported lineage edge between two asset MRNs.
+type Edge struct {
+	ID     string  `json:"id"`
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Type   string  `json:"type"`
+	Origin string  `json:"origin"`
+	JobMRN *string `json:"job_mrn,omitempty"`
+} // @name GraphExportEdge
+
+// Owner links an asset to a user or team that owns it.
+type Owner struct {
+	ID        string `json:"id"`
+	AssetID   string `json:"asset_id"`
+	AssetMRN  string `json:"asset_mrn"`
+	OwnerType string `json:"owner_type" enums:"user,team"`
+	OwnerID   string `json:"owner_id"`
+	OwnerName string `json:"owner_name"`
+} // @name GraphExportOwner
+
+// Line is one line of an export. Every record carries the cursor that
+// resumes the export after it, so a client whose connection drops can
+// continue from the last line it received.
+type Line struct {
+	Kind   string      `json:"kind" enums:"asset,edge,owner,end"`
+	Cursor string      `json:"cursor,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+	// NextCursor is set on the end line when there is more to export.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Count is the number of records in the response, on the end line.
+	Count int `json:"count,omitempty"`
+} // @name GraphExportLine
+
+// position is the decoded form of a cursor: the section being exported and
+// the key of the last record written from it.
+type position struct {
+	Section string `json:"s"`
+	After   string `json:"a,omitempty"`
+}
+
+func encodeCursor(p position) string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (position, error) {
+	if cursor == "" {
+		return position{Section: KindAsset}, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position{}, ErrInvalidCursor
+	}
+	var p position
+	if err := json.Unmarshal(data, &p); err != nil {
+		return position{}, ErrInvalidCursor
+	}
+	if sectionIndex(p.Section) < 0 {
+		return position{}, ErrInvalidCursor
+	}
+	return p, nil
+}
+
+// ValidateCursor reports whether cursor can resume an export, so callers can
+// reject it before starting a response.
+func ValidateCursor(cursor string) error {
+	_, err := decodeCursor(cursor)
+	return err
+}
+
+func sectionIndex(section string) int {
+	for i, s := range sections {
+		if s == section {
+			return i
+		}
+	}
+	return -1
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Export writes up to limit records to w as newline-delimited JSON, starting
+// after cursor, followed by an end line. A response without an end line was
+// cut short and can be resumed from the cursor of its last record. The export
+// is not a snapshot: records changed while a client pages through may be
+// missed or repeated.
+func (s *Service) Export(ctx context.Context, w io.Writer, cursor string, limit int) error {
+	pos, err := decodeCursor(cursor)
+	if err != nil {
+		return err
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	enc := json.NewEncoder(w)
+	count := 0
+
+	for i := sectionIndex(pos.Section); i < len(sections); i++ {
+		section := sections[i]
+		after := ""
+		if section == pos.Section {
+			after = pos.After
+		}
+
+		for count < limit {
+			n := batchSize
+			if remaining := limit - count; remaining < n {
+				n = remaining
+			}
+
+			records, err := s.batch(ctx, section, after, n)
+			if err != nil {
+				return err
+			}
+
+			for _, r := range records {
+				after = r.key
+				line := Line{Kind: section, Cursor: encodeCursor(position{Section: section, After: after}), Data: r.data}
+				if err := enc.Encode(line); err != nil {
+					return fmt.Errorf("writing %s: %w", section, err)
+				}
+				count++
+			}
+			if flusher, ok := w.(interface{ Flush() }); ok {
+				flusher.Flush()
+			}
+
+			if len(records) < n {
+				break
+			}
+		}
+
+		if count >= limit {
+			end := Line{Kind: KindEnd, Count: count, NextCursor: encodeCursor(position{Section: section, After: after})}
+			return enc.Encode(end)
+		}
+	}
+
+	return enc.Encode(Line{Kind: KindEnd, Count: count})
+}
+
+type record struct {
+	key  string
+	data interface{}
+}
+
+func (s *Service) batch(ctx context.Context, section, after string, limit int) ([]record, error) {
+	var records []record
+	switch section {
+	case KindAsset:
+		assets, err := s.repo.ListAssets(ctx, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assets {
+			records = append(records, record{key: a.ID, data: a})
+		}
+	case KindEdge:
+		edges, err := s.repo.ListEdges(ctx, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			records = append(records, record{key: e.ID, data: e})
+		}
+	case KindOwner:
+		owners, err := s.repo.ListOwners(ctx, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range owners {
+			records = append(records, record{key: o.ID, data: o})
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,124 @@
+package graphexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	assets []*Asset
+	edges  []*Edge
+	owners []*Owner
+}
+
+func page[T any](items []T, key func(T) string, after string, limit int) []T {
+	i := sort.Search(len(items), func(i int) bool { return key(items[i]) > after })
+	end := i + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[i:end]
+}
+
+func (f *fakeRepo) ListAssets(_ context.Context, after string, limit int) ([]*Asset, error) {
+	return page(f.assets, func(a *Asset) string { return a.ID }, after, limit), nil
+}
+
+func (f *fakeRepo) ListEdges(_ context.Context, after string, limit int) ([]*Edge, error) {
+	return page(f.edges, func(e *Edge) string { return e.ID }, after, limit), nil
+}
+
+func (f *fakeRepo) ListOwners(_ context.Context, after string, limit int) ([]*Owner, error) {
+	return page(f.owners, func(o *Owner) string { return o.ID }, after, limit), nil
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		assets: []*Asset{{ID: "a1", MRN: "mrn://table/pg/orders"}, {ID: "a2", MRN: "mrn://table/pg/users"}, {ID: "a3", MRN: "mrn://topic/kafka/events"}},
+		edges:  []*Edge{{ID: "e1", Source: "mrn://topic/kafka/events", Target: "mrn://table/pg/orders"}},
+		owners: []*Owner{{ID: "o1", AssetID: "a1", OwnerType: "team", OwnerName: "payments"}, {ID: "o2", AssetID: "a2", OwnerType: "user", OwnerName: "Ada"}},
+	}
+}
+
+func readLines(t *testing.T, buf *bytes.Buffer) []Line {
+	t.Helper()
+	var lines []Line
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var l Line
+		require.NoError(t, dec.Decode(&l))
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func kinds(lines []Line) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.Kind
+	}
+	return out
+}
+
+func TestExportWholeGraph(t *testing.T) {
+	svc := NewService(newFakeRepo())
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), &buf, "", 0))
+
+	lines := readLines(t, &buf)
+	assert.Equal(t, []string{"asset", "asset", "asset", "edge", "owner", "owner", "end"}, kinds(lines))
+	end := lines[len(lines)-1]
+	assert.Empty(t, end.NextCursor)
+	assert.Equal(t, 6, end.Count)
+}
+
+func TestExportResumesAcrossSections(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	var all []string
+	cursor := ""
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		require.NoError(t, svc.Export(ctx, &buf, cursor, 2))
+		lines := readLines(t, &buf)
+		for _, l := range lines[:len(lines)-1] {
+			all = append(all, l.Kind)
+		}
+		cursor = lines[len(lines)-1].NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"asset", "asset", "asset", "edge", "owner", "owner"}, all)
+}
+
+func TestExportFromRecordCursor(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(ctx, &buf, "", 0))
+	lines := readLines(t, &buf)
+
+	// Resume after the edge, as a client whose connection dropped would.
+	buf.Reset()
+	require.NoError(t, svc.Export(ctx, &buf, lines[3].Cursor, 0))
+	assert.Equal(t, []string{"owner", "owner", "end"}, kinds(readLines(t, &buf)))
+}
+
+func TestExportInvalidCursor(t *testing.T) {
+	svc := NewService(newFakeRepo())
+
+	for _, cursor := range []string{"not base64!", encodeCursor(position{Section: "schema"})} {
+		assert.ErrorIs(t, ValidateCursor(cursor), ErrInvalidCursor)
+		assert.ErrorIs(t, svc.Export(context.Background(), &bytes.Buffer{}, cursor, 0), ErrInvalidCursor)
+	}
+}
@@ -0,0 +1,121 @@
+package graphexport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository reads the catalog graph in key order, so each call resumes
+// after the last key of the previous one.
+type Repository interface {
+	ListAssets(ctx context.Context, after string, limit int) ([]*Asset, error)
+	ListEdges(ctx context.Context, after string, limit int) ([]*Edge, error)
+	ListOwners(ctx context.Context, after string, limit int) ([]*Owner, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListAssets(ctx context.Context, after string, limit int) ([]*Asset, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, mrn, name, type, providers, COALESCE(user_description, description),
+			tags, metadata, is_stub, created_at, updated_at, last_sync_at
+		FROM assets
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying assets: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []*Asset{}
+	for rows.Next() {
+		var a Asset
+		if err := rows.Scan(&a.ID, &a.MRN, &a.Name, &a.Type, &a.Providers, &a.Description,
+			&a.Tags, &a.Metadata, &a.IsStub, &a.CreatedAt, &a.UpdatedAt, &a.LastSyncAt); err != nil {
+			return nil, fmt.Errorf("scanning asset: %w", err)
+		}
+		assets = append(assets, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+func (r *PostgresRepository) ListEdges(ctx context.Context, after string, limit int) ([]*Edge, error) {
+	if after == "" {
+		after = "00000000-0000-0000-0000-000000000000"
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, source_mrn, target_mrn, COALESCE(type, 'DIRECT'), origin, job_mrn
+		FROM lineage_edges
+		WHERE id > $1::uuid
+		ORDER BY id
+		LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []*Edge{}
+	for rows.Next() {
+		var e Edge
+		if err := rows.Scan(&e.ID, &e.Source, &e.Target, &e.Type, &e.Origin, &e.JobMRN); err != nil {
+			return nil, fmt.Errorf("scanning edge: %w", err)
+		}
+		edges = append(edges, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating edges: %w", err)
+	}
+
+	return edges, nil
+}
+
+func (r *PostgresRepository) ListOwners(ctx context.Context, after string, limit int) ([]*Owner, error) {
+	if after == "" {
+		after = "00000000-0000-0000-0000-000000000000"
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT o.id, o.asset_id, a.mrn,
+			CASE WHEN o.user_id IS NOT NULL THEN 'user' ELSE 'team' END,
+			COALESCE(o.user_id, o.team_id)::text,
+			COALESCE(u.name, t.name, '')
+		FROM asset_owners o
+		JOIN assets a ON a.id = o.asset_id
+		LEFT JOIN users u ON u.id = o.user_id
+		LEFT JOIN teams t ON t.id = o.team_id
+		WHERE o.id > $1::uuid
+		ORDER BY o.id
+		LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := []*Owner{}
+	for rows.Next() {
+		var o Owner
+		if err := rows.Scan(&o.ID, &o.AssetID, &o.AssetMRN, &o.OwnerType, &o.OwnerID, &o.OwnerName); err != nil {
+			return nil, fmt.Errorf("scanning owner: %w", err)
+		}
+		owners = append(owners, &o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating owners: %w", err)
+	}
+
+	return owners, nil
+}
@@ -0,0 +1,130 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const (
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultLockTimeout is how long an unfinished request holds its key
+	// before a retry may take it over, e.g. after a server restart.
+	DefaultLockTimeout = 5 * time.Minute
+
+	// MaxKeyLength matches the width of the key column.
+	MaxKeyLength = 255
+)
+
+var (
+	// ErrConflict means the key was released while it was being reserved.
+	ErrConflict = errors.New("idempotency key changed concurrently")
+)
+
+// Record is a reserved idempotency key and, once the request finishes, the
+// response to replay.
+type Record struct {
+	Scope       string
+	Key         string
+	Method      string
+	Path        string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	LockedAt    time.Time
+	CompletedAt *time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the original request has finished.
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Service reserves idempotency keys and stores the responses they replay.
+type Service struct {
+	repo        Repository
+	ttl         time.Duration
+	lockTimeout time.Duration
+	cleanup     *background.SingletonTask
+}
+
+// NewService creates an idempotency service. Keys and their responses are
+// kept for ttl.
+func NewService(repo Repository, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Service{
+		repo:        repo,
+		ttl:         ttl,
+		lockTimeout: DefaultLockTimeout,
+	}
+}
+
+// Fingerprint identifies a request by method, path and body, so a key reused
+// for a different request can be rejected.
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin reserves key for a request. When the key is already held it returns
+// the existing record and false; the caller should replay it if completed,
+// and reject the request otherwise.
+func (s *Service) Begin(ctx context.Context, scope, key, method, path, fingerprint string) (*Record, bool, error) {
+	now := time.Now()
+	return s.repo.Reserve(ctx, &Record{
+		Scope:       scope,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(s.ttl),
+	}, now.Add(-s.lockTimeout))
+}
+
+// Complete stores the response for a reserved key.
+func (s *Service) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	return s.repo.Complete(ctx, scope, key, statusCode, contentType, body)
+}
+
+// Release frees a reserved key so the request can be retried, e.g. after a
+// server error.
+func (s *Service) Release(ctx context.Context, scope, key string) error {
+	return s.repo.Release(ctx, scope, key)
+}
+
+// StartCleanup periodically deletes expired keys.
+func (s *Service) StartCleanup(ctx context.Context, db *pgxpool.Pool) {
+	s.cleanup = background.NewSingletonTask(background.SingletonConfig{
+		Name:         "idempotency-key-cleanup",
+		DB:           db,
+		Interval:     time.Hour,
+		InitialDelay: 10 * time.Minute,
+		TaskFn: func(ctx context.Context) error {
+			_, err := s.repo.DeleteExpired(ctx)
+			return err
+		},
+	})
+	s.cleanup.Start(ctx)
+}
+
+// Stop halts the cleanup task.
+func (s *Service) Stop() {
+	if s.cleanup != nil {
+		s.cleanup.Stop()
+	}
+}
@@ -0,0 +1,151 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memRepo keeps keys in memory and takes a held key over under the same
+// conditions as the ON CONFLICT clause in PostgresRepository.Reserve.
+type memRepo struct {
+	records     map[string]*Record
+	staleBefore time.Time
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{records: map[string]*Record{}}
+}
+
+func (r *memRepo) Reserve(_ context.Context, rec *Record, staleBefore time.Time) (*Record, bool, error) {
+	r.staleBefore = staleBefore
+	now := time.Now()
+	if existing, ok := r.records[rec.Scope+"/"+rec.Key]; ok {
+		expired := existing.ExpiresAt.Before(now)
+		stale := !existing.Completed() && existing.LockedAt.Before(staleBefore)
+		if !expired && !stale {
+			copied := *existing
+			return &copied, false, nil
+		}
+	}
+	reserved := *rec
+	reserved.LockedAt = now
+	r.records[rec.Scope+"/"+rec.Key] = &reserved
+	return &reserved, true, nil
+}
+
+func (r *memRepo) Complete(_ context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	rec := r.records[scope+"/"+key]
+	now := time.Now()
+	rec.StatusCode, rec.ContentType, rec.Body, rec.CompletedAt = statusCode, contentType, body, &now
+	return nil
+}
+
+func (r *memRepo) Release(_ context.Context, scope, key string) error {
+	if rec, ok := r.records[scope+"/"+key]; ok && !rec.Completed() {
+		delete(r.records, scope+"/"+key)
+	}
+	return nil
+}
+
+func (r *memRepo) DeleteExpired(context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("POST", "/api/v1/assets", []byte(`{"name":"orders"}`))
+
+	assert.Equal(t, base, Fingerprint("POST", "/api/v1/assets", []byte(`{"name":"orders"}`)))
+	assert.NotEqual(t, base, Fingerprint("PUT", "/api/v1/assets", []byte(`{"name":"orders"}`)))
+	assert.NotEqual(t, base, Fingerprint("POST", "/api/v1/lineage", []byte(`{"name":"orders"}`)))
+	assert.NotEqual(t, base, Fingerprint("POST", "/api/v1/assets", []byte(`{"name":"customers"}`)))
+	// Fields are separated, so moving bytes between them changes the result.
+	assert.NotEqual(t, Fingerprint("POST", "/a", []byte("b")), Fingerprint("POST", "/ab", nil))
+}
+
+func TestBegin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		existing     *Record
+		wantReserved bool
+		wantStatus   int
+	}{
+		{name: "new key", wantReserved: true},
+		{
+			name:       "completed key replays",
+			existing:   &Record{StatusCode: 201, LockedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+			wantStatus: 201,
+		},
+		{
+			name:     "key in progress",
+			existing: &Record{LockedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:         "stale lock taken over",
+			existing:     &Record{LockedAt: now.Add(-DefaultLockTimeout - time.Minute), ExpiresAt: now.Add(time.Hour)},
+			wantReserved: true,
+		},
+		{
+			name:       "old completed key is not taken over",
+			existing:   &Record{StatusCode: 200, LockedAt: now.Add(-DefaultLockTimeout - time.Minute), ExpiresAt: now.Add(time.Hour)},
+			wantStatus: 200,
+		},
+		{
+			name:         "expired key reused",
+			existing:     &Record{StatusCode: 200, LockedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+			wantReserved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo()
+			if tt.existing != nil {
+				tt.existing.Scope, tt.existing.Key, tt.existing.Fingerprint = "user:1", "key-1", "old"
+				repo.records["user:1/key-1"] = tt.existing
+			}
+			svc := NewService(repo, 0)
+
+			rec, reserved, err := svc.Begin(ctx, "user:1", "key-1", "POST", "/api/v1/assets", "new")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReserved, reserved)
+			assert.Equal(t, tt.wantStatus, rec.StatusCode)
+			if reserved {
+				assert.Equal(t, "new", rec.Fingerprint)
+				assert.WithinDuration(t, time.Now().Add(DefaultTTL), rec.ExpiresAt, time.Minute)
+			} else {
+				assert.Equal(t, "old", rec.Fingerprint)
+			}
+			assert.WithinDuration(t, time.Now().Add(-DefaultLockTimeout), repo.staleBefore, time.Minute)
+		})
+	}
+}
+
+func TestReleaseKeepsCompletedKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	svc := NewService(repo, time.Hour)
+
+	_, reserved, err := svc.Begin(ctx, "user:1", "failed", "POST", "/p", "f")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, svc.Release(ctx, "user:1", "failed"))
+
+	_, reserved, err = svc.Begin(ctx, "user:1", "failed", "POST", "/p", "f")
+	require.NoError(t, err)
+	assert.True(t, reserved, "a released key can be retried")
+
+	require.NoError(t, svc.Complete(ctx, "user:1", "failed", 201, "application/json", []byte(`{}`)))
+	require.NoError(t, svc.Release(ctx, "user:1", "failed"))
+
+	rec, reserved, err := svc.Begin(ctx, "user:1", "failed", "POST", "/p", "f")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, rec.StatusCode)
+}
@@ -0,0 +1,117 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the idempotency key data access interface.
+type Repository interface {
+	// Reserve claims key for a new request. It returns the existing record
+	// and false when the key is held by an unexpired request, unless that
+	// request was locked before staleBefore and never completed.
+	Reserve(ctx context.Context, rec *Record, staleBefore time.Time) (*Record, bool, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Reserve(ctx context.Context, rec *Record, staleBefore time.Time) (*Record, bool, error) {
+	var lockedAt time.Time
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (scope, key, method, path, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET method = EXCLUDED.method,
+		    path = EXCLUDED.path,
+		    fingerprint = EXCLUDED.fingerprint,
+		    status_code = NULL,
+		    content_type = NULL,
+		    response_body = NULL,
+		    locked_at = NOW(),
+		    completed_at = NULL,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_at < $7)
+		RETURNING locked_at`,
+		rec.Scope, rec.Key, rec.Method, rec.Path, rec.Fingerprint, rec.ExpiresAt, staleBefore,
+	).Scan(&lockedAt)
+	if err == nil {
+		rec.LockedAt = lockedAt
+		return rec, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("reserving idempotency key: %w", err)
+	}
+
+	existing := &Record{}
+	var statusCode *int
+	var contentType *string
+	err = r.db.QueryRow(ctx, `
+		SELECT scope, key, method, path, fingerprint, status_code, content_type,
+		       response_body, locked_at, completed_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2`,
+		rec.Scope, rec.Key,
+	).Scan(
+		&existing.Scope, &existing.Key, &existing.Method, &existing.Path, &existing.Fingerprint,
+		&statusCode, &contentType, &existing.Body, &existing.LockedAt, &existing.CompletedAt, &existing.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released between the insert and the read; let the caller retry.
+			return nil, false, ErrConflict
+		}
+		return nil, false, fmt.Errorf("getting idempotency key: %w", err)
+	}
+	if statusCode != nil {
+		existing.StatusCode = *statusCode
+	}
+	if contentType != nil {
+		existing.ContentType = *contentType
+	}
+
+	return existing, false, nil
+}
+
+func (r *PostgresRepository) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5, completed_at = NOW()
+		WHERE scope = $1 AND key = $2`,
+		scope, key, statusCode, contentType, body,
+	)
+	if err != nil {
+		return fmt.Errorf("completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Release(ctx context.Context, scope, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code IS NULL`, scope, key)
+	if err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteExpired(ctx context.Context) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired idempotency keys: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"net/http"
 
+	"golang.org/x/image/draw"
 	"golang.org/x/image/webp"
 )
 
@@ -134,3 +136,55 @@ func sanitizeWebP(data []byte) (*SanitizeResult, error) {
 
 	return &SanitizeResult{Data: buf.Bytes(), ContentType: "image/png"}, nil
 }
+
+// Thumbnail decodes an image, scales it down to fit within maxWidth by
+// maxHeight keeping its aspect ratio, and encodes it as a JPEG. Transparent
+// areas are flattened onto white. Images already small enough are only
+// re-encoded, which also strips any non-image payload.
+func Thumbnail(data []byte, maxWidth, maxHeight int) (*SanitizeResult, error) {
+	var (
+		img image.Image
+		err error
+	)
+	switch detected := http.DetectContentType(data); detected {
+	case "image/jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	case "image/webp":
+		img, err = webp.Decode(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, detected)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecodeFailed, err)
+	}
+	if err := checkDimensions(img); err != nil {
+		return nil, err
+	}
+
+	src := img.Bounds()
+	w, h := src.Dx(), src.Dy()
+	if w > maxWidth {
+		h = h * maxWidth / w
+		w = maxWidth
+	}
+	if h > maxHeight {
+		w = w * maxHeight / h
+		h = maxHeight
+	}
+	w, h = max(w, 1), max(h, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncodeFailed, err)
+	}
+
+	return &SanitizeResult{Data: buf.Bytes(), ContentType: "image/jpeg"}, nil
+}
@@ -147,3 +147,30 @@ func TestPolyglotStripping(t *testing.T) {
 	assert.NotContains(t, string(result.Data), "script")
 	assert.NotEqual(t, polyglot, result.Data)
 }
+
+func TestThumbnail(t *testing.T) {
+	result, err := Thumbnail(createTestPNG(t, 1600, 900), 400, 300)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", result.ContentType)
+
+	img, err := jpeg.Decode(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	assert.Equal(t, 400, img.Bounds().Dx())
+	assert.Equal(t, 225, img.Bounds().Dy())
+
+	result, err = Thumbnail(createTestJPEG(t, 100, 800), 400, 300)
+	require.NoError(t, err)
+	img, err = jpeg.Decode(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	assert.Equal(t, 37, img.Bounds().Dx())
+	assert.Equal(t, 300, img.Bounds().Dy())
+
+	result, err = Thumbnail(createTestGIF(t, 20, 10, 1), 400, 300)
+	require.NoError(t, err)
+	img, err = jpeg.Decode(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	assert.Equal(t, 20, img.Bounds().Dx())
+
+	_, err = Thumbnail([]byte("not an image"), 400, 300)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,239 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultPollInterval = 15 * time.Minute
+
+// StatusPoller looks up the current status of a link in its tracker.
+type StatusPoller interface {
+	// Provider is the link provider this poller handles, e.g. "jira".
+	Provider() string
+	// Status returns one of the link statuses for the external item.
+	Status(ctx context.Context, link *Link) (string, error)
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// getJSON performs req and decodes the JSON body into dst. Any status other
+// than 200 is an error, so the link keeps its last known status.
+func getJSON(ctx context.Context, client *http.Client, req *http.Request, dst interface{}) error {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// JiraPoller reads issue status categories from Jira Cloud or Server.
+// External IDs are issue keys such as DATA-123.
+type JiraPoller struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Client   *http.Client
+}
+
+func (p *JiraPoller) Provider() string { return ProviderJira }
+
+func (p *JiraPoller) Status(ctx context.Context, link *Link) (string, error) {
+	endpoint := strings.TrimRight(p.BaseURL, "/") + "/rest/api/2/issue/" + url.PathEscape(link.ExternalID) + "?fields=status"
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Email != "" {
+		req.SetBasicAuth(p.Email, p.APIToken)
+	} else if p.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIToken)
+	}
+
+	var issue struct {
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := getJSON(ctx, clientOrDefault(p.Client), req, &issue); err != nil {
+		return "", err
+	}
+
+	switch issue.Fields.Status.StatusCategory.Key {
+	case "new":
+		return StatusOpen, nil
+	case "indeterminate":
+		return StatusInProgress, nil
+	case "done":
+		return StatusResolved, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
+
+// GitHubPoller reads issue state from GitHub. External IDs are
+// owner/repo#number.
+type GitHubPoller struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func (p *GitHubPoller) Provider() string { return ProviderGitHub }
+
+func (p *GitHubPoller) Status(ctx context.Context, link *Link) (string, error) {
+	repo, number, ok := strings.Cut(link.ExternalID, "#")
+	if !ok || strings.Count(repo, "/") != 1 || number == "" {
+		return "", fmt.Errorf("external ID %q is not in owner/repo#number form", link.ExternalID)
+	}
+
+	base := p.BaseURL
+	if base == "" {
+		base = "https://api.github.com"
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(base, "/")+"/repos/"+repo+"/issues/"+url.PathEscape(number), nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	var issue struct {
+		State       string `json:"state"`
+		StateReason string `json:"state_reason"`
+	}
+	if err := getJSON(ctx, clientOrDefault(p.Client), req, &issue); err != nil {
+		return "", err
+	}
+
+	switch {
+	case issue.State == "open":
+		return StatusOpen, nil
+	case issue.State == "closed" && issue.StateReason == "completed":
+		return StatusResolved, nil
+	case issue.State == "closed":
+		return StatusClosed, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
+
+// PagerDutyPoller reads incident status from PagerDuty. External IDs are
+// incident IDs such as Q1ABC2DEF3GHIJ.
+type PagerDutyPoller struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func (p *PagerDutyPoller) Provider() string { return ProviderPagerDuty }
+
+func (p *PagerDutyPoller) Status(ctx context.Context, link *Link) (string, error) {
+	base := p.BaseURL
+	if base == "" {
+		base = "https://api.pagerduty.com"
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(base, "/")+"/incidents/"+url.PathEscape(link.ExternalID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Token token="+p.Token)
+
+	var body struct {
+		Incident struct {
+			Status string `json:"status"`
+		} `json:"incident"`
+	}
+	if err := getJSON(ctx, clientOrDefault(p.Client), req, &body); err != nil {
+		return "", err
+	}
+
+	switch body.Incident.Status {
+	case "triggered":
+		return StatusOpen, nil
+	case "acknowledged":
+		return StatusInProgress, nil
+	case "resolved":
+		return StatusResolved, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
+
+func clientOrDefault(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return defaultHTTPClient
+}
+
+// Poller periodically refreshes the status of active links.
+type Poller struct {
+	task *background.SingletonTask
+}
+
+// PollerConfig configures the poller.
+type PollerConfig struct {
+	// Interval between polls. Default: 15 minutes.
+	Interval time.Duration
+	// BatchSize caps the links refreshed per poll. Default: 200.
+	BatchSize int
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewPoller creates a new status poller for svc.
+func NewPoller(svc *Service, config *PollerConfig) *Poller {
+	if config == nil {
+		config = &PollerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultPollInterval
+	}
+
+	return &Poller{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "incident-status-poller",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.SyncStatuses(ctx, config.BatchSize)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins the periodic polling loop.
+func (p *Poller) Start(ctx context.Context) {
+	p.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the poller.
+func (p *Poller) Stop() {
+	p.task.Stop()
+}
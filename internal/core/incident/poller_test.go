@@ -0,0 +1,129 @@
+package incident
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJiraPoller_Status(t *testing.T) {
+	categories := map[string]string{
+		"DATA-1": "new",
+		"DATA-2": "indeterminate",
+		"DATA-3": "done",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot@example.com", user)
+		assert.Equal(t, "token", pass)
+
+		key := r.URL.Path[len("/rest/api/2/issue/"):]
+		category, found := categories[key]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"fields":{"status":{"statusCategory":{"key":"` + category + `"}}}}`))
+	}))
+	defer srv.Close()
+
+	p := &JiraPoller{BaseURL: srv.URL, Email: "bot@example.com", APIToken: "token"}
+	for key, want := range map[string]string{
+		"DATA-1": StatusOpen,
+		"DATA-2": StatusInProgress,
+		"DATA-3": StatusResolved,
+	} {
+		got, err := p.Status(context.Background(), &Link{ExternalID: key})
+		require.NoError(t, err)
+		assert.Equal(t, want, got, key)
+	}
+
+	_, err := p.Status(context.Background(), &Link{ExternalID: "DATA-404"})
+	assert.Error(t, err)
+}
+
+func TestGitHubPoller_Status(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gh-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/acme/pipelines/issues/1":
+			_, _ = w.Write([]byte(`{"state":"open"}`))
+		case "/repos/acme/pipelines/issues/2":
+			_, _ = w.Write([]byte(`{"state":"closed","state_reason":"completed"}`))
+		case "/repos/acme/pipelines/issues/3":
+			_, _ = w.Write([]byte(`{"state":"closed","state_reason":"not_planned"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := &GitHubPoller{BaseURL: srv.URL, Token: "gh-token"}
+	for id, want := range map[string]string{
+		"acme/pipelines#1": StatusOpen,
+		"acme/pipelines#2": StatusResolved,
+		"acme/pipelines#3": StatusClosed,
+	} {
+		got, err := p.Status(context.Background(), &Link{ExternalID: id})
+		require.NoError(t, err)
+		assert.Equal(t, want, got, id)
+	}
+
+	_, err := p.Status(context.Background(), &Link{ExternalID: "pipelines-1"})
+	assert.ErrorContains(t, err, "owner/repo#number")
+}
+
+type fakeRepo struct {
+	Repository
+	links  []*Link
+	synced map[string]string
+}
+
+func (f *fakeRepo) ListActiveForSync(ctx context.Context, providers []string, limit int) ([]*Link, error) {
+	return f.links, nil
+}
+
+func (f *fakeRepo) RecordSync(ctx context.Context, id, status string, syncErr *string) error {
+	f.synced[id] = status
+	return nil
+}
+
+type staticPoller struct {
+	status string
+	err    error
+}
+
+func (p *staticPoller) Provider() string { return ProviderJira }
+
+func (p *staticPoller) Status(ctx context.Context, link *Link) (string, error) {
+	return p.status, p.err
+}
+
+func TestService_SyncStatuses(t *testing.T) {
+	repo := &fakeRepo{
+		links:  []*Link{{ID: "a", Provider: ProviderJira, Status: StatusOpen}},
+		synced: map[string]string{},
+	}
+	svc := NewService(repo)
+
+	n, err := svc.SyncStatuses(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Zero(t, n, "no pollers registered")
+
+	svc.RegisterPoller(&staticPoller{status: StatusResolved})
+	n, err = svc.SyncStatuses(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, StatusResolved, repo.synced["a"])
+
+	svc.RegisterPoller(&staticPoller{err: assert.AnError})
+	n, err = svc.SyncStatuses(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, StatusOpen, repo.synced["a"], "failed polls keep the last status")
+}
@@ -0,0 +1,332 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound      = errors.New("incident link not found")
+	ErrAlreadyExists = errors.New("incident link already exists for this asset")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Link statuses. Open, in-progress and unknown links are active: they are
+// shown as known issues and are polled for updates.
+const (
+	StatusOpen       = "open"
+	StatusInProgress = "in_progress"
+	StatusResolved   = "resolved"
+	StatusClosed     = "closed"
+	StatusUnknown    = "unknown"
+)
+
+var ValidStatuses = map[string]bool{
+	StatusOpen:       true,
+	StatusInProgress: true,
+	StatusResolved:   true,
+	StatusClosed:     true,
+	StatusUnknown:    true,
+}
+
+// IsActive reports whether a link with this status is a current issue.
+func IsActive(status string) bool {
+	return status == StatusOpen || status == StatusInProgress || status == StatusUnknown
+}
+
+// Well-known providers. Any other lowercase name is accepted and treated as
+// manually maintained.
+const (
+	ProviderJira       = "jira"
+	ProviderGitHub     = "github"
+	ProviderPagerDuty  = "pagerduty"
+	ProviderOpsgenie   = "opsgenie"
+	ProviderServiceNow = "servicenow"
+	ProviderLinear     = "linear"
+)
+
+const defaultUpstreamDepth = 5
+
+// Link points at a ticket or incident in an external tracker that affects an
+// asset. Unlike an asset's generic external links it carries a status, so
+// open issues can be surfaced on the asset and everything downstream of it.
+type Link struct {
+	ID           string     `json:"id"`
+	AssetID      string     `json:"asset_id"`
+	Provider     string     `json:"provider"`
+	ExternalID   string     `json:"external_id"`
+	URL          string     `json:"url,omitempty"`
+	Title        string     `json:"title"`
+	Status       string     `json:"status"`
+	Severity     *string    `json:"severity,omitempty"`
+	Propagate    bool       `json:"propagate"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	SyncError    *string    `json:"sync_error,omitempty"`
+	CreatedBy    *string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+} // @name IncidentLink
+
+// AffectingLink is an active link on an asset, or on one of its upstream
+// dependencies when Inherited is set.
+type AffectingLink struct {
+	Link
+	Inherited bool `json:"inherited"`
+} // @name AffectingIncidentLink
+
+type CreateInput struct {
+	Provider   string  `json:"provider"`
+	ExternalID string  `json:"external_id"`
+	URL        string  `json:"url,omitempty"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status,omitempty"`
+	Severity   *string `json:"severity,omitempty"`
+	Propagate  *bool   `json:"propagate,omitempty"`
+} // @name CreateIncidentLinkInput
+
+type UpdateInput struct {
+	URL       *string `json:"url,omitempty"`
+	Title     *string `json:"title,omitempty"`
+	Status    *string `json:"status,omitempty"`
+	Severity  *string `json:"severity,omitempty"`
+	Propagate *bool   `json:"propagate,omitempty"`
+} // @name UpdateIncidentLinkInput
+
+// UpstreamResolver returns the IDs of assets upstream of assetID.
+type UpstreamResolver interface {
+	ResolveLineageAssets(ctx context.Context, assetID string, direction string, depth int) ([]string, error)
+}
+
+type Service struct {
+	repo     Repository
+	upstream UpstreamResolver
+	pollers  map[string]StatusPoller
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, pollers: map[string]StatusPoller{}}
+}
+
+// SetUpstreamResolver enables inherited links from upstream assets.
+func (s *Service) SetUpstreamResolver(r UpstreamResolver) {
+	s.upstream = r
+}
+
+// RegisterPoller enables status polling for the poller's provider.
+func (s *Service) RegisterPoller(p StatusPoller) {
+	s.pollers[p.Provider()] = p
+}
+
+// HasPollers reports whether any provider is polled.
+func (s *Service) HasPollers() bool {
+	return len(s.pollers) > 0
+}
+
+func (s *Service) Create(ctx context.Context, assetID string, input CreateInput, createdBy *string) (*Link, error) {
+	input.Provider = strings.ToLower(strings.TrimSpace(input.Provider))
+	input.ExternalID = strings.TrimSpace(input.ExternalID)
+	if assetID == "" || input.Provider == "" || input.ExternalID == "" {
+		return nil, &ValidationError{Message: "asset_id, provider and external_id are required"}
+	}
+	if len(input.Provider) > 50 || len(input.ExternalID) > 255 {
+		return nil, &ValidationError{Message: "provider must be at most 50 and external_id at most 255 characters"}
+	}
+	if err := validateURL(input.URL); err != nil {
+		return nil, err
+	}
+	if input.Status == "" {
+		input.Status = StatusOpen
+	}
+	if !ValidStatuses[input.Status] {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid status: %q", input.Status)}
+	}
+
+	link := &Link{
+		AssetID:    assetID,
+		Provider:   input.Provider,
+		ExternalID: input.ExternalID,
+		URL:        input.URL,
+		Title:      input.Title,
+		Status:     input.Status,
+		Severity:   input.Severity,
+		Propagate:  true,
+		CreatedBy:  createdBy,
+	}
+	if input.Propagate != nil {
+		link.Propagate = *input.Propagate
+	}
+
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Link, error) {
+	link, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if err := validateURL(*input.URL); err != nil {
+			return nil, err
+		}
+		link.URL = *input.URL
+	}
+	if input.Title != nil {
+		link.Title = *input.Title
+	}
+	if input.Status != nil {
+		if !ValidStatuses[*input.Status] {
+			return nil, &ValidationError{Message: fmt.Sprintf("invalid status: %q", *input.Status)}
+		}
+		link.Status = *input.Status
+	}
+	if input.Severity != nil {
+		link.Severity = input.Severity
+	}
+	if input.Propagate != nil {
+		link.Propagate = *input.Propagate
+	}
+
+	if err := s.repo.Update(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Link, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// ListForAsset returns every link attached directly to an asset, newest
+// first, whatever its status.
+func (s *Service) ListForAsset(ctx context.Context, assetID string) ([]*Link, error) {
+	return s.repo.ListForAssets(ctx, []string{assetID}, false)
+}
+
+// ListAffecting returns the active links that affect an asset: its own, plus
+// propagating links on assets upstream of it. Consumers of a broken table
+// see the issue without anyone linking it to each dashboard by hand.
+func (s *Service) ListAffecting(ctx context.Context, assetID string, depth int) ([]*AffectingLink, error) {
+	if depth <= 0 {
+		depth = defaultUpstreamDepth
+	}
+
+	own, err := s.repo.ListForAssets(ctx, []string{assetID}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*AffectingLink, 0, len(own))
+	for _, l := range own {
+		result = append(result, &AffectingLink{Link: *l})
+	}
+
+	if s.upstream == nil {
+		return result, nil
+	}
+
+	upstreamIDs, err := s.upstream.ResolveLineageAssets(ctx, assetID, "upstream", depth)
+	if err != nil {
+		return nil, fmt.Errorf("resolving upstream assets: %w", err)
+	}
+	if len(upstreamIDs) == 0 {
+		return result, nil
+	}
+
+	inherited, err := s.repo.ListForAssets(ctx, upstreamIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range inherited {
+		if l.Propagate {
+			result = append(result, &AffectingLink{Link: *l, Inherited: true})
+		}
+	}
+
+	return result, nil
+}
+
+// SyncStatuses polls the external tracker for active links whose provider
+// has a registered poller and records the result. A failed lookup keeps the
+// previous status and stores the error on the link.
+func (s *Service) SyncStatuses(ctx context.Context, batchSize int) (int, error) {
+	if len(s.pollers) == 0 {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+
+	providers := make([]string, 0, len(s.pollers))
+	for p := range s.pollers {
+		providers = append(providers, p)
+	}
+
+	links, err := s.repo.ListActiveForSync(ctx, providers, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, link := range links {
+		if ctx.Err() != nil {
+			return synced, ctx.Err()
+		}
+
+		status, pollErr := s.pollers[link.Provider].Status(ctx, link)
+		var syncErr *string
+		if pollErr != nil {
+			msg := pollErr.Error()
+			syncErr = &msg
+			status = link.Status
+			log.Warn().Err(pollErr).Str("provider", link.Provider).Str("external_id", link.ExternalID).Msg("Failed to poll incident status")
+		}
+
+		if err := s.repo.RecordSync(ctx, link.ID, status, syncErr); err != nil {
+			return synced, err
+		}
+		if pollErr == nil {
+			synced++
+		}
+	}
+
+	return synced, nil
+}
+
+func validateURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Message: "url must be an absolute http or https URL"}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the incident link data access interface.
+type Repository interface {
+	Create(ctx context.Context, link *Link) error
+	Update(ctx context.Context, link *Link) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (*Link, error)
+	ListForAssets(ctx context.Context, assetIDs []string, activeOnly bool) ([]*Link, error)
+	ListActiveForSync(ctx context.Context, providers []string, limit int) ([]*Link, error)
+	RecordSync(ctx context.Context, id, status string, syncErr *string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectLink = `
+	SELECT id, asset_id, provider, external_id, COALESCE(url, ''), title, status, severity, propagate,
+	       last_synced_at, sync_error, created_by, created_at, updated_at
+	FROM asset_incident_links`
+
+const activeStatuses = `status IN ('open', 'in_progress', 'unknown')`
+
+func (r *PostgresRepository) Create(ctx context.Context, link *Link) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_incident_links (asset_id, provider, external_id, url, title, status, severity, propagate, created_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`,
+		link.AssetID, link.Provider, link.ExternalID, link.URL, link.Title, link.Status, link.Severity, link.Propagate, link.CreatedBy,
+	).Scan(&link.ID, &link.CreatedAt, &link.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return ErrAlreadyExists
+			case "23503":
+				return &ValidationError{Message: "asset not found"}
+			}
+		}
+		return fmt.Errorf("creating incident link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, link *Link) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE asset_incident_links
+		SET url = NULLIF($1, ''), title = $2, status = $3, severity = $4, propagate = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at`,
+		link.URL, link.Title, link.Status, link.Severity, link.Propagate, link.ID,
+	).Scan(&link.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating incident link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM asset_incident_links WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting incident link: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Link, error) {
+	link, err := scanLink(r.db.QueryRow(ctx, selectLink+` WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting incident link: %w", err)
+	}
+	return link, nil
+}
+
+func (r *PostgresRepository) ListForAssets(ctx context.Context, assetIDs []string, activeOnly bool) ([]*Link, error) {
+	where := ` WHERE asset_id = ANY($1)`
+	if activeOnly {
+		where += ` AND ` + activeStatuses
+	}
+	return r.list(ctx, selectLink+where+` ORDER BY created_at DESC`, assetIDs)
+}
+
+// ListActiveForSync returns active links for the given providers, least
+// recently synced first, so a bounded batch eventually covers every link.
+func (r *PostgresRepository) ListActiveForSync(ctx context.Context, providers []string, limit int) ([]*Link, error) {
+	return r.list(ctx, selectLink+`
+		WHERE provider = ANY($1) AND `+activeStatuses+`
+		ORDER BY last_synced_at ASC NULLS FIRST
+		LIMIT $2`, providers, limit)
+}
+
+func (r *PostgresRepository) RecordSync(ctx context.Context, id, status string, syncErr *string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE asset_incident_links
+		SET status = $1, sync_error = $2, last_synced_at = NOW(),
+		    updated_at = CASE WHEN status <> $1 THEN NOW() ELSE updated_at END
+		WHERE id = $3`,
+		status, syncErr, id)
+	if err != nil {
+		return fmt.Errorf("recording incident sync: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) list(ctx context.Context, query string, args ...interface{}) ([]*Link, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing incident links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*Link{}
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning incident link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating incident links: %w", err)
+	}
+
+	return links, nil
+}
+
+func scanLink(row pgx.Row) (*Link, error) {
+	var l Link
+	if err := row.Scan(
+		&l.ID, &l.AssetID, &l.Provider, &l.ExternalID, &l.URL, &l.Title, &l.Status, &l.Severity, &l.Propagate,
+		&l.LastSyncedAt, &l.SyncError, &l.CreatedBy, &l.CreatedAt, &l.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
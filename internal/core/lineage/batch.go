@@ -0,0 +1,153 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+const (
+	BatchEdgeCreated   = "created"
+	BatchEdgeExisting  = "existing"
+	BatchEdgeDuplicate = "duplicate"
+	BatchEdgeInvalid   = "invalid"
+	BatchEdgeFailed    = "failed"
+
+	// MaxBatchEdges caps the number of edges accepted in one batch request.
+	MaxBatchEdges = 1000
+)
+
+var ErrBatchTooLarge = fmt.Errorf("batch exceeds %d edges", MaxBatchEdges)
+
+// BatchOptions controls how a batch of edges is created.
+type BatchOptions struct {
+	// CreateStubs creates stub assets for edge endpoints that aren't in the
+	// catalog yet. Otherwise edges with unknown endpoints fail.
+	CreateStubs bool
+	// CreatedBy is recorded on any stub assets created.
+	CreatedBy string
+
+	// maxStubs caps the stubs the batch may create when limitStubs is set,
+	// from the stub quota.
+	maxStubs   int
+	limitStubs bool
+}
+
+// StubQuota caps how many stub assets lineage may create.
+type StubQuota interface {
+	// RemainingStubs returns how many more stubs may be created, and false
+	// when stubs are unlimited.
+	RemainingStubs(ctx context.Context) (int, bool, error)
+}
+
+// SetStubQuota limits the stub assets batches create. Edges that would need
+// a stub past the limit fail instead.
+func (s *service) SetStubQuota(quota StubQuota) {
+	s.stubQuota = quota
+}
+
+// BatchEdgeResult is the outcome for one edge of a batch request.
+type BatchEdgeResult struct {
+	Edge   LineageEdge `json:"edge"`
+	Status string      `json:"status" enums:"created,existing,duplicate,invalid,failed"`
+	Error  string      `json:"error,omitempty"`
+	// StubsCreated lists endpoint MRNs created as stub assets for this edge.
+	StubsCreated []string `json:"stubs_created,omitempty"`
+} // @name BatchLineageResult
+
+// BatchCreateLineage creates many direct lineage edges in a single
+// transaction. Invalid and repeated edges are reported without failing the
+// batch; a database error fails the whole batch so nothing is half-written.
+func (s *service) BatchCreateLineage(ctx context.Context, edges []LineageEdge, opts BatchOptions) ([]BatchEdgeResult, error) {
+	if len(edges) > MaxBatchEdges {
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]BatchEdgeResult, len(edges))
+	pending := make([]int, 0, len(edges))
+	seen := make(map[string]struct{}, len(edges))
+
+	for i, edge := range edges {
+		edge.Source = strings.TrimSpace(edge.Source)
+		edge.Target = strings.TrimSpace(edge.Target)
+		results[i].Edge = edge
+
+		if err := validateBatchEdge(edge, opts); err != nil {
+			results[i].Status = BatchEdgeInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+
+		key := edge.Source + "->" + edge.Target
+		if _, ok := seen[key]; ok {
+			results[i].Status = BatchEdgeDuplicate
+			continue
+		}
+		seen[key] = struct{}{}
+		pending = append(pending, i)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	if opts.CreateStubs && s.stubQuota != nil {
+		remaining, limited, err := s.stubQuota.RemainingStubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checking stub quota: %w", err)
+		}
+		opts.maxStubs, opts.limitStubs = remaining, limited
+	}
+
+	batch := make([]*BatchEdgeResult, len(pending))
+	for j, i := range pending {
+		batch[j] = &results[i]
+	}
+	if err := s.repo.BatchCreateDirectLineage(ctx, batch, opts); err != nil {
+		return nil, err
+	}
+
+	for _, r := range batch {
+		if r.Status != BatchEdgeCreated && r.Status != BatchEdgeExisting {
+			continue
+		}
+		if err := s.setEdgeColumns(ctx, r.Edge); err != nil {
+			r.Error = "column lineage: " + err.Error()
+		}
+	}
+
+	if s.lineageObserver != nil {
+		for _, r := range batch {
+			if r.Status == BatchEdgeCreated {
+				s.lineageObserver.OnEdgeCreated(ctx, r.Edge.Source, r.Edge.Target, r.Edge.Type)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func validateBatchEdge(edge LineageEdge, opts BatchOptions) error {
+	if edge.Source == "" || edge.Target == "" {
+		return errors.New("source and target are required")
+	}
+	if edge.Source == edge.Target {
+		return errors.New("source and target must differ")
+	}
+	for _, c := range edge.Columns {
+		if strings.TrimSpace(c.SourceColumn) == "" || strings.TrimSpace(c.TargetColumn) == "" {
+			return errors.New("column mappings need a source_column and target_column")
+		}
+	}
+	if opts.CreateStubs {
+		for _, m := range []string{edge.Source, edge.Target} {
+			if _, err := mrn.Parse(m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
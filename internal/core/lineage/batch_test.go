@@ -0,0 +1,33 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBatchEdge(t *testing.T) {
+	tests := []struct {
+		name    string
+		edge    LineageEdge
+		opts    BatchOptions
+		wantErr bool
+	}{
+		{"valid", LineageEdge{Source: "mrn://table/postgres/a", Target: "mrn://table/postgres/b"}, BatchOptions{CreateStubs: true}, false},
+		{"missing target", LineageEdge{Source: "mrn://table/postgres/a"}, BatchOptions{}, true},
+		{"self loop", LineageEdge{Source: "mrn://table/postgres/a", Target: "mrn://table/postgres/a"}, BatchOptions{}, true},
+		{"unparseable mrn with stubs", LineageEdge{Source: "a", Target: "mrn://table/postgres/b"}, BatchOptions{CreateStubs: true}, true},
+		{"unparseable mrn without stubs", LineageEdge{Source: "a", Target: "mrn://table/postgres/b"}, BatchOptions{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBatchEdge(tt.edge, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
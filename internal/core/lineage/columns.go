@@ -0,0 +1,443 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+const (
+	DefaultColumnLineageDepth = 5
+	MaxColumnLineageDepth     = 10
+
+	// MaxColumnLineageEdges caps the number of column edges accepted in one
+	// request and returned in each direction of a query.
+	MaxColumnLineageEdges = 1000
+)
+
+var ErrInvalidColumnLineage = errors.New("invalid column lineage")
+
+// ColumnMapping maps one column of an edge's source asset to one column of
+// its target asset.
+type ColumnMapping struct {
+	SourceColumn string `json:"source_column"`
+	TargetColumn string `json:"target_column"`
+	// Transformation describes how the target column is derived, e.g.
+	// "identity" or "SUM(amount)".
+	Transformation string `json:"transformation,omitempty"`
+} // @name ColumnMapping
+
+// ColumnLineageEdge records that a column of one asset feeds a column of
+// another.
+type ColumnLineageEdge struct {
+	ID             string `json:"id,omitempty"`
+	Source         string `json:"source"`
+	SourceColumn   string `json:"source_column"`
+	Target         string `json:"target"`
+	TargetColumn   string `json:"target_column"`
+	Transformation string `json:"transformation,omitempty"`
+	// JobMRN is the job that moves data between the two columns, when known.
+	JobMRN string `json:"job_mrn,omitempty"`
+	// Depth is set on edges returned by column lineage queries and counts
+	// hops from the queried asset.
+	Depth     int        `json:"depth,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+} // @name ColumnLineageEdge
+
+// ColumnLineageResponse lists the column edges upstream and downstream of an
+// asset, or of one of its columns.
+type ColumnLineageResponse struct {
+	AssetMRN   string              `json:"asset_mrn"`
+	Column     string              `json:"column,omitempty"`
+	Upstream   []ColumnLineageEdge `json:"upstream"`
+	Downstream []ColumnLineageEdge `json:"downstream"`
+	// Truncated is true when either direction hit MaxColumnLineageEdges.
+	Truncated bool `json:"truncated"`
+} // @name ColumnLineageResponse
+
+// ColumnLineageQuery selects the column lineage around an asset.
+type ColumnLineageQuery struct {
+	AssetID string
+	// Column restricts the query to one column of the asset. Matching is
+	// case-insensitive.
+	Column    string
+	Direction string
+	Depth     int
+}
+
+func normalizeColumnEdge(edge ColumnLineageEdge) (ColumnLineageEdge, error) {
+	edge.Source = strings.TrimSpace(edge.Source)
+	edge.Target = strings.TrimSpace(edge.Target)
+	edge.SourceColumn = strings.TrimSpace(edge.SourceColumn)
+	edge.TargetColumn = strings.TrimSpace(edge.TargetColumn)
+	edge.Transformation = strings.TrimSpace(edge.Transformation)
+
+	if edge.Source == "" || edge.Target == "" {
+		return edge, fmt.Errorf("%w: source and target are required", ErrInvalidColumnLineage)
+	}
+	if edge.SourceColumn == "" || edge.TargetColumn == "" {
+		return edge, fmt.Errorf("%w: source_column and target_column are required", ErrInvalidColumnLineage)
+	}
+	if edge.Source == edge.Target && strings.EqualFold(edge.SourceColumn, edge.TargetColumn) {
+		return edge, fmt.Errorf("%w: %s.%s cannot feed itself", ErrInvalidColumnLineage, edge.Source, edge.SourceColumn)
+	}
+	return edge, nil
+}
+
+// CreateColumnLineage records column edges, updating the transformation and
+// job of edges that already exist.
+func (s *service) CreateColumnLineage(ctx context.Context, edges []ColumnLineageEdge) ([]ColumnLineageEdge, error) {
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%w: at least one edge is required", ErrInvalidColumnLineage)
+	}
+	if len(edges) > MaxColumnLineageEdges {
+		return nil, fmt.Errorf("%w: at most %d edges per request", ErrInvalidColumnLineage, MaxColumnLineageEdges)
+	}
+
+	normalized := make([]ColumnLineageEdge, len(edges))
+	for i, edge := range edges {
+		n, err := normalizeColumnEdge(edge)
+		if err != nil {
+			return nil, err
+		}
+		normalized[i] = n
+	}
+
+	return s.repo.UpsertColumnLineage(ctx, normalized)
+}
+
+// SetColumnLineage replaces the column mappings between two assets with
+// columns. Pipelines call it each time they report an edge, so mappings they
+// stop reporting are removed.
+func (s *service) SetColumnLineage(ctx context.Context, sourceMRN, targetMRN string, columns []ColumnMapping, jobMRN string) error {
+	sourceMRN = strings.TrimSpace(sourceMRN)
+	targetMRN = strings.TrimSpace(targetMRN)
+	edges := make([]ColumnLineageEdge, 0, len(columns))
+	for _, c := range columns {
+		edge, err := normalizeColumnEdge(ColumnLineageEdge{
+			Source:         sourceMRN,
+			SourceColumn:   c.SourceColumn,
+			Target:         targetMRN,
+			TargetColumn:   c.TargetColumn,
+			Transformation: c.Transformation,
+			JobMRN:         jobMRN,
+		})
+		if err != nil {
+			return err
+		}
+		edges = append(edges, edge)
+	}
+	return s.repo.ReplaceColumnLineage(ctx, sourceMRN, targetMRN, edges)
+}
+
+// GetColumnLineage walks column edges upstream and/or downstream of an asset,
+// or of a single column when q.Column is set.
+func (s *service) GetColumnLineage(ctx context.Context, q ColumnLineageQuery) (*ColumnLineageResponse, error) {
+	switch q.Direction {
+	case "":
+		q.Direction = "both"
+	case "upstream", "downstream", "both":
+	default:
+		return nil, fmt.Errorf("%w: direction must be upstream, downstream or both", ErrInvalidColumnLineage)
+	}
+	if q.Depth <= 0 {
+		q.Depth = DefaultColumnLineageDepth
+	}
+	if q.Depth > MaxColumnLineageDepth {
+		q.Depth = MaxColumnLineageDepth
+	}
+	q.Column = strings.TrimSpace(q.Column)
+
+	return s.repo.GetColumnLineage(ctx, q)
+}
+
+func (s *service) DeleteColumnLineage(ctx context.Context, id string) error {
+	return s.repo.DeleteColumnLineage(ctx, id)
+}
+
+// setEdgeColumns records the column mappings carried on an asset edge.
+func (s *service) setEdgeColumns(ctx context.Context, edge LineageEdge) error {
+	if len(edge.Columns) == 0 {
+		return nil
+	}
+	return s.SetColumnLineage(ctx, edge.Source, edge.Target, edge.Columns, edge.JobMRN)
+}
+
+const columnEdgeColumns = `c.id, c.source_mrn, c.source_column, c.target_mrn, c.target_column,
+	COALESCE(c.transformation, ''), COALESCE(c.job_mrn, ''), c.created_at, c.updated_at`
+
+func scanColumnEdge(row pgx.Row, edge *ColumnLineageEdge) error {
+	var createdAt, updatedAt time.Time
+	if err := row.Scan(&edge.ID, &edge.Source, &edge.SourceColumn, &edge.Target, &edge.TargetColumn,
+		&edge.Transformation, &edge.JobMRN, &createdAt, &updatedAt); err != nil {
+		return err
+	}
+	edge.CreatedAt = &createdAt
+	edge.UpdatedAt = &updatedAt
+	return nil
+}
+
+// UpsertColumnLineage inserts edges in one transaction, updating those that
+// already exist. Every asset referenced must already be catalogued.
+func (r *PostgresRepository) UpsertColumnLineage(ctx context.Context, edges []ColumnLineageEdge) ([]ColumnLineageEdge, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	mrns := make([]string, 0, len(edges)*2)
+	for _, e := range edges {
+		mrns = append(mrns, e.Source, e.Target)
+	}
+	known, err := r.existingAssetMRNs(ctx, tx, mrns)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, m := range mrns {
+		if _, ok := known[m]; !ok {
+			missing = append(missing, m)
+			known[m] = struct{}{}
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", asset.ErrAssetNotFound, strings.Join(missing, ", "))
+	}
+
+	result, err := upsertColumnEdges(ctx, tx, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return result, nil
+}
+
+// ReplaceColumnLineage makes edges the only column mappings from source to
+// target.
+func (r *PostgresRepository) ReplaceColumnLineage(ctx context.Context, source, target string, edges []ColumnLineageEdge) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	keep := make([]string, len(edges))
+	for i, e := range edges {
+		keep[i] = e.SourceColumn + "\x00" + e.TargetColumn
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM column_lineage
+		WHERE source_mrn = $1 AND target_mrn = $2
+		  AND NOT (source_column || chr(0) || target_column = ANY($3))`,
+		source, target, keep,
+	); err != nil {
+		return fmt.Errorf("deleting column lineage: %w", err)
+	}
+
+	if _, err := upsertColumnEdges(ctx, tx, edges); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func upsertColumnEdges(ctx context.Context, tx pgx.Tx, edges []ColumnLineageEdge) ([]ColumnLineageEdge, error) {
+	result := make([]ColumnLineageEdge, 0, len(edges))
+	for _, e := range edges {
+		var edge ColumnLineageEdge
+		row := tx.QueryRow(ctx, `
+			INSERT INTO column_lineage AS c (source_mrn, source_column, target_mrn, target_column, transformation, job_mrn)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+			ON CONFLICT (source_mrn, source_column, target_mrn, target_column) DO UPDATE SET
+				transformation = COALESCE(EXCLUDED.transformation, c.transformation),
+				job_mrn = COALESCE(EXCLUDED.job_mrn, c.job_mrn),
+				updated_at = NOW()
+			RETURNING `+columnEdgeColumns,
+			e.Source, e.SourceColumn, e.Target, e.TargetColumn, e.Transformation, e.JobMRN,
+		)
+		if err := scanColumnEdge(row, &edge); err != nil {
+			return nil, fmt.Errorf("upserting column lineage %s.%s -> %s.%s: %w", e.Source, e.SourceColumn, e.Target, e.TargetColumn, err)
+		}
+		result = append(result, edge)
+	}
+	return result, nil
+}
+
+// columnWalkSQL walks column edges away from the queried asset. %[1]s is the
+// near end of an edge and %[2]s the far end, so upstream walks match on
+// target and step to source.
+const columnWalkSQL = `
+	WITH RECURSIVE walk AS (
+		SELECT c.id, c.%[2]s_mrn AS next_mrn, c.%[2]s_column AS next_column, 1 AS depth
+		FROM column_lineage c
+		WHERE c.%[1]s_mrn = $1 AND ($2 = '' OR lower(c.%[1]s_column) = lower($2))
+		UNION ALL
+		SELECT c.id, c.%[2]s_mrn, c.%[2]s_column, w.depth + 1
+		FROM walk w
+		JOIN column_lineage c ON c.%[1]s_mrn = w.next_mrn AND lower(c.%[1]s_column) = lower(w.next_column)
+		WHERE w.depth < $3
+	)
+	SELECT ` + columnEdgeColumns + `, d.depth
+	FROM (SELECT id, MIN(depth) AS depth FROM walk GROUP BY id) d
+	JOIN column_lineage c ON c.id = d.id
+	ORDER BY d.depth, c.source_mrn, c.source_column, c.target_mrn, c.target_column
+	LIMIT $4`
+
+// GetColumnLineage returns the column edges within q.Depth hops of the
+// asset. An edge reached along several paths is reported at its shortest
+// depth.
+func (r *PostgresRepository) GetColumnLineage(ctx context.Context, q ColumnLineageQuery) (*ColumnLineageResponse, error) {
+	var assetMRN string
+	err := r.db.QueryRow(ctx, "SELECT mrn FROM assets WHERE id = $1", q.AssetID).Scan(&assetMRN)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, asset.ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("getting asset mrn: %w", err)
+	}
+
+	resp := &ColumnLineageResponse{
+		AssetMRN:   assetMRN,
+		Column:     q.Column,
+		Upstream:   []ColumnLineageEdge{},
+		Downstream: []ColumnLineageEdge{},
+	}
+
+	walks := []struct {
+		direction string
+		near, far string
+		into      *[]ColumnLineageEdge
+	}{
+		{"upstream", "target", "source", &resp.Upstream},
+		{"downstream", "source", "target", &resp.Downstream},
+	}
+	for _, w := range walks {
+		if q.Direction != "both" && q.Direction != w.direction {
+			continue
+		}
+		edges, err := r.walkColumnLineage(ctx, fmt.Sprintf(columnWalkSQL, w.near, w.far), assetMRN, q.Column, q.Depth)
+		if err != nil {
+			return nil, fmt.Errorf("walking %s column lineage: %w", w.direction, err)
+		}
+		if len(edges) > MaxColumnLineageEdges {
+			edges = edges[:MaxColumnLineageEdges]
+			resp.Truncated = true
+		}
+		*w.into = edges
+	}
+
+	return resp, nil
+}
+
+func (r *PostgresRepository) walkColumnLineage(ctx context.Context, query, assetMRN, column string, depth int) ([]ColumnLineageEdge, error) {
+	rows, err := r.db.Query(ctx, query, assetMRN, column, depth, MaxColumnLineageEdges+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edges := []ColumnLineageEdge{}
+	for rows.Next() {
+		var edge ColumnLineageEdge
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&edge.ID, &edge.Source, &edge.SourceColumn, &edge.Target, &edge.TargetColumn,
+			&edge.Transformation, &edge.JobMRN, &createdAt, &updatedAt, &edge.Depth); err != nil {
+			return nil, fmt.Errorf("scanning column edge: %w", err)
+		}
+		edge.CreatedAt = &createdAt
+		edge.UpdatedAt = &updatedAt
+		edges = append(edges, edge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating column edges: %w", err)
+	}
+	return edges, nil
+}
+
+func (r *PostgresRepository) DeleteColumnLineage(ctx context.Context, id string) error {
+	if _, err := r.db.Exec(ctx, "DELETE FROM column_lineage WHERE id = $1", id); err != nil {
+		return fmt.Errorf("deleting column lineage: %w", err)
+	}
+	return nil
+}
+
+// columnMappingsByInput groups an OpenLineage columnLineage facet's mappings
+// by the input dataset they read from, keyed by namespace and name.
+func columnMappingsByInput(facet map[string]interface{}) map[[2]string][]ColumnMapping {
+	fields, ok := facet["fields"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	targets := make([]string, 0, len(fields))
+	for target := range fields {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	byInput := map[[2]string][]ColumnMapping{}
+	for _, target := range targets {
+		field, ok := fields[target].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldTransformation := olTransformation(field["transformationDescription"], field["transformationType"], nil)
+
+		inputs, _ := field["inputFields"].([]interface{})
+		for _, raw := range inputs {
+			in, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			namespace, _ := in["namespace"].(string)
+			name, _ := in["name"].(string)
+			column, _ := in["field"].(string)
+			if namespace == "" || name == "" || column == "" {
+				continue
+			}
+
+			transformation := fieldTransformation
+			if ts, ok := in["transformations"].([]interface{}); ok && len(ts) > 0 {
+				if t, ok := ts[0].(map[string]interface{}); ok {
+					transformation = olTransformation(t["description"], t["type"], t["subtype"])
+				}
+			}
+
+			key := [2]string{namespace, name}
+			byInput[key] = append(byInput[key], ColumnMapping{
+				SourceColumn:   column,
+				TargetColumn:   target,
+				Transformation: transformation,
+			})
+		}
+	}
+	return byInput
+}
+
+// olTransformation prefers a transformation's description, falling back to
+// its type and subtype, e.g. "DIRECT/AGGREGATION".
+func olTransformation(description, kind, subtype interface{}) string {
+	if d, _ := description.(string); d != "" {
+		return d
+	}
+	k, _ := kind.(string)
+	st, _ := subtype.(string)
+	if k != "" && st != "" {
+		return k + "/" + st
+	}
+	return k
+}
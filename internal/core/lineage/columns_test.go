@@ -0,0 +1,61 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeColumnEdge(t *testing.T) {
+	edge, err := normalizeColumnEdge(ColumnLineageEdge{
+		Source:       " mrn://table/postgres/orders ",
+		SourceColumn: " amount ",
+		Target:       "mrn://table/snowflake/revenue",
+		TargetColumn: "total",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mrn://table/postgres/orders", edge.Source)
+	assert.Equal(t, "amount", edge.SourceColumn)
+
+	_, err = normalizeColumnEdge(ColumnLineageEdge{Source: "a", Target: "b", SourceColumn: "x"})
+	assert.ErrorIs(t, err, ErrInvalidColumnLineage)
+
+	_, err = normalizeColumnEdge(ColumnLineageEdge{Source: "a", Target: "a", SourceColumn: "id", TargetColumn: "ID"})
+	assert.ErrorIs(t, err, ErrInvalidColumnLineage)
+
+	_, err = normalizeColumnEdge(ColumnLineageEdge{Source: "a", Target: "a", SourceColumn: "first", TargetColumn: "full_name"})
+	assert.NoError(t, err)
+}
+
+func TestColumnMappingsByInput(t *testing.T) {
+	facet := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"total": map[string]interface{}{
+				"transformationDescription": "SUM(amount)",
+				"inputFields": []interface{}{
+					map[string]interface{}{"namespace": "postgres://db", "name": "public.orders", "field": "amount"},
+				},
+			},
+			"customer": map[string]interface{}{
+				"inputFields": []interface{}{
+					map[string]interface{}{
+						"namespace": "postgres://db", "name": "public.customers", "field": "name",
+						"transformations": []interface{}{
+							map[string]interface{}{"type": "DIRECT", "subtype": "IDENTITY"},
+						},
+					},
+					map[string]interface{}{"namespace": "postgres://db", "name": "public.orders"},
+				},
+			},
+		},
+	}
+
+	got := columnMappingsByInput(facet)
+	assert.Equal(t, map[[2]string][]ColumnMapping{
+		{"postgres://db", "public.orders"}:    {{SourceColumn: "amount", TargetColumn: "total", Transformation: "SUM(amount)"}},
+		{"postgres://db", "public.customers"}: {{SourceColumn: "name", TargetColumn: "customer", Transformation: "DIRECT/IDENTITY"}},
+	}, got)
+
+	assert.Nil(t, columnMappingsByInput(map[string]interface{}{}))
+}
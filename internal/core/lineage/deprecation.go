@@ -0,0 +1,60 @@
+package lineage
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DeprecationNotice marks a lineage node or impacted asset as deprecated and
+// points consumers at its replacement.
+type DeprecationNotice struct {
+	Reason          string     `json:"reason,omitempty"`
+	ReplacementID   string     `json:"replacement_id,omitempty"`
+	ReplacementMRN  string     `json:"replacement_mrn,omitempty"`
+	ReplacementName string     `json:"replacement_name,omitempty"`
+	SunsetAt        *time.Time `json:"sunset_at,omitempty"`
+} // @name LineageDeprecationNotice
+
+// DeprecationLookup returns the deprecation notices of the deprecated assets
+// among mrns, keyed by MRN.
+type DeprecationLookup interface {
+	DeprecationsByMRN(ctx context.Context, mrns []string) (map[string]*DeprecationNotice, error)
+}
+
+// SetDeprecationLookup registers where deprecation notices come from. Must
+// be called during initialization before any lineage operations begin.
+func (s *service) SetDeprecationLookup(lookup DeprecationLookup) {
+	s.deprecations = lookup
+}
+
+// deprecationNotices loads the notices for mrns. Failing to load them leaves
+// responses unannotated rather than failing the request.
+func (s *service) deprecationNotices(ctx context.Context, mrns []string) map[string]*DeprecationNotice {
+	if s.deprecations == nil || len(mrns) == 0 {
+		return nil
+	}
+
+	notices, err := s.deprecations.DeprecationsByMRN(ctx, mrns)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load asset deprecations")
+		return nil
+	}
+	return notices
+}
+
+// annotateDeprecations sets Deprecation on every node of a deprecated asset.
+func (s *service) annotateDeprecations(ctx context.Context, nodes []LineageNode) {
+	mrns := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Asset != nil {
+			mrns = append(mrns, n.ID)
+		}
+	}
+
+	notices := s.deprecationNotices(ctx, mrns)
+	for i := range nodes {
+		nodes[i].Deprecation = notices[nodes[i].ID]
+	}
+}
@@ -0,0 +1,71 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDeprecationLookup struct {
+	notices map[string]*DeprecationNotice
+	err     error
+}
+
+func (f *fakeDeprecationLookup) DeprecationsByMRN(_ context.Context, mrns []string) (map[string]*DeprecationNotice, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := map[string]*DeprecationNotice{}
+	for _, m := range mrns {
+		if n, ok := f.notices[m]; ok {
+			out[m] = n
+		}
+	}
+	return out, nil
+}
+
+func TestAnnotateDeprecations(t *testing.T) {
+	notice := &DeprecationNotice{Reason: "moved", ReplacementMRN: "mrn://table/pg/orders_v2"}
+	s := &service{deprecations: &fakeDeprecationLookup{notices: map[string]*DeprecationNotice{
+		"mrn://table/pg/orders": notice,
+	}}}
+
+	nodes := []LineageNode{
+		{ID: "mrn://table/pg/orders", Asset: &asset.Asset{}},
+		{ID: "mrn://table/pg/customers", Asset: &asset.Asset{}},
+	}
+	s.annotateDeprecations(context.Background(), nodes)
+
+	assert.Same(t, notice, nodes[0].Deprecation)
+	assert.Nil(t, nodes[1].Deprecation)
+}
+
+func TestAnnotateDeprecationsIgnoresLookupErrors(t *testing.T) {
+	s := &service{deprecations: &fakeDeprecationLookup{err: errors.New("boom")}}
+
+	nodes := []LineageNode{{ID: "mrn://table/pg/orders", Asset: &asset.Asset{}}}
+	s.annotateDeprecations(context.Background(), nodes)
+
+	assert.Nil(t, nodes[0].Deprecation)
+}
+
+func TestAnnotateImpactDeprecations(t *testing.T) {
+	notice := &DeprecationNotice{ReplacementMRN: "mrn://model/dbt/orders_v2"}
+	s := &service{deprecations: &fakeDeprecationLookup{notices: map[string]*DeprecationNotice{
+		"mrn://model/dbt/orders": notice,
+	}}}
+
+	report := &ImpactReport{
+		MRN:        "mrn://table/pg/orders",
+		Models:     []ImpactedAsset{{MRN: "mrn://model/dbt/orders"}},
+		Dashboards: []ImpactedAsset{{MRN: "mrn://dashboard/looker/sales"}},
+	}
+	s.annotateImpactDeprecations(context.Background(), report)
+
+	assert.Nil(t, report.Deprecation)
+	assert.Same(t, notice, report.Models[0].Deprecation)
+	assert.Nil(t, report.Dashboards[0].Deprecation)
+}
@@ -0,0 +1,212 @@
+package lineage
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	ExportFormatGraphML = "graphml"
+	ExportFormatDOT     = "dot"
+	ExportFormatCypher  = "cypher"
+
+	// DefaultExportLimit caps the number of nodes in an unscoped export.
+	DefaultExportLimit = 10000
+
+	DefaultExportDepth = 10
+	MaxExportDepth     = 20
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// exportTruncatedNote is written as a comment at the top of an export that
+// stopped at DefaultExportLimit nodes.
+var exportTruncatedNote = fmt.Sprintf("truncated: export stopped at %d nodes, scope it with asset_id to see the rest", DefaultExportLimit)
+
+// ExportContentType returns the MIME type for an export format.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatGraphML:
+		return "application/graphml+xml"
+	case ExportFormatDOT:
+		return "text/vnd.graphviz"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// ExportFileExtension returns the conventional file extension for an export
+// format.
+func ExportFileExtension(format string) string {
+	switch format {
+	case ExportFormatGraphML:
+		return "graphml"
+	case ExportFormatDOT:
+		return "dot"
+	default:
+		return "cypher"
+	}
+}
+
+// WriteGraph writes a lineage graph to w in the given format. Nodes are keyed
+// by MRN so exports from different scopes can be merged by downstream tools.
+func WriteGraph(w io.Writer, graph *LineageResponse, format string) error {
+	bw := bufio.NewWriter(w)
+
+	var err error
+	switch format {
+	case ExportFormatGraphML:
+		err = writeGraphML(bw, graph)
+	case ExportFormatDOT:
+		err = writeDOT(bw, graph)
+	case ExportFormatCypher:
+		err = writeCypher(bw, graph)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+type exportNode struct {
+	id       string
+	name     string
+	nodeType string
+	provider string
+	isStub   bool
+}
+
+func exportNodes(graph *LineageResponse) []exportNode {
+	nodes := make([]exportNode, 0, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		node := exportNode{id: n.ID, name: n.ID, nodeType: n.Type}
+		if a := n.Asset; a != nil {
+			if a.Name != nil && *a.Name != "" {
+				node.name = *a.Name
+			}
+			if len(a.Providers) > 0 {
+				node.provider = a.Providers[0]
+			}
+			node.isStub = a.IsStub
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func writeGraphML(w *bufio.Writer, graph *LineageResponse) error {
+	w.WriteString(xml.Header)
+	w.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	if graph.Truncated {
+		w.WriteString("  <!-- " + exportTruncatedNote + " -->\n")
+	}
+	w.WriteString(`  <key id="name" for="node" attr.name="name" attr.type="string"/>` + "\n")
+	w.WriteString(`  <key id="type" for="node" attr.name="type" attr.type="string"/>` + "\n")
+	w.WriteString(`  <key id="provider" for="node" attr.name="provider" attr.type="string"/>` + "\n")
+	w.WriteString(`  <key id="stub" for="node" attr.name="stub" attr.type="boolean"/>` + "\n")
+	w.WriteString(`  <key id="edge_type" for="edge" attr.name="type" attr.type="string"/>` + "\n")
+	w.WriteString(`  <key id="job_mrn" for="edge" attr.name="job_mrn" attr.type="string"/>` + "\n")
+	w.WriteString(`  <graph id="lineage" edgedefault="directed">` + "\n")
+
+	for _, n := range exportNodes(graph) {
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", xmlEscape(n.id))
+		fmt.Fprintf(w, "      <data key=\"name\">%s</data>\n", xmlEscape(n.name))
+		fmt.Fprintf(w, "      <data key=\"type\">%s</data>\n", xmlEscape(n.nodeType))
+		if n.provider != "" {
+			fmt.Fprintf(w, "      <data key=\"provider\">%s</data>\n", xmlEscape(n.provider))
+		}
+		fmt.Fprintf(w, "      <data key=\"stub\">%t</data>\n", n.isStub)
+		w.WriteString("    </node>\n")
+	}
+
+	for i, e := range graph.Edges {
+		id := e.ID
+		if id == "" {
+			id = fmt.Sprintf("e%d", i)
+		}
+		fmt.Fprintf(w, "    <edge id=\"%s\" source=\"%s\" target=\"%s\">\n", xmlEscape(id), xmlEscape(e.Source), xmlEscape(e.Target))
+		fmt.Fprintf(w, "      <data key=\"edge_type\">%s</data>\n", xmlEscape(e.Type))
+		if e.JobMRN != "" {
+			fmt.Fprintf(w, "      <data key=\"job_mrn\">%s</data>\n", xmlEscape(e.JobMRN))
+		}
+		w.WriteString("    </edge>\n")
+	}
+
+	_, err := w.WriteString("  </graph>\n</graphml>\n")
+	return err
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func writeDOT(w *bufio.Writer, graph *LineageResponse) error {
+	w.WriteString("digraph lineage {\n")
+	if graph.Truncated {
+		w.WriteString("  // " + exportTruncatedNote + "\n")
+	}
+	w.WriteString("  rankdir=LR;\n")
+	w.WriteString("  node [shape=box];\n")
+
+	for _, n := range exportNodes(graph) {
+		style := ""
+		if n.isStub {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(w, "  %s [label=%s, tooltip=%s%s];\n",
+			dotQuote(n.id), dotQuote(n.name+"\n"+n.nodeType), dotQuote(n.id), style)
+	}
+
+	for _, e := range graph.Edges {
+		fmt.Fprintf(w, "  %s -> %s [label=%s];\n", dotQuote(e.Source), dotQuote(e.Target), dotQuote(e.Type))
+	}
+
+	_, err := w.WriteString("}\n")
+	return err
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// writeCypher emits idempotent MERGE statements so an export can be loaded
+// into Neo4j repeatedly, or on top of another export, without duplicates.
+func writeCypher(w *bufio.Writer, graph *LineageResponse) error {
+	if graph.Truncated {
+		w.WriteString("// " + exportTruncatedNote + "\n")
+	}
+	w.WriteString("CREATE CONSTRAINT asset_mrn IF NOT EXISTS FOR (a:Asset) REQUIRE a.mrn IS UNIQUE;\n")
+
+	for _, n := range exportNodes(graph) {
+		fmt.Fprintf(w, "MERGE (a:Asset {mrn: %s}) SET a.name = %s, a.type = %s, a.provider = %s, a.stub = %t;\n",
+			cypherQuote(n.id), cypherQuote(n.name), cypherQuote(n.nodeType), cypherQuote(n.provider), n.isStub)
+	}
+
+	for _, e := range graph.Edges {
+		fmt.Fprintf(w, "MATCH (s:Asset {mrn: %s}), (t:Asset {mrn: %s}) MERGE (s)-[r:LINEAGE {type: %s}]->(t)",
+			cypherQuote(e.Source), cypherQuote(e.Target), cypherQuote(e.Type))
+		if e.JobMRN != "" {
+			fmt.Fprintf(w, " SET r.job_mrn = %s", cypherQuote(e.JobMRN))
+		}
+		w.WriteString(";\n")
+	}
+
+	return nil
+}
+
+var cypherEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+
+func cypherQuote(s string) string {
+	return "'" + cypherEscaper.Replace(s) + "'"
+}
@@ -0,0 +1,159 @@
+package lineage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() *LineageResponse {
+	name := `orders "raw"`
+	return &LineageResponse{
+		Nodes: []LineageNode{
+			{ID: "mrn://table/postgres/orders", Type: "Table", Asset: &asset.Asset{Name: &name, Providers: []string{"PostgreSQL"}}},
+			{ID: "mrn://table/snowflake/o'brien", Type: "Table", Asset: &asset.Asset{IsStub: true}},
+		},
+		Edges: []LineageEdge{
+			{ID: "1", Source: "mrn://table/postgres/orders", Target: "mrn://table/snowflake/o'brien", Type: "DIRECT"},
+		},
+	}
+}
+
+func TestWriteGraph(t *testing.T) {
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{
+			format: ExportFormatGraphML,
+			contains: []string{
+				`<node id="mrn://table/postgres/orders">`,
+				`<data key="name">orders &#34;raw&#34;</data>`,
+				`<edge id="1" source="mrn://table/postgres/orders" target="mrn://table/snowflake/o&#39;brien">`,
+			},
+		},
+		{
+			format: ExportFormatDOT,
+			contains: []string{
+				`"mrn://table/postgres/orders" [label="orders \"raw\"\nTable"`,
+				`"mrn://table/postgres/orders" -> "mrn://table/snowflake/o'brien" [label="DIRECT"];`,
+				`style=dashed`,
+			},
+		},
+		{
+			format: ExportFormatCypher,
+			contains: []string{
+				`MERGE (a:Asset {mrn: 'mrn://table/snowflake/o\'brien'})`,
+				`MERGE (s)-[r:LINEAGE {type: 'DIRECT'}]->(t);`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteGraph(&buf, testGraph(), tt.format))
+			for _, s := range tt.contains {
+				assert.Contains(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+func TestWriteGraphMLIsValidXML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteGraph(&buf, testGraph(), ExportFormatGraphML))
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Graph.Nodes, 2)
+	assert.Equal(t, "mrn://table/snowflake/o'brien", doc.Graph.Edges[0].Target)
+}
+
+func TestWriteGraphUnsupportedFormat(t *testing.T) {
+	err := WriteGraph(&bytes.Buffer{}, testGraph(), "svg")
+	assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+}
+
+func TestWriteGraphNotesTruncation(t *testing.T) {
+	for _, format := range []string{ExportFormatGraphML, ExportFormatDOT, ExportFormatCypher} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteGraph(&buf, testGraph(), format))
+			assert.NotContains(t, buf.String(), "truncated")
+
+			graph := testGraph()
+			graph.Truncated = true
+			buf.Reset()
+			require.NoError(t, WriteGraph(&buf, graph, format))
+			assert.Contains(t, buf.String(), exportTruncatedNote)
+		})
+	}
+
+	graph := testGraph()
+	graph.Truncated = true
+	var buf bytes.Buffer
+	require.NoError(t, WriteGraph(&buf, graph, ExportFormatGraphML))
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), new(struct{})))
+}
+
+// fakeExportRepo records the arguments ExportLineage passes to the repository.
+type fakeExportRepo struct {
+	Repository
+	assetID    string
+	depth      int
+	graphLimit int
+}
+
+func (r *fakeExportRepo) GetAssetLineage(_ context.Context, assetID string, limit int, _ string) (*LineageResponse, error) {
+	r.assetID, r.depth = assetID, limit
+	return &LineageResponse{}, nil
+}
+
+func (r *fakeExportRepo) GetLineageGraph(_ context.Context, limit int) (*LineageResponse, error) {
+	r.graphLimit = limit
+	return &LineageResponse{Truncated: true}, nil
+}
+
+func TestExportLineageDepth(t *testing.T) {
+	tests := []struct {
+		depth int
+		want  int
+	}{
+		{depth: 0, want: DefaultExportDepth},
+		{depth: -1, want: DefaultExportDepth},
+		{depth: 3, want: 3},
+		{depth: MaxExportDepth, want: MaxExportDepth},
+		{depth: 1000, want: MaxExportDepth},
+	}
+
+	for _, tt := range tests {
+		repo := &fakeExportRepo{}
+		_, err := NewService(repo, nil).ExportLineage(context.Background(), "asset-1", tt.depth)
+		require.NoError(t, err)
+		assert.Equal(t, "asset-1", repo.assetID)
+		assert.Equal(t, tt.want, repo.depth, "depth %d", tt.depth)
+	}
+}
+
+func TestExportLineageUnscoped(t *testing.T) {
+	repo := &fakeExportRepo{}
+	graph, err := NewService(repo, nil).ExportLineage(context.Background(), "", 5)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultExportLimit, repo.graphLimit)
+	assert.True(t, graph.Truncated)
+}
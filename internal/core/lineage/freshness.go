@@ -0,0 +1,99 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultStaleAfterRuns is the number of completed pipeline runs an edge can
+// be missing from before it is considered stale.
+const DefaultStaleAfterRuns = 3
+
+type FreshnessStatus string // @name LineageFreshnessStatus
+
+const (
+	// FreshnessCurrent edges were reported by a recent run of the pipeline
+	// that last confirmed them.
+	FreshnessCurrent FreshnessStatus = "current"
+	// FreshnessStale edges have been missing from the last N completed runs
+	// of the pipeline that last confirmed them.
+	FreshnessStale FreshnessStatus = "stale"
+	// FreshnessUntracked edges were never reported by a pipeline run, e.g.
+	// edges created through the API or OpenLineage events.
+	FreshnessUntracked FreshnessStatus = "untracked"
+)
+
+// EdgeFreshness tells whether an edge still reflects what its pipeline
+// reports.
+type EdgeFreshness struct {
+	Status          FreshnessStatus `json:"status"`
+	LastConfirmedAt *time.Time      `json:"last_confirmed_at,omitempty"`
+	// Pipeline is the pipeline whose run last confirmed the edge.
+	Pipeline string `json:"pipeline,omitempty"`
+	// MissedRuns counts that pipeline's completed runs since, none of which
+	// reported the edge.
+	MissedRuns int `json:"missed_runs"`
+} // @name LineageEdgeFreshness
+
+// EdgeConfirmation is the latest pipeline run to report an edge.
+type EdgeConfirmation struct {
+	ConfirmedAt time.Time
+	Pipeline    string
+	MissedRuns  int
+}
+
+// CheckpointMRN returns the MRN under which pipeline runs checkpoint an edge.
+func CheckpointMRN(source, target, lineageType string) string {
+	return mrn.New("lineage", strings.ToLower(lineageType), fmt.Sprintf("%s->%s", source, target))
+}
+
+// Freshness classifies an edge from its latest confirmation, which is nil for
+// edges no pipeline run has reported.
+func Freshness(c *EdgeConfirmation, staleAfterRuns int) *EdgeFreshness {
+	if c == nil {
+		return &EdgeFreshness{Status: FreshnessUntracked}
+	}
+	if staleAfterRuns <= 0 {
+		staleAfterRuns = DefaultStaleAfterRuns
+	}
+
+	confirmedAt := c.ConfirmedAt
+	f := &EdgeFreshness{
+		Status:          FreshnessCurrent,
+		LastConfirmedAt: &confirmedAt,
+		Pipeline:        c.Pipeline,
+		MissedRuns:      c.MissedRuns,
+	}
+	if c.MissedRuns >= staleAfterRuns {
+		f.Status = FreshnessStale
+	}
+	return f
+}
+
+// annotateFreshness sets Freshness on every edge of graph. Failing to load
+// confirmations leaves the edges unannotated rather than failing the request.
+func (s *service) annotateFreshness(ctx context.Context, edges []LineageEdge, staleAfterRuns int) {
+	if len(edges) == 0 {
+		return
+	}
+
+	keys := make([]string, len(edges))
+	for i, e := range edges {
+		keys[i] = CheckpointMRN(e.Source, e.Target, e.Type)
+	}
+
+	confirmations, err := s.repo.GetEdgeConfirmations(ctx, keys)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load lineage edge freshness")
+		return
+	}
+
+	for i := range edges {
+		edges[i].Freshness = Freshness(confirmations[keys[i]], staleAfterRuns)
+	}
+}
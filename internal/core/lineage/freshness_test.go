@@ -0,0 +1,33 @@
+package lineage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreshness(t *testing.T) {
+	confirmedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	f := Freshness(nil, 3)
+	assert.Equal(t, FreshnessUntracked, f.Status)
+	assert.Nil(t, f.LastConfirmedAt)
+
+	f = Freshness(&EdgeConfirmation{ConfirmedAt: confirmedAt, Pipeline: "warehouse", MissedRuns: 2}, 3)
+	assert.Equal(t, FreshnessCurrent, f.Status)
+	assert.Equal(t, confirmedAt, *f.LastConfirmedAt)
+	assert.Equal(t, "warehouse", f.Pipeline)
+
+	f = Freshness(&EdgeConfirmation{ConfirmedAt: confirmedAt, MissedRuns: 3}, 3)
+	assert.Equal(t, FreshnessStale, f.Status)
+
+	f = Freshness(&EdgeConfirmation{ConfirmedAt: confirmedAt, MissedRuns: 3}, 0)
+	assert.Equal(t, FreshnessStale, f.Status, "zero threshold uses the default")
+}
+
+func TestCheckpointMRN(t *testing.T) {
+	assert.Equal(t,
+		"mrn://lineage/direct/mrn:--table-postgres-orders->mrn:--table-postgres-facts",
+		CheckpointMRN("mrn://table/postgres/orders", "mrn://table/postgres/facts", "DIRECT"))
+}
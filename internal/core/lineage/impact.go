@@ -0,0 +1,378 @@
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+const (
+	ColumnChangeDrop   = "drop"
+	ColumnChangeRename = "rename"
+	ColumnChangeType   = "type_change"
+	ColumnChangeAdd    = "add"
+
+	// ImpactReasonColumn means a downstream asset has a column with the same
+	// name as an affected upstream column.
+	ImpactReasonColumn = "column_name"
+	// ImpactReasonQuery means a downstream asset's query references an
+	// affected column.
+	ImpactReasonQuery = "query"
+	// ImpactReasonLineage means a downstream asset has no schema or query to
+	// inspect but depends on an affected asset, so it may be impacted.
+	ImpactReasonLineage = "lineage"
+
+	DefaultImpactDepth = 5
+	MaxImpactDepth     = 10
+)
+
+var ErrInvalidImpactRequest = errors.New("invalid impact request")
+
+// ColumnChange is a single proposed change to a column of the producing asset.
+type ColumnChange struct {
+	Column  string `json:"column" validate:"required"`
+	Change  string `json:"change" validate:"required,oneof=drop rename type_change add"`
+	NewName string `json:"new_name,omitempty" validate:"required_if=Change rename"`
+	NewType string `json:"new_type,omitempty"`
+} // @name ColumnChange
+
+// ImpactRequest describes a proposed schema change to an asset.
+type ImpactRequest struct {
+	MRN     string         `json:"mrn" validate:"required"`
+	Changes []ColumnChange `json:"changes" validate:"required,min=1,dive"`
+	Depth   int            `json:"depth,omitempty" validate:"omitempty,min=1,max=10"`
+} // @name ImpactAnalysisRequest
+
+// ImpactedColumn is a downstream column fed by a changed column.
+type ImpactedColumn struct {
+	AssetMRN      string   `json:"asset_mrn"`
+	AssetName     string   `json:"asset_name"`
+	AssetType     string   `json:"asset_type"`
+	Column        string   `json:"column"`
+	SourceColumns []string `json:"source_columns"`
+	Depth         int      `json:"depth"`
+} // @name ImpactedColumn
+
+// ImpactedAsset is a downstream asset affected by the proposed change.
+type ImpactedAsset struct {
+	MRN       string   `json:"mrn"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+	Depth     int      `json:"depth"`
+	// Columns lists the changed columns of the producing asset this asset
+	// depends on.
+	Columns []string `json:"columns"`
+	Reason  string   `json:"reason"`
+	// Deprecation is set when the impacted asset is itself deprecated, in
+	// which case its replacement may be the better place to make the fix.
+	Deprecation *DeprecationNotice `json:"deprecation,omitempty"`
+} // @name ImpactedAsset
+
+// ImpactReport lists everything downstream of a proposed schema change.
+type ImpactReport struct {
+	MRN     string         `json:"mrn"`
+	Changes []ColumnChange `json:"changes"`
+	// Breaking is true when a drop, rename or type change reaches at least
+	// one downstream asset.
+	Breaking   bool             `json:"breaking"`
+	Columns    []ImpactedColumn `json:"columns"`
+	Models     []ImpactedAsset  `json:"models"`
+	Dashboards []ImpactedAsset  `json:"dashboards"`
+	Assets     []ImpactedAsset  `json:"assets"`
+	// Deprecation is set when the changed asset is deprecated.
+	Deprecation *DeprecationNotice `json:"deprecation,omitempty"`
+} // @name ImpactAnalysisReport
+
+var dashboardTypes = map[string]bool{
+	"dashboard":     true,
+	"chart":         true,
+	"report":        true,
+	"workbook":      true,
+	"look":          true,
+	"explore":       true,
+	"visualization": true,
+}
+
+// AnalyzeImpact walks downstream lineage from the changed asset and reports
+// the columns, models and dashboards that depend on the changed columns.
+// Columns are followed by name through asset schemas and by reference in
+// asset queries; assets with neither are reported as possibly impacted when
+// they depend on an impacted asset.
+func (s *service) AnalyzeImpact(ctx context.Context, req *ImpactRequest) (*ImpactReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImpactRequest, err)
+	}
+
+	depth := req.Depth
+	if depth <= 0 {
+		depth = DefaultImpactDepth
+	}
+
+	root, err := s.assetSvc.GetByMRN(ctx, req.MRN)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImpactReport{
+		MRN:        req.MRN,
+		Changes:    req.Changes,
+		Columns:    []ImpactedColumn{},
+		Models:     []ImpactedAsset{},
+		Dashboards: []ImpactedAsset{},
+		Assets:     []ImpactedAsset{},
+	}
+
+	// tracked maps an asset MRN to its affected columns (lowercased), each
+	// with the changed root columns that flow into it.
+	tracked := map[string]map[string][]string{}
+	rootColumns := map[string][]string{}
+	for _, c := range req.Changes {
+		if c.Change == ColumnChangeAdd {
+			continue
+		}
+		rootColumns[strings.ToLower(c.Column)] = []string{c.Column}
+	}
+	if len(rootColumns) == 0 {
+		s.annotateImpactDeprecations(ctx, report)
+		return report, nil
+	}
+	tracked[req.MRN] = rootColumns
+
+	graph, err := s.repo.GetAssetLineage(ctx, root.ID, depth, "downstream")
+	if err != nil {
+		return nil, fmt.Errorf("getting downstream lineage: %w", err)
+	}
+
+	incoming := map[string][]string{}
+	for _, e := range graph.Edges {
+		incoming[e.Target] = append(incoming[e.Target], e.Source)
+	}
+
+	nodes := make([]LineageNode, 0, len(graph.Nodes))
+	mrns := make([]string, 0, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		if n.Depth <= 0 || n.ID == req.MRN {
+			continue
+		}
+		nodes = append(nodes, n)
+		mrns = append(mrns, n.ID)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Depth < nodes[j].Depth })
+
+	// Lineage nodes don't carry queries, so load the full assets.
+	assets, err := s.assetSvc.GetByMRNs(ctx, mrns)
+	if err != nil {
+		return nil, fmt.Errorf("getting downstream assets: %w", err)
+	}
+
+	for _, n := range nodes {
+		upstream := map[string][]string{}
+		for _, src := range incoming[n.ID] {
+			for col, roots := range tracked[src] {
+				upstream[col] = mergeColumns(upstream[col], roots)
+			}
+		}
+		if len(upstream) == 0 {
+			continue
+		}
+
+		a := assets[n.ID]
+		if a == nil {
+			a = n.Asset
+		}
+		if a == nil {
+			continue
+		}
+
+		matched := map[string][]string{}
+		reason := ""
+
+		schemaColumns := SchemaColumns(a.Schema)
+		for col, roots := range upstream {
+			name, ok := schemaColumns[col]
+			if !ok {
+				continue
+			}
+			matched[col] = roots
+			reason = ImpactReasonColumn
+			report.Columns = append(report.Columns, ImpactedColumn{
+				AssetMRN:      n.ID,
+				AssetName:     assetName(a, n.ID),
+				AssetType:     a.Type,
+				Column:        name,
+				SourceColumns: roots,
+				Depth:         n.Depth,
+			})
+		}
+
+		if a.Query != nil && *a.Query != "" {
+			for col, roots := range upstream {
+				if _, ok := matched[col]; ok {
+					continue
+				}
+				if queryReferences(*a.Query, col) {
+					matched[col] = roots
+					if reason == "" {
+						reason = ImpactReasonQuery
+					}
+				}
+			}
+		}
+
+		if len(matched) == 0 && len(schemaColumns) == 0 && (a.Query == nil || *a.Query == "") {
+			// Nothing to inspect, so assume the columns pass through.
+			matched = upstream
+			reason = ImpactReasonLineage
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+		tracked[n.ID] = matched
+
+		var roots []string
+		for _, r := range matched {
+			roots = mergeColumns(roots, r)
+		}
+		sort.Strings(roots)
+
+		impacted := ImpactedAsset{
+			MRN:       n.ID,
+			Name:      assetName(a, n.ID),
+			Type:      a.Type,
+			Providers: a.Providers,
+			Depth:     n.Depth,
+			Columns:   roots,
+			Reason:    reason,
+		}
+
+		switch {
+		case dashboardTypes[strings.ToLower(a.Type)]:
+			report.Dashboards = append(report.Dashboards, impacted)
+		case a.Type == AssetTypeModel || a.Type == AssetTypeQuery || a.Type == "View" || (a.Query != nil && *a.Query != ""):
+			report.Models = append(report.Models, impacted)
+		default:
+			report.Assets = append(report.Assets, impacted)
+		}
+		report.Breaking = true
+	}
+
+	sort.SliceStable(report.Columns, func(i, j int) bool {
+		ci, cj := report.Columns[i], report.Columns[j]
+		if ci.Depth != cj.Depth {
+			return ci.Depth < cj.Depth
+		}
+		if ci.AssetMRN != cj.AssetMRN {
+			return ci.AssetMRN < cj.AssetMRN
+		}
+		return ci.Column < cj.Column
+	})
+
+	s.annotateImpactDeprecations(ctx, report)
+	return report, nil
+}
+
+// annotateImpactDeprecations marks the changed asset and every impacted
+// asset that is deprecated.
+func (s *service) annotateImpactDeprecations(ctx context.Context, report *ImpactReport) {
+	mrns := []string{report.MRN}
+	for _, group := range [][]ImpactedAsset{report.Models, report.Dashboards, report.Assets} {
+		for _, a := range group {
+			mrns = append(mrns, a.MRN)
+		}
+	}
+
+	notices := s.deprecationNotices(ctx, mrns)
+	if len(notices) == 0 {
+		return
+	}
+	report.Deprecation = notices[report.MRN]
+	for _, group := range [][]ImpactedAsset{report.Models, report.Dashboards, report.Assets} {
+		for i := range group {
+			group[i].Deprecation = notices[group[i].MRN]
+		}
+	}
+}
+
+// SchemaColumns returns the column names found in an asset schema, keyed by
+// lowercased name. It understands the column lists written by database
+// plugins, OpenLineage schema facets and JSON Schema properties.
+func SchemaColumns(schema map[string]string) map[string]string {
+	columns := map[string]string{}
+	for _, raw := range schema {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			continue
+		}
+		collectColumns(parsed, columns)
+	}
+	return columns
+}
+
+func collectColumns(v interface{}, columns map[string]string) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range []string{"column_name", "name"} {
+				if name, ok := obj[key].(string); ok && name != "" {
+					columns[strings.ToLower(name)] = name
+					break
+				}
+			}
+		}
+	case map[string]interface{}:
+		if fields, ok := t["fields"]; ok {
+			collectColumns(fields, columns)
+		}
+		if columnList, ok := t["columns"]; ok {
+			collectColumns(columnList, columns)
+		}
+		if props, ok := t["properties"].(map[string]interface{}); ok {
+			for name := range props {
+				columns[strings.ToLower(name)] = name
+			}
+		}
+	}
+}
+
+func queryReferences(query, column string) bool {
+	re, err := regexp.Compile(`(?i)(^|[^A-Za-z0-9_])` + regexp.QuoteMeta(column) + `($|[^A-Za-z0-9_])`)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(query)
+}
+
+func mergeColumns(dst, src []string) []string {
+	for _, s := range src {
+		found := false
+		for _, d := range dst {
+			if d == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+func assetName(a *asset.Asset, fallback string) string {
+	if a.Name != nil && *a.Name != "" {
+		return *a.Name
+	}
+	return fallback
+}
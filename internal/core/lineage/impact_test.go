@@ -0,0 +1,34 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaColumns(t *testing.T) {
+	schema := map[string]string{
+		"columns":     `[{"column_name": "Email", "data_type": "text"}, {"column_name": "id"}]`,
+		"dbt":         `[{"name": "total", "type": "numeric"}]`,
+		"openlineage": `{"fields": [{"name": "created_at", "type": "timestamp"}]}`,
+		"json":        `{"type": "object", "properties": {"customer_id": {"type": "string"}}}`,
+		"invalid":     `not json`,
+	}
+
+	assert.Equal(t, map[string]string{
+		"email":       "Email",
+		"id":          "id",
+		"total":       "total",
+		"created_at":  "created_at",
+		"customer_id": "customer_id",
+	}, SchemaColumns(schema))
+}
+
+func TestQueryReferences(t *testing.T) {
+	query := "SELECT o.email, sum(total_amount) AS total FROM orders o"
+
+	assert.True(t, queryReferences(query, "email"))
+	assert.True(t, queryReferences(query, "TOTAL"))
+	assert.False(t, queryReferences(query, "amount"))
+	assert.False(t, queryReferences(query, "order"))
+}
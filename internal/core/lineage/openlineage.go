@@ -288,6 +288,8 @@ func (s *service) processJobAsset(ctx context.Context, event *RunEvent, createdB
 	metadata["openlineage_producer"] = event.Producer
 	metadata["job_type"] = assetType
 	metadata["namespace"] = event.Job.Namespace
+	metadata[asset.MetadataJobNamespace] = event.Job.Namespace
+	metadata[asset.MetadataJobName] = event.Job.Name
 
 	var query string
 	var queryLanguage string
@@ -384,6 +386,7 @@ func (s *service) processDatasets(ctx context.Context, event *RunEvent, jobAsset
 			continue
 		}
 		outputMRNs = append(outputMRNs, mrn)
+		s.processColumnLineage(ctx, &output, mrn, jobAssetMRN)
 	}
 
 	for _, inputMRN := range inputMRNs {
@@ -407,6 +410,34 @@ func (s *service) processDatasets(ctx context.Context, event *RunEvent, jobAsset
 	return nil
 }
 
+// processColumnLineage records the output dataset's columnLineage facet, if
+// any, as column edges from each input dataset it reads.
+func (s *service) processColumnLineage(ctx context.Context, output *Dataset, outputMRN, jobAssetMRN string) {
+	facet, ok := output.Facets["columnLineage"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for input, columns := range columnMappingsByInput(facet) {
+		inputMRN := datasetMRN(&Dataset{Namespace: input[0], Name: input[1]})
+		if err := s.SetColumnLineage(ctx, inputMRN, outputMRN, columns, jobAssetMRN); err != nil {
+			log.Warn().Err(err).
+				Str("input_mrn", inputMRN).
+				Str("output_mrn", outputMRN).
+				Msg("Failed to store column lineage")
+		}
+	}
+}
+
+// datasetMRN returns the MRN of the asset an OpenLineage dataset maps to.
+func datasetMRN(dataset *Dataset) string {
+	return fmt.Sprintf("mrn://%s/%s/%s.%s",
+		strings.ToLower(inferDatasetType(dataset)),
+		strings.ToLower(inferDatasetProvider(dataset)),
+		dataset.Namespace,
+		dataset.Name)
+}
+
 func (s *service) processDatasetAsset(ctx context.Context, dataset *Dataset, role string, createdBy string) (string, error) {
 	provider := inferDatasetProvider(dataset)
 	assetType := inferDatasetType(dataset)
@@ -414,11 +445,7 @@ func (s *service) processDatasetAsset(ctx context.Context, dataset *Dataset, rol
 	name := dataset.Name
 	namespace := dataset.Namespace
 
-	mrn := fmt.Sprintf("mrn://%s/%s/%s.%s",
-		strings.ToLower(assetType),
-		strings.ToLower(provider),
-		namespace,
-		name)
+	mrn := datasetMRN(dataset)
 
 	desc := fmt.Sprintf("%s from %s namespace (%s)", assetType, namespace, role)
 
@@ -0,0 +1,370 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	PathModeConfidence = "confidence"
+	PathModeShortest   = "shortest"
+
+	DefaultPathDepth = 6
+	MaxPathDepth     = 10
+	DefaultPathLimit = 3
+	MaxPathLimit     = 10
+
+	// confidenceHalfLife is how long after an edge was last confirmed or
+	// observed its recency weight halves.
+	confidenceHalfLife = 30 * 24 * time.Hour
+	// minEdgeConfidence keeps weak edges traversable, so a path through them
+	// still beats no path at all.
+	minEdgeConfidence = 0.01
+)
+
+var ErrInvalidPathRequest = errors.New("invalid path request")
+
+// PathRequest asks for the routes data takes from one asset to another.
+type PathRequest struct {
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required,nefield=From"`
+	// Mode ranks paths by confidence, or by hop count with confidence
+	// breaking ties.
+	Mode  string `json:"mode,omitempty" validate:"omitempty,oneof=confidence shortest"`
+	Depth int    `json:"depth,omitempty" validate:"omitempty,min=1,max=10"`
+	Limit int    `json:"limit,omitempty" validate:"omitempty,min=1,max=10"`
+}
+
+// PathEdge is an edge of a path with how much it is trusted.
+type PathEdge struct {
+	LineageEdge
+	// Confidence is between 0 and 1. See EdgeConfidence.
+	Confidence float64 `json:"confidence"`
+} // @name LineagePathEdge
+
+// LineagePath is one route from the source asset to the target asset.
+type LineagePath struct {
+	// Nodes are the MRNs along the path, starting with the source asset.
+	Nodes []string   `json:"nodes"`
+	Edges []PathEdge `json:"edges"`
+	// Confidence is the product of the confidence of every edge.
+	Confidence float64 `json:"confidence"`
+	Hops       int     `json:"hops"`
+} // @name LineagePath
+
+// PathResult lists the best paths between two assets, best first. The first
+// path is the one to highlight.
+type PathResult struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Mode  string        `json:"mode"`
+	Paths []LineagePath `json:"paths"`
+	// Nodes are the assets on any of the paths.
+	Nodes []LineageNode `json:"nodes"`
+} // @name LineagePathResult
+
+// EdgeConfidence scores how likely an edge is to reflect how data actually
+// flows, between 0 and 1. Declared edges start fully trusted, and edges only
+// inferred from query logs gain trust with every observation. Edges a
+// pipeline stopped reporting lose half their trust, and edges confirmed by
+// a pipeline or observed lose trust with age, halving every 30 days down to
+// half their score.
+func EdgeConfidence(e LineageEdge, now time.Time) float64 {
+	confidence := 1.0
+	var seenAt *time.Time
+
+	if e.Origin == "observed" {
+		count := float64(max(e.ObservationCount, 1))
+		confidence = count / (count + 1)
+		seenAt = e.LastSeenAt
+	}
+
+	if f := e.Freshness; f != nil {
+		if f.Status == FreshnessStale {
+			confidence *= 0.5
+		}
+		if f.LastConfirmedAt != nil && (seenAt == nil || f.LastConfirmedAt.After(*seenAt)) {
+			seenAt = f.LastConfirmedAt
+		}
+	}
+
+	if seenAt != nil {
+		if age := now.Sub(*seenAt); age > 0 {
+			confidence *= 0.5 + 0.5*math.Pow(0.5, float64(age)/float64(confidenceHalfLife))
+		}
+	}
+
+	return max(confidence, minEdgeConfidence)
+}
+
+// FindPaths returns the most plausible routes data takes downstream from
+// req.From to req.To, within req.Depth hops of req.From. Paths is empty when
+// To isn't reachable.
+func (s *service) FindPaths(ctx context.Context, req *PathRequest) (*PathResult, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPathRequest, err)
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = PathModeConfidence
+	}
+	depth := req.Depth
+	if depth <= 0 {
+		depth = DefaultPathDepth
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultPathLimit
+	}
+
+	from, err := s.assetSvc.GetByMRN(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.assetSvc.GetByMRN(ctx, req.To); err != nil {
+		return nil, err
+	}
+
+	graph, err := s.repo.GetAssetLineage(ctx, from.ID, depth, "downstream")
+	if err != nil {
+		return nil, fmt.Errorf("getting downstream lineage: %w", err)
+	}
+	s.annotateFreshness(ctx, graph.Edges, DefaultStaleAfterRuns)
+
+	result := &PathResult{
+		From:  req.From,
+		To:    req.To,
+		Mode:  mode,
+		Paths: RankPaths(graph.Edges, req.From, req.To, mode, limit, time.Now()),
+		Nodes: []LineageNode{},
+	}
+
+	onPath := map[string]bool{}
+	for _, p := range result.Paths {
+		for _, n := range p.Nodes {
+			onPath[n] = true
+		}
+	}
+	for _, n := range graph.Nodes {
+		if onPath[n.ID] {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	s.annotateDeprecations(ctx, result.Nodes)
+
+	return result, nil
+}
+
+// pathCost orders paths. Weight is the sum of -ln(confidence) over a path's
+// edges, so the lowest weight is the highest confidence.
+type pathCost struct {
+	hops   int
+	weight float64
+}
+
+func (c pathCost) add(e pathEdge) pathCost {
+	return pathCost{hops: c.hops + 1, weight: c.weight + e.weight}
+}
+
+func (c pathCost) less(o pathCost, mode string) bool {
+	if mode == PathModeShortest && c.hops != o.hops {
+		return c.hops < o.hops
+	}
+	if c.weight != o.weight {
+		return c.weight < o.weight
+	}
+	return c.hops < o.hops
+}
+
+type pathEdge struct {
+	PathEdge
+	weight float64
+}
+
+type candidatePath struct {
+	nodes []string
+	edges []pathEdge
+	cost  pathCost
+}
+
+func (p candidatePath) key() string {
+	return strings.Join(p.nodes, "\x00")
+}
+
+type pathFinder struct {
+	out  map[string][]pathEdge
+	mode string
+}
+
+// RankPaths returns up to limit loop-free paths from one MRN to another over
+// edges, best first, ranked by mode. Of several edges between the same two
+// assets only the most confident is followed.
+func RankPaths(edges []LineageEdge, from, to, mode string, limit int, now time.Time) []LineagePath {
+	best := map[[2]string]pathEdge{}
+	for _, e := range edges {
+		if e.Source == e.Target {
+			continue
+		}
+		confidence := EdgeConfidence(e, now)
+		key := [2]string{e.Source, e.Target}
+		if existing, ok := best[key]; ok && existing.Confidence >= confidence {
+			continue
+		}
+		best[key] = pathEdge{
+			PathEdge: PathEdge{LineageEdge: e, Confidence: confidence},
+			weight:   -math.Log(confidence),
+		}
+	}
+
+	f := &pathFinder{out: map[string][]pathEdge{}, mode: mode}
+	for _, e := range best {
+		f.out[e.Source] = append(f.out[e.Source], e)
+	}
+	for _, out := range f.out {
+		sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	}
+
+	paths := []LineagePath{}
+	for _, c := range f.kBest(from, to, limit) {
+		p := LineagePath{
+			Nodes:      c.nodes,
+			Edges:      make([]PathEdge, len(c.edges)),
+			Confidence: math.Exp(-c.cost.weight),
+			Hops:       c.cost.hops,
+		}
+		for i, e := range c.edges {
+			p.Edges[i] = e.PathEdge
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// kBest finds the k best loop-free paths with Yen's algorithm: each next
+// best path leaves an already found path at some node, so for every node of
+// the last path found it searches for the best detour that avoids the edges
+// found paths already take from there.
+func (f *pathFinder) kBest(from, to string, k int) []candidatePath {
+	first, ok := f.best(from, to, nil, nil)
+	if !ok {
+		return nil
+	}
+
+	found := []candidatePath{first}
+	seen := map[string]bool{first.key(): true}
+	var candidates []candidatePath
+
+	for len(found) < k {
+		last := found[len(found)-1]
+		for i := range last.edges {
+			spur := last.nodes[i]
+			root := last.nodes[:i+1]
+
+			removedEdges := map[[2]string]bool{}
+			for _, p := range found {
+				if len(p.nodes) > i+1 && equalPrefix(p.nodes, root) {
+					removedEdges[[2]string{p.nodes[i], p.nodes[i+1]}] = true
+				}
+			}
+			removedNodes := map[string]bool{}
+			for _, n := range last.nodes[:i] {
+				removedNodes[n] = true
+			}
+
+			detour, ok := f.best(spur, to, removedEdges, removedNodes)
+			if !ok {
+				continue
+			}
+
+			c := candidatePath{
+				nodes: append(append([]string{}, root...), detour.nodes[1:]...),
+				edges: append(append([]pathEdge{}, last.edges[:i]...), detour.edges...),
+			}
+			for _, e := range c.edges {
+				c.cost = c.cost.add(e)
+			}
+			if key := c.key(); !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, c)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].cost.less(candidates[j].cost, f.mode)
+		})
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return found
+}
+
+// best finds the best path from one MRN to another with Dijkstra's
+// algorithm, skipping removed edges and nodes.
+func (f *pathFinder) best(from, to string, removedEdges map[[2]string]bool, removedNodes map[string]bool) (candidatePath, bool) {
+	costs := map[string]pathCost{from: {}}
+	via := map[string]pathEdge{}
+	done := map[string]bool{}
+
+	for {
+		current, found := "", false
+		for n, c := range costs {
+			if done[n] {
+				continue
+			}
+			if !found || c.less(costs[current], f.mode) || (!costs[current].less(c, f.mode) && n < current) {
+				current, found = n, true
+			}
+		}
+		if !found {
+			return candidatePath{}, false
+		}
+		if current == to {
+			break
+		}
+		done[current] = true
+
+		for _, e := range f.out[current] {
+			if done[e.Target] || removedNodes[e.Target] || removedEdges[[2]string{e.Source, e.Target}] {
+				continue
+			}
+			next := costs[current].add(e)
+			if existing, ok := costs[e.Target]; !ok || next.less(existing, f.mode) {
+				costs[e.Target] = next
+				via[e.Target] = e
+			}
+		}
+	}
+
+	var p candidatePath
+	for n := to; n != from; n = via[n].Source {
+		p.edges = append(p.edges, via[n])
+	}
+	for i, j := 0, len(p.edges)-1; i < j; i, j = i+1, j-1 {
+		p.edges[i], p.edges[j] = p.edges[j], p.edges[i]
+	}
+	p.nodes = []string{from}
+	for _, e := range p.edges {
+		p.nodes = append(p.nodes, e.Target)
+		p.cost = p.cost.add(e)
+	}
+	return p, true
+}
+
+func equalPrefix(nodes, prefix []string) bool {
+	for i, n := range prefix {
+		if nodes[i] != n {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package lineage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeConfidence(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	monthAgo := now.Add(-confidenceHalfLife)
+
+	tests := []struct {
+		name string
+		edge LineageEdge
+		want float64
+	}{
+		{
+			name: "declared untracked",
+			edge: LineageEdge{Origin: "declared", Freshness: &EdgeFreshness{Status: FreshnessUntracked}},
+			want: 1,
+		},
+		{
+			name: "observed once",
+			edge: LineageEdge{Origin: "observed", ObservationCount: 1, LastSeenAt: &now},
+			want: 0.5,
+		},
+		{
+			name: "observed often",
+			edge: LineageEdge{Origin: "observed", ObservationCount: 9, LastSeenAt: &now},
+			want: 0.9,
+		},
+		{
+			name: "confirmed a half-life ago",
+			edge: LineageEdge{Freshness: &EdgeFreshness{Status: FreshnessCurrent, LastConfirmedAt: &monthAgo}},
+			want: 0.75,
+		},
+		{
+			name: "stale",
+			edge: LineageEdge{Freshness: &EdgeFreshness{Status: FreshnessStale, LastConfirmedAt: &now}},
+			want: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, EdgeConfidence(tt.edge, now), 0.0001)
+		})
+	}
+
+	assert.Less(t, EdgeConfidence(LineageEdge{Freshness: &EdgeFreshness{Status: FreshnessCurrent, LastConfirmedAt: &monthAgo}}, now),
+		EdgeConfidence(LineageEdge{Freshness: &EdgeFreshness{Status: FreshnessCurrent, LastConfirmedAt: &recent}}, now))
+}
+
+func TestRankPaths(t *testing.T) {
+	now := time.Now()
+	declared := func(source, target string) LineageEdge {
+		return LineageEdge{Source: source, Target: target, Type: "DIRECT", Origin: "declared"}
+	}
+	observed := func(source, target string) LineageEdge {
+		return LineageEdge{Source: source, Target: target, Type: "DIRECT", Origin: "observed", ObservationCount: 1, LastSeenAt: &now}
+	}
+
+	// topic -> dashboard directly on a weak observed edge, or through two
+	// hops of declared lineage, or through a cycle that must not loop.
+	edges := []LineageEdge{
+		observed("topic", "dashboard"),
+		declared("topic", "table"),
+		declared("table", "model"),
+		declared("model", "dashboard"),
+		declared("model", "table"),
+		observed("table", "dashboard"),
+	}
+
+	t.Run("confidence", func(t *testing.T) {
+		paths := RankPaths(edges, "topic", "dashboard", PathModeConfidence, 5, now)
+		require.Len(t, paths, 3)
+
+		assert.Equal(t, []string{"topic", "table", "model", "dashboard"}, paths[0].Nodes)
+		assert.InDelta(t, 1.0, paths[0].Confidence, 0.0001)
+		assert.Equal(t, 3, paths[0].Hops)
+		require.Len(t, paths[0].Edges, 3)
+		assert.Equal(t, "model", paths[0].Edges[2].Source)
+
+		assert.Equal(t, []string{"topic", "dashboard"}, paths[1].Nodes)
+		assert.Equal(t, []string{"topic", "table", "dashboard"}, paths[2].Nodes)
+		assert.InDelta(t, 0.5, paths[1].Confidence, 0.0001)
+		assert.InDelta(t, 0.5, paths[2].Confidence, 0.0001)
+	})
+
+	t.Run("shortest", func(t *testing.T) {
+		paths := RankPaths(edges, "topic", "dashboard", PathModeShortest, 2, now)
+		require.Len(t, paths, 2)
+		assert.Equal(t, []string{"topic", "dashboard"}, paths[0].Nodes)
+		assert.Equal(t, []string{"topic", "table", "dashboard"}, paths[1].Nodes)
+	})
+
+	t.Run("prefers the more confident of parallel edges", func(t *testing.T) {
+		paths := RankPaths([]LineageEdge{observed("a", "b"), declared("a", "b")}, "a", "b", PathModeConfidence, 3, now)
+		require.Len(t, paths, 1)
+		assert.Equal(t, "declared", paths[0].Edges[0].Origin)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		assert.Empty(t, RankPaths(edges, "dashboard", "topic", PathModeConfidence, 3, now))
+	})
+}
@@ -0,0 +1,137 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxPurgePreview caps the number of matching edges listed in a purge result.
+const MaxPurgePreview = 100
+
+var ErrInvalidPurgeRequest = errors.New("invalid purge request")
+
+// PurgeRequest selects lineage edges to delete in bulk. Filters are combined,
+// so an edge must match every filter that is set, and at least one must be.
+type PurgeRequest struct {
+	// Pipeline matches edges reported by any run of the pipeline.
+	Pipeline string `json:"pipeline,omitempty"`
+	// MRNPattern matches edges whose source or target MRN matches the
+	// pattern, where * matches any run of characters.
+	MRNPattern string `json:"mrn_pattern,omitempty"`
+	// Type matches the edge type, e.g. DIRECT or DEPENDS_ON.
+	Type string `json:"type,omitempty"`
+	// DryRun only reports the edges that would be deleted.
+	DryRun bool `json:"dry_run,omitempty"`
+} // @name LineagePurgeRequest
+
+// PurgeFilter is a validated PurgeRequest as the repository applies it.
+type PurgeFilter struct {
+	Pipeline string
+	// MRNLike is MRNPattern translated to a LIKE pattern.
+	MRNLike string
+	Type    string
+}
+
+// PurgeResult reports the edges a purge matched.
+type PurgeResult struct {
+	DryRun bool `json:"dry_run"`
+	// Matched is the number of edges matching the request.
+	Matched int `json:"matched"`
+	// Deleted is the number of edges deleted, always zero on a dry run.
+	Deleted int `json:"deleted"`
+	// Edges lists up to MaxPurgePreview of the matched edges.
+	Edges     []LineageEdge `json:"edges"`
+	Truncated bool          `json:"truncated"`
+} // @name LineagePurgeResult
+
+// Filter validates the request and returns the filter it describes.
+func (r PurgeRequest) Filter() (PurgeFilter, error) {
+	f := PurgeFilter{
+		Pipeline: strings.TrimSpace(r.Pipeline),
+		Type:     strings.ToUpper(strings.TrimSpace(r.Type)),
+	}
+
+	pattern := strings.TrimSpace(r.MRNPattern)
+	if pattern != "" {
+		if strings.Trim(pattern, "*") == "" {
+			return f, fmt.Errorf("%w: mrn_pattern must contain more than wildcards", ErrInvalidPurgeRequest)
+		}
+		f.MRNLike = likePattern(pattern)
+	}
+
+	if f.Pipeline == "" && f.MRNLike == "" && f.Type == "" {
+		return f, fmt.Errorf("%w: at least one of pipeline, mrn_pattern or type is required", ErrInvalidPurgeRequest)
+	}
+	return f, nil
+}
+
+// likePattern turns a glob with * wildcards into a LIKE pattern, escaping
+// LIKE's own wildcards so they match literally.
+func likePattern(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '\\', '%', '_':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '*':
+			b.WriteRune('%')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// PurgeLineage deletes every lineage edge matching req, or with req.DryRun
+// only reports them. Assets at either end of a deleted edge are kept.
+func (s *service) PurgeLineage(ctx context.Context, req PurgeRequest) (*PurgeResult, error) {
+	filter, err := req.Filter()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{DryRun: req.DryRun}
+
+	if req.DryRun {
+		edges, matched, err := s.repo.FindEdges(ctx, filter, MaxPurgePreview)
+		if err != nil {
+			return nil, err
+		}
+		result.Matched = matched
+		result.Edges = edges
+		result.Truncated = matched > len(edges)
+		return result, nil
+	}
+
+	deleted, err := s.repo.DeleteEdges(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result.Matched = len(deleted)
+	result.Deleted = len(deleted)
+	result.Edges = deleted
+	if len(deleted) > MaxPurgePreview {
+		result.Edges = deleted[:MaxPurgePreview]
+		result.Truncated = true
+	}
+
+	if s.lineageObserver != nil {
+		for _, edge := range deleted {
+			s.lineageObserver.OnEdgeDeleted(ctx, edge.Source, edge.Target)
+		}
+	}
+
+	log.Info().
+		Str("pipeline", filter.Pipeline).
+		Str("mrn_pattern", req.MRNPattern).
+		Str("type", filter.Type).
+		Int("deleted", result.Deleted).
+		Msg("Purged lineage edges")
+
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeRequestFilter(t *testing.T) {
+	f, err := PurgeRequest{Pipeline: " warehouse ", MRNPattern: "mrn://table/postgres/staging_*", Type: "depends_on"}.Filter()
+	require.NoError(t, err)
+	assert.Equal(t, PurgeFilter{
+		Pipeline: "warehouse",
+		MRNLike:  `mrn://table/postgres/staging\_%`,
+		Type:     "DEPENDS_ON",
+	}, f)
+
+	_, err = PurgeRequest{DryRun: true}.Filter()
+	assert.ErrorIs(t, err, ErrInvalidPurgeRequest)
+
+	_, err = PurgeRequest{MRNPattern: "**"}.Filter()
+	assert.ErrorIs(t, err, ErrInvalidPurgeRequest)
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "mrn://topic/kafka/%", likePattern("mrn://topic/kafka/*"))
+	assert.Equal(t, `100\%\_done\\%`, likePattern(`100%_done\*`))
+}
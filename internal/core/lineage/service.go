@@ -19,15 +19,28 @@ type LineageChangeObserver interface {
 
 type Service interface {
 	GetAssetLineage(ctx context.Context, assetID string, limit int, direction string) (*LineageResponse, error)
+	GetSimplifiedAssetLineage(ctx context.Context, assetID string, limit int, direction string, opts SimplifyOptions) (*LineageResponse, error)
+	ExportLineage(ctx context.Context, assetID string, depth int) (*LineageResponse, error)
+	AnalyzeImpact(ctx context.Context, req *ImpactRequest) (*ImpactReport, error)
+	FindPaths(ctx context.Context, req *PathRequest) (*PathResult, error)
 	CreateDirectLineage(ctx context.Context, sourceMRN string, targetMRN string, lineageType string) (string, error)
+	BatchCreateLineage(ctx context.Context, edges []LineageEdge, opts BatchOptions) ([]BatchEdgeResult, error)
 	BatchObservedLineage(ctx context.Context, edges []ObservedEdge) error
 	EdgeExists(ctx context.Context, source, target string) (bool, error)
 	DeleteDirectLineage(ctx context.Context, edgeID string) error
+	PurgeLineage(ctx context.Context, req PurgeRequest) (*PurgeResult, error)
 	GetDirectLineage(ctx context.Context, edgeID string) (*LineageEdge, error)
 	GetImmediateNeighbors(ctx context.Context, assetMRN string, direction string) ([]string, error)
 	SetLineageChangeObserver(observer LineageChangeObserver)
+	AddLineageChangeObserver(observer LineageChangeObserver)
+	SetDeprecationLookup(lookup DeprecationLookup)
+	SetStubQuota(quota StubQuota)
 	ProcessOpenLineageEvent(ctx context.Context, event *RunEvent, createdBy string) error
 	StoreRunHistory(ctx context.Context, entry *RunHistoryEntry) error
+	CreateColumnLineage(ctx context.Context, edges []ColumnLineageEdge) ([]ColumnLineageEdge, error)
+	SetColumnLineage(ctx context.Context, sourceMRN, targetMRN string, columns []ColumnMapping, jobMRN string) error
+	GetColumnLineage(ctx context.Context, q ColumnLineageQuery) (*ColumnLineageResponse, error)
+	DeleteColumnLineage(ctx context.Context, id string) error
 }
 
 type Logger interface {
@@ -46,6 +59,8 @@ type service struct {
 	metrics         MetricsClient
 	assetSvc        asset.Service
 	lineageObserver LineageChangeObserver
+	deprecations    DeprecationLookup
+	stubQuota       StubQuota
 }
 
 type ServiceOption func(*service)
@@ -71,11 +86,61 @@ func WithMetrics(metrics MetricsClient) ServiceOption {
 }
 
 func (s *service) GetAssetLineage(ctx context.Context, assetID string, limit int, direction string) (*LineageResponse, error) {
-	return s.repo.GetAssetLineage(ctx, assetID, limit, direction)
+	graph, err := s.repo.GetAssetLineage(ctx, assetID, limit, direction)
+	if err != nil {
+		return nil, err
+	}
+	s.annotateFreshness(ctx, graph.Edges, DefaultStaleAfterRuns)
+	s.annotateDeprecations(ctx, graph.Nodes)
+	return graph, nil
+}
+
+// GetSimplifiedAssetLineage returns the lineage graph around assetID reduced
+// according to opts, so very wide graphs stay readable.
+func (s *service) GetSimplifiedAssetLineage(ctx context.Context, assetID string, limit int, direction string, opts SimplifyOptions) (*LineageResponse, error) {
+	graph, err := s.repo.GetAssetLineage(ctx, assetID, limit, direction)
+	if err != nil {
+		return nil, err
+	}
+	s.annotateFreshness(ctx, graph.Edges, opts.StaleAfterRuns)
+	s.annotateDeprecations(ctx, graph.Nodes)
+
+	rootID := ""
+	for _, n := range graph.Nodes {
+		if n.Asset != nil && n.Asset.ID == assetID {
+			rootID = n.ID
+			break
+		}
+	}
+
+	return Simplify(graph, rootID, opts), nil
+}
+
+// ExportLineage returns the lineage graph around assetID up to depth hops in
+// both directions, or the whole lineage graph when assetID is empty. Depth is
+// capped at MaxExportDepth. An unscoped export stops at DefaultExportLimit
+// nodes and sets Truncated when more were available.
+func (s *service) ExportLineage(ctx context.Context, assetID string, depth int) (*LineageResponse, error) {
+	if assetID == "" {
+		return s.repo.GetLineageGraph(ctx, DefaultExportLimit)
+	}
+	if depth <= 0 {
+		depth = DefaultExportDepth
+	}
+	if depth > MaxExportDepth {
+		depth = MaxExportDepth
+	}
+	return s.repo.GetAssetLineage(ctx, assetID, depth, "")
 }
 
 func (s *service) GetDirectLineage(ctx context.Context, edgeID string) (*LineageEdge, error) {
-	return s.repo.GetDirectLineage(ctx, edgeID)
+	edge, err := s.repo.GetDirectLineage(ctx, edgeID)
+	if err != nil || edge == nil {
+		return edge, err
+	}
+	edges := []LineageEdge{*edge}
+	s.annotateFreshness(ctx, edges, DefaultStaleAfterRuns)
+	return &edges[0], nil
 }
 
 func (s *service) CreateDirectLineage(ctx context.Context, sourceMRN string, targetMRN string, lineageType string) (string, error) {
@@ -137,6 +202,36 @@ func (s *service) SetLineageChangeObserver(observer LineageChangeObserver) {
 	s.lineageObserver = observer
 }
 
+// AddLineageChangeObserver registers an observer alongside those already
+// registered. Must be called during initialization before any lineage
+// operations begin.
+func (s *service) AddLineageChangeObserver(observer LineageChangeObserver) {
+	if s.lineageObserver == nil {
+		s.lineageObserver = observer
+		return
+	}
+	observers, ok := s.lineageObserver.(lineageObservers)
+	if !ok {
+		observers = lineageObservers{s.lineageObserver}
+	}
+	s.lineageObserver = append(observers, observer)
+}
+
+// lineageObservers notifies several observers of each lineage change.
+type lineageObservers []LineageChangeObserver
+
+func (o lineageObservers) OnEdgeCreated(ctx context.Context, sourceMRN, targetMRN, edgeType string) {
+	for _, observer := range o {
+		observer.OnEdgeCreated(ctx, sourceMRN, targetMRN, edgeType)
+	}
+}
+
+func (o lineageObservers) OnEdgeDeleted(ctx context.Context, sourceMRN, targetMRN string) {
+	for _, observer := range o {
+		observer.OnEdgeDeleted(ctx, sourceMRN, targetMRN)
+	}
+}
+
 func (s *service) StoreRunHistory(ctx context.Context, entry *RunHistoryEntry) error {
 	return s.repo.StoreRunHistory(ctx, entry)
 }
@@ -0,0 +1,480 @@
+package lineage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+const (
+	GroupByProvider = "provider"
+	GroupBySchema   = "schema"
+
+	// GroupNodeType is the node type used for nodes that stand in for a
+	// group of assets.
+	GroupNodeType = "Group"
+)
+
+// SimplifyOptions controls how a lineage graph is reduced before it is
+// returned. The zero value leaves the graph unchanged.
+type SimplifyOptions struct {
+	// HideStale removes edges whose pipeline has stopped reporting them,
+	// along with assets only connected to the root through them.
+	HideStale bool `json:"hide_stale,omitempty"`
+	// StaleAfterRuns is the number of missed runs after which an edge is
+	// stale. Default: DefaultStaleAfterRuns.
+	StaleAfterRuns int `json:"stale_after_runs,omitempty"`
+	// HideStubs removes stub assets, bridging their neighbours so paths
+	// through them are kept.
+	HideStubs bool `json:"hide_stubs,omitempty"`
+	// CollapsePassThrough replaces assets with exactly one input and one
+	// output by a single edge recording the assets it passes through.
+	CollapsePassThrough bool `json:"collapse_pass_through,omitempty"`
+	// GroupBy merges assets sharing a provider or schema into a single node.
+	GroupBy string `json:"group_by,omitempty"`
+	// MaxEdgesPerNode caps the incoming and outgoing edges kept for each
+	// node. Hidden edges are counted on the node so the UI can offer to
+	// expand it. Zero means no limit.
+	MaxEdgesPerNode int `json:"max_edges_per_node,omitempty"`
+	// Expand lists node IDs exempt from MaxEdgesPerNode.
+	Expand []string `json:"expand,omitempty"`
+}
+
+// IsZero reports whether the options leave the graph unchanged.
+func (o SimplifyOptions) IsZero() bool {
+	return !o.HideStale && !o.HideStubs && !o.CollapsePassThrough && o.GroupBy == "" && o.MaxEdgesPerNode <= 0
+}
+
+// Validate checks the options for unsupported values.
+func (o SimplifyOptions) Validate() error {
+	switch o.GroupBy {
+	case "", GroupByProvider, GroupBySchema:
+	default:
+		return fmt.Errorf("group_by must be %q or %q", GroupByProvider, GroupBySchema)
+	}
+	if o.MaxEdgesPerNode < 0 {
+		return fmt.Errorf("max_edges_per_node must not be negative")
+	}
+	if o.StaleAfterRuns < 0 {
+		return fmt.Errorf("stale_after_runs must not be negative")
+	}
+	return nil
+}
+
+// SimplificationSummary reports what was removed from a simplified graph.
+type SimplificationSummary struct {
+	HiddenStaleEdges int `json:"hidden_stale_edges"`
+	HiddenStubs      int `json:"hidden_stubs"`
+	CollapsedNodes   int `json:"collapsed_nodes"`
+	GroupedNodes     int `json:"grouped_nodes"`
+	HiddenEdges      int `json:"hidden_edges"`
+	HiddenNodes      int `json:"hidden_nodes"`
+} // @name LineageSimplificationSummary
+
+// Simplify reduces graph according to opts. rootID is the ID of the node the
+// graph was fetched around; it is never hidden, collapsed or grouped. The
+// input graph is not modified.
+func Simplify(graph *LineageResponse, rootID string, opts SimplifyOptions) *LineageResponse {
+	if graph == nil || opts.IsZero() {
+		return graph
+	}
+
+	g := newSimpleGraph(graph)
+	summary := &SimplificationSummary{}
+
+	if opts.HideStale {
+		before := len(g.order)
+		summary.HiddenStaleEdges = g.removeStale(rootID)
+		summary.HiddenNodes += before - len(g.order)
+	}
+
+	if opts.HideStubs {
+		for _, id := range append([]string(nil), g.order...) {
+			if id != rootID && g.nodes[id].Asset != nil && g.nodes[id].Asset.IsStub {
+				g.bridge(id)
+				summary.HiddenStubs++
+			}
+		}
+	}
+
+	if opts.CollapsePassThrough {
+		for changed := true; changed; {
+			changed = false
+			for _, id := range append([]string(nil), g.order...) {
+				if id == rootID || g.nodes[id] == nil || !g.isPassThrough(id) {
+					continue
+				}
+				g.bridge(id)
+				summary.CollapsedNodes++
+				changed = true
+			}
+		}
+	}
+
+	if opts.GroupBy != "" {
+		summary.GroupedNodes = g.group(rootID, opts.GroupBy)
+	}
+
+	if opts.MaxEdgesPerNode > 0 {
+		before := len(g.order)
+		summary.HiddenEdges = g.limitEdges(rootID, opts.MaxEdgesPerNode, opts.Expand)
+		summary.HiddenNodes += before - len(g.order)
+	}
+
+	resp := g.response()
+	resp.Simplification = summary
+	return resp
+}
+
+type edgeKey struct{ source, target string }
+
+// simpleGraph is a mutable copy of a lineage graph keyed by node ID.
+type simpleGraph struct {
+	order []string
+	nodes map[string]*LineageNode
+	edges map[edgeKey]*LineageEdge
+	out   map[string]map[string]bool
+	in    map[string]map[string]bool
+}
+
+func newSimpleGraph(graph *LineageResponse) *simpleGraph {
+	g := &simpleGraph{
+		nodes: make(map[string]*LineageNode, len(graph.Nodes)),
+		edges: make(map[edgeKey]*LineageEdge, len(graph.Edges)),
+		out:   map[string]map[string]bool{},
+		in:    map[string]map[string]bool{},
+	}
+	for _, n := range graph.Nodes {
+		if _, ok := g.nodes[n.ID]; ok {
+			continue
+		}
+		node := n
+		g.nodes[n.ID] = &node
+		g.order = append(g.order, n.ID)
+	}
+	for _, e := range graph.Edges {
+		if g.nodes[e.Source] == nil || g.nodes[e.Target] == nil {
+			continue
+		}
+		edge := e
+		g.addEdge(&edge)
+	}
+	return g
+}
+
+func (g *simpleGraph) addEdge(e *LineageEdge) {
+	if e.Source == e.Target {
+		return
+	}
+	key := edgeKey{e.Source, e.Target}
+	if existing, ok := g.edges[key]; ok {
+		// Keep the shortest description of the path.
+		if len(e.Via) < len(existing.Via) {
+			g.edges[key] = e
+		}
+		return
+	}
+	g.edges[key] = e
+	if g.out[e.Source] == nil {
+		g.out[e.Source] = map[string]bool{}
+	}
+	if g.in[e.Target] == nil {
+		g.in[e.Target] = map[string]bool{}
+	}
+	g.out[e.Source][e.Target] = true
+	g.in[e.Target][e.Source] = true
+}
+
+func (g *simpleGraph) removeEdge(source, target string) {
+	delete(g.edges, edgeKey{source, target})
+	delete(g.out[source], target)
+	delete(g.in[target], source)
+}
+
+func (g *simpleGraph) removeNode(id string) {
+	for t := range g.out[id] {
+		g.removeEdge(id, t)
+	}
+	for s := range g.in[id] {
+		g.removeEdge(s, id)
+	}
+	delete(g.nodes, id)
+	delete(g.out, id)
+	delete(g.in, id)
+	for i, o := range g.order {
+		if o == id {
+			g.order = append(g.order[:i], g.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// bridge removes a node, connecting each of its inputs directly to each of
+// its outputs. The new edges record the removed node in Via.
+func (g *simpleGraph) bridge(id string) {
+	for s := range g.in[id] {
+		inEdge := g.edges[edgeKey{s, id}]
+		for t := range g.out[id] {
+			outEdge := g.edges[edgeKey{id, t}]
+			via := make([]string, 0, len(inEdge.Via)+len(outEdge.Via)+1)
+			via = append(via, inEdge.Via...)
+			via = append(via, id)
+			via = append(via, outEdge.Via...)
+			g.addEdge(&LineageEdge{
+				ID:     inEdge.ID,
+				Source: s,
+				Target: t,
+				Type:   inEdge.Type,
+				JobMRN: inEdge.JobMRN,
+				Via:    via,
+			})
+		}
+	}
+	g.removeNode(id)
+}
+
+func (g *simpleGraph) isPassThrough(id string) bool {
+	if len(g.in[id]) != 1 || len(g.out[id]) != 1 {
+		return false
+	}
+	for s := range g.in[id] {
+		if g.out[id][s] {
+			return false
+		}
+	}
+	return true
+}
+
+// group merges nodes sharing a group key into one node per key. Keys with a
+// single member are left alone. It returns the number of nodes merged.
+func (g *simpleGraph) group(rootID, by string) int {
+	members := map[string][]string{}
+	var keys []string
+	for _, id := range g.order {
+		if id == rootID {
+			continue
+		}
+		key := groupKey(g.nodes[id], by)
+		if key == "" {
+			continue
+		}
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], id)
+	}
+
+	grouped := 0
+	for _, key := range keys {
+		ids := members[key]
+		if len(ids) < 2 {
+			continue
+		}
+
+		groupID := "group:" + by + ":" + key
+		groupNode := &LineageNode{ID: groupID, Type: GroupNodeType, Label: key, Members: ids, Depth: g.nodes[ids[0]].Depth}
+		for _, id := range ids {
+			if d := g.nodes[id].Depth; abs(d) < abs(groupNode.Depth) {
+				groupNode.Depth = d
+			}
+		}
+
+		isMember := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			isMember[id] = true
+		}
+
+		var rewired []*LineageEdge
+		for _, id := range ids {
+			for t := range g.out[id] {
+				rewired = append(rewired, g.edges[edgeKey{id, t}])
+			}
+			for s := range g.in[id] {
+				if !isMember[s] {
+					rewired = append(rewired, g.edges[edgeKey{s, id}])
+				}
+			}
+		}
+		for _, id := range ids {
+			g.removeNode(id)
+		}
+
+		g.nodes[groupID] = groupNode
+		g.order = append(g.order, groupID)
+		sort.Slice(rewired, func(i, j int) bool {
+			if rewired[i].Source != rewired[j].Source {
+				return rewired[i].Source < rewired[j].Source
+			}
+			return rewired[i].Target < rewired[j].Target
+		})
+		for _, old := range rewired {
+			e := *old
+			if isMember[e.Source] {
+				e.Source = groupID
+			}
+			if isMember[e.Target] {
+				e.Target = groupID
+			}
+			// Edges between two members become self-loops, which addEdge
+			// drops.
+			e.Via = nil
+			g.addEdge(&e)
+		}
+		grouped += len(ids)
+	}
+	return grouped
+}
+
+func groupKey(n *LineageNode, by string) string {
+	if n.Asset == nil {
+		return ""
+	}
+
+	provider := ""
+	if len(n.Asset.Providers) > 0 {
+		provider = strings.ToLower(n.Asset.Providers[0])
+	} else if f, err := mrn.Parse(n.ID); err == nil {
+		provider = f.Service
+	}
+
+	switch by {
+	case GroupByProvider:
+		return provider
+	case GroupBySchema:
+		if s, ok := n.Asset.Metadata["schema"].(string); ok && s != "" {
+			if db, ok := n.Asset.Metadata["database"].(string); ok && db != "" {
+				s = db + "." + s
+			}
+			return provider + "/" + s
+		}
+		f, err := mrn.Parse(n.ID)
+		if err != nil {
+			return ""
+		}
+		i := strings.LastIndex(f.Name, ".")
+		if i <= 0 {
+			return ""
+		}
+		return provider + "/" + f.Name[:i]
+	}
+	return ""
+}
+
+// limitEdges keeps at most max outgoing and max incoming edges per node,
+// preferring edges to nodes nearer the root, then drops nodes no longer
+// connected to the root. It returns the number of edges removed.
+func (g *simpleGraph) limitEdges(rootID string, max int, expand []string) int {
+	exempt := make(map[string]bool, len(expand))
+	for _, id := range expand {
+		exempt[id] = true
+	}
+
+	before := len(g.edges)
+	for _, id := range append([]string(nil), g.order...) {
+		if exempt[id] {
+			continue
+		}
+		node := g.nodes[id]
+		for _, t := range g.overflow(g.out[id], max) {
+			g.removeEdge(id, t)
+			node.HiddenDownstream++
+		}
+		for _, s := range g.overflow(g.in[id], max) {
+			g.removeEdge(s, id)
+			node.HiddenUpstream++
+		}
+	}
+	removed := before - len(g.edges)
+	g.pruneUnreachable(rootID)
+	return removed
+}
+
+// removeStale removes stale edges, then drops nodes no longer connected to
+// the root. It returns the number of edges removed.
+func (g *simpleGraph) removeStale(rootID string) int {
+	removed := 0
+	for key, e := range g.edges {
+		if e.Freshness != nil && e.Freshness.Status == FreshnessStale {
+			g.removeEdge(key.source, key.target)
+			removed++
+		}
+	}
+	g.pruneUnreachable(rootID)
+	return removed
+}
+
+// pruneUnreachable drops nodes with no path to the root in either direction.
+func (g *simpleGraph) pruneUnreachable(rootID string) {
+	if g.nodes[rootID] == nil {
+		return
+	}
+
+	reachable := map[string]bool{rootID: true}
+	queue := []string{rootID}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, adj := range []map[string]bool{g.out[cur], g.in[cur]} {
+			for n := range adj {
+				if !reachable[n] {
+					reachable[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	for _, id := range append([]string(nil), g.order...) {
+		if !reachable[id] {
+			g.removeNode(id)
+		}
+	}
+}
+
+// overflow returns the neighbours beyond the first max, ordered by distance
+// from the root and then by ID so results are stable.
+func (g *simpleGraph) overflow(neighbours map[string]bool, max int) []string {
+	if len(neighbours) <= max {
+		return nil
+	}
+	ids := make([]string, 0, len(neighbours))
+	for n := range neighbours {
+		ids = append(ids, n)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		di, dj := abs(g.nodes[ids[i]].Depth), abs(g.nodes[ids[j]].Depth)
+		if di != dj {
+			return di < dj
+		}
+		return ids[i] < ids[j]
+	})
+	return ids[max:]
+}
+
+func (g *simpleGraph) response() *LineageResponse {
+	resp := &LineageResponse{
+		Nodes: make([]LineageNode, 0, len(g.order)),
+		Edges: make([]LineageEdge, 0, len(g.edges)),
+	}
+	for _, id := range g.order {
+		resp.Nodes = append(resp.Nodes, *g.nodes[id])
+	}
+	for _, e := range g.edges {
+		resp.Edges = append(resp.Edges, *e)
+	}
+	sort.Slice(resp.Edges, func(i, j int) bool {
+		if resp.Edges[i].Source != resp.Edges[j].Source {
+			return resp.Edges[i].Source < resp.Edges[j].Source
+		}
+		return resp.Edges[i].Target < resp.Edges[j].Target
+	})
+	return resp
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
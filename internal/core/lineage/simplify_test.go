@@ -0,0 +1,185 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func node(id string, depth int, provider string, stub bool) LineageNode {
+	return LineageNode{ID: id, Type: "Table", Depth: depth, Asset: &asset.Asset{IsStub: stub, Providers: []string{provider}}}
+}
+
+func edge(source, target string) LineageEdge {
+	return LineageEdge{ID: source + "->" + target, Source: source, Target: target, Type: "DIRECT"}
+}
+
+func nodeIDs(g *LineageResponse) []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func findNode(t *testing.T, g *LineageResponse, id string) LineageNode {
+	t.Helper()
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	require.FailNow(t, "node not found", id)
+	return LineageNode{}
+}
+
+func TestSimplify_ZeroOptionsReturnsGraph(t *testing.T) {
+	g := &LineageResponse{Nodes: []LineageNode{node("a", 0, "kafka", false)}}
+	assert.Same(t, g, Simplify(g, "a", SimplifyOptions{}))
+}
+
+func TestSimplify_CollapsePassThrough(t *testing.T) {
+	// root -> b -> c -> d, with c also feeding e.
+	g := &LineageResponse{
+		Nodes: []LineageNode{
+			node("root", 0, "kafka", false),
+			node("b", 1, "postgres", false),
+			node("c", 2, "postgres", false),
+			node("d", 3, "postgres", false),
+			node("e", 3, "postgres", false),
+		},
+		Edges: []LineageEdge{edge("root", "b"), edge("b", "c"), edge("c", "d"), edge("c", "e")},
+	}
+
+	got := Simplify(g, "root", SimplifyOptions{CollapsePassThrough: true})
+
+	assert.Equal(t, []string{"root", "c", "d", "e"}, nodeIDs(got))
+	require.Len(t, got.Edges, 3)
+	assert.Equal(t, "root", got.Edges[2].Source)
+	assert.Equal(t, "c", got.Edges[2].Target)
+	assert.Equal(t, []string{"b"}, got.Edges[2].Via)
+	assert.Equal(t, 1, got.Simplification.CollapsedNodes)
+	assert.Len(t, g.Nodes, 5, "input graph must not be modified")
+}
+
+func TestSimplify_HideStubsKeepsPaths(t *testing.T) {
+	g := &LineageResponse{
+		Nodes: []LineageNode{
+			node("root", 0, "kafka", false),
+			node("stub", 1, "s3", true),
+			node("leaf", 2, "postgres", false),
+			node("dangling", 1, "s3", true),
+		},
+		Edges: []LineageEdge{edge("root", "stub"), edge("stub", "leaf"), edge("root", "dangling")},
+	}
+
+	got := Simplify(g, "root", SimplifyOptions{HideStubs: true})
+
+	assert.Equal(t, []string{"root", "leaf"}, nodeIDs(got))
+	require.Len(t, got.Edges, 1)
+	assert.Equal(t, LineageEdge{ID: "root->stub", Source: "root", Target: "leaf", Type: "DIRECT", Via: []string{"stub"}}, got.Edges[0])
+	assert.Equal(t, 2, got.Simplification.HiddenStubs)
+}
+
+func TestSimplify_GroupByProvider(t *testing.T) {
+	g := &LineageResponse{
+		Nodes: []LineageNode{
+			node("root", 0, "kafka", false),
+			node("p1", 1, "postgres", false),
+			node("p2", 2, "postgres", false),
+			node("s1", 1, "snowflake", false),
+		},
+		Edges: []LineageEdge{edge("root", "p1"), edge("p1", "p2"), edge("root", "s1"), edge("p2", "s1")},
+	}
+
+	got := Simplify(g, "root", SimplifyOptions{GroupBy: GroupByProvider})
+
+	assert.Equal(t, []string{"root", "s1", "group:provider:postgres"}, nodeIDs(got))
+	group := findNode(t, got, "group:provider:postgres")
+	assert.Equal(t, GroupNodeType, group.Type)
+	assert.Equal(t, []string{"p1", "p2"}, group.Members)
+	assert.Equal(t, 1, group.Depth)
+
+	pairs := [][2]string{}
+	for _, e := range got.Edges {
+		pairs = append(pairs, [2]string{e.Source, e.Target})
+	}
+	assert.ElementsMatch(t, [][2]string{
+		{"root", "group:provider:postgres"},
+		{"root", "s1"},
+		{"group:provider:postgres", "s1"},
+	}, pairs)
+	assert.Equal(t, 2, got.Simplification.GroupedNodes)
+}
+
+func TestGroupKey_Schema(t *testing.T) {
+	withMeta := LineageNode{ID: "mrn://table/postgres/shop.public.orders", Asset: &asset.Asset{
+		Providers: []string{"PostgreSQL"},
+		Metadata:  map[string]interface{}{"database": "shop", "schema": "public"},
+	}}
+	assert.Equal(t, "postgresql/shop.public", groupKey(&withMeta, GroupBySchema))
+
+	fromMRN := LineageNode{ID: "mrn://table/snowflake/analytics.marts.orders", Asset: &asset.Asset{}}
+	assert.Equal(t, "snowflake/analytics.marts", groupKey(&fromMRN, GroupBySchema))
+
+	flat := LineageNode{ID: "mrn://topic/kafka/orders", Asset: &asset.Asset{}}
+	assert.Empty(t, groupKey(&flat, GroupBySchema))
+}
+
+func TestSimplify_MaxEdgesPerNode(t *testing.T) {
+	g := &LineageResponse{
+		Nodes: []LineageNode{
+			node("root", 0, "kafka", false),
+			node("a", 1, "postgres", false),
+			node("b", 1, "postgres", false),
+			node("c", 1, "postgres", false),
+			node("c-child", 2, "postgres", false),
+		},
+		Edges: []LineageEdge{edge("root", "a"), edge("root", "b"), edge("root", "c"), edge("c", "c-child")},
+	}
+
+	got := Simplify(g, "root", SimplifyOptions{MaxEdgesPerNode: 2})
+
+	assert.Equal(t, []string{"root", "a", "b"}, nodeIDs(got))
+	assert.Equal(t, 1, findNode(t, got, "root").HiddenDownstream)
+	assert.Equal(t, 1, got.Simplification.HiddenEdges)
+	assert.Equal(t, 2, got.Simplification.HiddenNodes)
+
+	expanded := Simplify(g, "root", SimplifyOptions{MaxEdgesPerNode: 2, Expand: []string{"root"}})
+	assert.Len(t, expanded.Nodes, 5)
+	assert.Zero(t, findNode(t, expanded, "root").HiddenDownstream)
+}
+
+func TestSimplifyOptions_Validate(t *testing.T) {
+	assert.NoError(t, SimplifyOptions{GroupBy: GroupBySchema}.Validate())
+	assert.Error(t, SimplifyOptions{GroupBy: "owner"}.Validate())
+	assert.Error(t, SimplifyOptions{MaxEdgesPerNode: -1}.Validate())
+}
+
+func TestSimplify_HideStale(t *testing.T) {
+	// root -> a is stale and is a's only link to the root, so a and b drop
+	// out with it.
+	stale := edge("root", "a")
+	stale.Freshness = &EdgeFreshness{Status: FreshnessStale, MissedRuns: 4}
+	current := edge("root", "c")
+	current.Freshness = &EdgeFreshness{Status: FreshnessCurrent}
+	g := &LineageResponse{
+		Nodes: []LineageNode{
+			node("root", 0, "kafka", false),
+			node("a", 1, "postgres", false),
+			node("b", 2, "postgres", false),
+			node("c", 1, "postgres", false),
+		},
+		Edges: []LineageEdge{stale, edge("a", "b"), current},
+	}
+
+	got := Simplify(g, "root", SimplifyOptions{HideStale: true})
+
+	assert.Equal(t, []string{"root", "c"}, nodeIDs(got))
+	require.Len(t, got.Edges, 1)
+	assert.Equal(t, "c", got.Edges[0].Target)
+	assert.Equal(t, 1, got.Simplification.HiddenStaleEdges)
+	assert.Equal(t, 2, got.Simplification.HiddenNodes)
+}
@@ -4,23 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/mrn"
 )
 
 type Repository interface {
 	GetAssetLineage(ctx context.Context, assetID string, limit int, direction string) (*LineageResponse, error)
+	GetLineageGraph(ctx context.Context, limit int) (*LineageResponse, error)
 	CreateDirectLineage(ctx context.Context, sourceMRN string, targetMRN string, lineageType string) (string, error)
+	BatchCreateDirectLineage(ctx context.Context, results []*BatchEdgeResult, opts BatchOptions) error
 	BatchObservedLineage(ctx context.Context, edges []ObservedEdge) error
 	EdgeExists(ctx context.Context, source, target string) (bool, error)
 	DeleteDirectLineage(ctx context.Context, edgeID string) error
 	GetDirectLineage(ctx context.Context, edgeID string) (*LineageEdge, error)
 	GetImmediateNeighbors(ctx context.Context, assetMRN string, direction string) ([]string, error)
 	StoreRunHistory(ctx context.Context, entry *RunHistoryEntry) error
+	GetEdgeConfirmations(ctx context.Context, checkpointMRNs []string) (map[string]*EdgeConfirmation, error)
+	FindEdges(ctx context.Context, filter PurgeFilter, limit int) ([]LineageEdge, int, error)
+	DeleteEdges(ctx context.Context, filter PurgeFilter) ([]LineageEdge, error)
+	UpsertColumnLineage(ctx context.Context, edges []ColumnLineageEdge) ([]ColumnLineageEdge, error)
+	ReplaceColumnLineage(ctx context.Context, source, target string, edges []ColumnLineageEdge) error
+	GetColumnLineage(ctx context.Context, q ColumnLineageQuery) (*ColumnLineageResponse, error)
+	DeleteColumnLineage(ctx context.Context, id string) error
 }
 
 // ObservedEdge represents a runtime-observed lineage edge — typically emitted by
@@ -34,15 +46,31 @@ type ObservedEdge struct {
 }
 
 type LineageResponse struct {
-	Nodes []LineageNode `json:"nodes"`
-	Edges []LineageEdge `json:"edges"`
+	Nodes          []LineageNode          `json:"nodes"`
+	Edges          []LineageEdge          `json:"edges"`
+	Simplification *SimplificationSummary `json:"simplification,omitempty"`
+	Truncated      bool                   `json:"truncated,omitempty"`
 } // @name LineageResponse
 
 type LineageNode struct {
-	ID    string       `json:"id"`
-	Type  string       `json:"type"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Kind tells renderers whether the node is a dataset, job or service.
+	// Group nodes have none.
+	Kind  asset.Kind   `json:"kind,omitempty"`
 	Asset *asset.Asset `json:"asset"`
 	Depth int          `json:"depth"`
+
+	// Set on group nodes produced by SimplifyOptions.GroupBy.
+	Label   string   `json:"label,omitempty"`
+	Members []string `json:"members,omitempty"`
+
+	// Edges hidden by SimplifyOptions.MaxEdgesPerNode.
+	HiddenUpstream   int `json:"hidden_upstream,omitempty"`
+	HiddenDownstream int `json:"hidden_downstream,omitempty"`
+
+	// Deprecation is set when the asset is deprecated.
+	Deprecation *DeprecationNotice `json:"deprecation,omitempty"`
 } // @name LineageNode
 
 type LineageEdge struct {
@@ -54,6 +82,13 @@ type LineageEdge struct {
 	ObservationCount int        `json:"observation_count,omitempty"`
 	LastSeenAt       *time.Time `json:"last_seen_at,omitempty"`
 	JobMRN           string     `json:"job_mrn,omitempty"`
+	// Freshness is set on edges returned by asset lineage queries.
+	Freshness *EdgeFreshness `json:"freshness,omitempty"`
+	// Via lists the hidden or collapsed assets this edge passes through.
+	Via []string `json:"via,omitempty"`
+	// Columns maps source columns to target columns. When set on an edge
+	// being created it replaces the edge's existing column lineage.
+	Columns []ColumnMapping `json:"columns,omitempty"`
 } // @name LineageEdge
 
 type PostgresRepository struct {
@@ -241,6 +276,179 @@ func (r *PostgresRepository) CreateDirectLineage(ctx context.Context, sourceMRN
 	return edgeID.String(), nil
 }
 
+// BatchCreateDirectLineage creates the edges in results in one transaction,
+// filling in each result's status. Edges are locked in a stable order so
+// concurrent batches touching the same edges serialise instead of inserting
+// duplicates.
+func (r *PostgresRepository) BatchCreateDirectLineage(ctx context.Context, results []*BatchEdgeResult, opts BatchOptions) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	keys := make([]string, len(results))
+	for i, res := range results {
+		keys[i] = res.Edge.Source + "->" + res.Edge.Target
+	}
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	if _, err := tx.Exec(ctx, `
+        SELECT pg_advisory_xact_lock(hashtextextended(k, 0))
+        FROM unnest($1::text[]) AS k`, sorted); err != nil {
+		return fmt.Errorf("locking lineage edges: %w", err)
+	}
+
+	mrns := make([]string, 0, len(results)*2)
+	for _, res := range results {
+		mrns = append(mrns, res.Edge.Source, res.Edge.Target)
+	}
+	known, err := r.existingAssetMRNs(ctx, tx, mrns)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{})
+	rows, err := tx.Query(ctx, `
+        SELECT DISTINCT e.source_mrn || '->' || e.target_mrn
+        FROM lineage_edges e
+        WHERE (e.source_mrn || '->' || e.target_mrn) = ANY($1)`, keys)
+	if err != nil {
+		return fmt.Errorf("checking existing edges: %w", err)
+	}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning existing edge: %w", err)
+		}
+		existing[k] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating existing edges: %w", err)
+	}
+
+	now := time.Now()
+	stubsCreated := 0
+	for i, res := range results {
+		if _, ok := existing[keys[i]]; ok {
+			res.Status = BatchEdgeExisting
+			continue
+		}
+
+		var missing []string
+		for _, m := range []string{res.Edge.Source, res.Edge.Target} {
+			if _, ok := known[m]; !ok {
+				missing = append(missing, m)
+			}
+		}
+		if len(missing) > 0 && !opts.CreateStubs {
+			res.Status = BatchEdgeFailed
+			res.Error = "asset not found: " + strings.Join(missing, ", ")
+			continue
+		}
+		if opts.limitStubs && stubsCreated+len(missing) > opts.maxStubs {
+			res.Status = BatchEdgeFailed
+			res.Error = "stub quota exceeded, asset not found: " + strings.Join(missing, ", ")
+			continue
+		}
+		stubsCreated += len(missing)
+		for _, m := range missing {
+			if err := r.createStubAsset(ctx, tx, m, opts.CreatedBy, now); err != nil {
+				return err
+			}
+			known[m] = struct{}{}
+			res.StubsCreated = append(res.StubsCreated, m)
+		}
+
+		if res.Edge.Type == "" {
+			res.Edge.Type = "DIRECT"
+		}
+
+		eventID := uuid.New()
+		edgeID := uuid.New()
+		eventData, err := json.Marshal(map[string]interface{}{
+			"source": res.Edge.Source,
+			"target": res.Edge.Target,
+			"type":   res.Edge.Type,
+		})
+		if err != nil {
+			return fmt.Errorf("marshaling event data: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO lineage_events (event_id, event_time, event_type, event_data)
+            VALUES ($1, $2, $3, $4)`,
+			eventID, now, "DIRECT", eventData,
+		); err != nil {
+			return fmt.Errorf("inserting lineage event: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO lineage_edges (id, source_mrn, target_mrn, event_id, type, origin)
+            VALUES ($1, $2, $3, $4, $5, 'declared')`,
+			edgeID, res.Edge.Source, res.Edge.Target, eventID, res.Edge.Type,
+		); err != nil {
+			return fmt.Errorf("inserting lineage edge: %w", err)
+		}
+
+		res.Edge.ID = edgeID.String()
+		res.Edge.Origin = "declared"
+		res.Status = BatchEdgeCreated
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) existingAssetMRNs(ctx context.Context, tx pgx.Tx, mrns []string) (map[string]struct{}, error) {
+	rows, err := tx.Query(ctx, `SELECT mrn FROM assets WHERE mrn = ANY($1)`, mrns)
+	if err != nil {
+		return nil, fmt.Errorf("checking asset existence: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]struct{})
+	for rows.Next() {
+		var m string
+		if err := rows.Scan(&m); err != nil {
+			return nil, fmt.Errorf("scanning asset mrn: %w", err)
+		}
+		known[m] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating asset rows: %w", err)
+	}
+	return known, nil
+}
+
+// createStubAsset inserts a placeholder asset for an MRN referenced by
+// lineage before the asset itself has been ingested.
+func (r *PostgresRepository) createStubAsset(ctx context.Context, tx pgx.Tx, assetMRN, createdBy string, now time.Time) error {
+	parsed, err := mrn.Parse(assetMRN)
+	if err != nil {
+		return err
+	}
+	if createdBy == "" {
+		createdBy = "system"
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO assets (id, name, mrn, type, providers, created_by, created_at, updated_at, last_sync_at, is_stub)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $7, TRUE)
+        ON CONFLICT (mrn) DO NOTHING`,
+		uuid.New().String(), parsed.Name, assetMRN, parsed.Type, []string{parsed.Service}, createdBy, now,
+	)
+	if err != nil {
+		return fmt.Errorf("creating stub asset %s: %w", assetMRN, err)
+	}
+	return nil
+}
+
 func (r *PostgresRepository) ensureAssetsExist(ctx context.Context, tx pgx.Tx, sourceMRN, targetMRN string) error {
 	var count int
 	err := tx.QueryRow(ctx, `
@@ -267,14 +475,14 @@ func (r *PostgresRepository) GetAssetLineage(ctx context.Context, assetID string
 	err = tx.QueryRow(ctx, "SELECT mrn FROM assets WHERE id = $1", assetID).Scan(&mrn)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("asset not found: %s", assetID)
+			return nil, fmt.Errorf("%w: %s", asset.ErrAssetNotFound, assetID)
 		}
 		return nil, fmt.Errorf("getting asset mrn: %w", err)
 	}
 
 	nodes, err := r.scanLineageNodes(ctx, tx, `
 	SELECT id, name, mrn, type, providers, description,
-	metadata, schema, sources, tags,
+	metadata, resolve_schema_blobs(schema) AS schema, sources, tags, environments,
 	created_by, created_at, updated_at, last_sync_at, is_stub,
 	0 as depth
 	FROM assets WHERE mrn = $1`, mrn)
@@ -313,6 +521,52 @@ func (r *PostgresRepository) GetAssetLineage(ctx context.Context, assetID string
 	}, nil
 }
 
+// GetLineageGraph returns every asset that takes part in at least one lineage
+// edge, up to limit nodes, along with the edges between them.
+func (r *PostgresRepository) GetLineageGraph(ctx context.Context, limit int) (*LineageResponse, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	nodes, err := r.scanLineageNodes(ctx, tx, `
+	SELECT a.id, a.name, a.mrn, a.type, a.providers, a.description,
+	a.metadata, resolve_schema_blobs(a.schema) AS schema, a.sources, a.tags, a.environments,
+	a.created_by, a.created_at, a.updated_at, a.last_sync_at, a.is_stub,
+	0 as depth
+	FROM assets a
+	WHERE EXISTS (
+		SELECT 1 FROM lineage_edges e
+		WHERE e.source_mrn = a.mrn OR e.target_mrn = a.mrn
+	)
+	ORDER BY a.mrn
+	LIMIT $1`, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := len(nodes) > limit
+	if truncated {
+		nodes = nodes[:limit]
+	}
+
+	edges, err := r.getLineageEdges(ctx, tx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return &LineageResponse{
+		Nodes:     nodes,
+		Edges:     edges,
+		Truncated: truncated,
+	}, nil
+}
+
 func (r *PostgresRepository) getUpstreamNodes(ctx context.Context, tx pgx.Tx, mrn string, limit int) ([]LineageNode, error) {
 	return r.scanLineageNodes(ctx, tx, `
 	WITH RECURSIVE upstream AS (
@@ -337,7 +591,7 @@ func (r *PostgresRepository) getUpstreamNodes(ctx context.Context, tx pgx.Tx, mr
 	CYCLE mrn SET is_cycle USING path
 	SELECT DISTINCT ON (a.mrn)
 		a.id, a.name, a.mrn, a.type, a.providers, a.description,
-		a.metadata, a.schema, a.sources, a.tags,
+		a.metadata, resolve_schema_blobs(a.schema) AS schema, a.sources, a.tags, a.environments,
 		a.created_by, a.created_at, a.updated_at, a.last_sync_at, a.is_stub,
 		u.depth
 	FROM upstream u
@@ -370,7 +624,7 @@ func (r *PostgresRepository) getDownstreamNodes(ctx context.Context, tx pgx.Tx,
 	CYCLE mrn SET is_cycle USING path
 	SELECT DISTINCT ON (a.mrn)
 		a.id, a.name, a.mrn, a.type, a.providers, a.description,
-		a.metadata, a.schema, a.sources, a.tags,
+		a.metadata, resolve_schema_blobs(a.schema) AS schema, a.sources, a.tags, a.environments,
 		a.created_by, a.created_at, a.updated_at, a.last_sync_at, a.is_stub,
 		d.depth
 	FROM downstream d
@@ -462,6 +716,7 @@ func (r *PostgresRepository) scanLineageNodes(ctx context.Context, tx pgx.Tx, qu
 			&a.Schema,
 			&a.Sources,
 			&a.Tags,
+			&a.Environments,
 			&a.CreatedBy,
 			&a.CreatedAt,
 			&a.UpdatedAt,
@@ -480,6 +735,7 @@ func (r *PostgresRepository) scanLineageNodes(ctx context.Context, tx pgx.Tx, qu
 			node.ID = a.ID
 		}
 		node.Type = a.Type
+		node.Kind = a.Kind()
 		node.Asset = &a
 		nodes = append(nodes, node)
 	}
@@ -655,3 +911,188 @@ func (r *PostgresRepository) GetImmediateNeighbors(ctx context.Context, assetMRN
 
 	return mrns, nil
 }
+
+// GetEdgeConfirmations returns, for each checkpoint MRN some pipeline run has
+// reported, the latest such run and how many completed runs of the same
+// pipeline and source have happened since without reporting it.
+func (r *PostgresRepository) GetEdgeConfirmations(ctx context.Context, checkpointMRNs []string) (map[string]*EdgeConfirmation, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT k.mrn, last.created_at, last.pipeline_name,
+			(SELECT COUNT(*) FROM runs r
+			 WHERE r.pipeline_name = last.pipeline_name
+			   AND r.source_name = last.source_name
+			   AND r.status = 'completed'
+			   AND r.started_at > last.started_at)
+		FROM unnest($1::text[]) AS k(mrn)
+		CROSS JOIN LATERAL (
+			SELECT c.created_at, r.pipeline_name, r.source_name, r.started_at
+			FROM run_checkpoints c
+			JOIN runs r ON r.id = c.run_id
+			WHERE c.entity_type = 'lineage'
+			  AND c.entity_mrn = k.mrn
+			  AND c.operation NOT IN ('deleted', 'failed')
+			ORDER BY r.started_at DESC
+			LIMIT 1
+		) last`, checkpointMRNs)
+	if err != nil {
+		return nil, fmt.Errorf("querying edge confirmations: %w", err)
+	}
+	defer rows.Close()
+
+	confirmations := make(map[string]*EdgeConfirmation)
+	for rows.Next() {
+		var key string
+		c := &EdgeConfirmation{}
+		if err := rows.Scan(&key, &c.ConfirmedAt, &c.Pipeline, &c.MissedRuns); err != nil {
+			return nil, fmt.Errorf("scanning edge confirmation: %w", err)
+		}
+		confirmations[key] = c
+	}
+	return confirmations, rows.Err()
+}
+
+// purgeFilterSQL matches the edges of a PurgeFilter bound to $1 (pipeline),
+// $2 (MRN LIKE pattern) and $3 (type), where an empty value matches any edge.
+// The pipeline filter compares against the key CheckpointMRN gives the edge;
+// edges reported without a type are stored as DIRECT but keyed with none.
+const purgeFilterSQL = `
+		($1 = '' OR EXISTS (
+			SELECT 1 FROM run_checkpoints c
+			JOIN runs r ON r.id = c.run_id
+			WHERE r.pipeline_name = $1
+			  AND c.entity_type = 'lineage'
+			  AND c.operation NOT IN ('deleted', 'failed')
+			  AND c.entity_mrn IN (
+				'mrn://lineage/' || lower(e.type) || '/' || lower(translate(e.source_mrn || '->' || e.target_mrn, '/ ', '--')),
+				CASE WHEN e.type = 'DIRECT' THEN
+					'mrn://lineage//' || lower(translate(e.source_mrn || '->' || e.target_mrn, '/ ', '--'))
+				END
+			  )
+		))
+		AND ($2 = '' OR e.source_mrn LIKE $2 OR e.target_mrn LIKE $2)
+		AND ($3 = '' OR upper(e.type) = $3)`
+
+// FindEdges returns up to limit edges matching filter, ordered by source and
+// target, and the total number of matching edges.
+func (r *PostgresRepository) FindEdges(ctx context.Context, filter PurgeFilter, limit int) ([]LineageEdge, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM lineage_edges e WHERE`+purgeFilterSQL,
+		filter.Pipeline, filter.MRNLike, filter.Type,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting matching edges: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT e.id, e.source_mrn, e.target_mrn, e.job_mrn, COALESCE(e.type, 'DIRECT'),
+			e.origin, e.observation_count, e.last_seen_at
+		FROM lineage_edges e
+		WHERE`+purgeFilterSQL+`
+		ORDER BY e.source_mrn, e.target_mrn
+		LIMIT $4`,
+		filter.Pipeline, filter.MRNLike, filter.Type, limit,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("querying matching edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []LineageEdge{}
+	for rows.Next() {
+		var edge LineageEdge
+		var jobMRN *string
+		if err := rows.Scan(&edge.ID, &edge.Source, &edge.Target, &jobMRN, &edge.Type, &edge.Origin, &edge.ObservationCount, &edge.LastSeenAt); err != nil {
+			return nil, 0, fmt.Errorf("scanning edge: %w", err)
+		}
+		if jobMRN != nil {
+			edge.JobMRN = *jobMRN
+		}
+		edges = append(edges, edge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating edges: %w", err)
+	}
+
+	return edges, total, nil
+}
+
+// DeleteEdges deletes every edge matching filter in one transaction and
+// returns them ordered by source and target. Events no longer referenced by
+// any edge are deleted with them, as are the edges' run checkpoints so a
+// pipeline that still reports an edge recreates it on its next run.
+func (r *PostgresRepository) DeleteEdges(ctx context.Context, filter PurgeFilter) ([]LineageEdge, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		DELETE FROM lineage_edges e
+		WHERE`+purgeFilterSQL+`
+		RETURNING e.id, e.source_mrn, e.target_mrn, e.job_mrn, COALESCE(e.type, 'DIRECT'),
+			e.origin, e.observation_count, e.last_seen_at, e.event_id`,
+		filter.Pipeline, filter.MRNLike, filter.Type,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deleting edges: %w", err)
+	}
+
+	edges := []LineageEdge{}
+	var eventIDs []uuid.UUID
+	var checkpoints []string
+	for rows.Next() {
+		var edge LineageEdge
+		var jobMRN *string
+		var eventID uuid.UUID
+		if err := rows.Scan(&edge.ID, &edge.Source, &edge.Target, &jobMRN, &edge.Type, &edge.Origin, &edge.ObservationCount, &edge.LastSeenAt, &eventID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning deleted edge: %w", err)
+		}
+		if jobMRN != nil {
+			edge.JobMRN = *jobMRN
+		}
+		edges = append(edges, edge)
+		eventIDs = append(eventIDs, eventID)
+		checkpoints = append(checkpoints, CheckpointMRN(edge.Source, edge.Target, edge.Type))
+		if edge.Type == "DIRECT" {
+			checkpoints = append(checkpoints, CheckpointMRN(edge.Source, edge.Target, ""))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating deleted edges: %w", err)
+	}
+
+	if len(edges) == 0 {
+		return edges, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM lineage_events ev
+		WHERE ev.event_id = ANY($1)
+		  AND NOT EXISTS (SELECT 1 FROM lineage_edges e WHERE e.event_id = ev.event_id)`,
+		eventIDs,
+	); err != nil {
+		return nil, fmt.Errorf("deleting events: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM run_checkpoints
+		WHERE entity_type = 'lineage' AND entity_mrn = ANY($1)`,
+		checkpoints,
+	); err != nil {
+		return nil, fmt.Errorf("deleting checkpoints: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+	return edges, nil
+}
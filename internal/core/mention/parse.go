@@ -0,0 +1,82 @@
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+const maxNameLength = 100
+
+var (
+	// fencedCode and inlineCode are stripped before parsing so code samples
+	// such as decorators or shell comments are not read as mentions.
+	fencedCode = regexp.MustCompile("(?s)```.*?```|~~~.*?~~~")
+	inlineCode = regexp.MustCompile("`[^`\n]*`")
+
+	// linkRef is the form the editor inserts when a suggestion is picked:
+	// [@Jane Doe](mention:user:<id>) or [#Revenue](mention:term:<id>).
+	linkRef = regexp.MustCompile(`\[[@#]([^\]\n]+)\]\(mention:(user|team|term):([^)\s]+)\)`)
+
+	// bracketRef allows names with spaces: @[Data Platform] or #[Net Revenue].
+	bracketRef = regexp.MustCompile(`(^|[^\w\]])([@#])\[([^\]\n]+)\]`)
+
+	// bareRef matches @handle and #term. The sigil must not follow a word
+	// character, so e-mail addresses and URL fragments are ignored, and term
+	// names must start with a letter, so issue numbers like #12 are too.
+	bareRef = regexp.MustCompile(`(^|[^\w/&])(@[A-Za-z0-9][\w.-]*|#[A-Za-z][\w.-]*)`)
+)
+
+// Ref is a mention as written in the text, before it is resolved.
+type Ref struct {
+	// Sigil is '@' for users and teams and '#' for glossary terms.
+	Sigil byte
+	// Name is the handle or name after the sigil.
+	Name string
+	// Type and ID are set when the editor already resolved the mention.
+	Type TargetType
+	ID   string
+}
+
+// Parse returns the mentions in text in order of appearance, without
+// duplicates. Mentions inside code blocks and inline code are ignored.
+func Parse(text string) []Ref {
+	text = fencedCode.ReplaceAllString(text, " ")
+	text = inlineCode.ReplaceAllString(text, " ")
+
+	var refs []Ref
+	seen := make(map[string]bool)
+	add := func(ref Ref) {
+		ref.Name = strings.TrimSpace(ref.Name)
+		if ref.Name == "" || len(ref.Name) > maxNameLength {
+			return
+		}
+		key := string(ref.Sigil) + string(ref.Type) + ":" + strings.ToLower(ref.ID+ref.Name)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		refs = append(refs, ref)
+	}
+
+	for _, m := range linkRef.FindAllStringSubmatch(text, -1) {
+		sigil := byte('@')
+		if TargetType(m[2]) == TargetTerm {
+			sigil = '#'
+		}
+		add(Ref{Sigil: sigil, Name: m[1], Type: TargetType(m[2]), ID: m[3]})
+	}
+	text = linkRef.ReplaceAllString(text, " ")
+
+	for _, m := range bracketRef.FindAllStringSubmatch(text, -1) {
+		add(Ref{Sigil: m[2][0], Name: m[3]})
+	}
+	text = bracketRef.ReplaceAllString(text, "$1 ")
+
+	for _, m := range bareRef.FindAllStringSubmatch(text, -1) {
+		// Trailing punctuation ends a sentence rather than the name.
+		name := strings.TrimRight(m[2][1:], ".-")
+		add(Ref{Sigil: m[2][0], Name: name})
+	}
+
+	return refs
+}
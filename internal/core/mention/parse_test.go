@@ -0,0 +1,31 @@
+package mention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	refs := Parse("Ask @jane or @[Data Platform] about #revenue and #[Net Revenue]. Thanks @jane.")
+	assert.Equal(t, []Ref{
+		{Sigil: '@', Name: "Data Platform"},
+		{Sigil: '#', Name: "Net Revenue"},
+		{Sigil: '@', Name: "jane"},
+		{Sigil: '#', Name: "revenue"},
+	}, refs)
+}
+
+func TestParseEditorLinks(t *testing.T) {
+	refs := Parse("cc [@Jane Doe](mention:user:1234) and [#Churn](mention:term:abcd)")
+	assert.Equal(t, []Ref{
+		{Sigil: '@', Name: "Jane Doe", Type: TargetUser, ID: "1234"},
+		{Sigil: '#', Name: "Churn", Type: TargetTerm, ID: "abcd"},
+	}, refs)
+}
+
+func TestParseIgnoresNonMentions(t *testing.T) {
+	text := "Mail jane@example.com, see issue #12 and https://example.com/page#section.\n" +
+		"Run `grep @jane` or:\n```\n@decorator\n# comment\n```"
+	assert.Empty(t, Parse(text))
+}
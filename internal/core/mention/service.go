@@ -0,0 +1,229 @@
+package mention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrTargetNotFound = errors.New("mention target not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// SourceType is the kind of text a mention was written in.
+type SourceType string // @name MentionSourceType
+
+const (
+	SourceAssetDescription SourceType = "asset_description"
+	SourceQuestion         SourceType = "question"
+	SourceAnswer           SourceType = "answer"
+)
+
+// Valid reports whether t is a known source type.
+func (t SourceType) Valid() bool {
+	switch t {
+	case SourceAssetDescription, SourceQuestion, SourceAnswer:
+		return true
+	}
+	return false
+}
+
+// TargetType is the kind of entity a mention refers to.
+type TargetType string // @name MentionTargetType
+
+const (
+	TargetUser TargetType = "user"
+	TargetTeam TargetType = "team"
+	TargetTerm TargetType = "term"
+)
+
+// Valid reports whether t is a known target type.
+func (t TargetType) Valid() bool {
+	switch t {
+	case TargetUser, TargetTeam, TargetTerm:
+		return true
+	}
+	return false
+}
+
+// Source identifies the text mentions were parsed from. AssetID is the asset
+// the text belongs to, so mentions are removed along with the asset.
+type Source struct {
+	Type    SourceType
+	ID      string
+	AssetID string
+}
+
+// Target is a resolved mention.
+type Target struct {
+	Type TargetType `json:"type"`
+	ID   string     `json:"id"`
+	// Label is the target's current name, read at query time so renamed
+	// users, teams and terms are shown by their new name.
+	Label string `json:"label"`
+}
+
+// Mention is a stored reference from a piece of text to a user, team or
+// glossary term.
+type Mention struct {
+	ID         string     `json:"id"`
+	SourceType SourceType `json:"source_type"`
+	SourceID   string     `json:"source_id"`
+	AssetID    string     `json:"asset_id,omitempty"`
+	AssetMRN   string     `json:"asset_mrn,omitempty"`
+	AssetName  string     `json:"asset_name,omitempty"`
+	Target     Target     `json:"target"`
+	CreatedBy  *string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+} // @name Mention
+
+type ListResult struct {
+	Mentions []*Mention `json:"mentions"`
+	Total    int        `json:"total"`
+} // @name MentionListResult
+
+// Notifier is told about users and teams newly mentioned in a source.
+type Notifier interface {
+	NotifyMentioned(ctx context.Context, source Source, target Target, actorID string)
+}
+
+type Service struct {
+	repo     Repository
+	notifier Notifier
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// SetNotifier enables notifications for newly mentioned users and teams.
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// Sync parses text and replaces the mentions stored for source with the
+// ones that resolve. Users and teams that were not mentioned in the source
+// before are notified, except the actor themselves. Names that match no
+// user, team or term are ignored, since an @ or # is often just text.
+func (s *Service) Sync(ctx context.Context, source Source, text, actorID string) ([]Target, error) {
+	if !source.Type.Valid() || source.ID == "" {
+		return nil, &ValidationError{Message: "invalid mention source"}
+	}
+
+	targets := s.Resolve(ctx, Parse(text))
+
+	added, err := s.repo.Replace(ctx, source, targets, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("storing mentions: %w", err)
+	}
+
+	if s.notifier != nil {
+		for _, t := range added {
+			if t.Type == TargetTerm || (t.Type == TargetUser && t.ID == actorID) {
+				continue
+			}
+			s.notifier.NotifyMentioned(ctx, source, t, actorID)
+		}
+	}
+
+	return targets, nil
+}
+
+// Clear removes every mention stored for a source.
+func (s *Service) Clear(ctx context.Context, sourceType SourceType, sourceID string) error {
+	return s.repo.DeleteSource(ctx, sourceType, sourceID)
+}
+
+// TrackMentions syncs the mentions in a piece of text, logging failures
+// rather than returning them so callers don't fail the edit itself.
+func (s *Service) TrackMentions(ctx context.Context, sourceType, sourceID, assetID, text, actorID string) {
+	source := Source{Type: SourceType(sourceType), ID: sourceID, AssetID: assetID}
+	if _, err := s.Sync(ctx, source, text, actorID); err != nil {
+		log.Warn().Err(err).Str("source_type", sourceType).Str("source_id", sourceID).Msg("Failed to sync mentions")
+	}
+}
+
+// ForgetMentions clears a source's mentions, logging failures.
+func (s *Service) ForgetMentions(ctx context.Context, sourceType, sourceID string) {
+	if err := s.Clear(ctx, SourceType(sourceType), sourceID); err != nil {
+		log.Warn().Err(err).Str("source_type", sourceType).Str("source_id", sourceID).Msg("Failed to clear mentions")
+	}
+}
+
+// Resolve looks up the entities refs refer to. @names are matched against
+// usernames, then user and team names; #names against glossary term names.
+// Names are compared case-insensitively with spaces, hyphens and
+// underscores treated alike, so @data-platform finds the team
+// "Data Platform".
+func (s *Service) Resolve(ctx context.Context, refs []Ref) []Target {
+	var targets []Target
+	seen := make(map[string]bool)
+
+	for _, ref := range refs {
+		t, err := s.resolve(ctx, ref)
+		if err != nil {
+			if !errors.Is(err, ErrTargetNotFound) {
+				log.Warn().Err(err).Str("name", ref.Name).Msg("Failed to resolve mention")
+			}
+			continue
+		}
+		key := string(t.Type) + ":" + t.ID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, *t)
+	}
+
+	return targets
+}
+
+func (s *Service) resolve(ctx context.Context, ref Ref) (*Target, error) {
+	if ref.ID != "" {
+		return s.repo.GetTarget(ctx, ref.Type, ref.ID)
+	}
+
+	if ref.Sigil == '#' {
+		return s.repo.FindTarget(ctx, TargetTerm, ref.Name)
+	}
+
+	t, err := s.repo.FindTarget(ctx, TargetUser, ref.Name)
+	if errors.Is(err, ErrTargetNotFound) {
+		return s.repo.FindTarget(ctx, TargetTeam, ref.Name)
+	}
+	return t, err
+}
+
+// ListForSource returns the mentions stored for a source.
+func (s *Service) ListForSource(ctx context.Context, sourceType SourceType, sourceID string) ([]*Mention, error) {
+	if !sourceType.Valid() || sourceID == "" {
+		return nil, &ValidationError{Message: "source_type and source_id are required"}
+	}
+	return s.repo.ListBySource(ctx, sourceType, sourceID)
+}
+
+// ListForTarget returns where a user, team or term is mentioned, newest first.
+func (s *Service) ListForTarget(ctx context.Context, targetType TargetType, targetID string, limit, offset int) (*ListResult, error) {
+	if !targetType.Valid() || targetID == "" {
+		return nil, &ValidationError{Message: "target_type and target_id are required"}
+	}
+	mentions, total, err := s.repo.ListByTarget(ctx, targetType, targetID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Mentions: mentions, Total: total}, nil
+}
@@ -0,0 +1,123 @@
+package mention
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	targets map[TargetType][]Target
+	stored  map[string][]Target
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		targets: map[TargetType][]Target{
+			TargetUser: {{Type: TargetUser, ID: "u1", Label: "Jane Doe"}, {Type: TargetUser, ID: "u2", Label: "Sam"}},
+			TargetTeam: {{Type: TargetTeam, ID: "t1", Label: "Data Platform"}},
+			TargetTerm: {{Type: TargetTerm, ID: "g1", Label: "Revenue"}},
+		},
+		stored: make(map[string][]Target),
+	}
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "-", "_", "-").Replace(s))
+}
+
+func (f *fakeRepo) FindTarget(_ context.Context, targetType TargetType, name string) (*Target, error) {
+	for _, t := range f.targets[targetType] {
+		if normalize(t.Label) == normalize(name) || strings.EqualFold(t.ID, name) {
+			return &t, nil
+		}
+	}
+	return nil, ErrTargetNotFound
+}
+
+func (f *fakeRepo) GetTarget(_ context.Context, targetType TargetType, id string) (*Target, error) {
+	for _, t := range f.targets[targetType] {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, ErrTargetNotFound
+}
+
+func (f *fakeRepo) Replace(_ context.Context, source Source, targets []Target, _ string) ([]Target, error) {
+	key := string(source.Type) + ":" + source.ID
+	var added []Target
+	for _, t := range targets {
+		if !containsTarget(f.stored[key], t) {
+			added = append(added, t)
+		}
+	}
+	f.stored[key] = targets
+	return added, nil
+}
+
+func containsTarget(ts []Target, t Target) bool {
+	for _, x := range ts {
+		if x.Type == t.Type && x.ID == t.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRepo) DeleteSource(_ context.Context, sourceType SourceType, sourceID string) error {
+	delete(f.stored, string(sourceType)+":"+sourceID)
+	return nil
+}
+
+func (f *fakeRepo) ListBySource(context.Context, SourceType, string) ([]*Mention, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) ListByTarget(context.Context, TargetType, string, int, int) ([]*Mention, int, error) {
+	return nil, 0, nil
+}
+
+type recordingNotifier struct {
+	notified []Target
+}
+
+func (n *recordingNotifier) NotifyMentioned(_ context.Context, _ Source, target Target, _ string) {
+	n.notified = append(n.notified, target)
+}
+
+func TestSyncResolvesAndNotifiesNewMentions(t *testing.T) {
+	repo := newFakeRepo()
+	notifier := &recordingNotifier{}
+	svc := NewService(repo)
+	svc.SetNotifier(notifier)
+	ctx := context.Background()
+	source := Source{Type: SourceAssetDescription, ID: "asset-1", AssetID: "asset-1"}
+
+	targets, err := svc.Sync(ctx, source, "Owned by @data-platform, ask @nobody about #revenue. @sam", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, []Target{
+		{Type: TargetTeam, ID: "t1", Label: "Data Platform"},
+		{Type: TargetTerm, ID: "g1", Label: "Revenue"},
+		{Type: TargetUser, ID: "u2", Label: "Sam"},
+	}, targets)
+
+	// Terms and the author mentioning themselves are not notified.
+	assert.Equal(t, []Target{{Type: TargetTeam, ID: "t1", Label: "Data Platform"}}, notifier.notified)
+
+	// Editing the text again only notifies parties that were not mentioned before.
+	notifier.notified = nil
+	_, err = svc.Sync(ctx, source, "Ask @[Jane Doe] or [@Sam](mention:user:u2)", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Type: TargetUser, ID: "u1", Label: "Jane Doe"}}, notifier.notified)
+	assert.Len(t, repo.stored["asset_description:asset-1"], 2)
+}
+
+func TestSyncRejectsUnknownSource(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	_, err := svc.Sync(context.Background(), Source{Type: "page", ID: "1"}, "@jane", "")
+	assert.True(t, IsValidationError(err))
+}
@@ -0,0 +1,219 @@
+package mention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the mention data access interface.
+type Repository interface {
+	// FindTarget looks up a user, team or term by name.
+	FindTarget(ctx context.Context, targetType TargetType, name string) (*Target, error)
+	GetTarget(ctx context.Context, targetType TargetType, id string) (*Target, error)
+	// Replace stores targets as the mentions of source, removing any others,
+	// and returns the targets that were not mentioned before.
+	Replace(ctx context.Context, source Source, targets []Target, createdBy string) ([]Target, error)
+	DeleteSource(ctx context.Context, sourceType SourceType, sourceID string) error
+	ListBySource(ctx context.Context, sourceType SourceType, sourceID string) ([]*Mention, error)
+	ListByTarget(ctx context.Context, targetType TargetType, targetID string, limit, offset int) ([]*Mention, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// normalizedName folds case and treats runs of spaces, hyphens and
+// underscores as one separator, matching Parse's handles against names.
+const normalizedName = `lower(regexp_replace(%s, '[\s_-]+', '-', 'g'))`
+
+var findTarget = map[TargetType]string{
+	TargetUser: `
+		SELECT id::text, name FROM users
+		WHERE active AND (lower(username) = lower($1) OR ` + fmt.Sprintf(normalizedName, "name") + ` = ` + fmt.Sprintf(normalizedName, "$1") + `)
+		ORDER BY lower(username) = lower($1) DESC, name
+		LIMIT 1`,
+	TargetTeam: `
+		SELECT id::text, name FROM teams
+		WHERE ` + fmt.Sprintf(normalizedName, "name") + ` = ` + fmt.Sprintf(normalizedName, "$1") + `
+		ORDER BY lower(name) = lower($1) DESC, name
+		LIMIT 1`,
+	TargetTerm: `
+		SELECT id::text, name FROM glossary_terms
+		WHERE deleted_at IS NULL AND ` + fmt.Sprintf(normalizedName, "name") + ` = ` + fmt.Sprintf(normalizedName, "$1") + `
+		ORDER BY lower(name) = lower($1) DESC, name
+		LIMIT 1`,
+}
+
+var getTarget = map[TargetType]string{
+	TargetUser: `SELECT id::text, name FROM users WHERE id::text = $1 AND active`,
+	TargetTeam: `SELECT id::text, name FROM teams WHERE id::text = $1`,
+	TargetTerm: `SELECT id::text, name FROM glossary_terms WHERE id::text = $1 AND deleted_at IS NULL`,
+}
+
+func (r *PostgresRepository) FindTarget(ctx context.Context, targetType TargetType, name string) (*Target, error) {
+	query, ok := findTarget[targetType]
+	if !ok {
+		return nil, ErrTargetNotFound
+	}
+	return r.scanTarget(ctx, targetType, query, name)
+}
+
+func (r *PostgresRepository) GetTarget(ctx context.Context, targetType TargetType, id string) (*Target, error) {
+	query, ok := getTarget[targetType]
+	if !ok {
+		return nil, ErrTargetNotFound
+	}
+	return r.scanTarget(ctx, targetType, query, id)
+}
+
+func (r *PostgresRepository) scanTarget(ctx context.Context, targetType TargetType, query, arg string) (*Target, error) {
+	t := &Target{Type: targetType}
+	if err := r.db.QueryRow(ctx, query, arg).Scan(&t.ID, &t.Label); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTargetNotFound
+		}
+		return nil, fmt.Errorf("finding %s: %w", targetType, err)
+	}
+	return t, nil
+}
+
+func (r *PostgresRepository) Replace(ctx context.Context, source Source, targets []Target, createdBy string) ([]Target, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	types := make([]string, len(targets))
+	ids := make([]string, len(targets))
+	for i, t := range targets {
+		types[i] = string(t.Type)
+		ids[i] = t.ID
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM mentions m
+		WHERE m.source_type = $1 AND m.source_id = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM unnest($3::text[], $4::text[]) AS t(target_type, target_id)
+			WHERE t.target_type = m.target_type AND t.target_id = m.target_id::text
+		  )`, source.Type, source.ID, types, ids)
+	if err != nil {
+		return nil, fmt.Errorf("deleting mentions: %w", err)
+	}
+
+	var assetID, actor *string
+	if source.AssetID != "" {
+		assetID = &source.AssetID
+	}
+	if createdBy != "" {
+		actor = &createdBy
+	}
+
+	var added []Target
+	for _, t := range targets {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO mentions (source_type, source_id, asset_id, target_type, target_id, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (source_type, source_id, target_type, target_id) DO NOTHING`,
+			source.Type, source.ID, assetID, t.Type, t.ID, actor)
+		if err != nil {
+			return nil, fmt.Errorf("inserting mention: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			added = append(added, t)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return added, nil
+}
+
+func (r *PostgresRepository) DeleteSource(ctx context.Context, sourceType SourceType, sourceID string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM mentions WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID)
+	if err != nil {
+		return fmt.Errorf("deleting mentions: %w", err)
+	}
+	return nil
+}
+
+// selectMention joins each mention to its target's current name. Mentions of
+// deleted users, teams and terms have no name and are left out.
+const selectMention = `
+	SELECT m.id, m.source_type, m.source_id, COALESCE(m.asset_id, ''),
+	       COALESCE(a.mrn, ''), COALESCE(a.name, ''),
+	       m.target_type, m.target_id::text, t.label,
+	       m.created_by::text, m.created_at
+	FROM mentions m
+	LEFT JOIN assets a ON a.id = m.asset_id
+	JOIN LATERAL (
+		SELECT u.name AS label FROM users u WHERE m.target_type = 'user' AND u.id = m.target_id
+		UNION ALL
+		SELECT tm.name FROM teams tm WHERE m.target_type = 'team' AND tm.id = m.target_id
+		UNION ALL
+		SELECT g.name FROM glossary_terms g WHERE m.target_type = 'term' AND g.id = m.target_id AND g.deleted_at IS NULL
+	) t ON TRUE`
+
+func (r *PostgresRepository) ListBySource(ctx context.Context, sourceType SourceType, sourceID string) ([]*Mention, error) {
+	rows, err := r.db.Query(ctx, selectMention+`
+		WHERE m.source_type = $1 AND m.source_id = $2
+		ORDER BY m.target_type, t.label`, sourceType, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("listing mentions: %w", err)
+	}
+	return scanMentions(rows)
+}
+
+func (r *PostgresRepository) ListByTarget(ctx context.Context, targetType TargetType, targetID string, limit, offset int) ([]*Mention, int, error) {
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM mentions WHERE target_type = $1 AND target_id::text = $2`,
+		targetType, targetID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting mentions: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectMention+`
+		WHERE m.target_type = $1 AND m.target_id::text = $2
+		ORDER BY m.created_at DESC
+		LIMIT $3 OFFSET $4`, targetType, targetID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing mentions: %w", err)
+	}
+	mentions, err := scanMentions(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return mentions, total, nil
+}
+
+func scanMentions(rows pgx.Rows) ([]*Mention, error) {
+	defer rows.Close()
+
+	mentions := []*Mention{}
+	for rows.Next() {
+		m := &Mention{}
+		err := rows.Scan(&m.ID, &m.SourceType, &m.SourceID, &m.AssetID,
+			&m.AssetMRN, &m.AssetName,
+			&m.Target.Type, &m.Target.ID, &m.Target.Label,
+			&m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning mention: %w", err)
+		}
+		mentions = append(mentions, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating mentions: %w", err)
+	}
+	return mentions, nil
+}
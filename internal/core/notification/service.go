@@ -28,6 +28,12 @@ const (
 	TypeDownstreamSchemaChange = "downstream_schema_change"
 	TypeLineageChange          = "lineage_change"
 	TypeAssetDeleted           = "asset_deleted"
+	TypeAssetArchival          = "asset_archival"
+	TypeAssetQuestion          = "asset_question"
+	TypeSavedSearchMatch       = "saved_search_match"
+	TypeOwnershipRequest       = "ownership_request"
+	TypeQuota                  = "quota"
+	TypeOwnershipVacancy       = "ownership_vacancy"
 )
 
 const (
@@ -0,0 +1,275 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrUnknownStep = errors.New("unknown onboarding step")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Step statuses. A step starts pending and becomes completed once Marmot sees
+// the work done, or skipped when a user chooses not to do it. Completion is
+// recorded the first time it is seen, so deleting the only data product
+// later does not put the checklist back.
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusSkipped   = "skipped"
+)
+
+// Steps of the guided catalog setup, in order.
+const (
+	StepConnectSource     = "connect_source"
+	StepFirstIngestion    = "run_first_ingestion"
+	StepAssignOwners      = "assign_owners"
+	StepCreateDataProduct = "create_data_product"
+)
+
+// Step describes an onboarding step.
+type Step struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Link is the UI route where the step is done.
+	Link string `json:"link"`
+}
+
+// Steps is the ordered onboarding checklist.
+var Steps = []Step{
+	{
+		ID:          StepConnectSource,
+		Title:       "Connect a source",
+		Description: "Create an ingestion pipeline for a database, warehouse or other system.",
+		Link:        "/pipelines/new",
+	},
+	{
+		ID:          StepFirstIngestion,
+		Title:       "Run your first ingestion",
+		Description: "Run a pipeline to completion to populate the catalog.",
+		Link:        "/runs",
+	},
+	{
+		ID:          StepAssignOwners,
+		Title:       "Assign owners",
+		Description: "Give an asset an owning user or team so people know who to ask.",
+		Link:        "/discover",
+	},
+	{
+		ID:          StepCreateDataProduct,
+		Title:       "Create a data product",
+		Description: "Group related assets into a data product for consumers.",
+		Link:        "/products/new",
+	},
+}
+
+func findStep(id string) bool {
+	for _, s := range Steps {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Scopes for recorded progress. Deployment progress is shared by every
+// user; user progress only changes the checklist for that user.
+const (
+	ScopeDeployment = "deployment"
+	ScopeUser       = "user"
+)
+
+// Record is stored progress for a step. UserID is nil for deployment progress.
+type Record struct {
+	Step      string
+	Status    string
+	UserID    *string
+	UpdatedBy *string
+	UpdatedAt time.Time
+}
+
+// StepState is a step with its current status for a user.
+type StepState struct {
+	Step
+	Status    string     `json:"status"`
+	Scope     string     `json:"scope,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+} // @name OnboardingStep
+
+// Progress is the onboarding checklist as seen by a user.
+type Progress struct {
+	Steps []StepState `json:"steps"`
+	// CurrentStep is the first pending step, empty once every step is done.
+	CurrentStep string `json:"current_step,omitempty"`
+	Completed   int    `json:"completed"`
+	Total       int    `json:"total"`
+	Finished    bool   `json:"finished"`
+	Dismissed   bool   `json:"dismissed"`
+} // @name OnboardingProgress
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// GetProgress returns the checklist for userID. Pending steps are checked
+// against the catalog first and recorded for the deployment when done.
+func (s *Service) GetProgress(ctx context.Context, userID string) (*Progress, error) {
+	records, err := s.repo.ListProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := map[string]*Record{}
+	user := map[string]*Record{}
+	for _, r := range records {
+		if r.UserID == nil {
+			deployment[r.Step] = r
+		} else {
+			user[r.Step] = r
+		}
+	}
+
+	for _, step := range Steps {
+		if r, ok := deployment[step.ID]; ok && r.Status == StatusCompleted {
+			continue
+		}
+		done, err := s.repo.Detect(ctx, step.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("step", step.ID).Msg("Failed to detect onboarding step")
+			continue
+		}
+		if !done {
+			continue
+		}
+		rec := &Record{Step: step.ID, Status: StatusCompleted}
+		if err := s.repo.SetProgress(ctx, rec); err != nil {
+			return nil, err
+		}
+		deployment[step.ID] = rec
+	}
+
+	progress := &Progress{Total: len(Steps)}
+	for _, step := range Steps {
+		state := StepState{Step: step, Status: StatusPending}
+		// Completion always wins. Otherwise a user's own skip overrides a
+		// deployment-wide one, which they may want to undo for themselves.
+		switch r := pick(deployment[step.ID], user[step.ID]); {
+		case r == nil:
+		case r.UserID == nil:
+			state.Status, state.Scope, state.UpdatedAt = r.Status, ScopeDeployment, &r.UpdatedAt
+		default:
+			state.Status, state.Scope, state.UpdatedAt = r.Status, ScopeUser, &r.UpdatedAt
+		}
+
+		if state.Status == StatusPending {
+			if progress.CurrentStep == "" {
+				progress.CurrentStep = step.ID
+			}
+		} else {
+			progress.Completed++
+		}
+		progress.Steps = append(progress.Steps, state)
+	}
+	progress.Finished = progress.CurrentStep == ""
+
+	progress.Dismissed, err = s.repo.IsDismissed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func pick(deployment, user *Record) *Record {
+	if deployment != nil && deployment.Status == StatusCompleted {
+		return deployment
+	}
+	if user != nil {
+		return user
+	}
+	return deployment
+}
+
+// Skip marks a step skipped for the user or, with ScopeDeployment, for
+// everyone. Steps are worked through in order, so only the current step can
+// be skipped.
+func (s *Service) Skip(ctx context.Context, stepID, scope, userID string) (*Progress, error) {
+	if err := s.checkStep(stepID, scope); err != nil {
+		return nil, err
+	}
+
+	progress, err := s.GetProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if progress.CurrentStep != stepID {
+		return nil, &ValidationError{Message: fmt.Sprintf("only the current step can be skipped, currently %q", progress.CurrentStep)}
+	}
+
+	rec := &Record{Step: stepID, Status: StatusSkipped, UpdatedBy: &userID}
+	if scope == ScopeUser {
+		rec.UserID = &userID
+	}
+	if err := s.repo.SetProgress(ctx, rec); err != nil {
+		return nil, err
+	}
+
+	return s.GetProgress(ctx, userID)
+}
+
+// Reset returns a skipped or completed step to pending. A reset completed
+// step is detected as completed again on the next read if the work is still
+// there.
+func (s *Service) Reset(ctx context.Context, stepID, scope, userID string) (*Progress, error) {
+	if err := s.checkStep(stepID, scope); err != nil {
+		return nil, err
+	}
+
+	var owner *string
+	if scope == ScopeUser {
+		owner = &userID
+	}
+	if err := s.repo.DeleteProgress(ctx, stepID, owner); err != nil {
+		return nil, err
+	}
+
+	return s.GetProgress(ctx, userID)
+}
+
+// SetDismissed hides or shows the checklist for a user.
+func (s *Service) SetDismissed(ctx context.Context, userID string, dismissed bool) (*Progress, error) {
+	if err := s.repo.SetDismissed(ctx, userID, dismissed); err != nil {
+		return nil, err
+	}
+	return s.GetProgress(ctx, userID)
+}
+
+func (s *Service) checkStep(stepID, scope string) error {
+	if !findStep(stepID) {
+		return ErrUnknownStep
+	}
+	if scope != ScopeUser && scope != ScopeDeployment {
+		return &ValidationError{Message: fmt.Sprintf("scope must be %q or %q", ScopeUser, ScopeDeployment)}
+	}
+	return nil
+}
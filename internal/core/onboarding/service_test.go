@@ -0,0 +1,126 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	records   []*Record
+	detected  map[string]bool
+	dismissed map[string]bool
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{detected: map[string]bool{}, dismissed: map[string]bool{}}
+}
+
+func (f *fakeRepo) ListProgress(ctx context.Context, userID string) ([]*Record, error) {
+	var out []*Record
+	for _, r := range f.records {
+		if r.UserID == nil || *r.UserID == userID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) SetProgress(ctx context.Context, rec *Record) error {
+	_ = f.DeleteProgress(ctx, rec.Step, rec.UserID)
+	f.records = append(f.records, rec)
+	return nil
+}
+
+func (f *fakeRepo) DeleteProgress(ctx context.Context, step string, userID *string) error {
+	kept := f.records[:0]
+	for _, r := range f.records {
+		sameOwner := (r.UserID == nil && userID == nil) || (r.UserID != nil && userID != nil && *r.UserID == *userID)
+		if !(r.Step == step && sameOwner) {
+			kept = append(kept, r)
+		}
+	}
+	f.records = kept
+	return nil
+}
+
+func (f *fakeRepo) Detect(ctx context.Context, step string) (bool, error) {
+	return f.detected[step], nil
+}
+
+func (f *fakeRepo) IsDismissed(ctx context.Context, userID string) (bool, error) {
+	return f.dismissed[userID], nil
+}
+
+func (f *fakeRepo) SetDismissed(ctx context.Context, userID string, dismissed bool) error {
+	f.dismissed[userID] = dismissed
+	return nil
+}
+
+func statuses(p *Progress) []string {
+	out := make([]string, len(p.Steps))
+	for i, s := range p.Steps {
+		out[i] = s.Status
+	}
+	return out
+}
+
+func TestGetProgress_DetectsAndRemembersCompletion(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	p, err := svc.GetProgress(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, StepConnectSource, p.CurrentStep)
+	assert.Equal(t, 0, p.Completed)
+
+	repo.detected[StepConnectSource] = true
+	repo.detected[StepAssignOwners] = true
+	p, err = svc.GetProgress(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{StatusCompleted, StatusPending, StatusCompleted, StatusPending}, statuses(p))
+	assert.Equal(t, StepFirstIngestion, p.CurrentStep)
+
+	repo.detected = map[string]bool{}
+	p, err = svc.GetProgress(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Completed, "completion is shared and kept once seen")
+}
+
+func TestSkip_OnlyCurrentStepAndScoped(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	_, err := svc.Skip(ctx, StepAssignOwners, ScopeUser, "alice")
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.Skip(ctx, "nope", ScopeUser, "alice")
+	assert.ErrorIs(t, err, ErrUnknownStep)
+
+	p, err := svc.Skip(ctx, StepConnectSource, ScopeUser, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, StepFirstIngestion, p.CurrentStep)
+	assert.Equal(t, ScopeUser, p.Steps[0].Scope)
+
+	p, err = svc.GetProgress(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, StepConnectSource, p.CurrentStep, "user skips do not affect others")
+
+	_, err = svc.Skip(ctx, StepConnectSource, ScopeDeployment, "bob")
+	require.NoError(t, err)
+	p, err = svc.Reset(ctx, StepConnectSource, ScopeUser, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, ScopeDeployment, p.Steps[0].Scope)
+	assert.Equal(t, StatusSkipped, p.Steps[0].Status)
+}
+
+func TestSetDismissed(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	p, err := svc.SetDismissed(context.Background(), "alice", true)
+	require.NoError(t, err)
+	assert.True(t, p.Dismissed)
+}
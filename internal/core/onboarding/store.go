@@ -0,0 +1,135 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the onboarding data access interface.
+type Repository interface {
+	// ListProgress returns deployment progress and progress for userID.
+	ListProgress(ctx context.Context, userID string) ([]*Record, error)
+	SetProgress(ctx context.Context, rec *Record) error
+	DeleteProgress(ctx context.Context, step string, userID *string) error
+	// Detect reports whether the work for a step has been done in the catalog.
+	Detect(ctx context.Context, step string) (bool, error)
+	IsDismissed(ctx context.Context, userID string) (bool, error)
+	SetDismissed(ctx context.Context, userID string, dismissed bool) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// detectQueries check the catalog for the outcome of each step. Pipelines
+// may be scheduled in the UI or run from the CLI, so both count.
+var detectQueries = map[string]string{
+	StepConnectSource: `SELECT EXISTS (SELECT 1 FROM ingestion_schedules) OR EXISTS (SELECT 1 FROM runs)`,
+	StepFirstIngestion: `SELECT EXISTS (SELECT 1 FROM runs WHERE status = 'completed')
+		OR EXISTS (SELECT 1 FROM ingestion_job_runs WHERE status = 'succeeded')`,
+	StepAssignOwners:      `SELECT EXISTS (SELECT 1 FROM asset_owners)`,
+	StepCreateDataProduct: `SELECT EXISTS (SELECT 1 FROM data_products)`,
+}
+
+func (r *PostgresRepository) ListProgress(ctx context.Context, userID string) ([]*Record, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT step, status, user_id, updated_by, updated_at
+		FROM onboarding_progress
+		WHERE user_id IS NULL OR user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing onboarding progress: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*Record{}
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.Step, &rec.Status, &rec.UserID, &rec.UpdatedBy, &rec.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning onboarding progress: %w", err)
+		}
+		records = append(records, &rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating onboarding progress: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *PostgresRepository) SetProgress(ctx context.Context, rec *Record) error {
+	conflict := `(step) WHERE user_id IS NULL`
+	if rec.UserID != nil {
+		conflict = `(user_id, step) WHERE user_id IS NOT NULL`
+	}
+
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO onboarding_progress (user_id, step, status, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT `+conflict+` DO UPDATE SET
+			status = EXCLUDED.status,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING updated_at`,
+		rec.UserID, rec.Step, rec.Status, rec.UpdatedBy,
+	).Scan(&rec.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving onboarding progress: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteProgress(ctx context.Context, step string, userID *string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM onboarding_progress
+		WHERE step = $1 AND user_id IS NOT DISTINCT FROM $2`, step, userID)
+	if err != nil {
+		return fmt.Errorf("deleting onboarding progress: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Detect(ctx context.Context, step string) (bool, error) {
+	query, ok := detectQueries[step]
+	if !ok {
+		return false, ErrUnknownStep
+	}
+
+	var done bool
+	if err := r.db.QueryRow(ctx, query).Scan(&done); err != nil {
+		return false, fmt.Errorf("detecting onboarding step %s: %w", step, err)
+	}
+	return done, nil
+}
+
+func (r *PostgresRepository) IsDismissed(ctx context.Context, userID string) (bool, error) {
+	var dismissed bool
+	err := r.db.QueryRow(ctx, `SELECT dismissed FROM onboarding_user_state WHERE user_id = $1`, userID).Scan(&dismissed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting onboarding state: %w", err)
+	}
+	return dismissed, nil
+}
+
+func (r *PostgresRepository) SetDismissed(ctx context.Context, userID string, dismissed bool) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO onboarding_user_state (user_id, dismissed)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET dismissed = EXCLUDED.dismissed, updated_at = NOW()`,
+		userID, dismissed)
+	if err != nil {
+		return fmt.Errorf("saving onboarding state: %w", err)
+	}
+	return nil
+}
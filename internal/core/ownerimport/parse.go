@@ -0,0 +1,219 @@
+package ownerimport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format is the syntax of an ownership mapping.
+type Format string // @name OwnerImportFormat
+
+const (
+	// FormatCodeowners is one rule per line: an MRN pattern followed by
+	// owners, as in a GitHub CODEOWNERS file.
+	FormatCodeowners Format = "codeowners"
+	// FormatYAML is a list of rules with explicit team and user owners.
+	FormatYAML Format = "yaml"
+)
+
+// OwnerKind says whether an owner reference names a user, a team, or
+// either. CODEOWNERS handles such as @jane don't say which.
+type OwnerKind string
+
+const (
+	OwnerKindAny  OwnerKind = ""
+	OwnerKindUser OwnerKind = "user"
+	OwnerKindTeam OwnerKind = "team"
+)
+
+// OwnerRef is an owner as written in the mapping, before it is resolved.
+type OwnerRef struct {
+	Kind OwnerKind `json:"kind,omitempty"`
+	Name string    `json:"name"`
+}
+
+func (o OwnerRef) String() string {
+	if o.Kind == OwnerKindAny {
+		return "@" + o.Name
+	}
+	return string(o.Kind) + ":" + o.Name
+}
+
+// Rule maps an MRN pattern to owners. As in CODEOWNERS, when several rules
+// match an asset the last one wins.
+type Rule struct {
+	Line    int        `json:"line"`
+	Pattern string     `json:"pattern"`
+	Owners  []OwnerRef `json:"owners"`
+
+	re *regexp.Regexp
+}
+
+func (r *Rule) matches(mrn string) bool {
+	return r.re.MatchString(mrn)
+}
+
+// LineError is a problem with one rule of the mapping.
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+} // @name OwnerImportError
+
+type yamlRule struct {
+	Pattern string   `json:"pattern"`
+	Teams   []string `json:"teams"`
+	Users   []string `json:"users"`
+}
+
+type yamlMapping struct {
+	Rules []yamlRule `json:"rules"`
+}
+
+// Parse reads a mapping. Rules with errors are reported and left out, so a
+// dry run can show every problem at once.
+func Parse(format Format, content string) ([]*Rule, []LineError, error) {
+	switch format {
+	case FormatCodeowners, "":
+		rules, errs := parseCodeowners(content)
+		return rules, errs, nil
+	case FormatYAML:
+		return parseYAML(content)
+	default:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("unknown format %q, expected codeowners or yaml", format)}
+	}
+}
+
+func parseCodeowners(content string) ([]*Rule, []LineError) {
+	var rules []*Rule
+	var errs []LineError
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule := &Rule{Line: lineNo, Pattern: fields[0]}
+		for _, f := range fields[1:] {
+			rule.Owners = append(rule.Owners, parseCodeownersOwner(f))
+		}
+
+		if err := rule.compile(); err != nil {
+			errs = append(errs, LineError{Line: lineNo, Message: err.Error()})
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, errs
+}
+
+// parseCodeownersOwner reads @user, @org/team, an email address, or the
+// explicit user:name and team:name forms.
+func parseCodeownersOwner(s string) OwnerRef {
+	switch {
+	case strings.HasPrefix(s, "user:"):
+		return OwnerRef{Kind: OwnerKindUser, Name: strings.TrimPrefix(s, "user:")}
+	case strings.HasPrefix(s, "team:"):
+		return OwnerRef{Kind: OwnerKindTeam, Name: strings.TrimPrefix(s, "team:")}
+	case strings.HasPrefix(s, "@") && strings.Contains(s, "/"):
+		// GitHub team handles are @org/team; the org has no meaning here.
+		return OwnerRef{Kind: OwnerKindTeam, Name: s[strings.LastIndex(s, "/")+1:]}
+	case strings.HasPrefix(s, "@"):
+		return OwnerRef{Kind: OwnerKindAny, Name: strings.TrimPrefix(s, "@")}
+	case strings.Contains(s, "@"):
+		return OwnerRef{Kind: OwnerKindUser, Name: s}
+	default:
+		return OwnerRef{Kind: OwnerKindAny, Name: s}
+	}
+}
+
+func parseYAML(content string) ([]*Rule, []LineError, error) {
+	var mapping yamlMapping
+	if err := yaml.UnmarshalStrict([]byte(content), &mapping); err != nil {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("invalid yaml: %v", err)}
+	}
+
+	var rules []*Rule
+	var errs []LineError
+	for i, yr := range mapping.Rules {
+		// YAML rules are numbered by position, since line numbers are lost
+		// in parsing.
+		rule := &Rule{Line: i + 1, Pattern: strings.TrimSpace(yr.Pattern)}
+		for _, t := range yr.Teams {
+			rule.Owners = append(rule.Owners, OwnerRef{Kind: OwnerKindTeam, Name: strings.TrimSpace(t)})
+		}
+		for _, u := range yr.Users {
+			rule.Owners = append(rule.Owners, OwnerRef{Kind: OwnerKindUser, Name: strings.TrimSpace(u)})
+		}
+
+		if err := rule.compile(); err != nil {
+			errs = append(errs, LineError{Line: rule.Line, Message: err.Error()})
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, errs, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if strings.Trim(r.Pattern, "*") == "" {
+		return fmt.Errorf("pattern %q must contain more than wildcards", r.Pattern)
+	}
+	if len(r.Owners) == 0 {
+		return fmt.Errorf("pattern %q has no owners", r.Pattern)
+	}
+	for _, o := range r.Owners {
+		if o.Name == "" {
+			return fmt.Errorf("pattern %q has an empty owner", r.Pattern)
+		}
+	}
+
+	re, err := regexp.Compile(globToRegexp(r.Pattern))
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", r.Pattern, err)
+	}
+	r.re = re
+	return nil
+}
+
+// globToRegexp translates * and ? wildcards into an anchored expression.
+// * crosses dots and slashes, so mrn://snowflake/* matches every Snowflake
+// asset.
+func globToRegexp(glob string) string {
+	quoted := regexp.QuoteMeta(glob)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return "^" + quoted + "$"
+}
+
+// likePattern turns a glob into a LIKE pattern, so candidate assets can be
+// narrowed down in the database before rules are evaluated in order.
+func likePattern(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '\\', '%', '_':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '*':
+			b.WriteRune('%')
+		case '?':
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package ownerimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# Ownership for the warehouse
+mrn://snowflake/*          @acme/data-platform
+mrn://snowflake/*.finance.* @jane finance@acme.com team:finance # finance owns its schema
+
+mrn://kafka/orders-*       user:sam
+*
+mrn://s3/raw-*
+`
+
+	rules, errs, err := Parse(FormatCodeowners, content)
+	require.NoError(t, err)
+
+	require.Len(t, rules, 3)
+	assert.Equal(t, 2, rules[0].Line)
+	assert.Equal(t, []OwnerRef{{Kind: OwnerKindTeam, Name: "data-platform"}}, rules[0].Owners)
+	assert.Equal(t, []OwnerRef{
+		{Kind: OwnerKindAny, Name: "jane"},
+		{Kind: OwnerKindUser, Name: "finance@acme.com"},
+		{Kind: OwnerKindTeam, Name: "finance"},
+	}, rules[1].Owners)
+	assert.Equal(t, 5, rules[2].Line)
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, 6, errs[0].Line)
+	assert.Equal(t, 7, errs[1].Line)
+}
+
+func TestParseYAML(t *testing.T) {
+	content := `
+rules:
+  - pattern: mrn://snowflake/*
+    teams: [data-platform]
+  - pattern: mrn://kafka/orders-*
+    users: [sam]
+    teams: [orders]
+  - pattern: mrn://s3/*
+`
+
+	rules, errs, err := Parse(FormatYAML, content)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, []OwnerRef{
+		{Kind: OwnerKindTeam, Name: "orders"},
+		{Kind: OwnerKindUser, Name: "sam"},
+	}, rules[1].Owners)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 3, errs[0].Line)
+
+	_, _, err = Parse(FormatYAML, "rules: [{pattern: x, owner: y}]")
+	assert.True(t, IsValidationError(err))
+
+	_, _, err = Parse("csv", "")
+	assert.True(t, IsValidationError(err))
+}
+
+func TestRuleMatching(t *testing.T) {
+	rules, _, err := Parse(FormatCodeowners, "mrn://snowflake/db.*_raw @a\nmrn://kafka/orders-? @b")
+	require.NoError(t, err)
+
+	assert.True(t, rules[0].matches("mrn://snowflake/db.public.events_raw"))
+	assert.False(t, rules[0].matches("mrn://snowflake/db.public.events_raw_v2"))
+	assert.True(t, rules[1].matches("mrn://kafka/orders-1"))
+	assert.False(t, rules[1].matches("mrn://kafka/orders-10"))
+
+	assert.Equal(t, `mrn://snowflake/db.%\_raw`, likePattern(rules[0].Pattern))
+	assert.Equal(t, `mrn://kafka/orders-_`, likePattern(rules[1].Pattern))
+}
@@ -0,0 +1,262 @@
+package ownerimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Mode controls what happens to owners a rule doesn't list.
+type Mode string // @name OwnerImportMode
+
+const (
+	// ModeAdd adds the rule's owners and keeps existing ones.
+	ModeAdd Mode = "add"
+	// ModeReplace makes the rule's owners the asset's only owners.
+	ModeReplace Mode = "replace"
+)
+
+const (
+	OwnerTypeUser = "user"
+	OwnerTypeTeam = "team"
+
+	maxContentLength = 1 << 20
+)
+
+// Owner is a resolved user or team.
+type Owner struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+} // @name OwnerImportOwner
+
+// Asset is a candidate asset with its current owners.
+type Asset struct {
+	ID     string
+	MRN    string
+	Name   string
+	Owners []Owner
+}
+
+// Change is the ownership diff for one asset.
+type Change struct {
+	AssetID   string  `json:"asset_id"`
+	AssetMRN  string  `json:"asset_mrn"`
+	AssetName string  `json:"asset_name"`
+	Line      int     `json:"line"`
+	Add       []Owner `json:"add"`
+	Remove    []Owner `json:"remove"`
+} // @name OwnerImportChange
+
+type Request struct {
+	Format  Format `json:"format"`
+	Content string `json:"content"`
+	Mode    Mode   `json:"mode"`
+	DryRun  bool   `json:"dry_run"`
+} // @name OwnerImportRequest
+
+type Result struct {
+	DryRun  bool        `json:"dry_run"`
+	Mode    Mode        `json:"mode"`
+	Rules   int         `json:"rules"`
+	Errors  []LineError `json:"errors"`
+	Changes []Change    `json:"changes"`
+	// Unchanged counts matched assets whose owners are already correct.
+	Unchanged     int `json:"unchanged"`
+	OwnersAdded   int `json:"owners_added"`
+	OwnersRemoved int `json:"owners_removed"`
+	// UnmatchedLines lists rules that matched no asset, usually a typo in
+	// the pattern.
+	UnmatchedLines []int `json:"unmatched_lines"`
+} // @name OwnerImportResult
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Import plans the ownership changes a mapping makes and, unless it is a
+// dry run, applies them. Nothing is applied while the mapping has errors.
+func (s *Service) Import(ctx context.Context, req Request) (*Result, error) {
+	if req.Mode == "" {
+		req.Mode = ModeAdd
+	}
+	if req.Mode != ModeAdd && req.Mode != ModeReplace {
+		return nil, &ValidationError{Message: fmt.Sprintf("unknown mode %q, expected add or replace", req.Mode)}
+	}
+	if len(req.Content) > maxContentLength {
+		return nil, &ValidationError{Message: "mapping must be 1MB or less"}
+	}
+
+	rules, errs, err := Parse(req.Format, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 && len(errs) == 0 {
+		return nil, &ValidationError{Message: "mapping has no rules"}
+	}
+
+	resolved, resolveErrs, err := s.resolveOwners(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, resolveErrs...)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+
+	result := &Result{
+		DryRun:         req.DryRun,
+		Mode:           req.Mode,
+		Rules:          len(rules),
+		Errors:         errs,
+		Changes:        []Change{},
+		UnmatchedLines: []int{},
+	}
+	if result.Errors == nil {
+		result.Errors = []LineError{}
+	}
+
+	patterns := make([]string, len(rules))
+	for i, r := range rules {
+		patterns[i] = likePattern(r.Pattern)
+	}
+	assets, err := s.repo.ListCandidates(ctx, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[int]bool, len(rules))
+	for _, a := range assets {
+		rule := lastMatch(rules, a.MRN)
+		if rule == nil {
+			continue
+		}
+		matched[rule.Line] = true
+
+		change := diff(a, resolved[rule.Line], req.Mode)
+		if len(change.Add) == 0 && len(change.Remove) == 0 {
+			result.Unchanged++
+			continue
+		}
+		change.Line = rule.Line
+		result.Changes = append(result.Changes, change)
+		result.OwnersAdded += len(change.Add)
+		result.OwnersRemoved += len(change.Remove)
+	}
+
+	for _, r := range rules {
+		if !matched[r.Line] {
+			result.UnmatchedLines = append(result.UnmatchedLines, r.Line)
+		}
+	}
+
+	if req.DryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		return nil, &ValidationError{Message: fmt.Sprintf("mapping has %d errors; fix them or run with dry_run to see them", len(result.Errors))}
+	}
+
+	if err := s.repo.Apply(ctx, result.Changes); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveOwners looks up each rule's owners, keyed by rule line. Handles
+// that could be either kind are tried as a user first, then a team.
+func (s *Service) resolveOwners(ctx context.Context, rules []*Rule) (map[int][]Owner, []LineError, error) {
+	cache := make(map[OwnerRef]*Owner)
+	resolved := make(map[int][]Owner, len(rules))
+	var errs []LineError
+
+	for _, r := range rules {
+		for _, ref := range r.Owners {
+			owner, ok := cache[ref]
+			if !ok {
+				var err error
+				owner, err = s.resolve(ctx, ref)
+				if err != nil {
+					return nil, nil, err
+				}
+				cache[ref] = owner
+			}
+			if owner == nil {
+				errs = append(errs, LineError{Line: r.Line, Message: fmt.Sprintf("owner %s not found", ref)})
+				continue
+			}
+			resolved[r.Line] = appendOwner(resolved[r.Line], *owner)
+		}
+	}
+
+	return resolved, errs, nil
+}
+
+func (s *Service) resolve(ctx context.Context, ref OwnerRef) (*Owner, error) {
+	if ref.Kind != OwnerKindTeam {
+		owner, err := s.repo.FindUser(ctx, ref.Name)
+		if err != nil || owner != nil || ref.Kind == OwnerKindUser {
+			return owner, err
+		}
+	}
+	return s.repo.FindTeam(ctx, ref.Name)
+}
+
+func lastMatch(rules []*Rule, mrn string) *Rule {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].matches(mrn) {
+			return rules[i]
+		}
+	}
+	return nil
+}
+
+func diff(a Asset, want []Owner, mode Mode) Change {
+	change := Change{AssetID: a.ID, AssetMRN: a.MRN, AssetName: a.Name, Add: []Owner{}, Remove: []Owner{}}
+
+	for _, o := range want {
+		if !containsOwner(a.Owners, o) {
+			change.Add = append(change.Add, o)
+		}
+	}
+	if mode == ModeReplace && len(want) > 0 {
+		for _, o := range a.Owners {
+			if !containsOwner(want, o) {
+				change.Remove = append(change.Remove, o)
+			}
+		}
+	}
+	return change
+}
+
+func appendOwner(owners []Owner, o Owner) []Owner {
+	if containsOwner(owners, o) {
+		return owners
+	}
+	return append(owners, o)
+}
+
+func containsOwner(owners []Owner, o Owner) bool {
+	for _, existing := range owners {
+		if existing.Type == o.Type && existing.ID == o.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package ownerimport
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	users   map[string]Owner
+	teams   map[string]Owner
+	assets  []Asset
+	applied []Change
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users: map[string]Owner{
+			"jane":          {Type: OwnerTypeUser, ID: "u-jane", Name: "Jane"},
+			"sam@acme.com":  {Type: OwnerTypeUser, ID: "u-sam", Name: "Sam"},
+			"data-platform": {Type: OwnerTypeUser, ID: "u-clash", Name: "Not a team"},
+		},
+		teams: map[string]Owner{
+			"data-platform": {Type: OwnerTypeTeam, ID: "t-platform", Name: "Data Platform"},
+			"finance":       {Type: OwnerTypeTeam, ID: "t-finance", Name: "Finance"},
+		},
+		assets: []Asset{
+			{ID: "a1", MRN: "mrn://snowflake/db.sales.orders"},
+			{ID: "a2", MRN: "mrn://snowflake/db.finance.ledger", Owners: []Owner{{Type: OwnerTypeUser, ID: "u-old", Name: "Old"}}},
+			{ID: "a3", MRN: "mrn://snowflake/db.finance.budget", Owners: []Owner{{Type: OwnerTypeTeam, ID: "t-finance", Name: "Finance"}}},
+			{ID: "a4", MRN: "mrn://kafka/events"},
+		},
+	}
+}
+
+func (f *fakeRepo) FindUser(_ context.Context, name string) (*Owner, error) {
+	if o, ok := f.users[strings.ToLower(name)]; ok {
+		return &o, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeRepo) FindTeam(_ context.Context, name string) (*Owner, error) {
+	if o, ok := f.teams[strings.ToLower(name)]; ok {
+		return &o, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeRepo) ListCandidates(_ context.Context, _ []string) ([]Asset, error) {
+	return f.assets, nil
+}
+
+func (f *fakeRepo) Apply(_ context.Context, changes []Change) error {
+	f.applied = changes
+	return nil
+}
+
+const mapping = `mrn://snowflake/*           @acme/data-platform
+mrn://snowflake/*.finance.* @finance
+mrn://postgres/*            @jane`
+
+func TestImport_DryRunLastRuleWins(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	result, err := svc.Import(context.Background(), Request{Content: mapping, Mode: ModeReplace, DryRun: true})
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 3, result.Rules)
+	assert.Equal(t, []int{3}, result.UnmatchedLines)
+	assert.Equal(t, 1, result.Unchanged)
+	require.Len(t, result.Changes, 2)
+
+	assert.Equal(t, "a1", result.Changes[0].AssetID)
+	assert.Equal(t, 1, result.Changes[0].Line)
+	assert.Equal(t, []Owner{{Type: OwnerTypeTeam, ID: "t-platform", Name: "Data Platform"}}, result.Changes[0].Add)
+
+	assert.Equal(t, "a2", result.Changes[1].AssetID)
+	assert.Equal(t, 2, result.Changes[1].Line)
+	assert.Equal(t, "t-finance", result.Changes[1].Add[0].ID)
+	assert.Equal(t, "u-old", result.Changes[1].Remove[0].ID)
+
+	assert.Equal(t, 2, result.OwnersAdded)
+	assert.Equal(t, 1, result.OwnersRemoved)
+	assert.Nil(t, repo.applied)
+}
+
+func TestImport_AddModeKeepsOwners(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	result, err := svc.Import(context.Background(), Request{Content: mapping})
+	require.NoError(t, err)
+
+	assert.Equal(t, ModeAdd, result.Mode)
+	assert.Equal(t, 0, result.OwnersRemoved)
+	assert.Len(t, repo.applied, 2)
+}
+
+func TestImport_ResolvesHandles(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	result, err := svc.Import(context.Background(), Request{
+		Content: "mrn://kafka/* @jane SAM@acme.com team:data-platform @nobody",
+		DryRun:  true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "@nobody")
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, []Owner{
+		{Type: OwnerTypeUser, ID: "u-jane", Name: "Jane"},
+		{Type: OwnerTypeUser, ID: "u-sam", Name: "Sam"},
+		{Type: OwnerTypeTeam, ID: "t-platform", Name: "Data Platform"},
+	}, result.Changes[0].Add)
+}
+
+func TestImport_ErrorsBlockApply(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+
+	_, err := svc.Import(context.Background(), Request{Content: "mrn://kafka/* @nobody\nmrn://snowflake/* @jane"})
+	assert.True(t, IsValidationError(err))
+	assert.Nil(t, repo.applied)
+
+	_, err = svc.Import(context.Background(), Request{Content: mapping, Mode: "merge"})
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.Import(context.Background(), Request{Content: "# nothing here"})
+	assert.True(t, IsValidationError(err))
+}
@@ -0,0 +1,140 @@
+package ownerimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository resolves owners and reads and writes asset ownership.
+type Repository interface {
+	// FindUser matches a username or identity email, case-insensitively.
+	// It returns nil when there is no such user.
+	FindUser(ctx context.Context, name string) (*Owner, error)
+	// FindTeam matches a team name, case-insensitively. It returns nil when
+	// there is no such team.
+	FindTeam(ctx context.Context, name string) (*Owner, error)
+	// ListCandidates returns the assets whose MRN is LIKE any pattern, with
+	// their current owners.
+	ListCandidates(ctx context.Context, likePatterns []string) ([]Asset, error)
+	Apply(ctx context.Context, changes []Change) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) FindUser(ctx context.Context, name string) (*Owner, error) {
+	owner := Owner{Type: OwnerTypeUser}
+	err := r.db.QueryRow(ctx, `
+		SELECT u.id::text, COALESCE(u.name, u.username)
+		FROM users u
+		WHERE LOWER(u.username) = LOWER($1)
+		   OR EXISTS (SELECT 1 FROM user_identities ui WHERE ui.user_id = u.id AND LOWER(ui.provider_email) = LOWER($1))
+		ORDER BY LOWER(u.username) = LOWER($1) DESC
+		LIMIT 1`, name).Scan(&owner.ID, &owner.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding user %q: %w", name, err)
+	}
+	return &owner, nil
+}
+
+func (r *PostgresRepository) FindTeam(ctx context.Context, name string) (*Owner, error) {
+	owner := Owner{Type: OwnerTypeTeam}
+	err := r.db.QueryRow(ctx, `
+		SELECT id::text, name
+		FROM teams
+		WHERE LOWER(name) = LOWER($1)
+		LIMIT 1`, name).Scan(&owner.ID, &owner.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding team %q: %w", name, err)
+	}
+	return &owner, nil
+}
+
+func (r *PostgresRepository) ListCandidates(ctx context.Context, likePatterns []string) ([]Asset, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.mrn, a.name,
+		       COALESCE(array_agg(CASE WHEN ao.user_id IS NOT NULL THEN 'user' ELSE 'team' END) FILTER (WHERE ao.asset_id IS NOT NULL), '{}'),
+		       COALESCE(array_agg(COALESCE(ao.user_id, ao.team_id)::text) FILTER (WHERE ao.asset_id IS NOT NULL), '{}'),
+		       COALESCE(array_agg(COALESCE(u.name, u.username, t.name)) FILTER (WHERE ao.asset_id IS NOT NULL), '{}')
+		FROM assets a
+		LEFT JOIN asset_owners ao ON ao.asset_id = a.id
+		LEFT JOIN users u ON u.id = ao.user_id
+		LEFT JOIN teams t ON t.id = ao.team_id
+		WHERE a.is_stub = FALSE AND a.mrn LIKE ANY($1)
+		GROUP BY a.id, a.mrn, a.name
+		ORDER BY a.mrn`, likePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("listing candidate assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var a Asset
+		var types, ids, names []string
+		if err := rows.Scan(&a.ID, &a.MRN, &a.Name, &types, &ids, &names); err != nil {
+			return nil, fmt.Errorf("scanning candidate asset: %w", err)
+		}
+		for i := range ids {
+			a.Owners = append(a.Owners, Owner{Type: types[i], ID: ids[i], Name: names[i]})
+		}
+		assets = append(assets, a)
+	}
+	return assets, rows.Err()
+}
+
+func (r *PostgresRepository) Apply(ctx context.Context, changes []Change) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, c := range changes {
+		for _, o := range c.Add {
+			column := "team_id"
+			if o.Type == OwnerTypeUser {
+				column = "user_id"
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`
+				INSERT INTO asset_owners (asset_id, %s)
+				VALUES ($1, $2)
+				ON CONFLICT DO NOTHING`, column), c.AssetID, o.ID); err != nil {
+				return fmt.Errorf("adding owner to %s: %w", c.AssetMRN, err)
+			}
+		}
+		for _, o := range c.Remove {
+			column := "team_id"
+			if o.Type == OwnerTypeUser {
+				column = "user_id"
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`
+				DELETE FROM asset_owners WHERE asset_id = $1 AND %s::text = $2`, column), c.AssetID, o.ID); err != nil {
+				return fmt.Errorf("removing owner from %s: %w", c.AssetMRN, err)
+			}
+		}
+		if _, err := tx.Exec(ctx, `UPDATE assets SET updated_at = NOW() WHERE id = $1`, c.AssetID); err != nil {
+			return fmt.Errorf("updating asset timestamp: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing ownership import: %w", err)
+	}
+	return nil
+}
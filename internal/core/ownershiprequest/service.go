@@ -0,0 +1,365 @@
+package ownershiprequest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/team"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound   = errors.New("ownership request not found")
+	ErrForbidden  = errors.New("not allowed to act on this request")
+	ErrNotPending = errors.New("request has already been decided")
+	ErrDuplicate  = errors.New("a matching request is already pending")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const maxTextLength = 2000
+
+type Kind string // @name OwnershipRequestKind
+
+const (
+	// KindOwnership asks to be added as an owner of the asset, as a user or
+	// on behalf of a team.
+	KindOwnership Kind = "ownership"
+	// KindAccess asks for access to the data behind the asset. Marmot records
+	// the decision; granting access in the source system is up to the owner.
+	KindAccess Kind = "access"
+)
+
+type Status string // @name OwnershipRequestStatus
+
+const (
+	StatusPending   Status = "pending"
+	StatusApproved  Status = "approved"
+	StatusRejected  Status = "rejected"
+	StatusCancelled Status = "cancelled"
+)
+
+// Request is a user's request for ownership of, or access to, an asset.
+type Request struct {
+	ID            string  `json:"id"`
+	AssetID       string  `json:"asset_id"`
+	AssetName     string  `json:"asset_name,omitempty"`
+	Kind          Kind    `json:"kind"`
+	RequestedBy   string  `json:"requested_by"`
+	RequesterName string  `json:"requester_name,omitempty"`
+	TeamID        *string `json:"team_id,omitempty"`
+	TeamName      string  `json:"team_name,omitempty"`
+	Reason        string  `json:"reason"`
+	Status        Status  `json:"status"`
+	// DecidedBy is the owner who approved or rejected the request, or the
+	// requester who cancelled it.
+	DecidedBy    *string    `json:"decided_by,omitempty"`
+	DeciderName  string     `json:"decider_name,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+} // @name OwnershipRequest
+
+type CreateInput struct {
+	Kind Kind `json:"kind"`
+	// TeamID requests ownership for a team the requester belongs to.
+	TeamID *string `json:"team_id,omitempty"`
+	Reason string  `json:"reason"`
+} // @name CreateOwnershipRequestInput
+
+type DecideInput struct {
+	Note string `json:"note"`
+} // @name DecideOwnershipRequestInput
+
+type ListResult struct {
+	Requests []*Request `json:"requests"`
+	Total    int        `json:"total"`
+} // @name OwnershipRequestListResult
+
+// ListFilter narrows a listing. Empty fields match everything.
+type ListFilter struct {
+	AssetID     string
+	RequestedBy string
+	// OwnedBy limits requests to assets the user owns, directly or through a team.
+	OwnedBy string
+	Status  Status
+	Limit   int
+	Offset  int
+}
+
+// Actor is the user performing an operation. Moderators, typically users
+// with asset management permission, may decide any request.
+type Actor struct {
+	UserID      string
+	CanModerate bool
+}
+
+// Owners reads and changes asset ownership.
+type Owners interface {
+	CanUserAccessAsset(ctx context.Context, userID, assetID string) (bool, error)
+	ListAssetOwners(ctx context.Context, assetID string) ([]*team.Owner, error)
+	AddAssetOwner(ctx context.Context, assetID, ownerType, ownerID string) error
+	GetMember(ctx context.Context, teamID, userID string) (*team.TeamMember, error)
+}
+
+// Notifier is told about new and decided requests.
+type Notifier interface {
+	NotifyRequested(ctx context.Context, r *Request)
+	NotifyDecided(ctx context.Context, r *Request)
+}
+
+type Service struct {
+	repo     Repository
+	owners   Owners
+	notifier Notifier
+}
+
+func NewService(repo Repository, owners Owners) *Service {
+	return &Service{repo: repo, owners: owners}
+}
+
+// SetNotifier enables notifications for new and decided requests.
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// Create files a request and notifies the asset's owners.
+func (s *Service) Create(ctx context.Context, assetID string, input CreateInput, actor Actor) (*Request, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if assetID == "" {
+		return nil, &ValidationError{Message: "asset_id is required"}
+	}
+	if input.Kind != KindOwnership && input.Kind != KindAccess {
+		return nil, &ValidationError{Message: "kind must be ownership or access"}
+	}
+	if len(input.Reason) > maxTextLength {
+		return nil, &ValidationError{Message: "reason must be at most 2000 characters"}
+	}
+	if input.TeamID != nil && *input.TeamID == "" {
+		input.TeamID = nil
+	}
+	if input.TeamID != nil && input.Kind != KindOwnership {
+		return nil, &ValidationError{Message: "team_id is only allowed for ownership requests"}
+	}
+
+	if err := s.checkRequestable(ctx, assetID, input, actor); err != nil {
+		return nil, err
+	}
+
+	r := &Request{
+		AssetID:     assetID,
+		Kind:        input.Kind,
+		RequestedBy: actor.UserID,
+		TeamID:      input.TeamID,
+		Reason:      input.Reason,
+		Status:      StatusPending,
+	}
+	if err := s.repo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+
+	// Reload for the asset, requester and team names used in notifications.
+	created, err := s.repo.Get(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.notifier != nil {
+		s.notifier.NotifyRequested(ctx, created)
+	}
+	return created, nil
+}
+
+// checkRequestable rejects requests that would change nothing.
+func (s *Service) checkRequestable(ctx context.Context, assetID string, input CreateInput, actor Actor) error {
+	if input.TeamID != nil {
+		if _, err := uuid.Parse(*input.TeamID); err != nil {
+			return &ValidationError{Message: "team_id must be a team ID"}
+		}
+		if _, err := s.owners.GetMember(ctx, *input.TeamID, actor.UserID); err != nil {
+			if errors.Is(err, team.ErrMemberNotFound) || errors.Is(err, team.ErrTeamNotFound) {
+				return &ValidationError{Message: "you can only request ownership for teams you belong to"}
+			}
+			return err
+		}
+	}
+
+	if input.Kind == KindAccess {
+		if s.isOwner(ctx, actor.UserID, assetID) {
+			return &ValidationError{Message: "owners already have access to this asset"}
+		}
+		return nil
+	}
+
+	owners, err := s.owners.ListAssetOwners(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	ownerType, ownerID := team.OwnerTypeUser, actor.UserID
+	if input.TeamID != nil {
+		ownerType, ownerID = team.OwnerTypeTeam, *input.TeamID
+	}
+	for _, o := range owners {
+		if o.Type == ownerType && o.ID == ownerID {
+			return &ValidationError{Message: "already an owner of this asset"}
+		}
+	}
+	return nil
+}
+
+// Get returns a request visible to its requester, the asset's owners and
+// moderators.
+func (s *Service) Get(ctx context.Context, id string, actor Actor) (*Request, error) {
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.RequestedBy != actor.UserID && !s.canDecide(ctx, r, actor) {
+		return nil, ErrForbidden
+	}
+	return r, nil
+}
+
+// ListForAsset returns an asset's requests, newest first. Owners and
+// moderators see every request; anyone else sees only their own.
+func (s *Service) ListForAsset(ctx context.Context, assetID string, status Status, actor Actor, limit, offset int) (*ListResult, error) {
+	filter := ListFilter{AssetID: assetID, Status: status, Limit: limit, Offset: offset}
+	if !actor.CanModerate && !s.isOwner(ctx, actor.UserID, assetID) {
+		filter.RequestedBy = actor.UserID
+	}
+	return s.list(ctx, filter)
+}
+
+// ListMine returns the requests the actor has made, newest first.
+func (s *Service) ListMine(ctx context.Context, status Status, actor Actor, limit, offset int) (*ListResult, error) {
+	return s.list(ctx, ListFilter{RequestedBy: actor.UserID, Status: status, Limit: limit, Offset: offset})
+}
+
+// ListForReview returns the pending requests on assets the actor owns.
+func (s *Service) ListForReview(ctx context.Context, actor Actor, limit, offset int) (*ListResult, error) {
+	return s.list(ctx, ListFilter{OwnedBy: actor.UserID, Status: StatusPending, Limit: limit, Offset: offset})
+}
+
+func (s *Service) list(ctx context.Context, filter ListFilter) (*ListResult, error) {
+	if filter.Status != "" && !validStatus(filter.Status) {
+		return nil, &ValidationError{Message: "status must be pending, approved, rejected or cancelled"}
+	}
+	requests, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Requests: requests, Total: total}, nil
+}
+
+// Approve grants a pending request. Approved ownership requests add the
+// requester, or their team, as an owner.
+func (s *Service) Approve(ctx context.Context, id string, input DecideInput, actor Actor) (*Request, error) {
+	r, err := s.pendingForDecision(ctx, id, input, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Kind == KindOwnership {
+		ownerType, ownerID := team.OwnerTypeUser, r.RequestedBy
+		if r.TeamID != nil {
+			ownerType, ownerID = team.OwnerTypeTeam, *r.TeamID
+		}
+		if err := s.owners.AddAssetOwner(ctx, r.AssetID, ownerType, ownerID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.decide(ctx, r, StatusApproved, input, actor)
+}
+
+// Reject declines a pending request.
+func (s *Service) Reject(ctx context.Context, id string, input DecideInput, actor Actor) (*Request, error) {
+	r, err := s.pendingForDecision(ctx, id, input, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, r, StatusRejected, input, actor)
+}
+
+// Cancel withdraws a pending request. Only the requester or a moderator may
+// cancel, and owners aren't notified.
+func (s *Service) Cancel(ctx context.Context, id string, actor Actor) (*Request, error) {
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.RequestedBy != actor.UserID && !actor.CanModerate {
+		return nil, ErrForbidden
+	}
+	if r.Status != StatusPending {
+		return nil, ErrNotPending
+	}
+	return s.repo.Decide(ctx, id, StatusCancelled, actor.UserID, "")
+}
+
+func (s *Service) pendingForDecision(ctx context.Context, id string, input DecideInput, actor Actor) (*Request, error) {
+	if len(strings.TrimSpace(input.Note)) > maxTextLength {
+		return nil, &ValidationError{Message: "note must be at most 2000 characters"}
+	}
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canDecide(ctx, r, actor) {
+		return nil, ErrForbidden
+	}
+	if r.Status != StatusPending {
+		return nil, ErrNotPending
+	}
+	return r, nil
+}
+
+func (s *Service) decide(ctx context.Context, r *Request, status Status, input DecideInput, actor Actor) (*Request, error) {
+	decided, err := s.repo.Decide(ctx, r.ID, status, actor.UserID, strings.TrimSpace(input.Note))
+	if err != nil {
+		return nil, err
+	}
+	if s.notifier != nil {
+		s.notifier.NotifyDecided(ctx, decided)
+	}
+	return decided, nil
+}
+
+// canDecide reports whether actor may approve or reject r: moderators and
+// the asset's owners can, except on their own requests.
+func (s *Service) canDecide(ctx context.Context, r *Request, actor Actor) bool {
+	if actor.CanModerate {
+		return true
+	}
+	return r.RequestedBy != actor.UserID && s.isOwner(ctx, actor.UserID, r.AssetID)
+}
+
+func (s *Service) isOwner(ctx context.Context, userID, assetID string) bool {
+	ok, err := s.owners.CanUserAccessAsset(ctx, userID, assetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", assetID).Msg("Failed to check asset ownership")
+		return false
+	}
+	return ok
+}
+
+func validStatus(s Status) bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusRejected, StatusCancelled:
+		return true
+	}
+	return false
+}
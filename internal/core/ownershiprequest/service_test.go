@@ -0,0 +1,152 @@
+package ownershiprequest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	requests map[string]*Request
+}
+
+func (f *fakeRepo) Create(ctx context.Context, r *Request) error {
+	r.ID = "r1"
+	f.requests[r.ID] = r
+	return nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (*Request, error) {
+	r, ok := f.requests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *r
+	return &cp, nil
+}
+
+func (f *fakeRepo) Decide(ctx context.Context, id string, status Status, decidedBy, note string) (*Request, error) {
+	r := f.requests[id]
+	r.Status, r.DecidedBy, r.DecisionNote = status, &decidedBy, note
+	return f.Get(ctx, id)
+}
+
+// fakeOwners starts with "owner" owning the asset and knows one team,
+// teamID, whose only member is "bob".
+type fakeOwners struct {
+	owners []*team.Owner
+}
+
+const teamID = "7f5c2f8e-3b1a-4c8e-9d2a-1e4b5c6d7e8f"
+
+func (f *fakeOwners) CanUserAccessAsset(ctx context.Context, userID, assetID string) (bool, error) {
+	for _, o := range f.owners {
+		if o.ID == userID || (o.ID == teamID && userID == "bob") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeOwners) ListAssetOwners(ctx context.Context, assetID string) ([]*team.Owner, error) {
+	return f.owners, nil
+}
+
+func (f *fakeOwners) AddAssetOwner(ctx context.Context, assetID, ownerType, ownerID string) error {
+	f.owners = append(f.owners, &team.Owner{Type: ownerType, ID: ownerID})
+	return nil
+}
+
+func (f *fakeOwners) GetMember(ctx context.Context, teamID, userID string) (*team.TeamMember, error) {
+	if userID != "bob" {
+		return nil, team.ErrMemberNotFound
+	}
+	return &team.TeamMember{TeamID: teamID, UserID: userID}, nil
+}
+
+type recordingNotifier struct{ requested, decided []*Request }
+
+func (n *recordingNotifier) NotifyRequested(ctx context.Context, r *Request) {
+	n.requested = append(n.requested, r)
+}
+func (n *recordingNotifier) NotifyDecided(ctx context.Context, r *Request) {
+	n.decided = append(n.decided, r)
+}
+
+func newTestService() (*Service, *fakeOwners, *recordingNotifier) {
+	owners := &fakeOwners{owners: []*team.Owner{{Type: team.OwnerTypeUser, ID: "owner"}}}
+	notifier := &recordingNotifier{}
+	s := NewService(&fakeRepo{requests: map[string]*Request{}}, owners)
+	s.SetNotifier(notifier)
+	return s, owners, notifier
+}
+
+func TestCreateValidation(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService()
+	tid := teamID
+
+	_, err := s.Create(ctx, "a1", CreateInput{Kind: "admin"}, Actor{UserID: "alice"})
+	assert.True(t, IsValidationError(err))
+
+	_, err = s.Create(ctx, "a1", CreateInput{Kind: KindAccess, TeamID: &tid}, Actor{UserID: "bob"})
+	assert.True(t, IsValidationError(err), "teams can only request ownership")
+
+	_, err = s.Create(ctx, "a1", CreateInput{Kind: KindOwnership, TeamID: &tid}, Actor{UserID: "alice"})
+	assert.True(t, IsValidationError(err), "alice is not in the team")
+
+	_, err = s.Create(ctx, "a1", CreateInput{Kind: KindOwnership}, Actor{UserID: "owner"})
+	assert.True(t, IsValidationError(err), "already an owner")
+
+	_, err = s.Create(ctx, "a1", CreateInput{Kind: KindAccess}, Actor{UserID: "owner"})
+	assert.True(t, IsValidationError(err), "owners already have access")
+}
+
+func TestApproveTeamOwnership(t *testing.T) {
+	ctx := context.Background()
+	s, owners, notifier := newTestService()
+	tid := teamID
+
+	r, err := s.Create(ctx, "a1", CreateInput{Kind: KindOwnership, TeamID: &tid, Reason: " we maintain it "}, Actor{UserID: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "we maintain it", r.Reason)
+	require.Len(t, notifier.requested, 1)
+
+	_, err = s.Approve(ctx, r.ID, DecideInput{}, Actor{UserID: "bob"})
+	assert.ErrorIs(t, err, ErrForbidden, "requesters can't approve their own request")
+	_, err = s.Approve(ctx, r.ID, DecideInput{}, Actor{UserID: "alice"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	approved, err := s.Approve(ctx, r.ID, DecideInput{Note: "ok"}, Actor{UserID: "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, approved.Status)
+	assert.Equal(t, "ok", approved.DecisionNote)
+	assert.Equal(t, &team.Owner{Type: "team", ID: teamID}, owners.owners[1])
+	require.Len(t, notifier.decided, 1)
+
+	_, err = s.Reject(ctx, r.ID, DecideInput{}, Actor{UserID: "owner"})
+	assert.ErrorIs(t, err, ErrNotPending)
+}
+
+func TestRejectAndCancelAccess(t *testing.T) {
+	ctx := context.Background()
+	s, owners, _ := newTestService()
+
+	r, err := s.Create(ctx, "a1", CreateInput{Kind: KindAccess}, Actor{UserID: "alice"})
+	require.NoError(t, err)
+
+	_, err = s.Cancel(ctx, r.ID, Actor{UserID: "carol"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	rejected, err := s.Reject(ctx, r.ID, DecideInput{}, Actor{UserID: "carol", CanModerate: true})
+	require.NoError(t, err)
+	assert.Equal(t, StatusRejected, rejected.Status)
+	assert.Len(t, owners.owners, 1, "rejecting changes no owners")
+
+	_, err = s.Cancel(ctx, r.ID, Actor{UserID: "alice"})
+	assert.ErrorIs(t, err, ErrNotPending)
+}
@@ -0,0 +1,162 @@
+package ownershiprequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the ownership request data access interface.
+type Repository interface {
+	Create(ctx context.Context, r *Request) error
+	Get(ctx context.Context, id string) (*Request, error)
+	List(ctx context.Context, filter ListFilter) ([]*Request, int, error)
+	// Decide moves a pending request to status, returning ErrNotPending if
+	// it was decided in the meantime.
+	Decide(ctx context.Context, id string, status Status, decidedBy, note string) (*Request, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectRequest = `
+	SELECT r.id, r.asset_id, COALESCE(a.name, ''), r.kind, r.requested_by, COALESCE(u.name, ''),
+	       r.team_id, COALESCE(t.name, ''), r.reason, r.status,
+	       r.decided_by, COALESCE(d.name, ''), r.decision_note, r.decided_at, r.created_at
+	FROM asset_ownership_requests r
+	JOIN assets a ON a.id = r.asset_id
+	LEFT JOIN users u ON u.id = r.requested_by
+	LEFT JOIN teams t ON t.id = r.team_id
+	LEFT JOIN users d ON d.id = r.decided_by`
+
+func (r *PostgresRepository) Create(ctx context.Context, req *Request) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_ownership_requests (asset_id, kind, requested_by, team_id, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at`,
+		req.AssetID, req.Kind, req.RequestedBy, req.TeamID, req.Reason,
+	).Scan(&req.ID, &req.Status, &req.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503":
+				return &ValidationError{Message: "asset not found"}
+			case "23505":
+				return ErrDuplicate
+			}
+		}
+		return fmt.Errorf("creating ownership request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Request, error) {
+	req, err := scanRequest(r.db.QueryRow(ctx, selectRequest+` WHERE r.id::text = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting ownership request: %w", err)
+	}
+	return req, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter ListFilter) ([]*Request, int, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.AssetID != "" {
+		add("r.asset_id = $%d", filter.AssetID)
+	}
+	if filter.RequestedBy != "" {
+		add("r.requested_by::text = $%d", filter.RequestedBy)
+	}
+	if filter.OwnedBy != "" {
+		add(`r.asset_id IN (
+			SELECT ao.asset_id FROM asset_owners ao
+			LEFT JOIN team_members tm ON tm.team_id = ao.team_id
+			WHERE ao.user_id::text = $%[1]d OR tm.user_id::text = $%[1]d)`, filter.OwnedBy)
+	}
+	if filter.Status != "" {
+		add("r.status = $%d", filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_ownership_requests r`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting ownership requests: %w", err)
+	}
+
+	n := len(args)
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.Query(ctx, selectRequest+where+fmt.Sprintf(` ORDER BY r.created_at DESC LIMIT $%d OFFSET $%d`, n+1, n+2), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing ownership requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []*Request{}
+	for rows.Next() {
+		req, err := scanRequest(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning ownership request: %w", err)
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating ownership requests: %w", err)
+	}
+
+	return requests, total, nil
+}
+
+func (r *PostgresRepository) Decide(ctx context.Context, id string, status Status, decidedBy, note string) (*Request, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE asset_ownership_requests
+		SET status = $2, decided_by = $3, decision_note = $4, decided_at = NOW()
+		WHERE id::text = $1 AND status = 'pending'`,
+		id, status, decidedBy, note)
+	if err != nil {
+		return nil, fmt.Errorf("deciding ownership request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotPending
+	}
+	return r.Get(ctx, id)
+}
+
+func scanRequest(row pgx.Row) (*Request, error) {
+	var req Request
+	err := row.Scan(
+		&req.ID, &req.AssetID, &req.AssetName, &req.Kind, &req.RequestedBy, &req.RequesterName,
+		&req.TeamID, &req.TeamName, &req.Reason, &req.Status,
+		&req.DecidedBy, &req.DeciderName, &req.DecisionNote, &req.DecidedAt, &req.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
@@ -0,0 +1,57 @@
+package ownervacancy
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultCheckInterval = 24 * time.Hour
+
+// Monitor periodically alerts admins about ownership vacancies.
+type Monitor struct {
+	task *background.SingletonTask
+}
+
+// MonitorConfig configures the monitor.
+type MonitorConfig struct {
+	// Interval between checks. Default: 24 hours.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewMonitor creates a monitor for svc.
+func NewMonitor(svc *Service, config *MonitorConfig) *Monitor {
+	if config == nil {
+		config = &MonitorConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultCheckInterval
+	}
+
+	return &Monitor{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "ownership-vacancies",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.CheckOnce(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins the periodic check loop.
+func (m *Monitor) Start(ctx context.Context) {
+	m.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the monitor.
+func (m *Monitor) Stop() {
+	m.task.Stop()
+}
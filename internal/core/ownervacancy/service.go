@@ -0,0 +1,164 @@
+// Package ownervacancy finds assets and data products whose owners have all
+// been deactivated or left their team, and alerts admins so ownership never
+// lapses unnoticed.
+package ownervacancy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	EntityAsset       = "asset"
+	EntityDataProduct = "data_product"
+
+	// ReasonDeactivated is given for a user owner whose account was
+	// deactivated.
+	ReasonDeactivated = "deactivated"
+	// ReasonNoActiveMembers is given for a team owner whose members have
+	// all left or been deactivated.
+	ReasonNoActiveMembers = "no_active_members"
+
+	// DefaultRemindAfter is how long after the last alert admins are
+	// reminded of a vacancy that is still open.
+	DefaultRemindAfter = 7 * 24 * time.Hour
+)
+
+// Owner is a former owner of a vacant asset or data product.
+type Owner struct {
+	Type   string `json:"type" enums:"user,team"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason" enums:"deactivated,no_active_members"`
+} // @name VacantOwner
+
+// Vacancy is an asset or data product that has owners, none of them
+// active.
+type Vacancy struct {
+	EntityType string `json:"entity_type" enums:"asset,data_product"`
+	EntityID   string `json:"entity_id"`
+	Name       string `json:"name"`
+	// MRN is set for assets.
+	MRN    string  `json:"mrn,omitempty"`
+	Owners []Owner `json:"owners"`
+	// DetectedAt and NotifiedAt are unset until a check has alerted admins.
+	DetectedAt *time.Time `json:"detected_at,omitempty"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	Reminders  int        `json:"reminders"`
+} // @name OwnershipVacancy
+
+// key identifies the entity a vacancy is for.
+func (v Vacancy) key() string {
+	return v.EntityType + "/" + v.EntityID
+}
+
+// Report lists every open vacancy.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Total       int       `json:"total"`
+	Vacancies   []Vacancy `json:"vacancies"`
+} // @name OwnershipVacancyReport
+
+// Notifier tells admins about vacancies. reminder is true for vacancies
+// they were already told about.
+type Notifier interface {
+	NotifyVacancies(ctx context.Context, vacancies []Vacancy, reminder bool)
+}
+
+// Service reports on and alerts about ownership vacancies.
+type Service struct {
+	repo        Repository
+	remindAfter time.Duration
+	notifier    Notifier
+	now         func() time.Time
+}
+
+// NewService creates a vacancy service. Open vacancies are re-notified
+// every remindAfter; 0 disables reminders.
+func NewService(repo Repository, remindAfter time.Duration) *Service {
+	if remindAfter < 0 {
+		remindAfter = 0
+	}
+	return &Service{
+		repo:        repo,
+		remindAfter: remindAfter,
+		now:         time.Now,
+	}
+}
+
+// SetNotifier registers where alerts are sent.
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// Report returns every open vacancy.
+func (s *Service) Report(ctx context.Context) (*Report, error) {
+	vacancies, err := s.repo.ListVacancies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vacancies: %w", err)
+	}
+	if vacancies == nil {
+		vacancies = []Vacancy{}
+	}
+	return &Report{
+		GeneratedAt: s.now(),
+		Total:       len(vacancies),
+		Vacancies:   vacancies,
+	}, nil
+}
+
+// CheckOnce alerts admins about vacancies found since the last check and
+// reminds them of those still open remindAfter after the last alert.
+// Vacancies that were filled are forgotten, so they are alerted afresh if
+// they lapse again. It returns the number of vacancies notified.
+func (s *Service) CheckOnce(ctx context.Context) (int, error) {
+	vacancies, err := s.repo.ListVacancies(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing vacancies: %w", err)
+	}
+
+	open := make([]string, 0, len(vacancies))
+	var fresh, due []Vacancy
+	now := s.now()
+	for _, v := range vacancies {
+		open = append(open, v.key())
+		switch {
+		case v.NotifiedAt == nil:
+			fresh = append(fresh, v)
+		case s.remindAfter > 0 && now.Sub(*v.NotifiedAt) >= s.remindAfter:
+			due = append(due, v)
+		}
+	}
+
+	if err := s.repo.ForgetFilled(ctx, open); err != nil {
+		return 0, fmt.Errorf("forgetting filled vacancies: %w", err)
+	}
+
+	sent := 0
+	for _, batch := range []struct {
+		vacancies []Vacancy
+		reminder  bool
+	}{{fresh, false}, {due, true}} {
+		if len(batch.vacancies) == 0 {
+			continue
+		}
+		if s.notifier != nil {
+			s.notifier.NotifyVacancies(ctx, batch.vacancies, batch.reminder)
+		}
+		if err := s.repo.MarkNotified(ctx, batch.vacancies, now); err != nil {
+			return sent, fmt.Errorf("recording vacancy alerts: %w", err)
+		}
+		sent += len(batch.vacancies)
+	}
+
+	if sent > 0 {
+		log.Warn().
+			Int("new", len(fresh)).
+			Int("reminders", len(due)).
+			Msg("Assets and data products have no active owner")
+	}
+	return sent, nil
+}
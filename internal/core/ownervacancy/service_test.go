@@ -0,0 +1,182 @@
+package ownervacancy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type alert struct {
+	notifiedAt time.Time
+	reminders  int
+}
+
+type fakeRepo struct {
+	vacancies []Vacancy
+	alerts    map[string]alert
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{alerts: map[string]alert{}}
+}
+
+func (r *fakeRepo) ListVacancies(context.Context) ([]Vacancy, error) {
+	result := make([]Vacancy, 0, len(r.vacancies))
+	for _, v := range r.vacancies {
+		if a, ok := r.alerts[v.key()]; ok {
+			notifiedAt := a.notifiedAt
+			v.NotifiedAt = &notifiedAt
+			v.Reminders = a.reminders
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (r *fakeRepo) MarkNotified(_ context.Context, vacancies []Vacancy, at time.Time) error {
+	for _, v := range vacancies {
+		a, ok := r.alerts[v.key()]
+		if ok {
+			a.reminders++
+		}
+		a.notifiedAt = at
+		r.alerts[v.key()] = a
+	}
+	return nil
+}
+
+func (r *fakeRepo) ForgetFilled(_ context.Context, open []string) error {
+	keep := make(map[string]bool, len(open))
+	for _, key := range open {
+		keep[key] = true
+	}
+	for key := range r.alerts {
+		if !keep[key] {
+			delete(r.alerts, key)
+		}
+	}
+	return nil
+}
+
+type notified struct {
+	names    []string
+	reminder bool
+}
+
+type fakeNotifier struct {
+	calls []notified
+}
+
+func (n *fakeNotifier) NotifyVacancies(_ context.Context, vacancies []Vacancy, reminder bool) {
+	call := notified{reminder: reminder}
+	for _, v := range vacancies {
+		call.names = append(call.names, v.Name)
+	}
+	n.calls = append(n.calls, call)
+}
+
+func vacancy(entityType, id, name string) Vacancy {
+	return Vacancy{
+		EntityType: entityType,
+		EntityID:   id,
+		Name:       name,
+		Owners:     []Owner{{Type: "user", ID: "u1", Name: "Alice", Reason: ReasonDeactivated}},
+	}
+}
+
+func TestCheckOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	notifier := &fakeNotifier{}
+	svc := NewService(repo, 7*24*time.Hour)
+	svc.SetNotifier(notifier)
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	repo.vacancies = []Vacancy{
+		vacancy(EntityAsset, "a1", "orders"),
+		vacancy(EntityDataProduct, "p1", "Revenue"),
+	}
+
+	sent, err := svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	require.Len(t, notifier.calls, 1)
+	assert.Equal(t, notified{names: []string{"orders", "Revenue"}}, notifier.calls[0])
+
+	// Already alerted and not yet due a reminder.
+	now = now.Add(24 * time.Hour)
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, notifier.calls, 1)
+
+	// A new vacancy is alerted alongside the open ones.
+	repo.vacancies = append(repo.vacancies, vacancy(EntityAsset, "a2", "customers"))
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, notifier.calls, 2)
+	assert.Equal(t, notified{names: []string{"customers"}}, notifier.calls[1])
+
+	// A week after the first alert orders is reminded, and the product
+	// whose owner was replaced is forgotten.
+	repo.vacancies = repo.vacancies[:1:1]
+	repo.vacancies = append(repo.vacancies, vacancy(EntityAsset, "a2", "customers"))
+	now = now.Add(6 * 24 * time.Hour)
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, notifier.calls, 3)
+	assert.Equal(t, notified{names: []string{"orders"}, reminder: true}, notifier.calls[2])
+	assert.Equal(t, 1, repo.alerts["asset/a1"].reminders)
+	assert.NotContains(t, repo.alerts, "data_product/p1")
+
+	// When the product lapses again it is alerted afresh.
+	repo.vacancies = append(repo.vacancies, vacancy(EntityDataProduct, "p1", "Revenue"))
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, notified{names: []string{"Revenue"}}, notifier.calls[3])
+}
+
+func TestCheckOnceWithoutReminders(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	notifier := &fakeNotifier{}
+	svc := NewService(repo, 0)
+	svc.SetNotifier(notifier)
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	repo.vacancies = []Vacancy{vacancy(EntityAsset, "a1", "orders")}
+
+	_, err := svc.CheckOnce(ctx)
+	require.NoError(t, err)
+
+	now = now.Add(365 * 24 * time.Hour)
+	sent, err := svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, notifier.calls, 1)
+}
+
+func TestReport(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo, DefaultRemindAfter)
+
+	report, err := svc.Report(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Total)
+	assert.NotNil(t, report.Vacancies)
+
+	repo.vacancies = []Vacancy{vacancy(EntityAsset, "a1", "orders")}
+	report, err = svc.Report(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Total)
+	assert.Equal(t, "orders", report.Vacancies[0].Name)
+}
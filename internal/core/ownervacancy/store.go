@@ -0,0 +1,138 @@
+package ownervacancy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository finds vacancies and remembers the alerts sent for them.
+type Repository interface {
+	// ListVacancies returns the assets and data products that have owners,
+	// none of them active, with the alert last sent for each.
+	ListVacancies(ctx context.Context) ([]Vacancy, error)
+	// MarkNotified records that vacancies were alerted at at.
+	MarkNotified(ctx context.Context, vacancies []Vacancy, at time.Time) error
+	// ForgetFilled drops the alerts of vacancies not in open.
+	ForgetFilled(ctx context.Context, open []string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// A user owner is active while their account is. A team owner is active
+// while it has an active member. Stub and archived assets are skipped.
+const listVacanciesQuery = `
+	WITH owners AS (
+		SELECT 'asset' AS entity_type, ao.asset_id::text AS entity_id, ao.user_id, ao.team_id
+		FROM asset_owners ao
+		JOIN assets a ON a.id = ao.asset_id
+		WHERE a.is_stub = FALSE AND a.archived_at IS NULL AND a.lifecycle_status <> 'archived'
+		UNION ALL
+		SELECT 'data_product', dpo.data_product_id::text, dpo.user_id, dpo.team_id
+		FROM data_product_owners dpo
+	),
+	owner_state AS (
+		SELECT o.entity_type, o.entity_id,
+			CASE WHEN o.user_id IS NOT NULL THEN 'user' ELSE 'team' END AS owner_type,
+			COALESCE(o.user_id, o.team_id)::text AS owner_id,
+			COALESCE(u.name, t.name, '') AS owner_name,
+			CASE
+				WHEN o.user_id IS NOT NULL THEN u.active
+				ELSE EXISTS (
+					SELECT 1 FROM team_members tm
+					JOIN users mu ON mu.id = tm.user_id
+					WHERE tm.team_id = o.team_id AND mu.active
+				)
+			END AS active
+		FROM owners o
+		LEFT JOIN users u ON u.id = o.user_id
+		LEFT JOIN teams t ON t.id = o.team_id
+	),
+	vacant AS (
+		SELECT entity_type, entity_id
+		FROM owner_state
+		GROUP BY entity_type, entity_id
+		HAVING NOT bool_or(COALESCE(active, FALSE))
+	)
+	SELECT s.entity_type, s.entity_id, COALESCE(a.name, dp.name, ''), COALESCE(a.mrn, ''),
+		s.owner_type, s.owner_id, s.owner_name,
+		v.detected_at, v.notified_at, COALESCE(v.reminders, 0)
+	FROM owner_state s
+	JOIN vacant va ON va.entity_type = s.entity_type AND va.entity_id = s.entity_id
+	LEFT JOIN assets a ON s.entity_type = 'asset' AND a.id = s.entity_id
+	LEFT JOIN data_products dp ON s.entity_type = 'data_product' AND dp.id::text = s.entity_id
+	LEFT JOIN ownership_vacancies v ON v.entity_type = s.entity_type AND v.entity_id = s.entity_id
+	ORDER BY v.detected_at NULLS LAST, s.entity_type, 3, s.entity_id, s.owner_type, s.owner_name`
+
+func (r *PostgresRepository) ListVacancies(ctx context.Context) ([]Vacancy, error) {
+	rows, err := r.db.Query(ctx, listVacanciesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying vacancies: %w", err)
+	}
+	defer rows.Close()
+
+	var vacancies []Vacancy
+	for rows.Next() {
+		var v Vacancy
+		var owner Owner
+		if err := rows.Scan(&v.EntityType, &v.EntityID, &v.Name, &v.MRN,
+			&owner.Type, &owner.ID, &owner.Name,
+			&v.DetectedAt, &v.NotifiedAt, &v.Reminders); err != nil {
+			return nil, fmt.Errorf("scanning vacancy: %w", err)
+		}
+		owner.Reason = ReasonDeactivated
+		if owner.Type == "team" {
+			owner.Reason = ReasonNoActiveMembers
+		}
+
+		if n := len(vacancies); n > 0 && vacancies[n-1].key() == v.key() {
+			vacancies[n-1].Owners = append(vacancies[n-1].Owners, owner)
+			continue
+		}
+		v.Owners = []Owner{owner}
+		vacancies = append(vacancies, v)
+	}
+	return vacancies, rows.Err()
+}
+
+func (r *PostgresRepository) MarkNotified(ctx context.Context, vacancies []Vacancy, at time.Time) error {
+	types := make([]string, 0, len(vacancies))
+	ids := make([]string, 0, len(vacancies))
+	for _, v := range vacancies {
+		types = append(types, v.EntityType)
+		ids = append(ids, v.EntityID)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO ownership_vacancies (entity_type, entity_id, detected_at, notified_at)
+		SELECT t, id, $3, $3 FROM unnest($1::text[], $2::text[]) AS v(t, id)
+		ON CONFLICT (entity_type, entity_id) DO UPDATE
+		SET notified_at = EXCLUDED.notified_at,
+			reminders = ownership_vacancies.reminders + 1`,
+		types, ids, at)
+	if err != nil {
+		return fmt.Errorf("upserting vacancies: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ForgetFilled(ctx context.Context, open []string) error {
+	if open == nil {
+		open = []string{}
+	}
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM ownership_vacancies
+		WHERE NOT (entity_type || '/' || entity_id = ANY($1::text[]))`, open)
+	if err != nil {
+		return fmt.Errorf("deleting filled vacancies: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,656 @@
+package policytag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/rs/zerolog/log"
+)
+
+const DefaultPropagationInterval = 15 * time.Minute
+
+// Override audit actions.
+const (
+	OverrideCleared  = "cleared"
+	OverrideRestored = "restored"
+)
+
+var ErrOverrideNotFound = errors.New("policy tag override not found")
+
+// InheritedAssignment is a column policy tag propagated along column lineage
+// from a tagged upstream column.
+type InheritedAssignment struct {
+	AssetID   string    `json:"asset_id"`
+	Column    string    `json:"column"`
+	PolicyTag PolicyTag `json:"policy_tag"`
+	// Origin is the directly tagged column the tag was inherited from.
+	Origin     ColumnOrigin `json:"origin"`
+	Hops       int          `json:"hops"`
+	ComputedAt time.Time    `json:"computed_at"`
+} // @name InheritedPolicyTag
+
+// ColumnOrigin identifies the column an inherited tag came from.
+type ColumnOrigin struct {
+	AssetID string `json:"asset_id"`
+	MRN     string `json:"mrn"`
+	Column  string `json:"column"`
+} // @name PolicyTagColumnOrigin
+
+// Override clears a tag a column would otherwise inherit. Columns derived
+// from it no longer inherit the tag through it either.
+type Override struct {
+	AssetID   string    `json:"asset_id"`
+	Column    string    `json:"column"`
+	PolicyTag PolicyTag `json:"policy_tag"`
+	Reason    *string   `json:"reason,omitempty"`
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+} // @name PolicyTagOverride
+
+// OverrideAuditEntry records an override being set or removed.
+type OverrideAuditEntry struct {
+	ID            string    `json:"id"`
+	AssetID       string    `json:"asset_id"`
+	Column        string    `json:"column"`
+	PolicyTagID   *string   `json:"policy_tag_id,omitempty"`
+	PolicyTagName string    `json:"policy_tag_name"`
+	Action        string    `json:"action"`
+	Reason        *string   `json:"reason,omitempty"`
+	Actor         *string   `json:"actor,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+} // @name PolicyTagOverrideAuditEntry
+
+type OverrideInput struct {
+	PolicyTagID string `json:"policy_tag_id"`
+	Column      string `json:"column"`
+	Reason      string `json:"reason"`
+} // @name PolicyTagOverrideInput
+
+// PropagationResult summarises a recomputation.
+type PropagationResult struct {
+	Sources    int       `json:"sources"`
+	Inherited  int       `json:"inherited"`
+	Overrides  int       `json:"overrides"`
+	ComputedAt time.Time `json:"computed_at"`
+} // @name PolicyTagPropagationResult
+
+// TaggedColumn is a policy tag on one column of an asset.
+type TaggedColumn struct {
+	AssetID string
+	MRN     string
+	Column  string
+	TagID   string
+}
+
+// ColumnEdge is a column lineage edge, source column feeding target column.
+type ColumnEdge struct {
+	SourceMRN     string
+	SourceColumn  string
+	TargetAssetID string
+	TargetMRN     string
+	TargetColumn  string
+}
+
+// PropagationGraph is everything a recomputation needs: directly tagged
+// columns, overrides clearing inherited tags and the column lineage.
+type PropagationGraph struct {
+	Tagged  []TaggedColumn
+	Cleared []TaggedColumn
+	Edges   []ColumnEdge
+}
+
+// PropagatedTag is a tag inherited by Column from Origin, Hops edges
+// upstream.
+type PropagatedTag struct {
+	Column TaggedColumn
+	Origin TaggedColumn
+	Hops   int
+}
+
+type columnKey struct {
+	mrn    string
+	column string
+}
+
+func keyOf(mrn, column string) columnKey {
+	return columnKey{mrn: mrn, column: strings.ToLower(column)}
+}
+
+// Propagate walks column lineage downstream from every directly tagged
+// column. A column inherits each tag reachable upstream of it unless it
+// carries the tag directly or has been cleared of it; cleared columns also
+// stop the tag reaching columns derived from them. Columns are matched
+// case-insensitively and each inherits a tag from its nearest origin.
+func Propagate(graph *PropagationGraph) []PropagatedTag {
+	edges := append([]ColumnEdge(nil), graph.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].TargetMRN != edges[j].TargetMRN {
+			return edges[i].TargetMRN < edges[j].TargetMRN
+		}
+		return edges[i].TargetColumn < edges[j].TargetColumn
+	})
+	downstream := make(map[columnKey][]ColumnEdge)
+	for _, e := range edges {
+		k := keyOf(e.SourceMRN, e.SourceColumn)
+		downstream[k] = append(downstream[k], e)
+	}
+
+	cleared := make(map[string]map[columnKey]bool)
+	for _, c := range graph.Cleared {
+		if cleared[c.TagID] == nil {
+			cleared[c.TagID] = make(map[columnKey]bool)
+		}
+		cleared[c.TagID][keyOf(c.MRN, c.Column)] = true
+	}
+
+	byTag := make(map[string][]TaggedColumn)
+	for _, t := range graph.Tagged {
+		if t.Column != "" {
+			byTag[t.TagID] = append(byTag[t.TagID], t)
+		}
+	}
+	tagIDs := make([]string, 0, len(byTag))
+	for id := range byTag {
+		tagIDs = append(tagIDs, id)
+	}
+	sort.Strings(tagIDs)
+
+	type step struct {
+		column TaggedColumn
+		origin TaggedColumn
+		hops   int
+	}
+
+	var propagated []PropagatedTag
+	for _, tagID := range tagIDs {
+		sources := byTag[tagID]
+		sort.Slice(sources, func(i, j int) bool {
+			if sources[i].MRN != sources[j].MRN {
+				return sources[i].MRN < sources[j].MRN
+			}
+			return sources[i].Column < sources[j].Column
+		})
+
+		visited := make(map[columnKey]bool)
+		queue := make([]step, 0, len(sources))
+		for _, s := range sources {
+			k := keyOf(s.MRN, s.Column)
+			if !visited[k] {
+				visited[k] = true
+				queue = append(queue, step{column: s, origin: s})
+			}
+		}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, e := range downstream[keyOf(current.column.MRN, current.column.Column)] {
+				k := keyOf(e.TargetMRN, e.TargetColumn)
+				if visited[k] || cleared[tagID][k] {
+					continue
+				}
+				visited[k] = true
+
+				next := step{
+					column: TaggedColumn{AssetID: e.TargetAssetID, MRN: e.TargetMRN, Column: e.TargetColumn, TagID: tagID},
+					origin: current.origin,
+					hops:   current.hops + 1,
+				}
+				propagated = append(propagated, PropagatedTag{Column: next.column, Origin: next.origin, Hops: next.hops})
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return propagated
+}
+
+// RecomputePropagation rebuilds every inherited column tag from the current
+// assignments, overrides and column lineage.
+func (s *Service) RecomputePropagation(ctx context.Context) (*PropagationResult, error) {
+	s.propagateMu.Lock()
+	defer s.propagateMu.Unlock()
+
+	graph, err := s.repo.LoadPropagationGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	propagated := Propagate(graph)
+	if err := s.repo.ReplaceInherited(ctx, propagated); err != nil {
+		return nil, err
+	}
+
+	return &PropagationResult{
+		Sources:    len(graph.Tagged),
+		Inherited:  len(propagated),
+		Overrides:  len(graph.Cleared),
+		ComputedAt: time.Now(),
+	}, nil
+}
+
+func (s *Service) ListInherited(ctx context.Context, assetID string) ([]*InheritedAssignment, error) {
+	return s.repo.ListInherited(ctx, assetID)
+}
+
+func (s *Service) ListOverrides(ctx context.Context, assetID string) ([]*Override, error) {
+	return s.repo.ListOverrides(ctx, assetID)
+}
+
+// ClearInherited stops a column inheriting a tag and recomputes propagation
+// so columns derived from it are updated too.
+func (s *Service) ClearInherited(ctx context.Context, assetID string, input OverrideInput, actor *string) (*Override, error) {
+	input.Column = strings.TrimSpace(input.Column)
+	input.Reason = strings.TrimSpace(input.Reason)
+	if assetID == "" || input.PolicyTagID == "" || input.Column == "" {
+		return nil, &ValidationError{Message: "asset_id, policy_tag_id and column are required"}
+	}
+	if len(input.Column) > 255 {
+		return nil, &ValidationError{Message: "column must be at most 255 characters"}
+	}
+	if input.Reason == "" {
+		return nil, &ValidationError{Message: "reason is required when clearing an inherited policy tag"}
+	}
+
+	if _, err := s.repo.Get(ctx, input.PolicyTagID); err != nil {
+		return nil, err
+	}
+
+	override, err := s.repo.CreateOverride(ctx, assetID, input.PolicyTagID, input.Column, input.Reason, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recomputeAfterOverride(ctx)
+	return override, nil
+}
+
+// RestoreInherited removes an override so the column inherits the tag again.
+func (s *Service) RestoreInherited(ctx context.Context, assetID, tagID, column, reason string, actor *string) error {
+	if err := s.repo.DeleteOverride(ctx, assetID, tagID, strings.TrimSpace(column), strings.TrimSpace(reason), actor); err != nil {
+		return err
+	}
+
+	s.recomputeAfterOverride(ctx)
+	return nil
+}
+
+// ListOverrideAudit returns override changes, newest first. An empty assetID
+// lists changes across all assets.
+func (s *Service) ListOverrideAudit(ctx context.Context, assetID string, limit, offset int) ([]*OverrideAuditEntry, int, error) {
+	if limit <= 0 {
+		limit = 50
+	} else if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.ListOverrideAudit(ctx, assetID, limit, offset)
+}
+
+// recomputeAfterOverride applies an override change straight away. Failures
+// are left to the next scheduled recomputation.
+func (s *Service) recomputeAfterOverride(ctx context.Context) {
+	if _, err := s.RecomputePropagation(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to recompute policy tag propagation after override change")
+	}
+}
+
+// Propagator periodically recomputes inherited column tags so they follow
+// changes to assignments and column lineage.
+type Propagator struct {
+	task *background.SingletonTask
+}
+
+// PropagatorConfig configures the propagator.
+type PropagatorConfig struct {
+	// Interval between recomputations. Default: 15 minutes.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewPropagator creates a propagator for svc.
+func NewPropagator(svc *Service, config *PropagatorConfig) *Propagator {
+	if config == nil {
+		config = &PropagatorConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultPropagationInterval
+	}
+
+	return &Propagator{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "policy-tag-propagation",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				result, err := svc.RecomputePropagation(ctx)
+				if err != nil {
+					return err
+				}
+				log.Debug().
+					Int("sources", result.Sources).
+					Int("inherited", result.Inherited).
+					Msg("Recomputed policy tag propagation")
+				return nil
+			},
+		}),
+	}
+}
+
+// Start begins the periodic recomputation loop.
+func (p *Propagator) Start(ctx context.Context) {
+	p.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the propagator.
+func (p *Propagator) Stop() {
+	p.task.Stop()
+}
+
+func (r *PostgresRepository) LoadPropagationGraph(ctx context.Context) (*PropagationGraph, error) {
+	graph := &PropagationGraph{}
+
+	tagged, err := r.queryTaggedColumns(ctx, `
+		SELECT apt.asset_id, a.mrn, apt.column_name, apt.policy_tag_id
+		FROM asset_policy_tags apt
+		JOIN assets a ON a.id = apt.asset_id
+		WHERE apt.column_name <> ''`)
+	if err != nil {
+		return nil, fmt.Errorf("loading tagged columns: %w", err)
+	}
+	graph.Tagged = tagged
+
+	cleared, err := r.queryTaggedColumns(ctx, `
+		SELECT o.asset_id, a.mrn, o.column_name, o.policy_tag_id
+		FROM policy_tag_overrides o
+		JOIN assets a ON a.id = o.asset_id`)
+	if err != nil {
+		return nil, fmt.Errorf("loading policy tag overrides: %w", err)
+	}
+	graph.Cleared = cleared
+
+	rows, err := r.db.Query(ctx, `
+		SELECT c.source_mrn, c.source_column, t.id, c.target_mrn, c.target_column
+		FROM column_lineage c
+		JOIN assets t ON t.mrn = c.target_mrn`)
+	if err != nil {
+		return nil, fmt.Errorf("loading column lineage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e ColumnEdge
+		if err := rows.Scan(&e.SourceMRN, &e.SourceColumn, &e.TargetAssetID, &e.TargetMRN, &e.TargetColumn); err != nil {
+			return nil, fmt.Errorf("scanning column lineage edge: %w", err)
+		}
+		graph.Edges = append(graph.Edges, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating column lineage: %w", err)
+	}
+
+	return graph, nil
+}
+
+func (r *PostgresRepository) queryTaggedColumns(ctx context.Context, query string) ([]TaggedColumn, error) {
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []TaggedColumn
+	for rows.Next() {
+		var c TaggedColumn
+		if err := rows.Scan(&c.AssetID, &c.MRN, &c.Column, &c.TagID); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// ReplaceInherited swaps the stored inherited tags for propagated in one
+// transaction, so readers never see a partial recomputation.
+func (r *PostgresRepository) ReplaceInherited(ctx context.Context, propagated []PropagatedTag) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM inherited_policy_tags`); err != nil {
+		return fmt.Errorf("clearing inherited policy tags: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"inherited_policy_tags"},
+		[]string{"asset_id", "policy_tag_id", "column_name", "origin_asset_id", "origin_column", "hops", "computed_at"},
+		pgx.CopyFromSlice(len(propagated), func(i int) ([]any, error) {
+			p := propagated[i]
+			return []any{p.Column.AssetID, p.Column.TagID, p.Column.Column, p.Origin.AssetID, p.Origin.Column, p.Hops, now}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("writing inherited policy tags: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing inherited policy tags: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListInherited(ctx context.Context, assetID string) ([]*InheritedAssignment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT ipt.asset_id, ipt.column_name, ipt.origin_asset_id, oa.mrn, ipt.origin_column, ipt.hops, ipt.computed_at,
+		       pt.id, pt.name, pt.description, pt.masking_strategy, pt.masking_params, pt.restricted,
+		       pt.created_by, pt.created_at, pt.updated_at
+		FROM inherited_policy_tags ipt
+		JOIN policy_tags pt ON pt.id = ipt.policy_tag_id
+		JOIN assets oa ON oa.id = ipt.origin_asset_id
+		WHERE ipt.asset_id = $1
+		ORDER BY ipt.column_name ASC, pt.name ASC`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("querying inherited policy tags: %w", err)
+	}
+	defer rows.Close()
+
+	inherited := []*InheritedAssignment{}
+	for rows.Next() {
+		var a InheritedAssignment
+		var paramsRaw []byte
+		if err := rows.Scan(
+			&a.AssetID, &a.Column, &a.Origin.AssetID, &a.Origin.MRN, &a.Origin.Column, &a.Hops, &a.ComputedAt,
+			&a.PolicyTag.ID, &a.PolicyTag.Name, &a.PolicyTag.Description, &a.PolicyTag.MaskingStrategy,
+			&paramsRaw, &a.PolicyTag.Restricted, &a.PolicyTag.CreatedBy, &a.PolicyTag.CreatedAt, &a.PolicyTag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning inherited policy tag: %w", err)
+		}
+		if err := unmarshalParams(paramsRaw, &a.PolicyTag.MaskingParams); err != nil {
+			return nil, err
+		}
+		inherited = append(inherited, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating inherited policy tags: %w", err)
+	}
+
+	return inherited, nil
+}
+
+// CreateOverride records an override, or updates the reason of an existing
+// one, and audits the change.
+func (r *PostgresRepository) CreateOverride(ctx context.Context, assetID, tagID, column, reason string, actor *string) (*Override, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO policy_tag_overrides (asset_id, policy_tag_id, column_name, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (asset_id, policy_tag_id, lower(column_name))
+		DO UPDATE SET reason = EXCLUDED.reason, created_by = EXCLUDED.created_by, created_at = NOW()`,
+		assetID, tagID, column, reason, actor)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, &ValidationError{Message: "asset not found"}
+		}
+		return nil, fmt.Errorf("creating policy tag override: %w", err)
+	}
+
+	if err := auditOverride(ctx, tx, assetID, tagID, column, OverrideCleared, reason, actor); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing policy tag override: %w", err)
+	}
+
+	overrides, err := r.listOverrides(ctx, `WHERE o.asset_id = $1 AND o.policy_tag_id = $2 AND lower(o.column_name) = lower($3)`, assetID, tagID, column)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return nil, ErrOverrideNotFound
+	}
+	return overrides[0], nil
+}
+
+// DeleteOverride removes an override and audits the change.
+func (r *PostgresRepository) DeleteOverride(ctx context.Context, assetID, tagID, column, reason string, actor *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var stored string
+	err = tx.QueryRow(ctx, `
+		DELETE FROM policy_tag_overrides
+		WHERE asset_id = $1 AND policy_tag_id = $2 AND lower(column_name) = lower($3)
+		RETURNING column_name`,
+		assetID, tagID, column).Scan(&stored)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrOverrideNotFound
+		}
+		return fmt.Errorf("deleting policy tag override: %w", err)
+	}
+
+	if err := auditOverride(ctx, tx, assetID, tagID, stored, OverrideRestored, reason, actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing policy tag override removal: %w", err)
+	}
+	return nil
+}
+
+func auditOverride(ctx context.Context, tx pgx.Tx, assetID, tagID, column, action, reason string, actor *string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO policy_tag_override_audit (asset_id, column_name, policy_tag_id, policy_tag_name, action, reason, actor)
+		SELECT $1, $2, id, name, $3, NULLIF($4, ''), $5
+		FROM policy_tags WHERE id = $6`,
+		assetID, column, action, reason, actor, tagID)
+	if err != nil {
+		return fmt.Errorf("auditing policy tag override: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListOverrides(ctx context.Context, assetID string) ([]*Override, error) {
+	return r.listOverrides(ctx, `WHERE o.asset_id = $1`, assetID)
+}
+
+func (r *PostgresRepository) listOverrides(ctx context.Context, where string, args ...interface{}) ([]*Override, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT o.asset_id, o.column_name, o.reason, o.created_by, o.created_at,
+		       pt.id, pt.name, pt.description, pt.masking_strategy, pt.masking_params, pt.restricted,
+		       pt.created_by, pt.created_at, pt.updated_at
+		FROM policy_tag_overrides o
+		JOIN policy_tags pt ON pt.id = o.policy_tag_id
+		`+where+`
+		ORDER BY o.column_name ASC, pt.name ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying policy tag overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []*Override{}
+	for rows.Next() {
+		var o Override
+		var paramsRaw []byte
+		if err := rows.Scan(
+			&o.AssetID, &o.Column, &o.Reason, &o.CreatedBy, &o.CreatedAt,
+			&o.PolicyTag.ID, &o.PolicyTag.Name, &o.PolicyTag.Description, &o.PolicyTag.MaskingStrategy,
+			&paramsRaw, &o.PolicyTag.Restricted, &o.PolicyTag.CreatedBy, &o.PolicyTag.CreatedAt, &o.PolicyTag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning policy tag override: %w", err)
+		}
+		if err := unmarshalParams(paramsRaw, &o.PolicyTag.MaskingParams); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, &o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating policy tag overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func (r *PostgresRepository) ListOverrideAudit(ctx context.Context, assetID string, limit, offset int) ([]*OverrideAuditEntry, int, error) {
+	const where = `WHERE ($1 = '' OR asset_id = $1)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM policy_tag_override_audit `+where, assetID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting policy tag override audit: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, asset_id, column_name, policy_tag_id, policy_tag_name, action, reason, actor, created_at
+		FROM policy_tag_override_audit
+		`+where+`
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("querying policy tag override audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*OverrideAuditEntry{}
+	for rows.Next() {
+		var e OverrideAuditEntry
+		if err := rows.Scan(&e.ID, &e.AssetID, &e.Column, &e.PolicyTagID, &e.PolicyTagName, &e.Action, &e.Reason, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scanning policy tag override audit: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating policy tag override audit: %w", err)
+	}
+
+	return entries, total, nil
+}
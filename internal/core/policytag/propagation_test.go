@@ -0,0 +1,97 @@
+package policytag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func edge(source, sourceColumn, target, targetColumn string) ColumnEdge {
+	return ColumnEdge{
+		SourceMRN:     source,
+		SourceColumn:  sourceColumn,
+		TargetAssetID: "id-" + target,
+		TargetMRN:     target,
+		TargetColumn:  targetColumn,
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	pii := TaggedColumn{AssetID: "id-raw", MRN: "raw", Column: "email", TagID: "pii"}
+
+	t.Run("follows column lineage downstream", func(t *testing.T) {
+		propagated := Propagate(&PropagationGraph{
+			Tagged: []TaggedColumn{pii},
+			Edges: []ColumnEdge{
+				edge("raw", "email", "staging", "Email"),
+				edge("staging", "EMAIL", "mart", "contact"),
+				edge("raw", "id", "staging", "id"),
+			},
+		})
+
+		require.Len(t, propagated, 2)
+		assert.Equal(t, TaggedColumn{AssetID: "id-staging", MRN: "staging", Column: "Email", TagID: "pii"}, propagated[0].Column)
+		assert.Equal(t, 1, propagated[0].Hops)
+		assert.Equal(t, "contact", propagated[1].Column.Column)
+		assert.Equal(t, pii, propagated[1].Origin)
+		assert.Equal(t, 2, propagated[1].Hops)
+	})
+
+	t.Run("cleared columns stop propagation", func(t *testing.T) {
+		propagated := Propagate(&PropagationGraph{
+			Tagged:  []TaggedColumn{pii},
+			Cleared: []TaggedColumn{{AssetID: "id-staging", MRN: "staging", Column: "EMAIL_HASH", TagID: "pii"}},
+			Edges: []ColumnEdge{
+				edge("raw", "email", "staging", "email_hash"),
+				edge("staging", "email_hash", "mart", "email_hash"),
+				edge("raw", "email", "audit", "email"),
+			},
+		})
+
+		require.Len(t, propagated, 1)
+		assert.Equal(t, "audit", propagated[0].Column.MRN)
+	})
+
+	t.Run("clearing one tag keeps others", func(t *testing.T) {
+		propagated := Propagate(&PropagationGraph{
+			Tagged:  []TaggedColumn{pii, {AssetID: "id-raw", MRN: "raw", Column: "email", TagID: "restricted"}},
+			Cleared: []TaggedColumn{{MRN: "staging", Column: "email", TagID: "pii"}},
+			Edges:   []ColumnEdge{edge("raw", "email", "staging", "email")},
+		})
+
+		require.Len(t, propagated, 1)
+		assert.Equal(t, "restricted", propagated[0].Column.TagID)
+	})
+
+	t.Run("directly tagged columns don't inherit", func(t *testing.T) {
+		propagated := Propagate(&PropagationGraph{
+			Tagged: []TaggedColumn{pii, {AssetID: "id-staging", MRN: "staging", Column: "email", TagID: "pii"}},
+			Edges: []ColumnEdge{
+				edge("raw", "email", "staging", "email"),
+				edge("staging", "email", "mart", "email"),
+			},
+		})
+
+		require.Len(t, propagated, 1)
+		assert.Equal(t, "mart", propagated[0].Column.MRN)
+		assert.Equal(t, "staging", propagated[0].Origin.MRN)
+		assert.Equal(t, 1, propagated[0].Hops)
+	})
+
+	t.Run("cycles and asset-level tags", func(t *testing.T) {
+		propagated := Propagate(&PropagationGraph{
+			Tagged: []TaggedColumn{pii, {AssetID: "id-raw", MRN: "raw", TagID: "internal"}},
+			Edges: []ColumnEdge{
+				edge("raw", "email", "a", "email"),
+				edge("a", "email", "b", "email"),
+				edge("b", "email", "a", "email"),
+			},
+		})
+
+		require.Len(t, propagated, 2)
+		for _, p := range propagated {
+			assert.Equal(t, "pii", p.Column.TagID)
+		}
+	})
+}
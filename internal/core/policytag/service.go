@@ -0,0 +1,221 @@
+package policytag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound           = errors.New("policy tag not found")
+	ErrAlreadyExists      = errors.New("policy tag already exists")
+	ErrAssignmentNotFound = errors.New("policy tag assignment not found")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Masking strategies a consumer is expected to apply to tagged data.
+const (
+	MaskingNone     = "none"
+	MaskingRedact   = "redact"
+	MaskingHash     = "hash"
+	MaskingNullify  = "nullify"
+	MaskingPartial  = "partial"
+	MaskingTokenize = "tokenize"
+)
+
+var ValidMaskingStrategies = map[string]bool{
+	MaskingNone:     true,
+	MaskingRedact:   true,
+	MaskingHash:     true,
+	MaskingNullify:  true,
+	MaskingPartial:  true,
+	MaskingTokenize: true,
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]{0,99}$`)
+
+// PolicyTag is a governance label carrying machine-readable enforcement
+// hints. Marmot does not enforce these itself; query tools read them from the
+// API and apply masking or access restrictions on their side.
+type PolicyTag struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Description     *string           `json:"description,omitempty"`
+	MaskingStrategy string            `json:"masking_strategy"`
+	MaskingParams   map[string]string `json:"masking_params,omitempty"`
+	Restricted      bool              `json:"restricted"`
+	CreatedBy       *string           `json:"created_by,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+} // @name PolicyTag
+
+// Assignment attaches a policy tag to an asset, or to a single column of it
+// when Column is set.
+type Assignment struct {
+	AssetID   string    `json:"asset_id"`
+	Column    string    `json:"column,omitempty"`
+	PolicyTag PolicyTag `json:"policy_tag"`
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+} // @name PolicyTagAssignment
+
+// EnforcementHint is the flattened, consumer-facing form of an assignment.
+// Hints for tags a column inherited through column lineage carry the column
+// they were inherited from.
+type EnforcementHint struct {
+	AssetID         string            `json:"asset_id"`
+	MRN             string            `json:"mrn"`
+	Column          string            `json:"column,omitempty"`
+	PolicyTag       string            `json:"policy_tag"`
+	MaskingStrategy string            `json:"masking_strategy"`
+	MaskingParams   map[string]string `json:"masking_params,omitempty"`
+	Restricted      bool              `json:"restricted"`
+	InheritedFrom   *ColumnOrigin     `json:"inherited_from,omitempty"`
+} // @name PolicyEnforcementHint
+
+type CreateInput struct {
+	Name            string            `json:"name"`
+	Description     *string           `json:"description,omitempty"`
+	MaskingStrategy string            `json:"masking_strategy"`
+	MaskingParams   map[string]string `json:"masking_params,omitempty"`
+	Restricted      bool              `json:"restricted"`
+} // @name CreatePolicyTagInput
+
+type UpdateInput struct {
+	Description     *string           `json:"description,omitempty"`
+	MaskingStrategy *string           `json:"masking_strategy,omitempty"`
+	MaskingParams   map[string]string `json:"masking_params,omitempty"`
+	Restricted      *bool             `json:"restricted,omitempty"`
+} // @name UpdatePolicyTagInput
+
+type Service struct {
+	repo        Repository
+	propagateMu sync.Mutex
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput, createdBy *string) (*PolicyTag, error) {
+	if !namePattern.MatchString(input.Name) {
+		return nil, &ValidationError{Message: "name must be lowercase alphanumeric (with - _ . :) and at most 100 characters"}
+	}
+	if input.MaskingStrategy == "" {
+		input.MaskingStrategy = MaskingNone
+	}
+	if !ValidMaskingStrategies[input.MaskingStrategy] {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid masking strategy: %q", input.MaskingStrategy)}
+	}
+
+	tag := &PolicyTag{
+		Name:            input.Name,
+		Description:     input.Description,
+		MaskingStrategy: input.MaskingStrategy,
+		MaskingParams:   input.MaskingParams,
+		Restricted:      input.Restricted,
+		CreatedBy:       createdBy,
+	}
+
+	if err := s.repo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*PolicyTag, error) {
+	tag, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Description != nil {
+		tag.Description = input.Description
+	}
+	if input.MaskingStrategy != nil {
+		if !ValidMaskingStrategies[*input.MaskingStrategy] {
+			return nil, &ValidationError{Message: fmt.Sprintf("invalid masking strategy: %q", *input.MaskingStrategy)}
+		}
+		tag.MaskingStrategy = *input.MaskingStrategy
+	}
+	if input.MaskingParams != nil {
+		tag.MaskingParams = input.MaskingParams
+	}
+	if input.Restricted != nil {
+		tag.Restricted = *input.Restricted
+	}
+
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*PolicyTag, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context) ([]*PolicyTag, error) {
+	return s.repo.List(ctx)
+}
+
+// Assign attaches a policy tag to an asset. An empty column applies the tag
+// to the whole asset.
+func (s *Service) Assign(ctx context.Context, assetID, tagID, column string, createdBy *string) (*Assignment, error) {
+	if assetID == "" || tagID == "" {
+		return nil, &ValidationError{Message: "asset_id and policy_tag_id are required"}
+	}
+	if len(column) > 255 {
+		return nil, &ValidationError{Message: "column must be at most 255 characters"}
+	}
+
+	if _, err := s.repo.Get(ctx, tagID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Assign(ctx, assetID, tagID, column, createdBy)
+}
+
+func (s *Service) Unassign(ctx context.Context, assetID, tagID, column string) error {
+	return s.repo.Unassign(ctx, assetID, tagID, column)
+}
+
+func (s *Service) ListForAsset(ctx context.Context, assetID string) ([]*Assignment, error) {
+	return s.repo.ListForAsset(ctx, assetID)
+}
+
+// ListEnforcementHints returns flattened hints for consumers. When mrns is
+// empty, hints for every tagged asset are returned, paginated.
+func (s *Service) ListEnforcementHints(ctx context.Context, mrns []string, limit, offset int) ([]*EnforcementHint, int, error) {
+	if limit <= 0 {
+		limit = 500
+	} else if limit > 5000 {
+		limit = 5000
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.ListEnforcementHints(ctx, mrns, limit, offset)
+}
@@ -0,0 +1,231 @@
+package policytag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo keeps policy tags in memory and records the arguments of the
+// calls the service passes through.
+type fakeRepo struct {
+	Repository
+	tags     map[string]*PolicyTag
+	assigned []string
+
+	hintLimit  int
+	hintOffset int
+	hintMRNs   []string
+}
+
+func newFakeRepo(tags ...*PolicyTag) *fakeRepo {
+	r := &fakeRepo{tags: map[string]*PolicyTag{}}
+	for _, tag := range tags {
+		r.tags[tag.ID] = tag
+	}
+	return r
+}
+
+func (r *fakeRepo) Create(_ context.Context, tag *PolicyTag) error {
+	for _, existing := range r.tags {
+		if existing.Name == tag.Name {
+			return ErrAlreadyExists
+		}
+	}
+	tag.ID = "tag-" + tag.Name
+	r.tags[tag.ID] = tag
+	return nil
+}
+
+func (r *fakeRepo) Get(_ context.Context, id string) (*PolicyTag, error) {
+	tag, ok := r.tags[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *tag
+	return &copied, nil
+}
+
+func (r *fakeRepo) Update(_ context.Context, tag *PolicyTag) error {
+	r.tags[tag.ID] = tag
+	return nil
+}
+
+func (r *fakeRepo) Assign(_ context.Context, assetID, tagID, column string, _ *string) (*Assignment, error) {
+	r.assigned = append(r.assigned, assetID+"/"+tagID+"/"+column)
+	return &Assignment{AssetID: assetID, Column: column, PolicyTag: *r.tags[tagID]}, nil
+}
+
+func (r *fakeRepo) ListEnforcementHints(_ context.Context, mrns []string, limit, offset int) ([]*EnforcementHint, int, error) {
+	r.hintMRNs, r.hintLimit, r.hintOffset = mrns, limit, offset
+	return []*EnforcementHint{{MRN: "mrn://table/postgres/db.users", PolicyTag: "pii", MaskingStrategy: MaskingHash}}, 1, nil
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        CreateInput
+		wantStrategy string
+		wantErr      bool
+	}{
+		{name: "defaults to no masking", input: CreateInput{Name: "pii"}, wantStrategy: MaskingNone},
+		{name: "namespaced name", input: CreateInput{Name: "gdpr:special_category", MaskingStrategy: MaskingRedact}, wantStrategy: MaskingRedact},
+		{name: "every strategy is accepted", input: CreateInput{Name: "card", MaskingStrategy: MaskingPartial, MaskingParams: map[string]string{"keep_last": "4"}}, wantStrategy: MaskingPartial},
+		{name: "uppercase name", input: CreateInput{Name: "PII"}, wantErr: true},
+		{name: "leading punctuation", input: CreateInput{Name: "-pii"}, wantErr: true},
+		{name: "spaces", input: CreateInput{Name: "personal data"}, wantErr: true},
+		{name: "empty name", input: CreateInput{}, wantErr: true},
+		{name: "name too long", input: CreateInput{Name: strings.Repeat("a", 101)}, wantErr: true},
+		{name: "unknown strategy", input: CreateInput{Name: "pii", MaskingStrategy: "encrypt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := NewService(repo)
+
+			tag, err := svc.Create(context.Background(), tt.input, nil)
+			if tt.wantErr {
+				assert.True(t, IsValidationError(err), "expected a validation error, got %v", err)
+				assert.Empty(t, repo.tags)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStrategy, tag.MaskingStrategy)
+			assert.Equal(t, tt.input.MaskingParams, tag.MaskingParams)
+			assert.Contains(t, repo.tags, tag.ID)
+		})
+	}
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	svc := NewService(newFakeRepo(&PolicyTag{ID: "tag-pii", Name: "pii"}))
+
+	_, err := svc.Create(context.Background(), CreateInput{Name: "pii"}, nil)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestUpdate(t *testing.T) {
+	desc := "Personal data"
+	existing := func() *PolicyTag {
+		return &PolicyTag{ID: "t1", Name: "pii", Description: &desc, MaskingStrategy: MaskingHash, MaskingParams: map[string]string{"salt": "x"}}
+	}
+
+	t.Run("changes only the fields given", func(t *testing.T) {
+		repo := newFakeRepo(existing())
+		svc := NewService(repo)
+		restricted := true
+		strategy := MaskingNullify
+
+		tag, err := svc.Update(context.Background(), "t1", UpdateInput{MaskingStrategy: &strategy, Restricted: &restricted})
+		require.NoError(t, err)
+		assert.Equal(t, MaskingNullify, tag.MaskingStrategy)
+		assert.True(t, tag.Restricted)
+		assert.Equal(t, &desc, tag.Description)
+		assert.Equal(t, map[string]string{"salt": "x"}, tag.MaskingParams)
+		assert.Equal(t, MaskingNullify, repo.tags["t1"].MaskingStrategy)
+	})
+
+	t.Run("rejects an unknown strategy without saving", func(t *testing.T) {
+		repo := newFakeRepo(existing())
+		svc := NewService(repo)
+		strategy := "shuffle"
+
+		_, err := svc.Update(context.Background(), "t1", UpdateInput{MaskingStrategy: &strategy})
+		assert.True(t, IsValidationError(err))
+		assert.Equal(t, MaskingHash, repo.tags["t1"].MaskingStrategy)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		svc := NewService(newFakeRepo())
+
+		_, err := svc.Update(context.Background(), "missing", UpdateInput{})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestAssign(t *testing.T) {
+	tests := []struct {
+		name      string
+		assetID   string
+		tagID     string
+		column    string
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "whole asset", assetID: "a1", tagID: "t1"},
+		{name: "single column", assetID: "a1", tagID: "t1", column: "email"},
+		{name: "missing asset", tagID: "t1", wantValid: true},
+		{name: "missing tag id", assetID: "a1", wantValid: true},
+		{name: "column too long", assetID: "a1", tagID: "t1", column: strings.Repeat("c", 256), wantValid: true},
+		{name: "unknown tag", assetID: "a1", tagID: "t2", wantErr: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(&PolicyTag{ID: "t1", Name: "pii", MaskingStrategy: MaskingHash})
+			svc := NewService(repo)
+
+			assignment, err := svc.Assign(context.Background(), tt.assetID, tt.tagID, tt.column, nil)
+			switch {
+			case tt.wantValid:
+				assert.True(t, IsValidationError(err), "expected a validation error, got %v", err)
+				assert.Empty(t, repo.assigned)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.assigned)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, []string{tt.assetID + "/" + tt.tagID + "/" + tt.column}, repo.assigned)
+				assert.Equal(t, tt.column, assignment.Column)
+				assert.Equal(t, MaskingHash, assignment.PolicyTag.MaskingStrategy)
+			}
+		})
+	}
+}
+
+func TestListEnforcementHints(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		offset     int
+		wantLimit  int
+		wantOffset int
+	}{
+		{name: "as given", limit: 100, offset: 200, wantLimit: 100, wantOffset: 200},
+		{name: "default limit", wantLimit: 500},
+		{name: "limit capped", limit: 10000, wantLimit: 5000},
+		{name: "negative offset", limit: 10, offset: -5, wantLimit: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := NewService(repo)
+			mrns := []string{"mrn://table/postgres/db.users"}
+
+			hints, total, err := svc.ListEnforcementHints(context.Background(), mrns, tt.limit, tt.offset)
+			require.NoError(t, err)
+			assert.Equal(t, 1, total)
+			assert.Equal(t, "pii", hints[0].PolicyTag)
+			assert.Equal(t, mrns, repo.hintMRNs)
+			assert.Equal(t, tt.wantLimit, repo.hintLimit)
+			assert.Equal(t, tt.wantOffset, repo.hintOffset)
+		})
+	}
+}
+
+func TestUnmarshalParams(t *testing.T) {
+	var params map[string]string
+	require.NoError(t, unmarshalParams(nil, &params))
+	require.NoError(t, unmarshalParams([]byte("null"), &params))
+	assert.Nil(t, params)
+
+	require.NoError(t, unmarshalParams([]byte(`{"keep_last":"4"}`), &params))
+	assert.Equal(t, map[string]string{"keep_last": "4"}, params)
+
+	assert.Error(t, unmarshalParams([]byte(`{"keep_last":4}`), &params))
+}
@@ -0,0 +1,319 @@
+package policytag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the policy tag data access interface.
+type Repository interface {
+	Create(ctx context.Context, tag *PolicyTag) error
+	Update(ctx context.Context, tag *PolicyTag) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (*PolicyTag, error)
+	List(ctx context.Context) ([]*PolicyTag, error)
+
+	Assign(ctx context.Context, assetID, tagID, column string, createdBy *string) (*Assignment, error)
+	Unassign(ctx context.Context, assetID, tagID, column string) error
+	ListForAsset(ctx context.Context, assetID string) ([]*Assignment, error)
+	ListEnforcementHints(ctx context.Context, mrns []string, limit, offset int) ([]*EnforcementHint, int, error)
+
+	LoadPropagationGraph(ctx context.Context) (*PropagationGraph, error)
+	ReplaceInherited(ctx context.Context, propagated []PropagatedTag) error
+	ListInherited(ctx context.Context, assetID string) ([]*InheritedAssignment, error)
+	CreateOverride(ctx context.Context, assetID, tagID, column, reason string, actor *string) (*Override, error)
+	DeleteOverride(ctx context.Context, assetID, tagID, column, reason string, actor *string) error
+	ListOverrides(ctx context.Context, assetID string) ([]*Override, error)
+	ListOverrideAudit(ctx context.Context, assetID string, limit, offset int) ([]*OverrideAuditEntry, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectPolicyTag = `
+	SELECT id, name, description, masking_strategy, masking_params, restricted,
+	       created_by, created_at, updated_at
+	FROM policy_tags`
+
+func (r *PostgresRepository) Create(ctx context.Context, tag *PolicyTag) error {
+	paramsJSON, err := json.Marshal(tag.MaskingParams)
+	if err != nil {
+		return fmt.Errorf("marshaling masking params: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO policy_tags (name, description, masking_strategy, masking_params, restricted, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		tag.Name, tag.Description, tag.MaskingStrategy, paramsJSON, tag.Restricted, tag.CreatedBy,
+	).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("creating policy tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, tag *PolicyTag) error {
+	paramsJSON, err := json.Marshal(tag.MaskingParams)
+	if err != nil {
+		return fmt.Errorf("marshaling masking params: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		UPDATE policy_tags
+		SET description = $1, masking_strategy = $2, masking_params = $3, restricted = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at`,
+		tag.Description, tag.MaskingStrategy, paramsJSON, tag.Restricted, tag.ID,
+	).Scan(&tag.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating policy tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM policy_tags WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting policy tag: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*PolicyTag, error) {
+	tag, err := scanPolicyTag(r.db.QueryRow(ctx, selectPolicyTag+` WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting policy tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]*PolicyTag, error) {
+	rows, err := r.db.Query(ctx, selectPolicyTag+` ORDER BY name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing policy tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*PolicyTag{}
+	for rows.Next() {
+		tag, err := scanPolicyTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning policy tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating policy tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (r *PostgresRepository) Assign(ctx context.Context, assetID, tagID, column string, createdBy *string) (*Assignment, error) {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_policy_tags (asset_id, policy_tag_id, column_name, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (asset_id, policy_tag_id, column_name) DO NOTHING`,
+		assetID, tagID, column, createdBy)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, &ValidationError{Message: "asset not found"}
+		}
+		return nil, fmt.Errorf("assigning policy tag: %w", err)
+	}
+
+	assignments, err := r.listAssignments(ctx, `WHERE apt.asset_id = $1 AND apt.policy_tag_id = $2 AND apt.column_name = $3`, assetID, tagID, column)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return nil, ErrAssignmentNotFound
+	}
+
+	return assignments[0], nil
+}
+
+func (r *PostgresRepository) Unassign(ctx context.Context, assetID, tagID, column string) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM asset_policy_tags
+		WHERE asset_id = $1 AND policy_tag_id = $2 AND column_name = $3`,
+		assetID, tagID, column)
+	if err != nil {
+		return fmt.Errorf("removing policy tag assignment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAssignmentNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListForAsset(ctx context.Context, assetID string) ([]*Assignment, error) {
+	return r.listAssignments(ctx, `WHERE apt.asset_id = $1`, assetID)
+}
+
+func (r *PostgresRepository) listAssignments(ctx context.Context, where string, args ...interface{}) ([]*Assignment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT apt.asset_id, apt.column_name, apt.created_by, apt.created_at,
+		       pt.id, pt.name, pt.description, pt.masking_strategy, pt.masking_params, pt.restricted,
+		       pt.created_by, pt.created_at, pt.updated_at
+		FROM asset_policy_tags apt
+		JOIN policy_tags pt ON pt.id = apt.policy_tag_id
+		`+where+`
+		ORDER BY apt.column_name ASC, pt.name ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying policy tag assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []*Assignment{}
+	for rows.Next() {
+		var a Assignment
+		var paramsRaw []byte
+		if err := rows.Scan(
+			&a.AssetID, &a.Column, &a.CreatedBy, &a.CreatedAt,
+			&a.PolicyTag.ID, &a.PolicyTag.Name, &a.PolicyTag.Description, &a.PolicyTag.MaskingStrategy,
+			&paramsRaw, &a.PolicyTag.Restricted, &a.PolicyTag.CreatedBy, &a.PolicyTag.CreatedAt, &a.PolicyTag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning policy tag assignment: %w", err)
+		}
+		if err := unmarshalParams(paramsRaw, &a.PolicyTag.MaskingParams); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating policy tag assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// enforcedTags is every direct assignment plus the inherited tags of
+// columns that don't carry the same tag directly.
+const enforcedTags = `
+	SELECT apt.asset_id, apt.column_name, apt.policy_tag_id,
+	       NULL::varchar AS origin_asset_id, NULL::varchar AS origin_column
+	FROM asset_policy_tags apt
+	UNION ALL
+	SELECT ipt.asset_id, ipt.column_name, ipt.policy_tag_id, ipt.origin_asset_id, ipt.origin_column
+	FROM inherited_policy_tags ipt
+	WHERE NOT EXISTS (
+		SELECT 1 FROM asset_policy_tags d
+		WHERE d.asset_id = ipt.asset_id
+		  AND d.policy_tag_id = ipt.policy_tag_id
+		  AND lower(d.column_name) = lower(ipt.column_name)
+	)`
+
+func (r *PostgresRepository) ListEnforcementHints(ctx context.Context, mrns []string, limit, offset int) ([]*EnforcementHint, int, error) {
+	where := ""
+	args := []interface{}{}
+	if len(mrns) > 0 {
+		where = "WHERE a.mrn = ANY($1)"
+		args = append(args, mrns)
+	}
+
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM (`+enforcedTags+`) t
+		JOIN assets a ON a.id = t.asset_id
+		`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting enforcement hints: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT a.id, a.mrn, t.column_name, pt.name, pt.masking_strategy, pt.masking_params, pt.restricted,
+		       t.origin_asset_id, oa.mrn, t.origin_column
+		FROM (`+enforcedTags+`) t
+		JOIN assets a ON a.id = t.asset_id
+		JOIN policy_tags pt ON pt.id = t.policy_tag_id
+		LEFT JOIN assets oa ON oa.id = t.origin_asset_id
+		%s
+		ORDER BY a.mrn ASC, t.column_name ASC, pt.name ASC
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("querying enforcement hints: %w", err)
+	}
+	defer rows.Close()
+
+	hints := []*EnforcementHint{}
+	for rows.Next() {
+		var h EnforcementHint
+		var paramsRaw []byte
+		var originAssetID, originMRN, originColumn *string
+		if err := rows.Scan(&h.AssetID, &h.MRN, &h.Column, &h.PolicyTag, &h.MaskingStrategy, &paramsRaw, &h.Restricted,
+			&originAssetID, &originMRN, &originColumn); err != nil {
+			return nil, 0, fmt.Errorf("scanning enforcement hint: %w", err)
+		}
+		if err := unmarshalParams(paramsRaw, &h.MaskingParams); err != nil {
+			return nil, 0, err
+		}
+		if originAssetID != nil && originMRN != nil && originColumn != nil {
+			h.InheritedFrom = &ColumnOrigin{AssetID: *originAssetID, MRN: *originMRN, Column: *originColumn}
+		}
+		hints = append(hints, &h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating enforcement hints: %w", err)
+	}
+
+	return hints, total, nil
+}
+
+func scanPolicyTag(row pgx.Row) (*PolicyTag, error) {
+	var tag PolicyTag
+	var paramsRaw []byte
+	if err := row.Scan(
+		&tag.ID, &tag.Name, &tag.Description, &tag.MaskingStrategy, &paramsRaw, &tag.Restricted,
+		&tag.CreatedBy, &tag.CreatedAt, &tag.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := unmarshalParams(paramsRaw, &tag.MaskingParams); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func unmarshalParams(raw []byte, dst *map[string]string) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("unmarshaling masking params: %w", err)
+	}
+	return nil
+}
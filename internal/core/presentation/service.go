@@ -0,0 +1,215 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("presentation schema not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxAssetTypeLength = 100
+	maxFields          = 200
+	maxLinks           = 20
+)
+
+// placeholderPattern matches the placeholders a link template may use:
+// {name}, {mrn}, {type}, {provider} or {metadata.<key>}, where the key is a
+// dot-separated path into the asset's metadata.
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+var assetPlaceholders = map[string]bool{
+	"name":     true,
+	"mrn":      true,
+	"type":     true,
+	"provider": true,
+}
+
+// Schema tells the UI how to present the metadata of one asset type. The full
+// metadata is still shown below, so keys the schema doesn't mention aren't
+// hidden.
+type Schema struct {
+	AssetType string    `json:"asset_type"`
+	Groups    []Group   `json:"groups"`
+	Links     []Link    `json:"links"`
+	UpdatedBy *string   `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+} // @name PresentationSchema
+
+// Group is a titled section of metadata fields, shown in order.
+type Group struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+} // @name PresentationGroup
+
+// Field describes how to show one metadata key.
+type Field struct {
+	// Key is a dot-separated path into the asset's metadata.
+	Key   string `json:"key"`
+	Label string `json:"label,omitempty"`
+	// Unit is appended to the value, e.g. "GB" or "ms".
+	Unit string `json:"unit,omitempty"`
+	// Highlight shows the field in the asset's summary.
+	Highlight bool `json:"highlight,omitempty"`
+} // @name PresentationField
+
+// Link is an external link built from the asset, e.g.
+// https://console.aws.amazon.com/s3/buckets/{name}?region={metadata.region}.
+// Links with a placeholder the asset has no value for are not shown.
+type Link struct {
+	Label    string `json:"label"`
+	Template string `json:"template"`
+	Icon     string `json:"icon,omitempty"`
+} // @name PresentationLink
+
+// Input is the editable part of a schema.
+type Input struct {
+	Groups []Group `json:"groups"`
+	Links  []Link  `json:"links"`
+} // @name PresentationSchemaInput
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Put creates or replaces the schema for an asset type.
+func (s *Service) Put(ctx context.Context, assetType string, input Input, updatedBy *string) (*Schema, error) {
+	assetType = strings.TrimSpace(assetType)
+	if assetType == "" || len(assetType) > maxAssetTypeLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("asset type must be between 1 and %d characters", maxAssetTypeLength)}
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		AssetType: assetType,
+		Groups:    input.Groups,
+		Links:     input.Links,
+		UpdatedBy: updatedBy,
+	}
+	if schema.Groups == nil {
+		schema.Groups = []Group{}
+	}
+	if schema.Links == nil {
+		schema.Links = []Link{}
+	}
+
+	if err := s.repo.Upsert(ctx, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *Service) Get(ctx context.Context, assetType string) (*Schema, error) {
+	return s.repo.Get(ctx, assetType)
+}
+
+func (s *Service) List(ctx context.Context) ([]*Schema, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *Service) Delete(ctx context.Context, assetType string) error {
+	return s.repo.Delete(ctx, assetType)
+}
+
+func validate(input Input) error {
+	groups := make(map[string]bool, len(input.Groups))
+	keys := make(map[string]bool)
+	for i := range input.Groups {
+		g := &input.Groups[i]
+		g.Name = strings.TrimSpace(g.Name)
+		if g.Name == "" {
+			return &ValidationError{Message: "group name is required"}
+		}
+		if groups[g.Name] {
+			return &ValidationError{Message: fmt.Sprintf("duplicate group %q", g.Name)}
+		}
+		groups[g.Name] = true
+
+		for j := range g.Fields {
+			f := &g.Fields[j]
+			f.Key = strings.TrimSpace(f.Key)
+			if err := validateKey(f.Key); err != nil {
+				return err
+			}
+			if keys[f.Key] {
+				return &ValidationError{Message: fmt.Sprintf("metadata key %q appears more than once", f.Key)}
+			}
+			keys[f.Key] = true
+		}
+	}
+	if len(keys) > maxFields {
+		return &ValidationError{Message: fmt.Sprintf("at most %d fields are allowed", maxFields)}
+	}
+
+	if len(input.Links) > maxLinks {
+		return &ValidationError{Message: fmt.Sprintf("at most %d links are allowed", maxLinks)}
+	}
+	for i := range input.Links {
+		l := &input.Links[i]
+		l.Label = strings.TrimSpace(l.Label)
+		l.Template = strings.TrimSpace(l.Template)
+		if l.Label == "" {
+			return &ValidationError{Message: "link label is required"}
+		}
+		if err := validateTemplate(l.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateKey(key string) error {
+	if key == "" {
+		return &ValidationError{Message: "field key is required"}
+	}
+	for _, part := range strings.Split(key, ".") {
+		if part == "" {
+			return &ValidationError{Message: fmt.Sprintf("invalid metadata key %q", key)}
+		}
+	}
+	return nil
+}
+
+func validateTemplate(template string) error {
+	if !strings.HasPrefix(template, "https://") && !strings.HasPrefix(template, "http://") {
+		return &ValidationError{Message: fmt.Sprintf("link template %q must start with http:// or https://", template)}
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		name := m[1]
+		if assetPlaceholders[name] {
+			continue
+		}
+		if key, ok := strings.CutPrefix(name, "metadata."); ok && validateKey(key) == nil {
+			continue
+		}
+		return &ValidationError{Message: fmt.Sprintf("unknown placeholder {%s} in link template", name)}
+	}
+	if strings.Count(template, "{") != strings.Count(template, "}") {
+		return &ValidationError{Message: fmt.Sprintf("unbalanced braces in link template %q", template)}
+	}
+	return nil
+}
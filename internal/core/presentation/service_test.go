@@ -0,0 +1,78 @@
+package presentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	schemas map[string]*Schema
+}
+
+func (f *fakeRepo) Upsert(ctx context.Context, schema *Schema) error {
+	f.schemas[schema.AssetType] = schema
+	return nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, assetType string) (*Schema, error) {
+	if s, ok := f.schemas[assetType]; ok {
+		return s, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (f *fakeRepo) List(ctx context.Context) ([]*Schema, error) { return nil, nil }
+
+func (f *fakeRepo) Delete(ctx context.Context, assetType string) error { return nil }
+
+func TestPutNormalisesSchema(t *testing.T) {
+	repo := &fakeRepo{schemas: map[string]*Schema{}}
+	svc := NewService(repo)
+
+	schema, err := svc.Put(context.Background(), " Bucket ", Input{
+		Groups: []Group{{Name: " Storage ", Fields: []Field{{Key: " size_gb ", Unit: "GB", Highlight: true}}}},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bucket", schema.AssetType)
+	assert.Equal(t, "Storage", schema.Groups[0].Name)
+	assert.Equal(t, "size_gb", schema.Groups[0].Fields[0].Key)
+	assert.Equal(t, []Link{}, schema.Links)
+	assert.Same(t, schema, repo.schemas["Bucket"])
+}
+
+func TestPutValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Input
+	}{
+		{"empty group name", Input{Groups: []Group{{Name: " "}}}},
+		{"duplicate group", Input{Groups: []Group{{Name: "a"}, {Name: "a"}}}},
+		{"duplicate key across groups", Input{Groups: []Group{
+			{Name: "a", Fields: []Field{{Key: "region"}}},
+			{Name: "b", Fields: []Field{{Key: "region"}}},
+		}}},
+		{"empty key segment", Input{Groups: []Group{{Name: "a", Fields: []Field{{Key: "tags..env"}}}}}},
+		{"link without label", Input{Links: []Link{{Template: "https://example.com/{name}"}}}},
+		{"non http link", Input{Links: []Link{{Label: "x", Template: "javascript:alert(1)"}}}},
+		{"unknown placeholder", Input{Links: []Link{{Label: "x", Template: "https://example.com/{owner}"}}}},
+		{"unbalanced braces", Input{Links: []Link{{Label: "x", Template: "https://example.com/{name"}}}},
+	}
+
+	svc := NewService(&fakeRepo{schemas: map[string]*Schema{}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Put(context.Background(), "Table", tt.input, nil)
+			assert.True(t, IsValidationError(err), "got %v", err)
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, validateTemplate("https://console.aws.amazon.com/s3/buckets/{name}?region={metadata.region}"))
+	assert.NoError(t, validateTemplate("https://grafana.example.com/d/{metadata.dashboard.uid}"))
+	assert.Error(t, validateTemplate("https://example.com/{metadata.}"))
+}
@@ -0,0 +1,116 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the presentation schema data access interface.
+type Repository interface {
+	Upsert(ctx context.Context, schema *Schema) error
+	Get(ctx context.Context, assetType string) (*Schema, error)
+	List(ctx context.Context) ([]*Schema, error)
+	Delete(ctx context.Context, assetType string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectSchema = `
+	SELECT asset_type, groups, links, updated_by, created_at, updated_at
+	FROM asset_type_presentations`
+
+func (r *PostgresRepository) Upsert(ctx context.Context, schema *Schema) error {
+	groupsJSON, err := json.Marshal(schema.Groups)
+	if err != nil {
+		return fmt.Errorf("marshaling groups: %w", err)
+	}
+	linksJSON, err := json.Marshal(schema.Links)
+	if err != nil {
+		return fmt.Errorf("marshaling links: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO asset_type_presentations (asset_type, groups, links, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (asset_type) DO UPDATE
+		SET groups = EXCLUDED.groups, links = EXCLUDED.links,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING created_at, updated_at`,
+		schema.AssetType, groupsJSON, linksJSON, schema.UpdatedBy,
+	).Scan(&schema.CreatedAt, &schema.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving presentation schema: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, assetType string) (*Schema, error) {
+	schema, err := scanSchema(r.db.QueryRow(ctx, selectSchema+" WHERE asset_type = $1", assetType))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting presentation schema: %w", err)
+	}
+	return schema, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]*Schema, error) {
+	rows, err := r.db.Query(ctx, selectSchema+" ORDER BY asset_type")
+	if err != nil {
+		return nil, fmt.Errorf("listing presentation schemas: %w", err)
+	}
+	defer rows.Close()
+
+	schemas := []*Schema{}
+	for rows.Next() {
+		schema, err := scanSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning presentation schema: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating presentation schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, assetType string) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM asset_type_presentations WHERE asset_type = $1", assetType)
+	if err != nil {
+		return fmt.Errorf("deleting presentation schema: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanSchema(row pgx.Row) (*Schema, error) {
+	var schema Schema
+	var groupsJSON, linksJSON []byte
+	if err := row.Scan(&schema.AssetType, &groupsJSON, &linksJSON, &schema.UpdatedBy, &schema.CreatedAt, &schema.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(groupsJSON, &schema.Groups); err != nil {
+		return nil, fmt.Errorf("unmarshaling groups: %w", err)
+	}
+	if err := json.Unmarshal(linksJSON, &schema.Links); err != nil {
+		return nil, fmt.Errorf("unmarshaling links: %w", err)
+	}
+	return &schema, nil
+}
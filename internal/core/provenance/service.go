@@ -0,0 +1,296 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = asset.MaxRevisionsPerAsset
+
+	// runMatchWindow is how long after an ingestion revision its run may
+	// record the asset for the two to be treated as the same change.
+	runMatchWindow = 5 * time.Minute
+)
+
+type EventType string // @name ProvenanceEventType
+
+const (
+	// EventCreated is the asset being created.
+	EventCreated EventType = "created"
+	// EventRun is a pipeline run processing the asset. Runs that changed
+	// the asset carry the revision they recorded.
+	EventRun EventType = "run"
+	// EventRevision is a change made outside a pipeline run, such as an
+	// edit in the UI.
+	EventRevision EventType = "revision"
+)
+
+// Event is one step in how an asset reached its current state.
+type Event struct {
+	Type EventType `json:"type"`
+	At   time.Time `json:"at"`
+	// Actor is the user or pipeline responsible.
+	Actor  string `json:"actor,omitempty"`
+	Source string `json:"source" enums:"user,ingestion,system"`
+	// RunID, Pipeline, SourceName and Status describe the run for run events.
+	RunID      string `json:"run_id,omitempty"`
+	Pipeline   string `json:"pipeline,omitempty"`
+	SourceName string `json:"source_name,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// RevisionID and Fields describe what changed, when the change was
+	// recorded.
+	RevisionID int64    `json:"revision_id,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+} // @name ProvenanceEvent
+
+// Source is a source that reports the asset, ranked as the merge policy
+// ranks it.
+type Source struct {
+	Name       string    `json:"name"`
+	Priority   int       `json:"priority"`
+	LastSyncAt time.Time `json:"last_sync_at"`
+} // @name ProvenanceSource
+
+// Field explains where a field's current value came from.
+type Field struct {
+	Field string `json:"field"`
+	// Locked fields keep their hand-edited value whatever sources report.
+	Locked bool `json:"locked"`
+	// Strategy, Contributors and Winner are set for fields governed by the
+	// merge policy. Contributors are the sources that reported a value,
+	// highest priority first. Winner is the source whose value is used,
+	// empty when values from every contributor are combined.
+	Strategy     asset.MergeStrategy `json:"strategy,omitempty"`
+	Contributors []string            `json:"contributors,omitempty"`
+	Winner       string              `json:"winner,omitempty"`
+	// LastChange is the most recent event that changed the field, if it is
+	// still in the asset's history.
+	LastChange *Event `json:"last_change,omitempty"`
+} // @name ProvenanceField
+
+// Chain is the provenance of an asset: the sources that report it, where
+// each field's value came from, and the runs and edits that shaped it.
+type Chain struct {
+	AssetID      string   `json:"asset_id"`
+	MRN          string   `json:"mrn"`
+	MergeEnabled bool     `json:"merge_enabled"`
+	Sources      []Source `json:"sources"`
+	Fields       []Field  `json:"fields"`
+	// Events are oldest first.
+	Events []Event `json:"events"`
+	// Truncated is set when older runs or revisions were left out.
+	Truncated bool `json:"truncated"`
+} // @name AssetProvenance
+
+type Service struct {
+	repo   Repository
+	assets asset.Service
+	policy *asset.MergePolicy
+}
+
+// NewService creates a provenance service. policy is nil when merge
+// policies are disabled.
+func NewService(repo Repository, assets asset.Service, policy *asset.MergePolicy) *Service {
+	return &Service{repo: repo, assets: assets, policy: policy}
+}
+
+// Get reconstructs how an asset reached its current state from up to limit
+// of its latest runs and revisions.
+func (s *Service) Get(ctx context.Context, assetID string, limit int) (*Chain, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	a, err := s.assets.Get(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	revs, totalRevs, err := s.assets.ListRevisions(ctx, assetID, limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing revisions: %w", err)
+	}
+
+	chain := &Chain{AssetID: a.ID, MergeEnabled: s.policy != nil}
+	var runs []RunContribution
+	if a.MRN != nil {
+		chain.MRN = *a.MRN
+		if runs, err = s.repo.ListAssetRuns(ctx, *a.MRN, limit); err != nil {
+			return nil, err
+		}
+	}
+	chain.Truncated = totalRevs > len(revs) || len(runs) == limit
+
+	chain.Events = buildEvents(a, runs, revs)
+	chain.Sources = s.rankSources(a.Sources)
+	chain.Fields = s.fieldOrigins(a, chain.Sources, chain.Events)
+	return chain, nil
+}
+
+// buildEvents merges runs and revisions into one timeline, oldest first.
+// An ingestion revision is folded into the run that recorded it.
+func buildEvents(a *asset.Asset, runs []RunContribution, revs []*asset.Revision) []Event {
+	events := make([]Event, 0, len(runs)+len(revs)+1)
+	created := false
+	for _, r := range runs {
+		events = append(events, Event{
+			Type:       EventRun,
+			At:         r.At,
+			Actor:      r.Pipeline,
+			Source:     asset.RevisionSourceIngestion,
+			RunID:      r.RunID,
+			Pipeline:   r.Pipeline,
+			SourceName: r.Source,
+			Status:     r.Status,
+		})
+		if r.Status == "created" {
+			created = true
+		}
+	}
+	runCount := len(events)
+
+	for _, rev := range revs {
+		if rev.Source == asset.RevisionSourceIngestion {
+			if i := matchRun(events[:runCount], rev); i >= 0 {
+				events[i].RevisionID = rev.ID
+				events[i].Fields = rev.ChangedFields
+				continue
+			}
+		}
+		events = append(events, Event{
+			Type:       EventRevision,
+			At:         rev.CreatedAt,
+			Actor:      rev.ChangedBy,
+			Source:     rev.Source,
+			RevisionID: rev.ID,
+			Fields:     rev.ChangedFields,
+		})
+	}
+
+	if !created {
+		source := asset.RevisionSourceUser
+		if a.CreatedBy == "" {
+			source = asset.RevisionSourceSystem
+		}
+		events = append(events, Event{Type: EventCreated, At: a.CreatedAt, Actor: a.CreatedBy, Source: source})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].At.Equal(events[j].At) {
+			return events[i].At.Before(events[j].At)
+		}
+		return events[i].Type == EventCreated && events[j].Type != EventCreated
+	})
+	return events
+}
+
+// matchRun finds the run by the revision's pipeline that recorded the asset
+// soonest after the revision and has no revision yet.
+func matchRun(runs []Event, rev *asset.Revision) int {
+	best := -1
+	for i, e := range runs {
+		if e.RevisionID != 0 || e.Pipeline != rev.ChangedBy {
+			continue
+		}
+		gap := e.At.Sub(rev.CreatedAt)
+		if gap < 0 || gap > runMatchWindow {
+			continue
+		}
+		if best < 0 || gap < runs[best].At.Sub(rev.CreatedAt) {
+			best = i
+		}
+	}
+	return best
+}
+
+// rankSources orders sources as the merge policy does: highest priority
+// first, then most recently synced.
+func (s *Service) rankSources(sources []asset.AssetSource) []Source {
+	ranked := make([]Source, 0, len(sources))
+	for _, src := range sources {
+		priority := src.Priority
+		if s.policy != nil {
+			priority = s.policy.Priority(src.Name)
+		}
+		ranked = append(ranked, Source{Name: src.Name, Priority: priority, LastSyncAt: src.LastSyncAt})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.LastSyncAt.Equal(b.LastSyncAt) {
+			return a.LastSyncAt.After(b.LastSyncAt)
+		}
+		return a.Name < b.Name
+	})
+	return ranked
+}
+
+func (s *Service) fieldOrigins(a *asset.Asset, sources []Source, events []Event) []Field {
+	contributions := asset.Contributions(a)
+	fields := make([]Field, 0, len(asset.RevisionFields))
+	for _, name := range asset.RevisionFields {
+		f := Field{Field: name, Locked: a.IsLocked(name)}
+
+		for i := len(events) - 1; i >= 0; i-- {
+			if slices.Contains(events[i].Fields, name) {
+				e := events[i]
+				f.LastChange = &e
+				break
+			}
+		}
+
+		if s.policy != nil && s.policy.Merges(name) {
+			f.Strategy = s.policy.Strategy(name)
+			var latest *Source
+			for i, src := range sources {
+				c, ok := contributions[src.Name]
+				if !ok || !reported(c, name) {
+					continue
+				}
+				f.Contributors = append(f.Contributors, src.Name)
+				if latest == nil || src.LastSyncAt.After(latest.LastSyncAt) {
+					latest = &sources[i]
+				}
+			}
+			switch {
+			case len(f.Contributors) == 0:
+			case f.Strategy == asset.MergePriority:
+				f.Winner = f.Contributors[0]
+			case f.Strategy == asset.MergeLastWrite:
+				f.Winner = latest.Name
+			}
+		}
+
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// reported reports whether a source contributed a value for field.
+func reported(c asset.SourceContribution, field string) bool {
+	switch field {
+	case asset.FieldDescription:
+		return c.Description != nil && *c.Description != ""
+	case asset.FieldQuery:
+		return c.Query != nil && *c.Query != ""
+	case asset.FieldTags:
+		return len(c.Tags) > 0
+	case asset.FieldExternalLinks:
+		return len(c.ExternalLinks) > 0
+	case asset.FieldMetadata:
+		return len(c.Metadata) > 0
+	case asset.FieldSchema:
+		return len(c.Schema) > 0
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package provenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEvents(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	a := &asset.Asset{ID: "a1", CreatedAt: t0}
+
+	runs := []RunContribution{
+		{RunID: "r3", Pipeline: "dbt", Source: "dbt", Status: "unchanged", At: t0.Add(3 * time.Hour)},
+		{RunID: "r2", Pipeline: "dbt", Source: "dbt", Status: "updated", At: t0.Add(2*time.Hour + time.Second)},
+		{RunID: "r1", Pipeline: "warehouse", Source: "postgresql", Status: "created", At: t0},
+	}
+	revs := []*asset.Revision{
+		{ID: 2, Source: asset.RevisionSourceUser, ChangedBy: "alice", ChangedFields: []string{"tags"}, CreatedAt: t0.Add(150 * time.Minute)},
+		{ID: 1, Source: asset.RevisionSourceIngestion, ChangedBy: "dbt", ChangedFields: []string{"description"}, CreatedAt: t0.Add(2 * time.Hour)},
+	}
+
+	events := buildEvents(a, runs, revs)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, "r1", events[0].RunID, "the creating run stands in for the created event")
+	assert.Equal(t, "r2", events[1].RunID)
+	assert.Equal(t, int64(1), events[1].RevisionID, "ingestion revision is folded into its run")
+	assert.Equal(t, []string{"description"}, events[1].Fields)
+	assert.Equal(t, EventRevision, events[2].Type)
+	assert.Equal(t, "alice", events[2].Actor)
+	assert.Equal(t, "r3", events[3].RunID)
+	assert.Zero(t, events[3].RevisionID)
+}
+
+func TestFieldOrigins(t *testing.T) {
+	policy, err := asset.NewMergePolicy(map[string]int{"dbt": 100, "trino": 10}, nil)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	dbtDesc, trinoDesc := "Orders by day", "orders table"
+	a := &asset.Asset{
+		Sources: []asset.AssetSource{
+			policy.NewSource("trino", asset.SourceContribution{Description: &trinoDesc, Tags: []string{"raw"}}, now),
+			policy.NewSource("dbt", asset.SourceContribution{Description: &dbtDesc, Tags: []string{"mart"}}, now.Add(-time.Hour)),
+		},
+		LockedFields: []string{asset.FieldTags},
+	}
+
+	s := NewService(nil, nil, policy)
+	sources := s.rankSources(a.Sources)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "dbt", sources[0].Name)
+
+	edit := Event{Type: EventRevision, Actor: "alice", Fields: []string{asset.FieldTags}}
+	fields := s.fieldOrigins(a, sources, []Event{edit})
+
+	byName := map[string]Field{}
+	for _, f := range fields {
+		byName[f.Field] = f
+	}
+
+	desc := byName[asset.FieldDescription]
+	assert.Equal(t, asset.MergePriority, desc.Strategy)
+	assert.Equal(t, []string{"dbt", "trino"}, desc.Contributors)
+	assert.Equal(t, "dbt", desc.Winner)
+	assert.Nil(t, desc.LastChange)
+
+	tags := byName[asset.FieldTags]
+	assert.True(t, tags.Locked)
+	assert.Equal(t, asset.MergeUnion, tags.Strategy)
+	assert.Empty(t, tags.Winner)
+	require.NotNil(t, tags.LastChange)
+	assert.Equal(t, "alice", tags.LastChange.Actor)
+
+	assert.Empty(t, byName[asset.FieldUserDescription].Strategy)
+}
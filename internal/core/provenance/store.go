@@ -0,0 +1,59 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RunContribution is one pipeline run that processed an asset.
+type RunContribution struct {
+	// RunID is the run's ID in the runs API.
+	RunID     string
+	Pipeline  string
+	Source    string
+	Status    string
+	CreatedBy string
+	At        time.Time
+}
+
+// Repository reads the ingestion history of assets.
+type Repository interface {
+	// ListAssetRuns returns up to limit of the latest runs that processed
+	// the asset with the given MRN, newest first.
+	ListAssetRuns(ctx context.Context, mrn string, limit int) ([]RunContribution, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListAssetRuns(ctx context.Context, mrn string, limit int) ([]RunContribution, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.id, r.pipeline_name, r.source_name, e.status, COALESCE(r.created_by, ''), e.created_at
+		FROM run_entities e
+		JOIN runs r ON r.id = e.run_id
+		WHERE e.entity_type = 'asset' AND e.entity_mrn = $1
+		ORDER BY e.created_at DESC
+		LIMIT $2`, mrn, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying asset runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []RunContribution
+	for rows.Next() {
+		var c RunContribution
+		if err := rows.Scan(&c.RunID, &c.Pipeline, &c.Source, &c.Status, &c.CreatedBy, &c.At); err != nil {
+			return nil, fmt.Errorf("scanning asset run: %w", err)
+		}
+		runs = append(runs, c)
+	}
+	return runs, rows.Err()
+}
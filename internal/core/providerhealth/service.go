@@ -0,0 +1,363 @@
+package providerhealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/plugin"
+)
+
+const (
+	DefaultStaleAfter = 30 * 24 * time.Hour
+	DefaultTrendRuns  = 10
+	MaxTrendRuns      = 50
+
+	// FailingStreak is the number of consecutive failed runs after which a
+	// pipeline is failing rather than degraded.
+	FailingStreak = 3
+
+	// dropThreshold is the fraction of its previous asset count a pipeline
+	// may lose in one run before the drop is reported.
+	dropThreshold = 0.5
+)
+
+type Status string // @name ProviderHealthStatus
+
+const (
+	// StatusHealthy pipelines last succeeded recently with a steady asset
+	// count and no stale assets.
+	StatusHealthy Status = "healthy"
+	// StatusDegraded pipelines still succeed but recently failed, lost a
+	// large share of their assets or left assets stale.
+	StatusDegraded Status = "degraded"
+	// StatusFailing pipelines failed their last FailingStreak runs, or have
+	// never succeeded.
+	StatusFailing Status = "failing"
+	// StatusSilent pipelines have not succeeded within the stale window, or
+	// their last run reported no assets where earlier runs did. These are
+	// integrations that stopped working without reporting an error.
+	StatusSilent Status = "silent"
+)
+
+// severity orders statuses from best to worst.
+var severity = map[Status]int{
+	StatusHealthy:  0,
+	StatusDegraded: 1,
+	StatusSilent:   2,
+	StatusFailing:  3,
+}
+
+// Options controls how a report is built.
+type Options struct {
+	// StaleAfter is how long an asset may go without being synced before it
+	// counts as stale, and how long a pipeline may go without succeeding
+	// before it is silent.
+	StaleAfter time.Duration
+	// TrendRuns is the number of recent successful runs in asset trends.
+	TrendRuns int
+}
+
+// TrendPoint is the number of assets one successful run reported.
+type TrendPoint struct {
+	RunID       string    `json:"run_id"`
+	CompletedAt time.Time `json:"completed_at"`
+	Assets      int       `json:"assets"`
+} // @name ProviderHealthTrendPoint
+
+// PipelineHealth summarises the recent runs of one pipeline and source.
+type PipelineHealth struct {
+	Pipeline      string     `json:"pipeline"`
+	Source        string     `json:"source"`
+	Status        Status     `json:"status"`
+	Issues        []string   `json:"issues"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastRunStatus string     `json:"last_run_status,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	// ErrorStreak counts the failed runs since the last successful one.
+	ErrorStreak int `json:"error_streak"`
+	// AssetCount and StaleAssetCount cover the assets reported by the last
+	// successful run.
+	AssetCount      int          `json:"asset_count"`
+	StaleAssetCount int          `json:"stale_asset_count"`
+	AssetTrend      []TrendPoint `json:"asset_trend"`
+	Providers       []string     `json:"providers"`
+} // @name PipelineHealth
+
+// ProviderHealth summarises the assets of one provider and the pipelines
+// that ingest them.
+type ProviderHealth struct {
+	Provider        string     `json:"provider"`
+	Status          Status     `json:"status"`
+	AssetCount      int        `json:"asset_count"`
+	StaleAssetCount int        `json:"stale_asset_count"`
+	LastSyncAt      *time.Time `json:"last_sync_at,omitempty"`
+	// AssetsAdded counts assets first seen within the stale window.
+	AssetsAdded int      `json:"assets_added"`
+	Pipelines   []string `json:"pipelines"`
+} // @name ProviderHealth
+
+// Report is the health of every provider and pipeline.
+type Report struct {
+	GeneratedAt    time.Time         `json:"generated_at"`
+	StaleAfterDays int               `json:"stale_after_days"`
+	Counts         map[Status]int    `json:"counts"`
+	Providers      []*ProviderHealth `json:"providers"`
+	Pipelines      []*PipelineHealth `json:"pipelines"`
+} // @name ProviderHealthReport
+
+// PipelineKey identifies a pipeline run from one source.
+type PipelineKey struct {
+	Pipeline string
+	Source   string
+}
+
+// RunStats are the aggregate run facts of one pipeline.
+type RunStats struct {
+	PipelineKey
+	LastRunAt     time.Time
+	LastRunStatus string
+	LastSuccessAt *time.Time
+	LastError     string
+	ErrorStreak   int
+}
+
+// RunSummary is the summary of one successful run.
+type RunSummary struct {
+	PipelineKey
+	RunID       string
+	CompletedAt time.Time
+	Summary     *plugin.RunSummary
+}
+
+// AssetStats are the asset counts of a pipeline's last successful run.
+type AssetStats struct {
+	PipelineKey
+	Assets    int
+	Stale     int
+	Providers []string
+}
+
+// ProviderStats are the asset counts of one provider.
+type ProviderStats struct {
+	Provider   string
+	Assets     int
+	Stale      int
+	Added      int
+	LastSyncAt *time.Time
+}
+
+type Service struct {
+	repo       Repository
+	staleAfter time.Duration
+}
+
+// NewService creates a provider health service. staleAfter is the default
+// stale window, typically the archival policy's.
+func NewService(repo Repository, staleAfter time.Duration) *Service {
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	return &Service{repo: repo, staleAfter: staleAfter}
+}
+
+// Report builds the health of every pipeline that has run and every
+// provider with assets.
+func (s *Service) Report(ctx context.Context, opts Options) (*Report, error) {
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = s.staleAfter
+	}
+	if opts.TrendRuns <= 0 {
+		opts.TrendRuns = DefaultTrendRuns
+	}
+	if opts.TrendRuns > MaxTrendRuns {
+		opts.TrendRuns = MaxTrendRuns
+	}
+
+	now := time.Now().UTC()
+	staleBefore := now.Add(-opts.StaleAfter)
+
+	runStats, err := s.repo.ListRunStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing run stats: %w", err)
+	}
+	summaries, err := s.repo.ListRecentSummaries(ctx, opts.TrendRuns)
+	if err != nil {
+		return nil, fmt.Errorf("listing run summaries: %w", err)
+	}
+	assetStats, err := s.repo.ListPipelineAssets(ctx, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("listing pipeline assets: %w", err)
+	}
+	providerStats, err := s.repo.ListProviders(ctx, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("listing providers: %w", err)
+	}
+
+	return Build(now, opts.StaleAfter, runStats, summaries, assetStats, providerStats), nil
+}
+
+// Build assembles a report from repository results. Summaries must be
+// ordered newest first within each pipeline.
+func Build(now time.Time, staleAfter time.Duration, runStats []RunStats, summaries []RunSummary, assetStats []AssetStats, providerStats []ProviderStats) *Report {
+	trends := make(map[PipelineKey][]TrendPoint)
+	for _, rs := range summaries {
+		n, ok := assetsReported(rs.Summary)
+		if !ok {
+			continue
+		}
+		// Oldest first reads naturally as a trend.
+		trends[rs.PipelineKey] = append([]TrendPoint{{
+			RunID:       rs.RunID,
+			CompletedAt: rs.CompletedAt,
+			Assets:      n,
+		}}, trends[rs.PipelineKey]...)
+	}
+
+	assets := make(map[PipelineKey]AssetStats, len(assetStats))
+	for _, as := range assetStats {
+		assets[as.PipelineKey] = as
+	}
+
+	report := &Report{
+		GeneratedAt:    now,
+		StaleAfterDays: int(staleAfter / (24 * time.Hour)),
+		Counts:         map[Status]int{StatusHealthy: 0, StatusDegraded: 0, StatusFailing: 0, StatusSilent: 0},
+		Providers:      []*ProviderHealth{},
+		Pipelines:      []*PipelineHealth{},
+	}
+
+	providerPipelines := make(map[string][]*PipelineHealth)
+	for _, rs := range runStats {
+		lastRunAt := rs.LastRunAt
+		p := &PipelineHealth{
+			Pipeline:      rs.Pipeline,
+			Source:        rs.Source,
+			Issues:        []string{},
+			LastRunAt:     &lastRunAt,
+			LastRunStatus: rs.LastRunStatus,
+			LastSuccessAt: rs.LastSuccessAt,
+			LastError:     rs.LastError,
+			ErrorStreak:   rs.ErrorStreak,
+			AssetTrend:    trends[rs.PipelineKey],
+			Providers:     []string{},
+		}
+		if p.AssetTrend == nil {
+			p.AssetTrend = []TrendPoint{}
+		}
+		if as, ok := assets[rs.PipelineKey]; ok {
+			p.AssetCount = as.Assets
+			p.StaleAssetCount = as.Stale
+			if as.Providers != nil {
+				p.Providers = as.Providers
+			}
+		}
+		p.Status, p.Issues = classify(p, now.Add(-staleAfter))
+
+		report.Pipelines = append(report.Pipelines, p)
+		report.Counts[p.Status]++
+		for _, provider := range p.Providers {
+			providerPipelines[provider] = append(providerPipelines[provider], p)
+		}
+	}
+
+	for _, ps := range providerStats {
+		h := &ProviderHealth{
+			Provider:        ps.Provider,
+			Status:          StatusHealthy,
+			AssetCount:      ps.Assets,
+			StaleAssetCount: ps.Stale,
+			LastSyncAt:      ps.LastSyncAt,
+			AssetsAdded:     ps.Added,
+			Pipelines:       []string{},
+		}
+		if ps.Stale > 0 {
+			h.Status = StatusDegraded
+		}
+		for _, p := range providerPipelines[ps.Provider] {
+			h.Pipelines = append(h.Pipelines, p.Pipeline)
+			if severity[p.Status] > severity[h.Status] {
+				h.Status = p.Status
+			}
+		}
+		report.Providers = append(report.Providers, h)
+	}
+
+	// Worst first, so broken integrations lead the report.
+	sort.SliceStable(report.Pipelines, func(i, j int) bool {
+		a, b := report.Pipelines[i], report.Pipelines[j]
+		if severity[a.Status] != severity[b.Status] {
+			return severity[a.Status] > severity[b.Status]
+		}
+		if a.Pipeline != b.Pipeline {
+			return a.Pipeline < b.Pipeline
+		}
+		return a.Source < b.Source
+	})
+	sort.SliceStable(report.Providers, func(i, j int) bool {
+		a, b := report.Providers[i], report.Providers[j]
+		if severity[a.Status] != severity[b.Status] {
+			return severity[a.Status] > severity[b.Status]
+		}
+		return a.Provider < b.Provider
+	})
+
+	return report
+}
+
+// classify decides a pipeline's status, listing every issue found.
+func classify(p *PipelineHealth, staleBefore time.Time) (Status, []string) {
+	status := StatusHealthy
+	issues := []string{}
+	raise := func(s Status, issue string) {
+		if severity[s] > severity[status] {
+			status = s
+		}
+		issues = append(issues, issue)
+	}
+
+	switch {
+	case p.LastSuccessAt == nil && p.ErrorStreak > 0:
+		raise(StatusFailing, "has never completed successfully")
+	case p.ErrorStreak >= FailingStreak:
+		raise(StatusFailing, fmt.Sprintf("last %d runs failed", p.ErrorStreak))
+	case p.ErrorStreak > 0:
+		raise(StatusDegraded, fmt.Sprintf("last %d run(s) failed", p.ErrorStreak))
+	}
+
+	if p.LastSuccessAt != nil && p.LastSuccessAt.Before(staleBefore) {
+		raise(StatusSilent, fmt.Sprintf("no successful run since %s", p.LastSuccessAt.Format(time.RFC3339)))
+	}
+
+	if n := len(p.AssetTrend); n >= 2 {
+		prev, last := p.AssetTrend[n-2].Assets, p.AssetTrend[n-1].Assets
+		switch {
+		case prev > 0 && last == 0:
+			raise(StatusSilent, fmt.Sprintf("last run reported no assets, down from %d", prev))
+		case prev > 0 && float64(prev-last)/float64(prev) >= dropThreshold:
+			raise(StatusDegraded, fmt.Sprintf("asset count dropped from %d to %d", prev, last))
+		}
+	}
+
+	if p.StaleAssetCount > 0 {
+		raise(StatusDegraded, fmt.Sprintf("%d stale asset(s)", p.StaleAssetCount))
+	}
+
+	return status, issues
+}
+
+// assetsReported is the number of assets a run created, updated or found
+// unchanged. Summaries written before runs broke their counts down by entity
+// don't record unchanged assets, so they are left out of trends.
+func assetsReported(summary *plugin.RunSummary) (int, bool) {
+	if summary == nil || summary.Entities == nil {
+		return 0, false
+	}
+	c := summary.Entities["asset"]
+	if c == nil {
+		return 0, true
+	}
+	return c.Created + c.Updated + c.Unchanged, true
+}
@@ -0,0 +1,91 @@
+package providerhealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summary(assets int) *plugin.RunSummary {
+	return &plugin.RunSummary{Entities: plugin.EntitySummary{"asset": {Unchanged: assets}}}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	old := now.Add(-40 * 24 * time.Hour)
+
+	healthy := PipelineKey{Pipeline: "warehouse", Source: "postgresql"}
+	failing := PipelineKey{Pipeline: "events", Source: "kafka"}
+	emptied := PipelineKey{Pipeline: "lake", Source: "s3"}
+	silent := PipelineKey{Pipeline: "legacy", Source: "mysql"}
+
+	report := Build(now, DefaultStaleAfter,
+		[]RunStats{
+			{PipelineKey: healthy, LastRunAt: recent, LastRunStatus: "completed", LastSuccessAt: &recent},
+			{PipelineKey: failing, LastRunAt: recent, LastRunStatus: "failed", LastSuccessAt: &old, LastError: "broker unreachable", ErrorStreak: 4},
+			{PipelineKey: emptied, LastRunAt: recent, LastRunStatus: "completed", LastSuccessAt: &recent},
+			{PipelineKey: silent, LastRunAt: old, LastRunStatus: "completed", LastSuccessAt: &old},
+		},
+		[]RunSummary{
+			{PipelineKey: healthy, RunID: "h2", CompletedAt: recent, Summary: summary(10)},
+			{PipelineKey: healthy, RunID: "h1", CompletedAt: old, Summary: summary(9)},
+			{PipelineKey: emptied, RunID: "e2", CompletedAt: recent, Summary: summary(0)},
+			{PipelineKey: emptied, RunID: "e1", CompletedAt: old, Summary: summary(25)},
+			{PipelineKey: emptied, RunID: "e0", CompletedAt: old, Summary: &plugin.RunSummary{AssetsCreated: 3}},
+		},
+		[]AssetStats{
+			{PipelineKey: healthy, Assets: 10, Providers: []string{"PostgreSQL"}},
+			{PipelineKey: failing, Assets: 7, Stale: 7, Providers: []string{"Kafka"}},
+		},
+		[]ProviderStats{
+			{Provider: "PostgreSQL", Assets: 10},
+			{Provider: "Kafka", Assets: 7, Stale: 7},
+			{Provider: "Manual", Assets: 2},
+		},
+	)
+
+	require.Len(t, report.Pipelines, 4)
+	byName := make(map[string]*PipelineHealth)
+	for _, p := range report.Pipelines {
+		byName[p.Pipeline] = p
+	}
+
+	assert.Equal(t, StatusHealthy, byName["warehouse"].Status)
+	assert.Empty(t, byName["warehouse"].Issues)
+	assert.Equal(t, []TrendPoint{
+		{RunID: "h1", CompletedAt: old, Assets: 9},
+		{RunID: "h2", CompletedAt: recent, Assets: 10},
+	}, byName["warehouse"].AssetTrend)
+
+	assert.Equal(t, StatusFailing, byName["events"].Status)
+	assert.Len(t, byName["events"].Issues, 3)
+
+	assert.Equal(t, StatusSilent, byName["lake"].Status)
+	assert.Equal(t, []string{"last run reported no assets, down from 25"}, byName["lake"].Issues)
+	assert.Len(t, byName["lake"].AssetTrend, 2, "summaries without an entity breakdown are left out")
+
+	assert.Equal(t, StatusSilent, byName["legacy"].Status)
+
+	assert.Equal(t, "events", report.Pipelines[0].Pipeline, "worst pipelines come first")
+	assert.Equal(t, map[Status]int{StatusHealthy: 1, StatusDegraded: 0, StatusSilent: 2, StatusFailing: 1}, report.Counts)
+
+	require.Len(t, report.Providers, 3)
+	assert.Equal(t, "Kafka", report.Providers[0].Provider)
+	assert.Equal(t, StatusFailing, report.Providers[0].Status)
+	assert.Equal(t, []string{"events"}, report.Providers[0].Pipelines)
+	assert.Equal(t, StatusHealthy, report.Providers[1].Status)
+	assert.Equal(t, "Manual", report.Providers[1].Provider)
+	assert.Equal(t, StatusHealthy, report.Providers[2].Status)
+	assert.Equal(t, 30, report.StaleAfterDays)
+}
+
+func TestClassifyAssetDrop(t *testing.T) {
+	p := &PipelineHealth{AssetTrend: []TrendPoint{{Assets: 100}, {Assets: 40}}}
+	status, issues := classify(p, time.Now())
+	assert.Equal(t, StatusDegraded, status)
+	assert.Equal(t, []string{"asset count dropped from 100 to 40"}, issues)
+}
@@ -0,0 +1,156 @@
+package providerhealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository reads the run and asset facts a health report is built from.
+type Repository interface {
+	ListRunStats(ctx context.Context) ([]RunStats, error)
+	// ListRecentSummaries returns up to perPipeline of each pipeline's latest
+	// successful runs, newest first.
+	ListRecentSummaries(ctx context.Context, perPipeline int) ([]RunSummary, error)
+	ListPipelineAssets(ctx context.Context, staleBefore time.Time) ([]AssetStats, error)
+	ListProviders(ctx context.Context, staleBefore time.Time) ([]ProviderStats, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListRunStats(ctx context.Context) ([]RunStats, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH last_success AS (
+			SELECT pipeline_name, source_name, MAX(started_at) AS started_at, MAX(completed_at) AS completed_at
+			FROM runs
+			WHERE status = 'completed'
+			GROUP BY pipeline_name, source_name
+		)
+		SELECT r.pipeline_name, r.source_name,
+		       MAX(r.started_at),
+		       (array_agg(r.status ORDER BY r.started_at DESC))[1],
+		       ls.completed_at,
+		       COALESCE((array_agg(r.error_message ORDER BY r.started_at DESC) FILTER (WHERE r.status = 'failed'))[1], ''),
+		       COUNT(*) FILTER (WHERE r.status = 'failed' AND (ls.started_at IS NULL OR r.started_at > ls.started_at))
+		FROM runs r
+		LEFT JOIN last_success ls ON ls.pipeline_name = r.pipeline_name AND ls.source_name = r.source_name
+		GROUP BY r.pipeline_name, r.source_name, ls.started_at, ls.completed_at`)
+	if err != nil {
+		return nil, fmt.Errorf("querying run stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []RunStats
+	for rows.Next() {
+		var s RunStats
+		if err := rows.Scan(&s.Pipeline, &s.Source, &s.LastRunAt, &s.LastRunStatus, &s.LastSuccessAt, &s.LastError, &s.ErrorStreak); err != nil {
+			return nil, fmt.Errorf("scanning run stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *PostgresRepository) ListRecentSummaries(ctx context.Context, perPipeline int) ([]RunSummary, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT pipeline_name, source_name, run_id, completed_at, summary
+		FROM (
+			SELECT pipeline_name, source_name, run_id, completed_at, summary,
+			       ROW_NUMBER() OVER (PARTITION BY pipeline_name, source_name ORDER BY completed_at DESC) AS rn
+			FROM runs
+			WHERE status = 'completed' AND completed_at IS NOT NULL
+		) recent
+		WHERE rn <= $1
+		ORDER BY pipeline_name, source_name, completed_at DESC`, perPipeline)
+	if err != nil {
+		return nil, fmt.Errorf("querying run summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []RunSummary
+	for rows.Next() {
+		var s RunSummary
+		if err := rows.Scan(&s.Pipeline, &s.Source, &s.RunID, &s.CompletedAt, &s.Summary); err != nil {
+			return nil, fmt.Errorf("scanning run summary: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// lastSuccessfulRuns picks each pipeline's latest completed run, whose
+// checkpoints are the assets the pipeline currently reports.
+const lastSuccessfulRuns = `
+	WITH last_run AS (
+		SELECT DISTINCT ON (pipeline_name, source_name) id, pipeline_name, source_name
+		FROM runs
+		WHERE status = 'completed'
+		ORDER BY pipeline_name, source_name, completed_at DESC
+	),
+	pipeline_assets AS (
+		SELECT lr.pipeline_name, lr.source_name, a.id, a.last_sync_at, a.providers
+		FROM last_run lr
+		JOIN run_checkpoints c ON c.run_id = lr.id AND c.entity_type = 'asset' AND c.operation <> 'deleted'
+		JOIN assets a ON a.mrn = c.entity_mrn AND a.is_stub = FALSE
+	)`
+
+func (r *PostgresRepository) ListPipelineAssets(ctx context.Context, staleBefore time.Time) ([]AssetStats, error) {
+	rows, err := r.db.Query(ctx, lastSuccessfulRuns+`
+		SELECT pa.pipeline_name, pa.source_name,
+		       COUNT(DISTINCT pa.id),
+		       COUNT(DISTINCT pa.id) FILTER (WHERE pa.last_sync_at < $1),
+		       COALESCE((
+		           SELECT array_agg(DISTINCT p ORDER BY p)
+		           FROM pipeline_assets pp, unnest(pp.providers) AS p
+		           WHERE pp.pipeline_name = pa.pipeline_name AND pp.source_name = pa.source_name
+		       ), '{}')
+		FROM pipeline_assets pa
+		GROUP BY pa.pipeline_name, pa.source_name`, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("querying pipeline assets: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []AssetStats
+	for rows.Next() {
+		var s AssetStats
+		if err := rows.Scan(&s.Pipeline, &s.Source, &s.Assets, &s.Stale, &s.Providers); err != nil {
+			return nil, fmt.Errorf("scanning pipeline assets: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *PostgresRepository) ListProviders(ctx context.Context, staleBefore time.Time) ([]ProviderStats, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p, COUNT(*),
+		       COUNT(*) FILTER (WHERE a.last_sync_at < $1),
+		       COUNT(*) FILTER (WHERE a.created_at >= $1),
+		       MAX(a.last_sync_at)
+		FROM assets a, unnest(a.providers) AS p
+		WHERE a.is_stub = FALSE
+		GROUP BY p`, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("querying providers: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []ProviderStats
+	for rows.Next() {
+		var s ProviderStats
+		if err := rows.Scan(&s.Provider, &s.Assets, &s.Stale, &s.Added, &s.LastSyncAt); err != nil {
+			return nil, fmt.Errorf("scanning provider stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
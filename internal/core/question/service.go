@@ -0,0 +1,398 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound       = errors.New("question not found")
+	ErrAnswerNotFound = errors.New("answer not found")
+	ErrForbidden      = errors.New("not allowed to modify this question")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxTitleLength = 300
+	maxBodyLength  = 20000
+)
+
+// Question is a question asked about an asset.
+type Question struct {
+	ID               string    `json:"id"`
+	AssetID          string    `json:"asset_id"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"`
+	AcceptedAnswerID *string   `json:"accepted_answer_id,omitempty"`
+	AnswerCount      int       `json:"answer_count"`
+	CreatedBy        *string   `json:"created_by,omitempty"`
+	CreatedByName    string    `json:"created_by_name,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Answers          []*Answer `json:"answers,omitempty"`
+} // @name AssetQuestion
+
+// Answer is a reply to a question. ByOwner records whether the author owned
+// the asset when they answered, so owner answers can be highlighted.
+type Answer struct {
+	ID            string    `json:"id"`
+	QuestionID    string    `json:"question_id"`
+	Body          string    `json:"body"`
+	ByOwner       bool      `json:"by_owner"`
+	Accepted      bool      `json:"accepted"`
+	CreatedBy     *string   `json:"created_by,omitempty"`
+	CreatedByName string    `json:"created_by_name,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+} // @name AssetAnswer
+
+type AskInput struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+} // @name AskQuestionInput
+
+type UpdateInput struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+} // @name UpdateQuestionInput
+
+type AnswerInput struct {
+	Body string `json:"body"`
+} // @name AnswerQuestionInput
+
+type ListResult struct {
+	Questions []*Question `json:"questions"`
+	Total     int         `json:"total"`
+} // @name QuestionListResult
+
+// Actor is the user performing an operation. Moderators, typically users
+// with asset management permission, may edit or remove anyone's posts.
+type Actor struct {
+	UserID      string
+	CanModerate bool
+}
+
+// OwnerChecker reports whether a user owns an asset, directly or through a team.
+type OwnerChecker interface {
+	CanUserAccessAsset(ctx context.Context, userID, assetID string) (bool, error)
+}
+
+// Notifier is told about new questions and answers.
+type Notifier interface {
+	NotifyQuestionAsked(ctx context.Context, q *Question)
+	NotifyQuestionAnswered(ctx context.Context, q *Question, a *Answer)
+}
+
+// MentionTracker records the users, teams and glossary terms mentioned in
+// questions and answers.
+type MentionTracker interface {
+	TrackMentions(ctx context.Context, sourceType, sourceID, assetID, text, actorID string)
+	ForgetMentions(ctx context.Context, sourceType, sourceID string)
+}
+
+// Mention source types for questions and answers.
+const (
+	MentionSourceQuestion = "question"
+	MentionSourceAnswer   = "answer"
+)
+
+type Service struct {
+	repo     Repository
+	owners   OwnerChecker
+	notifier Notifier
+	mentions MentionTracker
+}
+
+func NewService(repo Repository, owners OwnerChecker) *Service {
+	return &Service{repo: repo, owners: owners}
+}
+
+// SetNotifier enables notifications for new questions and answers.
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// SetMentionTracker enables tracking of @ and # mentions in posts.
+func (s *Service) SetMentionTracker(t MentionTracker) {
+	s.mentions = t
+}
+
+func (s *Service) Ask(ctx context.Context, assetID string, input AskInput, actor Actor) (*Question, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Body = strings.TrimSpace(input.Body)
+	if err := validateQuestion(input.Title, input.Body); err != nil {
+		return nil, err
+	}
+	if assetID == "" {
+		return nil, &ValidationError{Message: "asset_id is required"}
+	}
+
+	q := &Question{
+		AssetID:   assetID,
+		Title:     input.Title,
+		Body:      input.Body,
+		CreatedBy: &actor.UserID,
+	}
+	if err := s.repo.CreateQuestion(ctx, q); err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.NotifyQuestionAsked(ctx, q)
+	}
+	s.trackQuestion(ctx, q, actor.UserID)
+
+	return q, nil
+}
+
+// Get returns a question with its answers, accepted answer first.
+func (s *Service) Get(ctx context.Context, id string) (*Question, error) {
+	q, err := s.repo.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := s.repo.ListAnswers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	q.Answers = answers
+
+	return q, nil
+}
+
+// ListForAsset returns an asset's questions, newest first. With unanswered
+// set, only questions without an accepted answer are returned.
+func (s *Service) ListForAsset(ctx context.Context, assetID string, unanswered bool, limit, offset int) (*ListResult, error) {
+	questions, total, err := s.repo.ListQuestions(ctx, assetID, unanswered, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Questions: questions, Total: total}, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput, actor Actor) (*Question, error) {
+	q, err := s.repo.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor(q.CreatedBy, actor) {
+		return nil, ErrForbidden
+	}
+
+	if input.Title != nil {
+		q.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Body != nil {
+		q.Body = strings.TrimSpace(*input.Body)
+	}
+	if err := validateQuestion(q.Title, q.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQuestion(ctx, q); err != nil {
+		return nil, err
+	}
+	s.trackQuestion(ctx, q, actor.UserID)
+
+	return q, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string, actor Actor) error {
+	q, err := s.repo.GetQuestion(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !isAuthor(q.CreatedBy, actor) {
+		return ErrForbidden
+	}
+
+	var answers []*Answer
+	if s.mentions != nil {
+		if answers, err = s.repo.ListAnswers(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.DeleteQuestion(ctx, id, q.AcceptedAnswerID != nil); err != nil {
+		return err
+	}
+
+	if s.mentions != nil {
+		s.mentions.ForgetMentions(ctx, MentionSourceQuestion, id)
+		for _, a := range answers {
+			s.mentions.ForgetMentions(ctx, MentionSourceAnswer, a.ID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) Answer(ctx context.Context, questionID string, input AnswerInput, actor Actor) (*Answer, error) {
+	input.Body = strings.TrimSpace(input.Body)
+	if err := validateAnswer(input.Body); err != nil {
+		return nil, err
+	}
+
+	q, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Answer{
+		QuestionID: questionID,
+		Body:       input.Body,
+		ByOwner:    s.isOwner(ctx, actor.UserID, q.AssetID),
+		CreatedBy:  &actor.UserID,
+	}
+	if err := s.repo.CreateAnswer(ctx, a); err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil && !isAuthor(q.CreatedBy, Actor{UserID: actor.UserID}) {
+		s.notifier.NotifyQuestionAnswered(ctx, q, a)
+	}
+	if s.mentions != nil {
+		s.mentions.TrackMentions(ctx, MentionSourceAnswer, a.ID, q.AssetID, a.Body, actor.UserID)
+	}
+
+	return a, nil
+}
+
+func (s *Service) UpdateAnswer(ctx context.Context, questionID, answerID string, input AnswerInput, actor Actor) (*Answer, error) {
+	input.Body = strings.TrimSpace(input.Body)
+	if err := validateAnswer(input.Body); err != nil {
+		return nil, err
+	}
+
+	a, err := s.repo.GetAnswer(ctx, questionID, answerID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor(a.CreatedBy, actor) {
+		return nil, ErrForbidden
+	}
+
+	a.Body = input.Body
+	if err := s.repo.UpdateAnswer(ctx, a); err != nil {
+		return nil, err
+	}
+	if s.mentions != nil {
+		q, err := s.repo.GetQuestion(ctx, questionID)
+		if err != nil {
+			return nil, err
+		}
+		s.mentions.TrackMentions(ctx, MentionSourceAnswer, a.ID, q.AssetID, a.Body, actor.UserID)
+	}
+
+	return a, nil
+}
+
+func (s *Service) DeleteAnswer(ctx context.Context, questionID, answerID string, actor Actor) error {
+	a, err := s.repo.GetAnswer(ctx, questionID, answerID)
+	if err != nil {
+		return err
+	}
+	if !isAuthor(a.CreatedBy, actor) {
+		return ErrForbidden
+	}
+
+	if err := s.repo.DeleteAnswer(ctx, questionID, answerID); err != nil {
+		return err
+	}
+	if s.mentions != nil {
+		s.mentions.ForgetMentions(ctx, MentionSourceAnswer, answerID)
+	}
+	return nil
+}
+
+// Accept marks answerID as the accepted answer, or clears the accepted
+// answer when answerID is empty. Only the asker and asset owners may accept,
+// since an accepted answer is indexed into search alongside the asset.
+func (s *Service) Accept(ctx context.Context, questionID, answerID string, actor Actor) (*Question, error) {
+	q, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor(q.CreatedBy, actor) && !s.isOwner(ctx, actor.UserID, q.AssetID) {
+		return nil, ErrForbidden
+	}
+
+	var accepted *string
+	if answerID != "" {
+		if _, err := s.repo.GetAnswer(ctx, questionID, answerID); err != nil {
+			return nil, err
+		}
+		accepted = &answerID
+	}
+
+	if err := s.repo.SetAcceptedAnswer(ctx, questionID, accepted); err != nil {
+		return nil, err
+	}
+	q.AcceptedAnswerID = accepted
+
+	return q, nil
+}
+
+func (s *Service) trackQuestion(ctx context.Context, q *Question, actorID string) {
+	if s.mentions != nil {
+		s.mentions.TrackMentions(ctx, MentionSourceQuestion, q.ID, q.AssetID, q.Title+"\n\n"+q.Body, actorID)
+	}
+}
+
+func (s *Service) isOwner(ctx context.Context, userID, assetID string) bool {
+	if s.owners == nil {
+		return false
+	}
+	ok, err := s.owners.CanUserAccessAsset(ctx, userID, assetID)
+	if err != nil {
+		log.Warn().Err(err).Str("asset_id", assetID).Msg("Failed to check asset ownership")
+		return false
+	}
+	return ok
+}
+
+func isAuthor(createdBy *string, actor Actor) bool {
+	if actor.CanModerate {
+		return true
+	}
+	return createdBy != nil && *createdBy == actor.UserID
+}
+
+func validateQuestion(title, body string) error {
+	if title == "" {
+		return &ValidationError{Message: "title is required"}
+	}
+	if len(title) > maxTitleLength {
+		return &ValidationError{Message: "title must be at most 300 characters"}
+	}
+	if len(body) > maxBodyLength {
+		return &ValidationError{Message: "body must be at most 20000 characters"}
+	}
+	return nil
+}
+
+func validateAnswer(body string) error {
+	if body == "" {
+		return &ValidationError{Message: "body is required"}
+	}
+	if len(body) > maxBodyLength {
+		return &ValidationError{Message: "body must be at most 20000 characters"}
+	}
+	return nil
+}
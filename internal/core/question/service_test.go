@@ -0,0 +1,128 @@
+package question
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	questions map[string]*Question
+	answers   map[string]*Answer
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{questions: map[string]*Question{}, answers: map[string]*Answer{}}
+}
+
+func (f *fakeRepo) CreateQuestion(ctx context.Context, q *Question) error {
+	q.ID = "q1"
+	f.questions[q.ID] = q
+	return nil
+}
+
+func (f *fakeRepo) GetQuestion(ctx context.Context, id string) (*Question, error) {
+	q, ok := f.questions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *q
+	return &cp, nil
+}
+
+func (f *fakeRepo) CreateAnswer(ctx context.Context, a *Answer) error {
+	a.ID = "a" + a.Body
+	f.answers[a.ID] = a
+	return nil
+}
+
+func (f *fakeRepo) GetAnswer(ctx context.Context, questionID, answerID string) (*Answer, error) {
+	a, ok := f.answers[answerID]
+	if !ok || a.QuestionID != questionID {
+		return nil, ErrAnswerNotFound
+	}
+	return a, nil
+}
+
+func (f *fakeRepo) SetAcceptedAnswer(ctx context.Context, questionID string, answerID *string) error {
+	f.questions[questionID].AcceptedAnswerID = answerID
+	return nil
+}
+
+type ownerSet map[string]bool
+
+func (o ownerSet) CanUserAccessAsset(ctx context.Context, userID, assetID string) (bool, error) {
+	return o[userID], nil
+}
+
+type recordingNotifier struct {
+	asked    int
+	answered int
+}
+
+func (n *recordingNotifier) NotifyQuestionAsked(ctx context.Context, q *Question) { n.asked++ }
+
+func (n *recordingNotifier) NotifyQuestionAnswered(ctx context.Context, q *Question, a *Answer) {
+	n.answered++
+}
+
+func TestService_AskAnswerAccept(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	notifier := &recordingNotifier{}
+	svc := NewService(repo, ownerSet{"owner": true})
+	svc.SetNotifier(notifier)
+
+	asker := Actor{UserID: "asker"}
+	owner := Actor{UserID: "owner"}
+	bystander := Actor{UserID: "bystander"}
+
+	_, err := svc.Ask(ctx, "asset-1", AskInput{Title: "  "}, asker)
+	assert.True(t, IsValidationError(err))
+
+	q, err := svc.Ask(ctx, "asset-1", AskInput{Title: "Is amount in cents?"}, asker)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notifier.asked)
+
+	ownerAnswer, err := svc.Answer(ctx, q.ID, AnswerInput{Body: "1"}, owner)
+	require.NoError(t, err)
+	assert.True(t, ownerAnswer.ByOwner)
+	assert.Equal(t, 1, notifier.answered)
+
+	selfAnswer, err := svc.Answer(ctx, q.ID, AnswerInput{Body: "2"}, asker)
+	require.NoError(t, err)
+	assert.False(t, selfAnswer.ByOwner)
+	assert.Equal(t, 1, notifier.answered, "askers are not notified of their own answers")
+
+	_, err = svc.Accept(ctx, q.ID, ownerAnswer.ID, bystander)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	accepted, err := svc.Accept(ctx, q.ID, ownerAnswer.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, ownerAnswer.ID, *accepted.AcceptedAnswerID)
+
+	_, err = svc.Accept(ctx, q.ID, "missing", asker)
+	assert.ErrorIs(t, err, ErrAnswerNotFound)
+
+	cleared, err := svc.Accept(ctx, q.ID, "", asker)
+	require.NoError(t, err)
+	assert.Nil(t, cleared.AcceptedAnswerID)
+}
+
+func TestService_EditRequiresAuthor(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newFakeRepo(), ownerSet{"owner": true})
+
+	q, err := svc.Ask(ctx, "asset-1", AskInput{Title: "Who maintains this?"}, Actor{UserID: "asker"})
+	require.NoError(t, err)
+
+	title := "Edited"
+	_, err = svc.Update(ctx, q.ID, UpdateInput{Title: &title}, Actor{UserID: "owner"})
+	assert.ErrorIs(t, err, ErrForbidden, "owning the asset does not allow editing questions")
+
+	err = svc.Delete(ctx, q.ID, Actor{UserID: "someone"})
+	assert.ErrorIs(t, err, ErrForbidden)
+}
@@ -0,0 +1,295 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the question and answer data access interface.
+type Repository interface {
+	CreateQuestion(ctx context.Context, q *Question) error
+	UpdateQuestion(ctx context.Context, q *Question) error
+	DeleteQuestion(ctx context.Context, id string, reindex bool) error
+	GetQuestion(ctx context.Context, id string) (*Question, error)
+	ListQuestions(ctx context.Context, assetID string, unanswered bool, limit, offset int) ([]*Question, int, error)
+	CreateAnswer(ctx context.Context, a *Answer) error
+	UpdateAnswer(ctx context.Context, a *Answer) error
+	DeleteAnswer(ctx context.Context, questionID, answerID string) error
+	GetAnswer(ctx context.Context, questionID, answerID string) (*Answer, error)
+	ListAnswers(ctx context.Context, questionID string) ([]*Answer, error)
+	SetAcceptedAnswer(ctx context.Context, questionID string, answerID *string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectQuestion = `
+	SELECT q.id, q.asset_id, q.title, q.body, q.accepted_answer_id,
+	       (SELECT COUNT(*) FROM asset_answers a WHERE a.question_id = q.id),
+	       q.created_by, COALESCE(u.name, ''), q.created_at, q.updated_at
+	FROM asset_questions q
+	LEFT JOIN users u ON u.id = q.created_by`
+
+const selectAnswer = `
+	SELECT a.id, a.question_id, a.body, a.by_owner, COALESCE(q.accepted_answer_id = a.id, FALSE),
+	       a.created_by, COALESCE(u.name, ''), a.created_at, a.updated_at
+	FROM asset_answers a
+	JOIN asset_questions q ON q.id = a.question_id
+	LEFT JOIN users u ON u.id = a.created_by`
+
+// reindexAsset re-runs the search_index triggers for an asset so accepted
+// answers are added to or removed from its search text.
+const reindexAsset = `
+	UPDATE search_index si SET search_text = a.search_text
+	FROM assets a
+	WHERE si.type = 'asset' AND si.entity_id = a.id AND a.id = $1`
+
+func (r *PostgresRepository) CreateQuestion(ctx context.Context, q *Question) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_questions (asset_id, title, body, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		q.AssetID, q.Title, q.Body, q.CreatedBy,
+	).Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return &ValidationError{Message: "asset not found"}
+		}
+		return fmt.Errorf("creating question: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) UpdateQuestion(ctx context.Context, q *Question) error {
+	return r.withReindex(ctx, q.ID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE asset_questions SET title = $1, body = $2, updated_at = NOW()
+			WHERE id = $3
+			RETURNING updated_at`,
+			q.Title, q.Body, q.ID,
+		).Scan(&q.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+func (r *PostgresRepository) DeleteQuestion(ctx context.Context, id string, reindex bool) error {
+	var assetID string
+	err := r.db.QueryRow(ctx, `DELETE FROM asset_questions WHERE id = $1 RETURNING asset_id`, id).Scan(&assetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("deleting question: %w", err)
+	}
+
+	if reindex {
+		if _, err := r.db.Exec(ctx, reindexAsset, assetID); err != nil {
+			return fmt.Errorf("reindexing asset: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetQuestion(ctx context.Context, id string) (*Question, error) {
+	q, err := scanQuestion(r.db.QueryRow(ctx, selectQuestion+` WHERE q.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting question: %w", err)
+	}
+	return q, nil
+}
+
+func (r *PostgresRepository) ListQuestions(ctx context.Context, assetID string, unanswered bool, limit, offset int) ([]*Question, int, error) {
+	where := ` WHERE q.asset_id = $1`
+	if unanswered {
+		where += ` AND q.accepted_answer_id IS NULL`
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_questions q`+where, assetID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting questions: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectQuestion+where+` ORDER BY q.created_at DESC LIMIT $2 OFFSET $3`, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []*Question{}
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning question: %w", err)
+		}
+		questions = append(questions, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating questions: %w", err)
+	}
+
+	return questions, total, nil
+}
+
+func (r *PostgresRepository) CreateAnswer(ctx context.Context, a *Answer) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_answers (question_id, body, by_owner, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		a.QuestionID, a.Body, a.ByOwner, a.CreatedBy,
+	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrNotFound
+		}
+		return fmt.Errorf("creating answer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) UpdateAnswer(ctx context.Context, a *Answer) error {
+	return r.withReindex(ctx, a.QuestionID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE asset_answers SET body = $1, updated_at = NOW()
+			WHERE id = $2 AND question_id = $3
+			RETURNING updated_at`,
+			a.Body, a.ID, a.QuestionID,
+		).Scan(&a.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAnswerNotFound
+		}
+		return err
+	})
+}
+
+func (r *PostgresRepository) DeleteAnswer(ctx context.Context, questionID, answerID string) error {
+	return r.withReindex(ctx, questionID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM asset_answers WHERE id = $1 AND question_id = $2`, answerID, questionID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAnswerNotFound
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) GetAnswer(ctx context.Context, questionID, answerID string) (*Answer, error) {
+	a, err := scanAnswer(r.db.QueryRow(ctx, selectAnswer+` WHERE a.id = $1 AND a.question_id = $2`, answerID, questionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAnswerNotFound
+		}
+		return nil, fmt.Errorf("getting answer: %w", err)
+	}
+	return a, nil
+}
+
+func (r *PostgresRepository) ListAnswers(ctx context.Context, questionID string) ([]*Answer, error) {
+	rows, err := r.db.Query(ctx, selectAnswer+`
+		WHERE a.question_id = $1
+		ORDER BY (q.accepted_answer_id = a.id) DESC NULLS LAST, a.by_owner DESC, a.created_at ASC`, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing answers: %w", err)
+	}
+	defer rows.Close()
+
+	answers := []*Answer{}
+	for rows.Next() {
+		a, err := scanAnswer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning answer: %w", err)
+		}
+		answers = append(answers, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating answers: %w", err)
+	}
+
+	return answers, nil
+}
+
+func (r *PostgresRepository) SetAcceptedAnswer(ctx context.Context, questionID string, answerID *string) error {
+	return r.withReindex(ctx, questionID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE asset_questions SET accepted_answer_id = $1, updated_at = NOW()
+			WHERE id = $2`, answerID, questionID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// withReindex runs fn and refreshes the question's asset in search_index in
+// the same transaction, so accepted Q&A text never drifts from the tables.
+func (r *PostgresRepository) withReindex(ctx context.Context, questionID string, fn func(pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrAnswerNotFound) {
+			return err
+		}
+		return fmt.Errorf("updating question: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE search_index si SET search_text = a.search_text
+		FROM assets a, asset_questions q
+		WHERE si.type = 'asset' AND si.entity_id = a.id AND a.id = q.asset_id AND q.id = $1`, questionID); err != nil {
+		return fmt.Errorf("reindexing asset: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanQuestion(row pgx.Row) (*Question, error) {
+	var q Question
+	if err := row.Scan(
+		&q.ID, &q.AssetID, &q.Title, &q.Body, &q.AcceptedAnswerID, &q.AnswerCount,
+		&q.CreatedBy, &q.CreatedByName, &q.CreatedAt, &q.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func scanAnswer(row pgx.Row) (*Answer, error) {
+	var a Answer
+	if err := row.Scan(
+		&a.ID, &a.QuestionID, &a.Body, &a.ByOwner, &a.Accepted,
+		&a.CreatedBy, &a.CreatedByName, &a.CreatedAt, &a.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
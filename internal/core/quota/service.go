@@ -0,0 +1,337 @@
+// Package quota caps how much a deployment ingests, so a runaway plugin
+// can't flood a shared catalog, and warns admins before a cap is reached.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	KindAssets = "assets"
+	KindStubs  = "stubs"
+	KindRuns   = "runs"
+
+	// DefaultWarnAt is the percentage of a limit at which admins are warned.
+	DefaultWarnAt = 80
+
+	// runWindow is the period MaxRunsPerDay applies to.
+	runWindow = 24 * time.Hour
+)
+
+var ErrExceeded = errors.New("quota exceeded")
+
+// Limits are the quotas of a deployment. A limit of 0 is unlimited.
+type Limits struct {
+	// MaxAssetsPerProvider caps the assets of each provider. An asset
+	// counts towards every provider it lists.
+	MaxAssetsPerProvider int
+	// Providers overrides MaxAssetsPerProvider by provider name, matched
+	// case-insensitively.
+	Providers map[string]int
+	// MaxStubs caps the placeholder assets lineage may create for
+	// endpoints that haven't been ingested.
+	MaxStubs int
+	// MaxRunsPerDay caps the ingestion runs started in any 24 hours.
+	MaxRunsPerDay int
+	// WarnAt is the percentage of a limit at which admins are warned.
+	WarnAt int
+}
+
+// ProviderLimit returns the asset limit of provider, or 0 when it is
+// unlimited.
+func (l Limits) ProviderLimit(provider string) int {
+	for name, limit := range l.Providers {
+		if strings.EqualFold(name, provider) {
+			return limit
+		}
+	}
+	return l.MaxAssetsPerProvider
+}
+
+func (l Limits) enabled() bool {
+	if l.MaxAssetsPerProvider > 0 || l.MaxStubs > 0 || l.MaxRunsPerDay > 0 {
+		return true
+	}
+	for _, limit := range l.Providers {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type Status string // @name QuotaStatus
+
+const (
+	StatusOK       Status = "ok"
+	StatusWarning  Status = "warning"
+	StatusExceeded Status = "exceeded"
+)
+
+// Usage is how much of one limit is used.
+type Usage struct {
+	Kind string `json:"kind" enums:"assets,stubs,runs"`
+	// Provider is set for asset limits.
+	Provider string `json:"provider,omitempty"`
+	Used     int    `json:"used"`
+	Limit    int    `json:"limit"`
+	Status   Status `json:"status"`
+} // @name QuotaUsage
+
+// key identifies the limit a usage is measured against.
+func (u Usage) key() string {
+	return u.Kind + "/" + strings.ToLower(u.Provider)
+}
+
+// Report is the usage of every configured limit.
+type Report struct {
+	Enabled     bool      `json:"enabled"`
+	WarnAt      int       `json:"warn_at"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Usage       []Usage   `json:"usage"`
+} // @name QuotaReport
+
+// Notifier tells admins that a limit is nearly or fully used.
+type Notifier interface {
+	NotifyQuota(ctx context.Context, usage Usage, warnAt int)
+}
+
+// Service enforces and reports on quotas.
+type Service struct {
+	repo     Repository
+	limits   Limits
+	notifier Notifier
+	now      func() time.Time
+}
+
+// NewService creates a quota service enforcing limits.
+func NewService(repo Repository, limits Limits) *Service {
+	if limits.WarnAt <= 0 || limits.WarnAt > 100 {
+		limits.WarnAt = DefaultWarnAt
+	}
+	return &Service{
+		repo:   repo,
+		limits: limits,
+		now:    time.Now,
+	}
+}
+
+// SetNotifier registers where early warnings are sent.
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// Enabled reports whether any limit is configured.
+func (s *Service) Enabled() bool {
+	return s.limits.enabled()
+}
+
+func (s *Service) status(used, limit int) Status {
+	switch {
+	case used >= limit:
+		return StatusExceeded
+	case used*100 >= limit*s.limits.WarnAt:
+		return StatusWarning
+	default:
+		return StatusOK
+	}
+}
+
+// CheckRun returns ErrExceeded when MaxRunsPerDay runs were already started
+// in the last 24 hours.
+func (s *Service) CheckRun(ctx context.Context) error {
+	limit := s.limits.MaxRunsPerDay
+	if limit <= 0 {
+		return nil
+	}
+
+	started, err := s.repo.CountRunsSince(ctx, s.now().Add(-runWindow))
+	if err != nil {
+		return fmt.Errorf("counting runs: %w", err)
+	}
+	if started >= limit {
+		return fmt.Errorf("%w: %d runs were started in the last 24 hours, the limit is %d", ErrExceeded, started, limit)
+	}
+	return nil
+}
+
+// RemainingStubs returns how many more stub assets may be created, and
+// false when stubs are unlimited.
+func (s *Service) RemainingStubs(ctx context.Context) (int, bool, error) {
+	limit := s.limits.MaxStubs
+	if limit <= 0 {
+		return 0, false, nil
+	}
+
+	stubs, err := s.repo.CountStubs(ctx)
+	if err != nil {
+		return 0, true, fmt.Errorf("counting stubs: %w", err)
+	}
+	return max(limit-stubs, 0), true, nil
+}
+
+// AssetBudget tracks how many more assets each provider may gain while one
+// batch of assets is ingested. Providers are counted when first seen, so
+// batches ingested concurrently can overshoot a limit by a batch at most.
+// It isn't safe for concurrent use.
+type AssetBudget struct {
+	svc       *Service
+	remaining map[string]int
+}
+
+// AssetBudget starts a budget for one batch of assets.
+func (s *Service) AssetBudget() *AssetBudget {
+	return &AssetBudget{svc: s, remaining: make(map[string]int)}
+}
+
+// Allow takes one asset from the budget of each of providers, or returns
+// ErrExceeded without taking any when a provider has reached its limit.
+func (b *AssetBudget) Allow(ctx context.Context, providers []string) error {
+	var limited []string
+	for _, provider := range providers {
+		limit := b.svc.limits.ProviderLimit(provider)
+		if limit <= 0 {
+			continue
+		}
+
+		remaining, ok := b.remaining[provider]
+		if !ok {
+			count, err := b.svc.repo.CountProviderAssets(ctx, provider)
+			if err != nil {
+				return fmt.Errorf("counting %s assets: %w", provider, err)
+			}
+			remaining = limit - count
+			b.remaining[provider] = remaining
+		}
+		if remaining <= 0 {
+			return fmt.Errorf("%w: %s has reached its limit of %d assets", ErrExceeded, provider, limit)
+		}
+		limited = append(limited, provider)
+	}
+
+	for _, provider := range limited {
+		b.remaining[provider]--
+	}
+	return nil
+}
+
+// Report returns the usage of every configured limit. Asset usage is listed
+// for each provider with a limit that has assets.
+func (s *Service) Report(ctx context.Context) (*Report, error) {
+	report := &Report{
+		Enabled:     s.Enabled(),
+		WarnAt:      s.limits.WarnAt,
+		GeneratedAt: s.now(),
+		Usage:       []Usage{},
+	}
+
+	if s.limits.MaxAssetsPerProvider > 0 || len(s.limits.Providers) > 0 {
+		counts, err := s.repo.CountAssetsByProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("counting assets: %w", err)
+		}
+		providers := make([]string, 0, len(counts))
+		for provider := range counts {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+
+		for _, provider := range providers {
+			limit := s.limits.ProviderLimit(provider)
+			if limit <= 0 {
+				continue
+			}
+			report.Usage = append(report.Usage, s.usage(KindAssets, provider, counts[provider], limit))
+		}
+	}
+
+	if limit := s.limits.MaxStubs; limit > 0 {
+		stubs, err := s.repo.CountStubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("counting stubs: %w", err)
+		}
+		report.Usage = append(report.Usage, s.usage(KindStubs, "", stubs, limit))
+	}
+
+	if limit := s.limits.MaxRunsPerDay; limit > 0 {
+		started, err := s.repo.CountRunsSince(ctx, s.now().Add(-runWindow))
+		if err != nil {
+			return nil, fmt.Errorf("counting runs: %w", err)
+		}
+		report.Usage = append(report.Usage, s.usage(KindRuns, "", started, limit))
+	}
+
+	return report, nil
+}
+
+func (s *Service) usage(kind, provider string, used, limit int) Usage {
+	return Usage{
+		Kind:     kind,
+		Provider: provider,
+		Used:     used,
+		Limit:    limit,
+		Status:   s.status(used, limit),
+	}
+}
+
+// CheckOnce warns admins about limits that reached the warning threshold
+// or were exceeded since the last check. Each limit is notified once per
+// status change, and again only after it drops back below the threshold.
+// It returns the number of warnings sent.
+func (s *Service) CheckOnce(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	report, err := s.Report(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	notified, err := s.repo.ListWarnings(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing quota warnings: %w", err)
+	}
+
+	sent := 0
+	for _, usage := range report.Usage {
+		key := usage.key()
+		previous, warned := notified[key]
+
+		if usage.Status == StatusOK {
+			if warned {
+				if err := s.repo.ClearWarning(ctx, key); err != nil {
+					return sent, fmt.Errorf("clearing quota warning: %w", err)
+				}
+			}
+			continue
+		}
+		if warned && previous == usage.Status {
+			continue
+		}
+
+		if s.notifier != nil {
+			s.notifier.NotifyQuota(ctx, usage, report.WarnAt)
+		}
+		if err := s.repo.SetWarning(ctx, key, usage.Status, s.now()); err != nil {
+			return sent, fmt.Errorf("recording quota warning: %w", err)
+		}
+		sent++
+
+		log.Warn().
+			Str("kind", usage.Kind).
+			Str("provider", usage.Provider).
+			Int("used", usage.Used).
+			Int("limit", usage.Limit).
+			Msg("Quota " + string(usage.Status))
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,171 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	assets   map[string]int
+	stubs    int
+	runs     int
+	warnings map[string]Status
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{assets: map[string]int{}, warnings: map[string]Status{}}
+}
+
+func (r *fakeRepo) CountProviderAssets(_ context.Context, provider string) (int, error) {
+	return r.assets[provider], nil
+}
+
+func (r *fakeRepo) CountAssetsByProvider(context.Context) (map[string]int, error) {
+	return r.assets, nil
+}
+
+func (r *fakeRepo) CountStubs(context.Context) (int, error) { return r.stubs, nil }
+
+func (r *fakeRepo) CountRunsSince(context.Context, time.Time) (int, error) { return r.runs, nil }
+
+func (r *fakeRepo) ListWarnings(context.Context) (map[string]Status, error) {
+	warnings := make(map[string]Status, len(r.warnings))
+	for k, v := range r.warnings {
+		warnings[k] = v
+	}
+	return warnings, nil
+}
+
+func (r *fakeRepo) SetWarning(_ context.Context, key string, status Status, _ time.Time) error {
+	r.warnings[key] = status
+	return nil
+}
+
+func (r *fakeRepo) ClearWarning(_ context.Context, key string) error {
+	delete(r.warnings, key)
+	return nil
+}
+
+type fakeNotifier struct {
+	usages []Usage
+}
+
+func (n *fakeNotifier) NotifyQuota(_ context.Context, usage Usage, _ int) {
+	n.usages = append(n.usages, usage)
+}
+
+func TestCheckRun(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo, Limits{MaxRunsPerDay: 3})
+
+	repo.runs = 2
+	assert.NoError(t, svc.CheckRun(context.Background()))
+
+	repo.runs = 3
+	assert.ErrorIs(t, svc.CheckRun(context.Background()), ErrExceeded)
+
+	unlimited := NewService(repo, Limits{})
+	assert.NoError(t, unlimited.CheckRun(context.Background()))
+}
+
+func TestAssetBudget(t *testing.T) {
+	repo := newFakeRepo()
+	repo.assets["Kafka"] = 8
+	repo.assets["S3"] = 100
+	svc := NewService(repo, Limits{
+		MaxAssetsPerProvider: 10,
+		Providers:            map[string]int{"s3": 0, "postgresql": 1},
+	})
+	ctx := context.Background()
+
+	budget := svc.AssetBudget()
+	require.NoError(t, budget.Allow(ctx, []string{"Kafka"}))
+	require.NoError(t, budget.Allow(ctx, []string{"Kafka"}))
+	assert.ErrorIs(t, budget.Allow(ctx, []string{"Kafka"}), ErrExceeded)
+
+	// The override of 0 makes S3 unlimited.
+	assert.NoError(t, budget.Allow(ctx, []string{"S3"}))
+
+	// An asset over any of its providers' limits takes nothing from the rest.
+	require.NoError(t, budget.Allow(ctx, []string{"PostgreSQL"}))
+	assert.ErrorIs(t, budget.Allow(ctx, []string{"Airflow", "PostgreSQL"}), ErrExceeded)
+	assert.Equal(t, 10, budget.remaining["Airflow"])
+}
+
+func TestRemainingStubs(t *testing.T) {
+	repo := newFakeRepo()
+	repo.stubs = 12
+	ctx := context.Background()
+
+	remaining, limited, err := NewService(repo, Limits{MaxStubs: 10}).RemainingStubs(ctx)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, 0, remaining)
+
+	_, limited, err = NewService(repo, Limits{}).RemainingStubs(ctx)
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestReport(t *testing.T) {
+	repo := newFakeRepo()
+	repo.assets = map[string]int{"Kafka": 85, "S3": 10, "BigQuery": 5}
+	repo.stubs = 50
+	svc := NewService(repo, Limits{
+		MaxAssetsPerProvider: 100,
+		Providers:            map[string]int{"S3": 0},
+		MaxStubs:             50,
+	})
+
+	report, err := svc.Report(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Enabled)
+	assert.Equal(t, DefaultWarnAt, report.WarnAt)
+	assert.Equal(t, []Usage{
+		{Kind: KindAssets, Provider: "BigQuery", Used: 5, Limit: 100, Status: StatusOK},
+		{Kind: KindAssets, Provider: "Kafka", Used: 85, Limit: 100, Status: StatusWarning},
+		{Kind: KindStubs, Used: 50, Limit: 50, Status: StatusExceeded},
+	}, report.Usage)
+}
+
+func TestCheckOnce(t *testing.T) {
+	repo := newFakeRepo()
+	notifier := &fakeNotifier{}
+	svc := NewService(repo, Limits{MaxRunsPerDay: 10, WarnAt: 50})
+	svc.SetNotifier(notifier)
+	ctx := context.Background()
+
+	repo.runs = 6
+	sent, err := svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, StatusWarning, notifier.usages[0].Status)
+
+	// Still warning, so nothing new is sent.
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	repo.runs = 10
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, StatusExceeded, notifier.usages[1].Status)
+
+	// Dropping below the threshold clears the warning so it can fire again.
+	repo.runs = 1
+	_, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, repo.warnings)
+
+	repo.runs = 5
+	sent, err = svc.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, notifier.usages, 3)
+}
@@ -0,0 +1,117 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository counts what quotas limit and remembers the warnings sent.
+type Repository interface {
+	CountProviderAssets(ctx context.Context, provider string) (int, error)
+	CountAssetsByProvider(ctx context.Context) (map[string]int, error)
+	CountStubs(ctx context.Context) (int, error)
+	CountRunsSince(ctx context.Context, since time.Time) (int, error)
+	// ListWarnings returns the status last notified for each limit.
+	ListWarnings(ctx context.Context) (map[string]Status, error)
+	SetWarning(ctx context.Context, key string, status Status, at time.Time) error
+	ClearWarning(ctx context.Context, key string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CountProviderAssets(ctx context.Context, provider string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM assets
+		WHERE providers @> ARRAY[$1]::text[] AND is_stub = FALSE`, provider).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting provider assets: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) CountAssetsByProvider(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.provider, COUNT(*)
+		FROM assets a, unnest(a.providers) AS p(provider)
+		WHERE a.is_stub = FALSE
+		GROUP BY p.provider`)
+	if err != nil {
+		return nil, fmt.Errorf("counting assets by provider: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var provider string
+		var count int
+		if err := rows.Scan(&provider, &count); err != nil {
+			return nil, fmt.Errorf("scanning provider count: %w", err)
+		}
+		counts[provider] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *PostgresRepository) CountStubs(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM assets WHERE is_stub = TRUE`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting stubs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) CountRunsSince(ctx context.Context, since time.Time) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM runs WHERE started_at >= $1`, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting runs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) ListWarnings(ctx context.Context) (map[string]Status, error) {
+	rows, err := r.db.Query(ctx, `SELECT key, status FROM quota_warnings`)
+	if err != nil {
+		return nil, fmt.Errorf("querying quota warnings: %w", err)
+	}
+	defer rows.Close()
+
+	warnings := make(map[string]Status)
+	for rows.Next() {
+		var key string
+		var status Status
+		if err := rows.Scan(&key, &status); err != nil {
+			return nil, fmt.Errorf("scanning quota warning: %w", err)
+		}
+		warnings[key] = status
+	}
+	return warnings, rows.Err()
+}
+
+func (r *PostgresRepository) SetWarning(ctx context.Context, key string, status Status, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO quota_warnings (key, status, notified_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET status = EXCLUDED.status, notified_at = EXCLUDED.notified_at`,
+		key, status, at)
+	if err != nil {
+		return fmt.Errorf("upserting quota warning: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ClearWarning(ctx context.Context, key string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM quota_warnings WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("deleting quota warning: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package runs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultArtifactRetention     = 14 * 24 * time.Hour
+	DefaultArtifactMaxSize       = 50 << 20
+	DefaultArtifactPruneInterval = time.Hour
+)
+
+var ErrArtifactNotFound = errors.New("run artifact not found")
+
+// RunPayload is what a plugin emitted in one batch of a run, exactly as it
+// was received.
+type RunPayload struct {
+	Assets        []CreateAssetInput   `json:"assets"`
+	Lineage       []LineageInput       `json:"lineage,omitempty"`
+	Documentation []DocumentationInput `json:"documentation,omitempty"`
+	Statistics    []StatisticInput     `json:"statistics,omitempty"`
+}
+
+// RunArtifact describes a stored payload. A run sending its entities in
+// several batches has one artifact per batch, numbered from 1.
+type RunArtifact struct {
+	RunID              string `json:"run_id"`
+	Sequence           int    `json:"sequence"`
+	AssetCount         int    `json:"asset_count"`
+	LineageCount       int    `json:"lineage_count"`
+	DocumentationCount int    `json:"documentation_count"`
+	StatisticCount     int    `json:"statistic_count"`
+	// SizeBytes is the compressed size; RawSizeBytes the size of the JSON.
+	SizeBytes    int       `json:"size_bytes"`
+	RawSizeBytes int       `json:"raw_size_bytes"`
+	CreatedAt    time.Time `json:"created_at"`
+} // @name RunArtifact
+
+// ArtifactConfig controls which run payloads are kept.
+type ArtifactConfig struct {
+	// MaxSize is the largest compressed payload stored, in bytes. Larger
+	// payloads are skipped.
+	MaxSize int
+}
+
+// SetArtifactCapture makes runs keep the raw payload of every batch they
+// process, gzip-compressed, for debugging. A nil config stops capture.
+func (s *service) SetArtifactCapture(config *ArtifactConfig) {
+	if config != nil && config.MaxSize <= 0 {
+		config.MaxSize = DefaultArtifactMaxSize
+	}
+	s.artifactConfig = config
+}
+
+// captureArtifact stores a batch's payload. Failing to store it doesn't
+// fail the run.
+func (s *service) captureArtifact(ctx context.Context, runDBID string, payload *RunPayload) {
+	if s.artifactConfig == nil {
+		return
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		log.Warn().Err(err).Str("run_id", runDBID).Msg("Failed to encode run artifact")
+		return
+	}
+	data, err := compressArtifact(raw)
+	if err != nil {
+		log.Warn().Err(err).Str("run_id", runDBID).Msg("Failed to compress run artifact")
+		return
+	}
+	if len(data) > s.artifactConfig.MaxSize {
+		log.Warn().
+			Str("run_id", runDBID).
+			Int("size_bytes", len(data)).
+			Int("max_size_bytes", s.artifactConfig.MaxSize).
+			Msg("Run artifact too large to store")
+		return
+	}
+
+	artifact := &RunArtifact{
+		RunID:              runDBID,
+		AssetCount:         len(payload.Assets),
+		LineageCount:       len(payload.Lineage),
+		DocumentationCount: len(payload.Documentation),
+		StatisticCount:     len(payload.Statistics),
+		SizeBytes:          len(data),
+		RawSizeBytes:       len(raw),
+	}
+	if err := s.repo.SaveArtifact(ctx, artifact, data); err != nil {
+		log.Warn().Err(err).Str("run_id", runDBID).Msg("Failed to store run artifact")
+	}
+}
+
+// ListArtifacts returns the stored payloads of a run, in the order they
+// were received.
+func (s *service) ListArtifacts(ctx context.Context, runDBID string) ([]*RunArtifact, error) {
+	if runDBID == "" {
+		return nil, fmt.Errorf("%w: run id is required", ErrInvalidInput)
+	}
+	return s.repo.ListArtifacts(ctx, runDBID)
+}
+
+// GetArtifact returns a stored payload as gzip-compressed JSON.
+func (s *service) GetArtifact(ctx context.Context, runDBID string, sequence int) (*RunArtifact, []byte, error) {
+	if runDBID == "" {
+		return nil, nil, fmt.Errorf("%w: run id is required", ErrInvalidInput)
+	}
+	return s.repo.GetArtifact(ctx, runDBID, sequence)
+}
+
+// PruneArtifacts deletes payloads older than retention.
+func (s *service) PruneArtifacts(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		retention = DefaultArtifactRetention
+	}
+	deleted, err := s.repo.DeleteArtifactsBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		log.Info().Int64("deleted", deleted).Msg("Pruned run artifacts")
+	}
+	return deleted, nil
+}
+
+func compressArtifact(raw []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressArtifact returns the JSON of a compressed payload.
+func DecompressArtifact(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading run artifact: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// ArtifactPruner periodically deletes expired run artifacts.
+type ArtifactPruner struct {
+	task *background.SingletonTask
+}
+
+// ArtifactPrunerConfig configures the pruner.
+type ArtifactPrunerConfig struct {
+	// Retention is how long artifacts are kept. Default: 14 days.
+	Retention time.Duration
+	// Interval between prunes. Default: 1 hour.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewArtifactPruner creates a pruner for svc's artifacts.
+func NewArtifactPruner(svc Service, config *ArtifactPrunerConfig) *ArtifactPruner {
+	if config.Interval <= 0 {
+		config.Interval = DefaultArtifactPruneInterval
+	}
+
+	return &ArtifactPruner{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "run-artifact-pruning",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.PruneArtifacts(ctx, config.Retention)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins periodic pruning.
+func (p *ArtifactPruner) Start(ctx context.Context) {
+	p.task.Start(ctx)
+}
+
+// Stop stops pruning.
+func (p *ArtifactPruner) Stop() {
+	p.task.Stop()
+}
+
+const artifactColumns = `run_id, sequence, asset_count, lineage_count, documentation_count,
+	statistic_count, size_bytes, raw_size_bytes, created_at`
+
+func (r *PostgresRepository) SaveArtifact(ctx context.Context, artifact *RunArtifact, data []byte) error {
+	// Batches of a run arrive one after another, so numbering by the
+	// current maximum is safe; the primary key catches the rare overlap.
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO run_artifacts (run_id, sequence, asset_count, lineage_count, documentation_count,
+			statistic_count, size_bytes, raw_size_bytes, data)
+		SELECT $1, COALESCE(MAX(sequence), 0) + 1, $2, $3, $4, $5, $6, $7, $8
+		FROM run_artifacts WHERE run_id = $1
+		RETURNING sequence, created_at`,
+		artifact.RunID, artifact.AssetCount, artifact.LineageCount, artifact.DocumentationCount,
+		artifact.StatisticCount, artifact.SizeBytes, artifact.RawSizeBytes, data,
+	).Scan(&artifact.Sequence, &artifact.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting run artifact: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListArtifacts(ctx context.Context, runDBID string) ([]*RunArtifact, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+artifactColumns+`
+		FROM run_artifacts
+		WHERE run_id = $1
+		ORDER BY sequence`, runDBID)
+	if err != nil {
+		return nil, fmt.Errorf("listing run artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	artifacts := []*RunArtifact{}
+	for rows.Next() {
+		var a RunArtifact
+		if err := rows.Scan(&a.RunID, &a.Sequence, &a.AssetCount, &a.LineageCount, &a.DocumentationCount,
+			&a.StatisticCount, &a.SizeBytes, &a.RawSizeBytes, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning run artifact: %w", err)
+		}
+		artifacts = append(artifacts, &a)
+	}
+	return artifacts, rows.Err()
+}
+
+func (r *PostgresRepository) GetArtifact(ctx context.Context, runDBID string, sequence int) (*RunArtifact, []byte, error) {
+	var a RunArtifact
+	var data []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT `+artifactColumns+`, data
+		FROM run_artifacts
+		WHERE run_id = $1 AND sequence = $2`, runDBID, sequence,
+	).Scan(&a.RunID, &a.Sequence, &a.AssetCount, &a.LineageCount, &a.DocumentationCount,
+		&a.StatisticCount, &a.SizeBytes, &a.RawSizeBytes, &a.CreatedAt, &data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrArtifactNotFound
+		}
+		return nil, nil, fmt.Errorf("getting run artifact: %w", err)
+	}
+	return &a, data, nil
+}
+
+func (r *PostgresRepository) DeleteArtifactsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM run_artifacts WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("deleting run artifacts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,142 @@
+package runs
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultBatchSize is how many entities a run records per write unless
+// SetBatchSize says otherwise.
+const DefaultBatchSize = 500
+
+// SetBatchSize sets how many entities a run writes to the database at once,
+// when creating assets, recording run entities and checkpoints, and loading
+// assets to update. Values below 1 restore DefaultBatchSize.
+func (s *service) SetBatchSize(size int) {
+	if size < 1 {
+		size = DefaultBatchSize
+	}
+	s.batchSize = size
+}
+
+func (s *service) effectiveBatchSize() int {
+	if s.batchSize < 1 {
+		return DefaultBatchSize
+	}
+	return s.batchSize
+}
+
+// entityRecorder buffers the run entity and checkpoint of each processed
+// entity and writes them in batches, so a large run costs a few statements
+// per batch rather than several per entity.
+type entityRecorder struct {
+	repo        Repository
+	runDBID     string
+	runID       string
+	size        int
+	entities    []*RunEntity
+	checkpoints []*plugin.RunCheckpoint
+}
+
+func (s *service) newEntityRecorder(runDBID, runID string) *entityRecorder {
+	size := s.effectiveBatchSize()
+	return &entityRecorder{
+		repo:        s.repo,
+		runDBID:     runDBID,
+		runID:       runID,
+		size:        size,
+		entities:    make([]*RunEntity, 0, size),
+		checkpoints: make([]*plugin.RunCheckpoint, 0, size),
+	}
+}
+
+// record adds an entity's outcome, flushing once a batch is full.
+func (r *entityRecorder) record(ctx context.Context, entityType, entityMRN, entityName, status, errorMessage string, sourceFields []string) {
+	now := time.Now()
+	r.entities = append(r.entities, &RunEntity{
+		ID:           uuid.New().String(),
+		RunID:        r.runID,
+		EntityType:   entityType,
+		EntityMRN:    entityMRN,
+		EntityName:   entityName,
+		Status:       status,
+		ErrorMessage: errorMessage,
+		CreatedAt:    now,
+	})
+	r.checkpoints = append(r.checkpoints, &plugin.RunCheckpoint{
+		ID:           uuid.New().String(),
+		RunID:        r.runID,
+		EntityType:   entityType,
+		EntityMRN:    entityMRN,
+		Operation:    status,
+		SourceFields: sourceFields,
+		CreatedAt:    now,
+	})
+
+	if len(r.entities) >= r.size {
+		r.flush(ctx)
+	}
+}
+
+// flush writes what is buffered. Failures are logged rather than failing the
+// run, as they only affect the run's history.
+func (r *entityRecorder) flush(ctx context.Context) {
+	if len(r.entities) > 0 {
+		if err := r.repo.AddRunEntities(ctx, r.runDBID, r.entities); err != nil {
+			log.Error().Err(err).Str("run_id", r.runID).Int("count", len(r.entities)).Msg("Failed to add run entities")
+		}
+		r.entities = r.entities[:0]
+	}
+	if len(r.checkpoints) > 0 {
+		if err := r.repo.AddCheckpoints(ctx, r.runDBID, r.checkpoints); err != nil {
+			log.Error().Err(err).Str("run_id", r.runID).Int("count", len(r.checkpoints)).Msg("Failed to add checkpoints")
+		}
+		r.checkpoints = r.checkpoints[:0]
+	}
+}
+
+// createAssets creates assets a batch at a time, returning the created asset
+// for each input in order, or nil where creating it failed.
+func (s *service) createAssets(ctx context.Context, inputs []asset.CreateInput) []*asset.Asset {
+	created := make([]*asset.Asset, 0, len(inputs))
+	size := s.effectiveBatchSize()
+	for start := 0; start < len(inputs); start += size {
+		end := min(start+size, len(inputs))
+		batch, errs := s.assetService.CreateMany(ctx, inputs[start:end])
+		for i, err := range errs {
+			if err != nil {
+				log.Error().Err(err).Str("asset_mrn", *inputs[start+i].MRN).Msg("Failed to create asset")
+			}
+		}
+		created = append(created, batch...)
+	}
+	return created
+}
+
+// loadExisting fetches, a batch at a time, the assets among mrns that the run
+// will update. Assets missing from the result are looked up one by one.
+func (s *service) loadExisting(ctx context.Context, mrns []string) map[string]*asset.Asset {
+	existing := make(map[string]*asset.Asset, len(mrns))
+	size := s.effectiveBatchSize()
+	for start := 0; start < len(mrns); start += size {
+		end := min(start+size, len(mrns))
+		found, err := s.assetService.GetByMRNs(ctx, mrns[start:end])
+		if err != nil {
+			log.Warn().Err(err).Int("count", end-start).Msg("Failed to load assets to update, falling back to single lookups")
+			continue
+		}
+		for assetMRN, ast := range found {
+			// Stubs are left out to match the single lookup, as GetByMRN
+			// doesn't return them either.
+			if !ast.IsStub {
+				existing[assetMRN] = ast
+			}
+		}
+	}
+	return existing
+}
@@ -0,0 +1,130 @@
+package runs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchRepo struct {
+	Repository
+	entityBatches     [][]*RunEntity
+	checkpointBatches [][]*plugin.RunCheckpoint
+}
+
+func (r *batchRepo) AddRunEntities(_ context.Context, _ string, entities []*RunEntity) error {
+	r.entityBatches = append(r.entityBatches, append([]*RunEntity(nil), entities...))
+	return nil
+}
+
+func (r *batchRepo) AddCheckpoints(_ context.Context, _ string, checkpoints []*plugin.RunCheckpoint) error {
+	r.checkpointBatches = append(r.checkpointBatches, append([]*plugin.RunCheckpoint(nil), checkpoints...))
+	return nil
+}
+
+func TestEntityRecorderFlushesFullBatches(t *testing.T) {
+	repo := &batchRepo{}
+	svc := &service{repo: repo}
+	svc.SetBatchSize(2)
+
+	ctx := context.Background()
+	recorder := svc.newEntityRecorder("run-db-id", "run-id")
+	recorder.record(ctx, "asset", "mrn://a", "a", StatusCreated, "", []string{"h1"})
+	assert.Empty(t, repo.entityBatches)
+
+	recorder.record(ctx, "asset", "mrn://b", "b", StatusUpdated, "", []string{"h2"})
+	recorder.record(ctx, "lineage", "mrn://c", "c", StatusFailed, "boom", nil)
+	require.Len(t, repo.entityBatches, 1)
+	assert.Len(t, repo.entityBatches[0], 2)
+
+	recorder.flush(ctx)
+	require.Len(t, repo.entityBatches, 2)
+	require.Len(t, repo.checkpointBatches, 2)
+	assert.Equal(t, "boom", repo.entityBatches[1][0].ErrorMessage)
+	assert.Equal(t, StatusFailed, repo.checkpointBatches[1][0].Operation)
+
+	recorder.flush(ctx)
+	assert.Len(t, repo.entityBatches, 2, "flushing an empty recorder writes nothing")
+}
+
+// createAssets records each CreateMany batch and fails inputs whose MRN is
+// listed.
+type createAssets struct {
+	asset.Service
+	failing map[string]bool
+	batches [][]string
+}
+
+func (s *createAssets) CreateMany(_ context.Context, inputs []asset.CreateInput) ([]*asset.Asset, []error) {
+	var mrns []string
+	created := make([]*asset.Asset, len(inputs))
+	errs := make([]error, len(inputs))
+	for i, input := range inputs {
+		mrns = append(mrns, *input.MRN)
+		if s.failing[*input.MRN] {
+			errs[i] = asset.ErrAlreadyExists
+			continue
+		}
+		created[i] = &asset.Asset{ID: *input.MRN, MRN: input.MRN}
+	}
+	s.batches = append(s.batches, mrns)
+	return created, errs
+}
+
+func TestProcessEntitiesCreatesAssetsInBatches(t *testing.T) {
+	assets := &createAssets{failing: map[string]bool{"mrn://table/postgres/b": true}}
+	svc := &service{repo: &runRepo{}, assetService: assets}
+	svc.SetBatchSize(2)
+
+	var inputs []CreateAssetInput
+	for _, name := range []string{"a", "b", "c"} {
+		mrn := "mrn://table/postgres/" + name
+		inputs = append(inputs, CreateAssetInput{Name: name, MRN: &mrn, Type: "Table", Providers: []string{"PostgreSQL"}})
+	}
+
+	resp, err := svc.ProcessEntityBatch(context.Background(), "run-1", inputs, nil, nil, nil, "pipeline", "source")
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"mrn://table/postgres/a", "mrn://table/postgres/b"},
+		{"mrn://table/postgres/c"},
+	}, assets.batches)
+	require.Len(t, resp.Assets, 3)
+	assert.Equal(t, StatusCreated, resp.Assets[0].Status)
+	assert.Equal(t, StatusFailed, resp.Assets[1].Status)
+	assert.Equal(t, StatusCreated, resp.Assets[2].Status)
+}
+
+func TestSetBatchSizeDefaults(t *testing.T) {
+	svc := &service{}
+	assert.Equal(t, DefaultBatchSize, svc.effectiveBatchSize())
+
+	svc.SetBatchSize(0)
+	assert.Equal(t, DefaultBatchSize, svc.effectiveBatchSize())
+
+	svc.SetBatchSize(50)
+	assert.Equal(t, 50, svc.effectiveBatchSize())
+}
+
+func TestLastPerEntity(t *testing.T) {
+	entities := []*RunEntity{
+		{EntityType: "asset", EntityMRN: "a", Status: StatusCreated},
+		{EntityType: "asset", EntityMRN: "b", Status: StatusCreated},
+		{EntityType: "lineage", EntityMRN: "a", Status: StatusCreated},
+		{EntityType: "asset", EntityMRN: "a", Status: StatusFailed},
+	}
+	key := func(e *RunEntity) string { return e.EntityType + "\x00" + e.EntityMRN }
+
+	kept := lastPerEntity(entities, key)
+	require.Len(t, kept, 3)
+	assert.Equal(t, "b", kept[0].EntityMRN)
+	assert.Equal(t, "lineage", kept[1].EntityType)
+	assert.Equal(t, StatusFailed, kept[2].Status)
+
+	unique := entities[:3]
+	assert.Equal(t, unique, lastPerEntity(unique, key))
+}
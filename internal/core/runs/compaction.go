@@ -0,0 +1,112 @@
+package runs
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultCheckpointHistory is how many checkpoints are kept per entity
+	// and pipeline: the latest plus four earlier ones.
+	DefaultCheckpointHistory  = 5
+	DefaultCompactionInterval = time.Hour
+)
+
+// CompactionResult reports one checkpoint compaction.
+type CompactionResult struct {
+	Deleted  int64         `json:"deleted"`
+	Retained int64         `json:"retained"`
+	Duration time.Duration `json:"duration"`
+}
+
+// CompactCheckpoints deletes run checkpoints beyond the latest history per
+// entity and pipeline. Every run checkpoints every entity it reports, so
+// without compaction checkpoints grow with each run of each pipeline.
+func (s *service) CompactCheckpoints(ctx context.Context, history int) (*CompactionResult, error) {
+	if history < 1 {
+		history = DefaultCheckpointHistory
+	}
+
+	start := time.Now()
+	deleted, err := s.repo.CompactCheckpoints(ctx, history)
+	if err != nil {
+		return nil, err
+	}
+	retained, err := s.repo.CountCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompactionResult{
+		Deleted:  deleted,
+		Retained: retained,
+		Duration: time.Since(start),
+	}
+	if s.metricsRecorder != nil {
+		s.metricsRecorder.RecordCheckpointCompaction(ctx, result.Deleted, result.Retained, result.Duration)
+	}
+
+	log.Info().
+		Int64("deleted", result.Deleted).
+		Int64("retained", result.Retained).
+		Dur("duration", result.Duration).
+		Msg("Compacted run checkpoints")
+
+	return result, nil
+}
+
+// Compactor periodically compacts run checkpoints.
+type Compactor struct {
+	task *background.SingletonTask
+}
+
+// CompactorConfig configures the compactor.
+type CompactorConfig struct {
+	// Interval between compactions. Default: 1 hour.
+	Interval time.Duration
+	// History is how many checkpoints to keep per entity and pipeline.
+	// Default: 5.
+	History int
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewCompactor creates a new checkpoint compactor for svc.
+func NewCompactor(svc Service, config *CompactorConfig) *Compactor {
+	if config == nil {
+		config = &CompactorConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultCompactionInterval
+	}
+	if config.History < 1 {
+		config.History = DefaultCheckpointHistory
+	}
+
+	return &Compactor{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "run-checkpoint-compaction",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 10 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.CompactCheckpoints(ctx, config.History)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins periodic compaction.
+func (c *Compactor) Start(ctx context.Context) {
+	c.task.Start(ctx)
+}
+
+// Stop stops compaction.
+func (c *Compactor) Stop() {
+	c.task.Stop()
+}
@@ -0,0 +1,129 @@
+package runs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/store/postgres/postgrestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type checkpointFixture struct {
+	t   *testing.T
+	db  *pgxpool.Pool
+	now time.Time
+}
+
+// run inserts a run of pipeline/source that started hoursAgo hours ago and
+// checkpoints the given entity MRNs.
+func (f *checkpointFixture) run(pipeline, source, runID, status string, hoursAgo int, mrns ...string) {
+	f.t.Helper()
+	ctx := context.Background()
+
+	started := f.now.Add(-time.Duration(hoursAgo) * time.Hour)
+	var completed *time.Time
+	if status != "running" {
+		end := started.Add(time.Minute)
+		completed = &end
+	}
+
+	var id string
+	require.NoError(f.t, f.db.QueryRow(ctx, `
+		INSERT INTO runs (pipeline_name, source_name, run_id, status, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id::text`, pipeline, source, runID, status, started, completed).Scan(&id))
+
+	for _, mrn := range mrns {
+		_, err := f.db.Exec(ctx, `
+			INSERT INTO run_checkpoints (run_id, entity_type, entity_mrn, operation)
+			VALUES ($1, 'asset', $2, 'create')`, id, mrn)
+		require.NoError(f.t, err)
+	}
+}
+
+// remaining lists the checkpoints left, as "run_id entity_mrn".
+func (f *checkpointFixture) remaining() []string {
+	f.t.Helper()
+	rows, err := f.db.Query(context.Background(), `
+		SELECT r.run_id || ' ' || c.entity_mrn
+		FROM run_checkpoints c
+		JOIN runs r ON r.id = c.run_id`)
+	require.NoError(f.t, err)
+	defer rows.Close()
+
+	var left []string
+	for rows.Next() {
+		var s string
+		require.NoError(f.t, rows.Scan(&s))
+		left = append(left, s)
+	}
+	require.NoError(f.t, rows.Err())
+	return left
+}
+
+func TestCompactCheckpoints(t *testing.T) {
+	f := &checkpointFixture{t: t, db: postgrestest.New(t), now: time.Now()}
+	repo := NewPostgresRepository(f.db)
+	ctx := context.Background()
+
+	// Newest first: two failed runs, then the last completed run, two older
+	// completed runs and a run that has been stuck running since before them.
+	f.run("warehouse", "postgres", "failed-2", "failed", 1, "mrn://a")
+	f.run("warehouse", "postgres", "failed-1", "failed", 2, "mrn://a")
+	f.run("warehouse", "postgres", "completed-3", "completed", 3, "mrn://a")
+	f.run("warehouse", "postgres", "completed-2", "completed", 4, "mrn://a")
+	f.run("warehouse", "postgres", "completed-1", "completed", 5, "mrn://a", "mrn://b")
+	f.run("warehouse", "postgres", "stuck", "running", 6, "mrn://a")
+	// Another source of the same pipeline keeps its own history.
+	f.run("warehouse", "mysql", "mysql-1", "completed", 10, "mrn://a")
+	f.run("warehouse", "mysql", "mysql-2", "failed", 9, "mrn://a")
+
+	deleted, err := repo.CompactCheckpoints(ctx, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, deleted)
+
+	assert.ElementsMatch(t, []string{
+		// Within the history of two.
+		"failed-2 mrn://a",
+		"failed-1 mrn://a",
+		// Beyond it, but the last completed run is read by change detection.
+		"completed-3 mrn://a",
+		// mrn://b's only checkpoint is its latest.
+		"completed-1 mrn://b",
+		// Running runs are never compacted.
+		"stuck mrn://a",
+		"mysql-1 mrn://a",
+		"mysql-2 mrn://a",
+	}, f.remaining())
+
+	deleted, err = repo.CompactCheckpoints(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "compaction is idempotent")
+
+	count, err := repo.CountCheckpoints(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, count)
+}
+
+func TestCompactCheckpointsHistoryCutoff(t *testing.T) {
+	f := &checkpointFixture{t: t, db: postgrestest.New(t), now: time.Now()}
+	repo := NewPostgresRepository(f.db)
+	ctx := context.Background()
+
+	for i, runID := range []string{"run-1", "run-2", "run-3", "run-4"} {
+		f.run("warehouse", "postgres", runID, "completed", 4-i, "mrn://a")
+	}
+
+	deleted, err := repo.CompactCheckpoints(ctx, 3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.ElementsMatch(t, []string{"run-2 mrn://a", "run-3 mrn://a", "run-4 mrn://a"}, f.remaining())
+
+	deleted, err = repo.CompactCheckpoints(ctx, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, deleted)
+	assert.Equal(t, []string{"run-4 mrn://a"}, f.remaining())
+}
@@ -0,0 +1,40 @@
+package runs
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConnectionResolver supplies the provider and decrypted config of a shared
+// connection referenced by a schedule.
+type ConnectionResolver interface {
+	ResolveConnection(ctx context.Context, id string) (provider string, config map[string]interface{}, err error)
+}
+
+// ApplyConnection merges the schedule's connection config over its own
+// config. Connection values win, so rotating a credential on the connection
+// takes effect for every schedule on its next run.
+func ApplyConnection(ctx context.Context, schedule *Schedule, resolver ConnectionResolver) error {
+	if schedule.ConnectionID == nil {
+		return nil
+	}
+	if resolver == nil {
+		return fmt.Errorf("schedule uses connection %s but connections are not available", *schedule.ConnectionID)
+	}
+
+	provider, config, err := resolver.ResolveConnection(ctx, *schedule.ConnectionID)
+	if err != nil {
+		return fmt.Errorf("resolving connection: %w", err)
+	}
+	if provider != schedule.PluginID {
+		return fmt.Errorf("connection is for %s but schedule uses %s", provider, schedule.PluginID)
+	}
+
+	if schedule.Config == nil {
+		schedule.Config = make(map[string]interface{}, len(config))
+	}
+	for key, value := range config {
+		schedule.Config[key] = value
+	}
+	return nil
+}
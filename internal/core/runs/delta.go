@@ -0,0 +1,84 @@
+package runs
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxDeltaChanges caps the changes listed in a catalog delta. Consumers of a
+// truncated delta should resync the whole pipeline instead.
+const MaxDeltaChanges = 1000
+
+// DeltaChange is one entity a run created, updated or deleted.
+type DeltaChange struct {
+	MRN        string `json:"mrn"`
+	EntityType string `json:"entity_type"`
+	Change     string `json:"change"`
+} // @name CatalogDeltaChange
+
+// CatalogDelta lists what a run changed in the catalog, so downstream caches
+// can invalidate only those entities. Unchanged and failed entities are left
+// out.
+type CatalogDelta struct {
+	RunID       string    `json:"run_id"`
+	Pipeline    string    `json:"pipeline"`
+	Source      string    `json:"source"`
+	Status      string    `json:"status"`
+	CompletedAt time.Time `json:"completed_at"`
+	// Counts has the number of changes of each kind, including any left
+	// out of a truncated Changes list.
+	Counts    map[string]int `json:"counts"`
+	Changes   []DeltaChange  `json:"changes"`
+	Truncated bool           `json:"truncated"`
+} // @name CatalogDelta
+
+// DeltaPublisher is sent the catalog delta of every finished run that
+// changed something.
+type DeltaPublisher interface {
+	PublishCatalogDelta(ctx context.Context, delta *CatalogDelta)
+}
+
+func (s *service) SetDeltaPublisher(publisher DeltaPublisher) {
+	s.deltaPublisher = publisher
+}
+
+// publishDelta builds and publishes a finished run's catalog delta. Failed
+// and cancelled runs are included, since entities they processed before
+// stopping were still changed.
+func (s *service) publishDelta(ctx context.Context, run *plugin.Run) {
+	if s.deltaPublisher == nil {
+		return
+	}
+
+	changes, counts, err := s.repo.ListRunChanges(ctx, run.ID, MaxDeltaChanges)
+	if err != nil {
+		log.Error().Err(err).Str("run_id", run.RunID).Msg("Failed to build catalog delta")
+		return
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total == 0 {
+		return
+	}
+
+	delta := &CatalogDelta{
+		RunID:     run.RunID,
+		Pipeline:  run.PipelineName,
+		Source:    run.SourceName,
+		Status:    string(run.Status),
+		Counts:    counts,
+		Changes:   changes,
+		Truncated: total > len(changes),
+	}
+	if run.CompletedAt != nil {
+		delta.CompletedAt = *run.CompletedAt
+	}
+
+	s.deltaPublisher.PublishCatalogDelta(ctx, delta)
+}
@@ -0,0 +1,114 @@
+package runs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxScheduleConcurrency caps a schedule's max_concurrent_runs.
+	MaxScheduleConcurrency = 100
+	// MaxScheduleTimeout caps a schedule's timeout.
+	MaxScheduleTimeout = 7 * 24 * time.Hour
+)
+
+// ValidateScheduleLimits returns ErrInvalidScheduleLimits when a
+// concurrency limit or timeout is out of range.
+func ValidateScheduleLimits(maxConcurrentRuns int, timeoutSeconds *int) error {
+	if maxConcurrentRuns < 0 || maxConcurrentRuns > MaxScheduleConcurrency {
+		return fmt.Errorf("%w: max_concurrent_runs must be between 0 and %d", ErrInvalidScheduleLimits, MaxScheduleConcurrency)
+	}
+	if timeoutSeconds != nil && (*timeoutSeconds < 0 || time.Duration(*timeoutSeconds)*time.Second > MaxScheduleTimeout) {
+		return fmt.Errorf("%w: timeout_seconds must be between 0 and %d", ErrInvalidScheduleLimits, int(MaxScheduleTimeout.Seconds()))
+	}
+	return nil
+}
+
+// SetScheduleLimits sets how many runs of a schedule may be in progress at
+// once, 0 meaning no limit, and how long each may take. A nil or zero
+// timeoutSeconds removes the timeout.
+func (s *ScheduleService) SetScheduleLimits(ctx context.Context, id string, maxConcurrentRuns int, timeoutSeconds *int) (*Schedule, error) {
+	if err := ValidateScheduleLimits(maxConcurrentRuns, timeoutSeconds); err != nil {
+		return nil, err
+	}
+	if timeoutSeconds != nil && *timeoutSeconds == 0 {
+		timeoutSeconds = nil
+	}
+
+	if err := s.repo.SetScheduleLimits(ctx, id, maxConcurrentRuns, timeoutSeconds); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSchedule(ctx, id)
+}
+
+// AbortTimedOutJobRuns fails runs still going grace after their schedule's
+// timeout. Workers stop runs at the timeout themselves; this catches runs
+// whose worker died or whose plugin ignored cancellation.
+func (s *ScheduleService) AbortTimedOutJobRuns(ctx context.Context, grace time.Duration) (int, error) {
+	ids, err := s.repo.AbortTimedOutJobRuns(ctx, grace)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if run, err := s.repo.GetJobRun(ctx, id); err == nil {
+			s.broadcaster.BroadcastJobRunCompleted(run)
+		}
+	}
+
+	return len(ids), nil
+}
+
+// timeoutMessage is recorded on runs aborted for exceeding their timeout.
+func timeoutMessage(timeoutSeconds int) string {
+	return fmt.Sprintf("Run exceeded its timeout of %d seconds", timeoutSeconds)
+}
+
+// SetScheduleLimits sets a schedule's concurrency limit and timeout.
+func (r *SchedulePostgresRepository) SetScheduleLimits(ctx context.Context, id string, maxConcurrentRuns int, timeoutSeconds *int) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE ingestion_schedules
+		SET max_concurrent_runs = $2, timeout_seconds = $3, updated_at = NOW()
+		WHERE id = $1`, id, maxConcurrentRuns, timeoutSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to set schedule limits: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+func (r *SchedulePostgresRepository) AbortTimedOutJobRuns(ctx context.Context, grace time.Duration) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE ingestion_job_runs jr
+		SET status = $1, finished_at = NOW(), updated_at = NOW(),
+			error_message = 'Run exceeded its timeout of ' || s.timeout_seconds || ' seconds'
+		FROM ingestion_schedules s
+		WHERE jr.schedule_id = s.id
+		  AND s.timeout_seconds IS NOT NULL
+		  AND jr.status = $2
+		  AND jr.started_at < NOW() - s.timeout_seconds * interval '1 second' - $3 * interval '1 second'
+		RETURNING jr.id`,
+		JobStatusFailed, JobStatusRunning, grace.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to abort timed out job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job runs: %w", err)
+	}
+
+	return ids, nil
+}
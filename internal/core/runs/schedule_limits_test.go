@@ -0,0 +1,56 @@
+package runs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type limitsRepo struct {
+	ScheduleRepository
+	limit   int
+	timeout *int
+}
+
+func (r *limitsRepo) SetScheduleLimits(_ context.Context, _ string, maxConcurrentRuns int, timeoutSeconds *int) error {
+	r.limit = maxConcurrentRuns
+	r.timeout = timeoutSeconds
+	return nil
+}
+
+func (r *limitsRepo) GetSchedule(_ context.Context, id string) (*Schedule, error) {
+	return &Schedule{ID: id, ConcurrencyLimit: r.limit, TimeoutSeconds: r.timeout}, nil
+}
+
+func TestSetScheduleLimits(t *testing.T) {
+	repo := &limitsRepo{}
+	svc := NewScheduleService(repo)
+	ctx := context.Background()
+	seconds := func(n int) *int { return &n }
+
+	schedule, err := svc.SetScheduleLimits(ctx, "s1", 2, seconds(1800))
+	require.NoError(t, err)
+	assert.Equal(t, 2, schedule.ConcurrencyLimit)
+	require.NotNil(t, schedule.TimeoutSeconds)
+	assert.Equal(t, 1800, *schedule.TimeoutSeconds)
+
+	schedule, err = svc.SetScheduleLimits(ctx, "s1", 0, seconds(0))
+	require.NoError(t, err)
+	assert.Equal(t, 0, schedule.ConcurrencyLimit)
+	assert.Nil(t, schedule.TimeoutSeconds, "a zero timeout removes it")
+
+	for _, tc := range []struct {
+		limit   int
+		timeout *int
+	}{
+		{limit: -1},
+		{limit: MaxScheduleConcurrency + 1},
+		{limit: 1, timeout: seconds(-5)},
+		{limit: 1, timeout: seconds(8 * 24 * 3600)},
+	} {
+		_, err := svc.SetScheduleLimits(ctx, "s1", tc.limit, tc.timeout)
+		assert.ErrorIs(t, err, ErrInvalidScheduleLimits)
+	}
+}
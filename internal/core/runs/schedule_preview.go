@@ -0,0 +1,214 @@
+package runs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPreviewCount = 10
+	MaxPreviewCount     = 100
+)
+
+// BlackoutWindow is a daily period when runs shouldn't happen, in the
+// preview's timezone. A window whose end is before its start spans
+// midnight and belongs to the day it starts on.
+type BlackoutWindow struct {
+	// Days the window applies to, as mon to sun. Empty means every day.
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start" example:"22:00"`
+	End   string   `json:"end" example:"06:00"`
+} // @name BlackoutWindow
+
+// PreviewInput describes a schedule to preview.
+type PreviewInput struct {
+	CronExpression string
+	// Timezone is an IANA name such as Europe/London. Empty uses the
+	// expression's CRON_TZ prefix, or server time without one.
+	Timezone        string
+	Count           int
+	BlackoutWindows []BlackoutWindow
+}
+
+// PreviewRun is one upcoming run time.
+type PreviewRun struct {
+	// Time is in the schedule's timezone.
+	Time time.Time `json:"time"`
+	// BlackoutWindow is the index of the first blackout window the run
+	// falls in.
+	BlackoutWindow *int `json:"blackout_window,omitempty"`
+} // @name SchedulePreviewRun
+
+// SchedulePreview lists when a schedule would run.
+type SchedulePreview struct {
+	// CronExpression is the expression to save for these run times. It
+	// carries a CRON_TZ prefix when a timezone was given.
+	CronExpression string       `json:"cron_expression"`
+	Timezone       string       `json:"timezone,omitempty"`
+	Runs           []PreviewRun `json:"runs"`
+	// BlackedOut counts the runs that fall in a blackout window.
+	BlackedOut int      `json:"blacked_out"`
+	Warnings   []string `json:"warnings,omitempty"`
+} // @name SchedulePreview
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// blackout is a validated BlackoutWindow, in minutes since midnight.
+type blackout struct {
+	days       map[time.Weekday]bool
+	start, end int
+}
+
+func (b blackout) onDay(day time.Weekday) bool {
+	return len(b.days) == 0 || b.days[day]
+}
+
+func (b blackout) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if b.start < b.end {
+		return b.onDay(t.Weekday()) && minute >= b.start && minute < b.end
+	}
+	if minute >= b.start {
+		return b.onDay(t.Weekday())
+	}
+	if minute < b.end {
+		return b.onDay((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+func parseBlackoutWindow(w BlackoutWindow) (blackout, error) {
+	var b blackout
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return b, fmt.Errorf("start %q is not HH:MM", w.Start)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return b, fmt.Errorf("end %q is not HH:MM", w.End)
+	}
+	b.start = start.Hour()*60 + start.Minute()
+	b.end = end.Hour()*60 + end.Minute()
+	if b.start == b.end {
+		return b, fmt.Errorf("start and end are both %s", w.Start)
+	}
+
+	if len(w.Days) > 0 {
+		b.days = make(map[time.Weekday]bool, len(w.Days))
+		for _, day := range w.Days {
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return b, fmt.Errorf("unknown day %q, use mon to sun", day)
+			}
+			b.days[weekday] = true
+		}
+	}
+	return b, nil
+}
+
+// PreviewSchedule returns the next run times of a cron expression after
+// from, flagging those in a blackout window. Blacked out runs are listed
+// rather than skipped, since the scheduler doesn't enforce blackouts.
+func PreviewSchedule(input PreviewInput, from time.Time) (*SchedulePreview, error) {
+	expr := strings.TrimSpace(input.CronExpression)
+	if expr == "" {
+		return nil, fmt.Errorf("%w: cron expression is required", ErrInvalidInput)
+	}
+
+	timezone := strings.TrimSpace(input.Timezone)
+	if timezone != "" {
+		if prefixed := cronTimezone(expr); prefixed != "" {
+			if prefixed != timezone {
+				return nil, fmt.Errorf("%w: timezone %s conflicts with CRON_TZ=%s in the expression", ErrInvalidInput, timezone, prefixed)
+			}
+		} else {
+			expr = "CRON_TZ=" + timezone + " " + expr
+		}
+	} else {
+		timezone = cronTimezone(expr)
+	}
+
+	// Run times and blackout windows are in the schedule's timezone.
+	loc := from.Location()
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidInput, timezone)
+		}
+	}
+
+	schedule, err := parseCronExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCronExpression, err)
+	}
+
+	count := input.Count
+	if count <= 0 {
+		count = DefaultPreviewCount
+	} else if count > MaxPreviewCount {
+		count = MaxPreviewCount
+	}
+
+	blackouts := make([]blackout, len(input.BlackoutWindows))
+	for i, w := range input.BlackoutWindows {
+		b, err := parseBlackoutWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("%w: blackout window %d: %v", ErrInvalidInput, i, err)
+		}
+		blackouts[i] = b
+	}
+
+	preview := &SchedulePreview{
+		CronExpression: expr,
+		Timezone:       timezone,
+		Runs:           make([]PreviewRun, 0, count),
+	}
+
+	next := from
+	for len(preview.Runs) < count {
+		next = schedule.Next(next)
+		// Next returns the zero time for expressions that never match, like
+		// February 30th.
+		if next.IsZero() {
+			break
+		}
+
+		local := next.In(loc)
+		run := PreviewRun{Time: local}
+		for i, b := range blackouts {
+			if b.contains(local) {
+				run.BlackoutWindow = &i
+				preview.BlackedOut++
+				break
+			}
+		}
+		preview.Runs = append(preview.Runs, run)
+	}
+
+	switch {
+	case len(preview.Runs) == 0:
+		preview.Warnings = append(preview.Warnings, "The expression never matches, so the schedule would never run")
+	case len(preview.Runs) < count:
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The expression only matches %d more times", len(preview.Runs)))
+	}
+	if preview.BlackedOut > 0 && preview.BlackedOut == len(preview.Runs) {
+		preview.Warnings = append(preview.Warnings, "Every previewed run falls in a blackout window")
+	}
+
+	return preview, nil
+}
+
+// cronTimezone returns the timezone of an expression's CRON_TZ or TZ
+// prefix, or "" without one.
+func cronTimezone(expr string) string {
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		return ""
+	}
+	prefix, _, _ := strings.Cut(expr, " ")
+	_, tz, _ := strings.Cut(prefix, "=")
+	return tz
+}
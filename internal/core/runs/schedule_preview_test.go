@@ -0,0 +1,135 @@
+package runs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewSchedule(t *testing.T) {
+	from := time.Date(2026, 3, 27, 23, 30, 0, 0, time.UTC) // Friday
+
+	preview, err := PreviewSchedule(PreviewInput{
+		CronExpression: "0 * * * *",
+		Timezone:       "Europe/London",
+		Count:          3,
+		BlackoutWindows: []BlackoutWindow{
+			{Days: []string{"fri"}, Start: "23:00", End: "01:00"},
+		},
+	}, from)
+	require.NoError(t, err)
+
+	assert.Equal(t, "CRON_TZ=Europe/London 0 * * * *", preview.CronExpression)
+	assert.Equal(t, "Europe/London", preview.Timezone)
+	require.Len(t, preview.Runs, 3)
+
+	// Saturday's midnight run belongs to Friday's overnight window.
+	assert.NotNil(t, preview.Runs[0].BlackoutWindow)
+	assert.Nil(t, preview.Runs[1].BlackoutWindow)
+	assert.Equal(t, 1, preview.BlackedOut)
+	assert.Empty(t, preview.Warnings)
+}
+
+func TestPreviewScheduleBlackoutsUseScheduleTimezone(t *testing.T) {
+	from := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC) // Wednesday
+
+	// 23:00 in New York is 03:00 UTC the next day.
+	tests := []struct {
+		name        string
+		input       PreviewInput
+		window      BlackoutWindow
+		wantBlocked bool
+	}{
+		{
+			name:        "CRON_TZ prefix",
+			input:       PreviewInput{CronExpression: "CRON_TZ=America/New_York 0 23 * * *"},
+			window:      BlackoutWindow{Start: "22:30", End: "23:30"},
+			wantBlocked: true,
+		},
+		{
+			name:        "timezone field",
+			input:       PreviewInput{CronExpression: "0 23 * * *", Timezone: "America/New_York"},
+			window:      BlackoutWindow{Start: "22:30", End: "23:30"},
+			wantBlocked: true,
+		},
+		{
+			name:   "window in UTC hours",
+			input:  PreviewInput{CronExpression: "0 23 * * *", Timezone: "America/New_York"},
+			window: BlackoutWindow{Start: "02:00", End: "04:00"},
+		},
+		{
+			name:   "window on the UTC day",
+			input:  PreviewInput{CronExpression: "0 23 * * *", Timezone: "America/New_York"},
+			window: BlackoutWindow{Days: []string{"thu"}, Start: "22:30", End: "23:30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Count = 1
+			tt.input.BlackoutWindows = []BlackoutWindow{tt.window}
+			preview, err := PreviewSchedule(tt.input, from)
+			require.NoError(t, err)
+			require.Len(t, preview.Runs, 1)
+
+			run := preview.Runs[0]
+			assert.Equal(t, "America/New_York", run.Time.Location().String())
+			assert.True(t, run.Time.Equal(time.Date(2026, 7, 2, 3, 0, 0, 0, time.UTC)))
+			assert.Equal(t, tt.wantBlocked, run.BlackoutWindow != nil)
+		})
+	}
+}
+
+func TestPreviewScheduleNeverMatches(t *testing.T) {
+	preview, err := PreviewSchedule(PreviewInput{CronExpression: "0 0 30 2 *"}, time.Now())
+	require.NoError(t, err)
+
+	assert.Empty(t, preview.Runs)
+	assert.Len(t, preview.Warnings, 1)
+}
+
+func TestPreviewScheduleCount(t *testing.T) {
+	preview, err := PreviewSchedule(PreviewInput{CronExpression: "* * * * *"}, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, preview.Runs, DefaultPreviewCount)
+
+	preview, err = PreviewSchedule(PreviewInput{CronExpression: "* * * * *", Count: 1000}, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, preview.Runs, MaxPreviewCount)
+}
+
+func TestPreviewScheduleInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input PreviewInput
+		want  error
+	}{
+		{"empty expression", PreviewInput{}, ErrInvalidInput},
+		{"bad expression", PreviewInput{CronExpression: "every hour"}, ErrInvalidCronExpression},
+		{"unknown timezone", PreviewInput{CronExpression: "0 * * * *", Timezone: "Mars/Olympus"}, ErrInvalidInput},
+		{
+			"conflicting timezone",
+			PreviewInput{CronExpression: "CRON_TZ=UTC 0 * * * *", Timezone: "Europe/London"},
+			ErrInvalidInput,
+		},
+		{
+			"bad blackout time",
+			PreviewInput{CronExpression: "0 * * * *", BlackoutWindows: []BlackoutWindow{{Start: "25:00", End: "01:00"}}},
+			ErrInvalidInput,
+		},
+		{
+			"unknown blackout day",
+			PreviewInput{CronExpression: "0 * * * *", BlackoutWindows: []BlackoutWindow{{Days: []string{"funday"}, Start: "01:00", End: "02:00"}}},
+			ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PreviewSchedule(tt.input, time.Now())
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marmotdata/marmot/internal/plugin"
 	"github.com/robfig/cron/v3"
 )
 
@@ -15,8 +16,9 @@ func parseCronExpression(cronExpr string) (cron.Schedule, error) {
 }
 
 type ScheduleService struct {
-	repo        ScheduleRepository
-	broadcaster EventBroadcaster
+	repo            ScheduleRepository
+	broadcaster     EventBroadcaster
+	serviceAccounts ServiceAccountResolver
 }
 
 func NewScheduleService(repo ScheduleRepository) *ScheduleService {
@@ -134,6 +136,15 @@ func (s *ScheduleService) SyncSchedule(ctx context.Context, name, pluginID strin
 	return schedule, nil
 }
 
+// SetScheduleConnection points a schedule at a shared connection, or
+// detaches it when connectionID is nil.
+func (s *ScheduleService) SetScheduleConnection(ctx context.Context, id string, connectionID *string) (*Schedule, error) {
+	if err := s.repo.SetScheduleConnection(ctx, id, connectionID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSchedule(ctx, id)
+}
+
 // Job run operations
 
 func (s *ScheduleService) CreateJobRun(ctx context.Context, scheduleID *string, triggeredBy string) (*JobRun, error) {
@@ -207,6 +218,21 @@ func (s *ScheduleService) UpdateJobRunProgress(ctx context.Context, id string, a
 	return nil
 }
 
+// UpdateJobRunEntityCounts records the per-entity-type breakdown of a job
+// run's results.
+func (s *ScheduleService) UpdateJobRunEntityCounts(ctx context.Context, id string, counts plugin.EntitySummary) error {
+	if err := s.repo.UpdateJobRunEntityCounts(ctx, id, counts); err != nil {
+		return err
+	}
+
+	run, err := s.repo.GetJobRun(ctx, id)
+	if err == nil {
+		s.broadcaster.BroadcastJobRunProgress(run)
+	}
+
+	return nil
+}
+
 func (s *ScheduleService) CompleteJobRun(ctx context.Context, id string, success bool, errorMessage *string, assetsCreated, assetsUpdated, assetsDeleted, lineageCreated, documentationAdded int) error {
 	status := JobStatusSucceeded
 	if !success {
@@ -26,53 +26,62 @@ const (
 )
 
 var (
-	ErrScheduleNotFound      = errors.New("schedule not found")
-	ErrScheduleNameExists    = errors.New("schedule name already exists")
-	ErrJobRunNotFound        = errors.New("job run not found")
-	ErrJobRunNotClaimable    = errors.New("job run not claimable")
-	ErrInvalidJobStatus      = errors.New("invalid job status")
-	ErrInvalidCronExpression = errors.New("invalid cron expression")
+	ErrScheduleNotFound          = errors.New("schedule not found")
+	ErrScheduleNameExists        = errors.New("schedule name already exists")
+	ErrJobRunNotFound            = errors.New("job run not found")
+	ErrJobRunNotClaimable        = errors.New("job run not claimable")
+	ErrInvalidJobStatus          = errors.New("invalid job status")
+	ErrInvalidCronExpression     = errors.New("invalid cron expression")
+	ErrConnectionNotFound        = errors.New("connection not found")
+	ErrServiceAccountUnavailable = errors.New("service account not found or inactive")
+	ErrScheduleConcurrencyLimit  = errors.New("schedule has reached its concurrent run limit")
+	ErrInvalidScheduleLimits     = errors.New("invalid schedule limits")
 )
 
 type Schedule struct {
-	ID                 string                 `json:"id"`
-	Name               string                 `json:"name"`
-	PluginID           string                 `json:"plugin_id"`
-	Config             map[string]interface{} `json:"config"`
-	CronExpression     string                 `json:"cron_expression"`
-	Enabled            bool                   `json:"enabled"`
-	LastRunAt          *time.Time             `json:"last_run_at,omitempty"`
-	LastRunStatus      *string                `json:"last_run_status,omitempty"`
-	NextRunAt          *time.Time             `json:"next_run_at,omitempty"`
-	ManagedBy          *string                `json:"managed_by,omitempty"`
-	CreatedBy          *string                `json:"created_by,omitempty"`
-	CreatedAt          time.Time              `json:"created_at"`
-	UpdatedAt          time.Time              `json:"updated_at"`
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	PluginID         string                 `json:"plugin_id"`
+	Config           map[string]interface{} `json:"config"`
+	ConnectionID     *string                `json:"connection_id,omitempty"`
+	ServiceAccountID *string                `json:"service_account_id,omitempty"`
+	CronExpression   string                 `json:"cron_expression"`
+	Enabled          bool                   `json:"enabled"`
+	LastRunAt        *time.Time             `json:"last_run_at,omitempty"`
+	LastRunStatus    *string                `json:"last_run_status,omitempty"`
+	NextRunAt        *time.Time             `json:"next_run_at,omitempty"`
+	ManagedBy        *string                `json:"managed_by,omitempty"`
+	ConcurrencyLimit int                    `json:"max_concurrent_runs"`
+	TimeoutSeconds   *int                   `json:"timeout_seconds,omitempty"`
+	CreatedBy        *string                `json:"created_by,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
 } // @name Schedule
 
 type JobRun struct {
-	ID                 string     `json:"id"`
-	ScheduleID         *string    `json:"schedule_id,omitempty"`
-	PluginRunID        *string    `json:"plugin_run_id,omitempty"`
-	PipelineName       string     `json:"pipeline_name"`
-	SourceName         string     `json:"source_name"`
-	RunID              string     `json:"run_id"`
-	Status             string     `json:"status"`
-	ClaimedBy          *string    `json:"claimed_by,omitempty"`
-	ClaimedAt          *time.Time `json:"claimed_at,omitempty"`
-	StartedAt          *time.Time `json:"started_at,omitempty"`
-	FinishedAt         *time.Time `json:"finished_at,omitempty"`
-	Log                *string    `json:"log,omitempty"`
-	ErrorMessage       *string    `json:"error_message,omitempty"`
-	AssetsCreated      int        `json:"assets_created"`
-	AssetsUpdated      int        `json:"assets_updated"`
-	AssetsDeleted      int        `json:"assets_deleted"`
-	LineageCreated     int        `json:"lineage_created"`
-	DocumentationAdded int        `json:"documentation_added"`
+	ID                 string                 `json:"id"`
+	ScheduleID         *string                `json:"schedule_id,omitempty"`
+	PluginRunID        *string                `json:"plugin_run_id,omitempty"`
+	PipelineName       string                 `json:"pipeline_name"`
+	SourceName         string                 `json:"source_name"`
+	RunID              string                 `json:"run_id"`
+	Status             string                 `json:"status"`
+	ClaimedBy          *string                `json:"claimed_by,omitempty"`
+	ClaimedAt          *time.Time             `json:"claimed_at,omitempty"`
+	StartedAt          *time.Time             `json:"started_at,omitempty"`
+	FinishedAt         *time.Time             `json:"finished_at,omitempty"`
+	Log                *string                `json:"log,omitempty"`
+	ErrorMessage       *string                `json:"error_message,omitempty"`
+	AssetsCreated      int                    `json:"assets_created"`
+	AssetsUpdated      int                    `json:"assets_updated"`
+	AssetsDeleted      int                    `json:"assets_deleted"`
+	LineageCreated     int                    `json:"lineage_created"`
+	DocumentationAdded int                    `json:"documentation_added"`
+	EntityCounts       plugin.EntitySummary   `json:"entity_counts,omitempty"`
 	Config             map[string]interface{} `json:"config,omitempty"`
-	CreatedBy          string     `json:"created_by"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
+	CreatedBy          string                 `json:"created_by"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
 } // @name JobRun
 
 // ValidJobStatus checks if a job status is valid
@@ -98,6 +107,9 @@ type ScheduleRepository interface {
 	UpdateScheduleLastRun(ctx context.Context, id string, lastRunAt time.Time) error
 	GetSchedulesDueForRun(ctx context.Context, limit int) ([]*Schedule, error)
 	UpsertSchedule(ctx context.Context, schedule *Schedule) error
+	SetScheduleConnection(ctx context.Context, id string, connectionID *string) error
+	SetScheduleServiceAccount(ctx context.Context, id string, serviceAccountID *string) error
+	SetScheduleLimits(ctx context.Context, id string, maxConcurrentRuns int, timeoutSeconds *int) error
 
 	// Job run operations
 	CreateJobRun(ctx context.Context, run *JobRun) error
@@ -107,10 +119,14 @@ type ScheduleRepository interface {
 	ClaimJobRun(ctx context.Context, id, workerID string) (*JobRun, error)
 	UpdateJobRunStatus(ctx context.Context, id, status string) error
 	UpdateJobRunProgress(ctx context.Context, id string, assetsCreated, assetsUpdated, assetsDeleted, lineageCreated, documentationAdded int) error
+	UpdateJobRunEntityCounts(ctx context.Context, id string, counts plugin.EntitySummary) error
 	SetJobRunPluginRunID(ctx context.Context, jobRunID, pluginRunID string) error
 	GetJobRunByPluginRunID(ctx context.Context, pluginRunID string) (*JobRun, error)
 	CompleteJobRun(ctx context.Context, id string, status string, errorMessage *string, assetsCreated, assetsUpdated, assetsDeleted, lineageCreated, documentationAdded int) error
 	ReleaseExpiredClaims(ctx context.Context, expiry time.Duration) (int, error)
+	// AbortTimedOutJobRuns fails runs that have been running longer than
+	// their schedule's timeout plus grace, returning their IDs.
+	AbortTimedOutJobRuns(ctx context.Context, grace time.Duration) ([]string, error)
 	CancelJobRun(ctx context.Context, id string) error
 
 	// Asset-schedule associations
@@ -182,7 +198,7 @@ func (r *SchedulePostgresRepository) CreateSchedule(ctx context.Context, schedul
 
 func (r *SchedulePostgresRepository) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
 	query := `
-		SELECT id, name, plugin_id, config, cron_expression, enabled, last_run_at, next_run_at, managed_by, created_by, created_at, updated_at
+		SELECT id, name, plugin_id, config, connection_id, service_account_id, cron_expression, enabled, last_run_at, next_run_at, managed_by, max_concurrent_runs, timeout_seconds, created_by, created_at, updated_at
 		FROM ingestion_schedules
 		WHERE id = $1`
 
@@ -193,11 +209,15 @@ func (r *SchedulePostgresRepository) GetSchedule(ctx context.Context, id string)
 		&schedule.Name,
 		&schedule.PluginID,
 		&configJSON,
+		&schedule.ConnectionID,
+		&schedule.ServiceAccountID,
 		&schedule.CronExpression,
 		&schedule.Enabled,
 		&schedule.LastRunAt,
 		&schedule.NextRunAt,
 		&schedule.ManagedBy,
+		&schedule.ConcurrencyLimit,
+		&schedule.TimeoutSeconds,
 
 		&schedule.CreatedBy,
 		&schedule.CreatedAt,
@@ -220,7 +240,7 @@ func (r *SchedulePostgresRepository) GetSchedule(ctx context.Context, id string)
 
 func (r *SchedulePostgresRepository) GetScheduleByName(ctx context.Context, name string) (*Schedule, error) {
 	query := `
-		SELECT id, name, plugin_id, config, cron_expression, enabled, last_run_at, next_run_at, managed_by, created_by, created_at, updated_at
+		SELECT id, name, plugin_id, config, connection_id, service_account_id, cron_expression, enabled, last_run_at, next_run_at, managed_by, max_concurrent_runs, timeout_seconds, created_by, created_at, updated_at
 		FROM ingestion_schedules
 		WHERE name = $1`
 
@@ -231,11 +251,15 @@ func (r *SchedulePostgresRepository) GetScheduleByName(ctx context.Context, name
 		&schedule.Name,
 		&schedule.PluginID,
 		&configJSON,
+		&schedule.ConnectionID,
+		&schedule.ServiceAccountID,
 		&schedule.CronExpression,
 		&schedule.Enabled,
 		&schedule.LastRunAt,
 		&schedule.NextRunAt,
 		&schedule.ManagedBy,
+		&schedule.ConcurrencyLimit,
+		&schedule.TimeoutSeconds,
 
 		&schedule.CreatedBy,
 		&schedule.CreatedAt,
@@ -323,8 +347,8 @@ func (r *SchedulePostgresRepository) ListSchedules(ctx context.Context, enabled
 		countQuery = `SELECT COUNT(*) FROM ingestion_schedules WHERE enabled = $1`
 		listQuery = `
 			SELECT
-				s.id, s.name, s.plugin_id, s.config, s.cron_expression, s.enabled,
-				s.last_run_at, s.next_run_at, s.managed_by, s.created_by, s.created_at, s.updated_at,
+				s.id, s.name, s.plugin_id, s.config, s.connection_id, s.service_account_id, s.cron_expression, s.enabled,
+				s.last_run_at, s.next_run_at, s.managed_by, s.max_concurrent_runs, s.timeout_seconds, s.created_by, s.created_at, s.updated_at,
 				(
 					SELECT status
 					FROM ingestion_job_runs jr
@@ -341,8 +365,8 @@ func (r *SchedulePostgresRepository) ListSchedules(ctx context.Context, enabled
 		countQuery = `SELECT COUNT(*) FROM ingestion_schedules`
 		listQuery = `
 			SELECT
-				s.id, s.name, s.plugin_id, s.config, s.cron_expression, s.enabled,
-				s.last_run_at, s.next_run_at, s.managed_by, s.created_by, s.created_at, s.updated_at,
+				s.id, s.name, s.plugin_id, s.config, s.connection_id, s.service_account_id, s.cron_expression, s.enabled,
+				s.last_run_at, s.next_run_at, s.managed_by, s.max_concurrent_runs, s.timeout_seconds, s.created_by, s.created_at, s.updated_at,
 				(
 					SELECT status
 					FROM ingestion_job_runs jr
@@ -383,12 +407,16 @@ func (r *SchedulePostgresRepository) ListSchedules(ctx context.Context, enabled
 			&schedule.Name,
 			&schedule.PluginID,
 			&configJSON,
+			&schedule.ConnectionID,
+			&schedule.ServiceAccountID,
 			&schedule.CronExpression,
 			&schedule.Enabled,
 			&schedule.LastRunAt,
 			&schedule.NextRunAt,
 			&schedule.ManagedBy,
-	
+			&schedule.ConcurrencyLimit,
+			&schedule.TimeoutSeconds,
+
 			&schedule.CreatedBy,
 			&schedule.CreatedAt,
 			&schedule.UpdatedAt,
@@ -448,7 +476,7 @@ func (r *SchedulePostgresRepository) UpdateScheduleLastRun(ctx context.Context,
 
 func (r *SchedulePostgresRepository) GetSchedulesDueForRun(ctx context.Context, limit int) ([]*Schedule, error) {
 	query := `
-		SELECT id, name, plugin_id, config, cron_expression, enabled, last_run_at, next_run_at, managed_by, created_by, created_at, updated_at
+		SELECT id, name, plugin_id, config, service_account_id, cron_expression, enabled, last_run_at, next_run_at, managed_by, max_concurrent_runs, timeout_seconds, created_by, created_at, updated_at
 		FROM ingestion_schedules
 		WHERE enabled = true AND managed_by IS NULL AND next_run_at IS NOT NULL AND next_run_at <= NOW()
 		ORDER BY next_run_at
@@ -469,12 +497,15 @@ func (r *SchedulePostgresRepository) GetSchedulesDueForRun(ctx context.Context,
 			&schedule.Name,
 			&schedule.PluginID,
 			&configJSON,
+			&schedule.ServiceAccountID,
 			&schedule.CronExpression,
 			&schedule.Enabled,
 			&schedule.LastRunAt,
 			&schedule.NextRunAt,
 			&schedule.ManagedBy,
-	
+			&schedule.ConcurrencyLimit,
+			&schedule.TimeoutSeconds,
+
 			&schedule.CreatedBy,
 			&schedule.CreatedAt,
 			&schedule.UpdatedAt,
@@ -521,7 +552,7 @@ func (r *SchedulePostgresRepository) GetJobRun(ctx context.Context, id string) (
 		SELECT
 			jr.id, jr.schedule_id, jr.plugin_run_id, jr.status, jr.claimed_by, jr.claimed_at, jr.started_at, jr.finished_at,
 			jr.log, jr.error_message, jr.assets_created, jr.assets_updated, jr.assets_deleted,
-			jr.lineage_created, jr.documentation_added, jr.created_at, jr.updated_at,
+			jr.lineage_created, jr.documentation_added, COALESCE(jr.entity_counts, '{}'::jsonb), jr.created_at, jr.updated_at,
 			COALESCE(jr.pipeline_name, s.name, 'Manual Run') as pipeline_name,
 			COALESCE(jr.source_name, s.plugin_id, '') as source_name,
 			COALESCE(s.config, '{}'::jsonb) as config,
@@ -549,6 +580,7 @@ func (r *SchedulePostgresRepository) GetJobRun(ctx context.Context, id string) (
 		&run.AssetsDeleted,
 		&run.LineageCreated,
 		&run.DocumentationAdded,
+		&run.EntityCounts,
 		&run.CreatedAt,
 		&run.UpdatedAt,
 		&run.PipelineName,
@@ -658,7 +690,7 @@ func (r *SchedulePostgresRepository) ListJobRuns(ctx context.Context, scheduleID
 		SELECT
 			jr.id, jr.schedule_id, jr.plugin_run_id, jr.status, jr.claimed_by, jr.claimed_at, jr.started_at, jr.finished_at,
 			jr.log, jr.error_message, jr.assets_created, jr.assets_updated, jr.assets_deleted,
-			jr.lineage_created, jr.documentation_added, jr.created_at, jr.updated_at,
+			jr.lineage_created, jr.documentation_added, COALESCE(jr.entity_counts, '{}'::jsonb), jr.created_at, jr.updated_at,
 			COALESCE(jr.pipeline_name, s.name, 'Manual Run') as pipeline_name,
 			COALESCE(jr.source_name, s.plugin_id, '') as source_name,
 			COALESCE(s.config, '{}'::jsonb) as config,
@@ -705,6 +737,7 @@ func (r *SchedulePostgresRepository) ListJobRuns(ctx context.Context, scheduleID
 			&run.AssetsDeleted,
 			&run.LineageCreated,
 			&run.DocumentationAdded,
+			&run.EntityCounts,
 			&run.CreatedAt,
 			&run.UpdatedAt,
 			&run.PipelineName,
@@ -733,17 +766,26 @@ func (r *SchedulePostgresRepository) ListJobRuns(ctx context.Context, scheduleID
 	return runs, total, nil
 }
 
+// ClaimJobRun claims a pending run for workerID. Runs of a schedule that
+// already has MaxConcurrentRuns runs in progress are refused with
+// ErrScheduleConcurrencyLimit and stay pending.
 func (r *SchedulePostgresRepository) ClaimJobRun(ctx context.Context, id, workerID string) (*JobRun, error) {
-	// Attempt to claim the job using UPDATE...RETURNING, then fetch full details
-	updateQuery := `
-		UPDATE ingestion_job_runs
-		SET status = $1, claimed_by = $2, claimed_at = NOW(), updated_at = NOW()
-		WHERE id = $3 AND status = $4
-		RETURNING id`
-
-	var runID string
-	err := r.db.QueryRow(ctx, updateQuery, JobStatusClaimed, workerID, id, JobStatusPending).Scan(&runID)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
+	// Lock the schedule so instances claiming its runs at the same time see
+	// each other's claims.
+	var scheduleID *string
+	var maxConcurrent *int
+	err = tx.QueryRow(ctx, `
+		SELECT jr.schedule_id, s.max_concurrent_runs
+		FROM ingestion_job_runs jr
+		LEFT JOIN ingestion_schedules s ON s.id = jr.schedule_id
+		WHERE jr.id = $1 AND jr.status = $2
+		FOR UPDATE OF jr`, id, JobStatusPending).Scan(&scheduleID, &maxConcurrent)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrJobRunNotClaimable
@@ -751,8 +793,38 @@ func (r *SchedulePostgresRepository) ClaimJobRun(ctx context.Context, id, worker
 		return nil, fmt.Errorf("failed to claim job run: %w", err)
 	}
 
+	if scheduleID != nil && maxConcurrent != nil && *maxConcurrent > 0 {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM ingestion_schedules WHERE id = $1 FOR UPDATE`, *scheduleID); err != nil {
+			return nil, fmt.Errorf("failed to lock schedule: %w", err)
+		}
+
+		var active int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM ingestion_job_runs
+			WHERE schedule_id = $1 AND status IN ($2, $3)`,
+			*scheduleID, JobStatusClaimed, JobStatusRunning).Scan(&active)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count active job runs: %w", err)
+		}
+		if active >= *maxConcurrent {
+			return nil, ErrScheduleConcurrencyLimit
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE ingestion_job_runs
+		SET status = $1, claimed_by = $2, claimed_at = NOW(), updated_at = NOW()
+		WHERE id = $3`, JobStatusClaimed, workerID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job run: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit job run claim: %w", err)
+	}
+
 	// Now fetch the full run with joined data
-	return r.GetJobRun(ctx, runID)
+	return r.GetJobRun(ctx, id)
 }
 
 func (r *SchedulePostgresRepository) UpdateJobRunStatus(ctx context.Context, id, status string) error {
@@ -800,7 +872,7 @@ func (r *SchedulePostgresRepository) GetJobRunByPluginRunID(ctx context.Context,
 		SELECT
 			jr.id, jr.schedule_id, jr.plugin_run_id, jr.status, jr.claimed_by, jr.claimed_at, jr.started_at, jr.finished_at,
 			jr.log, jr.error_message, jr.assets_created, jr.assets_updated, jr.assets_deleted,
-			jr.lineage_created, jr.documentation_added, jr.created_at, jr.updated_at,
+			jr.lineage_created, jr.documentation_added, COALESCE(jr.entity_counts, '{}'::jsonb), jr.created_at, jr.updated_at,
 			COALESCE(jr.pipeline_name, s.name, 'Manual Run') as pipeline_name,
 			COALESCE(jr.source_name, s.plugin_id, '') as source_name,
 			COALESCE(s.config, '{}'::jsonb) as config,
@@ -828,6 +900,7 @@ func (r *SchedulePostgresRepository) GetJobRunByPluginRunID(ctx context.Context,
 		&run.AssetsDeleted,
 		&run.LineageCreated,
 		&run.DocumentationAdded,
+		&run.EntityCounts,
 		&run.CreatedAt,
 		&run.UpdatedAt,
 		&run.PipelineName,
@@ -882,6 +955,28 @@ func (r *SchedulePostgresRepository) UpdateJobRunProgress(ctx context.Context, i
 	return nil
 }
 
+func (r *SchedulePostgresRepository) UpdateJobRunEntityCounts(ctx context.Context, id string, counts plugin.EntitySummary) error {
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		return fmt.Errorf("marshaling entity counts: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE ingestion_job_runs
+		SET entity_counts = $1, updated_at = NOW()
+		WHERE id = $2`,
+		countsJSON, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update job run entity counts: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrJobRunNotFound
+	}
+
+	return nil
+}
+
 func (r *SchedulePostgresRepository) CompleteJobRun(ctx context.Context, id string, status string, errorMessage *string, assetsCreated, assetsUpdated, assetsDeleted, lineageCreated, documentationAdded int) error {
 	if status != JobStatusSucceeded && status != JobStatusFailed {
 		return ErrInvalidJobStatus
@@ -892,7 +987,7 @@ func (r *SchedulePostgresRepository) CompleteJobRun(ctx context.Context, id stri
 		SET status = $1, finished_at = NOW(), error_message = $2,
 			assets_created = $3, assets_updated = $4, assets_deleted = $5,
 			lineage_created = $6, documentation_added = $7, updated_at = NOW()
-		WHERE id = $8
+		WHERE id = $8 AND status NOT IN ($9, $10, $11)
 		RETURNING id, schedule_id`
 
 	var returnedID string
@@ -906,6 +1001,9 @@ func (r *SchedulePostgresRepository) CompleteJobRun(ctx context.Context, id stri
 		lineageCreated,
 		documentationAdded,
 		id,
+		JobStatusSucceeded,
+		JobStatusFailed,
+		JobStatusCancelled,
 	).Scan(&returnedID, &scheduleID)
 
 	if err != nil {
@@ -1010,6 +1108,48 @@ func (r *SchedulePostgresRepository) UpsertSchedule(ctx context.Context, schedul
 	return nil
 }
 
+// SetScheduleConnection sets or, with a nil connectionID, clears the shared
+// connection a schedule uses.
+func (r *SchedulePostgresRepository) SetScheduleConnection(ctx context.Context, id string, connectionID *string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE ingestion_schedules
+		SET connection_id = $2, updated_at = NOW()
+		WHERE id = $1`, id, connectionID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+			return ErrConnectionNotFound
+		}
+		return fmt.Errorf("failed to set schedule connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+// SetScheduleServiceAccount sets or, with a nil serviceAccountID, clears the
+// service account a schedule runs as.
+func (r *SchedulePostgresRepository) SetScheduleServiceAccount(ctx context.Context, id string, serviceAccountID *string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE ingestion_schedules
+		SET service_account_id = $2, updated_at = NOW()
+		WHERE id = $1`, id, serviceAccountID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+			return ErrServiceAccountUnavailable
+		}
+		return fmt.Errorf("failed to set schedule service account: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
+
 // maskJobRunConfig masks sensitive fields in a job run's config
 func (r *SchedulePostgresRepository) maskJobRunConfig(run *JobRun) {
 	if run.Config == nil || len(run.Config) == 0 {
@@ -2,7 +2,9 @@ package runs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +23,9 @@ const (
 	DefaultMaxWorkers        = 10
 	DefaultLeaseExpiry       = 5 * time.Minute
 	DefaultClaimExpiry       = 30 * time.Second
+	// DefaultTimeoutGrace is how long past its timeout a run may still be
+	// marked running before the cleanup loop aborts it.
+	DefaultTimeoutGrace = 1 * time.Minute
 )
 
 type Scheduler struct {
@@ -32,6 +37,8 @@ type Scheduler struct {
 	db            *pgxpool.Pool
 	linkAssets    bool
 	pluginInstall *install.Options
+	connections   ConnectionResolver
+	workerID      string
 
 	maxWorkers        int
 	schedulerInterval time.Duration
@@ -59,6 +66,8 @@ type SchedulerConfig struct {
 	// PluginInstall configures installing a core plugin on demand when
 	// a job needs one that is not loaded. Nil disables it.
 	PluginInstall *install.Options
+	// Connections resolves the shared connections schedules reference.
+	Connections ConnectionResolver
 }
 
 func NewScheduler(service *ScheduleService, runsService Service, encryptor *crypto.Encryptor, registry *plugin.Registry, loadState *plugin.LoadState, config *SchedulerConfig) *Scheduler {
@@ -98,6 +107,8 @@ func NewScheduler(service *ScheduleService, runsService Service, encryptor *cryp
 		db:                config.DB,
 		linkAssets:        config.LinkAssets,
 		pluginInstall:     config.PluginInstall,
+		connections:       config.Connections,
+		workerID:          schedulerWorkerID(),
 		maxWorkers:        maxWorkers,
 		schedulerInterval: schedulerInterval,
 		leaseExpiry:       leaseExpiry,
@@ -200,8 +211,19 @@ func (s *Scheduler) jobDispatcher() {
 					s.activeWorkers.Add(-1)
 				}()
 
-				worker := newWorker(s.service, s.runsService, s.encryptor, s.registry, s.linkAssets, s.pluginInstall)
-				if err := worker.executeJob(s.ctx, j); err != nil {
+				claimed, err := s.service.ClaimJobRun(s.ctx, j.ID, s.workerID)
+				if err != nil {
+					switch {
+					case errors.Is(err, ErrScheduleConcurrencyLimit):
+						log.Debug().Str("run_id", j.ID).Msg("Schedule at its concurrent run limit, leaving run pending")
+					case !errors.Is(err, ErrJobRunNotClaimable):
+						log.Error().Err(err).Str("run_id", j.ID).Msg("Failed to claim job run")
+					}
+					return
+				}
+
+				worker := newWorker(s.service, s.runsService, s.encryptor, s.registry, s.linkAssets, s.pluginInstall, s.connections)
+				if err := worker.executeJob(s.ctx, claimed); err != nil {
 					log.Error().
 						Err(err).
 						Str("run_id", j.ID).
@@ -223,22 +245,10 @@ func (s *Scheduler) processSchedules(ctx context.Context) error {
 	}
 
 	for _, schedule := range schedules {
-		run, err := s.service.CreateJobRun(ctx, &schedule.ID, "scheduler")
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("schedule_id", schedule.ID).
-				Str("schedule_name", schedule.Name).
-				Msg("Failed to create job run")
+		if !s.createScheduledRun(ctx, schedule) {
 			continue
 		}
 
-		log.Info().
-			Str("schedule_id", schedule.ID).
-			Str("schedule_name", schedule.Name).
-			Str("run_id", run.ID).
-			Msg("Created job run for schedule")
-
 		nextRun, err := s.service.CalculateNextRun(schedule.CronExpression, time.Now())
 		if err != nil {
 			log.Error().
@@ -259,6 +269,51 @@ func (s *Scheduler) processSchedules(ctx context.Context) error {
 	return nil
 }
 
+// createScheduledRun queues a run of a due schedule, reporting whether the
+// schedule should move on to its next run time. A schedule whose service
+// account is unavailable is skipped but still moves on, so it doesn't retry
+// every tick.
+func (s *Scheduler) createScheduledRun(ctx context.Context, schedule *Schedule) bool {
+	createdBy, err := s.service.RunAs(ctx, schedule, "scheduler")
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("schedule_id", schedule.ID).
+			Str("schedule_name", schedule.Name).
+			Msg("Skipping scheduled run")
+		return true
+	}
+
+	// A run still waiting for the previous one to finish covers this one too,
+	// so a long run doesn't leave a backlog behind it.
+	pending := JobStatusPending
+	if _, waiting, err := s.service.ListJobRuns(ctx, &schedule.ID, &pending, 1, 0); err == nil && waiting > 0 {
+		log.Info().
+			Str("schedule_id", schedule.ID).
+			Str("schedule_name", schedule.Name).
+			Msg("Skipping scheduled run, previous run still pending")
+		return true
+	}
+
+	run, err := s.service.CreateJobRun(ctx, &schedule.ID, createdBy)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("schedule_id", schedule.ID).
+			Str("schedule_name", schedule.Name).
+			Msg("Failed to create job run")
+		return false
+	}
+
+	log.Info().
+		Str("schedule_id", schedule.ID).
+		Str("schedule_name", schedule.Name).
+		Str("run_id", run.ID).
+		Str("created_by", createdBy).
+		Msg("Created job run for schedule")
+	return true
+}
+
 func (s *Scheduler) pendingJobsPoller() {
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
@@ -312,6 +367,13 @@ func (s *Scheduler) leaseCleanupLoop() {
 			} else if released > 0 {
 				log.Warn().Int("count", released).Msg("Released expired job claims")
 			}
+
+			aborted, err := s.service.AbortTimedOutJobRuns(ctx, DefaultTimeoutGrace)
+			if err != nil {
+				log.Error().Err(err).Msg("Error aborting timed out job runs")
+			} else if aborted > 0 {
+				log.Warn().Int("count", aborted).Msg("Aborted job runs that exceeded their timeout")
+			}
 		}
 	}
 }
@@ -323,9 +385,10 @@ type worker struct {
 	registry      *plugin.Registry
 	linkAssets    bool
 	pluginInstall *install.Options
+	connections   ConnectionResolver
 }
 
-func newWorker(service *ScheduleService, runsService Service, encryptor *crypto.Encryptor, registry *plugin.Registry, linkAssets bool, pluginInstall *install.Options) *worker {
+func newWorker(service *ScheduleService, runsService Service, encryptor *crypto.Encryptor, registry *plugin.Registry, linkAssets bool, pluginInstall *install.Options, connections ConnectionResolver) *worker {
 	return &worker{
 		service:       service,
 		runsService:   runsService,
@@ -333,6 +396,7 @@ func newWorker(service *ScheduleService, runsService Service, encryptor *crypto.
 		registry:      registry,
 		linkAssets:    linkAssets,
 		pluginInstall: pluginInstall,
+		connections:   connections,
 	}
 }
 
@@ -378,6 +442,27 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 		return fmt.Errorf("decrypting config: %w", err)
 	}
 
+	if err := ApplyConnection(ctx, schedule, w.connections); err != nil {
+		errorMsg := fmt.Sprintf("Failed to apply connection: %v", err)
+		_ = w.service.CompleteJobRun(ctx, run.ID, false, &errorMsg, 0, 0, 0, 0, 0)
+		return fmt.Errorf("applying connection: %w", err)
+	}
+
+	// runCtx bounds the plugin's work by the schedule's timeout; ctx stays
+	// usable for recording the outcome.
+	runCtx := ctx
+	if schedule.TimeoutSeconds != nil {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, time.Duration(*schedule.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	failureReason := func(err error) string {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return timeoutMessage(*schedule.TimeoutSeconds)
+		}
+		return err.Error()
+	}
+
 	source, err := w.registry.GetSource(schedule.PluginID)
 	if err != nil && w.pluginInstall != nil {
 		source, err = w.installMissingPlugin(ctx, schedule.PluginID)
@@ -407,15 +492,16 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 		log.Warn().Err(err).Msg("Failed to set plugin run ID on job run")
 	}
 
-	result, err := source.Discover(ctx, validatedConfig)
+	result, err := source.Discover(runCtx, validatedConfig)
 	if err != nil {
-		errorMsg := fmt.Sprintf("Plugin discovery failed: %v", err)
+		errorMsg := fmt.Sprintf("Plugin discovery failed: %s", failureReason(err))
 		_ = w.service.CompleteJobRun(ctx, run.ID, false, &errorMsg, 0, 0, 0, 0, 0)
-		_ = w.runsService.CompleteRun(ctx, pluginRun.RunID, plugin.StatusFailed, nil, err.Error())
+		_ = w.runsService.CompleteRun(ctx, pluginRun.RunID, plugin.StatusFailed, nil, failureReason(err))
 		return fmt.Errorf("executing plugin: %w", err)
 	}
 
 	plugin.FilterDiscoveryResult(result, validatedConfig)
+	plugin.ApplyTTLRules(result, validatedConfig)
 
 	assetsInput := make([]CreateAssetInput, 0, len(result.Assets))
 	for _, a := range result.Assets {
@@ -453,9 +539,10 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 	lineageInput := make([]LineageInput, 0, len(result.Lineage))
 	for _, l := range result.Lineage {
 		lineageInput = append(lineageInput, LineageInput{
-			Source: l.Source,
-			Target: l.Target,
-			Type:   l.Type,
+			Source:  l.Source,
+			Target:  l.Target,
+			Type:    l.Type,
+			Columns: l.Columns,
 		})
 	}
 
@@ -478,7 +565,7 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 	}
 
 	response, err := w.runsService.ProcessEntities(
-		ctx,
+		runCtx,
 		pluginRun.RunID,
 		assetsInput,
 		lineageInput,
@@ -488,12 +575,16 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 		schedule.PluginID,
 	)
 	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to process entities: %v", err)
+		errorMsg := fmt.Sprintf("Failed to process entities: %s", failureReason(err))
 		_ = w.service.CompleteJobRun(ctx, run.ID, false, &errorMsg, 0, 0, 0, 0, 0)
-		_ = w.runsService.CompleteRun(ctx, pluginRun.RunID, plugin.StatusFailed, nil, err.Error())
+		_ = w.runsService.CompleteRun(ctx, pluginRun.RunID, plugin.StatusFailed, nil, failureReason(err))
 		return fmt.Errorf("processing entities: %w", err)
 	}
 
+	if err := w.service.UpdateJobRunEntityCounts(ctx, run.ID, response.Summary); err != nil {
+		log.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to record job run entity counts")
+	}
+
 	assetsCreated := 0
 	assetsUpdated := 0
 	assetMRNs := make([]string, 0, len(response.Assets))
@@ -555,6 +646,7 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 		LineageCreated:     lineageCreated,
 		DocumentationAdded: docsAdded,
 		TotalEntities:      len(result.Assets) + len(result.Lineage) + len(result.Documentation),
+		Entities:           response.Summary,
 	}
 	_ = w.runsService.CompleteRun(ctx, pluginRun.RunID, plugin.StatusCompleted, summary, "")
 
@@ -594,6 +686,15 @@ func (w *worker) executeJob(ctx context.Context, run *JobRun) error {
 	return nil
 }
 
+// schedulerWorkerID identifies this instance on the job runs it claims.
+func schedulerWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
 func convertAssetExternalLinks(links []asset.ExternalLink) []map[string]string {
 	result := make([]map[string]string, 0, len(links))
 	for _, link := range links {
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/marmotdata/marmot/internal/core/asset"
 	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/core/quota"
 	"github.com/marmotdata/marmot/internal/metrics"
 	"github.com/marmotdata/marmot/internal/mrn"
 	"github.com/marmotdata/marmot/internal/plugin"
@@ -45,6 +46,9 @@ type CreateAssetInput struct {
 	ExternalLinks []map[string]string    `json:"external_links"`
 	Query         *string                `json:"query,omitempty"`
 	QueryLanguage *string                `json:"query_language,omitempty"`
+	// Environments are where the asset is deployed, typically reported
+	// for services.
+	Environments map[string]asset.Environment `json:"environments,omitempty"`
 }
 
 type ProcessAssetsResponse struct {
@@ -52,6 +56,7 @@ type ProcessAssetsResponse struct {
 	Lineage              []LineageResult       `json:"lineage"`
 	Documentation        []DocumentationResult `json:"documentation"`
 	StaleEntitiesRemoved []string              `json:"stale_entities_removed,omitempty"`
+	Summary              plugin.EntitySummary  `json:"summary"`
 }
 
 type AssetResult struct {
@@ -62,6 +67,9 @@ type AssetResult struct {
 	Asset    interface{} `json:"asset"`
 	Status   string      `json:"status"`
 	Error    string      `json:"error,omitempty"`
+	// PossibleDuplicates are existing assets a newly created asset was
+	// flagged as a possible duplicate of.
+	PossibleDuplicates []string `json:"possible_duplicates,omitempty"`
 }
 
 type LineageResult struct {
@@ -80,9 +88,10 @@ type DocumentationResult struct {
 }
 
 type LineageInput struct {
-	Source string `json:"source"`
-	Target string `json:"target"`
-	Type   string `json:"type"`
+	Source  string                  `json:"source"`
+	Target  string                  `json:"target"`
+	Type    string                  `json:"type"`
+	Columns []lineage.ColumnMapping `json:"columns,omitempty"`
 }
 
 type DocumentationInput struct {
@@ -120,18 +129,38 @@ type Service interface {
 	CompleteRun(ctx context.Context, runID string, status plugin.RunStatus, summary *plugin.RunSummary, errorMessage string) error
 	ProcessAssets(ctx context.Context, runID string, assets []CreateAssetInput, pipelineName, sourceName string) (*ProcessAssetsResponse, error)
 	ProcessEntities(ctx context.Context, runID string, assets []CreateAssetInput, lineage []LineageInput, docs []DocumentationInput, stats []StatisticInput, pipelineName, sourceName string) (*ProcessAssetsResponse, error)
+	ProcessEntityBatch(ctx context.Context, runID string, assets []CreateAssetInput, lineage []LineageInput, docs []DocumentationInput, stats []StatisticInput, pipelineName, sourceName string) (*ProcessAssetsResponse, error)
+	RemoveStaleAssets(ctx context.Context, runID string) (*ProcessAssetsResponse, error)
 	ProcessRunHistory(ctx context.Context, runHistory []RunHistoryInput) (int, error)
 	AddCheckpoint(ctx context.Context, runID, entityType, entityMRN, operation string, sourceFields []string) error
 	GetLastRunCheckpoints(ctx context.Context, pipelineName, sourceName string) (map[string]*plugin.RunCheckpoint, error)
 	GetStaleEntities(ctx context.Context, lastCheckpoints map[string]*plugin.RunCheckpoint, currentEntityMRNs []string) []string
 	DestroyPipeline(ctx context.Context, pipelineName string) (*DestroyRunResponse, error)
 	CleanupStaleRuns(ctx context.Context, timeout time.Duration) (int, error)
+	CompactCheckpoints(ctx context.Context, history int) (*CompactionResult, error)
 	ListRuns(ctx context.Context, pipelineName string, limit, offset int) ([]*plugin.Run, int, error)
 	ListRunsWithFilters(ctx context.Context, pipelines, statuses []string, limit, offset int) ([]*plugin.Run, int, []string, error)
 	GetRun(ctx context.Context, id string) (*plugin.Run, error)
 	GetByRunID(ctx context.Context, runID string) (*plugin.Run, error)
 	ListRunEntities(ctx context.Context, runID, entityType, status string, limit, offset int) ([]*RunEntity, int, error)
 	SetCompletionObserver(observer RunCompletionObserver)
+	AddCompletionObserver(observer RunCompletionObserver)
+	SetDeltaPublisher(publisher DeltaPublisher)
+	SetMergePolicy(policy *asset.MergePolicy)
+	SetQuotas(quotas *quota.Service)
+	SetDuplicateDetector(detector DuplicateDetector)
+	SetArtifactCapture(config *ArtifactConfig)
+	SetBatchSize(size int)
+	ListArtifacts(ctx context.Context, runDBID string) ([]*RunArtifact, error)
+	GetArtifact(ctx context.Context, runDBID string, sequence int) (*RunArtifact, []byte, error)
+	PruneArtifacts(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// DuplicateDetector is shown each asset a run creates. It records the
+// existing assets the new one looks like a duplicate of, and returns their
+// MRNs.
+type DuplicateDetector interface {
+	FlagDuplicates(ctx context.Context, a *asset.Asset) ([]string, error)
 }
 
 // RunCompletionObserver is notified when runs complete.
@@ -146,6 +175,12 @@ type service struct {
 	metricsRecorder    metrics.Recorder
 	validator          *validator.Validate
 	completionObserver RunCompletionObserver
+	deltaPublisher     DeltaPublisher
+	mergePolicy        *asset.MergePolicy
+	artifactConfig     *ArtifactConfig
+	quotas             *quota.Service
+	duplicateDetector  DuplicateDetector
+	batchSize          int
 }
 
 func NewService(repo Repository, assetService asset.Service, lineageService lineage.Service, metricsRecorder metrics.Recorder) Service {
@@ -162,6 +197,50 @@ func (s *service) SetCompletionObserver(observer RunCompletionObserver) {
 	s.completionObserver = observer
 }
 
+// AddCompletionObserver registers an observer alongside those already
+// registered.
+func (s *service) AddCompletionObserver(observer RunCompletionObserver) {
+	if s.completionObserver == nil {
+		s.completionObserver = observer
+		return
+	}
+	observers, ok := s.completionObserver.(completionObservers)
+	if !ok {
+		observers = completionObservers{s.completionObserver}
+	}
+	s.completionObserver = append(observers, observer)
+}
+
+// completionObservers notifies several observers of each completed run.
+type completionObservers []RunCompletionObserver
+
+func (o completionObservers) OnRunCompleted(ctx context.Context, run *plugin.Run) {
+	for _, observer := range o {
+		observer.OnRunCompleted(ctx, run)
+	}
+}
+
+// SetMergePolicy makes runs merge fields reported by several sources for the
+// same asset instead of overwriting them. A nil policy restores last write
+// wins.
+func (s *service) SetMergePolicy(policy *asset.MergePolicy) {
+	s.mergePolicy = policy
+}
+
+// SetQuotas makes runs respect the run and asset quotas. Runs started past
+// the daily limit fail with quota.ErrExceeded, and new assets past their
+// provider's limit are recorded as failed.
+func (s *service) SetQuotas(quotas *quota.Service) {
+	s.quotas = quotas
+}
+
+// SetDuplicateDetector makes runs check each asset they create for
+// duplicates under other MRNs. The asset is still created; possible
+// duplicates are recorded for review.
+func (s *service) SetDuplicateDetector(detector DuplicateDetector) {
+	s.duplicateDetector = detector
+}
+
 func (s *service) ListRunsWithFilters(ctx context.Context, pipelines, statuses []string, limit, offset int) ([]*plugin.Run, int, []string, error) {
 	if limit <= 0 {
 		limit = 50
@@ -181,6 +260,12 @@ func (s *service) StartRun(ctx context.Context, pipelineName, sourceName, create
 		return nil, fmt.Errorf("%w: pipeline_name, source_name, and created_by are required", ErrInvalidInput)
 	}
 
+	if s.quotas != nil {
+		if err := s.quotas.CheckRun(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	runID := uuid.New().String()
 	now := time.Now()
 
@@ -233,63 +318,106 @@ func (s *service) CompleteRun(ctx context.Context, runID string, status plugin.R
 	if s.completionObserver != nil {
 		s.completionObserver.OnRunCompleted(ctx, run)
 	}
+	s.publishDelta(ctx, run)
 
 	return nil
 }
 
 func (s *service) ProcessEntities(ctx context.Context, runID string, assets []CreateAssetInput, lineage []LineageInput, docs []DocumentationInput, stats []StatisticInput, pipelineName, sourceName string) (*ProcessAssetsResponse, error) {
+	return s.processEntities(ctx, runID, assets, lineage, docs, stats, pipelineName, sourceName, true)
+}
+
+// ProcessEntityBatch processes one of several batches sent for a run. Unlike
+// ProcessEntities it doesn't remove stale assets, since the batch isn't the
+// run's full set; call RemoveStaleAssets once the last batch is in.
+func (s *service) ProcessEntityBatch(ctx context.Context, runID string, assets []CreateAssetInput, lineage []LineageInput, docs []DocumentationInput, stats []StatisticInput, pipelineName, sourceName string) (*ProcessAssetsResponse, error) {
+	return s.processEntities(ctx, runID, assets, lineage, docs, stats, pipelineName, sourceName, false)
+}
+
+func (s *service) processEntities(ctx context.Context, runID string, assets []CreateAssetInput, lineageInputs []LineageInput, docs []DocumentationInput, stats []StatisticInput, pipelineName, sourceName string, removeStale bool) (*ProcessAssetsResponse, error) {
 	run, err := s.repo.GetByRunID(ctx, runID)
 	if err != nil {
 		return nil, fmt.Errorf("getting run: %w", err)
 	}
 
+	s.captureArtifact(ctx, run.ID, &RunPayload{Assets: assets, Lineage: lineageInputs, Documentation: docs, Statistics: stats})
+
 	lastCheckpoints, _ := s.repo.GetLastRunCheckpoints(ctx, pipelineName, sourceName)
 
 	response := &ProcessAssetsResponse{
 		Assets:        make([]AssetResult, 0, len(assets)),
-		Lineage:       make([]LineageResult, 0, len(lineage)),
+		Lineage:       make([]LineageResult, 0, len(lineageInputs)),
 		Documentation: make([]DocumentationResult, 0, len(docs)),
+		Summary:       plugin.EntitySummary{},
 	}
 
-	currentMRNs := make([]string, 0, len(assets))
-	for _, ast := range assets {
-		var assetMRN string
+	var budget *quota.AssetBudget
+	if s.quotas != nil {
+		budget = s.quotas.AssetBudget()
+	}
+
+	currentMRNs := make([]string, len(assets))
+	assetHashes := make([]string, len(assets))
+	statuses := make([]string, len(assets))
+	var toUpdate []string
+	for i, ast := range assets {
 		if ast.MRN != nil && *ast.MRN != "" {
-			assetMRN = *ast.MRN
+			currentMRNs[i] = *ast.MRN
 		} else {
-			assetMRN = mrn.New(ast.Type, ast.Providers[0], ast.Name)
+			currentMRNs[i] = mrn.New(ast.Type, ast.Providers[0], ast.Name)
 		}
-		currentMRNs = append(currentMRNs, assetMRN)
-
-		assetHash := s.hashAsset(ast)
+		assetHashes[i] = s.hashAsset(ast)
+		statuses[i] = checkpointStatus(lastCheckpoints, currentMRNs[i], assetHashes[i])
+		if statuses[i] == StatusUpdated {
+			toUpdate = append(toUpdate, currentMRNs[i])
+		}
+	}
+	existingAssets := s.loadExisting(ctx, toUpdate)
 
-		status := StatusCreated
-		if checkpoint, exists := lastCheckpoints[assetMRN]; exists && checkpoint.Operation != StatusDeleted {
-			if len(checkpoint.SourceFields) > 0 && checkpoint.SourceFields[0] == assetHash {
-				status = StatusUnchanged
-			} else {
-				status = StatusUpdated
+	assetErrs := make([]string, len(assets))
+	var toCreate []int
+	var createInputs []asset.CreateInput
+	for i, ast := range assets {
+		if statuses[i] != StatusCreated {
+			continue
+		}
+		if budget != nil {
+			if err := budget.Allow(ctx, ast.Providers); err != nil {
+				log.Warn().Err(err).Str("asset_mrn", currentMRNs[i]).Msg("Skipped asset over quota")
+				statuses[i] = StatusFailed
+				assetErrs[i] = err.Error()
+				continue
 			}
 		}
+		toCreate = append(toCreate, i)
+		createInputs = append(createInputs, s.assetCreateInput(ast, currentMRNs[i], run.CreatedBy, sourceName))
+	}
+	createdAssets := make(map[int]*asset.Asset, len(toCreate))
+	for j, created := range s.createAssets(ctx, createInputs) {
+		if created != nil {
+			createdAssets[toCreate[j]] = created
+		}
+	}
+
+	recorder := s.newEntityRecorder(run.ID, runID)
+
+	for i, ast := range assets {
+		assetMRN, assetHash, status, assetErr := currentMRNs[i], assetHashes[i], statuses[i], assetErrs[i]
 
+		var duplicates []string
 		if status == StatusCreated {
-			createInput := asset.CreateInput{
-				Name:          &ast.Name,
-				MRN:           &assetMRN,
-				Type:          ast.Type,
-				Providers:     ast.Providers,
-				Description:   ast.Description,
-				Metadata:      ast.Metadata,
-				Schema:        convertSchemaToStringMap(ast.Schema),
-				Tags:          ast.Tags,
-				ExternalLinks: convertToAssetExternalLinks(ast.ExternalLinks),
-				Query:         ast.Query,
-				QueryLanguage: ast.QueryLanguage,
-				CreatedBy:     run.CreatedBy,
-			}
-			if _, err := s.assetService.Create(ctx, createInput); err != nil {
-				log.Error().Err(err).Str("asset_mrn", assetMRN).Msg("Failed to create asset")
+			created, ok := createdAssets[i]
+			if !ok {
 				status = StatusFailed
+			} else if s.duplicateDetector != nil {
+				var err error
+				duplicates, err = s.duplicateDetector.FlagDuplicates(ctx, created)
+				if err != nil {
+					log.Warn().Err(err).Str("asset_mrn", assetMRN).Msg("Failed to check asset for duplicates")
+				}
+				if len(duplicates) > 0 {
+					log.Info().Str("asset_mrn", assetMRN).Strs("duplicate_of", duplicates).Msg("Flagged possible duplicate asset")
+				}
 			}
 		} else if status == StatusUpdated {
 			updateInput := asset.UpdateInput{
@@ -303,14 +431,40 @@ func (s *service) ProcessEntities(ctx context.Context, runID string, assets []Cr
 				ExternalLinks:    convertToAssetExternalLinks(ast.ExternalLinks),
 				Query:            ast.Query,
 				QueryLanguage:    ast.QueryLanguage,
+				Environments:     ast.Environments,
 				SkipNotification: true,
 			}
-			existingAsset, err := s.assetService.GetByMRN(ctx, assetMRN)
+			var err error
+			existingAsset, ok := existingAssets[assetMRN]
+			if ok {
+				// A repeat of the MRN later in the batch must see this update.
+				delete(existingAssets, assetMRN)
+			} else {
+				existingAsset, err = s.assetService.GetByMRN(ctx, assetMRN)
+			}
 			if err != nil {
 				log.Error().Err(err).Str("asset_mrn", assetMRN).Msg("Failed to get existing asset for update")
 				status = StatusFailed
 			} else {
-				if _, err := s.assetService.Update(ctx, existingAsset.ID, updateInput); err != nil {
+				if s.mergePolicy != nil {
+					merged := s.mergePolicy.Resolve(existingAsset, sourceName, contributionFromInput(ast), time.Now())
+					updateInput.Description = merged.Description
+					updateInput.Metadata = merged.Metadata
+					updateInput.Schema = merged.Schema
+					updateInput.Tags = merged.Tags
+					updateInput.ExternalLinks = merged.ExternalLinks
+					updateInput.Query = merged.Query
+					updateInput.Sources = merged.Sources
+				}
+				// Fields curated by hand stay as they are until unlocked.
+				if existingAsset.IsLocked(asset.FieldDescription) {
+					updateInput.Description = nil
+				}
+				if existingAsset.IsLocked(asset.FieldTags) {
+					updateInput.Tags = nil
+				}
+				updateCtx := asset.WithActor(ctx, asset.Actor{Name: pipelineName, Source: asset.RevisionSourceIngestion})
+				if _, err := s.assetService.Update(updateCtx, existingAsset.ID, updateInput); err != nil {
 					log.Error().Err(err).Str("asset_mrn", assetMRN).Msg("Failed to update asset")
 					status = StatusFailed
 				}
@@ -318,73 +472,45 @@ func (s *service) ProcessEntities(ctx context.Context, runID string, assets []Cr
 		}
 
 		result := AssetResult{
-			Name:     ast.Name,
-			Type:     ast.Type,
-			Provider: ast.Providers[0],
-			MRN:      assetMRN,
-			Status:   status,
-			Asset:    ast,
+			Name:               ast.Name,
+			Type:               ast.Type,
+			Provider:           ast.Providers[0],
+			MRN:                assetMRN,
+			Status:             status,
+			Asset:              ast,
+			Error:              assetErr,
+			PossibleDuplicates: duplicates,
 		}
 		response.Assets = append(response.Assets, result)
+		response.Summary.Add("asset", status)
 
-		entity := &RunEntity{
-			ID:         uuid.New().String(),
-			RunID:      runID,
-			EntityType: "asset",
-			EntityMRN:  assetMRN,
-			EntityName: ast.Name,
-			Status:     result.Status,
-			CreatedAt:  time.Now(),
-		}
-		if err := s.repo.AddRunEntity(ctx, run.ID, entity); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", assetMRN).Msg("Failed to add run entity")
-		}
-
-		if err := s.AddCheckpoint(ctx, runID, "asset", assetMRN, result.Status, []string{assetHash}); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", assetMRN).Msg("Failed to add checkpoint")
-		}
+		recorder.record(ctx, "asset", assetMRN, ast.Name, result.Status, assetErr, []string{assetHash})
 	}
 
-	staleEntities := s.GetStaleEntities(ctx, lastCheckpoints, currentMRNs)
-	for _, staleMRN := range staleEntities {
-		if err := s.assetService.DeleteByMRN(ctx, staleMRN); err != nil {
-			if errors.Is(err, asset.ErrAssetNotFound) {
-				log.Debug().Str("asset_mrn", staleMRN).Msg("Stale asset already deleted")
-			} else {
-				log.Error().Err(err).Str("asset_mrn", staleMRN).Msg("Failed to delete stale asset")
-			}
-		}
-
-		entity := &RunEntity{
-			ID:         uuid.New().String(),
-			RunID:      runID,
-			EntityType: "asset",
-			EntityMRN:  staleMRN,
-			Status:     StatusDeleted,
-			CreatedAt:  time.Now(),
-		}
-		if err := s.repo.AddRunEntity(ctx, run.ID, entity); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", staleMRN).Msg("Failed to add deleted run entity")
-		}
-
-		if err := s.AddCheckpoint(ctx, runID, "asset", staleMRN, StatusDeleted, []string{}); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", staleMRN).Msg("Failed to add deleted checkpoint")
-		}
+	if removeStale {
+		staleEntities := s.GetStaleEntities(ctx, lastCheckpoints, currentMRNs)
+		s.deleteStaleAssets(ctx, recorder, staleEntities, response.Summary)
+		response.StaleEntitiesRemoved = staleEntities
 	}
-	response.StaleEntitiesRemoved = staleEntities
 
-	for _, lin := range lineage {
-		lineageMRN := mrn.New("lineage", strings.ToLower(lin.Type), fmt.Sprintf("%s->%s", lin.Source, lin.Target))
+	for _, lin := range lineageInputs {
+		lineageMRN := lineage.CheckpointMRN(lin.Source, lin.Target, lin.Type)
+		lineageHash := s.hashLineage(lin)
 
-		status := StatusCreated
-		if checkpoint, exists := lastCheckpoints[lineageMRN]; exists && checkpoint.Operation != StatusDeleted {
-			status = StatusUpdated
-		}
+		status := checkpointStatus(lastCheckpoints, lineageMRN, lineageHash)
 
-		if status == StatusCreated {
+		// Edges are keyed by source, target and type, so an updated edge is
+		// one whose last write failed or predates hashing; re-asserting it is
+		// an idempotent upsert.
+		if status == StatusCreated || status == StatusUpdated {
 			if _, err := s.lineageService.CreateDirectLineage(ctx, lin.Source, lin.Target, lin.Type); err != nil {
 				log.Error().Err(err).Str("source", lin.Source).Str("target", lin.Target).Str("type", lin.Type).Msg("Failed to create lineage")
 				status = StatusFailed
+			} else if len(lin.Columns) > 0 {
+				if err := s.lineageService.SetColumnLineage(ctx, lin.Source, lin.Target, lin.Columns, ""); err != nil {
+					log.Error().Err(err).Str("source", lin.Source).Str("target", lin.Target).Msg("Failed to set column lineage")
+					status = StatusFailed
+				}
 			}
 		}
 
@@ -395,32 +521,16 @@ func (s *service) ProcessEntities(ctx context.Context, runID string, assets []Cr
 			Status: status,
 		}
 		response.Lineage = append(response.Lineage, result)
+		response.Summary.Add("lineage", status)
 
-		entity := &RunEntity{
-			ID:         uuid.New().String(),
-			RunID:      runID,
-			EntityType: "lineage",
-			EntityMRN:  lineageMRN,
-			EntityName: fmt.Sprintf("%s -> %s", lin.Source, lin.Target),
-			Status:     result.Status,
-			CreatedAt:  time.Now(),
-		}
-		if err := s.repo.AddRunEntity(ctx, run.ID, entity); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", lineageMRN).Msg("Failed to add lineage run entity")
-		}
-
-		if err := s.AddCheckpoint(ctx, runID, "lineage", lineageMRN, result.Status, []string{"source", "target", "type"}); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", lineageMRN).Msg("Failed to add lineage checkpoint")
-		}
+		recorder.record(ctx, "lineage", lineageMRN, fmt.Sprintf("%s -> %s", lin.Source, lin.Target), result.Status, "", []string{lineageHash})
 	}
 
 	for _, doc := range docs {
 		docMRN := mrn.New("documentation", strings.ToLower(doc.Type), doc.AssetMRN)
+		docHash := s.hashDocumentation(doc)
 
-		status := StatusCreated
-		if checkpoint, exists := lastCheckpoints[docMRN]; exists && checkpoint.Operation != StatusDeleted {
-			status = StatusUpdated
-		}
+		status := checkpointStatus(lastCheckpoints, docMRN, docHash)
 
 		result := DocumentationResult{
 			AssetMRN: doc.AssetMRN,
@@ -428,25 +538,13 @@ func (s *service) ProcessEntities(ctx context.Context, runID string, assets []Cr
 			Status:   status,
 		}
 		response.Documentation = append(response.Documentation, result)
+		response.Summary.Add("documentation", status)
 
-		entity := &RunEntity{
-			ID:         uuid.New().String(),
-			RunID:      runID,
-			EntityType: "documentation",
-			EntityMRN:  docMRN,
-			EntityName: fmt.Sprintf("%s (%s)", doc.AssetMRN, doc.Type),
-			Status:     result.Status,
-			CreatedAt:  time.Now(),
-		}
-		if err := s.repo.AddRunEntity(ctx, run.ID, entity); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", docMRN).Msg("Failed to add documentation run entity")
-		}
-
-		if err := s.AddCheckpoint(ctx, runID, "documentation", docMRN, result.Status, []string{"asset_mrn", "type"}); err != nil {
-			log.Error().Err(err).Str("run_id", runID).Str("entity_mrn", docMRN).Msg("Failed to add documentation checkpoint")
-		}
+		recorder.record(ctx, "documentation", docMRN, fmt.Sprintf("%s (%s)", doc.AssetMRN, doc.Type), result.Status, "", []string{docHash})
 	}
 
+	recorder.flush(ctx)
+
 	if len(stats) > 0 {
 		s.processStatistics(ctx, stats)
 	}
@@ -454,6 +552,83 @@ func (s *service) ProcessEntities(ctx context.Context, runID string, assets []Cr
 	return response, nil
 }
 
+// assetCreateInput builds the input that creates an asset a run reports for
+// the first time.
+func (s *service) assetCreateInput(ast CreateAssetInput, assetMRN string, createdBy string, sourceName string) asset.CreateInput {
+	input := asset.CreateInput{
+		Name:          &ast.Name,
+		MRN:           &assetMRN,
+		Type:          ast.Type,
+		Providers:     ast.Providers,
+		Description:   ast.Description,
+		Metadata:      ast.Metadata,
+		Schema:        convertSchemaToStringMap(ast.Schema),
+		Tags:          ast.Tags,
+		ExternalLinks: convertToAssetExternalLinks(ast.ExternalLinks),
+		Query:         ast.Query,
+		QueryLanguage: ast.QueryLanguage,
+		Environments:  ast.Environments,
+		CreatedBy:     createdBy,
+	}
+	if s.mergePolicy != nil {
+		input.Sources = []asset.AssetSource{s.mergePolicy.NewSource(sourceName, contributionFromInput(ast), time.Now())}
+	}
+	return input
+}
+
+// deleteStaleAssets deletes assets a source no longer reports and records
+// them against the run.
+func (s *service) deleteStaleAssets(ctx context.Context, recorder *entityRecorder, staleEntities []string, summary plugin.EntitySummary) {
+	for _, staleMRN := range staleEntities {
+		summary.Add("asset", StatusDeleted)
+		if err := s.assetService.DeleteByMRN(ctx, staleMRN); err != nil {
+			if errors.Is(err, asset.ErrAssetNotFound) {
+				log.Debug().Str("asset_mrn", staleMRN).Msg("Stale asset already deleted")
+			} else {
+				log.Error().Err(err).Str("asset_mrn", staleMRN).Msg("Failed to delete stale asset")
+			}
+		}
+
+		recorder.record(ctx, "asset", staleMRN, "", StatusDeleted, "", []string{})
+	}
+}
+
+// RemoveStaleAssets deletes the assets reported by the source's last
+// completed run but not by this one. It is the final step of a run whose
+// entities were sent with ProcessEntityBatch.
+func (s *service) RemoveStaleAssets(ctx context.Context, runID string) (*ProcessAssetsResponse, error) {
+	run, err := s.repo.GetByRunID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("getting run: %w", err)
+	}
+	if run.Status != plugin.StatusRunning {
+		return nil, fmt.Errorf("%w: cannot remove stale assets for run with status %s", ErrInvalidStatus, run.Status)
+	}
+
+	lastCheckpoints, err := s.repo.GetLastRunCheckpoints(ctx, run.PipelineName, run.SourceName)
+	if err != nil {
+		return nil, fmt.Errorf("getting last run checkpoints: %w", err)
+	}
+	current, err := s.repo.GetRunCheckpointMRNs(ctx, run.ID, "asset")
+	if err != nil {
+		return nil, fmt.Errorf("getting run checkpoints: %w", err)
+	}
+
+	response := &ProcessAssetsResponse{
+		Assets:        []AssetResult{},
+		Lineage:       []LineageResult{},
+		Documentation: []DocumentationResult{},
+		Summary:       plugin.EntitySummary{},
+	}
+	staleEntities := s.GetStaleEntities(ctx, lastCheckpoints, current)
+	recorder := s.newEntityRecorder(run.ID, runID)
+	s.deleteStaleAssets(ctx, recorder, staleEntities, response.Summary)
+	recorder.flush(ctx)
+	response.StaleEntitiesRemoved = staleEntities
+
+	return response, nil
+}
+
 func (s *service) processStatistics(ctx context.Context, statistics []StatisticInput) {
 	if len(statistics) == 0 {
 		return
@@ -479,6 +654,17 @@ func (s *service) processStatistics(ctx context.Context, statistics []StatisticI
 	}
 }
 
+func contributionFromInput(ast CreateAssetInput) asset.SourceContribution {
+	return asset.SourceContribution{
+		Description:   ast.Description,
+		Metadata:      ast.Metadata,
+		Schema:        convertSchemaToStringMap(ast.Schema),
+		Tags:          ast.Tags,
+		ExternalLinks: convertToAssetExternalLinks(ast.ExternalLinks),
+		Query:         ast.Query,
+	}
+}
+
 func convertSchemaToStringMap(schema map[string]interface{}) map[string]string {
 	result := make(map[string]string)
 	for k, v := range schema {
@@ -714,8 +900,13 @@ func (s *service) GetStaleEntities(ctx context.Context, lastCheckpoints map[stri
 		currentSet[mrn] = true
 	}
 
+	// Only assets are removed when a source stops reporting them; lineage and
+	// documentation checkpoints are tracked for change detection only.
 	var staleEntities []string
 	for mrn, checkpoint := range lastCheckpoints {
+		if checkpoint.EntityType != "asset" {
+			continue
+		}
 		if checkpoint.Operation != StatusDeleted && !currentSet[mrn] {
 			staleEntities = append(staleEntities, mrn)
 		}
@@ -791,7 +982,22 @@ func (s *service) ListRunEntities(ctx context.Context, runID, entityType, status
 	return s.repo.ListRunEntities(ctx, run.ID, entityType, status, limit, offset)
 }
 
-func (s *service) hashAsset(asset CreateAssetInput) string {
+// checkpointStatus compares an entity's content hash with the checkpoint
+// recorded for it by the last successful run. Checkpoints are keyed by MRN
+// and store the hash as their only source field.
+func checkpointStatus(lastCheckpoints map[string]*plugin.RunCheckpoint, entityMRN, hash string) string {
+	checkpoint, exists := lastCheckpoints[entityMRN]
+	if !exists || checkpoint.Operation == StatusDeleted {
+		return StatusCreated
+	}
+	// A failed write is retried even if the content hasn't changed.
+	if checkpoint.Operation != StatusFailed && len(checkpoint.SourceFields) > 0 && checkpoint.SourceFields[0] == hash {
+		return StatusUnchanged
+	}
+	return StatusUpdated
+}
+
+func (s *service) hashAsset(input CreateAssetInput) string {
 	normalized := struct {
 		Name          string                 `json:"name"`
 		Type          string                 `json:"type"`
@@ -802,19 +1008,35 @@ func (s *service) hashAsset(asset CreateAssetInput) string {
 		Tags          []string               `json:"tags"`
 		Sources       []string               `json:"sources"`
 		ExternalLinks []map[string]string    `json:"external_links"`
+		// Omitted when empty so hashes of assets without environments
+		// don't change.
+		Environments map[string]asset.Environment `json:"environments,omitempty"`
 	}{
-		Name:          asset.Name,
-		Type:          asset.Type,
-		Providers:     asset.Providers,
-		Description:   asset.Description,
-		Metadata:      asset.Metadata,
-		Schema:        asset.Schema,
-		Tags:          asset.Tags,
-		Sources:       asset.Sources,
-		ExternalLinks: asset.ExternalLinks,
-	}
-
-	data, _ := json.Marshal(normalized)
+		Name:          input.Name,
+		Type:          input.Type,
+		Providers:     input.Providers,
+		Description:   input.Description,
+		Metadata:      input.Metadata,
+		Schema:        input.Schema,
+		Tags:          input.Tags,
+		Sources:       input.Sources,
+		ExternalLinks: input.ExternalLinks,
+		Environments:  input.Environments,
+	}
+
+	return hashJSON(normalized)
+}
+
+func (s *service) hashLineage(lin LineageInput) string {
+	return hashJSON(lin)
+}
+
+func (s *service) hashDocumentation(doc DocumentationInput) string {
+	return hashJSON(doc)
+}
+
+func hashJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
 	hash := sha256.Sum256(data)
 	return fmt.Sprintf("%x", hash)
 }
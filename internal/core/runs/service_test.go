@@ -0,0 +1,192 @@
+package runs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointStatus(t *testing.T) {
+	svc := &service{}
+	lin := LineageInput{Source: "mrn://topic/kafka/orders", Target: "mrn://table/postgres/orders", Type: "DIRECT"}
+	doc := DocumentationInput{AssetMRN: "mrn://table/postgres/orders", Content: "# Orders", Type: "markdown"}
+
+	changedLin := lin
+	changedLin.Columns = []lineage.ColumnMapping{{SourceColumn: "id", TargetColumn: "order_id"}}
+	changedDoc := doc
+	changedDoc.Content = "# Orders v2"
+
+	entities := []struct {
+		kind    string
+		hash    string
+		changed string
+	}{
+		{kind: "lineage", hash: svc.hashLineage(lin), changed: svc.hashLineage(changedLin)},
+		{kind: "documentation", hash: svc.hashDocumentation(doc), changed: svc.hashDocumentation(changedDoc)},
+	}
+
+	// operation is that of the last run's checkpoint, empty when there is
+	// none.
+	tests := []struct {
+		name      string
+		operation string
+		noHash    bool
+		changed   bool
+		want      string
+	}{
+		{name: "first run", want: StatusCreated},
+		{name: "deleted by a previous run", operation: StatusDeleted, want: StatusCreated},
+		{name: "same content", operation: StatusCreated, want: StatusUnchanged},
+		{name: "same content as an unchanged run", operation: StatusUnchanged, want: StatusUnchanged},
+		{name: "changed content", operation: StatusUpdated, changed: true, want: StatusUpdated},
+		{name: "checkpoint without hash", operation: StatusCreated, noHash: true, want: StatusUpdated},
+		{name: "retry after failure", operation: StatusFailed, want: StatusUpdated},
+		{name: "changed after failure", operation: StatusFailed, changed: true, want: StatusUpdated},
+	}
+
+	for _, entity := range entities {
+		for _, tt := range tests {
+			t.Run(entity.kind+"/"+tt.name, func(t *testing.T) {
+				const mrn = "mrn://entity"
+				checkpoints := map[string]*plugin.RunCheckpoint{}
+				if tt.operation != "" {
+					checkpoint := &plugin.RunCheckpoint{Operation: tt.operation, SourceFields: []string{entity.hash}}
+					if tt.noHash {
+						checkpoint.SourceFields = nil
+					}
+					checkpoints[mrn] = checkpoint
+				}
+
+				hash := entity.hash
+				if tt.changed {
+					hash = entity.changed
+				}
+				assert.Equal(t, tt.want, checkpointStatus(checkpoints, mrn, hash))
+			})
+		}
+	}
+}
+
+func TestHashLineage(t *testing.T) {
+	svc := &service{}
+	lin := LineageInput{Source: "mrn://a", Target: "mrn://b", Type: "DIRECT"}
+	hash := svc.hashLineage(lin)
+
+	assert.Equal(t, hash, svc.hashLineage(LineageInput{Source: "mrn://a", Target: "mrn://b", Type: "DIRECT"}))
+	assert.Len(t, hash, 64)
+
+	changes := map[string]LineageInput{
+		"source":  {Source: "mrn://c", Target: "mrn://b", Type: "DIRECT"},
+		"target":  {Source: "mrn://a", Target: "mrn://c", Type: "DIRECT"},
+		"type":    {Source: "mrn://a", Target: "mrn://b", Type: "DEPENDS_ON"},
+		"columns": {Source: "mrn://a", Target: "mrn://b", Type: "DIRECT", Columns: []lineage.ColumnMapping{{SourceColumn: "id", TargetColumn: "id"}}},
+	}
+	for field, changed := range changes {
+		assert.NotEqual(t, hash, svc.hashLineage(changed), "changing %s should change the hash", field)
+	}
+}
+
+func TestHashDocumentation(t *testing.T) {
+	svc := &service{}
+	doc := DocumentationInput{AssetMRN: "mrn://a", Content: "# A", Type: "markdown"}
+	hash := svc.hashDocumentation(doc)
+
+	assert.Equal(t, hash, svc.hashDocumentation(DocumentationInput{AssetMRN: "mrn://a", Content: "# A", Type: "markdown"}))
+
+	changes := map[string]DocumentationInput{
+		"asset":   {AssetMRN: "mrn://b", Content: "# A", Type: "markdown"},
+		"content": {AssetMRN: "mrn://a", Content: "# B", Type: "markdown"},
+		"type":    {AssetMRN: "mrn://a", Content: "# A", Type: "text"},
+	}
+	for field, changed := range changes {
+		assert.NotEqual(t, hash, svc.hashDocumentation(changed), "changing %s should change the hash", field)
+	}
+}
+
+// runRepo serves a single run whose previous checkpoints are given.
+type runRepo struct {
+	batchRepo
+	checkpoints map[string]*plugin.RunCheckpoint
+}
+
+func (r *runRepo) GetByRunID(_ context.Context, runID string) (*plugin.Run, error) {
+	return &plugin.Run{ID: "run-db-id", RunID: runID}, nil
+}
+
+func (r *runRepo) GetLastRunCheckpoints(context.Context, string, string) (map[string]*plugin.RunCheckpoint, error) {
+	return r.checkpoints, nil
+}
+
+// updateAssets holds existing assets by MRN and records updates to them.
+type updateAssets struct {
+	asset.Service
+	byMRN   map[string]*asset.Asset
+	updates map[string]asset.UpdateInput
+}
+
+func (s *updateAssets) GetByMRNs(_ context.Context, mrns []string) (map[string]*asset.Asset, error) {
+	found := map[string]*asset.Asset{}
+	for _, m := range mrns {
+		if a, ok := s.byMRN[m]; ok {
+			found[m] = a
+		}
+	}
+	return found, nil
+}
+
+func (s *updateAssets) Update(_ context.Context, id string, input asset.UpdateInput) (*asset.Asset, error) {
+	s.updates[id] = input
+	return &asset.Asset{ID: id}, nil
+}
+
+func TestProcessEntitiesSkipsLockedFields(t *testing.T) {
+	tests := []struct {
+		name            string
+		locked          []string
+		wantDescription bool
+		wantTags        bool
+	}{
+		{name: "nothing locked", wantDescription: true, wantTags: true},
+		{name: "description locked", locked: []string{asset.FieldDescription}, wantTags: true},
+		{name: "tags locked", locked: []string{asset.FieldTags}, wantDescription: true},
+		{name: "both locked", locked: []string{asset.FieldDescription, asset.FieldTags}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const assetMRN = "mrn://table/postgres/orders"
+			assets := &updateAssets{
+				byMRN:   map[string]*asset.Asset{assetMRN: {ID: "a1", LockedFields: tt.locked}},
+				updates: map[string]asset.UpdateInput{},
+			}
+			repo := &runRepo{checkpoints: map[string]*plugin.RunCheckpoint{
+				assetMRN: {Operation: StatusCreated, SourceFields: []string{"old-hash"}},
+			}}
+			svc := &service{repo: repo, assetService: assets}
+
+			mrn, description := assetMRN, "Orders from the shop"
+			resp, err := svc.ProcessEntityBatch(context.Background(), "run-1", []CreateAssetInput{{
+				Name:        "orders",
+				MRN:         &mrn,
+				Type:        "Table",
+				Providers:   []string{"PostgreSQL"},
+				Description: &description,
+				Tags:        []string{"sales"},
+			}}, nil, nil, nil, "pipeline", "source")
+			require.NoError(t, err)
+			require.Len(t, resp.Assets, 1)
+			assert.Equal(t, StatusUpdated, resp.Assets[0].Status)
+
+			input, ok := assets.updates["a1"]
+			require.True(t, ok)
+			assert.Equal(t, "orders", *input.Name)
+			assert.Equal(t, tt.wantDescription, input.Description != nil)
+			assert.Equal(t, tt.wantTags, input.Tags != nil)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccountResolver supplies the name runs are attributed to when a
+// schedule runs as a service account. It returns ErrServiceAccountUnavailable
+// for accounts that were deleted or deactivated.
+type ServiceAccountResolver interface {
+	ResolveServiceAccount(ctx context.Context, id string) (createdBy string, err error)
+}
+
+// SetServiceAccountResolver enables schedules to run as service accounts.
+func (s *ScheduleService) SetServiceAccountResolver(resolver ServiceAccountResolver) {
+	s.serviceAccounts = resolver
+}
+
+// SetScheduleServiceAccount makes a schedule run as a service account, or
+// with a nil serviceAccountID, as whoever triggers it.
+func (s *ScheduleService) SetScheduleServiceAccount(ctx context.Context, id string, serviceAccountID *string) (*Schedule, error) {
+	if serviceAccountID != nil {
+		if err := s.CheckServiceAccount(ctx, *serviceAccountID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.SetScheduleServiceAccount(ctx, id, serviceAccountID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSchedule(ctx, id)
+}
+
+// CheckServiceAccount returns ErrServiceAccountUnavailable unless a schedule
+// can run as the service account serviceAccountID.
+func (s *ScheduleService) CheckServiceAccount(ctx context.Context, serviceAccountID string) error {
+	if _, err := uuid.Parse(serviceAccountID); err != nil {
+		return ErrServiceAccountUnavailable
+	}
+	_, err := s.resolveServiceAccount(ctx, serviceAccountID)
+	return err
+}
+
+// RunAs returns who a run of schedule is attributed to: its service account
+// when it has one, otherwise fallback. A schedule whose account was deleted
+// or deactivated doesn't run under another name; it fails with
+// ErrServiceAccountUnavailable until the account is restored or replaced.
+func (s *ScheduleService) RunAs(ctx context.Context, schedule *Schedule, fallback string) (string, error) {
+	if schedule.ServiceAccountID == nil {
+		return fallback, nil
+	}
+	return s.resolveServiceAccount(ctx, *schedule.ServiceAccountID)
+}
+
+func (s *ScheduleService) resolveServiceAccount(ctx context.Context, id string) (string, error) {
+	if s.serviceAccounts == nil {
+		return "", fmt.Errorf("%w: service accounts are not available", ErrServiceAccountUnavailable)
+	}
+	createdBy, err := s.serviceAccounts.ResolveServiceAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrServiceAccountUnavailable) {
+			return "", err
+		}
+		return "", fmt.Errorf("resolving service account: %w", err)
+	}
+	return createdBy, nil
+}
@@ -44,11 +44,29 @@ type Repository interface {
 	List(ctx context.Context, pipelineName string, limit, offset int) ([]*plugin.Run, int, error)
 	ListWithFilters(ctx context.Context, pipelines, statuses []string, limit, offset int) ([]*plugin.Run, int, []string, error)
 	AddCheckpoint(ctx context.Context, runDBID string, checkpoint *plugin.RunCheckpoint) error
+	// AddCheckpoints upserts several checkpoints in one statement. When two
+	// share an entity the later one wins, as with repeated AddCheckpoint.
+	AddCheckpoints(ctx context.Context, runDBID string, checkpoints []*plugin.RunCheckpoint) error
 	DeleteCheckpoints(ctx context.Context, pipelineName, sourceName string) error
+	CompactCheckpoints(ctx context.Context, history int) (int64, error)
+	CountCheckpoints(ctx context.Context) (int64, error)
 	GetLastRunCheckpoints(ctx context.Context, pipelineName, sourceName string) (map[string]*plugin.RunCheckpoint, error)
+	GetRunCheckpointMRNs(ctx context.Context, runDBID, entityType string) ([]string, error)
 	CleanupStaleRuns(ctx context.Context, timeout time.Duration) (int, error)
 	AddRunEntity(ctx context.Context, runDBID string, entity *RunEntity) error
+	// AddRunEntities upserts several run entities in one statement, the
+	// later of any two for the same entity winning.
+	AddRunEntities(ctx context.Context, runDBID string, entities []*RunEntity) error
 	ListRunEntities(ctx context.Context, runDBID, entityType, status string, limit, offset int) ([]*RunEntity, int, error)
+	// ListRunChanges returns up to limit of the entities a run created,
+	// updated or deleted, with the total count of each.
+	ListRunChanges(ctx context.Context, runDBID string, limit int) ([]DeltaChange, map[string]int, error)
+	// SaveArtifact stores a compressed run payload as the run's next
+	// artifact, filling in its sequence and creation time.
+	SaveArtifact(ctx context.Context, artifact *RunArtifact, data []byte) error
+	ListArtifacts(ctx context.Context, runDBID string) ([]*RunArtifact, error)
+	GetArtifact(ctx context.Context, runDBID string, sequence int) (*RunArtifact, []byte, error)
+	DeleteArtifactsBefore(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
 type PostgresRepository struct {
@@ -182,6 +200,51 @@ func (r *PostgresRepository) AddCheckpoint(ctx context.Context, runDBID string,
 	return nil
 }
 
+func (r *PostgresRepository) AddCheckpoints(ctx context.Context, runDBID string, checkpoints []*plugin.RunCheckpoint) error {
+	checkpoints = lastPerEntity(checkpoints, func(c *plugin.RunCheckpoint) string {
+		return c.EntityType + "\x00" + c.EntityMRN
+	})
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(checkpoints))
+	entityTypes := make([]string, len(checkpoints))
+	entityMRNs := make([]string, len(checkpoints))
+	operations := make([]string, len(checkpoints))
+	sourceFields := make([]string, len(checkpoints))
+	createdAt := make([]time.Time, len(checkpoints))
+	for i, c := range checkpoints {
+		fields := c.SourceFields
+		if fields == nil {
+			fields = []string{}
+		}
+		// Postgres can't unnest an array of arrays into rows, so each
+		// checkpoint's source fields travel as a JSON array.
+		fieldsJSON, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("marshaling source fields: %w", err)
+		}
+		ids[i], entityTypes[i], entityMRNs[i], operations[i] = c.ID, c.EntityType, c.EntityMRN, c.Operation
+		sourceFields[i], createdAt[i] = string(fieldsJSON), c.CreatedAt
+	}
+
+	query := `
+		INSERT INTO run_checkpoints (id, run_id, entity_type, entity_mrn, operation, source_fields, created_at)
+		SELECT c.id, $1::uuid, c.entity_type, c.entity_mrn, c.operation,
+			ARRAY(SELECT jsonb_array_elements_text(c.source_fields::jsonb)), c.created_at
+		FROM UNNEST($2::uuid[], $3::text[], $4::text[], $5::text[], $6::text[], $7::timestamptz[])
+			AS c(id, entity_type, entity_mrn, operation, source_fields, created_at)
+		ON CONFLICT (run_id, entity_type, entity_mrn)
+		DO UPDATE SET operation = EXCLUDED.operation, source_fields = EXCLUDED.source_fields, created_at = EXCLUDED.created_at`
+
+	if _, err := r.db.Exec(ctx, query, runDBID, ids, entityTypes, entityMRNs, operations, sourceFields, createdAt); err != nil {
+		return fmt.Errorf("inserting checkpoints: %w", err)
+	}
+
+	return nil
+}
+
 func (r *PostgresRepository) GetLastRunCheckpoints(ctx context.Context, pipelineName, sourceName string) (map[string]*plugin.RunCheckpoint, error) {
 	query := `
 		WITH last_successful_run AS (
@@ -223,6 +286,34 @@ func (r *PostgresRepository) GetLastRunCheckpoints(ctx context.Context, pipeline
 	return checkpoints, nil
 }
 
+// GetRunCheckpointMRNs returns the MRNs of the entities of a type that a run
+// has checkpointed so far, excluding deletions.
+func (r *PostgresRepository) GetRunCheckpointMRNs(ctx context.Context, runDBID, entityType string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT entity_mrn FROM run_checkpoints
+		WHERE run_id = $1 AND entity_type = $2 AND operation != 'deleted'`,
+		runDBID, entityType,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying run checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	mrns := []string{}
+	for rows.Next() {
+		var mrn string
+		if err := rows.Scan(&mrn); err != nil {
+			return nil, fmt.Errorf("scanning run checkpoint: %w", err)
+		}
+		mrns = append(mrns, mrn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating run checkpoints: %w", err)
+	}
+
+	return mrns, nil
+}
+
 func (r *PostgresRepository) CleanupStaleRuns(ctx context.Context, timeout time.Duration) (int, error) {
 	cutoffTime := time.Now().Add(-timeout)
 
@@ -260,6 +351,60 @@ func (r *PostgresRepository) AddRunEntity(ctx context.Context, runDBID string, e
 	return nil
 }
 
+func (r *PostgresRepository) AddRunEntities(ctx context.Context, runDBID string, entities []*RunEntity) error {
+	entities = lastPerEntity(entities, func(e *RunEntity) string {
+		return e.EntityType + "\x00" + e.EntityMRN
+	})
+	if len(entities) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(entities))
+	entityTypes := make([]string, len(entities))
+	entityMRNs := make([]string, len(entities))
+	names := make([]string, len(entities))
+	statuses := make([]string, len(entities))
+	errorMessages := make([]string, len(entities))
+	createdAt := make([]time.Time, len(entities))
+	for i, e := range entities {
+		ids[i], entityTypes[i], entityMRNs[i], names[i] = e.ID, e.EntityType, e.EntityMRN, e.EntityName
+		statuses[i], errorMessages[i], createdAt[i] = e.Status, e.ErrorMessage, e.CreatedAt
+	}
+
+	query := `
+		INSERT INTO run_entities (id, run_id, entity_type, entity_mrn, entity_name, status, error_message, created_at)
+		SELECT e.id, $1::uuid, e.entity_type, e.entity_mrn, e.entity_name, e.status, e.error_message, e.created_at
+		FROM UNNEST($2::uuid[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::timestamptz[])
+			AS e(id, entity_type, entity_mrn, entity_name, status, error_message, created_at)
+		ON CONFLICT (run_id, entity_type, entity_mrn)
+		DO UPDATE SET status = EXCLUDED.status, error_message = EXCLUDED.error_message, created_at = EXCLUDED.created_at`
+
+	if _, err := r.db.Exec(ctx, query, runDBID, ids, entityTypes, entityMRNs, names, statuses, errorMessages, createdAt); err != nil {
+		return fmt.Errorf("inserting run entities: %w", err)
+	}
+
+	return nil
+}
+
+// lastPerEntity drops all but the last of items sharing a key, keeping
+// their order. A multi-row upsert fails if it touches the same row twice.
+func lastPerEntity[T any](items []T, key func(T) string) []T {
+	last := make(map[string]int, len(items))
+	for i, item := range items {
+		last[key(item)] = i
+	}
+	if len(last) == len(items) {
+		return items
+	}
+	kept := make([]T, 0, len(last))
+	for i, item := range items {
+		if last[key(item)] == i {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
 func (r *PostgresRepository) ListRunEntities(ctx context.Context, runDBID, entityType, status string, limit, offset int) ([]*RunEntity, int, error) {
 	countQuery := "SELECT COUNT(*) FROM run_entities WHERE run_id = $1"
 	countArgs := []interface{}{runDBID}
@@ -505,3 +650,98 @@ func (r *PostgresRepository) DeleteCheckpoints(ctx context.Context, pipelineName
 	return nil
 }
 
+// CompactCheckpoints deletes all but the latest history checkpoints of each
+// entity for each pipeline and source. Checkpoints of running runs and of the
+// last completed run of each pipeline and source are always kept, since
+// change detection reads them.
+func (r *PostgresRepository) CompactCheckpoints(ctx context.Context, history int) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		WITH last_completed AS (
+			SELECT DISTINCT ON (pipeline_name, source_name) id
+			FROM runs
+			WHERE status = 'completed'
+			ORDER BY pipeline_name, source_name, completed_at DESC
+		),
+		ranked AS (
+			SELECT c.id, c.run_id, r.status,
+				row_number() OVER (
+					PARTITION BY r.pipeline_name, r.source_name, c.entity_type, c.entity_mrn
+					ORDER BY r.started_at DESC, c.created_at DESC
+				) AS rank
+			FROM run_checkpoints c
+			JOIN runs r ON r.id = c.run_id
+		)
+		DELETE FROM run_checkpoints c
+		USING ranked
+		WHERE c.id = ranked.id
+		  AND ranked.rank > $1
+		  AND ranked.status != 'running'
+		  AND ranked.run_id NOT IN (SELECT id FROM last_completed)`,
+		history,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("compacting checkpoints: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *PostgresRepository) CountCheckpoints(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM run_checkpoints").Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting checkpoints: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) ListRunChanges(ctx context.Context, runDBID string, limit int) ([]DeltaChange, map[string]int, error) {
+	counts := map[string]int{StatusCreated: 0, StatusUpdated: 0, StatusDeleted: 0}
+	changed := []string{StatusCreated, StatusUpdated, StatusDeleted}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT status, COUNT(*)
+		FROM run_entities
+		WHERE run_id = $1 AND status = ANY($2)
+		GROUP BY status`, runDBID, changed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("counting run changes: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("scanning run change count: %w", err)
+		}
+		counts[status] = n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating run change counts: %w", err)
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT entity_mrn, entity_type, status
+		FROM run_entities
+		WHERE run_id = $1 AND status = ANY($2)
+		ORDER BY created_at, entity_mrn
+		LIMIT $3`, runDBID, changed, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying run changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []DeltaChange{}
+	for rows.Next() {
+		var c DeltaChange
+		if err := rows.Scan(&c.MRN, &c.EntityType, &c.Change); err != nil {
+			return nil, nil, fmt.Errorf("scanning run change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating run changes: %w", err)
+	}
+
+	return changes, counts, nil
+}
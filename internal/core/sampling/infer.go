@@ -0,0 +1,536 @@
+package sampling
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+// Confidence marks how far an inferred schema can be trusted. Schemas read
+// from a handful of rows, or from columns whose values disagree, are low.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+const (
+	// minConfidentRows is the number of non-null values a column needs
+	// before its type is trusted.
+	minConfidentRows = 10
+	// typeAgreement is the share of values that must parse as a type for
+	// the column to be given that type.
+	typeAgreement = 0.9
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// Column is an inferred column, typed with JSON Schema type names.
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Format is the JSON Schema string format, date or date-time.
+	Format     string     `json:"format,omitempty"`
+	Nullable   bool       `json:"nullable"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// Sample is the schema inferred from the start of one file.
+type Sample struct {
+	Format    Format `json:"format"`
+	Delimiter string `json:"delimiter,omitempty"`
+	// Header is false when a delimited file has no header row, in which
+	// case columns are named column_1, column_2 and so on.
+	Header     bool       `json:"header"`
+	Rows       int        `json:"rows"`
+	Columns    []Column   `json:"columns"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// FormatOf returns the format sampled for a file name, or "" when the file is
+// not one that can be sampled.
+func FormatOf(name string) Format {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv", ".tsv", ".psv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".jsonl", ".ndjson":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// Infer reads up to maxRows records from the start of a file and infers its
+// columns. truncated reports whether data was cut short, in which case the
+// trailing partial record is ignored.
+func Infer(name string, data []byte, maxRows int, truncated bool) (*Sample, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if truncated {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+
+	switch FormatOf(name) {
+	case FormatCSV:
+		return inferDelimited(data, maxRows, strings.ToLower(path.Ext(name)))
+	case FormatJSON, FormatNDJSON:
+		return inferJSON(data, maxRows)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// delimiters are tried in order; earlier ones win ties.
+var delimiters = []rune{',', '\t', ';', '|'}
+
+func inferDelimited(data []byte, maxRows int, ext string) (*Sample, error) {
+	candidates := delimiters
+	switch ext {
+	case ".tsv":
+		candidates = []rune{'\t'}
+	case ".psv":
+		candidates = []rune{'|'}
+	}
+
+	var (
+		best       [][]string
+		bestDelim  rune
+		consistent bool
+	)
+	for _, d := range candidates {
+		records := readRecords(data, d, maxRows+1)
+		if len(records) == 0 {
+			continue
+		}
+		width, ok := recordWidth(records)
+		if width < 2 && len(candidates) > 1 {
+			continue
+		}
+		if best == nil || (ok && !consistent) || (ok == consistent && width > len(best[0])) {
+			best, bestDelim, consistent = records, d, ok
+		}
+	}
+	if best == nil {
+		// Nothing split the rows; treat the file as a single column.
+		best = readRecords(data, candidates[0], maxRows+1)
+		bestDelim, consistent = candidates[0], true
+	}
+	if len(best) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	header := looksLikeHeader(best[0])
+	names := make([]string, len(best[0]))
+	rows := best
+	if header {
+		copy(names, best[0])
+		rows = best[1:]
+	} else {
+		for i := range names {
+			names[i] = fmt.Sprintf("column_%d", i+1)
+		}
+	}
+	if len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+
+	observers := make([]*observer, len(names))
+	for i := range observers {
+		observers[i] = &observer{textual: true}
+	}
+	for _, row := range rows {
+		for i := range names {
+			if i >= len(row) {
+				observers[i].observe(kindNull)
+				continue
+			}
+			observers[i].observe(textKind(row[i]))
+		}
+	}
+
+	sample := &Sample{
+		Format:    FormatCSV,
+		Delimiter: string(bestDelim),
+		Header:    header,
+		Rows:      len(rows),
+	}
+	for i, name := range names {
+		sample.Columns = append(sample.Columns, observers[i].column(strings.TrimSpace(name)))
+	}
+	sample.Confidence = overall(sample.Columns, len(rows))
+	if !consistent || !header {
+		sample.Confidence = lower(sample.Confidence, ConfidenceMedium)
+	}
+	return sample, nil
+}
+
+func readRecords(data []byte, delimiter rune, limit int) [][]string {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = false
+
+	var records [][]string
+	for len(records) < limit {
+		record, err := r.Read()
+		if err != nil {
+			break
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// recordWidth returns the field count of the first record and whether every
+// record has it.
+func recordWidth(records [][]string) (int, bool) {
+	width := len(records[0])
+	for _, r := range records[1:] {
+		if len(r) != width {
+			return width, false
+		}
+	}
+	return width, true
+}
+
+// looksLikeHeader reports whether a first row names columns rather than
+// holding data: every value is non-empty, distinct and not a number, boolean
+// or date.
+func looksLikeHeader(row []string) bool {
+	seen := make(map[string]bool, len(row))
+	for _, v := range row {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] || textKind(v) != kindString {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+func inferJSON(data []byte, maxRows int) (*Sample, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	var records []map[string]interface{}
+	format := FormatNDJSON
+	if trimmed[0] == '[' {
+		format = FormatJSON
+		records = readJSONArray(trimmed, maxRows)
+	} else {
+		records = readJSONLines(trimmed, maxRows)
+		if len(records) == 0 {
+			var doc map[string]interface{}
+			dec := json.NewDecoder(bytes.NewReader(trimmed))
+			dec.UseNumber()
+			if err := dec.Decode(&doc); err != nil {
+				return nil, fmt.Errorf("parsing JSON: %w", err)
+			}
+			format = FormatJSON
+			records = []map[string]interface{}{doc}
+		}
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no JSON objects found")
+	}
+
+	var order []string
+	observers := map[string]*observer{}
+	for _, rec := range records {
+		for key := range rec {
+			if _, ok := observers[key]; !ok {
+				observers[key] = &observer{}
+				order = append(order, key)
+			}
+		}
+	}
+	// Keys are seen in map order; sort by first appearance in the raw data
+	// so columns follow the file.
+	sortByOffset(order, trimmed)
+	for _, rec := range records {
+		for _, key := range order {
+			v, ok := rec[key]
+			if !ok {
+				observers[key].observe(kindNull)
+				continue
+			}
+			observers[key].observe(jsonKind(v))
+		}
+	}
+
+	sample := &Sample{Format: format, Header: true, Rows: len(records)}
+	for _, key := range order {
+		sample.Columns = append(sample.Columns, observers[key].column(key))
+	}
+	sample.Confidence = overall(sample.Columns, len(records))
+	return sample, nil
+}
+
+func readJSONArray(data []byte, maxRows int) []map[string]interface{} {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return nil
+	}
+	var records []map[string]interface{}
+	for dec.More() && len(records) < maxRows {
+		var rec map[string]interface{}
+		if err := dec.Decode(&rec); err != nil {
+			// A truncated trailing element ends the sample.
+			break
+		}
+		if rec != nil {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+func readJSONLines(data []byte, maxRows int) []map[string]interface{} {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	var records []map[string]interface{}
+	for scanner.Scan() && len(records) < maxRows {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&rec); err != nil || dec.InputOffset() != int64(len(line)) {
+			// A first line that is not a whole object means the file is a
+			// single pretty-printed document, not JSON lines.
+			if len(records) == 0 {
+				return nil
+			}
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func sortByOffset(keys []string, data []byte) {
+	offset := make(map[string]int, len(keys))
+	for _, k := range keys {
+		quoted, _ := json.Marshal(k)
+		if i := bytes.Index(data, quoted); i >= 0 {
+			offset[k] = i
+		} else {
+			offset[k] = len(data)
+		}
+	}
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && offset[keys[j]] < offset[keys[j-1]]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+}
+
+type kind int
+
+const (
+	kindNull kind = iota
+	kindInteger
+	kindNumber
+	kindBoolean
+	kindDate
+	kindTimestamp
+	kindString
+	kindObject
+	kindArray
+)
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05Z07:00",
+}
+
+func textKind(v string) kind {
+	v = strings.TrimSpace(v)
+	if v == "" || strings.EqualFold(v, "null") || strings.EqualFold(v, "NA") {
+		return kindNull
+	}
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return kindInteger
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil && !strings.ContainsAny(v, "xX") && !isSpecialFloat(v) {
+		return kindNumber
+	}
+	if strings.EqualFold(v, "true") || strings.EqualFold(v, "false") {
+		return kindBoolean
+	}
+	return stringKind(v)
+}
+
+func isSpecialFloat(v string) bool {
+	v = strings.ToLower(strings.TrimLeft(v, "+-"))
+	return v == "inf" || v == "infinity" || v == "nan"
+}
+
+func stringKind(v string) kind {
+	if len(v) < 10 || v[4] != '-' {
+		return kindString
+	}
+	if _, err := time.Parse("2006-01-02", v); err == nil {
+		return kindDate
+	}
+	for _, layout := range timestampLayouts {
+		if _, err := time.Parse(layout, v); err == nil {
+			return kindTimestamp
+		}
+	}
+	return kindString
+}
+
+func jsonKind(v interface{}) kind {
+	switch t := v.(type) {
+	case nil:
+		return kindNull
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return kindInteger
+		}
+		return kindNumber
+	case bool:
+		return kindBoolean
+	case string:
+		return stringKind(t)
+	case map[string]interface{}:
+		return kindObject
+	case []interface{}:
+		return kindArray
+	}
+	return kindString
+}
+
+// observer counts the kinds of value seen in one column.
+type observer struct {
+	// textual columns come from delimited files, where any value can be
+	// read as a string.
+	textual bool
+	counts  [kindArray + 1]int
+	total   int
+}
+
+func (o *observer) observe(k kind) {
+	o.counts[k]++
+	o.total++
+}
+
+// candidate is a column type and the value kinds that fit it.
+type candidate struct {
+	typ    string
+	format string
+	kinds  []kind
+}
+
+// candidates are tried from most to least specific.
+var candidates = []candidate{
+	{typ: "integer", kinds: []kind{kindInteger}},
+	{typ: "number", kinds: []kind{kindInteger, kindNumber}},
+	{typ: "boolean", kinds: []kind{kindBoolean}},
+	{typ: "string", format: "date", kinds: []kind{kindDate}},
+	{typ: "string", format: "date-time", kinds: []kind{kindDate, kindTimestamp}},
+	{typ: "object", kinds: []kind{kindObject}},
+	{typ: "array", kinds: []kind{kindArray}},
+	{typ: "string", kinds: []kind{kindString, kindDate, kindTimestamp}},
+}
+
+// column picks the most specific type that at least typeAgreement of the
+// non-null values fit. When none does, the best fitting type is used with
+// low confidence.
+func (o *observer) column(name string) Column {
+	col := Column{Name: name, Type: "string", Nullable: o.counts[kindNull] > 0}
+	nonNull := o.total - o.counts[kindNull]
+	if nonNull == 0 {
+		col.Nullable = true
+		col.Confidence = ConfidenceLow
+		return col
+	}
+
+	bestShare := -1.0
+	for _, c := range candidates {
+		matched := 0
+		for _, k := range c.kinds {
+			matched += o.counts[k]
+		}
+		if c.typ == "string" && c.format == "" && o.textual {
+			matched = nonNull
+		}
+		share := float64(matched) / float64(nonNull)
+		if share >= typeAgreement {
+			col.Type, col.Format = c.typ, c.format
+			col.Confidence = confidence(share, nonNull)
+			return col
+		}
+		if share > bestShare {
+			bestShare = share
+			col.Type, col.Format = c.typ, c.format
+		}
+	}
+	col.Confidence = ConfidenceLow
+	return col
+}
+
+func confidence(share float64, values int) Confidence {
+	switch {
+	case values < 3:
+		return ConfidenceLow
+	case share == 1 && values >= minConfidentRows:
+		return ConfidenceHigh
+	default:
+		return ConfidenceMedium
+	}
+}
+
+// overall is the lowest column confidence, capped by the number of rows read.
+func overall(columns []Column, rows int) Confidence {
+	c := ConfidenceHigh
+	if rows < minConfidentRows {
+		c = ConfidenceMedium
+	}
+	if rows < 3 {
+		c = ConfidenceLow
+	}
+	for _, col := range columns {
+		c = lower(c, col.Confidence)
+	}
+	return c
+}
+
+func lower(a, b Confidence) Confidence {
+	rank := map[Confidence]int{ConfidenceLow: 0, ConfidenceMedium: 1, ConfidenceHigh: 2}
+	if rank[b] < rank[a] {
+		return b
+	}
+	return a
+}
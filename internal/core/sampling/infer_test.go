@@ -0,0 +1,152 @@
+package sampling
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func csvRows(delimiter string, n int) string {
+	var b strings.Builder
+	b.WriteString(strings.Join([]string{"id", "amount", "active", "created_at", "note"}, delimiter) + "\n")
+	for i := 1; i <= n; i++ {
+		note := fmt.Sprintf("order %d", i)
+		if i%4 == 0 {
+			note = ""
+		}
+		fmt.Fprintf(&b, "%d%s%d.5%s%t%s2024-01-%02dT10:00:00Z%s%s\n",
+			i, delimiter, i, delimiter, i%2 == 0, delimiter, i%28+1, delimiter, note)
+	}
+	return b.String()
+}
+
+func TestInferDelimited(t *testing.T) {
+	for _, delimiter := range []string{",", ";", "\t", "|"} {
+		t.Run(fmt.Sprintf("%q", delimiter), func(t *testing.T) {
+			sample, err := Infer("orders.csv", []byte(csvRows(delimiter, 20)), 100, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, FormatCSV, sample.Format)
+			assert.Equal(t, delimiter, sample.Delimiter)
+			assert.True(t, sample.Header)
+			assert.Equal(t, 20, sample.Rows)
+			require.Len(t, sample.Columns, 5)
+
+			assert.Equal(t, Column{Name: "id", Type: "integer", Confidence: ConfidenceHigh}, sample.Columns[0])
+			assert.Equal(t, "number", sample.Columns[1].Type)
+			assert.Equal(t, "boolean", sample.Columns[2].Type)
+			assert.Equal(t, "string", sample.Columns[3].Type)
+			assert.Equal(t, "date-time", sample.Columns[3].Format)
+			assert.Equal(t, "string", sample.Columns[4].Type)
+			assert.True(t, sample.Columns[4].Nullable)
+			assert.Equal(t, ConfidenceHigh, sample.Confidence)
+		})
+	}
+}
+
+func TestInferDelimitedWithoutHeader(t *testing.T) {
+	sample, err := Infer("data.csv", []byte("1,alice,2024-01-01\n2,bob,2024-01-02\n3,carol,2024-01-03\n"), 100, false)
+	require.NoError(t, err)
+
+	assert.False(t, sample.Header)
+	assert.Equal(t, 3, sample.Rows)
+	assert.Equal(t, []string{"column_1", "column_2", "column_3"}, columnNames(sample))
+	assert.Equal(t, "date", sample.Columns[2].Format)
+	assert.Equal(t, ConfidenceMedium, sample.Confidence)
+}
+
+func TestInferMixedColumnFallsBackToString(t *testing.T) {
+	data := "code\n1\n2\nA7\nB9\n5\n"
+	sample, err := Infer("codes.csv", []byte(data), 100, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "string", sample.Columns[0].Type)
+}
+
+func TestInferIgnoresTruncatedRow(t *testing.T) {
+	data := csvRows(",", 12)
+	cut := data[:len(data)-10]
+
+	sample, err := Infer("orders.csv", []byte(cut), 100, true)
+	require.NoError(t, err)
+	assert.Equal(t, 11, sample.Rows)
+	assert.Equal(t, "string", sample.Columns[3].Type)
+	assert.Equal(t, "date-time", sample.Columns[3].Format)
+}
+
+func TestInferLimitsRows(t *testing.T) {
+	sample, err := Infer("orders.csv", []byte(csvRows(",", 50)), 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 10, sample.Rows)
+}
+
+func TestInferJSONLines(t *testing.T) {
+	data := `{"id": 1, "user": {"name": "a"}, "score": 1.5, "tags": ["x"]}
+{"id": 2, "user": {"name": "b"}, "score": 2, "tags": []}
+{"id": 3, "user": null, "score": 3.25, "tags": ["y"], "extra": true}
+`
+	sample, err := Infer("events.jsonl", []byte(data), 100, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatNDJSON, sample.Format)
+	assert.Equal(t, 3, sample.Rows)
+	assert.Equal(t, []string{"id", "user", "score", "tags", "extra"}, columnNames(sample))
+
+	byName := map[string]Column{}
+	for _, c := range sample.Columns {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, "integer", byName["id"].Type)
+	assert.Equal(t, "object", byName["user"].Type)
+	assert.True(t, byName["user"].Nullable)
+	assert.Equal(t, "number", byName["score"].Type)
+	assert.Equal(t, "array", byName["tags"].Type)
+	assert.Equal(t, "boolean", byName["extra"].Type)
+	assert.True(t, byName["extra"].Nullable)
+	assert.Equal(t, ConfidenceLow, sample.Confidence)
+}
+
+func TestInferJSONArray(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[\n")
+	for i := 0; i < 15; i++ {
+		fmt.Fprintf(&b, "  {\"id\": %d, \"at\": \"2024-02-%02d\"},\n", i, i+1)
+	}
+	// A truncated trailing element is ignored.
+	b.WriteString(`  {"id": 99, "at": "2024`)
+
+	sample, err := Infer("export.json", []byte(b.String()), 100, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatJSON, sample.Format)
+	assert.Equal(t, 15, sample.Rows)
+	assert.Equal(t, []string{"id", "at"}, columnNames(sample))
+	assert.Equal(t, "date", sample.Columns[1].Format)
+	assert.Equal(t, ConfidenceHigh, sample.Confidence)
+}
+
+func TestInferSingleJSONDocument(t *testing.T) {
+	data := "{\n  \"name\": \"x\",\n  \"count\": 3\n}\n"
+	sample, err := Infer("config.json", []byte(data), 100, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatJSON, sample.Format)
+	assert.Equal(t, 1, sample.Rows)
+	assert.Equal(t, ConfidenceLow, sample.Confidence)
+}
+
+func TestInferUnsupportedFormat(t *testing.T) {
+	_, err := Infer("data.parquet", []byte("PAR1"), 100, false)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func columnNames(s *Sample) []string {
+	names := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,289 @@
+package sampling
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, used to sign GET requests.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+const gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+// Bucket identifies a bucket to read. Region is used for S3 and may be empty.
+type Bucket struct {
+	Name   string
+	Region string
+}
+
+// Object is an object found in a bucket.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// ObjectStore reads objects from a bucket.
+type ObjectStore interface {
+	// List returns up to limit objects from the bucket.
+	List(ctx context.Context, bucket Bucket, limit int) ([]Object, error)
+	// Open reads an object. When limit is positive only the first limit
+	// bytes are read.
+	Open(ctx context.Context, bucket Bucket, key string, limit int64) (io.ReadCloser, error)
+}
+
+// S3Store reads S3 buckets with signed requests so the server doesn't need
+// the full S3 client. Credentials come from the default AWS chain
+// (environment, shared config or instance role).
+type S3Store struct {
+	region   string
+	endpoint string
+	creds    aws.CredentialsProvider
+	signer   *v4.Signer
+	client   *http.Client
+}
+
+// NewS3Store creates an S3 object store. endpoint overrides the AWS endpoint
+// for S3-compatible stores such as MinIO.
+func NewS3Store(ctx context.Context, endpoint string) (*S3Store, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3Store{
+		region:   region,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		creds:    cfg.Credentials,
+		signer:   v4.NewSigner(),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Store) List(ctx context.Context, bucket Bucket, limit int) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		q := url.Values{}
+		q.Set("list-type", "2")
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, bucket, s.objectURL(bucket, "")+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding S3 listing: %w", err)
+		}
+
+		for _, obj := range result.Contents {
+			if strings.HasSuffix(obj.Key, "/") {
+				continue
+			}
+			objects = append(objects, Object{Key: obj.Key, Size: obj.Size, ModTime: obj.LastModified})
+			if len(objects) >= limit {
+				return objects, nil
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *S3Store) Open(ctx context.Context, bucket Bucket, key string, limit int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	if limit > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=0-%d", limit-1))
+	}
+	resp, err := s.do(ctx, bucket, s.objectURL(bucket, key), header)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) regionOf(bucket Bucket) string {
+	if bucket.Region != "" {
+		return bucket.Region
+	}
+	return s.region
+}
+
+func (s *S3Store) objectURL(bucket Bucket, key string) string {
+	escaped := (&url.URL{Path: key}).EscapedPath()
+	if s.endpoint != "" {
+		// Custom endpoints (MinIO, LocalStack) generally need path-style URLs.
+		return s.endpoint + "/" + bucket.Name + "/" + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket.Name, s.regionOf(bucket), escaped)
+}
+
+func (s *S3Store) do(ctx context.Context, bucket Bucket, rawURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, emptyPayloadHash, "s3", s.regionOf(bucket), time.Now()); err != nil {
+		return nil, fmt.Errorf("signing S3 request: %w", err)
+	}
+
+	return checkResponse(s.client.Do(req))
+}
+
+// GCSStore reads Cloud Storage buckets through the JSON API. Application
+// default credentials are used when credentialsFile is empty.
+type GCSStore struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewGCSStore(ctx context.Context, credentialsFile string) (*GCSStore, error) {
+	var creds *google.Credentials
+	var err error
+	if credentialsFile != "" {
+		data, readErr := os.ReadFile(credentialsFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("reading GCS credentials: %w", readErr)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, gcsReadScope)
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, gcsReadScope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading GCS credentials: %w", err)
+	}
+
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = 30 * time.Second
+	return &GCSStore{baseURL: "https://storage.googleapis.com/storage/v1", client: client}, nil
+}
+
+type gcsListResult struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (s *GCSStore) List(ctx context.Context, bucket Bucket, limit int) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		q := url.Values{}
+		q.Set("fields", "items(name,size,updated),nextPageToken")
+		if token != "" {
+			q.Set("pageToken", token)
+		}
+
+		resp, err := s.get(ctx, s.baseURL+"/b/"+url.PathEscape(bucket.Name)+"/o?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var result gcsListResult
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding GCS listing: %w", err)
+		}
+
+		for _, item := range result.Items {
+			if strings.HasSuffix(item.Name, "/") {
+				continue
+			}
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			objects = append(objects, Object{Key: item.Name, Size: size, ModTime: item.Updated})
+			if len(objects) >= limit {
+				return objects, nil
+			}
+		}
+
+		if result.NextPageToken == "" {
+			return objects, nil
+		}
+		token = result.NextPageToken
+	}
+}
+
+func (s *GCSStore) Open(ctx context.Context, bucket Bucket, key string, limit int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	if limit > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=0-%d", limit-1))
+	}
+	rawURL := s.baseURL + "/b/" + url.PathEscape(bucket.Name) + "/o/" + url.PathEscape(key) + "?alt=media"
+	resp, err := s.get(ctx, rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *GCSStore) get(ctx context.Context, rawURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return checkResponse(s.client.Do(req))
+}
+
+func checkResponse(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
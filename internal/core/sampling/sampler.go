@@ -0,0 +1,57 @@
+package sampling
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultInterval = time.Hour
+
+// Sampler periodically samples buckets that are due.
+type Sampler struct {
+	task *background.SingletonTask
+}
+
+// SamplerConfig configures the sampler.
+type SamplerConfig struct {
+	// Interval between sampling runs. Default: 1 hour.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewSampler creates a new sampler for svc.
+func NewSampler(svc *Service, config *SamplerConfig) *Sampler {
+	if config == nil {
+		config = &SamplerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+
+	return &Sampler{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "object-schema-sampling",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.RunOnce(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins periodic sampling.
+func (s *Sampler) Start(ctx context.Context) {
+	s.task.Start(ctx)
+}
+
+// Stop stops sampling.
+func (s *Sampler) Stop() {
+	s.task.Stop()
+}
@@ -0,0 +1,289 @@
+package sampling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SchemaKeyPrefix prefixes the asset schema keys written by the sampler.
+// Each key names one dataset, e.g. "sampled:events/date=*/*.csv".
+const SchemaKeyPrefix = "sampled:"
+
+const (
+	DefaultMaxRows       = 100
+	DefaultMaxBytes      = 1 << 20
+	DefaultMaxObjects    = 1000
+	DefaultMaxDatasets   = 20
+	DefaultResampleAfter = 24 * time.Hour
+
+	batchSize = 50
+)
+
+// Candidate is a bucket asset due for sampling.
+type Candidate struct {
+	AssetID   string
+	Bucket    string
+	Providers []string
+	Region    string
+}
+
+// Result is the outcome of sampling one bucket. Schemas is nil when the
+// bucket could not be read, which keeps the schemas from the last run.
+type Result struct {
+	AssetID   string
+	Schemas   map[string]string
+	Error     string
+	SampledAt time.Time
+}
+
+// Config limits how much of each bucket is read.
+type Config struct {
+	// MaxRows is the number of records read from each file.
+	MaxRows int
+	// MaxBytes caps the bytes read from each file.
+	MaxBytes int64
+	// MaxObjects caps the objects listed per bucket.
+	MaxObjects int
+	// MaxDatasets caps the datasets sampled per bucket.
+	MaxDatasets int
+	// ResampleAfter is how long a bucket's samples are kept before the
+	// bucket is sampled again.
+	ResampleAfter time.Duration
+}
+
+type Service struct {
+	repo   Repository
+	stores map[string]ObjectStore
+	config Config
+	now    func() time.Time
+}
+
+// NewService creates a sampling service. stores maps an asset provider, such
+// as S3 or GCS, to the object store that reads its buckets.
+func NewService(repo Repository, stores map[string]ObjectStore, config Config) *Service {
+	if config.MaxRows <= 0 {
+		config.MaxRows = DefaultMaxRows
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultMaxBytes
+	}
+	if config.MaxObjects <= 0 {
+		config.MaxObjects = DefaultMaxObjects
+	}
+	if config.MaxDatasets <= 0 {
+		config.MaxDatasets = DefaultMaxDatasets
+	}
+	if config.ResampleAfter <= 0 {
+		config.ResampleAfter = DefaultResampleAfter
+	}
+	return &Service{repo: repo, stores: stores, config: config, now: time.Now}
+}
+
+// RunOnce samples the buckets that are due and returns how many were
+// sampled. Buckets that cannot be read are recorded, not returned as errors.
+func (s *Service) RunOnce(ctx context.Context) (int, error) {
+	if len(s.stores) == 0 {
+		return 0, nil
+	}
+	providers := make([]string, 0, len(s.stores))
+	for p := range s.stores {
+		providers = append(providers, p)
+	}
+
+	candidates, err := s.repo.ListDue(ctx, providers, s.now().Add(-s.config.ResampleAfter), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sampled := 0
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			return sampled, ctx.Err()
+		}
+		result := s.Sample(ctx, c)
+		if result.Error != "" {
+			log.Warn().Str("bucket", c.Bucket).Str("error", result.Error).Msg("Failed to sample bucket")
+		}
+		if err := s.repo.Save(ctx, result); err != nil {
+			log.Warn().Err(err).Str("bucket", c.Bucket).Msg("Failed to save sampled schemas")
+			continue
+		}
+		sampled++
+	}
+
+	if sampled > 0 {
+		log.Info().Int("buckets", sampled).Msg("Sampled object storage schemas")
+	}
+	return sampled, nil
+}
+
+// Sample infers a schema for each CSV or JSON dataset in a bucket. Files in
+// the same directory with the same extension are one dataset, and Hive-style
+// partition directories such as date=2024-01-01 are folded together; the
+// most recently modified file of each dataset is read.
+func (s *Service) Sample(ctx context.Context, c *Candidate) *Result {
+	result := &Result{AssetID: c.AssetID, SampledAt: s.now()}
+
+	store, provider := s.storeFor(c)
+	if store == nil {
+		result.Error = "no object store configured for bucket provider"
+		return result
+	}
+
+	bucket := Bucket{Name: c.Bucket, Region: c.Region}
+	objects, err := store.List(ctx, bucket, s.config.MaxObjects)
+	if err != nil {
+		result.Error = fmt.Sprintf("listing objects: %v", err)
+		return result
+	}
+
+	result.Schemas = map[string]string{}
+	for _, ds := range datasets(objects, s.config.MaxDatasets) {
+		sample, err := s.sampleObject(ctx, store, bucket, ds.latest)
+		if err != nil {
+			log.Debug().Err(err).Str("bucket", c.Bucket).Str("key", ds.latest.Key).Msg("Skipping object")
+			continue
+		}
+		doc, err := schemaDocument(objectURI(provider, c.Bucket, ds.latest.Key), sample, result.SampledAt)
+		if err != nil {
+			log.Debug().Err(err).Str("key", ds.latest.Key).Msg("Skipping object")
+			continue
+		}
+		result.Schemas[SchemaKeyPrefix+ds.pattern] = doc
+	}
+	return result
+}
+
+func (s *Service) storeFor(c *Candidate) (ObjectStore, string) {
+	for _, p := range c.Providers {
+		if store, ok := s.stores[p]; ok {
+			return store, p
+		}
+	}
+	return nil, ""
+}
+
+func (s *Service) sampleObject(ctx context.Context, store ObjectStore, bucket Bucket, obj Object) (*Sample, error) {
+	body, err := store.Open(ctx, bucket, obj.Key, s.config.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, s.config.MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading object: %w", err)
+	}
+	truncated := obj.Size > int64(len(data))
+	return Infer(obj.Key, data, s.config.MaxRows, truncated)
+}
+
+type dataset struct {
+	pattern string
+	latest  Object
+}
+
+// datasets groups sampleable objects into datasets and returns at most limit
+// of them, sorted by pattern.
+func datasets(objects []Object, limit int) []dataset {
+	byPattern := map[string]Object{}
+	for _, obj := range objects {
+		if obj.Size == 0 || FormatOf(obj.Key) == "" {
+			continue
+		}
+		pattern := datasetPattern(obj.Key)
+		if current, ok := byPattern[pattern]; !ok || obj.ModTime.After(current.ModTime) {
+			byPattern[pattern] = obj
+		}
+	}
+
+	result := make([]dataset, 0, len(byPattern))
+	for pattern, obj := range byPattern {
+		result = append(result, dataset{pattern: pattern, latest: obj})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].pattern < result[j].pattern })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// datasetPattern returns the glob naming the dataset key belongs to, e.g.
+// events/date=*/*.csv for events/date=2024-01-01/part-0001.csv.
+func datasetPattern(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	dir := path.Dir(key)
+	if dir == "." {
+		return "*" + ext
+	}
+	parts := strings.Split(dir, "/")
+	for i, part := range parts {
+		if name, _, ok := strings.Cut(part, "="); ok && name != "" {
+			parts[i] = name + "=*"
+		}
+	}
+	return strings.Join(parts, "/") + "/*" + ext
+}
+
+func objectURI(provider, bucket, key string) string {
+	scheme := "s3"
+	if strings.EqualFold(provider, "GCS") {
+		scheme = "gs"
+	}
+	return scheme + "://" + bucket + "/" + key
+}
+
+// schemaDocument renders a sample as a JSON Schema, the form asset schemas
+// are displayed and searched in. Each property carries the column's
+// confidence, and x-sampling records how the schema was inferred.
+func schemaDocument(uri string, sample *Sample, sampledAt time.Time) (string, error) {
+	properties := make(map[string]interface{}, len(sample.Columns))
+	order := make([]string, 0, len(sample.Columns))
+	for _, col := range sample.Columns {
+		prop := map[string]interface{}{
+			"type":         col.Type,
+			"x-confidence": col.Confidence,
+		}
+		if col.Format != "" {
+			prop["format"] = col.Format
+		}
+		if col.Nullable {
+			prop["nullable"] = true
+		}
+		properties[col.Name] = prop
+		order = append(order, col.Name)
+	}
+
+	sampling := map[string]interface{}{
+		"object":     uri,
+		"format":     sample.Format,
+		"header":     sample.Header,
+		"rows":       sample.Rows,
+		"columns":    order,
+		"confidence": sample.Confidence,
+		"sampled_at": sampledAt.UTC().Format(time.RFC3339),
+	}
+	if sample.Delimiter != "" {
+		sampling["delimiter"] = sample.Delimiter
+	}
+
+	doc := map[string]interface{}{
+		"type":        "object",
+		"description": fmt.Sprintf("Inferred from the first %d rows of %s (%s confidence)", sample.Rows, uri, sample.Confidence),
+		"properties":  properties,
+		"x-sampling":  sampling,
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("marshaling schema: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,122 @@
+package sampling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	due   []*Candidate
+	saved []*Result
+}
+
+func (f *fakeRepo) ListDue(ctx context.Context, providers []string, sampledBefore time.Time, limit int) ([]*Candidate, error) {
+	return f.due, nil
+}
+
+func (f *fakeRepo) Save(ctx context.Context, result *Result) error {
+	f.saved = append(f.saved, result)
+	return nil
+}
+
+type fakeStore struct {
+	objects map[string]string
+	modTime map[string]time.Time
+	listErr error
+	opened  []string
+}
+
+func (f *fakeStore) List(ctx context.Context, bucket Bucket, limit int) ([]Object, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	var objects []Object
+	for key, body := range f.objects {
+		objects = append(objects, Object{Key: key, Size: int64(len(body)), ModTime: f.modTime[key]})
+	}
+	return objects, nil
+}
+
+func (f *fakeStore) Open(ctx context.Context, bucket Bucket, key string, limit int64) (io.ReadCloser, error) {
+	f.opened = append(f.opened, key)
+	return io.NopCloser(strings.NewReader(f.objects[key])), nil
+}
+
+func TestDatasetPattern(t *testing.T) {
+	assert.Equal(t, "*.csv", datasetPattern("top.csv"))
+	assert.Equal(t, "raw/events/*.jsonl", datasetPattern("raw/events/part-1.JSONL"))
+	assert.Equal(t, "events/date=*/hour=*/*.csv", datasetPattern("events/date=2024-01-01/hour=03/part.csv"))
+}
+
+func TestSampleGroupsDatasetsAndReadsLatestFile(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		objects: map[string]string{
+			"orders/date=2024-05-30/part.csv": "id,total\n1,2.5\n",
+			"orders/date=2024-05-31/part.csv": "id,total,currency\n1,2.5,EUR\n",
+			"logs/app.jsonl":                  `{"level":"info","ts":"2024-05-31T10:00:00Z"}` + "\n",
+			"images/cat.png":                  "\x89PNG",
+			"empty/none.csv":                  "",
+		},
+		modTime: map[string]time.Time{
+			"orders/date=2024-05-30/part.csv": now.Add(-48 * time.Hour),
+			"orders/date=2024-05-31/part.csv": now.Add(-24 * time.Hour),
+		},
+	}
+	svc := NewService(&fakeRepo{}, map[string]ObjectStore{"S3": store}, Config{})
+	svc.now = func() time.Time { return now }
+
+	result := svc.Sample(context.Background(), &Candidate{AssetID: "a1", Bucket: "lake", Providers: []string{"S3"}})
+	require.Empty(t, result.Error)
+	require.Len(t, result.Schemas, 2)
+	assert.ElementsMatch(t, []string{"logs/app.jsonl", "orders/date=2024-05-31/part.csv"}, store.opened)
+
+	doc := result.Schemas["sampled:orders/date=*/*.csv"]
+	require.NotEmpty(t, doc)
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &parsed))
+	sampling := parsed["x-sampling"].(map[string]interface{})
+	assert.Equal(t, "s3://lake/orders/date=2024-05-31/part.csv", sampling["object"])
+	assert.Equal(t, ",", sampling["delimiter"])
+	assert.Equal(t, []interface{}{"id", "total", "currency"}, sampling["columns"])
+
+	// Sampled schemas feed the same column model as ingested schemas.
+	columns := lineage.SchemaColumns(result.Schemas)
+	assert.Contains(t, columns, "currency")
+	assert.Contains(t, columns, "level")
+}
+
+func TestSampleKeepsPreviousSchemasWhenListingFails(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("access denied")}
+	svc := NewService(&fakeRepo{}, map[string]ObjectStore{"GCS": store}, Config{})
+
+	result := svc.Sample(context.Background(), &Candidate{AssetID: "a1", Bucket: "b", Providers: []string{"GCS"}})
+	assert.Contains(t, result.Error, "access denied")
+	assert.Nil(t, result.Schemas)
+}
+
+func TestRunOnceSavesEveryCandidate(t *testing.T) {
+	repo := &fakeRepo{due: []*Candidate{
+		{AssetID: "a1", Bucket: "one", Providers: []string{"S3"}},
+		{AssetID: "a2", Bucket: "two", Providers: []string{"Azure"}},
+	}}
+	store := &fakeStore{objects: map[string]string{"x.csv": "a,b\n1,2\n"}}
+	svc := NewService(repo, map[string]ObjectStore{"S3": store}, Config{})
+
+	n, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.Len(t, repo.saved, 2)
+	assert.Len(t, repo.saved[0].Schemas, 1)
+	assert.NotEmpty(t, repo.saved[1].Error)
+}
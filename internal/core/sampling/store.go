@@ -0,0 +1,95 @@
+package sampling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the sampling data access interface.
+type Repository interface {
+	// ListDue returns buckets from the given providers that have never been
+	// sampled or were last sampled before sampledBefore, oldest first.
+	ListDue(ctx context.Context, providers []string, sampledBefore time.Time, limit int) ([]*Candidate, error)
+	// Save replaces the asset's sampled schemas and records the sampling.
+	Save(ctx context.Context, result *Result) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListDue(ctx context.Context, providers []string, sampledBefore time.Time, limit int) ([]*Candidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.name, a.providers, COALESCE(a.metadata->>'region', '')
+		FROM assets a
+		LEFT JOIN asset_schema_samples s ON s.asset_id = a.id
+		WHERE a.type = 'Bucket'
+		  AND a.is_stub = FALSE
+		  AND a.providers && $1
+		  AND (s.asset_id IS NULL OR s.sampled_at < $2)
+		ORDER BY s.sampled_at ASC NULLS FIRST
+		LIMIT $3`, providers, sampledBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying buckets to sample: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*Candidate{}
+	for rows.Next() {
+		c := &Candidate{}
+		if err := rows.Scan(&c.AssetID, &c.Bucket, &c.Providers, &c.Region); err != nil {
+			return nil, fmt.Errorf("scanning bucket: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, result *Result) error {
+	schemaJSON, err := json.Marshal(result.Schemas)
+	if err != nil {
+		return fmt.Errorf("marshaling sampled schemas: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Sampled schemas from the previous run are replaced; schemas from
+	// plugins or users are kept. A failed run leaves the old samples.
+	if result.Schemas != nil {
+		_, err = tx.Exec(ctx, `
+			UPDATE assets
+			SET schema = COALESCE(
+				(SELECT jsonb_object_agg(key, value) FROM jsonb_each(schema) WHERE key NOT LIKE $3),
+				'{}'::jsonb
+			) || $2::jsonb
+			WHERE id = $1`,
+			result.AssetID, schemaJSON, SchemaKeyPrefix+"%")
+		if err != nil {
+			return fmt.Errorf("updating asset schema: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO asset_schema_samples (asset_id, sampled_at, datasets, last_error)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT (asset_id) DO UPDATE
+		SET sampled_at = EXCLUDED.sampled_at, datasets = EXCLUDED.datasets, last_error = EXCLUDED.last_error`,
+		result.AssetID, result.SampledAt, len(result.Schemas), result.Error)
+	if err != nil {
+		return fmt.Errorf("recording sampling: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
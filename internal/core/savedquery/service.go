@@ -0,0 +1,313 @@
+package savedquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("saved query not found")
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrOwnerNotFound = errors.New("owner not found")
+	ErrForbidden     = errors.New("not allowed to modify this query")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	OwnerTypeUser = "user"
+	OwnerTypeTeam = "team"
+
+	// DefaultLanguage is assumed when a query doesn't name its language.
+	DefaultLanguage = "sql"
+
+	maxNameLength        = 255
+	maxDescriptionLength = 10000
+	maxQueryLength       = 100000
+	maxLanguageLength    = 50
+	maxTags              = 50
+	maxAssets            = 100
+)
+
+// Owner is the user or team responsible for a saved query.
+type Owner struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+} // @name SavedQueryOwner
+
+// Asset is an asset a saved query is linked to.
+type Asset struct {
+	ID        string   `json:"id"`
+	MRN       string   `json:"mrn"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+} // @name SavedQueryAsset
+
+// Query is a saved query in the query library.
+type Query struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Query       string   `json:"query"`
+	Language    string   `json:"language"`
+	Tags        []string `json:"tags"`
+	Owner       *Owner   `json:"owner,omitempty"`
+	Assets      []Asset  `json:"assets"`
+
+	CreatedBy     *string   `json:"created_by,omitempty"`
+	CreatedByName string    `json:"created_by_name,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+} // @name SavedQuery
+
+// Input creates a saved query or replaces every field of an existing one.
+type Input struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Query       string   `json:"query"`
+	Language    string   `json:"language"`
+	Tags        []string `json:"tags"`
+	// Owner is optional. Only its type and id are read.
+	Owner    *Owner   `json:"owner,omitempty"`
+	AssetIDs []string `json:"asset_ids"`
+} // @name SavedQueryInput
+
+type ListFilter struct {
+	// AssetID restricts the list to queries linked to one asset.
+	AssetID  string
+	Language string
+	Limit    int
+	Offset   int
+}
+
+type ListResult struct {
+	Queries []*Query `json:"queries"`
+	Total   int      `json:"total"`
+} // @name SavedQueryListResult
+
+// Actor is the user performing an operation. Moderators, typically users
+// with asset management permission, may edit or remove any query.
+type Actor struct {
+	UserID      string
+	CanModerate bool
+}
+
+// SearchObserver is notified when saved queries change.
+type SearchObserver interface {
+	OnEntityChanged(ctx context.Context, entityType, entityID string)
+	OnEntityDeleted(ctx context.Context, entityType, entityID string)
+}
+
+// searchEntityType is the search_index type of saved queries.
+const searchEntityType = "query"
+
+type Service struct {
+	repo           Repository
+	searchObserver SearchObserver
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// SetSearchObserver registers an observer for search index sync.
+func (s *Service) SetSearchObserver(observer SearchObserver) {
+	s.searchObserver = observer
+}
+
+func (s *Service) Create(ctx context.Context, input Input, actor Actor) (*Query, error) {
+	q, assetIDs, err := normalize(input)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != "" {
+		q.CreatedBy = &actor.UserID
+	}
+
+	if err := s.repo.Create(ctx, q, assetIDs); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, q.ID)
+
+	return s.repo.Get(ctx, q.ID)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Query, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns saved queries, most recently updated first.
+func (s *Service) List(ctx context.Context, filter ListFilter) (*ListResult, error) {
+	filter.Language = strings.ToLower(strings.TrimSpace(filter.Language))
+	queries, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Queries: queries, Total: total}, nil
+}
+
+// Update replaces a saved query, including its owner and linked assets.
+func (s *Service) Update(ctx context.Context, id string, input Input, actor Actor) (*Query, error) {
+	if err := s.authorize(ctx, id, actor); err != nil {
+		return nil, err
+	}
+
+	q, assetIDs, err := normalize(input)
+	if err != nil {
+		return nil, err
+	}
+	q.ID = id
+
+	if err := s.repo.Update(ctx, q, assetIDs); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, id)
+
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) Delete(ctx context.Context, id string, actor Actor) error {
+	if err := s.authorize(ctx, id, actor); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if s.searchObserver != nil {
+		s.searchObserver.OnEntityDeleted(ctx, searchEntityType, id)
+	}
+	return nil
+}
+
+// LinkAsset links a saved query to an asset. Linking twice is a no-op.
+func (s *Service) LinkAsset(ctx context.Context, id, assetID string, actor Actor) (*Query, error) {
+	if err := s.authorize(ctx, id, actor); err != nil {
+		return nil, err
+	}
+	if err := s.repo.LinkAsset(ctx, id, assetID); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) UnlinkAsset(ctx context.Context, id, assetID string, actor Actor) (*Query, error) {
+	if err := s.authorize(ctx, id, actor); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UnlinkAsset(ctx, id, assetID); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// authorize allows the query's author, its owner or a member of its owning
+// team, and moderators.
+func (s *Service) authorize(ctx context.Context, id string, actor Actor) error {
+	if actor.CanModerate {
+		_, err := s.repo.Get(ctx, id)
+		return err
+	}
+
+	ok, err := s.repo.CanEdit(ctx, id, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *Service) changed(ctx context.Context, id string) {
+	if s.searchObserver != nil {
+		s.searchObserver.OnEntityChanged(ctx, searchEntityType, id)
+	}
+}
+
+func normalize(input Input) (*Query, []string, error) {
+	q := &Query{
+		Name:     strings.TrimSpace(input.Name),
+		Query:    strings.TrimSpace(input.Query),
+		Language: strings.ToLower(strings.TrimSpace(input.Language)),
+		Tags:     []string{},
+	}
+
+	switch {
+	case q.Name == "":
+		return nil, nil, &ValidationError{Message: "name is required"}
+	case len(q.Name) > maxNameLength:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("name must be %d characters or fewer", maxNameLength)}
+	case q.Query == "":
+		return nil, nil, &ValidationError{Message: "query is required"}
+	case len(q.Query) > maxQueryLength:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("query must be %d characters or fewer", maxQueryLength)}
+	case len(q.Language) > maxLanguageLength:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("language must be %d characters or fewer", maxLanguageLength)}
+	}
+	if q.Language == "" {
+		q.Language = DefaultLanguage
+	}
+
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if len(description) > maxDescriptionLength {
+			return nil, nil, &ValidationError{Message: fmt.Sprintf("description must be %d characters or fewer", maxDescriptionLength)}
+		}
+		if description != "" {
+			q.Description = &description
+		}
+	}
+
+	seenTags := make(map[string]bool, len(input.Tags))
+	for _, tag := range input.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seenTags[tag] {
+			continue
+		}
+		seenTags[tag] = true
+		q.Tags = append(q.Tags, tag)
+	}
+	if len(q.Tags) > maxTags {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("a query can have at most %d tags", maxTags)}
+	}
+
+	if input.Owner != nil && input.Owner.ID != "" {
+		if input.Owner.Type != OwnerTypeUser && input.Owner.Type != OwnerTypeTeam {
+			return nil, nil, &ValidationError{Message: "owner type must be user or team"}
+		}
+		q.Owner = &Owner{Type: input.Owner.Type, ID: input.Owner.ID}
+	}
+
+	assetIDs := make([]string, 0, len(input.AssetIDs))
+	seenAssets := make(map[string]bool, len(input.AssetIDs))
+	for _, id := range input.AssetIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seenAssets[id] {
+			continue
+		}
+		seenAssets[id] = true
+		assetIDs = append(assetIDs, id)
+	}
+	if len(assetIDs) > maxAssets {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("a query can be linked to at most %d assets", maxAssets)}
+	}
+
+	return q, assetIDs, nil
+}
@@ -0,0 +1,245 @@
+package savedquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	queries map[string]*Query
+	links   map[string][]string
+	members map[string][]string // team ID -> user IDs
+	nextID  int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		queries: make(map[string]*Query),
+		links:   make(map[string][]string),
+		members: make(map[string][]string),
+	}
+}
+
+func (f *fakeRepo) Create(_ context.Context, q *Query, assetIDs []string) error {
+	f.nextID++
+	q.ID = fmt.Sprintf("query-%d", f.nextID)
+	stored := *q
+	f.queries[q.ID] = &stored
+	f.links[q.ID] = assetIDs
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, id string) (*Query, error) {
+	q, ok := f.queries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *q
+	out.Assets = []Asset{}
+	for _, assetID := range f.links[id] {
+		out.Assets = append(out.Assets, Asset{ID: assetID})
+	}
+	return &out, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, filter ListFilter) ([]*Query, int, error) {
+	var out []*Query
+	for id := range f.queries {
+		q, _ := f.Get(context.Background(), id)
+		if filter.Language != "" && q.Language != filter.Language {
+			continue
+		}
+		out = append(out, q)
+	}
+	return out, len(out), nil
+}
+
+func (f *fakeRepo) Update(_ context.Context, q *Query, assetIDs []string) error {
+	existing, ok := f.queries[q.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	q.CreatedBy = existing.CreatedBy
+	stored := *q
+	f.queries[q.ID] = &stored
+	f.links[q.ID] = assetIDs
+	return nil
+}
+
+func (f *fakeRepo) Delete(_ context.Context, id string) error {
+	if _, ok := f.queries[id]; !ok {
+		return ErrNotFound
+	}
+	delete(f.queries, id)
+	delete(f.links, id)
+	return nil
+}
+
+func (f *fakeRepo) LinkAsset(_ context.Context, id, assetID string) error {
+	for _, existing := range f.links[id] {
+		if existing == assetID {
+			return nil
+		}
+	}
+	f.links[id] = append(f.links[id], assetID)
+	return nil
+}
+
+func (f *fakeRepo) UnlinkAsset(_ context.Context, id, assetID string) error {
+	var kept []string
+	for _, existing := range f.links[id] {
+		if existing != assetID {
+			kept = append(kept, existing)
+		}
+	}
+	f.links[id] = kept
+	return nil
+}
+
+func (f *fakeRepo) CanEdit(_ context.Context, id, userID string) (bool, error) {
+	q, ok := f.queries[id]
+	if !ok {
+		return false, ErrNotFound
+	}
+	if q.CreatedBy != nil && *q.CreatedBy == userID {
+		return true, nil
+	}
+	if q.Owner == nil {
+		return false, nil
+	}
+	if q.Owner.Type == OwnerTypeUser {
+		return q.Owner.ID == userID, nil
+	}
+	for _, member := range f.members[q.Owner.ID] {
+		if member == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeObserver struct {
+	changed []string
+	deleted []string
+}
+
+func (o *fakeObserver) OnEntityChanged(_ context.Context, entityType, entityID string) {
+	o.changed = append(o.changed, entityType+":"+entityID)
+}
+
+func (o *fakeObserver) OnEntityDeleted(_ context.Context, entityType, entityID string) {
+	o.deleted = append(o.deleted, entityType+":"+entityID)
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestCreateNormalizesInput(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	observer := &fakeObserver{}
+	svc.SetSearchObserver(observer)
+
+	q, err := svc.Create(context.Background(), Input{
+		Name:        "  Weekly revenue ",
+		Description: strPtr("   "),
+		Query:       "SELECT sum(total) FROM orders",
+		Tags:        []string{"finance", " finance ", ""},
+		AssetIDs:    []string{"orders", "orders", " "},
+	}, Actor{UserID: "user-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Weekly revenue", q.Name)
+	assert.Nil(t, q.Description)
+	assert.Equal(t, DefaultLanguage, q.Language)
+	assert.Equal(t, []string{"finance"}, q.Tags)
+	assert.Equal(t, []Asset{{ID: "orders"}}, q.Assets)
+	assert.Equal(t, "user-1", *q.CreatedBy)
+	assert.Equal(t, []string{"query:" + q.ID}, observer.changed)
+}
+
+func TestCreateValidation(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	cases := []Input{
+		{Query: "SELECT 1"},
+		{Name: "empty"},
+		{Name: strings.Repeat("n", maxNameLength+1), Query: "SELECT 1"},
+		{Name: "long", Query: strings.Repeat("x", maxQueryLength+1)},
+		{Name: "owner", Query: "SELECT 1", Owner: &Owner{Type: "group", ID: "g"}},
+	}
+	for _, input := range cases {
+		_, err := svc.Create(ctx, input, Actor{UserID: "user-1"})
+		assert.True(t, IsValidationError(err), "input %+v", input)
+	}
+}
+
+func TestEditPermissions(t *testing.T) {
+	repo := newFakeRepo()
+	repo.members["team-1"] = []string{"member"}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	q, err := svc.Create(ctx, Input{
+		Name:  "Churn",
+		Query: "SELECT * FROM churn",
+		Owner: &Owner{Type: OwnerTypeTeam, ID: "team-1"},
+	}, Actor{UserID: "author"})
+	require.NoError(t, err)
+
+	update := Input{Name: "Churn by month", Query: "SELECT * FROM churn", Owner: &Owner{Type: OwnerTypeTeam, ID: "team-1"}}
+
+	_, err = svc.Update(ctx, q.ID, update, Actor{UserID: "stranger"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	for _, actor := range []Actor{{UserID: "author"}, {UserID: "member"}, {UserID: "admin", CanModerate: true}} {
+		updated, err := svc.Update(ctx, q.ID, update, actor)
+		require.NoError(t, err, "actor %s", actor.UserID)
+		assert.Equal(t, "Churn by month", updated.Name)
+	}
+
+	_, err = svc.LinkAsset(ctx, q.ID, "orders", Actor{UserID: "stranger"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.Update(ctx, "missing", update, Actor{UserID: "admin", CanModerate: true})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLinkAndUnlinkAssets(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+	actor := Actor{UserID: "user-1"}
+
+	q, err := svc.Create(ctx, Input{Name: "Orders join", Query: "SELECT 1", AssetIDs: []string{"orders"}}, actor)
+	require.NoError(t, err)
+
+	q, err = svc.LinkAsset(ctx, q.ID, "customers", actor)
+	require.NoError(t, err)
+	q, err = svc.LinkAsset(ctx, q.ID, "customers", actor)
+	require.NoError(t, err)
+	assert.Equal(t, []Asset{{ID: "orders"}, {ID: "customers"}}, q.Assets)
+
+	q, err = svc.UnlinkAsset(ctx, q.ID, "orders", actor)
+	require.NoError(t, err)
+	assert.Equal(t, []Asset{{ID: "customers"}}, q.Assets)
+}
+
+func TestDeleteNotifiesSearch(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	observer := &fakeObserver{}
+	svc.SetSearchObserver(observer)
+	ctx := context.Background()
+
+	q, err := svc.Create(ctx, Input{Name: "Temp", Query: "SELECT 1"}, Actor{UserID: "user-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Delete(ctx, q.ID, Actor{UserID: "user-1"}))
+	assert.Equal(t, []string{"query:" + q.ID}, observer.deleted)
+
+	_, err = svc.Get(ctx, q.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
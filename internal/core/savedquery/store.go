@@ -0,0 +1,304 @@
+package savedquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the saved query data access interface.
+type Repository interface {
+	Create(ctx context.Context, q *Query, assetIDs []string) error
+	Get(ctx context.Context, id string) (*Query, error)
+	List(ctx context.Context, filter ListFilter) ([]*Query, int, error)
+	// Update replaces the query's fields and linked assets.
+	Update(ctx context.Context, q *Query, assetIDs []string) error
+	Delete(ctx context.Context, id string) error
+	LinkAsset(ctx context.Context, id, assetID string) error
+	UnlinkAsset(ctx context.Context, id, assetID string) error
+	// CanEdit reports whether userID wrote the query, owns it, or is a
+	// member of the team that owns it. It returns ErrNotFound for unknown
+	// queries.
+	CanEdit(ctx context.Context, id, userID string) (bool, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectQuery = `
+	SELECT q.id::text, q.name, q.description, q.query, q.query_language, q.tags,
+	       CASE WHEN q.owner_user_id IS NOT NULL THEN 'user' WHEN q.owner_team_id IS NOT NULL THEN 'team' END,
+	       COALESCE(q.owner_user_id, q.owner_team_id)::text,
+	       COALESCE(ou.name, ou.username, ot.name),
+	       q.created_by::text, COALESCE(cu.name, cu.username, ''),
+	       q.created_at, q.updated_at
+	FROM saved_queries q
+	LEFT JOIN users ou ON ou.id = q.owner_user_id
+	LEFT JOIN teams ot ON ot.id = q.owner_team_id
+	LEFT JOIN users cu ON cu.id = q.created_by`
+
+func (r *PostgresRepository) Create(ctx context.Context, q *Query, assetIDs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ownerUser, ownerTeam := ownerColumns(q.Owner)
+	err = tx.QueryRow(ctx, `
+		INSERT INTO saved_queries (name, description, query, query_language, tags, owner_user_id, owner_team_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id::text, created_at, updated_at`,
+		q.Name, q.Description, q.Query, q.Language, q.Tags, ownerUser, ownerTeam, q.CreatedBy,
+	).Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		return mapWriteError(err, "creating saved query")
+	}
+
+	if err := linkAssets(ctx, tx, q.ID, assetIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Query, error) {
+	q, err := scanQuery(r.db.QueryRow(ctx, selectQuery+` WHERE q.id::text = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting saved query: %w", err)
+	}
+
+	if err := r.loadAssets(ctx, []*Query{q}); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter ListFilter) ([]*Query, int, error) {
+	where := `
+		WHERE ($1 = '' OR EXISTS (SELECT 1 FROM saved_query_assets l WHERE l.query_id = q.id AND l.asset_id = $1))
+		  AND ($2 = '' OR q.query_language = $2)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM saved_queries q`+where,
+		filter.AssetID, filter.Language).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting saved queries: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectQuery+where+`
+		ORDER BY q.updated_at DESC, q.id
+		LIMIT $3 OFFSET $4`, filter.AssetID, filter.Language, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing saved queries: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []*Query{}
+	for rows.Next() {
+		q, err := scanQuery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning saved query: %w", err)
+		}
+		queries = append(queries, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating saved queries: %w", err)
+	}
+
+	if err := r.loadAssets(ctx, queries); err != nil {
+		return nil, 0, err
+	}
+	return queries, total, nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, q *Query, assetIDs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ownerUser, ownerTeam := ownerColumns(q.Owner)
+	tag, err := tx.Exec(ctx, `
+		UPDATE saved_queries SET
+			name = $2, description = $3, query = $4, query_language = $5, tags = $6,
+			owner_user_id = $7, owner_team_id = $8, updated_at = NOW()
+		WHERE id::text = $1`,
+		q.ID, q.Name, q.Description, q.Query, q.Language, q.Tags, ownerUser, ownerTeam)
+	if err != nil {
+		return mapWriteError(err, "updating saved query")
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM saved_query_assets WHERE query_id::text = $1`, q.ID); err != nil {
+		return fmt.Errorf("clearing saved query assets: %w", err)
+	}
+	if err := linkAssets(ctx, tx, q.ID, assetIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_queries WHERE id::text = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting saved query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) LinkAsset(ctx context.Context, id, assetID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO saved_query_assets (query_id, asset_id)
+		VALUES ($1::uuid, $2)
+		ON CONFLICT DO NOTHING`, id, assetID)
+	if err != nil {
+		return mapWriteError(err, "linking saved query")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UnlinkAsset(ctx context.Context, id, assetID string) error {
+	if _, err := r.db.Exec(ctx, `
+		DELETE FROM saved_query_assets WHERE query_id::text = $1 AND asset_id = $2`, id, assetID); err != nil {
+		return fmt.Errorf("unlinking saved query: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CanEdit(ctx context.Context, id, userID string) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(q.created_by::text = $2 OR q.owner_user_id::text = $2, FALSE)
+		    OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = q.owner_team_id AND tm.user_id::text = $2)
+		FROM saved_queries q
+		WHERE q.id::text = $1`, id, userID).Scan(&ok)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking saved query permissions: %w", err)
+	}
+	return ok, nil
+}
+
+// loadAssets fills in the linked assets of queries with one round trip.
+func (r *PostgresRepository) loadAssets(ctx context.Context, queries []*Query) error {
+	if len(queries) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Query, len(queries))
+	ids := make([]string, len(queries))
+	for i, q := range queries {
+		q.Assets = []Asset{}
+		byID[q.ID] = q
+		ids[i] = q.ID
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT l.query_id::text, a.id, a.mrn, a.name, a.type, a.providers
+		FROM saved_query_assets l
+		JOIN assets a ON a.id = l.asset_id
+		WHERE l.query_id::text = ANY($1)
+		ORDER BY a.name, a.id`, ids)
+	if err != nil {
+		return fmt.Errorf("loading saved query assets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var queryID string
+		var a Asset
+		if err := rows.Scan(&queryID, &a.ID, &a.MRN, &a.Name, &a.Type, &a.Providers); err != nil {
+			return fmt.Errorf("scanning saved query asset: %w", err)
+		}
+		if q := byID[queryID]; q != nil {
+			q.Assets = append(q.Assets, a)
+		}
+	}
+	return rows.Err()
+}
+
+func linkAssets(ctx context.Context, tx pgx.Tx, id string, assetIDs []string) error {
+	if len(assetIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO saved_query_assets (query_id, asset_id)
+		SELECT $1::uuid, unnest($2::varchar[])
+		ON CONFLICT DO NOTHING`, id, assetIDs)
+	if err != nil {
+		return mapWriteError(err, "linking saved query assets")
+	}
+	return nil
+}
+
+func ownerColumns(o *Owner) (user, team *string) {
+	if o == nil {
+		return nil, nil
+	}
+	if o.Type == OwnerTypeUser {
+		return &o.ID, nil
+	}
+	return nil, &o.ID
+}
+
+// mapWriteError turns foreign key and malformed ID failures into the
+// service's errors.
+func mapWriteError(err error, action string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			if strings.Contains(pgErr.ConstraintName, "owner") {
+				return ErrOwnerNotFound
+			}
+			if strings.Contains(pgErr.ConstraintName, "asset_id") {
+				return ErrAssetNotFound
+			}
+			return ErrNotFound
+		case "22P02":
+			return &ValidationError{Message: "invalid id"}
+		}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+func scanQuery(row pgx.Row) (*Query, error) {
+	var q Query
+	var ownerType, ownerID, ownerName *string
+	if err := row.Scan(
+		&q.ID, &q.Name, &q.Description, &q.Query, &q.Language, &q.Tags,
+		&ownerType, &ownerID, &ownerName,
+		&q.CreatedBy, &q.CreatedByName, &q.CreatedAt, &q.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if ownerType != nil && ownerID != nil {
+		q.Owner = &Owner{Type: *ownerType, ID: *ownerID}
+		if ownerName != nil {
+			q.Owner.Name = *ownerName
+		}
+	}
+	return &q, nil
+}
@@ -0,0 +1,57 @@
+package savedsearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultCheckInterval = 15 * time.Minute
+
+// Checker periodically checks subscribed saved searches for new matches.
+type Checker struct {
+	task *background.SingletonTask
+}
+
+// CheckerConfig configures the checker.
+type CheckerConfig struct {
+	// Interval between checks. Default: 15 minutes.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewChecker creates a new checker for svc.
+func NewChecker(svc *Service, config *CheckerConfig) *Checker {
+	if config == nil {
+		config = &CheckerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultCheckInterval
+	}
+
+	return &Checker{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "saved-search-check",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.CheckAll(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins periodic checks.
+func (c *Checker) Start(ctx context.Context) {
+	c.task.Start(ctx)
+}
+
+// Stop stops checks.
+func (c *Checker) Stop() {
+	c.task.Stop()
+}
@@ -0,0 +1,448 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/search"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound     = errors.New("saved search not found")
+	ErrTeamNotFound = errors.New("team not found")
+	ErrForbidden    = errors.New("not allowed to modify this search")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxNameLength        = 255
+	maxDescriptionLength = 10000
+	maxQueryLength       = 256
+	maxFilterValues      = 50
+	maxTeams             = 50
+
+	// maxTrackedMatches caps how many matching assets a check reads. Past
+	// it, matches are only added, never pruned.
+	maxTrackedMatches = 1000
+	checkPageSize     = 100
+)
+
+// Filters narrows a saved search, mirroring the search API's filters.
+type Filters struct {
+	Types      []search.ResultType `json:"types"`
+	AssetTypes []string            `json:"asset_types"`
+	Providers  []string            `json:"providers"`
+	Tags       []string            `json:"tags"`
+} // @name SavedSearchFilters
+
+// Team is a team a saved search is shared with.
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+} // @name SavedSearchTeam
+
+// SavedSearch is a named search query and filters.
+type SavedSearch struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Query       string  `json:"query"`
+	Filters     Filters `json:"filters"`
+	Teams       []Team  `json:"teams"`
+	// URL opens the search in the UI. Anyone with access to Marmot can
+	// follow it, whether or not the saved search is shared with them.
+	URL string `json:"url"`
+
+	// Subscribed is whether the requesting user is notified of new matches.
+	Subscribed      bool `json:"subscribed"`
+	SubscriberCount int  `json:"subscriber_count"`
+
+	CreatedBy     *string    `json:"created_by,omitempty"`
+	CreatedByName string     `json:"created_by_name,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+} // @name SavedSearch
+
+// Input creates a saved search or replaces every field of an existing one.
+type Input struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Query       string  `json:"query"`
+	Filters     Filters `json:"filters"`
+	// TeamIDs are the teams whose members can see and subscribe to the
+	// search.
+	TeamIDs []string `json:"team_ids"`
+} // @name SavedSearchInput
+
+type ListResult struct {
+	SavedSearches []*SavedSearch `json:"saved_searches"`
+	Total         int            `json:"total"`
+} // @name SavedSearchListResult
+
+// Actor is the user performing an operation. Moderators, typically users
+// with asset management permission, may see and edit any saved search.
+type Actor struct {
+	UserID      string
+	CanModerate bool
+}
+
+// Match is an asset that newly matches a saved search.
+type Match struct {
+	AssetID string
+	MRN     string
+	Name    string
+}
+
+// Searcher runs search queries.
+type Searcher interface {
+	Search(ctx context.Context, filter search.Filter) (*search.Response, error)
+}
+
+// Notifier tells subscribers about assets that started matching a saved
+// search.
+type Notifier interface {
+	NotifyNewMatches(ctx context.Context, s *SavedSearch, subscriberIDs []string, matches []Match)
+}
+
+type Service struct {
+	repo     Repository
+	searcher Searcher
+	notifier Notifier
+}
+
+func NewService(repo Repository, searcher Searcher) *Service {
+	return &Service{repo: repo, searcher: searcher}
+}
+
+// SetNotifier registers the notifier for subscriptions.
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+func (s *Service) Create(ctx context.Context, input Input, actor Actor) (*SavedSearch, error) {
+	ss, teamIDs, err := normalize(input)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != "" {
+		ss.CreatedBy = &actor.UserID
+	}
+
+	if err := s.repo.Create(ctx, ss, teamIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, ss.ID, actor.UserID)
+}
+
+// Get returns a saved search the actor created, or that is shared with one
+// of their teams.
+func (s *Service) Get(ctx context.Context, id string, actor Actor) (*SavedSearch, error) {
+	if err := s.authorize(ctx, id, actor, false); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id, actor.UserID)
+}
+
+// List returns the saved searches the actor created or that are shared with
+// their teams, most recently updated first.
+func (s *Service) List(ctx context.Context, actor Actor, limit, offset int) (*ListResult, error) {
+	searches, total, err := s.repo.List(ctx, actor.UserID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{SavedSearches: searches, Total: total}, nil
+}
+
+// Update replaces a saved search. Changing its query or filters restarts
+// tracking of its matches, so subscribers aren't told about every asset the
+// new filters happen to match.
+func (s *Service) Update(ctx context.Context, id string, input Input, actor Actor) (*SavedSearch, error) {
+	if err := s.authorize(ctx, id, actor, true); err != nil {
+		return nil, err
+	}
+
+	ss, teamIDs, err := normalize(input)
+	if err != nil {
+		return nil, err
+	}
+	ss.ID = id
+
+	if err := s.repo.Update(ctx, ss, teamIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id, actor.UserID)
+}
+
+func (s *Service) Delete(ctx context.Context, id string, actor Actor) error {
+	if err := s.authorize(ctx, id, actor, true); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Run executes the saved search.
+func (s *Service) Run(ctx context.Context, id string, actor Actor, limit, offset int) (*search.Response, error) {
+	ss, err := s.Get(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	filter := ss.filter()
+	filter.Limit = limit
+	filter.Offset = offset
+	return s.searcher.Search(ctx, filter)
+}
+
+// Subscribe notifies the actor when new assets match the saved search.
+// Subscribing twice is a no-op.
+func (s *Service) Subscribe(ctx context.Context, id string, actor Actor) error {
+	if err := s.authorize(ctx, id, actor, false); err != nil {
+		return err
+	}
+	return s.repo.Subscribe(ctx, id, actor.UserID)
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, id string, actor Actor) error {
+	if err := s.authorize(ctx, id, actor, false); err != nil {
+		return err
+	}
+	return s.repo.Unsubscribe(ctx, id, actor.UserID)
+}
+
+// CheckAll runs every saved search with subscribers and notifies them of
+// assets that started matching since the last check. It returns how many
+// searches had new matches.
+func (s *Service) CheckAll(ctx context.Context) (int, error) {
+	searches, err := s.repo.ListSubscribed(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for _, ss := range searches {
+		if ctx.Err() != nil {
+			return notified, ctx.Err()
+		}
+		matches, err := s.check(ctx, ss)
+		if err != nil {
+			log.Warn().Err(err).Str("saved_search_id", ss.ID).Msg("Failed to check saved search")
+			continue
+		}
+		if len(matches) > 0 {
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// check records the assets matching ss and notifies its subscribers of new
+// ones. The first check only records a baseline.
+func (s *Service) check(ctx context.Context, ss *SavedSearch) ([]Match, error) {
+	filter := ss.filter()
+	if len(filter.Types) > 0 && !slices.Contains(filter.Types, search.ResultTypeAsset) {
+		return nil, nil
+	}
+	filter.Types = []search.ResultType{search.ResultTypeAsset}
+	filter.Limit = checkPageSize
+
+	byID := make(map[string]Match)
+	ids := []string{}
+	complete := false
+	for filter.Offset < maxTrackedMatches {
+		resp, err := s.searcher.Search(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("running saved search: %w", err)
+		}
+		for _, r := range resp.Results {
+			if r.Type != search.ResultTypeAsset {
+				continue
+			}
+			if _, seen := byID[r.ID]; seen {
+				continue
+			}
+			m := Match{AssetID: r.ID, Name: r.Name}
+			if mrn, ok := r.Metadata["mrn"].(string); ok {
+				m.MRN = mrn
+			}
+			byID[r.ID] = m
+			ids = append(ids, r.ID)
+		}
+		filter.Offset += filter.Limit
+		if len(resp.Results) < filter.Limit || filter.Offset >= resp.Total {
+			complete = true
+			break
+		}
+	}
+
+	added, err := s.repo.SyncMatches(ctx, ss.ID, ids, complete)
+	if err != nil {
+		return nil, err
+	}
+	if ss.LastCheckedAt == nil || len(added) == 0 || s.notifier == nil {
+		return nil, nil
+	}
+
+	subscribers, err := s.repo.Subscribers(ctx, ss.ID)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]Match, 0, len(added))
+	for _, id := range added {
+		matches = append(matches, byID[id])
+	}
+	s.notifier.NotifyNewMatches(ctx, ss, subscribers, matches)
+	return matches, nil
+}
+
+// authorize allows the search's author and moderators, and for reads the
+// members of the teams it is shared with.
+func (s *Service) authorize(ctx context.Context, id string, actor Actor, edit bool) error {
+	if actor.CanModerate {
+		_, err := s.repo.Get(ctx, id, actor.UserID)
+		return err
+	}
+
+	canView, canEdit, err := s.repo.Access(ctx, id, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !canView {
+		return ErrNotFound
+	}
+	if edit && !canEdit {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (ss *SavedSearch) filter() search.Filter {
+	return search.Filter{
+		Query:      ss.Query,
+		Types:      ss.Filters.Types,
+		AssetTypes: ss.Filters.AssetTypes,
+		Providers:  ss.Filters.Providers,
+		Tags:       ss.Filters.Tags,
+	}
+}
+
+// URL returns the discover page link for a search, using the same query
+// parameters the page writes.
+func URL(query string, f Filters) string {
+	v := url.Values{}
+	if query != "" {
+		v.Set("q", query)
+	}
+	if len(f.Types) > 0 {
+		kinds := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			kinds[i] = string(t)
+		}
+		v.Set("kind", strings.Join(kinds, ","))
+	}
+	if len(f.AssetTypes) > 0 {
+		v.Set("types", strings.Join(f.AssetTypes, ","))
+	}
+	if len(f.Providers) > 0 {
+		v.Set("providers", strings.Join(f.Providers, ","))
+	}
+	if len(f.Tags) > 0 {
+		v.Set("tags", strings.Join(f.Tags, ","))
+	}
+	if len(v) == 0 {
+		return "/discover"
+	}
+	return "/discover?" + v.Encode()
+}
+
+var validTypes = []search.ResultType{
+	search.ResultTypeAsset,
+	search.ResultTypeGlossary,
+	search.ResultTypeTeam,
+	search.ResultTypeDataProduct,
+	search.ResultTypeQuery,
+}
+
+func normalize(input Input) (*SavedSearch, []string, error) {
+	ss := &SavedSearch{
+		Name:  strings.TrimSpace(input.Name),
+		Query: strings.TrimSpace(input.Query),
+		Filters: Filters{
+			Types:      []search.ResultType{},
+			AssetTypes: dedupe(input.Filters.AssetTypes),
+			Providers:  dedupe(input.Filters.Providers),
+			Tags:       dedupe(input.Filters.Tags),
+		},
+	}
+
+	switch {
+	case ss.Name == "":
+		return nil, nil, &ValidationError{Message: "name is required"}
+	case len(ss.Name) > maxNameLength:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("name must be %d characters or fewer", maxNameLength)}
+	case len(ss.Query) > maxQueryLength:
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("query must be %d characters or fewer", maxQueryLength)}
+	}
+
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if len(description) > maxDescriptionLength {
+			return nil, nil, &ValidationError{Message: fmt.Sprintf("description must be %d characters or fewer", maxDescriptionLength)}
+		}
+		if description != "" {
+			ss.Description = &description
+		}
+	}
+
+	for _, t := range input.Filters.Types {
+		if !slices.Contains(validTypes, t) {
+			return nil, nil, &ValidationError{Message: fmt.Sprintf("invalid result type %q", t)}
+		}
+		if !slices.Contains(ss.Filters.Types, t) {
+			ss.Filters.Types = append(ss.Filters.Types, t)
+		}
+	}
+
+	for _, values := range [][]string{ss.Filters.AssetTypes, ss.Filters.Providers, ss.Filters.Tags} {
+		if len(values) > maxFilterValues {
+			return nil, nil, &ValidationError{Message: fmt.Sprintf("a filter can have at most %d values", maxFilterValues)}
+		}
+	}
+
+	teamIDs := dedupe(input.TeamIDs)
+	if len(teamIDs) > maxTeams {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("a search can be shared with at most %d teams", maxTeams)}
+	}
+
+	ss.URL = URL(ss.Query, ss.Filters)
+	return ss, teamIDs, nil
+}
+
+func dedupe(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || slices.Contains(out, v) {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
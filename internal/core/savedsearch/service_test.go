@@ -0,0 +1,234 @@
+package savedsearch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	searches    map[string]*SavedSearch
+	subscribers map[string][]string
+	matches     map[string][]string
+	nextID      int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		searches:    make(map[string]*SavedSearch),
+		subscribers: make(map[string][]string),
+		matches:     make(map[string][]string),
+	}
+}
+
+func (f *fakeRepo) Create(_ context.Context, ss *SavedSearch, _ []string) error {
+	f.nextID++
+	ss.ID = fmt.Sprintf("search-%d", f.nextID)
+	stored := *ss
+	f.searches[ss.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, id, userID string) (*SavedSearch, error) {
+	ss, ok := f.searches[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *ss
+	out.Subscribed = slices.Contains(f.subscribers[id], userID)
+	return &out, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, userID string, _, _ int) ([]*SavedSearch, int, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeRepo) Update(_ context.Context, ss *SavedSearch, _ []string) error {
+	stored := *ss
+	f.searches[ss.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepo) Delete(_ context.Context, id string) error {
+	delete(f.searches, id)
+	return nil
+}
+
+func (f *fakeRepo) Access(_ context.Context, id, userID string) (bool, bool, error) {
+	ss, ok := f.searches[id]
+	if !ok {
+		return false, false, ErrNotFound
+	}
+	mine := ss.CreatedBy != nil && *ss.CreatedBy == userID
+	return mine || userID == "teammate", mine, nil
+}
+
+func (f *fakeRepo) Subscribe(_ context.Context, id, userID string) error {
+	f.subscribers[id] = append(f.subscribers[id], userID)
+	return nil
+}
+
+func (f *fakeRepo) Unsubscribe(_ context.Context, id, userID string) error {
+	return nil
+}
+
+func (f *fakeRepo) ListSubscribed(_ context.Context) ([]*SavedSearch, error) {
+	var out []*SavedSearch
+	for id, ss := range f.searches {
+		if len(f.subscribers[id]) > 0 {
+			copied := *ss
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) Subscribers(_ context.Context, id string) ([]string, error) {
+	return f.subscribers[id], nil
+}
+
+func (f *fakeRepo) SyncMatches(_ context.Context, id string, assetIDs []string, prune bool) ([]string, error) {
+	var added []string
+	for _, assetID := range assetIDs {
+		if !slices.Contains(f.matches[id], assetID) {
+			added = append(added, assetID)
+		}
+	}
+	if prune {
+		f.matches[id] = slices.Clone(assetIDs)
+	} else {
+		f.matches[id] = append(f.matches[id], added...)
+	}
+	now := time.Now()
+	f.searches[id].LastCheckedAt = &now
+	return added, nil
+}
+
+type fakeSearcher struct {
+	assets  []string
+	filters []search.Filter
+}
+
+func (f *fakeSearcher) Search(_ context.Context, filter search.Filter) (*search.Response, error) {
+	f.filters = append(f.filters, filter)
+	resp := &search.Response{Total: len(f.assets), Results: []*search.Result{}}
+	for i := filter.Offset; i < len(f.assets) && i < filter.Offset+filter.Limit; i++ {
+		resp.Results = append(resp.Results, &search.Result{
+			Type:     search.ResultTypeAsset,
+			ID:       f.assets[i],
+			Name:     "asset " + f.assets[i],
+			Metadata: map[string]interface{}{"mrn": "mrn://" + f.assets[i]},
+		})
+	}
+	return resp, nil
+}
+
+type fakeNotifier struct {
+	recipients []string
+	matches    []Match
+}
+
+func (f *fakeNotifier) NotifyNewMatches(_ context.Context, _ *SavedSearch, subscriberIDs []string, matches []Match) {
+	f.recipients = subscriberIDs
+	f.matches = append(f.matches, matches...)
+}
+
+func TestCheckAllNotifiesNewMatches(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	searcher := &fakeSearcher{assets: []string{"a1", "a2"}}
+	notifier := &fakeNotifier{}
+	svc := NewService(repo, searcher)
+	svc.SetNotifier(notifier)
+
+	owner := Actor{UserID: "owner"}
+	ss, err := svc.Create(ctx, Input{Name: "PII tables", Query: "pii", Filters: Filters{Tags: []string{"pii"}}}, owner)
+	require.NoError(t, err)
+	require.NoError(t, svc.Subscribe(ctx, ss.ID, owner))
+
+	// The first check records what already matches without notifying.
+	n, err := svc.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, notifier.matches)
+	require.NotEmpty(t, searcher.filters)
+	assert.Equal(t, []search.ResultType{search.ResultTypeAsset}, searcher.filters[0].Types)
+	assert.Equal(t, []string{"pii"}, searcher.filters[0].Tags)
+
+	searcher.assets = append(searcher.assets, "a3")
+	n, err = svc.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"owner"}, notifier.recipients)
+	assert.Equal(t, []Match{{AssetID: "a3", MRN: "mrn://a3", Name: "asset a3"}}, notifier.matches)
+
+	// Nothing new, nothing sent.
+	n, err = svc.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, notifier.matches, 1)
+}
+
+func TestCheckAllSkipsSearchesWithoutAssets(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	searcher := &fakeSearcher{assets: []string{"a1"}}
+	svc := NewService(repo, searcher)
+
+	owner := Actor{UserID: "owner"}
+	ss, err := svc.Create(ctx, Input{Name: "Terms", Filters: Filters{Types: []search.ResultType{search.ResultTypeGlossary}}}, owner)
+	require.NoError(t, err)
+	require.NoError(t, svc.Subscribe(ctx, ss.ID, owner))
+
+	_, err = svc.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, searcher.filters)
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newFakeRepo(), &fakeSearcher{})
+
+	ss, err := svc.Create(ctx, Input{Name: "Mine"}, Actor{UserID: "owner"})
+	require.NoError(t, err)
+
+	_, err = svc.Get(ctx, ss.ID, Actor{UserID: "stranger"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.Get(ctx, ss.ID, Actor{UserID: "teammate"})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Subscribe(ctx, ss.ID, Actor{UserID: "teammate"}))
+	_, err = svc.Update(ctx, ss.ID, Input{Name: "Theirs"}, Actor{UserID: "teammate"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.Update(ctx, ss.ID, Input{Name: "Moderated"}, Actor{UserID: "admin", CanModerate: true})
+	assert.NoError(t, err)
+}
+
+func TestNormalize(t *testing.T) {
+	ss, teams, err := normalize(Input{
+		Name:    "  Orders  ",
+		Query:   " orders ",
+		Filters: Filters{Types: []search.ResultType{"asset", "asset"}, Providers: []string{"Snowflake", " ", "Snowflake"}},
+		TeamIDs: []string{"t1", "t1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Orders", ss.Name)
+	assert.Equal(t, "orders", ss.Query)
+	assert.Equal(t, []search.ResultType{search.ResultTypeAsset}, ss.Filters.Types)
+	assert.Equal(t, []string{"Snowflake"}, ss.Filters.Providers)
+	assert.Equal(t, []string{"t1"}, teams)
+	assert.Equal(t, "/discover?kind=asset&providers=Snowflake&q=orders", ss.URL)
+
+	_, _, err = normalize(Input{})
+	assert.True(t, IsValidationError(err))
+
+	_, _, err = normalize(Input{Name: "x", Filters: Filters{Types: []search.ResultType{"widget"}}})
+	assert.True(t, IsValidationError(err))
+}
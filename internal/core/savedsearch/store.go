@@ -0,0 +1,403 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/core/search"
+)
+
+// Repository defines the saved search data access interface.
+type Repository interface {
+	Create(ctx context.Context, ss *SavedSearch, teamIDs []string) error
+	// Get returns a saved search, with Subscribed set for userID.
+	Get(ctx context.Context, id, userID string) (*SavedSearch, error)
+	// List returns the saved searches userID created or that are shared
+	// with one of their teams.
+	List(ctx context.Context, userID string, limit, offset int) ([]*SavedSearch, int, error)
+	// Update replaces the search's fields and teams. If its query or
+	// filters changed, its recorded matches are cleared.
+	Update(ctx context.Context, ss *SavedSearch, teamIDs []string) error
+	Delete(ctx context.Context, id string) error
+	// Access reports whether userID may see and edit the search. It
+	// returns ErrNotFound for unknown searches.
+	Access(ctx context.Context, id, userID string) (canView, canEdit bool, err error)
+	Subscribe(ctx context.Context, id, userID string) error
+	Unsubscribe(ctx context.Context, id, userID string) error
+	// ListSubscribed returns every saved search with at least one
+	// subscriber.
+	ListSubscribed(ctx context.Context) ([]*SavedSearch, error)
+	Subscribers(ctx context.Context, id string) ([]string, error)
+	// SyncMatches records assetIDs as matching the search, returns the ones
+	// that weren't recorded before and marks the search checked. With
+	// prune, recorded matches not in assetIDs are forgotten.
+	SyncMatches(ctx context.Context, id string, assetIDs []string, prune bool) ([]string, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// selectSearch takes the requesting user's ID as $1.
+const selectSearch = `
+	SELECT s.id::text, s.name, s.description, s.query,
+	       s.result_types, s.asset_types, s.providers, s.tags,
+	       EXISTS (SELECT 1 FROM saved_search_subscriptions sub WHERE sub.saved_search_id = s.id AND sub.user_id::text = $1),
+	       (SELECT COUNT(*) FROM saved_search_subscriptions sub WHERE sub.saved_search_id = s.id),
+	       s.created_by::text, COALESCE(cu.name, cu.username, ''),
+	       s.created_at, s.updated_at, s.last_checked_at
+	FROM saved_searches s
+	LEFT JOIN users cu ON cu.id = s.created_by`
+
+// visibleTo matches searches the user in $1 created or is shared with.
+const visibleTo = `
+	(COALESCE(s.created_by::text = $1, FALSE) OR EXISTS (
+		SELECT 1 FROM saved_search_teams st
+		JOIN team_members tm ON tm.team_id = st.team_id
+		WHERE st.saved_search_id = s.id AND tm.user_id::text = $1))`
+
+func (r *PostgresRepository) Create(ctx context.Context, ss *SavedSearch, teamIDs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO saved_searches (name, description, query, result_types, asset_types, providers, tags, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id::text, created_at, updated_at`,
+		ss.Name, ss.Description, ss.Query, typeStrings(ss.Filters.Types),
+		ss.Filters.AssetTypes, ss.Filters.Providers, ss.Filters.Tags, ss.CreatedBy,
+	).Scan(&ss.ID, &ss.CreatedAt, &ss.UpdatedAt)
+	if err != nil {
+		return mapWriteError(err, "creating saved search")
+	}
+
+	if err := shareWithTeams(ctx, tx, ss.ID, teamIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id, userID string) (*SavedSearch, error) {
+	ss, err := scanSearch(r.db.QueryRow(ctx, selectSearch+` WHERE s.id::text = $2`, userID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting saved search: %w", err)
+	}
+
+	if err := r.loadTeams(ctx, []*SavedSearch{ss}); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, limit, offset int) ([]*SavedSearch, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM saved_searches s WHERE`+visibleTo,
+		userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting saved searches: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectSearch+` WHERE`+visibleTo+`
+		ORDER BY s.updated_at DESC, s.id
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing saved searches: %w", err)
+	}
+	searches, err := collectSearches(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadTeams(ctx, searches); err != nil {
+		return nil, 0, err
+	}
+	return searches, total, nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, ss *SavedSearch, teamIDs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var filtersChanged bool
+	err = tx.QueryRow(ctx, `
+		WITH old AS (
+			SELECT query, result_types, asset_types, providers, tags
+			FROM saved_searches WHERE id::text = $1 FOR UPDATE
+		)
+		UPDATE saved_searches s SET
+			name = $2, description = $3, query = $4, result_types = $5,
+			asset_types = $6, providers = $7, tags = $8, updated_at = NOW(),
+			last_checked_at = CASE
+				WHEN (old.query, old.result_types, old.asset_types, old.providers, old.tags)
+				     IS DISTINCT FROM ($4, $5::text[], $6::text[], $7::text[], $8::text[])
+				THEN NULL ELSE s.last_checked_at END
+		FROM old
+		WHERE s.id::text = $1
+		RETURNING s.last_checked_at IS NULL`,
+		ss.ID, ss.Name, ss.Description, ss.Query, typeStrings(ss.Filters.Types),
+		ss.Filters.AssetTypes, ss.Filters.Providers, ss.Filters.Tags,
+	).Scan(&filtersChanged)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return mapWriteError(err, "updating saved search")
+	}
+
+	if filtersChanged {
+		if _, err := tx.Exec(ctx, `DELETE FROM saved_search_matches WHERE saved_search_id::text = $1`, ss.ID); err != nil {
+			return fmt.Errorf("clearing saved search matches: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM saved_search_teams WHERE saved_search_id::text = $1`, ss.ID); err != nil {
+		return fmt.Errorf("clearing saved search teams: %w", err)
+	}
+	if err := shareWithTeams(ctx, tx, ss.ID, teamIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_searches WHERE id::text = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting saved search: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Access(ctx context.Context, id, userID string) (bool, bool, error) {
+	var canView, canEdit bool
+	err := r.db.QueryRow(ctx, `
+		SELECT `+visibleTo+`, COALESCE(s.created_by::text = $1, FALSE)
+		FROM saved_searches s
+		WHERE s.id::text = $2`, userID, id).Scan(&canView, &canEdit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, false, ErrNotFound
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("checking saved search permissions: %w", err)
+	}
+	return canView, canEdit, nil
+}
+
+func (r *PostgresRepository) Subscribe(ctx context.Context, id, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO saved_search_subscriptions (saved_search_id, user_id)
+		VALUES ($1::uuid, $2::uuid)
+		ON CONFLICT DO NOTHING`, id, userID)
+	if err != nil {
+		return mapWriteError(err, "subscribing to saved search")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Unsubscribe(ctx context.Context, id, userID string) error {
+	if _, err := r.db.Exec(ctx, `
+		DELETE FROM saved_search_subscriptions
+		WHERE saved_search_id::text = $1 AND user_id::text = $2`, id, userID); err != nil {
+		return fmt.Errorf("unsubscribing from saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListSubscribed(ctx context.Context) ([]*SavedSearch, error) {
+	rows, err := r.db.Query(ctx, selectSearch+`
+		WHERE EXISTS (SELECT 1 FROM saved_search_subscriptions sub WHERE sub.saved_search_id = s.id)
+		ORDER BY s.last_checked_at NULLS FIRST, s.id`, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing subscribed saved searches: %w", err)
+	}
+	return collectSearches(rows)
+}
+
+func (r *PostgresRepository) Subscribers(ctx context.Context, id string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id::text FROM saved_search_subscriptions
+		WHERE saved_search_id::text = $1
+		ORDER BY created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("listing saved search subscribers: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scanning saved search subscribers: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresRepository) SyncMatches(ctx context.Context, id string, assetIDs []string, prune bool) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if prune {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM saved_search_matches
+			WHERE saved_search_id::text = $1 AND NOT (asset_id = ANY($2))`, id, assetIDs); err != nil {
+			return nil, fmt.Errorf("pruning saved search matches: %w", err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO saved_search_matches (saved_search_id, asset_id)
+		SELECT $1::uuid, a.id FROM assets a WHERE a.id = ANY($2)
+		ON CONFLICT DO NOTHING
+		RETURNING asset_id`, id, assetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("recording saved search matches: %w", err)
+	}
+	added, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scanning saved search matches: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE saved_searches SET last_checked_at = NOW() WHERE id::text = $1`, id); err != nil {
+		return nil, fmt.Errorf("marking saved search checked: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing saved search matches: %w", err)
+	}
+	return added, nil
+}
+
+// loadTeams fills in the teams of searches with one round trip.
+func (r *PostgresRepository) loadTeams(ctx context.Context, searches []*SavedSearch) error {
+	if len(searches) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*SavedSearch, len(searches))
+	ids := make([]string, len(searches))
+	for i, ss := range searches {
+		ss.Teams = []Team{}
+		byID[ss.ID] = ss
+		ids[i] = ss.ID
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT st.saved_search_id::text, t.id::text, t.name
+		FROM saved_search_teams st
+		JOIN teams t ON t.id = st.team_id
+		WHERE st.saved_search_id::text = ANY($1)
+		ORDER BY t.name, t.id`, ids)
+	if err != nil {
+		return fmt.Errorf("loading saved search teams: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var searchID string
+		var t Team
+		if err := rows.Scan(&searchID, &t.ID, &t.Name); err != nil {
+			return fmt.Errorf("scanning saved search team: %w", err)
+		}
+		if ss := byID[searchID]; ss != nil {
+			ss.Teams = append(ss.Teams, t)
+		}
+	}
+	return rows.Err()
+}
+
+func shareWithTeams(ctx context.Context, tx pgx.Tx, id string, teamIDs []string) error {
+	if len(teamIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO saved_search_teams (saved_search_id, team_id)
+		SELECT $1::uuid, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING`, id, teamIDs)
+	if err != nil {
+		return mapWriteError(err, "sharing saved search")
+	}
+	return nil
+}
+
+// mapWriteError turns foreign key and malformed ID failures into the
+// service's errors.
+func mapWriteError(err error, action string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			if pgErr.ConstraintName == "saved_search_teams_team_id_fkey" {
+				return ErrTeamNotFound
+			}
+			return ErrNotFound
+		case "22P02":
+			return &ValidationError{Message: "invalid id"}
+		}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+func collectSearches(rows pgx.Rows) ([]*SavedSearch, error) {
+	defer rows.Close()
+
+	searches := []*SavedSearch{}
+	for rows.Next() {
+		ss, err := scanSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning saved search: %w", err)
+		}
+		searches = append(searches, ss)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+func scanSearch(row pgx.Row) (*SavedSearch, error) {
+	var ss SavedSearch
+	var types []string
+	if err := row.Scan(
+		&ss.ID, &ss.Name, &ss.Description, &ss.Query,
+		&types, &ss.Filters.AssetTypes, &ss.Filters.Providers, &ss.Filters.Tags,
+		&ss.Subscribed, &ss.SubscriberCount,
+		&ss.CreatedBy, &ss.CreatedByName, &ss.CreatedAt, &ss.UpdatedAt, &ss.LastCheckedAt,
+	); err != nil {
+		return nil, err
+	}
+	ss.Filters.Types = make([]search.ResultType, len(types))
+	for i, t := range types {
+		ss.Filters.Types[i] = search.ResultType(t)
+	}
+	ss.Teams = []Team{}
+	ss.URL = URL(ss.Query, ss.Filters)
+	return &ss, nil
+}
+
+func typeStrings(types []search.ResultType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package schemablob
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("schema not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// HashPrefix starts every schema hash. The rest is the hex SHA-256 of the
+// schema content.
+const HashPrefix = "sha256:"
+
+var hashPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// Blob is a schema stored once and referenced by every asset with the same
+// content.
+type Blob struct {
+	Hash       string    `json:"hash"`
+	Content    string    `json:"content"`
+	SizeBytes  int       `json:"size_bytes"`
+	AssetCount int       `json:"asset_count"`
+	CreatedAt  time.Time `json:"created_at"`
+} // @name SchemaBlob
+
+// AssetSchema is one named schema of an asset.
+type AssetSchema struct {
+	Name      string `json:"name"`
+	Hash      string `json:"hash"`
+	SizeBytes int    `json:"size_bytes"`
+	// SharedWith counts the other assets with exactly this schema.
+	SharedWith int `json:"shared_with"`
+} // @name AssetSchemaRef
+
+// SchemaAsset is an asset that references a schema.
+type SchemaAsset struct {
+	ID         string   `json:"id"`
+	MRN        string   `json:"mrn"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Providers  []string `json:"providers"`
+	SchemaName string   `json:"schema_name"`
+} // @name SchemaAsset
+
+type AssetsResult struct {
+	Assets []SchemaAsset `json:"assets"`
+	Total  int           `json:"total"`
+} // @name SchemaAssetsResult
+
+// Stats shows how much deduplication saves.
+type Stats struct {
+	Blobs      int `json:"blobs"`
+	References int `json:"references"`
+	// StoredBytes is the size of the distinct schemas; ReferencedBytes is
+	// what storing a copy per asset would take.
+	StoredBytes     int64 `json:"stored_bytes"`
+	ReferencedBytes int64 `json:"referenced_bytes"`
+} // @name SchemaStats
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// NormalizeHash accepts a hash with or without its "sha256:" prefix.
+func NormalizeHash(hash string) (string, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if !strings.HasPrefix(hash, HashPrefix) {
+		hash = HashPrefix + hash
+	}
+	if !hashPattern.MatchString(hash) {
+		return "", &ValidationError{Message: "hash must be a hex SHA-256 digest"}
+	}
+	return hash, nil
+}
+
+func (s *Service) Get(ctx context.Context, hash string) (*Blob, error) {
+	hash, err := NormalizeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, hash)
+}
+
+func (s *Service) ListForAsset(ctx context.Context, assetID string) ([]AssetSchema, error) {
+	if assetID == "" {
+		return nil, &ValidationError{Message: "asset id is required"}
+	}
+	return s.repo.ListForAsset(ctx, assetID)
+}
+
+// ListAssets returns the assets that share a schema.
+func (s *Service) ListAssets(ctx context.Context, hash string, limit, offset int) (*AssetsResult, error) {
+	hash, err := NormalizeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.ListAssets(ctx, hash, limit, offset)
+}
+
+func (s *Service) Stats(ctx context.Context) (*Stats, error) {
+	return s.repo.Stats(ctx)
+}
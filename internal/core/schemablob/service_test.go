@@ -0,0 +1,78 @@
+package schemablob
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	lastHash          string
+	lastLimit, offset int
+}
+
+func (f *fakeRepo) Get(_ context.Context, hash string) (*Blob, error) {
+	f.lastHash = hash
+	return &Blob{Hash: hash}, nil
+}
+
+func (f *fakeRepo) ListForAsset(_ context.Context, _ string) ([]AssetSchema, error) {
+	return []AssetSchema{}, nil
+}
+
+func (f *fakeRepo) ListAssets(_ context.Context, hash string, limit, offset int) (*AssetsResult, error) {
+	f.lastHash, f.lastLimit, f.offset = hash, limit, offset
+	return &AssetsResult{}, nil
+}
+
+func (f *fakeRepo) Stats(_ context.Context) (*Stats, error) {
+	return &Stats{}, nil
+}
+
+func TestNormalizeHash(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		invalid bool
+	}{
+		{name: "prefixed", in: "sha256:" + digest, want: "sha256:" + digest},
+		{name: "bare digest", in: digest, want: "sha256:" + digest},
+		{name: "upper case", in: " SHA256:" + strings.ToUpper(digest) + " ", want: "sha256:" + digest},
+		{name: "too short", in: "sha256:abc", invalid: true},
+		{name: "not hex", in: strings.Repeat("zz", 32), invalid: true},
+		{name: "empty", in: "", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeHash(tt.in)
+			if tt.invalid {
+				assert.True(t, IsValidationError(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListAssets_NormalizesInput(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+	digest := strings.Repeat("0f", 32)
+
+	_, err := svc.ListAssets(context.Background(), digest, 0, -5)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:"+digest, repo.lastHash)
+	assert.Equal(t, 20, repo.lastLimit)
+	assert.Equal(t, 0, repo.offset)
+
+	_, err = svc.ListForAsset(context.Background(), "")
+	assert.True(t, IsValidationError(err))
+}
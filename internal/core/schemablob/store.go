@@ -0,0 +1,114 @@
+package schemablob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository reads deduplicated schemas. Blobs and their asset references
+// are written by database triggers whenever assets.schema changes.
+type Repository interface {
+	Get(ctx context.Context, hash string) (*Blob, error)
+	ListForAsset(ctx context.Context, assetID string) ([]AssetSchema, error)
+	ListAssets(ctx context.Context, hash string, limit, offset int) (*AssetsResult, error)
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, hash string) (*Blob, error) {
+	var b Blob
+	err := r.db.QueryRow(ctx, `
+		SELECT s.hash, s.content, s.size_bytes, s.created_at,
+		       (SELECT COUNT(DISTINCT asset_id) FROM asset_schemas WHERE hash = s.hash)
+		FROM schemas s
+		WHERE s.hash = $1`, hash).Scan(&b.Hash, &b.Content, &b.SizeBytes, &b.CreatedAt, &b.AssetCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting schema: %w", err)
+	}
+	return &b, nil
+}
+
+func (r *PostgresRepository) ListForAsset(ctx context.Context, assetID string) ([]AssetSchema, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.name, r.hash, s.size_bytes,
+		       (SELECT COUNT(DISTINCT o.asset_id) FROM asset_schemas o WHERE o.hash = r.hash AND o.asset_id <> r.asset_id)
+		FROM asset_schemas r
+		JOIN schemas s ON s.hash = r.hash
+		WHERE r.asset_id = $1
+		ORDER BY r.name`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("listing asset schemas: %w", err)
+	}
+	defer rows.Close()
+
+	schemas := []AssetSchema{}
+	for rows.Next() {
+		var s AssetSchema
+		if err := rows.Scan(&s.Name, &s.Hash, &s.SizeBytes, &s.SharedWith); err != nil {
+			return nil, fmt.Errorf("scanning asset schema: %w", err)
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas, rows.Err()
+}
+
+func (r *PostgresRepository) ListAssets(ctx context.Context, hash string, limit, offset int) (*AssetsResult, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_schemas WHERE hash = $1`, hash).Scan(&total); err != nil {
+		return nil, fmt.Errorf("counting schema assets: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.mrn, a.name, a.type, a.providers, r.name
+		FROM asset_schemas r
+		JOIN assets a ON a.id = r.asset_id
+		WHERE r.hash = $1
+		ORDER BY a.name, a.id, r.name
+		LIMIT $2 OFFSET $3`, hash, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing schema assets: %w", err)
+	}
+	defer rows.Close()
+
+	result := &AssetsResult{Assets: []SchemaAsset{}, Total: total}
+	for rows.Next() {
+		var a SchemaAsset
+		if err := rows.Scan(&a.ID, &a.MRN, &a.Name, &a.Type, &a.Providers, &a.SchemaName); err != nil {
+			return nil, fmt.Errorf("scanning schema asset: %w", err)
+		}
+		result.Assets = append(result.Assets, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating schema assets: %w", err)
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) Stats(ctx context.Context) (*Stats, error) {
+	var s Stats
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM schemas),
+			(SELECT COUNT(*) FROM asset_schemas),
+			(SELECT COALESCE(SUM(size_bytes), 0) FROM schemas),
+			(SELECT COALESCE(SUM(s.size_bytes), 0) FROM asset_schemas r JOIN schemas s ON s.hash = r.hash)`,
+	).Scan(&s.Blobs, &s.References, &s.StoredBytes, &s.ReferencedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getting schema stats: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,154 @@
+package schematree
+
+import "strings"
+
+func isAvroRecord(schema map[string]interface{}) bool {
+	_, ok := schema["fields"].([]interface{})
+	return ok && schema["type"] == "record"
+}
+
+// avroReader reads Avro records. Named types are remembered as they are
+// defined so later references to them can be expanded; a record referring
+// to itself, directly or through its fields, is not expanded again.
+// namespace is the enclosing namespace, which names without their own
+// inherit.
+type avroReader struct {
+	named     map[string]map[string]interface{}
+	expanding map[string]bool
+	namespace string
+}
+
+func avroColumns(record map[string]interface{}) []*Column {
+	r := &avroReader{
+		named:     map[string]map[string]interface{}{},
+		expanding: map[string]bool{},
+	}
+	r.expanding[r.register(record)] = true
+	r.namespace = r.namespaceOf(record)
+	return r.fields(record, "", 0)
+}
+
+func (r *avroReader) fields(record map[string]interface{}, parent string, depth int) []*Column {
+	fields, _ := record["fields"].([]interface{})
+	columns := make([]*Column, 0, len(fields))
+	for _, item := range fields {
+		field, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := stringOf(field, "name")
+		if name == "" {
+			continue
+		}
+		col := &Column{Name: name, Path: joinPath(parent, name), Description: stringOf(field, "doc")}
+		r.describe(col, field["type"], depth)
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// describe fills in col's type, kind, children and whether it is required
+// from an Avro type: a primitive or named type, a union, or a complex type.
+func (r *avroReader) describe(col *Column, typ interface{}, depth int) {
+	col.Type, col.Kind, col.Required, col.Children = "", KindScalar, true, nil
+
+	switch t := typ.(type) {
+	case string:
+		if def, ok := r.named[t]; ok {
+			r.describe(col, def, depth)
+			return
+		}
+		col.Type = t
+	case []interface{}:
+		var branches []interface{}
+		nullable := false
+		for _, branch := range t {
+			if branch == "null" {
+				nullable = true
+				continue
+			}
+			branches = append(branches, branch)
+		}
+		if len(branches) == 1 {
+			r.describe(col, branches[0], depth)
+		} else {
+			names := make([]string, len(branches))
+			for i, branch := range branches {
+				b := &Column{Path: col.Path}
+				r.describe(b, branch, depth)
+				names[i] = b.Type
+			}
+			col.Type = "union<" + strings.Join(names, ", ") + ">"
+		}
+		col.Required = !nullable
+	case map[string]interface{}:
+		r.describeComplex(col, t, depth)
+	}
+}
+
+func (r *avroReader) describeComplex(col *Column, t map[string]interface{}, depth int) {
+	kind, ok := t["type"].(string)
+	if !ok {
+		// A type wrapped in another object, such as {"type": {"type": "array", ...}}.
+		r.describe(col, t["type"], depth)
+		return
+	}
+
+	name := r.register(t)
+	switch kind {
+	case "record", "error":
+		col.Type, col.Kind = name, KindStruct
+		if col.Type == "" {
+			col.Type = kind
+		}
+		if depth < maxDepth && !r.expanding[name] {
+			enclosing := r.namespace
+			r.namespace = r.namespaceOf(t)
+			r.expanding[name] = true
+			col.Children = r.fields(t, col.Path, depth+1)
+			delete(r.expanding, name)
+			r.namespace = enclosing
+		}
+	case "array", "map":
+		key := "items"
+		col.Kind = KindArray
+		if kind == "map" {
+			key, col.Kind = "values", KindMap
+		}
+		elem := &Column{Path: col.Path}
+		r.describe(elem, t[key], depth)
+		col.Type = kind + "<" + elem.Type + ">"
+		col.Children = elem.Children
+	case "enum", "fixed":
+		col.Type = kind
+		if name != "" {
+			col.Type = kind + "<" + name + ">"
+		}
+	default:
+		col.Type = kind
+		if logical := stringOf(t, "logicalType"); logical != "" {
+			col.Type = logical
+		}
+	}
+}
+
+// register remembers a named type under its full and short names and
+// returns its short name.
+func (r *avroReader) register(def map[string]interface{}) string {
+	name := stringOf(def, "name")
+	if name == "" {
+		return ""
+	}
+	r.named[name] = def
+	if ns := r.namespaceOf(def); ns != "" {
+		r.named[ns+"."+name] = def
+	}
+	return name
+}
+
+func (r *avroReader) namespaceOf(def map[string]interface{}) string {
+	if ns := stringOf(def, "namespace"); ns != "" {
+		return ns
+	}
+	return r.namespace
+}
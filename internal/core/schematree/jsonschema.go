@@ -0,0 +1,385 @@
+package schematree
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+func isJSONSchema(schema map[string]interface{}) bool {
+	for _, key := range []string{"$schema", "$ref", "$defs", "definitions", "properties", "allOf"} {
+		if _, ok := schema[key]; ok {
+			return true
+		}
+	}
+	return schema["type"] == "object"
+}
+
+// jsonSchemaReader reads JSON Schema, from draft-04 to 2020-12. Local
+// references are expanded, except where a definition refers back to
+// itself.
+type jsonSchemaReader struct {
+	root      map[string]interface{}
+	expanding map[string]bool
+}
+
+func jsonSchemaColumns(root map[string]interface{}) []*Column {
+	r := &jsonSchemaReader{root: root, expanding: map[string]bool{}}
+	schema, _, _ := r.flatten(root, 0)
+	return r.properties(schema, "", 0)
+}
+
+// flattenedKeys are the keywords flatten folds into the schema it returns,
+// rather than copying.
+var flattenedKeys = map[string]bool{
+	"properties": true, "required": true, "$ref": true, "$dynamicRef": true,
+	"allOf": true, "if": true, "then": true, "else": true, "dependentSchemas": true,
+	"$defs": true, "definitions": true,
+}
+
+// flatten merges the subschemas that describe the same value as schema
+// into one: the target of $ref or $dynamicRef, which since 2019-09 may
+// have siblings, allOf, and the conditional branches of if/then/else and
+// dependentSchemas. The schema's own keywords win; properties are
+// combined, and those only present in conditional branches are optional.
+//
+// The references expanded are marked as such until the caller releases
+// them, so that a definition isn't expanded again within itself. flatten
+// returns the merged schema, the name of the definition referenced, if
+// any, and the references to release.
+func (r *jsonSchemaReader) flatten(schema map[string]interface{}, depth int) (map[string]interface{}, string, []string) {
+	merged := make(map[string]interface{}, len(schema))
+	props := map[string]interface{}{}
+	var required []interface{}
+	var name string
+	var refs []string
+
+	add := func(sub map[string]interface{}, conditional bool) {
+		if p, ok := sub["properties"].(map[string]interface{}); ok {
+			for k, v := range p {
+				if _, ok := props[k]; !ok {
+					props[k] = v
+				}
+			}
+		}
+		if req, ok := sub["required"].([]interface{}); ok && !conditional {
+			required = append(required, req...)
+		}
+		for k, v := range sub {
+			if _, ok := merged[k]; !ok && !flattenedKeys[k] {
+				merged[k] = v
+			}
+		}
+	}
+	nested := func(sub map[string]interface{}) map[string]interface{} {
+		if depth >= maxDepth {
+			return sub
+		}
+		flat, _, subRefs := r.flatten(sub, depth+1)
+		refs = append(refs, subRefs...)
+		return flat
+	}
+
+	add(schema, false)
+
+	if ref := stringOf(schema, "$ref", "$dynamicRef"); ref != "" {
+		name = refName(ref)
+		if target := r.lookup(ref); target != nil && !r.expanding[ref] && depth < maxDepth {
+			r.expanding[ref] = true
+			refs = append(refs, ref)
+			add(nested(target), false)
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, item := range all {
+			if sub, ok := item.(map[string]interface{}); ok {
+				add(nested(sub), false)
+			}
+		}
+	}
+
+	for _, key := range []string{"then", "else"} {
+		if sub, ok := schema[key].(map[string]interface{}); ok {
+			add(nested(sub), true)
+		}
+	}
+	if deps, ok := schema["dependentSchemas"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(deps) {
+			if sub, ok := deps[key].(map[string]interface{}); ok {
+				add(nested(sub), true)
+			}
+		}
+	}
+
+	if len(props) > 0 {
+		merged["properties"] = props
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged, name, refs
+}
+
+func (r *jsonSchemaReader) release(refs []string) {
+	for _, ref := range refs {
+		delete(r.expanding, ref)
+	}
+}
+
+// properties returns the columns of an object schema, ordered by name.
+func (r *jsonSchemaReader) properties(schema map[string]interface{}, parent string, depth int) []*Column {
+	props, _ := schema["properties"].(map[string]interface{})
+	requiredNames := map[string]bool{}
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				requiredNames[s] = true
+			}
+		}
+	}
+
+	columns := make([]*Column, 0, len(props))
+	for _, name := range sortedKeys(props) {
+		col := &Column{Name: name, Path: joinPath(parent, name)}
+		prop, _ := props[name].(map[string]interface{})
+		r.describe(col, prop, depth)
+		col.Required = col.Required && requiredNames[name]
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// describe fills in col from a property's schema. Required is false if the
+// property allows null.
+func (r *jsonSchemaReader) describe(col *Column, schema map[string]interface{}, depth int) {
+	col.Type, col.Kind, col.Required, col.Children = "", KindScalar, true, nil
+	if schema == nil {
+		return
+	}
+
+	schema, name, refs := r.flatten(schema, depth)
+	defer r.release(refs)
+	if col.Description == "" {
+		col.Description = stringOf(schema, "description")
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if branches, ok := schema[key].([]interface{}); ok {
+			r.describeUnion(col, branches, depth)
+			return
+		}
+	}
+
+	var types []string
+	nullable := false
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		nonNull = append(nonNull, t)
+	}
+
+	typ := strings.Join(nonNull, " | ")
+	if typ == "" {
+		switch {
+		case schema["properties"] != nil:
+			typ = "object"
+		case schema["items"] != nil || schema["prefixItems"] != nil:
+			typ = "array"
+		case schema["enum"] != nil:
+			typ = "enum"
+		case schema["const"] != nil:
+			typ = "const"
+		default:
+			typ = name
+		}
+	}
+
+	switch typ {
+	case "object":
+		col.Type = "object"
+		if _, ok := schema["properties"]; ok {
+			col.Kind = KindStruct
+			if name != "" {
+				col.Type = name
+			}
+			if depth < maxDepth {
+				col.Children = r.properties(schema, col.Path, depth+1)
+			}
+		} else if values := additionalValues(schema); values != nil {
+			value := &Column{Path: col.Path}
+			r.describe(value, values, depth)
+			col.Type, col.Kind, col.Children = "map<"+value.Type+">", KindMap, value.Children
+		}
+	case "array":
+		r.describeArray(col, schema, depth)
+	default:
+		col.Type = typ
+	}
+	if nullable {
+		col.Required = false
+	}
+}
+
+// additionalValues returns the schema of an object's values when it has no
+// named properties: additionalProperties, or unevaluatedProperties from
+// 2019-09 on.
+func additionalValues(schema map[string]interface{}) map[string]interface{} {
+	for _, key := range []string{"additionalProperties", "unevaluatedProperties"} {
+		if values, ok := schema[key].(map[string]interface{}); ok {
+			return values
+		}
+	}
+	return nil
+}
+
+// describeArray describes an array. Tuples, given by prefixItems from
+// 2020-12 or by a list of items before it, have a child for each position.
+func (r *jsonSchemaReader) describeArray(col *Column, schema map[string]interface{}, depth int) {
+	col.Type, col.Kind = "array", KindArray
+
+	positions, ok := schema["prefixItems"].([]interface{})
+	if !ok {
+		positions, ok = schema["items"].([]interface{})
+	}
+	if ok {
+		names := make([]string, 0, len(positions))
+		for i, item := range positions {
+			sub, _ := item.(map[string]interface{})
+			pos := &Column{Name: fmt.Sprint(i), Path: joinPath(col.Path, fmt.Sprint(i))}
+			r.describe(pos, sub, depth+1)
+			names = append(names, pos.Type)
+			col.Children = append(col.Children, pos)
+		}
+		col.Type = "tuple<" + strings.Join(names, ", ") + ">"
+		return
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		elem := &Column{Path: col.Path}
+		r.describe(elem, items, depth)
+		col.Type, col.Children = "array<"+elem.Type+">", elem.Children
+	}
+}
+
+// describeUnion describes an anyOf or oneOf. A null branch makes the
+// column optional; a single other branch describes it.
+func (r *jsonSchemaReader) describeUnion(col *Column, branches []interface{}, depth int) {
+	var schemas []map[string]interface{}
+	nullable := false
+	for _, b := range branches {
+		branch, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if branch["type"] == "null" {
+			nullable = true
+			continue
+		}
+		schemas = append(schemas, branch)
+	}
+
+	if len(schemas) == 1 {
+		r.describe(col, schemas[0], depth)
+	} else {
+		names := make([]string, len(schemas))
+		for i, branch := range schemas {
+			b := &Column{Path: col.Path}
+			r.describe(b, branch, depth)
+			names[i] = b.Type
+		}
+		col.Type = "union<" + strings.Join(names, ", ") + ">"
+	}
+	if nullable {
+		col.Required = false
+	}
+}
+
+// lookup resolves a reference within the schema: a JSON pointer such as
+// #/definitions/Address or #/$defs/Address, or a plain-name fragment
+// naming an $anchor or $dynamicAnchor. A reference starting with the $id
+// of the schema or of one of its subschemas resolves within that schema.
+func (r *jsonSchemaReader) lookup(ref string) map[string]interface{} {
+	base, fragment, _ := strings.Cut(ref, "#")
+	current := r.root
+	if base != "" && base != stringOf(r.root, "$id") {
+		if current = findByKeyword(r.root, "$id", base); current == nil {
+			return nil
+		}
+	}
+
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		if found := findByKeyword(current, "$anchor", fragment); found != nil {
+			return found
+		}
+		return findByKeyword(current, "$dynamicAnchor", fragment)
+	}
+
+	for _, token := range strings.Split(fragment, "/") {
+		if token == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		next, ok := current[token].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// findByKeyword returns the first subschema of schema, or schema itself,
+// whose keyword has the given value. Relative $id values match by suffix.
+func findByKeyword(schema map[string]interface{}, keyword, value string) map[string]interface{} {
+	if v, ok := schema[keyword].(string); ok && (v == value || (keyword == "$id" && strings.HasSuffix(value, "/"+v))) {
+		return schema
+	}
+	for _, key := range sortedKeys(schema) {
+		switch child := schema[key].(type) {
+		case map[string]interface{}:
+			if found := findByKeyword(child, keyword, value); found != nil {
+				return found
+			}
+		case []interface{}:
+			for _, item := range child {
+				if m, ok := item.(map[string]interface{}); ok {
+					if found := findByKeyword(m, keyword, value); found != nil {
+						return found
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndexAny(ref, "/#")+1:]
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,179 @@
+// Package schematree normalizes asset schemas into a tree of columns.
+//
+// Plugins store schemas in several shapes (SQL-style column lists, Avro
+// records, JSON Schema) and each describes nested types differently:
+// Avro records, BigQuery RECORD fields, Parquet groups or STRUCT<...> type
+// names. The tree puts them in one shape, with the fields of structs,
+// arrays of structs and maps of structs as children of their column.
+package schematree
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Kind is the shape of a column's values.
+type Kind string
+
+const (
+	KindScalar Kind = "scalar"
+	KindStruct Kind = "struct"
+	KindArray  Kind = "array"
+	KindMap    Kind = "map"
+)
+
+// Schema formats a section can be read from.
+const (
+	FormatColumns    = "columns"
+	FormatAvro       = "avro"
+	FormatJSONSchema = "json_schema"
+)
+
+// Column is a column or a nested field. Path is the dotted path from the
+// top-level column. The children of an array or map column are the fields
+// of the structs it holds.
+type Column struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Type        string    `json:"type"`
+	Kind        Kind      `json:"kind"`
+	Required    bool      `json:"required"`
+	Description string    `json:"description,omitempty"`
+	Children    []*Column `json:"children,omitempty"`
+} // @name SchemaTreeColumn
+
+// Section is the tree of one schema section of an asset.
+type Section struct {
+	Name    string    `json:"name"`
+	Format  string    `json:"format"`
+	Columns []*Column `json:"columns"`
+} // @name SchemaTreeSection
+
+// maxDepth bounds nesting, so that recursive types end.
+const maxDepth = 32
+
+// Build returns the tree of each schema section, ordered by section name.
+// Sections that aren't JSON, such as Protobuf definitions, or whose shape
+// isn't recognised are left out.
+func Build(schema map[string]string) []Section {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sections := make([]Section, 0, len(names))
+	for _, name := range names {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(schema[name]), &parsed); err != nil {
+			continue
+		}
+		if section, ok := buildSection(parsed); ok {
+			section.Name = name
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+func buildSection(v interface{}) (Section, bool) {
+	switch t := v.(type) {
+	case []interface{}:
+		return Section{Format: FormatColumns, Columns: columnList(t, "", 0)}, true
+	case map[string]interface{}:
+		switch {
+		case isAvroRecord(t):
+			return Section{Format: FormatAvro, Columns: avroColumns(t)}, true
+		case isJSONSchema(t):
+			return Section{Format: FormatJSONSchema, Columns: jsonSchemaColumns(t)}, true
+		}
+		// OpenLineage schema facets keep their columns under fields.
+		for _, key := range []string{"columns", "fields"} {
+			if list, ok := t[key].([]interface{}); ok {
+				return Section{Format: FormatColumns, Columns: columnList(list, "", 0)}, true
+			}
+		}
+	}
+	return Section{}, false
+}
+
+// columnList reads a plugin column list: SQL-style columns with
+// column_name and data_type, dbt and OpenLineage columns with name and
+// type, and BigQuery table schemas with mode. Nested columns are read from
+// fields where the plugin lists them, and otherwise from the type name.
+func columnList(items []interface{}, parent string, depth int) []*Column {
+	columns := make([]*Column, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := stringOf(obj, "column_name", "name")
+		if name == "" {
+			continue
+		}
+
+		col := &Column{
+			Name:        name,
+			Path:        joinPath(parent, name),
+			Type:        stringOf(obj, "data_type", "type"),
+			Required:    required(obj),
+			Description: stringOf(obj, "comment", "description"),
+		}
+
+		parsed := parseType(col.Type, depth)
+		col.Kind = parsed.kind
+		if parsed.nullable {
+			col.Required = false
+		}
+		if fields, ok := obj["fields"].([]interface{}); ok && len(fields) > 0 {
+			if col.Kind == KindScalar {
+				col.Kind = KindStruct
+			}
+			if depth < maxDepth {
+				col.Children = columnList(fields, col.Path, depth+1)
+			}
+		} else {
+			col.Children = parsed.columns(col.Path, depth+1)
+		}
+		if strings.EqualFold(stringOf(obj, "mode"), "REPEATED") {
+			col.Kind = KindArray
+		}
+
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// required reports whether a listed column is declared not null. Columns
+// whose nullability isn't given are not required.
+func required(obj map[string]interface{}) bool {
+	switch v := obj["is_nullable"].(type) {
+	case bool:
+		return !v
+	case string:
+		return strings.EqualFold(v, "NO") || strings.EqualFold(v, "false")
+	}
+	if v, ok := obj["nullable"].(bool); ok {
+		return !v
+	}
+	return strings.EqualFold(stringOf(obj, "mode"), "REQUIRED")
+}
+
+// stringOf returns the first non-empty string found under keys.
+func stringOf(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := obj[key].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,264 @@
+package schematree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paths flattens a tree to "path kind type" lines, parents first.
+func paths(columns []*Column) []string {
+	var out []string
+	for _, c := range columns {
+		out = append(out, c.Path+" "+string(c.Kind)+" "+c.Type)
+		out = append(out, paths(c.Children)...)
+	}
+	return out
+}
+
+func TestBuildSQLColumns(t *testing.T) {
+	sections := Build(map[string]string{
+		"columns": `[
+			{"column_name": "id", "data_type": "bigint", "is_nullable": "NO"},
+			{"column_name": "address", "data_type": "STRUCT<city: STRING, geo: STRUCT<lat: DOUBLE, lon: DOUBLE>>", "is_nullable": "YES"},
+			{"column_name": "lines", "data_type": "array(row(sku varchar, \"unit price\" decimal(10, 2)))"},
+			{"column_name": "attrs", "data_type": "Map(String, Tuple(String, Nullable(UInt8)))"},
+			{"column_name": "tags", "data_type": "text[]"},
+			{"column_name": "note", "data_type": "Nullable(String)", "is_nullable": "NO"}
+		]`,
+		"protobuf": `syntax = "proto3";`,
+	})
+	require.Len(t, sections, 1)
+	assert.Equal(t, "columns", sections[0].Name)
+	assert.Equal(t, FormatColumns, sections[0].Format)
+
+	assert.Equal(t, []string{
+		"id scalar bigint",
+		"address struct STRUCT<city: STRING, geo: STRUCT<lat: DOUBLE, lon: DOUBLE>>",
+		"address.city scalar STRING",
+		"address.geo struct STRUCT<lat: DOUBLE, lon: DOUBLE>",
+		"address.geo.lat scalar DOUBLE",
+		"address.geo.lon scalar DOUBLE",
+		"lines array array(row(sku varchar, \"unit price\" decimal(10, 2)))",
+		"lines.sku scalar varchar",
+		"lines.unit price scalar decimal(10, 2)",
+		"attrs map Map(String, Tuple(String, Nullable(UInt8)))",
+		"attrs.1 scalar String",
+		"attrs.2 scalar Nullable(UInt8)",
+		"tags array text[]",
+		"note scalar Nullable(String)",
+	}, paths(sections[0].Columns))
+
+	columns := sections[0].Columns
+	assert.True(t, columns[0].Required)
+	assert.False(t, columns[1].Required)
+	assert.False(t, columns[5].Required, "Nullable() makes a column optional")
+}
+
+func TestBuildNestedFields(t *testing.T) {
+	sections := Build(map[string]string{
+		"parquet": `[
+			{"column_name": "id", "data_type": "INT64", "is_nullable": false},
+			{"column_name": "lines", "data_type": "ARRAY<STRUCT>", "is_nullable": true, "fields": [
+				{"column_name": "sku", "data_type": "STRING", "is_nullable": false}
+			]}
+		]`,
+		"bigquery": `[
+			{"name": "order", "type": "RECORD", "mode": "REQUIRED", "description": "The order", "fields": [
+				{"name": "id", "type": "STRING", "mode": "REQUIRED"},
+				{"name": "tags", "type": "STRING", "mode": "REPEATED"}
+			]}
+		]`,
+	})
+	require.Len(t, sections, 2)
+
+	assert.Equal(t, "bigquery", sections[0].Name)
+	assert.Equal(t, []string{
+		"order struct RECORD",
+		"order.id scalar STRING",
+		"order.tags array STRING",
+	}, paths(sections[0].Columns))
+	assert.Equal(t, "The order", sections[0].Columns[0].Description)
+	assert.True(t, sections[0].Columns[0].Required)
+
+	assert.Equal(t, []string{
+		"id scalar INT64",
+		"lines array ARRAY<STRUCT>",
+		"lines.sku scalar STRING",
+	}, paths(sections[1].Columns))
+	assert.True(t, sections[1].Columns[1].Children[0].Required)
+}
+
+func TestBuildAvro(t *testing.T) {
+	sections := Build(map[string]string{
+		"orders-value": `{
+			"type": "record", "name": "Order", "namespace": "shop",
+			"fields": [
+				{"name": "id", "type": "string", "doc": "Order ID"},
+				{"name": "placed_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+				{"name": "customer", "type": ["null", {
+					"type": "record", "name": "Customer",
+					"fields": [{"name": "email", "type": "string"}]
+				}]},
+				{"name": "referrer", "type": ["null", "shop.Customer"]},
+				{"name": "lines", "type": {"type": "array", "items": {
+					"type": "record", "name": "Line",
+					"fields": [{"name": "sku", "type": "string"}, {"name": "qty", "type": "int"}]
+				}}},
+				{"name": "attrs", "type": {"type": "map", "values": "string"}},
+				{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW"]}},
+				{"name": "parent", "type": ["null", "Order"]},
+				{"name": "amount", "type": ["int", "double"]}
+			]
+		}`,
+	})
+	require.Len(t, sections, 1)
+	assert.Equal(t, FormatAvro, sections[0].Format)
+	assert.Equal(t, []string{
+		"id scalar string",
+		"placed_at scalar timestamp-millis",
+		"customer struct Customer",
+		"customer.email scalar string",
+		"referrer struct Customer",
+		"referrer.email scalar string",
+		"lines array array<Line>",
+		"lines.sku scalar string",
+		"lines.qty scalar int",
+		"attrs map map<string>",
+		"status scalar enum<Status>",
+		"parent struct Order",
+		"amount scalar union<int, double>",
+	}, paths(sections[0].Columns))
+
+	columns := sections[0].Columns
+	assert.Equal(t, "Order ID", columns[0].Description)
+	assert.True(t, columns[0].Required)
+	assert.False(t, columns[2].Required)
+	assert.Empty(t, columns[7].Children, "recursive records are not expanded")
+}
+
+func TestBuildJSONSchema(t *testing.T) {
+	sections := Build(map[string]string{
+		"json_schema": `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"required": ["id", "shipping"],
+			"properties": {
+				"id": {"type": "string"},
+				"shipping": {"$ref": "#/definitions/Address"},
+				"billing": {"anyOf": [{"type": "null"}, {"$ref": "#/definitions/Address"}]},
+				"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"note": {"type": ["string", "null"], "description": "Free text"},
+				"node": {"$ref": "#/definitions/Node"}
+			},
+			"definitions": {
+				"Address": {"type": "object", "properties": {"city": {"type": "string"}}},
+				"Node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/Node"}}}
+			}
+		}`,
+	})
+	require.Len(t, sections, 1)
+	assert.Equal(t, FormatJSONSchema, sections[0].Format)
+	assert.Equal(t, []string{
+		"billing struct Address",
+		"billing.city scalar string",
+		"id scalar string",
+		"labels map map<string>",
+		"lines array array<object>",
+		"lines.sku scalar string",
+		"node struct Node",
+		"node.next scalar Node",
+		"note scalar string",
+		"shipping struct Address",
+		"shipping.city scalar string",
+	}, paths(sections[0].Columns))
+
+	columns := sections[0].Columns
+	assert.False(t, columns[0].Required)
+	assert.True(t, columns[1].Required)
+	assert.True(t, columns[6].Required)
+	assert.False(t, columns[5].Required)
+	assert.Equal(t, "Free text", columns[5].Description)
+}
+
+func TestParseTypeDepthIsBounded(t *testing.T) {
+	typ := "x"
+	for i := 0; i < 100; i++ {
+		typ = "ARRAY<" + typ + ">"
+	}
+	assert.NotPanics(t, func() { parseType(typ, 0) })
+}
+
+func TestBuildJSONSchema2020(t *testing.T) {
+	sections := Build(map[string]string{
+		"json_schema": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://example.com/order",
+			"allOf": [{"$ref": "#/$defs/Base"}],
+			"properties": {
+				"shipping": {"$ref": "#/$defs/Address", "description": "Where it goes"},
+				"billing": {"$ref": "#billing"},
+				"coords": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}]},
+				"labels": {"type": "object", "unevaluatedProperties": {"type": "string"}},
+				"payment": {
+					"type": "object",
+					"properties": {"method": {"enum": ["card", "bank"]}},
+					"required": ["method"],
+					"if": {"properties": {"method": {"const": "card"}}},
+					"then": {"properties": {"card_number": {"type": "string"}}, "required": ["card_number"]},
+					"else": {"properties": {"iban": {"type": "string"}}}
+				},
+				"gift": {
+					"type": "object",
+					"properties": {"message": {"type": "string"}},
+					"dependentSchemas": {"message": {"properties": {"signature": {"type": "string"}}}}
+				}
+			},
+			"required": ["id", "shipping"],
+			"unevaluatedProperties": false,
+			"$defs": {
+				"Base": {"properties": {"id": {"type": "string"}}, "required": ["id"]},
+				"Address": {"type": "object", "description": "An address", "properties": {"city": {"type": "string"}}},
+				"Billing": {"$anchor": "billing", "type": "object", "properties": {"vat": {"type": "string"}}}
+			}
+		}`,
+	})
+	require.Len(t, sections, 1)
+	assert.Equal(t, FormatJSONSchema, sections[0].Format)
+	assert.Equal(t, []string{
+		"billing struct billing",
+		"billing.vat scalar string",
+		"coords array tuple<number, number>",
+		"coords.0 scalar number",
+		"coords.1 scalar number",
+		"gift struct object",
+		"gift.message scalar string",
+		"gift.signature scalar string",
+		"id scalar string",
+		"labels map map<string>",
+		"payment struct object",
+		"payment.card_number scalar string",
+		"payment.iban scalar string",
+		"payment.method scalar enum",
+		"shipping struct Address",
+		"shipping.city scalar string",
+	}, paths(sections[0].Columns))
+
+	byPath := map[string]*Column{}
+	var index func([]*Column)
+	index = func(columns []*Column) {
+		for _, c := range columns {
+			byPath[c.Path] = c
+			index(c.Children)
+		}
+	}
+	index(sections[0].Columns)
+
+	assert.True(t, byPath["id"].Required, "required from allOf")
+	assert.True(t, byPath["shipping"].Required)
+	assert.Equal(t, "Where it goes", byPath["shipping"].Description, "$ref siblings apply since 2019-09")
+	assert.True(t, byPath["payment.method"].Required)
+	assert.False(t, byPath["payment.card_number"].Required, "conditional properties are optional")
+}
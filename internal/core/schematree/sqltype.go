@@ -0,0 +1,155 @@
+package schematree
+
+import (
+	"strconv"
+	"strings"
+)
+
+// sqlType is a parsed SQL type name. elem is an array's element or a
+// map's value.
+type sqlType struct {
+	kind     Kind
+	fields   []sqlField
+	elem     *sqlType
+	nullable bool
+}
+
+type sqlField struct {
+	name   string
+	typ    string
+	parsed *sqlType
+}
+
+// parseType parses the composite type names of the common dialects:
+// STRUCT<a: INT64>, ARRAY<T> and MAP<K, V> (BigQuery, Spark, Hive, Glue,
+// Databricks), ROW(a INTEGER) and ARRAY(T) (Trino), Tuple(a UInt8),
+// Array(T), Map(K, V), Nested(a UInt8) and Nullable(T) (ClickHouse), and
+// Postgres arrays such as integer[]. Anything else is a scalar.
+func parseType(s string, depth int) *sqlType {
+	s = strings.TrimSpace(s)
+	scalar := &sqlType{kind: KindScalar}
+	if s == "" || depth > maxDepth {
+		return scalar
+	}
+	if strings.HasSuffix(s, "[]") {
+		return &sqlType{kind: KindArray, elem: parseType(s[:len(s)-2], depth+1)}
+	}
+
+	open := strings.IndexAny(s, "<(")
+	if open <= 0 || (s[len(s)-1] != '>' && s[len(s)-1] != ')') {
+		return scalar
+	}
+	head := strings.ToUpper(strings.TrimSpace(s[:open]))
+	args := splitTopLevel(s[open+1 : len(s)-1])
+
+	switch head {
+	case "STRUCT", "ROW", "TUPLE", "RECORD":
+		return &sqlType{kind: KindStruct, fields: parseFields(args, depth)}
+	case "NESTED":
+		return &sqlType{kind: KindArray, elem: &sqlType{kind: KindStruct, fields: parseFields(args, depth)}}
+	case "ARRAY", "LIST":
+		if len(args) == 1 {
+			return &sqlType{kind: KindArray, elem: parseType(args[0], depth+1)}
+		}
+	case "MAP":
+		if len(args) == 2 {
+			return &sqlType{kind: KindMap, elem: parseType(args[1], depth+1)}
+		}
+	case "NULLABLE", "LOWCARDINALITY":
+		if len(args) == 1 {
+			inner := parseType(args[0], depth+1)
+			inner.nullable = inner.nullable || head == "NULLABLE"
+			return inner
+		}
+	}
+	return scalar
+}
+
+// parseFields parses struct fields written as "name type" or "name: type".
+// Unnamed tuple elements are named by their position, from 1.
+func parseFields(args []string, depth int) []sqlField {
+	fields := make([]sqlField, len(args))
+	for i, arg := range args {
+		name, typ := splitField(arg)
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		fields[i] = sqlField{name: name, typ: typ, parsed: parseType(typ, depth+1)}
+	}
+	return fields
+}
+
+// splitField splits a struct field into its name and type. Names may be
+// quoted with backticks or double quotes. A field starting with a type,
+// such as an unnamed tuple element, has no name.
+func splitField(field string) (name, typ string) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return "", ""
+	}
+	if q := field[0]; q == '`' || q == '"' {
+		if end := strings.IndexByte(field[1:], q); end >= 0 {
+			return field[1 : end+1], strings.TrimLeft(field[end+2:], ": \t")
+		}
+	}
+
+	i := strings.IndexAny(field, ": \t(<")
+	if i < 0 || field[i] == '(' || field[i] == '<' {
+		return "", field
+	}
+	return field[:i], strings.TrimLeft(field[i:], ": \t")
+}
+
+// splitTopLevel splits s on the commas that aren't nested in brackets or
+// quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '<' || c == '(':
+			depth++
+		case c == '>' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// columns returns the columns nested in t: a struct's fields, or those of
+// the struct held by an array or map.
+func (t *sqlType) columns(parent string, depth int) []*Column {
+	for t != nil && (t.kind == KindArray || t.kind == KindMap) {
+		t = t.elem
+	}
+	if t == nil || t.kind != KindStruct || depth > maxDepth {
+		return nil
+	}
+
+	columns := make([]*Column, len(t.fields))
+	for i, f := range t.fields {
+		path := joinPath(parent, f.name)
+		columns[i] = &Column{
+			Name:     f.name,
+			Path:     path,
+			Type:     f.typ,
+			Kind:     f.parsed.kind,
+			Children: f.parsed.columns(path, depth+1),
+		}
+	}
+	return columns
+}
@@ -0,0 +1,40 @@
+package scim
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Filter matches resources whose attribute equals a value. Identity
+// providers only filter to look up a resource before creating it, so
+// Marmot supports just the eq operator.
+type Filter struct {
+	// Attribute is the lowercased attribute name, e.g. "username".
+	Attribute string
+	Value     string
+}
+
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9.]*)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+var filterAttributes = map[string]map[string]bool{
+	"User":  {"id": true, "username": true, "externalid": true, "emails.value": true},
+	"Group": {"id": true, "displayname": true, "externalid": true},
+}
+
+// ParseFilter parses a filter on resourceType. An empty filter matches
+// everything and returns nil.
+func ParseFilter(resourceType, filter string) (*Filter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	m := filterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return nil, errInvalidFilter("unsupported filter %q, only attribute eq \"value\" is supported", filter)
+	}
+	attr := strings.ToLower(m[1])
+	if !filterAttributes[resourceType][attr] {
+		return nil, errInvalidFilter("filtering %s by %s is not supported", resourceType, m[1])
+	}
+	value := strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(m[2])
+	return &Filter{Attribute: attr, Value: value}, nil
+}
@@ -0,0 +1,36 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter("User", "")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	f, err = ParseFilter("User", `userName eq "Jane@Example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{Attribute: "username", Value: "Jane@Example.com"}, f)
+
+	f, err = ParseFilter("User", `emails.value EQ "a\"b"`)
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{Attribute: "emails.value", Value: `a"b`}, f)
+
+	f, err = ParseFilter("Group", `displayName eq "Data Platform"`)
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{Attribute: "displayname", Value: "Data Platform"}, f)
+
+	for resourceType, filter := range map[string]string{
+		"User":  `userName co "jane"`,
+		"Group": `userName eq "jane"`,
+	} {
+		_, err := ParseFilter(resourceType, filter)
+		var scimErr *Error
+		require.ErrorAs(t, err, &scimErr, filter)
+		assert.Equal(t, "invalidFilter", scimErr.ScimType)
+	}
+}
@@ -0,0 +1,184 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// memberFilterPath matches paths selecting one group member, as sent by
+// identity providers removing a member, e.g. members[value eq "2819c223"].
+var memberFilterPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]+)"\s*\]$`)
+
+func patchOp(op PatchOperation) (string, error) {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case "add", "replace", "remove":
+		return kind, nil
+	default:
+		return "", errInvalidValue("unsupported patch op %q", op.Op)
+	}
+}
+
+// applyUserPatch applies PATCH operations to a user. Attributes Marmot
+// doesn't store are ignored, as identity providers send many that don't
+// apply, such as titles and addresses.
+func applyUserPatch(u *UserRecord, ops []PatchOperation) error {
+	for _, op := range ops {
+		kind, err := patchOp(op)
+		if err != nil {
+			return err
+		}
+
+		if op.Path == "" {
+			if kind == "remove" {
+				return &Error{Status: 400, ScimType: "noTarget", Detail: "remove requires a path"}
+			}
+			var attrs map[string]json.RawMessage
+			if err := decodeObject(op.Value, &attrs); err != nil {
+				return err
+			}
+			for path, value := range attrs {
+				if err := setUserAttribute(u, path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if kind == "remove" {
+			clearUserAttribute(u, op.Path)
+			continue
+		}
+		if err := setUserAttribute(u, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setUserAttribute(u *UserRecord, path string, value json.RawMessage) error {
+	lower := strings.ToLower(path)
+	switch {
+	case lower == "active":
+		active, err := decodeBool(value)
+		if err != nil {
+			return errInvalidValue("active must be a boolean")
+		}
+		u.Active = active
+	case lower == "username":
+		s, err := decodeString(value)
+		if err != nil || strings.TrimSpace(s) == "" {
+			return errInvalidValue("userName must be a non-empty string")
+		}
+		u.UserName = strings.TrimSpace(s)
+	case lower == "displayname":
+		return decodeInto(value, &u.DisplayName, path)
+	case lower == "externalid":
+		return decodeInto(value, &u.ExternalID, path)
+	case lower == "name":
+		var name Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return errInvalidValue("name must be an object")
+		}
+		u.GivenName, u.FamilyName, u.Formatted = name.GivenName, name.FamilyName, name.Formatted
+	case lower == "name.givenname":
+		return decodeInto(value, &u.GivenName, path)
+	case lower == "name.familyname":
+		return decodeInto(value, &u.FamilyName, path)
+	case lower == "name.formatted":
+		return decodeInto(value, &u.Formatted, path)
+	case lower == "emails":
+		var emails []Email
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return errInvalidValue("emails must be an array")
+		}
+		u.Email = primaryEmail(emails)
+	case strings.HasPrefix(lower, "emails[") && strings.HasSuffix(lower, "].value"):
+		return decodeInto(value, &u.Email, path)
+	}
+	return nil
+}
+
+func clearUserAttribute(u *UserRecord, path string) {
+	lower := strings.ToLower(path)
+	switch {
+	case lower == "displayname":
+		u.DisplayName = ""
+	case lower == "externalid":
+		u.ExternalID = ""
+	case lower == "name":
+		u.GivenName, u.FamilyName, u.Formatted = "", "", ""
+	case lower == "name.givenname":
+		u.GivenName = ""
+	case lower == "name.familyname":
+		u.FamilyName = ""
+	case lower == "name.formatted":
+		u.Formatted = ""
+	case strings.HasPrefix(lower, "emails"):
+		u.Email = ""
+	}
+}
+
+func decodeObject(value json.RawMessage, dst interface{}) error {
+	if err := json.Unmarshal(value, dst); err != nil {
+		return errInvalidValue("patch value must be an object when no path is given")
+	}
+	return nil
+}
+
+func decodeInto(value json.RawMessage, dst *string, path string) error {
+	s, err := decodeString(value)
+	if err != nil {
+		return errInvalidValue("%s must be a string", path)
+	}
+	*dst = s
+	return nil
+}
+
+func decodeString(value json.RawMessage) (string, error) {
+	var s string
+	err := json.Unmarshal(value, &s)
+	return s, err
+}
+
+// decodeBool accepts booleans and, as some identity providers send them,
+// the strings "True" and "False".
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	s, err := decodeString(value)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// decodeMembers reads member references from a patch value, which is an
+// array of references or, from some providers, a single one.
+func decodeMembers(value json.RawMessage) ([]string, error) {
+	var refs []Reference
+	if err := json.Unmarshal(value, &refs); err != nil {
+		var ref Reference
+		if err := json.Unmarshal(value, &ref); err != nil {
+			return nil, errInvalidValue("members must be an array of references")
+		}
+		refs = []Reference{ref}
+	}
+	return memberIDs(refs), nil
+}
+
+func primaryEmail(emails []Email) string {
+	for _, e := range emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Reference points at another resource, such as a group member or one of a
+// user's groups.
+type Reference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is the SCIM representation of a Marmot user.
+type User struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *Name       `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []Email     `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Groups      []Reference `json:"groups,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// Group is the SCIM representation of a Marmot team.
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is a SCIM error. Service methods return it for failures the
+// identity provider should see.
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *Error) Error() string { return e.Detail }
+
+// Response is the SCIM error response body.
+func (e *Error) Response() map[string]interface{} {
+	body := map[string]interface{}{
+		"schemas": []string{SchemaError},
+		"status":  fmt.Sprint(e.Status),
+		"detail":  e.Detail,
+	}
+	if e.ScimType != "" {
+		body["scimType"] = e.ScimType
+	}
+	return body
+}
+
+func errNotFound(resource, id string) *Error {
+	return &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("%s %s not found", resource, id)}
+}
+
+func errUniqueness(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusConflict, ScimType: "uniqueness", Detail: fmt.Sprintf(format, args...)}
+}
+
+func errInvalidValue(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: "invalidValue", Detail: fmt.Sprintf(format, args...)}
+}
+
+func errInvalidFilter(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: "invalidFilter", Detail: fmt.Sprintf(format, args...)}
+}
+
+func errInvalidPath(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: "invalidPath", Detail: fmt.Sprintf(format, args...)}
+}
+
+// ServiceProviderConfig describes the SCIM features Marmot supports.
+func ServiceProviderConfig() map[string]interface{} {
+	unsupported := map[string]bool{"supported": false}
+	return map[string]interface{}{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": MaxCount},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "The token configured as auth.scim.token",
+			"primary":     true,
+		}},
+	}
+}
+
+// ResourceTypes lists the resources the endpoint serves.
+func ResourceTypes(baseURL string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"schemas":  []string{SchemaResourceType},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   SchemaUser,
+			"meta":     map[string]string{"resourceType": "ResourceType", "location": baseURL + "/ResourceTypes/User"},
+		},
+		map[string]interface{}{
+			"schemas":  []string{SchemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   SchemaGroup,
+			"meta":     map[string]string{"resourceType": "ResourceType", "location": baseURL + "/ResourceTypes/Group"},
+		},
+	}
+}
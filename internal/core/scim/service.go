@@ -0,0 +1,688 @@
+// Package scim serves the SCIM 2.0 protocol (RFC 7643, RFC 7644), so
+// identity providers such as Okta and Microsoft Entra ID can provision
+// Marmot users and teams directly.
+//
+// Users SCIM provisions carry a "scim" identity. Deleting one deactivates
+// the Marmot user rather than removing it, so its ownership and history
+// are kept. SCIM groups are teams: a group is linked to the team its SSO
+// mapping names, or a team of the same name, and otherwise a team is
+// created for it. Group members become SSO-managed members of the team.
+package scim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/team"
+	"github.com/marmotdata/marmot/internal/core/user"
+)
+
+const (
+	DefaultCount = 100
+	MaxCount     = 200
+)
+
+var (
+	ErrNotFound = errors.New("scim resource not found")
+	ErrConflict = errors.New("scim resource already exists")
+)
+
+// UserRecord is a SCIM-provisioned user.
+type UserRecord struct {
+	ID          string
+	UserName    string
+	Active      bool
+	ExternalID  string
+	DisplayName string
+	GivenName   string
+	FamilyName  string
+	Formatted   string
+	Email       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// FullName is the name Marmot shows for the user.
+func (u *UserRecord) FullName() string {
+	switch {
+	case u.DisplayName != "":
+		return u.DisplayName
+	case u.Formatted != "":
+		return u.Formatted
+	case u.GivenName != "" || u.FamilyName != "":
+		return strings.TrimSpace(u.GivenName + " " + u.FamilyName)
+	default:
+		return u.UserName
+	}
+}
+
+// GroupRecord is a team linked to a SCIM group.
+type GroupRecord struct {
+	TeamID      string
+	DisplayName string
+	ExternalID  string
+	// OwnsTeam is set when the team was created for the group, so it is
+	// renamed and deleted with it.
+	OwnsTeam  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MemberRecord is a SCIM-provisioned member of a team.
+type MemberRecord struct {
+	UserID   string
+	UserName string
+}
+
+// UserCreator creates Marmot users.
+type UserCreator interface {
+	Create(ctx context.Context, input user.CreateUserInput) (*user.User, error)
+}
+
+// TeamService finds and creates the teams groups are linked to.
+type TeamService interface {
+	GetTeamByName(ctx context.Context, name string) (*team.Team, error)
+	CreateTeamViaSSO(ctx context.Context, provider, groupName string) (*team.Team, error)
+	ListSSOMappings(ctx context.Context, provider string) ([]*team.SSOTeamMapping, error)
+}
+
+type Config struct {
+	// BaseURL is the URL of the SCIM endpoint, e.g.
+	// https://marmot.example.com/scim/v2, used in resource locations.
+	BaseURL string
+	// DefaultRoles are given to users SCIM creates.
+	DefaultRoles []string
+}
+
+type Service struct {
+	repo   Repository
+	users  UserCreator
+	teams  TeamService
+	config Config
+}
+
+func NewService(repo Repository, users UserCreator, teams TeamService, cfg Config) *Service {
+	if len(cfg.DefaultRoles) == 0 {
+		cfg.DefaultRoles = []string{"user"}
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &Service{repo: repo, users: users, teams: teams, config: cfg}
+}
+
+func (s *Service) BaseURL() string {
+	return s.config.BaseURL
+}
+
+// page converts a SCIM startIndex and count to an offset and limit.
+func page(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = DefaultCount
+	}
+	if count > MaxCount {
+		count = MaxCount
+	}
+	return startIndex - 1, count
+}
+
+func listResponse(total, startIndex int, resources []interface{}) *ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// validID reports whether id can name a user or team.
+func validID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func (s *Service) ListUsers(ctx context.Context, filter string, startIndex, count int) (*ListResponse, error) {
+	f, err := ParseFilter("User", filter)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil && f.Attribute == "id" && !validID(f.Value) {
+		return listResponse(0, startIndex, []interface{}{}), nil
+	}
+
+	offset, limit := page(startIndex, count)
+	records, total, err := s.repo.ListUsers(ctx, f, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	resources := make([]interface{}, len(records))
+	for i, r := range records {
+		resources[i] = s.userResource(r, nil)
+	}
+	return listResponse(total, startIndex, resources), nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id string) (*User, error) {
+	if !validID(id) {
+		return nil, errNotFound("User", id)
+	}
+	rec, err := s.repo.GetUser(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, errNotFound("User", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	groups, err := s.repo.ListUserGroups(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("listing user groups: %w", err)
+	}
+	return s.userResource(rec, groups), nil
+}
+
+// CreateUser provisions a user. A Marmot user with the same username that
+// SCIM doesn't yet manage, such as one who signed in with SSO before
+// provisioning was set up, is taken over rather than duplicated.
+func (s *Service) CreateUser(ctx context.Context, in *User) (*User, error) {
+	rec, err := userRecord(in)
+	if err != nil {
+		return nil, err
+	}
+
+	id, linked, err := s.repo.FindUserByUserName(ctx, rec.UserName)
+	switch {
+	case err == nil && linked:
+		return nil, errUniqueness("user %s already exists", rec.UserName)
+	case err == nil:
+		rec.ID = id
+		if err := s.repo.LinkUser(ctx, rec); err != nil {
+			return nil, fmt.Errorf("linking user: %w", err)
+		}
+	case errors.Is(err, ErrNotFound):
+		created, err := s.users.Create(ctx, user.CreateUserInput{
+			Username:        rec.UserName,
+			Name:            rec.FullName(),
+			RoleNames:       s.config.DefaultRoles,
+			OAuthProvider:   user.ProviderSCIM,
+			OAuthProviderID: uuid.NewString(),
+		})
+		switch {
+		case errors.Is(err, user.ErrAlreadyExists):
+			return nil, errUniqueness("user %s already exists", rec.UserName)
+		case errors.Is(err, user.ErrInvalidInput), errors.Is(err, user.ErrReservedUsername):
+			return nil, errInvalidValue("%s", err.Error())
+		case err != nil:
+			return nil, fmt.Errorf("creating user: %w", err)
+		}
+		rec.ID = created.ID
+	default:
+		return nil, fmt.Errorf("finding user: %w", err)
+	}
+
+	if err := s.updateUser(ctx, rec); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, rec.ID)
+}
+
+// ReplaceUser replaces every attribute of a user.
+func (s *Service) ReplaceUser(ctx context.Context, id string, in *User) (*User, error) {
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return nil, err
+	}
+	rec, err := userRecord(in)
+	if err != nil {
+		return nil, err
+	}
+	rec.ID = id
+	if err := s.updateUser(ctx, rec); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, id)
+}
+
+// PatchUser modifies a user. Setting active to false deactivates the user,
+// which signs them out and stops their API keys working.
+func (s *Service) PatchUser(ctx context.Context, id string, req *PatchRequest) (*User, error) {
+	if !validID(id) {
+		return nil, errNotFound("User", id)
+	}
+	rec, err := s.repo.GetUser(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, errNotFound("User", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if err := applyUserPatch(rec, req.Operations); err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(ctx, rec); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, id)
+}
+
+// DeleteUser deprovisions a user. The Marmot user is deactivated and
+// removed from the teams SCIM added them to, but not deleted.
+func (s *Service) DeleteUser(ctx context.Context, id string) error {
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.UnlinkUser(ctx, id); err != nil {
+		return fmt.Errorf("deprovisioning user: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) updateUser(ctx context.Context, rec *UserRecord) error {
+	err := s.repo.UpdateUser(ctx, rec)
+	if errors.Is(err, ErrConflict) {
+		return errUniqueness("user %s already exists", rec.UserName)
+	}
+	if err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+	return nil
+}
+
+func userRecord(in *User) (*UserRecord, error) {
+	if strings.TrimSpace(in.UserName) == "" {
+		return nil, errInvalidValue("userName is required")
+	}
+	rec := &UserRecord{
+		UserName:    strings.TrimSpace(in.UserName),
+		Active:      in.Active == nil || *in.Active,
+		ExternalID:  in.ExternalID,
+		DisplayName: in.DisplayName,
+		Email:       primaryEmail(in.Emails),
+	}
+	if in.Name != nil {
+		rec.GivenName, rec.FamilyName, rec.Formatted = in.Name.GivenName, in.Name.FamilyName, in.Name.Formatted
+	}
+	return rec, nil
+}
+
+func (s *Service) userResource(rec *UserRecord, groups []*GroupRecord) *User {
+	active := rec.Active
+	u := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          rec.ID,
+		ExternalID:  rec.ExternalID,
+		UserName:    rec.UserName,
+		DisplayName: rec.FullName(),
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      rec.CreatedAt,
+			LastModified: rec.UpdatedAt,
+			Location:     s.config.BaseURL + "/Users/" + rec.ID,
+		},
+	}
+	if rec.GivenName != "" || rec.FamilyName != "" || rec.Formatted != "" {
+		u.Name = &Name{GivenName: rec.GivenName, FamilyName: rec.FamilyName, Formatted: rec.Formatted}
+	}
+	if rec.Email != "" {
+		u.Emails = []Email{{Value: rec.Email, Type: "work", Primary: true}}
+	}
+	for _, g := range groups {
+		u.Groups = append(u.Groups, Reference{
+			Value:   g.TeamID,
+			Display: g.DisplayName,
+			Ref:     s.config.BaseURL + "/Groups/" + g.TeamID,
+		})
+	}
+	return u
+}
+
+// ListGroups lists groups, with their members unless excludeMembers is set.
+func (s *Service) ListGroups(ctx context.Context, filter string, startIndex, count int, excludeMembers bool) (*ListResponse, error) {
+	f, err := ParseFilter("Group", filter)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil && f.Attribute == "id" && !validID(f.Value) {
+		return listResponse(0, startIndex, []interface{}{}), nil
+	}
+
+	offset, limit := page(startIndex, count)
+	records, total, err := s.repo.ListGroups(ctx, f, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing groups: %w", err)
+	}
+	resources := make([]interface{}, len(records))
+	for i, r := range records {
+		var members []MemberRecord
+		if !excludeMembers {
+			members, err = s.repo.ListMembers(ctx, r.TeamID)
+			if err != nil {
+				return nil, fmt.Errorf("listing group members: %w", err)
+			}
+		}
+		resources[i] = s.groupResource(r, members)
+	}
+	return listResponse(total, startIndex, resources), nil
+}
+
+func (s *Service) GetGroup(ctx context.Context, id string) (*Group, error) {
+	rec, err := s.getGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("listing group members: %w", err)
+	}
+	return s.groupResource(rec, members), nil
+}
+
+func (s *Service) getGroup(ctx context.Context, id string) (*GroupRecord, error) {
+	if !validID(id) {
+		return nil, errNotFound("Group", id)
+	}
+	rec, err := s.repo.GetGroup(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, errNotFound("Group", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting group: %w", err)
+	}
+	return rec, nil
+}
+
+// CreateGroup provisions a group as a team and syncs its members.
+func (s *Service) CreateGroup(ctx context.Context, in *Group) (*Group, error) {
+	name := strings.TrimSpace(in.DisplayName)
+	if name == "" {
+		return nil, errInvalidValue("displayName is required")
+	}
+
+	rec := &GroupRecord{DisplayName: name, ExternalID: in.ExternalID}
+	teamID, err := s.mappedTeam(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if teamID == "" {
+		existing, err := s.teams.GetTeamByName(ctx, name)
+		switch {
+		case err == nil:
+			teamID = existing.ID
+		case errors.Is(err, team.ErrTeamNotFound):
+			created, err := s.teams.CreateTeamViaSSO(ctx, user.ProviderSCIM, name)
+			if err != nil {
+				return nil, fmt.Errorf("creating team: %w", err)
+			}
+			teamID = created.ID
+		default:
+			return nil, fmt.Errorf("finding team: %w", err)
+		}
+	}
+	rec.TeamID = teamID
+
+	err = s.repo.CreateGroup(ctx, rec)
+	if errors.Is(err, ErrConflict) {
+		return nil, errUniqueness("group %s already exists", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating group: %w", err)
+	}
+
+	if err := s.setMembers(ctx, teamID, memberIDs(in.Members)); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, teamID)
+}
+
+// ReplaceGroup replaces a group's name and members.
+func (s *Service) ReplaceGroup(ctx context.Context, id string, in *Group) (*Group, error) {
+	rec, err := s.getGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.DisplayName)
+	if name == "" {
+		return nil, errInvalidValue("displayName is required")
+	}
+	rec.DisplayName, rec.ExternalID = name, in.ExternalID
+	if err := s.updateGroup(ctx, rec); err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, id, memberIDs(in.Members)); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// PatchGroup renames a group or adds and removes members.
+func (s *Service) PatchGroup(ctx context.Context, id string, req *PatchRequest) (*Group, error) {
+	rec, err := s.getGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	for _, op := range req.Operations {
+		kind, err := patchOp(op)
+		if err != nil {
+			return nil, err
+		}
+
+		path := strings.ToLower(op.Path)
+		if m := memberFilterPath.FindStringSubmatch(op.Path); m != nil {
+			if kind != "remove" {
+				return nil, errInvalidPath("only remove is supported on %s", op.Path)
+			}
+			if err := s.repo.RemoveMembers(ctx, id, validIDs([]string{m[1]})); err != nil {
+				return nil, fmt.Errorf("removing members: %w", err)
+			}
+			continue
+		}
+
+		switch path {
+		case "":
+			if kind == "remove" {
+				return nil, &Error{Status: 400, ScimType: "noTarget", Detail: "remove requires a path"}
+			}
+			var value struct {
+				DisplayName *string     `json:"displayName"`
+				ExternalID  *string     `json:"externalId"`
+				Members     []Reference `json:"members"`
+			}
+			if err := decodeObject(op.Value, &value); err != nil {
+				return nil, err
+			}
+			if value.DisplayName != nil {
+				rec.DisplayName, renamed = strings.TrimSpace(*value.DisplayName), true
+			}
+			if value.ExternalID != nil {
+				rec.ExternalID, renamed = *value.ExternalID, true
+			}
+			if value.Members != nil {
+				if err := s.patchMembers(ctx, id, kind, memberIDs(value.Members)); err != nil {
+					return nil, err
+				}
+			}
+		case "displayname":
+			if kind == "remove" {
+				return nil, errInvalidValue("displayName is required")
+			}
+			if err := decodeInto(op.Value, &rec.DisplayName, op.Path); err != nil {
+				return nil, err
+			}
+			rec.DisplayName, renamed = strings.TrimSpace(rec.DisplayName), true
+		case "externalid":
+			rec.ExternalID, renamed = "", true
+			if kind != "remove" {
+				if err := decodeInto(op.Value, &rec.ExternalID, op.Path); err != nil {
+					return nil, err
+				}
+			}
+		case "members":
+			var ids []string
+			if len(op.Value) > 0 {
+				if ids, err = decodeMembers(op.Value); err != nil {
+					return nil, err
+				}
+			}
+			if kind == "remove" && len(op.Value) == 0 {
+				// Removing the attribute removes every member.
+				kind, ids = "replace", nil
+			}
+			if err := s.patchMembers(ctx, id, kind, ids); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errInvalidPath("unsupported path %q", op.Path)
+		}
+	}
+
+	if renamed {
+		if rec.DisplayName == "" {
+			return nil, errInvalidValue("displayName is required")
+		}
+		if err := s.updateGroup(ctx, rec); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetGroup(ctx, id)
+}
+
+func (s *Service) patchMembers(ctx context.Context, teamID, kind string, ids []string) error {
+	switch kind {
+	case "add":
+		role, err := s.memberRole(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.AddMembers(ctx, teamID, validIDs(ids), role); err != nil {
+			return fmt.Errorf("adding members: %w", err)
+		}
+	case "remove":
+		if err := s.repo.RemoveMembers(ctx, teamID, validIDs(ids)); err != nil {
+			return fmt.Errorf("removing members: %w", err)
+		}
+	default:
+		return s.setMembers(ctx, teamID, ids)
+	}
+	return nil
+}
+
+// DeleteGroup deprovisions a group. Its SCIM-managed members are removed
+// from the team, and a team created for the group is deleted.
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	if _, err := s.getGroup(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteGroup(ctx, id); err != nil {
+		return fmt.Errorf("deleting group: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) updateGroup(ctx context.Context, rec *GroupRecord) error {
+	err := s.repo.UpdateGroup(ctx, rec)
+	if errors.Is(err, ErrConflict) {
+		return errUniqueness("group %s already exists", rec.DisplayName)
+	}
+	if err != nil {
+		return fmt.Errorf("updating group: %w", err)
+	}
+	return nil
+}
+
+// setMembers makes ids the SCIM-managed members of a team.
+func (s *Service) setMembers(ctx context.Context, teamID string, ids []string) error {
+	role, err := s.memberRole(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetMembers(ctx, teamID, validIDs(ids), role); err != nil {
+		return fmt.Errorf("syncing members: %w", err)
+	}
+	return nil
+}
+
+// mappedTeam is the team an SSO mapping for the scim provider links a group
+// name to, if there is one.
+func (s *Service) mappedTeam(ctx context.Context, groupName string) (string, error) {
+	mappings, err := s.teams.ListSSOMappings(ctx, user.ProviderSCIM)
+	if err != nil {
+		return "", fmt.Errorf("listing SSO mappings: %w", err)
+	}
+	for _, m := range mappings {
+		if m.SSOGroupName == groupName {
+			return m.TeamID, nil
+		}
+	}
+	return "", nil
+}
+
+// memberRole is the role of members SCIM adds to a team: the member role
+// of the team's SSO mapping, or member.
+func (s *Service) memberRole(ctx context.Context, teamID string) (string, error) {
+	mappings, err := s.teams.ListSSOMappings(ctx, user.ProviderSCIM)
+	if err != nil {
+		return "", fmt.Errorf("listing SSO mappings: %w", err)
+	}
+	for _, m := range mappings {
+		if m.TeamID == teamID {
+			return m.MemberRole, nil
+		}
+	}
+	return team.RoleMember, nil
+}
+
+func (s *Service) groupResource(rec *GroupRecord, members []MemberRecord) *Group {
+	g := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          rec.TeamID,
+		ExternalID:  rec.ExternalID,
+		DisplayName: rec.DisplayName,
+		Members:     make([]Reference, len(members)),
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      rec.CreatedAt,
+			LastModified: rec.UpdatedAt,
+			Location:     s.config.BaseURL + "/Groups/" + rec.TeamID,
+		},
+	}
+	for i, m := range members {
+		g.Members[i] = Reference{
+			Value:   m.UserID,
+			Display: m.UserName,
+			Ref:     s.config.BaseURL + "/Users/" + m.UserID,
+		}
+	}
+	return g
+}
+
+func memberIDs(refs []Reference) []string {
+	ids := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if r.Value != "" {
+			ids = append(ids, r.Value)
+		}
+	}
+	return ids
+}
+
+// validIDs drops IDs that can't name a user. Members that aren't
+// provisioned users are ignored, like unknown attributes.
+func validIDs(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if validID(id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
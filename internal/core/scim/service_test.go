@@ -0,0 +1,342 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/team"
+	"github.com/marmotdata/marmot/internal/core/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	users    map[string]*UserRecord
+	unlinked map[string]string // userName -> id of users SCIM doesn't manage
+	groups   map[string]*GroupRecord
+	members  map[string]map[string]string // teamID -> userID -> role
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users:    make(map[string]*UserRecord),
+		unlinked: make(map[string]string),
+		groups:   make(map[string]*GroupRecord),
+		members:  make(map[string]map[string]string),
+	}
+}
+
+func (f *fakeRepo) GetUser(_ context.Context, id string) (*UserRecord, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func (f *fakeRepo) ListUsers(_ context.Context, filter *Filter, offset, limit int) ([]*UserRecord, int, error) {
+	var out []*UserRecord
+	for _, u := range f.users {
+		if filter == nil || (filter.Attribute == "username" && u.UserName == filter.Value) {
+			out = append(out, u)
+		}
+	}
+	return out, len(out), nil
+}
+
+func (f *fakeRepo) FindUserByUserName(_ context.Context, userName string) (string, bool, error) {
+	for _, u := range f.users {
+		if u.UserName == userName {
+			return u.ID, true, nil
+		}
+	}
+	if id, ok := f.unlinked[userName]; ok {
+		return id, false, nil
+	}
+	return "", false, ErrNotFound
+}
+
+func (f *fakeRepo) LinkUser(_ context.Context, rec *UserRecord) error {
+	delete(f.unlinked, rec.UserName)
+	f.users[rec.ID] = &UserRecord{ID: rec.ID, UserName: rec.UserName}
+	return nil
+}
+
+func (f *fakeRepo) UpdateUser(_ context.Context, rec *UserRecord) error {
+	for _, u := range f.users {
+		if u.ID != rec.ID && u.UserName == rec.UserName {
+			return ErrConflict
+		}
+	}
+	cp := *rec
+	f.users[rec.ID] = &cp
+	return nil
+}
+
+func (f *fakeRepo) UnlinkUser(_ context.Context, id string) error {
+	f.unlinked[f.users[id].UserName] = id
+	delete(f.users, id)
+	for _, m := range f.members {
+		delete(m, id)
+	}
+	return nil
+}
+
+func (f *fakeRepo) ListUserGroups(_ context.Context, id string) ([]*GroupRecord, error) {
+	var out []*GroupRecord
+	for teamID, m := range f.members {
+		if _, ok := m[id]; ok {
+			out = append(out, f.groups[teamID])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetGroup(_ context.Context, teamID string) (*GroupRecord, error) {
+	g, ok := f.groups[teamID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *g
+	return &cp, nil
+}
+
+func (f *fakeRepo) ListGroups(_ context.Context, _ *Filter, _, _ int) ([]*GroupRecord, int, error) {
+	var out []*GroupRecord
+	for _, g := range f.groups {
+		out = append(out, g)
+	}
+	return out, len(out), nil
+}
+
+func (f *fakeRepo) CreateGroup(_ context.Context, rec *GroupRecord) error {
+	if _, ok := f.groups[rec.TeamID]; ok {
+		return ErrConflict
+	}
+	cp := *rec
+	f.groups[rec.TeamID] = &cp
+	return nil
+}
+
+func (f *fakeRepo) UpdateGroup(_ context.Context, rec *GroupRecord) error {
+	cp := *rec
+	f.groups[rec.TeamID] = &cp
+	return nil
+}
+
+func (f *fakeRepo) DeleteGroup(_ context.Context, teamID string) error {
+	delete(f.groups, teamID)
+	delete(f.members, teamID)
+	return nil
+}
+
+func (f *fakeRepo) ListMembers(_ context.Context, teamID string) ([]MemberRecord, error) {
+	var out []MemberRecord
+	for id := range f.members[teamID] {
+		out = append(out, MemberRecord{UserID: id, UserName: f.users[id].UserName})
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) AddMembers(_ context.Context, teamID string, userIDs []string, role string) error {
+	if f.members[teamID] == nil {
+		f.members[teamID] = make(map[string]string)
+	}
+	for _, id := range userIDs {
+		if _, ok := f.users[id]; ok {
+			f.members[teamID][id] = role
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) RemoveMembers(_ context.Context, teamID string, userIDs []string) error {
+	for _, id := range userIDs {
+		delete(f.members[teamID], id)
+	}
+	return nil
+}
+
+func (f *fakeRepo) SetMembers(ctx context.Context, teamID string, userIDs []string, role string) error {
+	f.members[teamID] = make(map[string]string)
+	return f.AddMembers(ctx, teamID, userIDs, role)
+}
+
+type fakeUsers struct {
+	created []user.CreateUserInput
+}
+
+func (f *fakeUsers) Create(_ context.Context, input user.CreateUserInput) (*user.User, error) {
+	f.created = append(f.created, input)
+	return &user.User{ID: uuid.NewString(), Username: input.Username}, nil
+}
+
+type fakeTeams struct {
+	teams    map[string]*team.Team
+	mappings []*team.SSOTeamMapping
+}
+
+func (f *fakeTeams) GetTeamByName(_ context.Context, name string) (*team.Team, error) {
+	if t, ok := f.teams[name]; ok {
+		return t, nil
+	}
+	return nil, team.ErrTeamNotFound
+}
+
+func (f *fakeTeams) CreateTeamViaSSO(_ context.Context, provider, groupName string) (*team.Team, error) {
+	t := &team.Team{ID: uuid.NewString(), Name: groupName, CreatedViaSSO: true, SSOProvider: &provider}
+	f.teams[groupName] = t
+	return t, nil
+}
+
+func (f *fakeTeams) ListSSOMappings(_ context.Context, provider string) ([]*team.SSOTeamMapping, error) {
+	var out []*team.SSOTeamMapping
+	for _, m := range f.mappings {
+		if m.Provider == provider {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func newTestService() (*Service, *fakeRepo, *fakeUsers, *fakeTeams) {
+	repo := newFakeRepo()
+	users := &fakeUsers{}
+	teams := &fakeTeams{teams: make(map[string]*team.Team)}
+	svc := NewService(repo, users, teams, Config{BaseURL: "https://marmot.example.com/scim/v2/"})
+	return svc, repo, users, teams
+}
+
+func patch(ops ...PatchOperation) *PatchRequest {
+	return &PatchRequest{Schemas: []string{SchemaPatchOp}, Operations: ops}
+}
+
+func scimStatus(t *testing.T, err error) int {
+	t.Helper()
+	var scimErr *Error
+	require.ErrorAs(t, err, &scimErr)
+	return scimErr.Status
+}
+
+func TestService_UserLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, users, _ := newTestService()
+
+	created, err := svc.CreateUser(ctx, &User{
+		UserName: "jane@example.com",
+		Name:     &Name{GivenName: "Jane", FamilyName: "Doe"},
+		Emails:   []Email{{Value: "jane.doe@example.com", Primary: true}},
+	})
+	require.NoError(t, err)
+	require.Len(t, users.created, 1)
+	assert.Equal(t, user.ProviderSCIM, users.created[0].OAuthProvider)
+	assert.Equal(t, "Jane Doe", users.created[0].Name)
+	assert.Equal(t, []string{"user"}, users.created[0].RoleNames)
+	assert.True(t, *created.Active)
+	assert.Equal(t, "https://marmot.example.com/scim/v2/Users/"+created.ID, created.Meta.Location)
+
+	_, err = svc.CreateUser(ctx, &User{UserName: "jane@example.com"})
+	assert.Equal(t, http.StatusConflict, scimStatus(t, err))
+
+	patched, err := svc.PatchUser(ctx, created.ID, patch(
+		PatchOperation{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		PatchOperation{Op: "replace", Value: json.RawMessage(`{"displayName":"Jane D.","title":"Engineer"}`)},
+	))
+	require.NoError(t, err)
+	assert.False(t, *patched.Active)
+	assert.Equal(t, "Jane D.", patched.DisplayName)
+	assert.Equal(t, "jane.doe@example.com", patched.Emails[0].Value)
+
+	require.NoError(t, svc.DeleteUser(ctx, created.ID))
+	_, err = svc.GetUser(ctx, created.ID)
+	assert.Equal(t, http.StatusNotFound, scimStatus(t, err))
+
+	// Provisioning the user again takes over the deactivated account.
+	again, err := svc.CreateUser(ctx, &User{UserName: "jane@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, again.ID)
+	assert.True(t, *again.Active)
+	assert.Len(t, users.created, 1)
+	assert.True(t, repo.users[created.ID].Active)
+
+	_, err = svc.GetUser(ctx, "not-a-uuid")
+	assert.Equal(t, http.StatusNotFound, scimStatus(t, err))
+	_, err = svc.PatchUser(ctx, created.ID, patch(PatchOperation{Op: "move", Path: "active"}))
+	assert.Equal(t, http.StatusBadRequest, scimStatus(t, err))
+}
+
+func TestService_GroupMembership(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, teams := newTestService()
+
+	var ids []string
+	for _, name := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		u, err := svc.CreateUser(ctx, &User{UserName: name})
+		require.NoError(t, err)
+		ids = append(ids, u.ID)
+	}
+
+	g, err := svc.CreateGroup(ctx, &Group{
+		DisplayName: "Data Platform",
+		Members:     []Reference{{Value: ids[0]}, {Value: ids[1]}, {Value: "unknown"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, teams.teams, "Data Platform")
+	assert.Equal(t, teams.teams["Data Platform"].ID, g.ID)
+	assert.Len(t, g.Members, 2)
+	assert.Equal(t, team.RoleMember, repo.members[g.ID][ids[0]])
+
+	u, err := svc.GetUser(ctx, ids[0])
+	require.NoError(t, err)
+	require.Len(t, u.Groups, 1)
+	assert.Equal(t, "Data Platform", u.Groups[0].Display)
+
+	g, err = svc.PatchGroup(ctx, g.ID, patch(
+		PatchOperation{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + ids[2] + `"}]`)},
+		PatchOperation{Op: "remove", Path: `members[value eq "` + ids[0] + `"]`},
+		PatchOperation{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Data Platform Team"`)},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, "Data Platform Team", g.DisplayName)
+	assert.ElementsMatch(t, []string{ids[1], ids[2]}, memberValues(g.Members))
+
+	g, err = svc.PatchGroup(ctx, g.ID, patch(PatchOperation{Op: "remove", Path: "members"}))
+	require.NoError(t, err)
+	assert.Empty(t, g.Members)
+
+	_, err = svc.CreateGroup(ctx, &Group{DisplayName: "Data Platform"})
+	assert.Equal(t, http.StatusConflict, scimStatus(t, err))
+
+	require.NoError(t, svc.DeleteGroup(ctx, g.ID))
+	_, err = svc.GetGroup(ctx, g.ID)
+	assert.Equal(t, http.StatusNotFound, scimStatus(t, err))
+}
+
+func TestService_CreateGroupUsesSSOMapping(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, teams := newTestService()
+	teams.mappings = []*team.SSOTeamMapping{
+		{Provider: user.ProviderSCIM, SSOGroupName: "okta-data-eng", TeamID: uuid.NewString(), MemberRole: team.RoleOwner},
+	}
+
+	u, err := svc.CreateUser(ctx, &User{UserName: "a@example.com"})
+	require.NoError(t, err)
+
+	g, err := svc.CreateGroup(ctx, &Group{DisplayName: "okta-data-eng", Members: []Reference{{Value: u.ID}}})
+	require.NoError(t, err)
+	assert.Equal(t, teams.mappings[0].TeamID, g.ID)
+	assert.Empty(t, teams.teams)
+	assert.Equal(t, team.RoleOwner, repo.members[g.ID][u.ID])
+}
+
+func memberValues(refs []Reference) []string {
+	out := make([]string, len(refs))
+	for i, r := range refs {
+		out[i] = r.Value
+	}
+	return out
+}
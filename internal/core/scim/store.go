@@ -0,0 +1,496 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	GetUser(ctx context.Context, id string) (*UserRecord, error)
+	ListUsers(ctx context.Context, filter *Filter, offset, limit int) ([]*UserRecord, int, error)
+	// FindUserByUserName finds a Marmot user by username, reporting whether
+	// SCIM already manages them.
+	FindUserByUserName(ctx context.Context, userName string) (id string, linked bool, err error)
+	LinkUser(ctx context.Context, rec *UserRecord) error
+	UpdateUser(ctx context.Context, rec *UserRecord) error
+	// UnlinkUser deactivates a user and drops their SCIM identity and
+	// SCIM-managed team memberships.
+	UnlinkUser(ctx context.Context, id string) error
+	ListUserGroups(ctx context.Context, id string) ([]*GroupRecord, error)
+
+	GetGroup(ctx context.Context, teamID string) (*GroupRecord, error)
+	ListGroups(ctx context.Context, filter *Filter, offset, limit int) ([]*GroupRecord, int, error)
+	CreateGroup(ctx context.Context, rec *GroupRecord) error
+	UpdateGroup(ctx context.Context, rec *GroupRecord) error
+	DeleteGroup(ctx context.Context, teamID string) error
+
+	ListMembers(ctx context.Context, teamID string) ([]MemberRecord, error)
+	AddMembers(ctx context.Context, teamID string, userIDs []string, role string) error
+	RemoveMembers(ctx context.Context, teamID string, userIDs []string) error
+	SetMembers(ctx context.Context, teamID string, userIDs []string, role string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// userProfile holds the SCIM attributes the users table has no columns
+// for. It is stored as the provider data of the user's scim identity.
+type userProfile struct {
+	ExternalID  string `json:"external_id,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	GivenName   string `json:"given_name,omitempty"`
+	FamilyName  string `json:"family_name,omitempty"`
+	Formatted   string `json:"formatted,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+const userColumns = `
+	SELECT u.id, u.username, u.active, i.provider_data, u.created_at, u.updated_at
+	FROM users u
+	JOIN user_identities i ON i.user_id = u.id AND i.provider = 'scim'`
+
+var userFilterColumns = map[string]string{
+	"id":           "u.id = $1::uuid",
+	"username":     "LOWER(u.username) = LOWER($1)",
+	"externalid":   "i.provider_data->>'external_id' = $1",
+	"emails.value": "LOWER(i.provider_data->>'email') = LOWER($1)",
+}
+
+func scanUser(row pgx.Row) (*UserRecord, error) {
+	var (
+		rec     UserRecord
+		data    []byte
+		profile userProfile
+	)
+	if err := row.Scan(&rec.ID, &rec.UserName, &rec.Active, &data, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return nil, fmt.Errorf("unmarshaling provider data: %w", err)
+		}
+	}
+	rec.ExternalID = profile.ExternalID
+	rec.DisplayName = profile.DisplayName
+	rec.GivenName = profile.GivenName
+	rec.FamilyName = profile.FamilyName
+	rec.Formatted = profile.Formatted
+	rec.Email = profile.Email
+	return &rec, nil
+}
+
+func (r *PostgresRepository) GetUser(ctx context.Context, id string) (*UserRecord, error) {
+	rec, err := scanUser(r.db.QueryRow(ctx, userColumns+` WHERE u.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return rec, nil
+}
+
+func (r *PostgresRepository) ListUsers(ctx context.Context, filter *Filter, offset, limit int) ([]*UserRecord, int, error) {
+	where, args := filterClause(userFilterColumns, filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM users u JOIN user_identities i ON i.user_id = u.id AND i.provider = 'scim'` + where
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	if limit == 0 {
+		return []*UserRecord{}, total, nil
+	}
+
+	query := fmt.Sprintf(`%s%s ORDER BY u.created_at, u.id LIMIT %d OFFSET %d`, userColumns, where, limit, offset)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*UserRecord{}
+	for rows.Next() {
+		rec, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, total, rows.Err()
+}
+
+func (r *PostgresRepository) FindUserByUserName(ctx context.Context, userName string) (string, bool, error) {
+	query := `
+		SELECT u.id, EXISTS (
+			SELECT 1 FROM user_identities i WHERE i.user_id = u.id AND i.provider = 'scim'
+		)
+		FROM users u
+		WHERE LOWER(u.username) = LOWER($1)
+		ORDER BY u.username = $1 DESC
+		LIMIT 1`
+
+	var (
+		id     string
+		linked bool
+	)
+	err := r.db.QueryRow(ctx, query, userName).Scan(&id, &linked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, ErrNotFound
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to find user: %w", err)
+	}
+	return id, linked, nil
+}
+
+func (r *PostgresRepository) LinkUser(ctx context.Context, rec *UserRecord) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, provider_user_id, provider_email)
+		VALUES ($1, 'scim', $1::text, $2)
+		ON CONFLICT (provider, provider_user_id) DO NOTHING`
+
+	if _, err := r.db.Exec(ctx, query, rec.ID, rec.UserName); err != nil {
+		return fmt.Errorf("failed to link user: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateUser(ctx context.Context, rec *UserRecord) error {
+	data, err := json.Marshal(userProfile{
+		ExternalID:  rec.ExternalID,
+		DisplayName: rec.DisplayName,
+		GivenName:   rec.GivenName,
+		FamilyName:  rec.FamilyName,
+		Formatted:   rec.Formatted,
+		Email:       rec.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling provider data: %w", err)
+	}
+	email := rec.Email
+	if email == "" {
+		email = rec.UserName
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users SET username = $2, name = $3, active = $4, updated_at = NOW()
+		WHERE id = $1`,
+		rec.ID, rec.UserName, rec.FullName(), rec.Active)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_identities SET provider_email = $2, provider_data = $3, updated_at = NOW()
+		WHERE user_id = $1 AND provider = 'scim'`,
+		rec.ID, email, data); err != nil {
+		return fmt.Errorf("failed to update identity: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) UnlinkUser(ctx context.Context, id string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		`UPDATE users SET active = FALSE, updated_at = NOW() WHERE id = $1`,
+		`DELETE FROM team_members WHERE user_id = $1 AND source = 'sso' AND sso_provider = 'scim'`,
+		`DELETE FROM user_identities WHERE user_id = $1 AND provider = 'scim'`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt, id); err != nil {
+			return fmt.Errorf("failed to unlink user: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) ListUserGroups(ctx context.Context, id string) ([]*GroupRecord, error) {
+	query := groupColumns + `
+		JOIN team_members m ON m.team_id = g.team_id
+		WHERE m.user_id = $1
+		ORDER BY g.display_name`
+
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups: %w", err)
+	}
+	defer rows.Close()
+	return scanGroups(rows)
+}
+
+const groupColumns = `
+	SELECT g.team_id, g.display_name, COALESCE(g.external_id, ''),
+		t.created_via_sso AND COALESCE(t.sso_provider, '') = 'scim',
+		g.created_at, g.updated_at
+	FROM scim_groups g
+	JOIN teams t ON t.id = g.team_id`
+
+var groupFilterColumns = map[string]string{
+	"id":          "g.team_id = $1::uuid",
+	"displayname": "LOWER(g.display_name) = LOWER($1)",
+	"externalid":  "g.external_id = $1",
+}
+
+func scanGroup(row pgx.Row) (*GroupRecord, error) {
+	var rec GroupRecord
+	err := row.Scan(&rec.TeamID, &rec.DisplayName, &rec.ExternalID, &rec.OwnsTeam, &rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func scanGroups(rows pgx.Rows) ([]*GroupRecord, error) {
+	records := []*GroupRecord{}
+	for rows.Next() {
+		rec, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+func (r *PostgresRepository) GetGroup(ctx context.Context, teamID string) (*GroupRecord, error) {
+	rec, err := scanGroup(r.db.QueryRow(ctx, groupColumns+` WHERE g.team_id = $1`, teamID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	return rec, nil
+}
+
+func (r *PostgresRepository) ListGroups(ctx context.Context, filter *Filter, offset, limit int) ([]*GroupRecord, int, error) {
+	where, args := filterClause(groupFilterColumns, filter)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM scim_groups g`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count groups: %w", err)
+	}
+	if limit == 0 {
+		return []*GroupRecord{}, total, nil
+	}
+
+	query := fmt.Sprintf(`%s%s ORDER BY g.created_at, g.team_id LIMIT %d OFFSET %d`, groupColumns, where, limit, offset)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := scanGroups(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+func (r *PostgresRepository) CreateGroup(ctx context.Context, rec *GroupRecord) error {
+	query := `
+		INSERT INTO scim_groups (team_id, display_name, external_id)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRow(ctx, query, rec.TeamID, rec.DisplayName, rec.ExternalID).Scan(&rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	return nil
+}
+
+// UpdateGroup updates a group and, if the team was created for it, renames
+// the team to match.
+func (r *PostgresRepository) UpdateGroup(ctx context.Context, rec *GroupRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE scim_groups SET display_name = $2, external_id = NULLIF($3, ''), updated_at = NOW()
+		WHERE team_id = $1`,
+		rec.TeamID, rec.DisplayName, rec.ExternalID); err != nil {
+		return groupUpdateError(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE teams SET name = $2, updated_at = NOW()
+		WHERE id = $1 AND created_via_sso AND sso_provider = 'scim' AND name <> $2`,
+		rec.TeamID, rec.DisplayName); err != nil {
+		return groupUpdateError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func groupUpdateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrConflict
+	}
+	return fmt.Errorf("failed to update group: %w", err)
+}
+
+// DeleteGroup unlinks a group from its team, removing the team's
+// SCIM-managed members, and deletes the team if it was created for the
+// group.
+func (r *PostgresRepository) DeleteGroup(ctx context.Context, teamID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		`DELETE FROM team_members WHERE team_id = $1 AND source = 'sso' AND sso_provider = 'scim'`,
+		`DELETE FROM scim_groups WHERE team_id = $1`,
+		`DELETE FROM teams WHERE id = $1 AND created_via_sso AND sso_provider = 'scim'`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt, teamID); err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListMembers lists the team's members that SCIM manages. Members added in
+// Marmot are included if they are provisioned users, since identity
+// providers reconcile against the full list.
+func (r *PostgresRepository) ListMembers(ctx context.Context, teamID string) ([]MemberRecord, error) {
+	query := `
+		SELECT u.id, u.username
+		FROM team_members m
+		JOIN users u ON u.id = m.user_id
+		JOIN user_identities i ON i.user_id = u.id AND i.provider = 'scim'
+		WHERE m.team_id = $1
+		ORDER BY u.username`
+
+	rows, err := r.db.Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []MemberRecord{}
+	for rows.Next() {
+		var m MemberRecord
+		if err := rows.Scan(&m.UserID, &m.UserName); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// addMembersQuery adds provisioned users to a team. Users who are already
+// members keep their membership, so a manual member isn't turned into an
+// SSO-managed one.
+const addMembersQuery = `
+	INSERT INTO team_members (team_id, user_id, role, source, sso_provider)
+	SELECT $1, i.user_id, $3, 'sso', 'scim'
+	FROM user_identities i
+	WHERE i.provider = 'scim' AND i.user_id = ANY($2::uuid[])
+	ON CONFLICT (team_id, user_id) DO NOTHING`
+
+func (r *PostgresRepository) AddMembers(ctx context.Context, teamID string, userIDs []string, role string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if _, err := r.db.Exec(ctx, addMembersQuery, teamID, userIDs, role); err != nil {
+		return fmt.Errorf("failed to add members: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) RemoveMembers(ctx context.Context, teamID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	query := `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND user_id = ANY($2::uuid[]) AND source = 'sso' AND sso_provider = 'scim'`
+
+	if _, err := r.db.Exec(ctx, query, teamID, userIDs); err != nil {
+		return fmt.Errorf("failed to remove members: %w", err)
+	}
+	return nil
+}
+
+// SetMembers makes userIDs the team's SCIM-managed members with role.
+func (r *PostgresRepository) SetMembers(ctx context.Context, teamID string, userIDs []string, role string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND source = 'sso' AND sso_provider = 'scim' AND NOT (user_id = ANY($2::uuid[]))`,
+		teamID, userIDs); err != nil {
+		return fmt.Errorf("failed to remove members: %w", err)
+	}
+	if _, err := tx.Exec(ctx, addMembersQuery, teamID, userIDs, role); err != nil {
+		return fmt.Errorf("failed to add members: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE team_members SET role = $2
+		WHERE team_id = $1 AND source = 'sso' AND sso_provider = 'scim' AND role <> $2`,
+		teamID, role); err != nil {
+		return fmt.Errorf("failed to update member roles: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func filterClause(columns map[string]string, filter *Filter) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+	return " WHERE " + columns[filter.Attribute], []interface{}{filter.Value}
+}
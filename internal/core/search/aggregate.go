@@ -0,0 +1,246 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/query"
+)
+
+const (
+	// DefaultAggregationLimit is the number of buckets returned per facet
+	// when the caller does not ask for a specific limit.
+	DefaultAggregationLimit = 100
+
+	// MaxAggregationLimit caps buckets per facet. Aggregation-only requests
+	// skip hit retrieval so they can afford much larger facets than Search.
+	MaxAggregationLimit = 1000
+
+	// MaxAggregationGroups caps the number of custom metadata groupings per request.
+	MaxAggregationGroups = 5
+)
+
+// ErrInvalidAggregation is returned for an aggregation filter that fails
+// validation.
+var ErrInvalidAggregation = errors.New("invalid aggregation filter")
+
+// AggregationFilter describes an aggregation-only search. It accepts the same
+// scoping options as Filter but returns facet counts instead of hits.
+type AggregationFilter struct {
+	Query      string       `json:"query" validate:"omitempty,max=256"`
+	Types      []ResultType `json:"types,omitempty"`
+	AssetTypes []string     `json:"asset_types,omitempty"`
+	Providers  []string     `json:"providers,omitempty"`
+	Tags       []string     `json:"tags,omitempty"`
+	GroupBy    []string     `json:"group_by,omitempty" validate:"omitempty,max=5,dive,required,max=128"`
+	FacetLimit int          `json:"facet_limit" validate:"omitempty,gte=1,lte=1000"`
+
+	// AssetIDs scopes the aggregation like Filter.AssetIDs.
+	AssetIDs []string `json:"asset_ids,omitempty"`
+
+	// Environments and IncludeStubs scope the aggregation like the Filter
+	// fields of the same name.
+	Environments []string `json:"environments,omitempty"`
+	IncludeStubs bool     `json:"include_stubs,omitempty"`
+}
+
+// AggregationResponse carries facet counts for an aggregation-only search.
+// Groups is keyed by the metadata field path requested in GroupBy.
+type AggregationResponse struct {
+	Total  int                     `json:"total"`
+	Facets *Facets                 `json:"facets"`
+	Groups map[string][]FacetValue `json:"groups,omitempty"`
+} // @name SearchAggregationResponse
+
+// Aggregate computes facet counts for everything matching the filter without
+// fetching or ranking hits. Unlike Search it always computes facets, even for
+// text queries, because that is the whole point of the request.
+func (r *PostgresRepository) Aggregate(ctx context.Context, filter AggregationFilter) (int, *Facets, map[string][]FacetValue, error) {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultSearchTimeout)
+	defer cancel()
+
+	kindFilters := extractKindFilters(filter.Query)
+	if len(kindFilters) > 0 {
+		if len(kindFilters) == 1 && kindFilters[0] == "__CONTRADICTION__" {
+			return 0, emptyFacets(), map[string][]FacetValue{}, nil
+		}
+		filter.Types = kindFilters
+	}
+
+	columns, searchQuery := extractColumnFilters(stripKindFilter(filter.Query))
+
+	parser := query.NewParser()
+	parsedQuery, err := parser.Parse(searchQuery)
+	if err != nil {
+		parsedQuery = &query.Query{FreeText: searchQuery}
+	}
+
+	whereSQL, params := r.buildAggregationWhereClause(parsedQuery, filter, columns)
+	source := searchSource(filter.IncludeStubs)
+
+	facets := emptyFacets()
+
+	typeQuery := fmt.Sprintf(`
+		SELECT type, asset_type, COUNT(*) as cnt
+		FROM %s
+		%s
+		GROUP BY type, asset_type
+	`, source, whereSQL)
+
+	rows, err := r.db.Query(ctx, typeQuery, params...)
+	if err != nil {
+		r.recorder.RecordDBQuery(ctx, "search_aggregate", time.Since(start), false)
+		return 0, nil, nil, fmt.Errorf("querying type aggregations: %w", err)
+	}
+
+	total := 0
+	assetTypeCounts := make(map[string]int)
+	for rows.Next() {
+		var t string
+		var assetType *string
+		var count int
+		if err := rows.Scan(&t, &assetType, &count); err != nil {
+			rows.Close()
+			return 0, nil, nil, fmt.Errorf("scanning type aggregation: %w", err)
+		}
+		facets.Types[ResultType(t)] += count
+		total += count
+		if assetType != nil && t == "asset" {
+			assetTypeCounts[*assetType] += count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, nil, fmt.Errorf("iterating type aggregations: %w", err)
+	}
+
+	for at, count := range assetTypeCounts {
+		facets.AssetTypes = append(facets.AssetTypes, FacetValue{Value: at, Count: count})
+	}
+	sortFacetValues(facets.AssetTypes)
+	if len(facets.AssetTypes) > filter.FacetLimit {
+		facets.AssetTypes = facets.AssetTypes[:filter.FacetLimit]
+	}
+
+	facets.Providers, err = r.aggregateValues(ctx, fmt.Sprintf(`
+		SELECT p, COUNT(*) as cnt
+		FROM (
+			SELECT unnest(providers) as p
+			FROM %s
+			%s
+			AND type = 'asset' AND providers IS NOT NULL
+		) sub
+		GROUP BY p
+		ORDER BY cnt DESC, p ASC
+		LIMIT %d
+	`, source, whereSQL, filter.FacetLimit), params)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("querying provider aggregations: %w", err)
+	}
+
+	facets.Tags, err = r.aggregateValues(ctx, fmt.Sprintf(`
+		SELECT t, COUNT(*) as cnt
+		FROM (
+			SELECT unnest(tags) as t
+			FROM %s
+			%s
+			AND tags IS NOT NULL AND array_length(tags, 1) > 0
+		) sub
+		GROUP BY t
+		ORDER BY cnt DESC, t ASC
+		LIMIT %d
+	`, source, whereSQL, filter.FacetLimit), params)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("querying tag aggregations: %w", err)
+	}
+
+	groups := make(map[string][]FacetValue, len(filter.GroupBy))
+	for _, field := range filter.GroupBy {
+		pathParam := len(params) + 1
+		groupQuery := fmt.Sprintf(`
+			SELECT v, COUNT(*) as cnt
+			FROM (
+				SELECT metadata #>> $%d as v
+				FROM %s
+				%s
+				AND metadata IS NOT NULL
+			) sub
+			WHERE v IS NOT NULL AND v <> ''
+			GROUP BY v
+			ORDER BY cnt DESC, v ASC
+			LIMIT %d
+		`, pathParam, source, whereSQL, filter.FacetLimit)
+
+		values, err := r.aggregateValues(ctx, groupQuery, append(params, metadataPath(field)))
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("querying group aggregation for %q: %w", field, err)
+		}
+		groups[field] = values
+	}
+
+	r.recorder.RecordDBQuery(ctx, "search_aggregate", time.Since(start), true)
+	return total, facets, groups, nil
+}
+
+// buildAggregationWhereClause combines the free-text match with the regular
+// search filters. Text matching always goes through the tsvector so the counts
+// agree with what a full-text search would page through.
+func (r *PostgresRepository) buildAggregationWhereClause(parsedQuery *query.Query, filter AggregationFilter, columns []string) (string, []interface{}) {
+	var params []interface{}
+	paramCount := 0
+	var whereClauses []string
+
+	if freeText := strings.TrimSpace(parsedQuery.GetFreeText()); freeText != "" {
+		paramCount++
+		whereClauses = append(whereClauses, fmt.Sprintf(
+			"(search_text @@ websearch_to_tsquery('english', $%d) OR name %%> $%d)", paramCount, paramCount))
+		params = append(params, freeText)
+	}
+
+	searchFilter := Filter{
+		Types:        filter.Types,
+		AssetTypes:   filter.AssetTypes,
+		Providers:    filter.Providers,
+		Tags:         filter.Tags,
+		AssetIDs:     filter.AssetIDs,
+		Environments: filter.Environments,
+		Columns:      columns,
+	}
+	filterClauses, params, _ := r.buildFilterClauses(searchFilter, parsedQuery, params, paramCount)
+	whereClauses = append(whereClauses, filterClauses...)
+
+	if len(whereClauses) == 0 {
+		return "WHERE true", params
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND "), params
+}
+
+func (r *PostgresRepository) aggregateValues(ctx context.Context, sqlQuery string, params []interface{}) ([]FacetValue, error) {
+	rows, err := r.db.Query(ctx, sqlQuery, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []FacetValue{}
+	for rows.Next() {
+		var fv FacetValue
+		if err := rows.Scan(&fv.Value, &fv.Count); err != nil {
+			return nil, err
+		}
+		values = append(values, fv)
+	}
+	return values, rows.Err()
+}
+
+// metadataPath converts a dotted metadata field ("owner.team") into the text
+// array form expected by the #>> operator.
+func metadataPath(field string) []string {
+	field = strings.TrimPrefix(strings.TrimSpace(field), "metadata.")
+	return strings.Split(field, ".")
+}
@@ -0,0 +1,19 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate_InvalidFilter(t *testing.T) {
+	svc := NewService(nil)
+
+	_, err := svc.Aggregate(context.Background(), AggregationFilter{Query: strings.Repeat("a", 257)})
+	assert.ErrorIs(t, err, ErrInvalidAggregation)
+
+	_, err = svc.Aggregate(context.Background(), AggregationFilter{GroupBy: []string{""}})
+	assert.ErrorIs(t, err, ErrInvalidAggregation)
+}
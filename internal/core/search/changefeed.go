@@ -0,0 +1,309 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// changeChannel is the NOTIFY channel search_index writes are announced on.
+// The payload is the entity type.
+const changeChannel = "search_index_changes"
+
+const (
+	defaultChangeBatchSize    = 500
+	defaultChangeWindow       = time.Second
+	defaultChangePollInterval = 30 * time.Second
+	maxChangeFeedBackoff      = time.Minute
+)
+
+// IndexChange identifies a search_index row that was inserted, updated or
+// deleted.
+type IndexChange struct {
+	Type     string
+	EntityID string
+}
+
+// ChangeRepository gives the change feed access to queued search_index
+// changes.
+type ChangeRepository interface {
+	IndexRepository
+	// ProcessIndexChanges locks up to limit of the oldest queued changes and
+	// passes them to fn. They are removed from the queue only if fn
+	// succeeds, and changes locked by another server are skipped. It returns
+	// how many changes were processed.
+	ProcessIndexChanges(ctx context.Context, limit int, fn func(ctx context.Context, changes []IndexChange) error) (int, error)
+	// RegisterChangeConsumer records that consumerID is draining the queue.
+	// search_index writes are only queued while a consumer has registered
+	// in the last ten minutes.
+	RegisterChangeConsumer(ctx context.Context, consumerID string) error
+	// UnregisterChangeConsumer removes consumerID.
+	UnregisterChangeConsumer(ctx context.Context, consumerID string) error
+}
+
+// ChangeListener is told which entity types changed so it can drop cached
+// data. It runs on every server, whichever server made the change.
+type ChangeListener func(ctx context.Context, entityTypes []string)
+
+// ChangeFeedConfig configures a ChangeFeed.
+type ChangeFeedConfig struct {
+	DB   *pgxpool.Pool
+	Repo ChangeRepository
+	// Indexer receives changed documents. Nil when there is no external
+	// index, in which case the feed only tells its listeners and leaves the
+	// queue to servers that have one.
+	Indexer SearchIndexer
+	// BatchSize is how many queued changes are indexed at once.
+	BatchSize int
+	// Window is how long to gather notifications before acting on them, so
+	// a large ingestion run is indexed in batches rather than row by row.
+	Window time.Duration
+	// PollInterval is how often the queue is checked without a
+	// notification, catching changes whose notification was missed.
+	PollInterval time.Duration
+}
+
+// ChangeFeed keeps caches and the external search index in step with
+// search_index. Triggers NOTIFY on changeChannel for every search_index
+// write, and queue the write while a feed with an indexer is registered as
+// a consumer; the feed listens, tells its listeners, and drains the queue
+// into the indexer within a few seconds of the write.
+type ChangeFeed struct {
+	db           *pgxpool.Pool
+	repo         ChangeRepository
+	indexer      SearchIndexer
+	consumerID   string
+	batchSize    int
+	window       time.Duration
+	pollInterval time.Duration
+
+	mu        sync.RWMutex
+	listeners []ChangeListener
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewChangeFeed creates a change feed.
+func NewChangeFeed(config *ChangeFeedConfig) *ChangeFeed {
+	f := &ChangeFeed{
+		db:           config.DB,
+		repo:         config.Repo,
+		indexer:      config.Indexer,
+		batchSize:    config.BatchSize,
+		window:       config.Window,
+		pollInterval: config.PollInterval,
+	}
+	if f.indexer != nil {
+		f.consumerID = uuid.NewString()
+	}
+	if f.batchSize <= 0 {
+		f.batchSize = defaultChangeBatchSize
+	}
+	if f.window <= 0 {
+		f.window = defaultChangeWindow
+	}
+	if f.pollInterval <= 0 {
+		f.pollInterval = defaultChangePollInterval
+	}
+	return f
+}
+
+// AddListener registers a listener for changed entity types.
+func (f *ChangeFeed) AddListener(l ChangeListener) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listeners = append(f.listeners, l)
+}
+
+// Start begins listening for changes.
+func (f *ChangeFeed) Start(ctx context.Context) {
+	f.ctx, f.cancel = context.WithCancel(ctx)
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.run()
+	}()
+
+	log.Info().
+		Bool("external_index", f.indexer != nil).
+		Dur("window", f.window).
+		Msg("Search change feed started")
+}
+
+// Stop stops listening and waits for in-flight indexing. Changes stop
+// being queued once no other consumer is registered.
+func (f *ChangeFeed) Stop() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.wg.Wait()
+
+	if f.indexer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := f.repo.UnregisterChangeConsumer(ctx, f.consumerID); err != nil {
+			log.Warn().Err(err).Msg("Failed to unregister search change consumer")
+		}
+	}
+}
+
+func (f *ChangeFeed) run() {
+	backoff := time.Second
+	for {
+		err := f.listen()
+		if f.ctx.Err() != nil {
+			return
+		}
+		log.Warn().Err(err).Dur("retry_in", backoff).Msg("Search change feed disconnected")
+
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxChangeFeedBackoff)
+	}
+}
+
+// listen holds a dedicated connection for LISTEN until it fails or the
+// feed is stopped.
+func (f *ChangeFeed) listen() error {
+	pooled, err := f.db.Acquire(f.ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	// A listening connection must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(f.ctx, "LISTEN "+changeChannel); err != nil {
+		return fmt.Errorf("listening on %s: %w", changeChannel, err)
+	}
+
+	// Catch up on anything queued while nobody was listening.
+	f.notify(nil)
+	f.drain()
+
+	for {
+		waitCtx, cancel := context.WithTimeout(f.ctx, f.pollInterval)
+		n, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil {
+			if f.ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				f.drain()
+				continue
+			}
+			return fmt.Errorf("waiting for notification: %w", err)
+		}
+
+		types := map[string]struct{}{n.Payload: {}}
+		deadline := time.Now().Add(f.window)
+		for {
+			gatherCtx, cancel := context.WithDeadline(f.ctx, deadline)
+			n, err := conn.WaitForNotification(gatherCtx)
+			cancel()
+			if err != nil {
+				if f.ctx.Err() != nil {
+					return nil
+				}
+				if errors.Is(err, context.DeadlineExceeded) {
+					break
+				}
+				return fmt.Errorf("waiting for notification: %w", err)
+			}
+			types[n.Payload] = struct{}{}
+		}
+
+		entityTypes := make([]string, 0, len(types))
+		for t := range types {
+			entityTypes = append(entityTypes, t)
+		}
+		f.notify(entityTypes)
+		f.drain()
+	}
+}
+
+// notify tells listeners which entity types changed. nil means any type
+// may have changed.
+func (f *ChangeFeed) notify(entityTypes []string) {
+	f.mu.RLock()
+	listeners := f.listeners
+	f.mu.RUnlock()
+
+	for _, l := range listeners {
+		l(f.ctx, entityTypes)
+	}
+}
+
+// drain processes queued changes until the queue is empty or a batch
+// fails. Failed batches stay queued and are retried on the next drain.
+// Draining also renews the feed's consumer registration, which keeps
+// changes being queued.
+func (f *ChangeFeed) drain() {
+	if f.indexer == nil {
+		return
+	}
+	if err := f.repo.RegisterChangeConsumer(f.ctx, f.consumerID); err != nil {
+		if f.ctx.Err() == nil {
+			log.Warn().Err(err).Msg("Failed to register search change consumer")
+		}
+		return
+	}
+
+	for f.ctx.Err() == nil {
+		n, err := f.repo.ProcessIndexChanges(f.ctx, f.batchSize, f.apply)
+		if err != nil {
+			if f.ctx.Err() == nil {
+				log.Warn().Err(err).Msg("Failed to process search index changes")
+			}
+			return
+		}
+		if n < f.batchSize {
+			return
+		}
+	}
+}
+
+// apply pushes the current state of each changed entity to the indexer.
+func (f *ChangeFeed) apply(ctx context.Context, changes []IndexChange) error {
+	seen := make(map[IndexChange]struct{}, len(changes))
+	docs := make([]SearchDocument, 0, len(changes))
+	for _, c := range changes {
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+
+		doc, err := f.repo.GetSearchDocument(ctx, c.Type, c.EntityID)
+		if err != nil {
+			return err
+		}
+		if doc == nil {
+			if err := f.indexer.Delete(ctx, c.Type, c.EntityID); err != nil {
+				return fmt.Errorf("deleting %s %s from search index: %w", c.Type, c.EntityID, err)
+			}
+			continue
+		}
+		docs = append(docs, *doc)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := f.indexer.BulkIndex(ctx, docs); err != nil {
+		return fmt.Errorf("indexing changed documents: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queueRepo struct {
+	mockIndexRepo
+	queue     []IndexChange
+	consumers map[string]bool
+}
+
+func (r *queueRepo) RegisterChangeConsumer(_ context.Context, consumerID string) error {
+	if r.consumers == nil {
+		r.consumers = map[string]bool{}
+	}
+	r.consumers[consumerID] = true
+	return nil
+}
+
+func (r *queueRepo) UnregisterChangeConsumer(_ context.Context, consumerID string) error {
+	delete(r.consumers, consumerID)
+	return nil
+}
+
+func (r *queueRepo) ProcessIndexChanges(ctx context.Context, limit int, fn func(ctx context.Context, changes []IndexChange) error) (int, error) {
+	batch := r.queue[:min(limit, len(r.queue))]
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := fn(ctx, batch); err != nil {
+		return 0, err
+	}
+	r.queue = r.queue[len(batch):]
+	return len(batch), nil
+}
+
+type failingIndexer struct {
+	trackingIndexer
+}
+
+func (f *failingIndexer) BulkIndex(ctx context.Context, docs []SearchDocument) error {
+	return errors.New("cluster unavailable")
+}
+
+func TestChangeFeedDrain(t *testing.T) {
+	indexer := &trackingIndexer{}
+	repo := &queueRepo{
+		mockIndexRepo: mockIndexRepo{docs: map[string]*SearchDocument{
+			"asset:a1": {Type: "asset", EntityID: "a1", Name: "orders", UpdatedAt: time.Now()},
+			"asset:a2": {Type: "asset", EntityID: "a2", Name: "customers", UpdatedAt: time.Now()},
+		}},
+		queue: []IndexChange{
+			{Type: "asset", EntityID: "a1"},
+			{Type: "asset", EntityID: "a1"},
+			{Type: "asset", EntityID: "gone"},
+			{Type: "asset", EntityID: "a2"},
+		},
+	}
+
+	feed := NewChangeFeed(&ChangeFeedConfig{Repo: repo, Indexer: indexer, BatchSize: 3})
+	feed.ctx = context.Background()
+	feed.drain()
+
+	assert.Empty(t, repo.queue)
+	require.Len(t, indexer.indexed, 2)
+	assert.Equal(t, "a1", indexer.indexed[0].EntityID)
+	assert.Equal(t, "a2", indexer.indexed[1].EntityID)
+	assert.Equal(t, []string{"asset:gone"}, indexer.deleted)
+}
+
+func TestChangeFeedKeepsFailedBatches(t *testing.T) {
+	repo := &queueRepo{
+		mockIndexRepo: mockIndexRepo{docs: map[string]*SearchDocument{
+			"team:t1": {Type: "team", EntityID: "t1", Name: "Data"},
+		}},
+		queue: []IndexChange{{Type: "team", EntityID: "t1"}},
+	}
+
+	feed := NewChangeFeed(&ChangeFeedConfig{Repo: repo, Indexer: &failingIndexer{}})
+	feed.ctx = context.Background()
+	feed.drain()
+
+	assert.Len(t, repo.queue, 1)
+}
+
+func TestChangeFeedWithoutIndexerLeavesQueue(t *testing.T) {
+	repo := &queueRepo{queue: []IndexChange{{Type: "asset", EntityID: "a1"}}}
+
+	var notified [][]string
+	feed := NewChangeFeed(&ChangeFeedConfig{Repo: repo})
+	feed.AddListener(func(_ context.Context, entityTypes []string) {
+		notified = append(notified, entityTypes)
+	})
+	feed.ctx = context.Background()
+	feed.notify([]string{"asset"})
+	feed.drain()
+
+	// Another server's indexer may still need the queued changes.
+	assert.Len(t, repo.queue, 1)
+	assert.Empty(t, repo.consumers)
+	assert.Equal(t, [][]string{{"asset"}}, notified)
+}
+
+func TestChangeFeedRegistersAsConsumer(t *testing.T) {
+	repo := &queueRepo{}
+	feed := NewChangeFeed(&ChangeFeedConfig{Repo: repo, Indexer: &trackingIndexer{}})
+	feed.ctx = context.Background()
+
+	feed.drain()
+	require.Len(t, repo.consumers, 1)
+	assert.True(t, repo.consumers[feed.consumerID])
+
+	feed.Stop()
+	assert.Empty(t, repo.consumers)
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var columnFilterRegex = regexp.MustCompile(`(?i)@column\s*[:=]\s*(?:"([^"]*)"|(\S+))`)
+
+// extractColumnFilters parses @column filters from a query string. It returns
+// the lowercased column names, matched exactly against an asset's schema, and
+// the query with the filters removed.
+func extractColumnFilters(queryStr string) ([]string, string) {
+	if !strings.Contains(strings.ToLower(queryStr), "@column") {
+		return nil, queryStr
+	}
+
+	var columns []string
+	for _, m := range columnFilterRegex.FindAllStringSubmatch(queryStr, -1) {
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			columns = append(columns, name)
+		}
+	}
+
+	stripped := columnFilterRegex.ReplaceAllString(queryStr, "")
+	return columns, strings.Join(strings.Fields(stripped), " ")
+}
+
+// columnTerms returns the words a search matches column names against: the
+// @column names and the words of its free text.
+func columnTerms(columns []string, freeText string) []string {
+	terms := append([]string{}, columns...)
+	for _, word := range strings.FieldsFunc(strings.ToLower(freeText), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		switch word {
+		case "and", "or", "not":
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// matchColumns returns the columns a search for terms matched: those named
+// by a term, or with a term as one of the underscore separated words in
+// their name.
+func matchColumns(columns, terms []string) []string {
+	var matched []string
+	for _, column := range columns {
+		parts := strings.FieldsFunc(column, func(r rune) bool {
+			return r == '_' || r == '.' || r == '-' || unicode.IsSpace(r)
+		})
+	terms:
+		for _, term := range terms {
+			if column == term {
+				matched = append(matched, column)
+				break
+			}
+			for _, part := range parts {
+				if part == term {
+					matched = append(matched, column)
+					break terms
+				}
+			}
+		}
+	}
+	return matched
+}
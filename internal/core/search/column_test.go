@@ -0,0 +1,47 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractColumnFilters(t *testing.T) {
+	tests := []struct {
+		query   string
+		columns []string
+		rest    string
+	}{
+		{"orders", nil, "orders"},
+		{"@column:email", []string{"email"}, ""},
+		{"@column: Email orders", []string{"email"}, "orders"},
+		{`@column:"customer_id" @type:"table" @column=created_at`, []string{"customer_id", "created_at"}, `@type:"table"`},
+	}
+	for _, tt := range tests {
+		columns, rest := extractColumnFilters(tt.query)
+		assert.Equal(t, tt.columns, columns, tt.query)
+		assert.Equal(t, tt.rest, rest, tt.query)
+	}
+}
+
+func TestMatchColumns(t *testing.T) {
+	columns := []string{"customer_email", "email", "email_verified_at", "emails", "id"}
+	terms := columnTerms([]string{"id"}, "Email AND orders")
+
+	assert.Equal(t, []string{"id", "email", "orders"}, terms)
+	assert.Equal(t, []string{"customer_email", "email", "email_verified_at", "id"}, matchColumns(columns, terms))
+	assert.Empty(t, matchColumns(columns, []string{"mail"}))
+}
+
+func TestColumnSearchQueries(t *testing.T) {
+	r := &PostgresRepository{}
+	filter := Filter{Columns: []string{"email"}, Limit: 20}
+
+	sql, params := r.buildListingQuery(filter, &query.Query{})
+	assert.Contains(t, sql, "column_names @> $1")
+	assert.Equal(t, []string{"email"}, params[0])
+
+	sql, _ = r.buildFuzzySearchQuery("email", Filter{Limit: 20}, &query.Query{})
+	assert.Contains(t, sql, "OR column_names @> ARRAY[lower($1)]")
+}
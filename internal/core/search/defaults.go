@@ -0,0 +1,52 @@
+package search
+
+// SortOrder is the order search results are returned in.
+type SortOrder string // @name SearchSortOrder
+
+const (
+	// SortRelevance orders text matches by rank and listings by recency.
+	SortRelevance SortOrder = "relevance"
+	SortUpdated   SortOrder = "updated"
+	SortName      SortOrder = "name"
+)
+
+// Valid reports whether s is a known sort order.
+func (s SortOrder) Valid() bool {
+	switch s {
+	case SortRelevance, SortUpdated, SortName:
+		return true
+	}
+	return false
+}
+
+// Defaults is the search scope a deployment applies to requests that don't
+// set their own. Empty Types and Environments don't restrict results.
+type Defaults struct {
+	IncludeStubs bool         `json:"include_stubs"`
+	Types        []ResultType `json:"types"`
+	Environments []string     `json:"environments"`
+	Sort         SortOrder    `json:"sort"`
+} // @name SearchDefaults
+
+// NewDefaults builds Defaults from configuration, which has already been
+// validated. An unset sort means relevance.
+func NewDefaults(includeStubs bool, types, environments []string, sort string) Defaults {
+	d := Defaults{
+		IncludeStubs: includeStubs,
+		Types:        make([]ResultType, 0, len(types)),
+		Environments: make([]string, 0, len(environments)),
+		Sort:         SortOrder(sort),
+	}
+	for _, t := range types {
+		d.Types = append(d.Types, ResultType(t))
+	}
+	for _, env := range environments {
+		if env != "" {
+			d.Environments = append(d.Environments, env)
+		}
+	}
+	if !d.Sort.Valid() {
+		d.Sort = SortRelevance
+	}
+	return d
+}
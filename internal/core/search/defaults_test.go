@@ -0,0 +1,52 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaults(t *testing.T) {
+	d := NewDefaults(true, []string{"asset", "glossary"}, []string{"production", ""}, "name")
+	assert.True(t, d.IncludeStubs)
+	assert.Equal(t, []ResultType{ResultTypeAsset, ResultTypeGlossary}, d.Types)
+	assert.Equal(t, []string{"production"}, d.Environments)
+	assert.Equal(t, SortName, d.Sort)
+
+	d = NewDefaults(false, nil, nil, "")
+	assert.NotNil(t, d.Types)
+	assert.NotNil(t, d.Environments)
+	assert.Equal(t, SortRelevance, d.Sort)
+}
+
+func TestOrderBy(t *testing.T) {
+	assert.Equal(t, "rank DESC, updated_at DESC", orderBy("", true))
+	assert.Equal(t, "updated_at DESC", orderBy(SortRelevance, false))
+	assert.Equal(t, "updated_at DESC", orderBy(SortUpdated, true))
+	assert.Equal(t, "lower(name) ASC, updated_at DESC", orderBy(SortName, false))
+}
+
+func TestScopedSearchQueries(t *testing.T) {
+	r := &PostgresRepository{}
+	parsed := &query.Query{}
+	filter := Filter{
+		Environments: []string{"production"},
+		IncludeStubs: true,
+		Sort:         SortName,
+		Limit:        20,
+	}
+
+	for name, build := range map[string]func() (string, []interface{}){
+		"listing":   func() (string, []interface{}) { return r.buildListingQuery(filter, parsed) },
+		"prefix":    func() (string, []interface{}) { return r.buildPrefixSearchQuery("or", filter, parsed) },
+		"fuzzy":     func() (string, []interface{}) { return r.buildFuzzySearchQuery("orders", filter, parsed) },
+		"full text": func() (string, []interface{}) { return r.buildFullTextSearchQuery("orders table", filter, parsed) },
+	} {
+		sql, params := build()
+		assert.Contains(t, sql, "WHERE is_stub = TRUE", name)
+		assert.Contains(t, sql, "a.environments ?|", name)
+		assert.Contains(t, sql, "ORDER BY lower(name) ASC", name)
+		assert.Contains(t, params, []string{"production"}, name)
+	}
+}
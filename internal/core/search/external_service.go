@@ -3,6 +3,7 @@ package search
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -36,8 +37,11 @@ func (s *ExternalSearchService) Search(ctx context.Context, filter Filter) (*Res
 		filter.Offset = 0
 	}
 
-	// Empty/browse queries stay on PG
-	if filter.Query == "" {
+	// Empty/browse queries stay on PG, as do those scoped or sorted by
+	// what the external index doesn't hold.
+	if filter.Query == "" || filter.IncludeStubs || len(filter.Environments) > 0 ||
+		len(filter.Columns) > 0 || strings.Contains(strings.ToLower(filter.Query), "@column") ||
+		(filter.Sort != "" && filter.Sort != SortRelevance) {
 		return s.pgSvc.Search(ctx, filter)
 	}
 
@@ -58,3 +62,9 @@ func (s *ExternalSearchService) Search(ctx context.Context, filter Filter) (*Res
 		Offset:  filter.Offset,
 	}, nil
 }
+
+// Aggregate always runs on PG. Facet counts are served from search_index so
+// they stay consistent with browse listings regardless of the text backend.
+func (s *ExternalSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	return s.pgSvc.Aggregate(ctx, filter)
+}
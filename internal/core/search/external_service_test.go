@@ -26,13 +26,21 @@ func (m *mockIndexer) Close() error                         { return nil }
 
 // mockPGService implements Service for testing.
 type mockPGService struct {
-	searchFunc func(ctx context.Context, filter Filter) (*Response, error)
+	searchFunc    func(ctx context.Context, filter Filter) (*Response, error)
+	aggregateFunc func(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error)
 }
 
 func (m *mockPGService) Search(ctx context.Context, filter Filter) (*Response, error) {
 	return m.searchFunc(ctx, filter)
 }
 
+func (m *mockPGService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	if m.aggregateFunc != nil {
+		return m.aggregateFunc(ctx, filter)
+	}
+	return &AggregationResponse{Facets: emptyFacets()}, nil
+}
+
 func TestExternalSearchService_TextQueryGoesToIndexer(t *testing.T) {
 	indexerCalled := false
 	pgCalled := false
@@ -190,3 +198,34 @@ func TestExternalSearchService_DefaultLimits(t *testing.T) {
 		t.Errorf("expected default offset=0, got %d", capturedFilter.Offset)
 	}
 }
+
+func TestExternalSearchService_ScopedQueryGoesToPG(t *testing.T) {
+	indexer := &mockIndexer{
+		searchFunc: func(ctx context.Context, filter Filter) ([]*Result, int, *Facets, error) {
+			t.Errorf("did not expect indexer to be called for %+v", filter)
+			return nil, 0, nil, nil
+		},
+	}
+	pgCalls := 0
+	pgSvc := &mockPGService{
+		searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+			pgCalls++
+			return &Response{}, nil
+		},
+	}
+
+	svc := NewExternalSearchService(indexer, pgSvc, 10*time.Second)
+
+	for _, filter := range []Filter{
+		{Query: "orders", IncludeStubs: true},
+		{Query: "orders", Environments: []string{"production"}},
+		{Query: "orders", Sort: SortName},
+	} {
+		if _, err := svc.Search(context.Background(), filter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if pgCalls != 3 {
+		t.Errorf("expected 3 PG searches, got %d", pgCalls)
+	}
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFacetCacheTTL is how long facets are reused for a query.
+	DefaultFacetCacheTTL = time.Minute
+
+	// maxFacetCacheEntries bounds the cache. Expired entries are dropped
+	// first, then the oldest.
+	maxFacetCacheEntries = 1000
+)
+
+// FacetCache serves a search's facets apart from its hits, so the hits can
+// be returned as soon as they are ranked and the facet counts, which take
+// far longer for broad queries, fetched alongside. Facets are computed by
+// the wrapped service's Aggregate and cached per query, since paging and
+// refining a search ask for the same counts again.
+type FacetCache struct {
+	inner Service
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]facetCacheEntry
+}
+
+type facetCacheEntry struct {
+	response  *AggregationResponse
+	expiresAt time.Time
+}
+
+// NewFacetCache wraps a search service's aggregations with a per-query
+// cache. A ttl of 0 or less turns caching off.
+func NewFacetCache(inner Service, ttl time.Duration) *FacetCache {
+	return &FacetCache{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]facetCacheEntry),
+	}
+}
+
+// Facets returns the facet counts and total for everything matching filter.
+// GroupBy is ignored. Callers must not modify the response, which may be
+// shared with other requests.
+func (c *FacetCache) Facets(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	filter.GroupBy = nil
+	if c.ttl <= 0 {
+		return c.inner.Aggregate(ctx, filter)
+	}
+
+	key := facetCacheKey(filter)
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.response, nil
+	}
+
+	response, err := c.inner.Aggregate(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxFacetCacheEntries {
+		c.evict(now)
+	}
+	c.entries[key] = facetCacheEntry{response: response, expiresAt: now.Add(c.ttl)}
+	return response, nil
+}
+
+// evict drops expired entries, or the one closest to expiring if none have.
+// c.mu must be held.
+func (c *FacetCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= maxFacetCacheEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// facetCacheKey identifies a filter regardless of the order its values
+// were given in.
+func facetCacheKey(filter AggregationFilter) string {
+	sorted := func(values []string) []string {
+		if values == nil {
+			return nil
+		}
+		out := append([]string{}, values...)
+		sort.Strings(out)
+		return out
+	}
+
+	types := make([]string, len(filter.Types))
+	for i, t := range filter.Types {
+		types[i] = string(t)
+	}
+
+	data, _ := json.Marshal(struct {
+		Query        string   `json:"q"`
+		Types        []string `json:"t"`
+		AssetTypes   []string `json:"a"`
+		Providers    []string `json:"p"`
+		Tags         []string `json:"g"`
+		AssetIDs     []string `json:"i"`
+		Environments []string `json:"e"`
+		IncludeStubs bool     `json:"s"`
+		FacetLimit   int      `json:"l"`
+	}{
+		Query:        strings.TrimSpace(filter.Query),
+		Types:        sorted(types),
+		AssetTypes:   sorted(filter.AssetTypes),
+		Providers:    sorted(filter.Providers),
+		Tags:         sorted(filter.Tags),
+		AssetIDs:     sorted(filter.AssetIDs),
+		Environments: sorted(filter.Environments),
+		IncludeStubs: filter.IncludeStubs,
+		FacetLimit:   filter.FacetLimit,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
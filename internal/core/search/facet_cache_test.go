@@ -0,0 +1,77 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacetCache(t *testing.T) {
+	calls := 0
+	inner := &mockPGService{
+		aggregateFunc: func(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+			calls++
+			assert.Nil(t, filter.GroupBy)
+			return &AggregationResponse{Total: calls, Facets: emptyFacets()}, nil
+		},
+	}
+	cache := NewFacetCache(inner, time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	resp, err := cache.Facets(ctx, AggregationFilter{Query: "orders", Providers: []string{"kafka", "postgres"}, GroupBy: []string{"owner"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Total)
+
+	resp, err = cache.Facets(ctx, AggregationFilter{Query: " orders ", Providers: []string{"postgres", "kafka"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Total, "the same query in another order is served from the cache")
+
+	resp, err = cache.Facets(ctx, AggregationFilter{Query: "orders", Providers: []string{"postgres"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Total, "another filter is computed")
+
+	now = now.Add(2 * time.Minute)
+	resp, err = cache.Facets(ctx, AggregationFilter{Query: "orders", Providers: []string{"kafka", "postgres"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Total, "expired facets are recomputed")
+}
+
+func TestFacetCacheErrorsAreNotCached(t *testing.T) {
+	fail := true
+	inner := &mockPGService{
+		aggregateFunc: func(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+			if fail {
+				return nil, errors.New("timeout")
+			}
+			return &AggregationResponse{Total: 7, Facets: emptyFacets()}, nil
+		},
+	}
+	cache := NewFacetCache(inner, time.Minute)
+
+	_, err := cache.Facets(context.Background(), AggregationFilter{})
+	require.Error(t, err)
+
+	fail = false
+	resp, err := cache.Facets(context.Background(), AggregationFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 7, resp.Total)
+}
+
+func TestFacetCacheEvictsWhenFull(t *testing.T) {
+	cache := NewFacetCache(&mockPGService{}, time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < maxFacetCacheEntries+10; i++ {
+		now = now.Add(time.Millisecond)
+		_, err := cache.Facets(context.Background(), AggregationFilter{FacetLimit: i + 1})
+		require.NoError(t, err)
+	}
+	assert.LessOrEqual(t, len(cache.entries), maxFacetCacheEntries)
+}
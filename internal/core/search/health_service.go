@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+
+	"github.com/marmotdata/marmot/internal/core/assethealth"
+	"github.com/rs/zerolog/log"
+)
+
+// HealthSource computes the health of a set of assets.
+type HealthSource interface {
+	HealthFor(ctx context.Context, assetIDs []string) (map[string]*assethealth.Health, error)
+}
+
+// HealthSearchService sets Health on asset results, so result lists can
+// badge assets that are stale, failing checks or failing to run.
+type HealthSearchService struct {
+	inner  Service
+	health HealthSource
+}
+
+// NewHealthSearchService wraps a search service so asset results carry
+// their computed health.
+func NewHealthSearchService(inner Service, health HealthSource) Service {
+	return &HealthSearchService{
+		inner:  inner,
+		health: health,
+	}
+}
+
+func (s *HealthSearchService) Search(ctx context.Context, filter Filter) (*Response, error) {
+	resp, err := s.inner.Search(ctx, filter)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	var ids []string
+	for _, r := range resp.Results {
+		if r.Type == ResultTypeAsset {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return resp, nil
+	}
+
+	healths, err := s.health.HealthFor(ctx, ids)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load search result health")
+		return resp, nil
+	}
+	for _, r := range resp.Results {
+		if r.Type == ResultTypeAsset {
+			r.Health = healths[r.ID]
+		}
+	}
+	return resp, nil
+}
+
+func (s *HealthSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	return s.inner.Aggregate(ctx, filter)
+}
@@ -0,0 +1,32 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/assethealth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHealthSource map[string]*assethealth.Health
+
+func (m mockHealthSource) HealthFor(ctx context.Context, assetIDs []string) (map[string]*assethealth.Health, error) {
+	return m, nil
+}
+
+func TestHealthSearchService_SetsHealthOnAssets(t *testing.T) {
+	inner := &mockPGService{searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+		results := assetResults("orders", "customers")
+		results = append(results, &Result{Type: ResultTypeGlossary, ID: "orders"})
+		return &Response{Results: results}, nil
+	}}
+	red := &assethealth.Health{Status: assethealth.StatusRed}
+	svc := NewHealthSearchService(inner, mockHealthSource{"orders": red})
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "orders"})
+	require.NoError(t, err)
+	assert.Equal(t, red, resp.Results[0].Health)
+	assert.Nil(t, resp.Results[1].Health)
+	assert.Nil(t, resp.Results[2].Health)
+}
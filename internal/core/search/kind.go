@@ -10,7 +10,8 @@ var (
 	kindAssetRegex       = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?asset"?`)
 	kindTeamRegex        = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?team"?`)
 	kindDataProductRegex = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?data_product"?`)
-	kindStripRegex       = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?(glossary|asset|team|data_product)"?`)
+	kindQueryRegex       = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?query"?`)
+	kindStripRegex       = regexp.MustCompile(`(?i)@kind\s*[:=]\s*"?(glossary|asset|team|data_product|query)"?`)
 )
 
 // extractKindFilters parses @kind filters from a query string.
@@ -34,6 +35,9 @@ func extractKindFilters(queryStr string) []ResultType {
 	if kindDataProductRegex.MatchString(queryStr) {
 		kinds = append(kinds, ResultTypeDataProduct)
 	}
+	if kindQueryRegex.MatchString(queryStr) {
+		kinds = append(kinds, ResultTypeQuery)
+	}
 
 	if len(kinds) > 1 {
 		return []ResultType{"__CONTRADICTION__"}
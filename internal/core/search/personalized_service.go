@@ -0,0 +1,136 @@
+package search
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Affinity is how a user is connected to an asset.
+type Affinity struct {
+	Favorite bool
+	// Owned is set when the user or one of their teams owns the asset.
+	Owned bool
+	// RecentViews is how often the user opened the asset, when they last
+	// did so recently.
+	RecentViews int64
+}
+
+// Boosts are the number of positions an asset may move up the results.
+const (
+	favoriteBoost   = 10
+	ownedBoost      = 5
+	recentViewBoost = 2
+	// maxCountedViews caps how many recent views add to the boost, so an
+	// asset opened constantly can't outrank a favorite.
+	maxCountedViews = 3
+)
+
+// Boost is the number of positions the asset may move up.
+func (a Affinity) Boost() int {
+	boost := 0
+	if a.Favorite {
+		boost += favoriteBoost
+	}
+	if a.Owned {
+		boost += ownedBoost
+	}
+	views := a.RecentViews
+	if views > maxCountedViews {
+		views = maxCountedViews
+	}
+	return boost + int(views)*recentViewBoost
+}
+
+// AffinitySource reports how a user is connected to a set of assets.
+// Assets the user has no connection to may be omitted.
+type AffinitySource interface {
+	AssetAffinity(ctx context.Context, userID string, assetIDs []string) (map[string]Affinity, error)
+}
+
+// PersonalizedSearchService reorders each page of the wrapped service's
+// results for the searching user: assets they favorited, own or recently
+// viewed move up a bounded number of positions, so relevance still
+// dominates. Searches without Filter.PersonalizeFor are passed through.
+type PersonalizedSearchService struct {
+	inner    Service
+	affinity AffinitySource
+}
+
+// NewPersonalizedSearchService wraps a search service so results are
+// boosted for the searching user.
+func NewPersonalizedSearchService(inner Service, affinity AffinitySource) Service {
+	return &PersonalizedSearchService{
+		inner:    inner,
+		affinity: affinity,
+	}
+}
+
+func (s *PersonalizedSearchService) Search(ctx context.Context, filter Filter) (*Response, error) {
+	resp, err := s.inner.Search(ctx, filter)
+	if err != nil || filter.PersonalizeFor == "" || !includesAssets(filter.Types) {
+		return resp, err
+	}
+
+	var assetIDs []string
+	for _, r := range resp.Results {
+		if r.Type == ResultTypeAsset {
+			assetIDs = append(assetIDs, r.ID)
+		}
+	}
+	if len(assetIDs) == 0 {
+		return resp, nil
+	}
+
+	affinity, err := s.affinity.AssetAffinity(ctx, filter.PersonalizeFor, assetIDs)
+	if err != nil {
+		// Personalization is best effort; fall back to the base ranking.
+		log.Warn().Err(err).Str("user_id", filter.PersonalizeFor).Msg("Failed to load search personalization")
+		return resp, nil
+	}
+	resp.Results = personalize(resp.Results, affinity)
+	return resp, nil
+}
+
+func (s *PersonalizedSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	return s.inner.Aggregate(ctx, filter)
+}
+
+// personalize moves each asset up by its boost, ahead of the result whose
+// place it takes. Otherwise results keep their base order.
+func personalize(results []*Result, affinity map[string]Affinity) []*Result {
+	if len(affinity) == 0 {
+		return results
+	}
+
+	type ranked struct {
+		result   *Result
+		position int
+		boosted  bool
+	}
+	ordered := make([]ranked, len(results))
+	for i, r := range results {
+		ordered[i] = ranked{result: r, position: i}
+		if r.Type != ResultTypeAsset {
+			continue
+		}
+		if boost := affinity[r.ID].Boost(); boost > 0 {
+			ordered[i].position -= boost
+			ordered[i].boosted = true
+			r.Personalized = true
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].position != ordered[j].position {
+			return ordered[i].position < ordered[j].position
+		}
+		return ordered[i].boosted && !ordered[j].boosted
+	})
+
+	out := make([]*Result, len(ordered))
+	for i, r := range ordered {
+		out[i] = r.result
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAffinitySource struct {
+	affinity map[string]Affinity
+	err      error
+	userID   string
+}
+
+func (m *mockAffinitySource) AssetAffinity(ctx context.Context, userID string, assetIDs []string) (map[string]Affinity, error) {
+	m.userID = userID
+	return m.affinity, m.err
+}
+
+func rankedInner(ids ...string) *mockPGService {
+	return &mockPGService{
+		searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+			return &Response{Results: assetResults(ids...), Total: len(ids)}, nil
+		},
+	}
+}
+
+func TestAffinityBoost(t *testing.T) {
+	assert.Equal(t, 0, Affinity{}.Boost())
+	assert.Equal(t, favoriteBoost+ownedBoost, Affinity{Favorite: true, Owned: true}.Boost())
+	assert.Equal(t, recentViewBoost, Affinity{RecentViews: 1}.Boost())
+	assert.Equal(t, maxCountedViews*recentViewBoost, Affinity{RecentViews: 50}.Boost())
+}
+
+func TestPersonalizedSearchService_BoostsConnectedAssets(t *testing.T) {
+	ids := make([]string, 15)
+	for i := range ids {
+		ids[i] = string(rune('a' + i))
+	}
+	source := &mockAffinitySource{affinity: map[string]Affinity{
+		"o": {Favorite: true}, // 14 -> 4
+		"g": {Owned: true},    // 6 -> 1
+		"d": {RecentViews: 1}, // 3 -> 1
+	}}
+	svc := NewPersonalizedSearchService(rankedInner(ids...), source)
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "orders", PersonalizeFor: "u1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "u1", source.userID)
+	assert.Equal(t, []string{"a", "d", "g", "b", "c", "o", "e"}, resultIDs(resp.Results)[:7])
+	assert.True(t, resp.Results[1].Personalized)
+	assert.False(t, resp.Results[3].Personalized)
+	assert.Len(t, resp.Results, 15)
+}
+
+func TestPersonalizedSearchService_PassesThrough(t *testing.T) {
+	boosted := map[string]Affinity{"b": {Favorite: true}}
+	tests := []struct {
+		name   string
+		filter Filter
+		source *mockAffinitySource
+	}{
+		{name: "opted out", filter: Filter{Query: "orders"}, source: &mockAffinitySource{affinity: boosted}},
+		{name: "non-asset types", filter: Filter{Query: "orders", Types: []ResultType{ResultTypeGlossary}, PersonalizeFor: "u1"}, source: &mockAffinitySource{affinity: boosted}},
+		{name: "affinity lookup fails", filter: Filter{Query: "orders", PersonalizeFor: "u1"}, source: &mockAffinitySource{err: errors.New("boom")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewPersonalizedSearchService(rankedInner("a", "b"), tt.source)
+
+			resp, err := svc.Search(context.Background(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, resultIDs(resp.Results))
+		})
+	}
+}
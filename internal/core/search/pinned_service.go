@@ -0,0 +1,153 @@
+package search
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PinSource returns the assets stewards pinned for a search query, in the
+// order they should be shown.
+type PinSource interface {
+	PinnedAssetIDs(ctx context.Context, query string) ([]string, error)
+}
+
+// PinnedSearchService promotes pinned assets above the ranked results of the
+// wrapped service. Pins only apply to text queries that can return assets;
+// browse listings and aggregations are passed through unchanged.
+type PinnedSearchService struct {
+	inner Service
+	pins  PinSource
+}
+
+// NewPinnedSearchService wraps a search service so pinned assets are
+// returned first.
+func NewPinnedSearchService(inner Service, pins PinSource) Service {
+	return &PinnedSearchService{
+		inner: inner,
+		pins:  pins,
+	}
+}
+
+func (s *PinnedSearchService) Search(ctx context.Context, filter Filter) (*Response, error) {
+	resp, err := s.inner.Search(ctx, filter)
+	if err != nil || filter.Query == "" || !includesAssets(filter.Types) {
+		return resp, err
+	}
+
+	pinned, err := s.pinnedIDs(ctx, filter)
+	if err != nil {
+		// A broken curation table shouldn't take search down with it.
+		log.Warn().Err(err).Str("query", filter.Query).Msg("Failed to load search pins")
+		return resp, nil
+	}
+	if len(pinned) == 0 {
+		return resp, nil
+	}
+
+	isPinned := make(map[string]bool, len(pinned))
+	for _, id := range pinned {
+		isPinned[id] = true
+	}
+
+	// Later pages only need the pinned assets removed, since they were
+	// already shown on the first page.
+	if resp.Offset > 0 {
+		resp.Results = withoutPinned(resp.Results, isPinned)
+		return resp, nil
+	}
+
+	promoted, err := s.fetchPinned(ctx, filter, pinned)
+	if err != nil {
+		log.Warn().Err(err).Str("query", filter.Query).Msg("Failed to load pinned search results")
+		return resp, nil
+	}
+	if len(promoted) == 0 {
+		return resp, nil
+	}
+
+	results := append(promoted, withoutPinned(resp.Results, isPinned)...)
+	if resp.Limit > 0 && len(results) > resp.Limit {
+		results = results[:resp.Limit]
+	}
+	resp.Results = results
+	return resp, nil
+}
+
+func (s *PinnedSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	return s.inner.Aggregate(ctx, filter)
+}
+
+// pinnedIDs returns the query's pins that also satisfy the filter's asset
+// restriction.
+func (s *PinnedSearchService) pinnedIDs(ctx context.Context, filter Filter) ([]string, error) {
+	ids, err := s.pins.PinnedAssetIDs(ctx, filter.Query)
+	if err != nil || filter.AssetIDs == nil {
+		return ids, err
+	}
+
+	allowed := make(map[string]bool, len(filter.AssetIDs))
+	for _, id := range filter.AssetIDs {
+		allowed[id] = true
+	}
+	var restricted []string
+	for _, id := range ids {
+		if allowed[id] {
+			restricted = append(restricted, id)
+		}
+	}
+	return restricted, nil
+}
+
+// fetchPinned loads the pinned assets that pass the filter's asset facets,
+// ordered as pinned.
+func (s *PinnedSearchService) fetchPinned(ctx context.Context, filter Filter, ids []string) ([]*Result, error) {
+	resp, err := s.inner.Search(ctx, Filter{
+		Types:      []ResultType{ResultTypeAsset},
+		AssetTypes: filter.AssetTypes,
+		Providers:  filter.Providers,
+		Tags:       filter.Tags,
+		Limit:      len(ids),
+		AssetIDs:   ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Result, len(resp.Results))
+	for _, r := range resp.Results {
+		byID[r.ID] = r
+	}
+
+	results := make([]*Result, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := byID[id]; ok {
+			r.Pinned = true
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+func includesAssets(types []ResultType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == ResultTypeAsset {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutPinned(results []*Result, pinned map[string]bool) []*Result {
+	filtered := make([]*Result, 0, len(results))
+	for _, r := range results {
+		if r.Type == ResultTypeAsset && pinned[r.ID] {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPinSource struct {
+	ids []string
+	err error
+}
+
+func (m *mockPinSource) PinnedAssetIDs(ctx context.Context, query string) ([]string, error) {
+	return m.ids, m.err
+}
+
+func assetResults(ids ...string) []*Result {
+	results := make([]*Result, len(ids))
+	for i, id := range ids {
+		results[i] = &Result{Type: ResultTypeAsset, ID: id}
+	}
+	return results
+}
+
+func resultIDs(results []*Result) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// pinnedInner serves ranked results for text queries and looks up pinned
+// assets by ID for the empty-query fetch.
+func pinnedInner(ranked []*Result, offset int) *mockPGService {
+	return &mockPGService{
+		searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+			if filter.Query == "" && filter.AssetIDs != nil {
+				// Return in reverse to check pin order is restored.
+				var found []*Result
+				for i := len(filter.AssetIDs) - 1; i >= 0; i-- {
+					if filter.AssetIDs[i] != "missing" {
+						found = append(found, &Result{Type: ResultTypeAsset, ID: filter.AssetIDs[i]})
+					}
+				}
+				return &Response{Results: found, Limit: filter.Limit}, nil
+			}
+			return &Response{Results: ranked, Total: 10, Limit: 3, Offset: offset}, nil
+		},
+	}
+}
+
+func TestPinnedSearchService_PromotesPinsInOrder(t *testing.T) {
+	svc := NewPinnedSearchService(
+		pinnedInner(assetResults("a", "p2", "b"), 0),
+		&mockPinSource{ids: []string{"p1", "missing", "p2"}},
+	)
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "orders", Limit: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"p1", "p2", "a"}, resultIDs(resp.Results))
+	assert.True(t, resp.Results[0].Pinned)
+	assert.True(t, resp.Results[1].Pinned)
+	assert.False(t, resp.Results[2].Pinned)
+	assert.Equal(t, 10, resp.Total)
+}
+
+func TestPinnedSearchService_LaterPagesDropPins(t *testing.T) {
+	svc := NewPinnedSearchService(
+		pinnedInner(assetResults("c", "p1", "d"), 3),
+		&mockPinSource{ids: []string{"p1"}},
+	)
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "orders", Limit: 3, Offset: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, resultIDs(resp.Results))
+}
+
+func TestPinnedSearchService_RespectsAssetIDRestriction(t *testing.T) {
+	svc := NewPinnedSearchService(
+		pinnedInner(assetResults("a"), 0),
+		&mockPinSource{ids: []string{"p1", "p2"}},
+	)
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "orders", Limit: 3, AssetIDs: []string{"a", "p2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p2", "a"}, resultIDs(resp.Results))
+}
+
+func TestPinnedSearchService_PassesThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		pins   *mockPinSource
+	}{
+		{name: "browse query", filter: Filter{Limit: 3}, pins: &mockPinSource{ids: []string{"p1"}}},
+		{name: "non-asset types", filter: Filter{Query: "orders", Types: []ResultType{ResultTypeGlossary}}, pins: &mockPinSource{ids: []string{"p1"}}},
+		{name: "pin lookup fails", filter: Filter{Query: "orders"}, pins: &mockPinSource{err: errors.New("boom")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewPinnedSearchService(pinnedInner(assetResults("a", "b"), 0), tt.pins)
+
+			resp, err := svc.Search(context.Background(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, resultIDs(resp.Results))
+		})
+	}
+}
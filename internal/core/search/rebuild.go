@@ -0,0 +1,224 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	RebuildScopeAsset    = "asset"
+	RebuildScopeProvider = "provider"
+	RebuildScopeGlobal   = "global"
+)
+
+var (
+	ErrRebuildInProgress   = errors.New("search rebuild already in progress")
+	ErrInvalidRebuildScope = errors.New("invalid rebuild scope")
+)
+
+// RebuildRequest selects what to rebuild. AssetID is required for the asset
+// scope and Provider for the provider scope.
+type RebuildRequest struct {
+	Scope    string `json:"scope" enums:"asset,provider,global"`
+	AssetID  string `json:"asset_id,omitempty"`
+	Provider string `json:"provider,omitempty"`
+} // @name SearchRebuildRequest
+
+// RebuildStatus reports the progress of the current or most recent rebuild.
+type RebuildStatus struct {
+	Running     bool       `json:"running"`
+	Scope       string     `json:"scope,omitempty"`
+	AssetID     string     `json:"asset_id,omitempty"`
+	Provider    string     `json:"provider,omitempty"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Failed      int        `json:"failed"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+} // @name SearchRebuildStatus
+
+// RebuildRepository rewrites rows so the database recomputes their search
+// vectors and the search_index triggers refresh the unified index.
+type RebuildRepository interface {
+	CountAssetsForRebuild(ctx context.Context, provider string) (int, error)
+	RebuildAssetBatch(ctx context.Context, provider, afterID string, limit int) (lastID string, count int, err error)
+	RebuildAsset(ctx context.Context, assetID string) (bool, error)
+	RebuildOtherEntities(ctx context.Context) (int, error)
+	ReindexSearchIndexes(ctx context.Context) error
+}
+
+// Rebuilder rebuilds PostgreSQL search vectors and indexes in the background.
+// It is needed after indexing rules change, since existing rows keep the
+// vectors computed when they were last written.
+type Rebuilder struct {
+	repo      RebuildRepository
+	batchSize int
+	running   atomic.Bool
+
+	mu     sync.RWMutex
+	status RebuildStatus
+}
+
+// NewRebuilder creates a new search rebuilder.
+func NewRebuilder(repo RebuildRepository, batchSize int) *Rebuilder {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Rebuilder{
+		repo:      repo,
+		batchSize: batchSize,
+	}
+}
+
+// Status returns the progress of the current or most recent rebuild.
+func (r *Rebuilder) Status() RebuildStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// Start validates req and runs the rebuild asynchronously. Only one rebuild
+// can run at a time.
+func (r *Rebuilder) Start(req RebuildRequest) error {
+	switch req.Scope {
+	case RebuildScopeAsset:
+		if req.AssetID == "" {
+			return fmt.Errorf("%w: asset_id is required for the asset scope", ErrInvalidRebuildScope)
+		}
+	case RebuildScopeProvider:
+		if req.Provider == "" {
+			return fmt.Errorf("%w: provider is required for the provider scope", ErrInvalidRebuildScope)
+		}
+	case RebuildScopeGlobal:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidRebuildScope, req.Scope)
+	}
+
+	if !r.running.CompareAndSwap(false, true) {
+		return ErrRebuildInProgress
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	r.status = RebuildStatus{
+		Running:   true,
+		Scope:     req.Scope,
+		AssetID:   req.AssetID,
+		Provider:  req.Provider,
+		StartedAt: &now,
+	}
+	r.mu.Unlock()
+
+	go func() {
+		defer r.running.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+		defer cancel()
+
+		err := r.run(ctx, req)
+
+		completed := time.Now()
+		r.mu.Lock()
+		r.status.Running = false
+		r.status.CompletedAt = &completed
+		if err != nil {
+			r.status.Error = err.Error()
+		}
+		status := r.status
+		r.mu.Unlock()
+
+		if err != nil {
+			log.Error().Err(err).Str("scope", req.Scope).Msg("Search rebuild failed")
+			return
+		}
+		log.Info().
+			Str("scope", req.Scope).
+			Int("processed", status.Processed).
+			Int("failed", status.Failed).
+			Dur("duration", completed.Sub(now)).
+			Msg("Search rebuild complete")
+	}()
+
+	return nil
+}
+
+func (r *Rebuilder) run(ctx context.Context, req RebuildRequest) error {
+	if req.Scope == RebuildScopeAsset {
+		r.setTotal(1)
+		found, err := r.repo.RebuildAsset(ctx, req.AssetID)
+		if err != nil {
+			r.addProgress(0, 1)
+			return err
+		}
+		if !found {
+			return fmt.Errorf("asset not found: %s", req.AssetID)
+		}
+		r.addProgress(1, 0)
+		return nil
+	}
+
+	total, err := r.repo.CountAssetsForRebuild(ctx, req.Provider)
+	if err != nil {
+		return err
+	}
+	r.setTotal(total)
+
+	afterID := ""
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		lastID, count, err := r.repo.RebuildAssetBatch(ctx, req.Provider, afterID, r.batchSize)
+		if err != nil {
+			if lastID == "" {
+				return err
+			}
+			// Skip past the failed batch rather than aborting the rebuild.
+			log.Warn().Err(err).Str("after_id", afterID).Msg("Failed to rebuild search batch, continuing")
+			r.addProgress(0, count)
+		} else {
+			r.addProgress(count, 0)
+		}
+
+		if count < r.batchSize || lastID == "" {
+			break
+		}
+		afterID = lastID
+	}
+
+	if req.Scope != RebuildScopeGlobal {
+		return nil
+	}
+
+	n, err := r.repo.RebuildOtherEntities(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.status.Total += n
+	r.status.Processed += n
+	r.mu.Unlock()
+
+	return r.repo.ReindexSearchIndexes(ctx)
+}
+
+func (r *Rebuilder) setTotal(total int) {
+	r.mu.Lock()
+	r.status.Total = total
+	r.mu.Unlock()
+}
+
+func (r *Rebuilder) addProgress(processed, failed int) {
+	r.mu.Lock()
+	r.status.Processed += processed
+	r.status.Failed += failed
+	r.mu.Unlock()
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	validator "github.com/go-playground/validator/v10"
+	"github.com/marmotdata/marmot/internal/core/assethealth"
 )
 
 // ResultType represents the type of search result
@@ -16,6 +17,7 @@ const (
 	ResultTypeGlossary    ResultType = "glossary"
 	ResultTypeTeam        ResultType = "team"
 	ResultTypeDataProduct ResultType = "data_product"
+	ResultTypeQuery       ResultType = "query"
 )
 
 // Result represents a unified search result
@@ -28,6 +30,18 @@ type Result struct {
 	URL         string                 `json:"url"`
 	Rank        float32                `json:"rank"`
 	UpdatedAt   *time.Time             `json:"updated_at,omitempty"`
+	// Pinned marks an asset a steward promoted for the query.
+	Pinned bool `json:"pinned,omitempty"`
+	// ThumbnailURL serves a preview image of the asset, e.g. a dashboard
+	// screenshot, when it has one.
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// Personalized marks an asset moved up because the searching user
+	// favorited, owns or recently viewed it.
+	Personalized bool `json:"personalized,omitempty"`
+	// Health is the asset's computed health, with the factors behind it.
+	Health *assethealth.Health `json:"health,omitempty"`
+	// MatchedColumns are the asset's schema columns the search matched.
+	MatchedColumns []string `json:"matched_columns,omitempty"`
 } // @name Result
 
 // Filter represents search filter options
@@ -39,6 +53,34 @@ type Filter struct {
 	Tags       []string     `json:"tags,omitempty"`        // Filter assets by tags
 	Limit      int          `json:"limit" validate:"omitempty,gte=1,lte=100"`
 	Offset     int          `json:"offset" validate:"omitempty,gte=0"`
+
+	// AssetIDs restricts results to these assets, e.g. the lineage closure
+	// of an asset. Other result types are excluded when set. Nil means no
+	// restriction; an empty, non-nil slice matches nothing.
+	AssetIDs []string `json:"asset_ids,omitempty"`
+
+	// Columns restricts assets to those whose schemas have all of these
+	// columns, lowercased. @column filters in the query add to it.
+	Columns []string `json:"columns,omitempty"`
+
+	// Environments restricts assets to those deployed in one of these
+	// environments. Other result types are unaffected.
+	Environments []string `json:"environments,omitempty"`
+
+	// IncludeStubs adds stub assets, those only known as lineage endpoints,
+	// to the results.
+	IncludeStubs bool `json:"include_stubs,omitempty"`
+
+	// Sort orders the results. Empty means relevance.
+	Sort SortOrder `json:"sort,omitempty" validate:"omitempty,oneof=relevance updated name"`
+
+	// PersonalizeFor is the user whose favorites, team ownership and recent
+	// views boost results. Empty leaves the ranking unchanged.
+	PersonalizeFor string `json:"-"`
+
+	// SkipFacets returns hits without facets, for callers fetching facets
+	// separately. The total is then only known to reach past the last hit.
+	SkipFacets bool `json:"-"`
 }
 
 type FacetValue struct {
@@ -63,6 +105,7 @@ type Response struct {
 
 type Service interface {
 	Search(ctx context.Context, filter Filter) (*Response, error)
+	Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error)
 }
 
 type service struct {
@@ -108,3 +151,26 @@ func (s *service) Search(ctx context.Context, filter Filter) (*Response, error)
 		Offset:  filter.Offset,
 	}, nil
 }
+
+func (s *service) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	if filter.FacetLimit <= 0 {
+		filter.FacetLimit = DefaultAggregationLimit
+	} else if filter.FacetLimit > MaxAggregationLimit {
+		filter.FacetLimit = MaxAggregationLimit
+	}
+
+	if err := s.validator.Struct(filter); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAggregation, err)
+	}
+
+	total, facets, groups, err := s.repo.Aggregate(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("aggregating: %w", err)
+	}
+
+	return &AggregationResponse{
+		Total:  total,
+		Facets: facets,
+		Groups: groups,
+	}, nil
+}
@@ -14,7 +14,7 @@ const assetMetadataColumns = `jsonb_build_object(
 	'description', description,
 	'user_description', user_description,
 	'metadata', metadata,
-	'schema', schema,
+	'schema', resolve_schema_blobs(schema),
 	'sources', sources,
 	'tags', tags,
 	'created_at', created_at,
@@ -62,3 +62,34 @@ const dataProductMetadataColumns = `jsonb_build_object(
 	'created_at', dp.created_at,
 	'updated_at', dp.updated_at
 ) as metadata`
+
+const savedQueryMetadataColumns = `jsonb_build_object(
+	'id', id,
+	'name', name,
+	'description', description,
+	'query', query,
+	'query_language', query_language,
+	'tags', tags,
+	'created_by', created_by,
+	'created_at', created_at,
+	'updated_at', updated_at
+) as metadata`
+
+// stubSearchSource is search_index with stub assets, which the index leaves
+// out, appended. Stubs are projected the way the index triggers project
+// other assets.
+const stubSearchSource = `(
+	SELECT type, entity_id, name, description, search_text, updated_at,
+	       asset_type, primary_provider, providers, tags, url_path, mrn,
+	       created_by, created_at, metadata, column_names
+	FROM search_index
+	UNION ALL
+	SELECT 'asset', id, name, COALESCE(user_description, description), search_text, updated_at,
+	       type, providers[1], providers, tags,
+	       '/discover/' || LOWER(type) || '/' ||
+	           CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+	           '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+	       mrn, created_by, created_at, metadata, schema_column_names(schema)
+	FROM assets
+	WHERE is_stub = TRUE
+) search_index`
@@ -24,6 +24,7 @@ const (
 
 type Repository interface {
 	Search(ctx context.Context, filter Filter) ([]*Result, int, *Facets, error)
+	Aggregate(ctx context.Context, filter AggregationFilter) (int, *Facets, map[string][]FacetValue, error)
 	GetMetadata(ctx context.Context, resultType ResultType, ids []string) (map[string]map[string]interface{}, error)
 }
 
@@ -55,7 +56,8 @@ func (r *PostgresRepository) Search(ctx context.Context, filter Filter) ([]*Resu
 		filter.Types = kindFilters
 	}
 
-	searchQuery := stripKindFilter(filter.Query)
+	columns, searchQuery := extractColumnFilters(stripKindFilter(filter.Query))
+	filter.Columns = append(filter.Columns, columns...)
 
 	parser := query.NewParser()
 	parsedQuery, err := parser.Parse(searchQuery)
@@ -63,23 +65,66 @@ func (r *PostgresRepository) Search(ctx context.Context, filter Filter) ([]*Resu
 		parsedQuery = &query.Query{FreeText: searchQuery}
 	}
 
+	budget := query.BudgetFromContext(ctx)
+	limit, ok := budget.ClampPage(filter.Limit, filter.Offset)
+	if !ok {
+		return []*Result{}, 0, emptyFacets(), nil
+	}
+	filter.Limit = limit
+
 	sqlQuery, params := r.buildOptimizedSearchQuery(parsedQuery.GetFreeText(), filter, parsedQuery)
 
-	rows, err := r.db.Query(ctx, sqlQuery, params...)
+	var results []*Result
+	err = budget.Run(ctx, r.db, func(q query.Querier) error {
+		rows, err := q.Query(ctx, sqlQuery, params...)
+		if err != nil {
+			return fmt.Errorf("executing unified search: %w", err)
+		}
+		defer rows.Close()
+
+		results, err = r.scanSearchResults(rows)
+		if err != nil {
+			return fmt.Errorf("scanning search results: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), false)
-		return nil, 0, nil, fmt.Errorf("executing unified search: %w", err)
+		return nil, 0, nil, err
 	}
-	defer rows.Close()
 
-	results, err := r.scanSearchResults(rows)
-	if err != nil {
-		r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), false)
-		return nil, 0, nil, fmt.Errorf("scanning search results: %w", err)
+	// Prefix searches only match names, so only @column filters highlight.
+	freeText := parsedQuery.GetFreeText()
+	if classifyQuery(freeText) == queryTypePrefix {
+		freeText = ""
+	}
+	if terms := columnTerms(filter.Columns, freeText); len(terms) > 0 {
+		if err := r.highlightColumns(ctx, results, terms); err != nil {
+			r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), false)
+			return nil, 0, nil, fmt.Errorf("highlighting columns: %w", err)
+		}
+	}
+
+	if filter.SkipFacets {
+		r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), true)
+		return results, filter.Offset + len(results), emptyFacets(), nil
+	}
+
+	// Facets are a nice-to-have, so under load, or when they run out of
+	// time, return the results on their own rather than failing the search.
+	if budget.UnderLoad(r.db) {
+		budget.Truncate(query.TruncatedFacets)
+		r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), true)
+		return results, filter.Offset + len(results), emptyFacets(), nil
 	}
 
 	facets, total, err := r.buildFacetsParallel(ctx, parsedQuery.GetFreeText(), filter, parsedQuery)
 	if err != nil {
+		if budget != nil && query.IsTimeout(err) {
+			budget.Truncate(query.TruncatedFacets)
+			r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), true)
+			return results, filter.Offset + len(results), emptyFacets(), nil
+		}
 		r.recorder.RecordDBQuery(ctx, "unified_search", time.Since(start), false)
 		return nil, 0, nil, fmt.Errorf("building facets: %w", err)
 	}
@@ -157,6 +202,8 @@ func (r *PostgresRepository) buildListingQuery(filter Filter, parsedQuery *query
 	params = append(params, filter.Limit, filter.Offset)
 
 	canUseIndexSort := r.canUseIndexedSort(parsedQuery, filter)
+	source := searchSource(filter.IncludeStubs)
+	order := orderBy(filter.Sort, false)
 
 	if canUseIndexSort {
 		sqlQuery := fmt.Sprintf(`
@@ -164,11 +211,11 @@ func (r *PostgresRepository) buildListingQuery(filter Filter, parsedQuery *query
 				type, entity_id, name, description, url_path,
 				0.0::real as rank,
 				updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-			FROM search_index
+			FROM %s
 			%s
-			ORDER BY updated_at DESC
+			ORDER BY %s
 			LIMIT $%d OFFSET $%d
-		`, whereSQL, limitParam, offsetParam)
+		`, source, whereSQL, order, limitParam, offsetParam)
 		return sqlQuery, params
 	}
 
@@ -181,13 +228,13 @@ func (r *PostgresRepository) buildListingQuery(filter Filter, parsedQuery *query
 			FROM (
 				SELECT entity_id, type, name, description, url_path,
 				       updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-				FROM search_index
+				FROM %s
 				%s
 				LIMIT 1000
 			) candidates
-			ORDER BY updated_at DESC
+			ORDER BY %s
 			LIMIT $%d OFFSET $%d
-		`, whereSQL, limitParam, offsetParam)
+		`, source, whereSQL, order, limitParam, offsetParam)
 		return sqlQuery, params
 	}
 
@@ -198,11 +245,11 @@ func (r *PostgresRepository) buildListingQuery(filter Filter, parsedQuery *query
 			type, entity_id, name, description, url_path,
 			0.0::real as rank,
 			updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-		FROM search_index
+		FROM %s
 		%s
-		ORDER BY updated_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereSQL, limitParam, offsetParam)
+	`, source, whereSQL, order, limitParam, offsetParam)
 
 	return sqlQuery, params
 }
@@ -211,6 +258,12 @@ func (r *PostgresRepository) buildListingQuery(filter Filter, parsedQuery *query
 // that covers both the filter predicate AND the sort order (updated_at DESC).
 // This avoids expensive in-memory sorts for high-cardinality matches.
 func (r *PostgresRepository) canUseIndexedSort(parsedQuery *query.Query, filter Filter) bool {
+	// Stubs aren't in the index, nor are environments and columns, and
+	// the index only covers recency.
+	if filter.IncludeStubs || len(filter.Environments) > 0 || len(filter.Columns) > 0 || filter.Sort == SortName {
+		return false
+	}
+
 	// Check if query can use composite index (single @type or @provider exact match)
 	if parsedQuery != nil && parsedQuery.CanUseCompositeIndex() && len(filter.Tags) == 0 {
 		return true
@@ -266,12 +319,12 @@ func (r *PostgresRepository) buildPrefixSearchQuery(searchQuery string, filter F
 				ELSE 500.0
 			END::real as rank,
 			updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-		FROM search_index
+		FROM %s
 		WHERE (lower(name) = $%d OR lower(name) LIKE $%d || '%%')
 		%s
-		ORDER BY rank DESC, updated_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, queryParam, queryParam, queryParam, whereSQL, limitParam, offsetParam)
+	`, queryParam, searchSource(filter.IncludeStubs), queryParam, queryParam, whereSQL, orderBy(filter.Sort, true), limitParam, offsetParam)
 
 	return sqlQuery, params
 }
@@ -299,16 +352,19 @@ func (r *PostgresRepository) buildFuzzySearchQuery(searchQuery string, filter Fi
 	offsetParam := paramCount
 	params = append(params, filter.Limit, filter.Offset)
 
+	// An asset with a column of that name matches too, ranked below close
+	// name matches.
 	sqlQuery := fmt.Sprintf(`
 		SELECT type, entity_id, name, description, url_path,
-		       (word_similarity($%d, name) * 100.0)::real as rank,
+		       GREATEST(word_similarity($%d, name) * 100.0,
+		                CASE WHEN column_names @> ARRAY[lower($%d)] THEN %g ELSE 0 END)::real as rank,
 		       updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-		FROM search_index
-		WHERE name %%> $%d
+		FROM %s
+		WHERE (name %%> $%d OR column_names @> ARRAY[lower($%d)])
 		%s
-		ORDER BY rank DESC, updated_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, queryParam, queryParam, whereSQL, limitParam, offsetParam)
+	`, queryParam, queryParam, columnMatchRank, searchSource(filter.IncludeStubs), queryParam, queryParam, whereSQL, orderBy(filter.Sort, true), limitParam, offsetParam)
 
 	return sqlQuery, params
 }
@@ -343,7 +399,7 @@ func (r *PostgresRepository) buildFullTextSearchQuery(searchQuery string, filter
 		WITH candidates AS (
 			SELECT entity_id, type, name, description, url_path, search_text,
 			       updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
-			FROM search_index
+			FROM %s
 			WHERE search_text @@ websearch_to_tsquery('english', $%d)
 			%s
 			LIMIT 1000
@@ -353,9 +409,9 @@ func (r *PostgresRepository) buildFullTextSearchQuery(searchQuery string, filter
 			ts_rank_cd(search_text, websearch_to_tsquery('english', $%d), 32)::real as rank,
 			updated_at, asset_type, primary_provider, providers, tags, mrn, created_by, created_at
 		FROM candidates
-		ORDER BY rank DESC, updated_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, queryParam, whereSQL, queryParam, limitParam, offsetParam)
+	`, searchSource(filter.IncludeStubs), queryParam, whereSQL, queryParam, orderBy(filter.Sort, true), limitParam, offsetParam)
 
 	return sqlQuery, params
 }
@@ -390,6 +446,24 @@ func (r *PostgresRepository) buildFilterClauses(filter Filter, parsedQuery *quer
 		params = append(params, filter.Tags)
 	}
 
+	if filter.AssetIDs != nil {
+		paramCount++
+		whereClauses = append(whereClauses, fmt.Sprintf("(type = 'asset' AND entity_id = ANY($%d))", paramCount))
+		params = append(params, filter.AssetIDs)
+	}
+
+	if len(filter.Environments) > 0 {
+		paramCount++
+		whereClauses = append(whereClauses, environmentClause(paramCount))
+		params = append(params, filter.Environments)
+	}
+
+	if len(filter.Columns) > 0 {
+		paramCount++
+		whereClauses = append(whereClauses, fmt.Sprintf("(type = 'asset' AND column_names @> $%d)", paramCount))
+		params = append(params, filter.Columns)
+	}
+
 	// Add structured query conditions from the query package
 	if parsedQuery != nil && parsedQuery.HasStructuredFilters() {
 		builder := query.NewSearchIndexBuilder()
@@ -404,6 +478,76 @@ func (r *PostgresRepository) buildFilterClauses(filter Filter, parsedQuery *quer
 	return whereClauses, params, paramCount
 }
 
+// columnMatchRank ranks a single-word search matching a column name, rather
+// than the asset's name, alongside a weak name match.
+const columnMatchRank = 40.0
+
+// highlightColumns sets MatchedColumns on the asset results whose schema
+// columns match terms.
+func (r *PostgresRepository) highlightColumns(ctx context.Context, results []*Result, terms []string) error {
+	var ids []string
+	byID := make(map[string]*Result)
+	for _, result := range results {
+		if result.Type == ResultTypeAsset {
+			ids = append(ids, result.ID)
+			byID[result.ID] = result
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT entity_id, column_names
+		FROM search_index
+		WHERE type = 'asset' AND entity_id = ANY($1) AND cardinality(column_names) > 0`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var columns []string
+		if err := rows.Scan(&id, &columns); err != nil {
+			return err
+		}
+		byID[id].MatchedColumns = matchColumns(columns, terms)
+	}
+	return rows.Err()
+}
+
+// searchSource is the relation a search reads from. The index leaves stub
+// assets out, so including them reads stubSearchSource instead.
+func searchSource(includeStubs bool) string {
+	if includeStubs {
+		return stubSearchSource
+	}
+	return "search_index"
+}
+
+// environmentClause matches assets deployed in any of the environments in
+// parameter n. Other result types pass through.
+func environmentClause(n int) string {
+	return fmt.Sprintf(
+		"(type != 'asset' OR EXISTS (SELECT 1 FROM assets a WHERE a.id = entity_id AND a.environments ?| $%d))", n)
+}
+
+// orderBy returns the ORDER BY list for sort. Relevance orders ranked
+// queries by rank and listings, which have none, by recency.
+func orderBy(sort SortOrder, ranked bool) string {
+	switch sort {
+	case SortName:
+		return "lower(name) ASC, updated_at DESC"
+	case SortUpdated:
+		return "updated_at DESC"
+	}
+	if ranked {
+		return "rank DESC, updated_at DESC"
+	}
+	return "updated_at DESC"
+}
+
 // scanSearchResults scans rows from the search_index table into Result structs.
 func (r *PostgresRepository) scanSearchResults(rows pgx.Rows) ([]*Result, error) {
 	results := []*Result{}
@@ -516,50 +660,66 @@ func (r *PostgresRepository) buildFacetsParallel(ctx context.Context, searchQuer
 		return facets, 0, nil
 	}
 
+	budget := query.BudgetFromContext(ctx)
+
 	// For unfiltered empty queries, use cached facets from summary_counts
 	// This avoids expensive GROUP BY and UNNEST queries on the full table
 	// Note: selecting all 4 entity types is functionally equivalent to no type filter
 	allTypesSelected := len(filter.Types) == 4
 	noTypeFilter := len(filter.Types) == 0 || allTypesSelected
-	if noTypeFilter && len(filter.AssetTypes) == 0 && len(filter.Providers) == 0 && len(filter.Tags) == 0 {
-		return r.buildCachedFacets(ctx, filter)
+	if noTypeFilter && len(filter.AssetTypes) == 0 && len(filter.Providers) == 0 && len(filter.Tags) == 0 && filter.AssetIDs == nil &&
+		len(filter.Environments) == 0 && len(filter.Columns) == 0 && !filter.IncludeStubs {
+		var total int
+		err := budget.Run(ctx, r.db, func(q query.Querier) error {
+			var err error
+			facets, total, err = r.buildCachedFacets(ctx, q)
+			return err
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return facets, total, nil
 	}
 
 	// For listing queries with filters, compute facets (filtered queries are fast)
 	baseWhere, baseParams := r.buildListingFacetWhereClause(filter)
+	source := searchSource(filter.IncludeStubs)
 
 	// Type and asset_type facets (single query, no unnest)
 	typeQuery := fmt.Sprintf(`
 		SELECT type, asset_type, COUNT(*) as cnt
-		FROM search_index
+		FROM %s
 		%s
 		GROUP BY type, asset_type
-	`, baseWhere)
-
-	rows, err := r.db.Query(ctx, typeQuery, baseParams...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("querying type facets: %w", err)
-	}
-	defer rows.Close()
+	`, source, baseWhere)
 
 	total := 0
 	assetTypeCounts := make(map[string]int)
 
-	for rows.Next() {
-		var t string
-		var assetType *string
-		var count int
-		if err := rows.Scan(&t, &assetType, &count); err != nil {
-			return nil, 0, fmt.Errorf("scanning type facet: %w", err)
+	err := budget.Run(ctx, r.db, func(q query.Querier) error {
+		rows, err := q.Query(ctx, typeQuery, baseParams...)
+		if err != nil {
+			return fmt.Errorf("querying type facets: %w", err)
 		}
-		facets.Types[ResultType(t)] += count
-		total += count
-		if assetType != nil && t == "asset" {
-			assetTypeCounts[*assetType] += count
+		defer rows.Close()
+
+		for rows.Next() {
+			var t string
+			var assetType *string
+			var count int
+			if err := rows.Scan(&t, &assetType, &count); err != nil {
+				return fmt.Errorf("scanning type facet: %w", err)
+			}
+			facets.Types[ResultType(t)] += count
+			total += count
+			if assetType != nil && t == "asset" {
+				assetTypeCounts[*assetType] += count
+			}
 		}
-	}
-	if rows.Err() != nil {
-		return nil, 0, rows.Err()
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Convert asset type counts to sorted slice
@@ -571,9 +731,17 @@ func (r *PostgresRepository) buildFacetsParallel(ctx context.Context, searchQuer
 		facets.AssetTypes = facets.AssetTypes[:maxFacetResults]
 	}
 
-	// Provider and tag facets (with unnest, but no search filter so faster)
-	if err := r.computeArrayFacets(ctx, baseWhere, baseParams, facets); err != nil {
+	// Provider and tag facets (with unnest, but no search filter so faster).
+	// They run in their own budgeted query so a timeout here leaves the
+	// type facets intact.
+	err = budget.Run(ctx, r.db, func(q query.Querier) error {
+		return r.computeArrayFacets(ctx, q, source, baseWhere, baseParams, facets)
+	})
+	if err != nil {
 		// Non-fatal: return partial facets
+		if query.IsTimeout(err) {
+			budget.Truncate(query.TruncatedFacets)
+		}
 		return facets, total, nil
 	}
 
@@ -621,6 +789,24 @@ func (r *PostgresRepository) buildListingFacetWhereClause(filter Filter) (string
 		params = append(params, filter.Tags)
 	}
 
+	if filter.AssetIDs != nil {
+		paramCount++
+		whereClauses = append(whereClauses, fmt.Sprintf("(type = 'asset' AND entity_id = ANY($%d))", paramCount))
+		params = append(params, filter.AssetIDs)
+	}
+
+	if len(filter.Environments) > 0 {
+		paramCount++
+		whereClauses = append(whereClauses, environmentClause(paramCount))
+		params = append(params, filter.Environments)
+	}
+
+	if len(filter.Columns) > 0 {
+		paramCount++
+		whereClauses = append(whereClauses, fmt.Sprintf("(type = 'asset' AND column_names @> $%d)", paramCount))
+		params = append(params, filter.Columns)
+	}
+
 	whereSQL := "WHERE true"
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
@@ -630,22 +816,22 @@ func (r *PostgresRepository) buildListingFacetWhereClause(filter Filter) (string
 }
 
 // computeArrayFacets computes provider and tag facets
-func (r *PostgresRepository) computeArrayFacets(ctx context.Context, baseWhere string, baseParams []interface{}, facets *Facets) error {
+func (r *PostgresRepository) computeArrayFacets(ctx context.Context, q query.Querier, source, baseWhere string, baseParams []interface{}, facets *Facets) error {
 	// Provider facets
 	providerQuery := fmt.Sprintf(`
 		SELECT p, COUNT(*) as cnt
 		FROM (
 			SELECT unnest(providers) as p
-			FROM search_index
+			FROM %s
 			%s
 			AND type = 'asset' AND providers IS NOT NULL
 		) sub
 		GROUP BY p
 		ORDER BY cnt DESC
 		LIMIT %d
-	`, baseWhere, maxFacetResults)
+	`, source, baseWhere, maxFacetResults)
 
-	rows, err := r.db.Query(ctx, providerQuery, baseParams...)
+	rows, err := q.Query(ctx, providerQuery, baseParams...)
 	if err != nil {
 		return fmt.Errorf("querying provider facets: %w", err)
 	}
@@ -668,16 +854,16 @@ func (r *PostgresRepository) computeArrayFacets(ctx context.Context, baseWhere s
 		SELECT t, COUNT(*) as cnt
 		FROM (
 			SELECT unnest(tags) as t
-			FROM search_index
+			FROM %s
 			%s
 			AND tags IS NOT NULL AND array_length(tags, 1) > 0
 		) sub
 		GROUP BY t
 		ORDER BY cnt DESC
 		LIMIT %d
-	`, baseWhere, maxFacetResults)
+	`, source, baseWhere, maxFacetResults)
 
-	rows2, err := r.db.Query(ctx, tagQuery, baseParams...)
+	rows2, err := q.Query(ctx, tagQuery, baseParams...)
 	if err != nil {
 		return fmt.Errorf("querying tag facets: %w", err)
 	}
@@ -729,6 +915,12 @@ func (r *PostgresRepository) GetMetadata(ctx context.Context, resultType ResultT
 			LEFT JOIN product_images pi ON dp.id = pi.data_product_id AND pi.purpose = 'icon'
 			WHERE dp.id = ANY($1::uuid[])
 		`, dataProductMetadataColumns)
+	case ResultTypeQuery:
+		query = fmt.Sprintf(`
+			SELECT id::text, %s
+			FROM saved_queries
+			WHERE id = ANY($1::uuid[])
+		`, savedQueryMetadataColumns)
 	default:
 		return nil, fmt.Errorf("unknown result type: %s", resultType)
 	}
@@ -789,6 +981,77 @@ func (r *PostgresRepository) GetSearchDocument(ctx context.Context, entityType,
 	return &doc, nil
 }
 
+// ProcessIndexChanges implements ChangeRepository.
+func (r *PostgresRepository) ProcessIndexChanges(ctx context.Context, limit int, fn func(ctx context.Context, changes []IndexChange) error) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, type, entity_id
+		FROM search_index_changes
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("querying search index changes: %w", err)
+	}
+
+	var ids []int64
+	var changes []IndexChange
+	for rows.Next() {
+		var id int64
+		var c IndexChange
+		if err := rows.Scan(&id, &c.Type, &c.EntityID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning search index change: %w", err)
+		}
+		ids = append(ids, id)
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterating search index changes: %w", err)
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	if err := fn(ctx, changes); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM search_index_changes WHERE id = ANY($1)`, ids); err != nil {
+		return 0, fmt.Errorf("deleting processed search index changes: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing search index changes: %w", err)
+	}
+	return len(changes), nil
+}
+
+// RegisterChangeConsumer implements ChangeRepository.
+func (r *PostgresRepository) RegisterChangeConsumer(ctx context.Context, consumerID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO search_index_change_consumers (id, last_seen)
+		VALUES ($1, NOW())
+		ON CONFLICT (id) DO UPDATE SET last_seen = NOW()`, consumerID)
+	if err != nil {
+		return fmt.Errorf("registering search change consumer: %w", err)
+	}
+	return nil
+}
+
+// UnregisterChangeConsumer implements ChangeRepository.
+func (r *PostgresRepository) UnregisterChangeConsumer(ctx context.Context, consumerID string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM search_index_change_consumers WHERE id = $1`, consumerID); err != nil {
+		return fmt.Errorf("unregistering search change consumer: %w", err)
+	}
+	return nil
+}
+
 // ScanSearchDocuments reads search_index rows using keyset pagination.
 // Pass empty strings for afterType/afterID to start from the beginning.
 func (r *PostgresRepository) ScanSearchDocuments(ctx context.Context, afterType, afterID string, limit int) ([]SearchDocument, error) {
@@ -857,6 +1120,90 @@ func (r *PostgresRepository) CountSearchDocuments(ctx context.Context) (int, err
 	return count, nil
 }
 
+// CountAssetsForRebuild counts the assets a rebuild will rewrite, optionally
+// limited to a provider.
+func (r *PostgresRepository) CountAssetsForRebuild(ctx context.Context, provider string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM assets
+		WHERE ($1 = '' OR $1 = ANY(providers))`, provider).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting assets for rebuild: %w", err)
+	}
+	return count, nil
+}
+
+// RebuildAssetBatch rewrites the next batch of assets after afterID. The no-op
+// update recomputes the generated search_text column and fires the
+// search_index triggers, so the index is rebuilt with the current rules. The
+// last ID of the batch is returned even on failure so callers can skip it.
+func (r *PostgresRepository) RebuildAssetBatch(ctx context.Context, provider, afterID string, limit int) (string, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM assets
+		WHERE id > $1 AND ($2 = '' OR $2 = ANY(providers))
+		ORDER BY id
+		LIMIT $3`, afterID, provider, limit)
+	if err != nil {
+		return "", 0, fmt.Errorf("selecting rebuild batch: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", 0, fmt.Errorf("scanning rebuild batch: %w", err)
+	}
+	if len(ids) == 0 {
+		return "", 0, nil
+	}
+	lastID := ids[len(ids)-1]
+
+	if _, err := r.db.Exec(ctx, `UPDATE assets SET name = name WHERE id = ANY($1)`, ids); err != nil {
+		return lastID, len(ids), fmt.Errorf("rebuilding asset batch: %w", err)
+	}
+	return lastID, len(ids), nil
+}
+
+// RebuildAsset rewrites a single asset. It reports false if the asset does
+// not exist.
+func (r *PostgresRepository) RebuildAsset(ctx context.Context, assetID string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE assets SET name = name WHERE id = $1`, assetID)
+	if err != nil {
+		return false, fmt.Errorf("rebuilding asset: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RebuildOtherEntities rewrites glossary terms, teams, data products and
+// saved queries so their search_index rows are rebuilt.
+func (r *PostgresRepository) RebuildOtherEntities(ctx context.Context) (int, error) {
+	total := 0
+	for _, stmt := range []string{
+		`UPDATE glossary_terms SET name = name WHERE deleted_at IS NULL`,
+		`UPDATE teams SET name = name`,
+		`UPDATE data_products SET name = name`,
+		`UPDATE saved_queries SET name = name`,
+	} {
+		tag, err := r.db.Exec(ctx, stmt)
+		if err != nil {
+			return total, fmt.Errorf("rebuilding search entities: %w", err)
+		}
+		total += int(tag.RowsAffected())
+	}
+	return total, nil
+}
+
+// ReindexSearchIndexes rebuilds the full-text and trigram indexes on
+// search_index without blocking reads or writes.
+func (r *PostgresRepository) ReindexSearchIndexes(ctx context.Context) error {
+	for _, idx := range []string{"idx_search_index_fts", "idx_search_index_name_trgm"} {
+		if _, err := r.db.Exec(ctx, "REINDEX INDEX CONCURRENTLY "+idx); err != nil {
+			return fmt.Errorf("reindexing %s: %w", idx, err)
+		}
+	}
+	if _, err := r.db.Exec(ctx, "ANALYZE search_index"); err != nil {
+		return fmt.Errorf("analyzing search_index: %w", err)
+	}
+	return nil
+}
+
 // resultTypesToStrings converts ResultType slice to string slice for SQL.
 func resultTypesToStrings(types []ResultType) []string {
 	result := make([]string, len(types))
@@ -879,7 +1226,7 @@ func emptyFacets() *Facets {
 // buildCachedFacets reads pre-computed facet counts from summary_counts table.
 // This is used for empty/browse queries where computing facets on-the-fly is expensive.
 // The summary_counts table is maintained by triggers on the source tables.
-func (r *PostgresRepository) buildCachedFacets(ctx context.Context, filter Filter) (*Facets, int, error) {
+func (r *PostgresRepository) buildCachedFacets(ctx context.Context, q query.Querier) (*Facets, int, error) {
 	facets := &Facets{
 		Types:      make(map[ResultType]int),
 		AssetTypes: []FacetValue{},
@@ -887,7 +1234,7 @@ func (r *PostgresRepository) buildCachedFacets(ctx context.Context, filter Filte
 		Tags:       []FacetValue{},
 	}
 
-	rows, err := r.db.Query(ctx, `
+	rows, err := q.Query(ctx, `
 		SELECT dimension, key, count
 		FROM summary_counts
 		WHERE count > 0
@@ -917,7 +1264,7 @@ func (r *PostgresRepository) buildCachedFacets(ctx context.Context, filter Filte
 
 		switch dimension {
 		case "entity_type":
-			// Entity types for the Types facet (asset, glossary, team, data_product)
+			// Entity types for the Types facet (asset, glossary, team, data_product, query)
 			facets.Types[ResultType(key)] = count
 			total += count
 		case "type":
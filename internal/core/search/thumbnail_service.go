@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ThumbnailSource reports which of a set of assets have a thumbnail.
+type ThumbnailSource interface {
+	WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error)
+}
+
+// ThumbnailSearchService sets ThumbnailURL on asset results that have a
+// thumbnail, so dashboards are recognisable in result lists.
+type ThumbnailSearchService struct {
+	inner      Service
+	thumbnails ThumbnailSource
+	urlFor     func(assetID string) string
+}
+
+// NewThumbnailSearchService wraps a search service so asset results carry
+// their thumbnail URL, built by urlFor.
+func NewThumbnailSearchService(inner Service, thumbnails ThumbnailSource, urlFor func(assetID string) string) Service {
+	return &ThumbnailSearchService{
+		inner:      inner,
+		thumbnails: thumbnails,
+		urlFor:     urlFor,
+	}
+}
+
+func (s *ThumbnailSearchService) Search(ctx context.Context, filter Filter) (*Response, error) {
+	resp, err := s.inner.Search(ctx, filter)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	var ids []string
+	for _, r := range resp.Results {
+		if r.Type == ResultTypeAsset {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return resp, nil
+	}
+
+	has, err := s.thumbnails.WithThumbnails(ctx, ids)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load search result thumbnails")
+		return resp, nil
+	}
+	for _, r := range resp.Results {
+		if r.Type == ResultTypeAsset && has[r.ID] {
+			r.ThumbnailURL = s.urlFor(r.ID)
+		}
+	}
+	return resp, nil
+}
+
+func (s *ThumbnailSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	return s.inner.Aggregate(ctx, filter)
+}
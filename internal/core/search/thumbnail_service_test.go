@@ -0,0 +1,32 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockThumbnailSource map[string]bool
+
+func (m mockThumbnailSource) WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error) {
+	return m, nil
+}
+
+func TestThumbnailSearchService_SetsURLOnAssetsWithThumbnails(t *testing.T) {
+	inner := &mockPGService{searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+		results := assetResults("dash-1", "table-1")
+		results = append(results, &Result{Type: ResultTypeGlossary, ID: "dash-1"})
+		return &Response{Results: results}, nil
+	}}
+	svc := NewThumbnailSearchService(inner, mockThumbnailSource{"dash-1": true}, func(id string) string {
+		return "/thumbs/" + id
+	})
+
+	resp, err := svc.Search(context.Background(), Filter{Query: "revenue"})
+	require.NoError(t, err)
+	assert.Equal(t, "/thumbs/dash-1", resp.Results[0].ThumbnailURL)
+	assert.Empty(t, resp.Results[1].ThumbnailURL)
+	assert.Empty(t, resp.Results[2].ThumbnailURL)
+}
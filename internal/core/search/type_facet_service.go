@@ -0,0 +1,76 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AssetTypeSource lists the custom asset types admins registered.
+type AssetTypeSource interface {
+	Names(ctx context.Context) ([]string, error)
+}
+
+// TypeFacetSearchService lists registered asset types in the asset type
+// facet even when no result has them, with a count of zero, so custom types
+// show up as filters before their first asset is cataloged.
+type TypeFacetSearchService struct {
+	inner Service
+	types AssetTypeSource
+}
+
+// NewTypeFacetSearchService wraps a search service so its asset type facet
+// includes every registered type.
+func NewTypeFacetSearchService(inner Service, types AssetTypeSource) Service {
+	return &TypeFacetSearchService{
+		inner: inner,
+		types: types,
+	}
+}
+
+func (s *TypeFacetSearchService) Search(ctx context.Context, filter Filter) (*Response, error) {
+	resp, err := s.inner.Search(ctx, filter)
+	if err != nil || resp == nil || filter.SkipFacets || !includesAssets(filter.Types) {
+		return resp, err
+	}
+	s.addTypes(ctx, resp.Facets)
+	return resp, nil
+}
+
+func (s *TypeFacetSearchService) Aggregate(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+	resp, err := s.inner.Aggregate(ctx, filter)
+	if err != nil || resp == nil || !includesAssets(filter.Types) {
+		return resp, err
+	}
+	s.addTypes(ctx, resp.Facets)
+	return resp, nil
+}
+
+func (s *TypeFacetSearchService) addTypes(ctx context.Context, facets *Facets) {
+	if facets == nil {
+		return
+	}
+	names, err := s.types.Names(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load registered asset types for search facets")
+		return
+	}
+	facets.AssetTypes = withTypes(facets.AssetTypes, names)
+}
+
+// withTypes appends the names missing from facets, ignoring case, with a
+// zero count.
+func withTypes(facets []FacetValue, names []string) []FacetValue {
+	present := make(map[string]bool, len(facets))
+	for _, f := range facets {
+		present[strings.ToLower(f.Value)] = true
+	}
+	for _, name := range names {
+		if !present[strings.ToLower(name)] {
+			facets = append(facets, FacetValue{Value: name, Count: 0})
+			present[strings.ToLower(name)] = true
+		}
+	}
+	return facets
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAssetTypeSource struct {
+	names []string
+	err   error
+}
+
+func (m *mockAssetTypeSource) Names(ctx context.Context) ([]string, error) {
+	return m.names, m.err
+}
+
+func facetInner() *mockPGService {
+	return &mockPGService{
+		searchFunc: func(ctx context.Context, filter Filter) (*Response, error) {
+			return &Response{Facets: &Facets{AssetTypes: []FacetValue{{Value: "Table", Count: 4}}}}, nil
+		},
+	}
+}
+
+func TestTypeFacetSearchService_AddsRegisteredTypes(t *testing.T) {
+	svc := NewTypeFacetSearchService(facetInner(), &mockAssetTypeSource{names: []string{"ML Model", "table"}})
+
+	resp, err := svc.Search(context.Background(), Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, []FacetValue{{Value: "Table", Count: 4}, {Value: "ML Model", Count: 0}}, resp.Facets.AssetTypes)
+}
+
+func TestTypeFacetSearchService_AddsRegisteredTypesToAggregations(t *testing.T) {
+	inner := &mockPGService{
+		aggregateFunc: func(ctx context.Context, filter AggregationFilter) (*AggregationResponse, error) {
+			return &AggregationResponse{Facets: &Facets{AssetTypes: []FacetValue{{Value: "Table", Count: 4}}}}, nil
+		},
+	}
+	svc := NewTypeFacetSearchService(inner, &mockAssetTypeSource{names: []string{"ML Model"}})
+
+	resp, err := svc.Aggregate(context.Background(), AggregationFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []FacetValue{{Value: "Table", Count: 4}, {Value: "ML Model", Count: 0}}, resp.Facets.AssetTypes)
+}
+
+func TestTypeFacetSearchService_PassesThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		types  *mockAssetTypeSource
+	}{
+		{name: "non-asset types", filter: Filter{Types: []ResultType{ResultTypeGlossary}}, types: &mockAssetTypeSource{names: []string{"ML Model"}}},
+		{name: "registry fails", types: &mockAssetTypeSource{err: errors.New("boom")}},
+		{name: "facets skipped", filter: Filter{SkipFacets: true}, types: &mockAssetTypeSource{names: []string{"ML Model"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewTypeFacetSearchService(facetInner(), tt.types)
+
+			resp, err := svc.Search(context.Background(), tt.filter)
+			require.NoError(t, err)
+			assert.Len(t, resp.Facets.AssetTypes, 1)
+		})
+	}
+}
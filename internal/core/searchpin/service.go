@@ -0,0 +1,164 @@
+package searchpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("search pin not found")
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrAlreadyPinned = errors.New("asset is already pinned for this term")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxTermLength = 256
+
+	// MaxPinsPerTerm bounds how many assets can be promoted above the
+	// organic results of one search.
+	MaxPinsPerTerm = 10
+)
+
+// Pin promotes an asset to the top of the results for a search term.
+type Pin struct {
+	ID        string `json:"id"`
+	Term      string `json:"term"`
+	AssetID   string `json:"asset_id"`
+	AssetMRN  string `json:"asset_mrn"`
+	AssetName string `json:"asset_name"`
+	// Position orders the pins of a term, lowest first.
+	Position  int       `json:"position"`
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+} // @name SearchPin
+
+type CreateInput struct {
+	Term    string `json:"term"`
+	AssetID string `json:"asset_id"`
+	// Position defaults to after the term's existing pins.
+	Position *int `json:"position,omitempty"`
+} // @name CreateSearchPinInput
+
+type UpdateInput struct {
+	Position int `json:"position"`
+} // @name UpdateSearchPinInput
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// NormalizeTerm folds a search query to the form pins are stored under, so
+// "Orders", " orders " and "ORDERS" share the same pins.
+func NormalizeTerm(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), " "))
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput, createdBy string) (*Pin, error) {
+	term := NormalizeTerm(input.Term)
+	if term == "" {
+		return nil, &ValidationError{Message: "term is required"}
+	}
+	if len(term) > maxTermLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("term must be %d characters or less", maxTermLength)}
+	}
+	if input.AssetID == "" {
+		return nil, &ValidationError{Message: "asset_id is required"}
+	}
+
+	existing, err := s.repo.List(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxPinsPerTerm {
+		return nil, &ValidationError{Message: fmt.Sprintf("a term can have at most %d pins", MaxPinsPerTerm)}
+	}
+
+	position := len(existing)
+	if len(existing) > 0 {
+		position = existing[len(existing)-1].Position + 1
+	}
+	if input.Position != nil {
+		if *input.Position < 0 {
+			return nil, &ValidationError{Message: "position must not be negative"}
+		}
+		position = *input.Position
+	}
+
+	pin := &Pin{
+		Term:     term,
+		AssetID:  input.AssetID,
+		Position: position,
+	}
+	if createdBy != "" {
+		pin.CreatedBy = &createdBy
+	}
+
+	if err := s.repo.Create(ctx, pin); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, pin.ID)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Pin, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns the pins for a term, or every pin when term is empty, ordered
+// by term and position.
+func (s *Service) List(ctx context.Context, term string) ([]*Pin, error) {
+	return s.repo.List(ctx, NormalizeTerm(term))
+}
+
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Pin, error) {
+	if input.Position < 0 {
+		return nil, &ValidationError{Message: "position must not be negative"}
+	}
+	if err := s.repo.UpdatePosition(ctx, id, input.Position); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// PinnedAssetIDs returns the assets pinned for a search query, in order.
+// It implements search.PinSource.
+func (s *Service) PinnedAssetIDs(ctx context.Context, query string) ([]string, error) {
+	term := NormalizeTerm(query)
+	if term == "" {
+		return nil, nil
+	}
+
+	pins, err := s.repo.List(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(pins))
+	for i, p := range pins {
+		ids[i] = p.AssetID
+	}
+	return ids, nil
+}
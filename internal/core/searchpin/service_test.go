@@ -0,0 +1,161 @@
+package searchpin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	pins   map[string]*Pin
+	nextID int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{pins: make(map[string]*Pin)}
+}
+
+func (f *fakeRepo) Create(_ context.Context, pin *Pin) error {
+	for _, p := range f.pins {
+		if p.Term == pin.Term && p.AssetID == pin.AssetID {
+			return ErrAlreadyPinned
+		}
+	}
+	f.nextID++
+	pin.ID = fmt.Sprintf("pin-%d", f.nextID)
+	stored := *pin
+	f.pins[pin.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, id string) (*Pin, error) {
+	p, ok := f.pins[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *p
+	return &out, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, term string) ([]*Pin, error) {
+	var pins []*Pin
+	for _, p := range f.pins {
+		if term == "" || p.Term == term {
+			out := *p
+			pins = append(pins, &out)
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		if pins[i].Term != pins[j].Term {
+			return pins[i].Term < pins[j].Term
+		}
+		return pins[i].Position < pins[j].Position
+	})
+	return pins, nil
+}
+
+func (f *fakeRepo) UpdatePosition(_ context.Context, id string, position int) error {
+	p, ok := f.pins[id]
+	if !ok {
+		return ErrNotFound
+	}
+	p.Position = position
+	return nil
+}
+
+func (f *fakeRepo) Delete(_ context.Context, id string) error {
+	if _, ok := f.pins[id]; !ok {
+		return ErrNotFound
+	}
+	delete(f.pins, id)
+	return nil
+}
+
+func TestNormalizeTerm(t *testing.T) {
+	assert.Equal(t, "customer orders", NormalizeTerm("  Customer\tORDERS "))
+	assert.Equal(t, "", NormalizeTerm("   "))
+}
+
+func TestCreate_AppendsAfterExistingPins(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	first, err := svc.Create(ctx, CreateInput{Term: "Orders", AssetID: "a1"}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", first.Term)
+	assert.Equal(t, 0, first.Position)
+	require.NotNil(t, first.CreatedBy)
+	assert.Equal(t, "u1", *first.CreatedBy)
+
+	second, err := svc.Create(ctx, CreateInput{Term: " orders ", AssetID: "a2"}, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Position)
+
+	_, err = svc.Create(ctx, CreateInput{Term: "ORDERS", AssetID: "a1"}, "u1")
+	assert.ErrorIs(t, err, ErrAlreadyPinned)
+}
+
+func TestCreate_Validation(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+	negative := -1
+
+	tests := []struct {
+		name  string
+		input CreateInput
+	}{
+		{name: "empty term", input: CreateInput{Term: "  ", AssetID: "a1"}},
+		{name: "missing asset", input: CreateInput{Term: "orders"}},
+		{name: "negative position", input: CreateInput{Term: "orders", AssetID: "a1", Position: &negative}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(ctx, tt.input, "")
+			assert.True(t, IsValidationError(err))
+		})
+	}
+}
+
+func TestCreate_LimitsPinsPerTerm(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	for i := 0; i < MaxPinsPerTerm; i++ {
+		_, err := svc.Create(ctx, CreateInput{Term: "orders", AssetID: fmt.Sprintf("a%d", i)}, "")
+		require.NoError(t, err)
+	}
+
+	_, err := svc.Create(ctx, CreateInput{Term: "orders", AssetID: "extra"}, "")
+	assert.True(t, IsValidationError(err))
+}
+
+func TestPinnedAssetIDs_FollowsPosition(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	first, err := svc.Create(ctx, CreateInput{Term: "orders", AssetID: "a1"}, "")
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, CreateInput{Term: "orders", AssetID: "a2"}, "")
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, CreateInput{Term: "customers", AssetID: "a3"}, "")
+	require.NoError(t, err)
+
+	moved, err := svc.Update(ctx, first.ID, UpdateInput{Position: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 5, moved.Position)
+	_, err = svc.Update(ctx, first.ID, UpdateInput{Position: -1})
+	assert.True(t, IsValidationError(err))
+
+	ids, err := svc.PinnedAssetIDs(ctx, "Orders")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a2", "a1"}, ids)
+
+	ids, err = svc.PinnedAssetIDs(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
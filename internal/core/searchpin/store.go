@@ -0,0 +1,121 @@
+package searchpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the search pin data access interface.
+type Repository interface {
+	Create(ctx context.Context, pin *Pin) error
+	Get(ctx context.Context, id string) (*Pin, error)
+	// List returns the pins for term, or all pins when term is empty.
+	List(ctx context.Context, term string) ([]*Pin, error)
+	UpdatePosition(ctx context.Context, id string, position int) error
+	Delete(ctx context.Context, id string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectPin = `
+	SELECT p.id, p.term, p.asset_id, a.mrn, a.name, p.position, p.created_by::text, p.created_at
+	FROM search_pins p
+	JOIN assets a ON a.id = p.asset_id`
+
+func (r *PostgresRepository) Create(ctx context.Context, pin *Pin) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO search_pins (term, asset_id, position, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		pin.Term, pin.AssetID, pin.Position, pin.CreatedBy,
+	).Scan(&pin.ID, &pin.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return ErrAlreadyPinned
+			case "23503":
+				return ErrAssetNotFound
+			}
+		}
+		return fmt.Errorf("creating search pin: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Pin, error) {
+	pin, err := scanPin(r.db.QueryRow(ctx, selectPin+` WHERE p.id::text = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting search pin: %w", err)
+	}
+	return pin, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, term string) ([]*Pin, error) {
+	rows, err := r.db.Query(ctx, selectPin+`
+		WHERE $1 = '' OR p.term = $1
+		ORDER BY p.term, p.position, p.created_at`, term)
+	if err != nil {
+		return nil, fmt.Errorf("listing search pins: %w", err)
+	}
+	defer rows.Close()
+
+	pins := []*Pin{}
+	for rows.Next() {
+		pin, err := scanPin(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning search pin: %w", err)
+		}
+		pins = append(pins, pin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating search pins: %w", err)
+	}
+	return pins, nil
+}
+
+func (r *PostgresRepository) UpdatePosition(ctx context.Context, id string, position int) error {
+	tag, err := r.db.Exec(ctx, `UPDATE search_pins SET position = $2 WHERE id::text = $1`, id, position)
+	if err != nil {
+		return fmt.Errorf("updating search pin: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM search_pins WHERE id::text = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting search pin: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanPin(row pgx.Row) (*Pin, error) {
+	var p Pin
+	err := row.Scan(&p.ID, &p.Term, &p.AssetID, &p.AssetMRN, &p.AssetName, &p.Position, &p.CreatedBy, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,334 @@
+package share
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultExpiry = 7 * 24 * time.Hour
+	MaxExpiry     = 90 * 24 * time.Hour
+
+	// keyContext separates share link signatures from other uses of the
+	// server signing key, so a share token can never pass as a session.
+	keyContext = "marmot-share-link-v1"
+)
+
+var (
+	ErrNotFound  = errors.New("share link not found")
+	ErrForbidden = errors.New("only the link's creator or an asset manager can do this")
+	// ErrInvalidLink covers unknown, tampered, expired and revoked links
+	// alike, so the public endpoint does not reveal which it was.
+	ErrInvalidLink = errors.New("share link is invalid or has expired")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Link grants read-only access to one asset without an account until it
+// expires or is revoked.
+type Link struct {
+	ID             string     `json:"id"`
+	AssetID        string     `json:"asset_id"`
+	Note           string     `json:"note,omitempty"`
+	Anonymize      bool       `json:"anonymize"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy      *string    `json:"revoked_by,omitempty"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AccessCount    int        `json:"access_count"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+} // @name ShareLink
+
+// Active reports whether the link can still be opened.
+func (l *Link) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// CreatedLink is returned once, on creation. The token is not stored and
+// cannot be retrieved again.
+type CreatedLink struct {
+	Link
+	Token string `json:"token"`
+} // @name CreatedShareLink
+
+type CreateInput struct {
+	// ExpiresIn is a duration such as 24h or 168h. Default: 7 days.
+	ExpiresIn string `json:"expires_in,omitempty"`
+	Anonymize bool   `json:"anonymize"`
+	Note      string `json:"note,omitempty"`
+} // @name CreateShareLinkInput
+
+// Access is one opening of a share link.
+type Access struct {
+	ID         string    `json:"id"`
+	LinkID     string    `json:"link_id"`
+	AccessedAt time.Time `json:"accessed_at"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+} // @name ShareLinkAccess
+
+// SharedAsset is what a share link recipient sees.
+type SharedAsset struct {
+	Asset      *asset.Asset `json:"asset"`
+	Anonymized bool         `json:"anonymized"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+} // @name SharedAsset
+
+// Actor is the user acting on a link. CanManage is set for users allowed to
+// manage assets, who may revoke and audit anyone's links.
+type Actor struct {
+	UserID    string
+	CanManage bool
+}
+
+func (a Actor) owns(link *Link) bool {
+	return link.CreatedBy == a.UserID || a.CanManage
+}
+
+// KeySource provides the server signing key.
+type KeySource interface {
+	GetSigningKey(ctx context.Context) ([]byte, error)
+}
+
+// AssetGetter loads the shared asset.
+type AssetGetter interface {
+	Get(ctx context.Context, id string) (*asset.Asset, error)
+}
+
+type Service struct {
+	repo   Repository
+	keys   KeySource
+	assets AssetGetter
+	now    func() time.Time
+}
+
+func NewService(repo Repository, keys KeySource, assets AssetGetter) *Service {
+	return &Service{repo: repo, keys: keys, assets: assets, now: time.Now}
+}
+
+func (s *Service) Create(ctx context.Context, assetID string, input CreateInput, createdBy string) (*CreatedLink, error) {
+	expiresIn := DefaultExpiry
+	if input.ExpiresIn != "" {
+		d, err := time.ParseDuration(input.ExpiresIn)
+		if err != nil || d <= 0 {
+			return nil, &ValidationError{Message: "expires_in must be a positive duration such as 24h"}
+		}
+		expiresIn = d
+	}
+	if expiresIn > MaxExpiry {
+		return nil, &ValidationError{Message: fmt.Sprintf("share links can last at most %d days", int(MaxExpiry.Hours()/24))}
+	}
+	if len(input.Note) > 500 {
+		return nil, &ValidationError{Message: "note must be at most 500 characters"}
+	}
+
+	if _, err := s.assets.Get(ctx, assetID); err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			return nil, &ValidationError{Message: "asset not found"}
+		}
+		return nil, fmt.Errorf("getting asset: %w", err)
+	}
+
+	link := &Link{
+		AssetID:   assetID,
+		Note:      strings.TrimSpace(input.Note),
+		Anonymize: input.Anonymize,
+		ExpiresAt: s.now().Add(expiresIn).UTC(),
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	token, err := s.token(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedLink{Link: *link, Token: token}, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Link, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// ListForAsset returns an asset's share links, newest first, including
+// expired and revoked ones so their access history stays visible.
+func (s *Service) ListForAsset(ctx context.Context, assetID string) ([]*Link, error) {
+	return s.repo.ListForAsset(ctx, assetID)
+}
+
+// Revoke disables a link immediately. Revoking an already revoked link is a
+// no-op.
+func (s *Service) Revoke(ctx context.Context, id string, actor Actor) (*Link, error) {
+	link, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.owns(link) {
+		return nil, ErrForbidden
+	}
+	if link.RevokedAt != nil {
+		return link, nil
+	}
+
+	now := s.now()
+	if err := s.repo.Revoke(ctx, id, actor.UserID, now); err != nil {
+		return nil, err
+	}
+	link.RevokedAt = &now
+	link.RevokedBy = &actor.UserID
+	return link, nil
+}
+
+// ListAccesses returns when and from where a link was opened. Like revoking,
+// it is limited to the link's creator and asset managers, as the accesses
+// hold recipients' IP addresses and user agents.
+func (s *Service) ListAccesses(ctx context.Context, linkID string, actor Actor, limit, offset int) ([]*Access, int, error) {
+	link, err := s.repo.Get(ctx, linkID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !actor.owns(link) {
+		return nil, 0, ErrForbidden
+	}
+	return s.repo.ListAccesses(ctx, linkID, limit, offset)
+}
+
+// Open verifies a token, records the access and returns the shared asset.
+func (s *Service) Open(ctx context.Context, token, ipAddress, userAgent string) (*SharedAsset, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || sig == "" {
+		return nil, ErrInvalidLink
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidLink
+	}
+
+	link, err := s.repo.Get(ctx, parsed.String())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidLink
+		}
+		return nil, err
+	}
+
+	want, err := s.token(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(token), []byte(want)) || !link.Active(s.now()) {
+		return nil, ErrInvalidLink
+	}
+
+	a, err := s.assets.Get(ctx, link.AssetID)
+	if err != nil {
+		if errors.Is(err, asset.ErrAssetNotFound) {
+			return nil, ErrInvalidLink
+		}
+		return nil, fmt.Errorf("getting asset: %w", err)
+	}
+
+	if err := s.repo.RecordAccess(ctx, &Access{LinkID: link.ID, IPAddress: ipAddress, UserAgent: truncate(userAgent, 500)}); err != nil {
+		log.Warn().Err(err).Str("link_id", link.ID).Msg("Failed to record share link access")
+	}
+
+	if link.Anonymize {
+		a = Anonymize(a)
+	}
+
+	return &SharedAsset{Asset: a, Anonymized: link.Anonymize, ExpiresAt: link.ExpiresAt}, nil
+}
+
+// token signs the link ID and expiry with a key derived from the server
+// signing key. The expiry is part of the signature, so a link cannot be
+// extended without issuing a new token.
+func (s *Service) token(ctx context.Context, link *Link) (string, error) {
+	signingKey, err := s.keys.GetSigningKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting signing key: %w", err)
+	}
+
+	kdf := hmac.New(sha256.New, signingKey)
+	kdf.Write([]byte(keyContext))
+
+	mac := hmac.New(sha256.New, kdf.Sum(nil))
+	fmt.Fprintf(mac, "%s|%d", link.ID, link.ExpiresAt.Unix())
+	return link.ID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// anonymizedMetadataKeys are metadata key fragments that usually identify
+// people or internal infrastructure.
+var anonymizedMetadataKeys = []string{
+	"owner", "email", "user", "contact", "steward", "author", "created_by",
+	"host", "endpoint", "url", "uri", "account", "bucket_arn", "connection",
+}
+
+// Anonymize returns a copy of a with people, source properties, links and
+// infrastructure details removed, keeping what an external reviewer needs:
+// name, type, description, schema and tags.
+func Anonymize(a *asset.Asset) *asset.Asset {
+	c := *a
+	c.CreatedBy = ""
+	c.ExternalLinks = nil
+	c.Environments = nil
+	c.LockedFields = nil
+
+	if a.Sources != nil {
+		c.Sources = make([]asset.AssetSource, 0, len(a.Sources))
+		for _, src := range a.Sources {
+			c.Sources = append(c.Sources, asset.AssetSource{Name: src.Name, LastSyncAt: src.LastSyncAt, Priority: src.Priority})
+		}
+	}
+
+	if a.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(a.Metadata))
+		for k, v := range a.Metadata {
+			if !sensitiveKey(k) {
+				c.Metadata[k] = v
+			}
+		}
+	}
+
+	return &c
+}
+
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range anonymizedMetadataKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
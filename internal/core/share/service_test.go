@@ -0,0 +1,179 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	links    map[string]*Link
+	accesses []*Access
+}
+
+func (f *fakeRepo) Create(ctx context.Context, link *Link) error {
+	link.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(f.links)+1)
+	link.CreatedAt = time.Now()
+	f.links[link.ID] = link
+	return nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (*Link, error) {
+	link, ok := f.links[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (f *fakeRepo) Revoke(ctx context.Context, id, userID string, at time.Time) error {
+	f.links[id].RevokedAt = &at
+	f.links[id].RevokedBy = &userID
+	return nil
+}
+
+func (f *fakeRepo) ListAccesses(ctx context.Context, linkID string, limit, offset int) ([]*Access, int, error) {
+	var accesses []*Access
+	for _, a := range f.accesses {
+		if a.LinkID == linkID {
+			accesses = append(accesses, a)
+		}
+	}
+	return accesses, len(accesses), nil
+}
+
+func (f *fakeRepo) RecordAccess(ctx context.Context, access *Access) error {
+	f.accesses = append(f.accesses, access)
+	return nil
+}
+
+type staticKey []byte
+
+func (k staticKey) GetSigningKey(ctx context.Context) ([]byte, error) { return k, nil }
+
+type fakeAssets map[string]*asset.Asset
+
+func (f fakeAssets) Get(ctx context.Context, id string) (*asset.Asset, error) {
+	a, ok := f[id]
+	if !ok {
+		return nil, asset.ErrAssetNotFound
+	}
+	return a, nil
+}
+
+func newTestService() (*Service, *fakeRepo) {
+	name := "orders"
+	repo := &fakeRepo{links: map[string]*Link{}}
+	assets := fakeAssets{"asset-1": {
+		ID:        "asset-1",
+		Name:      &name,
+		CreatedBy: "alice",
+		Metadata:  map[string]interface{}{"owner_email": "alice@example.com", "row_count": 10, "host": "db.internal"},
+		Sources:   []asset.AssetSource{{Name: "PostgreSQL", Properties: map[string]interface{}{"dsn": "secret"}}},
+	}}
+	return NewService(repo, staticKey("key"), assets), repo
+}
+
+func TestService_CreateAndOpen(t *testing.T) {
+	svc, repo := newTestService()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "asset-1", CreateInput{ExpiresIn: "24h"}, "user-1")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), created.ExpiresAt, time.Minute)
+
+	shared, err := svc.Open(ctx, created.Token, "203.0.113.7", "curl/8")
+	require.NoError(t, err)
+	assert.False(t, shared.Anonymized)
+	assert.Equal(t, "alice", shared.Asset.CreatedBy)
+	require.Len(t, repo.accesses, 1)
+	assert.Equal(t, "203.0.113.7", repo.accesses[0].IPAddress)
+
+	_, err = svc.Open(ctx, created.Token+"x", "", "")
+	assert.ErrorIs(t, err, ErrInvalidLink, "tampered signature")
+	_, err = svc.Open(ctx, "not-a-token", "", "")
+	assert.ErrorIs(t, err, ErrInvalidLink)
+	_, err = svc.Open(ctx, "not-a-uuid.c2ln", "", "")
+	assert.ErrorIs(t, err, ErrInvalidLink)
+}
+
+func TestService_OpenExpiredOrRevoked(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "asset-1", CreateInput{ExpiresIn: "1h"}, "user-1")
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = svc.Open(ctx, created.Token, "", "")
+	assert.ErrorIs(t, err, ErrInvalidLink)
+	svc.now = time.Now
+
+	_, err = svc.Revoke(ctx, created.ID, Actor{UserID: "user-2"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	revoked, err := svc.Revoke(ctx, created.ID, Actor{UserID: "user-2", CanManage: true})
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+
+	_, err = svc.Open(ctx, created.Token, "", "")
+	assert.ErrorIs(t, err, ErrInvalidLink)
+}
+
+func TestService_ListAccessesLimitedToCreatorAndManagers(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "asset-1", CreateInput{}, "user-1")
+	require.NoError(t, err)
+	_, err = svc.Open(ctx, created.Token, "203.0.113.7", "curl/8")
+	require.NoError(t, err)
+
+	_, _, err = svc.ListAccesses(ctx, created.ID, Actor{UserID: "user-2"}, 50, 0)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	for _, actor := range []Actor{{UserID: "user-1"}, {UserID: "user-2", CanManage: true}} {
+		accesses, total, err := svc.ListAccesses(ctx, created.ID, actor, 50, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, "203.0.113.7", accesses[0].IPAddress)
+	}
+
+	_, _, err = svc.ListAccesses(ctx, "00000000-0000-0000-0000-000000000099", Actor{UserID: "user-1"}, 50, 0)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_CreateValidation(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "asset-1", CreateInput{ExpiresIn: "2400h"}, "user-1")
+	assert.True(t, IsValidationError(err))
+	_, err = svc.Create(ctx, "asset-1", CreateInput{ExpiresIn: "soon"}, "user-1")
+	assert.True(t, IsValidationError(err))
+	_, err = svc.Create(ctx, "missing", CreateInput{}, "user-1")
+	assert.True(t, IsValidationError(err))
+}
+
+func TestService_OpenAnonymized(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "asset-1", CreateInput{Anonymize: true}, "user-1")
+	require.NoError(t, err)
+
+	shared, err := svc.Open(ctx, created.Token, "", "")
+	require.NoError(t, err)
+	assert.True(t, shared.Anonymized)
+	assert.Empty(t, shared.Asset.CreatedBy)
+	assert.Equal(t, map[string]interface{}{"row_count": 10}, shared.Asset.Metadata)
+	assert.Nil(t, shared.Asset.Sources[0].Properties)
+	assert.Equal(t, "PostgreSQL", shared.Asset.Sources[0].Name)
+}
@@ -0,0 +1,151 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the share link data access interface.
+type Repository interface {
+	Create(ctx context.Context, link *Link) error
+	Get(ctx context.Context, id string) (*Link, error)
+	ListForAsset(ctx context.Context, assetID string) ([]*Link, error)
+	Revoke(ctx context.Context, id, userID string, at time.Time) error
+	RecordAccess(ctx context.Context, access *Access) error
+	ListAccesses(ctx context.Context, linkID string, limit, offset int) ([]*Access, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectLink = `
+	SELECT l.id, l.asset_id, COALESCE(l.note, ''), l.anonymize, l.expires_at, l.revoked_at, l.revoked_by,
+	       l.created_by, l.created_at,
+	       (SELECT COUNT(*) FROM asset_share_link_accesses a WHERE a.link_id = l.id),
+	       (SELECT MAX(accessed_at) FROM asset_share_link_accesses a WHERE a.link_id = l.id)
+	FROM asset_share_links l`
+
+func (r *PostgresRepository) Create(ctx context.Context, link *Link) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_share_links (asset_id, note, anonymize, expires_at, created_by)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+		RETURNING id, created_at`,
+		link.AssetID, link.Note, link.Anonymize, link.ExpiresAt, link.CreatedBy,
+	).Scan(&link.ID, &link.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return &ValidationError{Message: "asset not found"}
+		}
+		return fmt.Errorf("creating share link: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*Link, error) {
+	link, err := scanLink(r.db.QueryRow(ctx, selectLink+` WHERE l.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting share link: %w", err)
+	}
+	return link, nil
+}
+
+func (r *PostgresRepository) ListForAsset(ctx context.Context, assetID string) ([]*Link, error) {
+	rows, err := r.db.Query(ctx, selectLink+` WHERE l.asset_id = $1 ORDER BY l.created_at DESC`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("listing share links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*Link{}
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning share link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating share links: %w", err)
+	}
+	return links, nil
+}
+
+func (r *PostgresRepository) Revoke(ctx context.Context, id, userID string, at time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE asset_share_links SET revoked_at = $2, revoked_by = $3
+		WHERE id = $1 AND revoked_at IS NULL`, id, at, userID)
+	if err != nil {
+		return fmt.Errorf("revoking share link: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) RecordAccess(ctx context.Context, access *Access) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO asset_share_link_accesses (link_id, ip_address, user_agent)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+		RETURNING id, accessed_at`,
+		access.LinkID, access.IPAddress, access.UserAgent,
+	).Scan(&access.ID, &access.AccessedAt)
+	if err != nil {
+		return fmt.Errorf("recording share link access: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListAccesses(ctx context.Context, linkID string, limit, offset int) ([]*Access, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM asset_share_link_accesses WHERE link_id = $1`, linkID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting share link accesses: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, link_id, accessed_at, COALESCE(ip_address, ''), COALESCE(user_agent, '')
+		FROM asset_share_link_accesses
+		WHERE link_id = $1
+		ORDER BY accessed_at DESC
+		LIMIT $2 OFFSET $3`, linkID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing share link accesses: %w", err)
+	}
+	defer rows.Close()
+
+	accesses := []*Access{}
+	for rows.Next() {
+		a := &Access{}
+		if err := rows.Scan(&a.ID, &a.LinkID, &a.AccessedAt, &a.IPAddress, &a.UserAgent); err != nil {
+			return nil, 0, fmt.Errorf("scanning share link access: %w", err)
+		}
+		accesses = append(accesses, a)
+	}
+	return accesses, total, rows.Err()
+}
+
+func scanLink(row pgx.Row) (*Link, error) {
+	var l Link
+	if err := row.Scan(
+		&l.ID, &l.AssetID, &l.Note, &l.Anonymize, &l.ExpiresAt, &l.RevokedAt, &l.RevokedBy,
+		&l.CreatedBy, &l.CreatedAt, &l.AccessCount, &l.LastAccessedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
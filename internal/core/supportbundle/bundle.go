@@ -0,0 +1,170 @@
+// Package supportbundle collects what maintainers need to debug an
+// installation into one archive: the configuration, database schema stats,
+// recent errors and catalog statistics. Nothing that identifies the
+// catalog's contents is included: secrets and connection details are
+// redacted, asset names and metadata values are left out, and error
+// messages have names, URLs and addresses masked.
+//
+// A bundle is a gzipped tar of JSON files described by manifest.json, plus
+// the filtered server log when one is given.
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+// FormatVersion is the bundle layout version.
+const FormatVersion = 1
+
+const (
+	// DefaultSince is how far back errors are collected.
+	DefaultSince = 7 * 24 * time.Hour
+
+	// DefaultMaxLogLines is how many of the most recent warning and error
+	// lines are kept from a server log.
+	DefaultMaxLogLines = 1000
+)
+
+// Options controls what a bundle holds.
+type Options struct {
+	// Since is how far back failed runs and log lines are collected.
+	Since time.Duration
+	// LogFile is a server log to take warnings and errors from. Empty
+	// leaves logs out.
+	LogFile     string
+	MaxLogLines int
+}
+
+// File is one file in a bundle. Error is set when its contents couldn't
+// be collected, in which case the file is left out.
+type File struct {
+	Name  string `json:"name"`
+	Size  int    `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	MarmotVersion string    `json:"marmot_version"`
+	GoVersion     string    `json:"go_version"`
+	Platform      string    `json:"platform"`
+	CreatedAt     time.Time `json:"created_at"`
+	Since         time.Time `json:"since"`
+	Files         []File    `json:"files"`
+}
+
+type collector struct {
+	name    string
+	collect func() (interface{}, error)
+}
+
+// Write collects a bundle and writes it to w. A section that can't be
+// collected, for example because the database user lacks a permission, is
+// recorded in the manifest instead of failing the bundle.
+func Write(ctx context.Context, db *pgxpool.Pool, cfg *config.Config, w io.Writer, marmotVersion string, opts Options) (*Manifest, error) {
+	if opts.Since <= 0 {
+		opts.Since = DefaultSince
+	}
+	if opts.MaxLogLines <= 0 {
+		opts.MaxLogLines = DefaultMaxLogLines
+	}
+	now := time.Now().UTC()
+	since := now.Add(-opts.Since)
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		MarmotVersion: marmotVersion,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		CreatedAt:     now,
+		Since:         since,
+	}
+
+	collectors := []collector{
+		{"config.json", func() (interface{}, error) { return RedactConfig(cfg), nil }},
+		{"database.json", func() (interface{}, error) { return databaseStats(ctx, db) }},
+		{"catalog.json", func() (interface{}, error) { return catalogStats(ctx, db) }},
+		{"errors.json", func() (interface{}, error) { return runErrors(ctx, db, since) }},
+	}
+
+	files := map[string][]byte{}
+	var order []string
+	for _, c := range collectors {
+		v, err := c.collect()
+		if err != nil {
+			manifest.Files = append(manifest.Files, File{Name: c.name, Error: err.Error()})
+			continue
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", c.name, err)
+		}
+		files[c.name] = data
+		order = append(order, c.name)
+		manifest.Files = append(manifest.Files, File{Name: c.name, Size: len(data)})
+	}
+
+	if opts.LogFile != "" {
+		const name = "logs/server.log"
+		data, err := readLog(opts.LogFile, since, opts.MaxLogLines)
+		if err != nil {
+			manifest.Files = append(manifest.Files, File{Name: name, Error: err.Error()})
+		} else {
+			files[name] = data
+			order = append(order, name)
+			manifest.Files = append(manifest.Files, File{Name: name, Size: len(data)})
+		}
+	}
+
+	if err := writeArchive(w, manifest, files, order); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeArchive writes manifest.json followed by files, in order.
+func writeArchive(w io.Writer, manifest *Manifest, files map[string][]byte, order []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	write := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := write("manifest.json", data); err != nil {
+		return err
+	}
+	for _, name := range order {
+		if err := write(name, files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,163 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/marmot/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database.Host = "db.internal.example.com"
+	cfg.Database.Password = "hunter2"
+	cfg.Database.Port = 5432
+	cfg.Database.SSLMode = "require"
+	cfg.Logging.Level = "debug"
+
+	redacted := RedactConfig(cfg)
+	db := redacted["database"].(map[string]interface{})
+	assert.Equal(t, Redacted, db["host"])
+	assert.Equal(t, Redacted, db["password"])
+	assert.Equal(t, 5432, db["port"])
+	assert.Equal(t, "require", db["sslmode"])
+	assert.Equal(t, "", db["user"])
+
+	logging := redacted["logging"].(map[string]interface{})
+	assert.Equal(t, "debug", logging["level"])
+
+	data, err := json.Marshal(redacted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+	assert.NotContains(t, string(data), "example.com")
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "quoted name",
+			message: `ERROR: relation "sales.orders" does not exist`,
+			want:    `ERROR: relation "[redacted]" does not exist`,
+		},
+		{
+			name:    "url",
+			message: "GET https://warehouse.example.com/api?token=abc failed: 401",
+			want:    "GET [redacted] failed: 401",
+		},
+		{
+			name:    "mrn",
+			message: "asset mrn://table/postgres/orders not found",
+			want:    "asset [redacted] not found",
+		},
+		{
+			name:    "email and address",
+			message: "user jane@example.com from 10.1.2.3:5432 denied",
+			want:    "user [redacted] from [redacted] denied",
+		},
+		{
+			name:    "nothing to mask",
+			message: "context deadline exceeded",
+			want:    "context deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Scrub(tt.message))
+		})
+	}
+}
+
+func TestPseudonymIsStable(t *testing.T) {
+	assert.Equal(t, pseudonym("nightly-snowflake"), pseudonym("nightly-snowflake"))
+	assert.NotEqual(t, pseudonym("nightly-snowflake"), pseudonym("nightly-bigquery"))
+	assert.Len(t, pseudonym("nightly-snowflake"), 12)
+}
+
+func TestReadLog(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	lines := []string{
+		`{"level":"error","time":"2024-04-30T12:00:00Z","message":"too old"}`,
+		`{"level":"info","time":"2024-05-02T12:00:00Z","message":"started"}`,
+		`{"level":"error","time":"2024-05-02T12:00:00Z","caller":"run.go:10","message":"relation \"orders\" does not exist","pipeline":"nightly","attempt":2}`,
+		`{"level":"warn","time":"2024-05-03T12:00:00Z","message":"slow query"}`,
+		`12:00PM INF serving on :8080`,
+		`12:01PM ERR dial tcp 10.0.0.5:5432: connection refused`,
+		`not a log line`,
+	}
+	path := filepath.Join(t.TempDir(), "server.log")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+
+	data, err := readLog(path, since, 10)
+	require.NoError(t, err)
+	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, got, 3)
+
+	var first map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(got[0]), &first))
+	assert.Equal(t, "error", first["level"])
+	assert.Equal(t, "run.go:10", first["caller"])
+	assert.Equal(t, `relation "[redacted]" does not exist`, first["message"])
+	assert.Equal(t, Redacted, first["pipeline"])
+	assert.Equal(t, float64(2), first["attempt"])
+
+	assert.Contains(t, got[1], "slow query")
+	assert.Equal(t, "12:01PM ERR dial tcp [redacted]: connection refused", got[2])
+
+	data, err = readLog(path, since, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "12:01PM ERR dial tcp [redacted]: connection refused\n", string(data))
+}
+
+func TestWriteArchive(t *testing.T) {
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		MarmotVersion: "v1.2.3",
+		CreatedAt:     time.Now().UTC(),
+		Files: []File{
+			{Name: "config.json", Size: 2},
+			{Name: "database.json", Error: "permission denied"},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeArchive(&buf, manifest, map[string][]byte{"config.json": []byte("{}")}, []string{"config.json"}))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	contents := map[string][]byte{}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		contents[hdr.Name] = data
+	}
+	assert.Equal(t, []string{"manifest.json", "config.json"}, names)
+
+	var got Manifest
+	require.NoError(t, json.Unmarshal(contents["manifest.json"], &got))
+	assert.Equal(t, "v1.2.3", got.MarmotVersion)
+	require.Len(t, got.Files, 2)
+	assert.Equal(t, "permission denied", got.Files[1].Error)
+	assert.Equal(t, "{}", string(contents["config.json"]))
+}
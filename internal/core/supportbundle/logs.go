@@ -0,0 +1,112 @@
+package supportbundle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// keptLogFields are the JSON log fields kept as they are.
+var keptLogFields = map[string]bool{
+	"level":  true,
+	"time":   true,
+	"caller": true,
+}
+
+// scrubbedLogFields are the JSON log fields kept with Scrub applied.
+var scrubbedLogFields = map[string]bool{
+	"message": true,
+	"error":   true,
+}
+
+// textLevelPattern matches warnings and errors written by the console
+// logger or in logfmt.
+var textLevelPattern = regexp.MustCompile(`\b(WRN|ERR|FTL|PNC)\b|level=(warn|error|fatal|panic)\b`)
+
+// readLog returns the last maxLines warnings and errors from a server log,
+// in order. JSON lines logged before since are skipped; of their fields
+// only the level, time, caller, message and error are kept, the last two
+// scrubbed. Other lines are scrubbed whole.
+func readLog(path string, since time.Time, maxLines int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening log: %w", err)
+	}
+	defer f.Close()
+
+	var kept []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line, ok := filterLogLine(scanner.Text(), since)
+		if !ok {
+			continue
+		}
+		kept = append(kept, line)
+		if len(kept) > maxLines {
+			kept = kept[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading log: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, line := range kept {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func filterLogLine(line string, since time.Time) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", false
+	}
+
+	var fields map[string]interface{}
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &fields) != nil {
+		if !textLevelPattern.MatchString(line) {
+			return "", false
+		}
+		return Scrub(line), true
+	}
+
+	switch level, _ := fields["level"].(string); level {
+	case "warn", "error", "fatal", "panic":
+	default:
+		return "", false
+	}
+	if raw, ok := fields["time"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil && t.Before(since) {
+			return "", false
+		}
+	}
+
+	for key, value := range fields {
+		switch {
+		case keptLogFields[key]:
+		case scrubbedLogFields[key]:
+			if s, ok := value.(string); ok {
+				fields[key] = Scrub(s)
+			}
+		default:
+			switch value.(type) {
+			case float64, bool, nil:
+			default:
+				fields[key] = Redacted
+			}
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
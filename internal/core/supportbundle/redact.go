@@ -0,0 +1,117 @@
+package supportbundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/marmotdata/marmot/pkg/config"
+)
+
+// Redacted replaces values left out of a bundle.
+const Redacted = "[redacted]"
+
+// safeConfigKeys are the string options kept as they are. They choose
+// between behaviours rather than naming anything in the installation.
+var safeConfigKeys = map[string]bool{
+	"default_roles":        true,
+	"direction":            true,
+	"excluded_asset_types": true,
+	"excluded_providers":   true,
+	"asset_types":          true,
+	"format":               true,
+	"level":                true,
+	"mode":                 true,
+	"on_conflict":          true,
+	"region":               true,
+	"role":                 true,
+	"roles":                true,
+	"scopes":               true,
+	"sslmode":              true,
+	"type":                 true,
+	"variant":              true,
+}
+
+// RedactConfig returns cfg as a map keyed by option name. Numbers and
+// booleans are kept. Strings are redacted, apart from a few that only
+// choose a behaviour, so hosts, credentials and names don't leave the
+// installation; empty strings stay empty, showing which options are set.
+// Maps, such as per-source priorities, are reduced to their size.
+func RedactConfig(cfg *config.Config) map[string]interface{} {
+	if cfg == nil {
+		return nil
+	}
+	redacted, _ := redactValue(reflect.ValueOf(*cfg), "").(map[string]interface{})
+	return redacted
+}
+
+func redactValue(v reflect.Value, key string) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), key)
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				name = strings.ToLower(field.Name)
+			}
+			out[name] = redactValue(v.Field(i), name)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = redactValue(v.Index(i), key)
+		}
+		return items
+	case reflect.Map:
+		return map[string]interface{}{"entries": v.Len()}
+	case reflect.String:
+		if v.String() == "" || safeConfigKeys[key] {
+			return v.String()
+		}
+		return Redacted
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v.Interface()
+	}
+	return Redacted
+}
+
+var (
+	urlPattern    = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`)
+	emailPattern  = regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`)
+	ipPattern     = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`)
+	quotedPattern = regexp.MustCompile("\"[^\"]*\"|'[^']*'|`[^`]*`")
+)
+
+// Scrub masks what an error message may reveal about the catalog: URLs and
+// MRNs, email addresses, IP addresses and quoted names, such as the table
+// in `relation "orders" does not exist`.
+func Scrub(message string) string {
+	message = urlPattern.ReplaceAllString(message, Redacted)
+	message = emailPattern.ReplaceAllString(message, Redacted)
+	message = ipPattern.ReplaceAllString(message, Redacted)
+	return quotedPattern.ReplaceAllStringFunc(message, func(quoted string) string {
+		return quoted[:1] + Redacted + quoted[len(quoted)-1:]
+	})
+}
+
+// pseudonym stands in for a name, such as a pipeline's, so that entries
+// about the same thing can be grouped without revealing it.
+func pseudonym(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,227 @@
+package supportbundle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DatabaseStats describes the database Marmot runs on. Table names are
+// Marmot's own.
+type DatabaseStats struct {
+	ServerVersion string       `json:"server_version"`
+	SchemaVersion int32        `json:"schema_version"`
+	SizeBytes     int64        `json:"size_bytes"`
+	Extensions    []Extension  `json:"extensions"`
+	Tables        []TableStats `json:"tables"`
+}
+
+type Extension struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type TableStats struct {
+	Name            string     `json:"name"`
+	LiveRows        int64      `json:"live_rows"`
+	DeadRows        int64      `json:"dead_rows"`
+	TotalBytes      int64      `json:"total_bytes"`
+	IndexBytes      int64      `json:"index_bytes"`
+	SeqScans        int64      `json:"seq_scans"`
+	IndexScans      int64      `json:"index_scans"`
+	LastAutovacuum  *time.Time `json:"last_autovacuum,omitempty"`
+	LastAutoanalyze *time.Time `json:"last_autoanalyze,omitempty"`
+}
+
+func databaseStats(ctx context.Context, db *pgxpool.Pool) (*DatabaseStats, error) {
+	var stats DatabaseStats
+	if err := db.QueryRow(ctx, `SHOW server_version`).Scan(&stats.ServerVersion); err != nil {
+		return nil, fmt.Errorf("reading server version: %w", err)
+	}
+	if err := db.QueryRow(ctx, `SELECT version FROM public.schema_version`).Scan(&stats.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("reading schema version: %w", err)
+	}
+	if err := db.QueryRow(ctx, `SELECT pg_database_size(current_database())`).Scan(&stats.SizeBytes); err != nil {
+		return nil, fmt.Errorf("reading database size: %w", err)
+	}
+
+	rows, err := db.Query(ctx, `SELECT extname, extversion FROM pg_extension ORDER BY extname`)
+	if err != nil {
+		return nil, fmt.Errorf("listing extensions: %w", err)
+	}
+	stats.Extensions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (Extension, error) {
+		var e Extension
+		err := row.Scan(&e.Name, &e.Version)
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning extensions: %w", err)
+	}
+
+	rows, err = db.Query(ctx, `
+		SELECT relname, n_live_tup, n_dead_tup,
+		       pg_total_relation_size(relid), pg_indexes_size(relid),
+		       COALESCE(seq_scan, 0), COALESCE(idx_scan, 0),
+		       last_autovacuum, last_autoanalyze
+		FROM pg_stat_user_tables
+		WHERE schemaname = 'public'
+		ORDER BY relname`)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	stats.Tables, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (TableStats, error) {
+		var t TableStats
+		err := row.Scan(&t.Name, &t.LiveRows, &t.DeadRows, &t.TotalBytes, &t.IndexBytes,
+			&t.SeqScans, &t.IndexScans, &t.LastAutovacuum, &t.LastAutoanalyze)
+		return t, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning tables: %w", err)
+	}
+	return &stats, nil
+}
+
+// Count is the number of catalog entries of one kind, such as assets of a
+// type or runs of a plugin.
+type Count struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// CatalogStats are counts describing the catalog's shape. Asset types,
+// providers and plugins are kept, being categories rather than names;
+// nothing is said about individual assets.
+type CatalogStats struct {
+	Assets           int64   `json:"assets"`
+	StubAssets       int64   `json:"stub_assets"`
+	AssetsWithSchema int64   `json:"assets_with_schema"`
+	AssetsWithOwners int64   `json:"assets_with_owners"`
+	AssetTypes       []Count `json:"asset_types"`
+	Providers        []Count `json:"providers"`
+	// MetadataKeys buckets assets by how many metadata keys they have.
+	MetadataKeys  []Count `json:"metadata_keys"`
+	LineageEdges  int64   `json:"lineage_edges"`
+	LineageTypes  []Count `json:"lineage_types"`
+	Users         int64   `json:"users"`
+	Teams         int64   `json:"teams"`
+	GlossaryTerms int64   `json:"glossary_terms"`
+	DataProducts  int64   `json:"data_products"`
+	Pipelines     int64   `json:"pipelines"`
+	// Runs counts runs started in the last 30 days by plugin and status.
+	Runs []Count `json:"runs"`
+}
+
+func catalogStats(ctx context.Context, db *pgxpool.Pool) (*CatalogStats, error) {
+	var stats CatalogStats
+
+	counts := []struct {
+		dest  *int64
+		query string
+	}{
+		{&stats.Assets, `SELECT COUNT(*) FROM assets`},
+		{&stats.StubAssets, `SELECT COUNT(*) FROM assets WHERE is_stub`},
+		{&stats.AssetsWithSchema, `SELECT COUNT(*) FROM assets WHERE schema <> '{}'::jsonb`},
+		{&stats.AssetsWithOwners, `SELECT COUNT(DISTINCT asset_id) FROM asset_owners`},
+		{&stats.LineageEdges, `SELECT COUNT(*) FROM lineage_edges`},
+		{&stats.Users, `SELECT COUNT(*) FROM users`},
+		{&stats.Teams, `SELECT COUNT(*) FROM teams`},
+		{&stats.GlossaryTerms, `SELECT COUNT(*) FROM glossary_terms`},
+		{&stats.DataProducts, `SELECT COUNT(*) FROM data_products`},
+		{&stats.Pipelines, `SELECT COUNT(DISTINCT pipeline_name) FROM runs`},
+	}
+	for _, c := range counts {
+		if err := db.QueryRow(ctx, c.query).Scan(c.dest); err != nil {
+			return nil, fmt.Errorf("counting: %s: %w", c.query, err)
+		}
+	}
+
+	groups := []struct {
+		dest  *[]Count
+		query string
+	}{
+		{&stats.AssetTypes, `SELECT type, COUNT(*) FROM assets GROUP BY type ORDER BY COUNT(*) DESC, type`},
+		{&stats.Providers, `
+			SELECT p, COUNT(*) FROM assets, unnest(providers) AS p
+			GROUP BY p ORDER BY COUNT(*) DESC, p`},
+		{&stats.MetadataKeys, `
+			SELECT bucket, COUNT(*) FROM (
+				SELECT CASE
+					WHEN n = 0 THEN '0'
+					WHEN n <= 5 THEN '1-5'
+					WHEN n <= 20 THEN '6-20'
+					WHEN n <= 100 THEN '21-100'
+					ELSE '>100'
+				END AS bucket
+				FROM (
+					SELECT CASE WHEN jsonb_typeof(metadata) = 'object'
+						THEN (SELECT COUNT(*) FROM jsonb_object_keys(metadata)) ELSE 0 END AS n
+					FROM assets
+				) keyed
+			) bucketed
+			GROUP BY bucket ORDER BY bucket`},
+		{&stats.LineageTypes, `
+			SELECT COALESCE(type, ''), COUNT(*) FROM lineage_edges
+			GROUP BY type ORDER BY COUNT(*) DESC, type`},
+		{&stats.Runs, `
+			SELECT source_name || '/' || status, COUNT(*) FROM runs
+			WHERE started_at >= NOW() - INTERVAL '30 days'
+			GROUP BY source_name, status ORDER BY source_name, status`},
+	}
+	for _, g := range groups {
+		rows, err := db.Query(ctx, g.query)
+		if err != nil {
+			return nil, fmt.Errorf("grouping: %w", err)
+		}
+		*g.dest, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (Count, error) {
+			var c Count
+			err := row.Scan(&c.Key, &c.Count)
+			return c, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning counts: %w", err)
+		}
+	}
+	return &stats, nil
+}
+
+// RunError is a failed pipeline run. The pipeline is named by a pseudonym,
+// the same in every bundle, and the error is scrubbed.
+type RunError struct {
+	Pipeline    string     `json:"pipeline"`
+	Plugin      string     `json:"plugin"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error"`
+}
+
+// maxRunErrors bounds the failed runs included.
+const maxRunErrors = 500
+
+func runErrors(ctx context.Context, db *pgxpool.Pool, since time.Time) ([]RunError, error) {
+	rows, err := db.Query(ctx, `
+		SELECT pipeline_name, source_name, started_at, completed_at, COALESCE(error_message, '')
+		FROM runs
+		WHERE status = 'failed' AND started_at >= $1
+		ORDER BY started_at DESC
+		LIMIT $2`, since, maxRunErrors)
+	if err != nil {
+		return nil, fmt.Errorf("listing failed runs: %w", err)
+	}
+	runs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (RunError, error) {
+		var r RunError
+		var pipeline, message string
+		if err := row.Scan(&pipeline, &r.Plugin, &r.StartedAt, &r.CompletedAt, &message); err != nil {
+			return r, err
+		}
+		r.Pipeline = pseudonym(pipeline)
+		r.Error = Scrub(message)
+		return r, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning failed runs: %w", err)
+	}
+	return runs, nil
+}
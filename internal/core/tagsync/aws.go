@@ -0,0 +1,229 @@
+package tagsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+const (
+	awsProviderName       = "AWS"
+	awsDefaultMetadataKey = "aws_tags"
+	awsTaggingTarget      = "ResourceGroupsTaggingAPI_20170126.GetResources"
+)
+
+// awsProviders are the Marmot providers of AWS plugins that record the ARN
+// of the resource behind each asset.
+var awsProviders = []string{"S3", "SQS", "SNS", "Lambda", "DynamoDB"}
+
+// awsARNKeys are the metadata keys AWS plugins store resource ARNs under.
+var awsARNKeys = []string{"arn", "bucket_arn", "queue_arn", "topic_arn", "function_arn", "table_arn"}
+
+// AWSConfig configures access to the Resource Groups Tagging API. Credentials
+// come from the default AWS chain.
+type AWSConfig struct {
+	// Region is used for resources whose ARN has no region, such as S3
+	// buckets. Defaults to the region of the AWS config.
+	Region string
+	// Endpoint overrides the tagging API endpoint, e.g. for LocalStack.
+	Endpoint    string
+	MetadataKey string
+	HTTPClient  *http.Client
+}
+
+// AWSProvider imports AWS resource tags. It is read-only.
+type AWSProvider struct {
+	region      string
+	endpoint    string
+	metadataKey string
+	creds       aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// NewAWSProvider creates an AWS tag provider.
+func NewAWSProvider(ctx context.Context, config AWSConfig) (*AWSProvider, error) {
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if config.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(config.Region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	key := config.MetadataKey
+	if key == "" {
+		key = awsDefaultMetadataKey
+	}
+
+	return &AWSProvider{
+		region:      region,
+		endpoint:    strings.TrimSuffix(config.Endpoint, "/"),
+		metadataKey: key,
+		creds:       cfg.Credentials,
+		signer:      v4.NewSigner(),
+		client:      client,
+	}, nil
+}
+
+func (p *AWSProvider) Name() string {
+	return awsProviderName
+}
+
+func (p *AWSProvider) Providers() []string {
+	return awsProviders
+}
+
+func (p *AWSProvider) MetadataKey() string {
+	return p.metadataKey
+}
+
+// Resolve uses the ARN recorded by the AWS plugins.
+func (p *AWSProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil || a.IsStub {
+		return ObjectRef{}, false
+	}
+	for _, key := range awsARNKeys {
+		if arn, _ := a.Metadata[key].(string); strings.HasPrefix(arn, "arn:") {
+			return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: arn}, true
+		}
+	}
+	return ObjectRef{}, false
+}
+
+func (p *AWSProvider) ReadLabels(ctx context.Context, obj ObjectRef) (map[string]string, error) {
+	body, err := json.Marshal(map[string]any{"ResourceARNList": []string{obj.Path}})
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	region := arnRegion(obj.Path)
+	if region == "" {
+		region = p.region
+	}
+	endpoint := p.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://tagging.%s.amazonaws.com", region)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", awsTaggingTarget)
+
+	creds, err := p.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "tagging", region, time.Now()); err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling AWS tagging API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"Message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("aws tagging %s (%d): %s", obj.Path, resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("aws tagging %s: unexpected status %d", obj.Path, resp.StatusCode)
+	}
+
+	var result struct {
+		ResourceTagMappingList []struct {
+			ResourceARN string `json:"ResourceARN"`
+			Tags        []struct {
+				Key   string `json:"Key"`
+				Value string `json:"Value"`
+			} `json:"Tags"`
+		} `json:"ResourceTagMappingList"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding AWS tagging response: %w", err)
+	}
+
+	// Untagged resources are left out of the response entirely.
+	labels := make(map[string]string)
+	for _, mapping := range result.ResourceTagMappingList {
+		if mapping.ResourceARN != obj.Path {
+			continue
+		}
+		for _, tag := range mapping.Tags {
+			labels[tag.Key] = tag.Value
+		}
+	}
+	return labels, nil
+}
+
+func (p *AWSProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	return readMappedLabels(ctx, p, obj, tags)
+}
+
+func (p *AWSProvider) SetTag(context.Context, ObjectRef, string, string) error {
+	return ErrReadOnly
+}
+
+func (p *AWSProvider) UnsetTag(context.Context, ObjectRef, string) error {
+	return ErrReadOnly
+}
+
+// arnRegion returns the region field of an ARN, which is empty for global
+// resources such as S3 buckets.
+func arnRegion(arn string) string {
+	parts := strings.SplitN(arn, ":", 5)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[3]
+}
+
+// readMappedLabels implements Provider.ReadTags for label readers.
+func readMappedLabels(ctx context.Context, reader LabelReader, obj ObjectRef, tags []string) (map[string]string, error) {
+	labels, err := reader.ReadLabels(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for _, tag := range tags {
+		if value, ok := labels[tag]; ok {
+			result[tag] = value
+		}
+	}
+	return result, nil
+}
+
+var (
+	_ LabelReader = (*AWSProvider)(nil)
+	_ ProviderSet = (*AWSProvider)(nil)
+)
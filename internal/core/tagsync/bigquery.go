@@ -0,0 +1,338 @@
+package tagsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/mrn"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	bigQueryProviderName = "BigQuery"
+	bigQueryBaseURL      = "https://bigquery.googleapis.com/bigquery/v2"
+	bigQueryScope        = "https://www.googleapis.com/auth/cloud-platform"
+	bigQueryLabelMaxLen  = 63
+)
+
+// BigQueryConfig configures access to the BigQuery API. Application default
+// credentials are used when CredentialsFile is empty.
+type BigQueryConfig struct {
+	ProjectID       string
+	CredentialsFile string
+	HTTPClient      *http.Client
+}
+
+// BigQueryProvider syncs tag categories with table labels and column policy
+// tags with BigQuery policy tags.
+type BigQueryProvider struct {
+	projectID string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewBigQueryProvider creates an authenticated BigQuery provider.
+func NewBigQueryProvider(ctx context.Context, config BigQueryConfig) (*BigQueryProvider, error) {
+	client := config.HTTPClient
+	if client == nil {
+		var creds *google.Credentials
+		var err error
+		if config.CredentialsFile != "" {
+			data, readErr := os.ReadFile(config.CredentialsFile)
+			if readErr != nil {
+				return nil, fmt.Errorf("reading bigquery credentials: %w", readErr)
+			}
+			creds, err = google.CredentialsFromJSON(ctx, data, bigQueryScope)
+		} else {
+			creds, err = google.FindDefaultCredentials(ctx, bigQueryScope)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading bigquery credentials: %w", err)
+		}
+		client = oauth2.NewClient(ctx, creds.TokenSource)
+	}
+
+	return &BigQueryProvider{
+		projectID: config.ProjectID,
+		baseURL:   bigQueryBaseURL,
+		client:    client,
+	}, nil
+}
+
+func (p *BigQueryProvider) Name() string {
+	return bigQueryProviderName
+}
+
+// Resolve prefers the project, dataset and table recorded by the BigQuery
+// plugin and falls back to a project.dataset.table MRN name, as produced by
+// the dbt plugin.
+func (p *BigQueryProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil || a.IsStub {
+		return ObjectRef{}, false
+	}
+
+	project, _ := a.Metadata["project_id"].(string)
+	dataset, _ := a.Metadata["dataset_id"].(string)
+	table, _ := a.Metadata["table_id"].(string)
+
+	if project == "" || dataset == "" || table == "" {
+		parsed, err := mrn.Parse(*a.MRN)
+		if err != nil || parsed.Service != "bigquery" {
+			return ObjectRef{}, false
+		}
+		parts := strings.Split(parsed.Name, ".")
+		switch {
+		case len(parts) == 3:
+			project, dataset, table = parts[0], parts[1], parts[2]
+		case len(parts) == 2 && p.projectID != "":
+			project, dataset, table = p.projectID, parts[0], parts[1]
+		default:
+			return ObjectRef{}, false
+		}
+	}
+
+	return ObjectRef{
+		AssetID: a.ID,
+		MRN:     *a.MRN,
+		Kind:    a.Type,
+		Path:    project + "." + dataset + "." + table,
+	}, true
+}
+
+// NormalizeValue maps a value onto the characters BigQuery allows in labels.
+func (p *BigQueryProvider) NormalizeValue(value string) string {
+	return bigQueryLabel(value)
+}
+
+func (p *BigQueryProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	table, err := p.getTable(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, tag := range tags {
+		if value, ok := table.Labels[bigQueryLabel(tag)]; ok {
+			result[tag] = value
+		}
+	}
+	return result, nil
+}
+
+func (p *BigQueryProvider) SetTag(ctx context.Context, obj ObjectRef, tag, value string) error {
+	return p.patchTable(ctx, obj, map[string]any{
+		"labels": map[string]any{bigQueryLabel(tag): bigQueryLabel(value)},
+	})
+}
+
+func (p *BigQueryProvider) UnsetTag(ctx context.Context, obj ObjectRef, tag string) error {
+	return p.patchTable(ctx, obj, map[string]any{
+		"labels": map[string]any{bigQueryLabel(tag): nil},
+	})
+}
+
+// ReadColumnTags returns policy tags keyed by column, with nested fields
+// addressed by their dotted path.
+func (p *BigQueryProvider) ReadColumnTags(ctx context.Context, obj ObjectRef) (map[string][]string, error) {
+	table, err := p.getTable(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	walkBigQueryFields(table.Schema.Fields, "", func(path string, field map[string]any) {
+		if names := bigQueryPolicyTags(field); len(names) > 0 {
+			result[path] = names
+		}
+	})
+	return result, nil
+}
+
+// WriteColumnTags rewrites the schema with updated policy tags. BigQuery
+// replaces the whole schema on patch, so the current schema is read first
+// and only the policyTags of the given columns are changed.
+func (p *BigQueryProvider) WriteColumnTags(ctx context.Context, obj ObjectRef, tags map[string][]string) error {
+	table, err := p.getTable(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool, len(tags))
+	walkBigQueryFields(table.Schema.Fields, "", func(path string, field map[string]any) {
+		names, ok := tags[path]
+		if !ok {
+			return
+		}
+		found[path] = true
+		if names == nil {
+			names = []string{}
+		}
+		field["policyTags"] = map[string]any{"names": names}
+	})
+
+	for column := range tags {
+		if !found[column] {
+			return fmt.Errorf("column %q not found in %s", column, obj.Path)
+		}
+	}
+
+	return p.patchTable(ctx, obj, map[string]any{
+		"schema": map[string]any{"fields": table.Schema.Fields},
+	})
+}
+
+type bigQueryTable struct {
+	Labels map[string]string `json:"labels"`
+	Schema struct {
+		Fields []map[string]any `json:"fields"`
+	} `json:"schema"`
+}
+
+func (p *BigQueryProvider) tableURL(obj ObjectRef) (string, error) {
+	parts := strings.SplitN(obj.Path, ".", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid bigquery table path %q", obj.Path)
+	}
+	return fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s", p.baseURL,
+		url.PathEscape(parts[0]), url.PathEscape(parts[1]), url.PathEscape(parts[2])), nil
+}
+
+func (p *BigQueryProvider) getTable(ctx context.Context, obj ObjectRef) (*bigQueryTable, error) {
+	var table bigQueryTable
+	if err := p.do(ctx, http.MethodGet, obj, nil, &table); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (p *BigQueryProvider) patchTable(ctx context.Context, obj ObjectRef, body map[string]any) error {
+	return p.do(ctx, http.MethodPatch, obj, body, nil)
+}
+
+func (p *BigQueryProvider) do(ctx context.Context, method string, obj ObjectRef, body any, out any) error {
+	endpoint, err := p.tableURL(obj)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling bigquery: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("bigquery %s %s (%d): %s", method, obj.Path, resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("bigquery %s %s: unexpected status %d", method, obj.Path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding bigquery response: %w", err)
+	}
+	return nil
+}
+
+func walkBigQueryFields(fields []map[string]any, prefix string, fn func(path string, field map[string]any)) {
+	for _, field := range fields {
+		name, _ := field["name"].(string)
+		if name == "" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fn(path, field)
+
+		nested, ok := field["fields"].([]any)
+		if !ok {
+			continue
+		}
+		children := make([]map[string]any, 0, len(nested))
+		for _, n := range nested {
+			if child, ok := n.(map[string]any); ok {
+				children = append(children, child)
+			}
+		}
+		walkBigQueryFields(children, path, fn)
+	}
+}
+
+func bigQueryPolicyTags(field map[string]any) []string {
+	tags, ok := field["policyTags"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	raw, ok := tags["names"].([]any)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(raw))
+	for _, n := range raw {
+		if s, ok := n.(string); ok && s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
+
+// bigQueryLabel lower-cases s and replaces characters labels do not allow.
+func bigQueryLabel(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	label := []rune(b.String())
+	if len(label) > bigQueryLabelMaxLen {
+		label = label[:bigQueryLabelMaxLen]
+	}
+	return string(label)
+}
+
+var (
+	_ ColumnTagProvider = (*BigQueryProvider)(nil)
+	_ ValueNormalizer   = (*BigQueryProvider)(nil)
+)
@@ -0,0 +1,154 @@
+package tagsync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bigQueryTestSchema = `{
+	"labels": {"data_domain": "sales"},
+	"schema": {"fields": [
+		{"name": "id", "type": "INTEGER"},
+		{"name": "email", "type": "STRING", "policyTags": {"names": ["other"]}},
+		{"name": "address", "type": "RECORD", "fields": [
+			{"name": "street", "type": "STRING", "policyTags": {"names": ["pii"]}}
+		]}
+	]}
+}`
+
+// fakeBigQuery serves a single table and records schema patches.
+type fakeBigQuery struct {
+	paths   []string
+	patches []map[string]any
+}
+
+func newBigQueryTestProvider(t *testing.T) (*BigQueryProvider, *fakeBigQuery) {
+	t.Helper()
+	fake := &fakeBigQuery{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.paths = append(fake.paths, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(bigQueryTestSchema))
+		case http.MethodPatch:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			fake.patches = append(fake.patches, body)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewBigQueryProvider(context.Background(), BigQueryConfig{ProjectID: "proj", HTTPClient: server.Client()})
+	require.NoError(t, err)
+	provider.baseURL = server.URL
+	return provider, fake
+}
+
+var bigQueryTestTable = ObjectRef{Path: "proj.shop.orders"}
+
+func TestBigQueryReadColumnTags(t *testing.T) {
+	provider, fake := newBigQueryTestProvider(t)
+
+	tags, err := provider.ReadColumnTags(context.Background(), bigQueryTestTable)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"email": {"other"}, "address.street": {"pii"}}, tags)
+	assert.Equal(t, []string{"GET /projects/proj/datasets/shop/tables/orders"}, fake.paths)
+}
+
+func TestBigQueryWriteColumnTags(t *testing.T) {
+	provider, fake := newBigQueryTestProvider(t)
+
+	err := provider.WriteColumnTags(context.Background(), bigQueryTestTable, map[string][]string{
+		"email":          {"pii"},
+		"address.street": nil,
+	})
+	require.NoError(t, err)
+	require.Len(t, fake.patches, 1)
+
+	// The whole schema is sent back with only the given columns changed.
+	fields := fake.patches[0]["schema"].(map[string]any)["fields"].([]any)
+	require.Len(t, fields, 3)
+	assert.NotContains(t, fields[0], "policyTags")
+	assert.Equal(t, map[string]any{"names": []any{"pii"}}, fields[1].(map[string]any)["policyTags"])
+	street := fields[2].(map[string]any)["fields"].([]any)[0].(map[string]any)
+	assert.Equal(t, map[string]any{"names": []any{}}, street["policyTags"])
+}
+
+func TestBigQueryWriteColumnTagsUnknownColumn(t *testing.T) {
+	provider, fake := newBigQueryTestProvider(t)
+
+	err := provider.WriteColumnTags(context.Background(), bigQueryTestTable, map[string][]string{"missing": {"pii"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `column "missing" not found`)
+	assert.Empty(t, fake.patches)
+}
+
+func TestBigQueryReadTagsNormalizesLabels(t *testing.T) {
+	provider, _ := newBigQueryTestProvider(t)
+
+	tags, err := provider.ReadTags(context.Background(), bigQueryTestTable, []string{"DATA_DOMAIN", "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATA_DOMAIN": "sales"}, tags)
+}
+
+func TestBigQueryLabel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"sales", "sales"},
+		{"DATA_DOMAIN", "data_domain"},
+		{"Customer Data", "customer_data"},
+		{"eu-west", "eu-west"},
+		{"a.b/c", "a_b_c"},
+		{"café", "café"},
+		{strings.Repeat("x", 70), strings.Repeat("x", bigQueryLabelMaxLen)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, bigQueryLabel(tt.in), "label for %q", tt.in)
+	}
+}
+
+func TestBigQueryResolve(t *testing.T) {
+	mrn := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		asset    *asset.Asset
+		wantPath string
+		wantOK   bool
+	}{
+		{
+			name: "plugin metadata",
+			asset: &asset.Asset{MRN: mrn("mrn://table/bigquery/anything"), Metadata: map[string]interface{}{
+				"project_id": "p", "dataset_id": "d", "table_id": "t",
+			}},
+			wantPath: "p.d.t",
+			wantOK:   true,
+		},
+		{name: "fully qualified mrn", asset: &asset.Asset{MRN: mrn("mrn://table/bigquery/p.d.t")}, wantPath: "p.d.t", wantOK: true},
+		{name: "dataset and table use configured project", asset: &asset.Asset{MRN: mrn("mrn://table/bigquery/d.t")}, wantPath: "proj.d.t", wantOK: true},
+		{name: "other service", asset: &asset.Asset{MRN: mrn("mrn://table/snowflake/p.d.t")}},
+		{name: "table only", asset: &asset.Asset{MRN: mrn("mrn://table/bigquery/t")}},
+		{name: "stub", asset: &asset.Asset{MRN: mrn("mrn://table/bigquery/p.d.t"), IsStub: true}},
+		{name: "no mrn", asset: &asset.Asset{}},
+	}
+
+	provider := &BigQueryProvider{projectID: "proj"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, ok := provider.Resolve(tt.asset)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantPath, obj.Path)
+		})
+	}
+}
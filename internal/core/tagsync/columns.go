@@ -0,0 +1,229 @@
+package tagsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/policytag"
+)
+
+// ColumnMapping links a Marmot policy tag, by name, to a column-level tag in
+// the remote system such as a BigQuery policy tag resource name.
+type ColumnMapping struct {
+	PolicyTag  string `json:"policy_tag"`
+	RemoteTag  string `json:"remote_tag"`
+	Direction  string `json:"direction"`
+	OnConflict string `json:"on_conflict"`
+}
+
+// ColumnTagProvider is implemented by providers that support column-level
+// tags. A column carries at most one remote tag, so pushing a tag onto a
+// column that already has a different one is treated as a conflict.
+type ColumnTagProvider interface {
+	// ReadColumnTags returns the remote tags on each tagged column.
+	ReadColumnTags(ctx context.Context, obj ObjectRef) (map[string][]string, error)
+	// WriteColumnTags replaces the tags on the given columns.
+	WriteColumnTags(ctx context.Context, obj ObjectRef, tags map[string][]string) error
+}
+
+// PolicyTagStore is the subset of the policy tag service used for column sync.
+type PolicyTagStore interface {
+	List(ctx context.Context) ([]*policytag.PolicyTag, error)
+	ListForAsset(ctx context.Context, assetID string) ([]*policytag.Assignment, error)
+	Assign(ctx context.Context, assetID, tagID, column string, createdBy *string) (*policytag.Assignment, error)
+	Unassign(ctx context.Context, assetID, tagID, column string) error
+}
+
+// SetColumnMappings enables column-level sync between Marmot policy tag
+// assignments and the provider's column tags.
+func (s *Service) SetColumnMappings(store PolicyTagStore, mappings []ColumnMapping) error {
+	if _, ok := s.provider.(ColumnTagProvider); !ok && len(mappings) > 0 {
+		return fmt.Errorf("%w: %s does not support column tags", ErrInvalidMapping, s.provider.Name())
+	}
+
+	normalized := make([]ColumnMapping, 0, len(mappings))
+	seen := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		m.PolicyTag = strings.TrimSpace(m.PolicyTag)
+		m.RemoteTag = strings.TrimSpace(m.RemoteTag)
+		if err := normalizeDirection(&m.Direction, &m.OnConflict); err != nil {
+			return err
+		}
+
+		if m.PolicyTag == "" || m.RemoteTag == "" {
+			return fmt.Errorf("%w: policy tag and remote tag are required", ErrInvalidMapping)
+		}
+		if seen[m.PolicyTag] {
+			return fmt.Errorf("%w: policy tag %q is mapped more than once", ErrInvalidMapping, m.PolicyTag)
+		}
+		seen[m.PolicyTag] = true
+
+		normalized = append(normalized, m)
+	}
+
+	s.policyTags = store
+	s.columnMappings = normalized
+	return nil
+}
+
+// ColumnMappings returns the normalized column policy tag mappings.
+func (s *Service) ColumnMappings() []ColumnMapping {
+	return s.columnMappings
+}
+
+// policyTagIDs resolves the mapped policy tag names to IDs. Mappings whose
+// policy tag does not exist in Marmot are reported as an error, since
+// silently skipping them would hide a typo in the configuration.
+func (s *Service) policyTagIDs(ctx context.Context) (map[string]string, error) {
+	if s.policyTags == nil || len(s.columnMappings) == 0 {
+		return nil, nil
+	}
+
+	tags, err := s.policyTags.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing policy tags: %w", err)
+	}
+
+	byName := make(map[string]string, len(tags))
+	for _, t := range tags {
+		byName[t.Name] = t.ID
+	}
+
+	ids := make(map[string]string, len(s.columnMappings))
+	for _, m := range s.columnMappings {
+		id, ok := byName[m.PolicyTag]
+		if !ok {
+			return nil, fmt.Errorf("%w: policy tag %q does not exist", ErrInvalidMapping, m.PolicyTag)
+		}
+		ids[m.PolicyTag] = id
+	}
+	return ids, nil
+}
+
+func (s *Service) syncColumns(ctx context.Context, a *asset.Asset, obj ObjectRef, policyTagIDs map[string]string, opts SyncOptions) ([]Change, error) {
+	provider := s.provider.(ColumnTagProvider)
+
+	remote, err := provider.ReadColumnTags(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading column tags: %w", err)
+	}
+
+	assignments, err := s.policyTags.ListForAsset(ctx, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing policy tag assignments: %w", err)
+	}
+
+	local := make(map[string]map[string]bool)
+	for _, asg := range assignments {
+		if asg.Column == "" {
+			continue
+		}
+		if local[asg.PolicyTag.Name] == nil {
+			local[asg.PolicyTag.Name] = make(map[string]bool)
+		}
+		local[asg.PolicyTag.Name][asg.Column] = true
+	}
+
+	// desired starts as the remote state and is edited in place so later
+	// mappings see the columns earlier ones have claimed.
+	desired := make(map[string][]string, len(remote))
+	for column, tags := range remote {
+		desired[column] = slices.Clone(tags)
+	}
+	dirty := make(map[string]bool)
+
+	var changes []Change
+	var errs []error
+
+	for _, m := range s.columnMappings {
+		columns := make(map[string]bool)
+		for column := range local[m.PolicyTag] {
+			columns[column] = true
+		}
+		for column, tags := range remote {
+			if slices.Contains(tags, m.RemoteTag) {
+				columns[column] = true
+			}
+		}
+
+		for _, column := range sortedKeys(columns) {
+			hasLocal := local[m.PolicyTag][column]
+			hasRemote := slices.Contains(desired[column], m.RemoteTag)
+			if hasLocal == hasRemote {
+				continue
+			}
+
+			change := Change{
+				AssetID:   a.ID,
+				MRN:       obj.MRN,
+				PolicyTag: m.PolicyTag,
+				Column:    column,
+				RemoteTag: m.RemoteTag,
+			}
+			change.Action, _ = resolve(m.Direction, m.OnConflict, hasLocal, hasRemote)
+
+			if change.Action == ActionSet && len(desired[column]) > 0 {
+				change.Previous = strings.Join(desired[column], valueSeparator)
+				switch m.OnConflict {
+				case ConflictRemoteWins, ConflictSkip:
+					change.Action = ActionSkip
+				}
+				change.Resolution = m.OnConflict
+			}
+
+			switch change.Action {
+			case ActionSet:
+				change.Value = m.RemoteTag
+				desired[column] = []string{m.RemoteTag}
+				dirty[column] = true
+			case ActionUnset:
+				change.Previous = m.RemoteTag
+				desired[column] = slices.DeleteFunc(desired[column], func(t string) bool { return t == m.RemoteTag })
+				dirty[column] = true
+			case ActionPull:
+				change.Value = m.PolicyTag
+				if !opts.DryRun {
+					if _, err := s.policyTags.Assign(ctx, a.ID, policyTagIDs[m.PolicyTag], column, nil); err != nil {
+						errs = append(errs, fmt.Errorf("assigning %s to %s: %w", m.PolicyTag, column, err))
+						continue
+					}
+				}
+			case ActionRemove:
+				change.Previous = m.PolicyTag
+				if !opts.DryRun {
+					if err := s.policyTags.Unassign(ctx, a.ID, policyTagIDs[m.PolicyTag], column); err != nil {
+						errs = append(errs, fmt.Errorf("unassigning %s from %s: %w", m.PolicyTag, column, err))
+						continue
+					}
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	if len(dirty) > 0 && !opts.DryRun {
+		updates := make(map[string][]string, len(dirty))
+		for column := range dirty {
+			updates[column] = desired[column]
+		}
+		if err := provider.WriteColumnTags(ctx, obj, updates); err != nil {
+			errs = append(errs, fmt.Errorf("writing column tags: %w", err))
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,247 @@
+package tagsync
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/policytag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const piiPolicyTag = "projects/p/locations/us/taxonomies/1/policyTags/pii"
+
+// fakeColumnProvider keeps column tags for a single table in memory.
+type fakeColumnProvider struct {
+	*fakeTagProvider
+	columns map[string][]string
+	written []map[string][]string
+}
+
+func (p *fakeColumnProvider) ReadColumnTags(context.Context, ObjectRef) (map[string][]string, error) {
+	result := make(map[string][]string, len(p.columns))
+	for column, tags := range p.columns {
+		result[column] = slices.Clone(tags)
+	}
+	return result, nil
+}
+
+func (p *fakeColumnProvider) WriteColumnTags(_ context.Context, _ ObjectRef, tags map[string][]string) error {
+	p.written = append(p.written, tags)
+	for column, names := range tags {
+		p.columns[column] = names
+	}
+	return nil
+}
+
+// fakePolicyTags holds the "PII" policy tag and its column assignments.
+type fakePolicyTags struct {
+	tag     *policytag.PolicyTag
+	columns map[string]bool
+}
+
+func newFakePolicyTags(columns ...string) *fakePolicyTags {
+	s := &fakePolicyTags{tag: &policytag.PolicyTag{ID: "tag-pii", Name: "PII"}, columns: map[string]bool{}}
+	for _, c := range columns {
+		s.columns[c] = true
+	}
+	return s
+}
+
+func (s *fakePolicyTags) List(context.Context) ([]*policytag.PolicyTag, error) {
+	return []*policytag.PolicyTag{s.tag}, nil
+}
+
+func (s *fakePolicyTags) ListForAsset(_ context.Context, assetID string) ([]*policytag.Assignment, error) {
+	var result []*policytag.Assignment
+	for _, column := range sortedKeys(s.columns) {
+		result = append(result, &policytag.Assignment{AssetID: assetID, Column: column, PolicyTag: *s.tag})
+	}
+	return result, nil
+}
+
+func (s *fakePolicyTags) Assign(_ context.Context, assetID, tagID, column string, _ *string) (*policytag.Assignment, error) {
+	s.columns[column] = true
+	return &policytag.Assignment{AssetID: assetID, Column: column, PolicyTag: *s.tag}, nil
+}
+
+func (s *fakePolicyTags) Unassign(_ context.Context, _, _, column string) error {
+	delete(s.columns, column)
+	return nil
+}
+
+func TestSyncColumnsConflictsAndDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		direction   string
+		onConflict  string
+		local       []string
+		remote      map[string][]string
+		dryRun      bool
+		wantAction  string
+		wantRes     string
+		wantPrev    string
+		wantLocal   []string
+		wantRemote  map[string][]string
+		wantWritten bool
+	}{
+		{
+			name:        "push tags untagged column",
+			direction:   DirectionPush,
+			local:       []string{"email"},
+			remote:      map[string][]string{},
+			wantAction:  ActionSet,
+			wantLocal:   []string{"email"},
+			wantRemote:  map[string][]string{"email": {piiPolicyTag}},
+			wantWritten: true,
+		},
+		{
+			name:        "push removes tag not assigned in marmot",
+			direction:   DirectionPush,
+			remote:      map[string][]string{"email": {piiPolicyTag}},
+			wantAction:  ActionUnset,
+			wantPrev:    piiPolicyTag,
+			wantRemote:  map[string][]string{"email": {}},
+			wantWritten: true,
+		},
+		{
+			name:       "pull assigns remote tag",
+			direction:  DirectionPull,
+			remote:     map[string][]string{"email": {piiPolicyTag}},
+			wantAction: ActionPull,
+			wantLocal:  []string{"email"},
+			wantRemote: map[string][]string{"email": {piiPolicyTag}},
+		},
+		{
+			name:       "pull unassigns tag missing remotely",
+			direction:  DirectionPull,
+			local:      []string{"email"},
+			remote:     map[string][]string{},
+			wantAction: ActionRemove,
+			wantPrev:   "PII",
+			wantRemote: map[string][]string{},
+		},
+		{
+			name:        "other remote tag replaced when marmot wins",
+			direction:   DirectionPush,
+			onConflict:  ConflictMarmotWins,
+			local:       []string{"email"},
+			remote:      map[string][]string{"email": {"other"}},
+			wantAction:  ActionSet,
+			wantRes:     ConflictMarmotWins,
+			wantPrev:    "other",
+			wantLocal:   []string{"email"},
+			wantRemote:  map[string][]string{"email": {piiPolicyTag}},
+			wantWritten: true,
+		},
+		{
+			name:       "other remote tag kept when remote wins",
+			direction:  DirectionPush,
+			onConflict: ConflictRemoteWins,
+			local:      []string{"email"},
+			remote:     map[string][]string{"email": {"other"}},
+			wantAction: ActionSkip,
+			wantRes:    ConflictRemoteWins,
+			wantPrev:   "other",
+			wantLocal:  []string{"email"},
+			wantRemote: map[string][]string{"email": {"other"}},
+		},
+		{
+			name:       "other remote tag kept when skipping",
+			direction:  DirectionBoth,
+			onConflict: ConflictSkip,
+			local:      []string{"email"},
+			remote:     map[string][]string{"email": {"other"}},
+			wantAction: ActionSkip,
+			wantRes:    ConflictSkip,
+			wantPrev:   "other",
+			wantLocal:  []string{"email"},
+			wantRemote: map[string][]string{"email": {"other"}},
+		},
+		{
+			name:       "dry run push writes nothing",
+			direction:  DirectionPush,
+			onConflict: ConflictMarmotWins,
+			local:      []string{"email"},
+			remote:     map[string][]string{"email": {"other"}},
+			dryRun:     true,
+			wantAction: ActionSet,
+			wantRes:    ConflictMarmotWins,
+			wantPrev:   "other",
+			wantLocal:  []string{"email"},
+			wantRemote: map[string][]string{"email": {"other"}},
+		},
+		{
+			name:       "dry run pull assigns nothing",
+			direction:  DirectionPull,
+			remote:     map[string][]string{"email": {piiPolicyTag}},
+			dryRun:     true,
+			wantAction: ActionPull,
+			wantRemote: map[string][]string{"email": {piiPolicyTag}},
+		},
+		{
+			name:       "dry run remove unassigns nothing",
+			direction:  DirectionPull,
+			local:      []string{"email"},
+			remote:     map[string][]string{},
+			dryRun:     true,
+			wantAction: ActionRemove,
+			wantPrev:   "PII",
+			wantLocal:  []string{"email"},
+			wantRemote: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := newFakeCatalog(1)
+			provider := &fakeColumnProvider{fakeTagProvider: newFakeTagProvider(), columns: tt.remote}
+			store := newFakePolicyTags(tt.local...)
+
+			svc, err := NewService(catalog, provider, nil)
+			require.NoError(t, err)
+			require.NoError(t, svc.SetColumnMappings(store, []ColumnMapping{
+				{PolicyTag: "PII", RemoteTag: piiPolicyTag, Direction: tt.direction, OnConflict: tt.onConflict},
+			}))
+
+			report, err := svc.Sync(context.Background(), SyncOptions{DryRun: tt.dryRun})
+			require.NoError(t, err)
+			assert.Empty(t, report.Errors)
+			require.Len(t, report.Changes, 1)
+			change := report.Changes[0]
+			assert.Equal(t, "email", change.Column)
+			assert.Equal(t, tt.wantAction, change.Action)
+			assert.Equal(t, tt.wantRes, change.Resolution)
+			assert.Equal(t, tt.wantPrev, change.Previous)
+
+			local := sortedKeys(store.columns)
+			if len(tt.wantLocal) == 0 {
+				assert.Empty(t, local)
+			} else {
+				assert.Equal(t, tt.wantLocal, local)
+			}
+			assert.Equal(t, tt.wantRemote, provider.columns)
+			assert.Equal(t, tt.wantWritten, len(provider.written) > 0)
+		})
+	}
+}
+
+func TestSetColumnMappingsRequiresColumnProvider(t *testing.T) {
+	svc, err := NewService(&fakeAssetService{asset: &asset.Asset{}}, newFakeTagProvider(), nil)
+	require.NoError(t, err)
+
+	err = svc.SetColumnMappings(newFakePolicyTags(), []ColumnMapping{{PolicyTag: "PII", RemoteTag: piiPolicyTag}})
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+}
+
+func TestSyncColumnsUnknownPolicyTag(t *testing.T) {
+	provider := &fakeColumnProvider{fakeTagProvider: newFakeTagProvider(), columns: map[string][]string{}}
+	svc, err := NewService(newFakeCatalog(1), provider, nil)
+	require.NoError(t, err)
+	require.NoError(t, svc.SetColumnMappings(newFakePolicyTags(), []ColumnMapping{{PolicyTag: "Secret", RemoteTag: piiPolicyTag}}))
+
+	_, err = svc.Sync(context.Background(), SyncOptions{})
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+}
@@ -0,0 +1,151 @@
+package tagsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/mrn"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcsProviderName       = "GCS"
+	gcsDefaultMetadataKey = "gcp_labels"
+	gcsBaseURL            = "https://storage.googleapis.com/storage/v1"
+	gcsScope              = "https://www.googleapis.com/auth/devstorage.read_only"
+)
+
+// GCSConfig configures access to the Cloud Storage API. Application default
+// credentials are used when CredentialsFile is empty.
+type GCSConfig struct {
+	CredentialsFile string
+	MetadataKey     string
+	HTTPClient      *http.Client
+}
+
+// GCSProvider imports Cloud Storage bucket labels. It is read-only.
+type GCSProvider struct {
+	baseURL     string
+	metadataKey string
+	client      *http.Client
+}
+
+// NewGCSProvider creates an authenticated Cloud Storage label provider.
+func NewGCSProvider(ctx context.Context, config GCSConfig) (*GCSProvider, error) {
+	client := config.HTTPClient
+	if client == nil {
+		var creds *google.Credentials
+		var err error
+		if config.CredentialsFile != "" {
+			data, readErr := os.ReadFile(config.CredentialsFile)
+			if readErr != nil {
+				return nil, fmt.Errorf("reading gcs credentials: %w", readErr)
+			}
+			creds, err = google.CredentialsFromJSON(ctx, data, gcsScope)
+		} else {
+			creds, err = google.FindDefaultCredentials(ctx, gcsScope)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading gcs credentials: %w", err)
+		}
+		client = oauth2.NewClient(ctx, creds.TokenSource)
+	}
+
+	key := config.MetadataKey
+	if key == "" {
+		key = gcsDefaultMetadataKey
+	}
+
+	return &GCSProvider{
+		baseURL:     gcsBaseURL,
+		metadataKey: key,
+		client:      client,
+	}, nil
+}
+
+func (p *GCSProvider) Name() string {
+	return gcsProviderName
+}
+
+func (p *GCSProvider) MetadataKey() string {
+	return p.metadataKey
+}
+
+// Resolve prefers the bucket name recorded by the GCS plugin and falls back
+// to the MRN name.
+func (p *GCSProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil || a.IsStub {
+		return ObjectRef{}, false
+	}
+
+	bucket, _ := a.Metadata["bucket_name"].(string)
+	if bucket == "" {
+		parsed, err := mrn.Parse(*a.MRN)
+		if err != nil || parsed.Service != "gcs" {
+			return ObjectRef{}, false
+		}
+		bucket = parsed.Name
+	}
+
+	return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: bucket}, true
+}
+
+func (p *GCSProvider) ReadLabels(ctx context.Context, obj ObjectRef) (map[string]string, error) {
+	endpoint := fmt.Sprintf("%s/b/%s?fields=labels", p.baseURL, url.PathEscape(obj.Path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling gcs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("gcs bucket %s (%d): %s", obj.Path, resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("gcs bucket %s: unexpected status %d", obj.Path, resp.StatusCode)
+	}
+
+	var bucket struct {
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bucket); err != nil {
+		return nil, fmt.Errorf("decoding gcs response: %w", err)
+	}
+	if bucket.Labels == nil {
+		bucket.Labels = map[string]string{}
+	}
+	return bucket.Labels, nil
+}
+
+func (p *GCSProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	return readMappedLabels(ctx, p, obj, tags)
+}
+
+func (p *GCSProvider) SetTag(context.Context, ObjectRef, string, string) error {
+	return ErrReadOnly
+}
+
+func (p *GCSProvider) UnsetTag(context.Context, ObjectRef, string) error {
+	return ErrReadOnly
+}
+
+var _ LabelReader = (*GCSProvider)(nil)
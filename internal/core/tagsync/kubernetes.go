@@ -0,0 +1,159 @@
+package tagsync
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	kubernetesProviderName       = "Kubernetes"
+	kubernetesDefaultMetadataKey = "kubernetes_labels"
+)
+
+// kubernetesResources maps the asset types of the Kubernetes plugin to the
+// resources behind them.
+var kubernetesResources = map[string]schema.GroupVersionResource{
+	"Namespace":   {Version: "v1", Resource: "namespaces"},
+	"Service":     {Version: "v1", Resource: "services"},
+	"Pod":         {Version: "v1", Resource: "pods"},
+	"Deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"StatefulSet": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"CronJob":     {Group: "batch", Version: "v1", Resource: "cronjobs"},
+}
+
+// KubernetesConfig configures access to a cluster. The in-cluster service
+// account is used when Kubeconfig is empty.
+type KubernetesConfig struct {
+	Kubeconfig string
+	Context    string
+	// ClusterName must match the cluster name of the Kubernetes plugin so
+	// only that cluster's assets are read.
+	ClusterName string
+	MetadataKey string
+}
+
+// KubernetesProvider imports the labels of Kubernetes objects. It is
+// read-only.
+type KubernetesProvider struct {
+	client      metadata.Interface
+	clusterName string
+	metadataKey string
+}
+
+// NewKubernetesProvider creates a Kubernetes label provider.
+func NewKubernetesProvider(config KubernetesConfig) (*KubernetesProvider, error) {
+	var restConfig *rest.Config
+	var err error
+	if config.Kubeconfig != "" {
+		restConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: config.Context},
+		).ClientConfig()
+	} else {
+		restConfig, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading kubernetes config: %w", err)
+	}
+
+	client, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client: %w", err)
+	}
+
+	return newKubernetesProvider(client, config), nil
+}
+
+func newKubernetesProvider(client metadata.Interface, config KubernetesConfig) *KubernetesProvider {
+	key := config.MetadataKey
+	if key == "" {
+		key = kubernetesDefaultMetadataKey
+	}
+	return &KubernetesProvider{
+		client:      client,
+		clusterName: config.ClusterName,
+		metadataKey: key,
+	}
+}
+
+func (p *KubernetesProvider) Name() string {
+	return kubernetesProviderName
+}
+
+func (p *KubernetesProvider) MetadataKey() string {
+	return p.metadataKey
+}
+
+// Resolve maps an asset to its object from the namespace and cluster the
+// Kubernetes plugin records. Objects of other clusters are skipped.
+func (p *KubernetesProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil || a.Name == nil || a.IsStub {
+		return ObjectRef{}, false
+	}
+	if _, ok := kubernetesResources[a.Type]; !ok {
+		return ObjectRef{}, false
+	}
+	if cluster, _ := a.Metadata["cluster"].(string); cluster != p.clusterName {
+		return ObjectRef{}, false
+	}
+
+	name := path.Base(*a.Name)
+	if a.Type == "Namespace" {
+		return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: name}, true
+	}
+	namespace, _ := a.Metadata["namespace"].(string)
+	if namespace == "" {
+		return ObjectRef{}, false
+	}
+	return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: namespace + "/" + name}, true
+}
+
+func (p *KubernetesProvider) ReadLabels(ctx context.Context, obj ObjectRef) (map[string]string, error) {
+	gvr, ok := kubernetesResources[obj.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported kubernetes kind %q", obj.Kind)
+	}
+
+	resource := p.client.Resource(gvr)
+	var (
+		meta *metav1.PartialObjectMetadata
+		err  error
+	)
+	if namespace, name, namespaced := strings.Cut(obj.Path, "/"); namespaced {
+		meta, err = resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		meta, err = resource.Get(ctx, obj.Path, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting %s %s: %w", strings.ToLower(obj.Kind), obj.Path, err)
+	}
+
+	labels := meta.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return labels, nil
+}
+
+func (p *KubernetesProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	return readMappedLabels(ctx, p, obj, tags)
+}
+
+func (p *KubernetesProvider) SetTag(context.Context, ObjectRef, string, string) error {
+	return ErrReadOnly
+}
+
+func (p *KubernetesProvider) UnsetTag(context.Context, ObjectRef, string) error {
+	return ErrReadOnly
+}
+
+var _ LabelReader = (*KubernetesProvider)(nil)
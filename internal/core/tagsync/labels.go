@@ -0,0 +1,207 @@
+package tagsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+// ResolutionLocked is reported when a label would change the tags of an
+// asset whose tags are locked against ingestion.
+const ResolutionLocked = "locked"
+
+// ErrReadOnly is returned when writing to a provider that can only be read.
+var ErrReadOnly = errors.New("provider is read-only")
+
+// LabelReader is implemented by read-only providers such as cloud resource
+// tags and Kubernetes labels. Every label on an object is imported into the
+// asset's metadata under MetadataKey, replacing what the previous run
+// imported, and mappings can only pull.
+type LabelReader interface {
+	MetadataKey() string
+	ReadLabels(ctx context.Context, obj ObjectRef) (map[string]string, error)
+}
+
+// ProviderSet is implemented by providers whose objects are spread over
+// several Marmot providers, such as the individual AWS services.
+type ProviderSet interface {
+	Providers() []string
+}
+
+// syncLabels imports an object's labels into the asset's metadata and pulls
+// mapped labels into tag categories.
+//
+// The labels imported by the previous run are what tells a pulled tag from
+// one set by hand: a catalog value that differs from the last imported label
+// was set in Marmot, and the mapping's conflict setting decides whether it is
+// overwritten. Tags set by hand are never removed because a label is missing.
+func (s *Service) syncLabels(ctx context.Context, a *asset.Asset, obj ObjectRef, reader LabelReader, opts SyncOptions) ([]Change, error) {
+	labels, err := reader.ReadLabels(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading labels: %w", err)
+	}
+
+	key := reader.MetadataKey()
+	previous := importedLabels(a.Metadata[key])
+	local := categoryValues(a.Tags)
+	locked := a.IsLocked(asset.FieldTags)
+
+	tags := a.Tags
+	tagsChanged := false
+
+	var changes []Change
+	for _, m := range s.mappings {
+		localVal, hasLocal := local[m.Category]
+		remoteVal, hasRemote := labels[m.RemoteTag]
+		if hasLocal == hasRemote && localVal == remoteVal {
+			continue
+		}
+
+		importedVal, imported := previous[m.RemoteTag]
+		manual := hasLocal && (!imported || importedVal != localVal)
+		if manual && !hasRemote {
+			continue
+		}
+
+		change := Change{
+			AssetID:   a.ID,
+			MRN:       obj.MRN,
+			Category:  m.Category,
+			RemoteTag: m.RemoteTag,
+			Value:     remoteVal,
+			Previous:  localVal,
+		}
+		change.Action, change.Resolution = resolveImport(m.OnConflict, manual, hasRemote, locked)
+
+		switch change.Action {
+		case ActionPull:
+			tags = replaceCategory(tags, m.Category, remoteVal)
+			tagsChanged = true
+		case ActionRemove:
+			tags = replaceCategory(tags, m.Category, "")
+			tagsChanged = true
+		}
+		changes = append(changes, change)
+	}
+
+	labelChanges := diffLabels(a, obj, key, previous, labels)
+	changes = append(changes, labelChanges...)
+
+	if opts.DryRun || (!tagsChanged && len(labelChanges) == 0) {
+		return changes, nil
+	}
+
+	var input asset.UpdateInput
+	if tagsChanged {
+		if tags == nil {
+			tags = []string{}
+		}
+		input.Tags = tags
+	}
+	if len(labelChanges) > 0 {
+		input.Metadata = make(map[string]interface{}, len(a.Metadata)+1)
+		for k, v := range a.Metadata {
+			input.Metadata[k] = v
+		}
+		if len(labels) == 0 {
+			delete(input.Metadata, key)
+		} else {
+			imported := make(map[string]interface{}, len(labels))
+			for k, v := range labels {
+				imported[k] = v
+			}
+			input.Metadata[key] = imported
+		}
+	}
+
+	if _, err := s.assetSvc.Update(ctx, a.ID, input); err != nil {
+		return changes, fmt.Errorf("updating asset: %w", err)
+	}
+	return changes, nil
+}
+
+// resolveImport decides what to do about a mapped label whose value differs
+// from the catalog's. manual is true when the catalog value was set by hand
+// rather than imported.
+func resolveImport(onConflict string, manual, hasRemote, locked bool) (action, resolution string) {
+	action = ActionRemove
+	if hasRemote {
+		action = ActionPull
+	}
+
+	if manual {
+		switch onConflict {
+		case ConflictRemoteWins:
+			resolution = ConflictRemoteWins
+		case ConflictSkip:
+			return ActionSkip, ConflictSkip
+		default:
+			return ActionSkip, ConflictMarmotWins
+		}
+	}
+
+	if locked {
+		return ActionSkip, ResolutionLocked
+	}
+	return action, resolution
+}
+
+// diffLabels reports each label added, changed or removed since the previous
+// run, sorted by label.
+func diffLabels(a *asset.Asset, obj ObjectRef, key string, previous, labels map[string]string) []Change {
+	names := make([]string, 0, len(previous)+len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	for name := range previous {
+		if _, ok := labels[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		value, ok := labels[name]
+		prev, hadPrev := previous[name]
+		if ok && hadPrev && value == prev {
+			continue
+		}
+
+		change := Change{
+			AssetID:     a.ID,
+			MRN:         obj.MRN,
+			MetadataKey: key,
+			RemoteTag:   name,
+			Action:      ActionPull,
+			Value:       value,
+			Previous:    prev,
+		}
+		if !ok {
+			change.Action = ActionRemove
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// importedLabels reads the labels a previous run stored in metadata.
+func importedLabels(v interface{}) map[string]string {
+	labels := make(map[string]string)
+	switch m := v.(type) {
+	case map[string]interface{}:
+		for k, val := range m {
+			if s, ok := val.(string); ok {
+				labels[k] = s
+			}
+		}
+	case map[string]string:
+		for k, val := range m {
+			labels[k] = val
+		}
+	}
+	return labels
+}
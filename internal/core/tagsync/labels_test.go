@@ -0,0 +1,171 @@
+package tagsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLabelProvider struct {
+	labels map[string]string
+}
+
+func (p *fakeLabelProvider) Name() string        { return "Kubernetes" }
+func (p *fakeLabelProvider) MetadataKey() string { return "kubernetes_labels" }
+
+func (p *fakeLabelProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: "default/orders"}, true
+}
+
+func (p *fakeLabelProvider) ReadLabels(context.Context, ObjectRef) (map[string]string, error) {
+	return p.labels, nil
+}
+
+func (p *fakeLabelProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	return readMappedLabels(ctx, p, obj, tags)
+}
+
+func (p *fakeLabelProvider) SetTag(context.Context, ObjectRef, string, string) error {
+	return ErrReadOnly
+}
+
+func (p *fakeLabelProvider) UnsetTag(context.Context, ObjectRef, string) error {
+	return ErrReadOnly
+}
+
+// fakeAssetService holds a single asset. Unused methods panic through the
+// embedded nil interface.
+type fakeAssetService struct {
+	asset.Service
+	asset *asset.Asset
+}
+
+func (f *fakeAssetService) Search(context.Context, asset.SearchFilter, bool) ([]*asset.Asset, int, asset.AvailableFilters, error) {
+	return []*asset.Asset{f.asset}, 1, asset.AvailableFilters{}, nil
+}
+
+func (f *fakeAssetService) Get(_ context.Context, id string) (*asset.Asset, error) {
+	if f.asset == nil || f.asset.ID != id {
+		return nil, asset.ErrNotFound
+	}
+	return f.asset, nil
+}
+
+func (f *fakeAssetService) Update(_ context.Context, _ string, input asset.UpdateInput) (*asset.Asset, error) {
+	if input.Tags != nil {
+		f.asset.Tags = input.Tags
+	}
+	if input.Metadata != nil {
+		f.asset.Metadata = input.Metadata
+	}
+	return f.asset, nil
+}
+
+func newLabelTestService(t *testing.T, a *asset.Asset, labels map[string]string, onConflict string) (*Service, *fakeLabelProvider) {
+	t.Helper()
+	mrn := "mrn://deployment/kubernetes/default/orders"
+	a.ID, a.MRN, a.Type = "orders", &mrn, "Deployment"
+	provider := &fakeLabelProvider{labels: labels}
+	svc, err := NewService(&fakeAssetService{asset: a}, provider, []Mapping{
+		{Category: "team", RemoteTag: "team", OnConflict: onConflict},
+	})
+	require.NoError(t, err)
+	return svc, provider
+}
+
+func TestLabelMappingsMustPull(t *testing.T) {
+	_, err := NewService(nil, &fakeLabelProvider{}, []Mapping{{Category: "team", RemoteTag: "team", Direction: DirectionPush}})
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+
+	svc, err := NewService(nil, &fakeLabelProvider{}, []Mapping{{Category: "team", RemoteTag: "team"}})
+	require.NoError(t, err)
+	assert.Equal(t, DirectionPull, svc.Mappings()[0].Direction)
+}
+
+func TestSyncLabelsImportsMetadataAndPullsTags(t *testing.T) {
+	a := &asset.Asset{Tags: []string{"pii"}, Metadata: map[string]interface{}{"namespace": "default"}}
+	svc, provider := newLabelTestService(t, a, map[string]string{"team": "orders", "app": "api"}, "")
+	ctx := context.Background()
+
+	report, err := svc.Sync(ctx, SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.ElementsMatch(t, []string{"pii", "team:orders"}, a.Tags)
+	assert.Equal(t, map[string]interface{}{"team": "orders", "app": "api"}, a.Metadata["kubernetes_labels"])
+	assert.Equal(t, "default", a.Metadata["namespace"])
+
+	// A second run with nothing changed is a no-op.
+	report, err = svc.Sync(ctx, SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+
+	// Removing a label removes the tag it was pulled into.
+	provider.labels = map[string]string{"app": "api"}
+	_, err = svc.Sync(ctx, SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pii"}, a.Tags)
+	assert.Equal(t, map[string]interface{}{"app": "api"}, a.Metadata["kubernetes_labels"])
+}
+
+func TestSyncLabelsManualTagConflicts(t *testing.T) {
+	cases := []struct {
+		onConflict string
+		wantTags   []string
+		resolution string
+	}{
+		{"", []string{"team:payments"}, ConflictMarmotWins},
+		{ConflictSkip, []string{"team:payments"}, ConflictSkip},
+		{ConflictRemoteWins, []string{"team:orders"}, ConflictRemoteWins},
+	}
+	for _, tc := range cases {
+		a := &asset.Asset{
+			Tags: []string{"team:payments"},
+			// The previous run imported team=orders, so payments was set by hand.
+			Metadata: map[string]interface{}{"kubernetes_labels": map[string]interface{}{"team": "orders"}},
+		}
+		svc, _ := newLabelTestService(t, a, map[string]string{"team": "orders"}, tc.onConflict)
+
+		report, err := svc.Sync(context.Background(), SyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, tc.wantTags, a.Tags, "on_conflict %q", tc.onConflict)
+		require.Len(t, report.Changes, 1)
+		assert.Equal(t, tc.resolution, report.Changes[0].Resolution)
+		assert.Equal(t, 1, report.Conflicts)
+	}
+}
+
+func TestSyncLabelsKeepsManualTagWhenLabelMissing(t *testing.T) {
+	a := &asset.Asset{Tags: []string{"team:payments"}}
+	svc, _ := newLabelTestService(t, a, map[string]string{}, ConflictRemoteWins)
+
+	report, err := svc.Sync(context.Background(), SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, []string{"team:payments"}, a.Tags)
+}
+
+func TestSyncLabelsRespectsLockedTags(t *testing.T) {
+	a := &asset.Asset{LockedFields: []string{asset.FieldTags}}
+	svc, _ := newLabelTestService(t, a, map[string]string{"team": "orders"}, "")
+
+	report, err := svc.Sync(context.Background(), SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, a.Tags)
+	assert.Equal(t, map[string]interface{}{"team": "orders"}, a.Metadata["kubernetes_labels"])
+	require.Len(t, report.Changes, 2)
+	assert.Equal(t, ResolutionLocked, report.Changes[0].Resolution)
+}
+
+func TestSyncLabelsDryRun(t *testing.T) {
+	a := &asset.Asset{}
+	svc, _ := newLabelTestService(t, a, map[string]string{"team": "orders"}, "")
+
+	report, err := svc.Sync(context.Background(), SyncOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, report.Changes, 2)
+	assert.Empty(t, a.Tags)
+	assert.Nil(t, a.Metadata)
+}
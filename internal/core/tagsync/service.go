@@ -0,0 +1,502 @@
+package tagsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DirectionPush makes Marmot authoritative: remote tags are set or unset to
+	// match the catalog.
+	DirectionPush = "push"
+	// DirectionPull makes the warehouse authoritative: catalog tags are
+	// rewritten to match the remote tag.
+	DirectionPull = "pull"
+	// DirectionBoth pushes catalog values and pulls remote values for assets
+	// that have no catalog value for the category yet. When both sides hold
+	// different values the mapping's conflict setting decides.
+	DirectionBoth = "both"
+
+	// ConflictMarmotWins resolves a DirectionBoth conflict, where both sides
+	// have different values, by pushing the catalog value. This is the default.
+	ConflictMarmotWins = "marmot_wins"
+	// ConflictRemoteWins resolves a conflict by pulling the remote value.
+	ConflictRemoteWins = "remote_wins"
+	// ConflictSkip leaves both sides untouched and reports the conflict.
+	ConflictSkip = "skip"
+
+	// ActionSet and friends describe a single change made during a sync.
+	ActionSet    = "set"
+	ActionUnset  = "unset"
+	ActionPull   = "pull"
+	ActionRemove = "remove"
+	ActionSkip   = "skip"
+
+	// categorySeparator separates a category from its value in Marmot tags,
+	// e.g. "pii:email".
+	categorySeparator = ":"
+
+	// valueSeparator joins multiple catalog values into one remote tag value.
+	valueSeparator = ","
+
+	// flagValue is the remote value used for bare category tags like "pii".
+	flagValue = "true"
+
+	searchPageSize = 100
+)
+
+var ErrInvalidMapping = errors.New("invalid tag sync mapping")
+
+// Mapping links a Marmot tag category to a tag in the remote system.
+type Mapping struct {
+	Category   string `json:"category"`
+	RemoteTag  string `json:"remote_tag"`
+	Direction  string `json:"direction"`
+	OnConflict string `json:"on_conflict"`
+}
+
+// SyncOptions controls a single sync run.
+type SyncOptions struct {
+	// DryRun computes the changes a sync would make without applying them.
+	DryRun bool
+}
+
+// ObjectRef identifies a remote object that backs a Marmot asset.
+type ObjectRef struct {
+	AssetID string `json:"asset_id"`
+	MRN     string `json:"mrn"`
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+}
+
+// Provider reads and writes native tags in a remote system.
+type Provider interface {
+	// Name is the Marmot provider name of the assets this provider manages.
+	Name() string
+	// Resolve maps an asset to its remote object. ok is false for assets the
+	// provider cannot address.
+	Resolve(a *asset.Asset) (ObjectRef, bool)
+	// ReadTags returns the current value of each requested tag that is set
+	// on the object, keyed by the tag name as requested.
+	ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error)
+	SetTag(ctx context.Context, obj ObjectRef, tag, value string) error
+	UnsetTag(ctx context.Context, obj ObjectRef, tag string) error
+}
+
+// ValueNormalizer is implemented by providers that restrict the characters a
+// tag value may contain. Catalog values are normalized before they are
+// compared or pushed so restricted values do not show up as drift every run.
+type ValueNormalizer interface {
+	NormalizeValue(value string) string
+}
+
+// Change records one tag change applied during a sync.
+type Change struct {
+	AssetID     string `json:"asset_id"`
+	MRN         string `json:"mrn"`
+	Category    string `json:"category,omitempty"`
+	PolicyTag   string `json:"policy_tag,omitempty"`
+	Column      string `json:"column,omitempty"`
+	MetadataKey string `json:"metadata_key,omitempty"`
+	RemoteTag   string `json:"remote_tag"`
+	Action      string `json:"action"`
+	Value       string `json:"value,omitempty"`
+	Previous    string `json:"previous,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+} // @name TagSyncChange
+
+// Report summarises a sync run. In a dry run Changes lists what would have
+// been applied.
+type Report struct {
+	Provider      string    `json:"provider"`
+	DryRun        bool      `json:"dry_run"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	AssetsScanned int       `json:"assets_scanned"`
+	Conflicts     int       `json:"conflicts"`
+	Changes       []Change  `json:"changes"`
+	Errors        []string  `json:"errors,omitempty"`
+} // @name TagSyncReport
+
+// Service keeps Marmot tag categories and remote object tags in step.
+type Service struct {
+	assetSvc       asset.Service
+	provider       Provider
+	mappings       []Mapping
+	policyTags     PolicyTagStore
+	columnMappings []ColumnMapping
+}
+
+// NewService creates a tag sync service for a single provider.
+func NewService(assetSvc asset.Service, provider Provider, mappings []Mapping) (*Service, error) {
+	_, readOnly := provider.(LabelReader)
+
+	normalized := make([]Mapping, 0, len(mappings))
+	seen := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		m.Category = strings.ToLower(strings.TrimSpace(m.Category))
+		m.RemoteTag = strings.TrimSpace(m.RemoteTag)
+		if readOnly && strings.TrimSpace(m.Direction) == "" {
+			m.Direction = DirectionPull
+		}
+		if err := normalizeDirection(&m.Direction, &m.OnConflict); err != nil {
+			return nil, err
+		}
+		if readOnly && m.Direction != DirectionPull {
+			return nil, fmt.Errorf("%w: %s labels are read-only, so %q can only be pulled", ErrInvalidMapping, provider.Name(), m.Category)
+		}
+
+		if m.Category == "" || m.RemoteTag == "" {
+			return nil, fmt.Errorf("%w: category and remote tag are required", ErrInvalidMapping)
+		}
+		if strings.Contains(m.Category, categorySeparator) {
+			return nil, fmt.Errorf("%w: category %q must not contain %q", ErrInvalidMapping, m.Category, categorySeparator)
+		}
+		if seen[m.Category] {
+			return nil, fmt.Errorf("%w: category %q is mapped more than once", ErrInvalidMapping, m.Category)
+		}
+		seen[m.Category] = true
+
+		normalized = append(normalized, m)
+	}
+
+	return &Service{
+		assetSvc: assetSvc,
+		provider: provider,
+		mappings: normalized,
+	}, nil
+}
+
+func normalizeDirection(direction, onConflict *string) error {
+	*direction = strings.ToLower(strings.TrimSpace(*direction))
+	if *direction == "" {
+		*direction = DirectionPush
+	}
+	switch *direction {
+	case DirectionPush, DirectionPull, DirectionBoth:
+	default:
+		return fmt.Errorf("%w: unknown direction %q", ErrInvalidMapping, *direction)
+	}
+
+	*onConflict = strings.ToLower(strings.TrimSpace(*onConflict))
+	if *onConflict == "" {
+		*onConflict = ConflictMarmotWins
+	}
+	switch *onConflict {
+	case ConflictMarmotWins, ConflictRemoteWins, ConflictSkip:
+	default:
+		return fmt.Errorf("%w: unknown conflict resolution %q", ErrInvalidMapping, *onConflict)
+	}
+	return nil
+}
+
+// Provider returns the name of the provider this service syncs with.
+func (s *Service) Provider() string {
+	return s.provider.Name()
+}
+
+// Mappings returns the normalized tag category mappings.
+func (s *Service) Mappings() []Mapping {
+	return s.mappings
+}
+
+// Sync walks every asset owned by the provider and reconciles the mapped tag
+// categories and column policy tags. Failures on individual assets are
+// collected in the report so one bad object does not stop the run.
+func (s *Service) Sync(ctx context.Context, opts SyncOptions) (*Report, error) {
+	report := &Report{
+		Provider:  s.provider.Name(),
+		DryRun:    opts.DryRun,
+		StartedAt: time.Now(),
+		Changes:   []Change{},
+	}
+
+	policyTagIDs, err := s.policyTagIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteTags := make([]string, len(s.mappings))
+	for i, m := range s.mappings {
+		remoteTags[i] = m.RemoteTag
+	}
+
+	providers := []string{s.provider.Name()}
+	if set, ok := s.provider.(ProviderSet); ok {
+		providers = set.Providers()
+	}
+	labelReader, importLabels := s.provider.(LabelReader)
+
+	assetIDs, err := s.assetIDs(ctx, providers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range assetIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		a, err := s.assetSvc.Get(ctx, id)
+		if errors.Is(err, asset.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: loading asset: %v", id, err))
+			continue
+		}
+
+		obj, ok := s.provider.Resolve(a)
+		if !ok {
+			continue
+		}
+		report.AssetsScanned++
+
+		if importLabels {
+			changes, err := s.syncLabels(ctx, a, obj, labelReader, opts)
+			report.Changes = append(report.Changes, changes...)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", obj.MRN, err))
+			}
+		} else if len(s.mappings) > 0 {
+			changes, err := s.syncAsset(ctx, a, obj, remoteTags, opts)
+			report.Changes = append(report.Changes, changes...)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", obj.MRN, err))
+			}
+		}
+
+		if len(policyTagIDs) > 0 {
+			changes, err := s.syncColumns(ctx, a, obj, policyTagIDs, opts)
+			report.Changes = append(report.Changes, changes...)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", obj.MRN, err))
+			}
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	for _, c := range report.Changes {
+		if c.Resolution != "" {
+			report.Conflicts++
+		}
+	}
+
+	log.Info().
+		Str("provider", report.Provider).
+		Bool("dry_run", report.DryRun).
+		Int("assets", report.AssetsScanned).
+		Int("changes", len(report.Changes)).
+		Int("errors", len(report.Errors)).
+		Msg("Tag sync completed")
+
+	return report, nil
+}
+
+// assetIDs lists the IDs of every asset of providers before any is synced.
+// Syncing updates assets, which reorders search results, so paging through
+// them while syncing could skip some assets and visit others twice.
+func (s *Service) assetIDs(ctx context.Context, providers []string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += searchPageSize {
+		assets, total, _, err := s.assetSvc.Search(ctx, asset.SearchFilter{
+			Providers: providers,
+			Limit:     searchPageSize,
+			Offset:    offset,
+		}, false)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s assets: %w", s.provider.Name(), err)
+		}
+
+		for _, a := range assets {
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				ids = append(ids, a.ID)
+			}
+		}
+
+		if len(assets) < searchPageSize || offset+len(assets) >= total {
+			return ids, nil
+		}
+	}
+}
+
+func (s *Service) syncAsset(ctx context.Context, a *asset.Asset, obj ObjectRef, remoteTags []string, opts SyncOptions) ([]Change, error) {
+	remote, err := s.provider.ReadTags(ctx, obj, remoteTags)
+	if err != nil {
+		return nil, fmt.Errorf("reading tags: %w", err)
+	}
+
+	local := categoryValues(a.Tags)
+	normalizer, _ := s.provider.(ValueNormalizer)
+
+	tags := a.Tags
+	tagsChanged := false
+
+	var changes []Change
+	var errs []error
+
+	for _, m := range s.mappings {
+		localVal, hasLocal := local[m.Category]
+		remoteVal, hasRemote := remote[m.RemoteTag]
+		if hasLocal && normalizer != nil {
+			localVal = normalizer.NormalizeValue(localVal)
+		}
+		if hasLocal == hasRemote && localVal == remoteVal {
+			continue
+		}
+
+		change := Change{
+			AssetID:   a.ID,
+			MRN:       obj.MRN,
+			Category:  m.Category,
+			RemoteTag: m.RemoteTag,
+		}
+		change.Action, change.Resolution = resolve(m.Direction, m.OnConflict, hasLocal, hasRemote)
+
+		switch change.Action {
+		case ActionSet:
+			change.Value, change.Previous = localVal, remoteVal
+			if !opts.DryRun {
+				if err := s.provider.SetTag(ctx, obj, m.RemoteTag, localVal); err != nil {
+					errs = append(errs, fmt.Errorf("setting %s: %w", m.RemoteTag, err))
+					continue
+				}
+			}
+		case ActionUnset:
+			change.Previous = remoteVal
+			if !opts.DryRun {
+				if err := s.provider.UnsetTag(ctx, obj, m.RemoteTag); err != nil {
+					errs = append(errs, fmt.Errorf("unsetting %s: %w", m.RemoteTag, err))
+					continue
+				}
+			}
+		case ActionPull:
+			change.Value, change.Previous = remoteVal, localVal
+			tags = replaceCategory(tags, m.Category, remoteVal)
+			tagsChanged = true
+		case ActionRemove:
+			change.Previous = localVal
+			tags = replaceCategory(tags, m.Category, "")
+			tagsChanged = true
+		case ActionSkip:
+			change.Value, change.Previous = localVal, remoteVal
+		}
+		changes = append(changes, change)
+	}
+
+	if tagsChanged && !opts.DryRun {
+		if tags == nil {
+			tags = []string{}
+		}
+		if _, err := s.assetSvc.Update(ctx, a.ID, asset.UpdateInput{Tags: tags}); err != nil {
+			errs = append(errs, fmt.Errorf("updating catalog tags: %w", err))
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// resolve decides what to do about a tag whose catalog and remote state
+// differ. resolution is only set when a DirectionBoth conflict was decided by
+// the mapping's conflict setting.
+func resolve(direction, onConflict string, hasLocal, hasRemote bool) (action, resolution string) {
+	switch direction {
+	case DirectionPush:
+		if hasLocal {
+			return ActionSet, ""
+		}
+		return ActionUnset, ""
+	case DirectionPull:
+		if hasRemote {
+			return ActionPull, ""
+		}
+		return ActionRemove, ""
+	}
+
+	switch {
+	case hasLocal && !hasRemote:
+		return ActionSet, ""
+	case !hasLocal && hasRemote:
+		return ActionPull, ""
+	}
+
+	switch onConflict {
+	case ConflictRemoteWins:
+		return ActionPull, onConflict
+	case ConflictSkip:
+		return ActionSkip, onConflict
+	default:
+		return ActionSet, ConflictMarmotWins
+	}
+}
+
+// categoryValues groups "category:value" tags by category. Multiple values are
+// sorted and joined so the result is stable across runs; a bare "category" tag
+// is treated as a flag.
+func categoryValues(tags []string) map[string]string {
+	grouped := make(map[string][]string)
+	for _, tag := range tags {
+		category, value, found := strings.Cut(tag, categorySeparator)
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if !found || value == "" {
+			value = flagValue
+		}
+		grouped[category] = append(grouped[category], value)
+	}
+
+	values := make(map[string]string, len(grouped))
+	for category, vals := range grouped {
+		sort.Strings(vals)
+		values[category] = strings.Join(dedupe(vals), valueSeparator)
+	}
+	return values
+}
+
+// replaceCategory drops every tag in the category and, if value is non-empty,
+// adds tags for it. The flag value collapses back to a bare category tag.
+func replaceCategory(tags []string, category, value string) []string {
+	result := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		c, _, _ := strings.Cut(tag, categorySeparator)
+		if strings.EqualFold(strings.TrimSpace(c), category) {
+			continue
+		}
+		result = append(result, tag)
+	}
+
+	if value == "" {
+		return result
+	}
+	if value == flagValue {
+		return append(result, category)
+	}
+	for _, v := range strings.Split(value, valueSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, category+categorySeparator+v)
+		}
+	}
+	return result
+}
+
+func dedupe(sorted []string) []string {
+	result := sorted[:0]
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
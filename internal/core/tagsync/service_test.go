@@ -0,0 +1,376 @@
+package tagsync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTagProvider keeps remote tags per asset in memory.
+type fakeTagProvider struct {
+	remote map[string]map[string]string
+	reads  map[string]int
+	writes []string
+}
+
+func newFakeTagProvider() *fakeTagProvider {
+	return &fakeTagProvider{remote: map[string]map[string]string{}, reads: map[string]int{}}
+}
+
+func (p *fakeTagProvider) Name() string { return "Snowflake" }
+
+func (p *fakeTagProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil {
+		return ObjectRef{}, false
+	}
+	return ObjectRef{AssetID: a.ID, MRN: *a.MRN, Kind: a.Type, Path: a.ID}, true
+}
+
+func (p *fakeTagProvider) ReadTags(_ context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	p.reads[obj.AssetID]++
+	result := make(map[string]string)
+	for _, tag := range tags {
+		if v, ok := p.remote[obj.AssetID][tag]; ok {
+			result[tag] = v
+		}
+	}
+	return result, nil
+}
+
+func (p *fakeTagProvider) SetTag(_ context.Context, obj ObjectRef, tag, value string) error {
+	if p.remote[obj.AssetID] == nil {
+		p.remote[obj.AssetID] = map[string]string{}
+	}
+	p.remote[obj.AssetID][tag] = value
+	p.writes = append(p.writes, fmt.Sprintf("set %s %s=%s", obj.AssetID, tag, value))
+	return nil
+}
+
+func (p *fakeTagProvider) UnsetTag(_ context.Context, obj ObjectRef, tag string) error {
+	delete(p.remote[obj.AssetID], tag)
+	p.writes = append(p.writes, fmt.Sprintf("unset %s %s", obj.AssetID, tag))
+	return nil
+}
+
+// fakeCatalog models a search without a query: every asset ranks the same,
+// so results come back in table order, and an update rewrites the row at
+// the end of the table.
+type fakeCatalog struct {
+	asset.Service
+	assets []*asset.Asset
+}
+
+func newFakeCatalog(n int) *fakeCatalog {
+	c := &fakeCatalog{}
+	for i := range n {
+		id := fmt.Sprintf("t%03d", i)
+		mrn := "mrn://table/snowflake/db.public." + id
+		c.assets = append(c.assets, &asset.Asset{ID: id, MRN: &mrn, Type: "Table"})
+	}
+	return c
+}
+
+func (c *fakeCatalog) Search(_ context.Context, filter asset.SearchFilter, _ bool) ([]*asset.Asset, int, asset.AvailableFilters, error) {
+	start := min(filter.Offset, len(c.assets))
+	end := min(start+filter.Limit, len(c.assets))
+	return slices.Clone(c.assets[start:end]), len(c.assets), asset.AvailableFilters{}, nil
+}
+
+func (c *fakeCatalog) Get(_ context.Context, id string) (*asset.Asset, error) {
+	for _, a := range c.assets {
+		if a.ID == id {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, asset.ErrNotFound
+}
+
+func (c *fakeCatalog) Update(_ context.Context, id string, input asset.UpdateInput) (*asset.Asset, error) {
+	for i, a := range c.assets {
+		if a.ID != id {
+			continue
+		}
+		if input.Tags != nil {
+			a.Tags = input.Tags
+		}
+		c.assets = append(append(c.assets[:i:i], c.assets[i+1:]...), a)
+		return a, nil
+	}
+	return nil, asset.ErrNotFound
+}
+
+func TestSyncVisitsEveryAssetOnce(t *testing.T) {
+	catalog := newFakeCatalog(2*searchPageSize + 50)
+	provider := newFakeTagProvider()
+	for _, a := range catalog.assets {
+		provider.remote[a.ID] = map[string]string{"DATA_DOMAIN": "sales"}
+	}
+
+	svc, err := NewService(catalog, provider, []Mapping{{Category: "domain", RemoteTag: "DATA_DOMAIN", Direction: DirectionPull}})
+	require.NoError(t, err)
+
+	report, err := svc.Sync(context.Background(), SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, len(catalog.assets), report.AssetsScanned)
+	assert.Len(t, report.Changes, len(catalog.assets))
+
+	require.Len(t, provider.reads, len(catalog.assets))
+	for id, reads := range provider.reads {
+		assert.Equal(t, 1, reads, "asset %s", id)
+	}
+	for _, a := range catalog.assets {
+		assert.Equal(t, []string{"domain:sales"}, a.Tags)
+	}
+}
+
+func TestSyncSkipsAssetsDeletedMidRun(t *testing.T) {
+	catalog := newFakeCatalog(3)
+	deleted := catalog.assets[1].ID
+	provider := newFakeTagProvider()
+
+	svc, err := NewService(&deletingCatalog{fakeCatalog: catalog, deleteID: deleted}, provider, []Mapping{{Category: "domain", RemoteTag: "DATA_DOMAIN"}})
+	require.NoError(t, err)
+
+	report, err := svc.Sync(context.Background(), SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 2, report.AssetsScanned)
+	assert.NotContains(t, provider.reads, deleted)
+}
+
+// deletingCatalog loses an asset between listing and syncing it.
+type deletingCatalog struct {
+	*fakeCatalog
+	deleteID string
+}
+
+func (c *deletingCatalog) Get(ctx context.Context, id string) (*asset.Asset, error) {
+	if id == c.deleteID {
+		return nil, asset.ErrNotFound
+	}
+	return c.fakeCatalog.Get(ctx, id)
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		direction      string
+		onConflict     string
+		hasLocal       bool
+		hasRemote      bool
+		wantAction     string
+		wantResolution string
+	}{
+		{DirectionPush, ConflictMarmotWins, true, false, ActionSet, ""},
+		{DirectionPush, ConflictMarmotWins, true, true, ActionSet, ""},
+		{DirectionPush, ConflictMarmotWins, false, true, ActionUnset, ""},
+		{DirectionPush, ConflictSkip, true, true, ActionSet, ""},
+		{DirectionPull, ConflictMarmotWins, false, true, ActionPull, ""},
+		{DirectionPull, ConflictMarmotWins, true, true, ActionPull, ""},
+		{DirectionPull, ConflictMarmotWins, true, false, ActionRemove, ""},
+		{DirectionBoth, ConflictMarmotWins, true, false, ActionSet, ""},
+		{DirectionBoth, ConflictSkip, false, true, ActionPull, ""},
+		{DirectionBoth, ConflictMarmotWins, true, true, ActionSet, ConflictMarmotWins},
+		{DirectionBoth, "", true, true, ActionSet, ConflictMarmotWins},
+		{DirectionBoth, ConflictRemoteWins, true, true, ActionPull, ConflictRemoteWins},
+		{DirectionBoth, ConflictSkip, true, true, ActionSkip, ConflictSkip},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s/%s/local=%v/remote=%v", tt.direction, tt.onConflict, tt.hasLocal, tt.hasRemote)
+		t.Run(name, func(t *testing.T) {
+			action, resolution := resolve(tt.direction, tt.onConflict, tt.hasLocal, tt.hasRemote)
+			assert.Equal(t, tt.wantAction, action)
+			assert.Equal(t, tt.wantResolution, resolution)
+		})
+	}
+}
+
+func TestCategoryValues(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want map[string]string
+	}{
+		{name: "no tags", tags: nil, want: map[string]string{}},
+		{name: "single value", tags: []string{"domain:sales"}, want: map[string]string{"domain": "sales"}},
+		{name: "bare tag is a flag", tags: []string{"pii"}, want: map[string]string{"pii": flagValue}},
+		{name: "empty value is a flag", tags: []string{"pii:"}, want: map[string]string{"pii": flagValue}},
+		{name: "category is case and space insensitive", tags: []string{" Domain : sales "}, want: map[string]string{"domain": "sales"}},
+		{name: "values sorted and joined", tags: []string{"region:us", "region:eu", "region:us"}, want: map[string]string{"region": "eu,us"}},
+		{name: "only the first separator splits", tags: []string{"owner:team:data"}, want: map[string]string{"owner": "team:data"}},
+		{name: "empty category ignored", tags: []string{":orphan", ""}, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, categoryValues(tt.tags))
+		})
+	}
+}
+
+func TestReplaceCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		category string
+		value    string
+		want     []string
+	}{
+		{name: "replaces every value", tags: []string{"region:us", "keep", "region:eu"}, category: "region", value: "apac", want: []string{"keep", "region:apac"}},
+		{name: "empty value removes", tags: []string{"region:us", "keep"}, category: "region", want: []string{"keep"}},
+		{name: "flag collapses to bare tag", tags: []string{"keep"}, category: "pii", value: flagValue, want: []string{"keep", "pii"}},
+		{name: "joined values split", tags: nil, category: "region", value: "eu, us", want: []string{"region:eu", "region:us"}},
+		{name: "category matched case-insensitively", tags: []string{"Region:us", "PII"}, category: "region", value: "eu", want: []string{"PII", "region:eu"}},
+		{name: "bare tag replaced", tags: []string{"pii"}, category: "pii", value: "email", want: []string{"pii:email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replaceCategory(tt.tags, tt.category, tt.value))
+		})
+	}
+}
+
+func TestReplaceCategoryRoundTrips(t *testing.T) {
+	tags := []string{"region:us", "region:eu", "pii", "keep"}
+	values := categoryValues(tags)
+
+	rebuilt := replaceCategory(replaceCategory(tags, "region", values["region"]), "pii", values["pii"])
+	assert.Equal(t, values, categoryValues(rebuilt))
+}
+
+func TestSyncAssetConflictsAndDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		direction  string
+		onConflict string
+		local      []string
+		remote     map[string]string
+		dryRun     bool
+		wantAction string
+		wantRes    string
+		wantLocal  []string
+		wantRemote map[string]string
+	}{
+		{
+			name:       "push sets missing remote tag",
+			direction:  DirectionPush,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{},
+			wantAction: ActionSet,
+			wantLocal:  []string{"domain:sales"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "sales"},
+		},
+		{
+			name:       "push unsets remote tag without catalog value",
+			direction:  DirectionPush,
+			remote:     map[string]string{"DATA_DOMAIN": "sales"},
+			wantAction: ActionUnset,
+			wantRemote: map[string]string{},
+		},
+		{
+			name:       "pull removes catalog tag missing remotely",
+			direction:  DirectionPull,
+			local:      []string{"domain:sales", "keep"},
+			remote:     map[string]string{},
+			wantAction: ActionRemove,
+			wantLocal:  []string{"keep"},
+			wantRemote: map[string]string{},
+		},
+		{
+			name:       "conflict marmot wins",
+			direction:  DirectionBoth,
+			onConflict: ConflictMarmotWins,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{"DATA_DOMAIN": "finance"},
+			wantAction: ActionSet,
+			wantRes:    ConflictMarmotWins,
+			wantLocal:  []string{"domain:sales"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "sales"},
+		},
+		{
+			name:       "conflict remote wins",
+			direction:  DirectionBoth,
+			onConflict: ConflictRemoteWins,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{"DATA_DOMAIN": "finance"},
+			wantAction: ActionPull,
+			wantRes:    ConflictRemoteWins,
+			wantLocal:  []string{"domain:finance"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "finance"},
+		},
+		{
+			name:       "conflict skipped",
+			direction:  DirectionBoth,
+			onConflict: ConflictSkip,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{"DATA_DOMAIN": "finance"},
+			wantAction: ActionSkip,
+			wantRes:    ConflictSkip,
+			wantLocal:  []string{"domain:sales"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "finance"},
+		},
+		{
+			name:       "dry run push leaves remote untouched",
+			direction:  DirectionBoth,
+			onConflict: ConflictMarmotWins,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{"DATA_DOMAIN": "finance"},
+			dryRun:     true,
+			wantAction: ActionSet,
+			wantRes:    ConflictMarmotWins,
+			wantLocal:  []string{"domain:sales"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "finance"},
+		},
+		{
+			name:       "dry run pull leaves catalog untouched",
+			direction:  DirectionBoth,
+			onConflict: ConflictRemoteWins,
+			local:      []string{"domain:sales"},
+			remote:     map[string]string{"DATA_DOMAIN": "finance"},
+			dryRun:     true,
+			wantAction: ActionPull,
+			wantRes:    ConflictRemoteWins,
+			wantLocal:  []string{"domain:sales"},
+			wantRemote: map[string]string{"DATA_DOMAIN": "finance"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := newFakeCatalog(1)
+			a := catalog.assets[0]
+			a.Tags = tt.local
+			provider := newFakeTagProvider()
+			provider.remote[a.ID] = tt.remote
+
+			svc, err := NewService(catalog, provider, []Mapping{
+				{Category: "domain", RemoteTag: "DATA_DOMAIN", Direction: tt.direction, OnConflict: tt.onConflict},
+			})
+			require.NoError(t, err)
+
+			report, err := svc.Sync(context.Background(), SyncOptions{DryRun: tt.dryRun})
+			require.NoError(t, err)
+			assert.Empty(t, report.Errors)
+			require.Len(t, report.Changes, 1)
+			assert.Equal(t, tt.wantAction, report.Changes[0].Action)
+			assert.Equal(t, tt.wantRes, report.Changes[0].Resolution)
+			if tt.wantRes != "" {
+				assert.Equal(t, 1, report.Conflicts)
+			}
+
+			assert.Equal(t, tt.wantLocal, a.Tags)
+			assert.Equal(t, tt.wantRemote, provider.remote[a.ID])
+			if tt.dryRun {
+				assert.Empty(t, provider.writes)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package tagsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+const (
+	snowflakeProviderName = "Snowflake"
+	snowflakeJWTLifetime  = 55 * time.Minute
+	snowflakePollInterval = 500 * time.Millisecond
+	snowflakeStmtTimeout  = 60
+)
+
+// SnowflakeConfig configures access to the Snowflake SQL API. Either a key
+// pair (PrivateKeyPath) or a pre-issued OAuthToken must be provided.
+type SnowflakeConfig struct {
+	Account        string
+	Host           string
+	User           string
+	Role           string
+	Warehouse      string
+	PrivateKeyPath string
+	OAuthToken     string
+	HTTPClient     *http.Client
+}
+
+// SnowflakeProvider syncs with Snowflake object tags through the SQL API.
+// Assets are addressed by their MRN name, which must be database.schema.object.
+type SnowflakeProvider struct {
+	config     SnowflakeConfig
+	baseURL    string
+	client     *http.Client
+	privateKey *rsa.PrivateKey
+	issuer     string
+	subject    string
+}
+
+// NewSnowflakeProvider validates config and loads the signing key, if any.
+func NewSnowflakeProvider(config SnowflakeConfig) (*SnowflakeProvider, error) {
+	if config.Account == "" {
+		return nil, errors.New("snowflake account is required")
+	}
+	if config.PrivateKeyPath == "" && config.OAuthToken == "" {
+		return nil, errors.New("snowflake private key path or OAuth token is required")
+	}
+
+	p := &SnowflakeProvider{
+		config: config,
+		client: config.HTTPClient,
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 2 * time.Minute}
+	}
+
+	host := config.Host
+	if host == "" {
+		host = strings.ToLower(config.Account) + ".snowflakecomputing.com"
+	}
+	p.baseURL = "https://" + strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/")
+
+	if config.PrivateKeyPath != "" {
+		if config.User == "" {
+			return nil, errors.New("snowflake user is required for key pair authentication")
+		}
+		key, err := loadRSAPrivateKey(config.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		fingerprint, err := publicKeyFingerprint(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		// Key pair JWTs use the account locator without region or cloud suffixes.
+		account, _, _ := strings.Cut(strings.ToUpper(config.Account), ".")
+		p.privateKey = key
+		p.subject = account + "." + strings.ToUpper(config.User)
+		p.issuer = p.subject + "." + fingerprint
+	}
+
+	return p, nil
+}
+
+func (p *SnowflakeProvider) Name() string {
+	return snowflakeProviderName
+}
+
+func (p *SnowflakeProvider) Resolve(a *asset.Asset) (ObjectRef, bool) {
+	if a.MRN == nil || a.IsStub {
+		return ObjectRef{}, false
+	}
+	parsed, err := mrn.Parse(*a.MRN)
+	if err != nil || parsed.Service != "snowflake" {
+		return ObjectRef{}, false
+	}
+	if len(strings.Split(parsed.Name, ".")) != 3 {
+		return ObjectRef{}, false
+	}
+
+	kind := "TABLE"
+	if strings.Contains(strings.ToUpper(a.Type), "VIEW") {
+		kind = "VIEW"
+	}
+
+	return ObjectRef{
+		AssetID: a.ID,
+		MRN:     *a.MRN,
+		Kind:    kind,
+		Path:    parsed.Name,
+	}, true
+}
+
+func (p *SnowflakeProvider) ReadTags(ctx context.Context, obj ObjectRef, tags []string) (map[string]string, error) {
+	parts := strings.Split(obj.Path, ".")
+	stmt := fmt.Sprintf(
+		"SELECT tag_database, tag_schema, tag_name, tag_value FROM TABLE(%s.INFORMATION_SCHEMA.TAG_REFERENCES(%s, 'table'))",
+		snowflakeIdentifier(parts[0]), snowflakeString(snowflakeObjectName(obj.Path)))
+
+	rows, err := p.execute(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, row := range rows {
+		if len(row) < 4 || row[2] == nil {
+			continue
+		}
+		qualified := strings.ToUpper(deref(row[0]) + "." + deref(row[1]) + "." + deref(row[2]))
+		name := strings.ToUpper(deref(row[2]))
+		for _, tag := range tags {
+			want := strings.ToUpper(tag)
+			if want == qualified || want == name {
+				result[tag] = deref(row[3])
+			}
+		}
+	}
+	return result, nil
+}
+
+func (p *SnowflakeProvider) SetTag(ctx context.Context, obj ObjectRef, tag, value string) error {
+	_, err := p.execute(ctx, fmt.Sprintf("ALTER %s %s SET TAG %s = %s",
+		obj.Kind, snowflakeObjectName(obj.Path), snowflakeObjectName(tag), snowflakeString(value)))
+	return err
+}
+
+func (p *SnowflakeProvider) UnsetTag(ctx context.Context, obj ObjectRef, tag string) error {
+	_, err := p.execute(ctx, fmt.Sprintf("ALTER %s %s UNSET TAG %s",
+		obj.Kind, snowflakeObjectName(obj.Path), snowflakeObjectName(tag)))
+	return err
+}
+
+type snowflakeRequest struct {
+	Statement string `json:"statement"`
+	Timeout   int    `json:"timeout"`
+	Role      string `json:"role,omitempty"`
+	Warehouse string `json:"warehouse,omitempty"`
+}
+
+type snowflakeResponse struct {
+	Code               string      `json:"code"`
+	Message            string      `json:"message"`
+	StatementHandle    string      `json:"statementHandle"`
+	StatementStatusURL string      `json:"statementStatusUrl"`
+	Data               [][]*string `json:"data"`
+}
+
+// execute runs a statement and waits for it to finish. Statements that take
+// longer than the synchronous window are polled until they complete.
+func (p *SnowflakeProvider) execute(ctx context.Context, statement string) ([][]*string, error) {
+	body, err := json.Marshal(snowflakeRequest{
+		Statement: statement,
+		Timeout:   snowflakeStmtTimeout,
+		Role:      p.config.Role,
+		Warehouse: p.config.Warehouse,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding statement: %w", err)
+	}
+
+	resp, status, err := p.do(ctx, http.MethodPost, p.baseURL+"/api/v2/statements", body)
+	if err != nil {
+		return nil, err
+	}
+
+	for status == http.StatusAccepted {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(snowflakePollInterval):
+		}
+		resp, status, err = p.do(ctx, http.MethodGet, p.baseURL+resp.StatementStatusURL, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("snowflake statement failed (%d, code %s): %s", status, resp.Code, resp.Message)
+	}
+	return resp.Data, nil
+}
+
+func (p *SnowflakeProvider) do(ctx context.Context, method, url string, body []byte) (*snowflakeResponse, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "marmot-tag-sync")
+
+	if p.privateKey != nil {
+		token, err := p.signJWT()
+		if err != nil {
+			return nil, 0, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Snowflake-Authorization-Token-Type", "KEYPAIR_JWT")
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.config.OAuthToken)
+		req.Header.Set("X-Snowflake-Authorization-Token-Type", "OAUTH")
+	}
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("calling snowflake: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading snowflake response: %w", err)
+	}
+
+	var resp snowflakeResponse
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, 0, fmt.Errorf("decoding snowflake response (%d): %w", httpResp.StatusCode, err)
+		}
+	}
+	return &resp, httpResp.StatusCode, nil
+}
+
+func (p *SnowflakeProvider) signJWT() (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    p.issuer,
+		Subject:   p.subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(snowflakeJWTLifetime)),
+	})
+	signed, err := token.SignedString(p.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("signing snowflake JWT: %w", err)
+	}
+	return signed, nil
+}
+
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snowflake private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("snowflake private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing snowflake private key (encrypted keys are not supported): %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("snowflake private key must be an RSA key")
+	}
+	return key, nil
+}
+
+func publicKeyFingerprint(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("encoding snowflake public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// snowflakeObjectName quotes each part of a dotted object name as needed.
+func snowflakeObjectName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = snowflakeIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// snowflakeIdentifier leaves simple identifiers unquoted so Snowflake resolves
+// them case-insensitively, which matches the lower-cased names in MRNs.
+func snowflakeIdentifier(ident string) string {
+	simple := ident != ""
+	for i, r := range ident {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		isDigit := (r >= '0' && r <= '9') || r == '$'
+		if !isLetter && (i == 0 || !isDigit) {
+			simple = false
+			break
+		}
+	}
+	if simple {
+		return ident
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func snowflakeString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,70 @@
+package tagsync
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const (
+	DefaultSyncInterval = 1 * time.Hour
+)
+
+// Syncer runs a tag sync service on a fixed interval.
+type Syncer struct {
+	svc  *Service
+	task *background.SingletonTask
+}
+
+// SyncerConfig configures the syncer.
+type SyncerConfig struct {
+	// Interval between sync runs. Default: 1 hour.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+	// DryRun only reports the changes each run would make.
+	DryRun bool
+}
+
+// NewSyncer creates a new periodic syncer for svc.
+func NewSyncer(svc *Service, config *SyncerConfig) *Syncer {
+	if config == nil {
+		config = &SyncerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultSyncInterval
+	}
+
+	s := &Syncer{svc: svc}
+
+	s.task = background.NewSingletonTask(background.SingletonConfig{
+		Name:         "tag-sync-" + strings.ToLower(svc.Provider()),
+		DB:           config.DB,
+		Interval:     config.Interval,
+		InitialDelay: time.Minute,
+		TaskFn: func(ctx context.Context) error {
+			_, err := svc.Sync(ctx, SyncOptions{DryRun: config.DryRun})
+			return err
+		},
+	})
+
+	return s
+}
+
+// Start begins the periodic sync loop.
+func (s *Syncer) Start(ctx context.Context) {
+	s.task.Start(ctx)
+}
+
+// Stop gracefully shuts down the syncer.
+func (s *Syncer) Stop() {
+	s.task.Stop()
+}
+
+// Service returns the tag sync service run by this syncer.
+func (s *Syncer) Service() *Service {
+	return s.svc
+}
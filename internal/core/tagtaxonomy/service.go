@@ -0,0 +1,474 @@
+// Package tagtaxonomy manages the controlled vocabulary of asset tags:
+// categories such as pii, the approved tags in them such as pii.email, and
+// whether assets may use tags outside the vocabulary.
+package tagtaxonomy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("tag category not found")
+	ErrTagNotFound      = errors.New("managed tag not found")
+	ErrCategoryInUse    = errors.New("tag category has tags")
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+const (
+	maxCategoryLength    = 100
+	maxTagLength         = 255
+	maxDescriptionLength = 2000
+	maxMappings          = 500
+
+	// cacheTTL bounds how long other instances can check tags against a
+	// stale taxonomy after it changes.
+	cacheTTL = 30 * time.Second
+)
+
+// Category is a namespace of managed tags, e.g. pii for pii.email.
+type Category struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Enforced restricts tags in the category to the managed ones, even
+	// when the taxonomy as a whole isn't enforced.
+	Enforced  bool      `json:"enforced"`
+	TagCount  int       `json:"tag_count"`
+	UpdatedBy *string   `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+} // @name TagCategory
+
+// Tag is an approved tag.
+type Tag struct {
+	Name string `json:"name"`
+	// Category is the part of the name before the first dot, or nil for
+	// tags without one.
+	Category    *string   `json:"category,omitempty"`
+	Description string    `json:"description,omitempty"`
+	AssetCount  int       `json:"asset_count"`
+	UpdatedBy   *string   `json:"updated_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+} // @name ManagedTag
+
+// Settings controls enforcement across the whole taxonomy.
+type Settings struct {
+	// Enforce restricts assets to managed tags.
+	Enforce   bool      `json:"enforce"`
+	UpdatedBy *string   `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+} // @name TagTaxonomySettings
+
+type CategoryInput struct {
+	Description string `json:"description,omitempty"`
+	Enforced    bool   `json:"enforced"`
+} // @name TagCategoryInput
+
+type TagInput struct {
+	Description string `json:"description,omitempty"`
+} // @name ManagedTagInput
+
+type SettingsInput struct {
+	Enforce bool `json:"enforce"`
+} // @name TagTaxonomySettingsInput
+
+// UnmanagedTag is a free-form tag in use on assets that isn't in the
+// taxonomy.
+type UnmanagedTag struct {
+	Tag        string `json:"tag"`
+	AssetCount int    `json:"asset_count"`
+	// Suggestion is a managed tag the tag probably means, if one stands out.
+	Suggestion string `json:"suggestion,omitempty"`
+} // @name UnmanagedTag
+
+// Mapping replaces a tag on every asset. An empty To removes it.
+type Mapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+} // @name TagMapping
+
+type MigrationInput struct {
+	Mappings []Mapping `json:"mappings"`
+	// DryRun reports how many assets each mapping would change without
+	// changing them.
+	DryRun bool `json:"dry_run"`
+} // @name TagMigrationInput
+
+type MappingResult struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	AssetCount int    `json:"asset_count"`
+} // @name TagMappingResult
+
+type MigrationResult struct {
+	DryRun   bool            `json:"dry_run"`
+	Mappings []MappingResult `json:"mappings"`
+} // @name TagMigrationResult
+
+// taxonomy is what checking an asset's tags needs, cached between writes.
+type taxonomy struct {
+	enforce   bool
+	tags      map[string]bool
+	enforced  map[string]bool // enforced category names
+	expiresAt time.Time
+}
+
+type Service struct {
+	repo Repository
+
+	mu    sync.RWMutex
+	cache taxonomy
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) ListCategories(ctx context.Context) ([]*Category, error) {
+	return s.repo.ListCategories(ctx)
+}
+
+func (s *Service) GetCategory(ctx context.Context, name string) (*Category, error) {
+	return s.repo.GetCategory(ctx, name)
+}
+
+// PutCategory creates or replaces a category.
+func (s *Service) PutCategory(ctx context.Context, name string, input CategoryInput, updatedBy *string) (*Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxCategoryLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("category name must be between 1 and %d characters", maxCategoryLength)}
+	}
+	if strings.ContainsAny(name, ". \t\n") {
+		return nil, &ValidationError{Message: "category name can't contain dots or whitespace"}
+	}
+	input.Description = strings.TrimSpace(input.Description)
+	if len(input.Description) > maxDescriptionLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("description must be %d characters or fewer", maxDescriptionLength)}
+	}
+
+	c := &Category{
+		Name:        name,
+		Description: input.Description,
+		Enforced:    input.Enforced,
+		UpdatedBy:   updatedBy,
+	}
+	if err := s.repo.UpsertCategory(ctx, c); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return c, nil
+}
+
+// DeleteCategory deletes a category. Categories with tags can't be deleted.
+func (s *Service) DeleteCategory(ctx context.Context, name string) error {
+	if err := s.repo.DeleteCategory(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ListTags lists managed tags, optionally only those in category.
+func (s *Service) ListTags(ctx context.Context, category string) ([]*Tag, error) {
+	return s.repo.ListTags(ctx, category)
+}
+
+func (s *Service) GetTag(ctx context.Context, name string) (*Tag, error) {
+	return s.repo.GetTag(ctx, name)
+}
+
+// PutTag creates or replaces a managed tag. A tag with a dot in its name
+// belongs to the category named by the part before the first dot, which
+// must exist.
+func (s *Service) PutTag(ctx context.Context, name string, input TagInput, updatedBy *string) (*Tag, error) {
+	name = strings.TrimSpace(name)
+	if err := validateTagName(name); err != nil {
+		return nil, err
+	}
+	input.Description = strings.TrimSpace(input.Description)
+	if len(input.Description) > maxDescriptionLength {
+		return nil, &ValidationError{Message: fmt.Sprintf("description must be %d characters or fewer", maxDescriptionLength)}
+	}
+
+	t := &Tag{
+		Name:        name,
+		Description: input.Description,
+		UpdatedBy:   updatedBy,
+	}
+	if category, ok := categoryOf(name); ok {
+		if _, err := s.repo.GetCategory(ctx, category); err != nil {
+			if errors.Is(err, ErrCategoryNotFound) {
+				return nil, &ValidationError{Message: fmt.Sprintf("category %q does not exist", category)}
+			}
+			return nil, err
+		}
+		t.Category = &category
+	}
+
+	if err := s.repo.UpsertTag(ctx, t); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return t, nil
+}
+
+// DeleteTag removes a tag from the taxonomy. Assets using it keep it.
+func (s *Service) DeleteTag(ctx context.Context, name string) error {
+	if err := s.repo.DeleteTag(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *Service) GetSettings(ctx context.Context) (*Settings, error) {
+	return s.repo.GetSettings(ctx)
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, input SettingsInput, updatedBy *string) (*Settings, error) {
+	settings := &Settings{Enforce: input.Enforce, UpdatedBy: updatedBy}
+	if err := s.repo.UpdateSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return settings, nil
+}
+
+// ListUnmanaged returns the free-form tags on assets, most used first,
+// with the managed tag each probably means.
+func (s *Service) ListUnmanaged(ctx context.Context, limit int) ([]UnmanagedTag, error) {
+	unmanaged, err := s.repo.ListUnmanagedTags(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	managed, err := s.repo.ListTags(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(managed))
+	for i, t := range managed {
+		names[i] = t.Name
+	}
+	for i := range unmanaged {
+		unmanaged[i].Suggestion = suggestMapping(unmanaged[i].Tag, names)
+	}
+	return unmanaged, nil
+}
+
+// Migrate replaces free-form tags on assets with managed ones. Each
+// mapping must lead to a managed tag, or be empty to remove the tag.
+func (s *Service) Migrate(ctx context.Context, input MigrationInput) (*MigrationResult, error) {
+	if len(input.Mappings) == 0 || len(input.Mappings) > maxMappings {
+		return nil, &ValidationError{Message: fmt.Sprintf("between 1 and %d mappings are required", maxMappings)}
+	}
+
+	from := make(map[string]bool, len(input.Mappings))
+	for i := range input.Mappings {
+		m := &input.Mappings[i]
+		m.From = strings.TrimSpace(m.From)
+		m.To = strings.TrimSpace(m.To)
+		if m.From == "" {
+			return nil, &ValidationError{Message: "every mapping needs a tag to map from"}
+		}
+		if m.From == m.To {
+			return nil, &ValidationError{Message: fmt.Sprintf("tag %q is mapped to itself", m.From)}
+		}
+		if from[m.From] {
+			return nil, &ValidationError{Message: fmt.Sprintf("tag %q is mapped more than once", m.From)}
+		}
+		from[m.From] = true
+	}
+	for _, m := range input.Mappings {
+		if m.To == "" {
+			continue
+		}
+		if from[m.To] {
+			// Mappings are applied one after another, so chains would
+			// depend on their order.
+			return nil, &ValidationError{Message: fmt.Sprintf("tag %q is both mapped and mapped to", m.To)}
+		}
+		if _, err := s.repo.GetTag(ctx, m.To); err != nil {
+			if errors.Is(err, ErrTagNotFound) {
+				return nil, &ValidationError{Message: fmt.Sprintf("%q is not a managed tag", m.To)}
+			}
+			return nil, err
+		}
+	}
+
+	var (
+		counts []int
+		err    error
+	)
+	if input.DryRun {
+		counts, err = s.repo.CountTagUsage(ctx, input.Mappings)
+	} else {
+		counts, err = s.repo.ApplyMappings(ctx, input.Mappings)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{DryRun: input.DryRun, Mappings: make([]MappingResult, len(input.Mappings))}
+	for i, m := range input.Mappings {
+		result.Mappings[i] = MappingResult{From: m.From, To: m.To, AssetCount: counts[i]}
+	}
+	return result, nil
+}
+
+// ValidateTags rejects tags the taxonomy doesn't allow: any unmanaged tag
+// when the taxonomy is enforced, otherwise unmanaged tags in enforced
+// categories. It implements asset.TagValidator.
+func (s *Service) ValidateTags(ctx context.Context, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	t, err := s.load(ctx)
+	if err != nil {
+		return fmt.Errorf("loading tag taxonomy: %w", err)
+	}
+
+	var rejected []string
+	for _, tag := range tags {
+		if t.tags[tag] {
+			continue
+		}
+		category, _ := categoryOf(tag)
+		if t.enforce || t.enforced[category] {
+			rejected = append(rejected, tag)
+		}
+	}
+	if len(rejected) > 0 {
+		return &ValidationError{Message: fmt.Sprintf("tags not in the tag taxonomy: %s", strings.Join(rejected, ", "))}
+	}
+	return nil
+}
+
+// load returns the taxonomy, cached so checking tags on every asset write
+// doesn't query it every time.
+func (s *Service) load(ctx context.Context) (taxonomy, error) {
+	s.mu.RLock()
+	if s.cache.tags != nil && time.Now().Before(s.cache.expiresAt) {
+		t := s.cache
+		s.mu.RUnlock()
+		return t, nil
+	}
+	s.mu.RUnlock()
+
+	settings, err := s.repo.GetSettings(ctx)
+	if err != nil {
+		return taxonomy{}, err
+	}
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return taxonomy{}, err
+	}
+	tags, err := s.repo.ListTags(ctx, "")
+	if err != nil {
+		return taxonomy{}, err
+	}
+
+	t := taxonomy{
+		enforce:   settings.Enforce,
+		tags:      make(map[string]bool, len(tags)),
+		enforced:  make(map[string]bool),
+		expiresAt: time.Now().Add(cacheTTL),
+	}
+	for _, tag := range tags {
+		t.tags[tag.Name] = true
+	}
+	for _, c := range categories {
+		if c.Enforced {
+			t.enforced[c.Name] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.cache = t
+	s.mu.Unlock()
+	return t, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.cache = taxonomy{}
+	s.mu.Unlock()
+}
+
+func validateTagName(name string) error {
+	if name == "" || len(name) > maxTagLength {
+		return &ValidationError{Message: fmt.Sprintf("tag name must be between 1 and %d characters", maxTagLength)}
+	}
+	for _, part := range strings.Split(name, ".") {
+		if strings.TrimSpace(part) == "" {
+			return &ValidationError{Message: fmt.Sprintf("invalid tag name %q", name)}
+		}
+	}
+	return nil
+}
+
+// categoryOf returns the category a tag name belongs to: the part before
+// the first dot.
+func categoryOf(tag string) (string, bool) {
+	category, _, found := strings.Cut(tag, ".")
+	if !found {
+		return "", false
+	}
+	return category, true
+}
+
+// suggestMapping returns the managed tag a free-form tag most likely
+// means. Tags match if they are spelled the same ignoring case and
+// separators, e.g. PII-Email and pii.email, or if the free-form tag is the
+// unambiguous last part of a managed tag, e.g. email and pii.email.
+func suggestMapping(tag string, managed []string) string {
+	normalized := normalizeTag(tag)
+	if normalized == "" {
+		return ""
+	}
+
+	var exact, suffix []string
+	for _, m := range managed {
+		nm := normalizeTag(m)
+		switch {
+		case nm == normalized:
+			exact = append(exact, m)
+		case strings.HasSuffix(nm, "."+normalized):
+			suffix = append(suffix, m)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0]
+	}
+	if len(exact) == 0 && len(suffix) == 1 {
+		return suffix[0]
+	}
+	return ""
+}
+
+func normalizeTag(tag string) string {
+	fields := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		switch r {
+		case '.', '-', '_', ':', '/', ' ':
+			return true
+		}
+		return false
+	})
+	return strings.Join(fields, ".")
+}
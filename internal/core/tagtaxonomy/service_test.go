@@ -0,0 +1,299 @@
+package tagtaxonomy
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	categories map[string]*Category
+	tags       map[string]*Tag
+	settings   Settings
+	// assetTags holds each asset's tags, keyed by asset ID.
+	assetTags map[string][]string
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		categories: map[string]*Category{},
+		tags:       map[string]*Tag{},
+		assetTags:  map[string][]string{},
+	}
+}
+
+func (f *fakeRepo) UpsertCategory(ctx context.Context, c *Category) error {
+	f.categories[c.Name] = c
+	return nil
+}
+
+func (f *fakeRepo) GetCategory(ctx context.Context, name string) (*Category, error) {
+	if c, ok := f.categories[name]; ok {
+		return c, nil
+	}
+	return nil, ErrCategoryNotFound
+}
+
+func (f *fakeRepo) ListCategories(ctx context.Context) ([]*Category, error) {
+	list := make([]*Category, 0, len(f.categories))
+	for _, c := range f.categories {
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+func (f *fakeRepo) DeleteCategory(ctx context.Context, name string) error {
+	if _, ok := f.categories[name]; !ok {
+		return ErrCategoryNotFound
+	}
+	for _, t := range f.tags {
+		if t.Category != nil && *t.Category == name {
+			return ErrCategoryInUse
+		}
+	}
+	delete(f.categories, name)
+	return nil
+}
+
+func (f *fakeRepo) UpsertTag(ctx context.Context, t *Tag) error {
+	f.tags[t.Name] = t
+	return nil
+}
+
+func (f *fakeRepo) GetTag(ctx context.Context, name string) (*Tag, error) {
+	if t, ok := f.tags[name]; ok {
+		return t, nil
+	}
+	return nil, ErrTagNotFound
+}
+
+func (f *fakeRepo) ListTags(ctx context.Context, category string) ([]*Tag, error) {
+	list := []*Tag{}
+	for _, t := range f.tags {
+		if category == "" || (t.Category != nil && *t.Category == category) {
+			list = append(list, t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (f *fakeRepo) DeleteTag(ctx context.Context, name string) error {
+	if _, ok := f.tags[name]; !ok {
+		return ErrTagNotFound
+	}
+	delete(f.tags, name)
+	return nil
+}
+
+func (f *fakeRepo) GetSettings(ctx context.Context) (*Settings, error) {
+	s := f.settings
+	return &s, nil
+}
+
+func (f *fakeRepo) UpdateSettings(ctx context.Context, s *Settings) error {
+	f.settings = *s
+	return nil
+}
+
+func (f *fakeRepo) ListUnmanagedTags(ctx context.Context, limit int) ([]UnmanagedTag, error) {
+	counts := map[string]int{}
+	for _, tags := range f.assetTags {
+		for _, tag := range tags {
+			if _, ok := f.tags[tag]; !ok {
+				counts[tag]++
+			}
+		}
+	}
+	list := []UnmanagedTag{}
+	for tag, n := range counts {
+		list = append(list, UnmanagedTag{Tag: tag, AssetCount: n})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Tag < list[j].Tag })
+	return list, nil
+}
+
+func (f *fakeRepo) CountTagUsage(ctx context.Context, mappings []Mapping) ([]int, error) {
+	counts := make([]int, len(mappings))
+	for i, m := range mappings {
+		for _, tags := range f.assetTags {
+			if contains(tags, m.From) {
+				counts[i]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeRepo) ApplyMappings(ctx context.Context, mappings []Mapping) ([]int, error) {
+	counts := make([]int, len(mappings))
+	for i, m := range mappings {
+		for id, tags := range f.assetTags {
+			if !contains(tags, m.From) {
+				continue
+			}
+			var updated []string
+			for _, tag := range tags {
+				switch {
+				case tag != m.From:
+					updated = append(updated, tag)
+				case m.To != "" && !contains(tags, m.To):
+					updated = append(updated, m.To)
+				}
+			}
+			f.assetTags[id] = updated
+			counts[i]++
+		}
+	}
+	return counts, nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPutTagCategory(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	_, err := svc.PutTag(ctx, "pii.email", TagInput{}, nil)
+	require.Error(t, err)
+	assert.True(t, IsValidationError(err), "the pii category doesn't exist yet")
+
+	_, err = svc.PutCategory(ctx, "pii", CategoryInput{Description: "Personal data"}, nil)
+	require.NoError(t, err)
+
+	tag, err := svc.PutTag(ctx, " pii.email ", TagInput{Description: " Email addresses "}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pii.email", tag.Name)
+	require.NotNil(t, tag.Category)
+	assert.Equal(t, "pii", *tag.Category)
+	assert.Equal(t, "Email addresses", tag.Description)
+
+	plain, err := svc.PutTag(ctx, "gold", TagInput{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, plain.Category)
+
+	assert.ErrorIs(t, svc.DeleteCategory(ctx, "pii"), ErrCategoryInUse)
+}
+
+func TestNameValidation(t *testing.T) {
+	svc := NewService(newFakeRepo())
+	ctx := context.Background()
+
+	for _, name := range []string{"", "pii.", ".email", "pii..email"} {
+		_, err := svc.PutTag(ctx, name, TagInput{}, nil)
+		assert.True(t, IsValidationError(err), "tag %q", name)
+	}
+	for _, name := range []string{"", "pii.email", "personal data"} {
+		_, err := svc.PutCategory(ctx, name, CategoryInput{}, nil)
+		assert.True(t, IsValidationError(err), "category %q", name)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	_, err := svc.PutCategory(ctx, "pii", CategoryInput{Enforced: true}, nil)
+	require.NoError(t, err)
+	_, err = svc.PutCategory(ctx, "team", CategoryInput{}, nil)
+	require.NoError(t, err)
+	_, err = svc.PutTag(ctx, "pii.email", TagInput{}, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.ValidateTags(ctx, []string{"pii.email", "team.payments", "adhoc"}))
+
+	err = svc.ValidateTags(ctx, []string{"pii.email", "pii.phone"})
+	require.Error(t, err)
+	assert.True(t, IsValidationError(err))
+	assert.Contains(t, err.Error(), "pii.phone")
+
+	_, err = svc.UpdateSettings(ctx, SettingsInput{Enforce: true}, nil)
+	require.NoError(t, err)
+	err = svc.ValidateTags(ctx, []string{"pii.email", "team.payments", "adhoc"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "team.payments, adhoc")
+}
+
+func TestMigrate(t *testing.T) {
+	repo := newFakeRepo()
+	repo.assetTags = map[string][]string{
+		"a1": {"Email", "gold"},
+		"a2": {"Email", "pii.email"},
+		"a3": {"temp"},
+	}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	_, err := svc.PutCategory(ctx, "pii", CategoryInput{}, nil)
+	require.NoError(t, err)
+	_, err = svc.PutTag(ctx, "pii.email", TagInput{}, nil)
+	require.NoError(t, err)
+
+	unmanaged, err := svc.ListUnmanaged(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []UnmanagedTag{
+		{Tag: "Email", AssetCount: 2, Suggestion: "pii.email"},
+		{Tag: "gold", AssetCount: 1},
+		{Tag: "temp", AssetCount: 1},
+	}, unmanaged)
+
+	mappings := []Mapping{{From: "Email", To: "pii.email"}, {From: "temp"}}
+	dryRun, err := svc.Migrate(ctx, MigrationInput{Mappings: mappings, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []MappingResult{{From: "Email", To: "pii.email", AssetCount: 2}, {From: "temp", AssetCount: 1}}, dryRun.Mappings)
+	assert.Equal(t, []string{"Email", "gold"}, repo.assetTags["a1"], "a dry run changes nothing")
+
+	_, err = svc.Migrate(ctx, MigrationInput{Mappings: mappings})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pii.email", "gold"}, repo.assetTags["a1"])
+	assert.Equal(t, []string{"pii.email"}, repo.assetTags["a2"])
+	assert.Empty(t, repo.assetTags["a3"])
+}
+
+func TestMigrateValidation(t *testing.T) {
+	repo := newFakeRepo()
+	repo.tags["gold"] = &Tag{Name: "gold"}
+	svc := NewService(repo)
+
+	tests := []struct {
+		name     string
+		mappings []Mapping
+	}{
+		{"no mappings", nil},
+		{"empty from", []Mapping{{From: " ", To: "gold"}}},
+		{"unmanaged target", []Mapping{{From: "Gold", To: "golden"}}},
+		{"mapped twice", []Mapping{{From: "Gold", To: "gold"}, {From: "Gold"}}},
+		{"chained", []Mapping{{From: "Gold", To: "gold"}, {From: "gold"}}},
+		{"to itself", []Mapping{{From: "gold", To: "gold"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Migrate(context.Background(), MigrationInput{Mappings: tt.mappings})
+			assert.True(t, IsValidationError(err), "got %v", err)
+		})
+	}
+}
+
+func TestSuggestMapping(t *testing.T) {
+	managed := []string{"pii.email", "pii.phone", "finance.phone", "tier.gold"}
+
+	assert.Equal(t, "pii.email", suggestMapping("PII-Email", managed))
+	assert.Equal(t, "pii.email", suggestMapping("pii_email", managed))
+	assert.Equal(t, "pii.email", suggestMapping("email", managed))
+	assert.Equal(t, "tier.gold", suggestMapping("Gold", managed))
+	assert.Equal(t, "", suggestMapping("phone", managed), "ambiguous")
+	assert.Equal(t, "", suggestMapping("temp", managed))
+	assert.Equal(t, "", suggestMapping("--", managed))
+}
@@ -0,0 +1,300 @@
+package tagtaxonomy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the tag taxonomy data access interface.
+type Repository interface {
+	UpsertCategory(ctx context.Context, c *Category) error
+	GetCategory(ctx context.Context, name string) (*Category, error)
+	ListCategories(ctx context.Context) ([]*Category, error)
+	DeleteCategory(ctx context.Context, name string) error
+
+	UpsertTag(ctx context.Context, t *Tag) error
+	GetTag(ctx context.Context, name string) (*Tag, error)
+	// ListTags lists managed tags, only those in category if it isn't empty.
+	ListTags(ctx context.Context, category string) ([]*Tag, error)
+	DeleteTag(ctx context.Context, name string) error
+
+	GetSettings(ctx context.Context) (*Settings, error)
+	UpdateSettings(ctx context.Context, s *Settings) error
+
+	// ListUnmanagedTags returns the tags on assets that aren't managed,
+	// most used first.
+	ListUnmanagedTags(ctx context.Context, limit int) ([]UnmanagedTag, error)
+	// CountTagUsage returns how many assets have each mapping's From tag.
+	CountTagUsage(ctx context.Context, mappings []Mapping) ([]int, error)
+	// ApplyMappings replaces each mapping's From tag with its To tag on
+	// every asset, in one transaction, and returns how many assets each
+	// mapping changed.
+	ApplyMappings(ctx context.Context, mappings []Mapping) ([]int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const selectCategory = `
+	SELECT c.name, c.description, c.enforced,
+	       (SELECT COUNT(*) FROM managed_tags t WHERE t.category = c.name),
+	       c.updated_by::text, c.created_at, c.updated_at
+	FROM tag_categories c`
+
+const selectTag = `
+	SELECT t.name, t.category, t.description,
+	       (SELECT COUNT(*) FROM assets a WHERE t.name = ANY(a.tags)),
+	       t.updated_by::text, t.created_at, t.updated_at
+	FROM managed_tags t`
+
+func (r *PostgresRepository) UpsertCategory(ctx context.Context, c *Category) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO tag_categories (name, description, enforced, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET description = EXCLUDED.description, enforced = EXCLUDED.enforced,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING (SELECT COUNT(*) FROM managed_tags t WHERE t.category = $1), created_at, updated_at`,
+		c.Name, c.Description, c.Enforced, c.UpdatedBy,
+	).Scan(&c.TagCount, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving tag category: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetCategory(ctx context.Context, name string) (*Category, error) {
+	c, err := scanCategory(r.db.QueryRow(ctx, selectCategory+" WHERE c.name = $1", name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("getting tag category: %w", err)
+	}
+	return c, nil
+}
+
+func (r *PostgresRepository) ListCategories(ctx context.Context) ([]*Category, error) {
+	rows, err := r.db.Query(ctx, selectCategory+" ORDER BY c.name")
+	if err != nil {
+		return nil, fmt.Errorf("listing tag categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []*Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning tag category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tag categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *PostgresRepository) DeleteCategory(ctx context.Context, name string) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM tag_categories WHERE name = $1", name)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCategoryInUse
+		}
+		return fmt.Errorf("deleting tag category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpsertTag(ctx context.Context, t *Tag) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO managed_tags (name, category, description, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET description = EXCLUDED.description,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING (SELECT COUNT(*) FROM assets a WHERE $1 = ANY(a.tags)), created_at, updated_at`,
+		t.Name, t.Category, t.Description, t.UpdatedBy,
+	).Scan(&t.AssetCount, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCategoryNotFound
+		}
+		return fmt.Errorf("saving managed tag: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetTag(ctx context.Context, name string) (*Tag, error) {
+	t, err := scanTag(r.db.QueryRow(ctx, selectTag+" WHERE t.name = $1", name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("getting managed tag: %w", err)
+	}
+	return t, nil
+}
+
+func (r *PostgresRepository) ListTags(ctx context.Context, category string) ([]*Tag, error) {
+	query := selectTag + " WHERE ($1 = '' OR t.category = $1) ORDER BY t.name"
+	rows, err := r.db.Query(ctx, query, category)
+	if err != nil {
+		return nil, fmt.Errorf("listing managed tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*Tag{}
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning managed tag: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating managed tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *PostgresRepository) DeleteTag(ctx context.Context, name string) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM managed_tags WHERE name = $1", name)
+	if err != nil {
+		return fmt.Errorf("deleting managed tag: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetSettings(ctx context.Context) (*Settings, error) {
+	var s Settings
+	err := r.db.QueryRow(ctx, `
+		SELECT enforce, updated_by::text, updated_at
+		FROM tag_taxonomy_settings WHERE id = 1`,
+	).Scan(&s.Enforce, &s.UpdatedBy, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &Settings{}, nil
+		}
+		return nil, fmt.Errorf("getting tag taxonomy settings: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *PostgresRepository) UpdateSettings(ctx context.Context, s *Settings) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO tag_taxonomy_settings (id, enforce, updated_by)
+		VALUES (1, $1, $2)
+		ON CONFLICT (id) DO UPDATE
+		SET enforce = EXCLUDED.enforce, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at`,
+		s.Enforce, s.UpdatedBy,
+	).Scan(&s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving tag taxonomy settings: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListUnmanagedTags(ctx context.Context, limit int) ([]UnmanagedTag, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT at.tag, COUNT(DISTINCT at.asset_id) AS asset_count
+		FROM asset_tags at
+		WHERE NOT EXISTS (SELECT 1 FROM managed_tags m WHERE m.name = at.tag)
+		GROUP BY at.tag
+		ORDER BY asset_count DESC, at.tag
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing unmanaged tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []UnmanagedTag{}
+	for rows.Next() {
+		var t UnmanagedTag
+		if err := rows.Scan(&t.Tag, &t.AssetCount); err != nil {
+			return nil, fmt.Errorf("scanning unmanaged tag: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating unmanaged tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *PostgresRepository) CountTagUsage(ctx context.Context, mappings []Mapping) ([]int, error) {
+	counts := make([]int, len(mappings))
+	for i, m := range mappings {
+		err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM assets WHERE $1 = ANY(tags)`, m.From).Scan(&counts[i])
+		if err != nil {
+			return nil, fmt.Errorf("counting assets tagged %q: %w", m.From, err)
+		}
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) ApplyMappings(ctx context.Context, mappings []Mapping) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Assets that already have the new tag just lose the old one, so tags
+	// aren't duplicated.
+	counts := make([]int, len(mappings))
+	for i, m := range mappings {
+		tag, err := tx.Exec(ctx, `
+			UPDATE assets
+			SET tags = CASE
+			        WHEN $2 = '' OR $2 = ANY(tags) THEN array_remove(tags, $1)
+			        ELSE array_replace(tags, $1, $2)
+			    END,
+			    updated_at = NOW()
+			WHERE $1 = ANY(tags)`, m.From, m.To)
+		if err != nil {
+			return nil, fmt.Errorf("mapping tag %q: %w", m.From, err)
+		}
+		counts[i] = int(tag.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return counts, nil
+}
+
+func scanCategory(row pgx.Row) (*Category, error) {
+	var c Category
+	if err := row.Scan(&c.Name, &c.Description, &c.Enforced, &c.TagCount, &c.UpdatedBy, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanTag(row pgx.Row) (*Tag, error) {
+	var t Tag
+	if err := row.Scan(&t.Name, &t.Category, &t.Description, &t.AssetCount, &t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
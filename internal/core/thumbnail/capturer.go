@@ -0,0 +1,57 @@
+package thumbnail
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+)
+
+const DefaultInterval = time.Hour
+
+// Capturer periodically captures thumbnails for assets that are due.
+type Capturer struct {
+	task *background.SingletonTask
+}
+
+// CapturerConfig configures the capturer.
+type CapturerConfig struct {
+	// Interval between capture runs. Default: 1 hour.
+	Interval time.Duration
+	// DB is the PostgreSQL connection pool for singleton coordination.
+	DB *pgxpool.Pool
+}
+
+// NewCapturer creates a new capturer for svc.
+func NewCapturer(svc *Service, config *CapturerConfig) *Capturer {
+	if config == nil {
+		config = &CapturerConfig{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+
+	return &Capturer{
+		task: background.NewSingletonTask(background.SingletonConfig{
+			Name:         "asset-thumbnail-capture",
+			DB:           config.DB,
+			Interval:     config.Interval,
+			InitialDelay: 5 * time.Minute,
+			TaskFn: func(ctx context.Context) error {
+				_, err := svc.RunOnce(ctx)
+				return err
+			},
+		}),
+	}
+}
+
+// Start begins periodic capture.
+func (c *Capturer) Start(ctx context.Context) {
+	c.task.Start(ctx)
+}
+
+// Stop stops capture.
+func (c *Capturer) Stop() {
+	c.task.Stop()
+}
@@ -0,0 +1,92 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Renderer takes a screenshot of a web page.
+type Renderer interface {
+	Render(ctx context.Context, pageURL string, width, height int) ([]byte, error)
+}
+
+// HTTPRenderer asks a headless browser service for a screenshot. The request
+// follows Browserless's /screenshot API: a JSON body with the page URL,
+// viewport and image options, answered with the image itself. The token, if
+// any, is sent as a token query parameter.
+type HTTPRenderer struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+func NewHTTPRenderer(endpoint, token string, timeout time.Duration) *HTTPRenderer {
+	return &HTTPRenderer{endpoint: endpoint, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+type renderViewport struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type renderOptions struct {
+	Type string `json:"type"`
+}
+
+type renderRequest struct {
+	URL      string         `json:"url"`
+	Viewport renderViewport `json:"viewport"`
+	Options  renderOptions  `json:"options"`
+}
+
+func (r *HTTPRenderer) Render(ctx context.Context, pageURL string, width, height int) ([]byte, error) {
+	body, err := json.Marshal(renderRequest{
+		URL:      pageURL,
+		Viewport: renderViewport{Width: width, Height: height},
+		Options:  renderOptions{Type: "png"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding render request: %w", err)
+	}
+
+	endpoint := r.endpoint
+	if r.token != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parsing renderer URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("token", r.token)
+		u.RawQuery = q.Encode()
+		endpoint = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating render request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling renderer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("renderer returned %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading screenshot: %w", err)
+	}
+	return data, nil
+}
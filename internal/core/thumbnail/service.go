@@ -0,0 +1,225 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/imageproc"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SourceUpload  = "upload"
+	SourceCapture = "capture"
+
+	DefaultWidth        = 640
+	DefaultHeight       = 400
+	DefaultRefreshAfter = 7 * 24 * time.Hour
+
+	// MaxUploadBytes caps uploaded and rendered images before they are
+	// scaled down.
+	MaxUploadBytes = 10 * 1024 * 1024
+
+	batchSize = 20
+)
+
+// DefaultAssetTypes are the BI asset types captured when none are configured.
+var DefaultAssetTypes = []string{"Dashboard", "Report", "Workbook", "Chart"}
+
+var (
+	ErrNotFound = errors.New("thumbnail not found")
+	// ErrCaptureDisabled is returned by Capture when no renderer is
+	// configured.
+	ErrCaptureDisabled = errors.New("thumbnail capture is not configured")
+	// ErrNoURL is returned by Capture for assets with no URL to render.
+	ErrNoURL    = errors.New("asset has no URL to capture")
+	ErrTooLarge = fmt.Errorf("image exceeds %d bytes", MaxUploadBytes)
+)
+
+// Thumbnail is a small image of an asset, e.g. a dashboard screenshot.
+type Thumbnail struct {
+	AssetID     string    `json:"asset_id"`
+	Source      string    `json:"source" enums:"upload,capture"`
+	SourceURL   string    `json:"source_url,omitempty"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int       `json:"size_bytes"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	URL         string    `json:"url"`
+	CapturedAt  time.Time `json:"captured_at"`
+	CreatedBy   *string   `json:"created_by,omitempty"`
+	Data        []byte    `json:"-"`
+} // @name AssetThumbnail
+
+// Candidate is an asset whose thumbnail can be captured from a URL.
+type Candidate struct {
+	AssetID string
+	URL     string
+}
+
+// Config controls the size of thumbnails and which assets are captured.
+type Config struct {
+	Width  int
+	Height int
+	// AssetTypes are captured by RunOnce. Matching is case-insensitive.
+	AssetTypes []string
+	// RefreshAfter is how long a captured thumbnail, or a failed attempt,
+	// is kept before the asset is captured again.
+	RefreshAfter time.Duration
+}
+
+type Service struct {
+	repo     Repository
+	renderer Renderer
+	config   Config
+	now      func() time.Time
+}
+
+// NewService creates a thumbnail service. renderer may be nil, in which case
+// thumbnails can only be uploaded.
+func NewService(repo Repository, renderer Renderer, config Config) *Service {
+	if config.Width <= 0 {
+		config.Width = DefaultWidth
+	}
+	if config.Height <= 0 {
+		config.Height = DefaultHeight
+	}
+	if len(config.AssetTypes) == 0 {
+		config.AssetTypes = DefaultAssetTypes
+	}
+	if config.RefreshAfter <= 0 {
+		config.RefreshAfter = DefaultRefreshAfter
+	}
+	return &Service{repo: repo, renderer: renderer, config: config, now: time.Now}
+}
+
+// URL returns the API path serving an asset's thumbnail image.
+func URL(assetID string) string {
+	return "/api/v1/thumbnails/assets/" + assetID
+}
+
+// Get returns the asset's thumbnail with its image data.
+func (s *Service) Get(ctx context.Context, assetID string) (*Thumbnail, error) {
+	return s.repo.Get(ctx, assetID)
+}
+
+// Upload scales image down to a thumbnail and stores it for the asset. An
+// uploaded thumbnail is never replaced by a captured one.
+func (s *Service) Upload(ctx context.Context, assetID string, data []byte, createdBy *string) (*Thumbnail, error) {
+	t, err := s.scale(assetID, data)
+	if err != nil {
+		return nil, err
+	}
+	t.Source = SourceUpload
+	t.CreatedBy = createdBy
+	if err := s.repo.Save(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Capture renders the asset's URL and stores the result as its thumbnail,
+// replacing any existing one.
+func (s *Service) Capture(ctx context.Context, assetID string) (*Thumbnail, error) {
+	if s.renderer == nil {
+		return nil, ErrCaptureDisabled
+	}
+	c, err := s.repo.GetCandidate(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if c.URL == "" {
+		return nil, ErrNoURL
+	}
+	return s.capture(ctx, *c)
+}
+
+func (s *Service) capture(ctx context.Context, c Candidate) (*Thumbnail, error) {
+	data, err := s.renderer.Render(ctx, c.URL, s.config.Width*2, s.config.Height*2)
+	if err == nil {
+		var t *Thumbnail
+		if t, err = s.scale(c.AssetID, data); err == nil {
+			t.Source = SourceCapture
+			t.SourceURL = c.URL
+			if err := s.repo.Save(ctx, t); err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+	}
+
+	if saveErr := s.repo.SaveFailure(ctx, c.AssetID, c.URL, err.Error(), s.now()); saveErr != nil {
+		log.Warn().Err(saveErr).Str("asset_id", c.AssetID).Msg("Failed to record thumbnail capture failure")
+	}
+	return nil, fmt.Errorf("capturing %s: %w", c.URL, err)
+}
+
+func (s *Service) scale(assetID string, data []byte) (*Thumbnail, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	result, err := imageproc.Thumbnail(data, s.config.Width, s.config.Height)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(result.Data))
+	if err != nil {
+		return nil, fmt.Errorf("reading thumbnail size: %w", err)
+	}
+	return &Thumbnail{
+		AssetID:     assetID,
+		ContentType: result.ContentType,
+		SizeBytes:   len(result.Data),
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		URL:         URL(assetID),
+		CapturedAt:  s.now(),
+		Data:        result.Data,
+	}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, assetID string) error {
+	return s.repo.Delete(ctx, assetID)
+}
+
+// WithThumbnails returns which of assetIDs have a thumbnail.
+func (s *Service) WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error) {
+	return s.repo.WithThumbnails(ctx, assetIDs)
+}
+
+// RunOnce captures thumbnails for assets of the configured types that have
+// none, or whose captured thumbnail is due a refresh, and returns how many
+// were captured. Failed captures are recorded, not returned as errors.
+func (s *Service) RunOnce(ctx context.Context) (int, error) {
+	if s.renderer == nil {
+		return 0, nil
+	}
+	types := make([]string, len(s.config.AssetTypes))
+	for i, t := range s.config.AssetTypes {
+		types[i] = strings.ToLower(t)
+	}
+
+	candidates, err := s.repo.ListDue(ctx, types, s.now().Add(-s.config.RefreshAfter), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	captured := 0
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			return captured, ctx.Err()
+		}
+		if _, err := s.capture(ctx, c); err != nil {
+			log.Warn().Err(err).Str("asset_id", c.AssetID).Msg("Failed to capture asset thumbnail")
+			continue
+		}
+		captured++
+	}
+	return captured, nil
+}
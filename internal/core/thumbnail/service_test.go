@@ -0,0 +1,128 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	due      []Candidate
+	types    []string
+	saved    []*Thumbnail
+	failures map[string]string
+}
+
+func (f *fakeRepo) Get(ctx context.Context, assetID string) (*Thumbnail, error) {
+	return nil, ErrNotFound
+}
+
+func (f *fakeRepo) Save(ctx context.Context, t *Thumbnail) error {
+	f.saved = append(f.saved, t)
+	return nil
+}
+
+func (f *fakeRepo) SaveFailure(ctx context.Context, assetID, sourceURL, message string, at time.Time) error {
+	if f.failures == nil {
+		f.failures = map[string]string{}
+	}
+	f.failures[assetID] = message
+	return nil
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, assetID string) error { return nil }
+
+func (f *fakeRepo) WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) GetCandidate(ctx context.Context, assetID string) (*Candidate, error) {
+	for _, c := range f.due {
+		if c.AssetID == assetID {
+			return &c, nil
+		}
+	}
+	return &Candidate{AssetID: assetID}, nil
+}
+
+func (f *fakeRepo) ListDue(ctx context.Context, assetTypes []string, before time.Time, limit int) ([]Candidate, error) {
+	f.types = assetTypes
+	return f.due, nil
+}
+
+type fakeRenderer struct {
+	pages map[string][]byte
+}
+
+func (f *fakeRenderer) Render(ctx context.Context, pageURL string, width, height int) ([]byte, error) {
+	if data, ok := f.pages[pageURL]; ok {
+		return data, nil
+	}
+	return nil, errors.New("page not found")
+}
+
+func screenshot(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func TestRunOnceCapturesAndRecordsFailures(t *testing.T) {
+	repo := &fakeRepo{due: []Candidate{
+		{AssetID: "a1", URL: "https://looker.example.com/dashboards/1"},
+		{AssetID: "a2", URL: "https://looker.example.com/dashboards/2"},
+	}}
+	renderer := &fakeRenderer{pages: map[string][]byte{
+		"https://looker.example.com/dashboards/1": screenshot(t, 1280, 800),
+	}}
+	svc := NewService(repo, renderer, Config{AssetTypes: []string{"Dashboard"}})
+
+	captured, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, captured)
+	assert.Equal(t, []string{"dashboard"}, repo.types)
+
+	require.Len(t, repo.saved, 1)
+	got := repo.saved[0]
+	assert.Equal(t, "a1", got.AssetID)
+	assert.Equal(t, SourceCapture, got.Source)
+	assert.Equal(t, "https://looker.example.com/dashboards/1", got.SourceURL)
+	assert.Equal(t, "image/jpeg", got.ContentType)
+	assert.Equal(t, DefaultWidth, got.Width)
+	assert.Equal(t, DefaultHeight, got.Height)
+	assert.Equal(t, "/api/v1/thumbnails/assets/a1", got.URL)
+
+	assert.Contains(t, repo.failures["a2"], "page not found")
+}
+
+func TestCapture(t *testing.T) {
+	_, err := NewService(&fakeRepo{}, nil, Config{}).Capture(context.Background(), "a1")
+	assert.ErrorIs(t, err, ErrCaptureDisabled)
+
+	_, err = NewService(&fakeRepo{}, &fakeRenderer{}, Config{}).Capture(context.Background(), "a1")
+	assert.ErrorIs(t, err, ErrNoURL)
+}
+
+func TestUpload(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo, nil, Config{Width: 100, Height: 100})
+	userID := "u1"
+
+	got, err := svc.Upload(context.Background(), "a1", screenshot(t, 400, 200), &userID)
+	require.NoError(t, err)
+	assert.Equal(t, SourceUpload, got.Source)
+	assert.Equal(t, 100, got.Width)
+	assert.Equal(t, 50, got.Height)
+	assert.Equal(t, &userID, got.CreatedBy)
+
+	_, err = svc.Upload(context.Background(), "a1", make([]byte, MaxUploadBytes+1), nil)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
@@ -0,0 +1,188 @@
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/core/asset"
+)
+
+// Repository defines the thumbnail data access interface.
+type Repository interface {
+	Get(ctx context.Context, assetID string) (*Thumbnail, error)
+	Save(ctx context.Context, t *Thumbnail) error
+	SaveFailure(ctx context.Context, assetID, sourceURL, message string, at time.Time) error
+	Delete(ctx context.Context, assetID string) error
+	WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error)
+	GetCandidate(ctx context.Context, assetID string) (*Candidate, error)
+	ListDue(ctx context.Context, assetTypes []string, before time.Time, limit int) ([]Candidate, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// candidateURL picks the page a renderer should capture for an asset: an
+// explicit URL in its metadata, else its first external link.
+const candidateURL = `COALESCE(
+	NULLIF(a.metadata->>'dashboard_url', ''),
+	NULLIF(a.metadata->>'url', ''),
+	NULLIF(a.external_links->0->>'url', ''),
+	'')`
+
+func (r *PostgresRepository) Get(ctx context.Context, assetID string) (*Thumbnail, error) {
+	t := &Thumbnail{AssetID: assetID, URL: URL(assetID)}
+	var sourceURL *string
+	err := r.db.QueryRow(ctx, `
+		SELECT source, source_url, content_type, size_bytes, width, height, data, captured_at, created_by
+		FROM asset_thumbnails
+		WHERE asset_id = $1 AND data IS NOT NULL`,
+		assetID,
+	).Scan(&t.Source, &sourceURL, &t.ContentType, &t.SizeBytes, &t.Width, &t.Height, &t.Data, &t.CapturedAt, &t.CreatedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting thumbnail: %w", err)
+	}
+	if sourceURL != nil {
+		t.SourceURL = *sourceURL
+	}
+	return t, nil
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, t *Thumbnail) error {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO asset_thumbnails
+			(asset_id, source, source_url, content_type, size_bytes, width, height, data, last_error, captured_at, created_by)
+		SELECT a.id, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULL, $9, $10
+		FROM assets a WHERE a.id = $1
+		ON CONFLICT (asset_id) DO UPDATE SET
+			source = EXCLUDED.source,
+			source_url = EXCLUDED.source_url,
+			content_type = EXCLUDED.content_type,
+			size_bytes = EXCLUDED.size_bytes,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			data = EXCLUDED.data,
+			last_error = NULL,
+			captured_at = EXCLUDED.captured_at,
+			created_by = EXCLUDED.created_by`,
+		t.AssetID, t.Source, t.SourceURL, t.ContentType, t.SizeBytes, t.Width, t.Height, t.Data, t.CapturedAt, t.CreatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("saving thumbnail: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return asset.ErrAssetNotFound
+	}
+	return nil
+}
+
+// SaveFailure records a failed capture so the asset is not retried until its
+// refresh is due. An existing captured image is kept, and uploaded
+// thumbnails are left untouched.
+func (r *PostgresRepository) SaveFailure(ctx context.Context, assetID, sourceURL, message string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO asset_thumbnails (asset_id, source, source_url, last_error, captured_at)
+		VALUES ($1, 'capture', $2, $3, $4)
+		ON CONFLICT (asset_id) DO UPDATE SET
+			source_url = EXCLUDED.source_url,
+			last_error = EXCLUDED.last_error,
+			captured_at = EXCLUDED.captured_at
+		WHERE asset_thumbnails.source = 'capture'`,
+		assetID, sourceURL, message, at,
+	)
+	if err != nil {
+		return fmt.Errorf("saving thumbnail failure: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, assetID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM asset_thumbnails WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return fmt.Errorf("deleting thumbnail: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) WithThumbnails(ctx context.Context, assetIDs []string) (map[string]bool, error) {
+	found := make(map[string]bool)
+	if len(assetIDs) == 0 {
+		return found, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT asset_id FROM asset_thumbnails
+		WHERE asset_id = ANY($1) AND data IS NOT NULL`,
+		assetIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing thumbnails: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning thumbnail: %w", err)
+		}
+		found[id] = true
+	}
+	return found, rows.Err()
+}
+
+func (r *PostgresRepository) GetCandidate(ctx context.Context, assetID string) (*Candidate, error) {
+	c := &Candidate{AssetID: assetID}
+	err := r.db.QueryRow(ctx, `SELECT `+candidateURL+` FROM assets a WHERE a.id = $1`, assetID).Scan(&c.URL)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, asset.ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("getting thumbnail candidate: %w", err)
+	}
+	return c, nil
+}
+
+// ListDue returns up to limit assets of assetTypes with a URL and either no
+// thumbnail or a captured one, or failed attempt, older than before.
+func (r *PostgresRepository) ListDue(ctx context.Context, assetTypes []string, before time.Time, limit int) ([]Candidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, `+candidateURL+` AS url
+		FROM assets a
+		LEFT JOIN asset_thumbnails t ON t.asset_id = a.id
+		WHERE lower(a.type) = ANY($1)
+		  AND NOT a.is_stub
+		  AND `+candidateURL+` <> ''
+		  AND (t.asset_id IS NULL OR (t.source = 'capture' AND t.captured_at < $2))
+		ORDER BY t.captured_at NULLS FIRST, a.id
+		LIMIT $3`,
+		assetTypes, before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing thumbnail candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []Candidate
+	for rows.Next() {
+		var c Candidate
+		if err := rows.Scan(&c.AssetID, &c.URL); err != nil {
+			return nil, fmt.Errorf("scanning thumbnail candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
@@ -0,0 +1,352 @@
+// Package timeline merges everything recorded about an asset into one feed,
+// newest first: schema and metadata revisions, ownership, runs, questions
+// and answers, and deprecation and incident announcements. Nothing is
+// stored here; events are read from the tables that already record them.
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/schematree"
+)
+
+var ErrNotFound = errors.New("asset not found")
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+type EventType string // @name AssetTimelineEventType
+
+const (
+	// EventSchemaChange is a revision that changed the asset's schema.
+	EventSchemaChange EventType = "schema_change"
+	// EventMetadataChange is a revision that changed any other tracked
+	// field, such as the description, metadata or tags.
+	EventMetadataChange EventType = "metadata_change"
+	// EventOwnership is an owner being added, or an ownership request
+	// being made or decided. Owner removals aren't recorded.
+	EventOwnership EventType = "ownership"
+	// EventRun is a finished run of a job producing the asset.
+	EventRun EventType = "run"
+	// EventComment is a question asked about the asset, or an answer.
+	EventComment EventType = "comment"
+	// EventAnnouncement is the asset being deprecated or linked to an
+	// incident.
+	EventAnnouncement EventType = "announcement"
+)
+
+// EventTypes are all event types, in the order they are documented.
+var EventTypes = []EventType{
+	EventSchemaChange, EventMetadataChange, EventOwnership,
+	EventRun, EventComment, EventAnnouncement,
+}
+
+// Actions say what happened within an event type.
+const (
+	ActionUpdated        = "updated"
+	ActionOwnerAdded     = "owner_added"
+	ActionRequested      = "requested"
+	ActionApproved       = "approved"
+	ActionRejected       = "rejected"
+	ActionRunComplete    = "complete"
+	ActionRunFailed      = "fail"
+	ActionRunAborted     = "abort"
+	ActionQuestion       = "question"
+	ActionAnswer         = "answer"
+	ActionDeprecated     = "deprecated"
+	ActionIncidentLinked = "incident_linked"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+
+	// maxColumnsListed bounds the columns named in a schema change's
+	// details; the counts are always complete.
+	maxColumnsListed = 50
+)
+
+// Event is one entry in an asset's timeline. ID is unique within the
+// timeline and says where the event was read from, such as revision:42.
+// Details depend on the type and action.
+type Event struct {
+	ID         string                 `json:"id"`
+	Type       EventType              `json:"type"`
+	Action     string                 `json:"action"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      string                 `json:"actor,omitempty"`
+	Summary    string                 `json:"summary"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+} // @name AssetTimelineEvent
+
+type Timeline struct {
+	Events []*Event `json:"events"`
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+} // @name AssetTimeline
+
+// Filter selects events. No types means all of them.
+type Filter struct {
+	Types  []EventType
+	Limit  int
+	Offset int
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// List returns a page of the asset's timeline, newest first.
+func (s *Service) List(ctx context.Context, assetID string, filter Filter) (*Timeline, error) {
+	types, err := normalizeTypes(filter.Types)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	exists, err := s.repo.AssetExists(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("checking asset: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	events, total, err := s.repo.List(ctx, assetID, types, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing timeline: %w", err)
+	}
+	for _, e := range events {
+		if e.Type == EventSchemaChange {
+			summarizeSchemaChange(e)
+		}
+		e.Summary = describe(e)
+	}
+	return &Timeline{Events: events, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// normalizeTypes checks and dedupes types, returning all types when none
+// are given.
+func normalizeTypes(types []EventType) ([]EventType, error) {
+	if len(types) == 0 {
+		return EventTypes, nil
+	}
+	valid := make(map[EventType]bool, len(EventTypes))
+	for _, t := range EventTypes {
+		valid[t] = true
+	}
+	seen := make(map[EventType]bool, len(types))
+	var out []EventType
+	for _, t := range types {
+		t = EventType(strings.TrimSpace(string(t)))
+		if !valid[t] {
+			names := make([]string, len(EventTypes))
+			for i, v := range EventTypes {
+				names[i] = string(v)
+			}
+			return nil, &ValidationError{Message: fmt.Sprintf("unknown event type %q, expected one of %s", t, strings.Join(names, ", "))}
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// summarizeSchemaChange replaces the old and new schemas of a schema
+// change with the column paths added, removed and retyped, which is what a
+// feed shows and is far smaller. The full schemas remain available from
+// the revision.
+func summarizeSchemaChange(e *Event) {
+	raw, err := json.Marshal(e.Details["change"])
+	delete(e.Details, "change")
+	if err != nil {
+		return
+	}
+
+	var change struct {
+		Old map[string]string `json:"old"`
+		New map[string]string `json:"new"`
+	}
+	if err := json.Unmarshal(raw, &change); err != nil {
+		return
+	}
+	added, removed, retyped := diffColumns(columnTypes(change.Old), columnTypes(change.New))
+	e.Details["added_columns"] = truncate(added)
+	e.Details["removed_columns"] = truncate(removed)
+	e.Details["retyped_columns"] = truncate(retyped)
+	e.Details["added_count"] = len(added)
+	e.Details["removed_count"] = len(removed)
+	e.Details["retyped_count"] = len(retyped)
+}
+
+// columnTypes maps the path of every column in schema, prefixed by its
+// section, to its type.
+func columnTypes(schema map[string]string) map[string]string {
+	out := map[string]string{}
+	var collect func(section string, columns []*schematree.Column)
+	collect = func(section string, columns []*schematree.Column) {
+		for _, c := range columns {
+			out[section+"."+c.Path] = c.Type
+			collect(section, c.Children)
+		}
+	}
+	for _, s := range schematree.Build(schema) {
+		collect(s.Name, s.Columns)
+	}
+	return out
+}
+
+func diffColumns(old, updated map[string]string) (added, removed, retyped []string) {
+	added, removed, retyped = []string{}, []string{}, []string{}
+	for path, typ := range updated {
+		oldType, ok := old[path]
+		switch {
+		case !ok:
+			added = append(added, path)
+		case oldType != typ:
+			retyped = append(retyped, path)
+		}
+	}
+	for path := range old {
+		if _, ok := updated[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(retyped)
+	return added, removed, retyped
+}
+
+func truncate(paths []string) []string {
+	if len(paths) > maxColumnsListed {
+		return paths[:maxColumnsListed]
+	}
+	return paths
+}
+
+// describe returns a one-line summary of e for feeds that don't render
+// each type themselves.
+func describe(e *Event) string {
+	str := func(key string) string {
+		s, _ := e.Details[key].(string)
+		return s
+	}
+	count := func(key string) int {
+		n, _ := e.Details[key].(int)
+		return n
+	}
+
+	switch e.Type {
+	case EventSchemaChange:
+		var parts []string
+		for _, p := range []struct {
+			key, verb string
+		}{{"added_count", "added"}, {"removed_count", "removed"}, {"retyped_count", "retyped"}} {
+			if n := count(p.key); n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s %s", n, plural(n, "column", "columns"), p.verb))
+			}
+		}
+		if len(parts) == 0 {
+			return "Schema updated"
+		}
+		return "Schema updated: " + strings.Join(parts, ", ")
+	case EventMetadataChange:
+		var fields []string
+		items, _ := e.Details["changed_fields"].([]interface{})
+		for _, item := range items {
+			if f, ok := item.(string); ok {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) == 0 {
+			return "Asset updated"
+		}
+		return "Updated " + strings.Join(fields, ", ")
+	case EventOwnership:
+		owner := str("owner")
+		switch e.Action {
+		case ActionOwnerAdded:
+			return fmt.Sprintf("%s added as owner", owner)
+		case ActionRequested:
+			return "Ownership requested" + onBehalfOf(owner)
+		case ActionApproved:
+			return "Ownership request approved" + onBehalfOf(owner)
+		case ActionRejected:
+			return "Ownership request rejected" + onBehalfOf(owner)
+		}
+	case EventRun:
+		job := str("job_name")
+		switch e.Action {
+		case ActionRunComplete:
+			return fmt.Sprintf("Run of %s completed", job)
+		case ActionRunFailed:
+			return fmt.Sprintf("Run of %s failed", job)
+		case ActionRunAborted:
+			return fmt.Sprintf("Run of %s aborted", job)
+		}
+	case EventComment:
+		switch e.Action {
+		case ActionQuestion:
+			return fmt.Sprintf("Asked %q", str("title"))
+		case ActionAnswer:
+			return fmt.Sprintf("Answered %q", str("title"))
+		}
+	case EventAnnouncement:
+		switch e.Action {
+		case ActionDeprecated:
+			if reason := str("reason"); reason != "" {
+				return "Deprecated: " + reason
+			}
+			return "Deprecated"
+		case ActionIncidentLinked:
+			return fmt.Sprintf("Incident linked: %s", str("title"))
+		}
+	}
+	return string(e.Type)
+}
+
+func onBehalfOf(owner string) string {
+	if owner == "" {
+		return ""
+	}
+	return " for " + owner
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,128 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	assets map[string]bool
+	events []*Event
+
+	gotTypes  []EventType
+	gotLimit  int
+	gotOffset int
+}
+
+func (f *fakeRepo) AssetExists(_ context.Context, assetID string) (bool, error) {
+	return f.assets[assetID], nil
+}
+
+func (f *fakeRepo) List(_ context.Context, _ string, types []EventType, limit, offset int) ([]*Event, int, error) {
+	f.gotTypes, f.gotLimit, f.gotOffset = types, limit, offset
+	return f.events, len(f.events), nil
+}
+
+// details decodes JSON the way the Postgres repository does.
+func details(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &out))
+	return out
+}
+
+func TestListValidatesTypes(t *testing.T) {
+	repo := &fakeRepo{assets: map[string]bool{"a1": true}}
+	svc := NewService(repo)
+
+	_, err := svc.List(context.Background(), "a1", Filter{Types: []EventType{"gossip"}})
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.List(context.Background(), "a1", Filter{Types: []EventType{EventRun, " run", EventComment}})
+	require.NoError(t, err)
+	assert.Equal(t, []EventType{EventRun, EventComment}, repo.gotTypes)
+
+	_, err = svc.List(context.Background(), "a1", Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, EventTypes, repo.gotTypes)
+}
+
+func TestListPagination(t *testing.T) {
+	repo := &fakeRepo{assets: map[string]bool{"a1": true}}
+	svc := NewService(repo)
+
+	page, err := svc.List(context.Background(), "a1", Filter{Limit: 0, Offset: -5})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLimit, repo.gotLimit)
+	assert.Equal(t, 0, repo.gotOffset)
+	assert.Equal(t, DefaultLimit, page.Limit)
+
+	_, err = svc.List(context.Background(), "a1", Filter{Limit: 10000, Offset: 20})
+	require.NoError(t, err)
+	assert.Equal(t, MaxLimit, repo.gotLimit)
+	assert.Equal(t, 20, repo.gotOffset)
+}
+
+func TestListUnknownAsset(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	_, err := svc.List(context.Background(), "missing", Filter{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestListSummarizesEvents(t *testing.T) {
+	now := time.Now()
+	repo := &fakeRepo{
+		assets: map[string]bool{"a1": true},
+		events: []*Event{
+			{
+				ID: "revision:2:schema", Type: EventSchemaChange, Action: ActionUpdated, OccurredAt: now,
+				Details: details(t, `{"revision_id": 2, "change": {
+					"old": {"columns": "[{\"name\":\"id\",\"type\":\"int\"},{\"name\":\"email\",\"type\":\"string\"}]"},
+					"new": {"columns": "[{\"name\":\"id\",\"type\":\"bigint\"},{\"name\":\"name\",\"type\":\"string\"},{\"name\":\"created\",\"type\":\"timestamp\"}]"}
+				}}`),
+			},
+			{
+				ID: "revision:2", Type: EventMetadataChange, Action: ActionUpdated, OccurredAt: now,
+				Details: details(t, `{"revision_id": 2, "changed_fields": ["description", "tags"]}`),
+			},
+			{
+				ID: "owner:1", Type: EventOwnership, Action: ActionOwnerAdded, OccurredAt: now,
+				Details: details(t, `{"owner": "data-platform", "owner_type": "team"}`),
+			},
+			{
+				ID: "run:1", Type: EventRun, Action: ActionRunFailed, OccurredAt: now,
+				Details: details(t, `{"job_name": "nightly_orders"}`),
+			},
+			{
+				ID: "question:1", Type: EventComment, Action: ActionQuestion, OccurredAt: now,
+				Details: details(t, `{"title": "Is this table PII?"}`),
+			},
+			{
+				ID: "deprecation:a1", Type: EventAnnouncement, Action: ActionDeprecated, OccurredAt: now,
+				Details: details(t, `{"reason": "Use orders_v2"}`),
+			},
+		},
+	}
+
+	page, err := NewService(repo).List(context.Background(), "a1", Filter{})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 6)
+
+	schema := page.Events[0]
+	assert.Equal(t, "Schema updated: 2 columns added, 1 column removed, 1 column retyped", schema.Summary)
+	assert.Equal(t, []string{"columns.created", "columns.name"}, schema.Details["added_columns"])
+	assert.Equal(t, []string{"columns.email"}, schema.Details["removed_columns"])
+	assert.Equal(t, []string{"columns.id"}, schema.Details["retyped_columns"])
+	assert.NotContains(t, schema.Details, "change")
+
+	assert.Equal(t, "Updated description, tags", page.Events[1].Summary)
+	assert.Equal(t, "data-platform added as owner", page.Events[2].Summary)
+	assert.Equal(t, "Run of nightly_orders failed", page.Events[3].Summary)
+	assert.Equal(t, `Asked "Is this table PII?"`, page.Events[4].Summary)
+	assert.Equal(t, "Deprecated: Use orders_v2", page.Events[5].Summary)
+}
@@ -0,0 +1,165 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository reads timeline events from the tables that record them.
+type Repository interface {
+	AssetExists(ctx context.Context, assetID string) (bool, error)
+	// List returns the asset's events of the given types, newest first,
+	// and how many there are in all.
+	List(ctx context.Context, assetID string, types []EventType, limit, offset int) ([]*Event, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) AssetExists(ctx context.Context, assetID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1)`, assetID).Scan(&exists)
+	return exists, err
+}
+
+// eventsQuery selects every event of asset $1 as (id, type, action,
+// occurred_at, actor, details). Each branch reads one source; filtering on
+// the constant type lets the planner skip the branches not asked for.
+const eventsQuery = `
+	SELECT 'revision:' || rv.id || ':schema', 'schema_change', 'updated', rv.created_at,
+	       COALESCE(rv.changed_by, ''),
+	       jsonb_build_object('revision_id', rv.id, 'source', rv.source, 'change', rv.changes->'schema')
+	FROM asset_revisions rv
+	WHERE rv.asset_id = $1 AND 'schema' = ANY(rv.changed_fields)
+
+	UNION ALL
+	SELECT 'revision:' || rv.id, 'metadata_change', 'updated', rv.created_at,
+	       COALESCE(rv.changed_by, ''),
+	       jsonb_build_object('revision_id', rv.id, 'source', rv.source,
+	                          'changed_fields', to_jsonb(array_remove(rv.changed_fields, 'schema')))
+	FROM asset_revisions rv
+	WHERE rv.asset_id = $1 AND cardinality(array_remove(rv.changed_fields, 'schema')) > 0
+
+	UNION ALL
+	SELECT 'owner:' || o.id, 'ownership', 'owner_added', o.created_at, '',
+	       jsonb_build_object('owner', COALESCE(u.username, t.name),
+	                          'owner_type', CASE WHEN o.user_id IS NOT NULL THEN 'user' ELSE 'team' END,
+	                          'owner_id', COALESCE(o.user_id, o.team_id))
+	FROM asset_owners o
+	LEFT JOIN users u ON u.id = o.user_id
+	LEFT JOIN teams t ON t.id = o.team_id
+	WHERE o.asset_id = $1
+
+	UNION ALL
+	SELECT 'ownership_request:' || req.id, 'ownership', 'requested', req.created_at,
+	       COALESCE(u.username, ''),
+	       jsonb_build_object('request_id', req.id, 'owner', COALESCE(t.name, u.username), 'reason', req.reason)
+	FROM asset_ownership_requests req
+	LEFT JOIN users u ON u.id = req.requested_by
+	LEFT JOIN teams t ON t.id = req.team_id
+	WHERE req.asset_id = $1 AND req.kind = 'ownership'
+
+	UNION ALL
+	SELECT 'ownership_request:' || req.id || ':' || req.status, 'ownership', req.status, req.decided_at,
+	       COALESCE(d.username, ''),
+	       jsonb_build_object('request_id', req.id, 'owner', COALESCE(t.name, u.username), 'note', req.decision_note)
+	FROM asset_ownership_requests req
+	LEFT JOIN users u ON u.id = req.requested_by
+	LEFT JOIN users d ON d.id = req.decided_by
+	LEFT JOIN teams t ON t.id = req.team_id
+	WHERE req.asset_id = $1 AND req.kind = 'ownership'
+	  AND req.status IN ('approved', 'rejected') AND req.decided_at IS NOT NULL
+
+	UNION ALL
+	SELECT 'run:' || rh.id, 'run', lower(rh.event_type), rh.event_time, '',
+	       jsonb_build_object('run_id', rh.run_id, 'job_namespace', rh.job_namespace, 'job_name', rh.job_name)
+	FROM run_history rh
+	WHERE rh.asset_id = $1 AND rh.event_type IN ('COMPLETE', 'FAIL', 'ABORT')
+
+	UNION ALL
+	SELECT 'question:' || q.id, 'comment', 'question', q.created_at, COALESCE(u.username, ''),
+	       jsonb_build_object('question_id', q.id, 'title', q.title)
+	FROM asset_questions q
+	LEFT JOIN users u ON u.id = q.created_by
+	WHERE q.asset_id = $1
+
+	UNION ALL
+	SELECT 'answer:' || a.id, 'comment', 'answer', a.created_at, COALESCE(u.username, ''),
+	       jsonb_build_object('question_id', q.id, 'answer_id', a.id, 'title', q.title,
+	                          'by_owner', a.by_owner, 'accepted', q.accepted_answer_id IS NOT DISTINCT FROM a.id)
+	FROM asset_answers a
+	JOIN asset_questions q ON q.id = a.question_id
+	LEFT JOIN users u ON u.id = a.created_by
+	WHERE q.asset_id = $1
+
+	UNION ALL
+	SELECT 'deprecation:' || dep.asset_id, 'announcement', 'deprecated', dep.deprecated_at,
+	       COALESCE(u.username, ''),
+	       jsonb_build_object('reason', dep.reason, 'sunset_at', dep.sunset_at,
+	                          'replacement_asset_id', dep.replacement_asset_id)
+	FROM asset_deprecations dep
+	LEFT JOIN users u ON u.id = dep.deprecated_by
+	WHERE dep.asset_id = $1
+
+	UNION ALL
+	SELECT 'incident:' || i.id, 'announcement', 'incident_linked', i.created_at, COALESCE(u.username, ''),
+	       jsonb_build_object('incident_id', i.id, 'provider', i.provider, 'external_id', i.external_id,
+	                          'title', i.title, 'status', i.status, 'severity', i.severity, 'url', i.url)
+	FROM asset_incident_links i
+	LEFT JOIN users u ON u.id = i.created_by
+	WHERE i.asset_id = $1`
+
+func (r *PostgresRepository) List(ctx context.Context, assetID string, types []EventType, limit, offset int) ([]*Event, int, error) {
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, type, action, occurred_at, actor, details, COUNT(*) OVER ()
+		FROM (`+eventsQuery+`) AS events (id, type, action, occurred_at, actor, details)
+		WHERE type = ANY($2)
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $3 OFFSET $4`, assetID, typeNames, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	total := 0
+	for rows.Next() {
+		var e Event
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.Type, &e.Action, &e.OccurredAt, &e.Actor, &details, &total); err != nil {
+			return nil, 0, fmt.Errorf("scanning event: %w", err)
+		}
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			return nil, 0, fmt.Errorf("unmarshaling event details: %w", err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Past the last page the window count isn't available.
+	if len(events) == 0 && offset > 0 {
+		err := r.db.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM (`+eventsQuery+`) AS events (id, type, action, occurred_at, actor, details)
+			WHERE type = ANY($2)`, assetID, typeNames).Scan(&total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("counting events: %w", err)
+		}
+	}
+	return events, total, nil
+}
@@ -2,10 +2,14 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ProviderSCIM is the identity provider of users provisioned through SCIM.
+const ProviderSCIM = "scim"
+
 type UserIdentity struct {
 	ID             string                 `json:"id"`
 	UserID         string                 `json:"user_id"`
@@ -33,6 +37,22 @@ func (s *service) AuthenticateOAuth(ctx context.Context, provider string, provid
 	email, _ := userInfo["email"].(string)
 	name, _ := userInfo["name"].(string)
 
+	if email != "" {
+		provisioned, err := s.provisionedUser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if provisioned != nil {
+			if !provisioned.Active {
+				return nil, ErrUnauthorized
+			}
+			if err := s.LinkOAuthAccount(ctx, provisioned.ID, provider, providerUserID, userInfo); err != nil {
+				return nil, fmt.Errorf("linking provisioned user: %w", err)
+			}
+			return provisioned, nil
+		}
+	}
+
 	input := CreateUserInput{
 		Username:          email,
 		Name:              name,
@@ -45,6 +65,29 @@ func (s *service) AuthenticateOAuth(ctx context.Context, provider string, provid
 	return s.Create(ctx, input)
 }
 
+// provisionedUser returns the SCIM-provisioned user with the username,
+// if there is one, so their first SSO login signs in as them.
+func (s *service) provisionedUser(ctx context.Context, username string) (*User, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("checking existing user: %w", err)
+	}
+
+	identities, err := s.repo.GetUserIdentities(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user identities: %w", err)
+	}
+	for _, identity := range identities {
+		if identity.Provider == ProviderSCIM {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
 func (s *service) LinkOAuthAccount(ctx context.Context, userID string, provider string, providerUserID string, userInfo map[string]interface{}) error {
 	email, _ := userInfo["email"].(string)
 	identity := &UserIdentity{
@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/mrn"
+)
+
+// maxCSVRows bounds the size of a single CSV manifest.
+const maxCSVRows = 50000
+
+// CSVManifest is the content of a CSV manifest drop.
+type CSVManifest struct {
+	Assets  []runs.CreateAssetInput
+	Lineage []runs.LineageInput
+}
+
+// ParseCSVManifest reads a CSV manifest. The header row names the columns:
+// name, type and provider are required; mrn, description, tags (separated by
+// ";") and upstream (MRNs separated by ";") are optional, and any
+// metadata.<key> column is stored as asset metadata.
+func ParseCSVManifest(r io.Reader) (*CSVManifest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("manifest is empty")
+		}
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "type", "provider"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	manifest := &CSVManifest{}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if line > maxCSVRows+1 {
+			return nil, fmt.Errorf("manifest exceeds %d rows", maxCSVRows)
+		}
+
+		get := func(col string) string {
+			i, ok := cols[col]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		name, assetType, provider := get("name"), get("type"), get("provider")
+		if name == "" && assetType == "" && provider == "" {
+			continue
+		}
+		if name == "" || assetType == "" || provider == "" {
+			return nil, fmt.Errorf("line %d: name, type and provider are required", line)
+		}
+
+		assetMRN := get("mrn")
+		if assetMRN == "" {
+			assetMRN = mrn.New(assetType, provider, name)
+		}
+
+		input := runs.CreateAssetInput{
+			Name:      name,
+			MRN:       &assetMRN,
+			Type:      assetType,
+			Providers: []string{provider},
+			Tags:      splitList(get("tags")),
+			Metadata:  map[string]interface{}{},
+			Schema:    map[string]interface{}{},
+		}
+		if desc := get("description"); desc != "" {
+			input.Description = &desc
+		}
+		for col := range cols {
+			if key, ok := strings.CutPrefix(col, "metadata."); ok && key != "" {
+				if v := get(col); v != "" {
+					input.Metadata[key] = v
+				}
+			}
+		}
+		manifest.Assets = append(manifest.Assets, input)
+
+		for _, upstream := range splitList(get("upstream")) {
+			manifest.Lineage = append(manifest.Lineage, runs.LineageInput{
+				Source: upstream,
+				Target: assetMRN,
+				Type:   "DIRECT",
+			})
+		}
+	}
+
+	return manifest, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
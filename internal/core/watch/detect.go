@@ -0,0 +1,164 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	KindDBT     = "dbt"
+	KindOpenAPI = "openapi"
+	KindCSV     = "csv"
+
+	// headSize is how much of a file is read to recognise it.
+	headSize = 4096
+)
+
+// dbtArtifacts are the files the dbt plugin reads from a target directory.
+var dbtArtifacts = map[string]bool{
+	"manifest.json":    true,
+	"catalog.json":     true,
+	"run_results.json": true,
+	"sources.json":     true,
+}
+
+var (
+	openAPIMarker    = regexp.MustCompile(`(?m)^\s*\{?\s*"?(openapi|swagger)"?\s*:`)
+	dbtProjectMarker = regexp.MustCompile(`"project_name"\s*:\s*"([^"]+)"`)
+)
+
+// Drop is a unit of metadata found in a watch folder: a dbt target
+// directory, a directory of OpenAPI specs or a single CSV manifest.
+type Drop struct {
+	// Key identifies the drop within its folder: the relative directory for
+	// dbt and OpenAPI drops, the relative file name for CSV manifests.
+	Key   string
+	Kind  string
+	Files []File
+	// Fingerprint changes whenever any file in the drop changes.
+	Fingerprint string
+	// Project is the dbt project name, when one could be found.
+	Project string
+}
+
+// Detect groups the files in a folder into drops. A directory holding a dbt
+// manifest.json is a dbt drop; otherwise a directory holding OpenAPI or
+// Swagger documents is an OpenAPI drop. Every .csv file is its own drop.
+func Detect(ctx context.Context, lister Lister, files []File) ([]Drop, error) {
+	byDir := map[string][]File{}
+	for _, f := range files {
+		byDir[f.Dir()] = append(byDir[f.Dir()], f)
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for d := range byDir {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	var drops []Drop
+	for _, dir := range dirs {
+		dirFiles := byDir[dir]
+
+		var manifest *File
+		for i, f := range dirFiles {
+			if path.Base(f.Name) == "manifest.json" {
+				manifest = &dirFiles[i]
+				break
+			}
+		}
+
+		if manifest != nil {
+			var artifacts []File
+			for _, f := range dirFiles {
+				if dbtArtifacts[path.Base(f.Name)] {
+					artifacts = append(artifacts, f)
+				}
+			}
+			head, err := readHead(ctx, lister, manifest.Name)
+			if err != nil {
+				return nil, err
+			}
+			drop := newDrop(dir, KindDBT, artifacts)
+			if m := dbtProjectMarker.FindSubmatch(head); m != nil {
+				drop.Project = string(m[1])
+			}
+			drops = append(drops, drop)
+		} else {
+			var specs []File
+			for _, f := range dirFiles {
+				if !isSpecCandidate(f.Name) {
+					continue
+				}
+				head, err := readHead(ctx, lister, f.Name)
+				if err != nil {
+					return nil, err
+				}
+				if openAPIMarker.Match(head) {
+					specs = append(specs, f)
+				}
+			}
+			if len(specs) > 0 {
+				drops = append(drops, newDrop(dir, KindOpenAPI, specs))
+			}
+		}
+
+		for _, f := range dirFiles {
+			if strings.EqualFold(path.Ext(f.Name), ".csv") {
+				drops = append(drops, newDrop(f.Name, KindCSV, []File{f}))
+			}
+		}
+	}
+
+	return drops, nil
+}
+
+func isSpecCandidate(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func readHead(ctx context.Context, lister Lister, name string) ([]byte, error) {
+	rc, err := lister.Open(ctx, name, headSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(rc, headSize)); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newDrop(key, kind string, files []File) Drop {
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	h := sha256.New()
+	for _, f := range files {
+		version := f.ETag
+		if version == "" {
+			version = f.ModTime.UTC().Format("2006-01-02T15:04:05.000000000Z")
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%s\n", f.Name, f.Size, version)
+	}
+
+	return Drop{
+		Key:         key,
+		Kind:        kind,
+		Files:       files,
+		Fingerprint: hex.EncodeToString(h.Sum(nil)),
+	}
+}
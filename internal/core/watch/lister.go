@@ -0,0 +1,234 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// File is a file found in a watch folder. Name is relative to the folder root
+// and always uses forward slashes.
+type File struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	ETag    string
+}
+
+// Dir returns the directory of the file relative to the folder root, or "."
+// for files at the root.
+func (f File) Dir() string {
+	return path.Dir(f.Name)
+}
+
+// Lister reads a watch folder.
+type Lister interface {
+	// List returns the files in the folder root and its immediate
+	// subdirectories.
+	List(ctx context.Context) ([]File, error)
+	// Open reads a file. When limit is positive only the first limit bytes
+	// are read.
+	Open(ctx context.Context, name string, limit int64) (io.ReadCloser, error)
+	// URI returns the location of name in the form plugins accept, e.g. a
+	// local path or s3://bucket/key.
+	URI(name string) string
+}
+
+// NewLister returns a lister for a local directory or an s3://bucket/prefix
+// URI.
+func NewLister(folder Folder) (Lister, error) {
+	if strings.HasPrefix(folder.Path, "s3://") {
+		return newS3Lister(folder)
+	}
+	if folder.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	info, err := os.Stat(folder.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", folder.Path)
+	}
+	return &localLister{root: filepath.Clean(folder.Path)}, nil
+}
+
+type localLister struct {
+	root string
+}
+
+func (l *localLister) List(ctx context.Context) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") && rel != "." {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if strings.Count(rel, "/") >= 1 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Name: rel, Size: info.Size(), ModTime: info.ModTime()})
+		if len(files) > maxFilesPerFolder {
+			return errTooManyFiles
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (l *localLister) Open(_ context.Context, name string, limit int64) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(l.root, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return f, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, limit), f}, nil
+}
+
+func (l *localLister) URI(name string) string {
+	if name == "." {
+		return l.root
+	}
+	return filepath.Join(l.root, filepath.FromSlash(name))
+}
+
+// s3Lister lists and reads objects with the S3 client. Credentials come from
+// the default AWS chain (environment, shared config or instance role).
+type s3Lister struct {
+	bucket string
+	prefix string
+	client *s3.Client
+}
+
+func newS3Lister(folder Folder) (*s3Lister, error) {
+	u, err := url.Parse(folder.Path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing S3 path: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("S3 path %q has no bucket", folder.Path)
+	}
+
+	prefix := strings.TrimPrefix(u.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if folder.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(folder.Region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Ranged reads of the first bytes never carry a whole-object
+		// checksum, so don't warn on each one.
+		o.DisableLogOutputChecksumValidationSkipped = true
+		if folder.Endpoint != "" {
+			// Custom endpoints (MinIO, LocalStack) generally need path-style URLs.
+			o.BaseEndpoint = aws.String(folder.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3Lister{bucket: u.Host, prefix: prefix, client: client}, nil
+}
+
+func (l *s3Lister) List(ctx context.Context) ([]File, error) {
+	var files []File
+	paginator := s3.NewListObjectsV2Paginator(l.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(l.bucket),
+		Prefix: aws.String(l.prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing S3 objects: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(aws.ToString(obj.Key), l.prefix)
+			if name == "" || strings.HasSuffix(name, "/") || strings.Count(name, "/") > 1 {
+				continue
+			}
+			files = append(files, File{
+				Name:    name,
+				Size:    aws.ToInt64(obj.Size),
+				ModTime: aws.ToTime(obj.LastModified),
+				ETag:    strings.Trim(aws.ToString(obj.ETag), `"`),
+			})
+			if len(files) > maxFilesPerFolder {
+				return files, errTooManyFiles
+			}
+		}
+	}
+	return files, nil
+}
+
+func (l *s3Lister) Open(ctx context.Context, name string, limit int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(l.bucket),
+		Key:    aws.String(l.prefix + name),
+	}
+	if limit > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=0-%d", limit-1))
+	}
+	out, err := l.client.GetObject(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("reading S3 object: %w", err)
+	}
+	return out.Body, nil
+}
+
+func (l *s3Lister) URI(name string) string {
+	key := strings.TrimSuffix(l.prefix, "/")
+	if name != "." {
+		key = path.Join(key, name)
+	}
+	return "s3://" + l.bucket + "/" + key
+}
@@ -0,0 +1,420 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/marmotdata/marmot/internal/core/runs"
+	"github.com/marmotdata/marmot/internal/plugin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultInterval = time.Minute
+
+	// DefaultSettleTime is how long a drop must go unchanged before it is
+	// ingested, so half-written artifacts aren't picked up.
+	DefaultSettleTime = 30 * time.Second
+
+	// ManagedBy marks the schedules created for watch folder drops.
+	ManagedBy = "watch-folder"
+
+	StatusQueued   = "queued"
+	StatusIngested = "ingested"
+	StatusFailed   = "failed"
+
+	maxFilesPerFolder = 5000
+)
+
+var (
+	ErrFolderNotFound = errors.New("watch folder not found")
+	errTooManyFiles   = fmt.Errorf("watch folder has more than %d files", maxFilesPerFolder)
+)
+
+// Folder is a directory or S3 prefix watched for metadata drops.
+type Folder struct {
+	Name string `json:"name"`
+	// Path is a local directory or an s3://bucket/prefix URI.
+	Path string `json:"path"`
+	// Region and Endpoint configure S3 access for listing the folder.
+	Region   string `json:"region,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Config is merged into the plugin config of every drop, e.g. to pass
+	// tags or S3 credentials to the plugin.
+	Config map[string]interface{} `json:"-"`
+} // @name WatchFolder
+
+// DropStatus records a drop picked up from a watch folder.
+type DropStatus struct {
+	Folder      string    `json:"folder"`
+	Key         string    `json:"key"`
+	Kind        string    `json:"kind" enums:"dbt,openapi,csv"`
+	Path        string    `json:"path"`
+	Fingerprint string    `json:"fingerprint"`
+	Status      string    `json:"status" enums:"queued,ingested,failed"`
+	JobRunID    *string   `json:"job_run_id,omitempty"`
+	RunID       *string   `json:"run_id,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	DetectedAt  time.Time `json:"detected_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+} // @name WatchFolderDrop
+
+// ScanResult summarises one scan of the watch folders.
+type ScanResult struct {
+	Folders int      `json:"folders"`
+	Drops   int      `json:"drops"`
+	Queued  int      `json:"queued"`
+	Skipped int      `json:"skipped"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+} // @name WatchFolderScanResult
+
+type watchedFolder struct {
+	Folder
+	lister Lister
+}
+
+// Service watches folders for dropped metadata files and ingests them as
+// runs. dbt and OpenAPI drops become managed schedules whose job runs are
+// executed by the ingestion scheduler; CSV manifests are ingested directly.
+type Service struct {
+	repo        Repository
+	scheduleSvc *runs.ScheduleService
+	runsSvc     runs.Service
+	folders     []watchedFolder
+	settleTime  time.Duration
+
+	scanMu sync.Mutex
+	task   *background.SingletonTask
+}
+
+// NewService creates a watch folder service. Folders whose path can't be
+// read are logged and skipped so one bad mount doesn't disable the others.
+func NewService(repo Repository, scheduleSvc *runs.ScheduleService, runsSvc runs.Service, folders []Folder) *Service {
+	s := &Service{
+		repo:        repo,
+		scheduleSvc: scheduleSvc,
+		runsSvc:     runsSvc,
+		settleTime:  DefaultSettleTime,
+	}
+
+	seen := map[string]bool{}
+	for _, f := range folders {
+		if f.Name == "" || seen[f.Name] {
+			log.Error().Str("path", f.Path).Msg("Watch folder needs a unique name, skipping")
+			continue
+		}
+		lister, err := NewLister(f)
+		if err != nil {
+			log.Error().Err(err).Str("folder", f.Name).Str("path", f.Path).Msg("Failed to open watch folder, skipping")
+			continue
+		}
+		seen[f.Name] = true
+		s.folders = append(s.folders, watchedFolder{Folder: f, lister: lister})
+	}
+
+	return s
+}
+
+// Folders returns the folders being watched.
+func (s *Service) Folders() []Folder {
+	folders := make([]Folder, len(s.folders))
+	for i, f := range s.folders {
+		folders[i] = f.Folder
+	}
+	return folders
+}
+
+// ListDrops returns drops picked up from the watch folders. An empty folder
+// lists drops from all folders.
+func (s *Service) ListDrops(ctx context.Context, folder string, limit, offset int) ([]*DropStatus, int, error) {
+	return s.repo.ListDrops(ctx, folder, limit, offset)
+}
+
+// Scan checks every folder, or only the named one, for new or changed drops
+// and ingests them.
+func (s *Service) Scan(ctx context.Context, folder string) (*ScanResult, error) {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+
+	result := &ScanResult{}
+	found := false
+	for _, f := range s.folders {
+		if folder != "" && f.Name != folder {
+			continue
+		}
+		found = true
+		result.Folders++
+		if err := s.scanFolder(ctx, f, result); err != nil {
+			log.Error().Err(err).Str("folder", f.Name).Msg("Failed to scan watch folder")
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", f.Name, err))
+		}
+	}
+	if folder != "" && !found {
+		return nil, ErrFolderNotFound
+	}
+
+	if result.Queued > 0 || result.Failed > 0 {
+		log.Info().
+			Int("queued", result.Queued).
+			Int("failed", result.Failed).
+			Int("skipped", result.Skipped).
+			Msg("Watch folder scan completed")
+	}
+
+	return result, nil
+}
+
+func (s *Service) scanFolder(ctx context.Context, f watchedFolder, result *ScanResult) error {
+	files, err := f.lister.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing files: %w", err)
+	}
+
+	drops, err := Detect(ctx, f.lister, files)
+	if err != nil {
+		return err
+	}
+	result.Drops += len(drops)
+
+	settled := time.Now().Add(-s.settleTime)
+	for _, drop := range drops {
+		if !dropSettled(drop, settled) {
+			result.Skipped++
+			continue
+		}
+
+		last, err := s.repo.GetFingerprint(ctx, f.Name, drop.Key)
+		if err != nil {
+			return err
+		}
+		if last == drop.Fingerprint {
+			result.Skipped++
+			continue
+		}
+
+		status := s.ingest(ctx, f, drop)
+		if err := s.repo.SaveDrop(ctx, status); err != nil {
+			return err
+		}
+		if status.Status == StatusFailed {
+			result.Failed++
+		} else {
+			result.Queued++
+		}
+	}
+
+	return nil
+}
+
+func dropSettled(drop Drop, before time.Time) bool {
+	for _, f := range drop.Files {
+		if f.ModTime.After(before) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) ingest(ctx context.Context, f watchedFolder, drop Drop) *DropStatus {
+	status := &DropStatus{
+		Folder:      f.Name,
+		Key:         drop.Key,
+		Kind:        drop.Kind,
+		Path:        f.lister.URI(drop.Key),
+		Fingerprint: drop.Fingerprint,
+		Status:      StatusQueued,
+	}
+
+	var err error
+	switch drop.Kind {
+	case KindCSV:
+		err = s.ingestCSV(ctx, f, drop, status)
+	default:
+		err = s.queuePluginRun(ctx, f, drop, status)
+	}
+	if err != nil {
+		msg := err.Error()
+		status.Status = StatusFailed
+		status.Error = &msg
+		log.Warn().Err(err).Str("folder", f.Name).Str("drop", drop.Key).Msg("Failed to ingest watch folder drop")
+	}
+
+	return status
+}
+
+// queuePluginRun registers the drop as a managed schedule and queues a job
+// run for it, so it is executed, tracked and retried like any other
+// scheduled ingestion.
+func (s *Service) queuePluginRun(ctx context.Context, f watchedFolder, drop Drop, status *DropStatus) error {
+	config := map[string]interface{}{}
+	for k, v := range f.Config {
+		config[k] = v
+	}
+
+	pluginID := drop.Kind
+	switch drop.Kind {
+	case KindDBT:
+		config["target_path"] = status.Path
+		project := drop.Project
+		if project == "" {
+			project = dropName(f.Name, drop.Key)
+		}
+		config["project_name"] = project
+	case KindOpenAPI:
+		config["spec_path"] = status.Path
+	default:
+		return fmt.Errorf("unsupported drop kind %q", drop.Kind)
+	}
+
+	schedule, err := s.scheduleSvc.SyncSchedule(ctx, pipelineName(f.Name, drop.Key), pluginID, config, "", ManagedBy)
+	if err != nil {
+		return fmt.Errorf("registering schedule: %w", err)
+	}
+
+	jobRun, err := s.scheduleSvc.CreateJobRun(ctx, &schedule.ID, ManagedBy)
+	if err != nil {
+		return fmt.Errorf("queueing job run: %w", err)
+	}
+	status.JobRunID = &jobRun.ID
+
+	return nil
+}
+
+// ingestCSV reads a CSV manifest and ingests it as a run. Each manifest is
+// its own pipeline, so rows removed from a manifest are removed from the
+// catalog on the next run without touching other drops.
+func (s *Service) ingestCSV(ctx context.Context, f watchedFolder, drop Drop, status *DropStatus) error {
+	rc, err := f.lister.Open(ctx, drop.Key, 0)
+	if err != nil {
+		return fmt.Errorf("opening manifest: %w", err)
+	}
+	manifest, err := ParseCSVManifest(rc)
+	rc.Close()
+	if err != nil {
+		return fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	pipeline := pipelineName(f.Name, drop.Key)
+	run, err := s.runsSvc.StartRun(ctx, pipeline, KindCSV, ManagedBy, plugin.RawPluginConfig{"path": status.Path})
+	if err != nil {
+		return fmt.Errorf("starting run: %w", err)
+	}
+	status.RunID = &run.RunID
+
+	if jobRun, err := s.scheduleSvc.CreateCLIJobRun(ctx, pipeline, KindCSV, run.ID, ManagedBy); err != nil {
+		log.Warn().Err(err).Str("pipeline", pipeline).Msg("Failed to create job run for watch folder drop")
+	} else {
+		status.JobRunID = &jobRun.ID
+	}
+
+	response, err := s.runsSvc.ProcessEntities(ctx, run.RunID, manifest.Assets, manifest.Lineage, nil, nil, pipeline, KindCSV)
+	if err != nil {
+		_ = s.runsSvc.CompleteRun(ctx, run.RunID, plugin.StatusFailed, nil, err.Error())
+		s.completeJobRun(ctx, status, false, err.Error(), nil)
+		return fmt.Errorf("processing entities: %w", err)
+	}
+
+	summary := &plugin.RunSummary{
+		AssetsDeleted: len(response.StaleEntitiesRemoved),
+		TotalEntities: len(manifest.Assets) + len(manifest.Lineage),
+		Entities:      response.Summary,
+	}
+	for _, a := range response.Assets {
+		switch a.Status {
+		case runs.StatusCreated:
+			summary.AssetsCreated++
+		case runs.StatusUpdated:
+			summary.AssetsUpdated++
+		}
+	}
+	for _, l := range response.Lineage {
+		if l.Status == runs.StatusCreated {
+			summary.LineageCreated++
+		}
+	}
+
+	if err := s.runsSvc.CompleteRun(ctx, run.RunID, plugin.StatusCompleted, summary, ""); err != nil {
+		return fmt.Errorf("completing run: %w", err)
+	}
+	s.completeJobRun(ctx, status, true, "", summary)
+	status.Status = StatusIngested
+
+	return nil
+}
+
+func (s *Service) completeJobRun(ctx context.Context, status *DropStatus, success bool, errMsg string, summary *plugin.RunSummary) {
+	if status.JobRunID == nil {
+		return
+	}
+	if summary != nil && len(summary.Entities) > 0 {
+		if err := s.scheduleSvc.UpdateJobRunEntityCounts(ctx, *status.JobRunID, summary.Entities); err != nil {
+			log.Warn().Err(err).Str("job_run_id", *status.JobRunID).Msg("Failed to record entity counts for watch folder drop")
+		}
+	}
+	var msg *string
+	if errMsg != "" {
+		msg = &errMsg
+	}
+	if summary == nil {
+		summary = &plugin.RunSummary{}
+	}
+	if err := s.scheduleSvc.CompleteJobRun(ctx, *status.JobRunID, success, msg,
+		summary.AssetsCreated, summary.AssetsUpdated, summary.AssetsDeleted, summary.LineageCreated, 0); err != nil {
+		log.Warn().Err(err).Str("job_run_id", *status.JobRunID).Msg("Failed to complete job run for watch folder drop")
+	}
+}
+
+var nameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// pipelineName derives a stable pipeline and schedule name for a drop.
+func pipelineName(folder, key string) string {
+	name := "watch-" + dropName(folder, key)
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name
+}
+
+func dropName(folder, key string) string {
+	key = strings.TrimSuffix(key, path.Ext(key))
+	parts := []string{folder}
+	if key != "." && key != "" {
+		parts = append(parts, key)
+	}
+	return strings.Trim(nameSanitizer.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-"), "-")
+}
+
+// Start periodically scans the watch folders. Only one server instance scans
+// at a time.
+func (s *Service) Start(ctx context.Context, db *pgxpool.Pool, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	s.task = background.NewSingletonTask(background.SingletonConfig{
+		Name:         "watch-folders",
+		DB:           db,
+		Interval:     interval,
+		InitialDelay: 15 * time.Second,
+		TaskFn: func(ctx context.Context) error {
+			_, err := s.Scan(ctx, "")
+			return err
+		},
+	})
+	s.task.Start(ctx)
+}
+
+// Stop halts periodic scanning.
+func (s *Service) Stop() {
+	if s.task != nil {
+		s.task.Stop()
+	}
+}
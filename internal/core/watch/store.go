@@ -0,0 +1,118 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the watch folder data access interface.
+type Repository interface {
+	GetFingerprint(ctx context.Context, folder, key string) (string, error)
+	SaveDrop(ctx context.Context, drop *DropStatus) error
+	ListDrops(ctx context.Context, folder string, limit, offset int) ([]*DropStatus, int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// GetFingerprint returns the fingerprint of the last version of a drop that
+// was picked up, or "" if there is none.
+func (r *PostgresRepository) GetFingerprint(ctx context.Context, folder, key string) (string, error) {
+	var fingerprint string
+	err := r.db.QueryRow(ctx, `
+		SELECT fingerprint FROM watch_folder_drops
+		WHERE folder = $1 AND drop_key = $2`,
+		folder, key,
+	).Scan(&fingerprint)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("getting drop fingerprint: %w", err)
+	}
+	return fingerprint, nil
+}
+
+func (r *PostgresRepository) SaveDrop(ctx context.Context, drop *DropStatus) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO watch_folder_drops (folder, drop_key, kind, path, fingerprint, status, job_run_id, run_id, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (folder, drop_key) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			path = EXCLUDED.path,
+			fingerprint = EXCLUDED.fingerprint,
+			status = EXCLUDED.status,
+			job_run_id = EXCLUDED.job_run_id,
+			run_id = EXCLUDED.run_id,
+			error = EXCLUDED.error,
+			detected_at = NOW(),
+			updated_at = NOW()
+		RETURNING detected_at, updated_at`,
+		drop.Folder, drop.Key, drop.Kind, drop.Path, drop.Fingerprint, drop.Status,
+		drop.JobRunID, drop.RunID, drop.Error,
+	).Scan(&drop.DetectedAt, &drop.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saving watch folder drop: %w", err)
+	}
+	return nil
+}
+
+// ListDrops returns drops, most recently updated first. For drops ingested
+// through a scheduled job, Status reflects the job's current state.
+func (r *PostgresRepository) ListDrops(ctx context.Context, folder string, limit, offset int) ([]*DropStatus, int, error) {
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM watch_folder_drops WHERE ($1 = '' OR folder = $1)`,
+		folder,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting watch folder drops: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT d.folder, d.drop_key, d.kind, d.path, d.fingerprint,
+		       CASE
+		           WHEN j.status = 'succeeded' THEN 'ingested'
+		           WHEN j.status IN ('failed', 'cancelled') THEN 'failed'
+		           ELSE d.status
+		       END,
+		       d.job_run_id, d.run_id, COALESCE(d.error, j.error_message),
+		       d.detected_at, d.updated_at
+		FROM watch_folder_drops d
+		LEFT JOIN ingestion_job_runs j ON j.id = d.job_run_id
+		WHERE ($1 = '' OR d.folder = $1)
+		ORDER BY d.updated_at DESC
+		LIMIT $2 OFFSET $3`,
+		folder, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing watch folder drops: %w", err)
+	}
+	defer rows.Close()
+
+	drops := []*DropStatus{}
+	for rows.Next() {
+		d := &DropStatus{}
+		if err := rows.Scan(
+			&d.Folder, &d.Key, &d.Kind, &d.Path, &d.Fingerprint, &d.Status,
+			&d.JobRunID, &d.RunID, &d.Error, &d.DetectedAt, &d.UpdatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scanning watch folder drop: %w", err)
+		}
+		drops = append(drops, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating watch folder drops: %w", err)
+	}
+
+	return drops, total, nil
+}
@@ -0,0 +1,143 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSVManifest(t *testing.T) {
+	manifest, err := ParseCSVManifest(strings.NewReader("\ufeffname,type,provider,tags,upstream,metadata.owner\n" +
+		"revenue,Sheet,Excel,finance; kpi,mrn://table/postgres/orders,finance@example.com\n" +
+		",,\n"))
+	require.NoError(t, err)
+	require.Len(t, manifest.Assets, 1)
+
+	a := manifest.Assets[0]
+	assert.Equal(t, "revenue", a.Name)
+	assert.Equal(t, []string{"Excel"}, a.Providers)
+	assert.Equal(t, []string{"finance", "kpi"}, a.Tags)
+	assert.Equal(t, "finance@example.com", a.Metadata["owner"])
+	require.Len(t, manifest.Lineage, 1)
+	assert.Equal(t, "mrn://table/postgres/orders", manifest.Lineage[0].Source)
+	assert.Equal(t, *a.MRN, manifest.Lineage[0].Target)
+
+	_, err = ParseCSVManifest(strings.NewReader("name,type\nrevenue,Sheet\n"))
+	assert.Error(t, err)
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	write("shop/manifest.json", `{"metadata": {"project_name": "jaffle_shop"}}`)
+	write("shop/catalog.json", `{}`)
+	write("shop/graph.gpickle", ``)
+	write("api/openapi.yaml", "openapi: 3.0.0\n")
+	write("api/notes.json", `{"hello": "world"}`)
+	write("sheets.csv", "name,type,provider\n")
+	write(".hidden/manifest.json", `{}`)
+
+	lister, err := NewLister(Folder{Name: "drops", Path: root})
+	require.NoError(t, err)
+	files, err := lister.List(context.Background())
+	require.NoError(t, err)
+
+	drops, err := Detect(context.Background(), lister, files)
+	require.NoError(t, err)
+	require.Len(t, drops, 3)
+
+	byKey := map[string]Drop{}
+	for _, d := range drops {
+		byKey[d.Key] = d
+	}
+	assert.Equal(t, KindCSV, byKey["sheets.csv"].Kind)
+	assert.Equal(t, KindOpenAPI, byKey["api"].Kind)
+	assert.Len(t, byKey["api"].Files, 1)
+	assert.Equal(t, KindDBT, byKey["shop"].Kind)
+	assert.Equal(t, "jaffle_shop", byKey["shop"].Project)
+	assert.Len(t, byKey["shop"].Files, 2)
+
+	before := byKey["shop"].Fingerprint
+	write("shop/catalog.json", `{"nodes": {}}`)
+	files, err = lister.List(context.Background())
+	require.NoError(t, err)
+	drops, err = Detect(context.Background(), lister, files)
+	require.NoError(t, err)
+	for _, d := range drops {
+		if d.Key == "shop" {
+			assert.NotEqual(t, before, d.Fingerprint)
+		}
+	}
+}
+
+func TestS3Lister(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256")
+
+		if r.URL.Path == "/landing/drops/sub dir/report.csv" {
+			ranges = append(ranges, r.Header.Get("Range"))
+			_, _ = io.WriteString(w, "name,type\n")
+			return
+		}
+
+		require.Equal(t, "/landing", r.URL.Path)
+		assert.Equal(t, "drops/", r.URL.Query().Get("prefix"))
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Query().Get("continuation-token") == "" {
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>true</IsTruncated><NextContinuationToken>page-2</NextContinuationToken>
+				<Contents><Key>drops/root.csv</Key><Size>10</Size><ETag>"abc"</ETag><LastModified>2026-01-02T03:04:05Z</LastModified></Contents>
+				<Contents><Key>drops/sub dir/</Key><Size>0</Size></Contents>
+			</ListBucketResult>`)
+			return
+		}
+		fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>
+			<Contents><Key>drops/sub dir/report.csv</Key><Size>20</Size><ETag>"def"</ETag><LastModified>2026-01-02T03:04:05Z</LastModified></Contents>
+			<Contents><Key>drops/a/b/too-deep.csv</Key><Size>30</Size></Contents>
+		</ListBucketResult>`)
+	}))
+	defer server.Close()
+
+	lister, err := NewLister(Folder{Path: "s3://landing/drops", Region: "eu-west-1", Endpoint: server.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	files, err := lister.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "root.csv", files[0].Name)
+	assert.Equal(t, int64(10), files[0].Size)
+	assert.Equal(t, "abc", files[0].ETag)
+	assert.Equal(t, "sub dir/report.csv", files[1].Name)
+	assert.Equal(t, "sub dir", files[1].Dir())
+
+	body, err := lister.Open(ctx, "sub dir/report.csv", 512)
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "name,type\n", string(data))
+	assert.Equal(t, []string{"bytes=0-511"}, ranges)
+
+	assert.Equal(t, "s3://landing/drops/sub dir/report.csv", lister.URI("sub dir/report.csv"))
+	assert.Equal(t, "s3://landing/drops", lister.URI("."))
+}
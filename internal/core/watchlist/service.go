@@ -0,0 +1,132 @@
+// Package watchlist lets users watch assets, data products and glossary
+// terms. Watchers are notified when what they watch changes, and the number
+// of watchers is shown on the asset.
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound       = errors.New("watch not found")
+	ErrEntityNotFound = errors.New("entity not found")
+)
+
+const (
+	EntityAsset        = "asset"
+	EntityDataProduct  = "data_product"
+	EntityGlossaryTerm = "glossary_term"
+)
+
+// ValidationError represents a user-facing validation failure.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// IsValidationError reports whether err is a user-facing validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// Watch is an entity a user watches.
+type Watch struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Name       string    `json:"name"`
+	MRN        string    `json:"mrn,omitempty"`
+	AssetType  string    `json:"asset_type,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+} // @name Watch
+
+type ListResult struct {
+	Watches []*Watch `json:"watches"`
+	Total   int      `json:"total"`
+} // @name WatchListResult
+
+// Status is whether the current user watches an entity, and how many users
+// do.
+type Status struct {
+	Watching     bool `json:"watching"`
+	WatcherCount int  `json:"watcher_count"`
+} // @name WatchStatus
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Watch starts watching an entity. Watching an entity already watched is not
+// an error.
+func (s *Service) Watch(ctx context.Context, userID, entityType, entityID string) (*Watch, error) {
+	if err := validateEntityType(entityType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Add(ctx, userID, entityType, entityID); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, userID, entityType, entityID)
+}
+
+func (s *Service) Unwatch(ctx context.Context, userID, entityType, entityID string) error {
+	if err := validateEntityType(entityType); err != nil {
+		return err
+	}
+	return s.repo.Remove(ctx, userID, entityType, entityID)
+}
+
+// Status returns whether userID watches an entity and its watcher count.
+func (s *Service) Status(ctx context.Context, userID, entityType, entityID string) (*Status, error) {
+	if err := validateEntityType(entityType); err != nil {
+		return nil, err
+	}
+	return s.repo.Status(ctx, userID, entityType, entityID)
+}
+
+// List returns what a user watches, most recently watched first. An empty
+// entityType lists every kind of entity.
+func (s *Service) List(ctx context.Context, userID, entityType string, limit, offset int) (*ListResult, error) {
+	if entityType != "" {
+		if err := validateEntityType(entityType); err != nil {
+			return nil, err
+		}
+	}
+	watches, total, err := s.repo.List(ctx, userID, entityType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ListResult{Watches: watches, Total: total}, nil
+}
+
+// Watchers returns the IDs of the users watching an entity, for
+// notification fan-out.
+func (s *Service) Watchers(ctx context.Context, entityType, entityID string) ([]string, error) {
+	if err := validateEntityType(entityType); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWatchers(ctx, entityType, entityID)
+}
+
+// CountWatchers returns the number of users watching each asset.
+func (s *Service) CountWatchers(ctx context.Context, assetIDs []string) (map[string]int, error) {
+	if len(assetIDs) == 0 {
+		return map[string]int{}, nil
+	}
+	return s.repo.CountAssetWatchers(ctx, assetIDs)
+}
+
+func validateEntityType(entityType string) error {
+	if _, ok := entityColumns[entityType]; !ok {
+		return &ValidationError{Message: fmt.Sprintf("invalid entity type %q, must be one of %s, %s or %s",
+			entityType, EntityAsset, EntityDataProduct, EntityGlossaryTerm)}
+	}
+	return nil
+}
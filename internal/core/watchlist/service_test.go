@@ -0,0 +1,125 @@
+package watchlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchKey struct {
+	userID, entityType, entityID string
+}
+
+type fakeRepo struct {
+	watches map[watchKey]bool
+}
+
+func (f *fakeRepo) Add(_ context.Context, userID, entityType, entityID string) error {
+	f.watches[watchKey{userID, entityType, entityID}] = true
+	return nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, userID, entityType, entityID string) (*Watch, error) {
+	if !f.watches[watchKey{userID, entityType, entityID}] {
+		return nil, ErrNotFound
+	}
+	return &Watch{EntityType: entityType, EntityID: entityID}, nil
+}
+
+func (f *fakeRepo) Remove(_ context.Context, userID, entityType, entityID string) error {
+	k := watchKey{userID, entityType, entityID}
+	if !f.watches[k] {
+		return ErrNotFound
+	}
+	delete(f.watches, k)
+	return nil
+}
+
+func (f *fakeRepo) Status(_ context.Context, userID, entityType, entityID string) (*Status, error) {
+	status := &Status{}
+	for k := range f.watches {
+		if k.entityType == entityType && k.entityID == entityID {
+			status.WatcherCount++
+			status.Watching = status.Watching || k.userID == userID
+		}
+	}
+	return status, nil
+}
+
+func (f *fakeRepo) List(_ context.Context, userID, entityType string, _, _ int) ([]*Watch, int, error) {
+	var out []*Watch
+	for k := range f.watches {
+		if k.userID == userID && (entityType == "" || k.entityType == entityType) {
+			out = append(out, &Watch{EntityType: k.entityType, EntityID: k.entityID})
+		}
+	}
+	return out, len(out), nil
+}
+
+func (f *fakeRepo) ListWatchers(_ context.Context, entityType, entityID string) ([]string, error) {
+	var out []string
+	for k := range f.watches {
+		if k.entityType == entityType && k.entityID == entityID {
+			out = append(out, k.userID)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) CountAssetWatchers(_ context.Context, assetIDs []string) (map[string]int, error) {
+	counts := map[string]int{}
+	for k := range f.watches {
+		for _, id := range assetIDs {
+			if k.entityType == EntityAsset && k.entityID == id {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(&fakeRepo{watches: map[watchKey]bool{}})
+
+	_, err := svc.Watch(ctx, "alice", EntityAsset, "orders")
+	require.NoError(t, err)
+	_, err = svc.Watch(ctx, "bob", EntityAsset, "orders")
+	require.NoError(t, err)
+	_, err = svc.Watch(ctx, "alice", EntityGlossaryTerm, "revenue")
+	require.NoError(t, err)
+
+	status, err := svc.Status(ctx, "carol", EntityAsset, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, &Status{Watching: false, WatcherCount: 2}, status)
+
+	result, err := svc.List(ctx, "alice", EntityGlossaryTerm, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+
+	counts, err := svc.CountWatchers(ctx, []string{"orders", "customers"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"orders": 2}, counts)
+
+	watchers, err := svc.Watchers(ctx, EntityGlossaryTerm, "revenue")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, watchers)
+
+	require.NoError(t, svc.Unwatch(ctx, "bob", EntityAsset, "orders"))
+	assert.ErrorIs(t, svc.Unwatch(ctx, "bob", EntityAsset, "orders"), ErrNotFound)
+}
+
+func TestService_InvalidEntityType(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(&fakeRepo{watches: map[watchKey]bool{}})
+
+	_, err := svc.Watch(ctx, "alice", "dashboard", "x")
+	assert.True(t, IsValidationError(err))
+	_, err = svc.List(ctx, "alice", "dashboard", 50, 0)
+	assert.True(t, IsValidationError(err))
+
+	_, err = svc.List(ctx, "alice", "", 50, 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,212 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository defines the watch list data access interface.
+type Repository interface {
+	Add(ctx context.Context, userID, entityType, entityID string) error
+	Get(ctx context.Context, userID, entityType, entityID string) (*Watch, error)
+	Remove(ctx context.Context, userID, entityType, entityID string) error
+	Status(ctx context.Context, userID, entityType, entityID string) (*Status, error)
+	List(ctx context.Context, userID, entityType string, limit, offset int) ([]*Watch, int, error)
+	ListWatchers(ctx context.Context, entityType, entityID string) ([]string, error)
+	CountAssetWatchers(ctx context.Context, assetIDs []string) (map[string]int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// entityColumns maps each entity type to the watches column referencing it.
+var entityColumns = map[string]string{
+	EntityAsset:        "asset_id",
+	EntityDataProduct:  "data_product_id",
+	EntityGlossaryTerm: "glossary_term_id",
+}
+
+const selectWatch = `
+	SELECT
+		CASE
+			WHEN w.asset_id IS NOT NULL THEN 'asset'
+			WHEN w.data_product_id IS NOT NULL THEN 'data_product'
+			ELSE 'glossary_term'
+		END,
+		COALESCE(w.asset_id, w.data_product_id::text, w.glossary_term_id::text),
+		COALESCE(a.name, dp.name, gt.name, ''),
+		COALESCE(a.mrn, ''),
+		COALESCE(a.type, ''),
+		w.created_at
+	FROM watches w
+	LEFT JOIN assets a ON a.id = w.asset_id
+	LEFT JOIN data_products dp ON dp.id = w.data_product_id
+	LEFT JOIN glossary_terms gt ON gt.id = w.glossary_term_id`
+
+func scanWatch(row pgx.Row) (*Watch, error) {
+	var w Watch
+	if err := row.Scan(&w.EntityType, &w.EntityID, &w.Name, &w.MRN, &w.AssetType, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// isMissingEntity reports whether err means the watched entity doesn't
+// exist: a foreign key violation, or an ID that isn't a valid UUID.
+func isMissingEntity(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02")
+}
+
+func (r *PostgresRepository) Add(ctx context.Context, userID, entityType, entityID string) error {
+	column := entityColumns[entityType]
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO watches (user_id, %[1]s)
+		VALUES ($1, $2)
+		ON CONFLICT (%[1]s, user_id) WHERE %[1]s IS NOT NULL DO NOTHING`, column),
+		userID, entityID)
+	if err != nil {
+		if isMissingEntity(err) {
+			return ErrEntityNotFound
+		}
+		return fmt.Errorf("adding watch: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, entityType, entityID string) (*Watch, error) {
+	w, err := scanWatch(r.db.QueryRow(ctx, selectWatch+fmt.Sprintf(`
+		WHERE w.user_id = $1 AND w.%s = $2`, entityColumns[entityType]), userID, entityID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isMissingEntity(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting watch: %w", err)
+	}
+	return w, nil
+}
+
+func (r *PostgresRepository) Remove(ctx context.Context, userID, entityType, entityID string) error {
+	tag, err := r.db.Exec(ctx, fmt.Sprintf(`
+		DELETE FROM watches WHERE user_id = $1 AND %s = $2`, entityColumns[entityType]),
+		userID, entityID)
+	if err != nil {
+		if isMissingEntity(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("removing watch: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Status(ctx context.Context, userID, entityType, entityID string) (*Status, error) {
+	var status Status
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COALESCE(bool_or(user_id = $1), FALSE), COUNT(*)
+		FROM watches WHERE %s = $2`, entityColumns[entityType]),
+		userID, entityID).Scan(&status.Watching, &status.WatcherCount)
+	if err != nil {
+		if isMissingEntity(err) {
+			return &Status{}, nil
+		}
+		return nil, fmt.Errorf("getting watch status: %w", err)
+	}
+	return &status, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID, entityType string, limit, offset int) ([]*Watch, int, error) {
+	where := `WHERE w.user_id = $1`
+	if entityType != "" {
+		where += fmt.Sprintf(` AND w.%s IS NOT NULL`, entityColumns[entityType])
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM watches w `+where, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting watches: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, selectWatch+`
+		`+where+`
+		ORDER BY w.created_at DESC, w.id
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing watches: %w", err)
+	}
+	defer rows.Close()
+
+	watches := []*Watch{}
+	for rows.Next() {
+		w, err := scanWatch(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning watch: %w", err)
+		}
+		watches = append(watches, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating watches: %w", err)
+	}
+	return watches, total, nil
+}
+
+func (r *PostgresRepository) ListWatchers(ctx context.Context, entityType, entityID string) ([]string, error) {
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT w.user_id
+		FROM watches w
+		JOIN users u ON u.id = w.user_id AND u.active
+		WHERE w.%s = $1`, entityColumns[entityType]), entityID)
+	if err != nil {
+		if isMissingEntity(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning watcher: %w", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+func (r *PostgresRepository) CountAssetWatchers(ctx context.Context, assetIDs []string) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT asset_id, COUNT(*)
+		FROM watches
+		WHERE asset_id = ANY($1)
+		GROUP BY asset_id`, assetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("counting watchers: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(assetIDs))
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scanning watcher count: %w", err)
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,338 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/marmotdata/marmot/internal/background"
+	"github.com/marmotdata/marmot/internal/worker"
+	"github.com/rs/zerolog/log"
+)
+
+// Headers sent with every event delivery.
+const (
+	// HeaderSignature carries "t=<unix seconds>,v1=<hex HMAC-SHA256>", the
+	// HMAC being of "<t>.<body>" keyed with the webhook's secret.
+	HeaderSignature = "X-Marmot-Signature"
+	HeaderEvent     = "X-Marmot-Event"
+	HeaderDelivery  = "X-Marmot-Delivery"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// EventDeliveryConfig configures how events are delivered.
+type EventDeliveryConfig struct {
+	// DB coordinates the retry loop across instances.
+	DB *pgxpool.Pool
+	// MaxWorkers is the number of concurrent deliveries. Default: 5.
+	MaxWorkers int
+	// QueueSize is the number of deliveries waiting for a worker. Deliveries
+	// that don't fit are left to the retry loop. Default: 500.
+	QueueSize int
+	// Timeout of each attempt. Default: 10 seconds.
+	Timeout time.Duration
+	// MaxAttempts before a delivery fails. Default: 6.
+	MaxAttempts int
+	// RetryDelay is the wait after the first failed attempt, quadrupling
+	// after each further one up to MaxRetryDelay. Default: 30 seconds.
+	RetryDelay time.Duration
+	// MaxRetryDelay caps the wait between attempts. Default: 2 hours.
+	MaxRetryDelay time.Duration
+	// RetryInterval is how often due retries are looked for. Default: 30
+	// seconds.
+	RetryInterval time.Duration
+	// Lease is how long a delivery being attempted is hidden from the retry
+	// loop. Default: 1 minute.
+	Lease time.Duration
+	// Retention is how long finished deliveries are kept. Default: 30 days.
+	Retention time.Duration
+	// HTTPClient sends deliveries. Default: an http.Client with Timeout.
+	HTTPClient HTTPClient
+}
+
+// RetryBackoff returns the wait after attempt failed.
+func (c EventDeliveryConfig) RetryBackoff(attempt int) time.Duration {
+	delay := c.RetryDelay
+	for i := 1; i < attempt && delay < c.MaxRetryDelay; i++ {
+		delay *= 4
+	}
+	return min(delay, c.MaxRetryDelay)
+}
+
+func (c EventDeliveryConfig) withDefaults() EventDeliveryConfig {
+	if c.MaxWorkers <= 0 {
+		c.MaxWorkers = 5
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 500
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 6
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = 30 * time.Second
+	}
+	if c.MaxRetryDelay <= 0 {
+		c.MaxRetryDelay = 2 * time.Hour
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = 30 * time.Second
+	}
+	if c.Lease <= 0 {
+		c.Lease = time.Minute
+	}
+	if c.Retention <= 0 {
+		c.Retention = 30 * 24 * time.Hour
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: c.Timeout}
+	}
+	return c
+}
+
+// Sign returns the HeaderSignature value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + signature(secret, timestamp, body)
+}
+
+// VerifySignature checks a HeaderSignature value against body, rejecting
+// signatures made more than tolerance before now, so captured deliveries
+// can't be replayed later.
+func VerifySignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverer attempts deliveries on a worker pool and retries failed ones
+// from the database.
+type deliverer struct {
+	svc     *EventService
+	config  EventDeliveryConfig
+	pool    *worker.Pool
+	retry   *background.SingletonTask
+	running atomic.Bool
+}
+
+func newDeliverer(svc *EventService, config EventDeliveryConfig) *deliverer {
+	config = config.withDefaults()
+	d := &deliverer{svc: svc, config: config}
+
+	d.pool = worker.NewPool(worker.PoolConfig{
+		Name:       "event-webhooks",
+		MaxWorkers: config.MaxWorkers,
+		QueueSize:  config.QueueSize,
+	})
+	d.retry = background.NewSingletonTask(background.SingletonConfig{
+		Name:         "event-webhook-retry",
+		DB:           config.DB,
+		Interval:     config.RetryInterval,
+		InitialDelay: config.RetryInterval,
+		TaskFn:       d.retryDue,
+	})
+	return d
+}
+
+func (d *deliverer) start(ctx context.Context) {
+	d.pool.Start(ctx)
+	d.retry.Start(ctx)
+	d.running.Store(true)
+}
+
+func (d *deliverer) stop() {
+	if !d.running.Swap(false) {
+		return
+	}
+	d.retry.Stop()
+	d.pool.Stop()
+}
+
+// submit queues an attempt. Attempts that can't be queued are picked up by
+// the retry loop once their lease expires.
+func (d *deliverer) submit(endpoint *EventWebhook, delivery *Delivery) {
+	if !d.running.Load() {
+		return
+	}
+	d.pool.Submit(&eventDeliveryJob{deliverer: d, endpoint: endpoint, delivery: delivery})
+}
+
+// retryDue queues the deliveries due for another attempt and prunes old
+// ones.
+func (d *deliverer) retryDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := d.svc.repo.ClaimDueDeliveries(ctx, now, now.Add(d.config.Lease), d.config.QueueSize)
+	if err != nil {
+		return err
+	}
+
+	endpoints := map[string]*EventWebhook{}
+	for _, delivery := range due {
+		endpoint, ok := endpoints[delivery.WebhookID]
+		if !ok {
+			endpoint, err = d.svc.endpoint(ctx, delivery.WebhookID)
+			if err != nil {
+				log.Warn().Err(err).Str("webhook_id", delivery.WebhookID).Msg("Failed to load event webhook for retry")
+				continue
+			}
+			endpoints[delivery.WebhookID] = endpoint
+		}
+
+		if !endpoint.Enabled && delivery.EventType != EventPing {
+			d.finish(ctx, delivery, 0, errors.New("webhook is disabled"), false)
+			continue
+		}
+		d.submit(endpoint, delivery)
+	}
+
+	pruned, err := d.svc.repo.PruneDeliveries(ctx, now.Add(-d.config.Retention))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to prune webhook deliveries")
+	} else if pruned > 0 {
+		log.Debug().Int64("deleted", pruned).Msg("Pruned webhook deliveries")
+	}
+	return nil
+}
+
+// attempt sends a delivery once and records the outcome.
+func (d *deliverer) attempt(ctx context.Context, endpoint *EventWebhook, delivery *Delivery) {
+	status, err := d.send(ctx, endpoint, delivery)
+
+	var nre *nonRetryableError
+	d.finish(ctx, delivery, status, err, err != nil && !errors.As(err, &nre))
+}
+
+// finish records the outcome of an attempt, scheduling another when the
+// failure is retryable and attempts remain.
+func (d *deliverer) finish(ctx context.Context, delivery *Delivery, status int, err error, retryable bool) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = DeliverySucceeded
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+		delivery.CompletedAt = &now
+	case retryable && delivery.Attempts < d.config.MaxAttempts:
+		msg := err.Error()
+		next := now.Add(d.config.RetryBackoff(delivery.Attempts))
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = &next
+	default:
+		msg := err.Error()
+		delivery.Status = DeliveryFailed
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = nil
+		delivery.CompletedAt = &now
+	}
+
+	if err := d.svc.repo.UpdateDelivery(ctx, delivery); err != nil {
+		log.Warn().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to record webhook delivery attempt")
+	}
+
+	if delivery.Status == DeliveryFailed {
+		log.Warn().
+			Str("webhook_id", delivery.WebhookID).
+			Str("delivery_id", delivery.ID).
+			Str("event", delivery.EventType).
+			Int("attempts", delivery.Attempts).
+			Msg("Webhook delivery failed")
+	}
+}
+
+// send posts a delivery, returning the response status when there was one.
+func (d *deliverer) send(ctx context.Context, endpoint *EventWebhook, delivery *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, &nonRetryableError{err: fmt.Errorf("creating request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Marmot-Webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, time.Now(), delivery.Payload))
+
+	resp, err := d.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return resp.StatusCode, &nonRetryableError{err: fmt.Errorf("HTTP %d: request rejected by endpoint", resp.StatusCode)}
+	}
+}
+
+// eventDeliveryJob implements worker.Job for one delivery attempt.
+type eventDeliveryJob struct {
+	deliverer *deliverer
+	endpoint  *EventWebhook
+	delivery  *Delivery
+}
+
+func (j *eventDeliveryJob) ID() string {
+	return "event-delivery:" + j.delivery.ID
+}
+
+func (j *eventDeliveryJob) Execute(ctx context.Context) error {
+	j.deliverer.attempt(ctx, j.endpoint, j.delivery)
+	return nil
+}
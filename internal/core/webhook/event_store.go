@@ -0,0 +1,284 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventRepository stores event webhooks and their deliveries.
+type EventRepository interface {
+	CreateEndpoint(ctx context.Context, webhook *EventWebhook) error
+	GetEndpoint(ctx context.Context, id string) (*EventWebhook, error)
+	ListEndpoints(ctx context.Context) ([]*EventWebhook, error)
+	UpdateEndpoint(ctx context.Context, id string, input UpdateEventWebhookInput) (*EventWebhook, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, filter DeliveryFilter) ([]*Delivery, int, error)
+	// UpdateDelivery records the outcome of an attempt.
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries whose next
+	// attempt is due, holding each until lease so they're only tried once.
+	ClaimDueDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]*Delivery, error)
+	// PruneDeliveries deletes finished deliveries created before cutoff.
+	PruneDeliveries(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type PostgresEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresEventRepository(db *pgxpool.Pool) EventRepository {
+	return &PostgresEventRepository{db: db}
+}
+
+const endpointColumns = `id, name, url, secret, events, enabled, COALESCE(created_by, ''), created_at, updated_at`
+
+func scanEndpoint(row pgx.Row) (*EventWebhook, error) {
+	var w EventWebhook
+	var eventsRaw []byte
+	if err := row.Scan(&w.ID, &w.Name, &w.URL, &w.Secret, &eventsRaw, &w.Enabled, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventsRaw, &w.Events); err != nil {
+		return nil, fmt.Errorf("unmarshaling events: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *PostgresEventRepository) CreateEndpoint(ctx context.Context, webhook *EventWebhook) error {
+	eventsJSON, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("marshaling events: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO event_webhooks (name, url, secret, events, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING id, created_at, updated_at`,
+		webhook.Name, webhook.URL, webhook.Secret, eventsJSON, webhook.Enabled, webhook.CreatedBy,
+	).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating event webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresEventRepository) GetEndpoint(ctx context.Context, id string) (*EventWebhook, error) {
+	w, err := scanEndpoint(r.db.QueryRow(ctx, `SELECT `+endpointColumns+` FROM event_webhooks WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting event webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *PostgresEventRepository) ListEndpoints(ctx context.Context) ([]*EventWebhook, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+endpointColumns+` FROM event_webhooks ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing event webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*EventWebhook{}
+	for rows.Next() {
+		w, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning event webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating event webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresEventRepository) UpdateEndpoint(ctx context.Context, id string, input UpdateEventWebhookInput) (*EventWebhook, error) {
+	setClauses := []string{}
+	args := []interface{}{}
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if input.Name != nil {
+		set("name", strings.TrimSpace(*input.Name))
+	}
+	if input.URL != nil {
+		set("url", *input.URL)
+	}
+	if input.Secret != nil {
+		set("secret", *input.Secret)
+	}
+	if input.Events != nil {
+		eventsJSON, err := json.Marshal(input.Events)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling events: %w", err)
+		}
+		set("events", eventsJSON)
+	}
+	if input.Enabled != nil {
+		set("enabled", *input.Enabled)
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetEndpoint(ctx, id)
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`UPDATE event_webhooks SET %s, updated_at = NOW() WHERE id = $%d RETURNING `+endpointColumns,
+		strings.Join(setClauses, ", "), len(args))
+
+	w, err := scanEndpoint(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("updating event webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *PostgresEventRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM event_webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting event webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, response_status, last_error, created_at, completed_at`
+
+func scanDelivery(row pgx.Row) (*Delivery, error) {
+	var d Delivery
+	if err := row.Scan(
+		&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.CompletedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func scanDeliveries(rows pgx.Rows) ([]*Delivery, error) {
+	defer rows.Close()
+
+	deliveries := []*Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresEventRepository) CreateDelivery(ctx context.Context, d *Delivery) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		d.WebhookID, d.EventID, d.EventType, d.Payload, d.Status, d.NextAttemptAt,
+	).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("creating webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresEventRepository) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	d, err := scanDelivery(r.db.QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("getting webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *PostgresEventRepository) ListDeliveries(ctx context.Context, webhookID string, filter DeliveryFilter) ([]*Delivery, int, error) {
+	where := `webhook_id = $1 AND ($2 = '' OR status = $2) AND ($3 = '' OR event_type = $3)`
+	args := []interface{}{webhookID, filter.Status, filter.EventType}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting webhook deliveries: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE `+where+`
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5`,
+		append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *PostgresEventRepository) UpdateDelivery(ctx context.Context, d *Delivery) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, response_status = $5,
+		    last_error = $6, completed_at = $7
+		WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, d.LastError, d.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("updating webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresEventRepository) ClaimDueDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]*Delivery, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		now, lease, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claiming webhook deliveries: %w", err)
+	}
+	return scanDeliveries(rows)
+}
+
+func (r *PostgresEventRepository) PruneDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("pruning webhook deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
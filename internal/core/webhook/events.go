@@ -0,0 +1,571 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marmotdata/marmot/internal/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// Catalog events admin webhooks can subscribe to.
+const (
+	EventAssetCreated   = "asset.created"
+	EventAssetUpdated   = "asset.updated"
+	EventAssetDeleted   = "asset.deleted"
+	EventLineageCreated = "lineage.created"
+	EventLineageDeleted = "lineage.deleted"
+	EventRunCompleted   = "run.completed"
+	// EventPing is only sent by EventService.Ping, to test a webhook.
+	EventPing = "ping"
+)
+
+// Events lists the events webhooks can subscribe to. A filter may also be
+// "*" for every event, or a prefix like "asset.*".
+var Events = []string{
+	EventAssetCreated,
+	EventAssetUpdated,
+	EventAssetDeleted,
+	EventLineageCreated,
+	EventLineageDeleted,
+	EventRunCompleted,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// endpointCacheTTL is how long Publish trusts its list of webhooks. Changes
+// made through this instance apply immediately.
+const endpointCacheTTL = 30 * time.Second
+
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// EventWebhook is an admin webhook that receives catalog events, signed with
+// its secret.
+type EventWebhook struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret is only returned when the webhook is created or its secret is
+	// changed.
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+} // @name EventWebhook
+
+// CreateEventWebhookInput is the input for creating an event webhook.
+type CreateEventWebhookInput struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret signs deliveries. One is generated when empty.
+	Secret  string   `json:"secret,omitempty"`
+	Events  []string `json:"events"`
+	Enabled *bool    `json:"enabled,omitempty"`
+} // @name CreateEventWebhookRequest
+
+// UpdateEventWebhookInput is the input for updating an event webhook.
+type UpdateEventWebhookInput struct {
+	Name   *string  `json:"name,omitempty"`
+	URL    *string  `json:"url,omitempty"`
+	Secret *string  `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+	// RotateSecret replaces the secret with a generated one.
+	RotateSecret bool  `json:"rotate_secret,omitempty"`
+	Enabled      *bool `json:"enabled,omitempty"`
+} // @name UpdateEventWebhookRequest
+
+// Event is the body of every delivery. Deliveries of the same event to
+// several webhooks, and redeliveries, share its ID.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+} // @name WebhookEvent
+
+// Delivery is one event sent, or being sent, to a webhook.
+type Delivery struct {
+	ID        string          `json:"id"`
+	WebhookID string          `json:"webhook_id"`
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Status    string          `json:"status" enums:"pending,succeeded,failed"`
+	Attempts  int             `json:"attempts"`
+	// NextAttemptAt is when a pending delivery is next tried.
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+} // @name WebhookDelivery
+
+// DeliveryFilter narrows a webhook's delivery history.
+type DeliveryFilter struct {
+	Status    string
+	EventType string
+	Limit     int
+	Offset    int
+}
+
+// EventService manages admin event webhooks and delivers events to them.
+type EventService struct {
+	repo      EventRepository
+	encryptor *crypto.Encryptor
+	deliverer *deliverer
+
+	mu        sync.RWMutex
+	endpoints []*EventWebhook
+	loadedAt  time.Time
+}
+
+// NewEventService creates an event webhook service. Start must be called
+// for events to be delivered.
+func NewEventService(repo EventRepository, encryptor *crypto.Encryptor, config EventDeliveryConfig) *EventService {
+	s := &EventService{
+		repo:      repo,
+		encryptor: encryptor,
+	}
+	s.deliverer = newDeliverer(s, config)
+	return s
+}
+
+// Start begins delivering events and retrying failed deliveries.
+func (s *EventService) Start(ctx context.Context) {
+	s.deliverer.start(ctx)
+}
+
+// Stop stops delivering events. Undelivered events are retried after the
+// next Start.
+func (s *EventService) Stop() {
+	s.deliverer.stop()
+}
+
+// Create registers a webhook. The returned webhook carries its secret.
+func (s *EventService) Create(ctx context.Context, input CreateEventWebhookInput, createdBy string) (*EventWebhook, error) {
+	if err := validateEventWebhook(input.Name, input.URL, input.Events); err != nil {
+		return nil, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	enabled := true
+	if input.Enabled != nil {
+		enabled = *input.Enabled
+	}
+
+	w := &EventWebhook{
+		Name:      strings.TrimSpace(input.Name),
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    normalizeEvents(input.Events),
+		Enabled:   enabled,
+		CreatedBy: createdBy,
+	}
+
+	stored := *w
+	var err error
+	if stored.URL, err = s.encrypt(w.URL); err != nil {
+		return nil, err
+	}
+	if stored.Secret, err = s.encrypt(w.Secret); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateEndpoint(ctx, &stored); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	w.ID, w.CreatedAt, w.UpdatedAt = stored.ID, stored.CreatedAt, stored.UpdatedAt
+	return w, nil
+}
+
+// Get returns a webhook without its secret.
+func (s *EventService) Get(ctx context.Context, id string) (*EventWebhook, error) {
+	w, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.decrypt(w)
+	w.Secret = ""
+	return w, nil
+}
+
+// List returns every webhook without their secrets.
+func (s *EventService) List(ctx context.Context) ([]*EventWebhook, error) {
+	webhooks, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range webhooks {
+		s.decrypt(w)
+		w.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// Update changes a webhook. The returned webhook carries its secret when it
+// was changed.
+func (s *EventService) Update(ctx context.Context, id string, input UpdateEventWebhookInput) (*EventWebhook, error) {
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return nil, &ValidationError{Message: "name cannot be empty"}
+	}
+	if input.URL != nil {
+		if err := validateEventWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+	}
+	if input.Events != nil {
+		if err := validateEvents(input.Events); err != nil {
+			return nil, err
+		}
+		input.Events = normalizeEvents(input.Events)
+	}
+	if input.RotateSecret {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		input.Secret = &generated
+	}
+	if input.Secret != nil && *input.Secret == "" {
+		return nil, &ValidationError{Message: "secret cannot be empty"}
+	}
+
+	var secret string
+	stored := input
+	if input.URL != nil {
+		url, err := s.encrypt(*input.URL)
+		if err != nil {
+			return nil, err
+		}
+		stored.URL = &url
+	}
+	if input.Secret != nil {
+		secret = *input.Secret
+		encrypted, err := s.encrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+		stored.Secret = &encrypted
+	}
+
+	w, err := s.repo.UpdateEndpoint(ctx, id, stored)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.decrypt(w)
+	w.Secret = secret
+	return w, nil
+}
+
+// Delete removes a webhook and its delivery history.
+func (s *EventService) Delete(ctx context.Context, id string) error {
+	if err := s.repo.DeleteEndpoint(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Publish sends an event to every enabled webhook subscribed to it. Each
+// delivery is recorded before it is attempted, so events aren't lost when
+// the endpoint is down or the server restarts.
+func (s *EventService) Publish(ctx context.Context, eventType string, data interface{}) {
+	endpoints, err := s.subscribers(ctx, eventType)
+	if err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("Failed to get webhooks for event")
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("Failed to marshal webhook event")
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if _, err := s.enqueue(ctx, endpoint, eventType, payload); err != nil {
+			log.Error().Err(err).
+				Str("webhook_id", endpoint.ID).
+				Str("event", eventType).
+				Msg("Failed to record webhook delivery")
+		}
+	}
+}
+
+// Ping sends a test event to a webhook, whether or not it is enabled.
+func (s *EventService) Ping(ctx context.Context, id string) (*Delivery, error) {
+	endpoint, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.decrypt(endpoint)
+
+	payload, err := json.Marshal(Event{
+		ID:         uuid.New().String(),
+		Type:       EventPing,
+		OccurredAt: time.Now().UTC(),
+		Data:       map[string]string{"webhook_id": endpoint.ID, "message": "This is a test event from Marmot."},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ping event: %w", err)
+	}
+	return s.enqueue(ctx, endpoint, EventPing, payload)
+}
+
+// ListDeliveries returns a webhook's deliveries, most recent first, and how
+// many match filter in total.
+func (s *EventService) ListDeliveries(ctx context.Context, webhookID string, filter DeliveryFilter) ([]*Delivery, int, error) {
+	if _, err := s.repo.GetEndpoint(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListDeliveries(ctx, webhookID, filter)
+}
+
+// GetDelivery returns one of a webhook's deliveries.
+func (s *EventService) GetDelivery(ctx context.Context, webhookID, deliveryID string) (*Delivery, error) {
+	d, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.WebhookID != webhookID {
+		return nil, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+// Redeliver sends the event of a previous delivery again, as a new delivery
+// with the same event ID.
+func (s *EventService) Redeliver(ctx context.Context, webhookID, deliveryID string) (*Delivery, error) {
+	previous, err := s.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.repo.GetEndpoint(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	s.decrypt(endpoint)
+
+	return s.enqueue(ctx, endpoint, previous.EventType, previous.Payload)
+}
+
+func (s *EventService) enqueue(ctx context.Context, endpoint *EventWebhook, eventType string, payload []byte) (*Delivery, error) {
+	var event struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("reading event ID: %w", err)
+	}
+
+	// The attempt made now holds the delivery for the lease, so the retry
+	// loop only picks it up if that attempt never happens.
+	next := time.Now().Add(s.deliverer.config.Lease)
+	d := &Delivery{
+		WebhookID:     endpoint.ID,
+		EventID:       event.ID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: &next,
+	}
+	if err := s.repo.CreateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+
+	s.deliverer.submit(endpoint, d)
+	return d, nil
+}
+
+// subscribers returns the enabled webhooks subscribed to eventType, with
+// their URL and secret decrypted.
+func (s *EventService) subscribers(ctx context.Context, eventType string) ([]*EventWebhook, error) {
+	s.mu.RLock()
+	endpoints, fresh := s.endpoints, time.Since(s.loadedAt) < endpointCacheTTL
+	s.mu.RUnlock()
+
+	if !fresh {
+		all, err := s.repo.ListEndpoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = make([]*EventWebhook, 0, len(all))
+		for _, w := range all {
+			if w.Enabled {
+				s.decrypt(w)
+				endpoints = append(endpoints, w)
+			}
+		}
+
+		s.mu.Lock()
+		s.endpoints, s.loadedAt = endpoints, time.Now()
+		s.mu.Unlock()
+	}
+
+	var matched []*EventWebhook
+	for _, w := range endpoints {
+		if MatchesEvent(w.Events, eventType) {
+			matched = append(matched, w)
+		}
+	}
+	return matched, nil
+}
+
+// endpoint returns a webhook with its URL and secret decrypted.
+func (s *EventService) endpoint(ctx context.Context, id string) (*EventWebhook, error) {
+	w, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.decrypt(w)
+	return w, nil
+}
+
+func (s *EventService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *EventService) encrypt(v string) (string, error) {
+	if s.encryptor == nil {
+		return v, nil
+	}
+	encrypted, err := s.encryptor.EncryptString(v)
+	if err != nil {
+		return "", fmt.Errorf("encrypting webhook: %w", err)
+	}
+	return encrypted, nil
+}
+
+func (s *EventService) decrypt(w *EventWebhook) {
+	if s.encryptor == nil {
+		return
+	}
+	for _, v := range []*string{&w.URL, &w.Secret} {
+		decrypted, err := s.encryptor.DecryptString(*v)
+		if err != nil {
+			log.Debug().Err(err).Str("webhook_id", w.ID).Msg("Could not decrypt event webhook, using as-is")
+			continue
+		}
+		*v = decrypted
+	}
+}
+
+// MatchesEvent reports whether a webhook with filters receives eventType.
+func MatchesEvent(filters []string, eventType string) bool {
+	for _, f := range filters {
+		if f == "*" || f == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(f, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateEventWebhook(name, url string, events []string) error {
+	if strings.TrimSpace(name) == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	if len(name) > 255 {
+		return &ValidationError{Message: "name must be 255 characters or less"}
+	}
+	if err := validateEventWebhookURL(url); err != nil {
+		return err
+	}
+	return validateEvents(events)
+}
+
+// validateEventWebhookURL applies the team webhook URL checks to the url
+// field.
+func validateEventWebhookURL(url string) error {
+	if strings.TrimSpace(url) == "" {
+		return &ValidationError{Message: "url is required"}
+	}
+	if err := validateWebhookURL(url); err != nil {
+		return &ValidationError{Message: strings.Replace(err.Message, "webhook_url", "url", 1)}
+	}
+	return nil
+}
+
+func validateEvents(events []string) error {
+	if len(events) == 0 {
+		return &ValidationError{Message: "at least one event is required"}
+	}
+	for _, e := range events {
+		if e == "*" || slices.Contains(Events, e) {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(e, "*"); ok && prefix != "" {
+			if slices.ContainsFunc(Events, func(known string) bool { return strings.HasPrefix(known, prefix) }) {
+				continue
+			}
+		}
+		return &ValidationError{Message: fmt.Sprintf("unknown event %q, must be one of: %s", e, strings.Join(Events, ", "))}
+	}
+	return nil
+}
+
+func normalizeEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
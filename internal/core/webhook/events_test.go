@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventRepo struct {
+	EventRepository
+	updated []Delivery
+}
+
+func (r *fakeEventRepo) UpdateDelivery(_ context.Context, d *Delivery) error {
+	r.updated = append(r.updated, *d)
+	return nil
+}
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"id":"1","type":"asset.created"}`)
+	sentAt := time.Unix(1700000000, 0)
+	header := Sign("whsec_test", sentAt, body)
+
+	assert.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, VerifySignature("whsec_test", header, body, sentAt.Add(time.Minute), 5*time.Minute))
+
+	assert.ErrorIs(t, VerifySignature("other", header, body, sentAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("whsec_test", header, []byte(`{}`), sentAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("whsec_test", header, body, sentAt.Add(time.Hour), 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("whsec_test", "v1=abc", body, sentAt, 0), ErrInvalidSignature)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	config := EventDeliveryConfig{}.withDefaults()
+
+	assert.Equal(t, 30*time.Second, config.RetryBackoff(1))
+	assert.Equal(t, 2*time.Minute, config.RetryBackoff(2))
+	assert.Equal(t, 8*time.Minute, config.RetryBackoff(3))
+	assert.Equal(t, 2*time.Hour, config.RetryBackoff(10))
+}
+
+func TestMatchesEvent(t *testing.T) {
+	assert.True(t, MatchesEvent([]string{"*"}, EventRunCompleted))
+	assert.True(t, MatchesEvent([]string{"asset.*"}, EventAssetDeleted))
+	assert.True(t, MatchesEvent([]string{EventLineageCreated}, EventLineageCreated))
+	assert.False(t, MatchesEvent([]string{"asset.*", EventLineageCreated}, EventLineageDeleted))
+}
+
+func TestValidateEvents(t *testing.T) {
+	assert.NoError(t, validateEvents([]string{"*"}))
+	assert.NoError(t, validateEvents([]string{"asset.*", EventRunCompleted}))
+
+	for _, events := range [][]string{nil, {"asset.renamed"}, {"dashboard.*"}, {EventPing}} {
+		err := validateEvents(events)
+		assert.Error(t, err, events)
+		assert.True(t, IsValidationError(err))
+	}
+}
+
+func TestDeliverer_Attempt(t *testing.T) {
+	var status int
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	repo := &fakeEventRepo{}
+	svc := NewEventService(repo, nil, EventDeliveryConfig{MaxAttempts: 2})
+	endpoint := &EventWebhook{ID: "w1", URL: server.URL, Secret: "whsec_test", Enabled: true}
+	payload := []byte(`{"id":"e1","type":"asset.created","data":{}}`)
+	ctx := context.Background()
+
+	t.Run("signed and succeeded", func(t *testing.T) {
+		status = http.StatusNoContent
+		delivery := &Delivery{ID: "d1", WebhookID: "w1", EventType: EventAssetCreated, Payload: payload, Status: DeliveryPending}
+		svc.deliverer.attempt(ctx, endpoint, delivery)
+
+		assert.Equal(t, EventAssetCreated, got.Header.Get(HeaderEvent))
+		assert.Equal(t, "d1", got.Header.Get(HeaderDelivery))
+		assert.NoError(t, VerifySignature("whsec_test", got.Header.Get(HeaderSignature), body, time.Now(), time.Minute))
+
+		assert.Equal(t, DeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		require.NotNil(t, delivery.ResponseStatus)
+		assert.Equal(t, http.StatusNoContent, *delivery.ResponseStatus)
+		assert.NotNil(t, delivery.CompletedAt)
+	})
+
+	t.Run("server errors are retried until attempts run out", func(t *testing.T) {
+		status = http.StatusBadGateway
+		delivery := &Delivery{ID: "d2", WebhookID: "w1", EventType: EventAssetCreated, Payload: payload, Status: DeliveryPending}
+
+		svc.deliverer.attempt(ctx, endpoint, delivery)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		require.NotNil(t, delivery.NextAttemptAt)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), *delivery.NextAttemptAt, 5*time.Second)
+		require.NotNil(t, delivery.LastError)
+		assert.Contains(t, *delivery.LastError, "502")
+
+		svc.deliverer.attempt(ctx, endpoint, delivery)
+		assert.Equal(t, DeliveryFailed, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Nil(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("client errors fail immediately", func(t *testing.T) {
+		status = http.StatusGone
+		delivery := &Delivery{ID: "d3", WebhookID: "w1", EventType: EventAssetCreated, Payload: payload, Status: DeliveryPending}
+		svc.deliverer.attempt(ctx, endpoint, delivery)
+
+		assert.Equal(t, DeliveryFailed, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+	})
+
+	assert.Len(t, repo.updated, 4)
+}
@@ -19,6 +19,11 @@ const (
 	ProviderGeneric = "generic"
 )
 
+// EventCatalogDelta is sent after each ingestion run that changed the
+// catalog. Unlike notification types it isn't addressed to a team, so every
+// team's webhooks that subscribe to it receive it.
+const EventCatalogDelta = "catalog_delta"
+
 var (
 	ErrNotFound = errors.New("webhook not found")
 
@@ -230,6 +235,28 @@ func (s *Service) DispatchToTeam(ctx context.Context, teamID, notificationType,
 	}
 }
 
+// DispatchEvent sends a catalog-wide event to every enabled webhook that
+// subscribes to eventType.
+func (s *Service) DispatchEvent(ctx context.Context, eventType, title, message string, data map[string]interface{}) {
+	webhooks, err := s.repo.GetEnabledForEventType(ctx, eventType)
+	if err != nil {
+		log.Error().Err(err).Str("type", eventType).Msg("Failed to get webhooks for event")
+		return
+	}
+
+	notification := WebhookNotification{
+		Type:    eventType,
+		Title:   title,
+		Message: message,
+		Data:    data,
+	}
+
+	for _, webhook := range webhooks {
+		s.decryptURL(webhook)
+		s.dispatcher.Dispatch(webhook, notification)
+	}
+}
+
 func (s *Service) decryptURL(webhook *Webhook) {
 	if s.encryptor == nil {
 		return
@@ -19,6 +19,9 @@ type Repository interface {
 	Delete(ctx context.Context, id string) error
 	ListByTeam(ctx context.Context, teamID string) ([]*Webhook, error)
 	GetEnabledForNotificationType(ctx context.Context, teamID string, notificationType string) ([]*Webhook, error)
+	// GetEnabledForEventType returns every team's enabled webhooks that
+	// subscribe to a catalog-wide event.
+	GetEnabledForEventType(ctx context.Context, eventType string) ([]*Webhook, error)
 	UpdateLastTriggered(ctx context.Context, id string, lastError *string) error
 }
 
@@ -218,6 +221,28 @@ func (r *PostgresRepository) GetEnabledForNotificationType(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("querying enabled webhooks: %w", err)
 	}
+	return scanWebhooks(rows)
+}
+
+func (r *PostgresRepository) GetEnabledForEventType(ctx context.Context, eventType string) ([]*Webhook, error) {
+	typeFilter, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling type filter: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, team_id, name, provider, webhook_url, notification_types, enabled,
+		       last_triggered_at, last_error, created_at, updated_at
+		FROM team_webhooks
+		WHERE enabled = TRUE AND notification_types @> $1::jsonb`,
+		typeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("querying enabled webhooks: %w", err)
+	}
+	return scanWebhooks(rows)
+}
+
+func scanWebhooks(rows pgx.Rows) ([]*Webhook, error) {
 	defer rows.Close()
 
 	webhooks := []*Webhook{}
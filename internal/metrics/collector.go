@@ -40,6 +40,11 @@ type Collector struct {
 
 	assets *prometheus.GaugeVec
 
+	// Run checkpoint compaction metrics
+	checkpointsCompacted         prometheus.Counter
+	checkpoints                  prometheus.Gauge
+	checkpointCompactionDuration prometheus.Histogram
+
 	// Async metric recording
 	metricQueue chan Metric
 	stopCh      chan struct{}
@@ -121,6 +126,22 @@ func NewCollector(store Store) *Collector {
 		Help: "Number of assets by various dimensions",
 	}, []string{"type", "provider", "has_schema", "owner"})
 
+	c.checkpointsCompacted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "marmot_run_checkpoints_compacted_total",
+		Help: "Total number of run checkpoints removed by compaction",
+	})
+
+	c.checkpoints = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "marmot_run_checkpoints",
+		Help: "Number of run checkpoints retained after the last compaction",
+	})
+
+	c.checkpointCompactionDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "marmot_run_checkpoint_compaction_duration_seconds",
+		Help:    "Run checkpoint compaction duration in seconds",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	})
+
 	return c
 }
 
@@ -223,6 +244,12 @@ func (c *Collector) RecordDBQuery(operation string, duration time.Duration, succ
 	c.dbQueries.WithLabelValues(operation, status).Inc()
 }
 
+func (c *Collector) RecordCheckpointCompaction(deleted, retained int64, duration time.Duration) {
+	c.checkpointsCompacted.Add(float64(deleted))
+	c.checkpoints.Set(float64(retained))
+	c.checkpointCompactionDuration.Observe(duration.Seconds())
+}
+
 func (c *Collector) SetDBConnections(count int) {
 	c.dbConnections.Set(float64(count))
 }
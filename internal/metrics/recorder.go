@@ -11,6 +11,7 @@ type Recorder interface {
 	RecordDBQuery(ctx context.Context, operation string, duration time.Duration, success bool)
 	WrapDBQuery(ctx context.Context, operation string, fn func() error) error
 	RecordCustomMetrics(ctx context.Context, metrics []Metric) error
+	RecordCheckpointCompaction(ctx context.Context, deleted, retained int64, duration time.Duration)
 }
 
 type recorder struct {
@@ -42,6 +43,10 @@ func (r *recorder) WrapDBQuery(ctx context.Context, operation string, fn func()
 	return err
 }
 
+func (r *recorder) RecordCheckpointCompaction(ctx context.Context, deleted, retained int64, duration time.Duration) {
+	r.collector.RecordCheckpointCompaction(deleted, retained, duration)
+}
+
 func (r *recorder) RecordCustomMetrics(ctx context.Context, metrics []Metric) error {
 	return r.collector.store.RecordMetrics(ctx, metrics)
 }
@@ -102,6 +102,7 @@ func registerExternalPlugin(path string) (bool, error) {
 		source = &ExternalDataFetcherSource{ExternalSource{path: path}}
 	}
 
+	sdkMeta.ConfigSpec = withFilterRulesSpec(sdkMeta.ConfigSpec)
 	if err := GetRegistry().Register(*sdkMeta, source); err != nil {
 		// Lost a race with a concurrent loader; the first registration
 		// wins, same as the check above.
@@ -119,6 +120,13 @@ type ExternalSource struct {
 }
 
 func (s *ExternalSource) Validate(config RawPluginConfig) (RawPluginConfig, error) {
+	if _, err := parseFilterRules(config); err != nil {
+		return nil, err
+	}
+	if _, err := parseTTLRules(config); err != nil {
+		return nil, err
+	}
+
 	process, err := pluginsdk.Open(s.path, pluginLogger())
 	if err != nil {
 		return nil, err
@@ -129,7 +137,7 @@ func (s *ExternalSource) Validate(config RawPluginConfig) (RawPluginConfig, erro
 	if err != nil {
 		return nil, err
 	}
-	return RawPluginConfig(validated), nil
+	return carryFilterRules(RawPluginConfig(validated), config), nil
 }
 
 func (s *ExternalSource) Discover(ctx context.Context, config RawPluginConfig) (*DiscoveryResult, error) {
@@ -1,39 +1,260 @@
 package plugin
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/marmotdata/marmot/internal/core/asset"
 	"github.com/marmotdata/marmot/internal/core/assetdocs"
 	"github.com/marmotdata/marmot/internal/core/lineage"
 	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/rs/zerolog/log"
+)
+
+// FiltersConfigKey is the plugin config key holding shared filter rules.
+// It sits beside the SDK's name-only "filter" so every plugin gets the same
+// scoping without each one reimplementing it.
+const FiltersConfigKey = "filters"
+
+// Filter rule fields and pattern syntaxes.
+const (
+	FilterFieldName     = "name"
+	FilterFieldMRN      = "mrn"
+	FilterFieldTag      = "tag"
+	FilterFieldMetadata = "metadata."
+
+	FilterSyntaxRegex = "regex"
+	FilterSyntaxGlob  = "glob"
 )
 
-// FilterDiscoveryResult filters a DiscoveryResult based on the Filter in the config.
-// It filters assets by name, then removes lineage, documentation, statistics, and
-// run history entries that reference excluded assets.
+// FilterRule includes or excludes assets by matching one field against a
+// list of patterns. Level restricts the rule to one asset type, so a
+// source can scope schemas and tables independently:
+//
+//	filters:
+//	  - field: metadata.schema
+//	    include: ["^analytics$"]
+//	  - level: Table
+//	    syntax: glob
+//	    exclude: ["tmp_*"]
+type FilterRule struct {
+	Level   string   `json:"level,omitempty" yaml:"level,omitempty"`
+	Field   string   `json:"field,omitempty" yaml:"field,omitempty"`
+	Syntax  string   `json:"syntax,omitempty" yaml:"syntax,omitempty"`
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+type filtersConfig struct {
+	Filters []FilterRule `json:"filters"`
+}
+
+type compiledRule struct {
+	level   string
+	field   string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// FilterRulesSpec documents the shared filter rules in a plugin's config
+// spec. The registry appends it to every plugin that does not declare its
+// own "filters" field.
+var FilterRulesSpec = pluginsdk.ConfigField{
+	Name:        FiltersConfigKey,
+	Label:       "Filters",
+	Type:        pluginsdk.FieldTypeObject,
+	IsArray:     true,
+	Description: "Include/exclude rules applied to every discovered asset. An asset is kept only if it passes every rule that applies to it.",
+	Fields: []pluginsdk.ConfigField{
+		{Name: "level", Label: "Level", Type: pluginsdk.FieldTypeString, Description: "Asset type the rule applies to, e.g. Schema or Table. Empty applies to all assets."},
+		{Name: "field", Label: "Field", Type: pluginsdk.FieldTypeString, Default: FilterFieldName, Description: "What to match: name, mrn, tag, or metadata.<key>. Metadata rules skip assets without the key."},
+		{Name: "syntax", Label: "Syntax", Type: pluginsdk.FieldTypeSelect, Default: FilterSyntaxRegex, Description: "Pattern syntax", Options: []pluginsdk.FieldOption{
+			{Label: "Regular expression", Value: FilterSyntaxRegex},
+			{Label: "Glob", Value: FilterSyntaxGlob},
+		}},
+		{Name: "include", Label: "Include", Type: pluginsdk.FieldTypeString, IsArray: true, Description: "Keep only values matching at least one pattern"},
+		{Name: "exclude", Label: "Exclude", Type: pluginsdk.FieldTypeString, IsArray: true, Description: "Drop values matching any pattern"},
+	},
+}
+
+// parseFilterRules reads and compiles the shared filter rules from a plugin
+// config. It returns an error for unknown fields or syntaxes and for
+// patterns that do not compile, so bad rules fail validation rather than
+// silently matching nothing.
+func parseFilterRules(rawConfig RawPluginConfig) ([]compiledRule, error) {
+	if _, ok := rawConfig[FiltersConfigKey]; !ok {
+		return nil, nil
+	}
+	cfg, err := UnmarshalPluginConfig[filtersConfig](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parsing filters: %w", err)
+	}
+
+	rules := make([]compiledRule, 0, len(cfg.Filters))
+	for i, r := range cfg.Filters {
+		rule, err := compileRule(r.Level, r.Field, r.Syntax, r.Include, r.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("filters[%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// compileRule validates a rule's field and syntax and compiles its
+// patterns.
+func compileRule(level, field, syntax string, includePatterns, excludePatterns []string) (compiledRule, error) {
+	if field == "" {
+		field = FilterFieldName
+	}
+	switch {
+	case field == FilterFieldName, field == FilterFieldMRN, field == FilterFieldTag:
+	case strings.HasPrefix(field, FilterFieldMetadata) && len(field) > len(FilterFieldMetadata):
+	default:
+		return compiledRule{}, fmt.Errorf("unknown field %q", field)
+	}
+
+	if syntax == "" {
+		syntax = FilterSyntaxRegex
+	}
+	if syntax != FilterSyntaxRegex && syntax != FilterSyntaxGlob {
+		return compiledRule{}, fmt.Errorf("unknown syntax %q", syntax)
+	}
+
+	include, err := compilePatterns(includePatterns, syntax)
+	if err != nil {
+		return compiledRule{}, err
+	}
+	exclude, err := compilePatterns(excludePatterns, syntax)
+	if err != nil {
+		return compiledRule{}, err
+	}
+	return compiledRule{
+		level:   strings.ToLower(level),
+		field:   field,
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+func compilePatterns(patterns []string, syntax string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		expr := p
+		if syntax == FilterSyntaxGlob {
+			expr = globToRegexp(p)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// globToRegexp translates * and ? wildcards into an anchored expression.
+// Unlike path.Match, * also crosses dots and slashes, which suits
+// qualified names like db.schema.table and object prefixes.
+func globToRegexp(glob string) string {
+	quoted := regexp.QuoteMeta(glob)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return "^" + quoted + "$"
+}
+
+// includes reports whether the asset passes the rule. Rules for another
+// level, and metadata rules for assets without the key, always pass.
+func (r compiledRule) includes(a asset.Asset) bool {
+	if r.level != "" && r.level != strings.ToLower(a.Type) {
+		return true
+	}
+
+	values, ok := fieldValues(a, r.field)
+	if !ok {
+		return true
+	}
+
+	for _, v := range values {
+		if matchesAny(v, r.exclude) {
+			return false
+		}
+	}
+	if len(r.include) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if matchesAny(v, r.include) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues returns the values of a rule field for an asset. ok is false
+// for metadata fields the asset does not have.
+func fieldValues(a asset.Asset, field string) (values []string, ok bool) {
+	switch {
+	case field == FilterFieldName:
+		if a.Name != nil {
+			return []string{*a.Name}, true
+		}
+		return []string{""}, true
+	case field == FilterFieldMRN:
+		if a.MRN != nil {
+			return []string{*a.MRN}, true
+		}
+		return []string{""}, true
+	case field == FilterFieldTag:
+		return a.Tags, true
+	default:
+		v, ok := a.Metadata[strings.TrimPrefix(field, FilterFieldMetadata)]
+		if !ok || v == nil {
+			return nil, false
+		}
+		return []string{fmt.Sprint(v)}, true
+	}
+}
+
+func matchesAny(value string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterDiscoveryResult filters a DiscoveryResult based on the Filter and
+// filter rules in the config. It filters assets first, then removes lineage,
+// documentation, statistics, and run history entries that reference
+// excluded assets. Rules that fail to parse are skipped here; Validate
+// rejects them before a run starts.
 func FilterDiscoveryResult(result *DiscoveryResult, rawConfig RawPluginConfig) {
 	if result == nil {
 		return
 	}
 
-	base, err := UnmarshalPluginConfig[BaseConfig](rawConfig)
-	if err != nil || base.Filter == nil {
-		return
+	var filter *Filter
+	if base, err := UnmarshalPluginConfig[BaseConfig](rawConfig); err == nil && base.Filter != nil &&
+		(len(base.Filter.Include) > 0 || len(base.Filter.Exclude) > 0) {
+		filter = base.Filter
 	}
-
-	filter := *base.Filter
-	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+	rules, err := parseFilterRules(rawConfig)
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring invalid filter rules")
+		rules = nil
+	}
+	if filter == nil && len(rules) == 0 {
 		return
 	}
 
-	// Filter assets by name and collect included MRNs
+	// Filter assets and collect included MRNs
 	includedMRNs := make(map[string]struct{})
 	filteredAssets := make([]asset.Asset, 0, len(result.Assets))
 	for _, a := range result.Assets {
-		name := ""
-		if a.Name != nil {
-			name = *a.Name
-		}
-		if pluginsdk.ShouldIncludeResource(name, filter) {
+		if includeAsset(a, filter, rules) {
 			filteredAssets = append(filteredAssets, a)
 			if a.MRN != nil {
 				includedMRNs[*a.MRN] = struct{}{}
@@ -80,3 +301,58 @@ func FilterDiscoveryResult(result *DiscoveryResult, rawConfig RawPluginConfig) {
 	}
 	result.RunHistory = filteredHistory
 }
+
+func includeAsset(a asset.Asset, filter *Filter, rules []compiledRule) bool {
+	if filter != nil {
+		name := ""
+		if a.Name != nil {
+			name = *a.Name
+		}
+		if !pluginsdk.ShouldIncludeResource(name, *filter) {
+			return false
+		}
+	}
+	for _, r := range rules {
+		if !r.includes(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// withFilterRulesSpec appends FilterRulesSpec and TTLRulesSpec to a
+// plugin's config spec unless the plugin already declares the keys.
+func withFilterRulesSpec(spec []pluginsdk.ConfigField) []pluginsdk.ConfigField {
+	for _, hostField := range []pluginsdk.ConfigField{FilterRulesSpec, TTLRulesSpec} {
+		declared := false
+		for _, f := range spec {
+			if f.Name == hostField.Name {
+				declared = true
+				break
+			}
+		}
+		if !declared {
+			spec = append(spec, hostField)
+		}
+	}
+	return spec
+}
+
+// carryFilterRules copies the filter and TTL rules from the submitted config
+// into the one a plugin returns from Validate. Plugins only echo the fields
+// they know about, and the rules are applied by the host after discovery.
+func carryFilterRules(validated, submitted RawPluginConfig) RawPluginConfig {
+	for _, key := range []string{FiltersConfigKey, TTLConfigKey} {
+		rules, ok := submitted[key]
+		if !ok {
+			continue
+		}
+		if validated == nil {
+			validated = RawPluginConfig{}
+		}
+		if _, exists := validated[key]; !exists {
+			validated[key] = rules
+		}
+	}
+	return validated
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/marmotdata/marmot/internal/core/lineage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAsset(typ, name string, tags []string, metadata map[string]interface{}) asset.Asset {
+	mrn := "mrn://" + typ + "/postgresql/" + name
+	return asset.Asset{Name: &name, MRN: &mrn, Type: typ, Tags: tags, Metadata: metadata}
+}
+
+func assetNames(assets []asset.Asset) []string {
+	names := make([]string, 0, len(assets))
+	for _, a := range assets {
+		names = append(names, *a.Name)
+	}
+	return names
+}
+
+func TestFilterDiscoveryResult_Rules(t *testing.T) {
+	newResult := func() *DiscoveryResult {
+		return &DiscoveryResult{
+			Assets: []asset.Asset{
+				testAsset("Database", "shop", nil, nil),
+				testAsset("Table", "orders", []string{"core"}, map[string]interface{}{"schema": "analytics"}),
+				testAsset("Table", "tmp_orders", nil, map[string]interface{}{"schema": "analytics"}),
+				testAsset("Table", "users", []string{"deprecated"}, map[string]interface{}{"schema": "analytics"}),
+				testAsset("Table", "audit", nil, map[string]interface{}{"schema": "internal"}),
+			},
+			Lineage: []lineage.LineageEdge{
+				{Source: "mrn://Database/postgresql/shop", Target: "mrn://Table/postgresql/orders", Type: "CONTAINS"},
+				{Source: "mrn://Database/postgresql/shop", Target: "mrn://Table/postgresql/audit", Type: "CONTAINS"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filters []interface{}
+		want    []string
+	}{
+		{
+			name: "metadata rule skips assets without the key",
+			filters: []interface{}{
+				map[string]interface{}{"field": "metadata.schema", "include": []interface{}{"^analytics$"}},
+			},
+			want: []string{"shop", "orders", "tmp_orders", "users"},
+		},
+		{
+			name: "glob exclude scoped to a level",
+			filters: []interface{}{
+				map[string]interface{}{"level": "table", "syntax": "glob", "exclude": []interface{}{"tmp_*"}},
+			},
+			want: []string{"shop", "orders", "users", "audit"},
+		},
+		{
+			name: "tag exclude",
+			filters: []interface{}{
+				map[string]interface{}{"field": "tag", "exclude": []interface{}{"deprecated"}},
+			},
+			want: []string{"shop", "orders", "tmp_orders", "audit"},
+		},
+		{
+			name: "tag include drops untagged assets",
+			filters: []interface{}{
+				map[string]interface{}{"level": "Table", "field": "tag", "include": []interface{}{"core"}},
+			},
+			want: []string{"shop", "orders"},
+		},
+		{
+			name: "rules combine",
+			filters: []interface{}{
+				map[string]interface{}{"field": "metadata.schema", "include": []interface{}{"analytics"}},
+				map[string]interface{}{"level": "Table", "exclude": []interface{}{"^tmp_", "^users$"}},
+			},
+			want: []string{"shop", "orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newResult()
+			FilterDiscoveryResult(result, RawPluginConfig{"filters": tt.filters})
+			assert.Equal(t, tt.want, assetNames(result.Assets))
+		})
+	}
+
+	t.Run("lineage to dropped assets is removed", func(t *testing.T) {
+		result := newResult()
+		FilterDiscoveryResult(result, RawPluginConfig{"filters": []interface{}{
+			map[string]interface{}{"field": "metadata.schema", "exclude": []interface{}{"internal"}},
+		}})
+		require.Len(t, result.Lineage, 1)
+		assert.Equal(t, "mrn://Table/postgresql/orders", result.Lineage[0].Target)
+	})
+
+	t.Run("legacy filter still applies", func(t *testing.T) {
+		result := newResult()
+		FilterDiscoveryResult(result, RawPluginConfig{
+			"filter": map[string]interface{}{"exclude": []interface{}{"^audit$"}},
+			"filters": []interface{}{
+				map[string]interface{}{"level": "Table", "syntax": "glob", "exclude": []interface{}{"tmp_*"}},
+			},
+		})
+		assert.Equal(t, []string{"shop", "orders", "users"}, assetNames(result.Assets))
+	})
+}
+
+func TestParseFilterRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    map[string]interface{}
+		wantErr string
+	}{
+		{"unknown field", map[string]interface{}{"field": "owner"}, "unknown field"},
+		{"empty metadata key", map[string]interface{}{"field": "metadata."}, "unknown field"},
+		{"unknown syntax", map[string]interface{}{"syntax": "sql"}, "unknown syntax"},
+		{"bad regex", map[string]interface{}{"include": []interface{}{"("}}, "invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFilterRules(RawPluginConfig{"filters": []interface{}{tt.rule}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	rules, err := parseFilterRules(RawPluginConfig{"filters": []interface{}{
+		map[string]interface{}{"syntax": "glob", "include": []interface{}{"analytics.*", "ord?rs"}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	assert.True(t, matchesAny("analytics.orders.daily", rules[0].include))
+	assert.True(t, matchesAny("orders", rules[0].include))
+	assert.False(t, matchesAny("raw.analytics.orders", rules[0].include))
+	assert.False(t, matchesAny("analytics", rules[0].include))
+}
+
+func TestCarryFilterRules(t *testing.T) {
+	rules := []interface{}{map[string]interface{}{"exclude": []interface{}{"tmp"}}}
+	got := carryFilterRules(RawPluginConfig{"host": "db"}, RawPluginConfig{"host": "db", "filters": rules})
+	assert.Equal(t, rules, got["filters"])
+
+	spec := withFilterRulesSpec(nil)
+	require.Len(t, spec, 2)
+	assert.Len(t, withFilterRulesSpec(spec), 2)
+}
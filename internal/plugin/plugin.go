@@ -109,8 +109,64 @@ type RunSummary struct {
 	ErrorsCount        int `json:"errors_count"`
 	TotalEntities      int `json:"total_entities"`
 	DurationSeconds    int `json:"duration_seconds"`
+	// Entities breaks the counts down by entity type and status.
+	Entities EntitySummary `json:"entities,omitempty"`
 } // @name RunSummary
 
+// EntityCounts counts the entities of one type processed by a run.
+type EntityCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+	Failed    int `json:"failed"`
+} // @name EntityCounts
+
+// EntitySummary maps entity types ('asset', 'lineage', 'documentation') to
+// their counts.
+type EntitySummary map[string]*EntityCounts // @name EntitySummary
+
+// Add counts one entity of entityType with the given status. Unknown
+// statuses are ignored.
+func (s EntitySummary) Add(entityType, status string) {
+	c, ok := s[entityType]
+	if !ok {
+		c = &EntityCounts{}
+		s[entityType] = c
+	}
+	switch status {
+	case "created":
+		c.Created++
+	case "updated":
+		c.Updated++
+	case "unchanged":
+		c.Unchanged++
+	case "deleted":
+		c.Deleted++
+	case "failed":
+		c.Failed++
+	}
+}
+
+// Merge adds the counts in other to s.
+func (s EntitySummary) Merge(other EntitySummary) {
+	for entityType, o := range other {
+		if o == nil {
+			continue
+		}
+		c, ok := s[entityType]
+		if !ok {
+			c = &EntityCounts{}
+			s[entityType] = c
+		}
+		c.Created += o.Created
+		c.Updated += o.Updated
+		c.Unchanged += o.Unchanged
+		c.Deleted += o.Deleted
+		c.Failed += o.Failed
+	}
+}
+
 // RunCheckpoint tracks what entities were processed in a run
 type RunCheckpoint struct {
 	ID           string    `json:"id"`
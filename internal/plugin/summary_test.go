@@ -0,0 +1,31 @@
+package plugin
+
+import "testing"
+
+func TestEntitySummary_AddAndMerge(t *testing.T) {
+	s := EntitySummary{}
+	s.Add("asset", "created")
+	s.Add("asset", "unchanged")
+	s.Add("asset", "unchanged")
+	s.Add("lineage", "failed")
+	s.Add("lineage", "skipped")
+
+	other := EntitySummary{}
+	other.Add("asset", "deleted")
+	other.Add("documentation", "updated")
+	s.Merge(other)
+
+	want := map[string]EntityCounts{
+		"asset":         {Created: 1, Unchanged: 2, Deleted: 1},
+		"lineage":       {Failed: 1},
+		"documentation": {Updated: 1},
+	}
+	if len(s) != len(want) {
+		t.Fatalf("expected %d entity types, got %d", len(want), len(s))
+	}
+	for entityType, counts := range want {
+		if got := s[entityType]; got == nil || *got != counts {
+			t.Errorf("%s: expected %+v, got %+v", entityType, counts, got)
+		}
+	}
+}
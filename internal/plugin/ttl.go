@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/rs/zerolog/log"
+)
+
+// TTLConfigKey is the plugin config key holding time-to-live rules.
+const TTLConfigKey = "ttl"
+
+// TTLRule gives matching assets a time to live. Assets that are not
+// discovered again within After of their last sync are archived, which
+// suits temporary tables and short-lived topics:
+//
+//	ttl:
+//	  - level: Table
+//	    syntax: glob
+//	    match: ["tmp_*"]
+//	    after: 24h
+type TTLRule struct {
+	Level  string   `json:"level,omitempty" yaml:"level,omitempty"`
+	Field  string   `json:"field,omitempty" yaml:"field,omitempty"`
+	Syntax string   `json:"syntax,omitempty" yaml:"syntax,omitempty"`
+	Match  []string `json:"match,omitempty" yaml:"match,omitempty"`
+	After  string   `json:"after" yaml:"after"`
+}
+
+type ttlConfig struct {
+	TTL []TTLRule `json:"ttl"`
+}
+
+type compiledTTLRule struct {
+	compiledRule
+	seconds int
+}
+
+// TTLRulesSpec documents the TTL rules in a plugin's config spec.
+var TTLRulesSpec = pluginsdk.ConfigField{
+	Name:        TTLConfigKey,
+	Label:       "Time to Live",
+	Type:        pluginsdk.FieldTypeObject,
+	IsArray:     true,
+	Description: "Archive matching assets when they are not discovered again within a duration. The first matching rule wins.",
+	Fields: []pluginsdk.ConfigField{
+		{Name: "level", Label: "Level", Type: pluginsdk.FieldTypeString, Description: "Asset type the rule applies to, e.g. Table or Topic. Empty applies to all assets."},
+		{Name: "field", Label: "Field", Type: pluginsdk.FieldTypeString, Default: FilterFieldName, Description: "What to match: name, mrn, tag, or metadata.<key>"},
+		{Name: "syntax", Label: "Syntax", Type: pluginsdk.FieldTypeSelect, Default: FilterSyntaxRegex, Description: "Pattern syntax", Options: []pluginsdk.FieldOption{
+			{Label: "Regular expression", Value: FilterSyntaxRegex},
+			{Label: "Glob", Value: FilterSyntaxGlob},
+		}},
+		{Name: "match", Label: "Match", Type: pluginsdk.FieldTypeString, IsArray: true, Description: "Patterns an asset must match. Empty matches every asset at the level."},
+		{Name: "after", Label: "After", Type: pluginsdk.FieldTypeString, Required: true, Description: "Time to live, e.g. 6h or 168h"},
+	},
+}
+
+// parseTTLRules reads and compiles the TTL rules from a plugin config.
+func parseTTLRules(rawConfig RawPluginConfig) ([]compiledTTLRule, error) {
+	if _, ok := rawConfig[TTLConfigKey]; !ok {
+		return nil, nil
+	}
+	cfg, err := UnmarshalPluginConfig[ttlConfig](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ttl: %w", err)
+	}
+
+	rules := make([]compiledTTLRule, 0, len(cfg.TTL))
+	for i, r := range cfg.TTL {
+		after, err := time.ParseDuration(r.After)
+		if err != nil || after < time.Minute {
+			return nil, fmt.Errorf("ttl[%d]: after must be a duration of at least 1m, got %q", i, r.After)
+		}
+
+		rule, err := compileRule(r.Level, r.Field, r.Syntax, r.Match, nil)
+		if err != nil {
+			return nil, fmt.Errorf("ttl[%d]: %w", i, err)
+		}
+
+		rules = append(rules, compiledTTLRule{compiledRule: rule, seconds: int(after.Seconds())})
+	}
+	return rules, nil
+}
+
+// matches reports whether the rule applies to the asset. Unlike filter
+// rules, a rule for another level or a missing metadata key never matches.
+func (r compiledTTLRule) matches(a asset.Asset) bool {
+	if r.level != "" && r.level != strings.ToLower(a.Type) {
+		return false
+	}
+	values, ok := fieldValues(a, r.field)
+	if !ok {
+		return false
+	}
+	if len(r.include) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if matchesAny(v, r.include) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTTLRules stamps the TTL of the first matching rule into each asset's
+// metadata. Assets whose plugin already set a TTL keep it.
+func ApplyTTLRules(result *DiscoveryResult, rawConfig RawPluginConfig) {
+	if result == nil {
+		return
+	}
+
+	rules, err := parseTTLRules(rawConfig)
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring invalid ttl rules")
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	for i := range result.Assets {
+		a := &result.Assets[i]
+		if _, ok := a.Metadata[asset.MetadataTTLKey]; ok {
+			continue
+		}
+		for _, r := range rules {
+			if r.matches(*a) {
+				if a.Metadata == nil {
+					a.Metadata = map[string]interface{}{}
+				}
+				a.Metadata[asset.MetadataTTLKey] = r.seconds
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/marmotdata/marmot/internal/core/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTTLRules(t *testing.T) {
+	tmp, orders, topic := "tmp_orders", "orders", "events"
+	result := &DiscoveryResult{Assets: []asset.Asset{
+		{Name: &tmp, Type: "Table"},
+		{Name: &orders, Type: "Table"},
+		{Name: &topic, Type: "Topic", Metadata: map[string]interface{}{asset.MetadataTTLKey: 60}},
+	}}
+	config := RawPluginConfig{TTLConfigKey: []interface{}{
+		map[string]interface{}{"level": "table", "syntax": "glob", "match": []interface{}{"tmp_*"}, "after": "24h"},
+		map[string]interface{}{"level": "Topic", "after": "1h"},
+	}}
+
+	ApplyTTLRules(result, config)
+
+	assert.Equal(t, 86400, result.Assets[0].Metadata[asset.MetadataTTLKey])
+	assert.NotContains(t, result.Assets[1].Metadata, asset.MetadataTTLKey)
+	assert.Equal(t, 60, result.Assets[2].Metadata[asset.MetadataTTLKey], "plugin-set TTL wins")
+}
+
+func TestParseTTLRules_Invalid(t *testing.T) {
+	_, err := parseTTLRules(RawPluginConfig{TTLConfigKey: []interface{}{
+		map[string]interface{}{"after": "10s"},
+	}})
+	assert.ErrorContains(t, err, "at least 1m")
+
+	_, err = parseTTLRules(RawPluginConfig{TTLConfigKey: []interface{}{
+		map[string]interface{}{"field": "owner", "after": "1h"},
+	}})
+	assert.ErrorContains(t, err, `ttl[0]: unknown field "owner"`)
+
+	rules, err := parseTTLRules(RawPluginConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
@@ -0,0 +1,162 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reasons a budgeted query returned less than it was asked for.
+const (
+	TruncatedLimit  = "limit"
+	TruncatedOffset = "offset"
+	TruncatedCount  = "count"
+	TruncatedFacets = "facets"
+)
+
+// pgQueryCanceled is the SQLSTATE raised when statement_timeout fires.
+const pgQueryCanceled = "57014"
+
+// Querier is the subset of pgxpool.Pool and pgx.Tx used by budgeted queries.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Budget bounds the database work done for a single request so one
+// pathological query can't saturate the database. Repositories read it from
+// the request context and record the shortcuts they take, which the API
+// reports back to the client.
+//
+// All methods are safe to call on a nil Budget, which imposes no limits.
+type Budget struct {
+	// StatementTimeout is applied as statement_timeout to each budgeted query.
+	StatementTimeout time.Duration
+	// MaxRows caps the page size.
+	MaxRows int
+	// MaxOffset caps how deep a caller can paginate.
+	MaxOffset int
+	// FacetLoadThreshold is the fraction of the connection pool in use above
+	// which facet and count queries are skipped.
+	FacetLoadThreshold float64
+
+	mu          sync.Mutex
+	truncations []string
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a context carrying b.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetFromContext returns the budget attached to ctx, or nil.
+func BudgetFromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
+
+// Truncate records that a result was cut short for reason.
+func (b *Budget) Truncate(reason string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range b.truncations {
+		if r == reason {
+			return
+		}
+	}
+	b.truncations = append(b.truncations, reason)
+}
+
+// Truncations returns the reasons recorded by Truncate, in order.
+func (b *Budget) Truncations() []string {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.truncations...)
+}
+
+// ClampPage caps limit at MaxRows. It returns false when offset is past
+// MaxOffset, in which case the caller should return no rows.
+func (b *Budget) ClampPage(limit, offset int) (int, bool) {
+	if b == nil {
+		return limit, true
+	}
+	if b.MaxRows > 0 && limit > b.MaxRows {
+		limit = b.MaxRows
+		b.Truncate(TruncatedLimit)
+	}
+	if b.MaxOffset > 0 && offset > b.MaxOffset {
+		b.Truncate(TruncatedOffset)
+		return limit, false
+	}
+	return limit, true
+}
+
+// UnderLoad reports whether the share of acquired connections in db has
+// reached FacetLoadThreshold.
+func (b *Budget) UnderLoad(db *pgxpool.Pool) bool {
+	if b == nil || db == nil {
+		return false
+	}
+	stat := db.Stat()
+	return b.loaded(stat.AcquiredConns(), stat.MaxConns())
+}
+
+func (b *Budget) loaded(acquired, maxConns int32) bool {
+	if b.FacetLoadThreshold <= 0 || maxConns <= 0 {
+		return false
+	}
+	return float64(acquired)/float64(maxConns) >= b.FacetLoadThreshold
+}
+
+// Run calls fn with a read-only transaction that has statement_timeout set to
+// the budget's StatementTimeout. Without a timeout fn runs directly on db.
+// Each call gets its own transaction, so a statement that times out in one
+// call doesn't abort queries in the next.
+func (b *Budget) Run(ctx context.Context, db *pgxpool.Pool, fn func(q Querier) error) error {
+	if b == nil || b.StatementTimeout <= 0 {
+		return fn(db)
+	}
+
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("beginning budgeted transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", b.StatementTimeout.Milliseconds())); err != nil {
+		return fmt.Errorf("setting statement timeout: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// IsTimeout reports whether err was caused by statement_timeout or a context
+// deadline.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceled
+}
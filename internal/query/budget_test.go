@@ -0,0 +1,150 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetTruncate(t *testing.T) {
+	b := &Budget{}
+	assert.Empty(t, b.Truncations())
+
+	b.Truncate(TruncatedFacets)
+	b.Truncate(TruncatedCount)
+	b.Truncate(TruncatedFacets)
+	assert.Equal(t, []string{TruncatedFacets, TruncatedCount}, b.Truncations())
+
+	// Callers can't change the recorded reasons through the returned slice.
+	b.Truncations()[0] = "changed"
+	assert.Equal(t, []string{TruncatedFacets, TruncatedCount}, b.Truncations())
+
+	var none *Budget
+	none.Truncate(TruncatedLimit)
+	assert.Nil(t, none.Truncations())
+}
+
+func TestBudgetFromContext(t *testing.T) {
+	assert.Nil(t, BudgetFromContext(context.Background()))
+
+	b := &Budget{MaxRows: 10}
+	assert.Same(t, b, BudgetFromContext(WithBudget(context.Background(), b)))
+}
+
+func TestClampPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		budget      *Budget
+		limit       int
+		offset      int
+		wantLimit   int
+		wantOK      bool
+		wantReasons []string
+	}{
+		{name: "nil budget", limit: 5000, offset: 50000, wantLimit: 5000, wantOK: true},
+		{name: "no limits", budget: &Budget{}, limit: 5000, offset: 50000, wantLimit: 5000, wantOK: true},
+		{name: "within limits", budget: &Budget{MaxRows: 100, MaxOffset: 1000}, limit: 100, offset: 1000, wantLimit: 100, wantOK: true},
+		{name: "limit clamped", budget: &Budget{MaxRows: 100}, limit: 500, wantLimit: 100, wantOK: true, wantReasons: []string{TruncatedLimit}},
+		{name: "offset past max", budget: &Budget{MaxOffset: 1000}, limit: 20, offset: 1001, wantLimit: 20, wantReasons: []string{TruncatedOffset}},
+		{
+			name:        "both exceeded",
+			budget:      &Budget{MaxRows: 100, MaxOffset: 1000},
+			limit:       500,
+			offset:      5000,
+			wantLimit:   100,
+			wantReasons: []string{TruncatedLimit, TruncatedOffset},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, ok := tt.budget.ClampPage(tt.limit, tt.offset)
+			assert.Equal(t, tt.wantLimit, limit)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantReasons, tt.budget.Truncations())
+		})
+	}
+}
+
+func TestUnderLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		acquired  int32
+		max       int32
+		want      bool
+	}{
+		{name: "threshold disabled", threshold: 0, acquired: 10, max: 10},
+		{name: "below threshold", threshold: 0.8, acquired: 7, max: 10},
+		{name: "at threshold", threshold: 0.8, acquired: 8, max: 10, want: true},
+		{name: "pool exhausted", threshold: 0.8, acquired: 10, max: 10, want: true},
+		{name: "no pool size", threshold: 0.8, acquired: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Budget{FacetLoadThreshold: tt.threshold}
+			assert.Equal(t, tt.want, b.loaded(tt.acquired, tt.max))
+		})
+	}
+
+	// A pool that hasn't handed out any connections is never under load.
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/marmot")
+	require.NoError(t, err)
+	defer pool.Close()
+	assert.False(t, (&Budget{FacetLoadThreshold: 0.01}).UnderLoad(pool))
+	assert.False(t, (&Budget{FacetLoadThreshold: 0.01}).UnderLoad(nil))
+
+	var none *Budget
+	assert.False(t, none.UnderLoad(pool))
+}
+
+func TestRunWithoutTimeoutUsesPool(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/marmot")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	for _, b := range []*Budget{nil, {MaxRows: 10}} {
+		var got Querier
+		err := b.Run(context.Background(), pool, func(q Querier) error {
+			got = q
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Same(t, pool, got)
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil},
+		{name: "other error", err: errors.New("connection refused")},
+		{name: "statement timeout", err: &pgconn.PgError{Code: pgQueryCanceled}, want: true},
+		{name: "wrapped statement timeout", err: fmt.Errorf("executing search: %w", &pgconn.PgError{Code: pgQueryCanceled}), want: true},
+		{name: "other postgres error", err: &pgconn.PgError{Code: "42P01"}},
+		{name: "context deadline", err: ctx.Err(), want: true},
+		{name: "wrapped context deadline", err: fmt.Errorf("counting: %w", context.DeadlineExceeded), want: true},
+		{name: "context canceled", err: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTimeout(tt.err))
+		})
+	}
+}
@@ -14,6 +14,7 @@ type TableConfig struct {
 	ProviderColumn string // "providers" for both
 	NameColumn     string // "name" for both
 	MetadataColumn string // "metadata" for both
+	StatusColumn   string // lifecycle status, looked up from assets for search_index
 }
 
 // DefaultAssetsConfig returns column config for the assets table
@@ -23,6 +24,7 @@ func DefaultAssetsConfig() TableConfig {
 		ProviderColumn: "providers",
 		NameColumn:     "name",
 		MetadataColumn: "metadata",
+		StatusColumn:   "lifecycle_status",
 	}
 }
 
@@ -33,6 +35,7 @@ func SearchIndexConfig() TableConfig {
 		ProviderColumn: "providers",
 		NameColumn:     "name",
 		MetadataColumn: "metadata",
+		StatusColumn:   "(CASE WHEN type = 'asset' THEN (SELECT a.lifecycle_status FROM assets a WHERE a.id = entity_id) END)",
 	}
 }
 
@@ -282,6 +285,9 @@ func (b *Builder) buildFilterCondition(filter Filter, paramCount int) (string, [
 	case FieldName:
 		// @name queries the name column directly
 		columnRef = b.config.NameColumn
+	case FieldStatus:
+		// @status queries the asset's lifecycle status
+		columnRef = b.config.StatusColumn
 	case FieldMetadata:
 		// @metadata.* queries the metadata JSONB column
 		// Build JSON path with validated field names
@@ -322,7 +328,7 @@ func (b *Builder) buildFilterCondition(filter Filter, paramCount int) (string, [
 			// Use lower() for case-insensitive match with functional index
 			condition = fmt.Sprintf("lower(%s) = lower($%d)", columnRef, paramCount)
 			params = append(params, filter.Value)
-		case filter.FieldType == FieldName:
+		case filter.FieldType == FieldName, filter.FieldType == FieldStatus:
 			// Use lower() for case-insensitive match with functional index
 			condition = fmt.Sprintf("lower(%s) = lower($%d)", columnRef, paramCount)
 			params = append(params, filter.Value)
@@ -365,6 +371,10 @@ func (b *Builder) buildFilterCondition(filter Filter, paramCount int) (string, [
 			// Use lower() for case-insensitive not-equal
 			condition = fmt.Sprintf("lower(%s) != lower($%d)", columnRef, paramCount)
 			params = append(params, filter.Value)
+		case filter.FieldType == FieldStatus:
+			// Non-assets have no status and never match
+			condition = fmt.Sprintf("(%s IS NOT NULL AND lower(%s) != lower($%d))", columnRef, columnRef, paramCount)
+			params = append(params, filter.Value)
 		case filter.FieldType == FieldKind:
 			condition = "TRUE"
 			return condition, nil, paramCount, nil
@@ -649,6 +649,32 @@ func TestBuildFilterCondition(t *testing.T) {
 			expectedStartIdx: 0,
 			expectedErr:      nil,
 		},
+		{
+			name: "Status Equals",
+			filter: Filter{
+				Field:     []string{"status"},
+				FieldType: FieldStatus,
+				Operator:  OpEquals,
+				Value:     "Deprecated",
+			},
+			expectedCond:     "lower(lifecycle_status) = lower($1)",
+			expectedParams:   []interface{}{"Deprecated"},
+			expectedStartIdx: 0,
+			expectedErr:      nil,
+		},
+		{
+			name: "Status NotEquals",
+			filter: Filter{
+				Field:     []string{"status"},
+				FieldType: FieldStatus,
+				Operator:  OpNotEquals,
+				Value:     "active",
+			},
+			expectedCond:     "(lifecycle_status IS NOT NULL AND lower(lifecycle_status) != lower($1))",
+			expectedParams:   []interface{}{"active"},
+			expectedStartIdx: 0,
+			expectedErr:      nil,
+		},
 		// Edge cases for special characters in metadata values
 		{
 			name: "Metadata with quotes",
@@ -34,6 +34,7 @@ const (
 	FieldProvider  FieldType = "provider"
 	FieldKind      FieldType = "kind"
 	FieldName      FieldType = "name"
+	FieldStatus    FieldType = "status"
 )
 
 // RangeValue represents a range query with optional bounds
@@ -79,6 +79,18 @@ func buildSearchQuery(filter search.Filter) map[string]interface{} {
 		})
 	}
 
+	// Asset scope, e.g. a lineage closure
+	if filter.AssetIDs != nil {
+		filterClauses = append(filterClauses,
+			map[string]interface{}{
+				"term": map[string]interface{}{"type": "asset"},
+			},
+			map[string]interface{}{
+				"terms": map[string]interface{}{"entity_id": filter.AssetIDs},
+			},
+		)
+	}
+
 	boolQuery := map[string]interface{}{}
 	if len(must) > 0 {
 		boolQuery["must"] = must
@@ -158,7 +170,9 @@ func buildSearchQuery(filter search.Filter) map[string]interface{} {
 		"sort":  sort,
 		"from":  filter.Offset,
 		"size":  filter.Limit,
-		"aggs":  aggs,
+	}
+	if !filter.SkipFacets {
+		body["aggs"] = aggs
 	}
 
 	return body
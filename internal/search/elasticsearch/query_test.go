@@ -153,6 +153,38 @@ func TestBuildSearchQuery_WithFilters(t *testing.T) {
 	}
 }
 
+func TestBuildSearchQuery_AssetScope(t *testing.T) {
+	body := buildSearchQuery(search.Filter{AssetIDs: []string{"a1", "a2"}, Limit: 20})
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to marshal query: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("failed to unmarshal query: %v", err)
+	}
+
+	boolQ := result["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	filterClauses := boolQ["filter"].([]interface{})
+	if len(filterClauses) != 2 {
+		t.Fatalf("expected type and entity_id filters, got %d clauses", len(filterClauses))
+	}
+
+	terms := filterClauses[1].(map[string]interface{})["terms"].(map[string]interface{})
+	ids := terms["entity_id"].([]interface{})
+	if len(ids) != 2 || ids[0] != "a1" || ids[1] != "a2" {
+		t.Errorf("unexpected entity_id filter: %v", ids)
+	}
+
+	// An empty scope must still filter, matching nothing.
+	body = buildSearchQuery(search.Filter{AssetIDs: []string{}, Limit: 20})
+	if _, ok := body["query"].(map[string]interface{})["match_all"]; ok {
+		t.Error("empty asset scope must not match all documents")
+	}
+}
+
 func TestBuildSearchQuery_Aggregations(t *testing.T) {
 	filter := search.Filter{
 		Query: "test",
@@ -173,3 +205,11 @@ func TestBuildSearchQuery_Aggregations(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildSearchQuery_SkipFacets(t *testing.T) {
+	body := buildSearchQuery(search.Filter{Query: "test", Limit: 20, SkipFacets: true})
+
+	if _, ok := body["aggs"]; ok {
+		t.Error("expected no aggs when facets are skipped")
+	}
+}
@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE data_product_rules DROP CONSTRAINT IF EXISTS data_product_rules_rule_type_check;
+ALTER TABLE data_product_rules
+  ADD CONSTRAINT data_product_rules_rule_type_check CHECK (rule_type IN ('query', 'metadata_match', 'lineage'));
+
+ALTER TABLE data_product_rules
+  ADD COLUMN lineage_asset_id UUID,
+  ADD COLUMN lineage_direction VARCHAR(20) CHECK (lineage_direction IN ('upstream', 'downstream', 'both')),
+  ADD COLUMN lineage_depth INT CHECK (lineage_depth BETWEEN 1 AND 10);
+
+COMMIT;
+
+---- create above / drop below ----
+
+BEGIN;
+
+DELETE FROM data_product_rules WHERE rule_type = 'lineage';
+
+ALTER TABLE data_product_rules
+  DROP COLUMN IF EXISTS lineage_depth,
+  DROP COLUMN IF EXISTS lineage_direction,
+  DROP COLUMN IF EXISTS lineage_asset_id;
+
+ALTER TABLE data_product_rules DROP CONSTRAINT IF EXISTS data_product_rules_rule_type_check;
+ALTER TABLE data_product_rules
+  ADD CONSTRAINT data_product_rules_rule_type_check CHECK (rule_type IN ('query', 'metadata_match'));
+
+COMMIT;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS policy_tags (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             VARCHAR(100) NOT NULL UNIQUE,
+    description      TEXT,
+    masking_strategy VARCHAR(20) NOT NULL DEFAULT 'none'
+        CHECK (masking_strategy IN ('none', 'redact', 'hash', 'nullify', 'partial', 'tokenize')),
+    masking_params   JSONB,
+    restricted       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by       UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- column_name is '' for asset-level assignments so it can participate in the primary key.
+CREATE TABLE IF NOT EXISTS asset_policy_tags (
+    asset_id      VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    policy_tag_id UUID NOT NULL REFERENCES policy_tags(id) ON DELETE CASCADE,
+    column_name   VARCHAR(255) NOT NULL DEFAULT '',
+    created_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, policy_tag_id, column_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_policy_tags_tag ON asset_policy_tags(policy_tag_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_policy_tags;
+DROP TABLE IF EXISTS policy_tags;
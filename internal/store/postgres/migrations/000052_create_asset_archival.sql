@@ -0,0 +1,34 @@
+-- Assets flagged as stale, waiting out the grace period before archival.
+CREATE TABLE IF NOT EXISTS asset_archival_candidates (
+    asset_id      VARCHAR(255) PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    mrn           VARCHAR(255) NOT NULL,
+    last_sync_at  TIMESTAMPTZ NOT NULL,
+    flagged_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archive_after TIMESTAMPTZ NOT NULL,
+    exempt        BOOLEAN NOT NULL DEFAULT FALSE,
+    exempted_by   UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_archival_candidates_due
+    ON asset_archival_candidates(archive_after) WHERE exempt = FALSE;
+
+-- Snapshots of archived assets so they can be restored.
+CREATE TABLE IF NOT EXISTS archived_assets (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id     VARCHAR(255) NOT NULL,
+    mrn          VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    type         VARCHAR(255) NOT NULL,
+    snapshot     JSONB NOT NULL,
+    owners       JSONB NOT NULL DEFAULT '[]'::jsonb,
+    last_sync_at TIMESTAMPTZ NOT NULL,
+    archived_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_assets_mrn ON archived_assets(mrn);
+CREATE INDEX IF NOT EXISTS idx_archived_assets_archived_at ON archived_assets(archived_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS archived_assets;
+DROP TABLE IF EXISTS asset_archival_candidates;
@@ -0,0 +1,22 @@
+-- Idempotency-Key reservations and the responses they replay. scope is the
+-- authenticated principal, so keys from different callers never collide.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope          VARCHAR(255) NOT NULL,
+    key            VARCHAR(255) NOT NULL,
+    method         VARCHAR(10) NOT NULL,
+    path           TEXT NOT NULL,
+    fingerprint    CHAR(64) NOT NULL,
+    status_code    INTEGER,
+    content_type   TEXT,
+    response_body  BYTEA,
+    locked_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at   TIMESTAMPTZ,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS idempotency_keys;
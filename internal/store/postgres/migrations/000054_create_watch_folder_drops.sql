@@ -0,0 +1,22 @@
+-- Metadata drops found in watch folders. fingerprint covers the files that
+-- make up a drop so unchanged drops aren't ingested again.
+CREATE TABLE IF NOT EXISTS watch_folder_drops (
+    folder       VARCHAR(255) NOT NULL,
+    drop_key     TEXT NOT NULL,
+    kind         VARCHAR(20) NOT NULL,
+    path         TEXT NOT NULL,
+    fingerprint  CHAR(64) NOT NULL,
+    status       VARCHAR(20) NOT NULL CHECK (status IN ('queued', 'ingested', 'failed')),
+    job_run_id   UUID REFERENCES ingestion_job_runs(id) ON DELETE SET NULL,
+    run_id       VARCHAR(255),
+    error        TEXT,
+    detected_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (folder, drop_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watch_folder_drops_updated_at ON watch_folder_drops(updated_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS watch_folder_drops;
@@ -0,0 +1,5 @@
+ALTER TABLE ingestion_job_runs ADD COLUMN entity_counts JSONB;
+
+---- create above / drop below ----
+
+ALTER TABLE ingestion_job_runs DROP COLUMN IF EXISTS entity_counts;
@@ -0,0 +1,6 @@
+-- Fields edited by hand that ingestion runs must not overwrite.
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS locked_fields TEXT[] NOT NULL DEFAULT '{}';
+
+---- create above / drop below ----
+
+ALTER TABLE assets DROP COLUMN IF EXISTS locked_fields;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS asset_incident_links (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id       VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    provider       VARCHAR(50) NOT NULL,
+    external_id    VARCHAR(255) NOT NULL,
+    url            TEXT,
+    title          TEXT NOT NULL DEFAULT '',
+    status         VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'in_progress', 'resolved', 'closed', 'unknown')),
+    severity       VARCHAR(20),
+    propagate      BOOLEAN NOT NULL DEFAULT TRUE,
+    last_synced_at TIMESTAMPTZ,
+    sync_error     TEXT,
+    created_by     UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (asset_id, provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_incident_links_asset ON asset_incident_links(asset_id);
+CREATE INDEX IF NOT EXISTS idx_asset_incident_links_active
+    ON asset_incident_links(provider, last_synced_at NULLS FIRST)
+    WHERE status IN ('open', 'in_progress', 'unknown');
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_incident_links;
@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS asset_questions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id           VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    title              VARCHAR(300) NOT NULL,
+    body               TEXT NOT NULL DEFAULT '',
+    accepted_answer_id UUID,
+    created_by         UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS asset_answers (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES asset_questions(id) ON DELETE CASCADE,
+    body        TEXT NOT NULL,
+    by_owner    BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE asset_questions
+    ADD CONSTRAINT fk_asset_questions_accepted_answer
+    FOREIGN KEY (accepted_answer_id) REFERENCES asset_answers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_asset_questions_asset ON asset_questions(asset_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_answers_question ON asset_answers(question_id, created_at);
+
+-- Accepted answers are folded into the asset's search_index row at the
+-- lowest weight, so searching for an answered question finds the asset.
+-- The asset triggers rewrite search_text from assets.search_text on every
+-- change, so this runs BEFORE the write and re-appends the Q&A text. Asset
+-- search text never uses weight D, so filtering it out first keeps upserts,
+-- which fire both the insert and update trigger, from doubling it up.
+CREATE OR REPLACE FUNCTION search_index_append_accepted_answers()
+RETURNS TRIGGER AS $$
+DECLARE
+    qa_text TEXT;
+BEGIN
+    IF NEW.type <> 'asset' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT STRING_AGG(q.title || ' ' || q.body || ' ' || a.body, ' ')
+    INTO qa_text
+    FROM asset_questions q
+    JOIN asset_answers a ON a.id = q.accepted_answer_id
+    WHERE q.asset_id = NEW.entity_id;
+
+    NEW.search_text := ts_filter(NEW.search_text, '{a,b,c}');
+    IF qa_text IS NOT NULL THEN
+        NEW.search_text := NEW.search_text || setweight(to_tsvector('english', qa_text), 'D');
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_accepted_answers ON search_index;
+CREATE TRIGGER search_index_accepted_answers
+    BEFORE INSERT OR UPDATE OF search_text ON search_index
+    FOR EACH ROW EXECUTE FUNCTION search_index_append_accepted_answers();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS search_index_accepted_answers ON search_index;
+DROP FUNCTION IF EXISTS search_index_append_accepted_answers();
+DROP TABLE IF EXISTS asset_answers CASCADE;
+DROP TABLE IF EXISTS asset_questions;
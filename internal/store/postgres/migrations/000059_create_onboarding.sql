@@ -0,0 +1,26 @@
+-- Onboarding checklist progress. Rows with a NULL user_id apply to the whole
+-- deployment; rows with a user_id only change that user's checklist.
+CREATE TABLE IF NOT EXISTS onboarding_progress (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID REFERENCES users(id) ON DELETE CASCADE,
+    step       VARCHAR(50) NOT NULL,
+    status     VARCHAR(20) NOT NULL CHECK (status IN ('completed', 'skipped')),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_onboarding_progress_deployment
+    ON onboarding_progress(step) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_onboarding_progress_user
+    ON onboarding_progress(user_id, step) WHERE user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS onboarding_user_state (
+    user_id    UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    dismissed  BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS onboarding_user_state;
+DROP TABLE IF EXISTS onboarding_progress;
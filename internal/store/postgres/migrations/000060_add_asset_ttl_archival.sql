@@ -0,0 +1,13 @@
+-- Assets with a time to live are archived once they go unsynced for longer
+-- than metadata.ttl_seconds.
+CREATE INDEX IF NOT EXISTS idx_assets_ttl_last_sync
+    ON assets(last_sync_at) WHERE metadata ? 'ttl_seconds';
+
+-- Why an asset was archived: 'stale' for the stale asset policy, 'expired'
+-- for assets whose time to live ran out.
+ALTER TABLE archived_assets ADD COLUMN IF NOT EXISTS reason VARCHAR(20) NOT NULL DEFAULT 'stale';
+
+---- create above / drop below ----
+
+ALTER TABLE archived_assets DROP COLUMN IF EXISTS reason;
+DROP INDEX IF EXISTS idx_assets_ttl_last_sync;
@@ -0,0 +1,31 @@
+-- Signed, expiring read-only links to a single asset. Links die with their
+-- creator so a departed user's links stop working.
+CREATE TABLE IF NOT EXISTS asset_share_links (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id   VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    note       TEXT,
+    anonymize  BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_share_links_asset ON asset_share_links(asset_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS asset_share_link_accesses (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id     UUID NOT NULL REFERENCES asset_share_links(id) ON DELETE CASCADE,
+    accessed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ip_address  VARCHAR(64),
+    user_agent  TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_share_link_accesses_link
+    ON asset_share_link_accesses(link_id, accessed_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_share_link_accesses;
+DROP TABLE IF EXISTS asset_share_links;
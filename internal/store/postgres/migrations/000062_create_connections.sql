@@ -0,0 +1,33 @@
+-- Shared connections hold a provider's host and credentials once, so several
+-- schedules can use them and credentials are rotated in one place. Sensitive
+-- config fields are encrypted like schedule config.
+CREATE TABLE IF NOT EXISTS connections (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                   VARCHAR(255) NOT NULL UNIQUE,
+    provider               VARCHAR(100) NOT NULL,
+    description            TEXT,
+    config                 JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status                 VARCHAR(20) NOT NULL DEFAULT 'unknown',
+    last_checked_at        TIMESTAMPTZ,
+    last_error             TEXT,
+    latency_ms             INTEGER,
+    credentials_rotated_at TIMESTAMPTZ,
+    created_by             VARCHAR(255),
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_connections_provider ON connections(provider);
+
+-- A connection cannot be deleted while schedules still use it.
+ALTER TABLE ingestion_schedules
+    ADD COLUMN IF NOT EXISTS connection_id UUID REFERENCES connections(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_schedules_connection
+    ON ingestion_schedules(connection_id) WHERE connection_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_ingestion_schedules_connection;
+ALTER TABLE ingestion_schedules DROP COLUMN IF EXISTS connection_id;
+DROP TABLE IF EXISTS connections;
@@ -0,0 +1,15 @@
+-- Records when each object storage bucket was last sampled for file schemas,
+-- so the sampler can resample buckets in turn. The inferred schemas
+-- themselves are stored on the asset under "sampled:" schema keys.
+CREATE TABLE IF NOT EXISTS asset_schema_samples (
+    asset_id   VARCHAR(255) PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    sampled_at TIMESTAMPTZ NOT NULL,
+    datasets   INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_schema_samples_sampled_at ON asset_schema_samples(sampled_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_schema_samples;
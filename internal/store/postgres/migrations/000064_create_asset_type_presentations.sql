@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS asset_type_presentations (
+    asset_type VARCHAR(100) PRIMARY KEY,
+    groups JSONB NOT NULL DEFAULT '[]',
+    links JSONB NOT NULL DEFAULT '[]',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_type_presentations;
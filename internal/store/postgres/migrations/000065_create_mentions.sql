@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS mentions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_type VARCHAR(50) NOT NULL,
+    source_id VARCHAR(255) NOT NULL,
+    asset_id VARCHAR(255) REFERENCES assets(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (source_type, source_id, target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_target ON mentions (target_type, target_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_mentions_target;
+DROP TABLE IF EXISTS mentions;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS search_pins (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    term VARCHAR(256) NOT NULL,
+    asset_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (term, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_pins_term ON search_pins (term, position);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_search_pins_term;
+DROP TABLE IF EXISTS search_pins;
@@ -0,0 +1,135 @@
+-- Schema blobs (OpenAPI specs, Avro schemas, dbt column JSON) are stored once
+-- in schemas, keyed by the SHA-256 of their content. assets.schema keeps the
+-- same name -> value shape, but each value is a "sha256:<hex>" reference that
+-- readers expand with resolve_schema_blobs(). Writers keep sending content:
+-- the trigger below swaps it for a reference on the way in.
+CREATE TABLE IF NOT EXISTS schemas (
+    hash VARCHAR(71) PRIMARY KEY,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Which assets reference which blob, maintained by trigger so "which assets
+-- share this schema" is an index lookup.
+CREATE TABLE IF NOT EXISTS asset_schemas (
+    asset_id VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL,
+    hash VARCHAR(71) NOT NULL REFERENCES schemas(hash),
+    PRIMARY KEY (asset_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_schemas_hash ON asset_schemas (hash);
+
+CREATE OR REPLACE FUNCTION store_schema_blobs(doc JSONB)
+RETURNS JSONB AS $$
+DECLARE
+    result JSONB := '{}'::jsonb;
+    entry RECORD;
+    body TEXT;
+    ref TEXT;
+BEGIN
+    IF doc IS NULL OR jsonb_typeof(doc) <> 'object' THEN
+        RETURN doc;
+    END IF;
+
+    FOR entry IN SELECT key, value FROM jsonb_each(doc) LOOP
+        IF jsonb_typeof(entry.value) <> 'string' THEN
+            result := result || jsonb_build_object(entry.key, entry.value);
+            CONTINUE;
+        END IF;
+
+        body := entry.value #>> '{}';
+        IF body ~ '^sha256:[0-9a-f]{64}$' AND EXISTS (SELECT 1 FROM schemas WHERE hash = body) THEN
+            result := result || jsonb_build_object(entry.key, body);
+            CONTINUE;
+        END IF;
+
+        ref := 'sha256:' || encode(sha256(convert_to(body, 'UTF8')), 'hex');
+        -- DO UPDATE rather than DO NOTHING locks the row, so a concurrent
+        -- orphan cleanup can't delete it before this transaction commits.
+        INSERT INTO schemas (hash, content, size_bytes)
+        VALUES (ref, body, octet_length(body))
+        ON CONFLICT (hash) DO UPDATE SET size_bytes = EXCLUDED.size_bytes;
+        result := result || jsonb_build_object(entry.key, ref);
+    END LOOP;
+
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION resolve_schema_blobs(doc JSONB)
+RETURNS JSONB AS $$
+    SELECT COALESCE(jsonb_object_agg(e.key, COALESCE(to_jsonb(s.content), e.value)), '{}'::jsonb)
+    FROM jsonb_each(COALESCE(doc, '{}'::jsonb)) e
+    LEFT JOIN schemas s ON jsonb_typeof(e.value) = 'string' AND s.hash = e.value #>> '{}'
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION assets_store_schema_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.schema := store_schema_blobs(NEW.schema);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION assets_sync_schema_refs_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    released TEXT[];
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.schema IS NOT DISTINCT FROM NEW.schema THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        WITH removed AS (
+            DELETE FROM asset_schemas WHERE asset_id = OLD.id RETURNING hash
+        )
+        SELECT array_agg(DISTINCT hash) INTO released FROM removed;
+    END IF;
+
+    IF TG_OP <> 'DELETE' THEN
+        INSERT INTO asset_schemas (asset_id, name, hash)
+        SELECT NEW.id, e.key, e.value #>> '{}'
+        FROM jsonb_each(NEW.schema) e
+        WHERE jsonb_typeof(e.value) = 'string'
+          AND EXISTS (SELECT 1 FROM schemas s WHERE s.hash = e.value #>> '{}');
+    END IF;
+
+    IF released IS NOT NULL THEN
+        DELETE FROM schemas s
+        WHERE s.hash = ANY(released)
+          AND NOT EXISTS (SELECT 1 FROM asset_schemas r WHERE r.hash = s.hash);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS assets_store_schema ON assets;
+CREATE TRIGGER assets_store_schema
+    BEFORE INSERT OR UPDATE OF schema ON assets
+    FOR EACH ROW EXECUTE FUNCTION assets_store_schema_trigger();
+
+DROP TRIGGER IF EXISTS assets_sync_schema_refs ON assets;
+CREATE TRIGGER assets_sync_schema_refs
+    AFTER INSERT OR UPDATE OF schema OR DELETE ON assets
+    FOR EACH ROW EXECUTE FUNCTION assets_sync_schema_refs_trigger();
+
+-- Move existing blobs out of the assets table.
+UPDATE assets SET schema = schema WHERE schema <> '{}'::jsonb;
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS assets_sync_schema_refs ON assets;
+DROP TRIGGER IF EXISTS assets_store_schema ON assets;
+
+UPDATE assets SET schema = resolve_schema_blobs(schema) WHERE schema <> '{}'::jsonb;
+
+DROP FUNCTION IF EXISTS assets_sync_schema_refs_trigger();
+DROP FUNCTION IF EXISTS assets_store_schema_trigger();
+DROP FUNCTION IF EXISTS resolve_schema_blobs(JSONB);
+DROP FUNCTION IF EXISTS store_schema_blobs(JSONB);
+DROP TABLE IF EXISTS asset_schemas;
+DROP TABLE IF EXISTS schemas;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS asset_deprecations (
+    asset_id VARCHAR(255) PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    replacement_asset_id VARCHAR(255) REFERENCES assets(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    sunset_at TIMESTAMP WITH TIME ZONE,
+    deprecated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    deprecated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (replacement_asset_id IS NULL OR replacement_asset_id <> asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_deprecations_replacement ON asset_deprecations (replacement_asset_id);
+
+-- Every asset seen consuming a deprecated asset, so consumers that have since
+-- moved off it still count as migrated.
+CREATE TABLE IF NOT EXISTS asset_deprecation_consumers (
+    asset_id VARCHAR(255) NOT NULL REFERENCES asset_deprecations(asset_id) ON DELETE CASCADE,
+    consumer_mrn VARCHAR(255) NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, consumer_mrn)
+);
+
+-- One row per deprecated asset per day, so migration progress can be charted.
+CREATE TABLE IF NOT EXISTS asset_deprecation_snapshots (
+    asset_id VARCHAR(255) NOT NULL REFERENCES asset_deprecations(asset_id) ON DELETE CASCADE,
+    recorded_on DATE NOT NULL,
+    remaining INTEGER NOT NULL,
+    dual INTEGER NOT NULL,
+    migrated INTEGER NOT NULL,
+    PRIMARY KEY (asset_id, recorded_on)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_deprecation_snapshots;
+DROP TABLE IF EXISTS asset_deprecation_consumers;
+DROP INDEX IF EXISTS idx_asset_deprecations_replacement;
+DROP TABLE IF EXISTS asset_deprecations;
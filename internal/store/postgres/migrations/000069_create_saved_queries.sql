@@ -0,0 +1,104 @@
+CREATE TABLE IF NOT EXISTS saved_queries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    query TEXT NOT NULL,
+    query_language VARCHAR(50) NOT NULL DEFAULT 'sql',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    owner_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    owner_team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    search_text tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+        setweight(array_to_tsvector(tags), 'B') ||
+        setweight(to_tsvector('english', query), 'C')
+    ) STORED,
+    CHECK (owner_user_id IS NULL OR owner_team_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_queries_updated ON saved_queries (updated_at DESC);
+
+-- A query can document several assets, e.g. a join across two tables.
+CREATE TABLE IF NOT EXISTS saved_query_assets (
+    query_id UUID NOT NULL REFERENCES saved_queries(id) ON DELETE CASCADE,
+    asset_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (query_id, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_query_assets_asset ON saved_query_assets (asset_id);
+
+ALTER TABLE search_index DROP CONSTRAINT IF EXISTS search_index_type_check;
+ALTER TABLE search_index ADD CONSTRAINT search_index_type_check
+    CHECK (type IN ('asset', 'glossary', 'team', 'data_product', 'query'));
+
+CREATE OR REPLACE FUNCTION search_index_saved_query_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM search_index WHERE type = 'query' AND entity_id = OLD.id::text;
+        UPDATE summary_counts SET count = count - 1 WHERE dimension = 'entity_type' AND key = 'query';
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path,
+        created_by, created_at, metadata
+    ) VALUES (
+        'query',
+        NEW.id::text,
+        NEW.name,
+        NEW.description,
+        NEW.search_text,
+        NEW.updated_at,
+        NULL, NULL, NULL,
+        NEW.tags,
+        '/queries/' || NEW.id::text,
+        NEW.created_by::text,
+        NEW.created_at,
+        jsonb_build_object('query_language', NEW.query_language)
+    )
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name,
+        description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text,
+        updated_at = EXCLUDED.updated_at,
+        tags = EXCLUDED.tags,
+        url_path = EXCLUDED.url_path,
+        created_by = EXCLUDED.created_by,
+        created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO summary_counts (dimension, key, count)
+        VALUES ('entity_type', 'query', 1)
+        ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + 1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_saved_query_sync ON saved_queries;
+CREATE TRIGGER search_index_saved_query_sync
+    AFTER INSERT OR UPDATE OR DELETE ON saved_queries
+    FOR EACH ROW EXECUTE FUNCTION search_index_saved_query_trigger();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS search_index_saved_query_sync ON saved_queries;
+DROP FUNCTION IF EXISTS search_index_saved_query_trigger();
+
+DELETE FROM search_index WHERE type = 'query';
+DELETE FROM summary_counts WHERE dimension = 'entity_type' AND key = 'query';
+
+ALTER TABLE search_index DROP CONSTRAINT IF EXISTS search_index_type_check;
+ALTER TABLE search_index ADD CONSTRAINT search_index_type_check
+    CHECK (type IN ('asset', 'glossary', 'team', 'data_product'));
+
+DROP TABLE IF EXISTS saved_query_assets;
+DROP TABLE IF EXISTS saved_queries;
@@ -0,0 +1,416 @@
+-- Definitions of Metric assets. The asset itself, its owners and its lineage
+-- live in the usual tables; this adds what makes it a metric.
+CREATE TABLE IF NOT EXISTS metric_definitions (
+    asset_id VARCHAR(255) PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    definition TEXT NOT NULL DEFAULT '',
+    formula TEXT NOT NULL DEFAULT '',
+    formula_language VARCHAR(50) NOT NULL DEFAULT 'sql',
+    unit VARCHAR(50),
+    certified BOOLEAN NOT NULL DEFAULT FALSE,
+    certified_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    certified_at TIMESTAMP WITH TIME ZONE,
+    certification_note TEXT,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_definitions_certified ON metric_definitions (certified);
+
+-- asset_search_metadata adds an asset's metric definition to the metadata
+-- stored in search_index, so metrics can be filtered and faceted with
+-- @metadata.metric.certified and @metadata.metric.unit.
+CREATE OR REPLACE FUNCTION asset_search_metadata(p_asset_id VARCHAR, p_metadata JSONB)
+RETURNS JSONB AS $$
+    SELECT CASE
+        WHEN m.asset_id IS NULL THEN p_metadata
+        ELSE COALESCE(p_metadata, '{}'::jsonb) || jsonb_build_object('metric', jsonb_strip_nulls(jsonb_build_object(
+            'certified', m.certified,
+            'unit', m.unit,
+            'formula_language', m.formula_language
+        )))
+    END
+    FROM (SELECT 1) AS one
+    LEFT JOIN metric_definitions m ON m.asset_id = p_asset_id;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION sync_asset_search_on_insert()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- entity_type count
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- type dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- provider dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- tag dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Replace update trigger
+CREATE OR REPLACE FUNCTION sync_asset_search_on_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Decrement old counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM asset_tags WHERE asset_id IN (SELECT id FROM deleted);
+
+    -- Insert new data
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- Increment new counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM search_index
+    WHERE type = 'asset' AND entity_id IN (SELECT id FROM inserted WHERE is_stub = TRUE);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Keep search_index in step when only the definition changes.
+CREATE OR REPLACE FUNCTION metric_definitions_search_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_asset_id VARCHAR;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_asset_id := OLD.asset_id;
+    ELSE
+        v_asset_id := NEW.asset_id;
+    END IF;
+
+    UPDATE search_index si
+    SET metadata = asset_search_metadata(a.id, a.metadata)
+    FROM assets a
+    WHERE si.type = 'asset' AND si.entity_id = v_asset_id AND a.id = v_asset_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS metric_definitions_search_sync ON metric_definitions;
+CREATE TRIGGER metric_definitions_search_sync
+    AFTER INSERT OR UPDATE OR DELETE ON metric_definitions
+    FOR EACH ROW EXECUTE FUNCTION metric_definitions_search_trigger();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS metric_definitions_search_sync ON metric_definitions;
+DROP FUNCTION IF EXISTS metric_definitions_search_trigger();
+
+-- Restore the asset search triggers from 000037
+CREATE OR REPLACE FUNCTION sync_asset_search_on_insert()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, metadata
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- entity_type count
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- type dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- provider dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- tag dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Replace update trigger
+CREATE OR REPLACE FUNCTION sync_asset_search_on_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Decrement old counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM asset_tags WHERE asset_id IN (SELECT id FROM deleted);
+
+    -- Insert new data
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, metadata
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- Increment new counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM search_index
+    WHERE type = 'asset' AND entity_id IN (SELECT id FROM inserted WHERE is_stub = TRUE);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE search_index si
+SET metadata = a.metadata
+FROM metric_definitions m
+JOIN assets a ON a.id = m.asset_id
+WHERE si.type = 'asset' AND si.entity_id = m.asset_id;
+
+DROP FUNCTION IF EXISTS asset_search_metadata(VARCHAR, JSONB);
+DROP TABLE IF EXISTS metric_definitions;
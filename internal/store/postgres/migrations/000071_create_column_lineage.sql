@@ -0,0 +1,26 @@
+-- Column lineage records which source columns feed which target columns. It
+-- is kept apart from lineage_edges because column mappings often skip the
+-- job between two datasets, e.g. OpenLineage's columnLineage facet maps input
+-- dataset columns straight to output dataset columns.
+CREATE TABLE IF NOT EXISTS column_lineage (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_mrn VARCHAR(255) NOT NULL REFERENCES assets(mrn) ON DELETE CASCADE,
+    source_column VARCHAR(255) NOT NULL,
+    target_mrn VARCHAR(255) NOT NULL REFERENCES assets(mrn) ON DELETE CASCADE,
+    target_column VARCHAR(255) NOT NULL,
+    transformation TEXT,
+    job_mrn VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_column_lineage UNIQUE (source_mrn, source_column, target_mrn, target_column)
+);
+
+-- Traversals match columns case-insensitively, as warehouses disagree on case.
+CREATE INDEX IF NOT EXISTS idx_column_lineage_target ON column_lineage (target_mrn, lower(target_column));
+CREATE INDEX IF NOT EXISTS idx_column_lineage_source ON column_lineage (source_mrn, lower(source_column));
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_column_lineage_source;
+DROP INDEX IF EXISTS idx_column_lineage_target;
+DROP TABLE IF EXISTS column_lineage;
@@ -0,0 +1,27 @@
+-- Thumbnail images for assets such as dashboards, uploaded by hand or
+-- captured from the asset's URL by a screenshot renderer. A failed capture
+-- keeps the row with no data so it is not retried until the next refresh.
+CREATE TABLE IF NOT EXISTS asset_thumbnails (
+    asset_id VARCHAR(255) PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL,
+    source_url TEXT,
+    content_type VARCHAR(100),
+    size_bytes INTEGER,
+    width INTEGER,
+    height INTEGER,
+    data BYTEA,
+    last_error TEXT,
+    captured_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    CONSTRAINT valid_thumbnail_source CHECK (source IN ('upload', 'capture')),
+    CONSTRAINT valid_thumbnail_content_type CHECK (content_type IS NULL OR content_type = 'image/jpeg'),
+    CONSTRAINT valid_thumbnail_size CHECK (size_bytes IS NULL OR size_bytes <= 1048576)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_thumbnails_captured ON asset_thumbnails (captured_at) WHERE source = 'capture';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_asset_thumbnails_captured;
+DROP TABLE IF EXISTS asset_thumbnails;
@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    query VARCHAR(256) NOT NULL DEFAULT '',
+    result_types TEXT[] NOT NULL DEFAULT '{}',
+    asset_types TEXT[] NOT NULL DEFAULT '{}',
+    providers TEXT[] NOT NULL DEFAULT '{}',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- NULL until the first check after the search is created or its
+    -- filters change. That check records the current matches without
+    -- notifying anyone.
+    last_checked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_created_by ON saved_searches (created_by);
+
+CREATE TABLE IF NOT EXISTS saved_search_teams (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    PRIMARY KEY (saved_search_id, team_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_teams_team ON saved_search_teams (team_id);
+
+CREATE TABLE IF NOT EXISTS saved_search_subscriptions (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_subscriptions_user ON saved_search_subscriptions (user_id);
+
+-- Assets already known to match a saved search, so subscribers are only
+-- told about new ones.
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    asset_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    matched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_asset ON saved_search_matches (asset_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_search_subscriptions;
+DROP TABLE IF EXISTS saved_search_teams;
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS asset_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    changed_fields TEXT[] NOT NULL,
+    -- Field name -> {"old": ..., "new": ...} for each changed field.
+    changes JSONB NOT NULL,
+    changed_by VARCHAR(255),
+    source VARCHAR(20) NOT NULL DEFAULT 'system' CHECK (source IN ('user', 'ingestion', 'system')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_revisions_asset ON asset_revisions (asset_id, id DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_revisions;
@@ -0,0 +1,49 @@
+-- Outbox of search_index writes. The change feed drains it to keep an
+-- external search index in step, and NOTIFY tells every server to drop its
+-- cached summaries and facets.
+CREATE TABLE IF NOT EXISTS search_index_changes (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION search_index_change_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed search_index%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    INSERT INTO search_index_changes (type, entity_id)
+    VALUES (changed.type, changed.entity_id);
+
+    -- Identical notifications within a transaction are delivered once, so
+    -- a bulk write sends one per entity type.
+    PERFORM pg_notify('search_index_changes', changed.type);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_change_insert_delete ON search_index;
+CREATE TRIGGER search_index_change_insert_delete
+    AFTER INSERT OR DELETE ON search_index
+    FOR EACH ROW EXECUTE FUNCTION search_index_change_trigger();
+
+DROP TRIGGER IF EXISTS search_index_change_update ON search_index;
+CREATE TRIGGER search_index_change_update
+    AFTER UPDATE ON search_index
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.*)
+    EXECUTE FUNCTION search_index_change_trigger();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS search_index_change_update ON search_index;
+DROP TRIGGER IF EXISTS search_index_change_insert_delete ON search_index;
+DROP FUNCTION IF EXISTS search_index_change_trigger();
+DROP TABLE IF EXISTS search_index_changes;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS data_product_drafts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Step data saved so far: details, owners, rules and assets, and SLA.
+    content JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    published_product_id UUID REFERENCES data_products(id) ON DELETE SET NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_product_drafts_created_by ON data_product_drafts (created_by, updated_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS data_product_drafts;
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_run_entities_entity_mrn ON run_entities (entity_mrn, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_run_entities_entity_mrn;
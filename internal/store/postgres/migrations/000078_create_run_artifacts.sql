@@ -0,0 +1,21 @@
+-- Raw payloads emitted by plugins, kept for debugging when artifact
+-- capture is enabled. data is gzip-compressed JSON.
+CREATE TABLE IF NOT EXISTS run_artifacts (
+    run_id UUID NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    sequence INTEGER NOT NULL,
+    asset_count INTEGER NOT NULL DEFAULT 0,
+    lineage_count INTEGER NOT NULL DEFAULT 0,
+    documentation_count INTEGER NOT NULL DEFAULT 0,
+    statistic_count INTEGER NOT NULL DEFAULT 0,
+    size_bytes INTEGER NOT NULL,
+    raw_size_bytes INTEGER NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_run_artifacts_created_at ON run_artifacts (created_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS run_artifacts;
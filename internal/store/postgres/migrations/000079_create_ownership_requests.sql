@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS asset_ownership_requests (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id      VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    kind          VARCHAR(20) NOT NULL CHECK (kind IN ('ownership', 'access')),
+    requested_by  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Ownership can be requested on behalf of a team the requester belongs to.
+    team_id       UUID REFERENCES teams(id) ON DELETE CASCADE,
+    reason        TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending'
+                  CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    decided_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    decided_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (team_id IS NULL OR kind = 'ownership')
+);
+
+-- One open request per asset, requester, kind and team.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_ownership_requests_pending
+    ON asset_ownership_requests (asset_id, requested_by, kind, COALESCE(team_id, '00000000-0000-0000-0000-000000000000'::uuid))
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_asset_ownership_requests_asset ON asset_ownership_requests (asset_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_ownership_requests_requester ON asset_ownership_requests (requested_by, created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_ownership_requests;
@@ -0,0 +1,12 @@
+-- A schedule can run as a service account, which its runs and the assets
+-- they create are attributed to instead of a person or "scheduler".
+ALTER TABLE ingestion_schedules
+    ADD COLUMN IF NOT EXISTS service_account_id UUID REFERENCES service_accounts(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_schedules_service_account
+    ON ingestion_schedules(service_account_id) WHERE service_account_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_ingestion_schedules_service_account;
+ALTER TABLE ingestion_schedules DROP COLUMN IF EXISTS service_account_id;
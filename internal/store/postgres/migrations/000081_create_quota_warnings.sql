@@ -0,0 +1,11 @@
+-- Quota warnings already sent to admins, so each limit is only notified
+-- once per status change.
+CREATE TABLE IF NOT EXISTS quota_warnings (
+    key         TEXT PRIMARY KEY,
+    status      TEXT NOT NULL,
+    notified_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS quota_warnings;
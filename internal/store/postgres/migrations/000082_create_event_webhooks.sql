@@ -0,0 +1,38 @@
+-- Admin webhooks that receive catalog events, signed with a shared secret.
+CREATE TABLE IF NOT EXISTS event_webhooks (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        VARCHAR(255) NOT NULL,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    events      JSONB NOT NULL DEFAULT '[]',
+    enabled     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Every attempt to send an event to a webhook. Pending deliveries are
+-- retried once next_attempt_at has passed.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id      UUID NOT NULL REFERENCES event_webhooks(id) ON DELETE CASCADE,
+    event_id        UUID NOT NULL,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created ON webhook_deliveries(created_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS event_webhooks;
@@ -0,0 +1,47 @@
+-- Column policy tags inherited along column lineage. Rebuilt in full by the
+-- propagation job; origin_* is the tagged upstream column it came from.
+CREATE TABLE IF NOT EXISTS inherited_policy_tags (
+    asset_id        VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    policy_tag_id   UUID NOT NULL REFERENCES policy_tags(id) ON DELETE CASCADE,
+    column_name     VARCHAR(255) NOT NULL,
+    origin_asset_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    origin_column   VARCHAR(255) NOT NULL,
+    hops            INTEGER NOT NULL,
+    computed_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, policy_tag_id, column_name)
+);
+
+-- A column explicitly cleared of a tag it would otherwise inherit.
+CREATE TABLE IF NOT EXISTS policy_tag_overrides (
+    asset_id      VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    policy_tag_id UUID NOT NULL REFERENCES policy_tags(id) ON DELETE CASCADE,
+    column_name   VARCHAR(255) NOT NULL,
+    reason        TEXT,
+    created_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_tag_overrides_column
+    ON policy_tag_overrides (asset_id, policy_tag_id, lower(column_name));
+
+-- policy_tag_name is kept so entries stay readable after the tag is deleted.
+CREATE TABLE IF NOT EXISTS policy_tag_override_audit (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id        VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    column_name     VARCHAR(255) NOT NULL,
+    policy_tag_id   UUID REFERENCES policy_tags(id) ON DELETE SET NULL,
+    policy_tag_name VARCHAR(100) NOT NULL,
+    action          VARCHAR(10) NOT NULL CHECK (action IN ('cleared', 'restored')),
+    reason          TEXT,
+    actor           UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_tag_override_audit_asset
+    ON policy_tag_override_audit (asset_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS policy_tag_override_audit;
+DROP TABLE IF EXISTS policy_tag_overrides;
+DROP TABLE IF EXISTS inherited_policy_tags;
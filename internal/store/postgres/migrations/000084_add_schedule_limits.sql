@@ -0,0 +1,12 @@
+-- max_concurrent_runs caps the runs of a schedule in progress at once, 0
+-- meaning no limit. Runs beyond it stay pending until one finishes.
+-- timeout_seconds aborts runs that take longer; NULL means no timeout.
+ALTER TABLE ingestion_schedules
+    ADD COLUMN IF NOT EXISTS max_concurrent_runs INTEGER NOT NULL DEFAULT 1 CHECK (max_concurrent_runs >= 0),
+    ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER CHECK (timeout_seconds > 0);
+
+---- create above / drop below ----
+
+ALTER TABLE ingestion_schedules
+    DROP COLUMN IF EXISTS timeout_seconds,
+    DROP COLUMN IF EXISTS max_concurrent_runs;
@@ -0,0 +1,18 @@
+-- Daily rollup of how often each glossary term shows up in search results,
+-- is opened, and is clicked through to from a search.
+CREATE TABLE IF NOT EXISTS glossary_term_usage (
+    term_id    UUID NOT NULL REFERENCES glossary_terms(id) ON DELETE CASCADE,
+    day        DATE NOT NULL,
+    searches   BIGINT NOT NULL DEFAULT 0,
+    views      BIGINT NOT NULL DEFAULT 0,
+    clicks     BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (term_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_glossary_term_usage_day
+    ON glossary_term_usage (day);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS glossary_term_usage;
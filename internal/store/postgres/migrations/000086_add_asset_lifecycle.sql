@@ -0,0 +1,16 @@
+-- Where an asset is in its lifecycle. Deprecated assets keep their reason
+-- and replacement in asset_deprecations.
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS lifecycle_status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (lifecycle_status IN ('active', 'experimental', 'deprecated', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_assets_lifecycle_status
+    ON assets (lifecycle_status) WHERE lifecycle_status <> 'active';
+
+UPDATE assets a SET lifecycle_status = 'deprecated'
+FROM asset_deprecations d
+WHERE d.asset_id = a.id;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_assets_lifecycle_status;
+ALTER TABLE assets DROP COLUMN IF EXISTS lifecycle_status;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS asset_favorites (
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    asset_id   VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_favorites_asset ON asset_favorites(asset_id);
+
+-- Recent asset views per user, used to personalise search ranking.
+CREATE TABLE IF NOT EXISTS asset_recent_views (
+    user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    asset_id       VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    view_count     BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_recent_views_last_viewed ON asset_recent_views(last_viewed_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_recent_views;
+DROP TABLE IF EXISTS asset_favorites;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS asset_types (
+    name            VARCHAR(100) PRIMARY KEY,
+    icon            VARCHAR(255) NOT NULL DEFAULT '',
+    description     TEXT NOT NULL DEFAULT '',
+    -- Metadata fields assets of this type are expected to have.
+    metadata_schema JSONB NOT NULL DEFAULT '[]',
+    updated_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_types_name_lower ON asset_types (LOWER(name));
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_types;
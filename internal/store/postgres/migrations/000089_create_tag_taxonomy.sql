@@ -0,0 +1,38 @@
+-- Namespaces of managed tags, e.g. the pii in pii.email.
+CREATE TABLE IF NOT EXISTS tag_categories (
+    name        VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    -- Whether assets may only use managed tags in this category.
+    enforced    BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS managed_tags (
+    name        VARCHAR(255) PRIMARY KEY,
+    -- The part of the name before the first dot, if the tag has one.
+    category    VARCHAR(100) REFERENCES tag_categories(name) ON DELETE RESTRICT,
+    description TEXT NOT NULL DEFAULT '',
+    updated_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_managed_tags_category ON managed_tags (category);
+
+CREATE TABLE IF NOT EXISTS tag_taxonomy_settings (
+    id         INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    -- Whether assets may only use managed tags.
+    enforce    BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tag_taxonomy_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS tag_taxonomy_settings;
+DROP TABLE IF EXISTS managed_tags;
+DROP TABLE IF EXISTS tag_categories;
@@ -0,0 +1,20 @@
+-- Results of data quality checks run against assets, reported by external
+-- tools such as dbt tests or Great Expectations. Asset health uses the
+-- latest result of each check.
+CREATE TABLE IF NOT EXISTS asset_quality_results (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id    VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    check_name  VARCHAR(255) NOT NULL,
+    status      VARCHAR(20) NOT NULL CHECK (status IN ('passed', 'warning', 'failed')),
+    message     TEXT NOT NULL DEFAULT '',
+    checked_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    reported_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_quality_results_latest
+    ON asset_quality_results (asset_id, check_name, checked_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_quality_results;
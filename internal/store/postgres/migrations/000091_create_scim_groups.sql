@@ -0,0 +1,18 @@
+-- Teams provisioned through SCIM. The team is either created for the group
+-- or, when an SSO mapping or a team of the same name exists, reused.
+CREATE TABLE IF NOT EXISTS scim_groups (
+    team_id      UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    -- The group's name in the identity provider, which may differ from
+    -- the name of a reused team.
+    display_name VARCHAR(255) NOT NULL,
+    external_id  VARCHAR(255),
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scim_groups_display_name ON scim_groups (display_name);
+CREATE INDEX IF NOT EXISTS idx_scim_groups_external_id ON scim_groups (external_id) WHERE external_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS scim_groups;
@@ -0,0 +1,20 @@
+-- Assets, data products and glossary terms users watch. Each row references
+-- exactly one entity so watches are removed with it.
+CREATE TABLE IF NOT EXISTS watches (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    asset_id         VARCHAR(255) REFERENCES assets(id) ON DELETE CASCADE,
+    data_product_id  UUID REFERENCES data_products(id) ON DELETE CASCADE,
+    glossary_term_id UUID REFERENCES glossary_terms(id) ON DELETE CASCADE,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(asset_id, data_product_id, glossary_term_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watches_asset ON watches (asset_id, user_id) WHERE asset_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watches_data_product ON watches (data_product_id, user_id) WHERE data_product_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watches_glossary_term ON watches (glossary_term_id, user_id) WHERE glossary_term_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_watches_user ON watches (user_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS watches;
@@ -0,0 +1,24 @@
+-- Pairs of assets that look like the same thing under different MRNs,
+-- flagged as runs create them, for an administrator to merge or dismiss.
+-- A pair is recorded once whichever asset came first, so a dismissed pair
+-- stays dismissed.
+CREATE TABLE IF NOT EXISTS asset_duplicates (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id        VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    duplicate_of_id VARCHAR(255) NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    similarity      DOUBLE PRECISION NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed')),
+    detected_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_by     VARCHAR(255),
+    reviewed_at     TIMESTAMP WITH TIME ZONE,
+    CHECK (asset_id <> duplicate_of_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_duplicates_pair
+    ON asset_duplicates (LEAST(asset_id, duplicate_of_id), GREATEST(asset_id, duplicate_of_id));
+CREATE INDEX IF NOT EXISTS idx_asset_duplicates_status ON asset_duplicates (status, detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_duplicates_duplicate_of ON asset_duplicates (duplicate_of_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS asset_duplicates;
@@ -0,0 +1,91 @@
+-- Column names from asset schemas are kept in search_index so searches find
+-- assets by their columns: @column:email matches the lowercased names in
+-- column_names, and the names are added to search_text at weight D so
+-- free text matches them too.
+CREATE OR REPLACE FUNCTION schema_column_names(doc JSONB)
+RETURNS TEXT[] AS $$
+DECLARE
+    names TEXT[] := '{}';
+    entry RECORD;
+    body JSONB;
+BEGIN
+    FOR entry IN SELECT value FROM jsonb_each(resolve_schema_blobs(doc)) LOOP
+        body := entry.value;
+        IF jsonb_typeof(body) = 'string' THEN
+            BEGIN
+                body := (body #>> '{}')::jsonb;
+            EXCEPTION WHEN OTHERS THEN
+                -- Not JSON, such as SQL DDL or a protobuf definition.
+                CONTINUE;
+            END;
+        END IF;
+
+        -- JSON Schema properties, at any depth.
+        names := names || ARRAY(
+            SELECT jsonb_object_keys(p)
+            FROM jsonb_path_query(body, 'lax $.**.properties') p
+            WHERE jsonb_typeof(p) = 'object'
+        );
+        -- Avro record fields, at any depth.
+        names := names || ARRAY(
+            SELECT f #>> '{}'
+            FROM jsonb_path_query(body, 'lax $.**.fields.name') f
+            WHERE jsonb_typeof(f) = 'string'
+        );
+        -- Lists of columns, as dbt and the warehouse plugins send.
+        IF jsonb_typeof(body) = 'array' THEN
+            names := names || ARRAY(
+                SELECT c ->> 'name'
+                FROM jsonb_array_elements(body) c
+                WHERE jsonb_typeof(c) = 'object' AND jsonb_typeof(c -> 'name') = 'string'
+            );
+        END IF;
+    END LOOP;
+
+    RETURN ARRAY(SELECT DISTINCT lower(n) FROM unnest(names) n WHERE n <> '' ORDER BY 1);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE search_index ADD COLUMN IF NOT EXISTS column_names TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_search_index_column_names ON search_index USING gin(column_names);
+
+-- Column names go in at weight D like accepted answers. Triggers on the
+-- same event fire in name order, so this runs after
+-- search_index_accepted_answers has filtered out the old weight D text and
+-- only needs to append.
+CREATE OR REPLACE FUNCTION search_index_append_column_names()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.type <> 'asset' THEN
+        RETURN NEW;
+    END IF;
+
+    NEW.column_names := COALESCE(
+        (SELECT schema_column_names(schema) FROM assets WHERE id = NEW.entity_id), '{}');
+
+    IF cardinality(NEW.column_names) > 0 THEN
+        NEW.search_text := NEW.search_text ||
+            setweight(to_tsvector('english', array_to_string(NEW.column_names, ' ')), 'D');
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER search_index_column_names
+    BEFORE INSERT OR UPDATE OF search_text ON search_index
+    FOR EACH ROW EXECUTE FUNCTION search_index_append_column_names();
+
+-- Rewriting search_text fires the triggers for existing assets.
+UPDATE search_index SET search_text = search_text WHERE type = 'asset';
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS search_index_column_names ON search_index;
+DROP FUNCTION IF EXISTS search_index_append_column_names();
+
+UPDATE search_index SET search_text = search_text WHERE type = 'asset';
+
+DROP INDEX IF EXISTS idx_search_index_column_names;
+ALTER TABLE search_index DROP COLUMN IF EXISTS column_names;
+DROP FUNCTION IF EXISTS schema_column_names(JSONB);
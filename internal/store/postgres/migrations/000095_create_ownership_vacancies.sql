@@ -0,0 +1,15 @@
+-- Assets and data products whose owners have all been deactivated or left,
+-- so admins are alerted once when ownership lapses and reminded until it
+-- is reassigned. Rows are removed once an active owner is back.
+CREATE TABLE IF NOT EXISTS ownership_vacancies (
+    entity_type  TEXT NOT NULL CHECK (entity_type IN ('asset', 'data_product')),
+    entity_id    TEXT NOT NULL,
+    detected_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notified_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reminders    INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (entity_type, entity_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS ownership_vacancies;
@@ -0,0 +1,356 @@
+-- Assets archived by the archival policy stay in the assets table, hidden
+-- from search and listings, so restoring one keeps its ID, owners, lineage
+-- and history. The search triggers treat them like stubs.
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_assets_archived_at
+    ON assets(archived_at) WHERE archived_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION sync_asset_search_on_insert()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- entity_type count
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- type dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- provider dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- tag dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_asset_search_on_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Decrement old counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND archived_at IS NULL
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM asset_tags WHERE asset_id IN (SELECT id FROM deleted);
+
+    -- Insert new data
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- Increment new counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND archived_at IS NULL AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM search_index
+    WHERE type = 'asset' AND entity_id IN (
+        SELECT id FROM inserted WHERE is_stub = TRUE OR archived_at IS NOT NULL
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+-- Bring archived assets back into search before the column goes.
+UPDATE assets SET archived_at = NULL WHERE archived_at IS NOT NULL;
+
+-- Restore the asset search triggers from 000070
+CREATE OR REPLACE FUNCTION sync_asset_search_on_insert()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- entity_type count
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- type dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- provider dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    -- tag dimension
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_asset_search_on_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Decrement old counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), -COUNT(*)
+    FROM deleted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM asset_tags WHERE asset_id IN (SELECT id FROM deleted);
+
+    -- Insert new data
+    INSERT INTO search_index (
+        type, entity_id, name, description, search_text, updated_at,
+        asset_type, primary_provider, providers, tags, url_path, mrn,
+        created_by, created_at, metadata
+    )
+    SELECT
+        'asset', id, name, COALESCE(user_description, description),
+        search_text, updated_at,
+        type, providers[1], providers, tags,
+        '/discover/' || LOWER(type) || '/' ||
+            CASE WHEN array_length(providers, 1) > 0 THEN providers[1] ELSE 'unknown' END ||
+            '/' || COALESCE(SUBSTRING(mrn FROM 'mrn://[^/]+/[^/]+/(.+)'), id),
+        mrn,
+        created_by, created_at, asset_search_metadata(id, metadata)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (type, entity_id) DO UPDATE SET
+        name = EXCLUDED.name, description = EXCLUDED.description,
+        search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at,
+        asset_type = EXCLUDED.asset_type, primary_provider = EXCLUDED.primary_provider,
+        providers = EXCLUDED.providers, tags = EXCLUDED.tags, url_path = EXCLUDED.url_path,
+        mrn = EXCLUDED.mrn,
+        created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at,
+        metadata = EXCLUDED.metadata;
+
+    INSERT INTO asset_tags (asset_id, tag)
+    SELECT id, unnest(tags)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    ON CONFLICT DO NOTHING;
+
+    -- Increment new counts
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'entity_type', 'asset', COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'type', type, COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND type IS NOT NULL
+    GROUP BY type
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'provider', unnest(providers), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND providers IS NOT NULL AND array_length(providers, 1) > 0
+    GROUP BY unnest(providers)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    INSERT INTO summary_counts (dimension, key, count)
+    SELECT 'tag', unnest(tags), COUNT(*)
+    FROM inserted
+    WHERE is_stub = FALSE AND tags IS NOT NULL AND array_length(tags, 1) > 0
+    GROUP BY unnest(tags)
+    ON CONFLICT (dimension, key) DO UPDATE SET count = summary_counts.count + EXCLUDED.count;
+
+    DELETE FROM search_index
+    WHERE type = 'asset' AND entity_id IN (SELECT id FROM inserted WHERE is_stub = TRUE);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_assets_archived_at;
+ALTER TABLE assets DROP COLUMN IF EXISTS archived_at;
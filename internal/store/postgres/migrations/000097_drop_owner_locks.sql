@@ -0,0 +1,9 @@
+-- Owners can no longer be locked; ingestion never sets them.
+UPDATE assets
+SET locked_fields = array_remove(locked_fields, 'owners')
+WHERE 'owners' = ANY(locked_fields);
+
+---- create above / drop below ----
+
+-- Owner locks had no effect, so there is nothing to restore.
+SELECT 1;
@@ -0,0 +1,106 @@
+-- Queue search_index writes only while a server with an external search
+-- index is draining the queue, and announce them once per statement and
+-- entity type rather than once per row. Consumers renew their row every
+-- poll, so one that stops without unregistering is ignored after ten
+-- minutes.
+CREATE TABLE IF NOT EXISTS search_index_change_consumers (
+    id TEXT PRIMARY KEY,
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+DROP TRIGGER IF EXISTS search_index_change_update ON search_index;
+DROP TRIGGER IF EXISTS search_index_change_insert_delete ON search_index;
+DROP FUNCTION IF EXISTS search_index_change_trigger();
+
+CREATE OR REPLACE FUNCTION search_index_statement_change_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed_types TEXT[];
+    changed_ids TEXT[];
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        SELECT array_agg(type), array_agg(entity_id)
+        INTO changed_types, changed_ids
+        FROM new_rows;
+    ELSIF TG_OP = 'DELETE' THEN
+        SELECT array_agg(type), array_agg(entity_id)
+        INTO changed_types, changed_ids
+        FROM old_rows;
+    ELSE
+        SELECT array_agg(n.type), array_agg(n.entity_id)
+        INTO changed_types, changed_ids
+        FROM new_rows n
+        JOIN old_rows o ON o.type = n.type AND o.entity_id = n.entity_id
+        WHERE ROW(o.*) IS DISTINCT FROM ROW(n.*);
+    END IF;
+
+    IF changed_types IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM search_index_change_consumers
+        WHERE last_seen > NOW() - INTERVAL '10 minutes'
+    ) THEN
+        INSERT INTO search_index_changes (type, entity_id)
+        SELECT * FROM unnest(changed_types, changed_ids);
+    END IF;
+
+    PERFORM pg_notify('search_index_changes', t)
+    FROM (SELECT DISTINCT unnest(changed_types) AS t) types;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER search_index_change_insert
+    AFTER INSERT ON search_index
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION search_index_statement_change_trigger();
+
+CREATE TRIGGER search_index_change_update
+    AFTER UPDATE ON search_index
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION search_index_statement_change_trigger();
+
+CREATE TRIGGER search_index_change_delete
+    AFTER DELETE ON search_index
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION search_index_statement_change_trigger();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS search_index_change_delete ON search_index;
+DROP TRIGGER IF EXISTS search_index_change_update ON search_index;
+DROP TRIGGER IF EXISTS search_index_change_insert ON search_index;
+DROP FUNCTION IF EXISTS search_index_statement_change_trigger();
+
+CREATE OR REPLACE FUNCTION search_index_change_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed search_index%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    INSERT INTO search_index_changes (type, entity_id)
+    VALUES (changed.type, changed.entity_id);
+
+    PERFORM pg_notify('search_index_changes', changed.type);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER search_index_change_insert_delete
+    AFTER INSERT OR DELETE ON search_index
+    FOR EACH ROW EXECUTE FUNCTION search_index_change_trigger();
+
+CREATE TRIGGER search_index_change_update
+    AFTER UPDATE ON search_index
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.*)
+    EXECUTE FUNCTION search_index_change_trigger();
+
+DROP TABLE IF EXISTS search_index_change_consumers;
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// countQuery is a named count included in the feature usage report. Queries
+// return a single count and never read names, descriptions or other values.
+type countQuery struct {
+	name  string
+	query string
+}
+
+// featureQueries count how much each optional feature is used.
+var featureQueries = []countQuery{
+	{"glossary_terms", "SELECT COUNT(*) FROM glossary_terms"},
+	{"data_products", "SELECT COUNT(*) FROM data_products"},
+	{"teams", "SELECT COUNT(*) FROM teams"},
+	{"schedules", "SELECT COUNT(*) FROM ingestion_schedules"},
+	{"connections", "SELECT COUNT(*) FROM connections"},
+	{"service_accounts", "SELECT COUNT(*) FROM service_accounts"},
+	{"asset_subscriptions", "SELECT COUNT(*) FROM asset_subscriptions"},
+	{"watches", "SELECT COUNT(*) FROM watches"},
+	{"saved_searches", "SELECT COUNT(*) FROM saved_searches"},
+	{"saved_queries", "SELECT COUNT(*) FROM saved_queries"},
+	{"team_webhooks", "SELECT COUNT(*) FROM team_webhooks"},
+	{"event_webhooks", "SELECT COUNT(*) FROM event_webhooks"},
+	{"asset_rules", "SELECT COUNT(*) FROM asset_rules"},
+	{"policy_tags", "SELECT COUNT(*) FROM policy_tags"},
+	{"doc_pages", "SELECT COUNT(*) FROM doc_pages"},
+	{"asset_questions", "SELECT COUNT(*) FROM asset_questions"},
+	{"asset_deprecations", "SELECT COUNT(*) FROM asset_deprecations"},
+	{"share_links", "SELECT COUNT(*) FROM asset_share_links"},
+	{"agent_runs", "SELECT COUNT(*) FROM agent_runs"},
+}
+
+// scaleQueries describe the size and shape of the catalog.
+var scaleQueries = []countQuery{
+	{"asset_types", "SELECT COUNT(DISTINCT type) FROM assets"},
+	{"providers", "SELECT COUNT(DISTINCT provider) FROM assets, UNNEST(providers) AS provider"},
+	{"documented_assets", "SELECT COUNT(*) FROM assets WHERE COALESCE(description, '') <> ''"},
+	{"owned_assets", "SELECT COUNT(DISTINCT asset_id) FROM asset_owners"},
+	{"column_lineage_edges", "SELECT COUNT(*) FROM column_lineage"},
+	{"runs", "SELECT COUNT(*) FROM runs"},
+}
+
+// FeatureUsage is the opt-in part of the payload, sent only when
+// telemetry.feature_usage is enabled. It holds counts only.
+type FeatureUsage struct {
+	Features map[string]int `json:"features"`
+	Scale    map[string]int `json:"scale"`
+}
+
+func (c *Collector) collectFeatureUsage(ctx context.Context) *FeatureUsage {
+	return &FeatureUsage{
+		Features: c.runCounts(ctx, featureQueries),
+		Scale:    c.runCounts(ctx, scaleQueries),
+	}
+}
+
+// runCounts runs each query, leaving out any that fail so one missing table
+// doesn't drop the whole report.
+func (c *Collector) runCounts(ctx context.Context, queries []countQuery) map[string]int {
+	counts := make(map[string]int, len(queries))
+	for _, q := range queries {
+		var n int
+		if err := c.db.QueryRow(ctx, q.query).Scan(&n); err != nil {
+			log.Trace().Err(err).Str("count", q.name).Msg("telemetry: failed to count feature usage")
+			continue
+		}
+		counts[q.name] = n
+	}
+	return counts
+}
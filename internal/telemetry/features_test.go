@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureQueriesOnlyCount(t *testing.T) {
+	seen := map[string]bool{}
+	for _, q := range append(append([]countQuery{}, featureQueries...), scaleQueries...) {
+		assert.False(t, seen[q.name], "duplicate count %q", q.name)
+		seen[q.name] = true
+		assert.True(t, strings.HasPrefix(q.query, "SELECT COUNT("), "%s must select a single count", q.name)
+	}
+}
+
+func TestPayloadOmitsFeatureUsageUnlessCollected(t *testing.T) {
+	body, err := json.Marshal(Payload{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "feature_usage")
+
+	body, err = json.Marshal(Payload{FeatureUsage: &FeatureUsage{
+		Features: map[string]int{"glossary_terms": 3},
+		Scale:    map[string]int{"asset_types": 2},
+	}})
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"feature_usage":{"features":{"glossary_terms":3},"scale":{"asset_types":2}}`)
+}
@@ -29,6 +29,7 @@ type Payload struct {
 	LineageEdges    int              `json:"lineage_edges"`
 	ConnectorCounts map[string]int   `json:"connector_counts"`
 	Lookups         lookups.Snapshot `json:"lookups,omitempty"`
+	FeatureUsage    *FeatureUsage    `json:"feature_usage,omitempty"`
 }
 
 // CollectorConfig holds configuration for the telemetry collector.
@@ -37,6 +38,9 @@ type CollectorConfig struct {
 	Endpoint string
 	Interval time.Duration
 	Version  string
+	// FeatureUsage adds counts of feature usage and catalog scale to the
+	// payload. It is opt-in and off by default.
+	FeatureUsage bool
 }
 
 // Collector gathers anonymous usage data and sends it periodically.
@@ -127,6 +131,30 @@ func GetOrCreateInstallID(ctx context.Context, db *pgxpool.Pool) (string, error)
 	return id, nil
 }
 
+// Preview is what the collector would send next, for operators to inspect.
+type Preview struct {
+	Enabled      bool    `json:"enabled"`
+	FeatureUsage bool    `json:"feature_usage"`
+	Endpoint     string  `json:"endpoint"`
+	Payload      Payload `json:"payload"`
+} // @name TelemetryPreview
+
+// Preview builds the payload the next report would carry, without sending it
+// or creating an install ID. When telemetry is disabled nothing is sent, but
+// the preview still shows what enabling it would report.
+func (c *Collector) Preview(ctx context.Context) Preview {
+	var installID string
+	_ = c.db.QueryRow(ctx, "SELECT id FROM telemetry_install LIMIT 1").Scan(&installID)
+
+	payload, _ := c.buildPayload(ctx, installID)
+	return Preview{
+		Enabled:      c.config.Enabled,
+		FeatureUsage: c.config.FeatureUsage,
+		Endpoint:     c.config.Endpoint,
+		Payload:      payload,
+	}
+}
+
 func (c *Collector) buildPayload(ctx context.Context, installID string) (Payload, lookups.Snapshot) {
 	p := Payload{
 		InstallID:      installID,
@@ -156,6 +184,10 @@ func (c *Collector) buildPayload(ctx context.Context, installID string) (Payload
 		}
 	}
 
+	if c.config.FeatureUsage {
+		p.FeatureUsage = c.collectFeatureUsage(ctx)
+	}
+
 	var lookupDeltas lookups.Snapshot
 	if c.lookupsStore != nil {
 		if snap, err := c.lookupsStore.UnreportedDeltas(ctx, installID); err != nil {
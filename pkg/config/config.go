@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
 	"sync"
 
@@ -13,6 +14,32 @@ type AnonymousAuthConfig struct {
 	Role    string `mapstructure:"role"`
 }
 
+// AuthorizationConfig configures hooks that can deny API requests after
+// Marmot's role based permission check has allowed them.
+type AuthorizationConfig struct {
+	OPA OPAConfig `mapstructure:"opa"`
+}
+
+type OPAConfig struct {
+	// URL is the full decision endpoint, e.g.
+	// http://localhost:8181/v1/data/marmot/authz. Empty disables OPA.
+	URL     string `mapstructure:"url"`
+	Timeout int    `mapstructure:"timeout"` // seconds
+	// FailOpen allows requests when OPA can't be reached. By default
+	// they are rejected.
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// SCIMConfig configures the SCIM 2.0 endpoint identity providers use to
+// provision users and teams.
+type SCIMConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Token is the bearer token identity providers authenticate with.
+	Token string `mapstructure:"token"`
+	// DefaultRoles are given to users SCIM creates.
+	DefaultRoles []string `mapstructure:"default_roles"`
+}
+
 type RateLimitConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 }
@@ -65,6 +92,10 @@ type Config struct {
 		EncryptionKey         string            `mapstructure:"encryption_key"`
 		AllowUnencrypted      bool              `mapstructure:"allow_unencrypted"`
 		TLS                   *TLSConfig        `mapstructure:"tls"`
+		// TrustedProxies lists the IPs or CIDR ranges of reverse proxies
+		// whose X-Forwarded-For header is believed. Empty means the
+		// connection's address is always used.
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	} `mapstructure:"server"`
 
 	Metrics struct {
@@ -104,6 +135,9 @@ type Config struct {
 		Slack       *OAuthProviderConfig `mapstructure:"slack"`
 		Auth0       *OAuthProviderConfig `mapstructure:"auth0"`
 		Anonymous   AnonymousAuthConfig  `mapstructure:"anonymous"`
+		SCIM        SCIMConfig           `mapstructure:"scim"`
+
+		Authorization AuthorizationConfig `mapstructure:"authorization"`
 	} `mapstructure:"auth"`
 
 	OpenLineage struct {
@@ -119,8 +153,15 @@ type Config struct {
 	} `mapstructure:"ui"`
 
 	Search struct {
-		Timeout       int                  `mapstructure:"timeout"` // seconds
-		Elasticsearch *ElasticsearchConfig `mapstructure:"elasticsearch"`
+		Timeout            int                  `mapstructure:"timeout"`           // seconds
+		StatementTimeout   int                  `mapstructure:"statement_timeout"` // milliseconds
+		MaxResults         int                  `mapstructure:"max_results"`
+		MaxOffset          int                  `mapstructure:"max_offset"`
+		FacetLoadThreshold float64              `mapstructure:"facet_load_threshold"`
+		FacetCacheTTL      int                  `mapstructure:"facet_cache_ttl"` // seconds, 0 disables
+		Elasticsearch      *ElasticsearchConfig `mapstructure:"elasticsearch"`
+		// Defaults scope searches that don't set these themselves.
+		Defaults SearchDefaultsConfig `mapstructure:"defaults"`
 	} `mapstructure:"search"`
 
 	Pipelines struct {
@@ -128,6 +169,22 @@ type Config struct {
 		SchedulerInterval int `mapstructure:"scheduler_interval"`
 		LeaseExpiry       int `mapstructure:"lease_expiry"`
 		ClaimExpiry       int `mapstructure:"claim_expiry"`
+		// CheckpointHistory is how many checkpoints are kept per entity and
+		// pipeline when run checkpoints are compacted.
+		CheckpointHistory            int `mapstructure:"checkpoint_history"`
+		CheckpointCompactionInterval int `mapstructure:"checkpoint_compaction_interval"` // seconds, 0 disables
+		// StoreArtifacts keeps the raw payload each run emits, compressed,
+		// for ArtifactRetentionDays so operators can inspect what a plugin sent.
+		StoreArtifacts        bool `mapstructure:"store_artifacts"`
+		ArtifactRetentionDays int  `mapstructure:"artifact_retention_days"`
+		ArtifactMaxSizeMB     int  `mapstructure:"artifact_max_size_mb"` // compressed, larger payloads are skipped
+		// BatchSize is how many entities a run writes to the database per
+		// statement while ingesting.
+		BatchSize int `mapstructure:"batch_size"`
+		// DuplicateSimilarity is the share of schema columns a new asset must
+		// have in common with an existing asset of the same name to be
+		// flagged as a possible duplicate. 0 turns detection off.
+		DuplicateSimilarity float64 `mapstructure:"duplicate_similarity"`
 	} `mapstructure:"pipelines"`
 
 	Operator struct {
@@ -140,12 +197,161 @@ type Config struct {
 		Enabled  bool   `mapstructure:"enabled"`
 		Endpoint string `mapstructure:"endpoint"`
 		Interval int    `mapstructure:"interval"` // seconds
+		// FeatureUsage adds counts of feature usage and catalog scale to
+		// telemetry reports. Opt-in, and only sent while Enabled is set.
+		FeatureUsage bool `mapstructure:"feature_usage"`
 	} `mapstructure:"telemetry"`
 
 	Experimental struct {
 		TablePreview bool `mapstructure:"table_preview"`
 	} `mapstructure:"experimental"`
 
+	// Features switches whole subsystems off. Disabled features have no API
+	// routes and are hidden in the UI.
+	Features struct {
+		Glossary     bool `mapstructure:"glossary"`
+		DataProducts bool `mapstructure:"data_products"`
+		Scheduling   bool `mapstructure:"scheduling"`
+		AI           bool `mapstructure:"ai"`
+	} `mapstructure:"features"`
+
+	TagSync struct {
+		Interval  int                     `mapstructure:"interval"` // seconds
+		DryRun    bool                    `mapstructure:"dry_run"`
+		Snowflake  *SnowflakeTagSyncConfig  `mapstructure:"snowflake"`
+		BigQuery   *BigQueryTagSyncConfig   `mapstructure:"bigquery"`
+		AWS        *AWSTagSyncConfig        `mapstructure:"aws"`
+		GCS        *GCSTagSyncConfig        `mapstructure:"gcs"`
+		Kubernetes *KubernetesTagSyncConfig `mapstructure:"kubernetes"`
+	} `mapstructure:"tag_sync"`
+
+	Archival struct {
+		Enabled         bool `mapstructure:"enabled"`
+		StaleAfterDays  int  `mapstructure:"stale_after_days"`
+		GracePeriodDays int  `mapstructure:"grace_period_days"`
+		Interval        int  `mapstructure:"interval"` // seconds
+		// Expiry archives assets whose ttl_seconds metadata has run out.
+		// It runs independently of the stale asset policy.
+		ExpiryEnabled  bool `mapstructure:"expiry_enabled"`
+		ExpiryInterval int  `mapstructure:"expiry_interval"` // seconds
+	} `mapstructure:"archival"`
+
+	// Lifecycle banners are shown on assets in each lifecycle status.
+	// {reason}, {replacement} and {sunset} are filled in for deprecated
+	// assets. An empty banner shows nothing.
+	Lifecycle struct {
+		Banners map[string]string `mapstructure:"banners"`
+	} `mapstructure:"lifecycle"`
+
+	// Quotas protect shared deployments from a runaway plugin flooding the
+	// catalog. A limit of 0 is unlimited.
+	Quotas struct {
+		MaxAssetsPerProvider int `mapstructure:"max_assets_per_provider"`
+		// Providers overrides max_assets_per_provider for named providers.
+		Providers     map[string]int `mapstructure:"providers"`
+		MaxStubs      int            `mapstructure:"max_stubs"`
+		MaxRunsPerDay int            `mapstructure:"max_runs_per_day"`
+		// WarnAt is the percentage of a limit at which admins are notified.
+		WarnAt   int `mapstructure:"warn_at"`
+		Interval int `mapstructure:"interval"` // seconds
+	} `mapstructure:"quotas"`
+
+	// OwnershipVacancies alerts admins when every owner of an asset or
+	// data product has been deactivated or left their team.
+	OwnershipVacancies struct {
+		Enabled  bool `mapstructure:"enabled"`
+		Interval int  `mapstructure:"interval"` // seconds
+		// RemindAfterDays re-alerts open vacancies this long after the
+		// last alert. 0 disables reminders.
+		RemindAfterDays int `mapstructure:"remind_after_days"`
+	} `mapstructure:"ownership_vacancies"`
+
+	Connections struct {
+		// HealthCheckInterval is how often every connection's endpoint is
+		// checked. 0 disables continuous monitoring.
+		HealthCheckInterval int `mapstructure:"health_check_interval"` // seconds
+		HealthCheckTimeout  int `mapstructure:"health_check_timeout"`  // seconds
+	} `mapstructure:"connections"`
+
+	// Sampling infers CSV and JSON file schemas in S3 and GCS buckets.
+	Sampling struct {
+		Enabled       bool   `mapstructure:"enabled"`
+		Interval      int    `mapstructure:"interval"`       // seconds
+		ResampleAfter int    `mapstructure:"resample_after"` // seconds
+		MaxRows       int    `mapstructure:"max_rows"`
+		MaxBytes      int64  `mapstructure:"max_bytes"`
+		MaxDatasets   int    `mapstructure:"max_datasets"`
+		S3Endpoint    string `mapstructure:"s3_endpoint"`
+		// GCSCredentialsFile is a service account key file. Application
+		// default credentials are used when empty.
+		GCSCredentialsFile string `mapstructure:"gcs_credentials_file"`
+	} `mapstructure:"sampling"`
+
+	// Thumbnails captures preview images of dashboard assets with a headless
+	// browser service such as Browserless.
+	Thumbnails struct {
+		Enabled      bool     `mapstructure:"enabled"`
+		Interval     int      `mapstructure:"interval"`      // seconds
+		RefreshAfter int      `mapstructure:"refresh_after"` // seconds
+		AssetTypes   []string `mapstructure:"asset_types"`
+		// RendererURL is the screenshot endpoint, e.g.
+		// http://browserless:3000/screenshot. Capture is disabled when empty;
+		// uploaded thumbnails are still served.
+		RendererURL   string `mapstructure:"renderer_url"`
+		RendererToken string `mapstructure:"renderer_token"`
+		Width         int    `mapstructure:"width"`
+		Height        int    `mapstructure:"height"`
+		Timeout       int    `mapstructure:"timeout"` // seconds
+	} `mapstructure:"thumbnails"`
+
+	Idempotency struct {
+		TTL int `mapstructure:"ttl"` // seconds
+	} `mapstructure:"idempotency"`
+
+	AssetMerge struct {
+		// Enabled merges fields reported by several sources for the same
+		// asset instead of keeping the last write.
+		Enabled bool `mapstructure:"enabled"`
+		// SourcePriority ranks sources by name; higher wins.
+		SourcePriority map[string]int `mapstructure:"source_priority"`
+		// Fields overrides the merge strategy per field, e.g. tags: union.
+		Fields map[string]string `mapstructure:"fields"`
+	} `mapstructure:"asset_merge"`
+
+	Watch struct {
+		Enabled  bool                `mapstructure:"enabled"`
+		Interval int                 `mapstructure:"interval"` // seconds
+		Folders  []WatchFolderConfig `mapstructure:"folders"`
+	} `mapstructure:"watch"`
+
+	Incidents struct {
+		// Polling refreshes the status of open ticket and incident links
+		// from the trackers configured below.
+		Polling   bool `mapstructure:"polling"`
+		Interval  int  `mapstructure:"interval"` // seconds
+		BatchSize int  `mapstructure:"batch_size"`
+		Jira      struct {
+			URL      string `mapstructure:"url"`
+			Email    string `mapstructure:"email"`
+			APIToken string `mapstructure:"api_token"`
+		} `mapstructure:"jira"`
+		GitHub struct {
+			URL   string `mapstructure:"url"`
+			Token string `mapstructure:"token"`
+		} `mapstructure:"github"`
+		PagerDuty struct {
+			Token string `mapstructure:"token"`
+		} `mapstructure:"pagerduty"`
+	} `mapstructure:"incidents"`
+
+	GRPC struct {
+		// Enabled serves the gRPC ingestion API alongside the REST API.
+		Enabled bool `mapstructure:"enabled"`
+		Port    int  `mapstructure:"port"`
+		// MaxMessageSize caps a single message, in MB.
+		MaxMessageSize int `mapstructure:"max_message_size"`
+	} `mapstructure:"grpc"`
+
 	Plugins struct {
 		// Registry overrides the OCI registry namespace core plugins
 		// are installed from, e.g. an internal mirror.
@@ -164,6 +370,15 @@ type BannerConfig struct {
 	ID          string `mapstructure:"id"`
 }
 
+// SearchDefaultsConfig is the search scope applied to requests that leave
+// it out. Empty Types and Environments don't restrict results.
+type SearchDefaultsConfig struct {
+	IncludeStubs bool     `mapstructure:"include_stubs"`
+	Types        []string `mapstructure:"types"`
+	Environments []string `mapstructure:"environments"`
+	Sort         string   `mapstructure:"sort"` // relevance, updated or name
+}
+
 // ElasticsearchConfig holds configuration for the optional Elasticsearch search backend.
 type ElasticsearchConfig struct {
 	Enabled        bool       `mapstructure:"enabled"`
@@ -179,6 +394,91 @@ type ElasticsearchConfig struct {
 	Replicas       *int       `mapstructure:"replicas"`
 }
 
+// TagSyncMapping links a Marmot tag category ("pii" for "pii:email") to a
+// native tag in the warehouse. Direction is push, pull or both; OnConflict
+// (marmot_wins, remote_wins or skip) applies when both sides disagree.
+type TagSyncMapping struct {
+	Category   string `mapstructure:"category"`
+	Tag        string `mapstructure:"tag"`
+	Direction  string `mapstructure:"direction"`
+	OnConflict string `mapstructure:"on_conflict"`
+}
+
+// PolicyTagSyncMapping links a Marmot policy tag, by name, to a column-level
+// tag in the warehouse such as a BigQuery policy tag resource name.
+type PolicyTagSyncMapping struct {
+	PolicyTag  string `mapstructure:"policy_tag"`
+	Tag        string `mapstructure:"tag"`
+	Direction  string `mapstructure:"direction"`
+	OnConflict string `mapstructure:"on_conflict"`
+}
+
+// WatchFolderConfig describes a local directory or S3 prefix watched for
+// dropped dbt artifacts, OpenAPI specs and CSV manifests.
+type WatchFolderConfig struct {
+	Name     string                 `mapstructure:"name"`
+	Path     string                 `mapstructure:"path"`
+	Region   string                 `mapstructure:"region"`
+	Endpoint string                 `mapstructure:"endpoint"`
+	Config   map[string]interface{} `mapstructure:"config"`
+}
+
+// SnowflakeTagSyncConfig holds configuration for syncing tags with Snowflake
+// object tags. Authenticate with either a key pair or an OAuth token.
+type SnowflakeTagSyncConfig struct {
+	Enabled        bool             `mapstructure:"enabled"`
+	Account        string           `mapstructure:"account"`
+	Host           string           `mapstructure:"host"`
+	User           string           `mapstructure:"user"`
+	Role           string           `mapstructure:"role"`
+	Warehouse      string           `mapstructure:"warehouse"`
+	PrivateKeyPath string           `mapstructure:"private_key_path"`
+	OAuthToken     string           `mapstructure:"oauth_token"`
+	Mappings       []TagSyncMapping `mapstructure:"mappings"`
+}
+
+// BigQueryTagSyncConfig holds configuration for syncing tag categories with
+// BigQuery table labels and policy tags with BigQuery column policy tags.
+type BigQueryTagSyncConfig struct {
+	Enabled           bool                   `mapstructure:"enabled"`
+	ProjectID         string                 `mapstructure:"project_id"`
+	CredentialsFile   string                 `mapstructure:"credentials_file"`
+	Mappings          []TagSyncMapping       `mapstructure:"mappings"`
+	PolicyTagMappings []PolicyTagSyncMapping `mapstructure:"policy_tag_mappings"`
+}
+
+// AWSTagSyncConfig holds configuration for importing AWS resource tags into
+// asset metadata. Mappings can only pull. Credentials come from the default
+// AWS chain.
+type AWSTagSyncConfig struct {
+	Enabled     bool             `mapstructure:"enabled"`
+	Region      string           `mapstructure:"region"`
+	Endpoint    string           `mapstructure:"endpoint"`
+	MetadataKey string           `mapstructure:"metadata_key"`
+	Mappings    []TagSyncMapping `mapstructure:"mappings"`
+}
+
+// GCSTagSyncConfig holds configuration for importing Cloud Storage bucket
+// labels into asset metadata. Mappings can only pull.
+type GCSTagSyncConfig struct {
+	Enabled         bool             `mapstructure:"enabled"`
+	CredentialsFile string           `mapstructure:"credentials_file"`
+	MetadataKey     string           `mapstructure:"metadata_key"`
+	Mappings        []TagSyncMapping `mapstructure:"mappings"`
+}
+
+// KubernetesTagSyncConfig holds configuration for importing Kubernetes
+// object labels into asset metadata. Mappings can only pull. The in-cluster
+// service account is used when Kubeconfig is empty.
+type KubernetesTagSyncConfig struct {
+	Enabled     bool             `mapstructure:"enabled"`
+	Kubeconfig  string           `mapstructure:"kubeconfig"`
+	Context     string           `mapstructure:"context"`
+	ClusterName string           `mapstructure:"cluster_name"`
+	MetadataKey string           `mapstructure:"metadata_key"`
+	Mappings    []TagSyncMapping `mapstructure:"mappings"`
+}
+
 var (
 	config *Config
 	once   sync.Once
@@ -225,6 +525,13 @@ func loadConfig(configPath string) error {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
+	v.BindEnv("auth.authorization.opa.url")
+	v.BindEnv("auth.authorization.opa.timeout")
+	v.BindEnv("auth.authorization.opa.fail_open")
+
+	v.BindEnv("auth.scim.enabled")
+	v.BindEnv("auth.scim.token")
+
 	// Explicitly bind auth provider config env vars
 	v.BindEnv("auth.okta.client_id")
 	v.BindEnv("auth.okta.client_secret")
@@ -332,6 +639,7 @@ func loadConfig(configPath string) error {
 	v.BindEnv("server.root_url")
 	v.BindEnv("server.encryption_key")
 	v.BindEnv("server.allow_unencrypted")
+	v.BindEnv("server.trusted_proxies")
 	v.BindEnv("server.tls.cert_path")
 	v.BindEnv("server.tls.key_path")
 	v.BindEnv("server.tls.ca_cert_path")
@@ -352,6 +660,13 @@ func loadConfig(configPath string) error {
 	v.BindEnv("pipelines.scheduler_interval")
 	v.BindEnv("pipelines.lease_expiry")
 	v.BindEnv("pipelines.claim_expiry")
+	v.BindEnv("pipelines.checkpoint_history")
+	v.BindEnv("pipelines.checkpoint_compaction_interval")
+	v.BindEnv("pipelines.store_artifacts")
+	v.BindEnv("pipelines.artifact_retention_days")
+	v.BindEnv("pipelines.artifact_max_size_mb")
+	v.BindEnv("pipelines.batch_size")
+	v.BindEnv("pipelines.duplicate_similarity")
 
 	// Operator env vars
 	v.BindEnv("operator.enabled")
@@ -362,12 +677,28 @@ func loadConfig(configPath string) error {
 	v.BindEnv("telemetry.enabled")
 	v.BindEnv("telemetry.endpoint")
 	v.BindEnv("telemetry.interval")
+	v.BindEnv("telemetry.feature_usage")
 
 	// Experimental env vars
 	v.BindEnv("experimental.table_preview")
 
+	// Feature env vars
+	v.BindEnv("features.glossary")
+	v.BindEnv("features.data_products")
+	v.BindEnv("features.scheduling")
+	v.BindEnv("features.ai")
+
 	// Search env vars
 	v.BindEnv("search.timeout")
+	v.BindEnv("search.statement_timeout")
+	v.BindEnv("search.max_results")
+	v.BindEnv("search.max_offset")
+	v.BindEnv("search.facet_load_threshold")
+	v.BindEnv("search.facet_cache_ttl")
+	v.BindEnv("search.defaults.include_stubs")
+	v.BindEnv("search.defaults.types")
+	v.BindEnv("search.defaults.environments")
+	v.BindEnv("search.defaults.sort")
 	v.BindEnv("search.elasticsearch.enabled")
 	v.BindEnv("search.elasticsearch.addresses")
 	v.BindEnv("search.elasticsearch.username")
@@ -381,6 +712,107 @@ func loadConfig(configPath string) error {
 	v.BindEnv("search.elasticsearch.tls.cert_path")
 	v.BindEnv("search.elasticsearch.tls.key_path")
 
+	// Tag sync env vars
+	v.BindEnv("tag_sync.interval")
+	v.BindEnv("tag_sync.dry_run")
+	v.BindEnv("tag_sync.snowflake.enabled")
+	v.BindEnv("tag_sync.snowflake.account")
+	v.BindEnv("tag_sync.snowflake.host")
+	v.BindEnv("tag_sync.snowflake.user")
+	v.BindEnv("tag_sync.snowflake.role")
+	v.BindEnv("tag_sync.snowflake.warehouse")
+	v.BindEnv("tag_sync.snowflake.private_key_path")
+	v.BindEnv("tag_sync.snowflake.oauth_token")
+	v.BindEnv("tag_sync.bigquery.enabled")
+	v.BindEnv("tag_sync.bigquery.project_id")
+	v.BindEnv("tag_sync.bigquery.credentials_file")
+	v.BindEnv("tag_sync.aws.enabled")
+	v.BindEnv("tag_sync.aws.region")
+	v.BindEnv("tag_sync.aws.endpoint")
+	v.BindEnv("tag_sync.aws.metadata_key")
+	v.BindEnv("tag_sync.gcs.enabled")
+	v.BindEnv("tag_sync.gcs.credentials_file")
+	v.BindEnv("tag_sync.gcs.metadata_key")
+	v.BindEnv("tag_sync.kubernetes.enabled")
+	v.BindEnv("tag_sync.kubernetes.kubeconfig")
+	v.BindEnv("tag_sync.kubernetes.context")
+	v.BindEnv("tag_sync.kubernetes.cluster_name")
+	v.BindEnv("tag_sync.kubernetes.metadata_key")
+
+	// Archival env vars
+	v.BindEnv("archival.enabled")
+	v.BindEnv("archival.stale_after_days")
+	v.BindEnv("archival.grace_period_days")
+	v.BindEnv("archival.interval")
+	v.BindEnv("archival.expiry_enabled")
+	v.BindEnv("archival.expiry_interval")
+
+	// Lifecycle env vars
+	v.BindEnv("lifecycle.banners.experimental")
+	v.BindEnv("lifecycle.banners.deprecated")
+	v.BindEnv("lifecycle.banners.archived")
+
+	// Quota env vars
+	v.BindEnv("quotas.max_assets_per_provider")
+	v.BindEnv("quotas.max_stubs")
+	v.BindEnv("quotas.max_runs_per_day")
+	v.BindEnv("quotas.warn_at")
+	v.BindEnv("quotas.interval")
+
+	// Ownership vacancy env vars
+	v.BindEnv("ownership_vacancies.enabled")
+	v.BindEnv("ownership_vacancies.interval")
+	v.BindEnv("ownership_vacancies.remind_after_days")
+
+	// Connection registry env vars
+	v.BindEnv("connections.health_check_interval")
+	v.BindEnv("connections.health_check_timeout")
+
+	// Sampling env vars
+	v.BindEnv("sampling.enabled")
+	v.BindEnv("sampling.interval")
+	v.BindEnv("sampling.resample_after")
+	v.BindEnv("sampling.max_rows")
+	v.BindEnv("sampling.max_bytes")
+	v.BindEnv("sampling.max_datasets")
+	v.BindEnv("sampling.s3_endpoint")
+	v.BindEnv("sampling.gcs_credentials_file")
+
+	// Thumbnail env vars
+	v.BindEnv("thumbnails.enabled")
+	v.BindEnv("thumbnails.interval")
+	v.BindEnv("thumbnails.refresh_after")
+	v.BindEnv("thumbnails.asset_types")
+	v.BindEnv("thumbnails.renderer_url")
+	v.BindEnv("thumbnails.renderer_token")
+	v.BindEnv("thumbnails.width")
+	v.BindEnv("thumbnails.height")
+	v.BindEnv("thumbnails.timeout")
+
+	// Idempotency env vars
+	v.BindEnv("idempotency.ttl")
+
+	// Asset merge env vars
+	v.BindEnv("asset_merge.enabled")
+
+	// Watch folder env vars
+	v.BindEnv("watch.enabled")
+	v.BindEnv("watch.interval")
+
+	// Incident link env vars
+	v.BindEnv("incidents.polling")
+	v.BindEnv("incidents.interval")
+	v.BindEnv("incidents.jira.url")
+	v.BindEnv("incidents.jira.email")
+	v.BindEnv("incidents.jira.api_token")
+	v.BindEnv("incidents.github.token")
+	v.BindEnv("incidents.pagerduty.token")
+
+	// gRPC env vars
+	v.BindEnv("grpc.enabled")
+	v.BindEnv("grpc.port")
+	v.BindEnv("grpc.max_message_size")
+
 	// Set defaults
 	setDefaults(v)
 
@@ -418,6 +850,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.conn_lifetime", 5) // minutes
 
 	v.SetDefault("auth.anonymous.role", "user")
+	v.SetDefault("auth.authorization.opa.timeout", 2)
 
 	// OpenLineage defaults
 	v.SetDefault("openlineage.auth.enabled", true)
@@ -430,6 +863,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("plugins.registry", "")
 
 	// Auth defaults
+	v.SetDefault("auth.scim.enabled", false)
+	v.SetDefault("auth.scim.default_roles", []string{"user"})
+
 	v.SetDefault("auth.okta.type", "okta")
 	v.SetDefault("auth.okta.name", "Okta")
 	v.SetDefault("auth.okta.allow_signup", true)
@@ -494,6 +930,13 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("pipelines.scheduler_interval", 60)
 	v.SetDefault("pipelines.lease_expiry", 300)
 	v.SetDefault("pipelines.claim_expiry", 30)
+	v.SetDefault("pipelines.checkpoint_history", 5)
+	v.SetDefault("pipelines.checkpoint_compaction_interval", 3600) // 1 hour
+	v.SetDefault("pipelines.store_artifacts", false)
+	v.SetDefault("pipelines.artifact_retention_days", 14)
+	v.SetDefault("pipelines.artifact_max_size_mb", 50)
+	v.SetDefault("pipelines.batch_size", 500)
+	v.SetDefault("pipelines.duplicate_similarity", 0.8)
 
 	// Operator defaults
 	v.SetDefault("operator.service_account", "marmot-ingest")
@@ -502,17 +945,102 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("telemetry.enabled", true)
 	v.SetDefault("telemetry.endpoint", "https://telemetry.marmotdata.io/v1/ingest")
 	v.SetDefault("telemetry.interval", 86400) // 24h
+	v.SetDefault("telemetry.feature_usage", false)
 
 	// Experimental defaults
 	v.SetDefault("experimental.table_preview", false)
 
+	// Feature defaults
+	v.SetDefault("features.glossary", true)
+	v.SetDefault("features.data_products", true)
+	v.SetDefault("features.scheduling", true)
+	v.SetDefault("features.ai", true)
+
 	// Search defaults
-	v.SetDefault("search.timeout", 10) // 10 seconds
+	v.SetDefault("search.timeout", 10)             // 10 seconds
+	v.SetDefault("search.statement_timeout", 5000) // 5 seconds
+	v.SetDefault("search.max_results", 1000)
+	v.SetDefault("search.max_offset", 10000)
+	v.SetDefault("search.facet_load_threshold", 0.8) // skip facets above 80% pool usage
+	v.SetDefault("search.facet_cache_ttl", 60)
+	v.SetDefault("search.defaults.include_stubs", false)
+	v.SetDefault("search.defaults.sort", "relevance")
 	v.SetDefault("search.elasticsearch.enabled", false)
 	v.SetDefault("search.elasticsearch.index", "marmot")
 	v.SetDefault("search.elasticsearch.bulk_size", 500)
 	v.SetDefault("search.elasticsearch.flush_interval", 1000)
 	v.SetDefault("search.elasticsearch.reindex_on_start", false)
+
+	// Tag sync defaults
+	v.SetDefault("tag_sync.interval", 3600) // 1 hour
+	v.SetDefault("tag_sync.dry_run", false)
+	v.SetDefault("tag_sync.snowflake.enabled", false)
+	v.SetDefault("tag_sync.bigquery.enabled", false)
+	v.SetDefault("tag_sync.aws.enabled", false)
+	v.SetDefault("tag_sync.gcs.enabled", false)
+	v.SetDefault("tag_sync.kubernetes.enabled", false)
+
+	// Archival defaults
+	v.SetDefault("archival.enabled", false)
+	v.SetDefault("archival.stale_after_days", 30)
+	v.SetDefault("archival.grace_period_days", 7)
+	v.SetDefault("archival.interval", 86400) // 24 hours
+	v.SetDefault("archival.expiry_enabled", true)
+	v.SetDefault("archival.expiry_interval", 3600) // 1 hour
+
+	// Lifecycle defaults
+	v.SetDefault("lifecycle.banners.experimental", "This asset is experimental and may change without notice.")
+	v.SetDefault("lifecycle.banners.deprecated", "This asset is deprecated. {reason}")
+	v.SetDefault("lifecycle.banners.archived", "This asset is archived and no longer maintained.")
+
+	v.SetDefault("quotas.warn_at", 80)
+	v.SetDefault("quotas.interval", 3600) // 1 hour
+
+	// Ownership vacancy defaults
+	v.SetDefault("ownership_vacancies.enabled", true)
+	v.SetDefault("ownership_vacancies.interval", 86400) // 24 hours
+	v.SetDefault("ownership_vacancies.remind_after_days", 7)
+
+	// Connection registry defaults
+	v.SetDefault("connections.health_check_interval", 300) // 5 minutes
+	v.SetDefault("connections.health_check_timeout", 5)
+
+	// Sampling defaults
+	v.SetDefault("sampling.enabled", false)
+	v.SetDefault("sampling.interval", 3600)        // 1 hour
+	v.SetDefault("sampling.resample_after", 86400) // 24 hours
+	v.SetDefault("sampling.max_rows", 100)
+	v.SetDefault("sampling.max_bytes", 1048576) // 1 MiB
+	v.SetDefault("sampling.max_datasets", 20)
+
+	// Thumbnail defaults
+	v.SetDefault("thumbnails.enabled", false)
+	v.SetDefault("thumbnails.interval", 3600)        // 1 hour
+	v.SetDefault("thumbnails.refresh_after", 604800) // 7 days
+	v.SetDefault("thumbnails.asset_types", []string{"Dashboard", "Report", "Workbook", "Chart"})
+	v.SetDefault("thumbnails.width", 640)
+	v.SetDefault("thumbnails.height", 400)
+	v.SetDefault("thumbnails.timeout", 60)
+
+	// Idempotency defaults
+	v.SetDefault("idempotency.ttl", 86400) // 24 hours
+
+	// Asset merge defaults
+	v.SetDefault("asset_merge.enabled", false)
+
+	// Incident link defaults
+	v.SetDefault("incidents.polling", false)
+	v.SetDefault("incidents.interval", 900) // 15 minutes
+	v.SetDefault("incidents.batch_size", 200)
+
+	// Watch folder defaults
+	v.SetDefault("watch.enabled", false)
+	v.SetDefault("watch.interval", 60) // 1 minute
+
+	// gRPC defaults
+	v.SetDefault("grpc.enabled", false)
+	v.SetDefault("grpc.port", 9091)
+	v.SetDefault("grpc.max_message_size", 16)
 }
 
 // BuildDSN builds a PostgreSQL connection string from config
@@ -568,12 +1096,44 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("invalid banner variant: %s", cfg.UI.Banner.Variant)
 	}
 
+	validSorts := map[string]bool{
+		"relevance": true,
+		"updated":   true,
+		"name":      true,
+	}
+	if !validSorts[cfg.Search.Defaults.Sort] {
+		return fmt.Errorf("invalid search.defaults.sort: %s", cfg.Search.Defaults.Sort)
+	}
+
+	validResultTypes := map[string]bool{
+		"asset":        true,
+		"glossary":     true,
+		"team":         true,
+		"data_product": true,
+		"query":        true,
+	}
+	for _, t := range cfg.Search.Defaults.Types {
+		if !validResultTypes[t] {
+			return fmt.Errorf("invalid search.defaults.types: %s", t)
+		}
+	}
+
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, err := ParseTrustedProxy(proxy); err != nil {
+			return fmt.Errorf("invalid server.trusted_proxies entry: %s", proxy)
+		}
+	}
+
 	if cfg.Server.TLS != nil {
 		if cfg.Server.TLS.CertPath == "" || cfg.Server.TLS.KeyPath == "" {
 			return fmt.Errorf("server.tls requires both cert_path and key_path")
 		}
 	}
 
+	if cfg.Auth.Authorization.OPA.URL != "" && cfg.Auth.Authorization.OPA.Timeout < 1 {
+		return fmt.Errorf("invalid auth.authorization.opa.timeout: must be at least 1 second")
+	}
+
 	if cfg.Pipelines.MaxWorkers < 1 {
 		return fmt.Errorf("invalid pipelines.max_workers: must be at least 1")
 	}
@@ -586,6 +1146,39 @@ func validate(cfg *Config) error {
 	if cfg.Pipelines.ClaimExpiry < 1 {
 		return fmt.Errorf("invalid pipelines.claim_expiry: must be at least 1 second")
 	}
+	if cfg.Pipelines.CheckpointHistory < 1 {
+		return fmt.Errorf("invalid pipelines.checkpoint_history: must be at least 1")
+	}
+	if cfg.Pipelines.BatchSize < 1 || cfg.Pipelines.BatchSize > 10000 {
+		return fmt.Errorf("invalid pipelines.batch_size: must be between 1 and 10000")
+	}
+	if cfg.Pipelines.StoreArtifacts {
+		if cfg.Pipelines.ArtifactRetentionDays < 1 {
+			return fmt.Errorf("invalid pipelines.artifact_retention_days: must be at least 1")
+		}
+		if cfg.Pipelines.ArtifactMaxSizeMB < 1 {
+			return fmt.Errorf("invalid pipelines.artifact_max_size_mb: must be at least 1")
+		}
+	}
 
 	return nil
 }
+
+// ParseTrustedProxy parses a server.trusted_proxies entry, either a single IP
+// or a CIDR range.
+func ParseTrustedProxy(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
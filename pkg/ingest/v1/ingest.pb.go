@@ -0,0 +1,1478 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: marmot/ingest/v1/ingest.proto
+
+package ingestv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Asset struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mrn           string                 `protobuf:"bytes,2,opt,name=mrn,proto3" json:"mrn,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Providers     []string               `protobuf:"bytes,5,rep,name=providers,proto3" json:"providers,omitempty"`
+	Description   *string                `protobuf:"bytes,6,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Schema        *structpb.Struct       `protobuf:"bytes,8,opt,name=schema,proto3" json:"schema,omitempty"`
+	Tags          []string               `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	Sources       []string               `protobuf:"bytes,10,rep,name=sources,proto3" json:"sources,omitempty"`
+	ExternalLinks []*ExternalLink        `protobuf:"bytes,11,rep,name=external_links,json=externalLinks,proto3" json:"external_links,omitempty"`
+	Query         *string                `protobuf:"bytes,12,opt,name=query,proto3,oneof" json:"query,omitempty"`
+	QueryLanguage *string                `protobuf:"bytes,13,opt,name=query_language,json=queryLanguage,proto3,oneof" json:"query_language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Asset) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Asset) GetMrn() string {
+	if x != nil {
+		return x.Mrn
+	}
+	return ""
+}
+
+func (x *Asset) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Asset) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Asset) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *Asset) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *Asset) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Asset) GetSchema() *structpb.Struct {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+func (x *Asset) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Asset) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Asset) GetExternalLinks() []*ExternalLink {
+	if x != nil {
+		return x.ExternalLinks
+	}
+	return nil
+}
+
+func (x *Asset) GetQuery() string {
+	if x != nil && x.Query != nil {
+		return *x.Query
+	}
+	return ""
+}
+
+func (x *Asset) GetQueryLanguage() string {
+	if x != nil && x.QueryLanguage != nil {
+		return *x.QueryLanguage
+	}
+	return ""
+}
+
+type ExternalLink struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Icon          string                 `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExternalLink) Reset() {
+	*x = ExternalLink{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExternalLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExternalLink) ProtoMessage() {}
+
+func (x *ExternalLink) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExternalLink.ProtoReflect.Descriptor instead.
+func (*ExternalLink) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExternalLink) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExternalLink) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ExternalLink) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+type Lineage struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Source string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Type   string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	// columns maps columns of the source asset to columns of the target.
+	Columns       []*ColumnMapping `protobuf:"bytes,4,rep,name=columns,proto3" json:"columns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lineage) Reset() {
+	*x = Lineage{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lineage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lineage) ProtoMessage() {}
+
+func (x *Lineage) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lineage.ProtoReflect.Descriptor instead.
+func (*Lineage) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Lineage) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Lineage) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Lineage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Lineage) GetColumns() []*ColumnMapping {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+type ColumnMapping struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SourceColumn string                 `protobuf:"bytes,1,opt,name=source_column,json=sourceColumn,proto3" json:"source_column,omitempty"`
+	TargetColumn string                 `protobuf:"bytes,2,opt,name=target_column,json=targetColumn,proto3" json:"target_column,omitempty"`
+	// transformation describes how the target column is derived, e.g.
+	// "identity" or "SUM(amount)".
+	Transformation string `protobuf:"bytes,3,opt,name=transformation,proto3" json:"transformation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ColumnMapping) Reset() {
+	*x = ColumnMapping{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnMapping) ProtoMessage() {}
+
+func (x *ColumnMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnMapping.ProtoReflect.Descriptor instead.
+func (*ColumnMapping) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ColumnMapping) GetSourceColumn() string {
+	if x != nil {
+		return x.SourceColumn
+	}
+	return ""
+}
+
+func (x *ColumnMapping) GetTargetColumn() string {
+	if x != nil {
+		return x.TargetColumn
+	}
+	return ""
+}
+
+func (x *ColumnMapping) GetTransformation() string {
+	if x != nil {
+		return x.Transformation
+	}
+	return ""
+}
+
+type Documentation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetMrn      string                 `protobuf:"bytes,1,opt,name=asset_mrn,json=assetMrn,proto3" json:"asset_mrn,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Documentation) Reset() {
+	*x = Documentation{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Documentation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Documentation) ProtoMessage() {}
+
+func (x *Documentation) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Documentation.ProtoReflect.Descriptor instead.
+func (*Documentation) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Documentation) GetAssetMrn() string {
+	if x != nil {
+		return x.AssetMrn
+	}
+	return ""
+}
+
+func (x *Documentation) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Documentation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Statistic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetMrn      string                 `protobuf:"bytes,1,opt,name=asset_mrn,json=assetMrn,proto3" json:"asset_mrn,omitempty"`
+	MetricName    string                 `protobuf:"bytes,2,opt,name=metric_name,json=metricName,proto3" json:"metric_name,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statistic) Reset() {
+	*x = Statistic{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistic) ProtoMessage() {}
+
+func (x *Statistic) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistic.ProtoReflect.Descriptor instead.
+func (*Statistic) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Statistic) GetAssetMrn() string {
+	if x != nil {
+		return x.AssetMrn
+	}
+	return ""
+}
+
+func (x *Statistic) GetMetricName() string {
+	if x != nil {
+		return x.MetricName
+	}
+	return ""
+}
+
+func (x *Statistic) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type Run struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RunId         string                 `protobuf:"bytes,2,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	PipelineName  string                 `protobuf:"bytes,3,opt,name=pipeline_name,json=pipelineName,proto3" json:"pipeline_name,omitempty"`
+	SourceName    string                 `protobuf:"bytes,4,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Run) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Run) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Run) GetPipelineName() string {
+	if x != nil {
+		return x.PipelineName
+	}
+	return ""
+}
+
+func (x *Run) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *Run) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type StartRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PipelineName  string                 `protobuf:"bytes,1,opt,name=pipeline_name,json=pipelineName,proto3" json:"pipeline_name,omitempty"`
+	SourceName    string                 `protobuf:"bytes,2,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	Config        *structpb.Struct       `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRunRequest) Reset() {
+	*x = StartRunRequest{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRunRequest) ProtoMessage() {}
+
+func (x *StartRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRunRequest.ProtoReflect.Descriptor instead.
+func (*StartRunRequest) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartRunRequest) GetPipelineName() string {
+	if x != nil {
+		return x.PipelineName
+	}
+	return ""
+}
+
+func (x *StartRunRequest) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *StartRunRequest) GetConfig() *structpb.Struct {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type IngestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	RunId string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// batch is echoed back on the matching IngestResponse.
+	Batch         uint64           `protobuf:"varint,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Assets        []*Asset         `protobuf:"bytes,3,rep,name=assets,proto3" json:"assets,omitempty"`
+	Lineage       []*Lineage       `protobuf:"bytes,4,rep,name=lineage,proto3" json:"lineage,omitempty"`
+	Documentation []*Documentation `protobuf:"bytes,5,rep,name=documentation,proto3" json:"documentation,omitempty"`
+	Statistics    []*Statistic     `protobuf:"bytes,6,rep,name=statistics,proto3" json:"statistics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestRequest) Reset() {
+	*x = IngestRequest{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestRequest) ProtoMessage() {}
+
+func (x *IngestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestRequest.ProtoReflect.Descriptor instead.
+func (*IngestRequest) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IngestRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *IngestRequest) GetBatch() uint64 {
+	if x != nil {
+		return x.Batch
+	}
+	return 0
+}
+
+func (x *IngestRequest) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *IngestRequest) GetLineage() []*Lineage {
+	if x != nil {
+		return x.Lineage
+	}
+	return nil
+}
+
+func (x *IngestRequest) GetDocumentation() []*Documentation {
+	if x != nil {
+		return x.Documentation
+	}
+	return nil
+}
+
+func (x *IngestRequest) GetStatistics() []*Statistic {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+type IngestResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Batch   uint64                 `protobuf:"varint,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	Results []*EntityResult        `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	// summary counts the batch's entities by type: "asset", "lineage" or
+	// "documentation".
+	Summary       map[string]*EntityCounts `protobuf:"bytes,3,rep,name=summary,proto3" json:"summary,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestResponse) Reset() {
+	*x = IngestResponse{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestResponse) ProtoMessage() {}
+
+func (x *IngestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestResponse.ProtoReflect.Descriptor instead.
+func (*IngestResponse) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IngestResponse) GetBatch() uint64 {
+	if x != nil {
+		return x.Batch
+	}
+	return 0
+}
+
+func (x *IngestResponse) GetResults() []*EntityResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *IngestResponse) GetSummary() map[string]*EntityCounts {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type EntityResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// entity_type is "asset", "lineage" or "documentation".
+	EntityType string `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	// mrn is the asset MRN for assets and documentation; lineage results set
+	// source and target instead.
+	Mrn    string `protobuf:"bytes,2,opt,name=mrn,proto3" json:"mrn,omitempty"`
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Target string `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	// status is "created", "updated", "unchanged" or "failed".
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityResult) Reset() {
+	*x = EntityResult{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityResult) ProtoMessage() {}
+
+func (x *EntityResult) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityResult.ProtoReflect.Descriptor instead.
+func (*EntityResult) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EntityResult) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *EntityResult) GetMrn() string {
+	if x != nil {
+		return x.Mrn
+	}
+	return ""
+}
+
+func (x *EntityResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *EntityResult) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *EntityResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EntityResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type EntityCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int64                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int64                  `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	Unchanged     int64                  `protobuf:"varint,3,opt,name=unchanged,proto3" json:"unchanged,omitempty"`
+	Deleted       int64                  `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Failed        int64                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityCounts) Reset() {
+	*x = EntityCounts{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityCounts) ProtoMessage() {}
+
+func (x *EntityCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityCounts.ProtoReflect.Descriptor instead.
+func (*EntityCounts) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EntityCounts) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *EntityCounts) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *EntityCounts) GetUnchanged() int64 {
+	if x != nil {
+		return x.Unchanged
+	}
+	return 0
+}
+
+func (x *EntityCounts) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+func (x *EntityCounts) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type CompleteRunRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	RunId string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// status is "completed", "failed" or "cancelled".
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error  string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// entities is the run's summary, typically the sum of the IngestResponse
+	// summaries.
+	Entities      map[string]*EntityCounts `protobuf:"bytes,4,rep,name=entities,proto3" json:"entities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteRunRequest) Reset() {
+	*x = CompleteRunRequest{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteRunRequest) ProtoMessage() {}
+
+func (x *CompleteRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteRunRequest.ProtoReflect.Descriptor instead.
+func (*CompleteRunRequest) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CompleteRunRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *CompleteRunRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CompleteRunRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CompleteRunRequest) GetEntities() map[string]*EntityCounts {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type CompleteRunResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// stale_assets_removed lists the MRNs of assets deleted because the
+	// source no longer reports them.
+	StaleAssetsRemoved []string `protobuf:"bytes,1,rep,name=stale_assets_removed,json=staleAssetsRemoved,proto3" json:"stale_assets_removed,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CompleteRunResponse) Reset() {
+	*x = CompleteRunResponse{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteRunResponse) ProtoMessage() {}
+
+func (x *CompleteRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteRunResponse.ProtoReflect.Descriptor instead.
+func (*CompleteRunResponse) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CompleteRunResponse) GetStaleAssetsRemoved() []string {
+	if x != nil {
+		return x.StaleAssetsRemoved
+	}
+	return nil
+}
+
+type GetAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mrn           string                 `protobuf:"bytes,1,opt,name=mrn,proto3" json:"mrn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetRequest) Reset() {
+	*x = GetAssetRequest{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetRequest) ProtoMessage() {}
+
+func (x *GetAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetRequest) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetAssetRequest) GetMrn() string {
+	if x != nil {
+		return x.Mrn
+	}
+	return ""
+}
+
+type GetLineageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mrn   string                 `protobuf:"bytes,1,opt,name=mrn,proto3" json:"mrn,omitempty"`
+	// depth defaults to 10 and is capped at 20.
+	Depth int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	// direction is "upstream", "downstream" or "both" (the default).
+	Direction     string `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLineageRequest) Reset() {
+	*x = GetLineageRequest{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLineageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLineageRequest) ProtoMessage() {}
+
+func (x *GetLineageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLineageRequest.ProtoReflect.Descriptor instead.
+func (*GetLineageRequest) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetLineageRequest) GetMrn() string {
+	if x != nil {
+		return x.Mrn
+	}
+	return ""
+}
+
+func (x *GetLineageRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *GetLineageRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type LineageGraph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*LineageNode         `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges         []*Lineage             `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineageGraph) Reset() {
+	*x = LineageGraph{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineageGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineageGraph) ProtoMessage() {}
+
+func (x *LineageGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineageGraph.ProtoReflect.Descriptor instead.
+func (*LineageGraph) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LineageGraph) GetNodes() []*LineageNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *LineageGraph) GetEdges() []*Lineage {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type LineageNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mrn           string                 `protobuf:"bytes,1,opt,name=mrn,proto3" json:"mrn,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Depth         int32                  `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineageNode) Reset() {
+	*x = LineageNode{}
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineageNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineageNode) ProtoMessage() {}
+
+func (x *LineageNode) ProtoReflect() protoreflect.Message {
+	mi := &file_marmot_ingest_v1_ingest_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineageNode.ProtoReflect.Descriptor instead.
+func (*LineageNode) Descriptor() ([]byte, []int) {
+	return file_marmot_ingest_v1_ingest_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LineageNode) GetMrn() string {
+	if x != nil {
+		return x.Mrn
+	}
+	return ""
+}
+
+func (x *LineageNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LineageNode) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LineageNode) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+var File_marmot_ingest_v1_ingest_proto protoreflect.FileDescriptor
+
+const file_marmot_ingest_v1_ingest_proto_rawDesc = "" +
+	"\n" +
+	"\x1dmarmot/ingest/v1/ingest.proto\x12\x10marmot.ingest.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xe5\x03\n" +
+	"\x05Asset\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03mrn\x18\x02 \x01(\tR\x03mrn\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x1c\n" +
+	"\tproviders\x18\x05 \x03(\tR\tproviders\x12%\n" +
+	"\vdescription\x18\x06 \x01(\tH\x00R\vdescription\x88\x01\x01\x123\n" +
+	"\bmetadata\x18\a \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12/\n" +
+	"\x06schema\x18\b \x01(\v2\x17.google.protobuf.StructR\x06schema\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12\x18\n" +
+	"\asources\x18\n" +
+	" \x03(\tR\asources\x12E\n" +
+	"\x0eexternal_links\x18\v \x03(\v2\x1e.marmot.ingest.v1.ExternalLinkR\rexternalLinks\x12\x19\n" +
+	"\x05query\x18\f \x01(\tH\x01R\x05query\x88\x01\x01\x12*\n" +
+	"\x0equery_language\x18\r \x01(\tH\x02R\rqueryLanguage\x88\x01\x01B\x0e\n" +
+	"\f_descriptionB\b\n" +
+	"\x06_queryB\x11\n" +
+	"\x0f_query_language\"H\n" +
+	"\fExternalLink\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x12\n" +
+	"\x04icon\x18\x03 \x01(\tR\x04icon\"\x88\x01\n" +
+	"\aLineage\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x129\n" +
+	"\acolumns\x18\x04 \x03(\v2\x1f.marmot.ingest.v1.ColumnMappingR\acolumns\"\x81\x01\n" +
+	"\rColumnMapping\x12#\n" +
+	"\rsource_column\x18\x01 \x01(\tR\fsourceColumn\x12#\n" +
+	"\rtarget_column\x18\x02 \x01(\tR\ftargetColumn\x12&\n" +
+	"\x0etransformation\x18\x03 \x01(\tR\x0etransformation\"Z\n" +
+	"\rDocumentation\x12\x1b\n" +
+	"\tasset_mrn\x18\x01 \x01(\tR\bassetMrn\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"_\n" +
+	"\tStatistic\x12\x1b\n" +
+	"\tasset_mrn\x18\x01 \x01(\tR\bassetMrn\x12\x1f\n" +
+	"\vmetric_name\x18\x02 \x01(\tR\n" +
+	"metricName\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"\x8a\x01\n" +
+	"\x03Run\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06run_id\x18\x02 \x01(\tR\x05runId\x12#\n" +
+	"\rpipeline_name\x18\x03 \x01(\tR\fpipelineName\x12\x1f\n" +
+	"\vsource_name\x18\x04 \x01(\tR\n" +
+	"sourceName\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"\x88\x01\n" +
+	"\x0fStartRunRequest\x12#\n" +
+	"\rpipeline_name\x18\x01 \x01(\tR\fpipelineName\x12\x1f\n" +
+	"\vsource_name\x18\x02 \x01(\tR\n" +
+	"sourceName\x12/\n" +
+	"\x06config\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x06config\"\xa6\x02\n" +
+	"\rIngestRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x14\n" +
+	"\x05batch\x18\x02 \x01(\x04R\x05batch\x12/\n" +
+	"\x06assets\x18\x03 \x03(\v2\x17.marmot.ingest.v1.AssetR\x06assets\x123\n" +
+	"\alineage\x18\x04 \x03(\v2\x19.marmot.ingest.v1.LineageR\alineage\x12E\n" +
+	"\rdocumentation\x18\x05 \x03(\v2\x1f.marmot.ingest.v1.DocumentationR\rdocumentation\x12;\n" +
+	"\n" +
+	"statistics\x18\x06 \x03(\v2\x1b.marmot.ingest.v1.StatisticR\n" +
+	"statistics\"\x85\x02\n" +
+	"\x0eIngestResponse\x12\x14\n" +
+	"\x05batch\x18\x01 \x01(\x04R\x05batch\x128\n" +
+	"\aresults\x18\x02 \x03(\v2\x1e.marmot.ingest.v1.EntityResultR\aresults\x12G\n" +
+	"\asummary\x18\x03 \x03(\v2-.marmot.ingest.v1.IngestResponse.SummaryEntryR\asummary\x1aZ\n" +
+	"\fSummaryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x124\n" +
+	"\x05value\x18\x02 \x01(\v2\x1e.marmot.ingest.v1.EntityCountsR\x05value:\x028\x01\"\x9f\x01\n" +
+	"\fEntityResult\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x10\n" +
+	"\x03mrn\x18\x02 \x01(\tR\x03mrn\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"\x92\x01\n" +
+	"\fEntityCounts\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\x12\x18\n" +
+	"\aupdated\x18\x02 \x01(\x03R\aupdated\x12\x1c\n" +
+	"\tunchanged\x18\x03 \x01(\x03R\tunchanged\x12\x18\n" +
+	"\adeleted\x18\x04 \x01(\x03R\adeleted\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x03R\x06failed\"\x86\x02\n" +
+	"\x12CompleteRunRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12N\n" +
+	"\bentities\x18\x04 \x03(\v22.marmot.ingest.v1.CompleteRunRequest.EntitiesEntryR\bentities\x1a[\n" +
+	"\rEntitiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x124\n" +
+	"\x05value\x18\x02 \x01(\v2\x1e.marmot.ingest.v1.EntityCountsR\x05value:\x028\x01\"G\n" +
+	"\x13CompleteRunResponse\x120\n" +
+	"\x14stale_assets_removed\x18\x01 \x03(\tR\x12staleAssetsRemoved\"#\n" +
+	"\x0fGetAssetRequest\x12\x10\n" +
+	"\x03mrn\x18\x01 \x01(\tR\x03mrn\"Y\n" +
+	"\x11GetLineageRequest\x12\x10\n" +
+	"\x03mrn\x18\x01 \x01(\tR\x03mrn\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\"t\n" +
+	"\fLineageGraph\x123\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x1d.marmot.ingest.v1.LineageNodeR\x05nodes\x12/\n" +
+	"\x05edges\x18\x02 \x03(\v2\x19.marmot.ingest.v1.LineageR\x05edges\"]\n" +
+	"\vLineageNode\x12\x10\n" +
+	"\x03mrn\x18\x01 \x01(\tR\x03mrn\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x14\n" +
+	"\x05depth\x18\x04 \x01(\x05R\x05depth2\x9d\x03\n" +
+	"\rIngestService\x12D\n" +
+	"\bStartRun\x12!.marmot.ingest.v1.StartRunRequest\x1a\x15.marmot.ingest.v1.Run\x12O\n" +
+	"\x06Ingest\x12\x1f.marmot.ingest.v1.IngestRequest\x1a .marmot.ingest.v1.IngestResponse(\x010\x01\x12Z\n" +
+	"\vCompleteRun\x12$.marmot.ingest.v1.CompleteRunRequest\x1a%.marmot.ingest.v1.CompleteRunResponse\x12F\n" +
+	"\bGetAsset\x12!.marmot.ingest.v1.GetAssetRequest\x1a\x17.marmot.ingest.v1.Asset\x12Q\n" +
+	"\n" +
+	"GetLineage\x12#.marmot.ingest.v1.GetLineageRequest\x1a\x1e.marmot.ingest.v1.LineageGraphB5Z3github.com/marmotdata/marmot/pkg/ingest/v1;ingestv1b\x06proto3"
+
+var (
+	file_marmot_ingest_v1_ingest_proto_rawDescOnce sync.Once
+	file_marmot_ingest_v1_ingest_proto_rawDescData []byte
+)
+
+func file_marmot_ingest_v1_ingest_proto_rawDescGZIP() []byte {
+	file_marmot_ingest_v1_ingest_proto_rawDescOnce.Do(func() {
+		file_marmot_ingest_v1_ingest_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_marmot_ingest_v1_ingest_proto_rawDesc), len(file_marmot_ingest_v1_ingest_proto_rawDesc)))
+	})
+	return file_marmot_ingest_v1_ingest_proto_rawDescData
+}
+
+var file_marmot_ingest_v1_ingest_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_marmot_ingest_v1_ingest_proto_goTypes = []any{
+	(*Asset)(nil),               // 0: marmot.ingest.v1.Asset
+	(*ExternalLink)(nil),        // 1: marmot.ingest.v1.ExternalLink
+	(*Lineage)(nil),             // 2: marmot.ingest.v1.Lineage
+	(*ColumnMapping)(nil),       // 3: marmot.ingest.v1.ColumnMapping
+	(*Documentation)(nil),       // 4: marmot.ingest.v1.Documentation
+	(*Statistic)(nil),           // 5: marmot.ingest.v1.Statistic
+	(*Run)(nil),                 // 6: marmot.ingest.v1.Run
+	(*StartRunRequest)(nil),     // 7: marmot.ingest.v1.StartRunRequest
+	(*IngestRequest)(nil),       // 8: marmot.ingest.v1.IngestRequest
+	(*IngestResponse)(nil),      // 9: marmot.ingest.v1.IngestResponse
+	(*EntityResult)(nil),        // 10: marmot.ingest.v1.EntityResult
+	(*EntityCounts)(nil),        // 11: marmot.ingest.v1.EntityCounts
+	(*CompleteRunRequest)(nil),  // 12: marmot.ingest.v1.CompleteRunRequest
+	(*CompleteRunResponse)(nil), // 13: marmot.ingest.v1.CompleteRunResponse
+	(*GetAssetRequest)(nil),     // 14: marmot.ingest.v1.GetAssetRequest
+	(*GetLineageRequest)(nil),   // 15: marmot.ingest.v1.GetLineageRequest
+	(*LineageGraph)(nil),        // 16: marmot.ingest.v1.LineageGraph
+	(*LineageNode)(nil),         // 17: marmot.ingest.v1.LineageNode
+	nil,                         // 18: marmot.ingest.v1.IngestResponse.SummaryEntry
+	nil,                         // 19: marmot.ingest.v1.CompleteRunRequest.EntitiesEntry
+	(*structpb.Struct)(nil),     // 20: google.protobuf.Struct
+}
+var file_marmot_ingest_v1_ingest_proto_depIdxs = []int32{
+	20, // 0: marmot.ingest.v1.Asset.metadata:type_name -> google.protobuf.Struct
+	20, // 1: marmot.ingest.v1.Asset.schema:type_name -> google.protobuf.Struct
+	1,  // 2: marmot.ingest.v1.Asset.external_links:type_name -> marmot.ingest.v1.ExternalLink
+	3,  // 3: marmot.ingest.v1.Lineage.columns:type_name -> marmot.ingest.v1.ColumnMapping
+	20, // 4: marmot.ingest.v1.StartRunRequest.config:type_name -> google.protobuf.Struct
+	0,  // 5: marmot.ingest.v1.IngestRequest.assets:type_name -> marmot.ingest.v1.Asset
+	2,  // 6: marmot.ingest.v1.IngestRequest.lineage:type_name -> marmot.ingest.v1.Lineage
+	4,  // 7: marmot.ingest.v1.IngestRequest.documentation:type_name -> marmot.ingest.v1.Documentation
+	5,  // 8: marmot.ingest.v1.IngestRequest.statistics:type_name -> marmot.ingest.v1.Statistic
+	10, // 9: marmot.ingest.v1.IngestResponse.results:type_name -> marmot.ingest.v1.EntityResult
+	18, // 10: marmot.ingest.v1.IngestResponse.summary:type_name -> marmot.ingest.v1.IngestResponse.SummaryEntry
+	19, // 11: marmot.ingest.v1.CompleteRunRequest.entities:type_name -> marmot.ingest.v1.CompleteRunRequest.EntitiesEntry
+	17, // 12: marmot.ingest.v1.LineageGraph.nodes:type_name -> marmot.ingest.v1.LineageNode
+	2,  // 13: marmot.ingest.v1.LineageGraph.edges:type_name -> marmot.ingest.v1.Lineage
+	11, // 14: marmot.ingest.v1.IngestResponse.SummaryEntry.value:type_name -> marmot.ingest.v1.EntityCounts
+	11, // 15: marmot.ingest.v1.CompleteRunRequest.EntitiesEntry.value:type_name -> marmot.ingest.v1.EntityCounts
+	7,  // 16: marmot.ingest.v1.IngestService.StartRun:input_type -> marmot.ingest.v1.StartRunRequest
+	8,  // 17: marmot.ingest.v1.IngestService.Ingest:input_type -> marmot.ingest.v1.IngestRequest
+	12, // 18: marmot.ingest.v1.IngestService.CompleteRun:input_type -> marmot.ingest.v1.CompleteRunRequest
+	14, // 19: marmot.ingest.v1.IngestService.GetAsset:input_type -> marmot.ingest.v1.GetAssetRequest
+	15, // 20: marmot.ingest.v1.IngestService.GetLineage:input_type -> marmot.ingest.v1.GetLineageRequest
+	6,  // 21: marmot.ingest.v1.IngestService.StartRun:output_type -> marmot.ingest.v1.Run
+	9,  // 22: marmot.ingest.v1.IngestService.Ingest:output_type -> marmot.ingest.v1.IngestResponse
+	13, // 23: marmot.ingest.v1.IngestService.CompleteRun:output_type -> marmot.ingest.v1.CompleteRunResponse
+	0,  // 24: marmot.ingest.v1.IngestService.GetAsset:output_type -> marmot.ingest.v1.Asset
+	16, // 25: marmot.ingest.v1.IngestService.GetLineage:output_type -> marmot.ingest.v1.LineageGraph
+	21, // [21:26] is the sub-list for method output_type
+	16, // [16:21] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_marmot_ingest_v1_ingest_proto_init() }
+func file_marmot_ingest_v1_ingest_proto_init() {
+	if File_marmot_ingest_v1_ingest_proto != nil {
+		return
+	}
+	file_marmot_ingest_v1_ingest_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_marmot_ingest_v1_ingest_proto_rawDesc), len(file_marmot_ingest_v1_ingest_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_marmot_ingest_v1_ingest_proto_goTypes,
+		DependencyIndexes: file_marmot_ingest_v1_ingest_proto_depIdxs,
+		MessageInfos:      file_marmot_ingest_v1_ingest_proto_msgTypes,
+	}.Build()
+	File_marmot_ingest_v1_ingest_proto = out.File
+	file_marmot_ingest_v1_ingest_proto_goTypes = nil
+	file_marmot_ingest_v1_ingest_proto_depIdxs = nil
+}
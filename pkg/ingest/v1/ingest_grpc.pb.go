@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: marmot/ingest/v1/ingest.proto
+
+package ingestv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IngestService_StartRun_FullMethodName    = "/marmot.ingest.v1.IngestService/StartRun"
+	IngestService_Ingest_FullMethodName      = "/marmot.ingest.v1.IngestService/Ingest"
+	IngestService_CompleteRun_FullMethodName = "/marmot.ingest.v1.IngestService/CompleteRun"
+	IngestService_GetAsset_FullMethodName    = "/marmot.ingest.v1.IngestService/GetAsset"
+	IngestService_GetLineage_FullMethodName  = "/marmot.ingest.v1.IngestService/GetLineage"
+)
+
+// IngestServiceClient is the client API for IngestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IngestService is the gRPC counterpart of the /api/v1/runs endpoints, meant
+// for agents that push large volumes of metadata. Calls are authenticated
+// with an API key in the "x-api-key" metadata header.
+type IngestServiceClient interface {
+	// StartRun opens an ingestion run for a pipeline and source.
+	StartRun(ctx context.Context, in *StartRunRequest, opts ...grpc.CallOption) (*Run, error)
+	// Ingest streams entity batches for a run. Each batch is answered with its
+	// results once it has been stored, so agents can bound how much they have
+	// in flight. Stale assets are not removed until CompleteRun.
+	Ingest(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[IngestRequest, IngestResponse], error)
+	// CompleteRun finishes a run. When the run succeeded, assets the source
+	// reported last time but not in this run are removed.
+	CompleteRun(ctx context.Context, in *CompleteRunRequest, opts ...grpc.CallOption) (*CompleteRunResponse, error)
+	// GetAsset looks an asset up by MRN.
+	GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error)
+	// GetLineage returns the lineage graph around an asset.
+	GetLineage(ctx context.Context, in *GetLineageRequest, opts ...grpc.CallOption) (*LineageGraph, error)
+}
+
+type ingestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIngestServiceClient(cc grpc.ClientConnInterface) IngestServiceClient {
+	return &ingestServiceClient{cc}
+}
+
+func (c *ingestServiceClient) StartRun(ctx context.Context, in *StartRunRequest, opts ...grpc.CallOption) (*Run, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Run)
+	err := c.cc.Invoke(ctx, IngestService_StartRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestServiceClient) Ingest(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[IngestRequest, IngestResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IngestService_ServiceDesc.Streams[0], IngestService_Ingest_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestRequest, IngestResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IngestService_IngestClient = grpc.BidiStreamingClient[IngestRequest, IngestResponse]
+
+func (c *ingestServiceClient) CompleteRun(ctx context.Context, in *CompleteRunRequest, opts ...grpc.CallOption) (*CompleteRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompleteRunResponse)
+	err := c.cc.Invoke(ctx, IngestService_CompleteRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestServiceClient) GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, IngestService_GetAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestServiceClient) GetLineage(ctx context.Context, in *GetLineageRequest, opts ...grpc.CallOption) (*LineageGraph, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LineageGraph)
+	err := c.cc.Invoke(ctx, IngestService_GetLineage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IngestServiceServer is the server API for IngestService service.
+// All implementations must embed UnimplementedIngestServiceServer
+// for forward compatibility.
+//
+// IngestService is the gRPC counterpart of the /api/v1/runs endpoints, meant
+// for agents that push large volumes of metadata. Calls are authenticated
+// with an API key in the "x-api-key" metadata header.
+type IngestServiceServer interface {
+	// StartRun opens an ingestion run for a pipeline and source.
+	StartRun(context.Context, *StartRunRequest) (*Run, error)
+	// Ingest streams entity batches for a run. Each batch is answered with its
+	// results once it has been stored, so agents can bound how much they have
+	// in flight. Stale assets are not removed until CompleteRun.
+	Ingest(grpc.BidiStreamingServer[IngestRequest, IngestResponse]) error
+	// CompleteRun finishes a run. When the run succeeded, assets the source
+	// reported last time but not in this run are removed.
+	CompleteRun(context.Context, *CompleteRunRequest) (*CompleteRunResponse, error)
+	// GetAsset looks an asset up by MRN.
+	GetAsset(context.Context, *GetAssetRequest) (*Asset, error)
+	// GetLineage returns the lineage graph around an asset.
+	GetLineage(context.Context, *GetLineageRequest) (*LineageGraph, error)
+	mustEmbedUnimplementedIngestServiceServer()
+}
+
+// UnimplementedIngestServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIngestServiceServer struct{}
+
+func (UnimplementedIngestServiceServer) StartRun(context.Context, *StartRunRequest) (*Run, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRun not implemented")
+}
+func (UnimplementedIngestServiceServer) Ingest(grpc.BidiStreamingServer[IngestRequest, IngestResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Ingest not implemented")
+}
+func (UnimplementedIngestServiceServer) CompleteRun(context.Context, *CompleteRunRequest) (*CompleteRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteRun not implemented")
+}
+func (UnimplementedIngestServiceServer) GetAsset(context.Context, *GetAssetRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsset not implemented")
+}
+func (UnimplementedIngestServiceServer) GetLineage(context.Context, *GetLineageRequest) (*LineageGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLineage not implemented")
+}
+func (UnimplementedIngestServiceServer) mustEmbedUnimplementedIngestServiceServer() {}
+func (UnimplementedIngestServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeIngestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IngestServiceServer will
+// result in compilation errors.
+type UnsafeIngestServiceServer interface {
+	mustEmbedUnimplementedIngestServiceServer()
+}
+
+func RegisterIngestServiceServer(s grpc.ServiceRegistrar, srv IngestServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIngestServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IngestService_ServiceDesc, srv)
+}
+
+func _IngestService_StartRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestServiceServer).StartRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestService_StartRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestServiceServer).StartRun(ctx, req.(*StartRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestService_Ingest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngestServiceServer).Ingest(&grpc.GenericServerStream[IngestRequest, IngestResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IngestService_IngestServer = grpc.BidiStreamingServer[IngestRequest, IngestResponse]
+
+func _IngestService_CompleteRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestServiceServer).CompleteRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestService_CompleteRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestServiceServer).CompleteRun(ctx, req.(*CompleteRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestService_GetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestServiceServer).GetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestService_GetAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestServiceServer).GetAsset(ctx, req.(*GetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestService_GetLineage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLineageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestServiceServer).GetLineage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestService_GetLineage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestServiceServer).GetLineage(ctx, req.(*GetLineageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IngestService_ServiceDesc is the grpc.ServiceDesc for IngestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IngestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "marmot.ingest.v1.IngestService",
+	HandlerType: (*IngestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartRun",
+			Handler:    _IngestService_StartRun_Handler,
+		},
+		{
+			MethodName: "CompleteRun",
+			Handler:    _IngestService_CompleteRun_Handler,
+		},
+		{
+			MethodName: "GetAsset",
+			Handler:    _IngestService_GetAsset_Handler,
+		},
+		{
+			MethodName: "GetLineage",
+			Handler:    _IngestService_GetLineage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Ingest",
+			Handler:       _IngestService_Ingest_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "marmot/ingest/v1/ingest.proto",
+}
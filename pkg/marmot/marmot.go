@@ -21,7 +21,7 @@ type Server struct {
 // External embedders get a local in-memory lookup recorder that is never
 // drained — sufficient for handler correctness but no telemetry is sent.
 func NewServer(cfg *config.Config, db *pgxpool.Pool) *Server {
-	return &Server{internal: v1.New(cfg, db, lookups.NewRecorder())}
+	return &Server{internal: v1.New(cfg, db, lookups.NewRecorder(), nil)}
 }
 
 // RegisterRoutes registers all API routes on the given ServeMux.
@@ -59,6 +59,9 @@ type DAGCollection struct {
 type Task struct {
 	TaskID              string                 `json:"task_id"`
 	TaskDisplayName     string                 `json:"task_display_name,omitempty"`
+	Owner               string                 `json:"owner"`
+	DocMD               *string                `json:"doc_md"`
+	IsMapped            bool                   `json:"is_mapped"`
 	OperatorName        string                 `json:"operator_name"`
 	ClassName           string                 `json:"class_ref,omitempty"`
 	Pool                string                 `json:"pool"`
@@ -8,6 +8,8 @@ type AirflowDAGFields struct {
 	Description      string  `json:"description" metadata:"description" description:"DAG description"`
 	FilePath         string  `json:"file_path" metadata:"file_path" description:"Path to DAG definition file"`
 	ScheduleInterval string  `json:"schedule_interval" metadata:"schedule_interval" description:"DAG schedule (cron expression or preset)"`
+	ScheduleDesc     string  `json:"schedule_description" metadata:"schedule_description" description:"Human-readable description of the DAG's timetable"`
+	DAGTags          string  `json:"dag_tags" metadata:"dag_tags" description:"Tags set on the DAG in Airflow (comma-separated)"`
 	IsPaused         bool    `json:"is_paused" metadata:"is_paused" description:"Whether DAG is paused"`
 	IsActive         bool    `json:"is_active" metadata:"is_active" description:"Whether DAG is active"`
 	Owners           string  `json:"owners" metadata:"owners" description:"DAG owners (comma-separated)"`
@@ -25,6 +27,8 @@ type AirflowTaskFields struct {
 	TaskID          string   `json:"task_id" metadata:"task_id" description:"Task identifier within the DAG"`
 	DagID           string   `json:"dag_id" metadata:"dag_id" description:"Parent DAG ID"`
 	OperatorName    string   `json:"operator_name" metadata:"operator_name" description:"Airflow operator class name (e.g., BashOperator, PythonOperator)"`
+	Owner           string   `json:"owner" metadata:"owner" description:"Task owner"`
+	IsMapped        bool     `json:"is_mapped" metadata:"is_mapped" description:"Whether the task is dynamically mapped"`
 	TriggerRule     string   `json:"trigger_rule" metadata:"trigger_rule" description:"Task trigger rule (e.g., all_success, one_success)"`
 	Retries         int      `json:"retries" metadata:"retries" description:"Number of retries configured for the task"`
 	Pool            string   `json:"pool" metadata:"pool" description:"Execution pool for the task"`
@@ -106,7 +106,7 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	}
 
 	if s.config.DiscoverDatasets {
-		datasetAssets, datasetLineages, err := s.discoverDatasets(ctx)
+		datasetAssets, datasetLineages, err := s.discoverDatasets(ctx, discoveredMRNs(assets))
 		if err != nil {
 			log.Warn().Err(err).Msg("Failed to discover datasets (requires Airflow 2.4+)")
 		} else {
@@ -220,8 +220,10 @@ func (s *Source) discoverTasks(ctx context.Context, dagID string) ([]pluginsdk.A
 	return assets, lineages, nil
 }
 
-// discoverDatasets discovers Airflow Datasets and creates lineage.
-func (s *Source) discoverDatasets(ctx context.Context) ([]pluginsdk.Asset, []pluginsdk.LineageEdge, error) {
+// discoverDatasets discovers Airflow Datasets and creates lineage. Datasets
+// are linked to the DAGs they trigger and the DAGs that update them, and to
+// the producing tasks themselves when those tasks were discovered.
+func (s *Source) discoverDatasets(ctx context.Context, discovered map[string]bool) ([]pluginsdk.Asset, []pluginsdk.LineageEdge, error) {
 	var assets []pluginsdk.Asset
 	var lineages []pluginsdk.LineageEdge
 
@@ -255,12 +257,32 @@ func (s *Source) discoverDatasets(ctx context.Context) ([]pluginsdk.Asset, []plu
 				Target: datasetMRN,
 				Type:   "PRODUCES",
 			})
+
+			taskMRN := mrn.New("Task", "Airflow", fmt.Sprintf("%s.%s", producer.DagID, producer.TaskID))
+			if discovered[taskMRN] {
+				lineages = append(lineages, pluginsdk.LineageEdge{
+					Source: taskMRN,
+					Target: datasetMRN,
+					Type:   "PRODUCES",
+				})
+			}
 		}
 	}
 
 	return assets, lineages, nil
 }
 
+// discoveredMRNs returns the set of MRNs in assets.
+func discoveredMRNs(assets []pluginsdk.Asset) map[string]bool {
+	mrns := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		if a.MRN != nil {
+			mrns[*a.MRN] = true
+		}
+	}
+	return mrns
+}
+
 // createDAGAsset creates a Pipeline asset from an Airflow DAG.
 func (s *Source) createDAGAsset(dag DAG) pluginsdk.Asset {
 	mrnValue := mrn.New("Pipeline", "Airflow", dag.DagID)
@@ -285,6 +307,20 @@ func (s *Source) createDAGAsset(dag DAG) pluginsdk.Asset {
 		metadata["schedule_interval"] = dag.ScheduleInterval.Value
 	}
 
+	// Dataset-triggered and custom timetables have no schedule_interval, but
+	// the timetable description still says when the DAG runs.
+	if dag.TimetableDesc != nil {
+		metadata["schedule_description"] = *dag.TimetableDesc
+	}
+
+	if len(dag.Tags) > 0 {
+		tags := make([]string, 0, len(dag.Tags))
+		for _, tag := range dag.Tags {
+			tags = append(tags, tag.Name)
+		}
+		metadata["dag_tags"] = strings.Join(tags, ", ")
+	}
+
 	if dag.NextDagRun != nil {
 		metadata["next_run_date"] = *dag.NextDagRun
 	}
@@ -438,6 +474,11 @@ func (s *Source) createTaskAsset(dagID string, task Task) pluginsdk.Asset {
 		"dag_id":        dagID,
 		"operator_name": task.OperatorName,
 		"trigger_rule":  task.TriggerRule,
+		"owner":         task.Owner,
+	}
+
+	if task.IsMapped {
+		metadata["is_mapped"] = true
 	}
 
 	if task.Retries > 0 {
@@ -452,15 +493,21 @@ func (s *Source) createTaskAsset(dagID string, task Task) pluginsdk.Asset {
 		metadata["downstream_tasks"] = task.DownstreamTaskIDs
 	}
 
+	var description *string
+	if task.DocMD != nil && *task.DocMD != "" {
+		description = task.DocMD
+	}
+
 	cleanMetadata := s.cleanMetadata(metadata)
 
 	return pluginsdk.Asset{
-		Name:      &taskName,
-		MRN:       &mrnValue,
-		Type:      "Task",
-		Providers: []string{"Airflow"},
-		Metadata:  cleanMetadata,
-		Tags:      s.config.Tags,
+		Name:        &taskName,
+		MRN:         &mrnValue,
+		Type:        "Task",
+		Providers:   []string{"Airflow"},
+		Description: description,
+		Metadata:    cleanMetadata,
+		Tags:        s.config.Tags,
 		Sources: []pluginsdk.AssetSource{{
 			Name:       "Airflow",
 			LastSyncAt: time.Now(),
@@ -463,6 +463,102 @@ func TestCreateTaskAsset(t *testing.T) {
 	assert.Equal(t, "default_pool", asset.Metadata["pool"])
 }
 
+func TestCreateTaskAsset_OwnerAndDocs(t *testing.T) {
+	s := &Source{config: &Config{}}
+
+	doc := "Loads orders into the warehouse."
+	task := Task{
+		TaskID:       "load_orders",
+		OperatorName: "PythonOperator",
+		Owner:        "data-eng",
+		DocMD:        &doc,
+		IsMapped:     true,
+	}
+
+	asset := s.createTaskAsset("orders", task)
+
+	assert.Equal(t, "data-eng", asset.Metadata["owner"])
+	assert.Equal(t, true, asset.Metadata["is_mapped"])
+	require.NotNil(t, asset.Description)
+	assert.Equal(t, doc, *asset.Description)
+}
+
+func TestCreateDAGAsset_Timetable(t *testing.T) {
+	s := &Source{config: &Config{}}
+
+	timetable := "Triggered by datasets"
+	dag := DAG{
+		DagID:         "orders_report",
+		TimetableDesc: &timetable,
+		Tags:          []Tag{{Name: "finance"}, {Name: "daily"}},
+	}
+
+	asset := s.createDAGAsset(dag)
+
+	assert.NotContains(t, asset.Metadata, "schedule_interval")
+	assert.Equal(t, "Triggered by datasets", asset.Metadata["schedule_description"])
+	assert.Equal(t, "finance, daily", asset.Metadata["dag_tags"])
+}
+
+func TestSource_DiscoverTaskDatasetLineage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/dags":
+			_ = json.NewEncoder(w).Encode(DAGCollection{
+				DAGs: []DAG{{DagID: "producer", IsActive: true}},
+			})
+		case "/api/v1/dags/producer/tasks":
+			_ = json.NewEncoder(w).Encode(TaskCollection{
+				Tasks: []Task{{TaskID: "write_orders"}},
+			})
+		case "/api/v1/datasets":
+			_ = json.NewEncoder(w).Encode(DatasetCollection{
+				Datasets: []Dataset{{
+					URI: "s3://orders",
+					ProducingTasks: []TaskRef{
+						{DagID: "producer", TaskID: "write_orders"},
+						{DagID: "paused", TaskID: "write_orders"},
+					},
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := &Source{}
+	result, err := s.Discover(context.Background(), pluginsdk.RawConfig{
+		"host":              server.URL,
+		"username":          "admin",
+		"password":          "admin",
+		"discover_dags":     true,
+		"discover_tasks":    true,
+		"discover_datasets": true,
+	})
+	require.NoError(t, err)
+
+	datasetMRN := "mrn://bucket/s3/orders"
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{
+		Source: "mrn://task/airflow/producer.write_orders",
+		Target: datasetMRN,
+		Type:   "PRODUCES",
+	})
+	// Tasks that weren't discovered are only linked through their DAG.
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{
+		Source: "mrn://pipeline/airflow/paused",
+		Target: datasetMRN,
+		Type:   "PRODUCES",
+	})
+	assert.NotContains(t, result.Lineage, pluginsdk.LineageEdge{
+		Source: "mrn://task/airflow/paused.write_orders",
+		Target: datasetMRN,
+		Type:   "PRODUCES",
+	})
+}
+
 func TestCreateDatasetAsset(t *testing.T) {
 	s := &Source{
 		config: &Config{
@@ -0,0 +1,62 @@
+package azureblob
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/marmotdata/marmot/plugins/objectstore"
+)
+
+// containerStore implements objectstore.Store for one container.
+type containerStore struct {
+	client *azblob.Client
+	name   string
+}
+
+func (c *containerStore) container() *container.Client {
+	return c.client.ServiceClient().NewContainerClient(c.name)
+}
+
+func (c *containerStore) List(ctx context.Context, prefix string, fn func(objectstore.Object) error) error {
+	pager := c.container().NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			obj := objectstore.Object{Key: *item.Name}
+			if props := item.Properties; props != nil {
+				if props.ContentLength != nil {
+					obj.Size = *props.ContentLength
+				}
+				if props.LastModified != nil {
+					obj.LastModified = *props.LastModified
+				}
+			}
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *containerStore) ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	resp, err := c.container().NewBlobClient(key).DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: offset, Count: length},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
@@ -1,5 +1,5 @@
 // Package azureblob discovers containers from Azure Blob Storage
-// accounts.
+// accounts, and the datasets stored in them.
 package azureblob
 
 import (
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/marmotdata/marmot/plugins/objectstore"
 	pluginsdk "github.com/marmotdata/plugin-sdk"
 	"github.com/marmotdata/plugin-sdk/mrn"
 	"github.com/rs/zerolog/log"
@@ -19,11 +20,11 @@ func Meta() pluginsdk.Meta {
 	return pluginsdk.Meta{
 		ID:          "azureblob",
 		Name:        "Azure Blob Storage",
-		Description: "Discover containers from Azure Blob Storage accounts",
+		Description: "Discover containers and the datasets stored in them from Azure Blob Storage accounts",
 		Icon:        "azureblob",
 		Category:    "storage",
 		Status:      "experimental",
-		Features:    []string{"Assets"},
+		Features:    []string{"Assets", "Lineage"},
 		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
 	}
 }
@@ -41,6 +42,8 @@ type Config struct {
 	// Discovery options
 	IncludeMetadata  bool `json:"include_metadata" description:"Include container metadata" default:"true"`
 	IncludeBlobCount bool `json:"include_blob_count" description:"Count blobs in each container (can be slow for large containers)" default:"false"`
+
+	objectstore.DatasetConfig `json:",inline"`
 }
 
 // Example configuration for the plugin
@@ -48,6 +51,7 @@ var _ = `
 connection_string: "${AZURE_STORAGE_CONNECTION_STRING}"
 include_metadata: true
 include_blob_count: false
+discover_datasets: true
 filter:
   include:
     - "^data-.*"
@@ -69,6 +73,8 @@ func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, e
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
 
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
 	if config.ConnectionString == "" && config.AccountName == "" {
 		return nil, fmt.Errorf("either connection_string or account_name must be provided")
 	}
@@ -90,6 +96,7 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
+	pluginsdk.ApplyDefaults(config, pluginConfig)
 	s.config = config
 
 	client, err := s.createClient()
@@ -115,6 +122,25 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 			continue
 		}
 		assets = append(assets, asset)
+
+		if s.config.DiscoverDatasets {
+			datasets, edges, err := objectstore.Discover(ctx, &containerStore{
+				client: s.client,
+				name:   containerName,
+			}, objectstore.Target{
+				Provider:  "AzureBlob",
+				Bucket:    containerName,
+				BucketMRN: *asset.MRN,
+				Scheme:    "az",
+				Tags:      s.config.Tags,
+			}, s.config.DatasetConfig)
+			if err != nil {
+				log.Warn().Err(err).Str("container", containerName).Msg("Failed to discover datasets in container")
+				continue
+			}
+			assets = append(assets, datasets...)
+			lineages = append(lineages, edges...)
+		}
 	}
 
 	return &pluginsdk.DiscoveryResult{
@@ -102,4 +102,8 @@ func TestSource_ValidateDefaults(t *testing.T) {
 
 	assert.NotNil(t, s.config)
 	assert.Equal(t, "DefaultEndpointsProtocol=https;AccountName=test;AccountKey=key123;EndpointSuffix=core.windows.net", s.config.ConnectionString)
+	assert.True(t, s.config.IncludeMetadata)
+	assert.False(t, s.config.DiscoverDatasets)
+	assert.Equal(t, 10000, s.config.MaxObjects)
+	assert.True(t, s.config.SampleSchemas)
 }
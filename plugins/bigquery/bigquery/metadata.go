@@ -19,7 +19,7 @@ type BigQueryTableFields struct {
 	ProjectID              string                 `json:"project_id" metadata:"project_id" description:"Google Cloud Project ID"`
 	DatasetID              string                 `json:"dataset_id" metadata:"dataset_id" description:"Dataset ID"`
 	TableID                string                 `json:"table_id" metadata:"table_id" description:"Table ID"`
-	TableType              string                 `json:"table_type" metadata:"table_type" description:"Table type (TABLE, VIEW, EXTERNAL)"`
+	TableType              string                 `json:"table_type" metadata:"table_type" description:"Table type (TABLE, VIEW, MATERIALIZED_VIEW, EXTERNAL)"`
 	CreationTime           string                 `json:"creation_time" metadata:"creation_time" description:"Table creation timestamp"`
 	LastModified           string                 `json:"last_modified" metadata:"last_modified" description:"Last modification timestamp"`
 	Description            string                 `json:"description" metadata:"description" description:"Table description"`
@@ -32,7 +32,10 @@ type BigQueryTableFields struct {
 	PartitionExpiration    string                 `json:"partition_expiration" metadata:"partition_expiration" description:"Partition expiration duration"`
 	RangePartitioningField string                 `json:"range_partitioning_field" metadata:"range_partitioning_field" description:"Range partitioning field"`
 	ClusteringFields       []string               `json:"clustering_fields" metadata:"clustering_fields" description:"Clustering fields"`
-	ViewQuery              string                 `json:"view_query" metadata:"view_query" description:"SQL query for views"`
+	ViewQuery              string                 `json:"view_query" metadata:"view_query" description:"SQL query for views and materialized views"`
+	EnableRefresh          bool                   `json:"enable_refresh" metadata:"enable_refresh" description:"Whether the materialized view refreshes automatically"`
+	RefreshInterval        string                 `json:"refresh_interval" metadata:"refresh_interval" description:"Maximum refresh frequency of the materialized view"`
+	LastRefreshTime        string                 `json:"last_refresh_time" metadata:"last_refresh_time" description:"Last refresh timestamp of the materialized view"`
 	ExternalDataConfig     map[string]interface{} `json:"external_data_config" metadata:"external_data_config" description:"External data configuration for external tables"`
 }
 
@@ -49,4 +52,3 @@ type BigQueryExternalDataConfig struct {
 	SourceFormat string   `json:"source_format" metadata:"source_format" description:"Source data format (CSV, JSON, AVRO, etc.)"`
 	SourceURIs   []string `json:"source_uris" metadata:"source_uris" description:"Source URIs for external data"`
 }
-
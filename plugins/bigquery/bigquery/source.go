@@ -1,5 +1,5 @@
-// Package bigquery discovers datasets and tables from Google BigQuery
-// projects.
+// Package bigquery discovers datasets, tables and views from Google BigQuery
+// projects, with lineage from the project's query job history.
 package bigquery
 
 import (
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,7 +24,7 @@ func Meta() pluginsdk.Meta {
 	return pluginsdk.Meta{
 		ID:          "bigquery",
 		Name:        "BigQuery",
-		Description: "Discover datasets and tables from Google BigQuery projects",
+		Description: "Discover datasets, tables and views from Google BigQuery projects",
 		Icon:        "bigquery",
 		Category:    "data-warehouse",
 		Status:      "experimental",
@@ -40,18 +41,24 @@ type Config struct {
 	CredentialsJSON       string `json:"credentials_json,omitempty" description:"Service account credentials JSON content" sensitive:"true"`
 	UseDefaultCredentials bool   `json:"use_default_credentials" description:"Use default Google Cloud credentials" default:"false"`
 
-	IncludeDatasets       bool `json:"include_datasets" description:"Whether to discover datasets" default:"true"`
-	IncludeTableStats     bool `json:"include_table_stats" description:"Whether to include table statistics (row count, size)" default:"true"`
-	IncludeViews          bool `json:"include_views" description:"Whether to discover views" default:"true"`
-	IncludeExternalTables bool `json:"include_external_tables" description:"Whether to discover external tables" default:"true"`
-	ExcludeSystemDatasets bool `json:"exclude_system_datasets" description:"Whether to exclude system datasets (_script, _analytics, etc.)" default:"true"`
-	MaxConcurrentRequests int  `json:"max_concurrent_requests" description:"Maximum number of concurrent API requests" default:"10" validate:"omitempty,min=1,max=100"`
+	IncludeDatasets          bool `json:"include_datasets" description:"Whether to discover datasets" default:"true"`
+	IncludeTableStats        bool `json:"include_table_stats" description:"Whether to include table statistics (row count, size)" default:"true"`
+	IncludeViews             bool `json:"include_views" description:"Whether to discover views" default:"true"`
+	IncludeMaterializedViews bool `json:"include_materialized_views" description:"Whether to discover materialized views" default:"true"`
+	IncludeExternalTables    bool `json:"include_external_tables" description:"Whether to discover external tables" default:"true"`
+	ExcludeSystemDatasets    bool `json:"exclude_system_datasets" description:"Whether to exclude system datasets (_script, _analytics, etc.)" default:"true"`
+	LabelsAsTags             bool `json:"labels_as_tags" description:"Whether to add dataset and table labels as tags (key:value)" default:"true"`
+	IncludeJobLineage        bool `json:"include_job_lineage" description:"Whether to derive table lineage from query jobs in INFORMATION_SCHEMA.JOBS" default:"false"`
+	JobLineageDays           int  `json:"job_lineage_days" description:"How many days of query jobs to read for lineage" default:"7" validate:"omitempty,min=1,max=180"`
+	MaxConcurrentRequests    int  `json:"max_concurrent_requests" description:"Maximum number of concurrent API requests" default:"10" validate:"omitempty,min=1,max=100"`
 }
 
 // Example configuration for the plugin
 var _ = `
 project_id: "company-data-warehouse"
 credentials_path: "/etc/marmot/bq-service-account.json"
+include_job_lineage: true
+job_lineage_days: 14
 tags:
   - "bigquery"
   - "data-warehouse"
@@ -65,15 +72,19 @@ type Source struct {
 type TableType string
 
 const (
-	TableTypeTable    TableType = "TABLE"
-	TableTypeView     TableType = "VIEW"
-	TableTypeExternal TableType = "EXTERNAL"
+	TableTypeTable            TableType = "TABLE"
+	TableTypeView             TableType = "VIEW"
+	TableTypeMaterializedView TableType = "MATERIALIZED_VIEW"
+	TableTypeExternal         TableType = "EXTERNAL"
 )
 
 func (c *Config) ApplyDefaults(rawConfig pluginsdk.RawConfig) {
 	if c.MaxConcurrentRequests == 0 {
 		c.MaxConcurrentRequests = 10
 	}
+	if c.JobLineageDays == 0 {
+		c.JobLineageDays = 7
+	}
 
 	if _, exists := rawConfig["include_datasets"]; !exists {
 		c.IncludeDatasets = true
@@ -84,12 +95,18 @@ func (c *Config) ApplyDefaults(rawConfig pluginsdk.RawConfig) {
 	if _, exists := rawConfig["include_views"]; !exists {
 		c.IncludeViews = true
 	}
+	if _, exists := rawConfig["include_materialized_views"]; !exists {
+		c.IncludeMaterializedViews = true
+	}
 	if _, exists := rawConfig["include_external_tables"]; !exists {
 		c.IncludeExternalTables = true
 	}
 	if _, exists := rawConfig["exclude_system_datasets"]; !exists {
 		c.ExcludeSystemDatasets = true
 	}
+	if _, exists := rawConfig["labels_as_tags"]; !exists {
+		c.LabelsAsTags = true
+	}
 }
 
 func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
@@ -154,6 +171,11 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 		log.Debug().Int("count", len(datasetAssets)).Msg("Discovered datasets")
 	}
 
+	// Job lineage joins on fully qualified table names, and JOBS is
+	// queried once per region the datasets live in.
+	tableMRNs := make(map[string]string)
+	locations := make(map[string]bool)
+
 	// Discover tables in all datasets
 	for _, datasetAsset := range datasetAssets {
 		if datasetAsset.Type != "Dataset" {
@@ -161,6 +183,9 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 		}
 
 		datasetID := *datasetAsset.Name
+		if location, _ := datasetAsset.Metadata["location"].(string); location != "" {
+			locations[location] = true
+		}
 
 		log.Debug().Str("dataset", datasetID).Msg("Starting table discovery")
 		tableAssets, err := s.discoverTables(ctx, datasetID)
@@ -172,6 +197,11 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 		assets = append(assets, tableAssets...)
 		log.Debug().Int("count", len(tableAssets)).Str("dataset", datasetID).Msg("Discovered tables")
 
+		for _, tableAsset := range tableAssets {
+			tableID, _ := tableAsset.Metadata["table_id"].(string)
+			tableMRNs[qualifiedTableName(s.config.ProjectID, datasetID, tableID)] = *tableAsset.MRN
+		}
+
 		// Only create lineage if datasets are included
 		if s.config.IncludeDatasets {
 			for _, tableAsset := range tableAssets {
@@ -184,6 +214,16 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 		}
 	}
 
+	if s.config.IncludeJobLineage {
+		jobLineage, err := s.discoverJobLineage(ctx, locations, tableMRNs)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to discover lineage from query jobs")
+		} else {
+			lineages = append(lineages, jobLineage...)
+			log.Debug().Int("count", len(jobLineage)).Msg("Discovered lineage from query jobs")
+		}
+	}
+
 	return &pluginsdk.DiscoveryResult{
 		Assets:  assets,
 		Lineage: lineages,
@@ -295,6 +335,9 @@ func (s *Source) discoverDatasets(ctx context.Context) ([]pluginsdk.Asset, error
 		mrnValue := mrn.New("Dataset", "BigQuery", datasetID)
 
 		processedTags := pluginsdk.InterpolateTags(s.config.Tags, assetMetadata)
+		if s.config.LabelsAsTags {
+			processedTags = appendLabelTags(processedTags, metadata.Labels)
+		}
 
 		assets = append(assets, pluginsdk.Asset{
 			Name:      &datasetID,
@@ -345,6 +388,10 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 			continue
 		}
 
+		if tableType == TableTypeMaterializedView && !s.config.IncludeMaterializedViews {
+			continue
+		}
+
 		if tableType == TableTypeExternal && !s.config.IncludeExternalTables {
 			continue
 		}
@@ -375,7 +422,7 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 			assetMetadata["labels"] = metadata.Labels
 		}
 
-		if s.config.IncludeTableStats && tableType == TableTypeTable {
+		if s.config.IncludeTableStats && (tableType == TableTypeTable || tableType == TableTypeMaterializedView) {
 			if metadata.NumRows > 0 {
 				assetMetadata["num_rows"] = metadata.NumRows
 			}
@@ -406,6 +453,20 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 			assetMetadata["view_query"] = metadata.ViewQuery
 		}
 
+		if tableType == TableTypeMaterializedView {
+			mv := metadata.MaterializedView
+			if mv.Query != "" {
+				assetMetadata["view_query"] = mv.Query
+			}
+			assetMetadata["enable_refresh"] = mv.EnableRefresh
+			if mv.RefreshInterval > 0 {
+				assetMetadata["refresh_interval"] = mv.RefreshInterval.String()
+			}
+			if !mv.LastRefreshTime.IsZero() {
+				assetMetadata["last_refresh_time"] = mv.LastRefreshTime.Format(time.RFC3339)
+			}
+		}
+
 		if tableType == TableTypeExternal && metadata.ExternalDataConfig != nil {
 			externalConfig := make(map[string]interface{})
 			externalConfig["source_format"] = string(metadata.ExternalDataConfig.SourceFormat)
@@ -425,6 +486,9 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 		case TableTypeView:
 			assetType = "View"
 			assetDesc = fmt.Sprintf("BigQuery view %s.%s in project %s", datasetID, tableID, s.config.ProjectID)
+		case TableTypeMaterializedView:
+			assetType = "MaterializedView"
+			assetDesc = fmt.Sprintf("BigQuery materialized view %s.%s in project %s", datasetID, tableID, s.config.ProjectID)
 		case TableTypeExternal:
 			assetType = "ExternalTable"
 			assetDesc = fmt.Sprintf("BigQuery external table %s.%s in project %s", datasetID, tableID, s.config.ProjectID)
@@ -435,9 +499,12 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 		mrnValue := mrn.New(assetType, "BigQuery", tableID)
 
 		processedTags := pluginsdk.InterpolateTags(s.config.Tags, assetMetadata)
+		if s.config.LabelsAsTags {
+			processedTags = appendLabelTags(processedTags, metadata.Labels)
+		}
 
 		var schema map[string]string
-		if metadata.Schema != nil && (tableType == TableTypeTable || tableType == TableTypeView) {
+		if metadata.Schema != nil && tableType != TableTypeExternal {
 			jsonSchema := s.generateJSONSchema(metadata.Schema, tableID)
 			schemaBytes, _ := json.Marshal(jsonSchema)
 			schema = map[string]string{"json_schema": string(schemaBytes)}
@@ -465,6 +532,9 @@ func (s *Source) discoverTables(ctx context.Context, datasetID string) ([]plugin
 }
 
 func (s *Source) getTableType(metadata *bigquery.TableMetadata) TableType {
+	if metadata.MaterializedView != nil {
+		return TableTypeMaterializedView
+	}
 	if metadata.ViewQuery != "" {
 		return TableTypeView
 	}
@@ -474,6 +544,157 @@ func (s *Source) getTableType(metadata *bigquery.TableMetadata) TableType {
 	return TableTypeTable
 }
 
+// appendLabelTags adds labels to tags as "key:value", or just "key" for
+// labels without a value, which BigQuery uses as plain tags. Labels are
+// added in key order so tags are stable across runs.
+func appendLabelTags(tags []string, labels map[string]string) []string {
+	if len(labels) == 0 {
+		return tags
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		seen[tag] = true
+	}
+	for _, key := range keys {
+		tag := key
+		if value := labels[key]; value != "" {
+			tag = key + ":" + value
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func qualifiedTableName(projectID, datasetID, tableID string) string {
+	return projectID + "." + datasetID + "." + tableID
+}
+
+// jobLineageRow is a table a query job read from and the table it wrote to.
+type jobLineageRow struct {
+	SourceProject string `bigquery:"source_project"`
+	SourceDataset string `bigquery:"source_dataset"`
+	SourceTable   string `bigquery:"source_table"`
+	TargetProject string `bigquery:"target_project"`
+	TargetDataset string `bigquery:"target_dataset"`
+	TargetTable   string `bigquery:"target_table"`
+}
+
+// jobLineageQuery lists the distinct read/write table pairs of successful
+// query jobs. JOBS is regional, so it is formatted with the project and
+// region to query.
+const jobLineageQuery = `
+SELECT DISTINCT
+  ref.project_id AS source_project,
+  ref.dataset_id AS source_dataset,
+  ref.table_id AS source_table,
+  destination_table.project_id AS target_project,
+  destination_table.dataset_id AS target_dataset,
+  destination_table.table_id AS target_table
+FROM ` + "`%s`.`region-%s`" + `.INFORMATION_SCHEMA.JOBS, UNNEST(referenced_tables) AS ref
+WHERE creation_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @days DAY)
+  AND job_type = 'QUERY'
+  AND state = 'DONE'
+  AND error_result IS NULL
+  AND destination_table.table_id IS NOT NULL`
+
+// discoverJobLineage derives table-to-table lineage from the query jobs
+// that ran in each location over the last JobLineageDays days.
+func (s *Source) discoverJobLineage(ctx context.Context, locations map[string]bool, tableMRNs map[string]string) ([]pluginsdk.LineageEdge, error) {
+	regions := make([]string, 0, len(locations))
+	for location := range locations {
+		regions = append(regions, location)
+	}
+	sort.Strings(regions)
+
+	var rows []jobLineageRow
+	var lastErr error
+	for _, location := range regions {
+		regionRows, err := s.queryJobLineage(ctx, location)
+		if err != nil {
+			log.Warn().Err(err).Str("location", location).Msg("Failed to read query jobs")
+			lastErr = err
+			continue
+		}
+		rows = append(rows, regionRows...)
+	}
+
+	if len(rows) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return buildJobLineage(rows, tableMRNs), nil
+}
+
+func (s *Source) queryJobLineage(ctx context.Context, location string) ([]jobLineageRow, error) {
+	region := strings.ToLower(strings.ReplaceAll(location, "`", ""))
+	projectID := strings.ReplaceAll(s.config.ProjectID, "`", "")
+
+	q := s.client.Query(fmt.Sprintf(jobLineageQuery, projectID, region))
+	q.Location = location
+	q.Parameters = []bigquery.QueryParameter{{Name: "days", Value: s.config.JobLineageDays}}
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying INFORMATION_SCHEMA.JOBS in %s: %w", location, err)
+	}
+
+	var rows []jobLineageRow
+	for {
+		var row jobLineageRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading INFORMATION_SCHEMA.JOBS in %s: %w", location, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// buildJobLineage turns job rows into edges from the tables a job read to
+// the table it wrote. Only tables discovered in this run are linked, which
+// also drops the anonymous tables that hold plain query results.
+func buildJobLineage(rows []jobLineageRow, tableMRNs map[string]string) []pluginsdk.LineageEdge {
+	var edges []pluginsdk.LineageEdge
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		sourceMRN, ok := tableMRNs[qualifiedTableName(row.SourceProject, row.SourceDataset, row.SourceTable)]
+		if !ok {
+			continue
+		}
+		targetMRN, ok := tableMRNs[qualifiedTableName(row.TargetProject, row.TargetDataset, row.TargetTable)]
+		if !ok || sourceMRN == targetMRN {
+			continue
+		}
+
+		key := sourceMRN + "->" + targetMRN
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		edges = append(edges, pluginsdk.LineageEdge{
+			Source: sourceMRN,
+			Target: targetMRN,
+			Type:   "DEPENDS_ON",
+		})
+	}
+
+	return edges
+}
+
 func (s *Source) isSystemDataset(datasetID string) bool {
 	systemPrefixes := []string{
 		"_script",
@@ -0,0 +1,111 @@
+package bigquery
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/marmotdata/plugin-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_ValidateDefaults(t *testing.T) {
+	s := &Source{}
+	config := map[string]interface{}{
+		"project_id":       "test-project",
+		"credentials_path": "/path/to/creds.json",
+	}
+
+	_, err := s.Validate(pluginsdk.RawConfig(config))
+	require.NoError(t, err)
+
+	assert.True(t, s.config.IncludeMaterializedViews)
+	assert.True(t, s.config.LabelsAsTags)
+	assert.False(t, s.config.IncludeJobLineage)
+	assert.Equal(t, 7, s.config.JobLineageDays)
+}
+
+func TestSource_ValidateJobLineageDays(t *testing.T) {
+	s := &Source{}
+	config := map[string]interface{}{
+		"project_id":          "test-project",
+		"credentials_path":    "/path/to/creds.json",
+		"include_job_lineage": true,
+		"job_lineage_days":    365,
+	}
+
+	_, err := s.Validate(pluginsdk.RawConfig(config))
+	require.Error(t, err)
+}
+
+func TestGetTableType(t *testing.T) {
+	s := &Source{}
+
+	tests := []struct {
+		name     string
+		metadata *bigquery.TableMetadata
+		expected TableType
+	}{
+		{"table", &bigquery.TableMetadata{}, TableTypeTable},
+		{"view", &bigquery.TableMetadata{ViewQuery: "SELECT 1"}, TableTypeView},
+		{
+			"materialized view",
+			&bigquery.TableMetadata{MaterializedView: &bigquery.MaterializedViewDefinition{Query: "SELECT 1"}},
+			TableTypeMaterializedView,
+		},
+		{"external", &bigquery.TableMetadata{ExternalDataConfig: &bigquery.ExternalDataConfig{}}, TableTypeExternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.getTableType(tt.metadata))
+		})
+	}
+}
+
+func TestAppendLabelTags(t *testing.T) {
+	tags := appendLabelTags([]string{"bigquery", "env:prod"}, map[string]string{
+		"team":   "growth",
+		"env":    "prod",
+		"pii":    "",
+		"domain": "sales",
+	})
+
+	assert.Equal(t, []string{"bigquery", "env:prod", "domain:sales", "pii", "team:growth"}, tags)
+	assert.Equal(t, []string{"bigquery"}, appendLabelTags([]string{"bigquery"}, nil))
+}
+
+func TestBuildJobLineage(t *testing.T) {
+	tableMRNs := map[string]string{
+		"proj.raw.orders":         "mrn://table/bigquery/orders",
+		"proj.raw.customers":      "mrn://table/bigquery/customers",
+		"proj.mart.order_summary": "mrn://table/bigquery/order_summary",
+	}
+
+	rows := []jobLineageRow{
+		{"proj", "raw", "orders", "proj", "mart", "order_summary"},
+		{"proj", "raw", "customers", "proj", "mart", "order_summary"},
+		// Repeated by another job.
+		{"proj", "raw", "orders", "proj", "mart", "order_summary"},
+		// A MERGE reading its own target.
+		{"proj", "mart", "order_summary", "proj", "mart", "order_summary"},
+		// Plain query results land in an anonymous dataset.
+		{"proj", "raw", "orders", "proj", "_abc123", "anon456"},
+		// Tables outside the run are not linked.
+		{"other", "ext", "events", "proj", "raw", "orders"},
+	}
+
+	edges := buildJobLineage(rows, tableMRNs)
+
+	require.Len(t, edges, 2)
+	assert.Equal(t, pluginsdk.LineageEdge{
+		Source: "mrn://table/bigquery/orders",
+		Target: "mrn://table/bigquery/order_summary",
+		Type:   "DEPENDS_ON",
+	}, edges[0])
+	assert.Equal(t, pluginsdk.LineageEdge{
+		Source: "mrn://table/bigquery/customers",
+		Target: "mrn://table/bigquery/order_summary",
+		Type:   "DEPENDS_ON",
+	}, edges[1])
+}
@@ -55,6 +55,20 @@ type DBTSeedFields struct {
 	Environment        string `json:"environment" metadata:"environment" description:"Deployment environment"`
 }
 
+// DBTExposureFields represents DBT exposure-specific metadata fields
+type DBTExposureFields struct {
+	DBTUniqueID  string `json:"dbt_unique_id" metadata:"dbt_unique_id" description:"DBT's unique identifier for this exposure"`
+	DBTPackage   string `json:"dbt_package" metadata:"dbt_package" description:"DBT package name"`
+	ExposureName string `json:"exposure_name" metadata:"exposure_name" description:"DBT exposure name"`
+	ExposureType string `json:"exposure_type" metadata:"exposure_type" description:"Exposure type (dashboard, notebook, analysis, ml, application)"`
+	Maturity     string `json:"maturity" metadata:"maturity" description:"Exposure maturity (high, medium, low)"`
+	URL          string `json:"url" metadata:"url" description:"URL of the dashboard, notebook or application"`
+	OwnerName    string `json:"owner_name" metadata:"owner_name" description:"Name of the exposure's owner"`
+	OwnerEmail   string `json:"owner_email" metadata:"owner_email" description:"Email of the exposure's owner"`
+	ProjectName  string `json:"project_name" metadata:"project_name" description:"DBT project name"`
+	Environment  string `json:"environment" metadata:"environment" description:"Deployment environment"`
+}
+
 // DBTColumnFields represents DBT column-specific metadata fields
 type DBTColumnFields struct {
 	ColumnName        string   `json:"column_name" metadata:"column_name" description:"Column name"`
@@ -85,3 +99,15 @@ type DBTStatsFields struct {
 	StatApproximateCount int64   `json:"stat_approximate_count" metadata:"stat_approximate_count" description:"Approximate row count"`
 	StatSize             float64 `json:"stat_size" metadata:"stat_size" description:"Table size"`
 }
+
+// DBTTestFields represents the results of dbt tests run against a model,
+// source or seed, set when include_run_results is on
+type DBTTestFields struct {
+	TestStatus  string       `json:"dbt_test_status" metadata:"dbt_test_status" description:"Worst outcome of the tests run against this node (pass, warn, fail)"`
+	TestsTotal  int          `json:"dbt_tests_total" metadata:"dbt_tests_total" description:"Number of tests run against this node"`
+	TestsPassed int          `json:"dbt_tests_passed" metadata:"dbt_tests_passed" description:"Number of tests that passed"`
+	TestsWarned int          `json:"dbt_tests_warned" metadata:"dbt_tests_warned" description:"Number of tests that warned"`
+	TestsFailed int          `json:"dbt_tests_failed" metadata:"dbt_tests_failed" description:"Number of tests that failed or errored"`
+	Tests       []TestResult `json:"dbt_tests" metadata:"dbt_tests" description:"Each test's name, generic test, column, status, failure count and message"`
+	TestsRunAt  string       `json:"dbt_tests_run_at" metadata:"dbt_tests_run_at" description:"When run_results.json was generated"`
+}
@@ -1,5 +1,5 @@
-// Package dbt discovers models, sources, seeds and lineage from a DBT
-// (Data Build Tool) project's target artifacts.
+// Package dbt discovers models, sources, seeds, exposures and lineage from
+// a DBT (Data Build Tool) project's target artifacts.
 package dbt
 
 import (
@@ -49,6 +49,8 @@ type Config struct {
 	DiscoverModels  bool `json:"discover_models" description:"Discover DBT models" default:"true"`
 	DiscoverSources bool `json:"discover_sources" description:"Discover DBT sources" default:"true"`
 	DiscoverTests   bool `json:"discover_tests" description:"Discover DBT tests" default:"false"`
+
+	DiscoverExposures bool `json:"discover_exposures" description:"Discover DBT exposures, such as the dashboards built on models" default:"true"`
 }
 
 // Example configuration for the plugin
@@ -63,15 +65,15 @@ tags:
 
 // DBT artifact structures
 type DBTManifest struct {
-	Metadata     ManifestMetadata        `json:"metadata"`
-	Nodes        map[string]ManifestNode `json:"nodes"`
-	Sources      map[string]ManifestNode `json:"sources"`
-	Macros       map[string]interface{}  `json:"macros"`
-	ChildMap     map[string][]string     `json:"child_map"`
-	ParentMap    map[string][]string     `json:"parent_map"`
-	Exposures    map[string]interface{}  `json:"exposures"`
-	Metrics      map[string]interface{}  `json:"metrics"`
-	Dependencies map[string]interface{}  `json:"dependencies"`
+	Metadata     ManifestMetadata            `json:"metadata"`
+	Nodes        map[string]ManifestNode     `json:"nodes"`
+	Sources      map[string]ManifestNode     `json:"sources"`
+	Macros       map[string]interface{}      `json:"macros"`
+	ChildMap     map[string][]string         `json:"child_map"`
+	ParentMap    map[string][]string         `json:"parent_map"`
+	Exposures    map[string]ManifestExposure `json:"exposures"`
+	Metrics      map[string]interface{}      `json:"metrics"`
+	Dependencies map[string]interface{}      `json:"dependencies"`
 }
 
 type ManifestMetadata struct {
@@ -103,6 +105,33 @@ type ManifestNode struct {
 	RawSQL       string                 `json:"raw_sql"`
 	RawCode      string                 `json:"raw_code"`
 	Materialized string                 `json:"materialized"`
+	// AttachedNode, ColumnName and TestMetadata are set on test nodes.
+	AttachedNode string        `json:"attached_node"`
+	ColumnName   string        `json:"column_name"`
+	TestMetadata *TestMetadata `json:"test_metadata"`
+}
+
+// ManifestExposure is a downstream use of the project's models declared in
+// an exposures block, such as a dashboard or a notebook.
+type ManifestExposure struct {
+	UniqueID     string                 `json:"unique_id"`
+	Name         string                 `json:"name"`
+	Label        string                 `json:"label"`
+	ResourceType string                 `json:"resource_type"`
+	PackageName  string                 `json:"package_name"`
+	Type         string                 `json:"type"`
+	Maturity     string                 `json:"maturity"`
+	URL          string                 `json:"url"`
+	Description  string                 `json:"description"`
+	Owner        ExposureOwner          `json:"owner"`
+	Tags         []string               `json:"tags"`
+	Meta         map[string]interface{} `json:"meta"`
+	DependsOn    NodeDependency         `json:"depends_on"`
+}
+
+type ExposureOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
 
 type NodeColumn struct {
@@ -188,6 +217,9 @@ type Source struct {
 	manifest   *DBTManifest
 	catalog    *DBTCatalog
 	runResults *DBTRunResults
+
+	// testSummaries are the test results for each tested node, by ID.
+	testSummaries map[string]*TestSummary
 }
 
 func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
@@ -228,6 +260,7 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 	if err := s.loadArtifacts(ctx); err != nil {
 		return nil, fmt.Errorf("loading DBT artifacts: %w", err)
 	}
+	s.testSummaries = s.buildTestSummaries()
 
 	var assets []pluginsdk.Asset
 	var lineages []pluginsdk.LineageEdge
@@ -251,14 +284,25 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 		assets = append(assets, seedAssets...)
 	}
 
+	// Discover exposures
+	if config.DiscoverExposures && s.manifest != nil {
+		exposureAssets, exposureLineages := s.discoverExposures()
+		assets = append(assets, exposureAssets...)
+		lineages = append(lineages, exposureLineages...)
+	}
+
+	statistics := s.testStatistics(assets)
+
 	log.Info().
 		Int("assets", len(assets)).
 		Int("lineages", len(lineages)).
+		Int("tested_nodes", len(s.testSummaries)).
 		Msg("DBT discovery completed")
 
 	return &pluginsdk.DiscoveryResult{
-		Assets:  assets,
-		Lineage: lineages,
+		Assets:     assets,
+		Lineage:    lineages,
+		Statistics: statistics,
 	}, nil
 }
 
@@ -434,6 +478,7 @@ func (s *Source) createModelAsset(node ManifestNode, nodeID string) pluginsdk.As
 			}
 		}
 	}
+	s.addTestMetadata(metadata, nodeID)
 
 	if s.catalog != nil {
 		if catalogNode, exists := s.catalog.Nodes[nodeID]; exists {
@@ -538,6 +583,7 @@ func (s *Source) createMaterializedTableAsset(node ManifestNode, nodeID string)
 	metadata["table_name"] = tableName
 	metadata["fully_qualified_name"] = tableFQN
 	metadata["materialized_by"] = "dbt"
+	s.addTestMetadata(metadata, nodeID)
 
 	if s.catalog != nil {
 		if catalogNode, exists := s.catalog.Nodes[nodeID]; exists {
@@ -677,52 +723,11 @@ func (s *Source) createModelLineage(node ManifestNode, nodeID string) []pluginsd
 	outputMRN := mrn.New(outputType, provider, targetFQN)
 
 	for _, depNodeID := range node.DependsOn.Nodes {
-		var depNode ManifestNode
-		var found bool
-		var resourceType string
-
-		if n, exists := s.manifest.Nodes[depNodeID]; exists {
-			depNode = n
-			found = true
-			resourceType = n.ResourceType
-		}
-
-		if !found {
-			if n, exists := s.manifest.Sources[depNodeID]; exists {
-				depNode = n
-				found = true
-				resourceType = "source"
-			}
-		}
-
-		if !found {
+		sourceMRN, ok := s.dependencyMRN(depNodeID)
+		if !ok {
 			continue
 		}
 
-		depName := depNode.Name
-		if depNode.Alias != "" {
-			depName = depNode.Alias
-		}
-		sourceFQN := fmt.Sprintf("%s.%s.%s", depNode.Database, depNode.Schema, depName)
-
-		var sourceMRN string
-		switch {
-		case resourceType == "source" || resourceType == "seed":
-			sourceMRN = mrn.New("Table", provider, sourceFQN)
-		case resourceType == "model":
-			depMaterialization := s.getMaterialization(depNode)
-			if depMaterialization == "" {
-				depMaterialization = adapter.DefaultMaterialization()
-			}
-			depType := adapter.AssetTypeForMaterialization(depMaterialization)
-			if depType == "Ephemeral" {
-				depType = "Table"
-			}
-			sourceMRN = mrn.New(depType, provider, sourceFQN)
-		default:
-			sourceMRN = mrn.New("Table", provider, sourceFQN)
-		}
-
 		lineages = append(lineages, pluginsdk.LineageEdge{
 			Source: sourceMRN,
 			Target: modelMRN,
@@ -739,6 +744,59 @@ func (s *Source) createModelLineage(node ManifestNode, nodeID string) []pluginsd
 	return lineages
 }
 
+// dependencyMRN returns the MRN of the warehouse asset behind a node a model
+// or exposure depends on: a source or seed table, or the relation a model
+// materializes. Ephemeral models are referred to as tables.
+func (s *Source) dependencyMRN(depNodeID string) (string, bool) {
+	var depNode ManifestNode
+	var found bool
+	var resourceType string
+
+	if n, exists := s.manifest.Nodes[depNodeID]; exists {
+		depNode = n
+		found = true
+		resourceType = n.ResourceType
+	}
+
+	if !found {
+		if n, exists := s.manifest.Sources[depNodeID]; exists {
+			depNode = n
+			found = true
+			resourceType = "source"
+		}
+	}
+
+	if !found {
+		return "", false
+	}
+
+	adapter := s.getAdapter()
+	provider := adapter.Name()
+
+	depName := depNode.Name
+	if depNode.Alias != "" {
+		depName = depNode.Alias
+	}
+	sourceFQN := fmt.Sprintf("%s.%s.%s", depNode.Database, depNode.Schema, depName)
+
+	switch {
+	case resourceType == "source" || resourceType == "seed":
+		return mrn.New("Table", provider, sourceFQN), true
+	case resourceType == "model":
+		depMaterialization := s.getMaterialization(depNode)
+		if depMaterialization == "" {
+			depMaterialization = adapter.DefaultMaterialization()
+		}
+		depType := adapter.AssetTypeForMaterialization(depMaterialization)
+		if depType == "Ephemeral" {
+			depType = "Table"
+		}
+		return mrn.New(depType, provider, sourceFQN), true
+	default:
+		return mrn.New("Table", provider, sourceFQN), true
+	}
+}
+
 func (s *Source) discoverSources() []pluginsdk.Asset {
 	var assets []pluginsdk.Asset
 
@@ -769,6 +827,7 @@ func (s *Source) createSourceAsset(node ManifestNode) pluginsdk.Asset {
 	metadata["project_name"] = s.config.ProjectName
 	metadata["environment"] = s.config.Environment
 	metadata["resource_type"] = "source"
+	s.addTestMetadata(metadata, node.UniqueID)
 
 	for k, v := range node.Meta {
 		metadata[fmt.Sprintf("meta_%s", k)] = v
@@ -853,6 +912,7 @@ func (s *Source) createSeedAsset(node ManifestNode, nodeID string) pluginsdk.Ass
 	metadata["project_name"] = s.config.ProjectName
 	metadata["environment"] = s.config.Environment
 	metadata["resource_type"] = "seed"
+	s.addTestMetadata(metadata, nodeID)
 
 	if node.Path != "" {
 		metadata["seed_file_path"] = node.Path
@@ -907,3 +967,92 @@ func (s *Source) createSeedAsset(node ManifestNode, nodeID string) pluginsdk.Ass
 	}
 }
 
+// discoverExposures creates an asset for each exposure, with lineage from
+// the models and sources it depends on. The exposure's URL is kept in its
+// metadata, which Marmot uses to link it to the dashboard a BI plugin
+// discovers at that URL.
+func (s *Source) discoverExposures() ([]pluginsdk.Asset, []pluginsdk.LineageEdge) {
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	for _, exposure := range s.manifest.Exposures {
+		if exposure.Name == "" {
+			continue
+		}
+
+		asset := s.createExposureAsset(exposure)
+		assets = append(assets, asset)
+
+		for _, depNodeID := range exposure.DependsOn.Nodes {
+			sourceMRN, ok := s.dependencyMRN(depNodeID)
+			if !ok {
+				continue
+			}
+			lineages = append(lineages, pluginsdk.LineageEdge{
+				Source: sourceMRN,
+				Target: *asset.MRN,
+				Type:   "DEPENDS_ON",
+			})
+		}
+	}
+
+	return assets, lineages
+}
+
+func (s *Source) createExposureAsset(exposure ManifestExposure) pluginsdk.Asset {
+	name := exposure.Name
+	if exposure.Label != "" {
+		name = exposure.Label
+	}
+
+	packageName := exposure.PackageName
+	if packageName == "" {
+		packageName = s.config.ProjectName
+	}
+	fqn := fmt.Sprintf("%s.%s", packageName, exposure.Name)
+
+	metadata := make(map[string]interface{})
+	metadata["dbt_unique_id"] = exposure.UniqueID
+	metadata["dbt_package"] = exposure.PackageName
+	metadata["exposure_name"] = exposure.Name
+	metadata["exposure_type"] = exposure.Type
+	metadata["maturity"] = exposure.Maturity
+	metadata["url"] = exposure.URL
+	metadata["owner_name"] = exposure.Owner.Name
+	metadata["owner_email"] = exposure.Owner.Email
+	metadata["project_name"] = s.config.ProjectName
+	metadata["environment"] = s.config.Environment
+	metadata["resource_type"] = "exposure"
+
+	for k, v := range exposure.Meta {
+		metadata[fmt.Sprintf("meta_%s", k)] = v
+	}
+
+	allTags := append([]string{}, exposure.Tags...)
+	allTags = append(allTags, s.config.Tags...)
+	allTags = append(allTags, "dbt-exposure")
+
+	mrnValue := mrn.New("Exposure", "DBT", fqn)
+	var description *string
+	if exposure.Description != "" {
+		description = &exposure.Description
+	}
+
+	cleanMetadata := s.cleanMetadata(metadata)
+
+	return pluginsdk.Asset{
+		Name:        &name,
+		MRN:         &mrnValue,
+		Type:        "Exposure",
+		Providers:   []string{"DBT"},
+		Description: description,
+		Metadata:    cleanMetadata,
+		Tags:        allTags,
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "DBT",
+			LastSyncAt: time.Now(),
+			Properties: cleanMetadata,
+			Priority:   1,
+		}},
+	}
+}
@@ -0,0 +1,166 @@
+package dbt
+
+import (
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+)
+
+func TestDiscoverExposures(t *testing.T) {
+	s := &Source{
+		config: &Config{ProjectName: "analytics", Environment: "production"},
+		manifest: &DBTManifest{
+			Metadata: ManifestMetadata{AdapterType: "snowflake"},
+			Nodes: map[string]ManifestNode{
+				"model.analytics.orders": {
+					Name: "orders", ResourceType: "model", Database: "ANALYTICS", Schema: "MARTS",
+					Config: map[string]interface{}{"materialized": "view"},
+				},
+			},
+			Sources: map[string]ManifestNode{
+				"source.analytics.shop.customers": {Name: "customers", ResourceType: "source", Database: "RAW", Schema: "SHOP"},
+			},
+			Exposures: map[string]ManifestExposure{
+				"exposure.analytics.weekly_kpis": {
+					UniqueID:    "exposure.analytics.weekly_kpis",
+					Name:        "weekly_kpis",
+					Label:       "Weekly KPIs",
+					PackageName: "analytics",
+					Type:        "dashboard",
+					URL:         "https://looker.example.com/dashboards/42",
+					Owner:       ExposureOwner{Name: "Data Team", Email: "data@example.com"},
+					DependsOn: NodeDependency{Nodes: []string{
+						"model.analytics.orders",
+						"source.analytics.shop.customers",
+						"model.analytics.missing",
+					}},
+				},
+			},
+		},
+	}
+
+	assets, lineages := s.discoverExposures()
+	if len(assets) != 1 {
+		t.Fatalf("got %d assets, want 1", len(assets))
+	}
+
+	exposure := assets[0]
+	if got, want := *exposure.MRN, "mrn://exposure/dbt/analytics.weekly_kpis"; got != want {
+		t.Errorf("MRN = %q, want %q", got, want)
+	}
+	if got := *exposure.Name; got != "Weekly KPIs" {
+		t.Errorf("Name = %q, want the exposure's label", got)
+	}
+	if got := exposure.Metadata["url"]; got != "https://looker.example.com/dashboards/42" {
+		t.Errorf("url metadata = %v", got)
+	}
+	if got := exposure.Metadata["owner_email"]; got != "data@example.com" {
+		t.Errorf("owner_email metadata = %v", got)
+	}
+
+	want := map[string]bool{
+		"mrn://view/snowflake/analytics.marts.orders": true,
+		"mrn://table/snowflake/raw.shop.customers":    true,
+	}
+	if len(lineages) != len(want) {
+		t.Fatalf("got %d lineage edges, want %d", len(lineages), len(want))
+	}
+	for _, edge := range lineages {
+		if !want[edge.Source] {
+			t.Errorf("unexpected lineage source %q", edge.Source)
+		}
+		if edge.Target != *exposure.MRN {
+			t.Errorf("lineage target = %q, want %q", edge.Target, *exposure.MRN)
+		}
+	}
+}
+
+func TestTestResults(t *testing.T) {
+	s := &Source{
+		config: &Config{ProjectName: "analytics", Environment: "production"},
+		manifest: &DBTManifest{
+			Metadata: ManifestMetadata{AdapterType: "snowflake"},
+			Nodes: map[string]ManifestNode{
+				"model.analytics.orders": {
+					UniqueID: "model.analytics.orders", Name: "orders", ResourceType: "model",
+					Database: "ANALYTICS", Schema: "MARTS", Config: map[string]interface{}{"materialized": "table"},
+				},
+				"test.analytics.not_null_orders_id": {
+					Name: "not_null_orders_id", ResourceType: "test", AttachedNode: "model.analytics.orders",
+					ColumnName: "id", TestMetadata: &TestMetadata{Name: "not_null"},
+				},
+				"test.analytics.unique_orders_id": {
+					Name: "unique_orders_id", ResourceType: "test", AttachedNode: "model.analytics.orders",
+					TestMetadata: &TestMetadata{Name: "unique", Kwargs: map[string]interface{}{"column_name": "id"}},
+				},
+				"test.analytics.assert_positive_totals": {
+					Name: "assert_positive_totals", ResourceType: "test",
+					DependsOn: NodeDependency{Nodes: []string{"model.analytics.orders", "source.analytics.shop.customers"}},
+				},
+			},
+			Sources: map[string]ManifestNode{
+				"source.analytics.shop.customers": {
+					UniqueID: "source.analytics.shop.customers", Name: "customers", ResourceType: "source",
+					Database: "RAW", Schema: "SHOP",
+				},
+			},
+		},
+		runResults: &DBTRunResults{
+			Results: []RunResult{
+				{UniqueID: "model.analytics.orders", Status: "success"},
+				{UniqueID: "test.analytics.not_null_orders_id", Status: "pass"},
+				{UniqueID: "test.analytics.unique_orders_id", Status: "fail", Failures: 3, Message: "Got 3 results"},
+				{UniqueID: "test.analytics.assert_positive_totals", Status: "warn", Failures: 1},
+			},
+		},
+	}
+	s.testSummaries = s.buildTestSummaries()
+
+	orders := s.testSummaries["model.analytics.orders"]
+	if orders == nil {
+		t.Fatal("no test summary for orders")
+	}
+	if orders.Passed != 1 || orders.Failed != 1 || orders.Warned != 1 {
+		t.Errorf("orders passed/failed/warned = %d/%d/%d, want 1/1/1", orders.Passed, orders.Failed, orders.Warned)
+	}
+	if got := orders.Status(); got != "fail" {
+		t.Errorf("orders status = %q, want fail", got)
+	}
+	unique := orders.Results[2]
+	if unique.Name != "unique_orders_id" || unique.Column != "id" || unique.Test != "unique" || unique.Message != "Got 3 results" {
+		t.Errorf("unique test result = %+v", unique)
+	}
+
+	customers := s.testSummaries["source.analytics.shop.customers"]
+	if customers == nil || customers.Status() != "warn" || len(customers.Results) != 1 {
+		t.Fatalf("customers summary = %+v, want the singular test's warning", customers)
+	}
+
+	table := s.createMaterializedTableAsset(s.manifest.Nodes["model.analytics.orders"], "model.analytics.orders")
+	if got := table.Metadata["dbt_test_status"]; got != "fail" {
+		t.Errorf("table dbt_test_status = %v, want fail", got)
+	}
+	if got := table.Metadata["dbt_tests_total"]; got != 3 {
+		t.Errorf("table dbt_tests_total = %v, want 3", got)
+	}
+
+	model := s.createModelAsset(s.manifest.Nodes["model.analytics.orders"], "model.analytics.orders")
+	stats := s.testStatistics([]pluginsdk.Asset{table, model})
+	want := map[string]float64{
+		"mrn://table/snowflake/analytics.marts.orders|dbt.tests_failed": 1,
+		"mrn://table/snowflake/analytics.marts.orders|dbt.tests_total":  3,
+		"mrn://model/dbt/analytics.marts.orders|dbt.tests_passed":       1,
+	}
+	got := map[string]float64{}
+	for _, st := range stats {
+		got[st.AssetMRN+"|"+st.MetricName] = st.Value
+		if st.AssetMRN == "mrn://table/snowflake/raw.shop.customers" {
+			t.Errorf("statistic for undiscovered asset %s", st.AssetMRN)
+		}
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("statistic %s = %v, want %v", key, got[key], value)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package dbt
+
+import (
+	"sort"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+)
+
+// Test statuses, as written to run_results.json. Errors are counted as
+// failures.
+const (
+	testPass    = "pass"
+	testWarn    = "warn"
+	testFail    = "fail"
+	testError   = "error"
+	testSkipped = "skipped"
+)
+
+// TestMetadata names the generic test a test node runs, such as not_null.
+// Singular tests have none.
+type TestMetadata struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Kwargs    map[string]interface{} `json:"kwargs"`
+}
+
+// TestResult is the outcome of one dbt test against the node it validates.
+type TestResult struct {
+	Name     string `json:"name"`
+	Test     string `json:"test,omitempty"`
+	Column   string `json:"column,omitempty"`
+	Status   string `json:"status"`
+	Failures int    `json:"failures,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// TestSummary is the outcome of every test run against one node.
+type TestSummary struct {
+	Results []TestResult
+	Passed  int
+	Warned  int
+	Failed  int
+	Skipped int
+}
+
+// Status is the worst outcome among the tests that ran: fail, warn or pass.
+func (t *TestSummary) Status() string {
+	switch {
+	case t.Failed > 0:
+		return testFail
+	case t.Warned > 0:
+		return testWarn
+	case t.Passed > 0:
+		return testPass
+	}
+	return testSkipped
+}
+
+// testedNodes returns the models, sources and seeds test validates. A test
+// attached to a node validates that node; otherwise, as for singular tests
+// and those from older manifests, it validates everything it depends on.
+func (s *Source) testedNodes(test ManifestNode) []string {
+	if test.AttachedNode != "" {
+		return []string{test.AttachedNode}
+	}
+	var nodes []string
+	for _, dep := range test.DependsOn.Nodes {
+		if n, ok := s.manifest.Nodes[dep]; ok && (n.ResourceType == "model" || n.ResourceType == "seed") {
+			nodes = append(nodes, dep)
+		} else if _, ok := s.manifest.Sources[dep]; ok {
+			nodes = append(nodes, dep)
+		}
+	}
+	return nodes
+}
+
+// buildTestSummaries maps the test results in run_results.json to the
+// nodes the tests validate, keyed by node ID.
+func (s *Source) buildTestSummaries() map[string]*TestSummary {
+	summaries := make(map[string]*TestSummary)
+	if s.manifest == nil || s.runResults == nil {
+		return summaries
+	}
+
+	for _, result := range s.runResults.Results {
+		test, ok := s.manifest.Nodes[result.UniqueID]
+		if !ok || test.ResourceType != "test" {
+			continue
+		}
+
+		r := TestResult{
+			Name:     test.Name,
+			Column:   test.ColumnName,
+			Status:   result.Status,
+			Failures: result.Failures,
+		}
+		if test.TestMetadata != nil {
+			r.Test = test.TestMetadata.Name
+			if r.Column == "" {
+				r.Column, _ = test.TestMetadata.Kwargs["column_name"].(string)
+			}
+		}
+		if result.Status != testPass {
+			r.Message = result.Message
+		}
+
+		for _, nodeID := range s.testedNodes(test) {
+			summary, ok := summaries[nodeID]
+			if !ok {
+				summary = &TestSummary{}
+				summaries[nodeID] = summary
+			}
+			summary.Results = append(summary.Results, r)
+			switch result.Status {
+			case testPass:
+				summary.Passed++
+			case testWarn:
+				summary.Warned++
+			case testFail, testError:
+				summary.Failed++
+			default:
+				summary.Skipped++
+			}
+		}
+	}
+
+	for _, summary := range summaries {
+		sort.Slice(summary.Results, func(i, j int) bool {
+			return summary.Results[i].Name < summary.Results[j].Name
+		})
+	}
+	return summaries
+}
+
+// addTestMetadata records the tests run against nodeID in metadata.
+func (s *Source) addTestMetadata(metadata map[string]interface{}, nodeID string) {
+	summary, ok := s.testSummaries[nodeID]
+	if !ok {
+		return
+	}
+	metadata["dbt_test_status"] = summary.Status()
+	metadata["dbt_tests_total"] = len(summary.Results)
+	metadata["dbt_tests_passed"] = summary.Passed
+	metadata["dbt_tests_warned"] = summary.Warned
+	metadata["dbt_tests_failed"] = summary.Failed
+	metadata["dbt_tests"] = summary.Results
+	if !s.runResults.GeneratedAt.IsZero() {
+		metadata["dbt_tests_run_at"] = s.runResults.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+}
+
+// testStatistics returns pass and fail counts for each tested node, on the
+// warehouse table and, for models, the model asset. Only assets that were
+// discovered are included.
+func (s *Source) testStatistics(assets []pluginsdk.Asset) []pluginsdk.Statistic {
+	discovered := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		if a.MRN != nil {
+			discovered[*a.MRN] = true
+		}
+	}
+
+	nodeIDs := make([]string, 0, len(s.testSummaries))
+	for nodeID := range s.testSummaries {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	var stats []pluginsdk.Statistic
+	for _, nodeID := range nodeIDs {
+		summary := s.testSummaries[nodeID]
+
+		var mrns []string
+		if tableMRN, ok := s.dependencyMRN(nodeID); ok {
+			mrns = append(mrns, tableMRN)
+		}
+		if node, ok := s.manifest.Nodes[nodeID]; ok && node.ResourceType == "model" {
+			mrns = append(mrns, mrn.New("Model", "DBT", nodeFQN(node)))
+		}
+
+		for _, assetMRN := range mrns {
+			if !discovered[assetMRN] {
+				continue
+			}
+			stats = append(stats,
+				pluginsdk.Statistic{AssetMRN: assetMRN, MetricName: "dbt.tests_total", Value: float64(len(summary.Results))},
+				pluginsdk.Statistic{AssetMRN: assetMRN, MetricName: "dbt.tests_passed", Value: float64(summary.Passed)},
+				pluginsdk.Statistic{AssetMRN: assetMRN, MetricName: "dbt.tests_warned", Value: float64(summary.Warned)},
+				pluginsdk.Statistic{AssetMRN: assetMRN, MetricName: "dbt.tests_failed", Value: float64(summary.Failed)},
+			)
+		}
+	}
+	return stats
+}
+
+// nodeFQN is the database.schema.relation name of a node.
+func nodeFQN(node ManifestNode) string {
+	name := node.Name
+	if node.Alias != "" {
+		name = node.Alias
+	}
+	return node.Database + "." + node.Schema + "." + name
+}
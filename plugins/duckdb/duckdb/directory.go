@@ -0,0 +1,363 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/filesource"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+const parquetExtension = ".parquet"
+
+// duckDBExtensions are the file extensions treated as DuckDB databases when
+// scanning a directory.
+var duckDBExtensions = map[string]bool{
+	".duckdb": true,
+	".ddb":    true,
+}
+
+// parquetDataset is a table backed by one or more Parquet files.
+type parquetDataset struct {
+	// table is the asset name: the file name without extension, or the
+	// directory name for a grouped or partitioned dataset.
+	table string
+	// name is the dataset's path relative to the scanned directory, without
+	// extension, with "/" replaced by ".".
+	name string
+	// path is the file, or the directory for a grouped or partitioned
+	// dataset.
+	path string
+	// directory is the directory holding the dataset, relative to the
+	// scanned directory.
+	directory     string
+	files         []string
+	partitionKeys []string
+}
+
+// scanDirectory finds the Parquet datasets and DuckDB files under root.
+// Files under key=value directories belong to the partitioned dataset at
+// their nearest unpartitioned ancestor. Hidden files and directories, and
+// files starting with "_" such as _SUCCESS markers, are skipped.
+func scanDirectory(root string, groupDirectories bool) ([]*parquetDataset, []string, error) {
+	datasets := map[string]*parquetDataset{}
+	var databases []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(name))
+		if duckDBExtensions[ext] {
+			databases = append(databases, path)
+			return nil
+		}
+		if ext != parquetExtension {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		dir, partitionKeys := splitPartitions(filepath.Dir(rel))
+
+		key, datasetPath := strings.TrimSuffix(rel, filepath.Ext(rel)), path
+		if len(partitionKeys) > 0 || (groupDirectories && dir != ".") {
+			key, datasetPath = dir, filepath.Join(root, dir)
+			if dir == "." {
+				// The scanned directory is itself a partitioned dataset.
+				key = filepath.Base(root)
+			}
+		}
+
+		ds, ok := datasets[key]
+		if !ok {
+			ds = &parquetDataset{
+				table:         filepath.Base(key),
+				name:          strings.ReplaceAll(filepath.ToSlash(key), "/", "."),
+				path:          datasetPath,
+				directory:     filepath.ToSlash(filepath.Dir(key)),
+				partitionKeys: partitionKeys,
+			}
+			datasets[key] = ds
+		}
+		ds.files = append(ds.files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scanning %s: %w", root, err)
+	}
+
+	result := make([]*parquetDataset, 0, len(datasets))
+	for _, ds := range datasets {
+		result = append(result, ds)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	sort.Strings(databases)
+	return result, databases, nil
+}
+
+// splitPartitions strips trailing key=value partition directories from
+// dir, returning the dataset directory and the partition keys in order.
+func splitPartitions(dir string) (string, []string) {
+	var keys []string
+	for dir != "." {
+		base := filepath.Base(dir)
+		key, _, ok := strings.Cut(base, "=")
+		if !ok || key == "" {
+			break
+		}
+		keys = append([]string{key}, keys...)
+		dir = filepath.Dir(dir)
+	}
+	return dir, keys
+}
+
+// discoverDirectory catalogs the Parquet datasets and DuckDB databases in
+// s.config.Directory.
+func (s *Source) discoverDirectory(ctx context.Context) (*pluginsdk.DiscoveryResult, error) {
+	root, cleanup, err := filesource.ResolveFilePath(ctx, s.config.FileSourceConfig, s.config.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("resolving directory: %w", err)
+	}
+	defer cleanup()
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", s.config.Directory)
+	}
+
+	datasets, databases, err := scanDirectory(root, s.config.GroupDirectories)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().
+		Str("directory", root).
+		Int("parquet_datasets", len(datasets)).
+		Int("databases", len(databases)).
+		Msg("Scanned directory")
+
+	result := &pluginsdk.DiscoveryResult{}
+
+	if len(datasets) > 0 {
+		assets, statistics, err := s.discoverParquet(ctx, datasets)
+		if err != nil {
+			return nil, fmt.Errorf("discovering parquet files: %w", err)
+		}
+		result.Assets = append(result.Assets, assets...)
+		result.Statistics = append(result.Statistics, statistics...)
+	}
+
+	origPath := s.config.Path
+	defer func() {
+		s.config.Path = origPath
+		s.namePrefix, s.directory = "", ""
+	}()
+	for _, path := range databases {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, err
+		}
+		s.config.Path = path
+		s.namePrefix = strings.ReplaceAll(filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel))), "/", ".") + "."
+		s.directory = filepath.ToSlash(filepath.Dir(rel))
+
+		dbResult, err := s.discoverDatabase(ctx)
+		if err != nil {
+			// One unreadable database shouldn't hide the rest of the directory.
+			log.Warn().Err(err).Str("path", path).Msg("Failed to discover DuckDB database")
+			continue
+		}
+		result.Assets = append(result.Assets, dbResult.Assets...)
+		result.Lineage = append(result.Lineage, dbResult.Lineage...)
+		result.Statistics = append(result.Statistics, dbResult.Statistics...)
+	}
+
+	log.Info().
+		Int("assets", len(result.Assets)).
+		Int("lineages", len(result.Lineage)).
+		Int("statistics", len(result.Statistics)).
+		Msg("DuckDB directory discovery completed")
+
+	return result, nil
+}
+
+// discoverParquet reads each dataset's schema and row count from its file
+// metadata using an in-memory DuckDB connection.
+func (s *Source) discoverParquet(ctx context.Context, datasets []*parquetDataset) ([]pluginsdk.Asset, []pluginsdk.Statistic, error) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening in-memory database: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	var assets []pluginsdk.Asset
+	var statistics []pluginsdk.Statistic
+
+	for _, ds := range datasets {
+		columns, err := parquetColumns(ctx, db, ds)
+		if err != nil {
+			log.Warn().Err(err).Str("path", ds.path).Msg("Failed to read parquet schema")
+			continue
+		}
+
+		var size int64
+		for _, f := range ds.files {
+			if info, err := os.Stat(f); err == nil {
+				size += info.Size()
+			}
+		}
+
+		name := ds.table
+		metadata := map[string]interface{}{
+			"path":       ds.path,
+			"directory":  ds.directory,
+			"format":     "parquet",
+			"file_count": len(ds.files),
+			"size":       size,
+		}
+		if len(ds.partitionKeys) > 0 {
+			metadata["partition_keys"] = ds.partitionKeys
+		}
+
+		rowCount, err := parquetRowCount(ctx, db, ds.files)
+		if err != nil {
+			log.Warn().Err(err).Str("path", ds.path).Msg("Failed to read parquet row count")
+		} else {
+			metadata["row_count"] = rowCount
+		}
+
+		mrnValue := mrn.New("Table", "Parquet", ds.name)
+		a := pluginsdk.Asset{
+			Name:      &name,
+			MRN:       &mrnValue,
+			Type:      "Table",
+			Providers: []string{"Parquet"},
+			Metadata:  metadata,
+			Schema:    make(map[string]string),
+			Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+			Sources: []pluginsdk.AssetSource{{
+				Name:       "DuckDB",
+				LastSyncAt: time.Now(),
+				Properties: metadata,
+				Priority:   1,
+			}},
+		}
+
+		if s.config.IncludeColumns {
+			jsonBytes, err := json.Marshal(columns)
+			if err != nil {
+				log.Warn().Err(err).Str("path", ds.path).Msg("Failed to marshal columns")
+			} else {
+				a.Schema["columns"] = string(jsonBytes)
+			}
+		}
+		assets = append(assets, a)
+
+		if s.config.EnableMetrics {
+			statistics = append(statistics,
+				pluginsdk.Statistic{AssetMRN: mrnValue, MetricName: "asset.size_bytes", Value: float64(size)},
+				pluginsdk.Statistic{AssetMRN: mrnValue, MetricName: "asset.column_count", Value: float64(len(columns))},
+			)
+			if rowCount, ok := metadata["row_count"].(int64); ok {
+				statistics = append(statistics,
+					pluginsdk.Statistic{AssetMRN: mrnValue, MetricName: "asset.row_count", Value: float64(rowCount)},
+				)
+			}
+		}
+	}
+
+	return assets, statistics, nil
+}
+
+// parquetColumns returns a dataset's columns, including partition columns,
+// merging the schemas of its files by column name.
+func parquetColumns(ctx context.Context, db *sql.DB, ds *parquetDataset) ([]interface{}, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf(
+		`DESCRIBE SELECT * FROM read_parquet(%s, hive_partitioning = %t, union_by_name = true)`,
+		sqlStringList(ds.files), len(ds.partitionKeys) > 0)
+
+	rows, err := db.QueryContext(queryCtx, query)
+	if err != nil {
+		return nil, fmt.Errorf("describing parquet files: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scanning parquet column: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(cols))
+		for i, c := range cols {
+			row[c] = values[i]
+		}
+		columns = append(columns, map[string]interface{}{
+			"column_name": row["column_name"],
+			"data_type":   row["column_type"],
+			"is_nullable": row["null"] == "YES",
+		})
+	}
+	return columns, rows.Err()
+}
+
+func parquetRowCount(ctx context.Context, db *sql.DB, files []string) (int64, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var count int64
+	err := db.QueryRowContext(queryCtx, fmt.Sprintf(
+		`SELECT COALESCE(SUM(num_rows), 0)::BIGINT FROM parquet_file_metadata(%s)`,
+		sqlStringList(files))).Scan(&count)
+	return count, err
+}
+
+// sqlStringList renders values as a DuckDB list of string literals. Table
+// functions can't take their file list as a bound parameter.
+func sqlStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,127 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeParquet writes a small Parquet file with the given columns.
+func writeParquet(t *testing.T, db *sql.DB, path, selectSQL string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	_, err := db.Exec(fmt.Sprintf(`COPY (%s) TO '%s' (FORMAT parquet)`, selectSQL, path))
+	require.NoError(t, err)
+}
+
+func testDirectory(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	writeParquet(t, db, filepath.Join(root, "customers.parquet"), `SELECT 1 AS id, 'ada' AS name`)
+	writeParquet(t, db, filepath.Join(root, "sales", "orders.parquet"), `SELECT 1 AS id, 9.5 AS total UNION ALL SELECT 2, 3.0`)
+	writeParquet(t, db, filepath.Join(root, "sales", "refunds.parquet"), `SELECT 1 AS id`)
+	writeParquet(t, db, filepath.Join(root, "events", "day=2026-01-01", "part-0.parquet"), `SELECT 'click' AS kind`)
+	writeParquet(t, db, filepath.Join(root, "events", "day=2026-01-02", "part-0.parquet"), `SELECT 'view' AS kind`)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "events", "_SUCCESS"), nil, 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".cache"), 0o755))
+	writeParquet(t, db, filepath.Join(root, ".cache", "tmp.parquet"), `SELECT 1 AS id`)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "marts"), 0o755))
+	warehouse, err := sql.Open("duckdb", filepath.Join(root, "marts", "warehouse.duckdb"))
+	require.NoError(t, err)
+	_, err = warehouse.Exec(`CREATE TABLE dim_date (day DATE)`)
+	require.NoError(t, err)
+	require.NoError(t, warehouse.Close())
+
+	return root
+}
+
+func TestScanDirectory(t *testing.T) {
+	root := testDirectory(t)
+
+	datasets, databases, err := scanDirectory(root, false)
+	require.NoError(t, err)
+
+	names := map[string]*parquetDataset{}
+	for _, ds := range datasets {
+		names[ds.name] = ds
+	}
+	assert.Len(t, datasets, 4)
+	assert.Contains(t, names, "customers")
+	assert.Contains(t, names, "sales.orders")
+	assert.Contains(t, names, "sales.refunds")
+	require.Contains(t, names, "events")
+	assert.Len(t, names["events"].files, 2)
+	assert.Equal(t, []string{"day"}, names["events"].partitionKeys)
+	assert.Equal(t, "sales", names["sales.orders"].directory)
+	assert.Equal(t, []string{filepath.Join(root, "marts", "warehouse.duckdb")}, databases)
+
+	grouped, _, err := scanDirectory(root, true)
+	require.NoError(t, err)
+	var groupedNames []string
+	for _, ds := range grouped {
+		groupedNames = append(groupedNames, ds.name)
+	}
+	assert.Equal(t, []string{"customers", "events", "sales"}, groupedNames)
+}
+
+func TestSplitPartitions(t *testing.T) {
+	dir, keys := splitPartitions(filepath.Join("events", "year=2026", "month=01"))
+	assert.Equal(t, "events", dir)
+	assert.Equal(t, []string{"year", "month"}, keys)
+
+	dir, keys = splitPartitions("sales")
+	assert.Equal(t, "sales", dir)
+	assert.Empty(t, keys)
+}
+
+func TestDiscoverDirectory(t *testing.T) {
+	root := testDirectory(t)
+
+	s := &Source{}
+	result, err := s.Discover(context.Background(), pluginsdk.RawConfig{"directory": root})
+	require.NoError(t, err)
+
+	byMRN := map[string]pluginsdk.Asset{}
+	for _, a := range result.Assets {
+		byMRN[*a.MRN] = a
+	}
+
+	orders, ok := byMRN["mrn://table/parquet/sales.orders"]
+	require.True(t, ok)
+	assert.Equal(t, "orders", *orders.Name)
+	assert.Equal(t, "sales", orders.Metadata["directory"])
+	assert.Equal(t, int64(2), orders.Metadata["row_count"])
+	assert.Contains(t, orders.Schema["columns"], `"column_name":"total"`)
+
+	events, ok := byMRN["mrn://table/parquet/events"]
+	require.True(t, ok)
+	assert.Equal(t, 2, events.Metadata["file_count"])
+	assert.Contains(t, events.Schema["columns"], `"column_name":"day"`)
+
+	dimDate, ok := byMRN["mrn://table/duckdb/marts.warehouse.main.dim_date"]
+	require.True(t, ok)
+	assert.Equal(t, "marts", dimDate.Metadata["directory"])
+	assert.Empty(t, s.namePrefix)
+}
+
+func TestSource_ValidateDirectory(t *testing.T) {
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{"directory": "/data/lake"})
+	require.NoError(t, err)
+
+	_, err = s.Validate(pluginsdk.RawConfig{"directory": "/data/lake", "path": "/data/analytics.duckdb"})
+	assert.Error(t, err)
+}
@@ -13,6 +13,19 @@ type DuckDBFields struct {
 	Comment    string `json:"comment" metadata:"comment" description:"Object comment/description"`
 }
 
+// ParquetFields describes the metadata fields emitted for Parquet tables
+// discovered when scanning a directory. Tables from DuckDB files in the
+// directory also carry directory.
+type ParquetFields struct {
+	Path          string   `json:"path" metadata:"path" description:"Path to the Parquet file, or its directory when grouped or partitioned"`
+	Directory     string   `json:"directory" metadata:"directory" description:"Directory holding the table, relative to the scanned directory"`
+	Format        string   `json:"format" metadata:"format" description:"File format (parquet)"`
+	FileCount     int      `json:"file_count" metadata:"file_count" description:"Number of Parquet files in the table"`
+	PartitionKeys []string `json:"partition_keys" metadata:"partition_keys" description:"Hive partition keys from key=value directories"`
+	RowCount      int64    `json:"row_count" metadata:"row_count" description:"Row count from the Parquet file metadata"`
+	Size          int64    `json:"size" metadata:"size" description:"Total file size in bytes"`
+}
+
 // DuckDBColumnFields describes the per-column fields embedded in an
 // asset's schema.
 type DuckDBColumnFields struct {
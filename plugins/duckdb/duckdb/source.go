@@ -1,5 +1,6 @@
 // Package duckdb discovers schemas, tables, views and foreign key
-// relationships from DuckDB database files.
+// relationships from DuckDB database files, and tables from directories of
+// Parquet and DuckDB files.
 package duckdb
 
 import (
@@ -21,7 +22,11 @@ type Config struct {
 	pluginsdk.BaseConfig         `json:",inline"`
 	*filesource.FileSourceConfig `json:",inline"`
 
-	Path string `json:"path" description:"Path to the DuckDB database file (local path, s3://bucket/key or git::url)" validate:"required"`
+	Path      string `json:"path" description:"Path to the DuckDB database file (local path, s3://bucket/key or git::url)"`
+	Directory string `json:"directory" description:"Directory to scan for Parquet and DuckDB files instead of a single database (local path, s3://bucket/prefix/ or git::url)"`
+	// GroupDirectories catalogs every directory of Parquet files as one
+	// table, for datasets written as many part files.
+	GroupDirectories bool `json:"group_directories" description:"Catalog each directory of Parquet files as a single table instead of one table per file"`
 
 	IncludeColumns       bool `json:"include_columns" description:"Whether to include column information in table metadata" default:"true"`
 	EnableMetrics        bool `json:"enable_metrics" description:"Whether to include table metrics (row counts and sizes)" default:"true"`
@@ -34,7 +39,7 @@ func Meta() pluginsdk.Meta {
 	return pluginsdk.Meta{
 		ID:          "duckdb",
 		Name:        "DuckDB",
-		Description: "Discover schemas, tables, views and foreign key relationships from DuckDB database files",
+		Description: "Discover schemas, tables, views and foreign key relationships from DuckDB database files and directories of Parquet files",
 		Icon:        "duckdb",
 		Category:    "database",
 		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
@@ -45,6 +50,10 @@ func Meta() pluginsdk.Meta {
 type Source struct {
 	config *Config
 	db     *sql.DB
+	// namePrefix qualifies table names, and directory records where the
+	// database is, when several databases are discovered from one directory.
+	namePrefix string
+	directory  string
 }
 
 // Validate validates and normalises the plugin configuration.
@@ -68,6 +77,13 @@ func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, e
 		config.ExcludeSystemSchemas = true
 	}
 
+	if config.Path == "" && config.Directory == "" {
+		return nil, fmt.Errorf("either path or directory is required")
+	}
+	if config.Path != "" && config.Directory != "" {
+		return nil, fmt.Errorf("path and directory cannot both be set")
+	}
+
 	if err := pluginsdk.ValidateStruct(config); err != nil {
 		return nil, err
 	}
@@ -85,6 +101,10 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
+	if s.config.Directory != "" {
+		return s.discoverDirectory(ctx)
+	}
+
 	localPath, cleanup, err := filesource.ResolveFilePath(ctx, s.config.FileSourceConfig, s.config.Path)
 	if err != nil {
 		return nil, fmt.Errorf("resolving file path: %w", err)
@@ -95,6 +115,23 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	s.config.Path = localPath
 	defer func() { s.config.Path = origPath }()
 
+	result, err := s.discoverDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Int("assets", len(result.Assets)).
+		Int("lineages", len(result.Lineage)).
+		Int("statistics", len(result.Statistics)).
+		Msg("DuckDB discovery completed")
+
+	return result, nil
+}
+
+// discoverDatabase discovers the tables, views and foreign keys of the
+// database at s.config.Path.
+func (s *Source) discoverDatabase(ctx context.Context) (*pluginsdk.DiscoveryResult, error) {
 	if err := s.initConnection(ctx); err != nil {
 		return nil, fmt.Errorf("initialising database connection: %w", err)
 	}
@@ -129,12 +166,6 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 		}
 	}
 
-	log.Info().
-		Int("assets", len(assets)).
-		Int("lineages", len(lineages)).
-		Int("statistics", len(statistics)).
-		Msg("DuckDB discovery completed")
-
 	return &pluginsdk.DiscoveryResult{
 		Assets:     assets,
 		Lineage:    lineages,
@@ -142,6 +173,11 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	}, nil
 }
 
+// qualifiedName is the MRN name of a table or view.
+func (s *Source) qualifiedName(schema, table string) string {
+	return s.namePrefix + schema + "." + table
+}
+
 func (s *Source) initConnection(ctx context.Context) error {
 	s.closeConnection()
 
@@ -229,7 +265,7 @@ func (s *Source) discoverTablesAndViews(ctx context.Context) ([]pluginsdk.Asset,
 			continue
 		}
 
-		qualifiedName := fmt.Sprintf("%s.%s", schemaName, tableName)
+		qualifiedName := s.qualifiedName(schemaName, tableName)
 
 		metadata := map[string]interface{}{
 			"path":        s.config.Path,
@@ -237,6 +273,9 @@ func (s *Source) discoverTablesAndViews(ctx context.Context) ([]pluginsdk.Asset,
 			"table_name":  tableName,
 			"object_type": tableType,
 		}
+		if s.directory != "" {
+			metadata["directory"] = s.directory
+		}
 
 		mrnValue := mrn.New(assetType, "DuckDB", qualifiedName)
 		processedTags := pluginsdk.InterpolateTags(s.config.Tags, metadata)
@@ -453,8 +492,8 @@ func (s *Source) discoverForeignKeys(ctx context.Context) ([]pluginsdk.LineageEd
 			Str("constraint", constraintName).
 			Msg("Found foreign key relationship")
 
-		sourceMRN := mrn.New("Table", "DuckDB", fmt.Sprintf("%s.%s", sourceSchema, sourceTable))
-		targetMRN := mrn.New("Table", "DuckDB", fmt.Sprintf("%s.%s", targetSchema, targetTable))
+		sourceMRN := mrn.New("Table", "DuckDB", s.qualifiedName(sourceSchema, sourceTable))
+		targetMRN := mrn.New("Table", "DuckDB", s.qualifiedName(targetSchema, targetTable))
 
 		if sourceMRN == targetMRN {
 			continue
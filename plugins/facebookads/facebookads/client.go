@@ -0,0 +1,185 @@
+package facebookads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Facebook Graph API endpoint.
+const DefaultBaseURL = "https://graph.facebook.com/v21.0"
+
+// adAccountFields are the fields requested for each ad account.
+const adAccountFields = "id,account_id,name,currency,timezone_name,account_status,created_time,business"
+
+// AdAccount represents a Facebook ad account from the Graph API
+type AdAccount struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	Name          string    `json:"name"`
+	Currency      string    `json:"currency"`
+	TimezoneName  string    `json:"timezone_name"`
+	AccountStatus int       `json:"account_status"`
+	CreatedTime   string    `json:"created_time"`
+	Business      *Business `json:"business,omitempty"`
+}
+
+// Business represents the Business Manager that owns an ad account
+type Business struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Paging represents Graph API cursor pagination
+type Paging struct {
+	Cursors struct {
+		Before string `json:"before"`
+		After  string `json:"after"`
+	} `json:"cursors"`
+	Next string `json:"next"`
+}
+
+// AdAccountCollection represents the API response for listing ad accounts
+type AdAccountCollection struct {
+	Data   []AdAccount `json:"data"`
+	Paging *Paging     `json:"paging,omitempty"`
+}
+
+// APIError represents an error response from the Graph API
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+// ClientConfig holds configuration for the Graph API client
+type ClientConfig struct {
+	BaseURL     string
+	AccessToken string
+	Timeout     time.Duration
+}
+
+// Client is a Facebook Graph API client
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	accessToken string
+}
+
+// NewClient creates a new Graph API client
+func NewClient(config ClientConfig) *Client {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		accessToken: config.AccessToken,
+	}
+}
+
+// doRequest performs a GET request with authentication
+func (c *Client) doRequest(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/%s", c.baseURL, path)
+	if len(query) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, query.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// ListAdAccounts returns every ad account the access token can read
+func (c *Client) ListAdAccounts(ctx context.Context) ([]AdAccount, error) {
+	var accounts []AdAccount
+	after := ""
+
+	for {
+		query := url.Values{}
+		query.Set("fields", adAccountFields)
+		query.Set("limit", "100")
+		if after != "" {
+			query.Set("after", after)
+		}
+
+		body, err := c.doRequest(ctx, "me/adaccounts", query)
+		if err != nil {
+			return nil, err
+		}
+
+		var collection AdAccountCollection
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return nil, fmt.Errorf("parsing ad accounts response: %w", err)
+		}
+
+		accounts = append(accounts, collection.Data...)
+
+		// The next link is omitted on the last page.
+		if collection.Paging == nil || collection.Paging.Next == "" {
+			break
+		}
+		after = collection.Paging.Cursors.After
+	}
+
+	return accounts, nil
+}
+
+// GetAdAccount returns an ad account by its numeric ID
+func (c *Client) GetAdAccount(ctx context.Context, accountID string) (*AdAccount, error) {
+	query := url.Values{}
+	query.Set("fields", adAccountFields)
+
+	body, err := c.doRequest(ctx, "act_"+url.PathEscape(accountID), query)
+	if err != nil {
+		return nil, err
+	}
+
+	var account AdAccount
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, fmt.Errorf("parsing ad account response: %w", err)
+	}
+
+	return &account, nil
+}
@@ -0,0 +1,29 @@
+package facebookads
+
+// FacebookAdsAccountFields describes the metadata fields for an Account asset.
+// +marmot:metadata
+type FacebookAdsAccountFields struct {
+	AccountID    string `json:"account_id" metadata:"account_id" description:"Numeric ad account ID"`
+	Currency     string `json:"currency" metadata:"currency" description:"Currency the ad account is billed in"`
+	TimeZone     string `json:"time_zone" metadata:"time_zone" description:"Time zone of the ad account"`
+	Status       string `json:"status" metadata:"status" description:"Ad account status (active, disabled, unsettled, closed, ...)"`
+	CreatedAt    string `json:"created_at" metadata:"created_at" description:"Ad account creation timestamp"`
+	BusinessID   string `json:"business_id" metadata:"business_id" description:"ID of the Business Manager that owns the ad account"`
+	BusinessName string `json:"business_name" metadata:"business_name" description:"Name of the Business Manager that owns the ad account"`
+}
+
+// FacebookAdsBusinessFields describes the metadata fields for a Business
+// asset.
+// +marmot:metadata
+type FacebookAdsBusinessFields struct {
+	BusinessID string `json:"business_id" metadata:"business_id" description:"Business Manager ID"`
+}
+
+// FacebookAdsTableFields describes the metadata fields for a templated Table
+// asset.
+// +marmot:metadata
+type FacebookAdsTableFields struct {
+	AccountID string `json:"account_id" metadata:"account_id" description:"Numeric ad account ID"`
+	Object    string `json:"object" metadata:"object" description:"Marketing API object (campaigns, adsets, ads, adcreatives, ads_insights)"`
+	Template  string `json:"template" metadata:"template" description:"Schema template the table was created from"`
+}
@@ -0,0 +1,314 @@
+// Package facebookads discovers Facebook ad accounts and the businesses that
+// own them, and creates source tables for each account from the Marketing
+// API's object schemas.
+package facebookads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+// Meta describes the plugin to the Marmot host.
+func Meta() pluginsdk.Meta {
+	return pluginsdk.Meta{
+		ID:          "facebookads",
+		Name:        "Facebook Ads",
+		Description: "Discover Facebook ad accounts and businesses with templated Marketing API tables",
+		Icon:        "facebookads",
+		Category:    "saas",
+		Status:      "experimental",
+		Features:    []string{"Assets", "Lineage"},
+		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
+	}
+}
+
+// Config for the Facebook Ads plugin.
+type Config struct {
+	pluginsdk.BaseConfig `json:",inline"`
+
+	AccessToken string `json:"access_token" description:"Marketing API access token, such as a system user token with ads_read" validate:"required" sensitive:"true"`
+
+	AdAccountIDs     []string `json:"ad_account_ids,omitempty" label:"Ad Account IDs" description:"Numeric ad account IDs to discover. Defaults to every ad account the token can read"`
+	IncludeTemplates bool     `json:"include_templates" description:"Whether to create campaign, ad set, ad, creative and insights tables for each ad account" default:"true"`
+}
+
+// Example configuration for the plugin
+var _ = `
+access_token: "${FACEBOOK_ADS_ACCESS_TOKEN}"
+ad_account_ids:
+  - "1234567890"
+tags:
+  - "facebook-ads"
+  - "marketing"
+`
+
+// Source implements the Facebook Ads plugin.
+type Source struct {
+	config *Config
+	client *Client
+}
+
+// Validate validates and normalizes the plugin configuration.
+func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
+	// Ad account IDs are often copied with their act_ prefix.
+	for i, id := range config.AdAccountIDs {
+		config.AdAccountIDs[i] = strings.TrimPrefix(strings.TrimSpace(id), "act_")
+	}
+
+	if err := pluginsdk.ValidateStruct(config); err != nil {
+		return nil, err
+	}
+
+	s.config = config
+	return rawConfig, nil
+}
+
+// Discover discovers ad accounts, their businesses and templated tables.
+func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*pluginsdk.DiscoveryResult, error) {
+	// The host spawns a fresh plugin process per call, so Discover
+	// cannot rely on state set by an earlier Validate call.
+	if _, err := s.Validate(rawConfig); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	s.client = NewClient(ClientConfig{AccessToken: s.config.AccessToken})
+
+	return s.discover(ctx)
+}
+
+// discover reads the ad accounts using s.client.
+func (s *Source) discover(ctx context.Context) (*pluginsdk.DiscoveryResult, error) {
+	accounts, err := s.listAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+	businesses := make(map[string]bool)
+
+	for _, account := range accounts {
+		accountAsset := s.createAccountAsset(account)
+		assets = append(assets, accountAsset)
+
+		if account.Business != nil && account.Business.ID != "" {
+			if !businesses[account.Business.ID] {
+				businesses[account.Business.ID] = true
+				assets = append(assets, s.createBusinessAsset(*account.Business))
+			}
+			lineages = append(lineages, pluginsdk.LineageEdge{
+				Source: mrn.New("Business", "FacebookAds", account.Business.ID),
+				Target: *accountAsset.MRN,
+				Type:   "CONTAINS",
+			})
+		}
+
+		if !s.config.IncludeTemplates {
+			continue
+		}
+		for _, tmpl := range templates {
+			tableAsset := s.createTableAsset(account.AccountID, tmpl)
+			assets = append(assets, tableAsset)
+			lineages = append(lineages, pluginsdk.LineageEdge{
+				Source: *accountAsset.MRN,
+				Target: *tableAsset.MRN,
+				Type:   "CONTAINS",
+			})
+		}
+	}
+
+	log.Info().
+		Int("assets", len(assets)).
+		Int("lineages", len(lineages)).
+		Msg("Facebook Ads discovery completed")
+
+	return &pluginsdk.DiscoveryResult{
+		Assets:  assets,
+		Lineage: lineages,
+	}, nil
+}
+
+// listAccounts returns the configured ad accounts, or every account the
+// token can read when none are configured.
+func (s *Source) listAccounts(ctx context.Context) ([]AdAccount, error) {
+	if len(s.config.AdAccountIDs) == 0 {
+		accounts, err := s.client.ListAdAccounts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing ad accounts: %w", err)
+		}
+		return accounts, nil
+	}
+
+	accounts := make([]AdAccount, 0, len(s.config.AdAccountIDs))
+	for _, id := range s.config.AdAccountIDs {
+		account, err := s.client.GetAdAccount(ctx, id)
+		if err != nil {
+			log.Warn().Err(err).Str("ad_account_id", id).Msg("Failed to fetch ad account")
+			continue
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, nil
+}
+
+// createAccountAsset creates an Account asset from an ad account.
+func (s *Source) createAccountAsset(account AdAccount) pluginsdk.Asset {
+	mrnValue := mrn.New("Account", "FacebookAds", account.AccountID)
+
+	name := account.Name
+	if name == "" {
+		name = account.AccountID
+	}
+
+	metadata := map[string]interface{}{
+		"account_id": account.AccountID,
+		"currency":   account.Currency,
+		"time_zone":  account.TimezoneName,
+		"status":     accountStatus(account.AccountStatus),
+		"created_at": account.CreatedTime,
+	}
+	if account.Business != nil {
+		metadata["business_id"] = account.Business.ID
+		metadata["business_name"] = account.Business.Name
+	}
+	metadata = cleanMetadata(metadata)
+
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      "Account",
+		Providers: []string{"FacebookAds"},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "FacebookAds",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+// createBusinessAsset creates a Business asset for a Business Manager.
+func (s *Source) createBusinessAsset(business Business) pluginsdk.Asset {
+	mrnValue := mrn.New("Business", "FacebookAds", business.ID)
+
+	name := business.Name
+	if name == "" {
+		name = business.ID
+	}
+
+	metadata := map[string]interface{}{
+		"business_id": business.ID,
+	}
+
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      "Business",
+		Providers: []string{"FacebookAds"},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "FacebookAds",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+// createTableAsset creates a Table asset for an ad account from a template.
+func (s *Source) createTableAsset(accountID string, tmpl tableTemplate) pluginsdk.Asset {
+	name := fmt.Sprintf("%s.%s", accountID, tmpl.Name)
+	mrnValue := mrn.New("Table", "FacebookAds", name)
+	description := tmpl.Description
+
+	metadata := map[string]interface{}{
+		"account_id": accountID,
+		"object":     tmpl.Name,
+		"template":   "facebookads_" + tmpl.Name,
+	}
+
+	a := pluginsdk.Asset{
+		Name:        &name,
+		MRN:         &mrnValue,
+		Type:        "Table",
+		Providers:   []string{"FacebookAds"},
+		Description: &description,
+		Metadata:    metadata,
+		Schema:      make(map[string]string),
+		Tags:        pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "FacebookAds",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+
+	jsonBytes, err := json.Marshal(tmpl.Columns)
+	if err != nil {
+		log.Warn().Err(err).Str("table", name).Msg("Failed to marshal columns")
+	} else {
+		a.Schema["columns"] = string(jsonBytes)
+	}
+
+	return a
+}
+
+// accountStatus maps a Marketing API account_status code to its name.
+func accountStatus(code int) string {
+	switch code {
+	case 1:
+		return "active"
+	case 2:
+		return "disabled"
+	case 3:
+		return "unsettled"
+	case 7:
+		return "pending_risk_review"
+	case 8:
+		return "pending_settlement"
+	case 9:
+		return "in_grace_period"
+	case 100:
+		return "pending_closure"
+	case 101:
+		return "closed"
+	default:
+		return ""
+	}
+}
+
+// cleanMetadata removes nil and empty values from metadata.
+func cleanMetadata(metadata map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{})
+	for k, v := range metadata {
+		if v == nil {
+			continue
+		}
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		cleaned[k] = v
+	}
+	return cleaned
+}
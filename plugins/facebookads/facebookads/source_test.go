@@ -0,0 +1,114 @@
+package facebookads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_Validate(t *testing.T) {
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{
+		"access_token":   "token",
+		"ad_account_ids": []interface{}{"act_123", "456"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"123", "456"}, s.config.AdAccountIDs)
+	assert.True(t, s.config.IncludeTemplates)
+
+	_, err = s.Validate(pluginsdk.RawConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access_token")
+}
+
+func TestSource_Discover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path != "/me/adaccounts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		business := &Business{ID: "900", Name: "Acme Ltd"}
+		if r.URL.Query().Get("after") == "" {
+			collection := AdAccountCollection{
+				Data:   []AdAccount{{ID: "act_1", AccountID: "1", Name: "Acme UK", Currency: "GBP", AccountStatus: 1, Business: business}},
+				Paging: &Paging{Next: "https://graph.facebook.com/next"},
+			}
+			collection.Paging.Cursors.After = "cursor"
+			_ = json.NewEncoder(w).Encode(collection)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(AdAccountCollection{
+			Data: []AdAccount{{ID: "act_2", AccountID: "2", Name: "Acme US", Currency: "USD", AccountStatus: 101, Business: business}},
+		})
+	}))
+	defer server.Close()
+
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{"access_token": "token"})
+	require.NoError(t, err)
+	s.client = NewClient(ClientConfig{BaseURL: server.URL, AccessToken: "token"})
+
+	result, err := s.discover(context.Background())
+	require.NoError(t, err)
+
+	// 2 accounts + 1 shared business + 5 templated tables per account
+	require.Len(t, result.Assets, 13)
+
+	byMRN := make(map[string]pluginsdk.Asset)
+	for _, a := range result.Assets {
+		byMRN[*a.MRN] = a
+	}
+
+	account, ok := byMRN[mrn.New("Account", "FacebookAds", "2")]
+	require.True(t, ok)
+	assert.Equal(t, "closed", account.Metadata["status"])
+	assert.Equal(t, "Acme Ltd", account.Metadata["business_name"])
+
+	insights, ok := byMRN[mrn.New("Table", "FacebookAds", "1.ads_insights")]
+	require.True(t, ok)
+	assert.Contains(t, insights.Schema["columns"], `"column_name":"spend"`)
+
+	// business -> 2 accounts, each account -> 5 tables
+	assert.Len(t, result.Lineage, 12)
+}
+
+func TestSource_DiscoverConfiguredAccounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/act_1":
+			_ = json.NewEncoder(w).Encode(AdAccount{ID: "act_1", AccountID: "1", Name: "Acme UK"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"Unsupported get request","type":"GraphMethodException","code":100}}`))
+		}
+	}))
+	defer server.Close()
+
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{
+		"access_token":      "token",
+		"ad_account_ids":    []interface{}{"1", "2"},
+		"include_templates": false,
+	})
+	require.NoError(t, err)
+	s.client = NewClient(ClientConfig{BaseURL: server.URL, AccessToken: "token"})
+
+	result, err := s.discover(context.Background())
+	require.NoError(t, err)
+
+	// The unreadable account is skipped.
+	require.Len(t, result.Assets, 1)
+	assert.Equal(t, "Acme UK", *result.Assets[0].Name)
+	assert.Empty(t, result.Lineage)
+}
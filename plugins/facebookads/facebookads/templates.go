@@ -0,0 +1,119 @@
+package facebookads
+
+// column is one column of a schema template, in the native SQL column
+// format the UI renders.
+type column struct {
+	Name     string `json:"column_name"`
+	DataType string `json:"data_type"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// tableTemplate describes a Marketing API object ELT connectors extract for
+// each ad account. Its fields are fixed by the API, so the schema is known
+// without reading any data.
+type tableTemplate struct {
+	Name        string
+	Description string
+	Columns     []column
+}
+
+var templates = []tableTemplate{
+	{
+		Name:        "campaigns",
+		Description: "Campaigns in the ad account with their objective, status and budgets.",
+		Columns: []column{
+			{"id", "STRING", "Campaign ID"},
+			{"account_id", "STRING", "ID of the ad account that owns the campaign"},
+			{"name", "STRING", "Campaign name"},
+			{"objective", "STRING", "Campaign objective (OUTCOME_SALES, OUTCOME_TRAFFIC, ...)"},
+			{"status", "STRING", "Configured status (ACTIVE, PAUSED, DELETED, ARCHIVED)"},
+			{"effective_status", "STRING", "Status after account, billing and review checks"},
+			{"buying_type", "STRING", "Buying type (AUCTION, RESERVED)"},
+			{"daily_budget", "INTEGER", "Daily budget in the account currency's minor unit"},
+			{"lifetime_budget", "INTEGER", "Lifetime budget in the account currency's minor unit"},
+			{"start_time", "TIMESTAMP", "Campaign start time"},
+			{"stop_time", "TIMESTAMP", "Campaign stop time"},
+			{"created_time", "TIMESTAMP", "Campaign creation time"},
+			{"updated_time", "TIMESTAMP", "Last time the campaign was updated"},
+		},
+	},
+	{
+		Name:        "adsets",
+		Description: "Ad sets in the ad account with their targeting, optimization goal, schedule and budgets.",
+		Columns: []column{
+			{"id", "STRING", "Ad set ID"},
+			{"account_id", "STRING", "ID of the ad account that owns the ad set"},
+			{"campaign_id", "STRING", "ID of the campaign the ad set belongs to"},
+			{"name", "STRING", "Ad set name"},
+			{"status", "STRING", "Configured status (ACTIVE, PAUSED, DELETED, ARCHIVED)"},
+			{"effective_status", "STRING", "Status after account, billing and review checks"},
+			{"optimization_goal", "STRING", "What delivery is optimized for (LINK_CLICKS, OFFSITE_CONVERSIONS, ...)"},
+			{"billing_event", "STRING", "Event the account is billed for (IMPRESSIONS, LINK_CLICKS, ...)"},
+			{"bid_amount", "INTEGER", "Bid cap in the account currency's minor unit"},
+			{"daily_budget", "INTEGER", "Daily budget in the account currency's minor unit"},
+			{"lifetime_budget", "INTEGER", "Lifetime budget in the account currency's minor unit"},
+			{"targeting", "JSON", "Audience targeting spec (locations, ages, interests, custom audiences)"},
+			{"start_time", "TIMESTAMP", "Ad set start time"},
+			{"end_time", "TIMESTAMP", "Ad set end time"},
+			{"created_time", "TIMESTAMP", "Ad set creation time"},
+			{"updated_time", "TIMESTAMP", "Last time the ad set was updated"},
+		},
+	},
+	{
+		Name:        "ads",
+		Description: "Ads in the ad account with their ad set, creative and review status.",
+		Columns: []column{
+			{"id", "STRING", "Ad ID"},
+			{"account_id", "STRING", "ID of the ad account that owns the ad"},
+			{"campaign_id", "STRING", "ID of the campaign the ad belongs to"},
+			{"adset_id", "STRING", "ID of the ad set the ad belongs to"},
+			{"name", "STRING", "Ad name"},
+			{"status", "STRING", "Configured status (ACTIVE, PAUSED, DELETED, ARCHIVED)"},
+			{"effective_status", "STRING", "Status after account, billing and review checks"},
+			{"creative_id", "STRING", "ID of the ad creative the ad shows"},
+			{"tracking_specs", "JSON", "Conversion tracking configuration"},
+			{"created_time", "TIMESTAMP", "Ad creation time"},
+			{"updated_time", "TIMESTAMP", "Last time the ad was updated"},
+		},
+	},
+	{
+		Name:        "adcreatives",
+		Description: "Ad creatives in the ad account with their copy, call to action and linked page.",
+		Columns: []column{
+			{"id", "STRING", "Creative ID"},
+			{"account_id", "STRING", "ID of the ad account that owns the creative"},
+			{"name", "STRING", "Creative name"},
+			{"title", "STRING", "Headline of the creative"},
+			{"body", "STRING", "Primary text of the creative"},
+			{"call_to_action_type", "STRING", "Call to action button (SHOP_NOW, LEARN_MORE, ...)"},
+			{"link_url", "STRING", "Destination URL"},
+			{"url_tags", "STRING", "Query parameters appended to the destination URL, such as UTM tags"},
+			{"image_url", "STRING", "URL of the creative image"},
+			{"object_story_spec", "JSON", "Page post the creative is built from"},
+			{"status", "STRING", "Creative status"},
+		},
+	},
+	{
+		Name:        "ads_insights",
+		Description: "Daily delivery and performance metrics per ad, including spend, reach, clicks and conversion actions.",
+		Columns: []column{
+			{"date_start", "DATE", "Start of the reporting day, in the account time zone"},
+			{"date_stop", "DATE", "End of the reporting day, in the account time zone"},
+			{"account_id", "STRING", "Ad account ID"},
+			{"campaign_id", "STRING", "Campaign ID"},
+			{"adset_id", "STRING", "Ad set ID"},
+			{"ad_id", "STRING", "Ad ID"},
+			{"impressions", "INTEGER", "Number of times ads were on screen"},
+			{"reach", "INTEGER", "Number of people who saw the ads at least once"},
+			{"frequency", "FLOAT", "Average number of times each person saw the ads"},
+			{"clicks", "INTEGER", "Number of clicks on the ads"},
+			{"inline_link_clicks", "INTEGER", "Number of clicks on links in the ads"},
+			{"spend", "NUMERIC", "Amount spent, in the account currency"},
+			{"cpc", "NUMERIC", "Average cost per click"},
+			{"cpm", "NUMERIC", "Average cost per 1,000 impressions"},
+			{"ctr", "FLOAT", "Click-through rate"},
+			{"actions", "ARRAY<STRUCT<action_type STRING, value NUMERIC>>", "Conversion and engagement actions attributed to the ads"},
+			{"action_values", "ARRAY<STRUCT<action_type STRING, value NUMERIC>>", "Value of the attributed actions"},
+		},
+	},
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+
+	"github.com/marmotdata/marmot/plugins/facebookads/facebookads"
+)
+
+func main() {
+	pluginsdk.Serve(&pluginsdk.ServeConfig{
+		Meta:   facebookads.Meta(),
+		Source: &facebookads.Source{},
+	})
+}
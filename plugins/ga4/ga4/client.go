@@ -0,0 +1,266 @@
+package ga4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Google Analytics Admin API endpoint.
+const DefaultBaseURL = "https://analyticsadmin.googleapis.com/v1beta"
+
+// AccountSummary represents a GA4 account and the properties under it.
+type AccountSummary struct {
+	Name              string            `json:"name"`
+	Account           string            `json:"account"`
+	DisplayName       string            `json:"displayName"`
+	PropertySummaries []PropertySummary `json:"propertySummaries"`
+}
+
+// PropertySummary represents a property listed in an account summary.
+type PropertySummary struct {
+	Property     string `json:"property"`
+	DisplayName  string `json:"displayName"`
+	PropertyType string `json:"propertyType"`
+	Parent       string `json:"parent"`
+}
+
+// AccountSummaryCollection represents the API response for listing account summaries
+type AccountSummaryCollection struct {
+	AccountSummaries []AccountSummary `json:"accountSummaries"`
+	NextPageToken    string           `json:"nextPageToken"`
+}
+
+// Property represents a GA4 property from the Admin API
+type Property struct {
+	Name             string `json:"name"`
+	DisplayName      string `json:"displayName"`
+	PropertyType     string `json:"propertyType"`
+	IndustryCategory string `json:"industryCategory"`
+	TimeZone         string `json:"timeZone"`
+	CurrencyCode     string `json:"currencyCode"`
+	ServiceLevel     string `json:"serviceLevel"`
+	CreateTime       string `json:"createTime"`
+	UpdateTime       string `json:"updateTime"`
+}
+
+// DataStream represents a web or app data stream feeding a property
+type DataStream struct {
+	Name                 string                `json:"name"`
+	Type                 string                `json:"type"`
+	DisplayName          string                `json:"displayName"`
+	CreateTime           string                `json:"createTime"`
+	UpdateTime           string                `json:"updateTime"`
+	WebStreamData        *WebStreamData        `json:"webStreamData,omitempty"`
+	AndroidAppStreamData *AndroidAppStreamData `json:"androidAppStreamData,omitempty"`
+	IosAppStreamData     *IosAppStreamData     `json:"iosAppStreamData,omitempty"`
+}
+
+// WebStreamData holds the settings of a web data stream
+type WebStreamData struct {
+	MeasurementID string `json:"measurementId"`
+	DefaultURI    string `json:"defaultUri"`
+}
+
+// AndroidAppStreamData holds the settings of an Android app data stream
+type AndroidAppStreamData struct {
+	PackageName string `json:"packageName"`
+}
+
+// IosAppStreamData holds the settings of an iOS app data stream
+type IosAppStreamData struct {
+	BundleID string `json:"bundleId"`
+}
+
+// DataStreamCollection represents the API response for listing data streams
+type DataStreamCollection struct {
+	DataStreams   []DataStream `json:"dataStreams"`
+	NextPageToken string       `json:"nextPageToken"`
+}
+
+// BigQueryLink represents a property's BigQuery export link
+type BigQueryLink struct {
+	Name                    string `json:"name"`
+	Project                 string `json:"project"`
+	DatasetLocation         string `json:"datasetLocation"`
+	DailyExportEnabled      bool   `json:"dailyExportEnabled"`
+	StreamingExportEnabled  bool   `json:"streamingExportEnabled"`
+	FreshDailyExportEnabled bool   `json:"freshDailyExportEnabled"`
+	CreateTime              string `json:"createTime"`
+}
+
+// BigQueryLinkCollection represents the API response for listing BigQuery links
+type BigQueryLinkCollection struct {
+	BigQueryLinks []BigQueryLink `json:"bigqueryLinks"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+// APIError represents an error response from a Google API
+type APIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// ClientConfig holds configuration for the Admin API client
+type ClientConfig struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Timeout    time.Duration
+}
+
+// Client is a Google Analytics Admin API client. Authentication is handled
+// by the HTTP client it is given.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Admin API client
+func NewClient(config ClientConfig) *Client {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	httpClient.Timeout = timeout
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+	}
+}
+
+// doRequest performs a GET request against the Admin API
+func (c *Client) doRequest(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/%s", c.baseURL, path)
+	if len(query) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, query.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// list follows nextPageToken across pages, decoding each page with decode.
+func (c *Client) list(ctx context.Context, path string, decode func([]byte) (string, error)) error {
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("pageSize", "200")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		body, err := c.doRequest(ctx, path, query)
+		if err != nil {
+			return err
+		}
+
+		pageToken, err = decode(body)
+		if err != nil {
+			return err
+		}
+		if pageToken == "" {
+			return nil
+		}
+	}
+}
+
+// ListAccountSummaries returns every account the caller can access, with
+// the properties under each.
+func (c *Client) ListAccountSummaries(ctx context.Context) ([]AccountSummary, error) {
+	var summaries []AccountSummary
+	err := c.list(ctx, "accountSummaries", func(body []byte) (string, error) {
+		var collection AccountSummaryCollection
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return "", fmt.Errorf("parsing account summaries response: %w", err)
+		}
+		summaries = append(summaries, collection.AccountSummaries...)
+		return collection.NextPageToken, nil
+	})
+	return summaries, err
+}
+
+// GetProperty returns a property by resource name (properties/{id})
+func (c *Client) GetProperty(ctx context.Context, name string) (*Property, error) {
+	body, err := c.doRequest(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var property Property
+	if err := json.Unmarshal(body, &property); err != nil {
+		return nil, fmt.Errorf("parsing property response: %w", err)
+	}
+
+	return &property, nil
+}
+
+// ListDataStreams returns the data streams of a property
+func (c *Client) ListDataStreams(ctx context.Context, property string) ([]DataStream, error) {
+	var streams []DataStream
+	err := c.list(ctx, property+"/dataStreams", func(body []byte) (string, error) {
+		var collection DataStreamCollection
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return "", fmt.Errorf("parsing data streams response: %w", err)
+		}
+		streams = append(streams, collection.DataStreams...)
+		return collection.NextPageToken, nil
+	})
+	return streams, err
+}
+
+// ListBigQueryLinks returns the BigQuery export links of a property
+func (c *Client) ListBigQueryLinks(ctx context.Context, property string) ([]BigQueryLink, error) {
+	var links []BigQueryLink
+	err := c.list(ctx, property+"/bigQueryLinks", func(body []byte) (string, error) {
+		var collection BigQueryLinkCollection
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return "", fmt.Errorf("parsing BigQuery links response: %w", err)
+		}
+		links = append(links, collection.BigQueryLinks...)
+		return collection.NextPageToken, nil
+	})
+	return links, err
+}
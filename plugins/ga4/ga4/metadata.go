@@ -0,0 +1,33 @@
+package ga4
+
+// GA4AccountFields describes the metadata fields for an Account asset.
+// +marmot:metadata
+type GA4AccountFields struct {
+	AccountID     string `json:"account_id" metadata:"account_id" description:"Numeric Google Analytics account ID"`
+	PropertyCount int    `json:"property_count" metadata:"property_count" description:"Number of properties in the account"`
+}
+
+// GA4PropertyFields describes the metadata fields for a Property asset.
+// +marmot:metadata
+type GA4PropertyFields struct {
+	PropertyID       string                   `json:"property_id" metadata:"property_id" description:"Numeric GA4 property ID"`
+	AccountID        string                   `json:"account_id" metadata:"account_id" description:"Numeric ID of the account that owns the property"`
+	PropertyType     string                   `json:"property_type" metadata:"property_type" description:"Property type (ordinary, subproperty, rollup)"`
+	TimeZone         string                   `json:"time_zone" metadata:"time_zone" description:"Reporting time zone of the property"`
+	CurrencyCode     string                   `json:"currency_code" metadata:"currency_code" description:"Reporting currency of the property"`
+	IndustryCategory string                   `json:"industry_category" metadata:"industry_category" description:"Industry category of the property"`
+	ServiceLevel     string                   `json:"service_level" metadata:"service_level" description:"Service level (standard, GA4 360)"`
+	CreatedAt        string                   `json:"created_at" metadata:"created_at" description:"Property creation timestamp"`
+	DataStreams      []map[string]interface{} `json:"data_streams" metadata:"data_streams" description:"Web and app data streams feeding the property"`
+	BigQueryProject  string                   `json:"bigquery_project" metadata:"bigquery_project" description:"Google Cloud project the property exports to"`
+	BigQueryDataset  string                   `json:"bigquery_dataset" metadata:"bigquery_dataset" description:"BigQuery dataset the property exports to"`
+	BigQueryExport   string                   `json:"bigquery_export" metadata:"bigquery_export" description:"Enabled BigQuery export modes (daily, streaming, fresh_daily)"`
+}
+
+// GA4TableFields describes the metadata fields for a templated Table asset.
+// +marmot:metadata
+type GA4TableFields struct {
+	PropertyID string `json:"property_id" metadata:"property_id" description:"Numeric GA4 property ID"`
+	TableName  string `json:"table_name" metadata:"table_name" description:"Name of the GA4 table (events, users, pseudonymous_users)"`
+	Template   string `json:"template" metadata:"template" description:"Schema template the table was created from"`
+}
@@ -0,0 +1,427 @@
+// Package ga4 discovers Google Analytics 4 accounts and properties, and
+// creates source tables for each property from GA4's fixed export schema.
+package ga4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// readonlyScope is the OAuth scope needed to read account structure.
+const readonlyScope = "https://www.googleapis.com/auth/analytics.readonly"
+
+// Meta describes the plugin to the Marmot host.
+func Meta() pluginsdk.Meta {
+	return pluginsdk.Meta{
+		ID:          "ga4",
+		Name:        "Google Analytics 4",
+		Description: "Discover Google Analytics 4 accounts and properties with templated event and user tables",
+		Icon:        "ga4",
+		Category:    "saas",
+		Status:      "experimental",
+		Features:    []string{"Assets", "Lineage"},
+		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
+	}
+}
+
+// Config for the GA4 plugin.
+type Config struct {
+	pluginsdk.BaseConfig `json:",inline"`
+
+	CredentialsPath       string `json:"credentials_path,omitempty" description:"Path to service account credentials JSON file"`
+	CredentialsJSON       string `json:"credentials_json,omitempty" description:"Service account credentials JSON content" sensitive:"true"`
+	UseDefaultCredentials bool   `json:"use_default_credentials" description:"Use default Google Cloud credentials" default:"false"`
+
+	AccountIDs          []string `json:"account_ids,omitempty" label:"Account IDs" description:"Numeric account IDs to discover. Defaults to every account the credentials can read"`
+	IncludeTemplates    bool     `json:"include_templates" description:"Whether to create events, users and pseudonymous_users tables for each property from the GA4 export schema" default:"true"`
+	IncludeDataStreams  bool     `json:"include_data_streams" description:"Whether to include each property's web and app data streams in its metadata" default:"true"`
+	LinkBigQueryExports bool     `json:"link_bigquery_exports" label:"Link BigQuery Exports" description:"Whether to create lineage from properties to the BigQuery datasets they export to" default:"true"`
+}
+
+// Example configuration for the plugin
+var _ = `
+credentials_path: "/etc/marmot/ga4-service-account.json"
+account_ids:
+  - "123456789"
+tags:
+  - "ga4"
+  - "marketing"
+`
+
+// Source implements the GA4 plugin.
+type Source struct {
+	config *Config
+	client *Client
+}
+
+// Validate validates and normalizes the plugin configuration.
+func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
+	if err := pluginsdk.ValidateStruct(config); err != nil {
+		return nil, err
+	}
+
+	authMethods := 0
+	if config.CredentialsPath != "" {
+		authMethods++
+	}
+	if config.CredentialsJSON != "" {
+		authMethods++
+	}
+	if config.UseDefaultCredentials {
+		authMethods++
+	}
+
+	if authMethods == 0 {
+		return nil, fmt.Errorf("at least one authentication method must be provided: credentials_path, credentials_json, or use_default_credentials")
+	}
+	if authMethods > 1 {
+		return nil, fmt.Errorf("only one authentication method should be provided")
+	}
+
+	s.config = config
+	return rawConfig, nil
+}
+
+// Discover discovers GA4 accounts, properties and their templated tables.
+func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*pluginsdk.DiscoveryResult, error) {
+	// The host spawns a fresh plugin process per call, so Discover
+	// cannot rely on state set by an earlier Validate call.
+	if _, err := s.Validate(rawConfig); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	httpClient, err := s.httpClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading credentials: %w", err)
+	}
+	s.client = NewClient(ClientConfig{HTTPClient: httpClient})
+
+	return s.discover(ctx)
+}
+
+// httpClient returns an HTTP client authenticated with the configured
+// credentials.
+func (s *Source) httpClient(ctx context.Context) (*http.Client, error) {
+	var creds *google.Credentials
+	var err error
+
+	switch {
+	case s.config.CredentialsJSON != "":
+		creds, err = google.CredentialsFromJSON(ctx, []byte(s.config.CredentialsJSON), readonlyScope)
+	case s.config.CredentialsPath != "":
+		data, readErr := os.ReadFile(s.config.CredentialsPath)
+		if readErr != nil {
+			return nil, fmt.Errorf("reading credentials file: %w", readErr)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, readonlyScope)
+	default:
+		creds, err = google.FindDefaultCredentials(ctx, readonlyScope)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return oauth2.NewClient(ctx, creds.TokenSource), nil
+}
+
+// discover walks the account summaries using s.client.
+func (s *Source) discover(ctx context.Context) (*pluginsdk.DiscoveryResult, error) {
+	summaries, err := s.client.ListAccountSummaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing account summaries: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(s.config.AccountIDs))
+	for _, id := range s.config.AccountIDs {
+		wanted[id] = true
+	}
+
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	for _, summary := range summaries {
+		accountID := strings.TrimPrefix(summary.Account, "accounts/")
+		if len(wanted) > 0 && !wanted[accountID] {
+			continue
+		}
+
+		accountAsset := s.createAccountAsset(accountID, summary)
+		assets = append(assets, accountAsset)
+
+		for _, ps := range summary.PropertySummaries {
+			propertyAssets, propertyLineages := s.discoverProperty(ctx, accountID, ps)
+			assets = append(assets, propertyAssets...)
+			lineages = append(lineages, propertyLineages...)
+		}
+	}
+
+	log.Info().
+		Int("assets", len(assets)).
+		Int("lineages", len(lineages)).
+		Msg("GA4 discovery completed")
+
+	return &pluginsdk.DiscoveryResult{
+		Assets:  assets,
+		Lineage: lineages,
+	}, nil
+}
+
+// discoverProperty creates a property, its templated tables and the edges
+// between them and to the property's account and BigQuery export.
+func (s *Source) discoverProperty(ctx context.Context, accountID string, ps PropertySummary) ([]pluginsdk.Asset, []pluginsdk.LineageEdge) {
+	propertyID := strings.TrimPrefix(ps.Property, "properties/")
+	propertyMRN := mrn.New("Property", "GA4", propertyID)
+
+	metadata := map[string]interface{}{
+		"property_id":   propertyID,
+		"account_id":    accountID,
+		"property_type": propertyType(ps.PropertyType),
+	}
+
+	property, err := s.client.GetProperty(ctx, ps.Property)
+	if err != nil {
+		log.Warn().Err(err).Str("property", propertyID).Msg("Failed to fetch property details")
+	} else {
+		metadata["time_zone"] = property.TimeZone
+		metadata["currency_code"] = property.CurrencyCode
+		metadata["industry_category"] = strings.ToLower(property.IndustryCategory)
+		metadata["service_level"] = strings.ToLower(strings.TrimPrefix(property.ServiceLevel, "GOOGLE_ANALYTICS_"))
+		metadata["created_at"] = property.CreateTime
+	}
+
+	if s.config.IncludeDataStreams {
+		streams, err := s.client.ListDataStreams(ctx, ps.Property)
+		if err != nil {
+			log.Warn().Err(err).Str("property", propertyID).Msg("Failed to list data streams")
+		} else if len(streams) > 0 {
+			metadata["data_streams"] = dataStreamMetadata(streams)
+		}
+	}
+
+	var exportDataset string
+	if s.config.LinkBigQueryExports {
+		links, err := s.client.ListBigQueryLinks(ctx, ps.Property)
+		if err != nil {
+			log.Warn().Err(err).Str("property", propertyID).Msg("Failed to list BigQuery links")
+		}
+		for _, link := range links {
+			modes := exportModes(link)
+			if len(modes) == 0 {
+				continue
+			}
+			// GA4 always exports to a dataset named after the property.
+			exportDataset = "analytics_" + propertyID
+			metadata["bigquery_project"] = strings.TrimPrefix(link.Project, "projects/")
+			metadata["bigquery_dataset"] = exportDataset
+			metadata["bigquery_export"] = strings.Join(modes, ", ")
+			break
+		}
+	}
+
+	name := ps.DisplayName
+	if name == "" {
+		name = propertyID
+	}
+	cleanMetadata := cleanMetadata(metadata)
+
+	assets := []pluginsdk.Asset{{
+		Name:      &name,
+		MRN:       &propertyMRN,
+		Type:      "Property",
+		Providers: []string{"GA4"},
+		Metadata:  cleanMetadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, cleanMetadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "GA4",
+			LastSyncAt: time.Now(),
+			Properties: cleanMetadata,
+			Priority:   1,
+		}},
+	}}
+	lineages := []pluginsdk.LineageEdge{{
+		Source: mrn.New("Account", "GA4", accountID),
+		Target: propertyMRN,
+		Type:   "CONTAINS",
+	}}
+
+	if !s.config.IncludeTemplates {
+		return assets, lineages
+	}
+
+	for _, tmpl := range templates {
+		tableAsset := s.createTableAsset(propertyID, tmpl)
+		assets = append(assets, tableAsset)
+		lineages = append(lineages, pluginsdk.LineageEdge{
+			Source: propertyMRN,
+			Target: *tableAsset.MRN,
+			Type:   "CONTAINS",
+		})
+
+		// Only the event export is configured by the link; user data
+		// export is a separate setting the Admin API does not expose.
+		if exportDataset != "" && tmpl.Name == "events" {
+			lineages = append(lineages, pluginsdk.LineageEdge{
+				Source: *tableAsset.MRN,
+				Target: mrn.New("Dataset", "BigQuery", exportDataset),
+				Type:   "FEEDS",
+			})
+		}
+	}
+
+	return assets, lineages
+}
+
+// createAccountAsset creates an Account asset from an account summary.
+func (s *Source) createAccountAsset(accountID string, summary AccountSummary) pluginsdk.Asset {
+	mrnValue := mrn.New("Account", "GA4", accountID)
+
+	name := summary.DisplayName
+	if name == "" {
+		name = accountID
+	}
+
+	metadata := cleanMetadata(map[string]interface{}{
+		"account_id":     accountID,
+		"property_count": len(summary.PropertySummaries),
+	})
+
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      "Account",
+		Providers: []string{"GA4"},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "GA4",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+// createTableAsset creates a Table asset for a property from a template.
+func (s *Source) createTableAsset(propertyID string, tmpl tableTemplate) pluginsdk.Asset {
+	name := fmt.Sprintf("%s.%s", propertyID, tmpl.Name)
+	mrnValue := mrn.New("Table", "GA4", name)
+	description := tmpl.Description
+
+	metadata := map[string]interface{}{
+		"property_id": propertyID,
+		"table_name":  tmpl.Name,
+		"template":    "ga4_" + tmpl.Name,
+	}
+
+	a := pluginsdk.Asset{
+		Name:        &name,
+		MRN:         &mrnValue,
+		Type:        "Table",
+		Providers:   []string{"GA4"},
+		Description: &description,
+		Metadata:    metadata,
+		Schema:      make(map[string]string),
+		Tags:        pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "GA4",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+
+	jsonBytes, err := json.Marshal(tmpl.Columns)
+	if err != nil {
+		log.Warn().Err(err).Str("table", name).Msg("Failed to marshal columns")
+	} else {
+		a.Schema["columns"] = string(jsonBytes)
+	}
+
+	return a
+}
+
+// dataStreamMetadata summarizes data streams for property metadata.
+func dataStreamMetadata(streams []DataStream) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(streams))
+	for _, stream := range streams {
+		entry := map[string]interface{}{
+			"stream_id": stream.Name[strings.LastIndex(stream.Name, "/")+1:],
+			"name":      stream.DisplayName,
+			"type":      strings.ToLower(strings.TrimSuffix(stream.Type, "_DATA_STREAM")),
+		}
+		switch {
+		case stream.WebStreamData != nil:
+			entry["measurement_id"] = stream.WebStreamData.MeasurementID
+			entry["url"] = stream.WebStreamData.DefaultURI
+		case stream.AndroidAppStreamData != nil:
+			entry["package_name"] = stream.AndroidAppStreamData.PackageName
+		case stream.IosAppStreamData != nil:
+			entry["bundle_id"] = stream.IosAppStreamData.BundleID
+		}
+		result = append(result, cleanMetadata(entry))
+	}
+	return result
+}
+
+// exportModes returns the enabled export modes of a BigQuery link.
+func exportModes(link BigQueryLink) []string {
+	var modes []string
+	if link.DailyExportEnabled {
+		modes = append(modes, "daily")
+	}
+	if link.StreamingExportEnabled {
+		modes = append(modes, "streaming")
+	}
+	if link.FreshDailyExportEnabled {
+		modes = append(modes, "fresh_daily")
+	}
+	return modes
+}
+
+// propertyType maps an Admin API property type to its short form.
+func propertyType(t string) string {
+	switch t {
+	case "PROPERTY_TYPE_SUBPROPERTY":
+		return "subproperty"
+	case "PROPERTY_TYPE_ROLLUP":
+		return "rollup"
+	default:
+		return "ordinary"
+	}
+}
+
+// cleanMetadata removes nil and empty values from metadata.
+func cleanMetadata(metadata map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{})
+	for k, v := range metadata {
+		if v == nil {
+			continue
+		}
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		cleaned[k] = v
+	}
+	return cleaned
+}
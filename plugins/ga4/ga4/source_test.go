@@ -0,0 +1,215 @@
+package ga4
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      pluginsdk.RawConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:   "valid config with credentials path",
+			config: pluginsdk.RawConfig{"credentials_path": "/etc/marmot/sa.json"},
+		},
+		{
+			name:   "valid config with default credentials",
+			config: pluginsdk.RawConfig{"use_default_credentials": true},
+		},
+		{
+			name:        "missing authentication",
+			config:      pluginsdk.RawConfig{},
+			wantErr:     true,
+			errContains: "authentication method",
+		},
+		{
+			name: "multiple authentication methods",
+			config: pluginsdk.RawConfig{
+				"credentials_path":        "/etc/marmot/sa.json",
+				"use_default_credentials": true,
+			},
+			wantErr:     true,
+			errContains: "only one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Source{}
+			_, err := s.Validate(tt.config)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, s.config.IncludeTemplates)
+			assert.True(t, s.config.LinkBigQueryExports)
+		})
+	}
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/accountSummaries":
+			_ = json.NewEncoder(w).Encode(AccountSummaryCollection{
+				AccountSummaries: []AccountSummary{
+					{
+						Account:     "accounts/100",
+						DisplayName: "Acme",
+						PropertySummaries: []PropertySummary{
+							{Property: "properties/2001", DisplayName: "Acme Web", PropertyType: "PROPERTY_TYPE_ORDINARY"},
+						},
+					},
+					{Account: "accounts/200", DisplayName: "Other"},
+				},
+			})
+		case "/properties/2001":
+			_ = json.NewEncoder(w).Encode(Property{
+				Name:         "properties/2001",
+				TimeZone:     "Europe/London",
+				CurrencyCode: "GBP",
+				ServiceLevel: "GOOGLE_ANALYTICS_STANDARD",
+			})
+		case "/properties/2001/dataStreams":
+			_ = json.NewEncoder(w).Encode(DataStreamCollection{
+				DataStreams: []DataStream{{
+					Name:          "properties/2001/dataStreams/555",
+					Type:          "WEB_DATA_STREAM",
+					DisplayName:   "acme.com",
+					WebStreamData: &WebStreamData{MeasurementID: "G-ABC123", DefaultURI: "https://acme.com"},
+				}},
+			})
+		case "/properties/2001/bigQueryLinks":
+			_ = json.NewEncoder(w).Encode(BigQueryLinkCollection{
+				BigQueryLinks: []BigQueryLink{{Project: "projects/987", DailyExportEnabled: true}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSource_Discover(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{
+		"use_default_credentials": true,
+		"account_ids":             []interface{}{"100"},
+	})
+	require.NoError(t, err)
+	s.client = NewClient(ClientConfig{BaseURL: server.URL})
+
+	result, err := s.discover(context.Background())
+	require.NoError(t, err)
+
+	// 1 account + 1 property + 3 templated tables; account 200 is filtered out.
+	require.Len(t, result.Assets, 5)
+
+	byMRN := make(map[string]pluginsdk.Asset)
+	for _, a := range result.Assets {
+		byMRN[*a.MRN] = a
+	}
+
+	property, ok := byMRN[mrn.New("Property", "GA4", "2001")]
+	require.True(t, ok)
+	assert.Equal(t, "Acme Web", *property.Name)
+	assert.Equal(t, "GBP", property.Metadata["currency_code"])
+	assert.Equal(t, "standard", property.Metadata["service_level"])
+	assert.Equal(t, "analytics_2001", property.Metadata["bigquery_dataset"])
+	assert.Equal(t, "987", property.Metadata["bigquery_project"])
+	streams, ok := property.Metadata["data_streams"].([]map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "G-ABC123", streams[0]["measurement_id"])
+	assert.Equal(t, "web", streams[0]["type"])
+
+	events, ok := byMRN[mrn.New("Table", "GA4", "2001.events")]
+	require.True(t, ok)
+	require.NotNil(t, events.Description)
+	assert.Contains(t, events.Schema["columns"], `"column_name":"event_name"`)
+
+	// account -> property, property -> 3 tables, events -> BigQuery dataset
+	assert.Len(t, result.Lineage, 5)
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{
+		Source: mrn.New("Table", "GA4", "2001.events"),
+		Target: mrn.New("Dataset", "BigQuery", "analytics_2001"),
+		Type:   "FEEDS",
+	})
+}
+
+func TestSource_DiscoverWithoutTemplates(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	s := &Source{}
+	_, err := s.Validate(pluginsdk.RawConfig{
+		"use_default_credentials": true,
+		"include_templates":       false,
+		"link_bigquery_exports":   false,
+	})
+	require.NoError(t, err)
+	s.client = NewClient(ClientConfig{BaseURL: server.URL})
+
+	result, err := s.discover(context.Background())
+	require.NoError(t, err)
+
+	// 2 accounts + 1 property
+	assert.Len(t, result.Assets, 3)
+	assert.Len(t, result.Lineage, 1)
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"message":"User does not have sufficient permissions","status":"PERMISSION_DENIED"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.ListAccountSummaries(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sufficient permissions")
+}
+
+func TestClient_Pagination(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("pageToken") == "" {
+			_ = json.NewEncoder(w).Encode(AccountSummaryCollection{
+				AccountSummaries: []AccountSummary{{Account: "accounts/1"}},
+				NextPageToken:    "next",
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(AccountSummaryCollection{
+			AccountSummaries: []AccountSummary{{Account: "accounts/2"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+	summaries, err := client.ListAccountSummaries(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,89 @@
+package ga4
+
+// column is one column of a schema template, in the native SQL column
+// format the UI renders.
+type column struct {
+	Name     string `json:"column_name"`
+	DataType string `json:"data_type"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// tableTemplate describes a table GA4 produces for every property. Its
+// schema is fixed by Google, so it is known without reading any data.
+type tableTemplate struct {
+	Name        string
+	Description string
+	Columns     []column
+}
+
+// templates follow the GA4 BigQuery export schema, which ELT connectors
+// also use for their raw event and user tables.
+var templates = []tableTemplate{
+	{
+		Name:        "events",
+		Description: "One row per event collected from the property's web and app data streams, with event parameters, user properties, device, geography and traffic source.",
+		Columns: []column{
+			{"event_date", "STRING", "Date the event was logged (YYYYMMDD, property time zone)"},
+			{"event_timestamp", "INTEGER", "Time the event was logged, in microseconds since epoch (UTC)"},
+			{"event_name", "STRING", "Name of the event"},
+			{"event_params", "ARRAY<STRUCT<key STRING, value STRUCT>>", "Parameters sent with the event"},
+			{"event_previous_timestamp", "INTEGER", "Time the event was previously logged, in microseconds (UTC)"},
+			{"event_value_in_usd", "FLOAT", "Currency-converted value of the event's value parameter, in USD"},
+			{"event_bundle_sequence_id", "INTEGER", "Sequential ID of the bundle the event was uploaded in"},
+			{"event_server_timestamp_offset", "INTEGER", "Offset between collection time and upload time, in microseconds"},
+			{"user_id", "STRING", "User ID set through the setUserId API"},
+			{"user_pseudo_id", "STRING", "Pseudonymous ID (app instance ID or client ID) of the user"},
+			{"privacy_info", "STRUCT", "Consent state of the user for analytics and ads storage"},
+			{"user_properties", "ARRAY<STRUCT<key STRING, value STRUCT>>", "User properties set for the user"},
+			{"user_first_touch_timestamp", "INTEGER", "Time the user first opened the app or visited the site, in microseconds"},
+			{"user_ltv", "STRUCT", "Lifetime revenue and currency of the user"},
+			{"device", "STRUCT", "Device category, operating system, browser and language"},
+			{"geo", "STRUCT", "Continent, country, region, city and metro of the user"},
+			{"app_info", "STRUCT", "App ID, version and install store"},
+			{"traffic_source", "STRUCT", "Name, medium and source of the campaign that first acquired the user"},
+			{"collected_traffic_source", "STRUCT", "Traffic source data collected with the event, such as UTM parameters and click IDs"},
+			{"session_traffic_source_last_click", "STRUCT", "Last-click attributed traffic source of the session"},
+			{"stream_id", "STRING", "Numeric ID of the data stream the event came from"},
+			{"platform", "STRING", "Platform of the data stream (WEB, IOS, ANDROID)"},
+			{"ecommerce", "STRUCT", "Purchase, refund, shipping and tax details of ecommerce events"},
+			{"items", "ARRAY<STRUCT>", "Items included in ecommerce events"},
+			{"is_active_user", "BOOLEAN", "Whether the user was active on the day"},
+			{"batch_event_index", "INTEGER", "Order of the event within its batch"},
+			{"batch_page_id", "INTEGER", "Sequential page ID of the batch"},
+			{"batch_ordering_id", "INTEGER", "Sequential ID of the batch, incremented on each batch sent"},
+		},
+	},
+	{
+		Name:        "users",
+		Description: "One row per user with a user ID, with audiences, lifetime value, predictions and user properties.",
+		Columns: []column{
+			{"user_id", "STRING", "User ID set through the setUserId API"},
+			{"user_info", "STRUCT", "Last active, first touch and first purchase timestamps"},
+			{"audiences", "ARRAY<STRUCT>", "Audiences the user belongs to"},
+			{"user_properties", "ARRAY<STRUCT>", "User properties set for the user"},
+			{"user_ltv", "STRUCT", "Lifetime revenue, engagement and session counts"},
+			{"predictions", "STRUCT", "Purchase, churn and revenue predictions for the user"},
+			{"privacy_info", "STRUCT", "Consent state of the user for analytics and ads storage"},
+			{"occurrence_date", "STRING", "Date the row's changes happened (YYYYMMDD)"},
+			{"last_updated_date", "STRING", "Date the row was last updated (YYYYMMDD)"},
+		},
+	},
+	{
+		Name:        "pseudonymous_users",
+		Description: "One row per pseudonymous identifier (app instance ID or client ID), with audiences, lifetime value, predictions and user properties.",
+		Columns: []column{
+			{"pseudo_user_id", "STRING", "Pseudonymous ID (app instance ID or client ID) of the user"},
+			{"stream_id", "STRING", "Numeric ID of the data stream"},
+			{"user_info", "STRUCT", "Last active, first touch and first purchase timestamps"},
+			{"device", "STRUCT", "Device category, operating system, browser and language"},
+			{"geo", "STRUCT", "Continent, country, region and city of the user"},
+			{"audiences", "ARRAY<STRUCT>", "Audiences the user belongs to"},
+			{"user_properties", "ARRAY<STRUCT>", "User properties set for the user"},
+			{"user_ltv", "STRUCT", "Lifetime revenue, engagement and session counts"},
+			{"predictions", "STRUCT", "Purchase, churn and revenue predictions for the user"},
+			{"privacy_info", "STRUCT", "Consent state of the user for analytics and ads storage"},
+			{"occurrence_date", "STRING", "Date the row's changes happened (YYYYMMDD)"},
+			{"last_updated_date", "STRING", "Date the row was last updated (YYYYMMDD)"},
+		},
+	},
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+
+	"github.com/marmotdata/marmot/plugins/ga4/ga4"
+)
+
+func main() {
+	pluginsdk.Serve(&pluginsdk.ServeConfig{
+		Meta:   ga4.Meta(),
+		Source: &ga4.Source{},
+	})
+}
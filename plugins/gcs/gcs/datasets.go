@@ -0,0 +1,52 @@
+package gcs
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"github.com/marmotdata/marmot/plugins/objectstore"
+	"google.golang.org/api/iterator"
+)
+
+// bucketStore implements objectstore.Store for one GCS bucket.
+type bucketStore struct {
+	bucket *storage.BucketHandle
+}
+
+func (b *bucketStore) List(ctx context.Context, prefix string, fn func(objectstore.Object) error) error {
+	query := &storage.Query{Prefix: prefix}
+	if err := query.SetAttrSelection([]string{"Name", "Size", "Updated"}); err != nil {
+		return err
+	}
+
+	it := b.bucket.Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = fn(objectstore.Object{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (b *bucketStore) ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	// Sampled objects are decompressed by the dataset sampler, so
+	// transcoding of gzip-encoded objects is disabled.
+	reader, err := b.bucket.Object(key).ReadCompressed(true).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -1,4 +1,5 @@
-// Package gcs discovers buckets from Google Cloud Storage.
+// Package gcs discovers buckets from Google Cloud Storage, and the datasets
+// stored in them.
 package gcs
 
 import (
@@ -10,8 +11,10 @@ import (
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 
+	"github.com/marmotdata/marmot/plugins/objectstore"
 	"github.com/marmotdata/plugin-sdk"
 	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
 )
 
 // Config for the Google Cloud Storage plugin
@@ -28,6 +31,8 @@ type Config struct {
 	// Discovery options
 	IncludeMetadata    bool `json:"include_metadata" description:"Include bucket metadata like labels" default:"true"`
 	IncludeObjectCount bool `json:"include_object_count" description:"Count objects in each bucket (can be slow for large buckets)" default:"false"`
+
+	objectstore.DatasetConfig `json:",inline"`
 }
 
 // Meta describes the plugin to the Marmot host.
@@ -35,9 +40,10 @@ func Meta() pluginsdk.Meta {
 	return pluginsdk.Meta{
 		ID:          "gcs",
 		Name:        "Google Cloud Storage",
-		Description: "Discover buckets from Google Cloud Storage",
+		Description: "Discover buckets and the datasets stored in them from Google Cloud Storage",
 		Icon:        "gcs",
 		Category:    "storage",
+		Features:    []string{"Assets", "Lineage"},
 		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
 	}
 }
@@ -53,6 +59,8 @@ func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, e
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
 
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
 	if config.ProjectID == "" {
 		return nil, fmt.Errorf("project_id is required")
 	}
@@ -70,6 +78,7 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	if err != nil {
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
+	pluginsdk.ApplyDefaults(config, rawConfig)
 	s.config = config
 
 	client, err := s.createClient(ctx)
@@ -85,6 +94,7 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 	}
 
 	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
 
 	for _, bucket := range buckets {
 		asset, err := s.createBucketAsset(ctx, bucket)
@@ -92,10 +102,29 @@ func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*
 			continue
 		}
 		assets = append(assets, asset)
+
+		if s.config.DiscoverDatasets {
+			datasets, edges, err := objectstore.Discover(ctx, &bucketStore{
+				bucket: s.client.Bucket(bucket.Name),
+			}, objectstore.Target{
+				Provider:  "GCS",
+				Bucket:    bucket.Name,
+				BucketMRN: *asset.MRN,
+				Scheme:    "gs",
+				Tags:      s.config.Tags,
+			}, s.config.DatasetConfig)
+			if err != nil {
+				log.Warn().Err(err).Str("bucket", bucket.Name).Msg("Failed to discover datasets in bucket")
+				continue
+			}
+			assets = append(assets, datasets...)
+			lineages = append(lineages, edges...)
+		}
 	}
 
 	return &pluginsdk.DiscoveryResult{
-		Assets: assets,
+		Assets:  assets,
+		Lineage: lineages,
 	}, nil
 }
 
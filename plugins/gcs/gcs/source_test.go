@@ -101,4 +101,8 @@ func TestSource_ValidateDefaults(t *testing.T) {
 	assert.NotNil(t, s.config)
 	assert.Equal(t, "test-project", s.config.ProjectID)
 	assert.Equal(t, "/path/to/creds.json", s.config.CredentialsFile)
+	assert.True(t, s.config.IncludeMetadata)
+	assert.False(t, s.config.DiscoverDatasets)
+	assert.Equal(t, 10000, s.config.MaxObjects)
+	assert.True(t, s.config.SampleSchemas)
 }
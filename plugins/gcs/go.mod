@@ -4,7 +4,9 @@ go 1.26.3
 
 require (
 	cloud.google.com/go/storage v1.63.0
+	github.com/marmotdata/marmot/plugins/objectstore v0.0.0-00010101000000-000000000000
 	github.com/marmotdata/plugin-sdk v0.0.0-20260711225716-7aecacb11402
+	github.com/rs/zerolog v1.35.1
 	github.com/stretchr/testify v1.11.1
 	google.golang.org/api v0.287.0
 )
@@ -88,3 +90,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	sigs.k8s.io/yaml v1.6.0 // indirect
 )
+
+replace github.com/marmotdata/marmot/plugins/objectstore => ../objectstore
@@ -0,0 +1,206 @@
+package googleads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Google Ads API endpoint.
+const DefaultBaseURL = "https://googleads.googleapis.com/v20"
+
+// customerClientQuery lists a customer and the accounts directly under it.
+// Level 0 is the customer itself.
+const customerClientQuery = `SELECT customer_client.id, customer_client.descriptive_name, customer_client.manager,
+customer_client.level, customer_client.currency_code, customer_client.time_zone,
+customer_client.test_account, customer_client.status
+FROM customer_client
+WHERE customer_client.level <= 1`
+
+// CustomerClient represents an account in a customer's hierarchy
+type CustomerClient struct {
+	ID              string `json:"id"`
+	DescriptiveName string `json:"descriptiveName"`
+	Manager         bool   `json:"manager"`
+	Level           string `json:"level"`
+	CurrencyCode    string `json:"currencyCode"`
+	TimeZone        string `json:"timeZone"`
+	TestAccount     bool   `json:"testAccount"`
+	Status          string `json:"status"`
+}
+
+// SearchRow represents one row of a GAQL search response
+type SearchRow struct {
+	CustomerClient *CustomerClient `json:"customerClient,omitempty"`
+}
+
+// SearchResponse represents the API response for a GAQL search
+type SearchResponse struct {
+	Results       []SearchRow `json:"results"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// accessibleCustomers represents the API response for listing accessible customers
+type accessibleCustomers struct {
+	ResourceNames []string `json:"resourceNames"`
+}
+
+// APIError represents an error response from the Google Ads API
+type APIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// ClientConfig holds configuration for the Google Ads API client
+type ClientConfig struct {
+	BaseURL         string
+	HTTPClient      *http.Client
+	DeveloperToken  string
+	LoginCustomerID string
+	Timeout         time.Duration
+}
+
+// Client is a Google Ads API client. OAuth is handled by the HTTP client it
+// is given; the developer token and login customer are sent as headers.
+type Client struct {
+	baseURL         string
+	httpClient      *http.Client
+	developerToken  string
+	loginCustomerID string
+}
+
+// NewClient creates a new Google Ads API client
+func NewClient(config ClientConfig) *Client {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	httpClient.Timeout = timeout
+
+	return &Client{
+		baseURL:         baseURL,
+		httpClient:      httpClient,
+		developerToken:  config.DeveloperToken,
+		loginCustomerID: config.LoginCustomerID,
+	}
+}
+
+// doRequest performs a request against the Google Ads API
+func (c *Client) doRequest(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, path), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("developer-token", c.developerToken)
+	if c.loginCustomerID != "" {
+		req.Header.Set("login-customer-id", c.loginCustomerID)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// ListAccessibleCustomers returns the IDs of customers the credentials can
+// access directly.
+func (c *Client) ListAccessibleCustomers(ctx context.Context) ([]string, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, "customers:listAccessibleCustomers", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result accessibleCustomers
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing accessible customers response: %w", err)
+	}
+
+	ids := make([]string, 0, len(result.ResourceNames))
+	for _, name := range result.ResourceNames {
+		ids = append(ids, strings.TrimPrefix(name, "customers/"))
+	}
+	return ids, nil
+}
+
+// ListCustomerClients returns a customer and, for manager accounts, the
+// accounts directly under it.
+func (c *Client) ListCustomerClients(ctx context.Context, customerID string) ([]CustomerClient, error) {
+	var clients []CustomerClient
+	pageToken := ""
+
+	for {
+		payload := map[string]string{"query": customerClientQuery}
+		if pageToken != "" {
+			payload["pageToken"] = pageToken
+		}
+
+		body, err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("customers/%s/googleAds:search", customerID), payload)
+		if err != nil {
+			return nil, err
+		}
+
+		var response SearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("parsing search response: %w", err)
+		}
+
+		for _, row := range response.Results {
+			if row.CustomerClient != nil {
+				clients = append(clients, *row.CustomerClient)
+			}
+		}
+
+		if response.NextPageToken == "" {
+			break
+		}
+		pageToken = response.NextPageToken
+	}
+
+	return clients, nil
+}
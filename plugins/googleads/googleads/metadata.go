@@ -0,0 +1,22 @@
+package googleads
+
+// GoogleAdsAccountFields describes the metadata fields for an Account asset.
+// +marmot:metadata
+type GoogleAdsAccountFields struct {
+	CustomerID   string `json:"customer_id" metadata:"customer_id" description:"Google Ads customer ID"`
+	Manager      bool   `json:"manager" metadata:"manager" description:"Whether the account is a manager (MCC) account"`
+	CurrencyCode string `json:"currency_code" metadata:"currency_code" description:"Currency the account is billed in"`
+	TimeZone     string `json:"time_zone" metadata:"time_zone" description:"Time zone of the account"`
+	Status       string `json:"status" metadata:"status" description:"Account status (enabled, canceled, suspended, closed)"`
+	TestAccount  bool   `json:"test_account" metadata:"test_account" description:"Whether the account is a test account"`
+	ManagerID    string `json:"manager_id" metadata:"manager_id" description:"Customer ID of the manager account the account was discovered under"`
+}
+
+// GoogleAdsTableFields describes the metadata fields for a templated Table
+// asset.
+// +marmot:metadata
+type GoogleAdsTableFields struct {
+	CustomerID string `json:"customer_id" metadata:"customer_id" description:"Google Ads customer ID"`
+	Resource   string `json:"resource" metadata:"resource" description:"Google Ads report resource (campaign, ad_group, ad_group_ad, keyword_view)"`
+	Template   string `json:"template" metadata:"template" description:"Schema template the table was created from"`
+}
@@ -0,0 +1,282 @@
+// Package googleads discovers Google Ads accounts and their manager
+// hierarchy, and creates source tables for each account from the API's
+// report resources.
+package googleads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// adwordsScope is the OAuth scope the Google Ads API requires.
+const adwordsScope = "https://www.googleapis.com/auth/adwords"
+
+// Meta describes the plugin to the Marmot host.
+func Meta() pluginsdk.Meta {
+	return pluginsdk.Meta{
+		ID:          "googleads",
+		Name:        "Google Ads",
+		Description: "Discover Google Ads accounts and manager hierarchies with templated report tables",
+		Icon:        "googleads",
+		Category:    "saas",
+		Status:      "experimental",
+		Features:    []string{"Assets", "Lineage"},
+		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
+	}
+}
+
+// Config for the Google Ads plugin.
+type Config struct {
+	pluginsdk.BaseConfig `json:",inline"`
+
+	DeveloperToken  string `json:"developer_token" description:"Google Ads API developer token" validate:"required" sensitive:"true"`
+	ClientID        string `json:"client_id" label:"Client ID" description:"OAuth client ID" validate:"required"`
+	ClientSecret    string `json:"client_secret" description:"OAuth client secret" validate:"required" sensitive:"true"`
+	RefreshToken    string `json:"refresh_token" description:"OAuth refresh token of a user with access to the accounts" validate:"required" sensitive:"true"`
+	LoginCustomerID string `json:"login_customer_id,omitempty" label:"Login Customer ID" description:"Manager account to authenticate through, when access is granted via a manager"`
+
+	CustomerIDs      []string `json:"customer_ids,omitempty" label:"Customer IDs" description:"Customer IDs to discover, with the accounts under them. Defaults to every account the user can access"`
+	IncludeTemplates bool     `json:"include_templates" description:"Whether to create campaign, ad group, ad and keyword tables for each account" default:"true"`
+}
+
+// Example configuration for the plugin
+var _ = `
+developer_token: "${GOOGLE_ADS_DEVELOPER_TOKEN}"
+client_id: "1234567890-abc.apps.googleusercontent.com"
+client_secret: "${GOOGLE_ADS_CLIENT_SECRET}"
+refresh_token: "${GOOGLE_ADS_REFRESH_TOKEN}"
+login_customer_id: "123-456-7890"
+tags:
+  - "google-ads"
+  - "marketing"
+`
+
+// Source implements the Google Ads plugin.
+type Source struct {
+	config *Config
+	client *Client
+}
+
+// Validate validates and normalizes the plugin configuration.
+func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
+	// Customer IDs are shown with dashes in the UI but the API takes
+	// digits only.
+	config.LoginCustomerID = normalizeCustomerID(config.LoginCustomerID)
+	for i, id := range config.CustomerIDs {
+		config.CustomerIDs[i] = normalizeCustomerID(id)
+	}
+
+	if err := pluginsdk.ValidateStruct(config); err != nil {
+		return nil, err
+	}
+
+	s.config = config
+	return rawConfig, nil
+}
+
+// Discover discovers Google Ads accounts and their templated tables.
+func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*pluginsdk.DiscoveryResult, error) {
+	// The host spawns a fresh plugin process per call, so Discover
+	// cannot rely on state set by an earlier Validate call.
+	if _, err := s.Validate(rawConfig); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	oauthConfig := &oauth2.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		Endpoint:     google.Endpoint,
+		Scopes:       []string{adwordsScope},
+	}
+	httpClient := oauthConfig.Client(ctx, &oauth2.Token{RefreshToken: s.config.RefreshToken})
+
+	s.client = NewClient(ClientConfig{
+		HTTPClient:      httpClient,
+		DeveloperToken:  s.config.DeveloperToken,
+		LoginCustomerID: s.config.LoginCustomerID,
+	})
+
+	return s.discover(ctx)
+}
+
+// discover walks the configured customers and the accounts under them using
+// s.client.
+func (s *Source) discover(ctx context.Context) (*pluginsdk.DiscoveryResult, error) {
+	roots := s.config.CustomerIDs
+	if len(roots) == 0 {
+		ids, err := s.client.ListAccessibleCustomers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing accessible customers: %w", err)
+		}
+		roots = ids
+	}
+
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+	seen := make(map[string]bool)
+
+	for _, rootID := range roots {
+		clients, err := s.client.ListCustomerClients(ctx, rootID)
+		if err != nil {
+			log.Warn().Err(err).Str("customer_id", rootID).Msg("Failed to list customer clients")
+			continue
+		}
+
+		for _, cc := range clients {
+			managerID := ""
+			if cc.Level != "0" {
+				managerID = rootID
+				lineages = append(lineages, pluginsdk.LineageEdge{
+					Source: mrn.New("Account", "GoogleAds", rootID),
+					Target: mrn.New("Account", "GoogleAds", cc.ID),
+					Type:   "CONTAINS",
+				})
+			}
+
+			// A client account is listed under every manager that
+			// can see it, and again as a root when directly accessible.
+			if seen[cc.ID] {
+				continue
+			}
+			seen[cc.ID] = true
+
+			assets = append(assets, s.createAccountAsset(cc, managerID))
+
+			if !s.config.IncludeTemplates || cc.Manager {
+				continue
+			}
+			for _, tmpl := range templates {
+				tableAsset := s.createTableAsset(cc.ID, tmpl)
+				assets = append(assets, tableAsset)
+				lineages = append(lineages, pluginsdk.LineageEdge{
+					Source: mrn.New("Account", "GoogleAds", cc.ID),
+					Target: *tableAsset.MRN,
+					Type:   "CONTAINS",
+				})
+			}
+		}
+	}
+
+	log.Info().
+		Int("assets", len(assets)).
+		Int("lineages", len(lineages)).
+		Msg("Google Ads discovery completed")
+
+	return &pluginsdk.DiscoveryResult{
+		Assets:  assets,
+		Lineage: lineages,
+	}, nil
+}
+
+// createAccountAsset creates an Account asset from a customer client.
+func (s *Source) createAccountAsset(cc CustomerClient, managerID string) pluginsdk.Asset {
+	mrnValue := mrn.New("Account", "GoogleAds", cc.ID)
+
+	name := cc.DescriptiveName
+	if name == "" {
+		name = cc.ID
+	}
+
+	metadata := cleanMetadata(map[string]interface{}{
+		"customer_id":   cc.ID,
+		"manager":       cc.Manager,
+		"currency_code": cc.CurrencyCode,
+		"time_zone":     cc.TimeZone,
+		"status":        strings.ToLower(cc.Status),
+		"test_account":  cc.TestAccount,
+		"manager_id":    managerID,
+	})
+
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      "Account",
+		Providers: []string{"GoogleAds"},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "GoogleAds",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+// createTableAsset creates a Table asset for an account from a template.
+func (s *Source) createTableAsset(customerID string, tmpl tableTemplate) pluginsdk.Asset {
+	name := fmt.Sprintf("%s.%s", customerID, tmpl.Name)
+	mrnValue := mrn.New("Table", "GoogleAds", name)
+	description := tmpl.Description
+
+	metadata := map[string]interface{}{
+		"customer_id": customerID,
+		"resource":    tmpl.Name,
+		"template":    "googleads_" + tmpl.Name,
+	}
+
+	a := pluginsdk.Asset{
+		Name:        &name,
+		MRN:         &mrnValue,
+		Type:        "Table",
+		Providers:   []string{"GoogleAds"},
+		Description: &description,
+		Metadata:    metadata,
+		Schema:      make(map[string]string),
+		Tags:        pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "GoogleAds",
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+
+	jsonBytes, err := json.Marshal(tmpl.Columns)
+	if err != nil {
+		log.Warn().Err(err).Str("table", name).Msg("Failed to marshal columns")
+	} else {
+		a.Schema["columns"] = string(jsonBytes)
+	}
+
+	return a
+}
+
+// normalizeCustomerID strips the dashes from a customer ID.
+func normalizeCustomerID(id string) string {
+	return strings.ReplaceAll(strings.TrimSpace(id), "-", "")
+}
+
+// cleanMetadata removes nil and empty values from metadata.
+func cleanMetadata(metadata map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{})
+	for k, v := range metadata {
+		if v == nil {
+			continue
+		}
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		cleaned[k] = v
+	}
+	return cleaned
+}
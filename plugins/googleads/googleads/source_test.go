@@ -0,0 +1,118 @@
+package googleads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() pluginsdk.RawConfig {
+	return pluginsdk.RawConfig{
+		"developer_token": "dev-token",
+		"client_id":       "client",
+		"client_secret":   "secret",
+		"refresh_token":   "refresh",
+	}
+}
+
+func TestSource_Validate(t *testing.T) {
+	s := &Source{}
+	config := validConfig()
+	config["login_customer_id"] = "123-456-7890"
+	config["customer_ids"] = []interface{}{"111-222-3333"}
+
+	_, err := s.Validate(config)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", s.config.LoginCustomerID)
+	assert.Equal(t, []string{"1112223333"}, s.config.CustomerIDs)
+	assert.True(t, s.config.IncludeTemplates)
+
+	delete(config, "developer_token")
+	_, err = s.Validate(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "developer_token")
+}
+
+func TestSource_Discover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "dev-token", r.Header.Get("developer-token"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/customers:listAccessibleCustomers":
+			_ = json.NewEncoder(w).Encode(accessibleCustomers{
+				ResourceNames: []string{"customers/100", "customers/201"},
+			})
+		case "/customers/100/googleAds:search":
+			_ = json.NewEncoder(w).Encode(SearchResponse{Results: []SearchRow{
+				{CustomerClient: &CustomerClient{ID: "100", DescriptiveName: "Acme MCC", Manager: true, Level: "0"}},
+				{CustomerClient: &CustomerClient{ID: "201", DescriptiveName: "Acme UK", Level: "1", CurrencyCode: "GBP", Status: "ENABLED"}},
+			}})
+		case "/customers/201/googleAds:search":
+			_ = json.NewEncoder(w).Encode(SearchResponse{Results: []SearchRow{
+				{CustomerClient: &CustomerClient{ID: "201", DescriptiveName: "Acme UK", Level: "0", CurrencyCode: "GBP", Status: "ENABLED"}},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := &Source{}
+	_, err := s.Validate(validConfig())
+	require.NoError(t, err)
+	s.client = NewClient(ClientConfig{BaseURL: server.URL, DeveloperToken: "dev-token"})
+
+	result, err := s.discover(context.Background())
+	require.NoError(t, err)
+
+	// 2 accounts + 4 templated tables for the non-manager account; the
+	// client account is only created once although it is reached twice.
+	require.Len(t, result.Assets, 6)
+
+	byMRN := make(map[string]pluginsdk.Asset)
+	for _, a := range result.Assets {
+		byMRN[*a.MRN] = a
+	}
+
+	child, ok := byMRN[mrn.New("Account", "GoogleAds", "201")]
+	require.True(t, ok)
+	assert.Equal(t, "Acme UK", *child.Name)
+	assert.Equal(t, "100", child.Metadata["manager_id"])
+	assert.Equal(t, "enabled", child.Metadata["status"])
+
+	campaign, ok := byMRN[mrn.New("Table", "GoogleAds", "201.campaign")]
+	require.True(t, ok)
+	assert.Contains(t, campaign.Schema["columns"], `"column_name":"metrics.cost_micros"`)
+
+	_, ok = byMRN[mrn.New("Table", "GoogleAds", "100.campaign")]
+	assert.False(t, ok, "manager accounts have no templated tables")
+
+	// manager -> client, client -> 4 tables
+	assert.Len(t, result.Lineage, 5)
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{
+		Source: mrn.New("Account", "GoogleAds", "100"),
+		Target: mrn.New("Account", "GoogleAds", "201"),
+		Type:   "CONTAINS",
+	})
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"code":401,"message":"Request is missing required authentication credential","status":"UNAUTHENTICATED"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.ListAccessibleCustomers(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required authentication")
+}
@@ -0,0 +1,91 @@
+package googleads
+
+// column is one column of a schema template, in the native SQL column
+// format the UI renders.
+type column struct {
+	Name     string `json:"column_name"`
+	DataType string `json:"data_type"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// tableTemplate describes a Google Ads report resource. Its fields are
+// fixed by the API, so the schema is known without reading any data.
+type tableTemplate struct {
+	Name        string
+	Description string
+	Columns     []column
+}
+
+// metricColumns are the performance metrics shared by the report resources,
+// segmented by day.
+var metricColumns = []column{
+	{"segments.date", "DATE", "Day the metrics were recorded, in the account time zone"},
+	{"metrics.impressions", "INT64", "Number of times ads were shown"},
+	{"metrics.clicks", "INT64", "Number of clicks"},
+	{"metrics.cost_micros", "INT64", "Cost in micros of the account currency"},
+	{"metrics.conversions", "DOUBLE", "Number of conversions, including modeled conversions"},
+	{"metrics.conversions_value", "DOUBLE", "Value of conversions"},
+	{"metrics.interactions", "INT64", "Number of interactions, such as clicks and video views"},
+}
+
+// templates follow the Google Ads API report resources that ELT connectors
+// extract for each customer.
+var templates = []tableTemplate{
+	{
+		Name:        "campaign",
+		Description: "Campaigns in the account with their settings and daily performance metrics.",
+		Columns: append([]column{
+			{"customer.id", "INT64", "ID of the customer that owns the campaign"},
+			{"campaign.id", "INT64", "Campaign ID"},
+			{"campaign.name", "STRING", "Campaign name"},
+			{"campaign.status", "ENUM", "Campaign status (ENABLED, PAUSED, REMOVED)"},
+			{"campaign.advertising_channel_type", "ENUM", "Primary serving target (SEARCH, DISPLAY, VIDEO, PERFORMANCE_MAX, ...)"},
+			{"campaign.bidding_strategy_type", "ENUM", "Bidding strategy type"},
+			{"campaign.start_date", "DATE", "Date the campaign starts serving"},
+			{"campaign.end_date", "DATE", "Date the campaign stops serving"},
+			{"campaign_budget.amount_micros", "INT64", "Daily budget in micros of the account currency"},
+		}, metricColumns...),
+	},
+	{
+		Name:        "ad_group",
+		Description: "Ad groups in the account with their campaign, settings and daily performance metrics.",
+		Columns: append([]column{
+			{"customer.id", "INT64", "ID of the customer that owns the ad group"},
+			{"campaign.id", "INT64", "ID of the campaign the ad group belongs to"},
+			{"ad_group.id", "INT64", "Ad group ID"},
+			{"ad_group.name", "STRING", "Ad group name"},
+			{"ad_group.status", "ENUM", "Ad group status (ENABLED, PAUSED, REMOVED)"},
+			{"ad_group.type", "ENUM", "Ad group type"},
+			{"ad_group.cpc_bid_micros", "INT64", "Maximum CPC bid in micros"},
+		}, metricColumns...),
+	},
+	{
+		Name:        "ad_group_ad",
+		Description: "Ads in the account with their ad group, creative details and daily performance metrics.",
+		Columns: append([]column{
+			{"customer.id", "INT64", "ID of the customer that owns the ad"},
+			{"campaign.id", "INT64", "ID of the campaign the ad belongs to"},
+			{"ad_group.id", "INT64", "ID of the ad group the ad belongs to"},
+			{"ad_group_ad.ad.id", "INT64", "Ad ID"},
+			{"ad_group_ad.ad.name", "STRING", "Ad name"},
+			{"ad_group_ad.ad.type", "ENUM", "Ad type (RESPONSIVE_SEARCH_AD, IMAGE_AD, ...)"},
+			{"ad_group_ad.ad.final_urls", "ARRAY<STRING>", "Landing page URLs of the ad"},
+			{"ad_group_ad.status", "ENUM", "Ad status (ENABLED, PAUSED, REMOVED)"},
+			{"ad_group_ad.policy_summary.approval_status", "ENUM", "Policy approval status of the ad"},
+		}, metricColumns...),
+	},
+	{
+		Name:        "keyword_view",
+		Description: "Keywords targeted by search ad groups with their match type and daily performance metrics.",
+		Columns: append([]column{
+			{"customer.id", "INT64", "ID of the customer that owns the keyword"},
+			{"campaign.id", "INT64", "ID of the campaign the keyword belongs to"},
+			{"ad_group.id", "INT64", "ID of the ad group the keyword belongs to"},
+			{"ad_group_criterion.criterion_id", "INT64", "Keyword criterion ID"},
+			{"ad_group_criterion.keyword.text", "STRING", "Keyword text"},
+			{"ad_group_criterion.keyword.match_type", "ENUM", "Keyword match type (EXACT, PHRASE, BROAD)"},
+			{"ad_group_criterion.status", "ENUM", "Keyword status (ENABLED, PAUSED, REMOVED)"},
+			{"ad_group_criterion.quality_info.quality_score", "INT64", "Quality score of the keyword (1-10)"},
+		}, metricColumns...),
+	},
+}
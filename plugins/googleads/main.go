@@ -0,0 +1,14 @@
+package main
+
+import (
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+
+	"github.com/marmotdata/marmot/plugins/googleads/googleads"
+)
+
+func main() {
+	pluginsdk.Serve(&pluginsdk.ServeConfig{
+		Meta:   googleads.Meta(),
+		Source: &googleads.Source{},
+	})
+}
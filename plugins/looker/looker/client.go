@@ -0,0 +1,330 @@
+package looker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dashboard represents a Looker dashboard from the API. IDs of LookML
+// dashboards have the form model::name.
+type Dashboard struct {
+	ID                string             `json:"id"`
+	Title             string             `json:"title"`
+	Description       string             `json:"description"`
+	Folder            *Folder            `json:"folder"`
+	UserID            string             `json:"user_id"`
+	ViewCount         int                `json:"view_count"`
+	FavoriteCount     int                `json:"favorite_count"`
+	LastViewedAt      *string            `json:"last_viewed_at"`
+	UpdatedAt         *string            `json:"updated_at"`
+	CreatedAt         *string            `json:"created_at"`
+	Deleted           bool               `json:"deleted"`
+	DashboardElements []DashboardElement `json:"dashboard_elements"`
+}
+
+// DashboardElement is a tile on a dashboard. A tile shows a saved Look, or
+// a query of its own.
+type DashboardElement struct {
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	Type        string       `json:"type"`
+	LookID      string       `json:"look_id"`
+	Look        *Look        `json:"look"`
+	Query       *Query       `json:"query"`
+	ResultMaker *ResultMaker `json:"result_maker"`
+}
+
+// ResultMaker holds the query behind a tile, including merged-results tiles
+// that combine several queries.
+type ResultMaker struct {
+	Query        *Query        `json:"query"`
+	MergeResults []MergeSource `json:"merge_result_source_queries"`
+}
+
+// MergeSource is one of the queries combined by a merged-results tile.
+type MergeSource struct {
+	Query *Query `json:"query"`
+}
+
+// Look represents a saved Look from the API.
+type Look struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	Description   string  `json:"description"`
+	Folder        *Folder `json:"folder"`
+	UserID        string  `json:"user_id"`
+	ViewCount     int     `json:"view_count"`
+	FavoriteCount int     `json:"favorite_count"`
+	LastViewedAt  *string `json:"last_viewed_at"`
+	UpdatedAt     *string `json:"updated_at"`
+	Deleted       bool    `json:"deleted"`
+	Query         *Query  `json:"query"`
+}
+
+// Query identifies the explore a Look or tile queries.
+type Query struct {
+	Model string `json:"model"`
+	View  string `json:"view"`
+}
+
+// Folder represents a Looker folder.
+type Folder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LookMLModel represents a LookML model and the explores it defines.
+type LookMLModel struct {
+	Name                     string               `json:"name"`
+	Label                    string               `json:"label"`
+	ProjectName              string               `json:"project_name"`
+	AllowedDBConnectionNames []string             `json:"allowed_db_connection_names"`
+	Explores                 []LookMLModelExplore `json:"explores"`
+}
+
+// LookMLModelExplore is an explore listed by its model.
+type LookMLModelExplore struct {
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	Hidden bool   `json:"hidden"`
+}
+
+// Explore is an explore's full definition. SQLTableName is the table of the
+// explore's base view.
+type Explore struct {
+	Name           string        `json:"name"`
+	ModelName      string        `json:"model_name"`
+	Label          string        `json:"label"`
+	Description    string        `json:"description"`
+	ViewName       string        `json:"view_name"`
+	SQLTableName   string        `json:"sql_table_name"`
+	ConnectionName string        `json:"connection_name"`
+	ProjectName    string        `json:"project_name"`
+	Hidden         bool          `json:"hidden"`
+	Joins          []ExploreJoin `json:"joins"`
+}
+
+// ExploreJoin is a view joined into an explore. From names the view when
+// the join is aliased.
+type ExploreJoin struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+}
+
+// View returns the name of the LookML view the join reads.
+func (j ExploreJoin) View() string {
+	if j.From != "" {
+		return j.From
+	}
+	return j.Name
+}
+
+// Connection represents a Looker database connection. For BigQuery, Host
+// is the project and Database the default dataset.
+type Connection struct {
+	Name        string `json:"name"`
+	DialectName string `json:"dialect_name"`
+	Host        string `json:"host"`
+	Database    string `json:"database"`
+	Schema      string `json:"schema"`
+}
+
+// APIError represents an error response from the Looker API.
+type APIError struct {
+	Message string `json:"message"`
+}
+
+// ClientConfig holds configuration for the Looker API client.
+type ClientConfig struct {
+	BaseURL      string
+	ClientID     string
+	ClientSecret string
+	Timeout      time.Duration
+}
+
+// Client is a Looker API 4.0 client. It logs in with API credentials on
+// first use and again when its token expires.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	clientID     string
+	clientSecret string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewClient creates a new Looker API client.
+func NewClient(config ClientConfig) *Client {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+	}
+}
+
+// accessToken returns a valid access token, logging in if needed.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.expires) {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/4.0/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("logging in: %w", err)
+	}
+
+	var login struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &login); err != nil {
+		return "", fmt.Errorf("parsing login response: %w", err)
+	}
+	if login.AccessToken == "" {
+		return "", fmt.Errorf("login response has no access token")
+	}
+
+	// Renew a minute early so a token doesn't expire mid-request.
+	c.token = login.AccessToken
+	c.expires = time.Now().Add(time.Duration(login.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+// get performs an authenticated GET and decodes the JSON response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqURL := c.baseURL + "/api/4.0" + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/json")
+
+	body, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response from %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// ListDashboards returns the summaries of all dashboards, without their
+// tiles.
+func (c *Client) ListDashboards(ctx context.Context) ([]Dashboard, error) {
+	var dashboards []Dashboard
+	if err := c.get(ctx, "/dashboards", nil, &dashboards); err != nil {
+		return nil, err
+	}
+	return dashboards, nil
+}
+
+// GetDashboard returns a dashboard with its tiles.
+func (c *Client) GetDashboard(ctx context.Context, id string) (*Dashboard, error) {
+	var dashboard Dashboard
+	if err := c.get(ctx, "/dashboards/"+url.PathEscape(id), nil, &dashboard); err != nil {
+		return nil, err
+	}
+	return &dashboard, nil
+}
+
+// ListLooks returns all saved Looks with their queries.
+func (c *Client) ListLooks(ctx context.Context) ([]Look, error) {
+	var looks []Look
+	if err := c.get(ctx, "/looks", url.Values{"fields": {"id,title,description,folder,user_id,view_count,favorite_count,last_viewed_at,updated_at,deleted,query(model,view)"}}, &looks); err != nil {
+		return nil, err
+	}
+	return looks, nil
+}
+
+// ListModels returns all LookML models with their explores.
+func (c *Client) ListModels(ctx context.Context) ([]LookMLModel, error) {
+	var models []LookMLModel
+	if err := c.get(ctx, "/lookml_models", nil, &models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// GetExplore returns an explore's definition.
+func (c *Client) GetExplore(ctx context.Context, model, explore string) (*Explore, error) {
+	var e Explore
+	path := fmt.Sprintf("/lookml_models/%s/explores/%s", url.PathEscape(model), url.PathEscape(explore))
+	query := url.Values{"fields": {"name,model_name,label,description,view_name,sql_table_name,connection_name,project_name,hidden,joins(name,from)"}}
+	if err := c.get(ctx, path, query, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetConnection returns a database connection.
+func (c *Client) GetConnection(ctx context.Context, name string) (*Connection, error) {
+	var conn Connection
+	if err := c.get(ctx, "/connections/"+url.PathEscape(name), nil, &conn); err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
@@ -0,0 +1,37 @@
+package looker
+
+// LookerDashboardFields describes the metadata fields Looker emits for a
+// Dashboard asset. It is kept as a documentation-only struct so downstream
+// tooling can introspect the shape of the metadata map.
+type LookerDashboardFields struct {
+	DashboardID     string `json:"dashboard_id" metadata:"dashboard_id" description:"Dashboard ID (model::name for LookML dashboards)"`
+	URL             string `json:"url" metadata:"url" description:"URL of the dashboard, Look or explore in Looker"`
+	Folder          string `json:"folder" metadata:"folder" description:"Folder the dashboard or Look is saved in"`
+	TileCount       int    `json:"tile_count" metadata:"tile_count" description:"Number of tiles on the dashboard"`
+	Explores        string `json:"explores" metadata:"explores" description:"Explores the dashboard's tiles query, as model.explore (comma-separated)"`
+	LookMLDashboard bool   `json:"lookml_dashboard" metadata:"lookml_dashboard" description:"Whether the dashboard is defined in LookML"`
+	ViewCount       int    `json:"view_count" metadata:"view_count" description:"Number of times viewed"`
+	FavoriteCount   int    `json:"favorite_count" metadata:"favorite_count" description:"Number of users who favorited it"`
+	LastViewedAt    string `json:"last_viewed_at" metadata:"last_viewed_at" description:"Last time it was viewed"`
+	UpdatedAt       string `json:"updated_at" metadata:"updated_at" description:"Last time it was updated"`
+	UserID          string `json:"user_id" metadata:"user_id" description:"ID of the Looker user who created it"`
+}
+
+// LookerLookFields describes the metadata fields for a Look asset.
+type LookerLookFields struct {
+	LookID      string `json:"look_id" metadata:"look_id" description:"Look ID"`
+	ModelName   string `json:"model_name" metadata:"model_name" description:"LookML model queried"`
+	ExploreName string `json:"explore_name" metadata:"explore_name" description:"Explore queried"`
+}
+
+// LookerExploreFields describes the metadata fields for an Explore asset.
+type LookerExploreFields struct {
+	ModelName      string `json:"model_name" metadata:"model_name" description:"LookML model defining the explore"`
+	ExploreName    string `json:"explore_name" metadata:"explore_name" description:"Explore name"`
+	ViewName       string `json:"view_name" metadata:"view_name" description:"Base view of the explore"`
+	SQLTableName   string `json:"sql_table_name" metadata:"sql_table_name" description:"Table of the base view"`
+	ConnectionName string `json:"connection_name" metadata:"connection_name" description:"Database connection the explore queries"`
+	ProjectName    string `json:"project_name" metadata:"project_name" description:"LookML project"`
+	Joins          string `json:"joins" metadata:"joins" description:"Views joined into the explore (comma-separated)"`
+	Hidden         bool   `json:"hidden" metadata:"hidden" description:"Whether the explore is hidden"`
+}
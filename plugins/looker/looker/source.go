@@ -0,0 +1,504 @@
+// Package looker ingests metadata from Looker, including dashboards, Looks
+// and explores, with lineage from the warehouse tables behind them.
+package looker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+// Config for the Looker plugin.
+type Config struct {
+	pluginsdk.BaseConfig `json:",inline"`
+
+	Host         string `json:"host" description:"Looker instance URL (e.g., https://company.cloud.looker.com)" validate:"required,url"`
+	APIURL       string `json:"api_url,omitempty" label:"API URL" description:"Looker API URL, when it differs from the instance URL (e.g., https://company.looker.com:19999)" validate:"omitempty,url"`
+	ClientID     string `json:"client_id" label:"Client ID" description:"API client ID" validate:"required"`
+	ClientSecret string `json:"client_secret" description:"API client secret" sensitive:"true" validate:"required"`
+
+	DiscoverDashboards bool `json:"discover_dashboards" description:"Discover dashboards" default:"true"`
+	DiscoverLooks      bool `json:"discover_looks" description:"Discover saved Looks" default:"true"`
+	DiscoverExplores   bool `json:"discover_explores" description:"Discover explores" default:"true"`
+	IncludeHidden      bool `json:"include_hidden" description:"Include hidden explores" default:"false"`
+
+	ViewTables map[string]string `json:"view_tables,omitempty" description:"Warehouse tables of LookML views, as view name to database.schema.table, for views the API doesn't report a table for"`
+}
+
+// Example configuration for the plugin
+var _ = `
+host: "https://company.cloud.looker.com"
+client_id: "${LOOKER_CLIENT_ID}"
+client_secret: "${LOOKER_CLIENT_SECRET}"
+discover_dashboards: true
+discover_looks: true
+discover_explores: true
+view_tables:
+  customers: "analytics.marts.customers"
+tags:
+  - "looker"
+`
+
+// Meta describes the plugin to the Marmot host.
+func Meta() pluginsdk.Meta {
+	return pluginsdk.Meta{
+		ID:          "looker",
+		Name:        "Looker",
+		Description: "Ingest metadata from Looker including dashboards, Looks, explores, and lineage to warehouse tables",
+		Icon:        "looker",
+		Category:    "bi",
+		Status:      "experimental",
+		Features:    []string{"Assets", "Lineage"},
+		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
+	}
+}
+
+// Source implements the Looker plugin.
+type Source struct {
+	config *Config
+	client *Client
+}
+
+// explore is an explore with the warehouse tables it reads.
+type explore struct {
+	*Explore
+	mrn    string
+	tables []string
+}
+
+// Validate validates and normalizes the plugin configuration.
+func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	config.Host = strings.TrimSuffix(config.Host, "/")
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+
+	if err := pluginsdk.ValidateStruct(config); err != nil {
+		return nil, err
+	}
+
+	s.config = config
+	return rawConfig, nil
+}
+
+// Discover discovers Looker explores, Looks and dashboards.
+func (s *Source) Discover(ctx context.Context, rawConfig pluginsdk.RawConfig) (*pluginsdk.DiscoveryResult, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+	s.config = config
+	s.config.Host = strings.TrimSuffix(s.config.Host, "/")
+
+	apiURL := strings.TrimSuffix(s.config.APIURL, "/")
+	if apiURL == "" {
+		apiURL = s.config.Host
+	}
+	s.client = NewClient(ClientConfig{
+		BaseURL:      apiURL,
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+	})
+
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	explores, err := s.loadExplores(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading explores: %w", err)
+	}
+
+	if s.config.DiscoverExplores {
+		for _, key := range sortedKeys(explores) {
+			e := explores[key]
+			assets = append(assets, s.createExploreAsset(e))
+			lineages = append(lineages, feeds(e.tables, e.mrn)...)
+		}
+	}
+
+	looks := make(map[string]string)
+	if s.config.DiscoverLooks {
+		lookAssets, lookLineages, err := s.discoverLooks(ctx, explores, looks)
+		if err != nil {
+			return nil, fmt.Errorf("discovering Looks: %w", err)
+		}
+		assets = append(assets, lookAssets...)
+		lineages = append(lineages, lookLineages...)
+	}
+
+	if s.config.DiscoverDashboards {
+		dashboardAssets, dashboardLineages, err := s.discoverDashboards(ctx, explores, looks)
+		if err != nil {
+			return nil, fmt.Errorf("discovering dashboards: %w", err)
+		}
+		assets = append(assets, dashboardAssets...)
+		lineages = append(lineages, dashboardLineages...)
+	}
+
+	log.Info().
+		Int("assets", len(assets)).
+		Int("lineages", len(lineages)).
+		Msg("Looker discovery completed")
+
+	return &pluginsdk.DiscoveryResult{
+		Assets:  assets,
+		Lineage: lineages,
+	}, nil
+}
+
+// loadExplores fetches every explore, keyed by model and explore name, and
+// works out the tables each reads. The API reports the table of an
+// explore's base view only, so a joined view's table is taken from
+// view_tables, or else from an explore based on that view.
+func (s *Source) loadExplores(ctx context.Context) (map[string]*explore, error) {
+	models, err := s.client.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing LookML models: %w", err)
+	}
+
+	explores := make(map[string]*explore)
+	viewTables := make(map[string]string)
+	for _, model := range models {
+		for _, summary := range model.Explores {
+			if summary.Hidden && !s.config.IncludeHidden {
+				continue
+			}
+			def, err := s.client.GetExplore(ctx, model.Name, summary.Name)
+			if err != nil {
+				log.Warn().Err(err).Str("model", model.Name).Str("explore", summary.Name).Msg("Failed to fetch explore")
+				continue
+			}
+			if def.ModelName == "" {
+				def.ModelName = model.Name
+			}
+			key := def.ModelName + "." + def.Name
+			explores[key] = &explore{Explore: def, mrn: mrn.New("Explore", "Looker", key)}
+
+			view := def.ViewName
+			if view == "" {
+				view = def.Name
+			}
+			if _, ok := viewTables[view]; !ok && def.SQLTableName != "" {
+				viewTables[view] = def.SQLTableName
+			}
+		}
+	}
+	for view, table := range s.config.ViewTables {
+		viewTables[view] = table
+	}
+
+	connections := make(map[string]*Connection)
+	for _, key := range sortedKeys(explores) {
+		e := explores[key]
+		conn, ok := connections[e.ConnectionName]
+		if !ok && e.ConnectionName != "" {
+			conn, err = s.client.GetConnection(ctx, e.ConnectionName)
+			if err != nil {
+				log.Warn().Err(err).Str("connection", e.ConnectionName).Msg("Failed to fetch connection")
+			}
+			connections[e.ConnectionName] = conn
+		}
+		if conn == nil {
+			continue
+		}
+
+		views := []string{e.ViewName}
+		if e.ViewName == "" {
+			views[0] = e.Name
+		}
+		for _, join := range e.Joins {
+			views = append(views, join.View())
+		}
+
+		seen := make(map[string]bool)
+		for i, view := range views {
+			sqlTable := viewTables[view]
+			if i == 0 && e.SQLTableName != "" {
+				sqlTable = e.SQLTableName
+			}
+			table, ok := tableMRN(conn, sqlTable)
+			if !ok || seen[table] {
+				continue
+			}
+			seen[table] = true
+			e.tables = append(e.tables, table)
+		}
+	}
+
+	return explores, nil
+}
+
+// upstreams returns what a query reads: its explore when explores are
+// discovered, otherwise the explore's tables.
+func (s *Source) upstreams(explores map[string]*explore, q *Query) []string {
+	if q == nil || q.Model == "" || q.View == "" {
+		return nil
+	}
+	e, ok := explores[q.Model+"."+q.View]
+	if !ok {
+		return nil
+	}
+	if s.config.DiscoverExplores {
+		return []string{e.mrn}
+	}
+	return e.tables
+}
+
+// discoverLooks discovers saved Looks, recording each Look's MRN in looks
+// so dashboards can link to the Looks on them.
+func (s *Source) discoverLooks(ctx context.Context, explores map[string]*explore, looks map[string]string) ([]pluginsdk.Asset, []pluginsdk.LineageEdge, error) {
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	list, err := s.client.ListLooks(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing Looks: %w", err)
+	}
+
+	log.Debug().Int("count", len(list)).Msg("Found Looks")
+
+	for _, look := range list {
+		if look.Deleted {
+			continue
+		}
+		asset := s.createLookAsset(look)
+		assets = append(assets, asset)
+		looks[look.ID] = *asset.MRN
+
+		lineages = append(lineages, feeds(s.upstreams(explores, look.Query), *asset.MRN)...)
+	}
+
+	return assets, lineages, nil
+}
+
+// discoverDashboards discovers dashboards. A dashboard is fed by the Looks
+// on it, and by the explores its other tiles query.
+func (s *Source) discoverDashboards(ctx context.Context, explores map[string]*explore, looks map[string]string) ([]pluginsdk.Asset, []pluginsdk.LineageEdge, error) {
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	list, err := s.client.ListDashboards(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing dashboards: %w", err)
+	}
+
+	log.Debug().Int("count", len(list)).Msg("Found dashboards")
+
+	for _, summary := range list {
+		if summary.Deleted {
+			continue
+		}
+		dashboard, err := s.client.GetDashboard(ctx, summary.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("dashboard_id", summary.ID).Msg("Failed to fetch dashboard")
+			continue
+		}
+
+		var sources []string
+		queried := make(map[string]bool)
+		for _, element := range dashboard.DashboardElements {
+			if lookMRN, ok := looks[element.LookID]; ok {
+				sources = append(sources, lookMRN)
+				continue
+			}
+			for _, q := range element.queries() {
+				if q.Model != "" && q.View != "" {
+					queried[q.Model+"."+q.View] = true
+				}
+				sources = append(sources, s.upstreams(explores, q)...)
+			}
+		}
+
+		asset := s.createDashboardAsset(dashboard, sortedKeys(queried))
+		assets = append(assets, asset)
+		lineages = append(lineages, feeds(dedupe(sources), *asset.MRN)...)
+	}
+
+	return assets, lineages, nil
+}
+
+// queries returns the queries behind a dashboard tile.
+func (e DashboardElement) queries() []*Query {
+	var queries []*Query
+	if e.Query != nil {
+		queries = append(queries, e.Query)
+	}
+	if e.Look != nil && e.Look.Query != nil {
+		queries = append(queries, e.Look.Query)
+	}
+	if e.ResultMaker != nil {
+		if e.ResultMaker.Query != nil {
+			queries = append(queries, e.ResultMaker.Query)
+		}
+		for _, merged := range e.ResultMaker.MergeResults {
+			if merged.Query != nil {
+				queries = append(queries, merged.Query)
+			}
+		}
+	}
+	return queries
+}
+
+func (s *Source) createExploreAsset(e *explore) pluginsdk.Asset {
+	name := e.Name
+	if e.Label != "" {
+		name = e.Label
+	}
+
+	metadata := map[string]interface{}{
+		"model_name":      e.ModelName,
+		"explore_name":    e.Name,
+		"view_name":       e.ViewName,
+		"sql_table_name":  e.SQLTableName,
+		"connection_name": e.ConnectionName,
+		"project_name":    e.ProjectName,
+		"url":             fmt.Sprintf("%s/explore/%s/%s", s.config.Host, e.ModelName, e.Name),
+	}
+	if len(e.Joins) > 0 {
+		joins := make([]string, 0, len(e.Joins))
+		for _, j := range e.Joins {
+			joins = append(joins, j.Name)
+		}
+		metadata["joins"] = strings.Join(joins, ", ")
+	}
+	if e.Hidden {
+		metadata["hidden"] = true
+	}
+
+	return s.asset(name, e.mrn, "Explore", e.Description, metadata)
+}
+
+func (s *Source) createLookAsset(look Look) pluginsdk.Asset {
+	metadata := map[string]interface{}{
+		"look_id":        look.ID,
+		"url":            fmt.Sprintf("%s/looks/%s", s.config.Host, look.ID),
+		"view_count":     look.ViewCount,
+		"favorite_count": look.FavoriteCount,
+		"user_id":        look.UserID,
+	}
+	if look.Folder != nil {
+		metadata["folder"] = look.Folder.Name
+	}
+	if look.LastViewedAt != nil {
+		metadata["last_viewed_at"] = *look.LastViewedAt
+	}
+	if look.UpdatedAt != nil {
+		metadata["updated_at"] = *look.UpdatedAt
+	}
+	if look.Query != nil {
+		metadata["model_name"] = look.Query.Model
+		metadata["explore_name"] = look.Query.View
+	}
+
+	return s.asset(look.Title, mrn.New("Look", "Looker", look.ID), "Look", look.Description, metadata)
+}
+
+func (s *Source) createDashboardAsset(dashboard *Dashboard, explores []string) pluginsdk.Asset {
+	metadata := map[string]interface{}{
+		"dashboard_id":   dashboard.ID,
+		"url":            fmt.Sprintf("%s/dashboards/%s", s.config.Host, dashboard.ID),
+		"view_count":     dashboard.ViewCount,
+		"favorite_count": dashboard.FavoriteCount,
+		"tile_count":     len(dashboard.DashboardElements),
+		"user_id":        dashboard.UserID,
+	}
+	if strings.Contains(dashboard.ID, "::") {
+		metadata["lookml_dashboard"] = true
+	}
+	if dashboard.Folder != nil {
+		metadata["folder"] = dashboard.Folder.Name
+	}
+	if dashboard.LastViewedAt != nil {
+		metadata["last_viewed_at"] = *dashboard.LastViewedAt
+	}
+	if dashboard.UpdatedAt != nil {
+		metadata["updated_at"] = *dashboard.UpdatedAt
+	}
+	if len(explores) > 0 {
+		metadata["explores"] = strings.Join(explores, ", ")
+	}
+
+	return s.asset(dashboard.Title, mrn.New("Dashboard", "Looker", dashboard.ID), "Dashboard", dashboard.Description, metadata)
+}
+
+func (s *Source) asset(name, mrnValue, assetType, description string, metadata map[string]interface{}) pluginsdk.Asset {
+	var desc *string
+	if description != "" {
+		desc = &description
+	}
+
+	cleanMetadata := s.cleanMetadata(metadata)
+
+	return pluginsdk.Asset{
+		Name:        &name,
+		MRN:         &mrnValue,
+		Type:        assetType,
+		Providers:   []string{"Looker"},
+		Description: desc,
+		Metadata:    cleanMetadata,
+		Tags:        s.config.Tags,
+		Sources: []pluginsdk.AssetSource{{
+			Name:       "Looker",
+			LastSyncAt: time.Now(),
+			Properties: cleanMetadata,
+			Priority:   1,
+		}},
+	}
+}
+
+func (s *Source) cleanMetadata(metadata map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{})
+	for k, v := range metadata {
+		if v == nil {
+			continue
+		}
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		cleaned[k] = v
+	}
+	return cleaned
+}
+
+// feeds returns FEEDS edges from each source to target.
+func feeds(sources []string, target string) []pluginsdk.LineageEdge {
+	edges := make([]pluginsdk.LineageEdge, 0, len(sources))
+	for _, source := range sources {
+		edges = append(edges, pluginsdk.LineageEdge{
+			Source: source,
+			Target: target,
+			Type:   "FEEDS",
+		})
+	}
+	return edges
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,231 @@
+package looker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      pluginsdk.RawConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid config",
+			config: pluginsdk.RawConfig{
+				"host":          "https://company.cloud.looker.com/",
+				"client_id":     "id",
+				"client_secret": "secret",
+			},
+		},
+		{
+			name: "missing credentials",
+			config: pluginsdk.RawConfig{
+				"host": "https://company.cloud.looker.com",
+			},
+			wantErr:     true,
+			errContains: "client_id",
+		},
+		{
+			name: "invalid API URL",
+			config: pluginsdk.RawConfig{
+				"host":          "https://company.cloud.looker.com",
+				"api_url":       "not a url",
+				"client_id":     "id",
+				"client_secret": "secret",
+			},
+			wantErr:     true,
+			errContains: "api_url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Source{}
+			_, err := s.Validate(tt.config)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func newLookerServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/4.0/login" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3600})
+			return
+		}
+		if r.Header.Get("Authorization") != "token token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(APIError{Message: "Requires authentication."})
+			return
+		}
+
+		var response interface{}
+		switch r.URL.Path {
+		case "/api/4.0/lookml_models":
+			response = []LookMLModel{{
+				Name: "ecommerce",
+				Explores: []LookMLModelExplore{
+					{Name: "orders"},
+					{Name: "customers"},
+					{Name: "internal", Hidden: true},
+				},
+			}}
+		case "/api/4.0/lookml_models/ecommerce/explores/orders":
+			response = Explore{
+				Name: "orders", ModelName: "ecommerce", Label: "Orders", ViewName: "orders",
+				SQLTableName: "marts.orders", ConnectionName: "snowflake_prod",
+				Joins: []ExploreJoin{{Name: "customers"}, {Name: "order_facts"}, {Name: "buyer", From: "customers"}},
+			}
+		case "/api/4.0/lookml_models/ecommerce/explores/customers":
+			response = Explore{
+				Name: "customers", ModelName: "ecommerce", ViewName: "customers",
+				SQLTableName: `"ANALYTICS"."MARTS"."CUSTOMERS"`, ConnectionName: "snowflake_prod",
+			}
+		case "/api/4.0/connections/snowflake_prod":
+			response = Connection{Name: "snowflake_prod", DialectName: "snowflake", Database: "ANALYTICS", Schema: "PUBLIC"}
+		case "/api/4.0/looks":
+			response = []Look{
+				{ID: "7", Title: "Weekly orders", Query: &Query{Model: "ecommerce", View: "orders"}},
+				{ID: "8", Title: "Old", Deleted: true},
+			}
+		case "/api/4.0/dashboards":
+			response = []Dashboard{{ID: "42", Title: "Sales"}}
+		case "/api/4.0/dashboards/42":
+			response = Dashboard{
+				ID: "42", Title: "Sales", Folder: &Folder{Name: "Shared"},
+				DashboardElements: []DashboardElement{
+					{ID: "1", LookID: "7", Look: &Look{ID: "7", Query: &Query{Model: "ecommerce", View: "orders"}}},
+					{ID: "2", Query: &Query{Model: "ecommerce", View: "customers"}},
+					{ID: "3", Type: "text"},
+				},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(APIError{Message: "Not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func lineageSet(result *pluginsdk.DiscoveryResult) map[string]bool {
+	edges := make(map[string]bool)
+	for _, l := range result.Lineage {
+		edges[l.Source+" -> "+l.Target] = true
+	}
+	return edges
+}
+
+func TestSource_Discover(t *testing.T) {
+	server := newLookerServer(t)
+	defer server.Close()
+
+	s := &Source{}
+	result, err := s.Discover(context.Background(), pluginsdk.RawConfig{
+		"host":                server.URL,
+		"client_id":           "id",
+		"client_secret":       "secret",
+		"discover_dashboards": true,
+		"discover_looks":      true,
+		"discover_explores":   true,
+	})
+	require.NoError(t, err)
+
+	byMRN := make(map[string]pluginsdk.Asset)
+	for _, a := range result.Assets {
+		byMRN[*a.MRN] = a
+	}
+	require.Len(t, byMRN, 4, "two explores, one Look and one dashboard")
+
+	dashboard, ok := byMRN["mrn://dashboard/looker/42"]
+	require.True(t, ok)
+	assert.Equal(t, "Dashboard", dashboard.Type)
+	assert.Equal(t, server.URL+"/dashboards/42", dashboard.Metadata["url"])
+	assert.Equal(t, "Shared", dashboard.Metadata["folder"])
+	assert.Equal(t, "ecommerce.customers", dashboard.Metadata["explores"])
+
+	orders := "mrn://explore/looker/ecommerce.orders"
+	customers := "mrn://explore/looker/ecommerce.customers"
+	assert.Equal(t, map[string]bool{
+		"mrn://table/snowflake/analytics.marts.orders -> " + orders:       true,
+		"mrn://table/snowflake/analytics.marts.customers -> " + orders:    true,
+		"mrn://table/snowflake/analytics.marts.customers -> " + customers: true,
+		orders + " -> mrn://look/looker/7":                                true,
+		"mrn://look/looker/7 -> mrn://dashboard/looker/42":                true,
+		customers + " -> mrn://dashboard/looker/42":                       true,
+	}, lineageSet(result), "order_facts has no known table and is left out")
+}
+
+func TestSource_DiscoverWithoutExplores(t *testing.T) {
+	server := newLookerServer(t)
+	defer server.Close()
+
+	s := &Source{}
+	result, err := s.Discover(context.Background(), pluginsdk.RawConfig{
+		"host":                server.URL,
+		"client_id":           "id",
+		"client_secret":       "secret",
+		"discover_dashboards": true,
+		"discover_looks":      false,
+		"discover_explores":   false,
+		"view_tables":         map[string]interface{}{"order_facts": "analytics.marts.order_facts"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Assets, 1)
+	assert.Equal(t, map[string]bool{
+		"mrn://table/snowflake/analytics.marts.orders -> mrn://dashboard/looker/42":      true,
+		"mrn://table/snowflake/analytics.marts.customers -> mrn://dashboard/looker/42":   true,
+		"mrn://table/snowflake/analytics.marts.order_facts -> mrn://dashboard/looker/42": true,
+	}, lineageSet(result), "without Looks and explores, tiles link straight to tables")
+}
+
+func TestTableMRN(t *testing.T) {
+	snowflake := &Connection{DialectName: "snowflake", Database: "ANALYTICS", Schema: "PUBLIC"}
+	bigquery := &Connection{DialectName: "bigquery_standard_sql", Host: "my-project", Database: "reporting"}
+
+	tests := []struct {
+		name   string
+		conn   *Connection
+		table  string
+		want   string
+		wantOK bool
+	}{
+		{"fully qualified", snowflake, "RAW.SHOP.ORDERS", "mrn://table/snowflake/raw.shop.orders", true},
+		{"schema and table", snowflake, "marts.orders", "mrn://table/snowflake/analytics.marts.orders", true},
+		{"table only", snowflake, "orders ;", "mrn://table/snowflake/analytics.public.orders", true},
+		{"quoted", snowflake, `"ANALYTICS"."MARTS"."ORDERS"`, "mrn://table/snowflake/analytics.marts.orders", true},
+		{"bigquery backticks", bigquery, "`other-project.sales.orders`", "mrn://table/bigquery/other-project.sales.orders", true},
+		{"bigquery dataset and table", bigquery, "sales.orders", "mrn://table/bigquery/my-project.sales.orders", true},
+		{"bigquery legacy", bigquery, "[other-project:sales.orders]", "mrn://table/bigquery/other-project.sales.orders", true},
+		{"liquid", snowflake, "{% if x %}a{% endif %}", "", false},
+		{"templated", snowflake, "${orders.SQL_TABLE_NAME}", "", false},
+		{"empty", snowflake, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tableMRN(tt.conn, tt.table)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
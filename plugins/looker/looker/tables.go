@@ -0,0 +1,74 @@
+package looker
+
+import (
+	"strings"
+
+	"github.com/marmotdata/plugin-sdk/mrn"
+)
+
+// dialectProviders maps Looker dialects to the provider names the
+// warehouse plugins and dbt give their tables.
+var dialectProviders = map[string]string{
+	"snowflake":             "Snowflake",
+	"bigquery_standard_sql": "BigQuery",
+	"bigquery_legacy_sql":   "BigQuery",
+	"redshift":              "Redshift",
+	"postgres":              "PostgreSQL",
+	"databricks":            "Databricks",
+	"spark":                 "Spark",
+	"mysql":                 "MySQL",
+	"mssql":                 "SQLServer",
+	"azure_sql_dw":          "Azure Synapse",
+	"oracle":                "Oracle",
+	"trino":                 "Trino",
+	"presto":                "Trino",
+	"athena":                "Athena",
+	"clickhouse":            "ClickHouse",
+	"duckdb":                "DuckDB",
+}
+
+// tableMRN returns the MRN of the table a view's sql_table_name names,
+// qualified as database.schema.table with the connection's defaults where
+// the name leaves them out. Names that aren't plain table references, such
+// as derived tables or Liquid templates, have no MRN.
+func tableMRN(conn *Connection, sqlTableName string) (string, bool) {
+	name := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlTableName), ";"))
+	if name == "" || strings.ContainsAny(name, " \t\n(){}$%") {
+		return "", false
+	}
+	name = strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(name)
+
+	provider, ok := dialectProviders[conn.DialectName]
+	if !ok {
+		provider = conn.DialectName
+	}
+
+	// Legacy SQL separates the project with a colon, as in project:dataset.table.
+	database, schema := conn.Database, conn.Schema
+	if provider == "BigQuery" {
+		name = strings.Replace(name, ":", ".", 1)
+		database, schema = conn.Host, conn.Database
+	}
+
+	parts := strings.Split(name, ".")
+	for _, p := range parts {
+		if p == "" {
+			return "", false
+		}
+	}
+	switch len(parts) {
+	case 1:
+		if schema != "" {
+			parts = append([]string{schema}, parts...)
+		}
+		if database != "" && schema != "" {
+			parts = append([]string{database}, parts...)
+		}
+	case 2:
+		if database != "" {
+			parts = append([]string{database}, parts...)
+		}
+	}
+
+	return mrn.New("Table", provider, strings.Join(parts, ".")), true
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+
+	"github.com/marmotdata/marmot/plugins/looker/looker"
+)
+
+func main() {
+	pluginsdk.Serve(&pluginsdk.ServeConfig{
+		Meta:   looker.Meta(),
+		Source: &looker.Source{},
+	})
+}
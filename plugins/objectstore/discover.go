@@ -0,0 +1,177 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+// Store is the part of an object storage client dataset inference needs.
+// The S3, GCS and Azure Blob Storage plugins each implement it for one
+// bucket or container.
+type Store interface {
+	// List calls fn for each object under prefix, in key order. It stops
+	// early when fn returns an error, and returns that error.
+	List(ctx context.Context, prefix string, fn func(Object) error) error
+	// ReadRange reads length bytes of an object from offset.
+	ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error)
+}
+
+// errMaxObjects stops a listing once MaxObjects objects have been seen.
+var errMaxObjects = errors.New("max objects reached")
+
+// Target identifies the bucket or container datasets are discovered in.
+type Target struct {
+	// Provider is the asset provider, such as "S3".
+	Provider string
+	// Bucket is the bucket or container name.
+	Bucket string
+	// BucketMRN is the MRN of the bucket or container asset, which
+	// contains the datasets.
+	BucketMRN string
+	// Scheme is the URI scheme of datasets, such as "s3".
+	Scheme string
+	// Tags are the plugin's configured tags.
+	Tags []string
+}
+
+// Discover lists the objects of a bucket, infers datasets from their keys
+// and returns a Dataset asset for each, with a CONTAINS edge from the
+// bucket.
+func Discover(ctx context.Context, store Store, target Target, config DatasetConfig) ([]pluginsdk.Asset, []pluginsdk.LineageEdge, error) {
+	objects, err := listObjects(ctx, store, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var assets []pluginsdk.Asset
+	var lineages []pluginsdk.LineageEdge
+
+	for _, ds := range InferDatasets(objects) {
+		var sample *Sample
+		if config.SampleSchemas {
+			sample, err = SampleSchema(ctx, store, ds)
+			if err != nil {
+				log.Debug().Err(err).Str("bucket", target.Bucket).Str("dataset", ds.Prefix).Msg("Failed to sample dataset schema")
+			}
+		}
+
+		asset := createDatasetAsset(target, ds, sample)
+		assets = append(assets, asset)
+		lineages = append(lineages, pluginsdk.LineageEdge{
+			Source: target.BucketMRN,
+			Target: *asset.MRN,
+			Type:   "CONTAINS",
+		})
+	}
+
+	return assets, lineages, nil
+}
+
+// listObjects lists the objects under the configured prefixes, up to
+// MaxObjects in total.
+func listObjects(ctx context.Context, store Store, config DatasetConfig) ([]Object, error) {
+	prefixes := config.DatasetPrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	var objects []Object
+	for _, prefix := range prefixes {
+		err := store.List(ctx, prefix, func(obj Object) error {
+			if config.MaxObjects > 0 && len(objects) >= config.MaxObjects {
+				return errMaxObjects
+			}
+			objects = append(objects, obj)
+			return nil
+		})
+		if errors.Is(err, errMaxObjects) {
+			log.Warn().Int("max_objects", config.MaxObjects).Msg("Reached max_objects, datasets may be incomplete")
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing objects under %q: %w", prefix, err)
+		}
+	}
+	return objects, nil
+}
+
+func createDatasetAsset(target Target, ds *Dataset, sample *Sample) pluginsdk.Asset {
+	qualified := target.Bucket
+	if ds.Prefix != "" {
+		qualified = target.Bucket + "/" + ds.Prefix
+	}
+	mrnValue := mrn.New("Dataset", target.Provider, qualified)
+	name := ds.Name(target.Bucket)
+
+	uri := fmt.Sprintf("%s://%s/", target.Scheme, target.Bucket)
+	if ds.Prefix != "" {
+		uri += ds.Prefix
+		if ds.FileCount > 1 || ds.PartitionCount > 0 || ds.Format == "delta" {
+			uri += "/"
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"bucket":     target.Bucket,
+		"uri":        uri,
+		"format":     ds.Format,
+		"file_count": ds.FileCount,
+		"size_bytes": ds.SizeBytes,
+	}
+	if ds.Prefix != "" {
+		metadata["prefix"] = ds.Prefix
+	}
+	if ds.Compression != "" {
+		metadata["compression"] = ds.Compression
+	}
+	if len(ds.PartitionKeys) > 0 {
+		metadata["partition_keys"] = strings.Join(ds.PartitionKeys, ", ")
+		metadata["partition_count"] = ds.PartitionCount
+	}
+	if !ds.LastModified.IsZero() {
+		metadata["last_modified"] = ds.LastModified.Format(time.RFC3339)
+	}
+	if sample != nil {
+		metadata["sample_file"] = ds.Sample.Key
+		if sample.RowCount > 0 {
+			metadata["sample_row_count"] = sample.RowCount
+		}
+		if sample.CreatedBy != "" {
+			metadata["created_by"] = sample.CreatedBy
+		}
+	}
+
+	a := pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      "Dataset",
+		Providers: []string{target.Provider},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(target.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       target.Provider,
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+
+	if sample != nil && len(sample.Columns) > 0 {
+		jsonBytes, err := json.Marshal(sample.Columns)
+		if err != nil {
+			log.Warn().Err(err).Str("dataset", qualified).Msg("Failed to marshal columns")
+		} else {
+			a.Schema = map[string]string{"columns": string(jsonBytes)}
+		}
+	}
+
+	return a
+}
@@ -0,0 +1,301 @@
+package objectstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	objects map[string][]byte
+	reads   int
+}
+
+func (m *memStore) List(_ context.Context, prefix string, fn func(Object) error) error {
+	keys := make([]string, 0, len(m.objects))
+	for k := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn(Object{Key: k, Size: int64(len(m.objects[k])), LastModified: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ReadRange(_ context.Context, key string, offset, length int64) ([]byte, error) {
+	m.reads++
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", key)
+	}
+	end := offset + length
+	if end > int64(len(data)) {
+		end = int64(len(data))
+	}
+	return data[offset:end], nil
+}
+
+// compactWriter encodes the Thrift compact protocol, to build Parquet
+// footers for tests.
+type compactWriter struct {
+	bytes.Buffer
+	lastIDs []int16
+}
+
+func (w *compactWriter) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.Write(b[:binary.PutUvarint(b[:], uint64((v<<1)^(v>>63)))])
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta<<4) | typ)
+	} else {
+		w.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	w.lastIDs[len(w.lastIDs)-1] = id
+}
+
+func (w *compactWriter) begin() { w.lastIDs = append(w.lastIDs, 0) }
+func (w *compactWriter) end()   { w.WriteByte(ctStop); w.lastIDs = w.lastIDs[:len(w.lastIDs)-1] }
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, ctI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) str(id int16, s string) {
+	w.field(id, ctBinary)
+	var b [binary.MaxVarintLen64]byte
+	w.Write(b[:binary.PutUvarint(b[:], uint64(len(s)))])
+	w.WriteString(s)
+}
+
+type testElement struct {
+	name                                 string
+	physical, repetition, children, conv int32
+	logical                              int16
+	hasPhysical, hasConv                 bool
+}
+
+// parquetFile returns a Parquet file with no data pages, whose footer
+// describes elements.
+func parquetFile(elements []testElement, numRows int64) []byte {
+	w := &compactWriter{}
+	w.begin()
+	w.i32(1, 1)
+	w.field(2, ctList)
+	w.WriteByte(0xf0 | ctStruct)
+	var b [binary.MaxVarintLen64]byte
+	w.Write(b[:binary.PutUvarint(b[:], uint64(len(elements)))])
+	for _, el := range elements {
+		w.begin()
+		if el.hasPhysical {
+			w.i32(1, el.physical)
+		}
+		w.i32(3, el.repetition)
+		w.str(4, el.name)
+		if el.children > 0 {
+			w.i32(5, el.children)
+		}
+		if el.hasConv {
+			w.i32(6, el.conv)
+		}
+		if el.logical > 0 {
+			w.field(10, ctStruct)
+			w.begin()
+			w.field(el.logical, ctStruct)
+			w.begin()
+			w.end()
+			w.end()
+		}
+		w.end()
+	}
+	w.field(3, ctI64)
+	w.varint(numRows)
+	// An empty row group list, which is skipped.
+	w.field(4, ctList)
+	w.WriteByte(ctStruct)
+	// Key-value metadata with a boolean-free struct, which is skipped.
+	w.field(5, ctList)
+	w.WriteByte(0x10 | ctStruct)
+	w.begin()
+	w.str(1, "ARROW:schema")
+	w.end()
+	w.str(6, "parquet-mr version 1.12.3")
+	w.end()
+
+	footer := w.Bytes()
+	file := append([]byte("PAR1"), footer...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(footer)))
+	return append(file, parquetMagic...)
+}
+
+func gzipBytes(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestParseParquetFooter(t *testing.T) {
+	file := parquetFile([]testElement{
+		{name: "schema", children: 8},
+		{name: "id", physical: typeInt64, hasPhysical: true, repetition: 0},
+		{name: "name", physical: typeByteArray, hasPhysical: true, repetition: 1, conv: convertedUTF8, hasConv: true},
+		{name: "created_at", physical: typeInt64, hasPhysical: true, repetition: 1, logical: logicalTimestamp},
+		{name: "tags", repetition: 1, children: 1, conv: convertedList, hasConv: true},
+		{name: "list", repetition: 2, children: 1},
+		{name: "element", physical: typeByteArray, hasPhysical: true, repetition: 1},
+		{name: "score", physical: typeDouble, hasPhysical: true, repetition: 1},
+		{name: "address", repetition: 1, children: 2},
+		{name: "city", physical: typeByteArray, hasPhysical: true, repetition: 1, logical: logicalString},
+		{name: "zip", physical: typeInt32, hasPhysical: true, repetition: 0},
+		{name: "lines", repetition: 1, children: 1, logical: logicalList},
+		{name: "list", repetition: 2, children: 1},
+		{name: "element", repetition: 1, children: 1},
+		{name: "sku", physical: typeByteArray, hasPhysical: true, repetition: 0, logical: logicalString},
+		{name: "attrs", repetition: 1, children: 1, conv: convertedMap, hasConv: true},
+		{name: "key_value", repetition: 2, children: 2},
+		{name: "key", physical: typeByteArray, hasPhysical: true, repetition: 0, logical: logicalString},
+		{name: "value", physical: typeInt64, hasPhysical: true, repetition: 1},
+	}, 42)
+
+	length, err := footerLength(file)
+	require.NoError(t, err)
+	footer, err := parseParquetFooter(file[int64(len(file))-length-8 : len(file)-8])
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), footer.NumRows)
+	assert.Equal(t, "parquet-mr version 1.12.3", footer.CreatedBy)
+	assert.Equal(t, []Column{
+		{Name: "id", DataType: "INT64", Nullable: false},
+		{Name: "name", DataType: "STRING", Nullable: true},
+		{Name: "created_at", DataType: "TIMESTAMP", Nullable: true},
+		{Name: "tags", DataType: "ARRAY<BINARY>", Nullable: true},
+		{Name: "score", DataType: "DOUBLE", Nullable: true},
+		{Name: "address", DataType: "STRUCT", Nullable: true, Fields: []Column{
+			{Name: "city", DataType: "STRING", Nullable: true},
+			{Name: "zip", DataType: "INT32", Nullable: false},
+		}},
+	}, footer.Columns[:6])
+	assert.Equal(t, Column{Name: "lines", DataType: "ARRAY<STRUCT>", Nullable: true, Fields: []Column{
+		{Name: "sku", DataType: "STRING", Nullable: false},
+	}}, footer.Columns[6])
+	assert.Equal(t, Column{Name: "attrs", DataType: "MAP<STRING, INT64>", Nullable: true}, footer.Columns[7])
+
+	_, err = footerLength([]byte("not parquet"))
+	assert.Error(t, err)
+}
+
+func TestCSVColumns(t *testing.T) {
+	columns, err := csvColumns([]byte("id;amount;paid;day;note\n1;9.99;true;2024-01-01;\n2;10;false;2024-01-02;hi\n3;1"), true)
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "id", DataType: "BIGINT", Nullable: false},
+		{Name: "amount", DataType: "DOUBLE", Nullable: false},
+		{Name: "paid", DataType: "BOOLEAN", Nullable: false},
+		{Name: "day", DataType: "DATE", Nullable: false},
+		{Name: "note", DataType: "VARCHAR", Nullable: true},
+	}, columns)
+}
+
+func TestJSONColumns(t *testing.T) {
+	columns, err := jsonColumns([]byte(`{"id": 1, "user": {"name": "a"}, "ts": "2024-01-01T00:00:00Z"}
+{"id": 2.5, "ts": "2024-01-02T00:00:00Z", "tags": ["x"]}
+{"id": 3, "ts`))
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "id", DataType: "DOUBLE", Nullable: false},
+		{Name: "ts", DataType: "TIMESTAMP", Nullable: false},
+		{Name: "user", DataType: "STRUCT", Nullable: true},
+		{Name: "tags", DataType: "ARRAY", Nullable: true},
+	}, columns)
+
+	columns, err = jsonColumns([]byte(`[{"a": true}, {"a": null}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Column{{Name: "a", DataType: "BOOLEAN", Nullable: true}}, columns)
+}
+
+func TestDiscover(t *testing.T) {
+	parquet := parquetFile([]testElement{
+		{name: "schema", children: 2},
+		{name: "event_id", physical: typeByteArray, hasPhysical: true, repetition: 0, logical: logicalString},
+		{name: "amount", physical: typeDouble, hasPhysical: true, repetition: 1},
+	}, 7)
+
+	store := &memStore{objects: map[string][]byte{
+		"events/dt=2024-01-01/part-0000.parquet": parquet,
+		"events/dt=2024-01-02/part-0000.parquet": parquet,
+		"exports/customers.csv.gz":               gzipBytes(t, "id,email\n1,a@example.com\n"),
+		"images/logo.png":                        []byte("png"),
+	}}
+
+	bucketMRN := mrn.New("Bucket", "S3", "data-lake")
+	assets, lineages, err := Discover(context.Background(), store, Target{
+		Provider:  "S3",
+		Bucket:    "data-lake",
+		BucketMRN: bucketMRN,
+		Scheme:    "s3",
+		Tags:      []string{"lake", "${format}"},
+	}, DatasetConfig{DiscoverDatasets: true, MaxObjects: 100, SampleSchemas: true})
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	require.Len(t, lineages, 2)
+
+	events := assets[0]
+	assert.Equal(t, "events", *events.Name)
+	assert.Equal(t, mrn.New("Dataset", "S3", "data-lake/events"), *events.MRN)
+	assert.Equal(t, "Dataset", events.Type)
+	assert.Equal(t, "s3://data-lake/events/", events.Metadata["uri"])
+	assert.Equal(t, "parquet", events.Metadata["format"])
+	assert.Equal(t, "dt", events.Metadata["partition_keys"])
+	assert.Equal(t, 2, events.Metadata["partition_count"])
+	assert.Equal(t, int64(7), events.Metadata["sample_row_count"])
+	assert.Equal(t, []string{"lake", "parquet"}, events.Tags)
+	assert.JSONEq(t, `[{"column_name":"event_id","data_type":"STRING","is_nullable":false},{"column_name":"amount","data_type":"DOUBLE","is_nullable":true}]`, events.Schema["columns"])
+
+	customers := assets[1]
+	assert.Equal(t, "exports", *customers.Name)
+	assert.Equal(t, "gzip", customers.Metadata["compression"])
+	assert.Equal(t, "s3://data-lake/exports", customers.Metadata["uri"])
+	assert.Contains(t, customers.Schema["columns"], `"column_name":"email"`)
+
+	assert.Equal(t, bucketMRN, lineages[0].Source)
+	assert.Equal(t, *events.MRN, lineages[0].Target)
+	assert.Equal(t, "CONTAINS", lineages[0].Type)
+}
+
+func TestDiscover_MaxObjects(t *testing.T) {
+	store := &memStore{objects: map[string][]byte{
+		"a/1.csv": []byte("x\n1\n"),
+		"b/1.csv": []byte("x\n1\n"),
+		"c/1.csv": []byte("x\n1\n"),
+	}}
+
+	assets, _, err := Discover(context.Background(), store, Target{Provider: "GCS", Bucket: "b", Scheme: "gs"},
+		DatasetConfig{DiscoverDatasets: true, MaxObjects: 2})
+	require.NoError(t, err)
+	assert.Len(t, assets, 2)
+	assert.Zero(t, store.reads, "schemas are not sampled when sample_schemas is off")
+	assert.Nil(t, assets[0].Schema)
+}
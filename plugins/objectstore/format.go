@@ -0,0 +1,57 @@
+package objectstore
+
+import "strings"
+
+// compressionExtensions map compression extensions to codec names.
+var compressionExtensions = map[string]string{
+	"gz":     "gzip",
+	"gzip":   "gzip",
+	"snappy": "snappy",
+	"zst":    "zstd",
+	"zstd":   "zstd",
+	"bz2":    "bzip2",
+	"lz4":    "lz4",
+}
+
+// formatExtensions map file extensions to formats.
+var formatExtensions = map[string]string{
+	"parquet": "parquet",
+	"pq":      "parquet",
+	"csv":     "csv",
+	"tsv":     "csv",
+	"json":    "json",
+	"jsonl":   "json",
+	"ndjson":  "json",
+	"avro":    "avro",
+	"orc":     "orc",
+}
+
+// DetectFormat infers a file's format and compression from its name, such
+// as ("csv", "gzip") for events.csv.gz. It returns an empty format for
+// files that are not data files.
+func DetectFormat(name string) (format, compression string) {
+	parts := strings.Split(strings.ToLower(name), ".")
+	if len(parts) < 2 {
+		return "", ""
+	}
+
+	ext := parts[len(parts)-1]
+	if codec, ok := compressionExtensions[ext]; ok {
+		if len(parts) < 3 {
+			return "", ""
+		}
+		compression = codec
+		ext = parts[len(parts)-2]
+	}
+
+	// Spark writes compressed Parquet as part-0000.snappy.parquet, where
+	// the codec is internal to the file.
+	format = formatExtensions[ext]
+	if format == "parquet" {
+		compression = ""
+	}
+	if format == "" {
+		return "", ""
+	}
+	return format, compression
+}
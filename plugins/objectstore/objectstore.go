@@ -0,0 +1,261 @@
+// Package objectstore infers logical datasets from the object keys of a
+// bucket, for the S3, GCS and Azure Blob Storage plugins.
+//
+// A dataset is a directory of data files, such as a hive-style partitioned
+// Parquet table:
+//
+//	events/dt=2024-01-01/part-0000.parquet
+//	events/dt=2024-01-02/part-0000.parquet
+//
+// becomes one "events" dataset partitioned by dt. Files are grouped by the
+// directory above their first partition segment, or by their own directory
+// when unpartitioned. The format is inferred from file extensions, and the
+// schema is sampled from the newest file of each dataset.
+package objectstore
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DatasetConfig configures dataset inference. Plugins embed it inline in
+// their config.
+type DatasetConfig struct {
+	DiscoverDatasets bool     `json:"discover_datasets" description:"Whether to infer datasets from object key prefixes, such as hive-style partitioned Parquet directories" default:"false"`
+	DatasetPrefixes  []string `json:"dataset_prefixes,omitempty" description:"Key prefixes to scan for datasets. Defaults to the whole bucket" show_when:"discover_datasets:true"`
+	MaxObjects       int      `json:"max_objects" description:"Maximum number of objects to list per bucket when inferring datasets" default:"10000" validate:"omitempty,min=1" show_when:"discover_datasets:true"`
+	SampleSchemas    bool     `json:"sample_schemas" description:"Whether to read each dataset's schema from its newest Parquet footer, CSV header or JSON record" default:"true" show_when:"discover_datasets:true"`
+}
+
+// Object is one object in a bucket.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Dataset is a logical dataset inferred from object keys.
+type Dataset struct {
+	// Prefix is the dataset's directory within the bucket, without a
+	// trailing slash, or the object key for a single file at the top of
+	// the bucket. It is empty when the whole bucket is one partitioned
+	// dataset.
+	Prefix string
+	// Format is the most common file format: parquet, csv, json, avro,
+	// orc, or delta for a Delta Lake table.
+	Format string
+	// Compression is the compression codec of the files, when the files
+	// carry one as an extra extension, such as .csv.gz.
+	Compression string
+	// PartitionKeys are the partition columns, in key order.
+	PartitionKeys  []string
+	PartitionCount int
+	FileCount      int
+	SizeBytes      int64
+	LastModified   time.Time
+	// Sample is the newest file of the dataset's format, which schemas
+	// are read from.
+	Sample Object
+
+	partitions   map[string]bool
+	formats      map[string]int
+	samples      map[string]Object
+	compressions map[string]string
+}
+
+var (
+	hivePartition = regexp.MustCompile(`^([^=]+)=(.*)$`)
+	yearSegment   = regexp.MustCompile(`^\d{4}$`)
+	dateSegment   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	numberSegment = regexp.MustCompile(`^\d{1,2}$`)
+)
+
+// positionalKeys name the numeric partition segments that follow a year,
+// such as 2024/01/31/23.
+var positionalKeys = []string{"year", "month", "day", "hour"}
+
+// partitionKeys returns the partition columns of the leading partition
+// segments of dirs. It returns nil when dirs does not start with one.
+func partitionKeys(dirs []string) []string {
+	var keys []string
+	for i, seg := range dirs {
+		if m := hivePartition.FindStringSubmatch(seg); m != nil {
+			keys = append(keys, m[1])
+			continue
+		}
+		if i == 0 && dateSegment.MatchString(seg) {
+			return []string{"date"}
+		}
+		if i == 0 && yearSegment.MatchString(seg) {
+			keys = append(keys, positionalKeys[0])
+			for j := 1; j < len(dirs) && j < len(positionalKeys) && numberSegment.MatchString(dirs[j]); j++ {
+				keys = append(keys, positionalKeys[j])
+			}
+			return keys
+		}
+		break
+	}
+	return keys
+}
+
+// isPartition reports whether seg is a hive-style or date-shaped
+// partition segment.
+func isPartition(seg string) bool {
+	return hivePartition.MatchString(seg) || yearSegment.MatchString(seg) || dateSegment.MatchString(seg)
+}
+
+// hidden reports whether a path segment is hidden or a marker, such as
+// .DS_Store, _SUCCESS or _temporary.
+func hidden(seg string) bool {
+	return strings.HasPrefix(seg, ".") || strings.HasPrefix(seg, "_")
+}
+
+// InferDatasets groups objects into datasets. Objects that are not data
+// files, or that are hidden, are ignored.
+func InferDatasets(objects []Object) []*Dataset {
+	deltaRoots := make(map[string]bool)
+	for _, obj := range objects {
+		if i := strings.Index("/"+obj.Key, "/_delta_log/"); i >= 0 {
+			deltaRoots[strings.TrimSuffix(obj.Key[:i], "/")] = true
+		}
+	}
+
+	datasets := make(map[string]*Dataset)
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		segments := strings.Split(obj.Key, "/")
+		dirs, file := segments[:len(segments)-1], segments[len(segments)-1]
+
+		if root, ok := deltaRoot(dirs, deltaRoots); ok {
+			if hidden(file) || containsHidden(dirs) {
+				continue
+			}
+			ds := dataset(datasets, root)
+			ds.add(obj, "delta", "", dirs[len(strings.Split(root, "/")):])
+			continue
+		}
+
+		if hidden(file) || containsHidden(dirs) {
+			continue
+		}
+		format, compression := DetectFormat(file)
+		if format == "" {
+			continue
+		}
+
+		p := len(dirs)
+		for i, seg := range dirs {
+			if isPartition(seg) {
+				p = i
+				break
+			}
+		}
+
+		prefix := strings.Join(dirs[:p], "/")
+		if prefix == "" && p == len(dirs) {
+			// A loose file at the top of the bucket is its own dataset.
+			prefix = obj.Key
+		}
+		ds := dataset(datasets, prefix)
+		ds.add(obj, format, compression, dirs[p:])
+	}
+
+	result := make([]*Dataset, 0, len(datasets))
+	for _, ds := range datasets {
+		ds.finish()
+		result = append(result, ds)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Prefix < result[j].Prefix })
+	return result
+}
+
+// deltaRoot returns the Delta table root dirs lie under, if any.
+func deltaRoot(dirs []string, roots map[string]bool) (string, bool) {
+	if len(roots) == 0 {
+		return "", false
+	}
+	for i := len(dirs); i >= 1; i-- {
+		root := strings.Join(dirs[:i], "/")
+		if roots[root] {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func containsHidden(dirs []string) bool {
+	for _, seg := range dirs {
+		if hidden(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+func dataset(datasets map[string]*Dataset, prefix string) *Dataset {
+	ds, ok := datasets[prefix]
+	if !ok {
+		ds = &Dataset{
+			Prefix:       prefix,
+			partitions:   make(map[string]bool),
+			formats:      make(map[string]int),
+			samples:      make(map[string]Object),
+			compressions: make(map[string]string),
+		}
+		datasets[prefix] = ds
+	}
+	return ds
+}
+
+// add records a data file whose directories below the dataset prefix are
+// partitionDirs.
+func (ds *Dataset) add(obj Object, format, compression string, partitionDirs []string) {
+	ds.FileCount++
+	ds.SizeBytes += obj.Size
+	if obj.LastModified.After(ds.LastModified) {
+		ds.LastModified = obj.LastModified
+	}
+	ds.formats[format]++
+
+	if keys := partitionKeys(partitionDirs); len(keys) > 0 {
+		if len(keys) > len(ds.PartitionKeys) {
+			ds.PartitionKeys = keys
+		}
+		ds.partitions[path.Join(partitionDirs[:len(keys)]...)] = true
+	}
+
+	if sample, ok := ds.samples[format]; !ok || obj.LastModified.After(sample.LastModified) {
+		ds.samples[format] = obj
+		ds.compressions[format] = compression
+	}
+}
+
+// finish settles the dataset's format on its most common one.
+func (ds *Dataset) finish() {
+	for format, n := range ds.formats {
+		if n > ds.formats[ds.Format] || (n == ds.formats[ds.Format] && format < ds.Format) {
+			ds.Format = format
+		}
+	}
+	ds.Compression = ds.compressions[ds.Format]
+	ds.Sample = ds.samples[ds.Format]
+	ds.PartitionCount = len(ds.partitions)
+}
+
+// Name returns the dataset's name: the last segment of its prefix without
+// extension, or bucket when the dataset is the whole bucket.
+func (ds *Dataset) Name(bucket string) string {
+	if ds.Prefix == "" {
+		return bucket
+	}
+	name := path.Base(ds.Prefix)
+	if format, _ := DetectFormat(name); format != "" {
+		name = name[:strings.Index(name, ".")]
+	}
+	return name
+}
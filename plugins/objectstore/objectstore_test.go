@@ -0,0 +1,118 @@
+package objectstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		compression string
+	}{
+		{"part-0000.parquet", "parquet", ""},
+		{"part-0000.snappy.parquet", "parquet", ""},
+		{"events.CSV", "csv", ""},
+		{"events.csv.gz", "csv", "gzip"},
+		{"events.tsv", "csv", ""},
+		{"events.jsonl.zst", "json", "zstd"},
+		{"events.ndjson", "json", ""},
+		{"events.avro", "avro", ""},
+		{"README.md", "", ""},
+		{"archive.gz", "", ""},
+		{"parquet", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, compression := DetectFormat(tt.name)
+			assert.Equal(t, tt.format, format)
+			assert.Equal(t, tt.compression, compression)
+		})
+	}
+}
+
+func TestInferDatasets(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	objects := []Object{
+		{Key: "raw/events/dt=2024-01-01/part-0000.parquet", Size: 100, LastModified: day},
+		{Key: "raw/events/dt=2024-01-01/part-0001.parquet", Size: 100, LastModified: day},
+		{Key: "raw/events/dt=2024-01-02/part-0000.parquet", Size: 100, LastModified: day.AddDate(0, 0, 1)},
+		{Key: "raw/events/dt=2024-01-02/_SUCCESS", Size: 0, LastModified: day.AddDate(0, 0, 1)},
+		{Key: "raw/events/_temporary/0/part-0002.parquet", Size: 100, LastModified: day.AddDate(0, 0, 2)},
+		{Key: "logs/2024/01/31/app.jsonl.gz", Size: 10, LastModified: day},
+		{Key: "logs/2024/02/01/app.jsonl.gz", Size: 10, LastModified: day},
+		{Key: "exports/customers.csv", Size: 50, LastModified: day},
+		{Key: "exports/orders.csv", Size: 50, LastModified: day},
+		{Key: "lake/orders/_delta_log/00000000000000000000.json", Size: 5, LastModified: day},
+		{Key: "lake/orders/region=eu/part-0000.snappy.parquet", Size: 100, LastModified: day},
+		{Key: "lake/orders/region=us/part-0000.snappy.parquet", Size: 100, LastModified: day},
+		{Key: "manifest.json", Size: 5, LastModified: day},
+		{Key: "docs/README.md", Size: 5, LastModified: day},
+		{Key: "empty/", Size: 0, LastModified: day},
+	}
+
+	datasets := InferDatasets(objects)
+	byPrefix := make(map[string]*Dataset)
+	for _, ds := range datasets {
+		byPrefix[ds.Prefix] = ds
+	}
+	require.Len(t, datasets, 5)
+
+	events := byPrefix["raw/events"]
+	require.NotNil(t, events)
+	assert.Equal(t, "parquet", events.Format)
+	assert.Equal(t, []string{"dt"}, events.PartitionKeys)
+	assert.Equal(t, 2, events.PartitionCount)
+	assert.Equal(t, 3, events.FileCount)
+	assert.Equal(t, int64(300), events.SizeBytes)
+	assert.Equal(t, "raw/events/dt=2024-01-02/part-0000.parquet", events.Sample.Key)
+	assert.Equal(t, "events", events.Name("data"))
+
+	logs := byPrefix["logs"]
+	require.NotNil(t, logs)
+	assert.Equal(t, "json", logs.Format)
+	assert.Equal(t, "gzip", logs.Compression)
+	assert.Equal(t, []string{"year", "month", "day"}, logs.PartitionKeys)
+	assert.Equal(t, 2, logs.PartitionCount)
+
+	exports := byPrefix["exports"]
+	require.NotNil(t, exports)
+	assert.Equal(t, "csv", exports.Format)
+	assert.Equal(t, 2, exports.FileCount)
+	assert.Empty(t, exports.PartitionKeys)
+
+	orders := byPrefix["lake/orders"]
+	require.NotNil(t, orders)
+	assert.Equal(t, "delta", orders.Format)
+	assert.Equal(t, []string{"region"}, orders.PartitionKeys)
+	assert.Equal(t, 2, orders.FileCount)
+
+	manifest := byPrefix["manifest.json"]
+	require.NotNil(t, manifest)
+	assert.Equal(t, "manifest", manifest.Name("data"))
+}
+
+func TestInferDatasets_PartitionedBucketRoot(t *testing.T) {
+	datasets := InferDatasets([]Object{
+		{Key: "dt=2024-01-01/part-0000.parquet", Size: 1},
+		{Key: "dt=2024-01-02/part-0000.parquet", Size: 1},
+	})
+	require.Len(t, datasets, 1)
+	assert.Equal(t, "", datasets[0].Prefix)
+	assert.Equal(t, "data", datasets[0].Name("data"))
+	assert.Equal(t, 2, datasets[0].PartitionCount)
+}
+
+func TestInferDatasets_MajorityFormat(t *testing.T) {
+	datasets := InferDatasets([]Object{
+		{Key: "events/a.json", Size: 1},
+		{Key: "events/b.parquet", Size: 1},
+		{Key: "events/c.parquet", Size: 1},
+	})
+	require.Len(t, datasets, 1)
+	assert.Equal(t, "parquet", datasets[0].Format)
+}
@@ -0,0 +1,639 @@
+package objectstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Parquet files end with the Thrift-encoded FileMetaData, its length as a
+// little-endian uint32, and the PAR1 magic. The schema is read from the
+// footer alone, so only the tail of each sampled file is downloaded.
+
+var parquetMagic = []byte("PAR1")
+
+// maxParquetFooter bounds the footer size read from a file. Footers of
+// files with many row groups can be large, but not this large.
+const maxParquetFooter = 16 << 20
+
+// parquetFooter is the part of a Parquet FileMetaData Marmot reads.
+type parquetFooter struct {
+	Columns   []Column
+	NumRows   int64
+	CreatedBy string
+}
+
+// footerLength returns the FileMetaData length from the last 8 bytes of a
+// Parquet file.
+func footerLength(tail []byte) (int64, error) {
+	if len(tail) < 8 || !bytes.Equal(tail[len(tail)-4:], parquetMagic) {
+		return 0, errors.New("not a parquet file")
+	}
+	n := int64(binary.LittleEndian.Uint32(tail[len(tail)-8:]))
+	if n > maxParquetFooter {
+		return 0, fmt.Errorf("parquet footer of %d bytes is too large", n)
+	}
+	return n, nil
+}
+
+// schemaElement is a Parquet SchemaElement.
+type schemaElement struct {
+	physicalType  int32
+	typeLength    int32
+	repetition    int32
+	name          string
+	numChildren   int32
+	convertedType int32
+	scale         int32
+	precision     int32
+	logicalType   int16
+	bitWidth      int8
+	signed        bool
+
+	hasPhysicalType  bool
+	hasRepetition    bool
+	hasConvertedType bool
+}
+
+// Parquet physical types.
+const (
+	typeBoolean = iota
+	typeInt32
+	typeInt64
+	typeInt96
+	typeFloat
+	typeDouble
+	typeByteArray
+	typeFixedLenByteArray
+)
+
+// Parquet converted types.
+const (
+	convertedUTF8            = 0
+	convertedMap             = 1
+	convertedMapKeyValue     = 2
+	convertedList            = 3
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimeMillis      = 7
+	convertedTimeMicros      = 8
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint16          = 12
+	convertedUint32          = 13
+	convertedUint64          = 14
+	convertedInt8            = 15
+	convertedInt16           = 16
+	convertedInt32           = 17
+	convertedInt64           = 18
+	convertedJSON            = 19
+	convertedBSON            = 20
+	convertedInterval        = 21
+)
+
+// Parquet LogicalType union members.
+const (
+	logicalString    = 1
+	logicalMap       = 2
+	logicalList      = 3
+	logicalEnum      = 4
+	logicalDecimal   = 5
+	logicalDate      = 6
+	logicalTime      = 7
+	logicalTimestamp = 8
+	logicalInteger   = 10
+	logicalJSON      = 12
+	logicalBSON      = 13
+	logicalUUID      = 14
+	logicalFloat16   = 15
+)
+
+// Parquet field repetitions.
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// parseParquetFooter decodes a Thrift compact-encoded FileMetaData.
+func parseParquetFooter(data []byte) (*parquetFooter, error) {
+	r := &compactReader{buf: data}
+	footer := &parquetFooter{}
+	var elements []schemaElement
+
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == ctList:
+			elemType, n, err := r.readListHeader()
+			if err != nil {
+				return err
+			}
+			if elemType != ctStruct {
+				return fmt.Errorf("unexpected schema element type %d", elemType)
+			}
+			for i := 0; i < n; i++ {
+				el, err := r.readSchemaElement()
+				if err != nil {
+					return err
+				}
+				elements = append(elements, el)
+			}
+			return nil
+		case id == 3 && typ == ctI64:
+			v, err := r.readVarint()
+			footer.NumRows = v
+			return err
+		case id == 6 && typ == ctBinary:
+			v, err := r.readBinary()
+			footer.CreatedBy = string(v)
+			return err
+		default:
+			return r.skip(typ)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decoding parquet footer: %w", err)
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("parquet footer has no schema")
+	}
+
+	// The first element is the root; its direct children are the top-level
+	// columns. Nested groups are described by the elements that follow
+	// them.
+	i := 1
+	for c := int32(0); c < elements[0].numChildren && i < len(elements); c++ {
+		var node *parquetNode
+		node, i = parquetSubtree(elements, i, 0)
+		footer.Columns = append(footer.Columns, node.column())
+	}
+	return footer, nil
+}
+
+// parquetNode is a schema element and its children.
+type parquetNode struct {
+	el       schemaElement
+	children []*parquetNode
+}
+
+// parquetSubtree returns the subtree rooted at elements[i] and the index of
+// the element after it. Groups nested deeper than maxNesting are kept
+// without their children.
+func parquetSubtree(elements []schemaElement, i, depth int) (*parquetNode, int) {
+	node := &parquetNode{el: elements[i]}
+	if depth >= maxNesting {
+		return node, skipSubtree(elements, i)
+	}
+	children := node.el.numChildren
+	i++
+	for c := int32(0); c < children && i < len(elements); c++ {
+		var child *parquetNode
+		child, i = parquetSubtree(elements, i, depth+1)
+		node.children = append(node.children, child)
+	}
+	return node, i
+}
+
+// skipSubtree returns the index of the element after the subtree rooted
+// at elements[i].
+func skipSubtree(elements []schemaElement, i int) int {
+	children := elements[i].numChildren
+	i++
+	for c := int32(0); c < children && i < len(elements); c++ {
+		i = skipSubtree(elements, i)
+	}
+	return i
+}
+
+// column returns the node as a column. Repeated fields outside a LIST are
+// the legacy encoding of a list.
+func (n *parquetNode) column() Column {
+	dataType, fields := n.valueType()
+	if n.el.hasRepetition && n.el.repetition == repetitionRepeated {
+		dataType = "ARRAY<" + dataType + ">"
+	}
+	return Column{
+		Name:     n.el.name,
+		DataType: dataType,
+		Nullable: !n.el.hasRepetition || n.el.repetition != repetitionRequired,
+		Fields:   fields,
+	}
+}
+
+// valueType returns the node's type, ignoring its repetition, and the
+// fields of the struct it holds, if any: its own for a struct, its
+// element's for a list and its value's for a map.
+func (n *parquetNode) valueType() (string, []Column) {
+	if len(n.children) == 0 {
+		return parquetType(n.el), nil
+	}
+
+	switch parquetType(n.el) {
+	case "LIST":
+		elemType, fields := n.listElement().valueType()
+		return "ARRAY<" + elemType + ">", fields
+	case "MAP":
+		keyValue := n.children[0]
+		if len(keyValue.children) < 2 {
+			return "MAP", nil
+		}
+		keyType, _ := keyValue.children[0].valueType()
+		valueType, fields := keyValue.children[1].valueType()
+		return "MAP<" + keyType + ", " + valueType + ">", fields
+	default:
+		fields := make([]Column, len(n.children))
+		for i, child := range n.children {
+			fields[i] = child.column()
+		}
+		return "STRUCT", fields
+	}
+}
+
+// listElement returns the element of a LIST group. Lists are normally a
+// repeated group holding a single element, but older writers repeat the
+// element itself; see the backward-compatibility rules of the Parquet
+// format specification.
+func (n *parquetNode) listElement() *parquetNode {
+	repeated := n.children[0]
+	if len(repeated.children) == 1 && repeated.el.name != "array" && repeated.el.name != n.el.name+"_tuple" {
+		return repeated.children[0]
+	}
+	return repeated
+}
+
+// parquetType returns a SQL-style type name for a schema element.
+func parquetType(el schemaElement) string {
+	if el.numChildren > 0 || !el.hasPhysicalType {
+		switch {
+		case el.logicalType == logicalList || (el.hasConvertedType && el.convertedType == convertedList):
+			return "LIST"
+		case el.logicalType == logicalMap || (el.hasConvertedType && (el.convertedType == convertedMap || el.convertedType == convertedMapKeyValue)):
+			return "MAP"
+		default:
+			return "STRUCT"
+		}
+	}
+
+	switch el.logicalType {
+	case logicalString, logicalEnum:
+		return "STRING"
+	case logicalJSON:
+		return "JSON"
+	case logicalBSON:
+		return "BSON"
+	case logicalUUID:
+		return "UUID"
+	case logicalDate:
+		return "DATE"
+	case logicalTime:
+		return "TIME"
+	case logicalTimestamp:
+		return "TIMESTAMP"
+	case logicalFloat16:
+		return "FLOAT16"
+	case logicalDecimal:
+		return fmt.Sprintf("DECIMAL(%d,%d)", el.precision, el.scale)
+	case logicalInteger:
+		if el.bitWidth > 0 {
+			if el.signed {
+				return fmt.Sprintf("INT%d", el.bitWidth)
+			}
+			return fmt.Sprintf("UINT%d", el.bitWidth)
+		}
+	}
+
+	if el.hasConvertedType {
+		switch el.convertedType {
+		case convertedUTF8, convertedEnum:
+			return "STRING"
+		case convertedJSON:
+			return "JSON"
+		case convertedBSON:
+			return "BSON"
+		case convertedDecimal:
+			return fmt.Sprintf("DECIMAL(%d,%d)", el.precision, el.scale)
+		case convertedDate:
+			return "DATE"
+		case convertedTimeMillis, convertedTimeMicros:
+			return "TIME"
+		case convertedTimestampMillis, convertedTimestampMicros:
+			return "TIMESTAMP"
+		case convertedUint8:
+			return "UINT8"
+		case convertedUint16:
+			return "UINT16"
+		case convertedUint32:
+			return "UINT32"
+		case convertedUint64:
+			return "UINT64"
+		case convertedInt8:
+			return "INT8"
+		case convertedInt16:
+			return "INT16"
+		case convertedInt32:
+			return "INT32"
+		case convertedInt64:
+			return "INT64"
+		case convertedInterval:
+			return "INTERVAL"
+		}
+	}
+
+	switch el.physicalType {
+	case typeBoolean:
+		return "BOOLEAN"
+	case typeInt32:
+		return "INT32"
+	case typeInt64:
+		return "INT64"
+	case typeInt96:
+		// INT96 is the legacy Impala and Spark timestamp encoding.
+		return "TIMESTAMP"
+	case typeFloat:
+		return "FLOAT"
+	case typeDouble:
+		return "DOUBLE"
+	case typeByteArray:
+		return "BINARY"
+	case typeFixedLenByteArray:
+		return fmt.Sprintf("FIXED_LEN_BYTE_ARRAY(%d)", el.typeLength)
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (r *compactReader) readSchemaElement() (schemaElement, error) {
+	var el schemaElement
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == ctI32:
+			el.physicalType, err = r.readI32()
+			el.hasPhysicalType = true
+		case id == 2 && typ == ctI32:
+			el.typeLength, err = r.readI32()
+		case id == 3 && typ == ctI32:
+			el.repetition, err = r.readI32()
+			el.hasRepetition = true
+		case id == 4 && typ == ctBinary:
+			var name []byte
+			name, err = r.readBinary()
+			el.name = string(name)
+		case id == 5 && typ == ctI32:
+			el.numChildren, err = r.readI32()
+		case id == 6 && typ == ctI32:
+			el.convertedType, err = r.readI32()
+			el.hasConvertedType = true
+		case id == 7 && typ == ctI32:
+			el.scale, err = r.readI32()
+		case id == 8 && typ == ctI32:
+			el.precision, err = r.readI32()
+		case id == 10 && typ == ctStruct:
+			err = r.readLogicalType(&el)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	return el, err
+}
+
+// readLogicalType reads the LogicalType union, keeping the member set and
+// the parameters of decimals and integers.
+func (r *compactReader) readLogicalType(el *schemaElement) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		if typ != ctStruct {
+			return r.skip(typ)
+		}
+		el.logicalType = id
+		switch id {
+		case logicalDecimal:
+			return r.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch {
+				case id == 1 && typ == ctI32:
+					el.scale, err = r.readI32()
+				case id == 2 && typ == ctI32:
+					el.precision, err = r.readI32()
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		case logicalInteger:
+			return r.readStruct(func(id int16, typ byte) error {
+				switch {
+				case id == 1 && typ == ctByte:
+					b, err := r.readByte()
+					el.bitWidth = int8(b)
+					return err
+				case id == 2 && (typ == ctBoolTrue || typ == ctBoolFalse):
+					el.signed = typ == ctBoolTrue
+					return nil
+				default:
+					return r.skip(typ)
+				}
+			})
+		default:
+			return r.skip(ctStruct)
+		}
+	})
+}
+
+// Thrift compact protocol types.
+const (
+	ctStop      = 0
+	ctBoolTrue  = 1
+	ctBoolFalse = 2
+	ctByte      = 3
+	ctI16       = 4
+	ctI32       = 5
+	ctI64       = 6
+	ctDouble    = 7
+	ctBinary    = 8
+	ctList      = 9
+	ctSet       = 10
+	ctMap       = 11
+	ctStruct    = 12
+)
+
+// maxNesting bounds struct nesting, so that corrupt footers cannot recurse
+// without limit.
+const maxNesting = 64
+
+var errTruncated = errors.New("truncated thrift data")
+
+// compactReader decodes the Thrift compact protocol.
+type compactReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (r *compactReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *compactReader) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+// readVarint reads a zigzag-encoded integer.
+func (r *compactReader) readVarint() (int64, error) {
+	v, err := r.readUvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *compactReader) readI32() (int32, error) {
+	v, err := r.readVarint()
+	return int32(v), err
+}
+
+func (r *compactReader) readBinary() ([]byte, error) {
+	n, err := r.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *compactReader) readListHeader() (elemType byte, size int, err error) {
+	b, err := r.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	size = int(b >> 4)
+	if size == 15 {
+		n, err := r.readUvarint()
+		if err != nil {
+			return 0, 0, err
+		}
+		if n > uint64(len(r.buf)) {
+			return 0, 0, errTruncated
+		}
+		size = int(n)
+	}
+	return b & 0x0f, size, nil
+}
+
+// readStruct reads the fields of a struct, calling fn with each field's
+// ID and type. fn must consume the field's value.
+func (r *compactReader) readStruct(fn func(id int16, typ byte) error) error {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > maxNesting {
+		return errors.New("thrift data nested too deeply")
+	}
+
+	var lastID int16
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		typ := b & 0x0f
+		if typ == ctStop {
+			return nil
+		}
+		id := lastID + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := r.readVarint()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		lastID = id
+		if err := fn(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip consumes a value of type typ. Booleans in struct fields are
+// encoded in the field type, so they have no value to skip.
+func (r *compactReader) skip(typ byte) error {
+	switch typ {
+	case ctBoolTrue, ctBoolFalse:
+		return nil
+	case ctByte:
+		_, err := r.readByte()
+		return err
+	case ctI16, ctI32, ctI64:
+		_, err := r.readUvarint()
+		return err
+	case ctDouble:
+		if len(r.buf)-r.pos < 8 {
+			return errTruncated
+		}
+		r.pos += 8
+		return nil
+	case ctBinary:
+		_, err := r.readBinary()
+		return err
+	case ctList, ctSet:
+		elemType, n, err := r.readListHeader()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err := r.skipElement(elemType); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ctMap:
+		n, err := r.readUvarint()
+		if err != nil || n == 0 {
+			return err
+		}
+		if n > uint64(len(r.buf)) {
+			return errTruncated
+		}
+		types, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := r.skipElement(types >> 4); err != nil {
+				return err
+			}
+			if err := r.skipElement(types & 0x0f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ctStruct:
+		return r.readStruct(func(_ int16, typ byte) error { return r.skip(typ) })
+	default:
+		return fmt.Errorf("unknown thrift type %d", typ)
+	}
+}
+
+// skipElement consumes a list, set or map element. Unlike struct fields,
+// boolean elements take a byte each.
+func (r *compactReader) skipElement(typ byte) error {
+	if typ == ctBoolTrue || typ == ctBoolFalse {
+		_, err := r.readByte()
+		return err
+	}
+	return r.skip(typ)
+}
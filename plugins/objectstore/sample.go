@@ -0,0 +1,377 @@
+package objectstore
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sampleBytes is how much of a file is read to sample its schema.
+const sampleBytes = 64 << 10
+
+// maxSampleRecords bounds the JSON records and CSV rows schemas are
+// inferred from.
+const maxSampleRecords = 100
+
+// Column is a sampled column, in the native SQL column format the UI
+// renders. Fields are the columns of a struct, or of the structs in a list
+// or map.
+type Column struct {
+	Name     string   `json:"column_name"`
+	DataType string   `json:"data_type"`
+	Nullable bool     `json:"is_nullable"`
+	Fields   []Column `json:"fields,omitempty"`
+}
+
+// Sample is the schema read from a dataset's sample file.
+type Sample struct {
+	Columns []Column
+	// RowCount is the row count of a Parquet sample file, or zero when
+	// unknown.
+	RowCount int64
+	// CreatedBy is the writer of a Parquet sample file, such as
+	// "parquet-mr version 1.12.3".
+	CreatedBy string
+}
+
+// SampleSchema reads the schema of ds from its sample file. Avro, ORC and
+// Delta datasets are not sampled.
+func SampleSchema(ctx context.Context, store Store, ds *Dataset) (*Sample, error) {
+	switch ds.Format {
+	case "parquet":
+		return sampleParquet(ctx, store, ds.Sample)
+	case "csv", "json":
+		if ds.Compression != "" && ds.Compression != "gzip" {
+			return nil, fmt.Errorf("%s compression is not supported for sampling", ds.Compression)
+		}
+		n := ds.Sample.Size
+		if n > sampleBytes {
+			n = sampleBytes
+		}
+		data, err := store.ReadRange(ctx, ds.Sample.Key, 0, n)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", ds.Sample.Key, err)
+		}
+		if ds.Compression == "gzip" {
+			data = gunzipPrefix(data)
+		}
+		var columns []Column
+		if ds.Format == "csv" {
+			columns, err = csvColumns(data, n < ds.Sample.Size)
+		} else {
+			columns, err = jsonColumns(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sampling %s: %w", ds.Sample.Key, err)
+		}
+		return &Sample{Columns: columns}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func sampleParquet(ctx context.Context, store Store, obj Object) (*Sample, error) {
+	if obj.Size < 12 {
+		return nil, fmt.Errorf("%s is too small to be a parquet file", obj.Key)
+	}
+
+	n := obj.Size
+	if n > sampleBytes {
+		n = sampleBytes
+	}
+	tail, err := store.ReadRange(ctx, obj.Key, obj.Size-n, n)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", obj.Key, err)
+	}
+	length, err := footerLength(tail)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", obj.Key, err)
+	}
+	if length+8 > obj.Size {
+		return nil, fmt.Errorf("reading %s: footer length %d exceeds file size", obj.Key, length)
+	}
+
+	// Footers larger than the sampled tail take a second read.
+	if length+8 > int64(len(tail)) {
+		tail, err = store.ReadRange(ctx, obj.Key, obj.Size-length-8, length+8)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", obj.Key, err)
+		}
+		if int64(len(tail)) < length+8 {
+			return nil, fmt.Errorf("reading %s: short read", obj.Key)
+		}
+	}
+
+	footer, err := parseParquetFooter(tail[int64(len(tail))-length-8 : len(tail)-8])
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", obj.Key, err)
+	}
+	return &Sample{
+		Columns:   footer.Columns,
+		RowCount:  footer.NumRows,
+		CreatedBy: footer.CreatedBy,
+	}, nil
+}
+
+// gunzipPrefix decompresses as much of a truncated gzip stream as it can.
+func gunzipPrefix(data []byte) []byte {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	out, _ := io.ReadAll(io.LimitReader(zr, sampleBytes))
+	return out
+}
+
+// csvColumns infers columns from a CSV header and the rows that follow
+// it. When truncated, the last line is partial and is dropped.
+func csvColumns(data []byte, truncated bool) ([]Column, error) {
+	if truncated {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = detectDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	types := make([]valueType, len(header))
+	nullable := make([]bool, len(header))
+	for i := 0; i < maxSampleRecords; i++ {
+		// A malformed row ends the sample rather than failing it.
+		row, err := r.Read()
+		if err != nil {
+			break
+		}
+		for j := range header {
+			if j >= len(row) || row[j] == "" {
+				nullable[j] = true
+				continue
+			}
+			types[j] = types[j].merge(inferString(row[j]))
+		}
+	}
+
+	columns := make([]Column, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column%d", i)
+		}
+		columns[i] = Column{Name: name, DataType: types[i].sqlType(), Nullable: nullable[i] || types[i] == valueUnknown}
+	}
+	return columns, nil
+}
+
+// detectDelimiter picks the most frequent of the common delimiters in the
+// first line.
+func detectDelimiter(data []byte) rune {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+	best, bestCount := ',', 0
+	for _, d := range []rune{',', '\t', ';', '|'} {
+		if n := bytes.Count(line, []byte(string(d))); n > bestCount {
+			best, bestCount = d, n
+		}
+	}
+	return best
+}
+
+// jsonColumns infers columns from JSON lines or a JSON array of objects,
+// merging the keys of the first records.
+func jsonColumns(data []byte) ([]Column, error) {
+	var records []map[string]interface{}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		for dec.More() && len(records) < maxSampleRecords {
+			var rec map[string]interface{}
+			if err := dec.Decode(&rec); err != nil {
+				break
+			}
+			records = append(records, rec)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, sampleBytes), sampleBytes)
+		for scanner.Scan() && len(records) < maxSampleRecords {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var rec map[string]interface{}
+			if err := json.Unmarshal(line, &rec); err != nil {
+				// The last line of a truncated sample is partial.
+				continue
+			}
+			records = append(records, rec)
+		}
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no JSON records found")
+	}
+
+	types := make(map[string]valueType)
+	present := make(map[string]int)
+	var order []string
+	for _, rec := range records {
+		keys := make([]string, 0, len(rec))
+		for k := range rec {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, ok := present[k]; !ok {
+				order = append(order, k)
+			}
+			present[k]++
+			if rec[k] != nil {
+				types[k] = types[k].merge(inferJSON(rec[k]))
+			}
+		}
+	}
+
+	columns := make([]Column, len(order))
+	for i, k := range order {
+		nullable := present[k] < len(records) || types[k] == valueUnknown
+		for _, rec := range records {
+			if v, ok := rec[k]; ok && v == nil {
+				nullable = true
+				break
+			}
+		}
+		columns[i] = Column{Name: k, DataType: types[k].sqlType(), Nullable: nullable}
+	}
+	return columns, nil
+}
+
+// valueType is an inferred column type. Types widen as more values are
+// seen, towards valueString.
+type valueType int
+
+const (
+	valueUnknown valueType = iota
+	valueBool
+	valueBigint
+	valueDouble
+	valueDate
+	valueTimestamp
+	valueString
+	valueStruct
+	valueArray
+)
+
+func (t valueType) merge(other valueType) valueType {
+	switch {
+	case t == valueUnknown:
+		return other
+	case other == valueUnknown || t == other:
+		return t
+	case (t == valueBigint && other == valueDouble) || (t == valueDouble && other == valueBigint):
+		return valueDouble
+	case (t == valueDate && other == valueTimestamp) || (t == valueTimestamp && other == valueDate):
+		return valueTimestamp
+	default:
+		return valueString
+	}
+}
+
+func (t valueType) sqlType() string {
+	switch t {
+	case valueBool:
+		return "BOOLEAN"
+	case valueBigint:
+		return "BIGINT"
+	case valueDouble:
+		return "DOUBLE"
+	case valueDate:
+		return "DATE"
+	case valueTimestamp:
+		return "TIMESTAMP"
+	case valueStruct:
+		return "STRUCT"
+	case valueArray:
+		return "ARRAY"
+	default:
+		return "VARCHAR"
+	}
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+func inferString(s string) valueType {
+	s = strings.TrimSpace(s)
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return valueBigint
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return valueDouble
+	}
+	switch strings.ToLower(s) {
+	case "true", "false":
+		return valueBool
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return valueDate
+	}
+	for _, layout := range timestampLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return valueTimestamp
+		}
+	}
+	return valueString
+}
+
+func inferJSON(v interface{}) valueType {
+	switch val := v.(type) {
+	case bool:
+		return valueBool
+	case float64:
+		if val == float64(int64(val)) {
+			return valueBigint
+		}
+		return valueDouble
+	case string:
+		switch t := inferString(val); t {
+		case valueDate, valueTimestamp:
+			return t
+		default:
+			return valueString
+		}
+	case map[string]interface{}:
+		return valueStruct
+	case []interface{}:
+		return valueArray
+	default:
+		return valueUnknown
+	}
+}
@@ -37,6 +37,13 @@ type JsonSchema struct {
 	UniqueItems     *bool `json:"uniqueItems,omitempty"`
 	MultipleOf      *float64 `json:"multipleOf,omitempty"`
 	Not		*JsonSchema `json:"not,omitempty"`
+	PrefixItems	[]*JsonSchema `json:"prefixItems,omitempty"`
+	If		*JsonSchema `json:"if,omitempty"`
+	Then		*JsonSchema `json:"then,omitempty"`
+	Else		*JsonSchema `json:"else,omitempty"`
+	DependentSchemas map[string]*JsonSchema `json:"dependentSchemas,omitempty"`
+	UnevaluatedProperties any `json:"unevaluatedProperties,omitempty"`
+	Const		any `json:"const,omitempty"`
 	AdditionalProperties any `json:"additionalProperties,omitempty"`
 	MaxProperties   *int64 `json:"maxProperties,omitempty"`
 	MinProperties   *int64 `json:"minProperties,omitempty"`
@@ -167,6 +174,68 @@ func NewJsonSchemaFromOpenAPISchema(schemaProxy *base.SchemaProxy) (*JsonSchema,
 			jSchema.Not = jsonNotSchema
 		}
 
+		// OpenAPI 3.1 schemas are JSON Schema 2020-12, so tuples and
+		// conditional schemas are kept for the schema viewer.
+		if schema.PrefixItems != nil {
+			jSchema.PrefixItems = []*JsonSchema{}
+			for _, prefixItem := range schema.PrefixItems {
+				jsonPrefixItem, err := dfs(prefixItem, depth + 1)
+				if err != nil {
+					return nil, fmt.Errorf("failed to render prefixItems schema at line %d: %w", prefixItem.GetValueNode().Line, err)
+				}
+				jSchema.PrefixItems = append(jSchema.PrefixItems, jsonPrefixItem)
+			}
+		}
+
+		for _, branch := range []struct {
+			name   string
+			proxy  *base.SchemaProxy
+			target **JsonSchema
+		}{
+			{"if", schema.If, &jSchema.If},
+			{"then", schema.Then, &jSchema.Then},
+			{"else", schema.Else, &jSchema.Else},
+		} {
+			if branch.proxy == nil {
+				continue
+			}
+			jsonBranch, err := dfs(branch.proxy, depth + 1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render %s schema at line %d: %w", branch.name, branch.proxy.GetValueNode().Line, err)
+			}
+			*branch.target = jsonBranch
+		}
+
+		if schema.DependentSchemas != nil {
+			jSchema.DependentSchemas = make(map[string]*JsonSchema)
+			for name, dependent := range schema.DependentSchemas.FromOldest() {
+				jsonDependent, err := dfs(dependent, depth + 1)
+				if err != nil {
+					return nil, fmt.Errorf("failed to render dependentSchemas schema at line %d: %w", dependent.GetValueNode().Line, err)
+				}
+				jSchema.DependentSchemas[name] = jsonDependent
+			}
+		}
+
+		if schema.UnevaluatedProperties != nil {
+			if schema.UnevaluatedProperties.IsA() {
+				jsonUnevaluated, err := dfs(schema.UnevaluatedProperties.A, depth + 1)
+				if err != nil {
+					return nil, fmt.Errorf("failed to render unevaluatedProperties schema at line %d: %w", schema.UnevaluatedProperties.A.GetValueNode().Line, err)
+				}
+				jSchema.UnevaluatedProperties = jsonUnevaluated
+			} else {
+				jSchema.UnevaluatedProperties = schema.UnevaluatedProperties.B
+			}
+		}
+
+		if schema.Const != nil {
+			var constValue any
+			if err := schema.Const.Decode(&constValue); err == nil {
+				jSchema.Const = constValue
+			}
+		}
+
 		jSchema.Pattern = schema.Pattern
 		jSchema.MultipleOf = schema.MultipleOf
 		jSchema.Maximum = schema.Maximum
@@ -181,7 +250,7 @@ func NewJsonSchemaFromOpenAPISchema(schemaProxy *base.SchemaProxy) (*JsonSchema,
 		jSchema.MinProperties = schema.MinProperties
 		jSchema.Deprecated = schema.Deprecated
 
-		mock, err := mg.GenerateMock(schema, "")
+		mock, err := generateMock(mg, schema)
 		if err != nil {
 			if err.Error() != "unable to render schema for mock, it's empty" {
 				log.Warn().Err(err).Msg(fmt.Sprintf("Failed to generate mock for schema at line %d", p.GetValueNode().Line))
@@ -205,6 +274,18 @@ func mockGenerator() *renderer.MockGenerator {
 	return mg
 }
 
+// generateMock renders an example for schema. The renderer panics on some
+// 2020-12 keywords, such as dependentSchemas, so a panic is returned as an
+// error and the schema is kept without an example.
+func generateMock(mg *renderer.MockGenerator, schema *base.Schema) (mock []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rendering mock: %v", r)
+		}
+	}()
+	return mg.GenerateMock(schema, "")
+}
+
 func (js JsonSchema) MarshalJSON() ([]byte, error) {
 	type jsonSchemaAlias JsonSchema
 	aux := &struct {
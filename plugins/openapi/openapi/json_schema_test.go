@@ -88,3 +88,44 @@ func getSchemaProxy(schema []byte) *highbase.SchemaProxy {
 	}
 	return highbase.NewSchemaProxy(&lp)
 }
+
+func TestJsonSchemaFromOpenAPI31Schema(t *testing.T) {
+	given := `type: object
+properties:
+  point:
+    type: array
+    prefixItems:
+      - type: number
+      - type: number
+  kind:
+    type: string
+if:
+  properties:
+    kind:
+      const: circle
+then:
+  properties:
+    radius:
+      type: number
+dependentSchemas:
+  kind:
+    properties:
+      label:
+        type: string
+unevaluatedProperties: false`
+
+	proxy := getSchemaProxy([]byte(given))
+	compiled, err := NewJsonSchemaFromOpenAPISchema(proxy)
+	assert.NoError(t, err)
+
+	assert.Len(t, compiled.Properties["point"].PrefixItems, 2)
+	assert.Equal(t, "circle", compiled.If.Properties["kind"].Const)
+	assert.Contains(t, compiled.Then.Properties, "radius")
+	assert.Contains(t, compiled.DependentSchemas["kind"].Properties, "label")
+	assert.Equal(t, false, compiled.UnevaluatedProperties)
+
+	body, err := json.Marshal(compiled)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"prefixItems":[`)
+	assert.Contains(t, string(body), `"unevaluatedProperties":false`)
+}
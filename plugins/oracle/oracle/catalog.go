@@ -0,0 +1,679 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	provider   = "Oracle"
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// constraintTypes maps Oracle's constraint type codes to the names used in
+// asset metadata. NOT NULL checks are left out; they show up on columns.
+var constraintTypes = map[string]string{
+	"P": "primary_key",
+	"U": "unique",
+	"R": "foreign_key",
+	"C": "check",
+}
+
+// catalog is what discovery read from the data dictionary.
+type catalog struct {
+	schemas     []schemaInfo
+	objects     []object
+	columns     map[string][]column     // keyed by schema.name
+	constraints map[string][]constraint // keyed by schema.table
+	synonyms    []synonym
+}
+
+type schemaInfo struct {
+	name             string
+	oracleMaintained bool
+	created          time.Time
+}
+
+// object is a table, view or materialized view.
+type object struct {
+	schema       string
+	name         string
+	objectType   string
+	status       string
+	created      time.Time
+	lastDDLTime  time.Time
+	numRows      sql.NullInt64
+	lastAnalyzed sql.NullTime
+	tablespace   sql.NullString
+	partitioned  sql.NullString
+	temporary    sql.NullString
+	comment      sql.NullString
+}
+
+type column struct {
+	name     string
+	dataType string
+	nullable bool
+	comment  string
+}
+
+type constraint struct {
+	name       string
+	kind       string
+	columns    []string
+	condition  string
+	refSchema  string
+	refTable   string
+	deleteRule string
+	status     string
+}
+
+type synonym struct {
+	schema      string
+	name        string
+	tableSchema string
+	tableName   string
+	dbLink      string
+}
+
+func qualifiedName(schema, name string) string {
+	return schema + "." + name
+}
+
+// readCatalog reads the selected schemas' objects from the data
+// dictionary. A schema that can't be read is logged and skipped so the rest
+// are still cataloged.
+func (s *Source) readCatalog(ctx context.Context) (*catalog, error) {
+	all, err := s.listSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing schemas: %w", err)
+	}
+
+	cat := &catalog{
+		columns:     make(map[string][]column),
+		constraints: make(map[string][]constraint),
+	}
+	for _, schema := range selectSchemas(all, s.config.Schemas, s.config.ExcludeSystemSchemas) {
+		objects, err := s.listObjects(ctx, schema.name)
+		if err != nil {
+			log.Warn().Err(err).Str("schema", schema.name).Msg("Failed to discover tables and views")
+			continue
+		}
+		if len(objects) == 0 {
+			// Most schemas of an instance are application logins that
+			// own nothing.
+			continue
+		}
+		cat.schemas = append(cat.schemas, schema)
+		cat.objects = append(cat.objects, objects...)
+
+		constraints, err := s.listConstraints(ctx, schema.name)
+		if err != nil {
+			log.Warn().Err(err).Str("schema", schema.name).Msg("Failed to discover constraints")
+		}
+		for key, c := range constraints {
+			cat.constraints[key] = c
+		}
+
+		if s.config.IncludeColumns {
+			columns, err := s.listColumns(ctx, schema.name)
+			if err != nil {
+				log.Warn().Err(err).Str("schema", schema.name).Msg("Failed to discover columns")
+			}
+			for key, c := range columns {
+				cat.columns[key] = c
+			}
+		}
+
+		if s.config.IncludeSynonyms {
+			synonyms, err := s.listSynonyms(ctx, schema.name)
+			if err != nil {
+				log.Warn().Err(err).Str("schema", schema.name).Msg("Failed to discover synonyms")
+			}
+			cat.synonyms = append(cat.synonyms, synonyms...)
+		}
+
+		log.Debug().
+			Str("schema", schema.name).
+			Int("objects", len(objects)).
+			Msg("Discovered schema")
+	}
+
+	return cat, nil
+}
+
+// selectSchemas returns the schemas to discover. Listed schemas are matched
+// ignoring case and are discovered even if Oracle maintains them.
+func selectSchemas(all []schemaInfo, include []string, excludeSystem bool) []schemaInfo {
+	var selected []schemaInfo
+	for _, schema := range all {
+		if len(include) > 0 {
+			for _, name := range include {
+				if strings.EqualFold(name, schema.name) {
+					selected = append(selected, schema)
+					break
+				}
+			}
+			continue
+		}
+		if excludeSystem && schema.oracleMaintained {
+			continue
+		}
+		selected = append(selected, schema)
+	}
+	return selected
+}
+
+func (s *Source) listSchemas(ctx context.Context) ([]schemaInfo, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT USERNAME, ORACLE_MAINTAINED, CREATED
+		FROM ALL_USERS
+		ORDER BY USERNAME`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []schemaInfo
+	for rows.Next() {
+		var (
+			schema     schemaInfo
+			maintained string
+		)
+		if err := rows.Scan(&schema.name, &maintained, &schema.created); err != nil {
+			return nil, fmt.Errorf("scanning schema row: %w", err)
+		}
+		schema.oracleMaintained = maintained == "Y"
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
+
+func (s *Source) listObjects(ctx context.Context, schema string) ([]object, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Materialized views are also listed as tables; only the materialized
+	// view is kept. Dropped tables in the recycle bin are skipped.
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT
+			o.OBJECT_NAME,
+			o.OBJECT_TYPE,
+			o.STATUS,
+			o.CREATED,
+			o.LAST_DDL_TIME,
+			t.NUM_ROWS,
+			t.LAST_ANALYZED,
+			t.TABLESPACE_NAME,
+			t.PARTITIONED,
+			t.TEMPORARY,
+			c.COMMENTS
+		FROM ALL_OBJECTS o
+		LEFT JOIN ALL_TABLES t
+			ON t.OWNER = o.OWNER AND t.TABLE_NAME = o.OBJECT_NAME
+		LEFT JOIN ALL_TAB_COMMENTS c
+			ON c.OWNER = o.OWNER AND c.TABLE_NAME = o.OBJECT_NAME
+		WHERE o.OWNER = :1
+			AND o.OBJECT_TYPE IN ('TABLE', 'VIEW', 'MATERIALIZED VIEW')
+			AND o.SECONDARY = 'N'
+			AND o.OBJECT_NAME NOT LIKE 'BIN$%'
+			AND NOT (o.OBJECT_TYPE = 'TABLE' AND EXISTS (
+				SELECT 1 FROM ALL_MVIEWS m
+				WHERE m.OWNER = o.OWNER AND m.MVIEW_NAME = o.OBJECT_NAME))
+		ORDER BY o.OBJECT_NAME`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("querying objects: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []object
+	for rows.Next() {
+		o := object{schema: schema}
+		if err := rows.Scan(
+			&o.name, &o.objectType, &o.status, &o.created, &o.lastDDLTime,
+			&o.numRows, &o.lastAnalyzed, &o.tablespace, &o.partitioned, &o.temporary,
+			&o.comment,
+		); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan object row")
+			continue
+		}
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating object rows: %w", err)
+	}
+	return objects, nil
+}
+
+func (s *Source) listColumns(ctx context.Context, schema string) (map[string][]column, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			c.CHAR_LENGTH,
+			c.DATA_PRECISION,
+			c.DATA_SCALE,
+			c.NULLABLE,
+			cc.COMMENTS
+		FROM ALL_TAB_COLUMNS c
+		LEFT JOIN ALL_COL_COMMENTS cc
+			ON cc.OWNER = c.OWNER AND cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+		WHERE c.OWNER = :1
+		ORDER BY c.TABLE_NAME, c.COLUMN_ID`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("querying columns: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]column)
+	for rows.Next() {
+		var (
+			table, name, dataType, nullable string
+			charLength, precision, scale    sql.NullInt64
+			comment                         sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &dataType, &charLength, &precision, &scale, &nullable, &comment); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan column row")
+			continue
+		}
+		key := qualifiedName(schema, table)
+		result[key] = append(result[key], column{
+			name:     name,
+			dataType: formatType(dataType, charLength, precision, scale),
+			nullable: nullable == "Y",
+			comment:  comment.String,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating column rows: %w", err)
+	}
+	return result, nil
+}
+
+// formatType renders a column type the way it would be declared, such as
+// VARCHAR2(100) or NUMBER(10,2).
+func formatType(dataType string, charLength, precision, scale sql.NullInt64) string {
+	switch dataType {
+	case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR", "RAW":
+		if charLength.Valid && charLength.Int64 > 0 {
+			return fmt.Sprintf("%s(%d)", dataType, charLength.Int64)
+		}
+	case "NUMBER":
+		switch {
+		case precision.Valid && scale.Valid && scale.Int64 != 0:
+			return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+		case precision.Valid:
+			return fmt.Sprintf("NUMBER(%d)", precision.Int64)
+		case scale.Valid && scale.Int64 == 0:
+			return "INTEGER"
+		}
+	}
+	return dataType
+}
+
+func (s *Source) listConstraints(ctx context.Context, schema string) (map[string][]constraint, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT
+			c.TABLE_NAME,
+			c.CONSTRAINT_NAME,
+			c.CONSTRAINT_TYPE,
+			c.SEARCH_CONDITION_VC,
+			c.GENERATED,
+			c.STATUS,
+			c.DELETE_RULE,
+			r.OWNER,
+			r.TABLE_NAME,
+			cc.COLUMN_NAME
+		FROM ALL_CONSTRAINTS c
+		LEFT JOIN ALL_CONS_COLUMNS cc
+			ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME
+		LEFT JOIN ALL_CONSTRAINTS r
+			ON r.OWNER = c.R_OWNER AND r.CONSTRAINT_NAME = c.R_CONSTRAINT_NAME
+		WHERE c.OWNER = :1
+			AND c.CONSTRAINT_TYPE IN ('P', 'U', 'R', 'C')
+			AND c.TABLE_NAME NOT LIKE 'BIN$%'
+		ORDER BY c.TABLE_NAME, c.CONSTRAINT_NAME, cc.POSITION`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("querying constraints: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]constraint)
+	for rows.Next() {
+		var (
+			table, name, kind, generated, status string
+			condition, deleteRule                sql.NullString
+			refSchema, refTable, columnName      sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &kind, &condition, &generated, &status, &deleteRule, &refSchema, &refTable, &columnName); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan constraint row")
+			continue
+		}
+		if kind == "C" && isNotNullCheck(condition.String, generated) {
+			continue
+		}
+
+		key := qualifiedName(schema, table)
+		constraints := result[key]
+		if n := len(constraints); n > 0 && constraints[n-1].name == name {
+			if columnName.Valid {
+				constraints[n-1].columns = append(constraints[n-1].columns, columnName.String)
+			}
+			continue
+		}
+
+		c := constraint{
+			name:      name,
+			kind:      constraintTypes[kind],
+			condition: condition.String,
+			refSchema: refSchema.String,
+			refTable:  refTable.String,
+			status:    strings.ToLower(status),
+		}
+		if kind == "R" {
+			c.deleteRule = deleteRule.String
+		}
+		if columnName.Valid {
+			c.columns = []string{columnName.String}
+		}
+		result[key] = append(constraints, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating constraint rows: %w", err)
+	}
+	return result, nil
+}
+
+// isNotNullCheck reports whether a check constraint is one Oracle created
+// for a NOT NULL column.
+func isNotNullCheck(condition, generated string) bool {
+	return generated == "GENERATED NAME" && strings.HasSuffix(strings.ToUpper(strings.TrimSpace(condition)), " IS NOT NULL")
+}
+
+// listSynonyms returns the schema's synonyms and the public synonyms of its
+// objects.
+func (s *Source) listSynonyms(ctx context.Context, schema string) ([]synonym, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT OWNER, SYNONYM_NAME, TABLE_OWNER, TABLE_NAME, DB_LINK
+		FROM ALL_SYNONYMS
+		WHERE OWNER = :1 OR (OWNER = 'PUBLIC' AND TABLE_OWNER = :2)
+		ORDER BY OWNER, SYNONYM_NAME`, schema, schema)
+	if err != nil {
+		return nil, fmt.Errorf("querying synonyms: %w", err)
+	}
+	defer rows.Close()
+
+	var synonyms []synonym
+	for rows.Next() {
+		var (
+			syn                    synonym
+			tableSchema, tableName sql.NullString
+			dbLink                 sql.NullString
+		)
+		if err := rows.Scan(&syn.schema, &syn.name, &tableSchema, &tableName, &dbLink); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan synonym row")
+			continue
+		}
+		syn.tableSchema, syn.tableName, syn.dbLink = tableSchema.String, tableName.String, dbLink.String
+		synonyms = append(synonyms, syn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating synonym rows: %w", err)
+	}
+	return synonyms, nil
+}
+
+// buildResult turns the catalog into assets and lineage. Schemas contain
+// their tables, views and synonyms, foreign keys link the referencing table
+// to the referenced one, and synonyms reference the object they alias.
+func (s *Source) buildResult(cat *catalog) *pluginsdk.DiscoveryResult {
+	result := &pluginsdk.DiscoveryResult{}
+	schemaMRNs := make(map[string]string, len(cat.schemas))
+
+	for _, schema := range cat.schemas {
+		metadata := s.baseMetadata()
+		metadata["schema"] = schema.name
+		metadata["created"] = schema.created.Format(timeFormat)
+
+		a := s.newAsset("Schema", schema.name, schema.name, metadata)
+		schemaMRNs[schema.name] = *a.MRN
+		result.Assets = append(result.Assets, a)
+	}
+
+	objectMRNs := make(map[string]string, len(cat.objects))
+	for _, o := range cat.objects {
+		key := qualifiedName(o.schema, o.name)
+		metadata := s.baseMetadata()
+		metadata["schema"] = o.schema
+		metadata["table_name"] = o.name
+		metadata["object_type"] = strings.ToLower(strings.ReplaceAll(o.objectType, " ", "_"))
+		metadata["status"] = strings.ToLower(o.status)
+		metadata["created"] = o.created.Format(timeFormat)
+		metadata["last_ddl_time"] = o.lastDDLTime.Format(timeFormat)
+		if o.numRows.Valid && s.config.IncludeRowCounts {
+			metadata["row_count"] = o.numRows.Int64
+		}
+		if o.lastAnalyzed.Valid {
+			metadata["last_analyzed"] = o.lastAnalyzed.Time.Format(timeFormat)
+		}
+		if o.tablespace.Valid {
+			metadata["tablespace"] = o.tablespace.String
+		}
+		if o.partitioned.Valid {
+			metadata["partitioned"] = o.partitioned.String == "YES"
+		}
+		if o.temporary.Valid {
+			metadata["temporary"] = o.temporary.String == "Y"
+		}
+		if o.comment.Valid && o.comment.String != "" {
+			metadata["comment"] = o.comment.String
+		}
+
+		constraints := cat.constraints[key]
+		if len(constraints) > 0 {
+			metadata["constraints"] = constraintMetadata(constraints)
+		}
+
+		assetType, kind := "Table", "table"
+		if o.objectType != "TABLE" {
+			assetType, kind = "View", "view"
+		}
+
+		a := s.newAsset(assetType, key, o.name, metadata)
+		desc := fmt.Sprintf("Oracle %s %s", kind, key)
+		if o.comment.Valid && o.comment.String != "" {
+			desc = o.comment.String
+		}
+		a.Description = &desc
+
+		if columns, ok := cat.columns[key]; ok {
+			a.Schema = map[string]string{"columns": columnsJSON(columns, constraints)}
+		}
+
+		objectMRNs[key] = *a.MRN
+		result.Assets = append(result.Assets, a)
+		if schemaMRN, ok := schemaMRNs[o.schema]; ok {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: schemaMRN, Target: *a.MRN, Type: "CONTAINS"})
+		}
+	}
+
+	if s.config.DiscoverForeignKeys {
+		result.Lineage = append(result.Lineage, foreignKeyLineage(cat.constraints)...)
+	}
+
+	for _, syn := range cat.synonyms {
+		targetKey := qualifiedName(syn.tableSchema, syn.tableName)
+		targetMRN, resolved := objectMRNs[targetKey]
+		if syn.dbLink == "" && !resolved {
+			// Synonyms of packages, sequences and other objects that
+			// weren't cataloged.
+			continue
+		}
+
+		key := qualifiedName(syn.schema, syn.name)
+		metadata := s.baseMetadata()
+		metadata["schema"] = syn.schema
+		metadata["table_name"] = syn.name
+		metadata["object_type"] = "synonym"
+		metadata["target_schema"] = syn.tableSchema
+		metadata["target_name"] = syn.tableName
+		if syn.dbLink != "" {
+			metadata["db_link"] = syn.dbLink
+		}
+
+		a := s.newAsset("Synonym", key, syn.name, metadata)
+		desc := fmt.Sprintf("Oracle synonym %s for %s", key, targetKey)
+		if syn.dbLink != "" {
+			desc += "@" + syn.dbLink
+		}
+		a.Description = &desc
+		result.Assets = append(result.Assets, a)
+
+		if schemaMRN, ok := schemaMRNs[syn.schema]; ok {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: schemaMRN, Target: *a.MRN, Type: "CONTAINS"})
+		}
+		if resolved && syn.dbLink == "" {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: *a.MRN, Target: targetMRN, Type: "REFERENCES"})
+		}
+	}
+
+	return result
+}
+
+func (s *Source) baseMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"host":         s.config.Host,
+		"port":         s.config.Port,
+		"service_name": s.config.ServiceName,
+	}
+}
+
+// newAsset builds an asset whose MRN name is qualified, e.g. SALES.ORDERS,
+// matching the MRNs the Trino plugin gives Oracle tables.
+func (s *Source) newAsset(assetType, qualified, name string, metadata map[string]interface{}) pluginsdk.Asset {
+	mrnValue := mrn.New(assetType, provider, qualified)
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      assetType,
+		Providers: []string{provider},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       provider,
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+func constraintMetadata(constraints []constraint) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(constraints))
+	for _, c := range constraints {
+		m := map[string]interface{}{
+			"name":    c.name,
+			"type":    c.kind,
+			"columns": c.columns,
+			"status":  c.status,
+		}
+		if c.condition != "" {
+			m["condition"] = c.condition
+		}
+		if c.refTable != "" {
+			m["references"] = qualifiedName(c.refSchema, c.refTable)
+		}
+		if c.deleteRule != "" {
+			m["delete_rule"] = c.deleteRule
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func columnsJSON(columns []column, constraints []constraint) string {
+	primary := make(map[string]bool)
+	for _, c := range constraints {
+		if c.kind == "primary_key" {
+			for _, col := range c.columns {
+				primary[col] = true
+			}
+		}
+	}
+
+	result := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		m := map[string]interface{}{
+			"column_name":    col.name,
+			"data_type":      col.dataType,
+			"is_nullable":    col.nullable,
+			"is_primary_key": primary[col.name],
+		}
+		if col.comment != "" {
+			m["comment"] = col.comment
+		}
+		result = append(result, m)
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to marshal columns")
+		return "[]"
+	}
+	return string(jsonBytes)
+}
+
+// foreignKeyLineage links each table to the tables its foreign keys
+// reference, once per pair.
+func foreignKeyLineage(constraints map[string][]constraint) []pluginsdk.LineageEdge {
+	var lineages []pluginsdk.LineageEdge
+	seen := make(map[string]struct{})
+
+	tables := make([]string, 0, len(constraints))
+	for table := range constraints {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		for _, c := range constraints[table] {
+			if c.kind != "foreign_key" || c.refTable == "" {
+				continue
+			}
+			sourceMRN := mrn.New("Table", provider, table)
+			targetMRN := mrn.New("Table", provider, qualifiedName(c.refSchema, c.refTable))
+			if sourceMRN == targetMRN {
+				continue
+			}
+			relationKey := sourceMRN + ":" + targetMRN
+			if _, ok := seen[relationKey]; ok {
+				continue
+			}
+			seen[relationKey] = struct{}{}
+			lineages = append(lineages, pluginsdk.LineageEdge{Source: sourceMRN, Target: targetMRN, Type: "FOREIGN_KEY"})
+		}
+	}
+	return lineages
+}
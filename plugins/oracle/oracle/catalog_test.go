@@ -0,0 +1,122 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findAsset(t *testing.T, result *pluginsdk.DiscoveryResult, id string) *pluginsdk.Asset {
+	t.Helper()
+	for i := range result.Assets {
+		if *result.Assets[i].MRN == id {
+			return &result.Assets[i]
+		}
+	}
+	t.Fatalf("asset %s was not discovered", id)
+	return nil
+}
+
+func TestSelectSchemas(t *testing.T) {
+	all := []schemaInfo{{name: "FINANCE"}, {name: "SALES"}, {name: "SYS", oracleMaintained: true}}
+
+	names := func(schemas []schemaInfo) []string {
+		var result []string
+		for _, s := range schemas {
+			result = append(result, s.name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"FINANCE", "SALES"}, names(selectSchemas(all, nil, true)))
+	assert.Equal(t, []string{"FINANCE", "SALES", "SYS"}, names(selectSchemas(all, nil, false)))
+	assert.Equal(t, []string{"SALES", "SYS"}, names(selectSchemas(all, []string{"sales", "SYS", "missing"}, true)))
+}
+
+func TestFormatType(t *testing.T) {
+	n := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+	null := sql.NullInt64{}
+
+	assert.Equal(t, "VARCHAR2(100)", formatType("VARCHAR2", n(100), null, null))
+	assert.Equal(t, "NUMBER(10,2)", formatType("NUMBER", null, n(10), n(2)))
+	assert.Equal(t, "NUMBER(10)", formatType("NUMBER", null, n(10), n(0)))
+	assert.Equal(t, "INTEGER", formatType("NUMBER", null, null, n(0)))
+	assert.Equal(t, "NUMBER", formatType("NUMBER", null, null, null))
+	assert.Equal(t, "TIMESTAMP(6)", formatType("TIMESTAMP(6)", null, null, n(6)))
+}
+
+func TestIsNotNullCheck(t *testing.T) {
+	assert.True(t, isNotNullCheck(`"ID" IS NOT NULL`, "GENERATED NAME"))
+	assert.False(t, isNotNullCheck(`"ID" IS NOT NULL`, "USER NAME"))
+	assert.False(t, isNotNullCheck(`amount > 0`, "GENERATED NAME"))
+}
+
+func TestBuildResult(t *testing.T) {
+	s := &Source{config: &Config{
+		Host:                "oracle.internal",
+		Port:                1521,
+		ServiceName:         "ORCLPDB1",
+		IncludeRowCounts:    true,
+		DiscoverForeignKeys: true,
+	}}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cat := &catalog{
+		schemas: []schemaInfo{{name: "SALES", created: created}},
+		objects: []object{
+			{schema: "SALES", name: "ORDERS", objectType: "TABLE", status: "VALID", created: created, lastDDLTime: created,
+				numRows: sql.NullInt64{Int64: 42, Valid: true}, comment: sql.NullString{String: "Customer orders", Valid: true}},
+			{schema: "SALES", name: "CUSTOMERS", objectType: "TABLE", status: "VALID", created: created, lastDDLTime: created},
+			{schema: "SALES", name: "ORDER_TOTALS", objectType: "MATERIALIZED VIEW", status: "VALID", created: created, lastDDLTime: created},
+		},
+		columns: map[string][]column{
+			"SALES.ORDERS": {{name: "ID", dataType: "NUMBER(10)"}, {name: "CUSTOMER_ID", dataType: "NUMBER(10)", nullable: true}},
+		},
+		constraints: map[string][]constraint{
+			"SALES.ORDERS": {
+				{name: "ORDERS_PK", kind: "primary_key", columns: []string{"ID"}, status: "enabled"},
+				{name: "ORDERS_CUSTOMER_FK", kind: "foreign_key", columns: []string{"CUSTOMER_ID"}, refSchema: "SALES", refTable: "CUSTOMERS", deleteRule: "CASCADE", status: "enabled"},
+			},
+		},
+		synonyms: []synonym{
+			{schema: "PUBLIC", name: "ORDERS", tableSchema: "SALES", tableName: "ORDERS"},
+			{schema: "SALES", name: "REMOTE_ORDERS", tableSchema: "ARCHIVE", tableName: "ORDERS", dbLink: "ARCHIVE_DB"},
+			{schema: "SALES", name: "NEXT_ID", tableSchema: "SALES", tableName: "ORDER_SEQ"},
+		},
+	}
+
+	result := s.buildResult(cat)
+
+	schema := mrn.New("Schema", "Oracle", "SALES")
+	orders := mrn.New("Table", "Oracle", "SALES.ORDERS")
+	customers := mrn.New("Table", "Oracle", "SALES.CUSTOMERS")
+	publicOrders := mrn.New("Synonym", "Oracle", "PUBLIC.ORDERS")
+
+	table := findAsset(t, result, orders)
+	assert.Equal(t, "ORDERS", *table.Name)
+	assert.Equal(t, "Customer orders", *table.Description)
+	assert.Equal(t, int64(42), table.Metadata["row_count"])
+	assert.Len(t, table.Metadata["constraints"], 2)
+	require.Contains(t, table.Schema, "columns")
+	assert.JSONEq(t, `[
+		{"column_name": "ID", "data_type": "NUMBER(10)", "is_nullable": false, "is_primary_key": true},
+		{"column_name": "CUSTOMER_ID", "data_type": "NUMBER(10)", "is_nullable": true, "is_primary_key": false}
+	]`, table.Schema["columns"])
+
+	view := findAsset(t, result, mrn.New("View", "Oracle", "SALES.ORDER_TOTALS"))
+	assert.Equal(t, "materialized_view", view.Metadata["object_type"])
+
+	findAsset(t, result, publicOrders)
+	remote := findAsset(t, result, mrn.New("Synonym", "Oracle", "SALES.REMOTE_ORDERS"))
+	assert.Equal(t, "ARCHIVE_DB", remote.Metadata["db_link"])
+	assert.Len(t, result.Assets, 6, "the synonym of an uncataloged sequence is skipped")
+
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: schema, Target: orders, Type: "CONTAINS"})
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: orders, Target: customers, Type: "FOREIGN_KEY"})
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: publicOrders, Target: orders, Type: "REFERENCES"})
+}
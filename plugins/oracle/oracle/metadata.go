@@ -0,0 +1,35 @@
+package oracle
+
+// OracleFields represents Oracle-specific metadata fields
+// +marmot:metadata
+type OracleFields struct {
+	Host         string                   `json:"host" metadata:"host" description:"Oracle server hostname"`
+	Port         int                      `json:"port" metadata:"port" description:"Oracle listener port"`
+	ServiceName  string                   `json:"service_name" metadata:"service_name" description:"Database service name"`
+	Schema       string                   `json:"schema" metadata:"schema" description:"Schema (owner) name"`
+	TableName    string                   `json:"table_name" metadata:"table_name" description:"Object name"`
+	ObjectType   string                   `json:"object_type" metadata:"object_type" description:"Object type (table, view, materialized_view, synonym)"`
+	Status       string                   `json:"status" metadata:"status" description:"Object status (valid, invalid)"`
+	Tablespace   string                   `json:"tablespace" metadata:"tablespace" description:"Tablespace holding the table"`
+	Partitioned  bool                     `json:"partitioned" metadata:"partitioned" description:"Whether the table is partitioned"`
+	Temporary    bool                     `json:"temporary" metadata:"temporary" description:"Whether the table is a global temporary table"`
+	RowCount     int64                    `json:"row_count" metadata:"row_count" description:"Row count from optimizer statistics"`
+	LastAnalyzed string                   `json:"last_analyzed" metadata:"last_analyzed" description:"When optimizer statistics were last gathered"`
+	Created      string                   `json:"created" metadata:"created" description:"Creation timestamp"`
+	LastDDLTime  string                   `json:"last_ddl_time" metadata:"last_ddl_time" description:"Last DDL change timestamp"`
+	Comment      string                   `json:"comment" metadata:"comment" description:"Table or view comment"`
+	Constraints  []map[string]interface{} `json:"constraints" metadata:"constraints" description:"Primary key, unique, foreign key and check constraints"`
+	TargetSchema string                   `json:"target_schema" metadata:"target_schema" description:"Schema of the object a synonym refers to"`
+	TargetName   string                   `json:"target_name" metadata:"target_name" description:"Name of the object a synonym refers to"`
+	DBLink       string                   `json:"db_link" metadata:"db_link" description:"Database link of a remote synonym"`
+}
+
+// OracleColumnFields represents Oracle column-specific metadata fields
+// +marmot:metadata
+type OracleColumnFields struct {
+	ColumnName   string `json:"column_name" metadata:"column_name" description:"Column name"`
+	DataType     string `json:"data_type" metadata:"data_type" description:"Data type, e.g. VARCHAR2(100)"`
+	IsNullable   bool   `json:"is_nullable" metadata:"is_nullable" description:"Whether null values are allowed"`
+	IsPrimaryKey bool   `json:"is_primary_key" metadata:"is_primary_key" description:"Whether column is part of primary key"`
+	Comment      string `json:"comment" metadata:"comment" description:"Column comment"`
+}
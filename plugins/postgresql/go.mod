@@ -4,8 +4,10 @@ go 1.26.1
 
 require (
 	github.com/jackc/pgx/v5 v5.9.2
+	github.com/marmotdata/marmot/plugins/testkit v0.0.0-00010101000000-000000000000
 	github.com/marmotdata/plugin-sdk v0.0.0-20260709145136-df61934963a2
 	github.com/rs/zerolog v1.35.1
+	github.com/stretchr/testify v1.11.1
 )
 
 require (
@@ -52,3 +54,5 @@ require (
 	google.golang.org/protobuf v1.36.11 // indirect
 	sigs.k8s.io/yaml v1.6.0 // indirect
 )
+
+replace github.com/marmotdata/marmot/plugins/testkit => ../testkit
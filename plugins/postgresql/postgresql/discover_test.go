@@ -0,0 +1,46 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marmotdata/marmot/plugins/testkit"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	pg := testkit.Postgres(t, testkit.WithInitSQL("testdata/schema.sql"))
+
+	result, err := (&Source{}).Discover(context.Background(), pg.Config())
+	require.NoError(t, err)
+
+	testkit.AssertConsistent(t, result)
+
+	db := mrn.New("Database", "PostgreSQL", "marmot")
+	orders := mrn.New("Table", "PostgreSQL", "orders")
+	customers := mrn.New("Table", "PostgreSQL", "customers")
+
+	testkit.FindAsset(t, result, db)
+	view := testkit.FindAsset(t, result, mrn.New("View", "PostgreSQL", "customer_totals"))
+	assert.Equal(t, "View", view.Type)
+
+	testkit.AssertLineage(t, result, db, orders, "CONTAINS")
+	testkit.AssertLineage(t, result, orders, customers, "FOREIGN_KEY")
+
+	invoices := mrn.New("Table", "PostgreSQL", "invoices")
+	customerTotals := mrn.New("View", "PostgreSQL", "customer_totals")
+	invoicedOrders := mrn.New("View", "PostgreSQL", "invoiced_orders")
+
+	testkit.AssertLineage(t, result, invoices, orders, "FOREIGN_KEY")
+	testkit.AssertLineage(t, result, customers, customerTotals, "VIEW_OF")
+	testkit.AssertLineage(t, result, orders, customerTotals, "VIEW_OF")
+	testkit.AssertLineage(t, result, customerTotals, mrn.New("View", "PostgreSQL", "big_spenders"), "VIEW_OF")
+	testkit.AssertLineage(t, result, orders, invoicedOrders, "VIEW_OF")
+	testkit.AssertLineage(t, result, invoices, invoicedOrders, "VIEW_OF")
+
+	for _, edge := range result.Lineage {
+		assert.False(t, edge.Source == edge.Target, "self-referencing edge on %s", edge.Source)
+	}
+}
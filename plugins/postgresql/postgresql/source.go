@@ -47,6 +47,7 @@ type Config struct {
 	IncludeColumns       bool `json:"include_columns" description:"Whether to include column information in table metadata" default:"true"`
 	EnableMetrics        bool `json:"enable_metrics" description:"Whether to include table metrics" default:"true"`
 	DiscoverForeignKeys  bool `json:"discover_foreign_keys" description:"Whether to discover foreign key relationships" default:"true"`
+	DiscoverViewLineage  bool `json:"discover_view_lineage" description:"Whether to discover lineage from the tables and views each view reads" default:"true"`
 	ExcludeSystemSchemas bool `json:"exclude_system_schemas" description:"Whether to exclude system schemas (pg_*)" default:"true"`
 }
 
@@ -169,6 +170,16 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 				log.Debug().Int("count", len(fkLineages)).Msg("Discovered foreign key relationships")
 			}
 		}
+		if s.config.DiscoverViewLineage {
+			log.Debug().Str("database", dbName).Msg("Starting view dependency discovery")
+			viewLineages, err := s.discoverViewDependencies(dbCtx)
+			if err != nil {
+				log.Warn().Err(err).Str("database", dbName).Msg("Failed to discover view dependencies")
+			} else {
+				lineages = append(lineages, viewLineages...)
+				log.Debug().Int("count", len(viewLineages)).Msg("Discovered view dependencies")
+			}
+		}
 		dbCancel()
 	}
 	return &pluginsdk.DiscoveryResult{
@@ -619,32 +630,36 @@ func (s *Source) getBulkColumnInfo(ctx context.Context, schemaTables []struct {
 	return result, nil
 }
 
+// discoverForeignKeys links each table to the tables its foreign keys
+// reference. It reads pg_constraint rather than information_schema, which
+// only lists constraints on tables the user owns and can't pair up columns
+// of keys that cross schemas.
 func (s *Source) discoverForeignKeys(ctx context.Context, dbName string) ([]pluginsdk.LineageEdge, error) {
 	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Constraints on partitioned tables are also listed on each partition,
+	// which are the tables discovered as assets.
 	query := `
-    SELECT
-        kcu.table_schema AS source_schema,
-        kcu.table_name AS source_table,
-        kcu.column_name AS source_column,
-        ccu.table_schema AS target_schema,
-        ccu.table_name AS target_table,
-        ccu.column_name AS target_column,
-        tc.constraint_name
+    SELECT DISTINCT
+        sn.nspname AS source_schema,
+        s.relname AS source_table,
+        tn.nspname AS target_schema,
+        t.relname AS target_table
     FROM
-        information_schema.table_constraints AS tc
-        JOIN information_schema.key_column_usage AS kcu
-            ON tc.constraint_name = kcu.constraint_name
-            AND tc.table_schema = kcu.table_schema
-        JOIN information_schema.constraint_column_usage AS ccu
-            ON ccu.constraint_name = tc.constraint_name
-            AND ccu.table_schema = tc.table_schema
+        pg_catalog.pg_constraint con
+        JOIN pg_catalog.pg_class s ON s.oid = con.conrelid
+        JOIN pg_catalog.pg_namespace sn ON sn.oid = s.relnamespace
+        JOIN pg_catalog.pg_class t ON t.oid = con.confrelid
+        JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace
     WHERE
-        tc.constraint_type = 'FOREIGN KEY'
-        AND (kcu.table_schema NOT LIKE 'pg\\_%' OR NOT $1)
-        AND kcu.table_schema NOT IN ('information_schema')
-    LIMIT 1000
+        con.contype = 'f'
+        AND s.relkind = 'r'
+        AND t.relkind = 'r'
+        AND (sn.nspname !~ '^pg_' OR NOT $1)
+        AND sn.nspname != 'information_schema'
+    ORDER BY
+        source_schema, source_table, target_schema, target_table
 `
 
 	rows, err := s.pool.Query(queryCtx, query, s.config.ExcludeSystemSchemas)
@@ -657,33 +672,24 @@ func (s *Source) discoverForeignKeys(ctx context.Context, dbName string) ([]plug
 	uniqueRelations := make(map[string]struct{})
 
 	for rows.Next() {
-		var (
-			sourceSchema   string
-			sourceTable    string
-			sourceColumn   string
-			targetSchema   string
-			targetTable    string
-			targetColumn   string
-			constraintName string
-		)
-
-		if err := rows.Scan(
-			&sourceSchema, &sourceTable, &sourceColumn,
-			&targetSchema, &targetTable, &targetColumn,
-			&constraintName,
-		); err != nil {
+		var sourceSchema, sourceTable, targetSchema, targetTable string
+		if err := rows.Scan(&sourceSchema, &sourceTable, &targetSchema, &targetTable); err != nil {
 			log.Warn().Err(err).Msg("Failed to scan foreign key row")
 			continue
 		}
 
 		log.Debug().
-			Str("source", fmt.Sprintf("%s.%s.%s", sourceSchema, sourceTable, sourceColumn)).
-			Str("target", fmt.Sprintf("%s.%s.%s", targetSchema, targetTable, targetColumn)).
-			Str("constraint", constraintName).
+			Str("database", dbName).
+			Str("source", sourceSchema+"."+sourceTable).
+			Str("target", targetSchema+"."+targetTable).
 			Msg("Found foreign key relationship")
 
 		sourceMRN := mrn.New("Table", "PostgreSQL", sourceTable)
 		targetMRN := mrn.New("Table", "PostgreSQL", targetTable)
+		if sourceMRN == targetMRN {
+			// Self-references, such as a parent_id column.
+			continue
+		}
 
 		relationKey := fmt.Sprintf("%s:%s", sourceMRN, targetMRN)
 		if _, exists := uniqueRelations[relationKey]; exists {
@@ -705,6 +711,84 @@ func (s *Source) discoverForeignKeys(ctx context.Context, dbName string) ([]plug
 	return lineages, nil
 }
 
+// discoverViewDependencies links each view and materialized view to the
+// tables and views its definition reads. Postgres records these in
+// pg_depend as dependencies of the view's rewrite rule, so they are exact
+// without parsing SQL or needing query logs.
+func (s *Source) discoverViewDependencies(ctx context.Context) ([]pluginsdk.LineageEdge, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT DISTINCT
+        ref.relname AS source_name,
+        ref.relkind::text AS source_kind,
+        v.relname AS view_name
+    FROM
+        pg_catalog.pg_rewrite rw
+        JOIN pg_catalog.pg_class v ON v.oid = rw.ev_class
+        JOIN pg_catalog.pg_namespace vn ON vn.oid = v.relnamespace
+        JOIN pg_catalog.pg_depend d
+            ON d.classid = 'pg_catalog.pg_rewrite'::regclass
+            AND d.objid = rw.oid
+            AND d.refclassid = 'pg_catalog.pg_class'::regclass
+        JOIN pg_catalog.pg_class ref ON ref.oid = d.refobjid
+        JOIN pg_catalog.pg_namespace rn ON rn.oid = ref.relnamespace
+    WHERE
+        v.relkind IN ('v', 'm')
+        AND ref.relkind IN ('r', 'v', 'm')
+        AND ref.oid != v.oid
+        AND (vn.nspname !~ '^pg_' OR NOT $1)
+        AND vn.nspname != 'information_schema'
+        AND (rn.nspname !~ '^pg_' OR NOT $1)
+        AND rn.nspname != 'information_schema'
+    ORDER BY
+        view_name, source_name
+`
+
+	rows, err := s.pool.Query(queryCtx, query, s.config.ExcludeSystemSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("querying view dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var lineages []pluginsdk.LineageEdge
+	uniqueRelations := make(map[string]struct{})
+
+	for rows.Next() {
+		var sourceName, sourceKind, viewName string
+		if err := rows.Scan(&sourceName, &sourceKind, &viewName); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan view dependency row")
+			continue
+		}
+
+		sourceType := "Table"
+		if sourceKind != "r" {
+			sourceType = "View"
+		}
+		sourceMRN := mrn.New(sourceType, "PostgreSQL", sourceName)
+		viewMRN := mrn.New("View", "PostgreSQL", viewName)
+
+		relationKey := fmt.Sprintf("%s:%s", sourceMRN, viewMRN)
+		if _, exists := uniqueRelations[relationKey]; exists {
+			continue
+		}
+		uniqueRelations[relationKey] = struct{}{}
+
+		lineages = append(lineages, pluginsdk.LineageEdge{
+			Source: sourceMRN,
+			Target: viewMRN,
+			Type:   "VIEW_OF",
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating view dependency rows: %w", err)
+	}
+
+	return lineages, nil
+}
+
 func (s *Source) collectTableStatistics(ctx context.Context, dbName string, assets []pluginsdk.Asset) []pluginsdk.Statistic {
 	var statistics []pluginsdk.Statistic
 
@@ -0,0 +1,32 @@
+CREATE TABLE customers (
+    id    SERIAL PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE orders (
+    id          SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers (id),
+    total       NUMERIC(10, 2) NOT NULL
+);
+
+CREATE VIEW customer_totals AS
+SELECT c.email, SUM(o.total) AS total
+FROM customers c
+JOIN orders o ON o.customer_id = c.id
+GROUP BY c.email;
+
+CREATE SCHEMA billing;
+
+CREATE TABLE billing.invoices (
+    id        SERIAL PRIMARY KEY,
+    order_id  INTEGER NOT NULL REFERENCES public.orders (id),
+    parent_id INTEGER REFERENCES billing.invoices (id)
+);
+
+CREATE VIEW big_spenders AS
+SELECT email FROM customer_totals WHERE total > 1000;
+
+CREATE MATERIALIZED VIEW billing.invoiced_orders AS
+SELECT o.id, o.total
+FROM orders o
+JOIN billing.invoices i ON i.order_id = o.id;
@@ -0,0 +1,63 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/marmotdata/marmot/plugins/objectstore"
+)
+
+// bucketStore implements objectstore.Store for one S3 bucket.
+type bucketStore struct {
+	client *s3.Client
+	bucket string
+	// region is the bucket's region. Requests are signed for it, so
+	// buckets outside the configured region can be read.
+	region string
+}
+
+func (b *bucketStore) options(o *s3.Options) {
+	if b.region != "" {
+		o.Region = b.region
+	}
+}
+
+func (b *bucketStore) List(ctx context.Context, prefix string, fn func(objectstore.Object) error) error {
+	paginator := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx, b.options)
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			err := fn(objectstore.Object{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *bucketStore) ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	output, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	}, b.options)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return io.ReadAll(output.Body)
+}
@@ -18,3 +18,22 @@ type S3Fields struct {
 	RequestPaymentConfig string            `json:"request_payment_config" metadata:"request_payment_config" description:"Request payment configuration"`
 	Tags                 map[string]string `json:"tags" metadata:"tags" description:"AWS resource tags"`
 }
+
+// S3DatasetFields defines metadata fields for datasets inferred from
+// object keys
+// +marmot:metadata
+type S3DatasetFields struct {
+	Bucket         string `json:"bucket" metadata:"bucket" description:"Bucket the dataset is stored in"`
+	Prefix         string `json:"prefix" metadata:"prefix" description:"Key prefix of the dataset within the bucket"`
+	URI            string `json:"uri" metadata:"uri" description:"URI of the dataset"`
+	Format         string `json:"format" metadata:"format" description:"File format (parquet, csv, json, avro, orc, delta)"`
+	Compression    string `json:"compression" metadata:"compression" description:"Compression codec of the files (gzip, zstd, ...)"`
+	PartitionKeys  string `json:"partition_keys" metadata:"partition_keys" description:"Comma-separated partition columns, such as dt"`
+	PartitionCount int    `json:"partition_count" metadata:"partition_count" description:"Number of partitions"`
+	FileCount      int    `json:"file_count" metadata:"file_count" description:"Number of data files"`
+	SizeBytes      int64  `json:"size_bytes" metadata:"size_bytes" description:"Total size of the data files in bytes"`
+	LastModified   string `json:"last_modified" metadata:"last_modified" description:"Last modification timestamp of the newest data file"`
+	SampleFile     string `json:"sample_file" metadata:"sample_file" description:"Key of the file the schema was sampled from"`
+	SampleRowCount int64  `json:"sample_row_count" metadata:"sample_row_count" description:"Row count of the sampled Parquet file"`
+	CreatedBy      string `json:"created_by" metadata:"created_by" description:"Writer of the sampled Parquet file"`
+}
@@ -1,4 +1,5 @@
-// Package s3 discovers S3 buckets from AWS accounts.
+// Package s3 discovers S3 buckets from AWS accounts, and the datasets
+// stored in them.
 package s3
 
 import (
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/marmotdata/marmot/plugins/objectstore"
 	pluginsdk "github.com/marmotdata/plugin-sdk"
 	"github.com/marmotdata/plugin-sdk/mrn"
 	"github.com/rs/zerolog/log"
@@ -19,11 +21,11 @@ func Meta() pluginsdk.Meta {
 	return pluginsdk.Meta{
 		ID:          "s3",
 		Name:        "AWS S3",
-		Description: "Discover S3 buckets from AWS accounts",
+		Description: "Discover S3 buckets and the datasets stored in them from AWS accounts",
 		Icon:        "s3",
 		Category:    "storage",
 		Status:      "experimental",
-		Features:    []string{"Assets"},
+		Features:    []string{"Assets", "Lineage"},
 		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
 	}
 }
@@ -32,6 +34,8 @@ func Meta() pluginsdk.Meta {
 type Config struct {
 	pluginsdk.BaseConfig `json:",inline"`
 	*pluginsdk.AWSConfig `json:",inline"`
+
+	objectstore.DatasetConfig `json:",inline"`
 }
 
 // Example configuration for the plugin
@@ -40,6 +44,9 @@ credentials:
   region: "us-east-1"
   id: "<aws-secret-id>"
   secret: "<aws-secret-key>"
+discover_datasets: true
+dataset_prefixes:
+  - "warehouse/"
 tags:
   - "s3"
 `
@@ -55,6 +62,8 @@ func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, e
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
 
+	pluginsdk.ApplyDefaults(config, rawConfig)
+
 	if err := pluginsdk.ValidateStruct(config); err != nil {
 		return nil, err
 	}
@@ -68,6 +77,7 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
+	pluginsdk.ApplyDefaults(config, pluginConfig)
 	s.config = config
 
 	awsConfig, err := pluginsdk.ExtractAWSConfig(pluginConfig)
@@ -103,6 +113,27 @@ func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig)
 			continue
 		}
 		assets = append(assets, asset)
+
+		if s.config.DiscoverDatasets {
+			region, _ := asset.Metadata["region"].(string)
+			datasets, edges, err := objectstore.Discover(ctx, &bucketStore{
+				client: s.client,
+				bucket: *bucket.Name,
+				region: region,
+			}, objectstore.Target{
+				Provider:  "S3",
+				Bucket:    *bucket.Name,
+				BucketMRN: *asset.MRN,
+				Scheme:    "s3",
+				Tags:      s.config.Tags,
+			}, s.config.DatasetConfig)
+			if err != nil {
+				log.Warn().Err(err).Str("bucket", *bucket.Name).Msg("Failed to discover datasets in bucket")
+				continue
+			}
+			assets = append(assets, datasets...)
+			lineages = append(lineages, edges...)
+		}
 	}
 
 	return &pluginsdk.DiscoveryResult{
@@ -0,0 +1,855 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// provider matches the provider the Trino plugin gives SQL Server
+	// tables, so assets discovered both ways merge.
+	provider   = "SQL Server"
+	timeFormat = "2006-01-02 15:04:05"
+
+	// descriptionProperty is the extended property SSMS and most tools
+	// use for descriptions.
+	descriptionProperty = "MS_Description"
+)
+
+// constraintTypes maps SQL Server's constraint type codes to the names used
+// in asset metadata.
+var constraintTypes = map[string]string{
+	"PK": "primary_key",
+	"UQ": "unique",
+	"F":  "foreign_key",
+	"C":  "check",
+}
+
+// catalog is what discovery read from the database's catalog views.
+type catalog struct {
+	database    databaseInfo
+	schemas     []schemaInfo
+	objects     []object
+	columns     map[string][]column     // keyed by schema.name
+	constraints map[string][]constraint // keyed by schema.table
+	synonyms    []synonym
+	// properties holds extended properties keyed by schema for schemas,
+	// schema.object for objects and schema.object.column for columns.
+	properties map[string]map[string]string
+}
+
+type databaseInfo struct {
+	name               string
+	created            time.Time
+	collation          sql.NullString
+	compatibilityLevel int
+	recoveryModel      sql.NullString
+}
+
+type schemaInfo struct {
+	name  string
+	owner sql.NullString
+}
+
+// object is a table or view.
+type object struct {
+	schema     string
+	name       string
+	objectType string
+	created    time.Time
+	modified   time.Time
+	rowCount   sql.NullInt64
+	temporal   sql.NullString
+}
+
+type column struct {
+	name         string
+	dataType     string
+	nullable     bool
+	isIdentity   bool
+	isComputed   bool
+	defaultValue sql.NullString
+}
+
+type constraint struct {
+	name       string
+	kind       string
+	columns    []string
+	condition  string
+	refSchema  string
+	refTable   string
+	deleteRule string
+	disabled   bool
+}
+
+type synonym struct {
+	schema     string
+	name       string
+	baseObject string
+}
+
+func qualifiedName(schema, name string) string {
+	return schema + "." + name
+}
+
+// readCatalog reads the database's catalog views. Parts other than the
+// tables and views are best effort: if one can't be read it is logged and
+// the rest are still cataloged.
+func (s *Source) readCatalog(ctx context.Context) (*catalog, error) {
+	cat := &catalog{
+		columns:     make(map[string][]column),
+		constraints: make(map[string][]constraint),
+		properties:  make(map[string]map[string]string),
+	}
+
+	var err error
+	if cat.database, err = s.getDatabase(ctx); err != nil {
+		return nil, fmt.Errorf("reading database: %w", err)
+	}
+	schemas, err := s.listSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing schemas: %w", err)
+	}
+	cat.schemas = selectSchemas(schemas, s.config.Schemas)
+
+	selected := make(map[string]bool, len(cat.schemas))
+	for _, schema := range cat.schemas {
+		selected[schema.name] = true
+	}
+
+	objects, err := s.listObjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables and views: %w", err)
+	}
+	for _, o := range objects {
+		if selected[o.schema] {
+			cat.objects = append(cat.objects, o)
+		}
+	}
+
+	if properties, err := s.listExtendedProperties(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to discover extended properties")
+	} else {
+		cat.properties = properties
+	}
+	constraints, err := s.listConstraints(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to discover constraints")
+	}
+	for _, o := range cat.objects {
+		key := qualifiedName(o.schema, o.name)
+		if c, ok := constraints[key]; ok {
+			cat.constraints[key] = c
+		}
+	}
+	if s.config.IncludeColumns {
+		columns, err := s.listColumns(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to discover columns")
+		}
+		for _, o := range cat.objects {
+			key := qualifiedName(o.schema, o.name)
+			if c, ok := columns[key]; ok {
+				cat.columns[key] = c
+			}
+		}
+	}
+	if s.config.IncludeSynonyms {
+		synonyms, err := s.listSynonyms(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to discover synonyms")
+		}
+		for _, syn := range synonyms {
+			if selected[syn.schema] {
+				cat.synonyms = append(cat.synonyms, syn)
+			}
+		}
+	}
+
+	return cat, nil
+}
+
+// selectSchemas returns the schemas to discover. Listed schemas are matched
+// ignoring case, as SQL Server does with the default collations.
+func selectSchemas(all []schemaInfo, include []string) []schemaInfo {
+	if len(include) == 0 {
+		return all
+	}
+	var selected []schemaInfo
+	for _, schema := range all {
+		for _, name := range include {
+			if strings.EqualFold(name, schema.name) {
+				selected = append(selected, schema)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func (s *Source) getDatabase(ctx context.Context) (databaseInfo, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var db databaseInfo
+	err := s.db.QueryRowContext(queryCtx, `
+		SELECT name, create_date, collation_name, compatibility_level, recovery_model_desc
+		FROM sys.databases
+		WHERE database_id = DB_ID()`).Scan(&db.name, &db.created, &db.collation, &db.compatibilityLevel, &db.recoveryModel)
+	return db, err
+}
+
+func (s *Source) listSchemas(ctx context.Context) ([]schemaInfo, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Schemas from 16384 up belong to the fixed database roles.
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT s.name, p.name
+		FROM sys.schemas s
+		LEFT JOIN sys.database_principals p ON p.principal_id = s.principal_id
+		WHERE s.schema_id < 16384
+			AND s.name NOT IN ('sys', 'INFORMATION_SCHEMA', 'guest')
+		ORDER BY s.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []schemaInfo
+	for rows.Next() {
+		var schema schemaInfo
+		if err := rows.Scan(&schema.name, &schema.owner); err != nil {
+			return nil, fmt.Errorf("scanning schema row: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
+
+func (s *Source) listObjects(ctx context.Context) ([]object, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT
+			s.name,
+			o.name,
+			RTRIM(o.type),
+			o.create_date,
+			o.modify_date,
+			(SELECT SUM(p.rows) FROM sys.partitions p
+				WHERE p.object_id = o.object_id AND p.index_id IN (0, 1)),
+			t.temporal_type_desc
+		FROM sys.objects o
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		LEFT JOIN sys.tables t ON t.object_id = o.object_id
+		WHERE o.type IN ('U', 'V') AND o.is_ms_shipped = 0
+		ORDER BY s.name, o.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.schema, &o.name, &o.objectType, &o.created, &o.modified, &o.rowCount, &o.temporal); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan object row")
+			continue
+		}
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating object rows: %w", err)
+	}
+	return objects, nil
+}
+
+func (s *Source) listColumns(ctx context.Context) (map[string][]column, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT
+			s.name,
+			o.name,
+			c.name,
+			ty.name,
+			c.max_length,
+			c.precision,
+			c.scale,
+			c.is_nullable,
+			c.is_identity,
+			c.is_computed,
+			dc.definition
+		FROM sys.columns c
+		JOIN sys.objects o ON o.object_id = c.object_id
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+		LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+		WHERE o.type IN ('U', 'V') AND o.is_ms_shipped = 0
+		ORDER BY s.name, o.name, c.column_id`)
+	if err != nil {
+		return nil, fmt.Errorf("querying columns: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]column)
+	for rows.Next() {
+		var (
+			schema, table, typeName string
+			col                     column
+			maxLength               int
+			precision, scale        int
+		)
+		if err := rows.Scan(
+			&schema, &table, &col.name, &typeName, &maxLength, &precision, &scale,
+			&col.nullable, &col.isIdentity, &col.isComputed, &col.defaultValue,
+		); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan column row")
+			continue
+		}
+		col.dataType = formatType(typeName, maxLength, precision, scale)
+		key := qualifiedName(schema, table)
+		result[key] = append(result[key], col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating column rows: %w", err)
+	}
+	return result, nil
+}
+
+// formatType renders a column type the way it would be declared, such as
+// nvarchar(50), varchar(max) or decimal(10,2).
+func formatType(typeName string, maxLength, precision, scale int) string {
+	switch typeName {
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength == -1 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "nvarchar", "nchar":
+		// Lengths are in bytes; each character takes two.
+		if maxLength == -1 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength/2)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", typeName, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	}
+	return typeName
+}
+
+func (s *Source) listConstraints(ctx context.Context) (map[string][]constraint, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT s.name, t.name, kc.name, RTRIM(kc.type), c.name, ic.key_ordinal,
+			NULL, NULL, NULL, NULL, CAST(0 AS BIT)
+		FROM sys.key_constraints kc
+		JOIN sys.tables t ON t.object_id = kc.parent_object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE t.is_ms_shipped = 0
+		UNION ALL
+		SELECT s.name, t.name, fk.name, 'F', c.name, fkc.constraint_column_id,
+			NULL, rs.name, rt.name, fk.delete_referential_action_desc, fk.is_disabled
+		FROM sys.foreign_keys fk
+		JOIN sys.tables t ON t.object_id = fk.parent_object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+		JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+		JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+		WHERE t.is_ms_shipped = 0
+		UNION ALL
+		SELECT s.name, t.name, cc.name, 'C', c.name, 1,
+			cc.definition, NULL, NULL, NULL, cc.is_disabled
+		FROM sys.check_constraints cc
+		JOIN sys.tables t ON t.object_id = cc.parent_object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		LEFT JOIN sys.columns c ON c.object_id = cc.parent_object_id AND c.column_id = cc.parent_column_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY 1, 2, 3, 6`)
+	if err != nil {
+		return nil, fmt.Errorf("querying constraints: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]constraint)
+	for rows.Next() {
+		var (
+			schema, table, name, kind      string
+			columnName                     sql.NullString
+			ordinal                        int
+			condition, refSchema, refTable sql.NullString
+			deleteRule                     sql.NullString
+			disabled                       bool
+		)
+		if err := rows.Scan(&schema, &table, &name, &kind, &columnName, &ordinal,
+			&condition, &refSchema, &refTable, &deleteRule, &disabled); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan constraint row")
+			continue
+		}
+
+		key := qualifiedName(schema, table)
+		constraints := result[key]
+		if n := len(constraints); n > 0 && constraints[n-1].name == name {
+			if columnName.Valid {
+				constraints[n-1].columns = append(constraints[n-1].columns, columnName.String)
+			}
+			continue
+		}
+
+		c := constraint{
+			name:       name,
+			kind:       constraintTypes[kind],
+			condition:  condition.String,
+			refSchema:  refSchema.String,
+			refTable:   refTable.String,
+			deleteRule: deleteRule.String,
+			disabled:   disabled,
+		}
+		if columnName.Valid {
+			c.columns = []string{columnName.String}
+		}
+		result[key] = append(constraints, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating constraint rows: %w", err)
+	}
+	return result, nil
+}
+
+// listExtendedProperties returns the extended properties of schemas,
+// tables, views and their columns.
+func (s *Source) listExtendedProperties(ctx context.Context) (map[string]map[string]string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT sc.name, NULL, NULL, ep.name, CAST(ep.value AS NVARCHAR(4000))
+		FROM sys.extended_properties ep
+		JOIN sys.schemas sc ON sc.schema_id = ep.major_id
+		WHERE ep.class = 3
+		UNION ALL
+		SELECT s.name, o.name, c.name, ep.name, CAST(ep.value AS NVARCHAR(4000))
+		FROM sys.extended_properties ep
+		JOIN sys.objects o ON o.object_id = ep.major_id
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		LEFT JOIN sys.columns c ON c.object_id = ep.major_id AND c.column_id = ep.minor_id
+		WHERE ep.class = 1 AND o.type IN ('U', 'V') AND o.is_ms_shipped = 0`)
+	if err != nil {
+		return nil, fmt.Errorf("querying extended properties: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]string)
+	for rows.Next() {
+		var (
+			schema, name        string
+			objectName, colName sql.NullString
+			value               sql.NullString
+		)
+		if err := rows.Scan(&schema, &objectName, &colName, &name, &value); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan extended property row")
+			continue
+		}
+		key := schema
+		if objectName.Valid {
+			key = qualifiedName(key, objectName.String)
+		}
+		if colName.Valid {
+			key = qualifiedName(key, colName.String)
+		}
+		if result[key] == nil {
+			result[key] = make(map[string]string)
+		}
+		result[key][name] = value.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating extended property rows: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Source) listSynonyms(ctx context.Context) ([]synonym, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(queryCtx, `
+		SELECT s.name, sy.name, sy.base_object_name
+		FROM sys.synonyms sy
+		JOIN sys.schemas s ON s.schema_id = sy.schema_id
+		ORDER BY s.name, sy.name`)
+	if err != nil {
+		return nil, fmt.Errorf("querying synonyms: %w", err)
+	}
+	defer rows.Close()
+
+	var synonyms []synonym
+	for rows.Next() {
+		var syn synonym
+		if err := rows.Scan(&syn.schema, &syn.name, &syn.baseObject); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan synonym row")
+			continue
+		}
+		synonyms = append(synonyms, syn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating synonym rows: %w", err)
+	}
+	return synonyms, nil
+}
+
+// splitObjectName splits a multi-part name such as [db].[dbo].[orders]
+// into its parts, unquoting bracketed and double-quoted parts.
+func splitObjectName(name string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+		closing rune
+	)
+	runes := []rune(name)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case closing != 0 && r == closing:
+			if i+1 < len(runes) && runes[i+1] == closing {
+				current.WriteRune(r)
+				i++
+				continue
+			}
+			closing = 0
+		case closing != 0:
+			current.WriteRune(r)
+		case r == '[':
+			closing = ']'
+		case r == '"':
+			closing = '"'
+		case r == '.':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(parts, current.String())
+}
+
+// resolveSynonym returns the schema and object a synonym refers to and
+// whether that object is in the current database. Unqualified names are
+// resolved against the synonym's own schema.
+func resolveSynonym(syn synonym, database string) (schema, object string, local bool) {
+	parts := splitObjectName(syn.baseObject)
+	switch len(parts) {
+	case 1:
+		return syn.schema, parts[0], true
+	case 2:
+		return parts[0], parts[1], true
+	case 3:
+		return parts[1], parts[2], strings.EqualFold(parts[0], database)
+	default:
+		n := len(parts)
+		return parts[n-2], parts[n-1], false
+	}
+}
+
+// buildResult turns the catalog into assets and lineage. The database
+// contains its schemas, schemas contain their tables, views and synonyms,
+// foreign keys link the referencing table to the referenced one, and
+// synonyms reference the object they alias.
+func (s *Source) buildResult(cat *catalog) *pluginsdk.DiscoveryResult {
+	result := &pluginsdk.DiscoveryResult{}
+
+	dbMetadata := s.baseMetadata()
+	dbMetadata["created"] = cat.database.created.Format(timeFormat)
+	dbMetadata["compatibility_level"] = cat.database.compatibilityLevel
+	if cat.database.collation.Valid {
+		dbMetadata["collation"] = cat.database.collation.String
+	}
+	if cat.database.recoveryModel.Valid {
+		dbMetadata["recovery_model"] = cat.database.recoveryModel.String
+	}
+	dbAsset := s.newAsset("Database", s.config.Database, s.config.Database, dbMetadata)
+	result.Assets = append(result.Assets, dbAsset)
+
+	schemaMRNs := make(map[string]string, len(cat.schemas))
+	hasObjects := make(map[string]bool)
+	for _, o := range cat.objects {
+		hasObjects[o.schema] = true
+	}
+	for _, syn := range cat.synonyms {
+		hasObjects[syn.schema] = true
+	}
+	for _, schema := range cat.schemas {
+		if !hasObjects[schema.name] {
+			continue
+		}
+		metadata := s.baseMetadata()
+		metadata["schema"] = schema.name
+		if schema.owner.Valid {
+			metadata["owner"] = schema.owner.String
+		}
+		description := s.applyProperties(metadata, cat.properties[schema.name])
+
+		a := s.newAsset("Schema", schema.name, schema.name, metadata)
+		if description != "" {
+			a.Description = &description
+		}
+		schemaMRNs[schema.name] = *a.MRN
+		result.Assets = append(result.Assets, a)
+		result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: *dbAsset.MRN, Target: *a.MRN, Type: "CONTAINS"})
+	}
+
+	objectMRNs := make(map[string]string, len(cat.objects))
+	for _, o := range cat.objects {
+		key := qualifiedName(o.schema, o.name)
+		metadata := s.baseMetadata()
+		metadata["schema"] = o.schema
+		metadata["table_name"] = o.name
+		metadata["created"] = o.created.Format(timeFormat)
+		metadata["modified"] = o.modified.Format(timeFormat)
+		if o.rowCount.Valid && s.config.IncludeRowCounts {
+			metadata["row_count"] = o.rowCount.Int64
+		}
+		if o.temporal.Valid && o.temporal.String != "NON_TEMPORAL_TABLE" {
+			metadata["temporal_type"] = strings.ToLower(o.temporal.String)
+		}
+		description := s.applyProperties(metadata, cat.properties[key])
+
+		constraints := cat.constraints[key]
+		if len(constraints) > 0 {
+			metadata["constraints"] = constraintMetadata(constraints)
+		}
+
+		assetType, kind := "Table", "table"
+		if o.objectType == "V" {
+			assetType, kind = "View", "view"
+		}
+		metadata["object_type"] = kind
+
+		a := s.newAsset(assetType, key, o.name, metadata)
+		if description == "" {
+			description = fmt.Sprintf("SQL Server %s %s in database %s", kind, key, s.config.Database)
+		}
+		a.Description = &description
+
+		if columns, ok := cat.columns[key]; ok {
+			a.Schema = map[string]string{"columns": columnsJSON(key, columns, constraints, cat.properties)}
+		}
+
+		objectMRNs[key] = *a.MRN
+		result.Assets = append(result.Assets, a)
+		if schemaMRN, ok := schemaMRNs[o.schema]; ok {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: schemaMRN, Target: *a.MRN, Type: "CONTAINS"})
+		}
+	}
+
+	if s.config.DiscoverForeignKeys {
+		result.Lineage = append(result.Lineage, foreignKeyLineage(cat.constraints)...)
+	}
+
+	for _, syn := range cat.synonyms {
+		targetSchema, targetName, local := resolveSynonym(syn, s.config.Database)
+		targetKey := qualifiedName(targetSchema, targetName)
+		targetMRN, resolved := objectMRNs[targetKey]
+		if local && !resolved {
+			// Synonyms of procedures, functions and other objects that
+			// weren't cataloged.
+			continue
+		}
+
+		key := qualifiedName(syn.schema, syn.name)
+		metadata := s.baseMetadata()
+		metadata["schema"] = syn.schema
+		metadata["table_name"] = syn.name
+		metadata["object_type"] = "synonym"
+		metadata["base_object_name"] = syn.baseObject
+
+		a := s.newAsset("Synonym", key, syn.name, metadata)
+		desc := fmt.Sprintf("SQL Server synonym %s for %s", key, syn.baseObject)
+		a.Description = &desc
+		result.Assets = append(result.Assets, a)
+
+		if schemaMRN, ok := schemaMRNs[syn.schema]; ok {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: schemaMRN, Target: *a.MRN, Type: "CONTAINS"})
+		}
+		if local {
+			result.Lineage = append(result.Lineage, pluginsdk.LineageEdge{Source: *a.MRN, Target: targetMRN, Type: "REFERENCES"})
+		}
+	}
+
+	return result
+}
+
+func (s *Source) baseMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"host":     s.config.Host,
+		"port":     s.config.Port,
+		"database": s.config.Database,
+	}
+}
+
+// applyProperties adds extended properties to metadata and returns the
+// MS_Description property, which becomes the asset description.
+func (s *Source) applyProperties(metadata map[string]interface{}, properties map[string]string) string {
+	description := properties[descriptionProperty]
+	if description != "" {
+		metadata["comment"] = description
+	}
+
+	extra := make(map[string]interface{}, len(properties))
+	for name, value := range properties {
+		if name != descriptionProperty {
+			extra[name] = value
+		}
+	}
+	if len(extra) > 0 {
+		metadata["extended_properties"] = extra
+	}
+	return description
+}
+
+// newAsset builds an asset whose MRN name is qualified, e.g. dbo.orders,
+// matching the MRNs the Trino plugin gives SQL Server tables.
+func (s *Source) newAsset(assetType, qualified, name string, metadata map[string]interface{}) pluginsdk.Asset {
+	mrnValue := mrn.New(assetType, provider, qualified)
+	return pluginsdk.Asset{
+		Name:      &name,
+		MRN:       &mrnValue,
+		Type:      assetType,
+		Providers: []string{provider},
+		Metadata:  metadata,
+		Tags:      pluginsdk.InterpolateTags(s.config.Tags, metadata),
+		Sources: []pluginsdk.AssetSource{{
+			Name:       provider,
+			LastSyncAt: time.Now(),
+			Properties: metadata,
+			Priority:   1,
+		}},
+	}
+}
+
+func constraintMetadata(constraints []constraint) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(constraints))
+	for _, c := range constraints {
+		m := map[string]interface{}{
+			"name":    c.name,
+			"type":    c.kind,
+			"columns": c.columns,
+		}
+		if c.disabled {
+			m["disabled"] = true
+		}
+		if c.condition != "" {
+			m["condition"] = c.condition
+		}
+		if c.refTable != "" {
+			m["references"] = qualifiedName(c.refSchema, c.refTable)
+		}
+		if c.deleteRule != "" {
+			m["delete_rule"] = c.deleteRule
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func columnsJSON(table string, columns []column, constraints []constraint, properties map[string]map[string]string) string {
+	primary := make(map[string]bool)
+	for _, c := range constraints {
+		if c.kind == "primary_key" {
+			for _, col := range c.columns {
+				primary[col] = true
+			}
+		}
+	}
+
+	result := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		m := map[string]interface{}{
+			"column_name":    col.name,
+			"data_type":      col.dataType,
+			"is_nullable":    col.nullable,
+			"is_primary_key": primary[col.name],
+		}
+		if col.isIdentity {
+			m["is_identity"] = true
+		}
+		if col.isComputed {
+			m["is_computed"] = true
+		}
+		if col.defaultValue.Valid {
+			m["column_default"] = col.defaultValue.String
+		}
+
+		colProperties := properties[qualifiedName(table, col.name)]
+		if description := colProperties[descriptionProperty]; description != "" {
+			m["comment"] = description
+		}
+		extra := make(map[string]string)
+		for name, value := range colProperties {
+			if name != descriptionProperty {
+				extra[name] = value
+			}
+		}
+		if len(extra) > 0 {
+			m["extended_properties"] = extra
+		}
+		result = append(result, m)
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to marshal columns")
+		return "[]"
+	}
+	return string(jsonBytes)
+}
+
+// foreignKeyLineage links each table to the tables its foreign keys
+// reference, once per pair.
+func foreignKeyLineage(constraints map[string][]constraint) []pluginsdk.LineageEdge {
+	tables := make([]string, 0, len(constraints))
+	for table := range constraints {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var lineages []pluginsdk.LineageEdge
+	seen := make(map[string]struct{})
+	for _, table := range tables {
+		for _, c := range constraints[table] {
+			if c.kind != "foreign_key" || c.refTable == "" {
+				continue
+			}
+			sourceMRN := mrn.New("Table", provider, table)
+			targetMRN := mrn.New("Table", provider, qualifiedName(c.refSchema, c.refTable))
+			if sourceMRN == targetMRN {
+				continue
+			}
+			relationKey := sourceMRN + ":" + targetMRN
+			if _, ok := seen[relationKey]; ok {
+				continue
+			}
+			seen[relationKey] = struct{}{}
+			lineages = append(lineages, pluginsdk.LineageEdge{Source: sourceMRN, Target: targetMRN, Type: "FOREIGN_KEY"})
+		}
+	}
+	return lineages
+}
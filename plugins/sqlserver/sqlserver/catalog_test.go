@@ -0,0 +1,157 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findAsset(t *testing.T, result *pluginsdk.DiscoveryResult, id string) *pluginsdk.Asset {
+	t.Helper()
+	for i := range result.Assets {
+		if *result.Assets[i].MRN == id {
+			return &result.Assets[i]
+		}
+	}
+	t.Fatalf("asset %s was not discovered", id)
+	return nil
+}
+
+func TestSelectSchemas(t *testing.T) {
+	all := []schemaInfo{{name: "dbo"}, {name: "reporting"}, {name: "staging"}}
+
+	assert.Equal(t, all, selectSchemas(all, nil))
+	assert.Equal(t, []schemaInfo{{name: "dbo"}, {name: "staging"}}, selectSchemas(all, []string{"DBO", "staging", "missing"}))
+}
+
+func TestFormatType(t *testing.T) {
+	assert.Equal(t, "nvarchar(50)", formatType("nvarchar", 100, 0, 0))
+	assert.Equal(t, "nvarchar(max)", formatType("nvarchar", -1, 0, 0))
+	assert.Equal(t, "varchar(20)", formatType("varchar", 20, 0, 0))
+	assert.Equal(t, "varbinary(max)", formatType("varbinary", -1, 0, 0))
+	assert.Equal(t, "decimal(10,2)", formatType("decimal", 9, 10, 2))
+	assert.Equal(t, "datetime2(7)", formatType("datetime2", 8, 27, 7))
+	assert.Equal(t, "int", formatType("int", 4, 10, 0))
+}
+
+func TestSplitObjectName(t *testing.T) {
+	assert.Equal(t, []string{"orders"}, splitObjectName("orders"))
+	assert.Equal(t, []string{"dbo", "orders"}, splitObjectName("[dbo].[orders]"))
+	assert.Equal(t, []string{"Sales", "dbo", "order.lines"}, splitObjectName(`[Sales].dbo."order.lines"`))
+	assert.Equal(t, []string{"dbo", "odd]name"}, splitObjectName("[dbo].[odd]]name]"))
+	assert.Equal(t, []string{"remote", "Archive", "dbo", "orders"}, splitObjectName("[remote].[Archive].[dbo].[orders]"))
+}
+
+func TestResolveSynonym(t *testing.T) {
+	tests := []struct {
+		baseObject string
+		schema     string
+		object     string
+		local      bool
+	}{
+		{"[orders]", "reporting", "orders", true},
+		{"[dbo].[orders]", "dbo", "orders", true},
+		{"[sales].[dbo].[orders]", "dbo", "orders", true},
+		{"[Archive].[dbo].[orders]", "dbo", "orders", false},
+		{"[remote].[Sales].[dbo].[orders]", "dbo", "orders", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseObject, func(t *testing.T) {
+			schema, object, local := resolveSynonym(synonym{schema: "reporting", baseObject: tt.baseObject}, "Sales")
+			assert.Equal(t, tt.schema, schema)
+			assert.Equal(t, tt.object, object)
+			assert.Equal(t, tt.local, local)
+		})
+	}
+}
+
+func TestBuildResult(t *testing.T) {
+	s := &Source{config: &Config{
+		Host:                "sqlserver.internal",
+		Port:                1433,
+		Database:            "Sales",
+		IncludeRowCounts:    true,
+		DiscoverForeignKeys: true,
+	}}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cat := &catalog{
+		database: databaseInfo{name: "Sales", created: created, compatibilityLevel: 160,
+			collation: sql.NullString{String: "SQL_Latin1_General_CP1_CI_AS", Valid: true}},
+		schemas: []schemaInfo{{name: "dbo"}, {name: "reporting"}, {name: "empty"}},
+		objects: []object{
+			{schema: "dbo", name: "orders", objectType: "U", created: created, modified: created,
+				rowCount: sql.NullInt64{Int64: 42, Valid: true}},
+			{schema: "dbo", name: "customers", objectType: "U", created: created, modified: created},
+			{schema: "reporting", name: "order_totals", objectType: "V", created: created, modified: created},
+		},
+		columns: map[string][]column{
+			"dbo.orders": {
+				{name: "id", dataType: "int", isIdentity: true},
+				{name: "customer_id", dataType: "int", nullable: true},
+			},
+		},
+		constraints: map[string][]constraint{
+			"dbo.orders": {
+				{name: "PK_orders", kind: "primary_key", columns: []string{"id"}},
+				{name: "FK_orders_customers", kind: "foreign_key", columns: []string{"customer_id"}, refSchema: "dbo", refTable: "customers", deleteRule: "CASCADE"},
+			},
+		},
+		synonyms: []synonym{
+			{schema: "reporting", name: "orders", baseObject: "[dbo].[orders]"},
+			{schema: "reporting", name: "archived_orders", baseObject: "[Archive].[dbo].[orders]"},
+			{schema: "reporting", name: "next_id", baseObject: "[dbo].[get_next_id]"},
+		},
+		properties: map[string]map[string]string{
+			"dbo":                {"MS_Description": "Core sales tables"},
+			"dbo.orders":         {"MS_Description": "Customer orders", "Owner": "Sales Ops"},
+			"dbo.orders.id":      {"MS_Description": "Order number"},
+			"dbo.orders.missing": {"MS_Description": "Dropped column"},
+		},
+	}
+
+	result := s.buildResult(cat)
+
+	database := mrn.New("Database", "SQL Server", "Sales")
+	schema := mrn.New("Schema", "SQL Server", "dbo")
+	orders := mrn.New("Table", "SQL Server", "dbo.orders")
+	customers := mrn.New("Table", "SQL Server", "dbo.customers")
+	ordersSynonym := mrn.New("Synonym", "SQL Server", "reporting.orders")
+
+	assert.Equal(t, "mrn://table/sql server/dbo.orders", orders, "MRNs match the Trino plugin's")
+
+	db := findAsset(t, result, database)
+	assert.Equal(t, "SQL_Latin1_General_CP1_CI_AS", db.Metadata["collation"])
+
+	dbo := findAsset(t, result, schema)
+	assert.Equal(t, "Core sales tables", *dbo.Description)
+
+	table := findAsset(t, result, orders)
+	assert.Equal(t, "orders", *table.Name)
+	assert.Equal(t, "Customer orders", *table.Description)
+	assert.Equal(t, map[string]interface{}{"Owner": "Sales Ops"}, table.Metadata["extended_properties"])
+	assert.Equal(t, int64(42), table.Metadata["row_count"])
+	assert.Len(t, table.Metadata["constraints"], 2)
+	require.Contains(t, table.Schema, "columns")
+	assert.JSONEq(t, `[
+		{"column_name": "id", "data_type": "int", "is_nullable": false, "is_primary_key": true, "is_identity": true, "comment": "Order number"},
+		{"column_name": "customer_id", "data_type": "int", "is_nullable": true, "is_primary_key": false}
+	]`, table.Schema["columns"])
+
+	view := findAsset(t, result, mrn.New("View", "SQL Server", "reporting.order_totals"))
+	assert.Equal(t, "view", view.Metadata["object_type"])
+
+	findAsset(t, result, ordersSynonym)
+	findAsset(t, result, mrn.New("Synonym", "SQL Server", "reporting.archived_orders"))
+	assert.Len(t, result.Assets, 8, "empty schemas and synonyms of uncataloged procedures are skipped")
+
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: database, Target: schema, Type: "CONTAINS"})
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: schema, Target: orders, Type: "CONTAINS"})
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: orders, Target: customers, Type: "FOREIGN_KEY"})
+	assert.Contains(t, result.Lineage, pluginsdk.LineageEdge{Source: ordersSynonym, Target: orders, Type: "REFERENCES"})
+}
@@ -0,0 +1,38 @@
+package sqlserver
+
+// SQLServerFields represents SQL Server-specific metadata fields
+// +marmot:metadata
+type SQLServerFields struct {
+	Host               string                   `json:"host" metadata:"host" description:"SQL Server hostname"`
+	Port               int                      `json:"port" metadata:"port" description:"SQL Server port"`
+	Database           string                   `json:"database" metadata:"database" description:"Database name"`
+	Schema             string                   `json:"schema" metadata:"schema" description:"Schema name"`
+	TableName          string                   `json:"table_name" metadata:"table_name" description:"Object name"`
+	ObjectType         string                   `json:"object_type" metadata:"object_type" description:"Object type (table, view, synonym)"`
+	Owner              string                   `json:"owner" metadata:"owner" description:"Schema owner"`
+	RowCount           int64                    `json:"row_count" metadata:"row_count" description:"Row count from partition statistics"`
+	TemporalType       string                   `json:"temporal_type" metadata:"temporal_type" description:"Temporal table type, e.g. system_versioned_temporal_table"`
+	Created            string                   `json:"created" metadata:"created" description:"Creation timestamp"`
+	Modified           string                   `json:"modified" metadata:"modified" description:"Last modification timestamp"`
+	Comment            string                   `json:"comment" metadata:"comment" description:"MS_Description extended property"`
+	ExtendedProperties map[string]interface{}   `json:"extended_properties" metadata:"extended_properties" description:"Other extended properties"`
+	Constraints        []map[string]interface{} `json:"constraints" metadata:"constraints" description:"Primary key, unique, foreign key and check constraints"`
+	BaseObjectName     string                   `json:"base_object_name" metadata:"base_object_name" description:"Object a synonym refers to"`
+	Collation          string                   `json:"collation" metadata:"collation" description:"Database default collation"`
+	CompatibilityLevel int                      `json:"compatibility_level" metadata:"compatibility_level" description:"Database compatibility level"`
+	RecoveryModel      string                   `json:"recovery_model" metadata:"recovery_model" description:"Database recovery model"`
+}
+
+// SQLServerColumnFields represents SQL Server column-specific metadata fields
+// +marmot:metadata
+type SQLServerColumnFields struct {
+	ColumnName         string            `json:"column_name" metadata:"column_name" description:"Column name"`
+	DataType           string            `json:"data_type" metadata:"data_type" description:"Data type, e.g. nvarchar(50)"`
+	IsNullable         bool              `json:"is_nullable" metadata:"is_nullable" description:"Whether null values are allowed"`
+	IsPrimaryKey       bool              `json:"is_primary_key" metadata:"is_primary_key" description:"Whether column is part of primary key"`
+	IsIdentity         bool              `json:"is_identity" metadata:"is_identity" description:"Whether column is an identity column"`
+	IsComputed         bool              `json:"is_computed" metadata:"is_computed" description:"Whether column is computed"`
+	ColumnDefault      string            `json:"column_default" metadata:"column_default" description:"Default value expression"`
+	Comment            string            `json:"comment" metadata:"comment" description:"MS_Description extended property"`
+	ExtendedProperties map[string]string `json:"extended_properties" metadata:"extended_properties" description:"Other extended properties"`
+}
@@ -0,0 +1,274 @@
+// Package sqlserver discovers schemas, tables, views and synonyms from
+// Microsoft SQL Server databases.
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	_ "github.com/microsoft/go-mssqldb"
+	"github.com/rs/zerolog/log"
+)
+
+// Meta describes the plugin to the Marmot host.
+func Meta() pluginsdk.Meta {
+	return pluginsdk.Meta{
+		ID:          "sqlserver",
+		Name:        "SQL Server",
+		Description: "Discover schemas, tables, views and synonyms from Microsoft SQL Server databases",
+		Icon:        "sqlserver",
+		Category:    "database",
+		Status:      "experimental",
+		Features:    []string{"Assets", "Lineage"},
+		ConfigSpec:  pluginsdk.GenerateConfigSpec(Config{}),
+	}
+}
+
+// Config for SQL Server plugin
+type Config struct {
+	pluginsdk.BaseConfig `json:",inline"`
+
+	Host                   string `json:"host" description:"SQL Server hostname or IP address" validate:"required"`
+	Port                   int    `json:"port" description:"SQL Server port" default:"1433" validate:"omitempty,min=1,max=65535"`
+	User                   string `json:"user" description:"Username for SQL Server authentication" validate:"required"`
+	Password               string `json:"password" description:"Password for authentication" sensitive:"true"`
+	Database               string `json:"database" description:"Database name to connect to" validate:"required"`
+	Encrypt                string `json:"encrypt" description:"Connection encryption (disable, false, true, strict)" default:"true" validate:"omitempty,oneof=disable false true strict"`
+	TrustServerCertificate bool   `json:"trust_server_certificate" description:"Whether to skip verifying the server certificate" default:"false"`
+
+	Schemas             []string `json:"schemas" description:"Schemas to discover. Defaults to every user schema"`
+	IncludeColumns      bool     `json:"include_columns" description:"Whether to include column information in table metadata" default:"true"`
+	IncludeRowCounts    bool     `json:"include_row_counts" description:"Whether to include row counts" default:"true"`
+	IncludeSynonyms     bool     `json:"include_synonyms" description:"Whether to discover synonyms" default:"true"`
+	DiscoverForeignKeys bool     `json:"discover_foreign_keys" description:"Whether to discover foreign key relationships" default:"true"`
+}
+
+// Example configuration for the plugin
+var _ = `
+host: "sqlserver-prod.internal"
+port: 1433
+user: "marmot_reader"
+password: "sqlserver_secure_pass"
+database: "Sales"
+encrypt: "true"
+schemas:
+  - "dbo"
+  - "reporting"
+tags:
+  - "sqlserver"
+  - "${schema}"
+`
+
+type Source struct {
+	config *Config
+	db     *sql.DB
+}
+
+func (s *Source) Validate(rawConfig pluginsdk.RawConfig) (pluginsdk.RawConfig, error) {
+	config, err := pluginsdk.UnmarshalConfig[Config](rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	if config.Port == 0 {
+		config.Port = 1433
+	}
+	if config.Encrypt == "" {
+		config.Encrypt = "true"
+	}
+
+	if err := pluginsdk.ValidateStruct(config); err != nil {
+		return nil, err
+	}
+
+	s.config = config
+	return rawConfig, nil
+}
+
+func (s *Source) Discover(ctx context.Context, pluginConfig pluginsdk.RawConfig) (*pluginsdk.DiscoveryResult, error) {
+	// The host spawns a fresh plugin process per call, so Discover
+	// cannot rely on state set by an earlier Validate call.
+	if _, err := s.Validate(pluginConfig); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	if err := s.initConnection(ctx); err != nil {
+		return nil, fmt.Errorf("initializing database connection: %w", err)
+	}
+	defer s.closeConnection()
+
+	cat, err := s.readCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.buildResult(cat)
+	log.Info().
+		Str("database", s.config.Database).
+		Int("assets", len(result.Assets)).
+		Int("lineages", len(result.Lineage)).
+		Msg("SQL Server discovery completed")
+
+	return result, nil
+}
+
+func (s *Source) initConnection(ctx context.Context) error {
+	s.closeConnection()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("database", s.config.Database)
+	query.Set("encrypt", s.config.Encrypt)
+	query.Set("TrustServerCertificate", strconv.FormatBool(s.config.TrustServerCertificate))
+	query.Set("app name", "marmot")
+	query.Set("dial timeout", "15")
+
+	dsn := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(s.config.User, s.config.Password),
+		Host:     fmt.Sprintf("%s:%d", s.config.Host, s.config.Port),
+		RawQuery: query.Encode(),
+	}
+
+	db, err := sql.Open("sqlserver", dsn.String())
+	if err != nil {
+		return fmt.Errorf("opening connection: %w", err)
+	}
+
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(2 * time.Minute)
+	db.SetConnMaxIdleTime(30 * time.Second)
+
+	if err := db.PingContext(timeoutCtx); err != nil {
+		db.Close()
+		return fmt.Errorf("pinging database: %w", err)
+	}
+
+	log.Debug().
+		Str("host", s.config.Host).
+		Int("port", s.config.Port).
+		Str("database", s.config.Database).
+		Msg("Successfully connected to SQL Server")
+
+	s.db = db
+	return nil
+}
+
+func (s *Source) closeConnection() {
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+}
+
+// FetchSampleData implements the DataFetcher interface to retrieve sample data from a SQL Server table
+func (s *Source) FetchSampleData(ctx context.Context, config pluginsdk.RawConfig, a *pluginsdk.Asset) ([]string, [][]interface{}, error) {
+	if a == nil || a.Metadata == nil {
+		return nil, nil, fmt.Errorf("asset or asset metadata is nil")
+	}
+
+	if _, err := s.Validate(config); err != nil {
+		return nil, nil, fmt.Errorf("parsing plugin config: %w", err)
+	}
+
+	schema, _ := a.Metadata["schema"].(string)
+	table, _ := a.Metadata["table_name"].(string)
+	if schema == "" || table == "" {
+		return nil, nil, fmt.Errorf("could not determine schema and table from asset metadata")
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := s.initConnection(fetchCtx); err != nil {
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	defer s.closeConnection()
+
+	//nolint:gosec // G201: inputs sanitized via quoteIdentifier
+	query := fmt.Sprintf("SELECT TOP 20 * FROM %s.%s",
+		quoteIdentifier(schema),
+		quoteIdentifier(table),
+	)
+
+	log.Debug().
+		Str("schema", schema).
+		Str("table", table).
+		Msg("Fetching sample data")
+
+	rows, err := s.db.QueryContext(fetchCtx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying table: %w", err)
+	}
+	defer rows.Close()
+
+	columnNames, err := rows.Columns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting column names: %w", err)
+	}
+
+	var dataRows [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columnNames))
+		valuePtrs := make([]interface{}, len(columnNames))
+		for i := range columnNames {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan row, skipping")
+			continue
+		}
+
+		convertedValues := make([]interface{}, len(values))
+		for i, val := range values {
+			convertedValues[i] = convertSQLServerValue(val)
+		}
+
+		dataRows = append(dataRows, convertedValues)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterating rows: %w", err)
+	}
+
+	return columnNames, dataRows, nil
+}
+
+// quoteIdentifier wraps an identifier in brackets for T-SQL.
+func quoteIdentifier(id string) string {
+	id = strings.ReplaceAll(id, "\x00", "")
+	return "[" + strings.ReplaceAll(id, "]", "]]") + "]"
+}
+
+// convertSQLServerValue converts SQL Server-specific types to JSON-friendly formats
+func convertSQLServerValue(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	switch v := val.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return fmt.Sprintf("0x%x", v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return val
+	}
+}
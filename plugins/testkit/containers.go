@@ -0,0 +1,175 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const startupTimeout = 2 * time.Minute
+
+// Container is a running service a plugin can discover against. It is
+// terminated when the test finishes.
+type Container struct {
+	testcontainers.Container
+	Host string
+}
+
+// Port returns the host port mapped to the container port, e.g. "5432/tcp".
+func (c *Container) Port(t testing.TB, port string) int {
+	t.Helper()
+	mapped, err := c.MappedPort(context.Background(), port)
+	if err != nil {
+		t.Fatalf("resolving mapped port %s: %v", port, err)
+	}
+	return mapped.Int()
+}
+
+// Endpoint returns host:port for the container port.
+func (c *Container) Endpoint(t testing.TB, port string) string {
+	t.Helper()
+	return fmt.Sprintf("%s:%d", c.Host, c.Port(t, port))
+}
+
+// Start runs a container for the duration of the test. The test is skipped
+// with -short or when no container runtime is available.
+func Start(t testing.TB, req testcontainers.ContainerRequest) *Container {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping container test in short mode")
+	}
+	skipWithoutDocker(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if c != nil {
+		t.Cleanup(func() {
+			if err := testcontainers.TerminateContainer(c); err != nil {
+				t.Logf("terminating %s: %v", req.Image, err)
+			}
+		})
+	}
+	if err != nil {
+		t.Fatalf("starting %s: %v", req.Image, err)
+	}
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		t.Fatalf("resolving host for %s: %v", req.Image, err)
+	}
+	return &Container{Container: c, Host: host}
+}
+
+func skipWithoutDocker(t testing.TB) {
+	t.Helper()
+	defer func() {
+		// The provider panics rather than erroring when no socket is found.
+		if r := recover(); r != nil {
+			t.Skipf("docker is not available: %v", r)
+		}
+	}()
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	defer provider.Close()
+	if err := provider.Health(context.Background()); err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+}
+
+// PostgresOption configures the Postgres container.
+type PostgresOption func(*postgresOptions)
+
+type postgresOptions struct {
+	image   string
+	initSQL []string
+}
+
+// WithImage overrides the default postgres:16-alpine image.
+func WithImage(image string) PostgresOption {
+	return func(o *postgresOptions) { o.image = image }
+}
+
+// WithInitSQL runs the SQL file when the database is first created. Files
+// run in the order given.
+func WithInitSQL(path string) PostgresOption {
+	return func(o *postgresOptions) { o.initSQL = append(o.initSQL, path) }
+}
+
+// PostgresContainer is a disposable PostgreSQL server.
+type PostgresContainer struct {
+	*Container
+	Port     int
+	User     string
+	Password string
+	Database string
+}
+
+// Postgres starts a PostgreSQL server for the test.
+func Postgres(t testing.TB, opts ...PostgresOption) *PostgresContainer {
+	t.Helper()
+
+	o := postgresOptions{image: "postgres:16-alpine"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        o.image,
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "marmot",
+			"POSTGRES_PASSWORD": "marmot",
+			"POSTGRES_DB":       "marmot",
+		},
+		// The server restarts once after running init scripts.
+		WaitingFor: wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(startupTimeout),
+	}
+	for i, path := range o.initSQL {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatalf("resolving %s: %v", path, err)
+		}
+		req.Files = append(req.Files, testcontainers.ContainerFile{
+			HostFilePath:      abs,
+			ContainerFilePath: "/docker-entrypoint-initdb.d/" + strconv.Itoa(i) + "_" + filepath.Base(path),
+			FileMode:          0o644,
+		})
+	}
+
+	c := Start(t, req)
+	return &PostgresContainer{
+		Container: c,
+		Port:      c.Port(t, "5432/tcp"),
+		User:      "marmot",
+		Password:  "marmot",
+		Database:  "marmot",
+	}
+}
+
+// Config returns connection settings in the shape most SQL plugins accept.
+// Add plugin-specific keys to the returned map as needed.
+func (p *PostgresContainer) Config() pluginsdk.RawConfig {
+	return pluginsdk.RawConfig{
+		"host":     p.Host,
+		"port":     p.Port,
+		"user":     p.User,
+		"password": p.Password,
+		"ssl_mode": "disable",
+	}
+}
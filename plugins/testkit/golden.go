@@ -0,0 +1,135 @@
+package testkit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldenOption adjusts how a result is normalised before comparison.
+type GoldenOption func(*goldenOptions)
+
+type goldenOptions struct {
+	ignoreMetadata   map[string]bool
+	ignoreStatistics bool
+	ignoreRunHistory bool
+}
+
+// IgnoreMetadata drops metadata keys whose values change between runs, such
+// as sizes, row estimates or creation times.
+func IgnoreMetadata(keys ...string) GoldenOption {
+	return func(o *goldenOptions) {
+		for _, k := range keys {
+			o.ignoreMetadata[k] = true
+		}
+	}
+}
+
+// IgnoreStatistics leaves statistics out of the snapshot.
+func IgnoreStatistics() GoldenOption {
+	return func(o *goldenOptions) { o.ignoreStatistics = true }
+}
+
+// IgnoreRunHistory leaves run history out of the snapshot.
+func IgnoreRunHistory() GoldenOption {
+	return func(o *goldenOptions) { o.ignoreRunHistory = true }
+}
+
+// Golden compares a discovery result with the snapshot at path. Assets,
+// lineage, documentation and statistics are sorted and sync timestamps are
+// removed first, so only meaningful changes show up. With -update the
+// snapshot is rewritten instead.
+func Golden(t testing.TB, path string, result *pluginsdk.DiscoveryResult, opts ...GoldenOption) {
+	t.Helper()
+
+	got, err := Snapshot(result, opts...)
+	require.NoError(t, err, "normalising discovery result")
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("golden file %s does not exist; run the test with -update to create it", path)
+	}
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(want), string(got), "discovery result differs from %s; run with -update if the change is intended", path)
+}
+
+// Snapshot returns the normalised JSON that Golden compares.
+func Snapshot(result *pluginsdk.DiscoveryResult, opts ...GoldenOption) ([]byte, error) {
+	o := goldenOptions{ignoreMetadata: map[string]bool{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Work on a copy so the caller's result is left untouched.
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var r pluginsdk.DiscoveryResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	for i := range r.Assets {
+		a := &r.Assets[i]
+		for k := range a.Metadata {
+			if o.ignoreMetadata[k] {
+				delete(a.Metadata, k)
+			}
+		}
+		for j := range a.Sources {
+			a.Sources[j].LastSyncAt = time.Time{}
+		}
+		sort.Strings(a.Tags)
+	}
+
+	sort.Slice(r.Assets, func(i, j int) bool { return deref(r.Assets[i].MRN) < deref(r.Assets[j].MRN) })
+	sort.Slice(r.Lineage, func(i, j int) bool {
+		a, b := r.Lineage[i], r.Lineage[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Type < b.Type
+	})
+	sort.Slice(r.Documentation, func(i, j int) bool { return r.Documentation[i].MRN < r.Documentation[j].MRN })
+	sort.Slice(r.Statistics, func(i, j int) bool {
+		a, b := r.Statistics[i], r.Statistics[j]
+		if a.AssetMRN != b.AssetMRN {
+			return a.AssetMRN < b.AssetMRN
+		}
+		return a.MetricName < b.MetricName
+	})
+
+	if o.ignoreStatistics {
+		r.Statistics = nil
+	}
+	if o.ignoreRunHistory {
+		r.RunHistory = nil
+	}
+
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,115 @@
+package testkit
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func sampleResult() *pluginsdk.DiscoveryResult {
+	orders := mrn.New("Table", "PostgreSQL", "shop.public.orders")
+	customers := mrn.New("Table", "PostgreSQL", "shop.public.customers")
+	return &pluginsdk.DiscoveryResult{
+		Assets: []pluginsdk.Asset{
+			{
+				Name:      strPtr("orders"),
+				MRN:       &orders,
+				Type:      "Table",
+				Providers: []string{"PostgreSQL"},
+				Tags:      []string{"sales", "core"},
+				Metadata:  map[string]interface{}{"schema": "public", "size": 8192},
+				Sources:   []pluginsdk.AssetSource{{Name: "PostgreSQL", LastSyncAt: time.Now()}},
+			},
+			{
+				Name:      strPtr("customers"),
+				MRN:       &customers,
+				Type:      "Table",
+				Providers: []string{"PostgreSQL"},
+			},
+		},
+		Lineage: []pluginsdk.LineageEdge{
+			{Source: orders, Target: customers, Type: "FOREIGN_KEY"},
+		},
+		Statistics: []pluginsdk.Statistic{
+			{AssetMRN: orders, MetricName: "row_count", Value: 42},
+		},
+	}
+}
+
+func TestSnapshot_IsStable(t *testing.T) {
+	a := sampleResult()
+	b := sampleResult()
+	b.Assets[0], b.Assets[1] = b.Assets[1], b.Assets[0]
+	b.Assets[1].Tags = []string{"core", "sales"}
+	b.Assets[1].Sources[0].LastSyncAt = time.Now().Add(time.Hour)
+
+	gotA, err := Snapshot(a, IgnoreMetadata("size"))
+	require.NoError(t, err)
+	gotB, err := Snapshot(b, IgnoreMetadata("size"))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(gotA), string(gotB))
+
+	var r pluginsdk.DiscoveryResult
+	require.NoError(t, json.Unmarshal(gotA, &r))
+	assert.Equal(t, "customers", *r.Assets[0].Name)
+	assert.NotContains(t, r.Assets[1].Metadata, "size")
+	assert.Contains(t, r.Assets[1].Metadata, "schema")
+}
+
+func TestSnapshot_LeavesInputUntouched(t *testing.T) {
+	r := sampleResult()
+	_, err := Snapshot(r, IgnoreMetadata("size"), IgnoreStatistics())
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders", *r.Assets[0].Name)
+	assert.Contains(t, r.Assets[0].Metadata, "size")
+	assert.Len(t, r.Statistics, 1)
+}
+
+func TestGolden_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discover.golden.json")
+
+	*update = true
+	Golden(t, path, sampleResult())
+	*update = false
+
+	Golden(t, path, sampleResult())
+}
+
+func TestAssertConsistent(t *testing.T) {
+	assert.True(t, AssertConsistent(t, sampleResult()))
+
+	bad := sampleResult()
+	bad.Assets[1].MRN = bad.Assets[0].MRN
+	bad.Lineage = append(bad.Lineage, pluginsdk.LineageEdge{Source: "orders", Target: "customers", Type: "FOREIGN_KEY"})
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertConsistent(rec, bad))
+	assert.Equal(t, 3, rec.errors)
+}
+
+// recorder counts failures instead of failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *recorder) Helper()                       {}
+func (r *recorder) Errorf(string, ...interface{}) { r.errors++ }
+
+func TestAssertMRN(t *testing.T) {
+	r := sampleResult()
+	AssertMRN(t, *r.Assets[0].MRN, "Table", "PostgreSQL", "shop.public.orders")
+
+	a := FindAsset(t, r, mrn.New("Table", "PostgreSQL", "shop.public.customers"))
+	assert.Equal(t, "customers", *a.Name)
+	AssertLineage(t, r, *r.Assets[0].MRN, *r.Assets[1].MRN, "FOREIGN_KEY")
+}
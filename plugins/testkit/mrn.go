@@ -0,0 +1,102 @@
+package testkit
+
+import (
+	"strings"
+	"testing"
+
+	pluginsdk "github.com/marmotdata/plugin-sdk"
+	"github.com/marmotdata/plugin-sdk/mrn"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertMRN checks that got is the MRN mrn.New builds for the given type,
+// service and name.
+func AssertMRN(t testing.TB, got, assetType, service, name string) bool {
+	t.Helper()
+	return assert.Equal(t, mrn.New(assetType, service, name), got, "unexpected MRN")
+}
+
+// AssertConsistent checks the invariants every plugin's result must hold:
+// each asset has a well-formed, unique MRN whose type segment matches the
+// asset type, lineage edges connect well-formed MRNs, and documentation and
+// statistics refer to discovered assets.
+func AssertConsistent(t testing.TB, result *pluginsdk.DiscoveryResult) bool {
+	t.Helper()
+	ok := true
+
+	seen := map[string]bool{}
+	for _, a := range result.Assets {
+		if a.MRN == nil || *a.MRN == "" {
+			ok = assert.Fail(t, "asset has no MRN", "name %q, type %q", deref(a.Name), a.Type)
+			continue
+		}
+		id := *a.MRN
+		if seen[id] {
+			ok = assert.Fail(t, "duplicate asset MRN", id)
+		}
+		seen[id] = true
+
+		parsed, err := mrn.Parse(id)
+		if !assert.NoError(t, err, "asset MRN %s", id) {
+			ok = false
+			continue
+		}
+		if parsed.Type != strings.ToLower(a.Type) {
+			ok = assert.Fail(t, "MRN type does not match asset type", "%s has type %q", id, a.Type)
+		}
+		if len(a.Providers) == 0 {
+			ok = assert.Fail(t, "asset has no providers", id)
+		}
+	}
+
+	for _, l := range result.Lineage {
+		for _, end := range []string{l.Source, l.Target} {
+			if _, err := mrn.Parse(end); err != nil {
+				ok = assert.Fail(t, "lineage endpoint is not an MRN", "%s -> %s: %v", l.Source, l.Target, err)
+			}
+		}
+		if l.Source == l.Target {
+			ok = assert.Fail(t, "lineage edge points at itself", l.Source)
+		}
+		if l.Type == "" {
+			ok = assert.Fail(t, "lineage edge has no type", "%s -> %s", l.Source, l.Target)
+		}
+	}
+
+	for _, d := range result.Documentation {
+		if !seen[d.MRN] {
+			ok = assert.Fail(t, "documentation for an undiscovered asset", d.MRN)
+		}
+	}
+	for _, s := range result.Statistics {
+		if !seen[s.AssetMRN] {
+			ok = assert.Fail(t, "statistic for an undiscovered asset", "%s %s", s.AssetMRN, s.MetricName)
+		}
+	}
+	return ok
+}
+
+// FindAsset returns the discovered asset with the given MRN, failing the
+// test if there is none.
+func FindAsset(t testing.TB, result *pluginsdk.DiscoveryResult, id string) *pluginsdk.Asset {
+	t.Helper()
+	for i := range result.Assets {
+		if deref(result.Assets[i].MRN) == id {
+			return &result.Assets[i]
+		}
+	}
+	t.Fatalf("asset %s was not discovered", id)
+	return nil
+}
+
+// AssertLineage checks that the result contains an edge from source to
+// target. An empty edgeType matches any type.
+func AssertLineage(t testing.TB, result *pluginsdk.DiscoveryResult, source, target, edgeType string) bool {
+	t.Helper()
+	for _, l := range result.Lineage {
+		if l.Source == source && l.Target == target && (edgeType == "" || l.Type == edgeType) {
+			return true
+		}
+	}
+	return assert.Fail(t, "lineage edge not found", "%s -[%s]-> %s", source, edgeType, target)
+}
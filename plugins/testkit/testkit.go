@@ -0,0 +1,26 @@
+// Package testkit gives provider plugins consistent end-to-end discovery
+// tests: disposable containers of the source system, golden snapshots of
+// discovered assets and lineage, and assertions on the MRNs a plugin emits.
+//
+// Container tests need Docker and are skipped with -short or when no
+// container runtime is reachable, so they can live beside unit tests:
+//
+//	func TestDiscover(t *testing.T) {
+//	    pg := testkit.Postgres(t, testkit.WithInitSQL("testdata/schema.sql"))
+//	    result, err := (&Source{}).Discover(ctx, pg.Config())
+//	    require.NoError(t, err)
+//
+//	    testkit.AssertConsistent(t, result)
+//	    testkit.AssertLineage(t, result, orders, customers, "FOREIGN_KEY")
+//	    testkit.Golden(t, "testdata/discover.golden.json", result)
+//	}
+//
+// Run `go test ./... -update` to rewrite golden files after an intended
+// change, and review the diff like any other code.
+package testkit
+
+import (
+	"flag"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current discovery result")
@@ -53,6 +53,16 @@ export async function getTerm(id: string): Promise<GlossaryTerm> {
 	return response.json();
 }
 
+// Record that a term was opened from search results. Best effort; failures
+// only lose a usage count.
+export async function recordTermClick(id: string): Promise<void> {
+	try {
+		await fetchApi(`/glossary/${id}/click`, { method: 'POST' });
+	} catch {
+		// ignore
+	}
+}
+
 export async function getTermByShortName(shortName: string): Promise<GlossaryTerm> {
 	const response = await fetchApi(`/glossary/name/${shortName}`);
 	if (!response.ok) {
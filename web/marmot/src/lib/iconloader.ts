@@ -28,6 +28,7 @@ import ClickHouseIcon from '~icons/devicon/clickhouse';
 import TrinoIcon from '~icons/simple-icons/trino';
 import TeradataIcon from '~icons/simple-icons/teradata';
 import OracleIcon from '~icons/devicon/oracle';
+import SqlServerIcon from '~icons/devicon/microsoftsqlserver';
 import SalesforceIcon from '~icons/devicon/salesforce';
 import AthenaIcon from '~icons/logos/aws-athena';
 import RedshiftIcon from '~icons/logos/aws-redshift';
@@ -40,6 +41,9 @@ import PulsarIcon from '~icons/devicon/pulsar';
 import RabbitMQIcon from '~icons/devicon/rabbitmq';
 import TableauIcon from '~icons/simple-icons/tableau';
 import LookerIcon from '~icons/logos/looker-icon';
+import GoogleAnalyticsIcon from '~icons/logos/google-analytics';
+import GoogleAdsIcon from '~icons/logos/google-ads';
+import FacebookIcon from '~icons/logos/facebook';
 import LangChainIcon from '~icons/simple-icons/langchain';
 import ClaudeIcon from '~icons/simple-icons/claude';
 
@@ -75,6 +79,7 @@ import MemoryOutline from '~icons/material-symbols/memory';
 import SendOutline from '~icons/material-symbols/send-outline';
 import LinkOutline from '~icons/material-symbols/link';
 import DashboardOutline from '~icons/material-symbols/dashboard-outline';
+import MonitoringOutline from '~icons/material-symbols/monitoring';
 import StorageOutline from '~icons/material-symbols/storage';
 import RobotOutline from '~icons/material-symbols/robot-2-outline';
 import AlternateEmailRounded from '~icons/material-symbols/alternate-email-rounded';
@@ -128,7 +133,14 @@ export const providerIconMap: Record<
 	starburst: { default: TrinoIcon, class: 'text-[#DD00A1]', displayName: 'Starburst' },
 	teradata: { default: TeradataIcon, class: 'text-[#F37440]', displayName: 'Teradata' },
 	oracle: { default: OracleIcon, displayName: 'Oracle' },
+	sqlserver: { default: SqlServerIcon, displayName: 'SQL Server' },
+	'sql-server': { default: SqlServerIcon, displayName: 'SQL Server' },
+	mssql: { default: SqlServerIcon, displayName: 'SQL Server' },
 	salesforce: { default: SalesforceIcon, displayName: 'Salesforce' },
+	ga4: { default: GoogleAnalyticsIcon, displayName: 'Google Analytics 4' },
+	'google-analytics': { default: GoogleAnalyticsIcon, displayName: 'Google Analytics' },
+	googleads: { default: GoogleAdsIcon, displayName: 'Google Ads' },
+	facebookads: { default: FacebookIcon, displayName: 'Facebook Ads' },
 	athena: { default: AthenaIcon, displayName: 'Athena' },
 	redshift: { default: RedshiftIcon, displayName: 'Redshift' },
 	'aws-glue': { default: GlueIcon, displayName: 'AWS Glue' },
@@ -382,6 +394,11 @@ export const typeIconMap: Record<
 		class: 'text-gray-900 dark:text-gray-100',
 		displayName: 'Dashboard'
 	},
+	metric: {
+		default: MonitoringOutline,
+		class: 'text-gray-900 dark:text-gray-100',
+		displayName: 'Metric'
+	},
 	datasource: {
 		default: StorageOutline,
 		class: 'text-gray-900 dark:text-gray-100',
@@ -1,8 +1,11 @@
 import type { Asset } from '$lib/assets/types';
 
+export type LineageNodeKind = 'dataset' | 'job' | 'service';
+
 export interface LineageNode {
 	id: string;
 	type: string;
+	kind?: LineageNodeKind;
 	asset: Asset;
 	depth: number;
 }
@@ -16,6 +19,14 @@ export interface LineageEdge {
 	observation_count?: number;
 	last_seen_at?: string;
 	job_mrn?: string;
+	freshness?: LineageEdgeFreshness;
+}
+
+export interface LineageEdgeFreshness {
+	status: 'current' | 'stale' | 'untracked';
+	last_confirmed_at?: string;
+	pipeline?: string;
+	missed_runs: number;
 }
 
 export interface LineageResponse {
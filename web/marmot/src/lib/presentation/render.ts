@@ -0,0 +1,58 @@
+import type { Asset } from '$lib/assets/types';
+import type { PresentationField } from './types';
+
+// Resolves a dot-separated key against metadata, preferring an exact match
+// for keys that themselves contain dots.
+export function metadataValue(metadata: Record<string, unknown> | undefined, key: string): unknown {
+	if (!metadata) return undefined;
+	if (key in metadata) return metadata[key];
+
+	let value: unknown = metadata;
+	for (const part of key.split('.')) {
+		if (typeof value !== 'object' || value === null || Array.isArray(value)) return undefined;
+		value = (value as Record<string, unknown>)[part];
+	}
+	return value;
+}
+
+export function fieldLabel(field: PresentationField): string {
+	if (field.label) return field.label;
+	const last = field.key.split('.').pop() || field.key;
+	return last.replace(/[_-]+/g, ' ').replace(/^\w/, (c) => c.toUpperCase());
+}
+
+export function formatFieldValue(field: PresentationField, value: unknown): string {
+	let text: string;
+	if (Array.isArray(value)) {
+		text = value.join(', ');
+	} else if (typeof value === 'object' && value !== null) {
+		text = JSON.stringify(value);
+	} else if (typeof value === 'number') {
+		text = value.toLocaleString();
+	} else {
+		text = String(value);
+	}
+	return field.unit ? `${text} ${field.unit}` : text;
+}
+
+// Expands a link template, returning null when a placeholder has no value so
+// half-built links are never shown.
+export function expandLink(template: string, asset: Asset): string | null {
+	let missing = false;
+	const url = template.replace(/\{([^{}]+)\}/g, (_, name: string) => {
+		let value: unknown;
+		if (name === 'name') value = asset.name;
+		else if (name === 'mrn') value = asset.mrn;
+		else if (name === 'type') value = asset.type;
+		else if (name === 'provider') value = asset.providers?.[0];
+		else if (name.startsWith('metadata.')) value = metadataValue(asset.metadata, name.slice(9));
+
+		if (value === undefined || value === null || value === '') {
+			missing = true;
+			return '';
+		}
+		return encodeURIComponent(String(value));
+	});
+	return missing ? null : url;
+}
+
@@ -0,0 +1,26 @@
+export interface PresentationField {
+	key: string;
+	label?: string;
+	unit?: string;
+	highlight?: boolean;
+}
+
+export interface PresentationGroup {
+	name: string;
+	fields: PresentationField[];
+}
+
+export interface PresentationLink {
+	label: string;
+	template: string;
+	icon?: string;
+}
+
+export interface PresentationSchema {
+	asset_type: string;
+	groups: PresentationGroup[];
+	links: PresentationLink[];
+	updated_by?: string;
+	created_at: string;
+	updated_at: string;
+}
@@ -0,0 +1,28 @@
+export interface SavedQueryOwner {
+	type: 'user' | 'team';
+	id: string;
+	name?: string;
+}
+
+export interface SavedQueryAsset {
+	id: string;
+	mrn: string;
+	name: string;
+	type: string;
+	providers: string[];
+}
+
+export interface SavedQuery {
+	id: string;
+	name: string;
+	description?: string;
+	query: string;
+	language: string;
+	tags: string[];
+	owner?: SavedQueryOwner;
+	assets: SavedQueryAsset[];
+	created_by?: string;
+	created_by_name?: string;
+	created_at: string;
+	updated_at: string;
+}
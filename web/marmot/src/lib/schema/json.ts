@@ -1,18 +1,60 @@
 import type { Field } from './types';
 import Ajv from 'ajv';
+import Ajv2019 from 'ajv/dist/2019';
+import Ajv2020 from 'ajv/dist/2020';
 import addFormats from 'ajv-formats';
 
-const ajv = new Ajv({
+type JsonSchemaValidator = Pick<Ajv, 'compile'>;
+
+const ajvOptions = {
 	allErrors: true,
 	verbose: true,
 	$data: true,
 	strict: false
-});
-addFormats(ajv);
+};
+
+const validators: Record<string, JsonSchemaValidator> = {};
+
+/**
+ * Returns a validator for the schema's dialect. Draft 2019-09 and 2020-12
+ * schemas need their own Ajv builds; anything else is treated as draft-07.
+ */
+function validatorFor(schema: unknown): JsonSchemaValidator {
+	const dialect =
+		isJsonSchemaObject(schema) && typeof schema.$schema === 'string' ? schema.$schema : '';
+	const key = dialect.includes('2020-12')
+		? '2020-12'
+		: dialect.includes('2019-09')
+			? '2019-09'
+			: 'draft-07';
+
+	if (!validators[key]) {
+		const ajv =
+			key === '2020-12'
+				? new Ajv2020(ajvOptions)
+				: key === '2019-09'
+					? new Ajv2019(ajvOptions)
+					: new Ajv(ajvOptions);
+		addFormats(ajv as Ajv);
+		validators[key] = ajv;
+	}
+	return validators[key];
+}
+
+// Maximum nesting rendered, so that deeply recursive schemas end.
+const MAX_DEPTH = 32;
+
+// References being expanded, so that a definition referring to itself is
+// shown as a reference rather than expanded again.
+const expandingRefs = new Set<string>();
 
 export interface JsonSchemaObject {
 	$ref?: string;
+	$dynamicRef?: string;
 	$schema?: string;
+	$id?: string;
+	$anchor?: string;
+	$defs?: Record<string, JsonSchemaObject>;
 	type?: string | string[];
 	format?: string;
 	description?: string;
@@ -20,7 +62,14 @@ export interface JsonSchemaObject {
 	properties?: Record<string, JsonSchemaObject>;
 	patternProperties?: Record<string, JsonSchemaObject>;
 	required?: string[];
-	items?: JsonSchemaObject;
+	items?: JsonSchemaObject | JsonSchemaObject[];
+	prefixItems?: JsonSchemaObject[];
+	additionalProperties?: JsonSchemaObject | boolean;
+	unevaluatedProperties?: JsonSchemaObject | boolean;
+	if?: JsonSchemaObject;
+	then?: JsonSchemaObject;
+	else?: JsonSchemaObject;
+	dependentSchemas?: Record<string, JsonSchemaObject>;
 	enum?: unknown[];
 	default?: unknown;
 	const?: unknown;
@@ -42,13 +91,69 @@ function isJsonSchemaObject(value: unknown): value is JsonSchemaObject {
 	return typeof value === 'object' && value !== null && !Array.isArray(value);
 }
 
+/**
+ * Find the first subschema, or the schema itself, whose keyword has the
+ * given value. Relative $id values match by suffix.
+ */
+function findByKeyword(schema: unknown, keyword: string, value: string): JsonSchemaObject | null {
+	if (Array.isArray(schema)) {
+		for (const item of schema) {
+			const found = findByKeyword(item, keyword, value);
+			if (found) return found;
+		}
+		return null;
+	}
+	if (!isJsonSchemaObject(schema)) return null;
+
+	const own = schema[keyword];
+	if (
+		typeof own === 'string' &&
+		(own === value || (keyword === '$id' && value.endsWith(`/${own}`)))
+	) {
+		return schema;
+	}
+	for (const child of Object.values(schema)) {
+		const found = findByKeyword(child, keyword, value);
+		if (found) return found;
+	}
+	return null;
+}
+
+/**
+ * Resolve a reference within the schema: a JSON pointer such as
+ * #/definitions/Address or #/$defs/Address, or a plain-name fragment naming
+ * an $anchor or $dynamicAnchor. References starting with the $id of the
+ * schema or one of its subschemas resolve within that schema.
+ */
 export function resolveRef(ref: string, rootSchema: JsonSchemaObject): JsonSchemaObject | null {
-	if (!ref?.startsWith('#/')) return null;
+	if (!ref) return null;
+
+	const hash = ref.indexOf('#');
+	const base = hash >= 0 ? ref.substring(0, hash) : ref;
+	const fragment = hash >= 0 ? ref.substring(hash + 1) : '';
 
-	const parts = ref.substring(2).split('/');
 	let current: unknown = rootSchema;
+	if (base && base !== rootSchema.$id) {
+		current = findByKeyword(rootSchema, '$id', base);
+		if (!current) return null;
+	}
 
-	for (const part of parts) {
+	if (fragment && !fragment.startsWith('/')) {
+		return (
+			findByKeyword(current, '$anchor', fragment) ||
+			findByKeyword(current, '$dynamicAnchor', fragment)
+		);
+	}
+
+	for (const token of fragment.split('/')) {
+		if (!token) continue;
+		let part = token;
+		try {
+			part = decodeURIComponent(token);
+		} catch {
+			// Keep the token as written.
+		}
+		part = part.replace(/~1/g, '/').replace(/~0/g, '~');
 		if (!isJsonSchemaObject(current) || !(part in current)) return null;
 		current = (current as Record<string, unknown>)[part];
 	}
@@ -58,21 +163,157 @@ export function resolveRef(ref: string, rootSchema: JsonSchemaObject): JsonSchem
 
 export function getSchemaNameFromRef(ref: string): string {
 	if (!ref) return 'unknown';
-	return ref.split('/').pop() || 'unknown';
+	return ref.split(/[/#]/).pop() || 'unknown';
+}
+
+function schemaRef(schema: JsonSchemaObject): string | undefined {
+	return schema.$ref || schema.$dynamicRef;
+}
+
+/**
+ * The schema of an array's elements, when it has a single one.
+ */
+function itemSchema(schema: JsonSchemaObject): JsonSchemaObject | undefined {
+	return isJsonSchemaObject(schema.items) ? schema.items : undefined;
+}
+
+/**
+ * The positional schemas of a tuple: prefixItems from 2020-12, or an items
+ * list before it.
+ */
+function tupleItems(schema: JsonSchemaObject): JsonSchemaObject[] | undefined {
+	if (Array.isArray(schema.prefixItems)) return schema.prefixItems;
+	if (Array.isArray(schema.items)) return schema.items;
+	return undefined;
+}
+
+function isObjectSchema(schema: JsonSchemaObject): boolean {
+	return (
+		schema.type === 'object' ||
+		(Array.isArray(schema.type) && schema.type.includes('object')) ||
+		(!schema.type && !!schema.properties)
+	);
+}
+
+/**
+ * Properties that only apply conditionally: those of the then and else
+ * branches of if/then/else, and of dependentSchemas.
+ */
+function conditionalProperties(
+	schema: JsonSchemaObject,
+	rootSchema: JsonSchemaObject
+): Record<string, JsonSchemaObject> {
+	const branches: JsonSchemaObject[] = [];
+	if (isJsonSchemaObject(schema.then)) branches.push(schema.then);
+	if (isJsonSchemaObject(schema.else)) branches.push(schema.else);
+	if (isJsonSchemaObject(schema.dependentSchemas)) {
+		branches.push(...Object.values(schema.dependentSchemas).filter(isJsonSchemaObject));
+	}
+
+	const properties: Record<string, JsonSchemaObject> = {};
+	for (let branch of branches) {
+		const ref = schemaRef(branch);
+		if (ref) branch = resolveRef(ref, rootSchema) || branch;
+		Object.entries(branch.properties || {}).forEach(([name, property]) => {
+			if (!(name in (schema.properties || {})) && !(name in properties)) {
+				properties[name] = property;
+			}
+		});
+	}
+	return properties;
+}
+
+/**
+ * Process an object schema's properties: its own, then those that only
+ * apply conditionally, then the schema of any further properties.
+ */
+function processObjectProperties(
+	schema: JsonSchemaObject,
+	rootSchema: JsonSchemaObject,
+	depth: number,
+	parentPath: string
+): Field[] {
+	const fields: Field[] = [];
+
+	Object.entries(schema.properties || {}).forEach(([name, propertySchema]) => {
+		const isRequired = (schema.required || []).includes(name);
+		const nestedFields = processSchemaRecursively(
+			name,
+			propertySchema,
+			rootSchema,
+			depth,
+			parentPath
+		);
+
+		if (nestedFields.length > 0) {
+			nestedFields[0].required = isRequired;
+		}
+
+		fields.push(...nestedFields);
+	});
+
+	Object.entries(conditionalProperties(schema, rootSchema)).forEach(([name, propertySchema]) => {
+		const nestedFields = processSchemaRecursively(
+			name,
+			propertySchema,
+			rootSchema,
+			depth,
+			parentPath
+		);
+
+		if (nestedFields.length > 0) {
+			nestedFields[0].required = false;
+			nestedFields[0].description = ['Conditional', nestedFields[0].description]
+				.filter(Boolean)
+				.join(' · ');
+		}
+
+		fields.push(...nestedFields);
+	});
+
+	if (schema.patternProperties) {
+		fields.push(
+			...processPatternProperties(schema.patternProperties, rootSchema, depth, parentPath)
+		);
+	}
+
+	const additional = isJsonSchemaObject(schema.unevaluatedProperties)
+		? schema.unevaluatedProperties
+		: isJsonSchemaObject(schema.additionalProperties)
+			? schema.additionalProperties
+			: undefined;
+	if (additional) {
+		const nestedFields = processSchemaRecursively('{*}', additional, rootSchema, depth, parentPath);
+		if (nestedFields.length > 0) {
+			nestedFields[0].required = false;
+			nestedFields[0].description = nestedFields[0].description || 'Any other property';
+		}
+		fields.push(...nestedFields);
+	}
+
+	return fields;
 }
 
 export function getFieldType(fieldSchema: JsonSchemaObject | null | undefined): string {
 	if (!fieldSchema) return 'unknown';
 
-	if (fieldSchema.$ref) {
-		return `ref(${getSchemaNameFromRef(fieldSchema.$ref)})`;
+	const ref = schemaRef(fieldSchema);
+	if (ref) {
+		return `ref(${getSchemaNameFromRef(ref)})`;
+	}
+
+	const tuple = tupleItems(fieldSchema);
+	if (tuple) {
+		return `tuple<${tuple.map((item) => getFieldType(item)).join(', ')}>`;
 	}
 
 	if (fieldSchema.type === 'array') {
-		if (fieldSchema.items?.$ref) {
-			return `array<${getSchemaNameFromRef(fieldSchema.items.$ref)}>`;
+		const items = itemSchema(fieldSchema);
+		const itemRef = items && schemaRef(items);
+		if (itemRef) {
+			return `array<${getSchemaNameFromRef(itemRef)}>`;
 		}
-		const itemType = fieldSchema.items?.type || 'any';
+		const itemType = items?.type || 'any';
 		return `array<${itemType}>`;
 	}
 
@@ -100,6 +341,10 @@ export function getFieldType(fieldSchema: JsonSchemaObject | null | undefined):
 		return fieldSchema.type.join(' | ');
 	}
 
+	if (!fieldSchema.type && fieldSchema.properties) {
+		return 'object';
+	}
+
 	return fieldSchema.type || 'any';
 }
 
@@ -296,19 +541,33 @@ export function processSchemaRecursively(
 	depth = 0,
 	parentPath = ''
 ): Field[] {
-	if (!fieldSchema) return [];
+	if (!fieldSchema || depth > MAX_DEPTH) return [];
 
 	const fields: Field[] = [];
 	const fullPath = parentPath ? `${parentPath}.${fieldName}` : fieldName;
 
-	if (fieldSchema.$ref) {
-		const resolvedSchema = resolveRef(fieldSchema.$ref, rootSchema);
+	const ref = schemaRef(fieldSchema);
+	if (ref) {
+		const resolvedSchema = expandingRefs.has(ref) ? null : resolveRef(ref, rootSchema);
 		if (resolvedSchema) {
-			return processSchemaRecursively(fieldName, resolvedSchema, rootSchema, depth, parentPath);
+			// Since 2019-09 a $ref may have siblings, which apply alongside it.
+			const { $ref: _ref, $dynamicRef: _dynamicRef, ...siblings } = fieldSchema;
+			expandingRefs.add(ref);
+			try {
+				return processSchemaRecursively(
+					fieldName,
+					{ ...resolvedSchema, ...siblings },
+					rootSchema,
+					depth,
+					parentPath
+				);
+			} finally {
+				expandingRefs.delete(ref);
+			}
 		} else {
 			fields.push({
 				name: fullPath,
-				type: `ref(${getSchemaNameFromRef(fieldSchema.$ref)})`,
+				type: `ref(${getSchemaNameFromRef(ref)})`,
 				description: fieldSchema.description,
 				required: false,
 				indentLevel: depth
@@ -355,55 +614,34 @@ export function processSchemaRecursively(
 
 	fields.push(field);
 
-	if (fieldSchema.type === 'object') {
-		if (fieldSchema.properties) {
-			Object.entries(fieldSchema.properties).forEach(([name, schema]) => {
-				const isRequired = (fieldSchema.required || []).includes(name);
-				const nestedFields = processSchemaRecursively(
-					name,
-					schema,
-					rootSchema,
-					depth + 1,
-					fullPath
-				);
-
-				if (nestedFields.length > 0) {
-					nestedFields[0].required = isRequired;
-				}
-
-				fields.push(...nestedFields);
-			});
-		}
-
-		if (fieldSchema.patternProperties) {
-			fields.push(
-				...processPatternProperties(fieldSchema.patternProperties, rootSchema, depth + 1, fullPath)
-			);
-		}
+	if (isObjectSchema(fieldSchema)) {
+		fields.push(...processObjectProperties(fieldSchema, rootSchema, depth + 1, fullPath));
 	}
 
-	if (fieldSchema.type === 'array' && fieldSchema.items) {
-		if (fieldSchema.items.type === 'object' && fieldSchema.items.properties) {
-			Object.entries(fieldSchema.items.properties).forEach(([name, schema]) => {
-				const isRequired = (fieldSchema.items?.required || []).includes(name);
-				const nestedFields = processSchemaRecursively(
-					name,
-					schema,
+	const tuple = tupleItems(fieldSchema);
+	if (tuple) {
+		tuple.forEach((item, index) => {
+			fields.push(
+				...processSchemaRecursively(
+					`${fieldName}[${index}]`,
+					item,
 					rootSchema,
 					depth + 1,
-					`${fullPath}[]`
-				);
-
-				if (nestedFields.length > 0) {
-					nestedFields[0].required = isRequired;
-				}
+					parentPath
+				)
+			);
+		});
+		return fields;
+	}
 
-				fields.push(...nestedFields);
-			});
+	const items = itemSchema(fieldSchema);
+	if (fieldSchema.type === 'array' && items) {
+		if (isObjectSchema(items) && items.properties) {
+			fields.push(...processObjectProperties(items, rootSchema, depth + 1, `${fullPath}[]`));
 		} else {
 			const nestedFields = processSchemaRecursively(
 				`${fieldName}[]`,
-				fieldSchema.items,
+				items,
 				rootSchema,
 				depth + 1,
 				parentPath
@@ -559,26 +797,8 @@ export function processJsonSchema(schemaSection: unknown): Field[] {
 			fields.push(...processComposition('root', schema, schema, 0, ''));
 		}
 
-		if (
-			schema.type === 'object' ||
-			(Array.isArray(schema.type) && schema.type.includes('object'))
-		) {
-			if (schema.properties) {
-				Object.entries(schema.properties).forEach(([fieldName, fieldSchema]) => {
-					const isRequired = (schema.required || []).includes(fieldName);
-					const fieldResults = processSchemaRecursively(fieldName, fieldSchema, schema, 0, '');
-
-					if (fieldResults.length > 0) {
-						fieldResults[0].required = isRequired;
-					}
-
-					fields.push(...fieldResults);
-				});
-			}
-
-			if (schema.patternProperties) {
-				fields.push(...processPatternProperties(schema.patternProperties, schema, 0, ''));
-			}
+		if (isObjectSchema(schema)) {
+			fields.push(...processObjectProperties(schema, schema, 0, ''));
 		}
 
 		if (fields.length === 0) {
@@ -617,7 +837,7 @@ export function validateJsonSchema(schema: unknown): { message: string }[] {
 			if (schemaCopy.examples) delete schemaCopy.examples;
 
 			try {
-				ajv.compile(schemaCopy);
+				validatorFor(schemaCopy).compile(schemaCopy);
 				return [];
 			} catch (error) {
 				return [{ message: error instanceof Error ? error.message : String(error) }];
@@ -647,6 +867,8 @@ export function isJsonSchema(schemaSection: unknown): boolean {
 		isJsonSchemaObject(schemaSection) &&
 		(schemaSection.properties ||
 			schemaSection.patternProperties ||
+			schemaSection.$defs ||
+			schemaSection.prefixItems ||
 			schemaSection.type === 'object' ||
 			schemaSection.allOf ||
 			schemaSection.oneOf ||
@@ -660,7 +882,7 @@ export function isJsonSchema(schemaSection: unknown): boolean {
 		const schemaCopy = JSON.parse(JSON.stringify(schemaSection));
 		if (schemaCopy.example) delete schemaCopy.example;
 		if (schemaCopy.examples) delete schemaCopy.examples;
-		ajv.compile(schemaCopy);
+		validatorFor(schemaCopy).compile(schemaCopy);
 		return true;
 	} catch {
 		return false;
@@ -16,6 +16,7 @@ interface SqlColumn {
 	is_sorting_key?: unknown;
 	comment?: unknown;
 	default_expression?: unknown;
+	fields?: unknown;
 }
 
 interface SchemaValidationError {
@@ -43,9 +44,11 @@ export function isSqlColumnSchema(schemaSection: unknown): boolean {
 
 /**
  * Process native SQL column array into Field[] for display.
- * Handles both Trino and ClickHouse column formats.
+ * Handles both Trino and ClickHouse column formats. Nested columns listed
+ * under `fields` (structs, and the structs in lists and maps) follow their
+ * parent one indent level deeper.
  */
-export function processSqlColumnSchema(schemaSection: unknown): Field[] {
+export function processSqlColumnSchema(schemaSection: unknown, depth = 0): Field[] {
 	if (!schemaSection || !Array.isArray(schemaSection)) return [];
 
 	const fields: Field[] = [];
@@ -80,8 +83,12 @@ export function processSqlColumnSchema(schemaSection: unknown): Field[] {
 			description: descParts.length > 0 ? descParts.join(' · ') : undefined,
 			required,
 			default: col.default_expression,
-			indentLevel: 0
+			indentLevel: depth
 		});
+
+		if (Array.isArray(col.fields)) {
+			fields.push(...processSqlColumnSchema(col.fields, depth + 1));
+		}
 	}
 
 	return fields;
@@ -1,3 +1,27 @@
 import { writable } from 'svelte/store';
 
 export const tablePreviewEnabled = writable(false);
+
+export interface Feature {
+	enabled: boolean;
+	can_view: boolean;
+	can_manage: boolean;
+}
+
+export interface Features {
+	glossary: Feature;
+	data_products: Feature;
+	scheduling: Feature;
+	ai: Feature;
+}
+
+const allowed: Feature = { enabled: true, can_view: true, can_manage: true };
+
+// Everything is shown until the server says otherwise, so a failed lookup
+// never hides a feature that is actually available.
+export const features = writable<Features>({
+	glossary: allowed,
+	data_products: allowed,
+	scheduling: allowed,
+	ai: allowed
+});
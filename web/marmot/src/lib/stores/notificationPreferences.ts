@@ -11,7 +11,10 @@ export type NotificationType =
 	| 'upstream_schema_change'
 	| 'downstream_schema_change'
 	| 'lineage_change'
-	| 'asset_deleted';
+	| 'asset_deleted'
+	| 'asset_archival'
+	| 'saved_search_match'
+	| 'ownership_vacancy';
 
 export interface NotificationPreferences {
 	system: boolean;
@@ -23,6 +26,9 @@ export interface NotificationPreferences {
 	downstream_schema_change: boolean;
 	lineage_change: boolean;
 	asset_deleted: boolean;
+	asset_archival: boolean;
+	saved_search_match: boolean;
+	ownership_vacancy: boolean;
 }
 
 const defaultPreferences: NotificationPreferences = {
@@ -34,7 +40,10 @@ const defaultPreferences: NotificationPreferences = {
 	upstream_schema_change: true,
 	downstream_schema_change: true,
 	lineage_change: true,
-	asset_deleted: true
+	asset_deleted: true,
+	asset_archival: true,
+	saved_search_match: true,
+	ownership_vacancy: true
 };
 
 function createNotificationPreferencesStore() {
@@ -44,7 +44,8 @@ export const NOTIFICATION_TYPE_OPTIONS: { type: string; label: string; icon: str
 		label: 'Downstream Schema Change',
 		icon: 'material-symbols:arrow-downward-alt'
 	},
-	{ type: 'lineage_change', label: 'Lineage Change', icon: 'material-symbols:timeline' }
+	{ type: 'lineage_change', label: 'Lineage Change', icon: 'material-symbols:timeline' },
+	{ type: 'catalog_delta', label: 'Catalog Delta', icon: 'material-symbols:sync-alt' }
 ];
 
 export const NOTIFICATION_TYPE_LABELS: Record<string, string> = Object.fromEntries(